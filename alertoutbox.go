@@ -0,0 +1,481 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/blockchain/v3"
+	"github.com/decred/dcrd/chaincfg/v3"
+)
+
+const (
+	// These constants define the kinds of critical events that can be
+	// recorded to the alert outbox.
+	alertKindReorg            = "reorg"
+	alertKindTicketExhaustion = "ticketexhaustion"
+	alertKindCorruption       = "corruption"
+
+	// alertOutboxFileName is the name of the file within the data directory
+	// that houses the alert outbox.
+	alertOutboxFileName = "alertoutbox.json"
+
+	// alertOutboxVersion is the version of the format of the alert outbox
+	// file.  It must be increased whenever the format changes in a way that
+	// is not backwards compatible.
+	alertOutboxVersion = 1
+
+	// maxAlertOutboxEntries is the maximum number of alerts retained in the
+	// outbox.  The oldest delivered alerts are discarded first once the limit
+	// is reached.
+	maxAlertOutboxEntries = 1000
+
+	// alertWebhookEvent is the event category reported to webhooks when an
+	// alert is delivered to them.
+	alertWebhookEvent = "alert"
+
+	// alertRetryBaseDelay and alertRetryMaxDelay are the initial and maximum
+	// amounts of time to wait before retrying the delivery of an alert to the
+	// webhooks that did not accept it.  The delay doubles after each failed
+	// attempt.
+	alertRetryBaseDelay = 15 * time.Second
+	alertRetryMaxDelay  = time.Hour
+)
+
+// alertKinds houses the kinds of critical events that can be recorded to the
+// alert outbox.
+var alertKinds = map[string]struct{}{
+	alertKindReorg:            {},
+	alertKindTicketExhaustion: {},
+	alertKindCorruption:       {},
+}
+
+// alertPayload is a critical event alert as it is delivered to webhooks.
+type alertPayload struct {
+	ID      uint64          `json:"id"`
+	Time    int64           `json:"time"`
+	Kind    string          `json:"kind"`
+	Network string          `json:"network"`
+	Message string          `json:"message"`
+	Details json.RawMessage `json:"details,omitempty"`
+}
+
+// alertEntry is a critical event alert along with its delivery state as it is
+// stored in the alert outbox.
+type alertEntry struct {
+	alertPayload
+
+	// Pending are the webhooks that have not accepted the alert yet.
+	Pending []string `json:"pending,omitempty"`
+
+	// Attempts is the number of failed delivery attempts and NextAttempt is
+	// the time the delivery will be retried in seconds since the Unix epoch.
+	Attempts    uint32 `json:"attempts,omitempty"`
+	NextAttempt int64  `json:"nextattempt,omitempty"`
+}
+
+// alertOutboxFile is the format of the alert outbox file.
+type alertOutboxFile struct {
+	Version int           `json:"version"`
+	NextID  uint64        `json:"nextid"`
+	Alerts  []*alertEntry `json:"alerts"`
+}
+
+// reorgAlertDetails houses the details of a reorg alert.
+type reorgAlertDetails struct {
+	Depth      int64  `json:"depth"`
+	OldHash    string `json:"oldhash"`
+	OldHeight  int64  `json:"oldheight"`
+	NewHash    string `json:"newhash"`
+	NewHeight  int64  `json:"newheight"`
+	ForkHash   string `json:"forkhash"`
+	ForkHeight int64  `json:"forkheight"`
+}
+
+// ticketExhaustionAlertDetails houses the details of a ticket exhaustion
+// alert.
+type ticketExhaustionAlertDetails struct {
+	Hash      string `json:"hash"`
+	Height    int64  `json:"height"`
+	PoolSize  uint32 `json:"poolsize"`
+	Threshold uint32 `json:"threshold"`
+}
+
+// corruptionAlertDetails houses the details of a corruption alert.
+type corruptionAlertDetails struct {
+	Hash   string `json:"hash"`
+	Height int64  `json:"height"`
+	Error  string `json:"error"`
+}
+
+// alertOutbox durably records critical events, such as deep chain
+// reorganizations, the live ticket pool nearing exhaustion, and detected
+// database corruption, to a file in the data directory and delivers them to
+// operator-configured webhooks with retries so the alerts are not lost when no
+// websocket client is attached to observe them.
+type alertOutbox struct {
+	path       string
+	params     *chaincfg.Params
+	kinds      map[string]struct{}
+	reorgDepth int64
+	webhooks   []string
+
+	// now returns the current time and post delivers the provided payload to
+	// the provided webhook.  They are fields so they can be mocked by tests.
+	now  func() time.Time
+	post func(ctx context.Context, url string, payload []byte) error
+
+	// wake is signalled when alerts are added so they are delivered.
+	wake chan struct{}
+
+	mtx    sync.Mutex
+	nextID uint64
+	alerts []*alertEntry
+
+	// ticketsLow tracks whether the live ticket pool is below the exhaustion
+	// threshold so the alert is only recorded when it first falls below it.
+	ticketsLow bool
+}
+
+// newAlertOutbox returns an alert outbox that records the provided kinds of
+// critical events to a file in the provided data directory and delivers them
+// to the provided webhooks.  Reorg alerts are only recorded for chain
+// reorganizations that disconnect at least the provided number of blocks.
+//
+// Any alerts previously recorded to the file are loaded and the deliveries that
+// are still pending to the provided webhooks are resumed.
+func newAlertOutbox(dataDir string, params *chaincfg.Params, kinds []string, reorgDepth uint32, webhooks []string) (*alertOutbox, error) {
+	o := &alertOutbox{
+		path:       filepath.Join(dataDir, alertOutboxFileName),
+		params:     params,
+		kinds:      make(map[string]struct{}, len(kinds)),
+		reorgDepth: int64(reorgDepth),
+		webhooks:   webhooks,
+		now:        time.Now,
+		post:       postAlert,
+		wake:       make(chan struct{}, 1),
+		nextID:     1,
+	}
+	for _, kind := range kinds {
+		o.kinds[kind] = struct{}{}
+	}
+
+	serialized, err := ioutil.ReadFile(o.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return o, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var file alertOutboxFile
+	if err := json.Unmarshal(serialized, &file); err != nil {
+		return nil, fmt.Errorf("unable to load alert outbox %s: %w", o.path,
+			err)
+	}
+	if file.Version != alertOutboxVersion {
+		return nil, fmt.Errorf("unable to load alert outbox %s: unsupported "+
+			"version %d", o.path, file.Version)
+	}
+
+	// Only resume the deliveries to webhooks that are still configured.
+	configured := make(map[string]struct{}, len(webhooks))
+	for _, url := range webhooks {
+		configured[url] = struct{}{}
+	}
+	for _, alert := range file.Alerts {
+		pending := alert.Pending[:0]
+		for _, url := range alert.Pending {
+			if _, ok := configured[url]; ok {
+				pending = append(pending, url)
+			}
+		}
+		alert.Pending = pending
+	}
+	o.nextID = file.NextID
+	o.alerts = file.Alerts
+	return o, nil
+}
+
+// postAlert delivers the provided serialized alert to the provided webhook via
+// an HTTP POST request.  The webhook must respond with a 2xx status code to
+// accept the alert.
+func postAlert(ctx context.Context, url string, payload []byte) error {
+	return postWebhook(ctx, url, alertWebhookEvent, payload, nil)
+}
+
+// save writes the alert outbox to its file.  The file is replaced atomically so
+// it is never left partially written.
+//
+// This function MUST be called with the outbox lock held.
+func (o *alertOutbox) save() error {
+	serialized, err := json.MarshalIndent(&alertOutboxFile{
+		Version: alertOutboxVersion,
+		NextID:  o.nextID,
+		Alerts:  o.alerts,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmpPath := o.path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, serialized, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, o.path)
+}
+
+// prune discards the oldest alerts once the maximum number of alerts is
+// exceeded.  Alerts that have been delivered to all webhooks are discarded
+// before those that have not.
+//
+// This function MUST be called with the outbox lock held.
+func (o *alertOutbox) prune() {
+	for _, delivered := range []bool{true, false} {
+		excess := len(o.alerts) - maxAlertOutboxEntries
+		if excess <= 0 {
+			return
+		}
+		alerts := o.alerts[:0]
+		for _, alert := range o.alerts {
+			if excess > 0 && (len(alert.Pending) == 0) == delivered {
+				excess--
+				continue
+			}
+			alerts = append(alerts, alert)
+		}
+		o.alerts = alerts
+	}
+}
+
+// add records an alert of the provided kind with the provided message and
+// details when that kind of critical event is enabled and wakes the delivery
+// of the alert to the webhooks.
+//
+// This function is safe for concurrent access.
+func (o *alertOutbox) add(kind, message string, details interface{}) {
+	if _, ok := o.kinds[kind]; !ok {
+		return
+	}
+	srvrLog.Warnf("ALERT (%s): %s", kind, message)
+
+	serializedDetails, err := json.Marshal(details)
+	if err != nil {
+		srvrLog.Errorf("Unable to serialize %s alert details: %v", kind, err)
+		return
+	}
+
+	o.mtx.Lock()
+	o.alerts = append(o.alerts, &alertEntry{
+		alertPayload: alertPayload{
+			ID:      o.nextID,
+			Time:    o.now().Unix(),
+			Kind:    kind,
+			Network: o.params.Name,
+			Message: message,
+			Details: serializedDetails,
+		},
+		Pending: append([]string(nil), o.webhooks...),
+	})
+	o.nextID++
+	o.prune()
+	err = o.save()
+	o.mtx.Unlock()
+	if err != nil {
+		srvrLog.Errorf("Unable to save alert outbox: %v", err)
+	}
+
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
+
+// ReorgAlert records a reorg alert when the provided chain reorganization
+// disconnected at least the configured number of blocks.
+//
+// This function is safe for concurrent access.
+func (o *alertOutbox) ReorgAlert(rd *blockchain.ReorganizationNtfnsData) {
+	depth := rd.OldHeight - rd.ForkHeight
+	if depth < o.reorgDepth {
+		return
+	}
+	message := fmt.Sprintf("Chain reorganization disconnected %d blocks "+
+		"from %v (height %d) to fork point %v (height %d) and switched to %v "+
+		"(height %d)", depth, rd.OldHash, rd.OldHeight, rd.ForkHash,
+		rd.ForkHeight, rd.NewHash, rd.NewHeight)
+	o.add(alertKindReorg, message, &reorgAlertDetails{
+		Depth:      depth,
+		OldHash:    rd.OldHash.String(),
+		OldHeight:  rd.OldHeight,
+		NewHash:    rd.NewHash.String(),
+		NewHeight:  rd.NewHeight,
+		ForkHash:   rd.ForkHash.String(),
+		ForkHeight: rd.ForkHeight,
+	})
+}
+
+// ticketExhaustionThreshold returns the live ticket pool size below which the
+// pool is considered to be nearing exhaustion, which is half of the target
+// pool size of the network.
+func (o *alertOutbox) ticketExhaustionThreshold() uint32 {
+	return uint32(o.params.TicketPoolSize) * uint32(o.params.TicketsPerBlock) / 2
+}
+
+// TicketPoolAlert records a ticket exhaustion alert when the live ticket pool
+// size of the provided best chain state first falls below the exhaustion
+// threshold once votes are required.  Another alert is only recorded after the
+// pool size recovers to the threshold and then falls below it again.
+//
+// This function is safe for concurrent access.
+func (o *alertOutbox) TicketPoolAlert(best *blockchain.BestState) {
+	if best.Height < o.params.StakeValidationHeight {
+		return
+	}
+
+	threshold := o.ticketExhaustionThreshold()
+	low := best.NextPoolSize < threshold
+	o.mtx.Lock()
+	wasLow := o.ticketsLow
+	o.ticketsLow = low
+	o.mtx.Unlock()
+	if !low || wasLow {
+		return
+	}
+
+	message := fmt.Sprintf("Live ticket pool size %d at block %v (height %d) "+
+		"fell below %d tickets -- the chain will stall once there are not "+
+		"enough tickets to vote", best.NextPoolSize, best.Hash, best.Height,
+		threshold)
+	o.add(alertKindTicketExhaustion, message, &ticketExhaustionAlertDetails{
+		Hash:      best.Hash.String(),
+		Height:    best.Height,
+		PoolSize:  best.NextPoolSize,
+		Threshold: threshold,
+	})
+}
+
+// CorruptionAlert records a corruption alert for the provided block whose
+// stored data was detected to be corrupted.
+//
+// This function is safe for concurrent access.
+func (o *alertOutbox) CorruptionAlert(cd *blockchain.BlockCorruptedNtfnsData) {
+	message := fmt.Sprintf("Stored data for block %v (height %d) is "+
+		"corrupted: %v", cd.Hash, cd.Height, cd.Err)
+	o.add(alertKindCorruption, message, &corruptionAlertDetails{
+		Hash:   cd.Hash.String(),
+		Height: cd.Height,
+		Error:  fmt.Sprint(cd.Err),
+	})
+}
+
+// deliver attempts to deliver all alerts that are due to the webhooks that
+// have not accepted them yet and returns the time the next delivery is due.  A
+// zero time is returned when there are no pending deliveries.
+//
+// This function is safe for concurrent access.
+func (o *alertOutbox) deliver(ctx context.Context) time.Time {
+	// Determine the alerts that are due while holding the lock and then
+	// deliver them without it so recording new alerts is not blocked by
+	// unresponsive webhooks.
+	type delivery struct {
+		id      uint64
+		payload []byte
+		pending []string
+	}
+	now := o.now()
+	var due []delivery
+	o.mtx.Lock()
+	for _, alert := range o.alerts {
+		if len(alert.Pending) == 0 || alert.NextAttempt > now.Unix() {
+			continue
+		}
+		payload, err := json.Marshal(&alert.alertPayload)
+		if err != nil {
+			srvrLog.Errorf("Unable to serialize alert %d: %v", alert.ID, err)
+			continue
+		}
+		due = append(due, delivery{
+			id:      alert.ID,
+			payload: payload,
+			pending: append([]string(nil), alert.Pending...),
+		})
+	}
+	o.mtx.Unlock()
+
+	failed := make(map[uint64][]string, len(due))
+	for _, d := range due {
+		for _, url := range d.pending {
+			if ctx.Err() != nil {
+				return time.Time{}
+			}
+			if err := o.post(ctx, url, d.payload); err != nil {
+				srvrLog.Warnf("Unable to deliver alert %d to webhook %s: %v",
+					d.id, url, err)
+				failed[d.id] = append(failed[d.id], url)
+			}
+		}
+	}
+
+	// Update the delivery state of the alerts, which might have been
+	// discarded in the mean time, and determine when the next delivery is
+	// due.
+	var next time.Time
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	delivered := make(map[uint64]struct{}, len(due))
+	for _, d := range due {
+		delivered[d.id] = struct{}{}
+	}
+	for _, alert := range o.alerts {
+		if _, ok := delivered[alert.ID]; ok {
+			alert.Pending = failed[alert.ID]
+			if len(alert.Pending) != 0 {
+				alert.Attempts++
+				alert.NextAttempt = now.Add(retryDelay(alert.Attempts,
+					alertRetryBaseDelay, alertRetryMaxDelay)).Unix()
+			}
+		}
+		if len(alert.Pending) == 0 {
+			continue
+		}
+		nextAttempt := time.Unix(alert.NextAttempt, 0)
+		if next.IsZero() || nextAttempt.Before(next) {
+			next = nextAttempt
+		}
+	}
+	if len(due) != 0 {
+		if err := o.save(); err != nil {
+			srvrLog.Errorf("Unable to save alert outbox: %v", err)
+		}
+	}
+	return next
+}
+
+// Run delivers recorded alerts to the webhooks until the provided context is
+// cancelled.  Deliveries that fail are retried with an exponential backoff.
+//
+// This must be run as a goroutine.
+func (o *alertOutbox) Run(ctx context.Context) {
+	for {
+		var retry <-chan time.Time
+		if next := o.deliver(ctx); !next.IsZero() {
+			retry = time.After(next.Sub(o.now()))
+		}
+
+		select {
+		case <-o.wake:
+		case <-retry:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/blockchain/v3"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
+)
+
+// TestAlertOutbox ensures the alert outbox only records the enabled kinds of
+// critical events when their conditions are met, persists them, and delivers
+// them to the webhooks with retries.
+func TestAlertOutbox(t *testing.T) {
+	t.Parallel()
+
+	dataDir, err := ioutil.TempDir("", "alertoutbox")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dataDir)
+
+	params := chaincfg.RegNetParams()
+	const webhook1, webhook2 = "http://127.0.0.1/1", "https://127.0.0.1/2"
+	webhooks := []string{webhook1, webhook2}
+	kinds := []string{alertKindReorg, alertKindTicketExhaustion}
+	o, err := newAlertOutbox(dataDir, params, kinds, 3, webhooks)
+	if err != nil {
+		t.Fatalf("unexpected error creating outbox: %v", err)
+	}
+	now := time.Unix(1592918788, 0)
+	o.now = func() time.Time { return now }
+
+	// Ensure reorgs are only recorded when they are deep enough.
+	reorg := func(depth int64) *blockchain.ReorganizationNtfnsData {
+		return &blockchain.ReorganizationNtfnsData{
+			OldHash:    chainhash.Hash{0x01},
+			OldHeight:  100,
+			NewHash:    chainhash.Hash{0x02},
+			NewHeight:  101,
+			ForkHash:   chainhash.Hash{0x03},
+			ForkHeight: 100 - depth,
+		}
+	}
+	o.ReorgAlert(reorg(2))
+	if len(o.alerts) != 0 {
+		t.Fatalf("unexpected alert for shallow reorg: %+v", o.alerts[0])
+	}
+	o.ReorgAlert(reorg(3))
+	if len(o.alerts) != 1 || o.alerts[0].Kind != alertKindReorg {
+		t.Fatalf("expected reorg alert -- got %d alerts", len(o.alerts))
+	}
+	var details reorgAlertDetails
+	if err := json.Unmarshal(o.alerts[0].Details, &details); err != nil {
+		t.Fatalf("unexpected error decoding details: %v", err)
+	}
+	if details.Depth != 3 || details.ForkHeight != 97 {
+		t.Fatalf("unexpected reorg details: %+v", details)
+	}
+
+	// Ensure the ticket exhaustion alert is only recorded when the pool size
+	// first falls below the threshold once votes are required.
+	threshold := o.ticketExhaustionThreshold()
+	tickets := func(height int64, poolSize uint32) *blockchain.BestState {
+		return &blockchain.BestState{Height: height, NextPoolSize: poolSize}
+	}
+	svh := params.StakeValidationHeight
+	o.TicketPoolAlert(tickets(svh-1, 0))
+	o.TicketPoolAlert(tickets(svh, threshold))
+	o.TicketPoolAlert(tickets(svh+1, threshold-1))
+	o.TicketPoolAlert(tickets(svh+2, threshold-2))
+	if len(o.alerts) != 2 || o.alerts[1].Kind != alertKindTicketExhaustion {
+		t.Fatalf("expected one ticket exhaustion alert -- got %d alerts",
+			len(o.alerts))
+	}
+	o.TicketPoolAlert(tickets(svh+3, threshold))
+	o.TicketPoolAlert(tickets(svh+4, threshold-1))
+	if len(o.alerts) != 3 {
+		t.Fatalf("expected another ticket exhaustion alert -- got %d "+
+			"alerts", len(o.alerts))
+	}
+
+	// Ensure disabled kinds of critical events are not recorded.
+	o.CorruptionAlert(&blockchain.BlockCorruptedNtfnsData{
+		Err: errors.New("corrupted"),
+	})
+	if len(o.alerts) != 3 {
+		t.Fatalf("unexpected corruption alert -- got %d alerts",
+			len(o.alerts))
+	}
+
+	// Deliver the alerts with the second webhook failing and ensure the
+	// delivery to it is retried with backoff.
+	delivered := make(map[string][]uint64)
+	failing := map[string]bool{webhook2: true}
+	o.post = func(ctx context.Context, url string, payload []byte) error {
+		if failing[url] {
+			return errors.New("unavailable")
+		}
+		var alert alertPayload
+		if err := json.Unmarshal(payload, &alert); err != nil {
+			t.Fatalf("unexpected error decoding payload: %v", err)
+		}
+		delivered[url] = append(delivered[url], alert.ID)
+		return nil
+	}
+	next := o.deliver(context.Background())
+	if len(delivered[webhook1]) != 3 || len(delivered[webhook2]) != 0 {
+		t.Fatalf("unexpected deliveries: %v", delivered)
+	}
+	if want := now.Add(alertRetryBaseDelay); !next.Equal(want) {
+		t.Fatalf("unexpected next delivery -- got %v, want %v", next, want)
+	}
+	if next := o.deliver(context.Background()); len(delivered[webhook1]) != 3 {
+		t.Fatalf("unexpected redelivery before retry is due (next %v)", next)
+	}
+
+	// Ensure the outbox, including the pending deliveries, is reloaded and
+	// the deliveries to webhooks that are no longer configured are dropped.
+	reloaded, err := newAlertOutbox(dataDir, params, kinds, 3, webhooks[1:])
+	if err != nil {
+		t.Fatalf("unexpected error reloading outbox: %v", err)
+	}
+	if len(reloaded.alerts) != 3 || reloaded.nextID != 4 {
+		t.Fatalf("unexpected reloaded outbox -- got %d alerts, next id %d",
+			len(reloaded.alerts), reloaded.nextID)
+	}
+	for _, alert := range reloaded.alerts {
+		if len(alert.Pending) != 1 || alert.Pending[0] != webhook2 ||
+			alert.Attempts != 1 {
+
+			t.Fatalf("unexpected reloaded delivery state: %+v", alert)
+		}
+	}
+	reloaded, err = newAlertOutbox(dataDir, params, kinds, 3, nil)
+	if err != nil {
+		t.Fatalf("unexpected error reloading outbox: %v", err)
+	}
+	for _, alert := range reloaded.alerts {
+		if len(alert.Pending) != 0 {
+			t.Fatalf("unexpected pending delivery: %+v", alert)
+		}
+	}
+
+	// Ensure the retry succeeds once it is due and the webhook recovers.
+	now = next
+	failing[webhook2] = false
+	if next := o.deliver(context.Background()); !next.IsZero() {
+		t.Fatalf("unexpected pending deliveries (next %v)", next)
+	}
+	if len(delivered[webhook2]) != 3 {
+		t.Fatalf("unexpected deliveries: %v", delivered)
+	}
+}
+
+// TestAlertOutboxPrune ensures the oldest delivered alerts are discarded before
+// pending ones once the maximum number of alerts is exceeded.
+func TestAlertOutboxPrune(t *testing.T) {
+	t.Parallel()
+
+	o := &alertOutbox{}
+	for i := 0; i < maxAlertOutboxEntries+2; i++ {
+		alert := &alertEntry{alertPayload: alertPayload{ID: uint64(i)}}
+		if i < 2 || i == 3 {
+			alert.Pending = []string{"http://127.0.0.1"}
+		}
+		o.alerts = append(o.alerts, alert)
+	}
+	o.prune()
+	if len(o.alerts) != maxAlertOutboxEntries {
+		t.Fatalf("unexpected number of alerts -- got %d, want %d",
+			len(o.alerts), maxAlertOutboxEntries)
+	}
+	for i, want := range []uint64{0, 1, 3, 5} {
+		if o.alerts[i].ID != want {
+			t.Fatalf("unexpected alert at index %d -- got %d, want %d", i,
+				o.alerts[i].ID, want)
+		}
+	}
+}
+
+// TestAlertRetryDelay ensures the delay before retrying the delivery of an
+// alert doubles after each failed attempt up to the maximum delay.
+func TestAlertRetryDelay(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		attempts uint32
+		want     time.Duration
+	}{
+		{attempts: 1, want: alertRetryBaseDelay},
+		{attempts: 2, want: 2 * alertRetryBaseDelay},
+		{attempts: 3, want: 4 * alertRetryBaseDelay},
+		{attempts: 100, want: alertRetryMaxDelay},
+	}
+	for _, test := range tests {
+		got := retryDelay(test.attempts, alertRetryBaseDelay,
+			alertRetryMaxDelay)
+		if got != test.want {
+			t.Errorf("unexpected delay after %d attempts -- got %v, want %v",
+				test.attempts, got, test.want)
+		}
+	}
+}
@@ -7,6 +7,7 @@ package blockchain
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/decred/dcrd/blockchain/stake/v3"
 	"github.com/decred/dcrd/database/v2"
@@ -21,12 +22,15 @@ import (
 // extends the best chain or is now the tip of the best chain due to causing a
 // reorganize, the fork length will be 0.
 //
+// The provided receive time is stored as the local time the block was received
+// when the block is stored in the database.
+//
 // The flags are also passed to checkBlockPositional, checkBlockContext and
 // connectBestChain.  See their documentation for how the flags modify their
 // behavior.
 //
 // This function MUST be called with the chain state lock held (for writes).
-func (b *BlockChain) maybeAcceptBlock(block *dcrutil.Block, flags BehaviorFlags) (int64, error) {
+func (b *BlockChain) maybeAcceptBlock(block *dcrutil.Block, receiveTime time.Time, flags BehaviorFlags) (int64, error) {
 	// This function should never be called with orphan blocks or the
 	// genesis block.
 	prevHash := &block.MsgBlock().Header.PrevBlock
@@ -72,8 +76,14 @@ func (b *BlockChain) maybeAcceptBlock(block *dcrutil.Block, flags BehaviorFlags)
 	// expensive connection logic.  It also has some other nice properties
 	// such as making blocks that never become part of the main chain or
 	// blocks that fail to connect available for further analysis.
+	//
+	// Also record the local time the block was received.
 	err = b.db.Update(func(dbTx database.Tx) error {
-		return dbMaybeStoreBlock(dbTx, block)
+		err := dbMaybeStoreBlock(dbTx, block)
+		if err != nil {
+			return err
+		}
+		return dbPutBlockReceiveTime(dbTx, block.Hash(), receiveTime)
 	})
 	if err != nil {
 		return 0, err
@@ -78,6 +78,17 @@ func TestBlockNodeHeader(t *testing.T) {
 		t.Fatalf("HeaderByHash: mismatched headers: got %+v, want %+v",
 			gotHeader, testHeader)
 	}
+
+	// Ensure fetching the median time from the chain produces the median
+	// time of the node.
+	gotMedianTime, err := bc.MedianTimeByHash(&testHeaderHash)
+	if err != nil {
+		t.Fatalf("MedianTimeByHash: unexpected error: %v", err)
+	}
+	if wantMedianTime := node.CalcPastMedianTime(); !gotMedianTime.Equal(wantMedianTime) {
+		t.Fatalf("MedianTimeByHash: mismatched median time: got %v, "+
+			"want %v", gotMedianTime, wantMedianTime)
+	}
 }
 
 // TestCalcPastMedianTime ensures the CalcPastMedianTie function works as
@@ -137,6 +137,7 @@ type BlockChain struct {
 	// separate mutex.
 	checkpoints         []chaincfg.Checkpoint
 	checkpointsByHeight map[int64]*chaincfg.Checkpoint
+	assumeValid         chainhash.Hash
 	deploymentVers      map[string]uint32
 	db                  database.DB
 	dbInfo              *databaseInfo
@@ -175,6 +176,11 @@ type BlockChain struct {
 	mainChainBlockCacheLock sync.RWMutex
 	mainChainBlockCache     map[chainhash.Hash]*dcrutil.Block
 
+	// These fields track blocks whose stored data was detected to be
+	// corrupted along with their heights until they are repaired.
+	quarantineLock sync.Mutex
+	quarantined    map[chainhash.Hash]int64
+
 	// These fields house a cached view that represents a block that votes
 	// against its parent and therefore contains all changes as a result
 	// of disconnecting all regular transactions in its parent.  It is only
@@ -409,6 +415,9 @@ func (b *BlockChain) fetchMainChainBlockByNode(node *blockNode) (*dcrutil.Block,
 		block, err = dbFetchBlockByNode(dbTx, node)
 		return err
 	})
+	if err != nil {
+		b.maybeQuarantineBlock(node, err)
+	}
 	return block, err
 }
 
@@ -432,6 +441,9 @@ func (b *BlockChain) fetchBlockByNode(node *blockNode) (*dcrutil.Block, error) {
 		block, err = dbFetchBlockByNode(dbTx, node)
 		return err
 	})
+	if err != nil {
+		b.maybeQuarantineBlock(node, err)
+	}
 	return block, err
 }
 
@@ -1174,8 +1186,16 @@ func (b *BlockChain) reorganizeChain(targetTip *blockNode) error {
 	}
 
 	// Send a notification that a blockchain reorganization took place.
-	reorgData := &ReorganizationNtfnsData{origTip.hash, origTip.height,
-		targetTip.hash, targetTip.height}
+	reorgData := &ReorganizationNtfnsData{
+		OldHash:   origTip.hash,
+		OldHeight: origTip.height,
+		NewHash:   targetTip.hash,
+		NewHeight: targetTip.height,
+	}
+	if fork != nil {
+		reorgData.ForkHash = fork.hash
+		reorgData.ForkHeight = fork.height
+	}
 	b.chainLock.Unlock()
 	b.sendNotification(NTReorganization, reorgData)
 	b.chainLock.Lock()
@@ -1248,6 +1268,40 @@ func (b *BlockChain) ForceHeadReorganization(formerBest chainhash.Hash, newBest
 	return err
 }
 
+// RollbackToHeight disconnects blocks from the tip of the main chain until the
+// block at the provided height is the tip.  The usual notifications for the
+// disconnected blocks are sent, so any indexes and the memory pool are updated
+// accordingly.
+//
+// The disconnected blocks remain known as a side chain, so new blocks that
+// extend the rolled back tip become part of the main chain as usual, however,
+// the chain will reorganize back to them should a block that extends them be
+// processed.
+//
+// This is intended to allow test suites to rewind the chain state and must not
+// be used on public networks.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) RollbackToHeight(height int64) error {
+	b.chainLock.Lock()
+	defer b.chainLock.Unlock()
+
+	tip := b.bestChain.Tip()
+	if height < 0 || height >= tip.height {
+		return fmt.Errorf("rollback height %d must be less than the current "+
+			"best height %d and not negative", height, tip.height)
+	}
+
+	// Reorganize the chain to the target block and flush any potential
+	// unsaved changes to the block index to the database.  It is safe to
+	// ignore any flushing errors here as the only time the index will be
+	// modified is if a block failed to connect.
+	target := b.bestChain.NodeByHeight(height)
+	err := b.reorganizeChain(target)
+	b.flushBlockIndexWarnOnly()
+	return err
+}
+
 // flushBlockIndex populates any ticket data that has been pruned from modified
 // block nodes, writes those nodes to the database and clears the set of
 // modified nodes if it succeeds.
@@ -1543,6 +1597,21 @@ func (b *BlockChain) HeaderByHash(hash *chainhash.Hash) (wire.BlockHeader, error
 	return node.Header(), nil
 }
 
+// MedianTimeByHash returns the median time of the block identified by the
+// given hash, as calculated from the timestamps of the block and the blocks
+// preceding it, or an error if it doesn't exist.  Note that this will return
+// the median time of blocks from both the main chain and any side chains.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) MedianTimeByHash(hash *chainhash.Hash) (time.Time, error) {
+	node := b.index.LookupNode(hash)
+	if node == nil {
+		return time.Time{}, fmt.Errorf("block %s is not known", hash)
+	}
+
+	return node.CalcPastMedianTime(), nil
+}
+
 // HeaderByHeight returns the block header at the given height in the main
 // chain.
 //
@@ -1572,6 +1641,23 @@ func (b *BlockChain) BlockByHash(hash *chainhash.Hash) (*dcrutil.Block, error) {
 	return b.fetchBlockByNode(node)
 }
 
+// BlockReceiveTime returns the local time the block with the given hash was
+// received.  A zero time is returned when the receive time of the block is not
+// known, such as for blocks that were stored before receive times were
+// recorded.  This function returns receive times regardless of whether or not
+// the block is part of the main chain.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) BlockReceiveTime(hash *chainhash.Hash) (time.Time, error) {
+	var receiveTime time.Time
+	err := b.db.View(func(dbTx database.Tx) error {
+		var err error
+		receiveTime, err = dbFetchBlockReceiveTime(dbTx, hash)
+		return err
+	})
+	return receiveTime, err
+}
+
 // BlockByHeight returns the block at the given height in the main chain.
 //
 // This function is safe for concurrent access.
@@ -1994,6 +2080,15 @@ type Config struct {
 	// checkpoints.
 	Checkpoints []chaincfg.Checkpoint
 
+	// AssumeValid is the hash of a block that has been externally verified
+	// to be valid, which is typically the AssumeValid block in ChainParams.
+	// Script validation is skipped for the block and its ancestors since
+	// their validity is implied by it.  All other checks are still enforced.
+	//
+	// This field can be the zero hash if the caller does not wish to assume
+	// any blocks are valid.
+	AssumeValid chainhash.Hash
+
 	// TimeSource defines the median time source to use for things such as
 	// block processing and determining whether or not the chain is current.
 	//
@@ -2079,6 +2174,7 @@ func New(ctx context.Context, config *Config) (*BlockChain, error) {
 	b := BlockChain{
 		checkpoints:                   config.Checkpoints,
 		checkpointsByHeight:           checkpointsByHeight,
+		assumeValid:                   config.AssumeValid,
 		deploymentVers:                deploymentVers,
 		db:                            config.DB,
 		chainParams:                   params,
@@ -2090,6 +2186,7 @@ func New(ctx context.Context, config *Config) (*BlockChain, error) {
 		index:                         newBlockIndex(config.DB),
 		bestChain:                     newChainView(nil),
 		mainChainBlockCache:           make(map[chainhash.Hash]*dcrutil.Block),
+		quarantined:                   make(map[chainhash.Hash]int64),
 		deploymentCaches:              newThresholdCaches(params),
 		isVoterMajorityVersionCache:   make(map[[stakeMajorityCacheKeySize]byte]bool),
 		isStakeMajorityVersionCache:   make(map[[stakeMajorityCacheKeySize]byte]bool),
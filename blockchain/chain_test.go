@@ -439,6 +439,58 @@ func nodeHashes(nodes []*blockNode, indexes ...int) []chainhash.Hash {
 	return hashes
 }
 
+// TestRollbackToHeight ensures rolling the main chain back to a given height
+// works as expected.
+func TestRollbackToHeight(t *testing.T) {
+	// Create a test harness initialized with the genesis block as the tip.
+	params := chaincfg.RegNetParams()
+	g, teardownFunc := newChaingenHarness(t, params, "rollbacktoheighttest")
+	defer teardownFunc()
+
+	// Build a few blocks on top of the genesis block.
+	//
+	//   genesis -> b1 -> b2 -> b3
+	g.CreateBlockOne("b1", 0)
+	g.AcceptTipBlock()
+	for i := 2; i <= 3; i++ {
+		g.NextBlock(fmt.Sprintf("b%d", i), nil, nil)
+		g.AcceptTipBlock()
+	}
+
+	// Ensure heights that are not below the current tip or are negative
+	// are rejected without modifying the chain.
+	for _, height := range []int64{3, 4, -1} {
+		if err := g.chain.RollbackToHeight(height); err == nil {
+			t.Fatalf("rollback to height %d did not fail", height)
+		}
+	}
+	g.ExpectTip("b3")
+
+	// Ensure rolling back disconnects the blocks above the target height.
+	//
+	//   genesis -> b1
+	if err := g.chain.RollbackToHeight(1); err != nil {
+		t.Fatalf("unexpected rollback error: %v", err)
+	}
+	g.ExpectTip("b1")
+
+	// Ensure a new block that extends the rolled back tip becomes part of
+	// the main chain even though the disconnected blocks have more work.
+	//
+	//   genesis -> b1 -> b2a
+	//                \-> b2 -> b3
+	g.SetTip("b1")
+	g.NextBlock("b2a", nil, nil)
+	g.AcceptTipBlock()
+	g.ExpectTip("b2a")
+
+	// Ensure rolling back to the genesis block works as expected.
+	if err := g.chain.RollbackToHeight(0); err != nil {
+		t.Fatalf("unexpected rollback error: %v", err)
+	}
+	g.ExpectTip("genesis")
+}
+
 // nodeHeaders is a convenience function that returns the headers for all of
 // the passed indexes of the provided nodes.  It is used to construct expected
 // located headers in the tests.
@@ -789,3 +841,53 @@ func TestLocateInventory(t *testing.T) {
 		}
 	}
 }
+
+// TestIsAssumedValid ensures only the assumed valid block and its ancestors
+// are reported as assumed valid and only once the header of the assumed valid
+// block is known.
+func TestIsAssumedValid(t *testing.T) {
+	// Construct a synthetic block chain with a block index consisting of
+	// the following structure.
+	// 	genesis -> 1 -> 2 -> 3 -> 4
+	// 	                \-> 3a
+	params := chaincfg.RegNetParams()
+	bc := newFakeChain(params)
+	genesis := bc.bestChain.NodeByHeight(0)
+	branch0Nodes := chainedFakeNodes(genesis, 4)
+	branch1Nodes := chainedFakeNodes(branch0Nodes[1], 1)
+	for _, node := range branch0Nodes {
+		bc.index.AddNode(node)
+	}
+	for _, node := range branch1Nodes {
+		bc.index.AddNode(node)
+	}
+
+	// Ensure no blocks are assumed valid when there is no assumed valid
+	// block or its header is not known.
+	if bc.isAssumedValid(branch0Nodes[0]) {
+		t.Fatal("block assumed valid without an assumed valid block")
+	}
+	bc.assumeValid = chainhash.Hash{0x01}
+	if bc.isAssumedValid(branch0Nodes[0]) {
+		t.Fatal("block assumed valid with unknown assumed valid block")
+	}
+
+	bc.assumeValid = branch0Nodes[2].hash
+	tests := []struct {
+		name string
+		node *blockNode
+		want bool
+	}{
+		{name: "genesis", node: genesis, want: true},
+		{name: "ancestor", node: branch0Nodes[0], want: true},
+		{name: "assumed valid block", node: branch0Nodes[2], want: true},
+		{name: "descendant", node: branch0Nodes[3], want: false},
+		{name: "side chain", node: branch1Nodes[0], want: false},
+	}
+	for _, test := range tests {
+		if got := bc.isAssumedValid(test.node); got != test.want {
+			t.Errorf("%q: unexpected result -- got %v, want %v", test.name,
+				got, test.want)
+		}
+	}
+}
@@ -17,6 +17,7 @@ import (
 
 	"github.com/decred/dcrd/blockchain/stake/v3"
 	"github.com/decred/dcrd/blockchain/standalone/v2"
+	"github.com/decred/dcrd/blockchain/v3/internal/muhash"
 	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/database/v2"
 	"github.com/decred/dcrd/dcrutil/v3"
@@ -28,7 +29,7 @@ import (
 const (
 	// currentDatabaseVersion indicates what the current database
 	// version is.
-	currentDatabaseVersion = 6
+	currentDatabaseVersion = 8
 
 	// currentBlockIndexVersion indicates what the current block index
 	// database version.
@@ -80,6 +81,10 @@ var (
 	// transaction output set.
 	utxoSetBucketName = []byte("utxoset")
 
+	// utxoSetHashKeyName is the name of the db key used to house the state of
+	// the multiset hash of the unspent transaction output set.
+	utxoSetHashKeyName = []byte("utxosethash")
+
 	// blockIndexBucketName is the name of the db bucket used to house the block
 	// index which consists of metadata for all known blocks both in the main
 	// chain and on side chains.
@@ -88,6 +93,10 @@ var (
 	// gcsFilterBucketName is the name of the db bucket used to house GCS
 	// filters.
 	gcsFilterBucketName = []byte("gcsfilters")
+
+	// blockReceiveTimeBucketName is the name of the db bucket used to house
+	// the local time each block was received.
+	blockReceiveTimeBucketName = []byte("blockrecvtimes")
 )
 
 // errNotInMainChain signifies that a block hash or height that is not in the
@@ -1167,13 +1176,50 @@ func dbFetchUtxoEntry(dbTx database.Tx, hash *chainhash.Hash) (*UtxoEntry, error
 	return entry, nil
 }
 
+// utxoSetHashElement returns the element that represents the utxo entry with
+// the provided database key and serialized value in the multiset hash of the
+// utxo set.
+func utxoSetHashElement(key, serializedUtxo []byte) []byte {
+	elem := make([]byte, 0, len(key)+len(serializedUtxo))
+	elem = append(elem, key...)
+	return append(elem, serializedUtxo...)
+}
+
+// dbFetchUtxoSetHash uses an existing database transaction to fetch the state
+// of the multiset hash of the utxo set.
+func dbFetchUtxoSetHash(dbTx database.Tx) (*muhash.MuHash, error) {
+	serialized := dbTx.Metadata().Get(utxoSetHashKeyName)
+	if serialized == nil {
+		return nil, AssertError("database does not contain the utxo set " +
+			"hash")
+	}
+	setHash, err := muhash.Deserialize(serialized)
+	if err != nil {
+		return nil, database.Error{
+			ErrorCode:   database.ErrCorruption,
+			Description: fmt.Sprintf("corrupt utxo set hash: %v", err),
+		}
+	}
+	return setHash, nil
+}
+
+// dbPutUtxoSetHash uses an existing database transaction to store the state of
+// the multiset hash of the utxo set.
+func dbPutUtxoSetHash(dbTx database.Tx, setHash *muhash.MuHash) error {
+	return dbTx.Metadata().Put(utxoSetHashKeyName, setHash.Serialize())
+}
+
 // dbFetchUxtoStats fetches statistics on the current unspent trnsaction output set.
+//
+// The multiset hash of the utxo set is recalculated from the entries and
+// verified against the incrementally maintained hash in the process.
 func dbFetchUtxoStats(dbTx database.Tx) (*UtxoStats, error) {
 	utxoBucket := dbTx.Metadata().Bucket(utxoSetBucketName)
 
 	var stats UtxoStats
 	transactions := make(map[chainhash.Hash]int64)
 	leaves := make([]chainhash.Hash, 0)
+	setHash := muhash.New()
 	cursor := utxoBucket.Cursor()
 
 	for ok := cursor.First(); ok; ok = cursor.Next() {
@@ -1194,6 +1240,7 @@ func dbFetchUtxoStats(dbTx database.Tx) (*UtxoStats, error) {
 		transactions[hash]++
 
 		leaves = append(leaves, chainhash.HashH(serializedUtxo))
+		setHash.Add(utxoSetHashElement(key, serializedUtxo))
 
 		// Deserialize the utxo entry and return it.
 		entry, err := deserializeUtxoEntry(serializedUtxo)
@@ -1219,6 +1266,20 @@ func dbFetchUtxoStats(dbTx database.Tx) (*UtxoStats, error) {
 
 	stats.SerializedHash = standalone.CalcMerkleRootInPlace(leaves)
 	stats.Transactions = int64(len(transactions))
+	stats.SetHash = setHash.Finalize()
+
+	// Ensure the incrementally maintained hash of the utxo set matches.
+	storedSetHash, err := dbFetchUtxoSetHash(dbTx)
+	if err != nil {
+		return nil, err
+	}
+	if storedHash := storedSetHash.Finalize(); storedHash != stats.SetHash {
+		return nil, database.Error{
+			ErrorCode: database.ErrCorruption,
+			Description: fmt.Sprintf("utxo set hash %v does not match "+
+				"calculated hash %v", storedHash, stats.SetHash),
+		}
+	}
 
 	return &stats, nil
 }
@@ -1227,7 +1288,14 @@ func dbFetchUtxoStats(dbTx database.Tx) (*UtxoStats, error) {
 // in the database based on the provided utxo view contents and state.  In
 // particular, only the entries that have been marked as modified are written
 // to the database.
+//
+// The multiset hash of the utxo set is updated accordingly.
 func dbPutUtxoView(dbTx database.Tx, view *UtxoViewpoint) error {
+	setHash, err := dbFetchUtxoSetHash(dbTx)
+	if err != nil {
+		return err
+	}
+
 	utxoBucket := dbTx.Metadata().Bucket(utxoSetBucketName)
 	for txHashIter, entry := range view.entries {
 		// No need to update the database if the entry was not modified.
@@ -1247,6 +1315,11 @@ func dbPutUtxoView(dbTx database.Tx, view *UtxoViewpoint) error {
 		// data to change out from under the put/delete funcs below.
 		txHash := txHashIter
 
+		// Remove the existing entry from the utxo set hash.
+		if existing := utxoBucket.Get(txHash[:]); existing != nil {
+			setHash.Remove(utxoSetHashElement(txHash[:], existing))
+		}
+
 		// Remove the utxo entry if it is now fully spent.
 		if serialized == nil {
 			if err := utxoBucket.Delete(txHash[:]); err != nil {
@@ -1262,9 +1335,10 @@ func dbPutUtxoView(dbTx database.Tx, view *UtxoViewpoint) error {
 		if err != nil {
 			return err
 		}
+		setHash.Add(utxoSetHashElement(txHash[:], serialized))
 	}
 
-	return nil
+	return dbPutUtxoSetHash(dbTx, setHash)
 }
 
 // -----------------------------------------------------------------------------
@@ -1320,6 +1394,63 @@ func dbPutGCSFilter(dbTx database.Tx, blockHash *chainhash.Hash, filter *gcs.Fil
 	return filterBucket.Put(blockHash[:], serialized)
 }
 
+// -----------------------------------------------------------------------------
+// The block receive times consist of an entry for each block that has been
+// stored in the database since the receive times were introduced which houses
+// the local time the block was received.
+//
+// The serialized key format is:
+//
+//   <block hash>
+//
+//   Field           Type              Size
+//   block hash      chainhash.Hash    chainhash.HashSize
+//
+// The serialized value format is:
+//
+//   <receive time>
+//
+//   Field           Type     Size
+//   receive time    int64    8 bytes (nanoseconds since the unix epoch)
+//
+// -----------------------------------------------------------------------------
+
+// dbFetchBlockReceiveTime fetches the local time the passed block was
+// received.
+//
+// When there is no entry for the provided hash, a zero time and nil error will
+// be returned.
+func dbFetchBlockReceiveTime(dbTx database.Tx, blockHash *chainhash.Hash) (time.Time, error) {
+	bucket := dbTx.Metadata().Bucket(blockReceiveTimeBucketName)
+	serialized := bucket.Get(blockHash[:])
+	if serialized == nil {
+		return time.Time{}, nil
+	}
+	if len(serialized) != 8 {
+		return time.Time{}, database.Error{
+			ErrorCode: database.ErrCorruption,
+			Description: fmt.Sprintf("corrupt receive time for %v: "+
+				"unexpected length %d", blockHash, len(serialized)),
+		}
+	}
+
+	return time.Unix(0, int64(byteOrder.Uint64(serialized))), nil
+}
+
+// dbPutBlockReceiveTime uses an existing database transaction to store the
+// provided local time the passed block was received unless a receive time is
+// already stored for it.
+func dbPutBlockReceiveTime(dbTx database.Tx, blockHash *chainhash.Hash, receiveTime time.Time) error {
+	bucket := dbTx.Metadata().Bucket(blockReceiveTimeBucketName)
+	if bucket.Get(blockHash[:]) != nil {
+		return nil
+	}
+
+	var serialized [8]byte
+	byteOrder.PutUint64(serialized[:], uint64(receiveTime.UnixNano()))
+	return bucket.Put(blockHash[:], serialized[:])
+}
+
 // -----------------------------------------------------------------------------
 // The database information contains information about the version and date
 // of the blockchain database.
@@ -1622,6 +1753,12 @@ func (b *BlockChain) createChainState() error {
 			return err
 		}
 
+		// Store the hash of the empty utxo set.
+		err = dbPutUtxoSetHash(dbTx, muhash.New())
+		if err != nil {
+			return err
+		}
+
 		// Add the genesis block to the block index.
 		err = dbPutBlockNode(dbTx, node)
 		if err != nil {
@@ -1659,7 +1796,16 @@ func (b *BlockChain) createChainState() error {
 		if err != nil {
 			return err
 		}
-		return dbPutGCSFilter(dbTx, &b.chainParams.GenesisHash, genesisFilter)
+		err = dbPutGCSFilter(dbTx, &b.chainParams.GenesisHash, genesisFilter)
+		if err != nil {
+			return err
+		}
+
+		// Create the bucket that houses the block receive times.  Note that
+		// the genesis block is intentionally not given a receive time since
+		// it is never received.
+		_, err = meta.CreateBucket(blockReceiveTimeBucketName)
+		return err
 	})
 	return err
 }
@@ -95,6 +95,16 @@ func TestErrNotInMainChain(t *testing.T) {
 	}
 }
 
+// errorAsType returns whether the passed error, or any error it wraps, has the
+// same concrete type as the provided target error.  A nil target only matches a
+// nil error.
+func errorAsType(err, target error) bool {
+	if target == nil {
+		return err == nil
+	}
+	return errors.As(err, reflect.New(reflect.TypeOf(target)).Interface())
+}
+
 // TestBlockIndexSerialization ensures serializing and deserializing block index
 // entries works as expected.
 func TestBlockIndexSerialization(t *testing.T) {
@@ -331,7 +341,7 @@ func TestBlockIndexDecodeErrors(t *testing.T) {
 		// Ensure the expected error type is returned.
 		gotBytesRead, err := decodeBlockIndexEntry(test.serialized,
 			&test.entry)
-		if !errors.As(err, &test.errType) {
+		if !errorAsType(err, test.errType) {
 			t.Errorf("decodeBlockIndexEntry (%s): expected error "+
 				"type does not match - got %T, want %T",
 				test.name, err, test.errType)
@@ -540,7 +550,7 @@ func TestStxoDecodeErrors(t *testing.T) {
 		// Ensure the expected error type is returned.
 		gotBytesRead, err := decodeSpentTxOut(test.serialized,
 			&test.stxo, test.stxo.amount, test.stxo.height, test.stxo.index)
-		if !errors.As(err, &test.errType) {
+		if !errorAsType(err, test.errType) {
 			t.Errorf("decodeSpentTxOut (%s): expected error type "+
 				"does not match - got %T, want %T", test.name,
 				err, test.errType)
@@ -880,7 +890,7 @@ func TestSpendJournalErrors(t *testing.T) {
 		// slice is nil.
 		stxos, err := deserializeSpendJournalEntry(test.serialized,
 			test.blockTxns)
-		if !errors.As(err, &test.errType) {
+		if !errorAsType(err, test.errType) {
 			t.Errorf("deserializeSpendJournalEntry (%s): expected "+
 				"error type does not match - got %T, want %T",
 				test.name, err, test.errType)
@@ -1345,7 +1355,7 @@ func TestUtxoEntryDeserializeErrors(t *testing.T) {
 		// Ensure the expected error type is returned and the returned
 		// entry is nil.
 		entry, err := deserializeUtxoEntry(test.serialized)
-		if !errors.As(err, &test.errType) {
+		if !errorAsType(err, test.errType) {
 			t.Errorf("deserializeUtxoEntry (%s): expected error "+
 				"type does not match - got %T, want %T",
 				test.name, err, test.errType)
@@ -1457,7 +1467,7 @@ func TestBestChainStateDeserializeErrors(t *testing.T) {
 	for _, test := range tests {
 		// Ensure the expected error type and code is returned.
 		_, err := deserializeBestChainState(test.serialized)
-		if !errors.As(err, &test.errType) {
+		if !errorAsType(err, test.errType) {
 			t.Errorf("deserializeBestChainState (%s): expected "+
 				"error type does not match - got %T, want %T",
 				test.name, err, test.errType)
@@ -93,6 +93,23 @@ func (b *BlockChain) maybeUpdateMostRecentCheckpoint(node *blockNode) {
 	}
 }
 
+// isAssumedValid returns whether or not the provided block node is the assumed
+// valid block or one of its ancestors, which means its scripts do not need to
+// be validated.  It will always return false when there is no assumed valid
+// block or its header is not yet known.
+//
+// This function MUST be called with the chain state lock held (for reads).
+func (b *BlockChain) isAssumedValid(node *blockNode) bool {
+	if b.assumeValid == *zeroHash {
+		return false
+	}
+	assumeValidNode := b.index.LookupNode(&b.assumeValid)
+	if assumeValidNode == nil {
+		return false
+	}
+	return assumeValidNode.Ancestor(node.height) == node
+}
+
 // isNonstandardTransaction determines whether a transaction contains any
 // scripts which are not one of the standard types.
 func isNonstandardTransaction(tx *dcrutil.Tx) bool {
@@ -16,35 +16,43 @@ import (
 	"github.com/decred/dcrd/wire"
 )
 
-var (
-	// bigZero is 0 represented as a big.Int.  It is defined here to avoid
-	// the overhead of creating it multiple times.
-	bigZero = big.NewInt(0)
-)
+// workDiffNode wraps a block node to implement the standalone.WorkDiffNode
+// interface so the required difficulty can be calculated by the standalone
+// implementation of the difficulty retarget rules.
+type workDiffNode blockNode
+
+// Ensure workDiffNode implements the standalone.WorkDiffNode interface.
+var _ standalone.WorkDiffNode = (*workDiffNode)(nil)
 
-// findPrevTestNetDifficulty returns the difficulty of the previous block which
-// did not have the special testnet minimum difficulty rule applied.
+// Height returns the height of the block.
 //
-// This function MUST be called with the chain state lock held (for writes).
-func (b *BlockChain) findPrevTestNetDifficulty(startNode *blockNode) uint32 {
-	// Search backwards through the chain for the last block without
-	// the special rule applied.
-	blocksPerRetarget := b.chainParams.WorkDiffWindowSize *
-		b.chainParams.WorkDiffWindows
-	iterNode := startNode
-	for iterNode != nil && iterNode.height%blocksPerRetarget != 0 &&
-		iterNode.bits == b.chainParams.PowLimitBits {
-
-		iterNode = iterNode.parent
-	}
+// This is part of the standalone.WorkDiffNode interface.
+func (n *workDiffNode) Height() int64 {
+	return n.height
+}
+
+// Bits returns the target difficulty bits of the block.
+//
+// This is part of the standalone.WorkDiffNode interface.
+func (n *workDiffNode) Bits() uint32 {
+	return n.bits
+}
+
+// Timestamp returns the timestamp of the block.
+//
+// This is part of the standalone.WorkDiffNode interface.
+func (n *workDiffNode) Timestamp() int64 {
+	return n.timestamp
+}
 
-	// Return the found difficulty or the minimum difficulty if no
-	// appropriate block was found.
-	lastBits := b.chainParams.PowLimitBits
-	if iterNode != nil {
-		lastBits = iterNode.bits
+// Parent returns the parent of the block or nil for the genesis block.
+//
+// This is part of the standalone.WorkDiffNode interface.
+func (n *workDiffNode) Parent() standalone.WorkDiffNode {
+	if n.parent == nil {
+		return nil
 	}
-	return lastBits
+	return (*workDiffNode)(n.parent)
 }
 
 // calcNextRequiredDifficulty calculates the required difficulty for the block
@@ -53,150 +61,20 @@ func (b *BlockChain) findPrevTestNetDifficulty(startNode *blockNode) uint32 {
 // the exported version uses the current best chain as the previous block node
 // while this function accepts any block node.
 func (b *BlockChain) calcNextRequiredDifficulty(curNode *blockNode, newBlockTime time.Time) (uint32, error) {
-	// Get the old difficulty; if we aren't at a block height where it changes,
-	// just return this.
-	oldDiff := curNode.bits
-	oldDiffBig := standalone.CompactToBig(curNode.bits)
-
-	// We're not at a retarget point, return the oldDiff.
-	if (curNode.height+1)%b.chainParams.WorkDiffWindowSize != 0 {
-		// For networks that support it, allow special reduction of the
-		// required difficulty once too much time has elapsed without
-		// mining a block.
-		if b.chainParams.ReduceMinDifficulty {
-			// Return minimum difficulty when more than the desired
-			// amount of time has elapsed without mining a block.
-			reductionTime := int64(b.chainParams.MinDiffReductionTime /
-				time.Second)
-			allowMinTime := curNode.timestamp + reductionTime
-			if newBlockTime.Unix() > allowMinTime {
-				return b.chainParams.PowLimitBits, nil
-			}
-
-			// The block was mined within the desired timeframe, so
-			// return the difficulty for the last block which did
-			// not have the special minimum difficulty rule applied.
-			return b.findPrevTestNetDifficulty(curNode), nil
-		}
-
-		return oldDiff, nil
-	}
-
-	// Declare some useful variables.
-	RAFBig := big.NewInt(b.chainParams.RetargetAdjustmentFactor)
-	nextDiffBigMin := standalone.CompactToBig(curNode.bits)
-	nextDiffBigMin.Div(nextDiffBigMin, RAFBig)
-	nextDiffBigMax := standalone.CompactToBig(curNode.bits)
-	nextDiffBigMax.Mul(nextDiffBigMax, RAFBig)
-
-	alpha := b.chainParams.WorkDiffAlpha
-
-	// Number of nodes to traverse while calculating difficulty.
-	nodesToTraverse := (b.chainParams.WorkDiffWindowSize *
-		b.chainParams.WorkDiffWindows)
-
-	// Initialize bigInt slice for the percentage changes for each window period
-	// above or below the target.
-	windowChanges := make([]*big.Int, b.chainParams.WorkDiffWindows)
-
-	// Regress through all of the previous blocks and store the percent changes
-	// per window period; use bigInts to emulate 64.32 bit fixed point.
-	var olderTime, windowPeriod int64
-	var weights uint64
-	oldNode := curNode
-	recentTime := curNode.timestamp
-
-	for i := int64(0); ; i++ {
-		// Store and reset after reaching the end of every window period.
-		if i%b.chainParams.WorkDiffWindowSize == 0 && i != 0 {
-			olderTime = oldNode.timestamp
-			timeDifference := recentTime - olderTime
-
-			// Just assume we're at the target (no change) if we've
-			// gone all the way back to the genesis block.
-			if oldNode.height == 0 {
-				timeDifference = int64(b.chainParams.TargetTimespan /
-					time.Second)
-			}
-
-			timeDifBig := big.NewInt(timeDifference)
-			timeDifBig.Lsh(timeDifBig, 32) // Add padding
-			targetTemp := big.NewInt(int64(b.chainParams.TargetTimespan /
-				time.Second))
-
-			windowAdjusted := targetTemp.Div(timeDifBig, targetTemp)
-
-			// Weight it exponentially. Be aware that this could at some point
-			// overflow if alpha or the number of blocks used is really large.
-			windowAdjusted = windowAdjusted.Lsh(windowAdjusted,
-				uint((b.chainParams.WorkDiffWindows-windowPeriod)*alpha))
-
-			// Sum up all the different weights incrementally.
-			weights += 1 << uint64((b.chainParams.WorkDiffWindows-windowPeriod)*
-				alpha)
+	nextDiffBits := standalone.CalcNextRequiredDifficulty(b.chainParams,
+		(*workDiffNode)(curNode), newBlockTime)
 
-			// Store it in the slice.
-			windowChanges[windowPeriod] = windowAdjusted
-
-			windowPeriod++
-
-			recentTime = olderTime
-		}
-
-		if i == nodesToTraverse {
-			break // Exit for loop when we hit the end.
-		}
-
-		// Get the previous node while staying at the genesis block as
-		// needed.
-		if oldNode.parent != nil {
-			oldNode = oldNode.parent
-		}
-	}
-
-	// Sum up the weighted window periods.
-	weightedSum := big.NewInt(0)
-	for i := int64(0); i < b.chainParams.WorkDiffWindows; i++ {
-		weightedSum.Add(weightedSum, windowChanges[i])
-	}
-
-	// Divide by the sum of all weights.
-	weightsBig := big.NewInt(int64(weights))
-	weightedSumDiv := weightedSum.Div(weightedSum, weightsBig)
-
-	// Multiply by the old diff.
-	nextDiffBig := weightedSumDiv.Mul(weightedSumDiv, oldDiffBig)
-
-	// Right shift to restore the original padding (restore non-fixed point).
-	nextDiffBig = nextDiffBig.Rsh(nextDiffBig, 32)
-
-	// Check to see if we're over the limits for the maximum allowable retarget;
-	// if we are, return the maximum or minimum except in the case that oldDiff
-	// is zero.
-	if oldDiffBig.Cmp(bigZero) == 0 { // This should never really happen,
-		nextDiffBig.Set(nextDiffBig) // but in case it does...
-	} else if nextDiffBig.Cmp(bigZero) == 0 {
-		nextDiffBig.Set(b.chainParams.PowLimit)
-	} else if nextDiffBig.Cmp(nextDiffBigMax) == 1 {
-		nextDiffBig.Set(nextDiffBigMax)
-	} else if nextDiffBig.Cmp(nextDiffBigMin) == -1 {
-		nextDiffBig.Set(nextDiffBigMin)
-	}
-
-	// Limit new value to the proof of work limit.
-	if nextDiffBig.Cmp(b.chainParams.PowLimit) > 0 {
-		nextDiffBig.Set(b.chainParams.PowLimit)
-	}
-
-	// Log new target difficulty and return it.  The new target logging is
-	// intentionally converting the bits back to a number instead of using
+	// Log new target difficulty at retarget points.  The new target logging
+	// is intentionally converting the bits back to a number instead of using
 	// newTarget since conversion to the compact representation loses
 	// precision.
-	nextDiffBits := standalone.BigToCompact(nextDiffBig)
-	log.Debugf("Difficulty retarget at block height %d", curNode.height+1)
-	log.Debugf("Old target %08x (%064x)", curNode.bits, oldDiffBig)
-	log.Debugf("New target %08x (%064x)", nextDiffBits, standalone.CompactToBig(
-		nextDiffBits))
+	if (curNode.height+1)%b.chainParams.WorkDiffWindowSize == 0 {
+		log.Debugf("Difficulty retarget at block height %d", curNode.height+1)
+		log.Debugf("Old target %08x (%064x)", curNode.bits,
+			standalone.CompactToBig(curNode.bits))
+		log.Debugf("New target %08x (%064x)", nextDiffBits,
+			standalone.CompactToBig(nextDiffBits))
+	}
 
 	return nextDiffBits, nil
 }
@@ -217,6 +95,22 @@ func (b *BlockChain) CalcNextRequiredDifficulty(hash *chainhash.Hash, timestamp
 	return difficulty, err
 }
 
+// SimulateRequiredDifficulty projects the required difficulty for the provided
+// number of blocks after the end of the current best chain assuming every block
+// is found by a constant hash rate of the provided number of hashes per second.
+// The projected difficulty is returned for every simulated block that is at a
+// difficulty retarget point.
+//
+// See standalone.SimulateWorkDiff for details on how the simulation is
+// performed.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) SimulateRequiredDifficulty(hashesPerSec *big.Int, numBlocks int64) []standalone.SimulatedWorkDiff {
+	tip := b.bestChain.Tip()
+	return standalone.SimulateWorkDiff(b.chainParams, (*workDiffNode)(tip),
+		hashesPerSec, numBlocks)
+}
+
 // mergeDifficulty takes an original stake difficulty and two new, scaled
 // stake difficulties, merges the new difficulties, and outputs a new
 // merged stake difficulty.
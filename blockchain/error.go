@@ -621,9 +621,15 @@ func (e ErrorCode) String() string {
 // rules.  The caller can use type assertions to determine if a failure was
 // specifically due to a rule violation and access the ErrorCode field to
 // ascertain the specific reason for the rule violation.
+//
+// Violations that are caused by a specific transaction input additionally
+// record the index of that input which may be obtained via InputIndex.
 type RuleError struct {
 	ErrorCode   ErrorCode // Describes the kind of error
 	Description string    // Human readable description of the issue
+
+	inputIndex int  // Index of the offending transaction input
+	hasInput   bool // Whether or not inputIndex is set
 }
 
 // Error satisfies the error interface and prints human-readable errors.
@@ -631,11 +637,29 @@ func (e RuleError) Error() string {
 	return e.Description
 }
 
+// InputIndex returns the index of the transaction input responsible for the
+// rule violation along with whether or not the violation is attributable to a
+// specific input at all.
+func (e RuleError) InputIndex() (int, bool) {
+	return e.inputIndex, e.hasInput
+}
+
 // ruleError creates a RuleError given a set of arguments.
 func ruleError(c ErrorCode, desc string) RuleError {
 	return RuleError{ErrorCode: c, Description: desc}
 }
 
+// ruleInputError creates a RuleError that is attributable to the transaction
+// input at the provided index given a set of arguments.
+func ruleInputError(c ErrorCode, inputIdx int, desc string) RuleError {
+	return RuleError{
+		ErrorCode:   c,
+		Description: desc,
+		inputIndex:  inputIdx,
+		hasInput:    true,
+	}
+}
+
 // IsErrorCode returns whether or not the provided error is a rule error with
 // the provided error code.
 func IsErrorCode(err error, c ErrorCode) bool {
@@ -163,6 +163,41 @@ func TestRuleError(t *testing.T) {
 	}
 }
 
+// TestRuleErrorInputIndex ensures the input index associated with rule errors
+// is reported as intended.
+func TestRuleErrorInputIndex(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       RuleError
+		wantIndex int
+		wantOk    bool
+	}{{
+		name:      "no input",
+		err:       ruleError(ErrSpendTooHigh, ""),
+		wantIndex: 0,
+		wantOk:    false,
+	}, {
+		name:      "first input",
+		err:       ruleInputError(ErrMissingTxOut, 0, ""),
+		wantIndex: 0,
+		wantOk:    true,
+	}, {
+		name:      "third input",
+		err:       ruleInputError(ErrScriptValidation, 2, ""),
+		wantIndex: 2,
+		wantOk:    true,
+	}}
+
+	for _, test := range tests {
+		idx, ok := test.err.InputIndex()
+		if idx != test.wantIndex || ok != test.wantOk {
+			t.Errorf("%s: unexpected input index -- got (%d, %v), "+
+				"want (%d, %v)", test.name, idx, ok, test.wantIndex,
+				test.wantOk)
+		}
+	}
+}
+
 // TestIsErrorCode ensures IsErrorCode works as intended.
 func TestIsErrorCode(t *testing.T) {
 	tests := []struct {
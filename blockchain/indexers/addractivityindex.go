@@ -0,0 +1,344 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package indexers
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+
+	"github.com/decred/dcrd/blockchain/stake/v3"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/database/v2"
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/decred/dcrd/txscript/v3"
+)
+
+const (
+	// addrActivityIndexName is the human-readable name for the index.
+	addrActivityIndexName = "address activity index"
+
+	// addrActivityIndexVersion is the current version of the address
+	// activity index.
+	addrActivityIndexVersion = 1
+
+	// addrActivityKeySize is the number of bytes an entry key in the
+	// address activity index consumes.  It consists of the address key + 4
+	// bytes for the height of the block.
+	addrActivityKeySize = addrKeySize + 4
+)
+
+var (
+	// addrActivityIndexKey is the key of the address activity index and
+	// the db bucket used to house it.
+	addrActivityIndexKey = []byte("addractivityidx")
+)
+
+// AddrActivity describes the range of main chain blocks an address has been
+// involved in.
+type AddrActivity struct {
+	// FirstSeen is the height of the first block that involves the
+	// address.
+	FirstSeen int64
+
+	// LastSeen is the height of the most recent block that involves the
+	// address.
+	LastSeen int64
+}
+
+// AddrActivityIndex implements an index which records the heights of the main
+// chain blocks that involve each address in order to efficiently determine
+// the first and last time an address was seen on the chain.
+//
+// The index consists of an entry for every address and block pair with an
+// empty value.  The key is the address key followed by the big endian height
+// of the block, so all entries for an address are ordered by height and the
+// first and last entries are found with a single seek.  Keeping an entry per
+// block, as opposed to only the first and last heights, is what allows blocks
+// to be disconnected without having to rescan the chain.
+//
+// Unlike the exists address index, only transactions in blocks of the main
+// chain are included.
+type AddrActivityIndex struct {
+	db          database.DB
+	chainParams *chaincfg.Params
+}
+
+// NewAddrActivityIndex returns a new instance of an indexer that is used to
+// track the first and last heights at which each address is seen in the main
+// chain.
+//
+// It implements the Indexer interface which plugs into the IndexManager that in
+// turn is used by the blockchain package.  This allows the index to be
+// seamlessly maintained along with the chain.
+func NewAddrActivityIndex(db database.DB, chainParams *chaincfg.Params) *AddrActivityIndex {
+	return &AddrActivityIndex{
+		db:          db,
+		chainParams: chainParams,
+	}
+}
+
+// Ensure the AddrActivityIndex type implements the Indexer interface.
+var _ Indexer = (*AddrActivityIndex)(nil)
+
+// Ensure the AddrActivityIndex type implements the NeedsInputser interface.
+var _ NeedsInputser = (*AddrActivityIndex)(nil)
+
+// NeedsInputs signals that the index requires the referenced inputs in order
+// to properly create the index.
+//
+// This implements the NeedsInputser interface.
+func (idx *AddrActivityIndex) NeedsInputs() bool {
+	return true
+}
+
+// Init is only provided to satisfy the Indexer interface as there is nothing to
+// initialize for this index.
+//
+// This is part of the Indexer interface.
+func (idx *AddrActivityIndex) Init() error {
+	// Nothing to do.
+	return nil
+}
+
+// Key returns the database key to use for the index as a byte slice.
+//
+// This is part of the Indexer interface.
+func (idx *AddrActivityIndex) Key() []byte {
+	return addrActivityIndexKey
+}
+
+// Name returns the human-readable name of the index.
+//
+// This is part of the Indexer interface.
+func (idx *AddrActivityIndex) Name() string {
+	return addrActivityIndexName
+}
+
+// Version returns the current version of the index.
+//
+// This is part of the Indexer interface.
+func (idx *AddrActivityIndex) Version() uint32 {
+	return addrActivityIndexVersion
+}
+
+// Create is invoked when the indexer manager determines the index needs
+// to be created for the first time.  It creates the bucket for the address
+// activity index.
+//
+// This is part of the Indexer interface.
+func (idx *AddrActivityIndex) Create(dbTx database.Tx) error {
+	_, err := dbTx.Metadata().CreateBucket(addrActivityIndexKey)
+	return err
+}
+
+// addrActivityKey returns the key of the address activity index entry for the
+// provided address key and block height.
+//
+// NOTE: The height is serialized big endian, unlike the other numeric fields
+// of the indexes, so the entries for an address are sorted by height.
+func addrActivityKey(addrKey [addrKeySize]byte, height uint32) [addrActivityKeySize]byte {
+	var key [addrActivityKeySize]byte
+	copy(key[:], addrKey[:])
+	binary.BigEndian.PutUint32(key[addrKeySize:], height)
+	return key
+}
+
+// dbFetchAddrActivity uses an existing database bucket to fetch the first and
+// last heights recorded for the provided address key.  Nil is returned when
+// there are no entries for the address.
+func dbFetchAddrActivity(bucket database.Bucket, addrKey [addrKeySize]byte) *AddrActivity {
+	cursor := bucket.Cursor()
+
+	// The first entry for the address is the first key at or after the
+	// entry with the lowest possible height.
+	firstKey := addrActivityKey(addrKey, 0)
+	if !cursor.Seek(firstKey[:]) || !bytes.HasPrefix(cursor.Key(), addrKey[:]) {
+		return nil
+	}
+	firstSeen := binary.BigEndian.Uint32(cursor.Key()[addrKeySize:])
+
+	// The last entry for the address is either the entry with the highest
+	// possible height or the entry just before the first key after it.
+	lastKey := addrActivityKey(addrKey, ^uint32(0))
+	var found bool
+	switch {
+	case !cursor.Seek(lastKey[:]):
+		found = cursor.Last()
+	case bytes.Equal(cursor.Key(), lastKey[:]):
+		found = true
+	default:
+		found = cursor.Prev()
+	}
+	if !found || !bytes.HasPrefix(cursor.Key(), addrKey[:]) {
+		// This should never happen since the first entry was found, but
+		// be paranoid.
+		return &AddrActivity{
+			FirstSeen: int64(firstSeen),
+			LastSeen:  int64(firstSeen),
+		}
+	}
+	lastSeen := binary.BigEndian.Uint32(cursor.Key()[addrKeySize:])
+
+	return &AddrActivity{
+		FirstSeen: int64(firstSeen),
+		LastSeen:  int64(lastSeen),
+	}
+}
+
+// AddressActivity returns the first and last heights at which the provided
+// address was seen in the main chain.  Nil is returned when the address has
+// not been seen.
+//
+// This function is safe for concurrent access.
+func (idx *AddrActivityIndex) AddressActivity(addr dcrutil.Address) (*AddrActivity, error) {
+	addrKey, err := addrToKey(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	var activity *AddrActivity
+	err = idx.db.View(func(dbTx database.Tx) error {
+		bucket := dbTx.Metadata().Bucket(addrActivityIndexKey)
+		activity = dbFetchAddrActivity(bucket, addrKey)
+		return nil
+	})
+	return activity, err
+}
+
+// addPkScriptAddrs adds the keys of all standard addresses in the passed public
+// key script to the provided set.
+func (idx *AddrActivityIndex) addPkScriptAddrs(addrKeys map[[addrKeySize]byte]struct{}, scriptVersion uint16, pkScript []byte, isSStx bool) {
+	class, addrs, _, err := txscript.ExtractPkScriptAddrs(scriptVersion,
+		pkScript, idx.chainParams)
+	if err != nil {
+		// Non-standard outputs are skipped.
+		return
+	}
+
+	if isSStx && class == txscript.NullDataTy {
+		addr, err := stake.AddrFromSStxPkScrCommitment(pkScript,
+			idx.chainParams)
+		if err != nil {
+			return
+		}
+
+		addrs = append(addrs, addr)
+	}
+
+	for _, addr := range addrs {
+		addrKey, err := addrToKey(addr)
+		if err != nil {
+			// Ignore unsupported address types.
+			continue
+		}
+		addrKeys[addrKey] = struct{}{}
+	}
+}
+
+// blockAddrs returns the keys of all standard addresses involved in the
+// regular and stake transactions of the passed block, including the addresses
+// of the outputs they spend.
+func (idx *AddrActivityIndex) blockAddrs(block *dcrutil.Block, prevScripts PrevScripter) map[[addrKeySize]byte]struct{} {
+	addrKeys := make(map[[addrKeySize]byte]struct{})
+	addPrevScripts := func(tx *dcrutil.Tx, skipFirst bool) {
+		for i, txIn := range tx.MsgTx().TxIn {
+			if skipFirst && i == 0 {
+				continue
+			}
+
+			// The input should always be available since the index
+			// contract requires it, however, be safe and simply
+			// ignore any missing entries.
+			origin := &txIn.PreviousOutPoint
+			version, pkScript, ok := prevScripts.PrevScript(origin)
+			if !ok {
+				log.Warnf("Missing input %v:%d for tx %v while indexing "+
+					"block %v (height %v)\n", origin, origin.Tree,
+					tx.Hash(), block.Hash(), block.Height())
+				continue
+			}
+			idx.addPkScriptAddrs(addrKeys, version, pkScript, false)
+		}
+	}
+
+	for txIdx, tx := range block.Transactions() {
+		// Coinbases do not reference any inputs.
+		if txIdx != 0 {
+			addPrevScripts(tx, false)
+		}
+		for _, txOut := range tx.MsgTx().TxOut {
+			idx.addPkScriptAddrs(addrKeys, txOut.Version, txOut.PkScript,
+				false)
+		}
+	}
+	for _, tx := range block.STransactions() {
+		msgTx := tx.MsgTx()
+
+		// Skip stakebases.
+		addPrevScripts(tx, stake.IsSSGen(msgTx))
+
+		isSStx := stake.IsSStx(msgTx)
+		for _, txOut := range msgTx.TxOut {
+			idx.addPkScriptAddrs(addrKeys, txOut.Version, txOut.PkScript,
+				isSStx)
+		}
+	}
+
+	return addrKeys
+}
+
+// ConnectBlock is invoked by the index manager when a new block has been
+// connected to the main chain.  This indexer adds an entry for each address
+// the transactions in the block involve.
+//
+// This is part of the Indexer interface.
+func (idx *AddrActivityIndex) ConnectBlock(dbTx database.Tx, block, parent *dcrutil.Block, prevScripts PrevScripter) error {
+	// NOTE: The fact that the block can disapprove the regular tree of the
+	// previous block is ignored for this index because the disapproved
+	// transactions still exist within the previous block and therefore the
+	// addresses they involve were still seen at its height.
+
+	bucket := dbTx.Metadata().Bucket(addrActivityIndexKey)
+	height := block.MsgBlock().Header.Height
+	for addrKey := range idx.blockAddrs(block, prevScripts) {
+		key := addrActivityKey(addrKey, height)
+		if err := bucket.Put(key[:], nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DisconnectBlock is invoked by the index manager when a block has been
+// disconnected from the main chain.  This indexer removes the entry for each
+// address the transactions in the block involve.
+//
+// This is part of the Indexer interface.
+func (idx *AddrActivityIndex) DisconnectBlock(dbTx database.Tx, block, parent *dcrutil.Block, prevScripts PrevScripter) error {
+	bucket := dbTx.Metadata().Bucket(addrActivityIndexKey)
+	height := block.MsgBlock().Header.Height
+	for addrKey := range idx.blockAddrs(block, prevScripts) {
+		key := addrActivityKey(addrKey, height)
+		if err := bucket.Delete(key[:]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DropAddrActivityIndex drops the address activity index from the provided
+// database if it exists.
+func DropAddrActivityIndex(ctx context.Context, db database.DB) error {
+	return dropFlatIndex(ctx, db, addrActivityIndexKey, addrActivityIndexName)
+}
+
+// DropIndex drops the address activity index from the provided database if it
+// exists.
+func (*AddrActivityIndex) DropIndex(ctx context.Context, db database.DB) error {
+	return DropAddrActivityIndex(ctx, db)
+}
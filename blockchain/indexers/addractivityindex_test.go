@@ -0,0 +1,153 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package indexers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/database/v2"
+	_ "github.com/decred/dcrd/database/v2/ffldb"
+	"github.com/decred/dcrd/dcrec"
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/decred/dcrd/txscript/v3"
+	"github.com/decred/dcrd/wire"
+)
+
+// mockPrevScripter provides a mock source of previous transaction scripts by
+// implementing the PrevScripter interface.
+type mockPrevScripter map[wire.OutPoint][]byte
+
+// PrevScript returns the mocked script for the provided outpoint.
+//
+// This is part of the PrevScripter interface.
+func (m mockPrevScripter) PrevScript(op *wire.OutPoint) (uint16, []byte, bool) {
+	script, ok := m[*op]
+	return 0, script, ok
+}
+
+// TestAddrActivityIndex ensures the address activity index reports the first
+// and last heights addresses are seen at as blocks are connected and
+// disconnected.
+func TestAddrActivityIndex(t *testing.T) {
+	t.Parallel()
+
+	params := chaincfg.RegNetParams()
+	dbPath := filepath.Join(os.TempDir(), "testaddractivityindex")
+	_ = os.RemoveAll(dbPath)
+	db, err := database.Create("ffldb", dbPath, params.Net)
+	if err != nil {
+		t.Fatalf("unable to create database: %v", err)
+	}
+	defer os.RemoveAll(dbPath)
+	defer db.Close()
+
+	idx := NewAddrActivityIndex(db, params)
+	if err := db.Update(idx.Create); err != nil {
+		t.Fatalf("unable to create index: %v", err)
+	}
+
+	// Create addresses and the scripts that pay to them.
+	addrs := make([]dcrutil.Address, 4)
+	scripts := make([][]byte, len(addrs))
+	for i := range addrs {
+		var hash [20]byte
+		hash[0] = byte(i + 1)
+		addr, err := dcrutil.NewAddressPubKeyHash(hash[:], params,
+			dcrec.STEcdsaSecp256k1)
+		if err != nil {
+			t.Fatalf("unable to create address: %v", err)
+		}
+		addrs[i] = addr
+		scripts[i], err = txscript.PayToAddrScript(addr)
+		if err != nil {
+			t.Fatalf("unable to create script: %v", err)
+		}
+	}
+	a, b, c, d := 0, 1, 2, 3
+
+	// makeBlock returns a block at the provided height with a coinbase that
+	// pays to the provided address and, when a spent outpoint is provided,
+	// a transaction that spends it and pays to the other address.
+	makeBlock := func(height uint32, coinbaseAddr int, spend *wire.OutPoint, payAddr int) *dcrutil.Block {
+		coinbase := wire.NewMsgTx()
+		coinbase.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{},
+			wire.MaxPrevOutIndex, wire.TxTreeRegular), 0, nil))
+		coinbase.AddTxOut(wire.NewTxOut(1, scripts[coinbaseAddr]))
+		msgBlock := &wire.MsgBlock{
+			Header:       wire.BlockHeader{Height: height},
+			Transactions: []*wire.MsgTx{coinbase},
+		}
+		if spend != nil {
+			tx := wire.NewMsgTx()
+			tx.AddTxIn(wire.NewTxIn(spend, 1, nil))
+			tx.AddTxOut(wire.NewTxOut(1, scripts[payAddr]))
+			msgBlock.Transactions = append(msgBlock.Transactions, tx)
+		}
+		return dcrutil.NewBlock(msgBlock)
+	}
+	block1 := makeBlock(1, a, nil, 0)
+	spent := wire.OutPoint{Hash: block1.Transactions()[0].MsgTx().TxHash()}
+	prevScripts := mockPrevScripter{spent: scripts[a]}
+	block2 := makeBlock(2, c, &spent, b)
+	block5 := makeBlock(5, a, nil, 0)
+
+	connect := func(block *dcrutil.Block) {
+		t.Helper()
+		err := db.Update(func(dbTx database.Tx) error {
+			return idx.ConnectBlock(dbTx, block, nil, prevScripts)
+		})
+		if err != nil {
+			t.Fatalf("unable to connect block %d: %v", block.Height(), err)
+		}
+	}
+	disconnect := func(block *dcrutil.Block) {
+		t.Helper()
+		err := db.Update(func(dbTx database.Tx) error {
+			return idx.DisconnectBlock(dbTx, block, nil, prevScripts)
+		})
+		if err != nil {
+			t.Fatalf("unable to disconnect block %d: %v", block.Height(),
+				err)
+		}
+	}
+	check := func(desc string, addr int, want *AddrActivity) {
+		t.Helper()
+		got, err := idx.AddressActivity(addrs[addr])
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", desc, err)
+		}
+		switch {
+		case want == nil && got != nil:
+			t.Fatalf("%s: unexpected activity %+v for unseen address",
+				desc, got)
+		case want != nil && got == nil:
+			t.Fatalf("%s: no activity for address, want %+v", desc, want)
+		case want != nil && *got != *want:
+			t.Fatalf("%s: unexpected activity -- got %+v, want %+v", desc,
+				got, want)
+		}
+	}
+
+	connect(block1)
+	connect(block2)
+	connect(block5)
+	check("spent and paid again", a, &AddrActivity{FirstSeen: 1, LastSeen: 5})
+	check("paid", b, &AddrActivity{FirstSeen: 2, LastSeen: 2})
+	check("coinbase", c, &AddrActivity{FirstSeen: 2, LastSeen: 2})
+	check("unseen", d, nil)
+
+	// Ensure disconnecting blocks restores the previous heights and
+	// removes addresses only seen in them.
+	disconnect(block5)
+	check("disconnected last seen", a, &AddrActivity{FirstSeen: 1, LastSeen: 2})
+	disconnect(block2)
+	check("disconnected spend", a, &AddrActivity{FirstSeen: 1, LastSeen: 1})
+	check("disconnected paid", b, nil)
+	check("disconnected coinbase", c, nil)
+}
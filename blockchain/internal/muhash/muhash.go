@@ -0,0 +1,153 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package muhash implements an incremental multiset hash.
+//
+// A multiset hash commits to an unordered collection of elements such that
+// elements may be added and removed in any order while only requiring
+// constant space and time per element.  Each element is mapped to a 3072-bit
+// number and the state of the hash is the product of all numbers of the added
+// elements divided by the product of all numbers of the removed elements in
+// the multiplicative group of integers modulo the prime 2^3072 - 1103717.
+//
+// Elements are mapped to numbers by hashing them with BLAKE-256 and expanding
+// the result to 384 bytes by hashing it along with a 32-bit little-endian
+// counter for each 32-byte chunk.  The final hash is the BLAKE-256 hash of the
+// 384-byte big-endian encoding of the normalized state.
+package muhash
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+const (
+	// numBytes is the number of bytes in the big-endian encoding of a number
+	// in the group.
+	numBytes = 384
+
+	// SerializeSize is the number of bytes in a serialized hash state.
+	SerializeSize = numBytes * 2
+)
+
+var (
+	// prime is the modulus of the group, 2^3072 - 1103717.
+	prime = func() *big.Int {
+		p := new(big.Int).Lsh(big.NewInt(1), numBytes*8)
+		return p.Sub(p, big.NewInt(1103717))
+	}()
+
+	// ErrMalformedState is returned by Deserialize when the provided bytes do
+	// not encode a valid hash state.
+	ErrMalformedState = errors.New("malformed multiset hash state")
+)
+
+// MuHash is an incremental multiset hash.  The zero value is not usable and
+// New must be used to create a hash of the empty set.
+//
+// A MuHash is not safe for concurrent access.
+type MuHash struct {
+	numerator   *big.Int
+	denominator *big.Int
+}
+
+// New returns a MuHash of the empty set.
+func New() *MuHash {
+	return &MuHash{
+		numerator:   big.NewInt(1),
+		denominator: big.NewInt(1),
+	}
+}
+
+// elementNum maps the provided element to a number in the group.
+func elementNum(data []byte) *big.Int {
+	var seed [chainhash.HashSize + 4]byte
+	copy(seed[:], chainhash.HashB(data))
+	var expanded [numBytes]byte
+	for i := 0; i < numBytes/chainhash.HashSize; i++ {
+		binary.LittleEndian.PutUint32(seed[chainhash.HashSize:], uint32(i))
+		chunk := chainhash.HashH(seed[:])
+		copy(expanded[i*chainhash.HashSize:], chunk[:])
+	}
+	num := new(big.Int).SetBytes(expanded[:])
+	return num.Mod(num, prime)
+}
+
+// Add adds the provided element to the set.
+func (h *MuHash) Add(data []byte) {
+	h.numerator.Mul(h.numerator, elementNum(data))
+	h.numerator.Mod(h.numerator, prime)
+}
+
+// Remove removes the provided element from the set.  Removing an element that
+// was never added results in a hash that will only match a set that has the
+// element added an equivalent number of times.
+func (h *MuHash) Remove(data []byte) {
+	h.denominator.Mul(h.denominator, elementNum(data))
+	h.denominator.Mod(h.denominator, prime)
+}
+
+// Combine adds all elements of the set represented by the provided hash to
+// the set.
+func (h *MuHash) Combine(other *MuHash) {
+	h.numerator.Mul(h.numerator, other.numerator)
+	h.numerator.Mod(h.numerator, prime)
+	h.denominator.Mul(h.denominator, other.denominator)
+	h.denominator.Mod(h.denominator, prime)
+}
+
+// normalize reduces the state to a single number by dividing the numerator by
+// the denominator and returns it.  The state is updated accordingly.
+func (h *MuHash) normalize() *big.Int {
+	if h.denominator.Cmp(big.NewInt(1)) != 0 {
+		inverse := new(big.Int).ModInverse(h.denominator, prime)
+		h.numerator.Mul(h.numerator, inverse)
+		h.numerator.Mod(h.numerator, prime)
+		h.denominator.SetInt64(1)
+	}
+	return h.numerator
+}
+
+// putNum encodes the provided number to the passed buffer as a big-endian
+// 384-byte integer.
+func putNum(buf []byte, num *big.Int) {
+	b := num.Bytes()
+	copy(buf[numBytes-len(b):numBytes], b)
+}
+
+// Finalize returns the hash of the set.  It does not modify the elements of the
+// set, so it may be called at any time.
+func (h *MuHash) Finalize() chainhash.Hash {
+	var buf [numBytes]byte
+	putNum(buf[:], h.normalize())
+	return chainhash.HashH(buf[:])
+}
+
+// Serialize returns the state of the hash such that it can be restored via
+// Deserialize.
+func (h *MuHash) Serialize() []byte {
+	buf := make([]byte, SerializeSize)
+	putNum(buf, h.numerator)
+	putNum(buf[numBytes:], h.denominator)
+	return buf
+}
+
+// Deserialize restores a hash from the provided state as returned by
+// Serialize.
+func Deserialize(serialized []byte) (*MuHash, error) {
+	if len(serialized) != SerializeSize {
+		return nil, ErrMalformedState
+	}
+	numerator := new(big.Int).SetBytes(serialized[:numBytes])
+	denominator := new(big.Int).SetBytes(serialized[numBytes:])
+	if numerator.Sign() == 0 || numerator.Cmp(prime) >= 0 ||
+		denominator.Sign() == 0 || denominator.Cmp(prime) >= 0 {
+
+		return nil, ErrMalformedState
+	}
+	return &MuHash{numerator: numerator, denominator: denominator}, nil
+}
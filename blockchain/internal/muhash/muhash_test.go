@@ -0,0 +1,123 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package muhash
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+// TestMuHash ensures the multiset hash is independent of the order elements
+// are added and removed in and that removal undoes addition.
+func TestMuHash(t *testing.T) {
+	elements := [][]byte{{0x01}, {0x02, 0x03}, {0x04}, {}}
+
+	// Ensure the hash of the empty set is stable.
+	emptyHash := New().Finalize()
+	var one [numBytes]byte
+	one[numBytes-1] = 0x01
+	if want := chainhash.HashH(one[:]); emptyHash != want {
+		t.Fatalf("unexpected empty set hash -- got %v, want %v", emptyHash,
+			want)
+	}
+
+	// Ensure the order elements are added in does not matter.
+	forward := New()
+	for _, elem := range elements {
+		forward.Add(elem)
+	}
+	reverse := New()
+	for i := len(elements) - 1; i >= 0; i-- {
+		reverse.Add(elements[i])
+	}
+	if forward.Finalize() != reverse.Finalize() {
+		t.Fatal("hash depends on order of added elements")
+	}
+	if forward.Finalize() == emptyHash {
+		t.Fatal("hash of non-empty set matches hash of empty set")
+	}
+
+	// Ensure removing elements, including before they are added, results in
+	// the hash of the remaining elements.
+	partial := New()
+	partial.Remove(elements[1])
+	for _, elem := range elements {
+		partial.Add(elem)
+	}
+	want := New()
+	want.Add(elements[0])
+	want.Add(elements[2])
+	want.Add(elements[3])
+	if partial.Finalize() != want.Finalize() {
+		t.Fatal("removing element does not undo adding it")
+	}
+	for _, elem := range [][]byte{elements[0], elements[2], elements[3]} {
+		partial.Remove(elem)
+	}
+	if partial.Finalize() != emptyHash {
+		t.Fatal("removing all elements does not result in empty set hash")
+	}
+
+	// Ensure combining the hashes of two sets results in the hash of their
+	// union.
+	first, second := New(), New()
+	first.Add(elements[0])
+	first.Add(elements[1])
+	second.Add(elements[2])
+	second.Add(elements[3])
+	first.Combine(second)
+	if first.Finalize() != forward.Finalize() {
+		t.Fatal("combined hash does not match hash of union")
+	}
+}
+
+// TestMuHashSerialize ensures hash states round trip through serialization and
+// malformed states are rejected.
+func TestMuHashSerialize(t *testing.T) {
+	h := New()
+	h.Add([]byte{0x01})
+	h.Remove([]byte{0x02})
+	serialized := h.Serialize()
+	if len(serialized) != SerializeSize {
+		t.Fatalf("unexpected serialized size -- got %d, want %d",
+			len(serialized), SerializeSize)
+	}
+
+	restored, err := Deserialize(serialized)
+	if err != nil {
+		t.Fatalf("unexpected error deserializing state: %v", err)
+	}
+	if !bytes.Equal(restored.Serialize(), serialized) {
+		t.Fatal("restored state does not match original")
+	}
+	if restored.Finalize() != h.Finalize() {
+		t.Fatal("restored hash does not match original")
+	}
+
+	tests := []struct {
+		name       string
+		serialized []byte
+	}{{
+		name:       "short",
+		serialized: serialized[:SerializeSize-1],
+	}, {
+		name:       "zero numerator",
+		serialized: append(make([]byte, numBytes), serialized[numBytes:]...),
+	}, {
+		name:       "zero denominator",
+		serialized: append(serialized[:numBytes:numBytes], make([]byte, numBytes)...),
+	}, {
+		name:       "numerator not reduced",
+		serialized: append(bytes.Repeat([]byte{0xff}, numBytes), serialized[numBytes:]...),
+	}}
+	for _, test := range tests {
+		if _, err := Deserialize(test.serialized); err != ErrMalformedState {
+			t.Errorf("%s: unexpected error -- got %v, want %v", test.name,
+				err, ErrMalformedState)
+		}
+	}
+}
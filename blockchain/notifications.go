@@ -72,6 +72,15 @@ const (
 	// NTSpentAndMissedTickets indicates newly maturing tickets from a newly
 	// accepted block.
 	NTNewTickets
+
+	// NTBlockCorrupted indicates the stored data for the associated block
+	// was detected to be corrupted and the block has been quarantined until
+	// it is repaired with a fresh copy via RepairBlock.
+	//
+	// Note that this notification may be sent with the chain lock held, so
+	// consumers must take care to avoid calling blockchain functions to
+	// avoid potential deadlock.
+	NTBlockCorrupted
 )
 
 // notificationTypeStrings is a map of notification types back to their constant
@@ -86,6 +95,7 @@ var notificationTypeStrings = map[NotificationType]string{
 	NTReorganization:        "NTReorganization",
 	NTSpentAndMissedTickets: "NTSpentAndMissedTickets",
 	NTNewTickets:            "NTNewTickets",
+	NTBlockCorrupted:        "NTBlockCorrupted",
 }
 
 // String returns the NotificationType in human-readable form.
@@ -119,12 +129,15 @@ type BlockAcceptedNtfnsData struct {
 }
 
 // ReorganizationNtfnsData is the structure for data indicating information
-// about a reorganization.
+// about a reorganization.  The fork hash and height identify the most recent
+// block the old and new best chains have in common.
 type ReorganizationNtfnsData struct {
-	OldHash   chainhash.Hash
-	OldHeight int64
-	NewHash   chainhash.Hash
-	NewHeight int64
+	OldHash    chainhash.Hash
+	OldHeight  int64
+	NewHash    chainhash.Hash
+	NewHeight  int64
+	ForkHash   chainhash.Hash
+	ForkHeight int64
 }
 
 // TicketNotificationsData is the structure for new/spent/missed ticket
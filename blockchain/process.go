@@ -45,6 +45,9 @@ const (
 // the best chain or is now the tip of the best chain due to causing a
 // reorganize, the fork length will be 0.
 //
+// The local time processing started is recorded as the time the block was
+// received.  See BlockReceiveTime.
+//
 // This function is safe for concurrent access.
 func (b *BlockChain) ProcessBlock(block *dcrutil.Block, flags BehaviorFlags) (int64, error) {
 	b.chainLock.Lock()
@@ -83,7 +86,7 @@ func (b *BlockChain) ProcessBlock(block *dcrutil.Block, flags BehaviorFlags) (in
 
 	// The block has passed all context independent checks and appears sane
 	// enough to potentially accept it into the block chain.
-	forkLen, err := b.maybeAcceptBlock(block, flags)
+	forkLen, err := b.maybeAcceptBlock(block, currentTime, flags)
 	if err != nil {
 		return 0, err
 	}
@@ -7,6 +7,7 @@ package blockchain
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/decred/dcrd/blockchain/v3/chaingen"
 	"github.com/decred/dcrd/chaincfg/chainhash"
@@ -148,3 +149,47 @@ func TestProcessOrder(t *testing.T) {
 	g.RejectBlock("bdc3", ErrNoTax)
 	g.ExpectTip("bdc2")
 }
+
+// TestBlockReceiveTime ensures the local time blocks are received is recorded
+// when they are processed and that the time is not known for the genesis block.
+func TestBlockReceiveTime(t *testing.T) {
+	// Create a test harness initialized with the genesis block as the tip.
+	params := chaincfg.RegNetParams()
+	g, teardownFunc := newChaingenHarness(t, params, "blockreceivetimetest")
+	defer teardownFunc()
+
+	// Ensure the receive time of the genesis block is not known.
+	receiveTime, err := g.chain.BlockReceiveTime(&params.GenesisHash)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !receiveTime.IsZero() {
+		t.Fatalf("unexpected genesis block receive time -- got %v, want zero",
+			receiveTime)
+	}
+
+	// Ensure the receive time of a processed block is recorded.
+	start := time.Now()
+	g.CreateBlockOne("bfb", 0)
+	g.AcceptTipBlock()
+	end := time.Now()
+	tipHash := g.Tip().BlockHash()
+	receiveTime, err = g.chain.BlockReceiveTime(&tipHash)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if receiveTime.Before(start) || receiveTime.After(end) {
+		t.Fatalf("unexpected block receive time -- got %v, want between %v "+
+			"and %v", receiveTime, start, end)
+	}
+
+	// Ensure the receive time of an unknown block is not known.
+	receiveTime, err = g.chain.BlockReceiveTime(&chainhash.Hash{0x01})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !receiveTime.IsZero() {
+		t.Fatalf("unexpected unknown block receive time -- got %v, want zero",
+			receiveTime)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"fmt"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/database/v2"
+	"github.com/decred/dcrd/dcrutil/v3"
+)
+
+// BlockCorruptedNtfnsData is the structure for data indicating information
+// about a block whose stored data was detected to be corrupted.
+type BlockCorruptedNtfnsData struct {
+	// Hash and Height identify the block that is corrupted.
+	Hash   chainhash.Hash
+	Height int64
+
+	// Err is the error that was returned when loading the block.
+	Err error
+}
+
+// maybeQuarantineBlock quarantines the block associated with the provided node
+// when the passed error, which resulted from loading the block from the
+// database, indicates the stored block data is corrupted.  A NTBlockCorrupted
+// notification is sent the first time a given block is quarantined so the
+// caller can obtain a fresh copy of the block and repair it via RepairBlock.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) maybeQuarantineBlock(node *blockNode, err error) {
+	if !database.IsError(err, database.ErrCorruption) {
+		return
+	}
+
+	b.quarantineLock.Lock()
+	_, exists := b.quarantined[node.hash]
+	if !exists {
+		b.quarantined[node.hash] = node.height
+	}
+	b.quarantineLock.Unlock()
+	if exists {
+		return
+	}
+
+	log.Errorf("Quarantined block %v (height %d) due to corrupted block "+
+		"data: %v", node.hash, node.height, err)
+	b.sendNotification(NTBlockCorrupted, &BlockCorruptedNtfnsData{
+		Hash:   node.hash,
+		Height: node.height,
+		Err:    err,
+	})
+}
+
+// IsBlockQuarantined returns whether or not the block with the given hash is
+// currently quarantined due to its stored data being corrupted.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) IsBlockQuarantined(hash *chainhash.Hash) bool {
+	b.quarantineLock.Lock()
+	_, exists := b.quarantined[*hash]
+	b.quarantineLock.Unlock()
+	return exists
+}
+
+// QuarantinedBlocks returns the hashes of all blocks that are currently
+// quarantined due to their stored data being corrupted.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) QuarantinedBlocks() []chainhash.Hash {
+	b.quarantineLock.Lock()
+	hashes := make([]chainhash.Hash, 0, len(b.quarantined))
+	for hash := range b.quarantined {
+		hashes = append(hashes, hash)
+	}
+	b.quarantineLock.Unlock()
+	return hashes
+}
+
+// RepairBlock replaces the corrupted data stored for a quarantined block with
+// the provided copy of the block and removes the block from quarantine.  The
+// provided block must pass the sanity checks and commit to the same
+// transactions as the quarantined block via the merkle roots in its header.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) RepairBlock(block *dcrutil.Block) error {
+	blockHash := block.Hash()
+	if !b.IsBlockQuarantined(blockHash) {
+		return fmt.Errorf("block %v is not quarantined", blockHash)
+	}
+	node := b.index.LookupNode(blockHash)
+	if node == nil {
+		return fmt.Errorf("block %s is not known", blockHash)
+	}
+
+	b.chainLock.Lock()
+	defer b.chainLock.Unlock()
+
+	// Ensure the provided block data is valid and matches the header of the
+	// quarantined block.
+	err := checkBlockSanity(block, b.timeSource, BFNone, b.chainParams)
+	if err != nil {
+		return err
+	}
+	if node.parent != nil {
+		err = b.checkMerkleRoots(block.MsgBlock(), node.parent)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Store the fresh copy of the block.  The database only replaces blocks
+	// whose stored data is corrupted, so the data having since become
+	// readable again is not treated as an error.
+	err = b.db.Update(func(dbTx database.Tx) error {
+		return dbTx.StoreBlock(block)
+	})
+	if err != nil && !database.IsError(err, database.ErrBlockExists) {
+		return err
+	}
+
+	b.quarantineLock.Lock()
+	delete(b.quarantined, *blockHash)
+	b.quarantineLock.Unlock()
+
+	log.Infof("Repaired quarantined block %v (height %d)", blockHash,
+		node.height)
+	return nil
+}
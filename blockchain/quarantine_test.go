@@ -0,0 +1,103 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/database/v2"
+	"github.com/decred/dcrd/dcrutil/v3"
+)
+
+// TestQuarantineBlock ensures blocks with corrupted data are quarantined and
+// may only be repaired with a copy of the block that matches its header.
+func TestQuarantineBlock(t *testing.T) {
+	// Create a test harness initialized with the genesis block as the tip.
+	params := chaincfg.RegNetParams()
+	g, teardownFunc := newChaingenHarness(t, params, "quarantinetest")
+	defer teardownFunc()
+
+	// Capture corrupted block notifications.
+	var ntfns []*BlockCorruptedNtfnsData
+	g.chain.notifications = func(n *Notification) {
+		if n.Type == NTBlockCorrupted {
+			ntfns = append(ntfns, n.Data.(*BlockCorruptedNtfnsData))
+		}
+	}
+
+	g.CreateBlockOne("bfb", 0)
+	g.AcceptTipBlock()
+	g.NextBlock("b1", nil, nil)
+	g.AcceptTipBlock()
+	b1 := g.BlockByName("b1")
+	b1Hash := b1.BlockHash()
+	node := g.chain.index.LookupNode(&b1Hash)
+
+	// Ensure errors that do not indicate corruption do not quarantine the
+	// block.
+	g.chain.maybeQuarantineBlock(node, errors.New("not corruption"))
+	if g.chain.IsBlockQuarantined(&b1Hash) {
+		t.Fatal("block quarantined for error that is not corruption")
+	}
+
+	// Ensure corruption quarantines the block and notifies the caller only
+	// once.
+	corruptErr := database.Error{ErrorCode: database.ErrCorruption}
+	g.chain.maybeQuarantineBlock(node, corruptErr)
+	g.chain.maybeQuarantineBlock(node, corruptErr)
+	if !g.chain.IsBlockQuarantined(&b1Hash) {
+		t.Fatal("block with corrupted data is not quarantined")
+	}
+	if got := g.chain.QuarantinedBlocks(); len(got) != 1 || got[0] != b1Hash {
+		t.Fatalf("unexpected quarantined blocks -- got %v, want [%v]", got,
+			b1Hash)
+	}
+	if len(ntfns) != 1 {
+		t.Fatalf("unexpected number of notifications -- got %d, want 1",
+			len(ntfns))
+	}
+	if ntfns[0].Hash != b1Hash || ntfns[0].Height != int64(b1.Header.Height) {
+		t.Fatalf("unexpected notification data -- got %v (height %d), "+
+			"want %v (height %d)", ntfns[0].Hash, ntfns[0].Height, b1Hash,
+			b1.Header.Height)
+	}
+
+	// Ensure blocks that are not quarantined can't be repaired.
+	bfb := dcrutil.NewBlock(g.BlockByName("bfb"))
+	if err := g.chain.RepairBlock(bfb); err == nil {
+		t.Fatal("RepairBlock succeeded for block that is not quarantined")
+	}
+
+	// Ensure a copy of the block with transactions that do not match the
+	// header is rejected.
+	b1Bytes, err := b1.Bytes()
+	if err != nil {
+		t.Fatalf("unable to serialize block: %v", err)
+	}
+	badBlock, err := dcrutil.NewBlockFromBytes(b1Bytes)
+	if err != nil {
+		t.Fatalf("unable to deserialize block: %v", err)
+	}
+	badBlock.MsgBlock().Transactions[0].TxOut[0].Value--
+	err = g.chain.RepairBlock(badBlock)
+	var rerr RuleError
+	if !errors.As(err, &rerr) || rerr.ErrorCode != ErrBadMerkleRoot {
+		t.Fatalf("unexpected error repairing mismatched block -- got %v, "+
+			"want %v", err, ErrBadMerkleRoot)
+	}
+	if !g.chain.IsBlockQuarantined(&b1Hash) {
+		t.Fatal("block removed from quarantine by mismatched block")
+	}
+
+	// Ensure the block is repaired with a matching copy.
+	if err := g.chain.RepairBlock(dcrutil.NewBlock(b1)); err != nil {
+		t.Fatalf("unexpected error repairing block: %v", err)
+	}
+	if g.chain.IsBlockQuarantined(&b1Hash) {
+		t.Fatal("repaired block is still quarantined")
+	}
+}
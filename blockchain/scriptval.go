@@ -66,7 +66,7 @@ out:
 					"transaction %v referenced from "+
 					"transaction %v", originTxHash,
 					txVI.tx.Hash())
-				err := ruleError(ErrMissingTxOut, str)
+				err := ruleInputError(ErrMissingTxOut, txVI.txInIndex, str)
 				v.sendResult(ctx, err)
 				break out
 			}
@@ -80,7 +80,7 @@ out:
 					"transaction %s:%d",
 					txIn.PreviousOutPoint, txVI.tx.Hash(),
 					txVI.txInIndex)
-				err := ruleError(ErrBadTxInput, str)
+				err := ruleInputError(ErrBadTxInput, txVI.txInIndex, str)
 				v.sendResult(ctx, err)
 				break out
 			}
@@ -98,7 +98,7 @@ out:
 					"script bytes %x)", txVI.tx.Hash(),
 					txVI.txInIndex, originTxHash,
 					originTxIndex, err, sigScript, pkScript)
-				err := ruleError(ErrScriptMalformed, str)
+				err := ruleInputError(ErrScriptMalformed, txVI.txInIndex, str)
 				v.sendResult(ctx, err)
 				break out
 			}
@@ -111,7 +111,7 @@ out:
 					"script bytes %x)", txVI.tx.Hash(),
 					txVI.txInIndex, originTxHash,
 					originTxIndex, err, sigScript, pkScript)
-				err := ruleError(ErrScriptValidation, str)
+				err := ruleInputError(ErrScriptValidation, txVI.txInIndex, str)
 				v.sendResult(ctx, err)
 				break out
 			}
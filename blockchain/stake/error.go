@@ -176,6 +176,10 @@ const (
 	// ErrUnknownTicketSpent indicates that an unknown ticket was spent by
 	// the block.
 	ErrUnknownTicketSpent
+
+	// ErrSSRtxFeeTooHigh indicates that the fee requested for an SSRtx
+	// exceeds the revocation fee limits committed to by the ticket.
+	ErrSSRtxFeeTooHigh
 )
 
 // Map of ErrorCode values back to their constant names for pretty printing.
@@ -219,6 +223,7 @@ var errorCodeStrings = map[ErrorCode]string{
 	ErrMissingTicket:        "ErrMissingTicket",
 	ErrDuplicateTicket:      "ErrDuplicateTicket",
 	ErrUnknownTicketSpent:   "ErrUnknownTicketSpent",
+	ErrSSRtxFeeTooHigh:      "ErrSSRtxFeeTooHigh",
 }
 
 // String returns the ErrorCode as a human-readable name.
@@ -56,6 +56,7 @@ func TestErrorCodeStringer(t *testing.T) {
 		{stake.ErrMissingTicket, "ErrMissingTicket"},
 		{stake.ErrDuplicateTicket, "ErrDuplicateTicket"},
 		{stake.ErrUnknownTicketSpent, "ErrUnknownTicketSpent"},
+		{stake.ErrSSRtxFeeTooHigh, "ErrSSRtxFeeTooHigh"},
 		{0xffff, "Unknown ErrorCode (65535)"},
 	}
 
@@ -575,6 +575,82 @@ func CalculateRewards(amounts []int64, amountTicket int64,
 	return outputsAmounts
 }
 
+// CreateRevocationFromTicket returns an unsigned SSRtx that revokes the ticket
+// with the provided hash and minimal outputs while paying the provided fee.
+//
+// The revocation pays the original contributions back to the addresses
+// committed to by the ticket.  The fee is deducted from the payments in order
+// while adhering to the revocation fee limits of each commitment, so an error
+// is returned when the commitments do not allow the full fee to be paid.
+//
+// NOTE: The provided minimal outputs MUST be those of a valid ticket.
+func CreateRevocationFromTicket(ticketHash *chainhash.Hash, ticketMinOuts []*MinimalOutput, fee dcrutil.Amount) (*wire.MsgTx, error) {
+	isP2SH, hash160s, amounts, _, spendRules, spendLimits :=
+		SStxStakeOutputInfo(ticketMinOuts)
+
+	// Calculate the payment amounts for the revocation.  Revocations do not
+	// produce any subsidy.
+	ticketPaidAmt := ticketMinOuts[0].Value
+	payments := CalculateRewards(amounts, ticketPaidAmt, 0)
+
+	// Deduct the fee from the payments in order.  The amount each payment may
+	// be reduced by is limited by the revocation fee limit of the associated
+	// commitment, where no fee limit means the payment must be exact and a fee
+	// limit of 63 or more means the entire payment may be spent as a fee.
+	remainingFee := int64(fee)
+	for i := range payments {
+		if remainingFee == 0 {
+			break
+		}
+		if !spendRules[i][1] {
+			continue
+		}
+		deduction := payments[i]
+		if spendLimits[i][1] < 63 {
+			feeLimit := int64(1 << uint64(spendLimits[i][1]))
+			if feeLimit < deduction {
+				deduction = feeLimit
+			}
+		}
+		if remainingFee < deduction {
+			deduction = remainingFee
+		}
+		payments[i] -= deduction
+		remainingFee -= deduction
+	}
+	if remainingFee != 0 {
+		str := fmt.Sprintf("fee of %v exceeds the revocation fee limits of "+
+			"ticket %v by %v", fee, ticketHash, dcrutil.Amount(remainingFee))
+		return nil, stakeRuleError(ErrSSRtxFeeTooHigh, str)
+	}
+
+	// The only input spends the stake submission output of the ticket.
+	mtx := wire.NewMsgTx()
+	prevOut := wire.NewOutPoint(ticketHash, 0, wire.TxTreeStake)
+	mtx.AddTxIn(wire.NewTxIn(prevOut, ticketPaidAmt, nil))
+
+	// Add an OP_SSRTX tagged output for each commitment that pays to the
+	// committed address.
+	for i, hash160 := range hash160s {
+		var pkScript []byte
+		var err error
+		if isP2SH[i] {
+			pkScript, err = txscript.PayToSSRtxSHDirect(hash160)
+		} else {
+			pkScript, err = txscript.PayToSSRtxPKHDirect(hash160)
+		}
+		if err != nil {
+			return nil, err
+		}
+		mtx.AddTxOut(wire.NewTxOut(payments[i], pkScript))
+	}
+
+	if err := CheckSSRtx(mtx); err != nil {
+		return nil, err
+	}
+	return mtx, nil
+}
+
 // --------------------------------------------------------------------------------
 // Stake Transaction Identification Functions
 // --------------------------------------------------------------------------------
@@ -13,6 +13,8 @@ import (
 
 	"github.com/decred/dcrd/blockchain/stake/v3"
 	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/dcrec"
 	"github.com/decred/dcrd/dcrutil/v3"
 	"github.com/decred/dcrd/txscript/v3"
 	"github.com/decred/dcrd/wire"
@@ -788,6 +790,116 @@ func TestIsSSRtxErrors(t *testing.T) {
 	}
 }
 
+// TestCreateRevocationFromTicket ensures revocations created from tickets pay
+// the committed addresses and deduct fees per the revocation fee limits.
+func TestCreateRevocationFromTicket(t *testing.T) {
+	params := chaincfg.RegNetParams()
+	pkh := bytes.Repeat([]byte{0x01}, 20)
+	sh := bytes.Repeat([]byte{0x02}, 20)
+	pkhAddr, err := dcrutil.NewAddressPubKeyHash(pkh, params,
+		dcrec.STEcdsaSecp256k1)
+	if err != nil {
+		t.Fatalf("unexpected error creating address: %v", err)
+	}
+	shAddr, err := dcrutil.NewAddressScriptHashFromHash(sh, params)
+	if err != nil {
+		t.Fatalf("unexpected error creating address: %v", err)
+	}
+
+	// Create a ticket with a P2PKH commitment that allows revocation fees of
+	// up to 2^24 atoms and a P2SH commitment that does not allow any fees.
+	mustScript := func(script []byte, err error) []byte {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("unexpected error creating script: %v", err)
+		}
+		return script
+	}
+	const feeLimitLog2 = 24
+	ticketOuts := []*stake.MinimalOutput{{
+		Value:    100000000,
+		PkScript: mustScript(txscript.PayToSStx(pkhAddr)),
+	}, {
+		PkScript: mustScript(txscript.GenerateSStxAddrPush(pkhAddr, 60000000,
+			stake.SStxRevFractionFlag|feeLimitLog2<<8)),
+	}, {
+		PkScript: mustScript(txscript.PayToSStxChange(pkhAddr)),
+	}, {
+		PkScript: mustScript(txscript.GenerateSStxAddrPush(shAddr, 40000000,
+			0)),
+	}, {
+		PkScript: mustScript(txscript.PayToSStxChange(shAddr)),
+	}}
+	ticketHash := &chainhash.Hash{0x03}
+
+	tests := []struct {
+		name    string
+		fee     dcrutil.Amount
+		want    []int64
+		wantErr bool
+	}{{
+		name: "no fee",
+		fee:  0,
+		want: []int64{60000000, 40000000},
+	}, {
+		name: "fee within limit",
+		fee:  10000,
+		want: []int64{59990000, 40000000},
+	}, {
+		name: "fee at limit",
+		fee:  1 << feeLimitLog2,
+		want: []int64{60000000 - 1<<feeLimitLog2, 40000000},
+	}, {
+		name:    "fee exceeds limit",
+		fee:     1<<feeLimitLog2 + 1,
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		tx, err := stake.CreateRevocationFromTicket(ticketHash, ticketOuts,
+			test.fee)
+		if test.wantErr {
+			var serr stake.RuleError
+			if !errors.As(err, &serr) ||
+				serr.GetCode() != stake.ErrSSRtxFeeTooHigh {
+
+				t.Errorf("%q: unexpected error -- got %v, want %v",
+					test.name, err, stake.ErrSSRtxFeeTooHigh)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.name, err)
+			continue
+		}
+
+		if !stake.IsSSRtx(tx) {
+			t.Errorf("%q: created transaction is not a revocation", test.name)
+			continue
+		}
+		prevOut := tx.TxIn[0].PreviousOutPoint
+		if prevOut.Hash != *ticketHash || prevOut.Index != 0 ||
+			tx.TxIn[0].ValueIn != ticketOuts[0].Value {
+			t.Errorf("%q: unexpected input %v (value %d)", test.name,
+				prevOut, tx.TxIn[0].ValueIn)
+		}
+		wantScripts := [][]byte{
+			mustScript(txscript.PayToSSRtx(pkhAddr)),
+			mustScript(txscript.PayToSSRtx(shAddr)),
+		}
+		for i, txOut := range tx.TxOut {
+			if txOut.Value != test.want[i] {
+				t.Errorf("%q: unexpected output %d amount -- got %d, "+
+					"want %d", test.name, i, txOut.Value, test.want[i])
+			}
+			if !bytes.Equal(txOut.PkScript, wantScripts[i]) {
+				t.Errorf("%q: unexpected output %d script -- got %x, "+
+					"want %x", test.name, i, txOut.PkScript, wantScripts[i])
+			}
+		}
+	}
+}
+
 // --------------------------------------------------------------------------------
 // Minor function testing
 func TestGetSSGenBlockVotedOn(t *testing.T) {
@@ -0,0 +1,81 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/decred/dcrd/blockchain/standalone/v2"
+	"github.com/decred/dcrd/chaincfg/v3"
+)
+
+// TestStandaloneLottery ensures the standalone ticket lottery selects the same
+// winners and final state as the chain for blocks after stake validation
+// height.
+func TestStandaloneLottery(t *testing.T) {
+	// Create a test harness initialized with the genesis block as the tip.
+	params := chaincfg.RegNetParams()
+	g, teardownFunc := newChaingenHarness(t, params, "standalonelotterytest")
+	defer teardownFunc()
+
+	g.AdvanceToStakeValidationHeight()
+	for i := 0; i < 4; i++ {
+		outs := g.OldestCoinbaseOuts()
+		g.NextBlock(fmt.Sprintf("bsl%d", i), nil, outs[1:])
+		g.SaveTipCoinbaseOuts()
+		g.AcceptTipBlock()
+
+		tipHash := g.chain.BestSnapshot().Hash
+		winners, poolSize, finalState, err := g.chain.LotteryDataForBlock(
+			&tipHash)
+		if err != nil {
+			t.Fatalf("unable to get lottery data: %v", err)
+		}
+		liveTickets, err := g.chain.LiveTickets()
+		if err != nil {
+			t.Fatalf("unable to get live tickets: %v", err)
+		}
+		if len(liveTickets) != poolSize {
+			t.Fatalf("unexpected pool size -- got %d, want %d",
+				len(liveTickets), poolSize)
+		}
+		header, err := g.chain.HeaderByHash(&tipHash)
+		if err != nil {
+			t.Fatalf("unable to get tip header: %v", err)
+		}
+
+		iv := standalone.CalcLotteryIV(&header)
+		result, err := standalone.SelectWinningTickets(iv, liveTickets,
+			params.TicketsPerBlock)
+		if err != nil {
+			t.Fatalf("unexpected lottery error: %v", err)
+		}
+		if len(result.Winners) != len(winners) {
+			t.Fatalf("unexpected number of winners -- got %d, want %d",
+				len(result.Winners), len(winners))
+		}
+		for j := range winners {
+			if result.Winners[j] != winners[j] {
+				t.Fatalf("unexpected winner %d -- got %v, want %v", j,
+					result.Winners[j], winners[j])
+			}
+		}
+		if result.FinalState != finalState {
+			t.Fatalf("unexpected final state -- got %x, want %x",
+				result.FinalState, finalState)
+		}
+
+		// Ensure test vectors generated from chain data verify.
+		vector, err := standalone.NewLotteryTestVector(&header, liveTickets,
+			params.TicketsPerBlock)
+		if err != nil {
+			t.Fatalf("unable to create test vector: %v", err)
+		}
+		if err := standalone.VerifyLotteryTestVector(vector); err != nil {
+			t.Fatalf("unexpected error verifying test vector: %v", err)
+		}
+	}
+}
@@ -24,6 +24,12 @@ The provided functions fall into the following categories:
    - Calculating work values based on the compact target difficulty
    - Checking a block hash satisfies a target difficulty and that target
      difficulty is within a valid range
+   - Calculating the required target difficulty for the next block
+   - Projecting the required target difficulty under a hypothetical hash rate
+ - Header chain verification
+   - Validating block headers against the proof-of-work, difficulty, and
+     timestamp consensus rules without a database
+   - Tracking the best header chain based on the most cumulative work
  - Merkle root calculation
    - Calculation from individual leaf hashes
    - Calculation from a slice of transactions
@@ -35,6 +41,10 @@ The provided functions fall into the following categories:
  - Merkle tree inclusion proofs
    - Generate an inclusion proof for a given tree and leaf index
    - Verify a leaf is a member of the tree at a given index via the proof
+ - Ticket lottery
+   - Calculating the lottery initialization vector for a block header
+   - Selecting the winning tickets and final state for a given ticket pool
+   - Generating and verifying test vectors for independent implementations
 
 Errors
 
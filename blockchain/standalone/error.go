@@ -22,6 +22,38 @@ const (
 	// lower than the required target difficultly.
 	ErrHighHash
 
+	// ErrTicketPoolTooSmall indicates the ticket pool has fewer tickets than
+	// the number of winners to select from it.
+	ErrTicketPoolTooSmall
+
+	// ErrTicketPoolTooLarge indicates the size of the ticket pool exceeds
+	// the maximum supported by the ticket lottery.
+	ErrTicketPoolTooLarge
+
+	// ErrLotteryMismatch indicates an output of the ticket lottery
+	// described by a test vector does not match the calculated value.
+	ErrLotteryMismatch
+
+	// ErrMissingParent indicates a block header references a previous block
+	// that is not known.
+	ErrMissingParent
+
+	// ErrBadBlockHeight indicates that a block header has a committed height
+	// that does not match the height it connects at in the header chain.
+	ErrBadBlockHeight
+
+	// ErrInvalidTime indicates the time in the passed block header has a
+	// precision that is more than one second.
+	ErrInvalidTime
+
+	// ErrTimeTooNew indicates the time is too far in the future as compared
+	// to the current time.
+	ErrTimeTooNew
+
+	// ErrTimeTooOld indicates the time is not after the median time of the
+	// last several blocks per the chain consensus rules.
+	ErrTimeTooOld
+
 	// numErrorCodes is the maximum error code number used in tests.
 	numErrorCodes
 )
@@ -30,6 +62,14 @@ const (
 var errorCodeStrings = map[ErrorCode]string{
 	ErrUnexpectedDifficulty: "ErrUnexpectedDifficulty",
 	ErrHighHash:             "ErrHighHash",
+	ErrTicketPoolTooSmall:   "ErrTicketPoolTooSmall",
+	ErrTicketPoolTooLarge:   "ErrTicketPoolTooLarge",
+	ErrLotteryMismatch:      "ErrLotteryMismatch",
+	ErrMissingParent:        "ErrMissingParent",
+	ErrBadBlockHeight:       "ErrBadBlockHeight",
+	ErrInvalidTime:          "ErrInvalidTime",
+	ErrTimeTooNew:           "ErrTimeTooNew",
+	ErrTimeTooOld:           "ErrTimeTooOld",
 }
 
 // String returns the ErrorCode as a human-readable name.
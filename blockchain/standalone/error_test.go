@@ -16,6 +16,14 @@ func TestErrorCodeStringer(t *testing.T) {
 	}{
 		{ErrUnexpectedDifficulty, "ErrUnexpectedDifficulty"},
 		{ErrHighHash, "ErrHighHash"},
+		{ErrTicketPoolTooSmall, "ErrTicketPoolTooSmall"},
+		{ErrTicketPoolTooLarge, "ErrTicketPoolTooLarge"},
+		{ErrLotteryMismatch, "ErrLotteryMismatch"},
+		{ErrMissingParent, "ErrMissingParent"},
+		{ErrBadBlockHeight, "ErrBadBlockHeight"},
+		{ErrInvalidTime, "ErrInvalidTime"},
+		{ErrTimeTooNew, "ErrTimeTooNew"},
+		{ErrTimeTooOld, "ErrTimeTooOld"},
 		{0xffff, "Unknown ErrorCode (65535)"},
 	}
 
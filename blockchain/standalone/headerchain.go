@@ -0,0 +1,307 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package standalone
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/wire"
+)
+
+const (
+	// MaxTimeOffset is the maximum amount of time a block header timestamp
+	// is allowed to be ahead of the current time.
+	MaxTimeOffset = 2 * time.Hour
+
+	// medianTimeBlocks is the number of previous block headers which should
+	// be used to calculate the median time used to validate block header
+	// timestamps.
+	medianTimeBlocks = 11
+)
+
+// headerNode represents a block header within the header chain.  It
+// implements the WorkDiffNode interface so the required difficulty can be
+// calculated directly from it.
+type headerNode struct {
+	parent    *headerNode
+	hash      chainhash.Hash
+	header    wire.BlockHeader
+	height    int64
+	workSum   *big.Int
+	timestamp int64
+}
+
+// Ensure headerNode implements the WorkDiffNode interface.
+var _ WorkDiffNode = (*headerNode)(nil)
+
+// Height returns the height of the block header.
+//
+// This is part of the WorkDiffNode interface.
+func (n *headerNode) Height() int64 {
+	return n.height
+}
+
+// Bits returns the target difficulty bits of the block header.
+//
+// This is part of the WorkDiffNode interface.
+func (n *headerNode) Bits() uint32 {
+	return n.header.Bits
+}
+
+// Timestamp returns the timestamp of the block header.
+//
+// This is part of the WorkDiffNode interface.
+func (n *headerNode) Timestamp() int64 {
+	return n.timestamp
+}
+
+// Parent returns the parent of the block header or nil for the genesis block.
+//
+// This is part of the WorkDiffNode interface.
+func (n *headerNode) Parent() WorkDiffNode {
+	if n.parent == nil {
+		return nil
+	}
+	return n.parent
+}
+
+// calcPastMedianTime calculates the median time of the previous few block
+// headers prior to, and including, the node.
+func (n *headerNode) calcPastMedianTime() time.Time {
+	timestamps := make([]int64, 0, medianTimeBlocks)
+	for iterNode := n; iterNode != nil && len(timestamps) < medianTimeBlocks; {
+		timestamps = append(timestamps, iterNode.timestamp)
+		iterNode = iterNode.parent
+	}
+	sort.Slice(timestamps, func(i, j int) bool {
+		return timestamps[i] < timestamps[j]
+	})
+
+	// NOTE: The consensus rules incorrectly calculate the median for even
+	// numbers of blocks.  This only affects the first few blocks of the chain
+	// since the number of blocks used is odd, and the same calculation is used
+	// here to ensure the same rules are applied.
+	return time.Unix(timestamps[len(timestamps)/2], 0)
+}
+
+// HeaderChain validates block headers against the proof-of-work, difficulty
+// retarget, and timestamp consensus rules and tracks the best header chain,
+// which is the valid chain with the most cumulative proof of work, without
+// requiring a database or any block data other than the headers themselves.
+//
+// It is intended for use by lightweight clients, such as SPV wallets, that
+// need to sync and verify the header chain using the exact same rules as full
+// nodes.
+//
+// Note that the header chain only enforces the rules that can be verified from
+// the headers alone.  Notably, this means the stake difficulty, stake version,
+// and ticket lottery commitments are not verified.
+//
+// All functions are safe for concurrent access.
+type HeaderChain struct {
+	params WorkDiffParams
+	now    func() time.Time
+
+	genesis *headerNode
+
+	mtx   sync.RWMutex
+	nodes map[chainhash.Hash]*headerNode
+	best  *headerNode
+}
+
+// NewHeaderChain returns a header chain for the network described by the
+// provided parameters that starts with the provided genesis block header.
+// The genesis block header is valid by definition.
+func NewHeaderChain(params WorkDiffParams, genesis *wire.BlockHeader) *HeaderChain {
+	node := &headerNode{
+		hash:      genesis.BlockHash(),
+		header:    *genesis,
+		height:    int64(genesis.Height),
+		workSum:   CalcWork(genesis.Bits),
+		timestamp: genesis.Timestamp.Unix(),
+	}
+	return &HeaderChain{
+		params:  params,
+		now:     time.Now,
+		genesis: node,
+		nodes:   map[chainhash.Hash]*headerNode{node.hash: node},
+		best:    node,
+	}
+}
+
+// checkHeaderSanity performs the checks on the provided block header that do
+// not depend on its position within the header chain.
+func (c *HeaderChain) checkHeaderSanity(header *wire.BlockHeader, hash *chainhash.Hash) error {
+	// Ensure the proof of work bits in the block header is in min/max range
+	// and the block hash is less than the target value described by the bits.
+	err := CheckProofOfWork(hash, header.Bits, c.params.PowLimitValue())
+	if err != nil {
+		return err
+	}
+
+	// A block timestamp must not have a greater precision than one second.
+	if !header.Timestamp.Equal(time.Unix(header.Timestamp.Unix(), 0)) {
+		str := fmt.Sprintf("block timestamp of %v has a higher precision "+
+			"than one second", header.Timestamp)
+		return ruleError(ErrInvalidTime, str)
+	}
+
+	// Ensure the block time is not too far in the future.
+	maxTimestamp := c.now().Add(MaxTimeOffset)
+	if header.Timestamp.After(maxTimestamp) {
+		str := fmt.Sprintf("block timestamp of %v is too far in the future",
+			header.Timestamp)
+		return ruleError(ErrTimeTooNew, str)
+	}
+
+	return nil
+}
+
+// checkHeaderPositional performs the checks on the provided block header that
+// depend on its position within the header chain as determined by its parent.
+func (c *HeaderChain) checkHeaderPositional(header *wire.BlockHeader, parent *headerNode) error {
+	// Ensure the header commits to the correct height based on the height it
+	// actually connects in the header chain.
+	height := parent.height + 1
+	if int64(header.Height) != height {
+		str := fmt.Sprintf("block header commitment to height %d does not "+
+			"match chain height %d", header.Height, height)
+		return ruleError(ErrBadBlockHeight, str)
+	}
+
+	// Ensure the difficulty specified in the block header matches the
+	// calculated difficulty based on the parent and difficulty retarget rules.
+	expDiff := CalcNextRequiredDifficulty(c.params, parent, header.Timestamp)
+	if header.Bits != expDiff {
+		str := fmt.Sprintf("block difficulty of %d is not the expected value "+
+			"of %d", header.Bits, expDiff)
+		return ruleError(ErrUnexpectedDifficulty, str)
+	}
+
+	// Ensure the timestamp for the block header is after the median time of
+	// the last several blocks.
+	medianTime := parent.calcPastMedianTime()
+	if !header.Timestamp.After(medianTime) {
+		str := fmt.Sprintf("block timestamp of %v is not after expected %v",
+			header.Timestamp, medianTime)
+		return ruleError(ErrTimeTooOld, str)
+	}
+
+	return nil
+}
+
+// addHeader validates and adds the provided block header to the header chain
+// and updates the best chain as needed.  Headers that are already known are
+// ignored.
+//
+// This function MUST be called with the chain lock held (for writes).
+func (c *HeaderChain) addHeader(header *wire.BlockHeader) error {
+	hash := header.BlockHash()
+	if _, ok := c.nodes[hash]; ok {
+		return nil
+	}
+
+	if err := c.checkHeaderSanity(header, &hash); err != nil {
+		return err
+	}
+
+	parent, ok := c.nodes[header.PrevBlock]
+	if !ok {
+		str := fmt.Sprintf("previous block %s of block %s is unknown",
+			header.PrevBlock, hash)
+		return ruleError(ErrMissingParent, str)
+	}
+	if err := c.checkHeaderPositional(header, parent); err != nil {
+		return err
+	}
+
+	node := &headerNode{
+		parent:    parent,
+		hash:      hash,
+		header:    *header,
+		height:    parent.height + 1,
+		workSum:   new(big.Int).Add(parent.workSum, CalcWork(header.Bits)),
+		timestamp: header.Timestamp.Unix(),
+	}
+	c.nodes[hash] = node
+
+	// The best chain only changes when the new header results in a chain
+	// with more cumulative work.  Ties favor the chain that was seen first.
+	if node.workSum.Cmp(c.best.workSum) > 0 {
+		c.best = node
+	}
+	return nil
+}
+
+// AddHeaders validates and adds the provided block headers, which must be
+// ordered such that each header is added after its parent, to the header
+// chain and updates the best chain accordingly.  Headers that are already
+// known are ignored.
+//
+// Processing stops at the first header that fails validation, in which case
+// the returned error is a RuleError describing the violated rule and the
+// headers before it remain added.
+//
+// This function is safe for concurrent access.
+func (c *HeaderChain) AddHeaders(headers []*wire.BlockHeader) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for _, header := range headers {
+		if err := c.addHeader(header); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddHeader validates and adds the provided block header to the header chain
+// and updates the best chain accordingly.  See AddHeaders for details.
+//
+// This function is safe for concurrent access.
+func (c *HeaderChain) AddHeader(header *wire.BlockHeader) error {
+	return c.AddHeaders([]*wire.BlockHeader{header})
+}
+
+// HaveHeader returns whether or not the header chain contains the block header
+// with the provided hash in any of its branches.
+//
+// This function is safe for concurrent access.
+func (c *HeaderChain) HaveHeader(hash *chainhash.Hash) bool {
+	c.mtx.RLock()
+	_, ok := c.nodes[*hash]
+	c.mtx.RUnlock()
+	return ok
+}
+
+// BestTip returns the hash and height of the tip of the best header chain.
+//
+// This function is safe for concurrent access.
+func (c *HeaderChain) BestTip() (chainhash.Hash, int64) {
+	c.mtx.RLock()
+	hash, height := c.best.hash, c.best.height
+	c.mtx.RUnlock()
+	return hash, height
+}
+
+// BestChain returns the block headers of the best header chain ordered from
+// the genesis block header to the tip.
+//
+// This function is safe for concurrent access.
+func (c *HeaderChain) BestChain() []wire.BlockHeader {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	headers := make([]wire.BlockHeader, c.best.height-c.genesis.height+1)
+	for node := c.best; node != nil; node = node.parent {
+		headers[node.height-c.genesis.height] = node.header
+	}
+	return headers
+}
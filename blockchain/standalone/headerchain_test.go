@@ -0,0 +1,289 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package standalone
+
+import (
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/wire"
+)
+
+// newTestHeaderChain returns a header chain that uses the easy mock work
+// difficulty params along with its genesis block header.  The current time of
+// the header chain is set well after the genesis block so all of the headers
+// created by the tests are not considered too far in the future.
+func newTestHeaderChain() (*HeaderChain, *wire.BlockHeader) {
+	params := mockEasyWorkDiffParams()
+	genesis := &wire.BlockHeader{
+		Version:   1,
+		Bits:      params.powLimitBits,
+		Timestamp: time.Unix(1600000000, 0),
+	}
+	chain := NewHeaderChain(params, genesis)
+	chain.now = func() time.Time {
+		return genesis.Timestamp.Add(24 * time.Hour)
+	}
+	return chain, genesis
+}
+
+// solveHeader modifies the nonce of the provided block header until its hash
+// satisfies the target difficulty described by its bits.
+func solveHeader(t *testing.T, chain *HeaderChain, header *wire.BlockHeader) {
+	t.Helper()
+
+	powLimit := chain.params.PowLimitValue()
+	for i := uint32(0); i < 1<<20; i++ {
+		header.Nonce = i
+		hash := header.BlockHash()
+		if CheckProofOfWork(&hash, header.Bits, powLimit) == nil {
+			return
+		}
+	}
+	t.Fatalf("failed to solve block header at height %d", header.Height)
+}
+
+// nextHeader returns an unsolved block header that builds on the provided
+// parent with the given timestamp offset from the parent and the difficulty
+// bits required by the header chain.  The voters field is set to the provided
+// value so tests can create distinct headers on different branches.
+func nextHeader(chain *HeaderChain, parent *wire.BlockHeader, offset time.Duration, voters uint16) *wire.BlockHeader {
+	chain.mtx.RLock()
+	parentNode := chain.nodes[parent.BlockHash()]
+	chain.mtx.RUnlock()
+
+	timestamp := parent.Timestamp.Add(offset)
+	return &wire.BlockHeader{
+		Version:   1,
+		PrevBlock: parent.BlockHash(),
+		Voters:    voters,
+		Bits:      CalcNextRequiredDifficulty(chain.params, parentNode, timestamp),
+		Height:    parent.Height + 1,
+		Timestamp: timestamp,
+	}
+}
+
+// addNextHeaders creates, solves, and adds the provided number of headers that
+// build on the provided parent and are spaced apart by the given offset to the
+// header chain.  It returns the added headers.
+func addNextHeaders(t *testing.T, chain *HeaderChain, parent *wire.BlockHeader, numHeaders int, offset time.Duration, voters uint16) []*wire.BlockHeader {
+	t.Helper()
+
+	headers := make([]*wire.BlockHeader, 0, numHeaders)
+	for i := 0; i < numHeaders; i++ {
+		header := nextHeader(chain, parent, offset, voters)
+		solveHeader(t, chain, header)
+		if err := chain.AddHeader(header); err != nil {
+			t.Fatalf("failed to add header at height %d: %v", header.Height,
+				err)
+		}
+		headers = append(headers, header)
+		parent = header
+	}
+	return headers
+}
+
+// assertBestTip ensures the best tip of the provided header chain is the
+// provided header.
+func assertBestTip(t *testing.T, chain *HeaderChain, want *wire.BlockHeader) {
+	t.Helper()
+
+	wantHash := want.BlockHash()
+	hash, height := chain.BestTip()
+	if hash != wantHash || height != int64(want.Height) {
+		t.Fatalf("unexpected best tip -- got %v (height %d), want %v "+
+			"(height %d)", hash, height, wantHash, want.Height)
+	}
+}
+
+// TestHeaderChain ensures the header chain accepts valid headers, including
+// those that require a difficulty retarget, and selects the best chain based
+// on the most cumulative work.
+func TestHeaderChain(t *testing.T) {
+	chain, genesis := newTestHeaderChain()
+	assertBestTip(t, chain, genesis)
+
+	// Create a main chain with blocks found faster than the target rate so
+	// the difficulty increases at each retarget.
+	mainHeaders := addNextHeaders(t, chain, genesis, 20, 8*time.Second, 0)
+	mainTip := mainHeaders[len(mainHeaders)-1]
+	assertBestTip(t, chain, mainTip)
+	if mainTip.Bits == genesis.Bits {
+		t.Fatalf("difficulty did not change -- bits %08x", mainTip.Bits)
+	}
+
+	// Ensure adding a header that is already known is ignored.
+	if err := chain.AddHeaders(mainHeaders[10:]); err != nil {
+		t.Fatalf("unexpected error adding duplicate headers: %v", err)
+	}
+	assertBestTip(t, chain, mainTip)
+
+	// Ensure the best chain consists of the genesis header and the main chain
+	// headers.
+	bestChain := chain.BestChain()
+	if len(bestChain) != len(mainHeaders)+1 {
+		t.Fatalf("unexpected best chain length -- got %d, want %d",
+			len(bestChain), len(mainHeaders)+1)
+	}
+	if bestChain[0] != *genesis {
+		t.Fatalf("unexpected first best chain header -- got %v, want %v",
+			bestChain[0].BlockHash(), genesis.BlockHash())
+	}
+	for i, header := range mainHeaders {
+		if bestChain[i+1] != *header {
+			t.Fatalf("unexpected best chain header at height %d -- got %v, "+
+				"want %v", i+1, bestChain[i+1].BlockHash(), header.BlockHash())
+		}
+	}
+
+	// Create a side chain that forks from the main chain at height 15 and
+	// extend it to the same height as the main chain.  The headers have the
+	// same difficulty as the main chain, so the main chain must remain the
+	// best chain since it was seen first.
+	forkParent := mainHeaders[14]
+	sideHeaders := addNextHeaders(t, chain, forkParent, 5, 8*time.Second, 1)
+	sideTip := sideHeaders[len(sideHeaders)-1]
+	if sideTip.Bits != mainTip.Bits {
+		t.Fatalf("unexpected side chain difficulty -- got %08x, want %08x",
+			sideTip.Bits, mainTip.Bits)
+	}
+	assertBestTip(t, chain, mainTip)
+
+	// Extend the side chain so it has more cumulative work and ensure it
+	// becomes the best chain.
+	sideHeaders = append(sideHeaders, addNextHeaders(t, chain, sideTip, 1,
+		8*time.Second, 1)...)
+	sideTip = sideHeaders[len(sideHeaders)-1]
+	assertBestTip(t, chain, sideTip)
+	bestChain = chain.BestChain()
+	if len(bestChain) != int(sideTip.Height)+1 {
+		t.Fatalf("unexpected best chain length -- got %d, want %d",
+			len(bestChain), sideTip.Height+1)
+	}
+	if bestChain[forkParent.Height] != *forkParent {
+		t.Fatalf("unexpected best chain fork point -- got %v, want %v",
+			bestChain[forkParent.Height].BlockHash(), forkParent.BlockHash())
+	}
+	if bestChain[len(bestChain)-1] != *sideTip {
+		t.Fatalf("unexpected best chain tip -- got %v, want %v",
+			bestChain[len(bestChain)-1].BlockHash(), sideTip.BlockHash())
+	}
+
+	// Ensure headers from both branches are known.
+	for _, header := range []*wire.BlockHeader{mainTip, sideTip} {
+		hash := header.BlockHash()
+		if !chain.HaveHeader(&hash) {
+			t.Fatalf("header %v at height %d is not known", hash,
+				header.Height)
+		}
+	}
+}
+
+// TestHeaderChainErrors ensures the header chain rejects headers that violate
+// the consensus rules with the expected error codes and that rejected headers
+// are not added.
+func TestHeaderChainErrors(t *testing.T) {
+	chain, genesis := newTestHeaderChain()
+	headers := addNextHeaders(t, chain, genesis, 12, 10*time.Second, 0)
+	tip := headers[len(headers)-1]
+	tipMedianTime := chain.nodes[tip.BlockHash()].calcPastMedianTime()
+
+	tests := []struct {
+		name  string                  // test description
+		mod   func(*wire.BlockHeader) // modifies the valid next header
+		solve bool                    // solve the header after modification
+		err   ErrorCode               // expected error code
+	}{{
+		name: "missing parent",
+		mod: func(header *wire.BlockHeader) {
+			header.PrevBlock = chainhash.Hash{0x01}
+		},
+		solve: true,
+		err:   ErrMissingParent,
+	}, {
+		name: "bad height commitment",
+		mod: func(header *wire.BlockHeader) {
+			header.Height++
+		},
+		solve: true,
+		err:   ErrBadBlockHeight,
+	}, {
+		name: "harder than expected difficulty",
+		mod: func(header *wire.BlockHeader) {
+			header.Bits = 0x1f7fffff
+		},
+		solve: true,
+		err:   ErrUnexpectedDifficulty,
+	}, {
+		name: "hash does not satisfy difficulty",
+		mod: func(header *wire.BlockHeader) {
+			header.Bits = 0x1d00ffff
+		},
+		solve: false,
+		err:   ErrHighHash,
+	}, {
+		name: "timestamp with sub-second precision",
+		mod: func(header *wire.BlockHeader) {
+			header.Timestamp = header.Timestamp.Add(time.Millisecond)
+		},
+		solve: true,
+		err:   ErrInvalidTime,
+	}, {
+		name: "timestamp too far in the future",
+		mod: func(header *wire.BlockHeader) {
+			header.Timestamp = chain.now().Add(MaxTimeOffset + time.Second)
+		},
+		solve: true,
+		err:   ErrTimeTooNew,
+	}, {
+		name: "timestamp at median time",
+		mod: func(header *wire.BlockHeader) {
+			header.Timestamp = tipMedianTime
+		},
+		solve: true,
+		err:   ErrTimeTooOld,
+	}}
+
+	for _, test := range tests {
+		header := nextHeader(chain, tip, 10*time.Second, 0)
+		test.mod(header)
+		if test.solve {
+			solveHeader(t, chain, header)
+		}
+
+		err := chain.AddHeader(header)
+		if !IsErrorCode(err, test.err) {
+			t.Errorf("%q: unexpected error -- got %v, want %v", test.name, err,
+				test.err)
+			continue
+		}
+
+		hash := header.BlockHash()
+		if chain.HaveHeader(&hash) {
+			t.Errorf("%q: rejected header was added", test.name)
+			continue
+		}
+	}
+	assertBestTip(t, chain, tip)
+
+	// Ensure processing of multiple headers stops at the first invalid header
+	// while keeping the headers before it.
+	valid := nextHeader(chain, tip, 10*time.Second, 0)
+	solveHeader(t, chain, valid)
+	invalid := &wire.BlockHeader{
+		Version:   1,
+		PrevBlock: valid.BlockHash(),
+		Bits:      valid.Bits,
+		Height:    valid.Height + 2,
+		Timestamp: valid.Timestamp.Add(10 * time.Second),
+	}
+	solveHeader(t, chain, invalid)
+	err := chain.AddHeaders([]*wire.BlockHeader{valid, invalid})
+	if !IsErrorCode(err, ErrBadBlockHeight) {
+		t.Fatalf("unexpected error -- got %v, want %v", err, ErrBadBlockHeight)
+	}
+	assertBestTip(t, chain, valid)
+}
@@ -0,0 +1,319 @@
+// Copyright (c) 2015-2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package standalone
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/wire"
+)
+
+// lotterySeedConst is a constant derived from the hex representation of pi.  It
+// is appended to the seed when calculating the initialization vector of the
+// deterministic lottery PRNG.
+var lotterySeedConst = [8]byte{0x24, 0x3F, 0x6A, 0x88, 0x85, 0xA3, 0x08, 0xD3}
+
+// lotteryPRNG is the deterministic pseudorandom number generator used by the
+// ticket lottery.  It produces uint32s from the BLAKE-256 hashes of its seed
+// concatenated with an incrementing 32-bit big-endian counter.
+//
+// It mirrors Hash256PRNG in the stake package which is used by consensus.
+type lotteryPRNG struct {
+	seed     chainhash.Hash
+	hashIdx  int
+	idx      uint64
+	lastHash chainhash.Hash
+}
+
+// newLotteryPRNG returns a lottery PRNG for the given initialization vector.
+func newLotteryPRNG(iv chainhash.Hash) *lotteryPRNG {
+	return &lotteryPRNG{seed: iv, lastHash: iv}
+}
+
+// stateHash returns a hash that commits to the current state of the PRNG.
+func (p *lotteryPRNG) stateHash() chainhash.Hash {
+	var state [chainhash.HashSize + 4 + 1]byte
+	copy(state[:], p.lastHash[:])
+	binary.BigEndian.PutUint32(state[chainhash.HashSize:], uint32(p.idx))
+	state[chainhash.HashSize+4] = byte(p.hashIdx)
+	return chainhash.HashH(state[:])
+}
+
+// next returns the next uint32 produced by the PRNG and updates its state.
+func (p *lotteryPRNG) next() uint32 {
+	r := binary.BigEndian.Uint32(p.lastHash[p.hashIdx*4 : p.hashIdx*4+4])
+	p.hashIdx++
+
+	// Roll over to the hash of the next counter value once all uint32s of
+	// the current hash have been used.
+	if p.hashIdx > 7 {
+		var buf [chainhash.HashSize + 4]byte
+		copy(buf[:], p.seed[:])
+		binary.BigEndian.PutUint32(buf[chainhash.HashSize:], uint32(p.idx))
+		p.lastHash = chainhash.HashH(buf[:])
+		p.idx++
+		p.hashIdx = 0
+	}
+
+	// Roll over the seed by rehashing it when the counter overflows.
+	if p.idx > 0xFFFFFFFF {
+		p.seed = chainhash.HashH(p.seed[:])
+		p.lastHash = p.seed
+		p.idx = 0
+	}
+
+	return r
+}
+
+// uniformRandom returns a random number in the range [0, upperBound) while
+// avoiding modulo bias.
+func (p *lotteryPRNG) uniformRandom(upperBound uint32) uint32 {
+	if upperBound < 2 {
+		return 0
+	}
+
+	var min uint32
+	if upperBound > 0x80000000 {
+		min = 1 + ^upperBound
+	} else {
+		// (2**32 - (x * 2)) % x == 2**32 % x when x <= 2**31
+		min = ((0xFFFFFFFF - (upperBound * 2)) + 1) % upperBound
+	}
+
+	for {
+		r := p.next()
+		if r >= min {
+			return r % upperBound
+		}
+	}
+}
+
+// CalcLotteryIV returns the initialization vector of the deterministic PRNG
+// used to select the tickets eligible to vote on the children of the block
+// with the provided header.  It is the BLAKE-256 hash of the serialized header
+// concatenated with a constant derived from the hex representation of pi.
+func CalcLotteryIV(header *wire.BlockHeader) chainhash.Hash {
+	// Serializing to a bytes buffer can only fail when the process is out
+	// of memory, in which case it would panic anyways.
+	buf := bytes.NewBuffer(make([]byte, 0, wire.MaxBlockHeaderPayload+
+		len(lotterySeedConst)))
+	if err := header.Serialize(buf); err != nil {
+		panic(err)
+	}
+	buf.Write(lotterySeedConst[:])
+	return chainhash.HashH(buf.Bytes())
+}
+
+// SortTicketPool sorts the provided live ticket hashes in place into the order
+// the ticket lottery indexes them, which is ascending by the bytes of the
+// hashes in their internal (not byte-reversed) order.
+func SortTicketPool(tickets []chainhash.Hash) {
+	sort.Slice(tickets, func(i, j int) bool {
+		return bytes.Compare(tickets[i][:], tickets[j][:]) < 0
+	})
+}
+
+// FindWinningTicketIdxs returns the indexes into the sorted ticket pool of the
+// given size of the tickets that win the lottery with the provided
+// initialization vector in the order they were selected along with the hash
+// of the final state of the PRNG.
+//
+// The returned error will be a RuleError with ErrTicketPoolTooSmall when the
+// pool has fewer tickets than the number of winners or ErrTicketPoolTooLarge
+// when the size of the pool can't be represented by a uint32.
+func FindWinningTicketIdxs(iv chainhash.Hash, poolSize int, numWinners uint16) ([]int, chainhash.Hash, error) {
+	if poolSize < int(numWinners) {
+		str := fmt.Sprintf("ticket pool size %d is less than the number "+
+			"of winners %d", poolSize, numWinners)
+		return nil, chainhash.Hash{}, ruleError(ErrTicketPoolTooSmall, str)
+	}
+	if int64(poolSize) > 0xFFFFFFFF {
+		str := fmt.Sprintf("ticket pool size %d exceeds the maximum of %d",
+			poolSize, uint32(0xFFFFFFFF))
+		return nil, chainhash.Hash{}, ruleError(ErrTicketPoolTooLarge, str)
+	}
+
+	prng := newLotteryPRNG(iv)
+	idxs := make([]int, 0, numWinners)
+	selected := make(map[int]struct{}, numWinners)
+	for len(idxs) < int(numWinners) {
+		idx := int(prng.uniformRandom(uint32(poolSize)))
+		if _, ok := selected[idx]; ok {
+			continue
+		}
+		selected[idx] = struct{}{}
+		idxs = append(idxs, idx)
+	}
+	return idxs, prng.stateHash(), nil
+}
+
+// LotteryResult houses the outcome of the ticket lottery for a block.
+type LotteryResult struct {
+	// Winners are the hashes of the tickets eligible to vote on the
+	// children of the block in the order they were selected.
+	Winners []chainhash.Hash
+
+	// FinalState is the final state of the lottery which is committed to
+	// by the headers of the children of the block.
+	FinalState [6]byte
+}
+
+// SelectWinningTickets runs the ticket lottery with the provided initialization
+// vector over the provided live ticket pool and returns the winners along with
+// the final lottery state.  The live tickets may be in any order since a
+// sorted copy is used.  The initialization vector for a block is calculated
+// with CalcLotteryIV.
+//
+// See FindWinningTicketIdxs for the errors that may be returned.
+func SelectWinningTickets(iv chainhash.Hash, liveTickets []chainhash.Hash, numWinners uint16) (*LotteryResult, error) {
+	pool := make([]chainhash.Hash, len(liveTickets))
+	copy(pool, liveTickets)
+	SortTicketPool(pool)
+
+	idxs, stateHash, err := FindWinningTicketIdxs(iv, len(pool), numWinners)
+	if err != nil {
+		return nil, err
+	}
+
+	// The final state is the first six bytes of the hash of the winners
+	// followed by the hash of the final PRNG state.
+	result := &LotteryResult{Winners: make([]chainhash.Hash, 0, len(idxs))}
+	stateBuf := make([]byte, 0, (len(idxs)+1)*chainhash.HashSize)
+	for _, idx := range idxs {
+		result.Winners = append(result.Winners, pool[idx])
+		stateBuf = append(stateBuf, pool[idx][:]...)
+	}
+	stateBuf = append(stateBuf, stateHash[:]...)
+	copy(result.FinalState[:], chainhash.HashB(stateBuf))
+	return result, nil
+}
+
+// LotteryTestVector is a self-contained description of the inputs and outputs
+// of the ticket lottery for a block suitable for independently verifying
+// implementations of the lottery.  It is intended to be serialized as JSON.
+//
+// All hashes are encoded as hex strings in the usual byte-reversed order.
+type LotteryTestVector struct {
+	// Header is the hex-encoded serialized header of the block.
+	Header string `json:"header"`
+
+	// LiveTickets are the hashes of the live tickets after the block is
+	// connected in ascending lottery order.
+	LiveTickets []string `json:"livetickets"`
+
+	// NumWinners is the number of winning tickets selected.
+	NumWinners uint16 `json:"numwinners"`
+
+	// IV is the initialization vector of the lottery PRNG.
+	IV string `json:"iv"`
+
+	// WinnerIdxs are the indexes into LiveTickets of the winners.
+	WinnerIdxs []int `json:"winneridxs"`
+
+	// Winners are the hashes of the winning tickets in selection order.
+	Winners []string `json:"winners"`
+
+	// FinalState is the hex-encoded final lottery state.
+	FinalState string `json:"finalstate"`
+}
+
+// NewLotteryTestVector runs the ticket lottery for the block with the provided
+// header and live ticket pool and returns a test vector that describes all of
+// its inputs and outputs.
+func NewLotteryTestVector(header *wire.BlockHeader, liveTickets []chainhash.Hash, numWinners uint16) (*LotteryTestVector, error) {
+	headerBytes, err := header.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	pool := make([]chainhash.Hash, len(liveTickets))
+	copy(pool, liveTickets)
+	SortTicketPool(pool)
+
+	iv := CalcLotteryIV(header)
+	idxs, _, err := FindWinningTicketIdxs(iv, len(pool), numWinners)
+	if err != nil {
+		return nil, err
+	}
+	result, err := SelectWinningTickets(iv, pool, numWinners)
+	if err != nil {
+		return nil, err
+	}
+
+	vector := &LotteryTestVector{
+		Header:      hex.EncodeToString(headerBytes),
+		LiveTickets: make([]string, 0, len(pool)),
+		NumWinners:  numWinners,
+		IV:          iv.String(),
+		WinnerIdxs:  idxs,
+		Winners:     make([]string, 0, len(result.Winners)),
+		FinalState:  hex.EncodeToString(result.FinalState[:]),
+	}
+	for i := range pool {
+		vector.LiveTickets = append(vector.LiveTickets, pool[i].String())
+	}
+	for i := range result.Winners {
+		vector.Winners = append(vector.Winners, result.Winners[i].String())
+	}
+	return vector, nil
+}
+
+// VerifyLotteryTestVector independently runs the ticket lottery described by
+// the provided test vector and ensures the outputs it specifies match.
+//
+// The returned error will be a RuleError with ErrLotteryMismatch when any of
+// the outputs do not match.
+func VerifyLotteryTestVector(vector *LotteryTestVector) error {
+	headerBytes, err := hex.DecodeString(vector.Header)
+	if err != nil {
+		return err
+	}
+	var header wire.BlockHeader
+	if err := header.FromBytes(headerBytes); err != nil {
+		return err
+	}
+	liveTickets := make([]chainhash.Hash, 0, len(vector.LiveTickets))
+	for _, ticketStr := range vector.LiveTickets {
+		ticket, err := chainhash.NewHashFromStr(ticketStr)
+		if err != nil {
+			return err
+		}
+		liveTickets = append(liveTickets, *ticket)
+	}
+
+	want, err := NewLotteryTestVector(&header, liveTickets,
+		vector.NumWinners)
+	if err != nil {
+		return err
+	}
+	mismatch := func(field string, got, want interface{}) error {
+		str := fmt.Sprintf("test vector %s mismatch -- got %v, want %v",
+			field, got, want)
+		return ruleError(ErrLotteryMismatch, str)
+	}
+	if want.IV != vector.IV {
+		return mismatch("iv", vector.IV, want.IV)
+	}
+	if fmt.Sprint(want.LiveTickets) != fmt.Sprint(vector.LiveTickets) {
+		return mismatch("live ticket order", vector.LiveTickets,
+			want.LiveTickets)
+	}
+	if fmt.Sprint(want.WinnerIdxs) != fmt.Sprint(vector.WinnerIdxs) {
+		return mismatch("winner indexes", vector.WinnerIdxs,
+			want.WinnerIdxs)
+	}
+	if fmt.Sprint(want.Winners) != fmt.Sprint(vector.Winners) {
+		return mismatch("winners", vector.Winners, want.Winners)
+	}
+	if want.FinalState != vector.FinalState {
+		return mismatch("final state", vector.FinalState,
+			want.FinalState)
+	}
+	return nil
+}
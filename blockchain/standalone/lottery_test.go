@@ -0,0 +1,153 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package standalone
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+// TestLotteryPRNG ensures the lottery PRNG produces the expected final state
+// after generating a large number of values.
+func TestLotteryPRNG(t *testing.T) {
+	seed := chainhash.HashB([]byte{0x01})
+	iv := chainhash.HashH(append(seed, lotterySeedConst[:]...))
+	prng := newLotteryPRNG(iv)
+	for i := 0; i < 100000; i++ {
+		prng.next()
+	}
+
+	want := "24f1cd72aefbfc85a9d3e21e2eb732615688d3634bf94499af5a81e0eb45c4e4"
+	if got := prng.stateHash().String(); got != want {
+		t.Fatalf("unexpected final state -- got %s, want %s", got, want)
+	}
+}
+
+// TestFindWinningTicketIdxs ensures the expected errors are returned for
+// invalid ticket pool sizes and that the selected indexes are unique and in
+// range.
+func TestFindWinningTicketIdxs(t *testing.T) {
+	iv := chainhash.HashH([]byte{0x01})
+	tests := []struct {
+		name       string
+		poolSize   int
+		numWinners uint16
+		err        error
+	}{{
+		name:       "pool too small",
+		poolSize:   4,
+		numWinners: 5,
+		err:        ruleError(ErrTicketPoolTooSmall, ""),
+	}, {
+		name:       "pool too large",
+		poolSize:   1 << 32,
+		numWinners: 5,
+		err:        ruleError(ErrTicketPoolTooLarge, ""),
+	}, {
+		name:       "pool exactly number of winners",
+		poolSize:   5,
+		numWinners: 5,
+	}, {
+		name:       "typical pool",
+		poolSize:   40960,
+		numWinners: 5,
+	}}
+
+	for _, test := range tests {
+		idxs, _, err := FindWinningTicketIdxs(iv, test.poolSize,
+			test.numWinners)
+		if test.err != nil {
+			wantCode := test.err.(RuleError).ErrorCode
+			if !IsErrorCode(err, wantCode) {
+				t.Errorf("%q: unexpected error -- got %v, want %v",
+					test.name, err, wantCode)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.name, err)
+			continue
+		}
+		if len(idxs) != int(test.numWinners) {
+			t.Errorf("%q: unexpected number of indexes -- got %d, want %d",
+				test.name, len(idxs), test.numWinners)
+			continue
+		}
+		seen := make(map[int]struct{})
+		for _, idx := range idxs {
+			if idx < 0 || idx >= test.poolSize {
+				t.Errorf("%q: index %d out of range", test.name, idx)
+			}
+			if _, ok := seen[idx]; ok {
+				t.Errorf("%q: duplicate index %d", test.name, idx)
+			}
+			seen[idx] = struct{}{}
+		}
+	}
+}
+
+// TestLotteryTestVectors ensures the ticket lottery produces the outputs
+// described by the test vectors in the testdata directory and that vectors
+// with modified outputs are rejected.
+func TestLotteryTestVectors(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("testdata",
+		"lotteryvectors.json"))
+	if err != nil {
+		t.Fatalf("unable to read test vectors: %v", err)
+	}
+	var vectors []*LotteryTestVector
+	if err := json.Unmarshal(data, &vectors); err != nil {
+		t.Fatalf("unable to parse test vectors: %v", err)
+	}
+
+	for i, vector := range vectors {
+		if err := VerifyLotteryTestVector(vector); err != nil {
+			t.Errorf("vector #%d: unexpected error: %v", i, err)
+			continue
+		}
+
+		// Ensure the vector selects the same winners when the live
+		// tickets are provided in a different order.
+		liveTickets := make([]chainhash.Hash, 0, len(vector.LiveTickets))
+		for j := len(vector.LiveTickets) - 1; j >= 0; j-- {
+			ticket, err := chainhash.NewHashFromStr(vector.LiveTickets[j])
+			if err != nil {
+				t.Fatalf("vector #%d: bad ticket hash: %v", i, err)
+			}
+			liveTickets = append(liveTickets, *ticket)
+		}
+		iv, err := chainhash.NewHashFromStr(vector.IV)
+		if err != nil {
+			t.Fatalf("vector #%d: bad iv: %v", i, err)
+		}
+		result, err := SelectWinningTickets(*iv, liveTickets,
+			vector.NumWinners)
+		if err != nil {
+			t.Errorf("vector #%d: unexpected error: %v", i, err)
+			continue
+		}
+		for j, winner := range result.Winners {
+			if winner.String() != vector.Winners[j] {
+				t.Errorf("vector #%d: unexpected winner %d -- got %v, "+
+					"want %v", i, j, winner, vector.Winners[j])
+			}
+		}
+
+		// Ensure a vector with a modified winner is rejected.
+		modified := *vector
+		modified.Winners = append([]string(nil), vector.Winners...)
+		modified.Winners[0], modified.Winners[1] = modified.Winners[1],
+			modified.Winners[0]
+		err = VerifyLotteryTestVector(&modified)
+		if !IsErrorCode(err, ErrLotteryMismatch) {
+			t.Errorf("vector #%d: unexpected error for modified vector "+
+				"-- got %v, want %v", i, err, ErrLotteryMismatch)
+		}
+	}
+}
@@ -0,0 +1,370 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2015-2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package standalone
+
+import (
+	"math/big"
+	"time"
+)
+
+var (
+	// bigZero is 0 represented as a big.Int.  It is defined here to avoid
+	// the overhead of creating it multiple times.
+	bigZero = big.NewInt(0)
+)
+
+// WorkDiffParams defines an interface that is used to provide the parameters
+// required when calculating the required proof-of-work difficulty.  These
+// values are typically well-defined and unique per network.
+type WorkDiffParams interface {
+	// PowLimitValue returns the highest allowed proof of work value for a
+	// block as a uint256.
+	PowLimitValue() *big.Int
+
+	// PowLimitCompact returns the highest allowed proof of work value for a
+	// block in the compact representation.
+	PowLimitCompact() uint32
+
+	// WorkDiffWindowBlocks returns the number of blocks in each of the
+	// windows used when calculating the required difficulty.
+	WorkDiffWindowBlocks() int64
+
+	// NumWorkDiffWindows returns the number of windows that are examined
+	// when calculating the required difficulty.  The difficulty is only
+	// adjusted once every WorkDiffWindowBlocks blocks.
+	NumWorkDiffWindows() int64
+
+	// WorkDiffAlphaShift returns the exponential weighting (smoothing)
+	// value used when calculating the required difficulty.  It determines
+	// how much more heavily the most recent windows are weighted.
+	WorkDiffAlphaShift() int64
+
+	// WorkDiffTargetTimespan returns the desired amount of time that should
+	// elapse for each window of WorkDiffWindowBlocks blocks.
+	WorkDiffTargetTimespan() time.Duration
+
+	// RetargetAdjustmentFactorValue returns the adjustment factor used to
+	// limit the minimum and maximum amount of adjustment that can occur
+	// between difficulty retargets.
+	RetargetAdjustmentFactorValue() int64
+
+	// ReduceMinDifficultyEnabled returns whether the network should reduce
+	// the minimum required difficulty after a long enough period of time
+	// has passed without finding a block.  This is really only useful for
+	// test networks and should not be set on a main network.
+	ReduceMinDifficultyEnabled() bool
+
+	// MinDiffReductionTimeValue returns the amount of time after which the
+	// minimum required difficulty should be reduced when a block hasn't
+	// been found.  It only applies when ReduceMinDifficultyEnabled is true.
+	MinDiffReductionTimeValue() time.Duration
+}
+
+// WorkDiffNode defines an interface that is used to provide the details of a
+// block and access its ancestors when calculating the required proof-of-work
+// difficulty.
+type WorkDiffNode interface {
+	// Height returns the height of the block.
+	Height() int64
+
+	// Bits returns the target difficulty bits of the block in the compact
+	// representation.
+	Bits() uint32
+
+	// Timestamp returns the timestamp of the block as the number of seconds
+	// since the Unix epoch.
+	Timestamp() int64
+
+	// Parent returns the parent of the block.  It MUST return an untyped nil
+	// for the genesis block.
+	Parent() WorkDiffNode
+}
+
+// findPrevTestNetDifficulty returns the difficulty of the previous block which
+// did not have the special testnet minimum difficulty rule applied.
+func findPrevTestNetDifficulty(params WorkDiffParams, startNode WorkDiffNode) uint32 {
+	// Search backwards through the chain for the last block without
+	// the special rule applied.
+	blocksPerRetarget := params.WorkDiffWindowBlocks() *
+		params.NumWorkDiffWindows()
+	powLimitBits := params.PowLimitCompact()
+	iterNode := startNode
+	for iterNode != nil && iterNode.Height()%blocksPerRetarget != 0 &&
+		iterNode.Bits() == powLimitBits {
+
+		iterNode = iterNode.Parent()
+	}
+
+	// Return the found difficulty or the minimum difficulty if no
+	// appropriate block was found.
+	lastBits := powLimitBits
+	if iterNode != nil {
+		lastBits = iterNode.Bits()
+	}
+	return lastBits
+}
+
+// CalcNextRequiredDifficulty calculates the required proof-of-work difficulty
+// in the compact representation for the block after the passed previous block
+// node based on the difficulty retarget rules.  The minimum difficulty is
+// returned when there is no previous block.
+//
+// The difficulty is only adjusted once every WorkDiffWindowBlocks blocks, at
+// which point the time it took to produce each of the NumWorkDiffWindows most
+// recent windows is compared to the target timespan, exponentially weighted
+// to favor the most recent windows, and used to scale the previous difficulty
+// within the limits imposed by the retarget adjustment factor.
+//
+// The timestamp of the new block is only used by networks that reduce the
+// minimum required difficulty when a block hasn't been found for long enough.
+func CalcNextRequiredDifficulty(params WorkDiffParams, prevNode WorkDiffNode, newBlockTime time.Time) uint32 {
+	if prevNode == nil {
+		return params.PowLimitCompact()
+	}
+
+	// Get the old difficulty; if we aren't at a block height where it changes,
+	// just return this.
+	oldDiff := prevNode.Bits()
+	oldDiffBig := CompactToBig(oldDiff)
+
+	// We're not at a retarget point, return the oldDiff.
+	windowSize := params.WorkDiffWindowBlocks()
+	if (prevNode.Height()+1)%windowSize != 0 {
+		// For networks that support it, allow special reduction of the
+		// required difficulty once too much time has elapsed without
+		// mining a block.
+		if params.ReduceMinDifficultyEnabled() {
+			// Return minimum difficulty when more than the desired
+			// amount of time has elapsed without mining a block.
+			reductionTime := int64(params.MinDiffReductionTimeValue() /
+				time.Second)
+			allowMinTime := prevNode.Timestamp() + reductionTime
+			if newBlockTime.Unix() > allowMinTime {
+				return params.PowLimitCompact()
+			}
+
+			// The block was mined within the desired timeframe, so
+			// return the difficulty for the last block which did
+			// not have the special minimum difficulty rule applied.
+			return findPrevTestNetDifficulty(params, prevNode)
+		}
+
+		return oldDiff
+	}
+
+	// Declare some useful variables.
+	powLimit := params.PowLimitValue()
+	numWindows := params.NumWorkDiffWindows()
+	targetTimespan := int64(params.WorkDiffTargetTimespan() / time.Second)
+	RAFBig := big.NewInt(params.RetargetAdjustmentFactorValue())
+	nextDiffBigMin := CompactToBig(oldDiff)
+	nextDiffBigMin.Div(nextDiffBigMin, RAFBig)
+	nextDiffBigMax := CompactToBig(oldDiff)
+	nextDiffBigMax.Mul(nextDiffBigMax, RAFBig)
+
+	alpha := params.WorkDiffAlphaShift()
+
+	// Number of nodes to traverse while calculating difficulty.
+	nodesToTraverse := windowSize * numWindows
+
+	// Initialize bigInt slice for the percentage changes for each window period
+	// above or below the target.
+	windowChanges := make([]*big.Int, numWindows)
+
+	// Regress through all of the previous blocks and store the percent changes
+	// per window period; use bigInts to emulate 64.32 bit fixed point.
+	var olderTime, windowPeriod int64
+	var weights uint64
+	oldNode := prevNode
+	recentTime := prevNode.Timestamp()
+
+	for i := int64(0); ; i++ {
+		// Store and reset after reaching the end of every window period.
+		if i%windowSize == 0 && i != 0 {
+			olderTime = oldNode.Timestamp()
+			timeDifference := recentTime - olderTime
+
+			// Just assume we're at the target (no change) if we've
+			// gone all the way back to the genesis block.
+			if oldNode.Height() == 0 {
+				timeDifference = targetTimespan
+			}
+
+			timeDifBig := big.NewInt(timeDifference)
+			timeDifBig.Lsh(timeDifBig, 32) // Add padding
+			targetTemp := big.NewInt(targetTimespan)
+
+			windowAdjusted := targetTemp.Div(timeDifBig, targetTemp)
+
+			// Weight it exponentially. Be aware that this could at some point
+			// overflow if alpha or the number of blocks used is really large.
+			windowAdjusted = windowAdjusted.Lsh(windowAdjusted,
+				uint((numWindows-windowPeriod)*alpha))
+
+			// Sum up all the different weights incrementally.
+			weights += 1 << uint64((numWindows-windowPeriod)*alpha)
+
+			// Store it in the slice.
+			windowChanges[windowPeriod] = windowAdjusted
+
+			windowPeriod++
+
+			recentTime = olderTime
+		}
+
+		if i == nodesToTraverse {
+			break // Exit for loop when we hit the end.
+		}
+
+		// Get the previous node while staying at the genesis block as
+		// needed.
+		if parent := oldNode.Parent(); parent != nil {
+			oldNode = parent
+		}
+	}
+
+	// Sum up the weighted window periods.
+	weightedSum := big.NewInt(0)
+	for i := int64(0); i < numWindows; i++ {
+		weightedSum.Add(weightedSum, windowChanges[i])
+	}
+
+	// Divide by the sum of all weights.
+	weightsBig := big.NewInt(int64(weights))
+	weightedSumDiv := weightedSum.Div(weightedSum, weightsBig)
+
+	// Multiply by the old diff.
+	nextDiffBig := weightedSumDiv.Mul(weightedSumDiv, oldDiffBig)
+
+	// Right shift to restore the original padding (restore non-fixed point).
+	nextDiffBig = nextDiffBig.Rsh(nextDiffBig, 32)
+
+	// Check to see if we're over the limits for the maximum allowable retarget;
+	// if we are, return the maximum or minimum except in the case that oldDiff
+	// is zero.
+	if oldDiffBig.Cmp(bigZero) == 0 { // This should never really happen,
+		nextDiffBig.Set(nextDiffBig) // but in case it does...
+	} else if nextDiffBig.Cmp(bigZero) == 0 {
+		nextDiffBig.Set(powLimit)
+	} else if nextDiffBig.Cmp(nextDiffBigMax) == 1 {
+		nextDiffBig.Set(nextDiffBigMax)
+	} else if nextDiffBig.Cmp(nextDiffBigMin) == -1 {
+		nextDiffBig.Set(nextDiffBigMin)
+	}
+
+	// Limit new value to the proof of work limit.
+	if nextDiffBig.Cmp(powLimit) > 0 {
+		nextDiffBig.Set(powLimit)
+	}
+
+	return BigToCompact(nextDiffBig)
+}
+
+// SimulatedWorkDiff describes the projected required proof-of-work difficulty
+// at a difficulty retarget point as produced by SimulateWorkDiff.
+type SimulatedWorkDiff struct {
+	// Height is the height of the first block the difficulty applies to.
+	Height int64
+
+	// Bits is the required difficulty in the compact representation.
+	Bits uint32
+
+	// Timestamp is the projected timestamp of the block at Height as the
+	// number of seconds since the Unix epoch.
+	Timestamp int64
+}
+
+// simWorkDiffNode is a hypothetical block used when simulating the required
+// difficulty.  It implements the WorkDiffNode interface.
+type simWorkDiffNode struct {
+	parent    WorkDiffNode
+	height    int64
+	bits      uint32
+	timestamp int64
+}
+
+// Ensure simWorkDiffNode implements the WorkDiffNode interface.
+var _ WorkDiffNode = (*simWorkDiffNode)(nil)
+
+// Height returns the height of the simulated block.
+//
+// This is part of the WorkDiffNode interface.
+func (n *simWorkDiffNode) Height() int64 {
+	return n.height
+}
+
+// Bits returns the target difficulty bits of the simulated block.
+//
+// This is part of the WorkDiffNode interface.
+func (n *simWorkDiffNode) Bits() uint32 {
+	return n.bits
+}
+
+// Timestamp returns the timestamp of the simulated block.
+//
+// This is part of the WorkDiffNode interface.
+func (n *simWorkDiffNode) Timestamp() int64 {
+	return n.timestamp
+}
+
+// Parent returns the parent of the simulated block.
+//
+// This is part of the WorkDiffNode interface.
+func (n *simWorkDiffNode) Parent() WorkDiffNode {
+	return n.parent
+}
+
+// SimulateWorkDiff projects the required proof-of-work difficulty for the
+// provided number of blocks after the passed tip assuming every block is found
+// by a constant hash rate of the provided number of hashes per second.  The tip
+// and its ancestors provide the history used by the difficulty retarget rules.
+//
+// Each simulated block is assumed to take the expected amount of time to find
+// given its required difficulty and the hash rate, rounded down to the nearest
+// second with a minimum of one second.  The projected difficulty is returned
+// for every simulated block that is at a difficulty retarget point.
+//
+// Since blocks are assumed to be found at the expected rate, the minimum
+// difficulty reduction rule used by some test networks is not applied.
+//
+// Nil is returned when the hash rate is not positive or there is no tip.
+func SimulateWorkDiff(params WorkDiffParams, tip WorkDiffNode, hashesPerSec *big.Int, numBlocks int64) []SimulatedWorkDiff {
+	if tip == nil || hashesPerSec.Sign() <= 0 {
+		return nil
+	}
+
+	windowSize := params.WorkDiffWindowBlocks()
+	var results []SimulatedWorkDiff
+	prevNode := tip
+	for i := int64(0); i < numBlocks; i++ {
+		// Calculate the required difficulty for the next block and determine
+		// how long it is expected to take to find based on the hash rate.  The
+		// timestamp of the previous block is used when calculating the
+		// difficulty so the minimum difficulty reduction rule never applies.
+		bits := CalcNextRequiredDifficulty(params, prevNode,
+			time.Unix(prevNode.Timestamp(), 0))
+		blockTime := new(big.Int).Div(CalcWork(bits), hashesPerSec)
+		if blockTime.Sign() <= 0 {
+			blockTime.SetInt64(1)
+		}
+
+		node := &simWorkDiffNode{
+			parent:    prevNode,
+			height:    prevNode.Height() + 1,
+			bits:      bits,
+			timestamp: prevNode.Timestamp() + blockTime.Int64(),
+		}
+		if node.height%windowSize == 0 {
+			results = append(results, SimulatedWorkDiff{
+				Height:    node.height,
+				Bits:      node.bits,
+				Timestamp: node.timestamp,
+			})
+		}
+		prevNode = node
+	}
+	return results
+}
@@ -0,0 +1,365 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package standalone
+
+import (
+	"math/big"
+	"testing"
+	"time"
+)
+
+// mockWorkDiffParams implements the WorkDiffParams interface and is used
+// throughout the tests to mock networks.
+type mockWorkDiffParams struct {
+	powLimit           *big.Int
+	powLimitBits       uint32
+	windowBlocks       int64
+	numWindows         int64
+	alphaShift         int64
+	targetTimespan     time.Duration
+	adjustmentFactor   int64
+	reduceMinDiff      bool
+	minDiffReductionTm time.Duration
+}
+
+// Ensure the mock work difficulty params satisfy the WorkDiffParams interface.
+var _ WorkDiffParams = (*mockWorkDiffParams)(nil)
+
+// PowLimitValue returns the value associated with the mock params for the
+// highest allowed proof of work value for a block.
+//
+// This is part of the WorkDiffParams interface.
+func (p *mockWorkDiffParams) PowLimitValue() *big.Int {
+	return p.powLimit
+}
+
+// PowLimitCompact returns the value associated with the mock params for the
+// highest allowed proof of work value for a block in compact form.
+//
+// This is part of the WorkDiffParams interface.
+func (p *mockWorkDiffParams) PowLimitCompact() uint32 {
+	return p.powLimitBits
+}
+
+// WorkDiffWindowBlocks returns the value associated with the mock params for
+// the number of blocks in each difficulty window.
+//
+// This is part of the WorkDiffParams interface.
+func (p *mockWorkDiffParams) WorkDiffWindowBlocks() int64 {
+	return p.windowBlocks
+}
+
+// NumWorkDiffWindows returns the value associated with the mock params for
+// the number of difficulty windows to examine.
+//
+// This is part of the WorkDiffParams interface.
+func (p *mockWorkDiffParams) NumWorkDiffWindows() int64 {
+	return p.numWindows
+}
+
+// WorkDiffAlphaShift returns the value associated with the mock params for the
+// exponential weighting applied to the difficulty windows.
+//
+// This is part of the WorkDiffParams interface.
+func (p *mockWorkDiffParams) WorkDiffAlphaShift() int64 {
+	return p.alphaShift
+}
+
+// WorkDiffTargetTimespan returns the value associated with the mock params for
+// the desired amount of time for each difficulty window.
+//
+// This is part of the WorkDiffParams interface.
+func (p *mockWorkDiffParams) WorkDiffTargetTimespan() time.Duration {
+	return p.targetTimespan
+}
+
+// RetargetAdjustmentFactorValue returns the value associated with the mock
+// params for the maximum adjustment between difficulty retargets.
+//
+// This is part of the WorkDiffParams interface.
+func (p *mockWorkDiffParams) RetargetAdjustmentFactorValue() int64 {
+	return p.adjustmentFactor
+}
+
+// ReduceMinDifficultyEnabled returns the value associated with the mock params
+// for whether or not the minimum difficulty reduction rule is enabled.
+//
+// This is part of the WorkDiffParams interface.
+func (p *mockWorkDiffParams) ReduceMinDifficultyEnabled() bool {
+	return p.reduceMinDiff
+}
+
+// MinDiffReductionTimeValue returns the value associated with the mock params
+// for the amount of time after which the minimum difficulty applies.
+//
+// This is part of the WorkDiffParams interface.
+func (p *mockWorkDiffParams) MinDiffReductionTimeValue() time.Duration {
+	return p.minDiffReductionTm
+}
+
+// mockEasyWorkDiffParams returns mock work difficulty params with a proof of
+// work limit that allows headers to be solved nearly instantly and small
+// difficulty windows of 4 blocks with a target block time of 10 seconds.
+func mockEasyWorkDiffParams() *mockWorkDiffParams {
+	// 2^255 - 1
+	powLimit := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255),
+		big.NewInt(1))
+	return &mockWorkDiffParams{
+		powLimit:           powLimit,
+		powLimitBits:       0x207fffff,
+		windowBlocks:       4,
+		numWindows:         3,
+		alphaShift:         1,
+		targetTimespan:     time.Second * 10 * 4,
+		adjustmentFactor:   4,
+		reduceMinDiff:      false,
+		minDiffReductionTm: time.Minute * 20,
+	}
+}
+
+// mockWorkDiffNode implements the WorkDiffNode interface and is used to mock
+// chains of blocks in the tests.
+type mockWorkDiffNode struct {
+	parent    *mockWorkDiffNode
+	height    int64
+	bits      uint32
+	timestamp int64
+}
+
+// Ensure the mock work difficulty node satisfies the WorkDiffNode interface.
+var _ WorkDiffNode = (*mockWorkDiffNode)(nil)
+
+// Height returns the height of the mock block.
+//
+// This is part of the WorkDiffNode interface.
+func (n *mockWorkDiffNode) Height() int64 {
+	return n.height
+}
+
+// Bits returns the target difficulty bits of the mock block.
+//
+// This is part of the WorkDiffNode interface.
+func (n *mockWorkDiffNode) Bits() uint32 {
+	return n.bits
+}
+
+// Timestamp returns the timestamp of the mock block.
+//
+// This is part of the WorkDiffNode interface.
+func (n *mockWorkDiffNode) Timestamp() int64 {
+	return n.timestamp
+}
+
+// Parent returns the parent of the mock block or nil for the genesis block.
+//
+// This is part of the WorkDiffNode interface.
+func (n *mockWorkDiffNode) Parent() WorkDiffNode {
+	if n.parent == nil {
+		return nil
+	}
+	return n.parent
+}
+
+// mockWorkDiffChain returns the tip of a mock chain with the provided number of
+// blocks after the genesis block that all have the provided difficulty bits and
+// are spaced apart by the provided number of seconds.
+func mockWorkDiffChain(numBlocks int64, bits uint32, spacing int64) *mockWorkDiffNode {
+	tip := &mockWorkDiffNode{bits: bits, timestamp: 1600000000}
+	for i := int64(0); i < numBlocks; i++ {
+		tip = &mockWorkDiffNode{
+			parent:    tip,
+			height:    tip.height + 1,
+			bits:      bits,
+			timestamp: tip.timestamp + spacing,
+		}
+	}
+	return tip
+}
+
+// TestCalcNextRequiredDifficulty ensures the required difficulty is calculated
+// as expected for various chains.
+func TestCalcNextRequiredDifficulty(t *testing.T) {
+	const startBits = 0x1f00ffff
+	startDiff := CompactToBig(startBits)
+	easierBits := BigToCompact(new(big.Int).Mul(startDiff, big.NewInt(4)))
+	harderBits := BigToCompact(new(big.Int).Div(startDiff, big.NewInt(4)))
+
+	tests := []struct {
+		name     string            // test description
+		reduce   bool              // enable min difficulty reduction
+		prevNode *mockWorkDiffNode // previous block node
+		offset   int64             // new block time offset from prev node
+		want     uint32            // expected difficulty bits
+	}{{
+		name:     "no previous block",
+		prevNode: nil,
+		want:     0x207fffff,
+	}, {
+		name:     "not at retarget point",
+		prevNode: mockWorkDiffChain(14, startBits, 1),
+		offset:   1,
+		want:     startBits,
+	}, {
+		name:     "retarget with blocks at target rate",
+		prevNode: mockWorkDiffChain(15, startBits, 10),
+		offset:   10,
+		want:     startBits,
+	}, {
+		name:     "retarget with fast blocks limited by adjustment factor",
+		prevNode: mockWorkDiffChain(15, startBits, 1),
+		offset:   1,
+		want:     harderBits,
+	}, {
+		name:     "retarget with slow blocks limited by adjustment factor",
+		prevNode: mockWorkDiffChain(15, startBits, 1000),
+		offset:   1000,
+		want:     easierBits,
+	}, {
+		name:     "retarget with slow blocks limited by pow limit",
+		prevNode: mockWorkDiffChain(15, 0x207fffff, 1000),
+		offset:   1000,
+		want:     0x207fffff,
+	}, {
+		name:     "retarget with window reaching genesis",
+		prevNode: mockWorkDiffChain(3, startBits, 1000),
+		offset:   1000,
+		want:     startBits,
+	}, {
+		name:     "min diff reduction after reduction time",
+		reduce:   true,
+		prevNode: mockWorkDiffChain(14, startBits, 10),
+		offset:   20*60 + 1,
+		want:     0x207fffff,
+	}, {
+		name:     "no min diff reduction at exactly reduction time",
+		reduce:   true,
+		prevNode: mockWorkDiffChain(14, startBits, 10),
+		offset:   20 * 60,
+		want:     startBits,
+	}}
+
+	for _, test := range tests {
+		params := mockEasyWorkDiffParams()
+		params.reduceMinDiff = test.reduce
+
+		// Avoid passing a typed nil as the previous node.
+		var prevNode WorkDiffNode
+		var prevTime int64
+		if test.prevNode != nil {
+			prevNode = test.prevNode
+			prevTime = test.prevNode.timestamp
+		}
+
+		newBlockTime := time.Unix(prevTime+test.offset, 0)
+		got := CalcNextRequiredDifficulty(params, prevNode, newBlockTime)
+		if got != test.want {
+			t.Errorf("%q: unexpected result -- got %08x, want %08x", test.name,
+				got, test.want)
+			continue
+		}
+	}
+}
+
+// TestCalcNextRequiredDifficultyMinDiffRecovery ensures that networks which
+// reduce the minimum required difficulty return to the difficulty of the last
+// block that did not have the special rule applied once blocks are found
+// within the desired timeframe again.
+func TestCalcNextRequiredDifficultyMinDiffRecovery(t *testing.T) {
+	const startBits = 0x1f00ffff
+	params := mockEasyWorkDiffParams()
+	params.reduceMinDiff = true
+
+	// Create a chain where the final two blocks had the minimum difficulty
+	// rule applied.
+	tip := mockWorkDiffChain(4, startBits, 10)
+	for i := 0; i < 2; i++ {
+		tip = &mockWorkDiffNode{
+			parent:    tip,
+			height:    tip.height + 1,
+			bits:      params.powLimitBits,
+			timestamp: tip.timestamp + 20*60 + 1,
+		}
+	}
+
+	newBlockTime := time.Unix(tip.timestamp+10, 0)
+	got := CalcNextRequiredDifficulty(params, tip, newBlockTime)
+	if got != startBits {
+		t.Fatalf("unexpected result -- got %08x, want %08x", got, startBits)
+	}
+}
+
+// TestSimulateWorkDiff ensures simulating the required difficulty under
+// various hash rates produces the expected retarget points and adjusts the
+// difficulty in the expected direction.
+func TestSimulateWorkDiff(t *testing.T) {
+	const startBits = 0x1f00ffff
+	params := mockEasyWorkDiffParams()
+	tip := mockWorkDiffChain(15, startBits, 10)
+
+	// Calculate the hash rate that results in blocks being found at the
+	// target rate for the starting difficulty.
+	targetHashRate := new(big.Int).Div(CalcWork(startBits), big.NewInt(10))
+
+	tests := []struct {
+		name       string // test description
+		multiplier int64  // hash rate multiplier
+		divisor    int64  // hash rate divisor
+		harder     bool   // whether the difficulty is expected to increase
+	}{{
+		name:       "hash rate doubled",
+		multiplier: 2,
+		divisor:    1,
+		harder:     true,
+	}, {
+		name:       "hash rate halved",
+		multiplier: 1,
+		divisor:    2,
+		harder:     false,
+	}}
+
+	for _, test := range tests {
+		hashRate := new(big.Int).Mul(targetHashRate,
+			big.NewInt(test.multiplier))
+		hashRate.Div(hashRate, big.NewInt(test.divisor))
+		results := SimulateWorkDiff(params, tip, hashRate, 12)
+
+		// Ensure the expected retarget points are returned.
+		wantHeights := []int64{16, 20, 24}
+		if len(results) != len(wantHeights) {
+			t.Errorf("%q: unexpected number of results -- got %d, want %d",
+				test.name, len(results), len(wantHeights))
+			continue
+		}
+		prevTimestamp := tip.timestamp
+		for i, result := range results {
+			if result.Height != wantHeights[i] {
+				t.Errorf("%q: unexpected height for result %d -- got %d, "+
+					"want %d", test.name, i, result.Height, wantHeights[i])
+			}
+			if result.Timestamp <= prevTimestamp {
+				t.Errorf("%q: timestamp for result %d did not increase",
+					test.name, i)
+			}
+			prevTimestamp = result.Timestamp
+		}
+
+		// Ensure the difficulty moved in the expected direction.
+		startTarget := CompactToBig(startBits)
+		finalTarget := CompactToBig(results[len(results)-1].Bits)
+		if got := finalTarget.Cmp(startTarget) < 0; got != test.harder {
+			t.Errorf("%q: unexpected difficulty direction -- start %08x, "+
+				"final %08x", test.name, uint32(startBits),
+				results[len(results)-1].Bits)
+		}
+	}
+
+	// Ensure no results are returned for invalid hash rates or a missing tip.
+	if results := SimulateWorkDiff(params, tip, big.NewInt(0), 12); results != nil {
+		t.Errorf("unexpected results for zero hash rate: %v", results)
+	}
+	if results := SimulateWorkDiff(params, nil, targetHashRate, 12); results != nil {
+		t.Errorf("unexpected results for missing tip: %v", results)
+	}
+}
@@ -567,6 +567,112 @@ func (b *BlockChain) StateLastChangedHeight(hash *chainhash.Hash, version uint32
 	return height, nil
 }
 
+// DeploymentVoteResult houses the outcome of the vote on a consensus deployment
+// agenda.
+type DeploymentVoteResult struct {
+	// DecisionHeight is the height of the final block of the rule change
+	// interval in which the outcome of the vote was decided.
+	DecisionHeight int64
+
+	// ActivationHeight is the height of the first block for which the
+	// rules defined by the agenda are active.  It is zero when the vote
+	// failed.
+	ActivationHeight int64
+
+	// Counts are the vote counts of the rule change interval in which the
+	// outcome of the vote was decided.
+	Counts VoteCounts
+}
+
+// DeploymentVoteResult returns the outcome of the vote on the provided
+// consensus deployment agenda as of the passed block hash along with the vote
+// counts of the interval it was decided in.  Nil is returned when the vote has
+// not concluded as of the passed block, meaning the agenda is not locked in,
+// active, or failed.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) DeploymentVoteResult(hash *chainhash.Hash, version uint32, deploymentID string) (*DeploymentVoteResult, error) {
+	// NOTE: The requirement for the node being fully validated here is strictly
+	// stronger than what is actually required.  In reality, all that is needed
+	// is for the block data for the node and all of its ancestors to be
+	// available, but there is not currently any tracking to be able to
+	// efficiently determine that state.
+	node := b.index.LookupNode(hash)
+	if node == nil || !b.index.NodeStatus(node).HasValidated() {
+		return nil, HashError(hash.String())
+	}
+
+	// Fetch the deployment, threshold state cache, and condition checker
+	// for the provided deployment id.
+	var deployment *chaincfg.ConsensusDeployment
+	var cache *thresholdStateCache
+	for k := range b.chainParams.Deployments[version] {
+		if b.chainParams.Deployments[version][k].Vote.Id == deploymentID {
+			deployment = &b.chainParams.Deployments[version][k]
+			cache = &b.deploymentCaches[version][k]
+			break
+		}
+	}
+	if deployment == nil {
+		return nil, DeploymentError(deploymentID)
+	}
+	checker := deploymentChecker{deployment: deployment, chain: b}
+
+	b.chainLock.Lock()
+	defer b.chainLock.Unlock()
+
+	// Determine the state of the requested block.  Notice that
+	// nextThresholdState always calculates the state for the block after the
+	// provided one, so use the parent to get the state for the requested
+	// block.
+	if node.parent == nil {
+		return nil, nil
+	}
+	state, err := b.nextThresholdState(version, node.parent, checker, cache)
+	if err != nil {
+		return nil, err
+	}
+	switch state.State {
+	case ThresholdLockedIn, ThresholdActive, ThresholdFailed:
+	default:
+		return nil, nil
+	}
+
+	// Find the first block of the interval in which the outcome of the vote
+	// took effect.  Agendas that are active were locked in for exactly one
+	// interval prior to becoming active.
+	changedNode, err := b.stateLastChanged(version, node, checker, cache)
+	if err != nil || changedNode == nil {
+		return nil, err
+	}
+	rcai := int64(checker.RuleChangeActivationInterval())
+	var activationHeight int64
+	switch state.State {
+	case ThresholdLockedIn:
+		activationHeight = changedNode.height + rcai
+	case ThresholdActive:
+		activationHeight = changedNode.height
+		changedNode = changedNode.RelativeAncestor(rcai)
+	}
+	if changedNode == nil || changedNode.parent == nil {
+		return nil, nil
+	}
+
+	// The outcome was decided by the votes in the interval that ends with
+	// the parent of the block at which the state changed.
+	decisionNode := changedNode.parent
+	counts, err := b.getVoteCounts(decisionNode, version, deployment)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DeploymentVoteResult{
+		DecisionHeight:   decisionNode.height,
+		ActivationHeight: activationHeight,
+		Counts:           counts,
+	}, nil
+}
+
 // NextThresholdState returns the current rule change threshold state of the
 // given deployment ID for the block AFTER the provided block hash.
 //
@@ -804,6 +910,31 @@ func (b *BlockChain) GetVoteCounts(version uint32, deploymentID string) (VoteCou
 	return VoteCounts{}, DeploymentError(deploymentID)
 }
 
+// GetVoteCountsAt returns the vote counts for the specified version and
+// deployment identifier for the rule change activation interval that contains
+// the block with the provided hash up to and including that block.  Providing
+// the hash of the final block of an interval therefore results in the counts
+// for the entire interval.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) GetVoteCountsAt(hash *chainhash.Hash, version uint32, deploymentID string) (VoteCounts, error) {
+	node := b.index.LookupNode(hash)
+	if node == nil || !b.index.NodeStatus(node).HasValidated() {
+		return VoteCounts{}, HashError(hash.String())
+	}
+
+	for k := range b.chainParams.Deployments[version] {
+		deployment := &b.chainParams.Deployments[version][k]
+		if deployment.Vote.Id == deploymentID {
+			b.chainLock.Lock()
+			counts, err := b.getVoteCounts(node, version, deployment)
+			b.chainLock.Unlock()
+			return counts, err
+		}
+	}
+	return VoteCounts{}, DeploymentError(deploymentID)
+}
+
 // CountVoteVersion returns the total number of version votes for the current
 // rule change activation interval.
 //
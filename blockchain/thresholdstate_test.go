@@ -604,4 +604,55 @@ func TestThresholdState(t *testing.T) {
 	g.AssertStakeVersion(4)
 	g.TestThresholdStateChoice(testDummy1ID, ThresholdActive, testDummy1YesIndex)
 	g.TestThresholdStateChoice(testDummy2ID, ThresholdFailed, testDummy2NoIndex)
+
+	// ---------------------------------------------------------------------
+	// Ensure the vote results as of the tip report the interval in which
+	// the outcome of each vote was decided.  The tip is the final block of
+	// the interval the first test dummy agenda was locked in for, so the
+	// agenda becomes active with the next block.
+	// ---------------------------------------------------------------------
+
+	decisionHeight := stakeValidationHeight + ruleChangeInterval*7 - 1
+	intervalVotes := uint32(ticketsPerBlock * ruleChangeInterval)
+	tipHash := g.Tip().BlockHash()
+	tests := []struct {
+		id             string
+		wantActivation int64
+		wantChoice     int
+	}{{
+		id:             testDummy1ID,
+		wantActivation: stakeValidationHeight + ruleChangeInterval*8,
+		wantChoice:     testDummy1YesIndex,
+	}, {
+		id:             testDummy2ID,
+		wantActivation: 0,
+		wantChoice:     testDummy2NoIndex,
+	}}
+	for _, test := range tests {
+		result, err := g.chain.DeploymentVoteResult(&tipHash, posVersion,
+			test.id)
+		if err != nil {
+			t.Fatalf("%s: unexpected vote result error: %v", test.id, err)
+		}
+		if result == nil {
+			t.Fatalf("%s: missing vote result", test.id)
+		}
+		if result.DecisionHeight != decisionHeight {
+			t.Fatalf("%s: unexpected decision height -- got %d, want %d",
+				test.id, result.DecisionHeight, decisionHeight)
+		}
+		if result.ActivationHeight != test.wantActivation {
+			t.Fatalf("%s: unexpected activation height -- got %d, want %d",
+				test.id, result.ActivationHeight, test.wantActivation)
+		}
+		if result.Counts.Total != intervalVotes {
+			t.Fatalf("%s: unexpected total votes -- got %d, want %d",
+				test.id, result.Counts.Total, intervalVotes)
+		}
+		gotChoice := result.Counts.VoteChoices[test.wantChoice]
+		if gotChoice != intervalVotes {
+			t.Fatalf("%s: unexpected choice votes -- got %d, want %d",
+				test.id, gotChoice, intervalVotes)
+		}
+	}
 }
@@ -15,6 +15,7 @@ import (
 
 	"github.com/decred/dcrd/blockchain/stake/v3"
 	"github.com/decred/dcrd/blockchain/standalone/v2"
+	"github.com/decred/dcrd/blockchain/v3/internal/muhash"
 	"github.com/decred/dcrd/blockchain/v3/internal/progresslog"
 	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/chaincfg/v3"
@@ -1315,6 +1316,88 @@ func migrateBlockIndexVersion2To3(ctx context.Context, db database.DB, dbInfo *d
 	return err
 }
 
+// upgradeToVersion7 upgrades a version 6 blockchain database to version 7.
+// This entails calculating the multiset hash of the existing utxo set and
+// storing it so it can be incrementally maintained going forward.
+func upgradeToVersion7(ctx context.Context, db database.DB, dbInfo *databaseInfo) error {
+	// Hardcoded bucket and key names so updates do not affect old upgrades.
+	utxoSetBucketName := []byte("utxoset")
+	utxoSetHashKeyName := []byte("utxosethash")
+
+	log.Info("Upgrading database to version 7...")
+	log.Info("Calculating the utxo set hash.  This may take a while...")
+	start := time.Now()
+
+	// Calculate the multiset hash of all entries in the utxo set.
+	setHash := muhash.New()
+	var numEntries uint64
+	err := db.View(func(dbTx database.Tx) error {
+		utxoBucket := dbTx.Metadata().Bucket(utxoSetBucketName)
+		if utxoBucket == nil {
+			return fmt.Errorf("bucket %s does not exist", utxoSetBucketName)
+		}
+		return utxoBucket.ForEach(func(k, v []byte) error {
+			if numEntries%100000 == 0 && interruptRequested(ctx) {
+				return errInterruptRequested
+			}
+
+			elem := make([]byte, 0, len(k)+len(v))
+			elem = append(elem, k...)
+			elem = append(elem, v...)
+			setHash.Add(elem)
+			numEntries++
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	// Store the hash and update and persist the database versions.
+	err = db.Update(func(dbTx database.Tx) error {
+		err := dbTx.Metadata().Put(utxoSetHashKeyName, setHash.Serialize())
+		if err != nil {
+			return err
+		}
+
+		dbInfo.version = 7
+		return dbPutDatabaseInfo(dbTx, dbInfo)
+	})
+	if err != nil {
+		return err
+	}
+
+	elapsed := time.Since(start).Round(time.Millisecond)
+	log.Infof("Done upgrading database in %v.  Hashed %d utxo entries.",
+		elapsed, numEntries)
+	return nil
+}
+
+// upgradeToVersion8 upgrades a version 7 blockchain database to version 8.
+// This entails creating the bucket that houses the local time each block was
+// received.  The receive times of the existing blocks are unknown, so they do
+// not have entries.
+func upgradeToVersion8(ctx context.Context, db database.DB, dbInfo *databaseInfo) error {
+	// Hardcoded bucket name so updates do not affect old upgrades.
+	blockReceiveTimeBucketName := []byte("blockrecvtimes")
+
+	if interruptRequested(ctx) {
+		return errInterruptRequested
+	}
+
+	log.Info("Upgrading database to version 8...")
+	return db.Update(func(dbTx database.Tx) error {
+		_, err := dbTx.Metadata().CreateBucketIfNotExists(
+			blockReceiveTimeBucketName)
+		if err != nil {
+			return err
+		}
+
+		dbInfo.version = 8
+		return dbPutDatabaseInfo(dbTx, dbInfo)
+	})
+}
+
 // upgradeDB upgrades old database versions to the newest version by applying
 // all possible upgrades iteratively.
 //
@@ -1370,5 +1453,21 @@ func upgradeDB(ctx context.Context, db database.DB, chainParams *chaincfg.Params
 		}
 	}
 
+	// Update to a version 7 database if needed.  This entails calculating and
+	// storing the multiset hash of the utxo set.
+	if dbInfo.version == 6 {
+		if err := upgradeToVersion7(ctx, db, dbInfo); err != nil {
+			return err
+		}
+	}
+
+	// Update to a version 8 database if needed.  This entails creating the
+	// bucket that houses the block receive times.
+	if dbInfo.version == 7 {
+		if err := upgradeToVersion8(ctx, db, dbInfo); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
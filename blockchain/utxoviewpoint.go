@@ -1020,6 +1020,10 @@ type UtxoStats struct {
 	Size           int64
 	Total          int64
 	SerializedHash chainhash.Hash
+
+	// SetHash is the multiset hash of the utxo set which commits to all of
+	// its entries independent of their order.
+	SetHash chainhash.Hash
 }
 
 // FetchUtxoStats returns statistics on the current utxo set.
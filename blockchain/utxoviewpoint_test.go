@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/decred/dcrd/blockchain/v3/chaingen"
+	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/chaincfg/v3"
 	"github.com/decred/dcrd/dcrutil/v3"
 	"github.com/decred/dcrd/wire"
@@ -232,3 +233,53 @@ func TestFetchUtxoView(t *testing.T) {
 	}
 	testInputsSpent(view, spendB1aTx1Out0, true)
 }
+
+// TestUtxoSetHash ensures the incrementally maintained hash of the utxo set
+// matches the hash calculated from the entries as blocks are connected and
+// disconnected.
+func TestUtxoSetHash(t *testing.T) {
+	// Create a test harness initialized with the genesis block as the tip.
+	params := chaincfg.RegNetParams()
+	g, teardownFunc := newChaingenHarness(t, params, "utxosethashtest")
+	defer teardownFunc()
+
+	// fetchSetHash fetches the utxo stats, which also verifies the maintained
+	// utxo set hash against the calculated one, and returns the set hash.
+	fetchSetHash := func() chainhash.Hash {
+		t.Helper()
+		stats, err := g.chain.FetchUtxoStats()
+		if err != nil {
+			t.Fatalf("unexpected error fetching utxo stats: %v", err)
+		}
+		return stats.SetHash
+	}
+
+	// Ensure the hash of the initial empty utxo set is correct.
+	fetchSetHash()
+
+	// Ensure the hash is updated as blocks are connected.
+	//
+	//   genesis -> bfb -> b1 -> b2
+	g.CreateBlockOne("bfb", 0)
+	g.AcceptTipBlock()
+	g.NextBlock("b1", nil, nil)
+	g.AcceptTipBlock()
+	b1SetHash := fetchSetHash()
+	g.NextBlock("b2", nil, nil)
+	g.AcceptTipBlock()
+	if fetchSetHash() == b1SetHash {
+		t.Fatal("utxo set hash did not change when block was connected")
+	}
+
+	// Ensure the hash is updated as blocks are disconnected by reorganizing
+	// to a side chain.
+	//
+	//   genesis -> bfb -> b1 -> b2
+	//                       \-> b2a -> b3a
+	g.SetTip("b1")
+	g.NextBlock("b2a", nil, nil)
+	g.AcceptedToSideChainWithExpectedTip("b2")
+	g.NextBlock("b3a", nil, nil)
+	g.AcceptTipBlock()
+	fetchSetHash()
+}
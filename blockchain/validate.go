@@ -2192,7 +2192,7 @@ func CheckTransactionInputs(subsidyCache *standalone.SubsidyCache, tx *dcrutil.T
 				"transaction %s:%d either does not exist or "+
 				"has already been spent", txIn.PreviousOutPoint,
 				txHash, idx)
-			return 0, ruleError(ErrMissingTxOut, str)
+			return 0, ruleInputError(ErrMissingTxOut, idx, str)
 		}
 
 		// Check fraud proof witness data.
@@ -2202,7 +2202,7 @@ func CheckTransactionInputs(subsidyCache *standalone.SubsidyCache, tx *dcrutil.T
 			str := fmt.Sprintf("tried to spend zero value output "+
 				"from input %v, idx %v", txInHash,
 				originTxIndex)
-			return 0, ruleError(ErrZeroValueOutputSpend, str)
+			return 0, ruleInputError(ErrZeroValueOutputSpend, idx, str)
 		}
 
 		if checkFraudProof {
@@ -2212,7 +2212,7 @@ func CheckTransactionInputs(subsidyCache *standalone.SubsidyCache, tx *dcrutil.T
 					"(expected %v, given %v) for txIn %v",
 					utxoEntry.AmountByIndex(originTxIndex),
 					txIn.ValueIn, idx)
-				return 0, ruleError(ErrFraudAmountIn, str)
+				return 0, ruleInputError(ErrFraudAmountIn, idx, str)
 			}
 
 			if int64(txIn.BlockHeight) != utxoEntry.BlockHeight() {
@@ -2220,7 +2220,7 @@ func CheckTransactionInputs(subsidyCache *standalone.SubsidyCache, tx *dcrutil.T
 					"height (expected %v, given %v) for "+
 					"txIn %v", utxoEntry.BlockHeight(),
 					txIn.BlockHeight, idx)
-				return 0, ruleError(ErrFraudBlockHeight, str)
+				return 0, ruleInputError(ErrFraudBlockHeight, idx, str)
 			}
 
 			if txIn.BlockIndex != utxoEntry.BlockIndex() {
@@ -2228,7 +2228,7 @@ func CheckTransactionInputs(subsidyCache *standalone.SubsidyCache, tx *dcrutil.T
 					"index (expected %v, given %v) for "+
 					"txIn %v", utxoEntry.BlockIndex(),
 					txIn.BlockIndex, idx)
-				return 0, ruleError(ErrFraudBlockIndex, str)
+				return 0, ruleInputError(ErrFraudBlockIndex, idx, str)
 			}
 		}
 
@@ -2245,7 +2245,7 @@ func CheckTransactionInputs(subsidyCache *standalone.SubsidyCache, tx *dcrutil.T
 					"maturity of %v blocks", txHash,
 					txInHash, originHeight, txHeight,
 					coinbaseMaturity)
-				return 0, ruleError(ErrImmatureSpend, str)
+				return 0, ruleInputError(ErrImmatureSpend, idx, str)
 			}
 		}
 
@@ -2262,7 +2262,7 @@ func CheckTransactionInputs(subsidyCache *standalone.SubsidyCache, tx *dcrutil.T
 					"required maturity of %v blocks",
 					txHash, txInHash, originHeight,
 					txHeight, coinbaseMaturity)
-				return 0, ruleError(ErrExpiryTxSpentEarly, str)
+				return 0, ruleInputError(ErrExpiryTxSpentEarly, idx, str)
 			}
 		}
 
@@ -2279,7 +2279,7 @@ func CheckTransactionInputs(subsidyCache *standalone.SubsidyCache, tx *dcrutil.T
 				"specified a %v tx tree instead", txHash,
 				indicatedTree, txIn.PreviousOutPoint.Hash,
 				originTxOPTree)
-			return 0, ruleError(ErrDiscordantTxTree, errStr)
+			return 0, ruleInputError(ErrDiscordantTxTree, idx, errStr)
 		}
 
 		// The only transaction types that are allowed to spend from OP_SSTX
@@ -2298,7 +2298,7 @@ func CheckTransactionInputs(subsidyCache *standalone.SubsidyCache, tx *dcrutil.T
 					" tx; SSGen err: %v, SSRtx err: %v",
 					txHash, errSSGen.Error(),
 					errSSRtx.Error())
-				return 0, ruleError(ErrTxSStxOutSpend, errStr)
+				return 0, ruleInputError(ErrTxSStxOutSpend, idx, errStr)
 			}
 		}
 
@@ -2320,7 +2320,7 @@ func CheckTransactionInputs(subsidyCache *standalone.SubsidyCache, tx *dcrutil.T
 					"required maturity of %v blocks",
 					txInHash, originHeight, txHeight,
 					coinbaseMaturity)
-				return 0, ruleError(ErrImmatureSpend, str)
+				return 0, ruleInputError(ErrImmatureSpend, idx, str)
 			}
 		}
 
@@ -2338,7 +2338,7 @@ func CheckTransactionInputs(subsidyCache *standalone.SubsidyCache, tx *dcrutil.T
 					"height %v before required maturity "+
 					"of %v blocks", txInHash, originHeight,
 					txHeight, chainParams.SStxChangeMaturity)
-				return 0, ruleError(ErrImmatureSpend, str)
+				return 0, ruleInputError(ErrImmatureSpend, idx, str)
 			}
 		}
 
@@ -2926,11 +2926,18 @@ func (b *BlockChain) checkConnectBlock(node *blockNode, block, parent *dcrutil.B
 	// will therefore be detected by the next checkpoint).  This is a huge
 	// optimization because running the scripts is the most time consuming
 	// portion of block handling.
+	//
+	// Similarly, don't run scripts for the block that has been externally
+	// verified to be valid or any of its ancestors.  All other checks are
+	// still enforced.
 	checkpoint := b.LatestCheckpoint()
 	runScripts := !b.noVerify
 	if checkpoint != nil && node.height <= checkpoint.Height {
 		runScripts = false
 	}
+	if runScripts && b.isAssumedValid(node) {
+		runScripts = false
+	}
 	var scriptFlags txscript.ScriptFlags
 	if runScripts {
 		var err error
@@ -0,0 +1,121 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"time"
+)
+
+const (
+	// initialBlockBatchSize is the number of blocks requested from a peer in
+	// headers-first mode before its throughput has been measured.
+	initialBlockBatchSize = 64
+
+	// minBlockBatchSize and maxBlockBatchSize are the minimum and maximum
+	// number of blocks that are requested from a peer in headers-first
+	// mode at once.  They also bound the number of blocks in flight to the
+	// peer.
+	minBlockBatchSize = 16
+	maxBlockBatchSize = 2048
+
+	// blockBatchDuration is the amount of time it should take a peer to
+	// deliver a batch of requested blocks at its measured throughput.
+	blockBatchDuration = 10 * time.Second
+
+	// blockRefillDuration is the amount of time it should take a peer to
+	// deliver the blocks that remain in flight to it at its measured
+	// throughput when more blocks are requested in headers-first mode.  It
+	// ensures more blocks are requested from fast peers before the queue
+	// runs dry.
+	blockRefillDuration = 2 * time.Second
+
+	// blockIntervalWeight is the weight given to each new measurement of the
+	// interval between blocks delivered by a peer in the moving average of
+	// the interval.
+	blockIntervalWeight = 0.2
+)
+
+// blockThroughput tracks the rate a peer delivers requested blocks in order to
+// dynamically size the block requests made to it.  Faster peers are asked for
+// larger batches of blocks so they are not left idle while slower peers are
+// asked for smaller batches so they are not overloaded.
+//
+// It is not safe for concurrent access and is only used from the block
+// handler goroutine.
+type blockThroughput struct {
+	// avgInterval is the exponential moving average of the time between
+	// the blocks delivered by the peer.  It is zero until the first
+	// measurement.
+	avgInterval time.Duration
+
+	// lastEvent is the time the most recent block was delivered or, when
+	// the peer was idle, the time blocks were requested.  It is zero until
+	// blocks are requested.
+	lastEvent time.Time
+}
+
+// requested updates the throughput state when blocks are requested from the
+// peer.  The idle flag indicates whether there were no blocks in flight to
+// the peer prior to the request so that the time the peer spent idle is not
+// counted against it.
+func (t *blockThroughput) requested(now time.Time, idle bool) {
+	if idle || t.lastEvent.IsZero() {
+		t.lastEvent = now
+	}
+}
+
+// received updates the throughput state when the peer delivers a requested
+// block.
+func (t *blockThroughput) received(now time.Time) {
+	if t.lastEvent.IsZero() {
+		return
+	}
+
+	interval := now.Sub(t.lastEvent)
+	if interval < 0 {
+		interval = 0
+	}
+	t.lastEvent = now
+	if t.avgInterval == 0 {
+		t.avgInterval = interval
+		return
+	}
+	t.avgInterval = time.Duration(blockIntervalWeight*float64(interval) +
+		(1-blockIntervalWeight)*float64(t.avgInterval))
+}
+
+// blocksIn returns the number of blocks the peer is expected to deliver in the
+// provided duration at its measured throughput limited to the provided bounds.
+func (t *blockThroughput) blocksIn(d time.Duration, min, max int) int {
+	n := max
+	if t.avgInterval > 0 && d/t.avgInterval < time.Duration(max) {
+		n = int(d / t.avgInterval)
+	}
+	if n < min {
+		n = min
+	}
+	return n
+}
+
+// batchSize returns the maximum number of blocks that should be in flight to
+// the peer, which is the number it is expected to deliver in the target batch
+// duration at its measured throughput.
+func (t *blockThroughput) batchSize() int {
+	if t.avgInterval == 0 {
+		return initialBlockBatchSize
+	}
+	return t.blocksIn(blockBatchDuration, minBlockBatchSize,
+		maxBlockBatchSize)
+}
+
+// refillThreshold returns the number of blocks in flight to the peer below
+// which more blocks should be requested from it.
+func (t *blockThroughput) refillThreshold() int {
+	if t.avgInterval == 0 {
+		return minInFlightBlocks
+	}
+	return t.blocksIn(blockRefillDuration, minInFlightBlocks,
+		t.batchSize()/2)
+}
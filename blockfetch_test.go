@@ -0,0 +1,79 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// TestBlockThroughput ensures the block request batch size and refill
+// threshold adapt to the measured block throughput of a peer within the
+// expected bounds and that time a peer spends idle is not measured.
+func TestBlockThroughput(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		interval      time.Duration
+		wantBatchSize int
+		wantRefill    int
+	}{{
+		name:          "fast peer limited to max batch size",
+		interval:      time.Millisecond,
+		wantBatchSize: maxBlockBatchSize,
+		wantRefill:    maxBlockBatchSize / 2,
+	}, {
+		name:          "moderate peer",
+		interval:      20 * time.Millisecond,
+		wantBatchSize: 500,
+		wantRefill:    100,
+	}, {
+		name:          "slow peer limited to min batch size",
+		interval:      2 * time.Second,
+		wantBatchSize: minBlockBatchSize,
+		wantRefill:    minInFlightBlocks,
+	}}
+
+	for _, test := range tests {
+		var tput blockThroughput
+		if got := tput.batchSize(); got != initialBlockBatchSize {
+			t.Fatalf("%q: unexpected initial batch size -- got %d, want %d",
+				test.name, got, initialBlockBatchSize)
+		}
+		if got := tput.refillThreshold(); got != minInFlightBlocks {
+			t.Fatalf("%q: unexpected initial refill threshold -- got %d, "+
+				"want %d", test.name, got, minInFlightBlocks)
+		}
+
+		// Deliver blocks at the test interval with an idle period in the
+		// middle that must not affect the measurement.
+		now := time.Unix(1592918788, 0)
+		tput.requested(now, true)
+		for i := 0; i < 20; i++ {
+			now = now.Add(test.interval)
+			tput.received(now)
+		}
+		now = now.Add(time.Hour)
+		tput.requested(now, true)
+		for i := 0; i < 20; i++ {
+			now = now.Add(test.interval)
+			tput.received(now)
+		}
+
+		if tput.avgInterval != test.interval {
+			t.Fatalf("%q: unexpected average interval -- got %v, want %v",
+				test.name, tput.avgInterval, test.interval)
+		}
+		if got := tput.batchSize(); got != test.wantBatchSize {
+			t.Fatalf("%q: unexpected batch size -- got %d, want %d",
+				test.name, got, test.wantBatchSize)
+		}
+		if got := tput.refillThreshold(); got != test.wantRefill {
+			t.Fatalf("%q: unexpected refill threshold -- got %d, want %d",
+				test.name, got, test.wantRefill)
+		}
+	}
+}
@@ -6,7 +6,6 @@
 package main
 
 import (
-	"container/list"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -22,6 +21,7 @@ import (
 	"github.com/decred/dcrd/chaincfg/v3"
 	"github.com/decred/dcrd/database/v2"
 	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/decred/dcrd/headersync"
 	"github.com/decred/dcrd/internal/fees"
 	"github.com/decred/dcrd/internal/mempool"
 	"github.com/decred/dcrd/internal/mining"
@@ -33,7 +33,8 @@ import (
 const (
 	// minInFlightBlocks is the minimum number of blocks that should be
 	// in the request queue for headers-first mode before requesting
-	// more.
+	// more.  The threshold is raised for peers that are measured to
+	// deliver blocks quickly.  See blockThroughput for details.
 	minInFlightBlocks = 10
 
 	// maxOrphanBlocks is the maximum number of orphan blocks that can be
@@ -46,7 +47,7 @@ const (
 	blockDbNamePrefix = "blocks"
 
 	// maxRejectedTxns is the maximum number of rejected transactions
-	// hashes to store in memory.
+	// along with the reasons they were rejected to store in memory.
 	maxRejectedTxns = 1000
 
 	// maxRequestedBlocks is the maximum number of requested block
@@ -92,8 +93,9 @@ type blockMsg struct {
 // invMsg packages a Decred inv message and the peer it came from together
 // so the block handler has access to that information.
 type invMsg struct {
-	inv  *wire.MsgInv
-	peer *peerpkg.Peer
+	inv       *wire.MsgInv
+	peer      *peerpkg.Peer
+	feeExempt bool
 }
 
 // headersMsg packages a Decred headers message and the peer it came from
@@ -118,9 +120,12 @@ type donePeerMsg struct {
 // txMsg packages a Decred tx message and the peer it came from together
 // so the block handler has access to that information.
 type txMsg struct {
-	tx    *dcrutil.Tx
-	peer  *peerpkg.Peer
-	reply chan struct{}
+	tx        *dcrutil.Tx
+	peer      *peerpkg.Peer
+	reply     chan txAcceptOutcome
+	received  time.Time
+	priority  bool
+	feeExempt bool
 }
 
 // getSyncPeerMsg is a message type to be sent across the message channel for
@@ -129,6 +134,12 @@ type getSyncPeerMsg struct {
 	reply chan int32
 }
 
+// getSyncPeerInfoMsg is a message type to be sent across the message channel
+// for retrieving details about the current sync peer and the changes to it.
+type getSyncPeerInfoMsg struct {
+	reply chan *rpcserver.SyncPeerInfo
+}
+
 // requestFromPeerMsg is a message type to be sent across the message channel
 // for requesting either blocks or transactions from a given peer. It routes
 // this through the block manager so the block manager doesn't ban the peer
@@ -222,8 +233,11 @@ type processTransactionMsg struct {
 	allowOrphans  bool
 	rateLimit     bool
 	allowHighFees bool
+	feeExempt     bool
 	tag           mempool.Tag
 	reply         chan processTransactionResponse
+	received      time.Time
+	priority      bool
 }
 
 // isCurrentMsg is a message type to be sent across the message channel for
@@ -233,12 +247,10 @@ type isCurrentMsg struct {
 	reply chan bool
 }
 
-// headerNode is used as a node in a list of headers that are linked together
-// between checkpoints.
-type headerNode struct {
-	height int64
-	hash   *chainhash.Hash
-}
+// requestQuarantinedMsg is a message type to be sent across the message channel
+// for requesting a fresh copy of all blocks that have been quarantined due to
+// their stored data being corrupted.
+type requestQuarantinedMsg struct{}
 
 // peerNotifier provides an interface for server peer notifications.
 type peerNotifier interface {
@@ -279,6 +291,14 @@ type blockManagerConfig struct {
 	NotifyWinningTickets      func(*rpcserver.WinningTicketsNtfnData)
 	PruneRebroadcastInventory func()
 	RpcServer                 func() *rpcserver.Server
+
+	// AlertOutbox records critical events.  It is nil when no kinds of
+	// critical events are enabled.
+	AlertOutbox *alertOutbox
+
+	// Webhooks delivers events to webhooks.  It is nil when no webhooks are
+	// configured.
+	Webhooks *webhookManager
 }
 
 // peerSyncState stores additional information that the blockManager tracks
@@ -287,6 +307,14 @@ type peerSyncState struct {
 	syncCandidate   bool
 	requestedTxns   map[chainhash.Hash]struct{}
 	requestedBlocks map[chainhash.Hash]struct{}
+
+	// throughput tracks the rate the peer delivers requested blocks in
+	// order to size the block requests made to it in headers-first mode.
+	throughput blockThroughput
+
+	// download tracks the deadline for the peer to deliver requested blocks
+	// and the rate it delivers them in order to detect stalls.
+	download blockDownloadMonitor
 }
 
 // orphanBlock represents a block for which the parent is not yet available.  It
@@ -303,21 +331,19 @@ type blockManager struct {
 	cfg             *blockManagerConfig
 	started         int32
 	shutdown        int32
-	rejectedTxns    map[chainhash.Hash]struct{}
+	rejectedTxns    *rejectedTxCache
 	requestedTxns   map[chainhash.Hash]struct{}
 	requestedBlocks map[chainhash.Hash]struct{}
 	progressLogger  *blockProgressLogger
 	syncPeer        *peerpkg.Peer
 	msgChan         chan interface{}
+	priorityMsgChan chan interface{}
 	wg              sync.WaitGroup
 	quit            chan struct{}
 	peerStates      map[*peerpkg.Peer]*peerSyncState
 
-	// The following fields are used for headers-first mode.
-	headersFirstMode bool
-	headerList       *list.List
-	startHeader      *list.Element
-	nextCheckpoint   *chaincfg.Checkpoint
+	// headerSync tracks the state of headers-first mode.
+	headerSync *headersync.Downloader
 
 	// These fields are related to handling of orphan blocks.  They are
 	// protected by the orphan lock.
@@ -341,22 +367,58 @@ type blockManager struct {
 	// peers.
 	syncHeightMtx sync.Mutex
 	syncHeight    int64
+
+	// txRelayMetrics tracks the latency of the transactions processed via
+	// the priority and general queues.
+	txRelayMetrics txRelayMetrics
+
+	// syncHistory tracks the changes to the sync peer.
+	syncHistory syncPeerHistory
+}
+
+// txRelayMetrics tracks the latency between receiving transactions and
+// accepting them to the mempool and announcing them for relay separately for
+// the priority queue used by votes and revocations and the general queue used
+// by all other transactions.  The zero value is ready for use.
+//
+// It is safe for concurrent access.
+type txRelayMetrics struct {
+	mtx      sync.Mutex
+	priority rpcserver.TxRelayLaneStats
+	regular  rpcserver.TxRelayLaneStats
+}
+
+// record adds the latency of a transaction processed via the priority or
+// general queue to the statistics.
+func (m *txRelayMetrics) record(priority bool, latency time.Duration) {
+	m.mtx.Lock()
+	stats := &m.regular
+	if priority {
+		stats = &m.priority
+	}
+	stats.Count++
+	stats.TotalLatency += latency
+	if latency > stats.MaxLatency {
+		stats.MaxLatency = latency
+	}
+	m.mtx.Unlock()
+}
+
+// Stats returns a snapshot of the current statistics for the priority and
+// general queues.
+//
+// This function is safe for concurrent access.
+func (m *txRelayMetrics) Stats() (priority, regular rpcserver.TxRelayLaneStats) {
+	m.mtx.Lock()
+	priority, regular = m.priority, m.regular
+	m.mtx.Unlock()
+	return priority, regular
 }
 
 // resetHeaderState sets the headers-first mode state to values appropriate for
 // syncing from a new peer.
 func (b *blockManager) resetHeaderState(newestHash *chainhash.Hash, newestHeight int64) {
-	b.headersFirstMode = false
-	b.headerList.Init()
-	b.startHeader = nil
-
-	// When there is a next checkpoint, add an entry for the latest known
-	// block into the header pool.  This allows the next downloaded header
-	// to prove it links to the chain properly.
-	if b.nextCheckpoint != nil {
-		node := headerNode{height: newestHeight, hash: newestHash}
-		b.headerList.PushBack(&node)
-	}
+	b.headerSync.Reset(newestHash, newestHeight, nil)
 }
 
 // NotifyWork passes new mining work to the notification manager
@@ -374,39 +436,6 @@ func (b *blockManager) SyncHeight() int64 {
 	return b.syncHeight
 }
 
-// findNextHeaderCheckpoint returns the next checkpoint after the passed height.
-// It returns nil when there is not one either because the height is already
-// later than the final checkpoint or some other reason such as disabled
-// checkpoints.
-func (b *blockManager) findNextHeaderCheckpoint(height int64) *chaincfg.Checkpoint {
-	// There is no next checkpoint if checkpoints are disabled or there are
-	// none for this current network.
-	if cfg.DisableCheckpoints {
-		return nil
-	}
-	checkpoints := b.cfg.Chain.Checkpoints()
-	if len(checkpoints) == 0 {
-		return nil
-	}
-
-	// There is no next checkpoint if the height is already after the final
-	// checkpoint.
-	finalCheckpoint := &checkpoints[len(checkpoints)-1]
-	if height >= finalCheckpoint.Height {
-		return nil
-	}
-
-	// Find the next checkpoint.
-	nextCheckpoint := finalCheckpoint
-	for i := len(checkpoints) - 2; i >= 0; i-- {
-		if height >= checkpoints[i].Height {
-			break
-		}
-		nextCheckpoint = &checkpoints[i]
-	}
-	return nextCheckpoint
-}
-
 // chainBlockLocatorToHashes converts a block locator from chain to a slice
 // of hashes.
 func chainBlockLocatorToHashes(locator blockchain.BlockLocator) []chainhash.Hash {
@@ -493,20 +522,17 @@ func (b *blockManager) startSync() {
 		// and fully validate them.  Finally, regression test mode does
 		// not support the headers-first approach so do normal block
 		// downloads when in regression test mode.
-		if b.nextCheckpoint != nil &&
-			best.Height < b.nextCheckpoint.Height &&
-			!cfg.DisableCheckpoints {
-
-			err := bestPeer.PushGetHeadersMsg(locator, b.nextCheckpoint.Hash)
+		b.resetHeaderState(&best.Hash, best.Height)
+		if hashStop, ok := b.headerSync.Start(); ok {
+			err := bestPeer.PushGetHeadersMsg(locator, hashStop)
 			if err != nil {
 				bmgrLog.Errorf("Failed to push getheadermsg for the "+
 					"latest blocks: %v", err)
 				return
 			}
-			b.headersFirstMode = true
 			bmgrLog.Infof("Downloading headers for blocks %d to "+
 				"%d from peer %s", best.Height+1,
-				b.nextCheckpoint.Height, bestPeer.Addr())
+				b.headerSync.NextCheckpoint().Height, bestPeer.Addr())
 		} else {
 			err := bestPeer.PushGetBlocksMsg(locator, &zeroHash)
 			if err != nil {
@@ -516,14 +542,59 @@ func (b *blockManager) startSync() {
 			}
 		}
 		b.syncPeer = bestPeer
+		b.syncHistory.selected(time.Now(), bestPeer.ID(), bestPeer.Addr())
 		b.syncHeightMtx.Lock()
 		b.syncHeight = bestPeer.LastBlock()
 		b.syncHeightMtx.Unlock()
+		b.requestQuarantinedBlocks()
 	} else {
 		bmgrLog.Warnf("No sync peer candidates available")
 	}
 }
 
+// requestQuarantinedBlocks requests a fresh copy of all blocks that have been
+// quarantined due to their stored data being corrupted, and are not already
+// being requested, from the sync peer.  The chain repairs the blocks with the
+// copies once they arrive.
+//
+// This function MUST be called from the block handler goroutine.
+func (b *blockManager) requestQuarantinedBlocks() {
+	// Start syncing in order to choose a sync peer when there is not one
+	// already.  This function is invoked again when a sync peer is chosen.
+	if b.syncPeer == nil {
+		b.startSync()
+		return
+	}
+	state, exists := b.peerStates[b.syncPeer]
+	if !exists {
+		return
+	}
+
+	idle := len(state.requestedBlocks) == 0
+	gdmsg := wire.NewMsgGetData()
+	for _, hash := range b.cfg.Chain.QuarantinedBlocks() {
+		if _, exists := b.requestedBlocks[hash]; exists {
+			continue
+		}
+		if len(gdmsg.InvList) == wire.MaxInvPerMsg {
+			break
+		}
+
+		hash := hash
+		limitAdd(b.requestedBlocks, hash, maxRequestedBlocks)
+		limitAdd(state.requestedBlocks, hash, maxRequestedBlocks)
+		gdmsg.AddInvVect(wire.NewInvVect(wire.InvTypeBlock, &hash))
+	}
+	if len(gdmsg.InvList) > 0 {
+		bmgrLog.Infof("Requesting %d quarantined block(s) from %s",
+			len(gdmsg.InvList), b.syncPeer)
+		now := time.Now()
+		state.throughput.requested(now, idle)
+		state.download.requested(now)
+		b.syncPeer.QueueMessage(gdmsg, nil)
+	}
+}
+
 // isSyncCandidate returns whether or not the peer is a candidate to consider
 // syncing from.
 func (b *blockManager) isSyncCandidate(peer *peerpkg.Peer) bool {
@@ -613,7 +684,8 @@ func (b *blockManager) handleDonePeerMsg(peer *peerpkg.Peer) {
 	// mode so
 	if b.syncPeer == peer {
 		b.syncPeer = nil
-		if b.headersFirstMode {
+		b.syncHistory.removed(syncSwitchDisconnected)
+		if b.headerSync.Active() {
 			best := b.cfg.Chain.BestSnapshot()
 			b.resetHeaderState(&best.Hash, best.Height)
 		}
@@ -679,13 +751,16 @@ func errToWireRejectCode(err error) (wire.RejectCode, string) {
 		case mempool.ErrOrphanPolicyViolation,
 			mempool.ErrOldVote,
 			mempool.ErrSeqLockUnmet,
-			mempool.ErrNonStandard:
+			mempool.ErrNonStandard,
+			mempool.ErrReplacementPolicyViolation,
+			mempool.ErrExternalPolicy:
 
 			code = wire.RejectNonstandard
 
 		// Error codes which map to an insufficient fee being paid.
 		case mempool.ErrInsufficientFee,
-			mempool.ErrInsufficientPriority:
+			mempool.ErrInsufficientPriority,
+			mempool.ErrInsufficientReplacementFee:
 
 			code = wire.RejectInsufficientFee
 
@@ -701,13 +776,15 @@ func errToWireRejectCode(err error) (wire.RejectCode, string) {
 	return code, reason
 }
 
-// handleTxMsg handles transaction messages from all peers.
-func (b *blockManager) handleTxMsg(tmsg *txMsg) {
+// handleTxMsg handles transaction messages from all peers.  It returns the
+// outcome of processing the transaction so the resources it consumed can be
+// charged to the transaction acceptance budgets of the peer.
+func (b *blockManager) handleTxMsg(tmsg *txMsg) txAcceptOutcome {
 	peer := tmsg.peer
 	state, exists := b.peerStates[peer]
 	if !exists {
 		bmgrLog.Warnf("Received tx message from unknown peer %s", peer)
-		return
+		return txAcceptOutcome{}
 	}
 
 	// NOTE:  BitcoinJ, and possibly other wallets, don't follow the spec of
@@ -723,17 +800,35 @@ func (b *blockManager) handleTxMsg(tmsg *txMsg) {
 	// Ignore transactions that we have already rejected.  Do not
 	// send a reject message here because if the transaction was already
 	// rejected, the transaction was unsolicited.
-	if _, exists = b.rejectedTxns[*txHash]; exists {
+	best := b.cfg.Chain.BestSnapshot()
+	if b.rejectedTxns.isRejected(txHash, &best.Hash, tmsg.feeExempt) {
 		bmgrLog.Debugf("Ignoring unsolicited previously rejected "+
 			"transaction %v from %s", txHash, peer)
-		return
+		return txAcceptOutcome{}
 	}
 
 	// Process the transaction to include validation, insertion in the
-	// memory pool, orphan handling, etc.
+	// memory pool, orphan handling, etc.  Transactions from peers the
+	// operator designated as exempt from the minimum relay fee bypass it.
 	allowOrphans := cfg.MaxOrphanTxs > 0
-	acceptedTxs, err := b.cfg.TxMemPool.ProcessTransaction(tmsg.tx,
-		allowOrphans, true, true, mempool.Tag(tmsg.peer.ID()))
+	tag := mempool.Tag(tmsg.peer.ID())
+	var acceptedTxs []*dcrutil.Tx
+	var err error
+	start := time.Now()
+	if tmsg.feeExempt {
+		acceptedTxs, err = b.cfg.TxMemPool.ProcessFeeExemptTransaction(
+			tmsg.tx, allowOrphans, true, tag)
+	} else {
+		acceptedTxs, err = b.cfg.TxMemPool.ProcessTransaction(tmsg.tx,
+			allowOrphans, true, true, tag)
+	}
+	outcome := txAcceptOutcome{
+		orphan: (err == nil && len(acceptedTxs) == 0) ||
+			mempool.IsErrorCode(err, mempool.ErrOrphan) ||
+			mempool.IsErrorCode(err, mempool.ErrOrphanPolicyViolation),
+		invalid:        isInvalidTxAttempt(err),
+		validationTime: time.Since(start),
+	}
 
 	// Remove transaction from request maps. Either the mempool/chain
 	// already knows about it and as such we shouldn't have any more
@@ -744,8 +839,18 @@ func (b *blockManager) handleTxMsg(tmsg *txMsg) {
 
 	if err != nil {
 		// Do not request this transaction again until a new block
-		// has been processed.
-		limitAdd(b.rejectedTxns, *txHash, maxRejectedTxns)
+		// has been processed unless the reason it was rejected does not
+		// depend on the state of the chain.
+		code, reason := errToWireRejectCode(err)
+		rejectedTx := rpcserver.RejectedTx{
+			Hash:   *txHash,
+			Code:   code,
+			Reason: reason,
+			Time:   time.Now(),
+			Height: best.Height,
+		}
+		b.rejectedTxns.add(&rejectedTx, &best.Hash,
+			isPermanentTxRejection(err))
 
 		// When the error is a rule error, it means the transaction was
 		// simply rejected as opposed to something actually going wrong,
@@ -760,14 +865,35 @@ func (b *blockManager) handleTxMsg(tmsg *txMsg) {
 				txHash, err)
 		}
 
-		// Convert the error into an appropriate reject message and
-		// send it.
-		code, reason := errToWireRejectCode(err)
+		// Send an appropriate reject message.
 		peer.PushRejectMsg(wire.CmdTx, code, reason, txHash, false)
-		return
+		return outcome
 	}
 
 	b.cfg.PeerNotifier.AnnounceNewTransactions(acceptedTxs)
+	b.txRelayMetrics.record(tmsg.priority, time.Since(tmsg.received))
+	return outcome
+}
+
+// handleProcessTransactionMsg handles transactions submitted for processing
+// from sources other than peers such as the RPC server.
+func (b *blockManager) handleProcessTransactionMsg(msg processTransactionMsg) {
+	var acceptedTxs []*dcrutil.Tx
+	var err error
+	if msg.feeExempt {
+		acceptedTxs, err = b.cfg.TxMemPool.ProcessFeeExemptTransaction(
+			msg.tx, msg.allowOrphans, msg.allowHighFees, msg.tag)
+	} else {
+		acceptedTxs, err = b.cfg.TxMemPool.ProcessTransaction(msg.tx,
+			msg.allowOrphans, msg.rateLimit, msg.allowHighFees, msg.tag)
+	}
+	if err == nil {
+		b.txRelayMetrics.record(msg.priority, time.Since(msg.received))
+	}
+	msg.reply <- processTransactionResponse{
+		acceptedTxs: acceptedTxs,
+		err:         err,
+	}
 }
 
 // isKnownOrphan returns whether the passed hash is currently a known orphan.
@@ -1034,24 +1160,10 @@ func (b *blockManager) handleBlockMsg(bmsg *blockMsg) {
 	// first header in the list of headers that are being fetched, it's
 	// eligible for less validation since the headers have already been
 	// verified to link together and are valid up to the next checkpoint.
-	// Also, remove the list entry for all blocks except the checkpoint
-	// since it is needed to verify the next round of headers links
-	// properly.
-	isCheckpointBlock := false
 	behaviorFlags := blockchain.BFNone
-	if b.headersFirstMode {
-		firstNodeEl := b.headerList.Front()
-		if firstNodeEl != nil {
-			firstNode := firstNodeEl.Value.(*headerNode)
-			if blockHash.IsEqual(firstNode.hash) {
-				behaviorFlags |= blockchain.BFFastAdd
-				if firstNode.hash.IsEqual(b.nextCheckpoint.Hash) {
-					isCheckpointBlock = true
-				} else {
-					b.headerList.Remove(firstNodeEl)
-				}
-			}
-		}
+	fastAdd, isCheckpointBlock := b.headerSync.BlockReceived(blockHash)
+	if fastAdd {
+		behaviorFlags |= blockchain.BFFastAdd
 	}
 
 	// Remove block from request maps. Either chain will know about it and
@@ -1059,6 +1171,21 @@ func (b *blockManager) handleBlockMsg(bmsg *blockMsg) {
 	// will fail the insert and thus we'll retry next time we get an inv.
 	delete(state.requestedBlocks, *blockHash)
 	delete(b.requestedBlocks, *blockHash)
+	now := time.Now()
+	state.throughput.received(now)
+	state.download.received(now, len(state.requestedBlocks))
+
+	// Blocks that were quarantined due to their stored data being corrupted
+	// are already part of the chain, so use the fresh copy to repair the
+	// stored data instead of processing it.
+	if b.cfg.Chain.IsBlockQuarantined(blockHash) {
+		err := b.cfg.Chain.RepairBlock(bmsg.block)
+		if err != nil {
+			bmgrLog.Warnf("Failed to repair quarantined block %v with "+
+				"block from %s: %v", blockHash, peer, err)
+		}
+		return
+	}
 
 	// Process the block to include validation, best chain selection, orphan
 	// handling, etc.
@@ -1127,9 +1254,6 @@ func (b *blockManager) handleBlockMsg(bmsg *blockMsg) {
 			}
 			b.cfg.TxMemPool.PruneStakeTx(best.NextStakeDiff, best.Height)
 			b.cfg.TxMemPool.PruneExpiredTx()
-
-			// Clear the rejected transactions.
-			b.rejectedTxns = make(map[chainhash.Hash]struct{})
 		}
 	}
 
@@ -1148,16 +1272,19 @@ func (b *blockManager) handleBlockMsg(bmsg *blockMsg) {
 	}
 
 	// Nothing more to do if we aren't in headers-first mode.
-	if !b.headersFirstMode {
+	if !b.headerSync.Active() {
 		return
 	}
 
 	// This is headers-first mode, so if the block is not a checkpoint
 	// request more blocks using the header list when the request queue is
-	// getting short.
+	// getting short.  The queue is considered short when the peer is
+	// expected to deliver the remaining blocks in a couple of seconds at its
+	// measured throughput.
 	if !isCheckpointBlock {
-		if b.startHeader != nil &&
-			len(state.requestedBlocks) < minInFlightBlocks {
+		if b.headerSync.HasPendingBlocks() && len(state.requestedBlocks) <
+			state.throughput.refillThreshold() {
+
 			b.fetchHeaderBlocks()
 		}
 		return
@@ -1167,19 +1294,17 @@ func (b *blockManager) handleBlockMsg(bmsg *blockMsg) {
 	// there is a next checkpoint, get the next round of headers by asking
 	// for headers starting from the block after this one up to the next
 	// checkpoint.
-	prevHeight := b.nextCheckpoint.Height
-	prevHash := b.nextCheckpoint.Hash
-	b.nextCheckpoint = b.findNextHeaderCheckpoint(prevHeight)
-	if b.nextCheckpoint != nil {
-		locator := []chainhash.Hash{*prevHash}
-		err := peer.PushGetHeadersMsg(locator, b.nextCheckpoint.Hash)
+	prevHeight := b.headerSync.NextCheckpoint().Height
+	if b.headerSync.AdvancePhase() {
+		locator, hashStop := b.headerSync.HeadersRequest()
+		err := peer.PushGetHeadersMsg(locator, hashStop)
 		if err != nil {
 			bmgrLog.Warnf("Failed to send getheaders message to "+
 				"peer %s: %v", peer.Addr(), err)
 			return
 		}
 		bmgrLog.Infof("Downloading headers for blocks %d to %d from "+
-			"peer %s", prevHeight+1, b.nextCheckpoint.Height,
+			"peer %s", prevHeight+1, b.headerSync.NextCheckpoint().Height,
 			b.syncPeer.Addr())
 		return
 	}
@@ -1187,8 +1312,6 @@ func (b *blockManager) handleBlockMsg(bmsg *blockMsg) {
 	// This is headers-first mode, the block is a checkpoint, and there are
 	// no more checkpoints, so switch to normal mode by requesting blocks
 	// from the block after this one up to the end of the chain (zero hash).
-	b.headersFirstMode = false
-	b.headerList.Init()
 	bmgrLog.Infof("Reached the final checkpoint -- switching to normal mode")
 	locator := []chainhash.Hash{*blockHash}
 	err = bmsg.peer.PushGetBlocksMsg(locator, &zeroHash)
@@ -1201,50 +1324,57 @@ func (b *blockManager) handleBlockMsg(bmsg *blockMsg) {
 
 // fetchHeaderBlocks creates and sends a request to the syncPeer for the next
 // list of blocks to be downloaded based on the current list of headers.
+//
+// The number of blocks requested is dynamically sized based on the measured
+// throughput of the sync peer such that the number of blocks in flight to it
+// does not exceed the number it is expected to deliver in blockBatchDuration.
 func (b *blockManager) fetchHeaderBlocks() {
-	// Nothing to do if there is no start header.
-	if b.startHeader == nil {
-		bmgrLog.Warnf("fetchHeaderBlocks called with no start header")
+	// Nothing to do if there are no blocks left to request.
+	if !b.headerSync.HasPendingBlocks() {
+		bmgrLog.Warnf("fetchHeaderBlocks called with no pending blocks")
 		return
 	}
 
-	// Build up a getdata request for the list of blocks the headers
-	// describe.  The size hint will be limited to wire.MaxInvPerMsg by
-	// the function, so no need to double check it here.
-	gdmsg := wire.NewMsgGetDataSizeHint(uint(b.headerList.Len()))
-	numRequested := 0
-	for e := b.startHeader; e != nil; e = e.Next() {
-		node, ok := e.Value.(*headerNode)
-		if !ok {
-			bmgrLog.Warn("Header list node type is not a headerNode")
-			continue
-		}
+	// Nothing to do when the sync peer already has as many blocks in
+	// flight as it is allowed.
+	syncPeerState := b.peerStates[b.syncPeer]
+	numInFlight := len(syncPeerState.requestedBlocks)
+	maxRequest := syncPeerState.throughput.batchSize() - numInFlight
+	if maxRequest <= 0 {
+		return
+	}
 
-		iv := wire.NewInvVect(wire.InvTypeBlock, node.hash)
+	// Build up a getdata request for the list of blocks the headers
+	// describe.  Blocks that can't be checked for existing inventory are
+	// skipped.
+	haveBlock := func(hash *chainhash.Hash) bool {
+		iv := wire.NewInvVect(wire.InvTypeBlock, hash)
 		haveInv, err := b.haveInventory(iv)
 		if err != nil {
 			bmgrLog.Warnf("Unexpected failure when checking for "+
 				"existing inventory during header block "+
 				"fetch: %v", err)
-			continue
-		}
-		if !haveInv {
-			b.requestedBlocks[*node.hash] = struct{}{}
-			syncPeerState := b.peerStates[b.syncPeer]
-			syncPeerState.requestedBlocks[*node.hash] = struct{}{}
-			err = gdmsg.AddInvVect(iv)
-			if err != nil {
-				bmgrLog.Warnf("Failed to add invvect while fetching "+
-					"block headers: %v", err)
-			}
-			numRequested++
+			return true
 		}
-		b.startHeader = e.Next()
-		if numRequested >= wire.MaxInvPerMsg {
-			break
+		return haveInv
+	}
+	hashes := b.headerSync.NextBlocks(maxRequest, haveBlock)
+	gdmsg := wire.NewMsgGetDataSizeHint(uint(len(hashes)))
+	for i := range hashes {
+		hash := &hashes[i]
+		b.requestedBlocks[*hash] = struct{}{}
+		syncPeerState.requestedBlocks[*hash] = struct{}{}
+		iv := wire.NewInvVect(wire.InvTypeBlock, hash)
+		err := gdmsg.AddInvVect(iv)
+		if err != nil {
+			bmgrLog.Warnf("Failed to add invvect while fetching "+
+				"block headers: %v", err)
 		}
 	}
 	if len(gdmsg.InvList) > 0 {
+		now := time.Now()
+		syncPeerState.throughput.requested(now, numInFlight == 0)
+		syncPeerState.download.requested(now)
 		b.syncPeer.QueueMessage(gdmsg, nil)
 	}
 }
@@ -1258,96 +1388,41 @@ func (b *blockManager) handleHeadersMsg(hmsg *headersMsg) {
 		return
 	}
 
-	// The remote peer is misbehaving if we didn't request headers.
+	// Process all of the received headers ensuring each one connects to the
+	// previous and that checkpoints match.  The remote peer is misbehaving
+	// if the headers were not requested or violate any of the rules.
 	msg := hmsg.headers
 	numHeaders := len(msg.Headers)
-	if !b.headersFirstMode {
-		bmgrLog.Warnf("Got %d unrequested headers from %s -- "+
-			"disconnecting", numHeaders, peer.Addr())
+	done, err := b.headerSync.ProcessHeaders(msg.Headers)
+	if err != nil {
+		bmgrLog.Warnf("Rejected %d block headers from peer %s: %v -- "+
+			"disconnecting", numHeaders, peer.Addr(), err)
 		peer.Disconnect()
 		return
 	}
 
-	// Nothing to do for an empty headers message.
-	if numHeaders == 0 {
+	// When the headers reached the checkpoint, switch to fetching the blocks
+	// for all of the headers since the last checkpoint.
+	if done {
+		checkpoint := b.headerSync.NextCheckpoint()
+		bmgrLog.Infof("Verified downloaded block headers against "+
+			"checkpoint at height %d/hash %s: Fetching blocks",
+			checkpoint.Height, checkpoint.Hash)
+		b.progressLogger.SetLastLogTime(time.Now())
+		b.fetchHeaderBlocks()
 		return
 	}
 
-	// Process all of the received headers ensuring each one connects to the
-	// previous and that checkpoints match.
-	receivedCheckpoint := false
-	var finalHash *chainhash.Hash
-	for _, blockHeader := range msg.Headers {
-		blockHash := blockHeader.BlockHash()
-		finalHash = &blockHash
-
-		// Ensure there is a previous header to compare against.
-		prevNodeEl := b.headerList.Back()
-		if prevNodeEl == nil {
-			bmgrLog.Warnf("Header list does not contain a previous " +
-				"element as expected -- disconnecting peer")
-			peer.Disconnect()
-			return
-		}
-
-		// Ensure the header properly connects to the previous one and
-		// add it to the list of headers.
-		node := headerNode{hash: &blockHash}
-		prevNode := prevNodeEl.Value.(*headerNode)
-		if prevNode.hash.IsEqual(&blockHeader.PrevBlock) {
-			node.height = prevNode.height + 1
-			e := b.headerList.PushBack(&node)
-			if b.startHeader == nil {
-				b.startHeader = e
-			}
-		} else {
-			bmgrLog.Warnf("Received block header that does not "+
-				"properly connect to the chain from peer %s "+
-				"-- disconnecting", peer.Addr())
-			peer.Disconnect()
-			return
-		}
-
-		// Verify the header at the next checkpoint height matches.
-		if node.height == b.nextCheckpoint.Height {
-			if node.hash.IsEqual(b.nextCheckpoint.Hash) {
-				receivedCheckpoint = true
-				bmgrLog.Infof("Verified downloaded block "+
-					"header against checkpoint at height "+
-					"%d/hash %s", node.height, node.hash)
-			} else {
-				bmgrLog.Warnf("Block header at height %d/hash "+
-					"%s from peer %s does NOT match "+
-					"expected checkpoint hash of %s -- "+
-					"disconnecting", node.height, node.hash,
-					peer.Addr(), b.nextCheckpoint.Hash)
-				peer.Disconnect()
-				return
-			}
-			break
-		}
-	}
-
-	// When this header is a checkpoint, switch to fetching the blocks for
-	// all of the headers since the last checkpoint.
-	if receivedCheckpoint {
-		// Since the first entry of the list is always the final block
-		// that is already in the database and is only used to ensure
-		// the next header links properly, it must be removed before
-		// fetching the blocks.
-		b.headerList.Remove(b.headerList.Front())
-		bmgrLog.Infof("Received %v block headers: Fetching blocks",
-			b.headerList.Len())
-		b.progressLogger.SetLastLogTime(time.Now())
-		b.fetchHeaderBlocks()
+	// Nothing more to do for an empty headers message.
+	if numHeaders == 0 {
 		return
 	}
 
-	// This header is not a checkpoint, so request the next batch of
-	// headers starting from the latest known header and ending with the
+	// The headers did not reach the checkpoint, so request the next batch
+	// of headers starting from the latest known header and ending with the
 	// next checkpoint.
-	locator := []chainhash.Hash{*finalHash}
-	err := peer.PushGetHeadersMsg(locator, b.nextCheckpoint.Hash)
+	locator, hashStop := b.headerSync.HeadersRequest()
+	err = peer.PushGetHeadersMsg(locator, hashStop)
 	if err != nil {
 		bmgrLog.Warnf("Failed to send getheaders message to "+
 			"peer %s: %v", peer.Addr(), err)
@@ -1371,6 +1446,8 @@ func (b *blockManager) handleNotFoundMsg(nfmsg *notFoundMsg) {
 			if _, exists := state.requestedBlocks[inv.Hash]; exists {
 				delete(state.requestedBlocks, inv.Hash)
 				delete(b.requestedBlocks, inv.Hash)
+				state.download.settled(time.Now(),
+					len(state.requestedBlocks))
 			}
 		case wire.InvTypeTx:
 			if _, exists := state.requestedTxns[inv.Hash]; exists {
@@ -1468,6 +1545,7 @@ func (b *blockManager) handleInvMsg(imsg *invMsg) {
 	// Finally, attempt to detect potential stalls due to long side chains
 	// we already have and request more blocks to prevent them.
 	var requestQueue []*wire.InvVect
+	best := b.cfg.Chain.BestSnapshot()
 	for i, iv := range invVects {
 		// Ignore unsupported inventory types.
 		if iv.Type != wire.InvTypeBlock && iv.Type != wire.InvTypeTx {
@@ -1479,7 +1557,7 @@ func (b *blockManager) handleInvMsg(imsg *invMsg) {
 		peer.AddKnownInventory(iv)
 
 		// Ignore inventory when we're in headers-first mode.
-		if b.headersFirstMode {
+		if b.headerSync.Active() {
 			continue
 		}
 
@@ -1495,7 +1573,9 @@ func (b *blockManager) handleInvMsg(imsg *invMsg) {
 			if iv.Type == wire.InvTypeTx {
 				// Skip the transaction if it has already been
 				// rejected.
-				if _, exists := b.rejectedTxns[iv.Hash]; exists {
+				if b.rejectedTxns.isRejected(&iv.Hash, &best.Hash,
+					imsg.feeExempt) {
+
 					continue
 				}
 			}
@@ -1558,6 +1638,7 @@ func (b *blockManager) handleInvMsg(imsg *invMsg) {
 
 	// Request as much as possible at once.
 	numRequested := 0
+	idle := len(state.requestedBlocks) == 0
 	gdmsg := wire.NewMsgGetData()
 	for _, iv := range requestQueue {
 		switch iv.Type {
@@ -1567,6 +1648,9 @@ func (b *blockManager) handleInvMsg(imsg *invMsg) {
 			if _, exists := b.requestedBlocks[iv.Hash]; !exists {
 				limitAdd(b.requestedBlocks, iv.Hash, maxRequestedBlocks)
 				limitAdd(state.requestedBlocks, iv.Hash, maxRequestedBlocks)
+				now := time.Now()
+				state.throughput.requested(now, idle)
+				state.download.requested(now)
 				gdmsg.AddInvVect(iv)
 				numRequested++
 			}
@@ -1599,6 +1683,18 @@ func (b *blockManager) handleInvMsg(imsg *invMsg) {
 	}
 }
 
+// isPermanentTxRejection returns whether or not the provided error that caused
+// a transaction to be rejected does not depend on the state of the chain, and
+// therefore the transaction will continue to be rejected after new blocks are
+// connected.
+//
+// Note that insufficient fee errors are not permanent since they are also the
+// result of the free transaction rate limiter and tickets that pay less than
+// the next stake difficulty, both of which change over time.
+func isPermanentTxRejection(err error) bool {
+	return mempool.IsErrorCode(err, mempool.ErrDustOutput)
+}
+
 // limitAdd is a helper function for maps that require a maximum limit by
 // evicting a random value if adding the new value would cause it to
 // overflow the maximum allowed.
@@ -1625,17 +1721,33 @@ func limitAdd(m map[chainhash.Hash]struct{}, hash chainhash.Hash, limit int) {
 // important because the block manager controls which blocks are needed and how
 // the fetching should proceed.
 func (b *blockManager) blockHandler() {
+	syncCheckTicker := time.NewTicker(syncCheckInterval)
+	defer syncCheckTicker.Stop()
+
 out:
 	for {
+		// Votes and revocations are time sensitive, so handle any that are
+		// waiting in the priority queue before the next message from the
+		// general queue which is typically congested with regular
+		// transactions when the mempool is under load.
+		select {
+		case m := <-b.priorityMsgChan:
+			b.handlePriorityMsg(m)
+			continue
+		default:
+		}
+
 		select {
+		case m := <-b.priorityMsgChan:
+			b.handlePriorityMsg(m)
+
 		case m := <-b.msgChan:
 			switch msg := m.(type) {
 			case *newPeerMsg:
 				b.handleNewPeerMsg(msg.peer)
 
 			case *txMsg:
-				b.handleTxMsg(msg)
-				msg.reply <- struct{}{}
+				msg.reply <- b.handleTxMsg(msg)
 
 			case *blockMsg:
 				b.handleBlockMsg(msg)
@@ -1660,6 +1772,9 @@ out:
 				}
 				msg.reply <- peerID
 
+			case getSyncPeerInfoMsg:
+				msg.reply <- b.syncPeerInfo()
+
 			case requestFromPeerMsg:
 				err := b.requestFromPeer(msg.peer, msg.blocks, msg.txs)
 				msg.reply <- requestFromPeerResponse{
@@ -1743,20 +1858,21 @@ out:
 				}
 
 			case processTransactionMsg:
-				acceptedTxs, err := b.cfg.TxMemPool.ProcessTransaction(msg.tx,
-					msg.allowOrphans, msg.rateLimit, msg.allowHighFees, msg.tag)
-				msg.reply <- processTransactionResponse{
-					acceptedTxs: acceptedTxs,
-					err:         err,
-				}
+				b.handleProcessTransactionMsg(msg)
 
 			case isCurrentMsg:
 				msg.reply <- b.current()
 
+			case requestQuarantinedMsg:
+				b.requestQuarantinedBlocks()
+
 			default:
 				bmgrLog.Warnf("Invalid message type in block handler: %T", msg)
 			}
 
+		case <-syncCheckTicker.C:
+			b.checkDownloadStalls(time.Now())
+
 		case <-b.quit:
 			break out
 		}
@@ -1766,6 +1882,21 @@ out:
 	bmgrLog.Trace("Block handler done")
 }
 
+// handlePriorityMsg handles a message from the priority queue.  Only votes and
+// revocations are sent to the priority queue.
+func (b *blockManager) handlePriorityMsg(m interface{}) {
+	switch msg := m.(type) {
+	case *txMsg:
+		msg.reply <- b.handleTxMsg(msg)
+
+	case processTransactionMsg:
+		b.handleProcessTransactionMsg(msg)
+
+	default:
+		bmgrLog.Warnf("Invalid message type in priority queue: %T", msg)
+	}
+}
+
 // notifiedWinningTickets returns whether or not the winning tickets
 // notification for the specified block hash has already been sent.
 func (b *blockManager) notifiedWinningTickets(hash *chainhash.Hash) bool {
@@ -2038,6 +2169,19 @@ func (b *blockManager) handleBlockchainNotification(notification *blockchain.Not
 			b.cfg.BgBlkTmplGenerator.BlockConnected(block)
 		}
 
+		// Alert when the live ticket pool is nearing exhaustion.  This is
+		// only done once the chain is current to avoid alerting about the
+		// historical state of the pool while syncing.
+		if b.cfg.AlertOutbox != nil && b.cfg.Chain.IsCurrent() {
+			b.cfg.AlertOutbox.TicketPoolAlert(b.cfg.Chain.BestSnapshot())
+		}
+
+		// Notify webhooks of the block once the chain is current to avoid
+		// flooding them with historical blocks while syncing.
+		if b.cfg.Webhooks != nil && b.cfg.Chain.IsCurrent() {
+			b.cfg.Webhooks.BlockConnected(block)
+		}
+
 	// Stake tickets are spent or missed from the most recently connected block.
 	case blockchain.NTSpentAndMissedTickets:
 		tnd, ok := notification.Data.(*blockchain.TicketNotificationsData)
@@ -2119,12 +2263,21 @@ func (b *blockManager) handleBlockchainNotification(notification *blockchain.Not
 		// block (probably the same one), which was disapproved, also spending
 		// those outputs, and, in that case, anything that happens to be in the
 		// pool which depends on the transaction is still valid.
+		//
+		// The transactions that were added back are tracked so websocket
+		// clients with a matching transaction filter can be notified that
+		// they are unmined again.
+		var unminedTxns []*dcrutil.Tx
 		handleDisconnectedBlockTxns := func(txns []*dcrutil.Tx) {
 			for _, tx := range txns {
-				_, err := txMemPool.MaybeAcceptTransaction(tx, false, true)
+				missingParents, err := txMemPool.MaybeAcceptTransaction(tx,
+					false, true)
 				if err != nil && !isDoubleSpendOrDuplicateError(err) {
 					txMemPool.RemoveTransaction(tx, true)
 				}
+				if err == nil && len(missingParents) == 0 {
+					unminedTxns = append(unminedTxns, tx)
+				}
 			}
 		}
 		handleDisconnectedBlockTxns(block.Transactions()[1:])
@@ -2139,8 +2292,11 @@ func (b *blockManager) handleBlockchainNotification(notification *blockchain.Not
 			// Filter and update the rebroadcast inventory.
 			b.cfg.PruneRebroadcastInventory()
 
-			// Notify registered websocket clients.
+			// Notify registered websocket clients of the disconnected
+			// block followed by the transactions in it that are unmined
+			// again.
 			r.NotifyBlockDisconnected(block)
+			r.NotifyUnminedTransactions(unminedTxns)
 		}
 
 	// Chain reorganization has commenced.
@@ -2155,6 +2311,29 @@ func (b *blockManager) handleBlockchainNotification(notification *blockchain.Not
 			b.cfg.BgBlkTmplGenerator.ChainReorgDone()
 		}
 
+	// The stored data for a block was detected to be corrupted and the block
+	// has been quarantined.  Request a fresh copy of it from the network in
+	// order to repair it.
+	case blockchain.NTBlockCorrupted:
+		// WARNING: The chain lock might not be released before sending this
+		// notification, so request the block from the block handler
+		// goroutine to avoid calling chain functions which could result in
+		// a deadlock.
+		cd, ok := notification.Data.(*blockchain.BlockCorruptedNtfnsData)
+		if !ok {
+			bmgrLog.Warnf("Block corrupted notification is malformed")
+			break
+		}
+		if b.cfg.AlertOutbox != nil {
+			b.cfg.AlertOutbox.CorruptionAlert(cd)
+		}
+		go func() {
+			select {
+			case b.msgChan <- requestQuarantinedMsg{}:
+			case <-b.quit:
+			}
+		}()
+
 	// The blockchain is reorganizing.
 	case blockchain.NTReorganization:
 		rd, ok := notification.Data.(*blockchain.ReorganizationNtfnsData)
@@ -2167,6 +2346,11 @@ func (b *blockManager) handleBlockchainNotification(notification *blockchain.Not
 		if r := b.cfg.RpcServer(); r != nil {
 			r.NotifyReorganization(rd)
 		}
+
+		// Alert when the reorganization is deep.
+		if b.cfg.AlertOutbox != nil {
+			b.cfg.AlertOutbox.ReorgAlert(rd)
+		}
 	}
 }
 
@@ -2180,15 +2364,30 @@ func (b *blockManager) NewPeer(peer *peerpkg.Peer) {
 }
 
 // QueueTx adds the passed transaction message and peer to the block handling
-// queue.
-func (b *blockManager) QueueTx(tx *dcrutil.Tx, peer *peerpkg.Peer, done chan struct{}) {
+// queue.  The feeExempt flag indicates the peer is trusted to relay
+// transactions that do not pay the minimum relay fee.
+func (b *blockManager) QueueTx(tx *dcrutil.Tx, peer *peerpkg.Peer, feeExempt bool, done chan txAcceptOutcome) {
 	// Don't accept more transactions if we're shutting down.
 	if atomic.LoadInt32(&b.shutdown) != 0 {
-		done <- struct{}{}
+		done <- txAcceptOutcome{}
 		return
 	}
 
-	b.msgChan <- &txMsg{tx: tx, peer: peer, reply: done}
+	// Votes and revocations are sent to the priority queue so they are not
+	// delayed behind regular transactions.
+	msg := &txMsg{
+		tx:        tx,
+		peer:      peer,
+		reply:     done,
+		received:  time.Now(),
+		priority:  isPriorityRelayTx(tx.MsgTx()),
+		feeExempt: feeExempt,
+	}
+	if msg.priority {
+		b.priorityMsgChan <- msg
+		return
+	}
+	b.msgChan <- msg
 }
 
 // QueueBlock adds the passed block message and peer to the block handling queue.
@@ -2203,14 +2402,16 @@ func (b *blockManager) QueueBlock(block *dcrutil.Block, peer *peerpkg.Peer, done
 }
 
 // QueueInv adds the passed inv message and peer to the block handling queue.
-func (b *blockManager) QueueInv(inv *wire.MsgInv, peer *peerpkg.Peer) {
+// The feeExempt flag indicates the peer is trusted to relay transactions that
+// do not pay the minimum relay fee.
+func (b *blockManager) QueueInv(inv *wire.MsgInv, peer *peerpkg.Peer, feeExempt bool) {
 	// No channel handling here because peers do not need to block on inv
 	// messages.
 	if atomic.LoadInt32(&b.shutdown) != 0 {
 		return
 	}
 
-	b.msgChan <- &invMsg{inv: inv, peer: peer}
+	b.msgChan <- &invMsg{inv: inv, peer: peer, feeExempt: feeExempt}
 }
 
 // QueueHeaders adds the passed headers message and peer to the block handling
@@ -2281,6 +2482,16 @@ func (b *blockManager) SyncPeerID() int32 {
 	return <-reply
 }
 
+// SyncPeerInfo returns details about the current sync peer along with the most
+// recent changes to it.
+//
+// This function is safe for concurrent access.
+func (b *blockManager) SyncPeerInfo() *rpcserver.SyncPeerInfo {
+	reply := make(chan *rpcserver.SyncPeerInfo)
+	b.msgChan <- getSyncPeerInfoMsg{reply: reply}
+	return <-reply
+}
+
 // RequestFromPeer allows an outside caller to request blocks or transactions
 // from a peer. The requests are logged in the blockmanager's internal map of
 // requests so they do not later ban the peer for sending the respective data.
@@ -2300,6 +2511,7 @@ func (b *blockManager) requestFromPeer(p *peerpkg.Peer, blocks, txs []*chainhash
 	if !exists {
 		return fmt.Errorf("unknown peer %s", p)
 	}
+	idle := len(state.requestedBlocks) == 0
 
 	// Add the blocks to the request.
 	for _, bh := range blocks {
@@ -2325,6 +2537,9 @@ func (b *blockManager) requestFromPeer(p *peerpkg.Peer, blocks, txs []*chainhash
 
 		state.requestedBlocks[*bh] = struct{}{}
 		b.requestedBlocks[*bh] = struct{}{}
+		now := time.Now()
+		state.throughput.requested(now, idle)
+		state.download.requested(now)
 	}
 
 	// Add the vote transactions to the request.
@@ -2418,16 +2633,51 @@ func (b *blockManager) ProcessBlock(block *dcrutil.Block, flags blockchain.Behav
 
 // ProcessTransaction makes use of ProcessTransaction on an internal instance of
 // a block chain.  It is funneled through the block manager since blockchain is
-// not safe for concurrent access.
+// not safe for concurrent access.  The feeExempt flag exempts the transaction
+// from the minimum relay fee policy.
 func (b *blockManager) ProcessTransaction(tx *dcrutil.Tx, allowOrphans bool,
-	rateLimit bool, allowHighFees bool, tag mempool.Tag) ([]*dcrutil.Tx, error) {
+	rateLimit bool, allowHighFees bool, feeExempt bool,
+	tag mempool.Tag) ([]*dcrutil.Tx, error) {
+
 	reply := make(chan processTransactionResponse, 1)
-	b.msgChan <- processTransactionMsg{tx, allowOrphans, rateLimit,
-		allowHighFees, tag, reply}
+	msg := processTransactionMsg{
+		tx:            tx,
+		allowOrphans:  allowOrphans,
+		rateLimit:     rateLimit,
+		allowHighFees: allowHighFees,
+		feeExempt:     feeExempt,
+		tag:           tag,
+		reply:         reply,
+		received:      time.Now(),
+		priority:      isPriorityRelayTx(tx.MsgTx()),
+	}
+	if msg.priority {
+		b.priorityMsgChan <- msg
+	} else {
+		b.msgChan <- msg
+	}
 	response := <-reply
 	return response.acceptedTxs, response.err
 }
 
+// TxRelayStats returns statistics about the latency of the transactions
+// processed via the priority queue used by votes and revocations and the
+// general queue used by all other transactions.
+//
+// This function is safe for concurrent access.
+func (b *blockManager) TxRelayStats() (priority, regular rpcserver.TxRelayLaneStats) {
+	return b.txRelayMetrics.Stats()
+}
+
+// RejectedTransactions returns the transactions most recently rejected from
+// peers along with the reasons they were rejected ordered from newest to
+// oldest.
+//
+// This function is safe for concurrent access.
+func (b *blockManager) RejectedTransactions() []rpcserver.RejectedTx {
+	return b.rejectedTxns.Entries()
+}
+
 // IsCurrent returns whether or not the block manager believes it is synced with
 // the connected peers.
 func (b *blockManager) IsCurrent() bool {
@@ -2447,28 +2697,32 @@ func (b *blockManager) TicketPoolValue() (dcrutil.Amount, error) {
 func newBlockManager(config *blockManagerConfig) (*blockManager, error) {
 	bm := blockManager{
 		cfg:             config,
-		rejectedTxns:    make(map[chainhash.Hash]struct{}),
+		rejectedTxns:    newRejectedTxCache(maxRejectedTxns),
 		requestedTxns:   make(map[chainhash.Hash]struct{}),
 		requestedBlocks: make(map[chainhash.Hash]struct{}),
 		peerStates:      make(map[*peerpkg.Peer]*peerSyncState),
 		progressLogger:  newBlockProgressLogger("Processed", bmgrLog),
 		msgChan:         make(chan interface{}, cfg.MaxPeers*3),
-		headerList:      list.New(),
+		priorityMsgChan: make(chan interface{}, cfg.MaxPeers*3),
 		quit:            make(chan struct{}),
 		orphans:         make(map[chainhash.Hash]*orphanBlock),
 		prevOrphans:     make(map[chainhash.Hash][]*orphanBlock),
 	}
 
+	// Initialize the headers-first state based on the current tip.
+	// Headers-first mode is only used to download the blocks up to the
+	// final checkpoint since the blocks after it are fully validated.
 	best := bm.cfg.Chain.BestSnapshot()
+	var checkpoints []chaincfg.Checkpoint
 	if !cfg.DisableCheckpoints {
-		// Initialize the next checkpoint based on the current height.
-		bm.nextCheckpoint = bm.findNextHeaderCheckpoint(best.Height)
-		if bm.nextCheckpoint != nil {
-			bm.resetHeaderState(&best.Hash, best.Height)
-		}
+		checkpoints = bm.cfg.Chain.Checkpoints()
 	} else {
 		bmgrLog.Info("Checkpoints are disabled")
 	}
+	bm.headerSync = headersync.New(&headersync.Config{
+		Checkpoints: checkpoints,
+		PowLimit:    bm.cfg.ChainParams.PowLimit,
+	}, &best.Hash, best.Height, nil)
 
 	// Dump the blockchain here if asked for it, and quit.
 	if cfg.DumpBlockchain != "" {
@@ -0,0 +1,184 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/decred/dcrd/lru"
+)
+
+const (
+	// ancientBlockDepth is the number of blocks below the current best
+	// block a requested block must be before it is considered ancient.
+	// Recent blocks are typically still cached by the chain and are needed
+	// by peers to stay in sync, so they are never throttled, while ancient
+	// blocks must be loaded from disk and are typically only requested by
+	// syncing peers and crawlers.
+	ancientBlockDepth = 288
+
+	// servedBlockCacheSize is the number of recently served ancient blocks
+	// that are kept in memory so that multiple peers requesting the same
+	// blocks, such as those syncing at the same time, do not result in
+	// repeatedly loading them from disk.
+	servedBlockCacheSize = 32
+
+	// maxConcurrentAncientLoads is the maximum number of ancient blocks that
+	// are loaded from disk concurrently on behalf of peers.  Requests for
+	// additional ancient blocks are queued until a load completes so that
+	// peers can't collectively saturate disk I/O.
+	maxConcurrentAncientLoads = 2
+
+	// ancientBlockBurstDuration is the amount of time worth of the ancient
+	// block rate of a peer that it may request in quick succession before
+	// the rate is enforced.
+	ancientBlockBurstDuration = 5 * time.Second
+)
+
+// errAncientBlockPeerQuit is returned when a peer disconnects while an ancient
+// block it requested is queued or throttled.
+var errAncientBlockPeerQuit = errors.New("peer disconnected while serving " +
+	"ancient block")
+
+// errBlocksNotServed is returned when a peer requests a full block that is
+// withheld from it due to the server being configured to not serve full blocks.
+var errBlocksNotServed = errors.New("full blocks are not served")
+
+// ancientBlockServer provides a cache of recently served ancient blocks and
+// limits the number of ancient blocks that are loaded from disk concurrently on
+// behalf of peers.
+//
+// It is safe for concurrent access.
+type ancientBlockServer struct {
+	// cache houses the recently served ancient blocks keyed by their hash.
+	cache lru.KVCache
+
+	// loadSem is a semaphore that limits the number of concurrent loads.
+	loadSem chan struct{}
+}
+
+// newAncientBlockServer returns a new ancient block server that caches up to
+// the provided number of blocks and limits the number of concurrent loads to
+// the provided maximum.
+func newAncientBlockServer(cacheSize uint, maxLoads int) *ancientBlockServer {
+	return &ancientBlockServer{
+		cache:   lru.NewKVCache(cacheSize),
+		loadSem: make(chan struct{}, maxLoads),
+	}
+}
+
+// fetch returns the ancient block with the provided hash from the cache when it
+// is available or loads it via the provided function and adds it to the cache
+// otherwise.  Loads are queued once the maximum number of concurrent loads is
+// reached.  errAncientBlockPeerQuit is returned when the provided quit channel
+// is closed while the load is queued.
+func (s *ancientBlockServer) fetch(hash *chainhash.Hash, load func(*chainhash.Hash) (*dcrutil.Block, error), quit <-chan struct{}) (*dcrutil.Block, error) {
+	if block, ok := s.cache.Lookup(*hash); ok {
+		return block.(*dcrutil.Block), nil
+	}
+
+	select {
+	case s.loadSem <- struct{}{}:
+	case <-quit:
+		return nil, errAncientBlockPeerQuit
+	}
+	defer func() { <-s.loadSem }()
+
+	// Another peer might have loaded the block while this one was queued.
+	if block, ok := s.cache.Lookup(*hash); ok {
+		return block.(*dcrutil.Block), nil
+	}
+	block, err := load(hash)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Add(*hash, block)
+	return block, nil
+}
+
+// ancientBlockThrottle limits the rate, in bytes per second, at which ancient
+// blocks are served to a peer.  It is a token bucket that permits the bucket
+// to go into debt so that blocks larger than the burst are still served and the
+// peer simply waits longer for the next one.
+//
+// It is not safe for concurrent access and is only used from the input handler
+// of the peer.
+type ancientBlockThrottle struct {
+	tokens     float64
+	lastUpdate time.Time
+}
+
+// reserve charges the provided number of bytes served at the provided time
+// against the bucket given the provided rate in bytes per second and returns
+// how long to wait before serving them in order to remain within the rate.
+func (t *ancientBlockThrottle) reserve(size int, rate float64, now time.Time) time.Duration {
+	burst := rate * ancientBlockBurstDuration.Seconds()
+	if t.lastUpdate.IsZero() {
+		t.tokens = burst
+	} else if elapsed := now.Sub(t.lastUpdate); elapsed > 0 {
+		t.tokens += elapsed.Seconds() * rate
+		if t.tokens > burst {
+			t.tokens = burst
+		}
+	}
+	t.lastUpdate = now
+
+	t.tokens -= float64(size)
+	if t.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-t.tokens / rate * float64(time.Second))
+}
+
+// fetchServedBlock returns the block with the provided hash that was requested
+// by the provided peer.  Ancient blocks are served from the cache of recently
+// served ancient blocks when possible and their loads are queued so that only a
+// limited number are loaded concurrently.  Serving ancient blocks to peers that
+// are not whitelisted is further throttled to the configured per-peer rate by
+// blocking until the peer is within its rate.
+//
+// Full blocks are not served to peers they are withheld from, which are those
+// that are not whitelisted when the server is configured to not serve them.
+//
+// This function MUST only be called from the input handler of the peer.
+func (s *server) fetchServedBlock(sp *serverPeer, hash *chainhash.Hash) (*dcrutil.Block, error) {
+	if sp.blocksWithheld {
+		return nil, errBlocksNotServed
+	}
+
+	header, err := s.chain.HeaderByHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	best := s.chain.BestSnapshot()
+	if best.Height-int64(header.Height) <= ancientBlockDepth {
+		return s.chain.BlockByHash(hash)
+	}
+
+	block, err := s.ancientBlocks.fetch(hash, s.chain.BlockByHash, sp.quit)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.AncientBlockRate == 0 || sp.isWhitelisted {
+		return block, nil
+	}
+
+	rate := float64(cfg.AncientBlockRate) * 1024
+	size := block.MsgBlock().SerializeSize()
+	delay := sp.ancientThrottle.reserve(size, rate, time.Now())
+	if delay > 0 {
+		peerLog.Tracef("Throttling ancient block %v to %s for %v", hash, sp,
+			delay)
+		select {
+		case <-time.After(delay):
+		case <-sp.quit:
+			return nil, errAncientBlockPeerQuit
+		}
+	}
+	return block, nil
+}
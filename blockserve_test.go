@@ -0,0 +1,107 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/decred/dcrd/wire"
+)
+
+// TestAncientBlockThrottle ensures the ancient block throttle allows bursts up
+// to the burst duration worth of the rate and then delays serving blocks such
+// that the rate is not exceeded.
+func TestAncientBlockThrottle(t *testing.T) {
+	t.Parallel()
+
+	const rate = 1000
+	now := time.Unix(1592918788, 0)
+	var throttle ancientBlockThrottle
+
+	// Ensure no delay is imposed within the burst.
+	burst := int(rate * ancientBlockBurstDuration.Seconds())
+	if delay := throttle.reserve(burst, rate, now); delay != 0 {
+		t.Fatalf("unexpected delay within burst -- got %v, want 0", delay)
+	}
+
+	// Ensure exceeding the burst results in a delay proportional to the
+	// deficit and that blocks larger than the burst are still served.
+	if delay := throttle.reserve(500, rate, now); delay != 500*time.Millisecond {
+		t.Fatalf("unexpected delay -- got %v, want 500ms", delay)
+	}
+	delay := throttle.reserve(2*burst, rate, now)
+	want := time.Duration(2*burst+500) * time.Millisecond
+	if delay != want {
+		t.Fatalf("unexpected delay -- got %v, want %v", delay, want)
+	}
+
+	// Ensure the bucket refills over time up to the burst.
+	now = now.Add(time.Hour)
+	if delay := throttle.reserve(burst, rate, now); delay != 0 {
+		t.Fatalf("unexpected delay after refill -- got %v, want 0", delay)
+	}
+	if delay := throttle.reserve(1, rate, now); delay != time.Millisecond {
+		t.Fatalf("unexpected delay after burst -- got %v, want 1ms", delay)
+	}
+}
+
+// TestAncientBlockServer ensures the ancient block server caches loaded blocks,
+// does not cache failed loads, and returns errAncientBlockPeerQuit when a peer
+// disconnects while its load is queued.
+func TestAncientBlockServer(t *testing.T) {
+	t.Parallel()
+
+	var numLoads int
+	block := dcrutil.NewBlock(&wire.MsgBlock{})
+	hash := block.Hash()
+	errLoad := errors.New("load failed")
+	load := func(h *chainhash.Hash) (*dcrutil.Block, error) {
+		numLoads++
+		if *h != *hash {
+			return nil, errLoad
+		}
+		return block, nil
+	}
+
+	// Ensure the block is only loaded once when fetched repeatedly.
+	s := newAncientBlockServer(1, 1)
+	quit := make(chan struct{})
+	for i := 0; i < 2; i++ {
+		got, err := s.fetch(hash, load, quit)
+		if err != nil {
+			t.Fatalf("unexpected error fetching block: %v", err)
+		}
+		if got != block {
+			t.Fatal("fetched block does not match loaded block")
+		}
+	}
+	if numLoads != 1 {
+		t.Fatalf("unexpected number of loads -- got %d, want 1", numLoads)
+	}
+
+	// Ensure failed loads are not cached.
+	var unknownHash chainhash.Hash
+	for i := 0; i < 2; i++ {
+		if _, err := s.fetch(&unknownHash, load, quit); err != errLoad {
+			t.Fatalf("unexpected error -- got %v, want %v", err, errLoad)
+		}
+	}
+	if numLoads != 3 {
+		t.Fatalf("unexpected number of loads -- got %d, want 3", numLoads)
+	}
+
+	// Ensure a queued load is abandoned when the peer disconnects.
+	s.loadSem <- struct{}{}
+	close(quit)
+	_, err := s.fetch(&unknownHash, load, quit)
+	if !errors.Is(err, errAncientBlockPeerQuit) {
+		t.Fatalf("unexpected error -- got %v, want %v", err,
+			errAncientBlockPeerQuit)
+	}
+}
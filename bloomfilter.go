@@ -0,0 +1,231 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/decred/dcrd/internal/bloom"
+	"github.com/decred/dcrd/peer/v2"
+	"github.com/decred/dcrd/wire"
+)
+
+const (
+	// maxBloomFalsePositiveRate is the maximum estimated false positive rate
+	// a bloom filter loaded by a peer may have.  Filters that match a large
+	// proportion of all data provide little benefit to the client over
+	// requesting full blocks while forcing the server to match every
+	// transaction against them, so peers that load them are disconnected.
+	maxBloomFalsePositiveRate = 0.1
+
+	// bloomBudgetHalfLife is the time it takes the usage of the bloom filter
+	// update budget of a peer to decay to half of its value.
+	bloomBudgetHalfLife = time.Minute
+
+	// bloomBudgetUpdates is the number of filterload and filteradd messages
+	// a peer may send before exceeding its bloom filter update budget.
+	bloomBudgetUpdates = 100
+
+	// bloomBudgetPenalty is the transient ban score a peer incurs for every
+	// filterload and filteradd message it sends while over its bloom filter
+	// update budget.  The message is otherwise ignored.
+	bloomBudgetPenalty = 10
+)
+
+// bloomFilterBudget tracks how much of its bloom filter update budget a peer
+// has used.  The usage decays exponentially over time so that peers are only
+// penalized for repeatedly updating their filter as opposed to occasional
+// bursts such as when a wallet loads and then extends its filter.
+//
+// It is not safe for concurrent access and is only used from the input handler
+// of the peer.
+type bloomFilterBudget struct {
+	lastDecay time.Time
+	usage     float64
+}
+
+// record records a filter update made by the peer at the provided time and
+// returns whether or not the peer is still within its budget.
+func (b *bloomFilterBudget) record(now time.Time) bool {
+	if !b.lastDecay.IsZero() {
+		if elapsed := now.Sub(b.lastDecay); elapsed > 0 {
+			b.usage *= math.Exp2(-elapsed.Seconds() /
+				bloomBudgetHalfLife.Seconds())
+		}
+	}
+	b.lastDecay = now
+
+	b.usage++
+	return b.usage <= bloomBudgetUpdates
+}
+
+// enforceNodeBloomFlag disconnects the peer and returns false when the server
+// does not serve bloom filters since the peer sent a bloom filter message
+// after it was already informed the service is not supported.
+func (sp *serverPeer) enforceNodeBloomFlag(cmd string) bool {
+	if sp.server.services&wire.SFNodeBloom == wire.SFNodeBloom {
+		return true
+	}
+
+	peerLog.Debugf("%s sent an unsupported %s request -- banning and "+
+		"disconnecting", sp, cmd)
+	sp.server.BanPeer(sp)
+	sp.Disconnect()
+	return false
+}
+
+// recordBloomFilterUpdate charges a filter update against the bloom filter
+// update budget of the peer and returns whether or not the update should be
+// applied.  Peers that are over their budget incur a transient ban score
+// penalty.
+func (sp *serverPeer) recordBloomFilterUpdate(cmd string) bool {
+	if sp.bloomBudget.record(time.Now()) || sp.isWhitelisted {
+		return true
+	}
+
+	sp.addBanScore(0, bloomBudgetPenalty, "exceeded bloom filter update "+
+		"budget with "+cmd)
+	return false
+}
+
+// OnFilterLoad is invoked when a peer receives a filterload wire message.  It
+// replaces the bloom filter of the peer, if any, with the provided one and
+// enables relaying transactions to the peer that match it.
+func (sp *serverPeer) OnFilterLoad(_ *peer.Peer, msg *wire.MsgFilterLoad) {
+	if !sp.enforceNodeBloomFlag(msg.Command()) ||
+		!sp.recordBloomFilterUpdate(msg.Command()) {
+
+		return
+	}
+
+	// Disconnect peers that load filters which match too much data.
+	filter := bloom.LoadFilter(msg)
+	if rate := filter.FalsePositiveRate(); rate > maxBloomFalsePositiveRate {
+		peerLog.Debugf("%s loaded a bloom filter with a false positive "+
+			"rate of %.4f, which exceeds the maximum of %.4f -- "+
+			"disconnecting", sp, rate, maxBloomFalsePositiveRate)
+		sp.Disconnect()
+		return
+	}
+
+	sp.setDisableRelayTx(false)
+	sp.filter.Reload(msg)
+}
+
+// OnFilterAdd is invoked when a peer receives a filteradd wire message.  It
+// adds the provided data to the loaded bloom filter of the peer.
+func (sp *serverPeer) OnFilterAdd(_ *peer.Peer, msg *wire.MsgFilterAdd) {
+	if !sp.enforceNodeBloomFlag(msg.Command()) {
+		return
+	}
+
+	// Disconnect peers that add to a filter without loading one.
+	if !sp.filter.IsLoaded() {
+		peerLog.Debugf("%s sent a filteradd request with no filter "+
+			"loaded -- disconnecting", sp)
+		sp.Disconnect()
+		return
+	}
+
+	if !sp.recordBloomFilterUpdate(msg.Command()) {
+		return
+	}
+
+	// Disconnect peers once their filter matches too much data.
+	sp.filter.Add(msg.Data)
+	if rate := sp.filter.FalsePositiveRate(); rate > maxBloomFalsePositiveRate {
+		peerLog.Debugf("%s bloom filter reached a false positive rate of "+
+			"%.4f, which exceeds the maximum of %.4f -- disconnecting", sp,
+			rate, maxBloomFalsePositiveRate)
+		sp.Disconnect()
+	}
+}
+
+// OnFilterClear is invoked when a peer receives a filterclear wire message.
+// It unloads the bloom filter of the peer so that all transactions are relayed
+// to it again.
+func (sp *serverPeer) OnFilterClear(_ *peer.Peer, msg *wire.MsgFilterClear) {
+	if !sp.enforceNodeBloomFlag(msg.Command()) {
+		return
+	}
+
+	// Disconnect peers that clear a filter without loading one.
+	if !sp.filter.IsLoaded() {
+		peerLog.Debugf("%s sent a filterclear request with no filter "+
+			"loaded -- disconnecting", sp)
+		sp.Disconnect()
+		return
+	}
+
+	sp.filter.Unload()
+}
+
+// pushMerkleBlockMsg sends a merkleblock message for the provided block hash
+// to the connected peer, followed by the transactions of the block that match
+// the bloom filter of the peer.  Nothing is sent when the peer does not have
+// a filter loaded.  An error is returned if the block hash is not known.
+func (s *server) pushMerkleBlockMsg(sp *serverPeer, hash *chainhash.Hash, doneChan chan<- struct{}, waitChan <-chan struct{}) error {
+	// Do not send a response if the peer doesn't have a filter loaded.
+	if !sp.filter.IsLoaded() {
+		if doneChan != nil {
+			doneChan <- struct{}{}
+		}
+		return nil
+	}
+
+	block, err := s.fetchServedBlock(sp, hash)
+	if err != nil {
+		peerLog.Tracef("Unable to fetch requested block hash %v: %v",
+			hash, err)
+
+		if doneChan != nil {
+			doneChan <- struct{}{}
+		}
+		return err
+	}
+
+	// Generate a merkle block by filtering the requested block according
+	// to the filter for the peer.
+	merkle, matchedTxns := bloom.NewMerkleBlock(block, sp.filter)
+
+	// Once we have fetched data wait for any previous operation to finish.
+	if waitChan != nil {
+		<-waitChan
+	}
+
+	// Send the merkleblock.  Only send the done channel with this message
+	// if no transactions will be sent afterwards.
+	var dc chan<- struct{}
+	if len(matchedTxns) == 0 {
+		dc = doneChan
+	}
+	sp.QueueMessage(merkle, dc)
+
+	// Finally, send any matched transactions.
+	for i, tx := range matchedTxns {
+		// Only send the done channel on the final transaction.
+		var dc chan<- struct{}
+		if i == len(matchedTxns)-1 {
+			dc = doneChan
+		}
+		sp.QueueMessage(tx.MsgTx(), dc)
+	}
+
+	return nil
+}
+
+// bloomFilterAllowsTx returns whether or not the provided transaction should be
+// relayed to the peer given its bloom filter.  All transactions are allowed
+// when the peer does not have a filter loaded.  The filter is updated with the
+// transaction when it matches according to its bloom update flags.
+func (sp *serverPeer) bloomFilterAllowsTx(tx *dcrutil.Tx) bool {
+	if !sp.filter.IsLoaded() {
+		return true
+	}
+	return sp.filter.MatchTxAndUpdate(tx)
+}
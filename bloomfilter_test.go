@@ -0,0 +1,44 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// TestBloomFilterBudget ensures peers are only considered over their bloom
+// filter update budget once they exceed it and that usage decays over time.
+func TestBloomFilterBudget(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1592918788, 0)
+
+	// Ensure updates within the budget are allowed.
+	var budget bloomFilterBudget
+	for i := 0; i < bloomBudgetUpdates; i++ {
+		if !budget.record(now) {
+			t.Fatalf("update %d within budget was not allowed", i)
+		}
+	}
+
+	// Ensure updates over the budget are not allowed.
+	if budget.record(now) {
+		t.Fatal("update over budget was allowed")
+	}
+
+	// Ensure usage decays to a quarter after two half lives, so the peer is
+	// within the budget again for another 74 updates (101 / 4 + 74 = 99.25),
+	// and the next update exceeds the budget again.
+	now = now.Add(2 * bloomBudgetHalfLife)
+	for i := 0; i < 74; i++ {
+		if !budget.record(now) {
+			t.Fatalf("update %d after decay was not allowed", i)
+		}
+	}
+	if budget.record(now) {
+		t.Fatal("update over budget after decay was allowed")
+	}
+}
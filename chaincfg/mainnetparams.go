@@ -141,6 +141,13 @@ func MainNetParams() *Params {
 		// Height: 395000
 		MinKnownChainWork: hexToBigInt("0000000000000000000000000000000000000000000ae01920a7ee4b769cc620"),
 
+		// AssumeValid is the hash of a block that has been externally verified
+		// to be valid.  This is intended to be updated periodically with new
+		// releases.
+		//
+		// Height: 395000
+		AssumeValid: *newHashFromStr("00000000000000001c654e2935e7722ddae8277da482e31557ac5c70ec101792"),
+
 		// The miner confirmation window is defined as:
 		//   target proof of work timespan / target proof of work spacing
 		RuleChangeActivationQuorum:     4032, // 10 % of RuleChangeActivationInterval * TicketsPerBlock
@@ -7,6 +7,7 @@ package chaincfg
 
 import (
 	"encoding/hex"
+	"errors"
 	"math/big"
 	"time"
 
@@ -317,6 +318,13 @@ type Params struct {
 	// with new releases.  It may be nil for networks that do not require it.
 	MinKnownChainWork *big.Int
 
+	// AssumeValid is the hash of a block that has been externally verified to
+	// be valid.  It allows several expensive validation checks, such as
+	// script validation, to be skipped for its ancestors during the initial
+	// chain sync.  This is intended to be updated periodically with new
+	// releases.  It may be the zero hash for networks that do not require it.
+	AssumeValid chainhash.Hash
+
 	// These fields are related to voting on consensus rule changes as
 	// defined by BIP0009.
 	//
@@ -592,6 +600,62 @@ func (p *Params) TicketExpiryBlocks() uint32 {
 	return p.TicketExpiry
 }
 
+// PowLimitValue returns the highest allowed proof of work value for a block as
+// a uint256.
+func (p *Params) PowLimitValue() *big.Int {
+	return p.PowLimit
+}
+
+// PowLimitCompact returns the highest allowed proof of work value for a block in
+// compact form.
+func (p *Params) PowLimitCompact() uint32 {
+	return p.PowLimitBits
+}
+
+// WorkDiffWindowBlocks returns the number of blocks in each of the windows used
+// when calculating the required proof of work difficulty.
+func (p *Params) WorkDiffWindowBlocks() int64 {
+	return p.WorkDiffWindowSize
+}
+
+// NumWorkDiffWindows returns the number of windows examined when calculating
+// the required proof of work difficulty.
+func (p *Params) NumWorkDiffWindows() int64 {
+	return p.WorkDiffWindows
+}
+
+// WorkDiffAlphaShift returns the exponential weighting value used when
+// calculating the required proof of work difficulty.
+func (p *Params) WorkDiffAlphaShift() int64 {
+	return p.WorkDiffAlpha
+}
+
+// WorkDiffTargetTimespan returns the desired amount of time that should elapse
+// for each window used when calculating the required proof of work difficulty.
+func (p *Params) WorkDiffTargetTimespan() time.Duration {
+	return p.TargetTimespan
+}
+
+// RetargetAdjustmentFactorValue returns the adjustment factor used to limit the
+// minimum and maximum amount of adjustment that can occur between difficulty
+// retargets.
+func (p *Params) RetargetAdjustmentFactorValue() int64 {
+	return p.RetargetAdjustmentFactor
+}
+
+// ReduceMinDifficultyEnabled returns whether the network should reduce the
+// minimum required difficulty after a long enough period of time has passed
+// without finding a block.
+func (p *Params) ReduceMinDifficultyEnabled() bool {
+	return p.ReduceMinDifficulty
+}
+
+// MinDiffReductionTimeValue returns the amount of time after which the minimum
+// required difficulty should be reduced when a block hasn't been found.
+func (p *Params) MinDiffReductionTimeValue() time.Duration {
+	return p.MinDiffReductionTime
+}
+
 // newHashFromStr converts the passed big-endian hex string into a
 // chainhash.Hash.  It only differs from the one available in chainhash in that
 // it panics on an error since it will only (and must only) be called with
@@ -669,3 +733,34 @@ func (p *Params) LatestCheckpointHeight() int64 {
 func (p *Params) Seeders() []string {
 	return p.seeders
 }
+
+// ErrMaturityOverride indicates an attempt to override the coinbase or ticket
+// maturity of a network that does not permit it.
+var ErrMaturityOverride = errors.New("maturity overrides are only permitted " +
+	"on the regression and simulation test networks")
+
+// WithMaturity returns a copy of the parameters with the provided coinbase and
+// ticket maturities in order to allow funds to become spendable more quickly
+// in test environments.  A maturity of zero retains the existing value.
+//
+// The parameters themselves are never modified and the maturities may only be
+// overridden for the regression and simulation test networks, so the values of
+// the main and test networks can't be changed.  ErrMaturityOverride is
+// returned for all other networks.
+//
+// Note that the heights that are derived from the maturities, such as the
+// stake enabled height, are not modified.
+func (p *Params) WithMaturity(coinbaseMaturity, ticketMaturity uint16) (*Params, error) {
+	if p.Net != wire.RegNet && p.Net != wire.SimNet {
+		return nil, ErrMaturityOverride
+	}
+
+	params := *p
+	if coinbaseMaturity != 0 {
+		params.CoinbaseMaturity = coinbaseMaturity
+	}
+	if ticketMaturity != 0 {
+		params.TicketMaturity = ticketMaturity
+	}
+	return &params, nil
+}
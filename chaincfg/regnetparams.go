@@ -118,6 +118,12 @@ func RegNetParams() *Params {
 		// Not set for regression test network since its chain is dynamic.
 		MinKnownChainWork: nil,
 
+		// AssumeValid is the hash of a block that has been externally verified
+		// to be valid.
+		//
+		// Not set for regression test network since its chain is dynamic.
+		AssumeValid: chainhash.Hash{},
+
 		// Consensus rule change deployments.
 		//
 		// The miner confirmation window is defined as:
@@ -52,3 +52,55 @@ func TestRegNetGenesisBlock(t *testing.T) {
 			spew.Sdump(params.GenesisHash))
 	}
 }
+
+// TestWithMaturity ensures the coinbase and ticket maturities may only be
+// overridden for the regression and simulation test networks and that the
+// original parameters are not modified.
+func TestWithMaturity(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  *Params
+		wantErr error
+	}{
+		{name: "mainnet", params: MainNetParams(), wantErr: ErrMaturityOverride},
+		{name: "testnet", params: TestNet3Params(), wantErr: ErrMaturityOverride},
+		{name: "simnet", params: SimNetParams()},
+		{name: "regnet", params: RegNetParams()},
+	}
+
+	for _, test := range tests {
+		origCoinbase := test.params.CoinbaseMaturity
+		origTicket := test.params.TicketMaturity
+		params, err := test.params.WithMaturity(2, 3)
+		if err != test.wantErr {
+			t.Errorf("%s: unexpected error -- got %v, want %v", test.name,
+				err, test.wantErr)
+			continue
+		}
+		if test.params.CoinbaseMaturity != origCoinbase ||
+			test.params.TicketMaturity != origTicket {
+
+			t.Errorf("%s: original params were modified", test.name)
+		}
+		if err != nil {
+			continue
+		}
+		if params.CoinbaseMaturity != 2 || params.TicketMaturity != 3 {
+			t.Errorf("%s: unexpected maturities -- got %d and %d, want 2 "+
+				"and 3", test.name, params.CoinbaseMaturity,
+				params.TicketMaturity)
+		}
+
+		// Ensure a maturity of zero retains the existing value.
+		params, err = test.params.WithMaturity(0, 5)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if params.CoinbaseMaturity != origCoinbase || params.TicketMaturity != 5 {
+			t.Errorf("%s: unexpected maturities -- got %d and %d, want %d "+
+				"and 5", test.name, params.CoinbaseMaturity,
+				params.TicketMaturity, origCoinbase)
+		}
+	}
+}
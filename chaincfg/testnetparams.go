@@ -118,6 +118,13 @@ func TestNet3Params() *Params {
 		// Height: 301000
 		MinKnownChainWork: hexToBigInt("0000000000000000000000000000000000000000000000005df2701ec6263182"),
 
+		// AssumeValid is the hash of a block that has been externally verified
+		// to be valid.  This is intended to be updated periodically with new
+		// releases.
+		//
+		// Height: 301000
+		AssumeValid: *newHashFromStr("0000004ce20783706c005901e44b984d8d0f9d62855f266f064a25f8131f84e4"),
+
 		// Consensus rule change deployments.
 		//
 		// The miner confirmation window is defined as:
@@ -10,9 +10,12 @@ import (
 	"crypto/elliptic"
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"math"
 	"net"
+	"net/url"
 	"os"
 	"os/user"
 	"path/filepath"
@@ -23,14 +26,20 @@ import (
 	"strings"
 	"time"
 
+	"github.com/decred/dcrd/blockchain/stake/v3"
+	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/connmgr/v3"
 	"github.com/decred/dcrd/database/v2"
 	_ "github.com/decred/dcrd/database/v2/ffldb"
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
 	"github.com/decred/dcrd/dcrutil/v3"
 	"github.com/decred/dcrd/internal/mempool"
+	"github.com/decred/dcrd/internal/mining"
 	"github.com/decred/dcrd/internal/version"
+	"github.com/decred/dcrd/peer/v2"
 	"github.com/decred/dcrd/rpc/jsonrpc/types/v2"
 	"github.com/decred/dcrd/sampleconfig"
+	"github.com/decred/dcrd/wire"
 	"github.com/decred/go-socks/socks"
 	"github.com/decred/slog"
 	flags "github.com/jessevdk/go-flags"
@@ -45,6 +54,8 @@ const (
 	defaultDbType          = "ffldb"
 	defaultLogLevel        = "info"
 	defaultSigCacheMaxSize = 100000
+	defaultDiskSpaceWarn   = 1024
+	defaultDiskSpaceStop   = 256
 
 	// Defaults for RPC server options and policy.
 	defaultTLSCurve             = "P-521"
@@ -52,20 +63,30 @@ const (
 	defaultMaxRPCWebsockets     = 25
 	defaultMaxRPCConcurrentReqs = 20
 
+	// Defaults for redundancy pair coordination options.
+	defaultCoordLease = time.Second * 15
+
 	// Defaults for P2P network options.
-	defaultMaxSameIP       = 5
-	defaultMaxPeers        = 125
-	defaultDialTimeout     = time.Second * 30
-	defaultPeerIdleTimeout = time.Second * 120
+	defaultMaxSameIP        = 5
+	defaultMaxPeers         = 125
+	defaultDialTimeout      = time.Second * 30
+	defaultPeerIdleTimeout  = time.Second * 120
+	defaultAncientBlockRate = 4096
 
 	// Defaults for banning options.
 	defaultBanDuration  = time.Hour * 24
 	defaultBanThreshold = 100
 
+	// Defaults for critical event alert options.
+	defaultAlertReorgDepth = 6
+
 	// Defaults for relay and mempool policy options.
-	defaultFreeTxRelayLimit      = 15.0
-	defaultMaxOrphanTransactions = 100
-	defaultAllowOldVotes         = false
+	defaultFreeTxRelayLimit       = 15.0
+	defaultMaxOrphanTransactions  = 100
+	defaultAllowOldVotes          = false
+	defaultReplacementFeeIncrease = 10.0
+	defaultTxPolicyTimeout        = 250 * time.Millisecond
+	maxTxPolicyTimeout            = 5 * time.Second
 
 	// Defaults for mining options and policy.
 	defaultGenerate            = false
@@ -74,11 +95,13 @@ const (
 	blockMaxSizeMin            = 1000
 	defaultNoMiningStateSync   = false
 	defaultAllowUnsyncedMining = false
+	defaultMiningAddrPolicy    = "random"
 
 	// Defaults for indexing options.
 	defaultTxIndex           = false
 	defaultAddrIndex         = false
 	defaultNoExistsAddrIndex = false
+	defaultAddrActivityIndex = false
 	defaultNoCFilters        = false
 )
 
@@ -128,22 +151,34 @@ type config struct {
 	RegNet          bool   `long:"regnet" description:"Use the regression test network"`
 	DebugLevel      string `short:"d" long:"debuglevel" description:"Logging level for all subsystems {trace, debug, info, warn, error, critical} -- You may also specify <subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems -- Use show to list available subsystems"`
 	SigCacheMaxSize uint   `long:"sigcachemaxsize" description:"The maximum number of entries in the signature verification cache"`
+	SharedSigCache  string `long:"sharedsigcache" description:"Share the signature verification cache with other processes on this host via a shared memory segment backed by this file, typically under /dev/shm -- The segment is created with room for sigcachemaxsize entries when the file does not exist"`
+	DiskSpaceWarn   uint64 `long:"diskspacewarn" description:"Warn when the free disk space available to the data directory falls below this many MiB -- 0 to disable"`
+	DiskSpaceStop   uint64 `long:"diskspacestop" description:"Gracefully shut down when the free disk space available to the data directory falls below this many MiB to avoid running out of space mid-write -- 0 to disable"`
 
 	// RPC server options and policy.
-	DisableRPC           bool     `long:"norpc" description:"Disable built-in RPC server -- NOTE: The RPC server is disabled by default if no rpcuser/rpcpass or rpclimituser/rpclimitpass is specified"`
-	RPCListeners         []string `long:"rpclisten" description:"Add an interface/port to listen for RPC connections (default port: 9109, testnet: 19109)"`
-	RPCUser              string   `short:"u" long:"rpcuser" description:"Username for RPC connections"`
-	RPCPass              string   `short:"P" long:"rpcpass" default-mask:"-" description:"Password for RPC connections"`
-	RPCLimitUser         string   `long:"rpclimituser" description:"Username for limited RPC connections"`
-	RPCLimitPass         string   `long:"rpclimitpass" default-mask:"-" description:"Password for limited RPC connections"`
-	RPCCert              string   `long:"rpccert" description:"File containing the certificate file"`
-	RPCKey               string   `long:"rpckey" description:"File containing the certificate key"`
-	TLSCurve             string   `long:"tlscurve" description:"Curve to use when generating TLS keypairs"`
-	AltDNSNames          []string `long:"altdnsnames" description:"Specify additional DNS names to use when generating the RPC server certificate" env:"DCRD_ALT_DNSNAMES" env-delim:","`
-	DisableTLS           bool     `long:"notls" description:"Disable TLS for the RPC server -- NOTE: This is only allowed if the RPC server is bound to localhost"`
-	RPCMaxClients        int      `long:"rpcmaxclients" description:"Max number of RPC clients for standard connections"`
-	RPCMaxWebsockets     int      `long:"rpcmaxwebsockets" description:"Max number of RPC websocket connections"`
-	RPCMaxConcurrentReqs int      `long:"rpcmaxconcurrentreqs" description:"Max number of concurrent RPC requests that may be processed concurrently"`
+	DisableRPC            bool     `long:"norpc" description:"Disable built-in RPC server -- NOTE: The RPC server is disabled by default if no rpcuser/rpcpass or rpclimituser/rpclimitpass is specified"`
+	RPCListeners          []string `long:"rpclisten" description:"Add an interface/port to listen for RPC connections (default port: 9109, testnet: 19109)"`
+	RPCUnixSocket         string   `long:"rpcunixsocket" description:"Path of a unix domain socket to additionally listen for RPC connections on -- NOTE: Connections over the socket do not use TLS and requests without credentials are authorized as the admin user, so access is only restricted by the permissions of the socket file, which is only accessible by the owner"`
+	RPCUser               string   `short:"u" long:"rpcuser" description:"Username for RPC connections"`
+	RPCPass               string   `short:"P" long:"rpcpass" default-mask:"-" description:"Password for RPC connections"`
+	RPCLimitUser          string   `long:"rpclimituser" description:"Username for limited RPC connections"`
+	RPCLimitPass          string   `long:"rpclimitpass" default-mask:"-" description:"Password for limited RPC connections"`
+	RPCCert               string   `long:"rpccert" description:"File containing the certificate file"`
+	RPCKey                string   `long:"rpckey" description:"File containing the certificate key"`
+	TLSCurve              string   `long:"tlscurve" description:"Curve to use when generating TLS keypairs"`
+	AltDNSNames           []string `long:"altdnsnames" description:"Specify additional DNS names to use when generating the RPC server certificate" env:"DCRD_ALT_DNSNAMES" env-delim:","`
+	DisableTLS            bool     `long:"notls" description:"Disable TLS for the RPC server -- NOTE: This is only allowed if the RPC server is bound to localhost"`
+	RPCMaxClients         int      `long:"rpcmaxclients" description:"Max number of RPC clients for standard connections"`
+	RPCMaxWebsockets      int      `long:"rpcmaxwebsockets" description:"Max number of RPC websocket connections"`
+	RPCMaxConcurrentReqs  int      `long:"rpcmaxconcurrentreqs" description:"Max number of concurrent RPC requests that may be processed concurrently"`
+	RPCAuditLog           string   `long:"rpcauditlog" description:"Append a record of every state-changing RPC, including the credentials and address of the client and the result, to the specified file"`
+	EnableExperimentalRPC bool     `long:"enableexperimentalrpc" description:"Enable the experimental RPCs, which are prefixed with x_ and come with no stability guarantees -- NOTE: They may change or be removed in any release"`
+
+	// Redundancy pair coordination options.
+	CoordPeer   string        `long:"coordpeer" description:"Form a redundancy pair that shares a virtual IP address with the dcrd instance whose RPC server listens on the specified address so that only the elected leader of the pair serves miners and websocket clients -- NOTE: Both instances must use the same rpcuser and rpcpass"`
+	CoordNodeID string        `long:"coordnodeid" description:"Identifier of this instance within its redundancy pair which must differ from the identifier of the peer instance -- The instance with the lower identifier is preferred as the leader (default: hostname)"`
+	CoordCert   string        `long:"coordcert" description:"File containing the certificate of the RPC server of the redundancy pair peer (default: rpccert)"`
+	CoordLease  time.Duration `long:"coordlease" description:"How long the standby instance of a redundancy pair waits without hearing from its peer before taking over as the leader.  Valid time units are {s, m, h}.  Minimum 3 seconds"`
 
 	// P2P proxy and Tor settings.
 	Proxy          string `long:"proxy" description:"Connect via SOCKS5 proxy (eg. 127.0.0.1:9050)"`
@@ -156,14 +191,25 @@ type config struct {
 	TorIsolation   bool   `long:"torisolation" description:"Enable Tor stream isolation by randomizing user credentials for each connection"`
 
 	// P2P network options.
-	AddPeers        []string      `short:"a" long:"addpeer" description:"Add a peer to connect with at startup"`
-	ConnectPeers    []string      `long:"connect" description:"Connect only to the specified peers at startup"`
-	DisableListen   bool          `long:"nolisten" description:"Disable listening for incoming connections -- NOTE: Listening is automatically disabled if the --connect or --proxy options are used without also specifying listen interfaces via --listen"`
-	Listeners       []string      `long:"listen" description:"Add an interface/port to listen for connections (default all interfaces port: 9108, testnet: 19108)"`
-	MaxSameIP       int           `long:"maxsameip" description:"Max number of connections with the same IP -- 0 to disable"`
-	MaxPeers        int           `long:"maxpeers" description:"Max number of inbound and outbound peers"`
-	DialTimeout     time.Duration `long:"dialtimeout" description:"How long to wait for TCP connection completion.  Valid time units are {s, m, h}.  Minimum 1 second"`
-	PeerIdleTimeout time.Duration `long:"peeridletimeout" description:"The duration of inactivity before a peer is timed out. Valid time units are {s,m,h}. Minimum 15 seconds"`
+	AddPeers         []string      `short:"a" long:"addpeer" description:"Add a peer to connect with at startup"`
+	ConnectPeers     []string      `long:"connect" description:"Connect only to the specified peers at startup"`
+	DisableListen    bool          `long:"nolisten" description:"Disable listening for incoming connections -- NOTE: Listening is automatically disabled if the --connect or --proxy options are used without also specifying listen interfaces via --listen"`
+	Listeners        []string      `long:"listen" description:"Add an interface/port to listen for connections (default all interfaces port: 9108, testnet: 19108)"`
+	MaxSameIP        int           `long:"maxsameip" description:"Max number of connections with the same IP -- 0 to disable"`
+	MaxPeers         int           `long:"maxpeers" description:"Max number of inbound and outbound peers"`
+	DialTimeout      time.Duration `long:"dialtimeout" description:"How long to wait for TCP connection completion.  Valid time units are {s, m, h}.  Minimum 1 second"`
+	PeerIdleTimeout  time.Duration `long:"peeridletimeout" description:"The duration of inactivity before a peer is timed out. Valid time units are {s,m,h}. Minimum 15 seconds"`
+	PeerIdentity     bool          `long:"peeridentity" description:"Authenticate to peers that support it with a long-term identity key that is stored in the data directory and created if needed"`
+	AllowPeerKeys    []string      `long:"allowpeerkey" description:"Only allow connections with peers that authenticate with the specified hex-encoded identity public key -- may be specified multiple times (implies --peeridentity)"`
+	MsgLimits        []string      `long:"msglimit" description:"Limit the payload size and optionally the rate of a type of message received from peers in the form <command>:<maxbytes>[:<maxrate>[:<burst>]] where the rate is in messages per second and 0 bytes means the protocol limit -- may be specified multiple times -- Only valid with the regnet and simnet options"`
+	AncientBlockRate uint32        `long:"ancientblockrate" description:"Maximum rate in KiB/s at which blocks more than 288 blocks deep in the chain are served to each peer that is not whitelisted -- 0 to disable"`
+	PeerTelemetry    bool          `long:"peertelemetry" description:"Record anonymized peer connection lifecycle, address relay, and inventory announcement events that can be exported for network research with the dumppeertelemetry RPC"`
+
+	// P2P network address family options.
+	IPv6Only           bool   `long:"ipv6only" description:"Only connect to peers over IPv6"`
+	PreferIPv4         bool   `long:"preferipv4" description:"Prefer IPv4 over IPv6 when racing connections to peers that have addresses in both families"`
+	TargetOutboundIPv4 uint32 `long:"targetoutboundipv4" description:"Minimum number of automatic outbound peers to maintain over IPv4 -- 0 to disable"`
+	TargetOutboundIPv6 uint32 `long:"targetoutboundipv6" description:"Minimum number of automatic outbound peers to maintain over IPv6 -- 0 to disable"`
 
 	// P2P network discovery options.
 	DisableSeeders bool     `long:"noseeders" description:"Disable seeding for peer discovery"`
@@ -171,6 +217,8 @@ type config struct {
 	ExternalIPs    []string `long:"externalip" description:"Add an ip to the list of local addresses we claim to listen on to peers"`
 	NoDiscoverIP   bool     `long:"nodiscoverip" description:"Disable automatic network address discovery of local external IPs"`
 	Upnp           bool     `long:"upnp" description:"Use UPnP to map our listening port outside of NAT"`
+	MDNS           bool     `long:"mdns" description:"Discover and automatically connect to other nodes on the local network via multicast DNS -- Not allowed on mainnet without also specifying --allowmdnsmainnet"`
+	AllowMDNSMain  bool     `long:"allowmdnsmainnet" description:"Allow --mdns to be used on mainnet"`
 
 	// Banning options.
 	DisableBanning bool          `long:"nobanning" description:"Disable banning of misbehaving peers"`
@@ -180,21 +228,46 @@ type config struct {
 
 	// Chain related options.
 	DisableCheckpoints bool   `long:"nocheckpoints" description:"Disable built-in checkpoints.  Don't do this unless you know what you're doing"`
+	AssumeValid        string `long:"assumevalid" description:"Hash of a block assumed to be valid such that script validation is skipped for it and its ancestors during the initial chain sync while all other checks are still enforced -- Defaults to a block built into the software for the active network, 0 to disable"`
 	DumpBlockchain     string `long:"dumpblockchain" description:"Write blockchain as a flat file of blocks for use with addblock, to the specified filename"`
+	CoinbaseMaturity   uint16 `long:"coinbasematurity" description:"Override the number of blocks before newly mined coins can be spent -- Only valid with the regnet and simnet options"`
+	TicketMaturity     uint16 `long:"ticketmaturity" description:"Override the number of blocks before newly purchased tickets are eligible to vote -- Only valid with the regnet and simnet options"`
+
+	// Critical event alert options.
+	AlertEvents     []string `long:"alertevent" description:"Record the specified kind of critical event to a durable outbox in the data directory and deliver it to the alert webhooks -- may be specified multiple times {reorg, ticketexhaustion, corruption}"`
+	AlertReorgDepth uint32   `long:"alertreorgdepth" description:"Minimum number of blocks a chain reorganization must disconnect to record a reorg alert"`
+	AlertWebhooks   []string `long:"alertwebhook" description:"POST recorded critical event alerts as JSON to the specified HTTP(S) URL, retrying until they are accepted -- may be specified multiple times"`
+
+	// Webhook notification options.
+	Webhooks       []string `long:"webhook" description:"POST events of the specified category as JSON to the specified HTTP(S) URL in the form category=url -- may be specified multiple times {block, tx, peerban}"`
+	WebhookSecret  string   `long:"webhooksecret" default-mask:"-" description:"Secret used to sign webhook payloads with HMAC-SHA256 in the X-Dcrd-Signature header"`
+	WebhookTxAddrs []string `long:"webhooktxaddr" description:"Deliver tx webhook events for transactions that pay to the specified address -- may be specified multiple times"`
 
 	// Relay and mempool policy.
-	MinRelayTxFee    float64 `long:"minrelaytxfee" description:"The minimum transaction fee in DCR/kB to be considered a non-zero fee"`
-	FreeTxRelayLimit float64 `long:"limitfreerelay" description:"Limit relay of transactions with no transaction fee to the given amount in thousands of bytes per minute"`
-	NoRelayPriority  bool    `long:"norelaypriority" description:"Do not require free or low-fee transactions to have high priority for relaying"`
-	MaxOrphanTxs     int     `long:"maxorphantx" description:"Max number of orphan transactions to keep in memory"`
-	BlocksOnly       bool    `long:"blocksonly" description:"Do not accept transactions from remote peers"`
-	AcceptNonStd     bool    `long:"acceptnonstd" description:"Accept and relay non-standard transactions to the network regardless of the default settings for the active network"`
-	RejectNonStd     bool    `long:"rejectnonstd" description:"Reject non-standard transactions regardless of the default settings for the active network"`
-	AllowOldVotes    bool    `long:"allowoldvotes" description:"Enable the addition of very old votes to the mempool"`
+	MinRelayTxFee          float64       `long:"minrelaytxfee" description:"The minimum transaction fee in DCR/kB to be considered a non-zero fee"`
+	FreeTxRelayLimit       float64       `long:"limitfreerelay" description:"Limit relay of transactions with no transaction fee to the given amount in thousands of bytes per minute"`
+	NoRelayPriority        bool          `long:"norelaypriority" description:"Do not require free or low-fee transactions to have high priority for relaying"`
+	MaxOrphanTxs           int           `long:"maxorphantx" description:"Max number of orphan transactions to keep in memory"`
+	BlocksOnly             bool          `long:"blocksonly" description:"Do not accept transactions from remote peers"`
+	NoServeBlocks          bool          `long:"noserveblocks" description:"Do not advertise the full node network service or serve and announce full blocks to peers that are not whitelisted while still serving block headers and compact filters -- NOTE: Full blocks are still downloaded and the full UTXO set is still maintained in order to validate them"`
+	TxRecon                bool          `long:"txrecon" description:"Announce transactions to peers that support it via set reconciliation instead of flooding"`
+	BloomFilters           bool          `long:"bloomfilters" description:"Serve BIP37-style bloom filtered blocks and transactions to legacy SPV clients that do not support version 2 compact filters"`
+	AcceptNonStd           bool          `long:"acceptnonstd" description:"Accept and relay non-standard transactions to the network regardless of the default settings for the active network"`
+	RejectNonStd           bool          `long:"rejectnonstd" description:"Reject non-standard transactions regardless of the default settings for the active network"`
+	TxTypePolicies         []string      `long:"txtypepolicy" description:"Override the relay policy of a type of transaction in the form <type>:<policy> where type is one of {regular, ticket, vote, revocation} and policy is one of {std, nonstd, reject} -- may be specified multiple times -- Only valid with the regnet and simnet options"`
+	AllowOldVotes          bool          `long:"allowoldvotes" description:"Enable the addition of very old votes to the mempool"`
+	MempoolTTL             time.Duration `long:"mempoolttl" description:"Evict regular transactions and ticket purchases that have been in the mempool longer than the given duration regardless of their expiry.  Valid time units are {s, m, h}.  Minimum 1 minute.  A value of 0 disables time-based eviction"`
+	TxReplacement          bool          `long:"txreplacement" description:"Allow regular transactions in the mempool to be replaced by conflicting regular transactions that pay sufficiently higher fees"`
+	ReplacementFeeIncrease float64       `long:"replacementfeeincrease" description:"The minimum percentage by which the fee rate of a replacement transaction must exceed the fee rate of each transaction it replaces"`
+	FeeExemptPeers         []string      `long:"feeexemptpeer" description:"Accept transactions relayed by peers from the specified IP network or IP regardless of the minimum relay fee -- may be specified multiple times (eg. 192.168.1.0/24 or ::1)"`
+	FeeExemptRPCUsers      []string      `long:"feeexemptrpcuser" description:"Accept transactions submitted via sendrawtransaction by the specified RPC user regardless of the minimum relay fee -- must be the rpcuser or rpclimituser"`
+	TxPolicyURL            string        `long:"txpolicyurl" description:"Consult the external transaction policy service at the specified HTTP(S) URL, such as an anti-spam scoring service, before accepting new transactions to the mempool.  Transactions are accepted when the service does not respond in time"`
+	TxPolicyTimeout        time.Duration `long:"txpolicytimeout" description:"Maximum amount of time to wait for the external transaction policy service to respond.  Valid time units are {ms, s}.  Maximum 5 seconds"`
 
 	// Mining options and policy.
 	Generate            bool     `long:"generate" description:"Generate (mine) coins using the CPU"`
 	MiningAddrs         []string `long:"miningaddr" description:"Add the specified payment address to the list of addresses to use for generated blocks -- At least one address is required if the generate option is set"`
+	MiningAddrPolicy    string   `long:"miningaddrpolicy" description:"Policy used to choose which of the mining addresses each block template pays to {random, roundrobin}"`
 	BlockMinSize        uint32   `long:"blockminsize" description:"Minimum block size in bytes to be used when creating a block"`
 	BlockMaxSize        uint32   `long:"blockmaxsize" description:"Maximum block size in bytes to be used when creating a block"`
 	BlockPrioritySize   uint32   `long:"blockprioritysize" description:"Size in bytes for high-priority/low-fee transactions when creating a block"`
@@ -204,14 +277,16 @@ type config struct {
 	AllowUnsyncedMining bool     `long:"allowunsyncedmining" description:"Allow block templates to be generated even when the chain is not considered synced on networks other than the main network.  This is automatically enabled when the simnet option is set.  Don't do this unless you know what you're doing"`
 
 	// Indexing options.
-	TxIndex             bool `long:"txindex" description:"Maintain a full hash-based transaction index which makes all transactions available via the getrawtransaction RPC"`
-	DropTxIndex         bool `long:"droptxindex" description:"Deletes the hash-based transaction index from the database on start up and then exits"`
-	AddrIndex           bool `long:"addrindex" description:"Maintain a full address-based transaction index which makes the searchrawtransactions RPC available"`
-	DropAddrIndex       bool `long:"dropaddrindex" description:"Deletes the address-based transaction index from the database on start up and then exits"`
-	NoExistsAddrIndex   bool `long:"noexistsaddrindex" description:"Disable the exists address index, which tracks whether or not an address has even been used"`
-	DropExistsAddrIndex bool `long:"dropexistsaddrindex" description:"Deletes the exists address index from the database on start up and then exits"`
-	NoCFilters          bool `long:"nocfilters" description:"(Deprecated) Disable compact filtering (CF) support"`
-	DropCFIndex         bool `long:"dropcfindex" description:"(Deprecated) Deletes the index used for compact filtering (CF) support from the database on start up and then exits"`
+	TxIndex               bool `long:"txindex" description:"Maintain a full hash-based transaction index which makes all transactions available via the getrawtransaction RPC"`
+	DropTxIndex           bool `long:"droptxindex" description:"Deletes the hash-based transaction index from the database on start up and then exits"`
+	AddrIndex             bool `long:"addrindex" description:"Maintain a full address-based transaction index which makes the searchrawtransactions RPC available"`
+	DropAddrIndex         bool `long:"dropaddrindex" description:"Deletes the address-based transaction index from the database on start up and then exits"`
+	NoExistsAddrIndex     bool `long:"noexistsaddrindex" description:"Disable the exists address index, which tracks whether or not an address has even been used"`
+	DropExistsAddrIndex   bool `long:"dropexistsaddrindex" description:"Deletes the exists address index from the database on start up and then exits"`
+	AddrActivityIndex     bool `long:"addractivityindex" description:"Maintain an index of the first and last heights at which each address is seen in the main chain which makes the getaddressactivity RPC available"`
+	DropAddrActivityIndex bool `long:"dropaddractivityindex" description:"Deletes the address activity index from the database on start up and then exits"`
+	NoCFilters            bool `long:"nocfilters" description:"(Deprecated) Disable compact filtering (CF) support"`
+	DropCFIndex           bool `long:"dropcfindex" description:"(Deprecated) Deletes the index used for compact filtering (CF) support from the database on start up and then exits"`
 
 	// IPC options.
 	PipeRx         uint `long:"piperx" description:"File descriptor of read end pipe to enable parent -> child process communication"`
@@ -224,8 +299,16 @@ type config struct {
 	oniondial     func(context.Context, string, string) (net.Conn, error)
 	dial          func(context.Context, string, string) (net.Conn, error)
 	miningAddrs   []dcrutil.Address
+	miningPolicy  mining.MiningAddrPolicy
+	webhooks      map[string][]string
+	webhookAddrs  []string
 	minRelayTxFee dcrutil.Amount
 	whitelists    []*net.IPNet
+	feeExempt     []*net.IPNet
+	allowPeerKeys map[[secp256k1.PubKeyBytesLenCompressed]byte]struct{}
+	assumeValid   chainhash.Hash
+	msgLimits     map[string]peer.MessageLimit
+	txPolicies    map[stake.TxType]mempool.TxTypePolicy
 	ipv4NetInfo   types.NetworksResult
 	ipv6NetInfo   types.NetworksResult
 	onionNetInfo  types.NetworksResult
@@ -408,6 +491,106 @@ func normalizeAddresses(addrs []string, defaultPort string) []string {
 	return removeDuplicateAddresses(addrs)
 }
 
+// parseIPNet parses the passed IP network in CIDR notation or single IP
+// address into an IP network.  A single IP address results in a network that
+// only contains that address.  It returns nil when the value is invalid.
+func parseIPNet(addr string) *net.IPNet {
+	_, ipnet, err := net.ParseCIDR(addr)
+	if err == nil {
+		return ipnet
+	}
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return nil
+	}
+	bits := 32
+	if ip.To4() == nil {
+		// IPv6
+		bits = 128
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+}
+
+// parseMessageLimit parses the passed message limit in the form
+// <command>:<maxbytes>[:<maxrate>[:<burst>]] into the command it applies to and
+// the limit itself.
+func parseMessageLimit(limitStr string) (string, peer.MessageLimit, error) {
+	fields := strings.Split(limitStr, ":")
+	if len(fields) < 2 || len(fields) > 4 {
+		return "", peer.MessageLimit{}, errors.New("the format must be " +
+			"<command>:<maxbytes>[:<maxrate>[:<burst>]]")
+	}
+	command := fields[0]
+	if command == "" || len(command) > wire.CommandSize {
+		err := fmt.Errorf("invalid command '%s'", command)
+		return "", peer.MessageLimit{}, err
+	}
+	maxPayload, err := strconv.ParseUint(fields[1], 10, 32)
+	if err != nil {
+		err := fmt.Errorf("invalid max bytes: %v", err)
+		return "", peer.MessageLimit{}, err
+	}
+	var maxRate float64
+	if len(fields) > 2 {
+		maxRate, err = strconv.ParseFloat(fields[2], 64)
+		if err != nil || maxRate < 0 || math.IsInf(maxRate, 0) ||
+			math.IsNaN(maxRate) {
+
+			err := fmt.Errorf("invalid max rate '%s'", fields[2])
+			return "", peer.MessageLimit{}, err
+		}
+	}
+	var burst uint64
+	if len(fields) > 3 {
+		burst, err = strconv.ParseUint(fields[3], 10, 32)
+		if err != nil {
+			err := fmt.Errorf("invalid burst: %v", err)
+			return "", peer.MessageLimit{}, err
+		}
+	}
+	limit := peer.MessageLimit{
+		MaxPayload: uint32(maxPayload),
+		MaxRate:    maxRate,
+		Burst:      uint32(burst),
+	}
+	return command, limit, nil
+}
+
+// parseTxTypePolicy parses the passed transaction type policy in the form
+// <type>:<policy> into the transaction type it applies to and the policy
+// itself.
+func parseTxTypePolicy(policyStr string) (stake.TxType, mempool.TxTypePolicy, error) {
+	fields := strings.Split(policyStr, ":")
+	if len(fields) != 2 {
+		return 0, 0, errors.New("the format must be <type>:<policy>")
+	}
+	var txType stake.TxType
+	switch fields[0] {
+	case "regular":
+		txType = stake.TxTypeRegular
+	case "ticket":
+		txType = stake.TxTypeSStx
+	case "vote":
+		txType = stake.TxTypeSSGen
+	case "revocation":
+		txType = stake.TxTypeSSRtx
+	default:
+		return 0, 0, fmt.Errorf("unknown transaction type '%s'", fields[0])
+	}
+	var policy mempool.TxTypePolicy
+	switch fields[1] {
+	case "std":
+		policy = mempool.TxTypePolicyStandard
+	case "nonstd":
+		policy = mempool.TxTypePolicyNonStandard
+	case "reject":
+		policy = mempool.TxTypePolicyReject
+	default:
+		return 0, 0, fmt.Errorf("unknown policy '%s'", fields[1])
+	}
+	return txType, policy, nil
+}
+
 // fileExists reports whether the named file or directory exists.
 func fileExists(name string) bool {
 	if _, err := os.Stat(name); err != nil {
@@ -552,6 +735,8 @@ func loadConfig() (*config, []string, error) {
 		DbType:          defaultDbType,
 		DebugLevel:      defaultLogLevel,
 		SigCacheMaxSize: defaultSigCacheMaxSize,
+		DiskSpaceWarn:   defaultDiskSpaceWarn,
+		DiskSpaceStop:   defaultDiskSpaceStop,
 
 		// RPC server options and policy.
 		RPCCert:              defaultRPCCertFile,
@@ -561,21 +746,30 @@ func loadConfig() (*config, []string, error) {
 		RPCMaxWebsockets:     defaultMaxRPCWebsockets,
 		RPCMaxConcurrentReqs: defaultMaxRPCConcurrentReqs,
 
+		// Redundancy pair coordination options.
+		CoordLease: defaultCoordLease,
+
 		// P2P network options.
-		MaxSameIP:       defaultMaxSameIP,
-		MaxPeers:        defaultMaxPeers,
-		DialTimeout:     defaultDialTimeout,
-		PeerIdleTimeout: defaultPeerIdleTimeout,
+		MaxSameIP:        defaultMaxSameIP,
+		MaxPeers:         defaultMaxPeers,
+		DialTimeout:      defaultDialTimeout,
+		PeerIdleTimeout:  defaultPeerIdleTimeout,
+		AncientBlockRate: defaultAncientBlockRate,
 
 		// Banning options.
 		BanDuration:  defaultBanDuration,
 		BanThreshold: defaultBanThreshold,
 
+		// Critical event alert options.
+		AlertReorgDepth: defaultAlertReorgDepth,
+
 		// Relay and mempool policy.
-		MinRelayTxFee:    mempool.DefaultMinRelayTxFee.ToCoin(),
-		FreeTxRelayLimit: defaultFreeTxRelayLimit,
-		MaxOrphanTxs:     defaultMaxOrphanTransactions,
-		AllowOldVotes:    defaultAllowOldVotes,
+		MinRelayTxFee:          mempool.DefaultMinRelayTxFee.ToCoin(),
+		FreeTxRelayLimit:       defaultFreeTxRelayLimit,
+		MaxOrphanTxs:           defaultMaxOrphanTransactions,
+		AllowOldVotes:          defaultAllowOldVotes,
+		ReplacementFeeIncrease: defaultReplacementFeeIncrease,
+		TxPolicyTimeout:        defaultTxPolicyTimeout,
 
 		// Mining options and policy.
 		Generate:            defaultGenerate,
@@ -584,11 +778,13 @@ func loadConfig() (*config, []string, error) {
 		BlockPrioritySize:   mempool.DefaultBlockPrioritySize,
 		NoMiningStateSync:   defaultNoMiningStateSync,
 		AllowUnsyncedMining: defaultAllowUnsyncedMining,
+		MiningAddrPolicy:    defaultMiningAddrPolicy,
 
 		// Indexing options.
 		TxIndex:           defaultTxIndex,
 		AddrIndex:         defaultAddrIndex,
 		NoExistsAddrIndex: defaultNoExistsAddrIndex,
+		AddrActivityIndex: defaultAddrActivityIndex,
 		NoCFilters:        defaultNoCFilters,
 
 		// Cooked options ready for use.
@@ -773,6 +969,41 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// Override the coinbase and ticket maturities of the active network when
+	// requested.  Note that the maturities of the main and test networks can't
+	// be overridden.
+	if cfg.CoinbaseMaturity != 0 || cfg.TicketMaturity != 0 {
+		chainParams, err := cfg.params.WithMaturity(cfg.CoinbaseMaturity,
+			cfg.TicketMaturity)
+		if err != nil {
+			str := "%s: the coinbasematurity and ticketmaturity options " +
+				"can only be used with the regnet and simnet options"
+			err := fmt.Errorf(str, funcName)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+		cfg.params = &params{Params: chainParams, rpcPort: cfg.params.rpcPort}
+	}
+
+	// Determine the block that is assumed to be valid.  It defaults to the
+	// block built into the active network parameters and may be overridden
+	// with any block hash or disabled entirely with a value of 0.
+	cfg.assumeValid = cfg.params.AssumeValid
+	if cfg.AssumeValid == "0" {
+		cfg.assumeValid = chainhash.Hash{}
+	} else if cfg.AssumeValid != "" {
+		hash, err := chainhash.NewHashFromStr(cfg.AssumeValid)
+		if err != nil {
+			str := "%s: the assumevalid value of '%s' is invalid: %v"
+			err := fmt.Errorf(str, funcName, cfg.AssumeValid, err)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+		cfg.assumeValid = *hash
+	}
+
 	// Set the default policy for relaying non-standard transactions
 	// according to the default of the active network. The set
 	// configuration value takes precedence over the default value for the
@@ -793,6 +1024,33 @@ func loadConfig() (*config, []string, error) {
 	}
 	cfg.AcceptNonStd = acceptNonStd
 
+	// Parse any given transaction type policies.  They are only allowed on
+	// private networks since relaying transaction types the rest of the
+	// network does not, or vice versa, is only useful for testing.
+	if len(cfg.TxTypePolicies) > 0 {
+		if cfg.params.Net != wire.RegNet && cfg.params.Net != wire.SimNet {
+			str := "%s: the txtypepolicy option can only be used with the " +
+				"regnet and simnet options"
+			err := fmt.Errorf(str, funcName)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+		cfg.txPolicies = make(map[stake.TxType]mempool.TxTypePolicy,
+			len(cfg.TxTypePolicies))
+		for _, policyStr := range cfg.TxTypePolicies {
+			txType, policy, err := parseTxTypePolicy(policyStr)
+			if err != nil {
+				str := "%s: the txtypepolicy value of '%s' is invalid: %v"
+				err := fmt.Errorf(str, funcName, policyStr, err)
+				fmt.Fprintln(os.Stderr, err)
+				fmt.Fprintln(os.Stderr, usageMessage)
+				return nil, nil, err
+			}
+			cfg.txPolicies[txType] = policy
+		}
+	}
+
 	// Append the network type to the data directory so it is "namespaced"
 	// per network.  In addition to the block database, there are other
 	// pieces of data that are saved to disk such as address manager state.
@@ -818,6 +1076,15 @@ func loadConfig() (*config, []string, error) {
 		// logger variables may be used.
 		initLogRotator(filepath.Join(cfg.LogDir, defaultLogFilename))
 	}
+	if cfg.RPCAuditLog != "" {
+		cfg.RPCAuditLog = cleanAndExpandPath(cfg.RPCAuditLog)
+	}
+	if cfg.RPCUnixSocket != "" {
+		cfg.RPCUnixSocket = cleanAndExpandPath(cfg.RPCUnixSocket)
+	}
+	if cfg.SharedSigCache != "" {
+		cfg.SharedSigCache = cleanAndExpandPath(cfg.SharedSigCache)
+	}
 
 	// Special show command to list supported subsystems and exit.
 	if cfg.DebugLevel == "show" {
@@ -888,6 +1155,36 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// The IPv6-only mode is incompatible with options that would result in
+	// connecting to peers over IPv4.
+	if cfg.IPv6Only && (cfg.PreferIPv4 || cfg.TargetOutboundIPv4 != 0) {
+		str := "%s: the ipv6only option may not be specified with the " +
+			"preferipv4 or targetoutboundipv4 options"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// The per-family outbound targets may not exceed the total number of
+	// automatic outbound peers.
+	targetOutbound := defaultTargetOutbound
+	if cfg.MaxPeers < targetOutbound {
+		targetOutbound = cfg.MaxPeers
+	}
+	familyTargets := uint64(cfg.TargetOutboundIPv4) +
+		uint64(cfg.TargetOutboundIPv6)
+	if familyTargets > uint64(targetOutbound) {
+		str := "%s: the combined targetoutboundipv4 and targetoutboundipv6 " +
+			"options may not exceed the number of outbound peers [%d] -- " +
+			"parsed [%d] and [%d]"
+		err := fmt.Errorf(str, funcName, targetOutbound,
+			cfg.TargetOutboundIPv4, cfg.TargetOutboundIPv6)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
 	// Don't allow peeridletimeout durations that are too short.
 	if cfg.PeerIdleTimeout < time.Second*15 {
 		str := "%s: the peeridletimeout option may not be less " +
@@ -900,36 +1197,90 @@ func loadConfig() (*config, []string, error) {
 
 	// Validate any given whitelisted IP addresses and networks.
 	if len(cfg.Whitelists) > 0 {
-		var ip net.IP
 		cfg.whitelists = make([]*net.IPNet, 0, len(cfg.Whitelists))
-
 		for _, addr := range cfg.Whitelists {
-			_, ipnet, err := net.ParseCIDR(addr)
-			if err != nil {
-				ip = net.ParseIP(addr)
-				if ip == nil {
-					str := "%s: the whitelist value of '%s' is invalid"
-					err = fmt.Errorf(str, funcName, addr)
-					fmt.Fprintln(os.Stderr, err)
-					fmt.Fprintln(os.Stderr, usageMessage)
-					return nil, nil, err
-				}
-				var bits int
-				if ip.To4() == nil {
-					// IPv6
-					bits = 128
-				} else {
-					bits = 32
-				}
-				ipnet = &net.IPNet{
-					IP:   ip,
-					Mask: net.CIDRMask(bits, bits),
-				}
+			ipnet := parseIPNet(addr)
+			if ipnet == nil {
+				str := "%s: the whitelist value of '%s' is invalid"
+				err := fmt.Errorf(str, funcName, addr)
+				fmt.Fprintln(os.Stderr, err)
+				fmt.Fprintln(os.Stderr, usageMessage)
+				return nil, nil, err
 			}
 			cfg.whitelists = append(cfg.whitelists, ipnet)
 		}
 	}
 
+	// Validate any given IP addresses and networks of peers whose relayed
+	// transactions are exempt from the minimum relay fee.
+	if len(cfg.FeeExemptPeers) > 0 {
+		cfg.feeExempt = make([]*net.IPNet, 0, len(cfg.FeeExemptPeers))
+		for _, addr := range cfg.FeeExemptPeers {
+			ipnet := parseIPNet(addr)
+			if ipnet == nil {
+				str := "%s: the feeexemptpeer value of '%s' is invalid"
+				err := fmt.Errorf(str, funcName, addr)
+				fmt.Fprintln(os.Stderr, err)
+				fmt.Fprintln(os.Stderr, usageMessage)
+				return nil, nil, err
+			}
+			cfg.feeExempt = append(cfg.feeExempt, ipnet)
+		}
+	}
+
+	// Validate any given allowed peer identity keys.  Allowing peers by
+	// their identity keys requires the local peer to have one since only
+	// peers that both advertise support for authentication exchange keys.
+	if len(cfg.AllowPeerKeys) > 0 {
+		cfg.PeerIdentity = true
+		const keyLen = secp256k1.PubKeyBytesLenCompressed
+		cfg.allowPeerKeys = make(map[[keyLen]byte]struct{},
+			len(cfg.AllowPeerKeys))
+		for _, keyStr := range cfg.AllowPeerKeys {
+			keyBytes, err := hex.DecodeString(keyStr)
+			var pubKey *secp256k1.PublicKey
+			if err == nil {
+				pubKey, err = secp256k1.ParsePubKey(keyBytes)
+			}
+			if err != nil {
+				str := "%s: the allowpeerkey value of '%s' is invalid: %v"
+				err = fmt.Errorf(str, funcName, keyStr, err)
+				fmt.Fprintln(os.Stderr, err)
+				fmt.Fprintln(os.Stderr, usageMessage)
+				return nil, nil, err
+			}
+			var key [keyLen]byte
+			copy(key[:], pubKey.SerializeCompressed())
+			cfg.allowPeerKeys[key] = struct{}{}
+		}
+	}
+
+	// Parse any given message limits.  They are only allowed on private
+	// networks since stricter limits than the protocol on the public
+	// networks could prevent the node from staying in sync with the network.
+	if len(cfg.MsgLimits) > 0 {
+		if cfg.params.Net != wire.RegNet && cfg.params.Net != wire.SimNet {
+			str := "%s: the msglimit option can only be used with the " +
+				"regnet and simnet options"
+			err := fmt.Errorf(str, funcName)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+		cfg.msgLimits = make(map[string]peer.MessageLimit, len(cfg.MsgLimits))
+		for _, limitStr := range cfg.MsgLimits {
+			command, limit, err := parseMessageLimit(limitStr)
+			if err != nil {
+				str := "%s: the msglimit value of '%s' is invalid: %v"
+				err := fmt.Errorf(str, funcName, limitStr, err)
+				fmt.Fprintln(os.Stderr, err)
+				fmt.Fprintln(os.Stderr, usageMessage)
+				return nil, nil, err
+			}
+			cfg.msgLimits[command] = limit
+		}
+	}
+
 	// --addPeer and --connect do not mix.
 	if len(cfg.AddPeers) > 0 && len(cfg.ConnectPeers) > 0 {
 		str := "%s: the --addpeer and --connect options can not be " +
@@ -946,9 +1297,21 @@ func loadConfig() (*config, []string, error) {
 		cfg.DisableListen = true
 	}
 
-	// Connect means no seeding.
+	// Connect means no seeding or local network discovery.
 	if len(cfg.ConnectPeers) > 0 {
 		cfg.DisableSeeders = true
+		cfg.MDNS = false
+	}
+
+	// Don't allow local network discovery on mainnet unless it is explicitly
+	// allowed.
+	if cfg.MDNS && cfg.params == &mainNetParams && !cfg.AllowMDNSMain {
+		str := "%s: mdns cannot be activated on mainnet without " +
+			"allowmdnsmainnet"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
 	}
 
 	// Add the default listener if none were specified. The default
@@ -980,6 +1343,18 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// Ensure any RPC users exempt from the minimum relay fee are configured.
+	for _, user := range cfg.FeeExemptRPCUsers {
+		if user == "" || (user != cfg.RPCUser && user != cfg.RPCLimitUser) {
+			str := "%s: the feeexemptrpcuser value of '%s' is not the " +
+				"rpcuser or rpclimituser"
+			err := fmt.Errorf(str, funcName, user)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+	}
+
 	// The RPC server is disabled if no username or password is provided.
 	if (cfg.RPCUser == "" || cfg.RPCPass == "") &&
 		(cfg.RPCLimitUser == "" || cfg.RPCLimitPass == "") {
@@ -999,6 +1374,44 @@ func loadConfig() (*config, []string, error) {
 		}
 	}
 
+	// Validate the redundancy pair options and default the node identifier
+	// to the hostname and the peer certificate to the RPC certificate.
+	if cfg.CoordPeer != "" {
+		if cfg.DisableRPC || cfg.RPCUser == "" || cfg.RPCPass == "" {
+			str := "%s: the coordpeer option requires the RPC server " +
+				"to be enabled with the rpcuser and rpcpass options"
+			err := fmt.Errorf(str, funcName)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+		if cfg.CoordLease < time.Second*3 {
+			str := "%s: the coordlease option may not be less than " +
+				"3s -- parsed [%v]"
+			err := fmt.Errorf(str, funcName, cfg.CoordLease)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+		if cfg.CoordNodeID == "" {
+			cfg.CoordNodeID, err = os.Hostname()
+			if err != nil {
+				str := "%s: unable to default the coordnodeid " +
+					"option to the hostname: %v"
+				err := fmt.Errorf(str, funcName, err)
+				fmt.Fprintln(os.Stderr, err)
+				fmt.Fprintln(os.Stderr, usageMessage)
+				return nil, nil, err
+			}
+		}
+		if cfg.CoordCert == "" {
+			cfg.CoordCert = cfg.RPCCert
+		} else {
+			cfg.CoordCert = cleanAndExpandPath(cfg.CoordCert)
+		}
+		cfg.CoordPeer = normalizeAddress(cfg.CoordPeer, cfg.params.rpcPort)
+	}
+
 	if cfg.RPCMaxConcurrentReqs < 0 {
 		str := "%s: the rpcmaxwebsocketconcurrentrequests option may " +
 			"not be less than 0 -- parsed [%d]"
@@ -1033,6 +1446,106 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// The disk space shutdown threshold may not exceed the warning threshold
+	// when both are enabled since the warning would never be issued.
+	if cfg.DiskSpaceWarn != 0 && cfg.DiskSpaceStop > cfg.DiskSpaceWarn {
+		str := "%s: the diskspacestop option may not be greater than the " +
+			"diskspacewarn option -- parsed [%d] and [%d]"
+		err := fmt.Errorf(str, funcName, cfg.DiskSpaceStop, cfg.DiskSpaceWarn)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// Ensure the kinds of critical events to alert on are known, the reorg
+	// depth is sane, and the webhooks to deliver the alerts to are valid HTTP
+	// or HTTPS URLs.
+	for _, kind := range cfg.AlertEvents {
+		if _, ok := alertKinds[kind]; !ok {
+			str := "%s: the alertevent option must be one of reorg, " +
+				"ticketexhaustion, or corruption -- parsed [%s]"
+			err := fmt.Errorf(str, funcName, kind)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+	}
+	if cfg.AlertReorgDepth == 0 {
+		str := "%s: the alertreorgdepth option must be greater than 0"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+	for _, webhook := range cfg.AlertWebhooks {
+		u, err := url.Parse(webhook)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") ||
+			u.Host == "" {
+
+			str := "%s: the alertwebhook option must be an HTTP or HTTPS " +
+				"URL -- parsed [%s]"
+			err := fmt.Errorf(str, funcName, webhook)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+	}
+	if len(cfg.AlertWebhooks) > 0 && len(cfg.AlertEvents) == 0 {
+		str := "%s: the alertwebhook option requires at least one " +
+			"alertevent option"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// Ensure the webhooks are for known categories of events and are valid
+	// HTTP or HTTPS URLs and save the parsed versions.
+	cfg.webhooks = make(map[string][]string)
+	for _, webhook := range cfg.Webhooks {
+		parts := strings.SplitN(webhook, "=", 2)
+		if _, ok := webhookCategories[parts[0]]; !ok || len(parts) != 2 {
+			str := "%s: the webhook option must be in the form category=url " +
+				"where category is one of block, tx, or peerban -- parsed [%s]"
+			err := fmt.Errorf(str, funcName, webhook)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+		u, err := url.Parse(parts[1])
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") ||
+			u.Host == "" {
+
+			str := "%s: the webhook option must specify an HTTP or HTTPS " +
+				"URL -- parsed [%s]"
+			err := fmt.Errorf(str, funcName, webhook)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+		cfg.webhooks[parts[0]] = append(cfg.webhooks[parts[0]], parts[1])
+	}
+
+	// Check webhook tx addresses are valid and save the encoded versions.
+	for _, strAddr := range cfg.WebhookTxAddrs {
+		addr, err := dcrutil.DecodeAddress(strAddr, cfg.params.Params)
+		if err != nil {
+			str := "%s: webhook tx address '%s' failed to decode: %v"
+			err := fmt.Errorf(str, funcName, strAddr, err)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+		cfg.webhookAddrs = append(cfg.webhookAddrs, addr.Address())
+	}
+	if len(cfg.webhooks[webhookCategoryTx]) > 0 && len(cfg.webhookAddrs) == 0 {
+		str := "%s: tx webhooks require at least one webhooktxaddr option"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
 	// Limit the max orphan count to a sane value.
 	if cfg.MaxOrphanTxs < 0 {
 		str := "%s: the maxorphantx option may not be less than 0 " +
@@ -1043,6 +1556,51 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// Don't allow mempool TTL durations that are too short.
+	if cfg.MempoolTTL != 0 && cfg.MempoolTTL < time.Minute {
+		str := "%s: the mempoolttl option may not be less than 1m unless " +
+			"it is 0 -- parsed [%v]"
+		err := fmt.Errorf(str, funcName, cfg.MempoolTTL)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// Don't allow negative replacement fee rate increases.
+	if cfg.ReplacementFeeIncrease < 0 {
+		str := "%s: the replacementfeeincrease option may not be less " +
+			"than 0 -- parsed [%v]"
+		err := fmt.Errorf(str, funcName, cfg.ReplacementFeeIncrease)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// Ensure the external transaction policy service is a valid HTTP or HTTPS
+	// URL and that the timeout is sane since the mempool waits for it.
+	if cfg.TxPolicyURL != "" {
+		u, err := url.Parse(cfg.TxPolicyURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") ||
+			u.Host == "" {
+
+			str := "%s: the txpolicyurl option must be an HTTP or HTTPS " +
+				"URL -- parsed [%s]"
+			err := fmt.Errorf(str, funcName, cfg.TxPolicyURL)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+	}
+	if cfg.TxPolicyTimeout <= 0 || cfg.TxPolicyTimeout > maxTxPolicyTimeout {
+		str := "%s: the txpolicytimeout option must be greater than 0 and " +
+			"may not be more than %v -- parsed [%v]"
+		err := fmt.Errorf(str, funcName, maxTxPolicyTimeout,
+			cfg.TxPolicyTimeout)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
 	// Limit the block priority and minimum block sizes to max block size.
 	cfg.BlockPrioritySize = minUint32(cfg.BlockPrioritySize, cfg.BlockMaxSize)
 	cfg.BlockMinSize = minUint32(cfg.BlockMinSize, cfg.BlockMaxSize)
@@ -1088,6 +1646,16 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// --addractivityindex and --dropaddractivityindex do not mix.
+	if cfg.AddrActivityIndex && cfg.DropAddrActivityIndex {
+		err := fmt.Errorf("%s: the --addractivityindex and "+
+			"--dropaddractivityindex options may not be activated at "+
+			"the same time", funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
 	// !--nocfilters and --dropcfindex do not mix.
 	if !cfg.NoCFilters && cfg.DropCFIndex {
 		err := errors.New("dropcfindex cannot be activated without nocfilters")
@@ -1096,6 +1664,25 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// --noserveblocks and --nocfilters do not mix.
+	if cfg.NoServeBlocks && cfg.NoCFilters {
+		err := errors.New("noserveblocks cannot be activated with " +
+			"nocfilters")
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// --noserveblocks and --bloomfilters do not mix since filtered blocks
+	// are derived from full blocks.
+	if cfg.NoServeBlocks && cfg.BloomFilters {
+		err := errors.New("noserveblocks cannot be activated with " +
+			"bloomfilters")
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
 	// Check mining addresses are valid and saved parsed versions.
 	cfg.miningAddrs = make([]dcrutil.Address, 0, len(cfg.MiningAddrs))
 	for _, strAddr := range cfg.MiningAddrs {
@@ -1110,6 +1697,17 @@ func loadConfig() (*config, []string, error) {
 		cfg.miningAddrs = append(cfg.miningAddrs, addr)
 	}
 
+	// Ensure the mining address policy is known and save the parsed version.
+	cfg.miningPolicy, err = mining.ParseMiningAddrPolicy(cfg.MiningAddrPolicy)
+	if err != nil {
+		str := "%s: the miningaddrpolicy option must be one of random or " +
+			"roundrobin -- parsed [%s]"
+		err := fmt.Errorf(str, funcName, cfg.MiningAddrPolicy)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
 	// Ensure there is at least one mining address when the generate flag is
 	// set.
 	if cfg.Generate && len(cfg.miningAddrs) == 0 {
@@ -8,6 +8,10 @@ import (
 	"os"
 	"strings"
 	"testing"
+
+	"github.com/decred/dcrd/blockchain/stake/v3"
+	"github.com/decred/dcrd/internal/mempool"
+	"github.com/decred/dcrd/peer/v2"
 )
 
 // In order to test command line arguments and environment variables, append
@@ -77,3 +81,142 @@ func TestAltDNSNamesWithArg(t *testing.T) {
 func init() {
 	os.Args = os.Args[:1]
 }
+
+// TestParseMessageLimit ensures message limits are parsed as intended and that
+// invalid limits are rejected.
+func TestParseMessageLimit(t *testing.T) {
+	tests := []struct {
+		name      string            // test description
+		limit     string            // limit to parse
+		wantCmd   string            // expected command
+		wantLimit peer.MessageLimit // expected parsed limit
+		wantErr   bool              // whether or not an error is expected
+	}{{
+		name:      "max bytes only",
+		limit:     "tx:100000",
+		wantCmd:   "tx",
+		wantLimit: peer.MessageLimit{MaxPayload: 100000},
+	}, {
+		name:      "max bytes and rate",
+		limit:     "getaddr:0:0.5",
+		wantCmd:   "getaddr",
+		wantLimit: peer.MessageLimit{MaxRate: 0.5},
+	}, {
+		name:      "max bytes, rate, and burst",
+		limit:     "inv:50000:10:20",
+		wantCmd:   "inv",
+		wantLimit: peer.MessageLimit{MaxPayload: 50000, MaxRate: 10, Burst: 20},
+	}, {
+		name:    "missing max bytes",
+		limit:   "tx",
+		wantErr: true,
+	}, {
+		name:    "too many fields",
+		limit:   "tx:1:2:3:4",
+		wantErr: true,
+	}, {
+		name:    "empty command",
+		limit:   ":1000",
+		wantErr: true,
+	}, {
+		name:    "command too long",
+		limit:   "thiscommandistoolong:1000",
+		wantErr: true,
+	}, {
+		name:    "max bytes overflow",
+		limit:   "tx:4294967296",
+		wantErr: true,
+	}, {
+		name:    "negative rate",
+		limit:   "tx:0:-1",
+		wantErr: true,
+	}, {
+		name:    "infinite rate",
+		limit:   "tx:0:+Inf",
+		wantErr: true,
+	}, {
+		name:    "invalid burst",
+		limit:   "tx:0:1:-1",
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		cmd, limit, err := parseMessageLimit(test.limit)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%q: unexpected error -- got %v, want error %v",
+				test.name, err, test.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if cmd != test.wantCmd || limit != test.wantLimit {
+			t.Errorf("%q: unexpected result -- got %s %+v, want %s %+v",
+				test.name, cmd, limit, test.wantCmd, test.wantLimit)
+		}
+	}
+}
+
+// TestParseTxTypePolicy ensures transaction type policies are parsed as
+// intended and that invalid policies are rejected.
+func TestParseTxTypePolicy(t *testing.T) {
+	tests := []struct {
+		name       string               // test description
+		policy     string               // policy to parse
+		wantType   stake.TxType         // expected transaction type
+		wantPolicy mempool.TxTypePolicy // expected parsed policy
+		wantErr    bool                 // whether or not an error is expected
+	}{{
+		name:       "reject tickets",
+		policy:     "ticket:reject",
+		wantType:   stake.TxTypeSStx,
+		wantPolicy: mempool.TxTypePolicyReject,
+	}, {
+		name:       "non-standard votes",
+		policy:     "vote:nonstd",
+		wantType:   stake.TxTypeSSGen,
+		wantPolicy: mempool.TxTypePolicyNonStandard,
+	}, {
+		name:       "standard revocations",
+		policy:     "revocation:std",
+		wantType:   stake.TxTypeSSRtx,
+		wantPolicy: mempool.TxTypePolicyStandard,
+	}, {
+		name:       "standard regular transactions",
+		policy:     "regular:std",
+		wantType:   stake.TxTypeRegular,
+		wantPolicy: mempool.TxTypePolicyStandard,
+	}, {
+		name:    "missing policy",
+		policy:  "ticket",
+		wantErr: true,
+	}, {
+		name:    "too many fields",
+		policy:  "ticket:reject:1",
+		wantErr: true,
+	}, {
+		name:    "unknown type",
+		policy:  "tspend:reject",
+		wantErr: true,
+	}, {
+		name:    "unknown policy",
+		policy:  "ticket:allow",
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		txType, policy, err := parseTxTypePolicy(test.policy)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%q: unexpected error -- got %v, want error %v",
+				test.name, err, test.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if txType != test.wantType || policy != test.wantPolicy {
+			t.Errorf("%q: unexpected result -- got %v %v, want %v %v",
+				test.name, txType, policy, test.wantType, test.wantPolicy)
+		}
+	}
+}
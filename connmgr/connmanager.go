@@ -72,12 +72,26 @@ type ConnReq struct {
 	//
 	// conn is the underlying network connection.  It will be nil before a
 	// connection has been established.
+	//
+	// family is the address family of the connection.  For automatic
+	// connection requests that have not been established yet, it is the
+	// family the request is reserved for in order to reach the per-family
+	// outbound targets and is set prior to obtaining the address.
 	retryCount uint32
 	conn       net.Conn
+	family     AddressFamily
 
-	// Addr is the address to connect to.
+	// Addr is the address to connect to.  When fallback addresses are
+	// provided, it is updated to the address the connection was actually
+	// established to once the connection succeeds.
 	Addr net.Addr
 
+	// FallbackAddrs are additional addresses for the same peer, such as the
+	// other addresses a host name resolves to, that are raced against Addr
+	// using the dual-stack Happy Eyeballs algorithm described by RFC 8305.
+	// It may be nil when the peer only has a single address.
+	FallbackAddrs []net.Addr
+
 	// Permanent specifies whether or not the connection request represents what
 	// should be treated as a permanent connection, meaning the connection
 	// manager will try to always maintain the connection including retries with
@@ -137,6 +151,31 @@ type Config struct {
 	// maintain. Defaults to 8.
 	TargetOutbound uint32
 
+	// TargetOutboundIPv4 and TargetOutboundIPv6 are the minimum number of
+	// the outbound network connections to maintain to IPv4 and IPv6
+	// addresses, respectively.  They count toward TargetOutbound, so their
+	// sum may not exceed it.  Automatic connection requests are reserved
+	// for a family until its target is reached and the reserved family is
+	// passed to GetNewAddressFamily.  Both default to 0.
+	TargetOutboundIPv4 uint32
+	TargetOutboundIPv6 uint32
+
+	// IPv6Only restricts outbound network connections to IPv6 addresses
+	// and addresses that are not IP addresses, such as onion addresses.
+	// Connection requests to IPv4 addresses fail.
+	IPv6Only bool
+
+	// PreferredFamily is the address family attempted first when racing
+	// connection attempts to the addresses of a peer that has fallback
+	// addresses.  Defaults to IPv6Family as recommended by RFC 8305.
+	PreferredFamily AddressFamily
+
+	// FallbackDelay is the amount of time to wait for a connection attempt
+	// to an address of a peer that has fallback addresses to complete
+	// before racing a connection attempt to its next address.  Defaults to
+	// 300ms.
+	FallbackDelay time.Duration
+
 	// RetryDuration is the duration to wait before retrying connection
 	// requests. Defaults to 5s.
 	RetryDuration time.Duration
@@ -150,9 +189,18 @@ type Config struct {
 	OnDisconnection func(*ConnReq)
 
 	// GetNewAddress is a way to get an address to make a network connection
-	// to.  If nil, no new connections will be made automatically.
+	// to.  If both it and GetNewAddressFamily are nil, no new connections
+	// will be made automatically.
 	GetNewAddress func() (net.Addr, error)
 
+	// GetNewAddressFamily is an alternative to GetNewAddress which receives
+	// the address family the returned address should be from in order to
+	// reach the per-family outbound targets.  It is AnyFamily when any
+	// family is acceptable, except in IPv6-only mode where it is always
+	// IPv6Family.  Either GetNewAddress or GetNewAddressFamily may be
+	// specified (but not both).
+	GetNewAddressFamily func(AddressFamily) (net.Addr, error)
+
 	// Dial connects to the address on the named network. Either Dial or
 	// DialAddr need to be specified (but not both).
 	Dial func(ctx context.Context, network, addr string) (net.Conn, error)
@@ -173,13 +221,16 @@ type Config struct {
 // longer wanted.
 type registerPending struct {
 	c    *ConnReq
+	auto bool
 	done chan struct{}
 }
 
-// handleConnected is used to queue a successful connection.
+// handleConnected is used to queue a successful connection along with the
+// address it was established to.
 type handleConnected struct {
 	c    *ConnReq
 	conn net.Conn
+	addr net.Addr
 }
 
 // handleDisconnected is used to remove a connection.
@@ -257,7 +308,7 @@ func (cm *ConnManager) handleFailedConn(ctx context.Context, c *ConnReq) {
 			case <-cm.quit:
 			}
 		}()
-	} else if cm.cfg.GetNewAddress != nil {
+	} else if cm.automaticConns() {
 		cm.failedAttempts++
 		if cm.failedAttempts >= maxFailedAttempts {
 			log.Debugf("Max failed connection attempts reached: [%d] "+
@@ -300,6 +351,11 @@ out:
 			case registerPending:
 				connReq := msg.c
 				connReq.updateState(ConnPending)
+				if msg.auto {
+					connReq.family = cm.reservedFamily(conns, pending)
+				} else if connReq.Addr != nil {
+					connReq.family = AddrFamily(connReq.Addr)
+				}
 				pending[msg.c.id] = connReq
 				close(msg.done)
 
@@ -314,8 +370,22 @@ out:
 					continue
 				}
 
+				// Update the address to the one the connection
+				// was established to when it differs due to
+				// racing the fallback addresses.
+				if msg.addr != connReq.Addr {
+					for i, addr := range connReq.FallbackAddrs {
+						if addr == msg.addr {
+							connReq.FallbackAddrs[i] = connReq.Addr
+							break
+						}
+					}
+					connReq.Addr = msg.addr
+				}
+
 				connReq.updateState(ConnEstablished)
 				connReq.conn = msg.conn
+				connReq.family = AddrFamily(msg.addr)
 				conns[connReq.id] = connReq
 				log.Debugf("Connected to %v", connReq)
 				connReq.retryCount = 0
@@ -430,6 +500,53 @@ out:
 	log.Trace("Connection handler done")
 }
 
+// automaticConns returns whether or not new connections are made automatically
+// to reach the target number of outbound connections.
+func (cm *ConnManager) automaticConns() bool {
+	return cm.cfg.GetNewAddress != nil || cm.cfg.GetNewAddressFamily != nil
+}
+
+// reservedFamily returns the address family a new automatic connection request
+// should be reserved for in order to reach the per-family outbound targets
+// given the provided established and pending connection requests.
+//
+// This function MUST only be called from the connection handler.
+func (cm *ConnManager) reservedFamily(conns, pending map[uint64]*ConnReq) AddressFamily {
+	var numIPv4, numIPv6 uint32
+	countFamilies := func(reqs map[uint64]*ConnReq) {
+		for _, req := range reqs {
+			switch req.family {
+			case IPv4Family:
+				numIPv4++
+			case IPv6Family:
+				numIPv6++
+			}
+		}
+	}
+	countFamilies(conns)
+	countFamilies(pending)
+
+	switch {
+	case numIPv6 < cm.cfg.TargetOutboundIPv6:
+		return IPv6Family
+	case cm.cfg.IPv6Only:
+		return IPv6Family
+	case numIPv4 < cm.cfg.TargetOutboundIPv4:
+		return IPv4Family
+	}
+	return AnyFamily
+}
+
+// getNewAddress returns a new address to connect to from the provided address
+// family using the function configured when initially creating the connection
+// manager.
+func (cm *ConnManager) getNewAddress(family AddressFamily) (net.Addr, error) {
+	if cm.cfg.GetNewAddressFamily != nil {
+		return cm.cfg.GetNewAddressFamily(family)
+	}
+	return cm.cfg.GetNewAddress()
+}
+
 // newConnReq creates a new connection request and connects to the
 // corresponding address.
 func (cm *ConnManager) newConnReq(ctx context.Context) {
@@ -447,20 +564,21 @@ func (cm *ConnManager) newConnReq(ctx context.Context) {
 	// Remove method.
 	done := make(chan struct{})
 	select {
-	case cm.requests <- registerPending{c, done}:
+	case cm.requests <- registerPending{c, true, done}:
 	case <-cm.quit:
 		return
 	}
 
 	// Wait for the registration to successfully add the pending conn req to
-	// the conn manager's internal state.
+	// the conn manager's internal state.  The family the request is reserved
+	// for is set by the time it completes.
 	select {
 	case <-done:
 	case <-cm.quit:
 		return
 	}
 
-	addr, err := cm.cfg.GetNewAddress()
+	addr, err := cm.getNewAddress(c.family)
 	if err != nil {
 		select {
 		case cm.requests <- handleFailed{c, err}:
@@ -512,7 +630,7 @@ func (cm *ConnManager) Connect(ctx context.Context, c *ConnReq) {
 		// cancel the connection via the Remove method.
 		done := make(chan struct{})
 		select {
-		case cm.requests <- registerPending{c, done}:
+		case cm.requests <- registerPending{c, false, done}:
 		case <-cm.quit:
 			return
 		}
@@ -528,17 +646,38 @@ func (cm *ConnManager) Connect(ctx context.Context, c *ConnReq) {
 
 	log.Debugf("Attempting to connect to %v", c)
 
+	// Determine the addresses that may be connected to.  IPv4 addresses are
+	// not allowed in IPv6-only mode.
+	addrs := make([]net.Addr, 0, len(c.FallbackAddrs)+1)
+	for _, addr := range append([]net.Addr{c.Addr}, c.FallbackAddrs...) {
+		if cm.cfg.IPv6Only && AddrFamily(addr) == IPv4Family {
+			continue
+		}
+		addrs = append(addrs, addr)
+	}
+	if len(addrs) == 0 {
+		str := fmt.Sprintf("no IPv6 addresses for %v in IPv6-only mode", c)
+		err := MakeError(ErrAddrFamilyNotAllowed, str)
+		select {
+		case cm.requests <- handleFailed{c, err}:
+		case <-cm.quit:
+		}
+		return
+	}
+
 	if cm.cfg.Timeout != 0 {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(ctx, cm.cfg.Timeout)
 		defer cancel()
 	}
 	var conn net.Conn
+	var addr net.Addr
 	var err error
-	if cm.cfg.Dial != nil {
-		conn, err = cm.cfg.Dial(ctx, c.Addr.Network(), c.Addr.String())
+	if len(addrs) == 1 {
+		addr = addrs[0]
+		conn, err = cm.dial(ctx, addr)
 	} else {
-		conn, err = cm.cfg.DialAddr(ctx, c.Addr)
+		conn, addr, err = cm.dialHappyEyeballs(ctx, addrs)
 	}
 	if err != nil {
 		select {
@@ -549,7 +688,7 @@ func (cm *ConnManager) Connect(ctx context.Context, c *ConnReq) {
 	}
 
 	select {
-	case cm.requests <- handleConnected{c, conn}:
+	case cm.requests <- handleConnected{c, conn, addr}:
 	case <-cm.quit:
 	}
 }
@@ -641,7 +780,7 @@ func (cm *ConnManager) Run(ctx context.Context) {
 
 	// Start enough outbound connections to reach the target number when not
 	// in manual connect mode.
-	if cm.cfg.GetNewAddress != nil {
+	if cm.automaticConns() {
 		curConnReqCount := atomic.LoadUint64(&cm.connReqCount)
 		for i := curConnReqCount; i < uint64(cm.cfg.TargetOutbound); i++ {
 			go cm.newConnReq(ctx)
@@ -680,6 +819,10 @@ func New(cfg *Config) (*ConnManager, error) {
 		return nil, MakeError(ErrBothDialsFilled,
 			"cannot specify both Dial and DialAddr")
 	}
+	if cfg.GetNewAddress != nil && cfg.GetNewAddressFamily != nil {
+		return nil, MakeError(ErrBothGetNewAddressFilled,
+			"cannot specify both GetNewAddress and GetNewAddressFamily")
+	}
 	// Default to sane values
 	if cfg.RetryDuration <= 0 {
 		cfg.RetryDuration = defaultRetryDuration
@@ -687,6 +830,23 @@ func New(cfg *Config) (*ConnManager, error) {
 	if cfg.TargetOutbound == 0 {
 		cfg.TargetOutbound = defaultTargetOutbound
 	}
+	if cfg.PreferredFamily == AnyFamily {
+		cfg.PreferredFamily = IPv6Family
+	}
+	if cfg.FallbackDelay <= 0 {
+		cfg.FallbackDelay = defaultFallbackDelay
+	}
+	if cfg.TargetOutboundIPv4+cfg.TargetOutboundIPv6 > cfg.TargetOutbound {
+		str := fmt.Sprintf("the per-family outbound targets (IPv4: %d, "+
+			"IPv6: %d) exceed the target number of outbound connections "+
+			"(%d)", cfg.TargetOutboundIPv4, cfg.TargetOutboundIPv6,
+			cfg.TargetOutbound)
+		return nil, MakeError(ErrInvalidFamilyTargets, str)
+	}
+	if cfg.IPv6Only && cfg.TargetOutboundIPv4 != 0 {
+		return nil, MakeError(ErrInvalidFamilyTargets,
+			"cannot specify an IPv4 outbound target in IPv6-only mode")
+	}
 	cm := ConnManager{
 		cfg:      *cfg, // Copy so caller can't mutate
 		requests: make(chan interface{}),
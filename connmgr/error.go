@@ -18,6 +18,18 @@ const (
 	// cannot both be specified in the configuration.
 	ErrBothDialsFilled = ErrorKind("ErrBothDialsFilled")
 
+	// ErrBothGetNewAddressFilled is used to indicate that GetNewAddress and
+	// GetNewAddressFamily cannot both be specified in the configuration.
+	ErrBothGetNewAddressFilled = ErrorKind("ErrBothGetNewAddressFilled")
+
+	// ErrInvalidFamilyTargets is used to indicate that the per-family
+	// outbound connection targets in the configuration are invalid.
+	ErrInvalidFamilyTargets = ErrorKind("ErrInvalidFamilyTargets")
+
+	// ErrAddrFamilyNotAllowed is used to indicate a connection request
+	// does not have any addresses from an allowed address family.
+	ErrAddrFamilyNotAllowed = ErrorKind("ErrAddrFamilyNotAllowed")
+
 	// ErrTorInvalidAddressResponse indicates an invalid address was
 	// returned by the Tor DNS resolver.
 	ErrTorInvalidAddressResponse = ErrorKind("ErrTorInvalidAddressResponse")
@@ -18,6 +18,9 @@ func TestErrorKindStringer(t *testing.T) {
 	}{
 		{ErrDialNil, "ErrDialNil"},
 		{ErrBothDialsFilled, "ErrBothDialsFilled"},
+		{ErrBothGetNewAddressFilled, "ErrBothGetNewAddressFilled"},
+		{ErrInvalidFamilyTargets, "ErrInvalidFamilyTargets"},
+		{ErrAddrFamilyNotAllowed, "ErrAddrFamilyNotAllowed"},
 		{ErrTorInvalidAddressResponse, "ErrTorInvalidAddressResponse"},
 		{ErrTorInvalidProxyResponse, "ErrTorInvalidProxyResponse"},
 		{ErrTorUnrecognizedAuthMethod, "ErrTorUnrecognizedAuthMethod"},
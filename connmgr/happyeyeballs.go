@@ -0,0 +1,198 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package connmgr
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// defaultFallbackDelay is the default amount of time to wait for a connection
+// attempt to an address to complete before racing a connection attempt to the
+// next address of the same peer.  This is the same delay used by the standard
+// library for dual-stack dialing.
+const defaultFallbackDelay = 300 * time.Millisecond
+
+// AddressFamily identifies the IP address family of a network address.
+type AddressFamily uint8
+
+const (
+	// AnyFamily is used for network addresses that are not IP addresses,
+	// such as onion addresses, and to indicate any family is acceptable.
+	AnyFamily AddressFamily = iota
+
+	// IPv4Family identifies IPv4 network addresses.
+	IPv4Family
+
+	// IPv6Family identifies IPv6 network addresses.
+	IPv6Family
+)
+
+// String returns the address family as a human-readable string.
+func (f AddressFamily) String() string {
+	switch f {
+	case IPv4Family:
+		return "IPv4"
+	case IPv6Family:
+		return "IPv6"
+	}
+	return "any"
+}
+
+// AddrFamily returns the IP address family of the provided network address.
+// AnyFamily is returned for addresses that are not TCP or UDP addresses.
+// IPv4-mapped IPv6 addresses are treated as IPv4.
+func AddrFamily(addr net.Addr) AddressFamily {
+	var ip net.IP
+	switch addr := addr.(type) {
+	case *net.TCPAddr:
+		ip = addr.IP
+	case *net.UDPAddr:
+		ip = addr.IP
+	}
+	switch {
+	case ip == nil:
+		return AnyFamily
+	case ip.To4() != nil:
+		return IPv4Family
+	}
+	return IPv6Family
+}
+
+// interleaveAddrs returns the provided addresses reordered so the families
+// alternate starting with the first address of the preferred family as
+// described by RFC 8305.  The relative order of addresses within each family
+// is preserved and addresses that are not IP addresses are placed last.
+func interleaveAddrs(addrs []net.Addr, preferred AddressFamily) []net.Addr {
+	other := IPv4Family
+	if preferred == IPv4Family {
+		other = IPv6Family
+	}
+
+	var first, second, rest []net.Addr
+	for _, addr := range addrs {
+		switch AddrFamily(addr) {
+		case preferred:
+			first = append(first, addr)
+		case other:
+			second = append(second, addr)
+		default:
+			rest = append(rest, addr)
+		}
+	}
+
+	result := make([]net.Addr, 0, len(addrs))
+	for len(first) > 0 || len(second) > 0 {
+		if len(first) > 0 {
+			result = append(result, first[0])
+			first = first[1:]
+		}
+		if len(second) > 0 {
+			result = append(result, second[0])
+			second = second[1:]
+		}
+	}
+	return append(result, rest...)
+}
+
+// dialResult houses the result of a connection attempt to an address raced by
+// dialHappyEyeballs.
+type dialResult struct {
+	conn net.Conn
+	addr net.Addr
+	err  error
+}
+
+// dial connects to the provided address using the dial function configured
+// when creating the connection manager.
+func (cm *ConnManager) dial(ctx context.Context, addr net.Addr) (net.Conn, error) {
+	if cm.cfg.Dial != nil {
+		return cm.cfg.Dial(ctx, addr.Network(), addr.String())
+	}
+	return cm.cfg.DialAddr(ctx, addr)
+}
+
+// dialHappyEyeballs races connection attempts to the provided addresses of the
+// same peer as described by RFC 8305 (Happy Eyeballs) and returns the first
+// connection that is established along with the address it was established to.
+//
+// The addresses are attempted in an order that alternates between the families
+// starting with the preferred family.  A connection attempt to the next address
+// is started as soon as the previous attempt fails or the configured fallback
+// delay elapses without it completing, whichever happens first.  The remaining
+// attempts are canceled and any connections they establish afterwards are
+// closed once a connection is established.
+//
+// The error from the final failed attempt is returned when all of them fail.
+func (cm *ConnManager) dialHappyEyeballs(ctx context.Context, addrs []net.Addr) (net.Conn, net.Addr, error) {
+	addrs = interleaveAddrs(addrs, cm.cfg.PreferredFamily)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// The results channel is buffered so attempts that complete after a
+	// winner is selected do not block.
+	results := make(chan dialResult, len(addrs))
+	startAttempt := func(addr net.Addr) {
+		go func() {
+			conn, err := cm.dial(ctx, addr)
+			results <- dialResult{conn, addr, err}
+		}()
+	}
+
+	fallbackTimer := time.NewTimer(cm.cfg.FallbackDelay)
+	defer fallbackTimer.Stop()
+
+	var next, inFlight int
+	var lastErr error
+	startNext := true
+	for {
+		// Start the next attempt when the previous attempt failed or the
+		// fallback delay for it elapsed.
+		if startNext && next < len(addrs) {
+			startAttempt(addrs[next])
+			next++
+			inFlight++
+			if !fallbackTimer.Stop() {
+				select {
+				case <-fallbackTimer.C:
+				default:
+				}
+			}
+			fallbackTimer.Reset(cm.cfg.FallbackDelay)
+		}
+		startNext = false
+		if inFlight == 0 {
+			return nil, nil, lastErr
+		}
+
+		select {
+		case result := <-results:
+			inFlight--
+			if result.err != nil {
+				log.Debugf("Failed to connect to %v: %v", result.addr,
+					result.err)
+				lastErr = result.err
+				startNext = true
+				continue
+			}
+
+			// Close any connections established by the remaining
+			// attempts once they complete.
+			go func(remaining int) {
+				for i := 0; i < remaining; i++ {
+					if result := <-results; result.conn != nil {
+						result.conn.Close()
+					}
+				}
+			}(inFlight)
+			return result.conn, result.addr, nil
+
+		case <-fallbackTimer.C:
+			startNext = true
+		}
+	}
+}
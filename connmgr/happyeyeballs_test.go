@@ -0,0 +1,366 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package connmgr
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// tcpAddr returns a TCP address for the provided IP address string and port.
+func tcpAddr(ip string, port int) *net.TCPAddr {
+	return &net.TCPAddr{IP: net.ParseIP(ip), Port: port}
+}
+
+// TestAddrFamily ensures the address family of network addresses is identified
+// as expected.
+func TestAddrFamily(t *testing.T) {
+	tests := []struct {
+		name string
+		addr net.Addr
+		want AddressFamily
+	}{{
+		name: "IPv4 TCP",
+		addr: tcpAddr("127.0.0.1", 9108),
+		want: IPv4Family,
+	}, {
+		name: "IPv4-mapped IPv6 TCP",
+		addr: tcpAddr("::ffff:127.0.0.1", 9108),
+		want: IPv4Family,
+	}, {
+		name: "IPv6 TCP",
+		addr: tcpAddr("2001:db8::1", 9108),
+		want: IPv6Family,
+	}, {
+		name: "IPv6 UDP",
+		addr: &net.UDPAddr{IP: net.ParseIP("::1"), Port: 9108},
+		want: IPv6Family,
+	}, {
+		name: "onion",
+		addr: &mockAddr{"tcp", "aaaaaaaaaaaaaaaa.onion:9108"},
+		want: AnyFamily,
+	}}
+
+	for _, test := range tests {
+		if got := AddrFamily(test.addr); got != test.want {
+			t.Errorf("%q: unexpected family -- got %v, want %v", test.name,
+				got, test.want)
+		}
+	}
+}
+
+// TestInterleaveAddrs ensures addresses are reordered to alternate between the
+// families starting with the preferred family.
+func TestInterleaveAddrs(t *testing.T) {
+	v4a := tcpAddr("10.0.0.1", 9108)
+	v4b := tcpAddr("10.0.0.2", 9108)
+	v4c := tcpAddr("10.0.0.3", 9108)
+	v6a := tcpAddr("2001:db8::1", 9108)
+	v6b := tcpAddr("2001:db8::2", 9108)
+	onion := &mockAddr{"tcp", "aaaaaaaaaaaaaaaa.onion:9108"}
+
+	tests := []struct {
+		name      string
+		addrs     []net.Addr
+		preferred AddressFamily
+		want      []net.Addr
+	}{{
+		name:      "prefer IPv6",
+		addrs:     []net.Addr{v4a, v4b, v4c, v6a, v6b},
+		preferred: IPv6Family,
+		want:      []net.Addr{v6a, v4a, v6b, v4b, v4c},
+	}, {
+		name:      "prefer IPv4",
+		addrs:     []net.Addr{v6a, v6b, v4a, v4b},
+		preferred: IPv4Family,
+		want:      []net.Addr{v4a, v6a, v4b, v6b},
+	}, {
+		name:      "single family",
+		addrs:     []net.Addr{v4a, v4b},
+		preferred: IPv6Family,
+		want:      []net.Addr{v4a, v4b},
+	}, {
+		name:      "non-IP addresses last",
+		addrs:     []net.Addr{onion, v4a, v6a},
+		preferred: IPv6Family,
+		want:      []net.Addr{v6a, v4a, onion},
+	}}
+
+	for _, test := range tests {
+		got := interleaveAddrs(test.addrs, test.preferred)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: unexpected order -- got %v, want %v", test.name,
+				got, test.want)
+		}
+	}
+}
+
+// TestHappyEyeballs ensures connection requests with fallback addresses race
+// the addresses as expected and are updated to the address the connection was
+// established to.
+func TestHappyEyeballs(t *testing.T) {
+	v4 := tcpAddr("10.0.0.1", 9108)
+	v6a := tcpAddr("2001:db8::1", 9108)
+	v6b := tcpAddr("2001:db8::2", 9108)
+
+	tests := []struct {
+		name     string
+		hangs    map[string]bool // Dials that never complete.
+		fails    map[string]bool // Dials that fail immediately.
+		ipv6Only bool
+		want     net.Addr
+	}{{
+		name: "preferred family connects first",
+		want: v6a,
+	}, {
+		name:  "fallback after preferred family hangs",
+		hangs: map[string]bool{v6a.String(): true},
+		want:  v4,
+	}, {
+		name:  "fallback after preferred family fails",
+		fails: map[string]bool{v6a.String(): true},
+		want:  v4,
+	}, {
+		name: "last address after others fail",
+		fails: map[string]bool{
+			v6a.String(): true,
+			v4.String():  true,
+		},
+		want: v6b,
+	}, {
+		name:     "IPv6-only skips IPv4",
+		fails:    map[string]bool{v6a.String(): true},
+		ipv6Only: true,
+		want:     v6b,
+	}}
+
+	for _, test := range tests {
+		var mtx sync.Mutex
+		var dialed []string
+		dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+			mtx.Lock()
+			dialed = append(dialed, addr)
+			mtx.Unlock()
+			switch {
+			case test.hangs[addr]:
+				<-ctx.Done()
+				return nil, ctx.Err()
+			case test.fails[addr]:
+				return nil, errors.New("connection refused")
+			}
+			return mockDialer(ctx, network, addr)
+		}
+
+		connected := make(chan *ConnReq)
+		cmgr, err := New(&Config{
+			Dial:          dial,
+			IPv6Only:      test.ipv6Only,
+			FallbackDelay: time.Millisecond * 5,
+			OnConnection: func(c *ConnReq, conn net.Conn) {
+				connected <- c
+			},
+		})
+		if err != nil {
+			t.Fatalf("%q: New error: %v", test.name, err)
+		}
+		ctx, shutdown, wg := runConnMgrAsync(context.Background(), cmgr)
+
+		cr := &ConnReq{
+			Addr:          v4,
+			FallbackAddrs: []net.Addr{v6a, v6b},
+		}
+		go cmgr.Connect(ctx, cr)
+
+		select {
+		case c := <-connected:
+			if c.Addr != test.want {
+				t.Errorf("%q: unexpected address -- got %v, want %v",
+					test.name, c.Addr, test.want)
+			}
+			if len(c.FallbackAddrs) != 2 {
+				t.Errorf("%q: unexpected fallback addresses %v",
+					test.name, c.FallbackAddrs)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%q: connection timeout", test.name)
+		}
+
+		mtx.Lock()
+		for _, addr := range dialed {
+			if test.ipv6Only && addr == v4.String() {
+				t.Errorf("%q: dialed IPv4 address in IPv6-only mode",
+					test.name)
+			}
+		}
+		mtx.Unlock()
+
+		shutdown()
+		wg.Wait()
+	}
+}
+
+// TestIPv6OnlyRejectsIPv4 ensures connection requests without any IPv6
+// addresses fail in IPv6-only mode without being dialed.
+func TestIPv6OnlyRejectsIPv4(t *testing.T) {
+	dialed := make(chan struct{}, 1)
+	cmgr, err := New(&Config{
+		IPv6Only: true,
+		Dial: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			dialed <- struct{}{}
+			return mockDialer(ctx, network, addr)
+		},
+	})
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	ctx, shutdown, wg := runConnMgrAsync(context.Background(), cmgr)
+
+	cr := &ConnReq{Addr: tcpAddr("10.0.0.1", 9108)}
+	cmgr.Connect(ctx, cr)
+
+	// The failure is processed asynchronously by the connection handler.
+	deadline := time.Now().Add(time.Second)
+	for cr.State() != ConnFailed && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	assertConnReqState(t, cr, ConnFailed)
+	select {
+	case <-dialed:
+		t.Fatal("dialed IPv4 address in IPv6-only mode")
+	default:
+	}
+
+	shutdown()
+	wg.Wait()
+}
+
+// TestFamilyTargets ensures automatic connection requests are reserved for the
+// families needed to reach the per-family outbound targets.
+func TestFamilyTargets(t *testing.T) {
+	const targetOutbound, targetIPv4, targetIPv6 = 5, 1, 2
+
+	var mtx sync.Mutex
+	requested := make(map[AddressFamily]int)
+	var nextID int
+	getNewAddress := func(family AddressFamily) (net.Addr, error) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		requested[family]++
+		nextID++
+		switch family {
+		case IPv6Family:
+			return tcpAddr("2001:db8::1", nextID), nil
+		}
+		return tcpAddr("10.0.0.1", nextID), nil
+	}
+
+	connected := make(chan *ConnReq)
+	cmgr, err := New(&Config{
+		TargetOutbound:      targetOutbound,
+		TargetOutboundIPv4:  targetIPv4,
+		TargetOutboundIPv6:  targetIPv6,
+		Dial:                mockDialer,
+		GetNewAddressFamily: getNewAddress,
+		OnConnection: func(c *ConnReq, conn net.Conn) {
+			connected <- c
+		},
+	})
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	_, shutdown, wg := runConnMgrAsync(context.Background(), cmgr)
+
+	families := make(map[AddressFamily]int)
+	for i := 0; i < targetOutbound; i++ {
+		select {
+		case c := <-connected:
+			families[AddrFamily(c.Addr)]++
+		case <-time.After(time.Second):
+			t.Fatalf("connection timeout after %d connections", i)
+		}
+	}
+	if families[IPv6Family] < targetIPv6 {
+		t.Errorf("unexpected number of IPv6 connections -- got %d, want "+
+			"at least %d", families[IPv6Family], targetIPv6)
+	}
+	if families[IPv4Family] < targetIPv4 {
+		t.Errorf("unexpected number of IPv4 connections -- got %d, want "+
+			"at least %d", families[IPv4Family], targetIPv4)
+	}
+	mtx.Lock()
+	if requested[IPv6Family] != targetIPv6 {
+		t.Errorf("unexpected number of IPv6 address requests -- got %d, "+
+			"want %d", requested[IPv6Family], targetIPv6)
+	}
+	if requested[IPv4Family] != targetIPv4 {
+		t.Errorf("unexpected number of IPv4 address requests -- got %d, "+
+			"want %d", requested[IPv4Family], targetIPv4)
+	}
+	mtx.Unlock()
+
+	shutdown()
+	wg.Wait()
+}
+
+// TestFamilyConfig ensures the address family related configuration options
+// are validated as expected.
+func TestFamilyConfig(t *testing.T) {
+	getNewAddress := func() (net.Addr, error) { return nil, nil }
+	getNewAddressFamily := func(AddressFamily) (net.Addr, error) {
+		return nil, nil
+	}
+
+	tests := []struct {
+		name string
+		cfg  Config
+		want error
+	}{{
+		name: "both address funcs",
+		cfg: Config{
+			Dial:                mockDialer,
+			GetNewAddress:       getNewAddress,
+			GetNewAddressFamily: getNewAddressFamily,
+		},
+		want: ErrBothGetNewAddressFilled,
+	}, {
+		name: "family targets exceed target outbound",
+		cfg: Config{
+			Dial:               mockDialer,
+			TargetOutbound:     4,
+			TargetOutboundIPv4: 2,
+			TargetOutboundIPv6: 3,
+		},
+		want: ErrInvalidFamilyTargets,
+	}, {
+		name: "IPv4 target in IPv6-only mode",
+		cfg: Config{
+			Dial:               mockDialer,
+			IPv6Only:           true,
+			TargetOutboundIPv4: 1,
+		},
+		want: ErrInvalidFamilyTargets,
+	}, {
+		name: "valid family targets",
+		cfg: Config{
+			Dial:               mockDialer,
+			TargetOutbound:     8,
+			TargetOutboundIPv4: 2,
+			TargetOutboundIPv6: 6,
+		},
+	}}
+
+	for _, test := range tests {
+		_, err := New(&test.cfg)
+		if !errors.Is(err, test.want) {
+			t.Errorf("%q: unexpected error -- got %v, want %v", test.name,
+				err, test.want)
+		}
+	}
+}
@@ -0,0 +1,321 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/dcrjson/v3"
+	"github.com/decred/dcrd/internal/rpcserver"
+	"github.com/decred/dcrd/rpc/jsonrpc/types/v2"
+)
+
+// coordHeartbeatsPerLease is the number of heartbeats sent to the peer node of a
+// redundancy pair during each lease so that a few of them may be lost before
+// the standby node promotes itself.
+const coordHeartbeatsPerLease = 3
+
+// errCoordSameNodeID is returned when a heartbeat is received from a peer node
+// that claims the same node id as the local node.
+var errCoordSameNodeID = errors.New("peer has the same node id as the " +
+	"local node")
+
+// coordinator implements a lease-based leader election between the two nodes
+// of a redundancy pair that share a virtual IP address so that only one of them
+// actively serves miners and websocket clients while the other one remains a
+// hot standby.
+//
+// The nodes periodically exchange heartbeats with their election state over
+// their RPC link via the coordheartbeat RPC.  Every time a node promotes itself
+// to leader it does so in a new term, and the following rules are applied to
+// the state received from the peer:
+//
+//   - A node adopts a higher term of its peer and steps down when it was the
+//     leader
+//   - When both nodes claim to be the leader of the same term, the node with the
+//     lower node id wins
+//   - When neither node is the leader of the same term, the node with the lower
+//     node id promotes itself in a new term
+//   - The standby node promotes itself in a new term once it has not heard from
+//     its peer for the duration of the lease
+//
+// Both nodes start out as the standby so a node that is restarted does not
+// interrupt the leader.  Note that since there are only two nodes, a failure
+// of the link between them while both are running results in both of them
+// becoming the leader until the link is restored.
+type coordinator struct {
+	nodeID string
+	lease  time.Duration
+
+	// sendHeartbeat sends a heartbeat with the provided election state to
+	// the peer node and returns the election state of the peer.  It is a
+	// field so it can be mocked by tests.
+	sendHeartbeat func(ctx context.Context, state *rpcserver.CoordinationState) (*rpcserver.CoordinationState, error)
+
+	// notify is invoked whenever the local node becomes or stops being the
+	// leader.  It may be nil.
+	notify func(leader bool)
+
+	// now returns the current time.  It is a field so it can be mocked by
+	// tests.
+	now func() time.Time
+
+	mtx          sync.Mutex
+	term         uint64
+	leader       bool
+	lastPeerSeen time.Time
+}
+
+// newCoordinator returns a coordinator for the local node with the provided
+// node id that exchanges heartbeats with the peer node at the provided RPC
+// address using the provided RPC credentials.  The peer's RPC server
+// certificate is verified against the provided certificate file unless TLS is
+// disabled.
+func newCoordinator(nodeID string, lease time.Duration, peerAddr, user, pass, certFile string, disableTLS bool) (*coordinator, error) {
+	scheme := "https"
+	transport := &http.Transport{}
+	if disableTLS {
+		scheme = "http"
+	} else {
+		pem, err := ioutil.ReadFile(certFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", certFile)
+		}
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   lease / coordHeartbeatsPerLease,
+	}
+	url := scheme + "://" + peerAddr
+
+	c := &coordinator{
+		nodeID: nodeID,
+		lease:  lease,
+		now:    time.Now,
+	}
+	c.sendHeartbeat = func(ctx context.Context, state *rpcserver.CoordinationState) (*rpcserver.CoordinationState, error) {
+		return postCoordHeartbeat(ctx, client, url, user, pass, state)
+	}
+	c.lastPeerSeen = c.now()
+	return c, nil
+}
+
+// postCoordHeartbeat sends a heartbeat with the provided election state to the
+// RPC server at the provided URL via a coordheartbeat JSON-RPC request and
+// returns the election state in the response.
+func postCoordHeartbeat(ctx context.Context, client *http.Client, url, user, pass string, state *rpcserver.CoordinationState) (*rpcserver.CoordinationState, error) {
+	cmd := types.NewCoordHeartbeatCmd(state.NodeID, state.Term, state.Leader)
+	body, err := dcrjson.MarshalCmd("1.0", 1, cmd)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url,
+		bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Close = true
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(user, pass)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var reply dcrjson.Response
+	if err := json.Unmarshal(respBody, &reply); err != nil {
+		return nil, fmt.Errorf("status code: %d, response: %q",
+			resp.StatusCode, respBody)
+	}
+	if reply.Error != nil {
+		return nil, reply.Error
+	}
+	var result types.CoordHeartbeatResult
+	if err := json.Unmarshal(reply.Result, &result); err != nil {
+		return nil, err
+	}
+	return &rpcserver.CoordinationState{
+		NodeID: result.NodeID,
+		Term:   result.Term,
+		Leader: result.Leader,
+	}, nil
+}
+
+// state returns the current election state of the local node.
+//
+// This function MUST be called with the coordinator lock held.
+func (c *coordinator) state() *rpcserver.CoordinationState {
+	return &rpcserver.CoordinationState{
+		NodeID: c.nodeID,
+		Term:   c.term,
+		Leader: c.leader,
+	}
+}
+
+// observe applies the provided election state of the peer node to the election
+// state of the local node.
+//
+// This function MUST be called with the coordinator lock held.
+func (c *coordinator) observe(peer *rpcserver.CoordinationState) error {
+	if peer.NodeID == c.nodeID {
+		return errCoordSameNodeID
+	}
+	c.lastPeerSeen = c.now()
+
+	switch {
+	case peer.Term > c.term:
+		c.term = peer.Term
+		c.leader = false
+
+	case peer.Term < c.term:
+		// The peer adopts the term of the local node once it receives
+		// a heartbeat from it.
+		return nil
+	}
+
+	switch {
+	case peer.Leader && c.leader:
+		c.leader = c.nodeID < peer.NodeID
+
+	case peer.Leader:
+		c.leader = false
+
+	case !c.leader && c.nodeID < peer.NodeID:
+		c.term++
+		c.leader = true
+	}
+	return nil
+}
+
+// checkLease promotes the local node to leader in a new term when it has not
+// heard from the peer node for the duration of the lease.
+//
+// This function MUST be called with the coordinator lock held.
+func (c *coordinator) checkLease() {
+	if !c.leader && c.now().Sub(c.lastPeerSeen) >= c.lease {
+		c.term++
+		c.leader = true
+	}
+}
+
+// update invokes the provided function with the coordinator lock held and
+// then logs and notifies any resulting change to whether or not the local node
+// is the leader.
+func (c *coordinator) update(f func() error) error {
+	c.mtx.Lock()
+	wasLeader := c.leader
+	err := f()
+	leader, term := c.leader, c.term
+	c.mtx.Unlock()
+
+	if leader != wasLeader {
+		if leader {
+			srvrLog.Infof("Elected leader of the redundancy pair for "+
+				"term %d", term)
+		} else {
+			srvrLog.Infof("Stepped down to standby of the redundancy "+
+				"pair in term %d", term)
+		}
+		if c.notify != nil {
+			c.notify(leader)
+		}
+	}
+	return err
+}
+
+// IsLeader returns whether or not the local node is currently the leader of the
+// redundancy pair.
+//
+// This function is safe for concurrent access and is part of the
+// rpcserver.Coordinator interface implementation.
+func (c *coordinator) IsLeader() bool {
+	c.mtx.Lock()
+	leader := c.leader
+	c.mtx.Unlock()
+	return leader
+}
+
+// Heartbeat applies the provided election state received from the peer node
+// and returns the resulting election state of the local node.
+//
+// This function is safe for concurrent access and is part of the
+// rpcserver.Coordinator interface implementation.
+func (c *coordinator) Heartbeat(peer *rpcserver.CoordinationState) (*rpcserver.CoordinationState, error) {
+	var state *rpcserver.CoordinationState
+	err := c.update(func() error {
+		if err := c.observe(peer); err != nil {
+			return err
+		}
+		state = c.state()
+		return nil
+	})
+	return state, err
+}
+
+// tick sends a heartbeat to the peer node, applies its election state when it
+// responds, and promotes the local node to leader when the lease expired.
+func (c *coordinator) tick(ctx context.Context) {
+	c.mtx.Lock()
+	state := c.state()
+	c.mtx.Unlock()
+
+	peer, err := c.sendHeartbeat(ctx, state)
+	if err != nil && ctx.Err() == nil {
+		srvrLog.Debugf("Unable to send heartbeat to redundancy pair "+
+			"peer: %v", err)
+	}
+	c.update(func() error {
+		if err == nil {
+			if err := c.observe(peer); err != nil {
+				srvrLog.Errorf("Invalid heartbeat response from "+
+					"redundancy pair peer: %v", err)
+			}
+		}
+		c.checkLease()
+		return nil
+	})
+}
+
+// Run exchanges heartbeats with the peer node periodically until the provided
+// context is cancelled.
+//
+// This must be run as a goroutine.
+func (c *coordinator) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.lease / coordHeartbeatsPerLease)
+	defer ticker.Stop()
+
+	for {
+		c.tick(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
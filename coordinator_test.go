@@ -0,0 +1,292 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrjson/v3"
+	"github.com/decred/dcrd/internal/rpcserver"
+)
+
+// TestCoordinatorObserve ensures the election state of the local node of a
+// redundancy pair is updated as expected when applying the election state of
+// its peer.
+func TestCoordinatorObserve(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		nodeID     string
+		term       uint64
+		leader     bool
+		peer       rpcserver.CoordinationState
+		wantTerm   uint64
+		wantLeader bool
+		wantErr    error
+	}{{
+		name:       "neither leader, local lower id promotes",
+		nodeID:     "a",
+		peer:       rpcserver.CoordinationState{NodeID: "b"},
+		wantTerm:   1,
+		wantLeader: true,
+	}, {
+		name:     "neither leader, local higher id waits",
+		nodeID:   "b",
+		peer:     rpcserver.CoordinationState{NodeID: "a"},
+		wantTerm: 0,
+	}, {
+		name:       "local leader, peer standby in same term",
+		nodeID:     "b",
+		term:       3,
+		leader:     true,
+		peer:       rpcserver.CoordinationState{NodeID: "a", Term: 3},
+		wantTerm:   3,
+		wantLeader: true,
+	}, {
+		name:     "peer leader in same term",
+		nodeID:   "a",
+		term:     3,
+		peer:     rpcserver.CoordinationState{NodeID: "b", Term: 3, Leader: true},
+		wantTerm: 3,
+	}, {
+		name:     "peer leader in higher term steps down local leader",
+		nodeID:   "a",
+		term:     3,
+		leader:   true,
+		peer:     rpcserver.CoordinationState{NodeID: "b", Term: 4, Leader: true},
+		wantTerm: 4,
+	}, {
+		name:       "peer standby in higher term, local lower id promotes",
+		nodeID:     "a",
+		term:       3,
+		leader:     true,
+		peer:       rpcserver.CoordinationState{NodeID: "b", Term: 4},
+		wantTerm:   5,
+		wantLeader: true,
+	}, {
+		name:       "peer leader in lower term is ignored",
+		nodeID:     "b",
+		term:       4,
+		leader:     true,
+		peer:       rpcserver.CoordinationState{NodeID: "a", Term: 3, Leader: true},
+		wantTerm:   4,
+		wantLeader: true,
+	}, {
+		name:       "both leaders in same term, local lower id wins",
+		nodeID:     "a",
+		term:       4,
+		leader:     true,
+		peer:       rpcserver.CoordinationState{NodeID: "b", Term: 4, Leader: true},
+		wantTerm:   4,
+		wantLeader: true,
+	}, {
+		name:     "both leaders in same term, local higher id loses",
+		nodeID:   "b",
+		term:     4,
+		leader:   true,
+		peer:     rpcserver.CoordinationState{NodeID: "a", Term: 4, Leader: true},
+		wantTerm: 4,
+	}, {
+		name:       "peer with same node id",
+		nodeID:     "a",
+		term:       2,
+		leader:     true,
+		peer:       rpcserver.CoordinationState{NodeID: "a", Term: 5, Leader: true},
+		wantTerm:   2,
+		wantLeader: true,
+		wantErr:    errCoordSameNodeID,
+	}}
+
+	for _, test := range tests {
+		c := &coordinator{
+			nodeID: test.nodeID,
+			now:    time.Now,
+			term:   test.term,
+			leader: test.leader,
+		}
+		peer := test.peer
+		err := c.observe(&peer)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("%q: unexpected error -- got %v, want %v", test.name,
+				err, test.wantErr)
+			continue
+		}
+		if c.term != test.wantTerm || c.leader != test.wantLeader {
+			t.Errorf("%q: unexpected state -- got term %d leader %v, "+
+				"want term %d leader %v", test.name, c.term, c.leader,
+				test.wantTerm, test.wantLeader)
+		}
+	}
+}
+
+// TestCoordinatorElection ensures the nodes of a redundancy pair that exchange
+// heartbeats agree on a single leader, that the standby takes over once the
+// lease expires without hearing from the leader, and that the pair converges
+// to a single leader again once the link between them is restored.
+func TestCoordinatorElection(t *testing.T) {
+	t.Parallel()
+
+	const lease = 15 * time.Second
+	now := time.Unix(1600000000, 0)
+	clock := func() time.Time { return now }
+	linkUp := true
+	newNode := func(nodeID string) *coordinator {
+		return &coordinator{
+			nodeID:       nodeID,
+			lease:        lease,
+			now:          clock,
+			lastPeerSeen: now,
+		}
+	}
+	a, b := newNode("a"), newNode("b")
+	connect := func(from, to *coordinator) {
+		from.sendHeartbeat = func(_ context.Context, state *rpcserver.CoordinationState) (*rpcserver.CoordinationState, error) {
+			if !linkUp {
+				return nil, errors.New("connection refused")
+			}
+			return to.Heartbeat(state)
+		}
+	}
+	connect(a, b)
+	connect(b, a)
+	var notified []bool
+	a.notify = func(leader bool) { notified = append(notified, leader) }
+
+	ctx := context.Background()
+	checkLeaders := func(desc string, wantA, wantB bool) {
+		t.Helper()
+		if a.IsLeader() != wantA || b.IsLeader() != wantB {
+			t.Fatalf("%s: unexpected leaders -- got a %v b %v, want a %v "+
+				"b %v", desc, a.IsLeader(), b.IsLeader(), wantA, wantB)
+		}
+	}
+
+	// Ensure the node with the higher id does not take over from a node
+	// that is still starting up and the node with the lower id is elected
+	// once they exchange heartbeats.
+	b.tick(ctx)
+	checkLeaders("startup", true, false)
+	a.tick(ctx)
+	checkLeaders("startup heartbeat", true, false)
+
+	// Ensure the standby does not take over while the leader keeps sending
+	// heartbeats.
+	for i := 0; i < 10; i++ {
+		now = now.Add(lease / coordHeartbeatsPerLease)
+		a.tick(ctx)
+		b.tick(ctx)
+	}
+	checkLeaders("steady state", true, false)
+
+	// Ensure the standby takes over once the lease expires without hearing
+	// from the leader and that the leader keeps serving since it can't tell
+	// the difference between a failed link and a failed peer.
+	linkUp = false
+	now = now.Add(lease - time.Second)
+	b.tick(ctx)
+	checkLeaders("lease not expired", true, false)
+	now = now.Add(time.Second)
+	b.tick(ctx)
+	a.tick(ctx)
+	checkLeaders("lease expired", true, true)
+
+	// Ensure the node that took over in the newer term remains the leader
+	// once the link is restored.
+	linkUp = true
+	now = now.Add(lease / coordHeartbeatsPerLease)
+	a.tick(ctx)
+	checkLeaders("link restored", false, true)
+	b.tick(ctx)
+	checkLeaders("link restored heartbeat", false, true)
+	if a.term != b.term {
+		t.Fatalf("terms did not converge -- got a %d b %d", a.term, b.term)
+	}
+
+	// Ensure the changes in leadership of the first node were notified.
+	wantNotified := []bool{true, false}
+	if !reflect.DeepEqual(notified, wantNotified) {
+		t.Fatalf("unexpected notifications -- got %v, want %v", notified,
+			wantNotified)
+	}
+}
+
+// TestPostCoordHeartbeat ensures heartbeats are sent to the peer node of a
+// redundancy pair as coordheartbeat JSON-RPC requests and that the election
+// state or error in the response is returned.
+func TestPostCoordHeartbeat(t *testing.T) {
+	t.Parallel()
+
+	var rpcErr *dcrjson.RPCError
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "user" || pass != "pass" {
+			http.Error(w, "401 Unauthorized.", http.StatusUnauthorized)
+			return
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("unable to read request: %v", err)
+			return
+		}
+		var req dcrjson.Request
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Errorf("unable to decode request: %v", err)
+			return
+		}
+		if req.Method != "coordheartbeat" {
+			t.Errorf("unexpected method %q", req.Method)
+		}
+		result := json.RawMessage(`{"nodeid":"b","term":7,"leader":true}`)
+		if rpcErr != nil {
+			result = nil
+		}
+		reply, err := dcrjson.MarshalResponse(req.Jsonrpc, req.ID, result,
+			rpcErr)
+		if err != nil {
+			t.Errorf("unable to marshal response: %v", err)
+			return
+		}
+		w.Write(reply)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	state := &rpcserver.CoordinationState{NodeID: "a", Term: 6}
+	peer, err := postCoordHeartbeat(ctx, srv.Client(), srv.URL, "user",
+		"pass", state)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := &rpcserver.CoordinationState{NodeID: "b", Term: 7, Leader: true}
+	if !reflect.DeepEqual(peer, want) {
+		t.Fatalf("unexpected peer state -- got %+v, want %+v", peer, want)
+	}
+
+	// Ensure invalid credentials result in an error.
+	_, err = postCoordHeartbeat(ctx, srv.Client(), srv.URL, "user",
+		"wrong", state)
+	if err == nil {
+		t.Fatal("did not receive error for invalid credentials")
+	}
+
+	// Ensure an RPC error in the response is returned.
+	rpcErr = dcrjson.NewRPCError(dcrjson.ErrRPCInvalidParameter,
+		"Invalid heartbeat")
+	_, err = postCoordHeartbeat(ctx, srv.Client(), srv.URL, "user",
+		"pass", state)
+	var gotErr *dcrjson.RPCError
+	if !errors.As(err, &gotErr) || gotErr.Code != rpcErr.Code {
+		t.Fatalf("unexpected error -- got %v, want %v", err, rpcErr)
+	}
+}
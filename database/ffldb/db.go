@@ -1135,11 +1135,31 @@ func (tx *transaction) hasBlock(hash *chainhash.Hash) bool {
 	return tx.hasKey(bucketizedKey(blockIdxBucketID, hash[:]))
 }
 
+// isBlockCorrupted returns whether or not the data stored in the block files
+// for the block with the given hash fails its integrity checks.  Blocks which
+// are pending to be written on commit or do not exist are not considered
+// corrupted.
+func (tx *transaction) isBlockCorrupted(hash *chainhash.Hash) bool {
+	if _, exists := tx.pendingBlocks[*hash]; exists {
+		return false
+	}
+	blockRow := tx.blockIdxBucket.Get(hash[:])
+	if blockRow == nil {
+		return false
+	}
+	location := deserializeBlockLoc(blockRow)
+	_, err := tx.db.store.readBlock(hash, location)
+	return database.IsError(err, database.ErrCorruption)
+}
+
 // StoreBlock stores the provided block into the database.  There are no checks
 // to ensure the block connects to a previous block, contains double spends, or
 // any additional functionality such as transaction indexing.  It simply stores
 // the block in the database.
 //
+// Blocks which already exist, but whose stored data is corrupted, are stored
+// again so that corruption may be repaired with a fresh copy of the block.
+//
 // Returns the following errors as required by the interface contract:
 //   - ErrBlockExists when the block hash already exists
 //   - ErrTxNotWritable if attempted against a read-only transaction
@@ -1158,9 +1178,11 @@ func (tx *transaction) StoreBlock(block database.BlockSerializer) error {
 		return makeDbErr(database.ErrTxNotWritable, str, nil)
 	}
 
-	// Reject the block if it already exists.
+	// Reject the block if it already exists unless the data stored for it
+	// is corrupted, in which case the block is stored again and the block
+	// index is updated to refer to the new copy on commit.
 	blockHash := block.Hash()
-	if tx.hasBlock(blockHash) {
+	if tx.hasBlock(blockHash) && !tx.isBlockCorrupted(blockHash) {
 		str := fmt.Sprintf("block %s already exists", blockHash)
 		return makeDbErr(database.ErrBlockExists, str, nil)
 	}
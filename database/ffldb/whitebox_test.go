@@ -572,6 +572,61 @@ func testCorruption(tc *testContext) bool {
 		return false
 	}
 
+	// Ensure storing a block that already exists is rejected while its data
+	// is intact.
+	err = tc.db.Update(func(tx database.Tx) error {
+		err := tx.StoreBlock(tc.blocks[0])
+		if !checkDbError(tc.t, "StoreBlock (intact)", err,
+			database.ErrBlockExists) {
+			return errSubTestFail
+		}
+		return nil
+	})
+	if err != nil {
+		if err != errSubTestFail {
+			tc.t.Errorf("Update: unexpected error: %v", err)
+		}
+		return false
+	}
+
+	// Ensure a block with corrupted data may be stored again and that the
+	// new copy is returned afterwards.
+	tc.files[0].file.(*mockFile).data[17] ^= 0x10
+	err = tc.db.Update(func(tx database.Tx) error {
+		if err := tx.StoreBlock(tc.blocks[0]); err != nil {
+			tc.t.Errorf("StoreBlock (corrupted): unexpected error: %v",
+				err)
+			return errSubTestFail
+		}
+		return nil
+	})
+	if err != nil {
+		if err != errSubTestFail {
+			tc.t.Errorf("Update: unexpected error: %v", err)
+		}
+		return false
+	}
+	err = tc.db.View(func(tx database.Tx) error {
+		gotBytes, err := tx.FetchBlock(block0Hash)
+		if err != nil {
+			tc.t.Errorf("FetchBlock (repaired): unexpected error: %v",
+				err)
+			return errSubTestFail
+		}
+		if !bytes.Equal(gotBytes, block0Bytes) {
+			tc.t.Errorf("FetchBlock (repaired): bytes mismatch - "+
+				"got %x, want %x", gotBytes, block0Bytes)
+			return errSubTestFail
+		}
+		return nil
+	})
+	if err != nil {
+		if err != errSubTestFail {
+			tc.t.Errorf("View: unexpected error: %v", err)
+		}
+		return false
+	}
+
 	return true
 }
 
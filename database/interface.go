@@ -232,6 +232,10 @@ type Tx interface {
 	// double spends, or any additional functionality such as transaction
 	// indexing.  It simply stores the block in the database.
 	//
+	// Blocks which already exist, but whose stored data fails integrity
+	// checks, are stored again in order to allow corrupted blocks to be
+	// repaired.
+	//
 	// The interface contract guarantees at least the following errors will
 	// be returned (other implementation-specific errors are possible):
 	//   - ErrBlockExists when the block hash already exists
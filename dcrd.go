@@ -166,6 +166,14 @@ func dcrdMain() error {
 
 		return nil
 	}
+	if cfg.DropAddrActivityIndex {
+		if err := indexers.DropAddrActivityIndex(ctx, db); err != nil {
+			dcrdLog.Errorf("%v", err)
+			return err
+		}
+
+		return nil
+	}
 	if cfg.DropCFIndex {
 		if err := indexers.DropCfIndex(ctx, db); err != nil {
 			dcrdLog.Errorf("%v", err)
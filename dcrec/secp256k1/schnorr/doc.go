@@ -75,6 +75,19 @@ EC-Schnorr-DCRv0 Specification
 See the README.md file for the specific details of the signing and verification
 algorithm as well as the signature serialization format.
 
+MuSig2 Multi-Party Signatures
+
+The package also provides support for collaboratively producing signatures with
+a MuSig2-style protocol.  A set of participants aggregate their public keys
+into a single public key via AggregateKeys and then produce a signature for it
+in two rounds of communication via GenerateNonce, AggregateNonces, PartialSign,
+and AggregatePartialSignatures.  All of the participants must take part in
+signing.
+
+The aggregate public key and final signature are ordinary EC-Schnorr-DCRv0
+values, so they are verified by the existing OP_CHECKSIG semantics for Schnorr
+signatures.
+
 Future Design Considerations
 
 It is worth noting that there are some additional optimizations and
@@ -59,6 +59,48 @@ const (
 	// greater than or equal to the group order.
 	ErrSigSTooBig
 
+	// ErrNoPubKeys indicates an attempt was made to aggregate an empty set of
+	// public keys.
+	ErrNoPubKeys
+
+	// ErrDuplicatePubKey indicates an attempt was made to aggregate a set of
+	// public keys that contains the same key more than once.
+	ErrDuplicatePubKey
+
+	// ErrAggregateKeyInfinity indicates that the aggregated public key is the
+	// point at infinity.
+	ErrAggregateKeyInfinity
+
+	// ErrPubKeyNotInSet indicates that a public key is not one of the keys
+	// that make up an aggregate public key.
+	ErrPubKeyNotInSet
+
+	// ErrNoPubNonces indicates an attempt was made to aggregate an empty set
+	// of public nonces.
+	ErrNoPubNonces
+
+	// ErrInvalidPubNonce indicates that a public nonce does not encode valid
+	// points on the curve.
+	ErrInvalidPubNonce
+
+	// ErrNonceReused indicates an attempt was made to sign with a secret
+	// nonce that was already used.
+	ErrNonceReused
+
+	// ErrInvalidPartialSig indicates that a partial signature is not encoded
+	// correctly or its value is not less than the group order.
+	ErrInvalidPartialSig
+
+	// ErrPartialSigMismatch indicates that a partial signature is not valid
+	// for the public nonce and public key of the participant that produced
+	// it.
+	ErrPartialSigMismatch
+
+	// ErrWrongNumPartialSigs indicates an attempt was made to aggregate a
+	// number of partial signatures that differs from the number of public
+	// keys that make up the aggregate public key.
+	ErrWrongNumPartialSigs
+
 	// numErrorCodes is the maximum error code number used in tests.  This entry
 	// MUST be the last entry in the enum.
 	numErrorCodes
@@ -66,17 +108,27 @@ const (
 
 // Map of ErrorCode values back to their constant names for pretty printing.
 var errorCodeStrings = map[ErrorCode]string{
-	ErrInvalidHashLen:   "ErrInvalidHashLen",
-	ErrPrivateKeyIsZero: "ErrPrivateKeyIsZero",
-	ErrSchnorrHashValue: "ErrSchnorrHashValue",
-	ErrPubKeyNotOnCurve: "ErrPubKeyNotOnCurve",
-	ErrSigRYIsOdd:       "ErrSigRYIsOdd",
-	ErrSigRNotOnCurve:   "ErrSigRNotOnCurve",
-	ErrUnequalRValues:   "ErrUnequalRValues",
-	ErrSigTooShort:      "ErrSigTooShort",
-	ErrSigTooLong:       "ErrSigTooLong",
-	ErrSigRTooBig:       "ErrSigRTooBig",
-	ErrSigSTooBig:       "ErrSigSTooBig",
+	ErrInvalidHashLen:       "ErrInvalidHashLen",
+	ErrPrivateKeyIsZero:     "ErrPrivateKeyIsZero",
+	ErrSchnorrHashValue:     "ErrSchnorrHashValue",
+	ErrPubKeyNotOnCurve:     "ErrPubKeyNotOnCurve",
+	ErrSigRYIsOdd:           "ErrSigRYIsOdd",
+	ErrSigRNotOnCurve:       "ErrSigRNotOnCurve",
+	ErrUnequalRValues:       "ErrUnequalRValues",
+	ErrSigTooShort:          "ErrSigTooShort",
+	ErrSigTooLong:           "ErrSigTooLong",
+	ErrSigRTooBig:           "ErrSigRTooBig",
+	ErrSigSTooBig:           "ErrSigSTooBig",
+	ErrNoPubKeys:            "ErrNoPubKeys",
+	ErrDuplicatePubKey:      "ErrDuplicatePubKey",
+	ErrAggregateKeyInfinity: "ErrAggregateKeyInfinity",
+	ErrPubKeyNotInSet:       "ErrPubKeyNotInSet",
+	ErrNoPubNonces:          "ErrNoPubNonces",
+	ErrInvalidPubNonce:      "ErrInvalidPubNonce",
+	ErrNonceReused:          "ErrNonceReused",
+	ErrInvalidPartialSig:    "ErrInvalidPartialSig",
+	ErrPartialSigMismatch:   "ErrPartialSigMismatch",
+	ErrWrongNumPartialSigs:  "ErrWrongNumPartialSigs",
 }
 
 // String returns the ErrorCode as a human-readable name.
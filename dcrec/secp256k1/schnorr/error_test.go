@@ -26,6 +26,16 @@ func TestErrorCodeStringer(t *testing.T) {
 		{ErrSigTooLong, "ErrSigTooLong"},
 		{ErrSigRTooBig, "ErrSigRTooBig"},
 		{ErrSigSTooBig, "ErrSigSTooBig"},
+		{ErrNoPubKeys, "ErrNoPubKeys"},
+		{ErrDuplicatePubKey, "ErrDuplicatePubKey"},
+		{ErrAggregateKeyInfinity, "ErrAggregateKeyInfinity"},
+		{ErrPubKeyNotInSet, "ErrPubKeyNotInSet"},
+		{ErrNoPubNonces, "ErrNoPubNonces"},
+		{ErrInvalidPubNonce, "ErrInvalidPubNonce"},
+		{ErrNonceReused, "ErrNonceReused"},
+		{ErrInvalidPartialSig, "ErrInvalidPartialSig"},
+		{ErrPartialSigMismatch, "ErrPartialSigMismatch"},
+		{ErrWrongNumPartialSigs, "ErrWrongNumPartialSigs"},
 		{0xffff, "Unknown ErrorCode (65535)"},
 	}
 
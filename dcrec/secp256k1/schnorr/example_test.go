@@ -88,3 +88,75 @@ func ExampleSignature_Verify() {
 	// Output:
 	// Signature Verified? true
 }
+
+// This example demonstrates collaboratively producing an EC-Schnorr-DCRv0
+// signature for an aggregate public key with the MuSig2 protocol.
+func ExampleAggregateKeys() {
+	// Generate the private keys of the participants for the purposes of the
+	// example.  In practice, each participant only has access to their own
+	// private key and shares the associated public key with the others.
+	const numSigners = 3
+	privKeys := make([]*secp256k1.PrivateKey, numSigners)
+	pubKeys := make([]*secp256k1.PublicKey, numSigners)
+	for i := range privKeys {
+		privKey, err := secp256k1.GeneratePrivateKey()
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		privKeys[i] = privKey
+		pubKeys[i] = privKey.PubKey()
+	}
+
+	// Aggregate the public keys.  All participants must use the same order.
+	aggKey, err := schnorr.AggregateKeys(pubKeys)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	// First round: every participant generates a nonce and shares the public
+	// nonce with the others.
+	messageHash := chainhash.HashB([]byte("test message"))
+	secNonces := make([]*schnorr.SecretNonce, numSigners)
+	pubNonces := make([]*schnorr.PubNonce, numSigners)
+	for i, privKey := range privKeys {
+		secNonces[i], pubNonces[i], err = schnorr.GenerateNonce(privKey,
+			aggKey, messageHash)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+	aggNonce, err := schnorr.AggregateNonces(pubNonces)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	// Second round: every participant produces a partial signature and shares
+	// it with the others.
+	partialSigs := make([]*schnorr.PartialSignature, numSigners)
+	for i, privKey := range privKeys {
+		partialSigs[i], err = schnorr.PartialSign(secNonces[i], privKey,
+			aggKey, aggNonce, messageHash)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+
+	// Combine the partial signatures into the final signature and verify it
+	// for the aggregate public key.
+	signature, err := schnorr.AggregatePartialSignatures(aggKey, aggNonce,
+		messageHash, partialSigs)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	verified := signature.Verify(messageHash, aggKey.PubKey())
+	fmt.Println("Signature Verified?", verified)
+
+	// Output:
+	// Signature Verified? true
+}
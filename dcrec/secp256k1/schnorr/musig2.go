@@ -0,0 +1,584 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package schnorr
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/decred/dcrd/crypto/blake256"
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
+)
+
+const (
+	// PubNonceSize is the size of an encoded MuSig2 public nonce.  It
+	// consists of two compressed points.
+	PubNonceSize = 2 * PubKeyBytesLen
+
+	// PartialSignatureSize is the size of an encoded MuSig2 partial
+	// signature.
+	PartialSignatureSize = scalarSize
+)
+
+var (
+	// These tags are used to domain separate the hashes used throughout the
+	// MuSig2 protocol from each other and from all other uses of BLAKE-256.
+	keyAggListTag  = []byte("EC-Schnorr-DCRv0/MuSig2/KeyAgg list")
+	keyAggCoeffTag = []byte("EC-Schnorr-DCRv0/MuSig2/KeyAgg coefficient")
+	nonceGenTag    = []byte("EC-Schnorr-DCRv0/MuSig2/nonce")
+	nonceCoeffTag  = []byte("EC-Schnorr-DCRv0/MuSig2/noncecoef")
+)
+
+// taggedHash returns the BLAKE-256 hash of the provided data prefixed by two
+// copies of the BLAKE-256 hash of the tag.  This ensures hashes computed for
+// different purposes can never collide.
+func taggedHash(tag []byte, data ...[]byte) [32]byte {
+	tagHash := blake256.Sum256(tag)
+	h := blake256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	for _, d := range data {
+		h.Write(d)
+	}
+	var result [32]byte
+	copy(result[:], h.Sum(nil))
+	return result
+}
+
+// isInfinity returns whether or not the provided point is the point at
+// infinity.
+func isInfinity(p *secp256k1.JacobianPoint) bool {
+	return (p.X.IsZero() && p.Y.IsZero()) || p.Z.IsZero()
+}
+
+// AggregateKey houses a MuSig2 aggregate public key along with the
+// information about the individual public keys it commits to that is needed
+// to produce and verify partial signatures.
+type AggregateKey struct {
+	pubKey *secp256k1.PublicKey
+	keys   [][PubKeyBytesLen]byte
+	coeffs []secp256k1.ModNScalar
+}
+
+// AggregateKeys combines the provided public keys into a single MuSig2
+// aggregate public key that requires all of the associated private keys to
+// collaboratively produce a signature for it.
+//
+// Every key is weighted by a coefficient that commits to the full set of keys
+// in order to prevent rogue key attacks, so the order of the keys matters and
+// all participants must provide the keys in the same order.  Each key may only
+// be specified once.
+//
+// The resulting public key is an ordinary secp256k1 public key and signatures
+// produced for it are ordinary EC-Schnorr-DCRv0 signatures.  This means they
+// are indistinguishable from single-party signatures and are verified by the
+// existing OP_CHECKSIG semantics for Schnorr signatures.
+func AggregateKeys(pubKeys []*secp256k1.PublicKey) (*AggregateKey, error) {
+	// The algorithm for aggregating the keys is as follows:
+	//
+	// P_1..P_u = public keys, encoded in compressed form
+	// G = curve generator
+	//
+	// 1. Fail if u = 0
+	// 2. Fail if any P_i is not a point on the curve or is a duplicate
+	// 3. L = H_list(P_1 || ... || P_u)
+	// 4. a_i = H_coeff(L || P_i) mod n for each i
+	// 5. Q = a_1*P_1 + ... + a_u*P_u
+	// 6. Fail if Q is the point at infinity
+	// 7. Return Q
+
+	// Step 1.
+	if len(pubKeys) == 0 {
+		str := "no public keys to aggregate"
+		return nil, signatureError(ErrNoPubKeys, str)
+	}
+
+	// Step 2.
+	keys := make([][PubKeyBytesLen]byte, len(pubKeys))
+	seen := make(map[[PubKeyBytesLen]byte]struct{}, len(pubKeys))
+	for i, pubKey := range pubKeys {
+		if !pubKey.IsOnCurve() {
+			str := fmt.Sprintf("pubkey %d is not on the curve", i)
+			return nil, signatureError(ErrPubKeyNotOnCurve, str)
+		}
+		copy(keys[i][:], pubKey.SerializeCompressed())
+		if _, ok := seen[keys[i]]; ok {
+			str := fmt.Sprintf("pubkey %d is a duplicate", i)
+			return nil, signatureError(ErrDuplicatePubKey, str)
+		}
+		seen[keys[i]] = struct{}{}
+	}
+
+	// Step 3.
+	listData := make([][]byte, len(keys))
+	for i := range keys {
+		listData[i] = keys[i][:]
+	}
+	keysHash := taggedHash(keyAggListTag, listData...)
+
+	// Steps 4 and 5.
+	var Q secp256k1.JacobianPoint
+	coeffs := make([]secp256k1.ModNScalar, len(keys))
+	for i, pubKey := range pubKeys {
+		coeffHash := taggedHash(keyAggCoeffTag, keysHash[:], keys[i][:])
+		coeffs[i].SetBytes(&coeffHash)
+
+		var P, aP secp256k1.JacobianPoint
+		pubKey.AsJacobian(&P)
+		secp256k1.ScalarMultNonConst(&coeffs[i], &P, &aP)
+		secp256k1.AddNonConst(&Q, &aP, &Q)
+	}
+
+	// Step 6.
+	if isInfinity(&Q) {
+		str := "aggregate public key is the point at infinity"
+		return nil, signatureError(ErrAggregateKeyInfinity, str)
+	}
+
+	// Step 7.
+	Q.ToAffine()
+	return &AggregateKey{
+		pubKey: secp256k1.NewPublicKey(&Q.X, &Q.Y),
+		keys:   keys,
+		coeffs: coeffs,
+	}, nil
+}
+
+// PubKey returns the aggregate public key.
+func (k *AggregateKey) PubKey() *secp256k1.PublicKey {
+	return k.pubKey
+}
+
+// coefficient returns the key aggregation coefficient for the provided public
+// key or an error if it is not one of the aggregated keys.
+func (k *AggregateKey) coefficient(pubKey *secp256k1.PublicKey) (*secp256k1.ModNScalar, error) {
+	var key [PubKeyBytesLen]byte
+	copy(key[:], pubKey.SerializeCompressed())
+	for i := range k.keys {
+		if k.keys[i] == key {
+			return &k.coeffs[i], nil
+		}
+	}
+	str := "pubkey is not part of the aggregate public key"
+	return nil, signatureError(ErrPubKeyNotInSet, str)
+}
+
+// PubNonce is a MuSig2 public nonce.  It consists of two compressed points and
+// is shared with the other participants during the first round of signing.
+//
+// Aggregate public nonces use the same format with the exception that either
+// point is encoded as all zeros when it is the point at infinity.
+type PubNonce [PubNonceSize]byte
+
+// SecretNonce is a MuSig2 secret nonce that corresponds to a public nonce.
+//
+// WARNING: A secret nonce MUST only ever be used to produce a single partial
+// signature since reusing it for different messages or sets of public nonces
+// reveals the private key.  PartialSign enforces this by zeroing the nonce
+// after it is used.
+type SecretNonce struct {
+	k1, k2 secp256k1.ModNScalar
+	used   bool
+}
+
+// Zero manually clears the memory associated with the secret nonce and marks
+// it as used so it can no longer be used to sign.
+func (n *SecretNonce) Zero() {
+	n.k1.Zero()
+	n.k2.Zero()
+	n.used = true
+}
+
+// generateNonce deterministically derives a MuSig2 nonce pair from the
+// provided random bytes along with the optional private key, aggregate key,
+// and message hash.  The optional inputs are mixed in as a defense in depth
+// measure against a faulty source of randomness.
+func generateNonce(random *[32]byte, privKey *secp256k1.PrivateKey, aggKey *AggregateKey, hash []byte) (*SecretNonce, *PubNonce) {
+	var privKeyBytes [scalarSize]byte
+	if privKey != nil {
+		privKey.Key.PutBytes(&privKeyBytes)
+		defer zeroArray(&privKeyBytes)
+	}
+	var aggKeyBytes []byte
+	if aggKey != nil {
+		aggKeyBytes = aggKey.pubKey.SerializeCompressed()
+	}
+
+	// Derive each of the secret nonces while ensuring neither is zero.  The
+	// chances of a zero nonce are astronomically small, but it is handled by
+	// deriving another one with an incremented counter.
+	var secNonce SecretNonce
+	var pubNonce PubNonce
+	counter := []byte{0}
+	for i, k := range []*secp256k1.ModNScalar{&secNonce.k1, &secNonce.k2} {
+		for {
+			kHash := taggedHash(nonceGenTag, random[:], privKeyBytes[:],
+				aggKeyBytes, hash, []byte{byte(i)}, counter)
+			k.SetBytes(&kHash)
+			zeroArray(&kHash)
+			if !k.IsZero() {
+				break
+			}
+			counter[0]++
+		}
+
+		var R secp256k1.JacobianPoint
+		secp256k1.ScalarBaseMultNonConst(k, &R)
+		R.ToAffine()
+		pubKey := secp256k1.NewPublicKey(&R.X, &R.Y)
+		copy(pubNonce[i*PubKeyBytesLen:], pubKey.SerializeCompressed())
+	}
+
+	return &secNonce, &pubNonce
+}
+
+// GenerateNonce generates a fresh MuSig2 nonce for the first round of signing
+// and returns the secret nonce to keep private along with the public nonce to
+// share with the other participants.
+//
+// The private key, aggregate key, and message hash are all optional and may be
+// nil when they are not yet known, however, providing them hardens the nonce
+// against a faulty source of randomness.  The hash must be 32 bytes when it is
+// provided.
+func GenerateNonce(privKey *secp256k1.PrivateKey, aggKey *AggregateKey, hash []byte) (*SecretNonce, *PubNonce, error) {
+	if hash != nil && len(hash) != scalarSize {
+		str := fmt.Sprintf("wrong size for message hash (got %v, want %v)",
+			len(hash), scalarSize)
+		return nil, nil, signatureError(ErrInvalidHashLen, str)
+	}
+
+	var random [32]byte
+	if _, err := rand.Read(random[:]); err != nil {
+		return nil, nil, err
+	}
+	defer zeroArray(&random)
+
+	secNonce, pubNonce := generateNonce(&random, privKey, aggKey, hash)
+	return secNonce, pubNonce, nil
+}
+
+// parseNoncePoint parses one of the two points of a public nonce into the
+// provided Jacobian point.  The point at infinity, encoded as all zeros, is
+// only accepted when allowInfinity is true.
+func parseNoncePoint(b []byte, allowInfinity bool, result *secp256k1.JacobianPoint) error {
+	if allowInfinity {
+		var zero [PubKeyBytesLen]byte
+		if string(b) == string(zero[:]) {
+			*result = secp256k1.JacobianPoint{}
+			return nil
+		}
+	}
+	pubKey, err := ParsePubKey(b)
+	if err != nil {
+		str := fmt.Sprintf("invalid nonce point: %v", err)
+		return signatureError(ErrInvalidPubNonce, str)
+	}
+	pubKey.AsJacobian(result)
+	return nil
+}
+
+// parsePubNonce parses both points of the provided public nonce.
+func parsePubNonce(nonce *PubNonce, allowInfinity bool, R1, R2 *secp256k1.JacobianPoint) error {
+	err := parseNoncePoint(nonce[:PubKeyBytesLen], allowInfinity, R1)
+	if err != nil {
+		return err
+	}
+	return parseNoncePoint(nonce[PubKeyBytesLen:], allowInfinity, R2)
+}
+
+// AggregateNonces combines the public nonces of all participants into an
+// aggregate public nonce which is needed to produce and combine the partial
+// signatures during the second round of signing.
+func AggregateNonces(pubNonces []*PubNonce) (*PubNonce, error) {
+	if len(pubNonces) == 0 {
+		str := "no public nonces to aggregate"
+		return nil, signatureError(ErrNoPubNonces, str)
+	}
+
+	var R1, R2 secp256k1.JacobianPoint
+	for _, nonce := range pubNonces {
+		var R1i, R2i secp256k1.JacobianPoint
+		if err := parsePubNonce(nonce, false, &R1i, &R2i); err != nil {
+			return nil, err
+		}
+		secp256k1.AddNonConst(&R1, &R1i, &R1)
+		secp256k1.AddNonConst(&R2, &R2i, &R2)
+	}
+
+	// Encode the points with the point at infinity encoded as all zeros.
+	var aggNonce PubNonce
+	for i, R := range []*secp256k1.JacobianPoint{&R1, &R2} {
+		if isInfinity(R) {
+			continue
+		}
+		R.ToAffine()
+		pubKey := secp256k1.NewPublicKey(&R.X, &R.Y)
+		copy(aggNonce[i*PubKeyBytesLen:], pubKey.SerializeCompressed())
+	}
+	return &aggNonce, nil
+}
+
+// musigSession houses the values shared by all participants that are derived
+// from the aggregate key, aggregate nonce, and message during the second round
+// of signing.
+type musigSession struct {
+	b           secp256k1.ModNScalar // nonce coefficient
+	e           secp256k1.ModNScalar // signature challenge
+	r           secp256k1.FieldVal   // x coordinate of the final nonce point
+	negateNonce bool                 // whether the final nonce was negated
+}
+
+// newMuSigSession derives the session values for the provided aggregate key,
+// aggregate nonce, and message hash.
+func newMuSigSession(aggKey *AggregateKey, aggNonce *PubNonce, hash []byte) (*musigSession, error) {
+	// The algorithm for deriving the session values is as follows:
+	//
+	// Q = aggregate public key
+	// R1, R2 = aggregate nonce points
+	// m = message
+	//
+	// 1. Fail if m is not 32 bytes
+	// 2. b = H_noncecoef(R1 || R2 || Q || m) mod n
+	// 3. R = R1 + b*R2
+	// 4. R = G if R is the point at infinity
+	// 5. Negate the nonces if R.y is odd
+	// 6. r = R.x
+	// 7. e = BLAKE-256(r || m) (Ensure r is padded to 32 bytes)
+	// 8. Fail if e >= n
+
+	// Step 1.
+	if len(hash) != scalarSize {
+		str := fmt.Sprintf("wrong size for message hash (got %v, want %v)",
+			len(hash), scalarSize)
+		return nil, signatureError(ErrInvalidHashLen, str)
+	}
+
+	var R1, R2 secp256k1.JacobianPoint
+	if err := parsePubNonce(aggNonce, true, &R1, &R2); err != nil {
+		return nil, err
+	}
+
+	// Step 2.
+	var session musigSession
+	bHash := taggedHash(nonceCoeffTag, aggNonce[:],
+		aggKey.pubKey.SerializeCompressed(), hash)
+	session.b.SetBytes(&bHash)
+
+	// Steps 3 and 4.
+	var R, bR2 secp256k1.JacobianPoint
+	secp256k1.ScalarMultNonConst(&session.b, &R2, &bR2)
+	secp256k1.AddNonConst(&R1, &bR2, &R)
+	if isInfinity(&R) {
+		var one secp256k1.ModNScalar
+		secp256k1.ScalarBaseMultNonConst(one.SetInt(1), &R)
+	}
+
+	// Steps 5 and 6.
+	//
+	// Note that R must be in affine coordinates for these.
+	R.ToAffine()
+	session.negateNonce = R.Y.IsOdd()
+	session.r.Set(&R.X)
+
+	// Steps 7 and 8.
+	var commitmentInput [scalarSize * 2]byte
+	session.r.PutBytesUnchecked(commitmentInput[0:scalarSize])
+	copy(commitmentInput[scalarSize:], hash)
+	commitment := blake256.Sum256(commitmentInput[:])
+	if overflow := session.e.SetBytes(&commitment); overflow != 0 {
+		str := "hash of (R || m) too big -- signing must be restarted " +
+			"with fresh nonces"
+		return nil, signatureError(ErrSchnorrHashValue, str)
+	}
+
+	return &session, nil
+}
+
+// PartialSignature is a MuSig2 partial signature produced by a single
+// participant during the second round of signing.
+type PartialSignature struct {
+	s secp256k1.ModNScalar
+}
+
+// Serialize returns the partial signature encoded as a 32-byte big-endian
+// scalar.
+func (sig *PartialSignature) Serialize() []byte {
+	var b [PartialSignatureSize]byte
+	sig.s.PutBytes(&b)
+	return b[:]
+}
+
+// ParsePartialSignature parses a partial signature encoded as a 32-byte
+// big-endian scalar that must be less than the group order.
+func ParsePartialSignature(sig []byte) (*PartialSignature, error) {
+	if len(sig) != PartialSignatureSize {
+		str := fmt.Sprintf("malformed partial signature: wrong size (got %d, "+
+			"want %d)", len(sig), PartialSignatureSize)
+		return nil, signatureError(ErrInvalidPartialSig, str)
+	}
+	var partialSig PartialSignature
+	if overflow := partialSig.s.SetByteSlice(sig); overflow {
+		str := "invalid partial signature: s >= group order"
+		return nil, signatureError(ErrInvalidPartialSig, str)
+	}
+	return &partialSig, nil
+}
+
+// PartialSign produces a MuSig2 partial signature for the provided message
+// hash during the second round of signing using the participant's secret nonce
+// and private key along with the aggregate key and aggregate public nonce.
+//
+// The secret nonce is zeroed once it is used and attempting to use it again
+// results in an error since reusing a nonce reveals the private key.
+func PartialSign(secNonce *SecretNonce, privKey *secp256k1.PrivateKey, aggKey *AggregateKey, aggNonce *PubNonce, hash []byte) (*PartialSignature, error) {
+	// The algorithm for producing a partial signature is as follows:
+	//
+	// k1, k2 = secret nonces
+	// d = private key
+	// a = key aggregation coefficient for the public key of d
+	//
+	// 1. Fail if the secret nonce was already used
+	// 2. Fail if d = 0 or the public key of d is not part of the aggregate
+	// 3. Derive the session values b, e, r and whether to negate the nonces
+	// 4. k = k1 + b*k2, negated if required by the session
+	// 5. s = k - e*a*d mod n
+	// 6. Return s
+
+	// Step 1.
+	if secNonce.used {
+		str := "secret nonce was already used"
+		return nil, signatureError(ErrNonceReused, str)
+	}
+	defer secNonce.Zero()
+
+	// Step 2.
+	if privKey.Key.IsZero() {
+		str := "private key is zero"
+		return nil, signatureError(ErrPrivateKeyIsZero, str)
+	}
+	a, err := aggKey.coefficient(privKey.PubKey())
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 3.
+	session, err := newMuSigSession(aggKey, aggNonce, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 4.
+	var k secp256k1.ModNScalar
+	k.Mul2(&session.b, &secNonce.k2).Add(&secNonce.k1)
+	if session.negateNonce {
+		k.Negate()
+	}
+
+	// Step 5.
+	var partialSig PartialSignature
+	partialSig.s.Mul2(&session.e, a).Mul(&privKey.Key).Negate().Add(&k)
+	k.Zero()
+
+	// Step 6.
+	return &partialSig, nil
+}
+
+// verifyPartialSig attempts to verify the partial signature produced by the
+// participant with the provided public nonce and public key and either returns
+// nil if successful or a specific error indicating why it failed if not.
+func verifyPartialSig(sig *PartialSignature, pubNonce *PubNonce, pubKey *secp256k1.PublicKey, aggKey *AggregateKey, aggNonce *PubNonce, hash []byte) error {
+	// The algorithm for verifying a partial signature is as follows:
+	//
+	// s = partial signature
+	// R1, R2 = public nonce points of the participant
+	// P = public key of the participant
+	// a = key aggregation coefficient for P
+	//
+	// 1. Fail if P is not part of the aggregate
+	// 2. Derive the session values b, e and whether to negate the nonces
+	// 3. R' = R1 + b*R2, negated if required by the session
+	// 4. Verified if s*G + e*a*P == R'
+
+	// Step 1.
+	a, err := aggKey.coefficient(pubKey)
+	if err != nil {
+		return err
+	}
+
+	// Step 2.
+	session, err := newMuSigSession(aggKey, aggNonce, hash)
+	if err != nil {
+		return err
+	}
+
+	// Step 3.
+	var R1, R2, bR2, wantR secp256k1.JacobianPoint
+	if err := parsePubNonce(pubNonce, false, &R1, &R2); err != nil {
+		return err
+	}
+	secp256k1.ScalarMultNonConst(&session.b, &R2, &bR2)
+	secp256k1.AddNonConst(&R1, &bR2, &wantR)
+
+	// Step 4.
+	var P, sG, eaP, gotR secp256k1.JacobianPoint
+	var ea secp256k1.ModNScalar
+	ea.Mul2(&session.e, a)
+	pubKey.AsJacobian(&P)
+	secp256k1.ScalarBaseMultNonConst(&sig.s, &sG)
+	secp256k1.ScalarMultNonConst(&ea, &P, &eaP)
+	secp256k1.AddNonConst(&sG, &eaP, &gotR)
+
+	wantInfinity, gotInfinity := isInfinity(&wantR), isInfinity(&gotR)
+	if wantInfinity || gotInfinity {
+		if wantInfinity != gotInfinity {
+			str := "calculated nonce point does not match public nonce"
+			return signatureError(ErrPartialSigMismatch, str)
+		}
+		return nil
+	}
+	wantR.ToAffine()
+	gotR.ToAffine()
+	if session.negateNonce {
+		wantR.Y.Negate(1).Normalize()
+	}
+	if !wantR.X.Equals(&gotR.X) || !wantR.Y.Equals(&gotR.Y) {
+		str := "calculated nonce point does not match public nonce"
+		return signatureError(ErrPartialSigMismatch, str)
+	}
+
+	return nil
+}
+
+// VerifyPartialSignature returns whether or not the partial signature produced
+// by the participant with the provided public nonce and public key is valid for
+// the aggregate key, aggregate nonce, and message hash.
+//
+// This allows the participant responsible for a bad partial signature to be
+// identified when the aggregate signature fails to verify.
+func VerifyPartialSignature(sig *PartialSignature, pubNonce *PubNonce, pubKey *secp256k1.PublicKey, aggKey *AggregateKey, aggNonce *PubNonce, hash []byte) bool {
+	return verifyPartialSig(sig, pubNonce, pubKey, aggKey, aggNonce, hash) == nil
+}
+
+// AggregatePartialSignatures combines the partial signatures of all
+// participants into a final EC-Schnorr-DCRv0 signature that is valid for the
+// aggregate public key and message hash.
+func AggregatePartialSignatures(aggKey *AggregateKey, aggNonce *PubNonce, hash []byte, partialSigs []*PartialSignature) (*Signature, error) {
+	if len(partialSigs) != len(aggKey.keys) {
+		str := fmt.Sprintf("wrong number of partial signatures (got %d, "+
+			"want %d)", len(partialSigs), len(aggKey.keys))
+		return nil, signatureError(ErrWrongNumPartialSigs, str)
+	}
+
+	session, err := newMuSigSession(aggKey, aggNonce, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	var s secp256k1.ModNScalar
+	for _, partialSig := range partialSigs {
+		s.Add(&partialSig.s)
+	}
+	return NewSignature(&session.r, &s), nil
+}
@@ -0,0 +1,283 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package schnorr
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
+)
+
+// musigSigner houses the state of a single participant used throughout the
+// MuSig2 tests.
+type musigSigner struct {
+	privKey  *secp256k1.PrivateKey
+	secNonce *SecretNonce
+	pubNonce *PubNonce
+}
+
+// randPrivKey returns a random private key generated from the provided source
+// of randomness.
+func randPrivKey(t *testing.T, rng *rand.Rand) *secp256k1.PrivateKey {
+	t.Helper()
+
+	var buf [32]byte
+	if _, err := rng.Read(buf[:]); err != nil {
+		t.Fatalf("failed to read random private key: %v", err)
+	}
+	var privKeyScalar secp256k1.ModNScalar
+	privKeyScalar.SetBytes(&buf)
+	return secp256k1.NewPrivateKey(&privKeyScalar)
+}
+
+// musigSetup creates the requested number of random signers, aggregates their
+// public keys, runs the first round of signing for the provided hash, and
+// returns the signers along with the aggregate key and aggregate nonce.
+func musigSetup(t *testing.T, rng *rand.Rand, numSigners int, hash []byte) ([]*musigSigner, *AggregateKey, *PubNonce) {
+	t.Helper()
+
+	signers := make([]*musigSigner, numSigners)
+	pubKeys := make([]*secp256k1.PublicKey, numSigners)
+	for i := range signers {
+		signers[i] = &musigSigner{privKey: randPrivKey(t, rng)}
+		pubKeys[i] = signers[i].privKey.PubKey()
+	}
+	aggKey, err := AggregateKeys(pubKeys)
+	if err != nil {
+		t.Fatalf("failed to aggregate keys: %v", err)
+	}
+
+	pubNonces := make([]*PubNonce, numSigners)
+	for i, signer := range signers {
+		var random [32]byte
+		if _, err := rng.Read(random[:]); err != nil {
+			t.Fatalf("failed to read random nonce data: %v", err)
+		}
+		signer.secNonce, signer.pubNonce = generateNonce(&random,
+			signer.privKey, aggKey, hash)
+		pubNonces[i] = signer.pubNonce
+	}
+	aggNonce, err := AggregateNonces(pubNonces)
+	if err != nil {
+		t.Fatalf("failed to aggregate nonces: %v", err)
+	}
+
+	return signers, aggKey, aggNonce
+}
+
+// TestMuSig2SignAndVerifyRandom ensures MuSig2 signatures produced by sets of
+// random signers for random messages are valid EC-Schnorr-DCRv0 signatures for
+// the aggregate public key and that each partial signature verifies.
+func TestMuSig2SignAndVerifyRandom(t *testing.T) {
+	// Use a unique random seed each test instance and log it if the tests fail.
+	seed := time.Now().Unix()
+	rng := rand.New(rand.NewSource(seed))
+	defer func(t *testing.T, seed int64) {
+		if t.Failed() {
+			t.Logf("random seed: %d", seed)
+		}
+	}(t, seed)
+
+	for i := 0; i < 50; i++ {
+		var hash [32]byte
+		if _, err := rng.Read(hash[:]); err != nil {
+			t.Fatalf("failed to read random hash: %v", err)
+		}
+
+		numSigners := rng.Intn(5) + 1
+		signers, aggKey, aggNonce := musigSetup(t, rng, numSigners, hash[:])
+
+		// Produce and verify the partial signatures.
+		partialSigs := make([]*PartialSignature, numSigners)
+		for j, signer := range signers {
+			partialSig, err := PartialSign(signer.secNonce, signer.privKey,
+				aggKey, aggNonce, hash[:])
+			if err != nil {
+				t.Fatalf("failed to partially sign: %v", err)
+			}
+			pubKey := signer.privKey.PubKey()
+			err = verifyPartialSig(partialSig, signer.pubNonce, pubKey, aggKey,
+				aggNonce, hash[:])
+			if err != nil {
+				t.Fatalf("failed to verify partial signature %d of %d: %v",
+					j, numSigners, err)
+			}
+
+			// Ensure the partial signature does not verify for another
+			// participant.
+			other := signers[(j+1)%numSigners]
+			if numSigners > 1 && VerifyPartialSignature(partialSig,
+				other.pubNonce, other.privKey.PubKey(), aggKey, aggNonce,
+				hash[:]) {
+
+				t.Fatalf("verified partial signature %d for wrong "+
+					"participant", j)
+			}
+
+			// Ensure the partial signature survives a serialization round
+			// trip.
+			parsed, err := ParsePartialSignature(partialSig.Serialize())
+			if err != nil {
+				t.Fatalf("failed to parse partial signature: %v", err)
+			}
+			partialSigs[j] = parsed
+		}
+
+		// Combine the partial signatures and ensure the result is a valid
+		// signature for the aggregate key once serialized and parsed the same
+		// way the script engine does.
+		sig, err := AggregatePartialSignatures(aggKey, aggNonce, hash[:],
+			partialSigs)
+		if err != nil {
+			t.Fatalf("failed to aggregate partial signatures: %v", err)
+		}
+		parsedSig, err := ParseSignature(sig.Serialize())
+		if err != nil {
+			t.Fatalf("failed to parse signature: %v", err)
+		}
+		pubKey, err := ParsePubKey(aggKey.PubKey().SerializeCompressed())
+		if err != nil {
+			t.Fatalf("failed to parse aggregate pubkey: %v", err)
+		}
+		if err := schnorrVerify(parsedSig, hash[:], pubKey); err != nil {
+			t.Fatalf("failed to verify aggregate signature with %d signers: "+
+				"%v", numSigners, err)
+		}
+
+		// Ensure the signature does not verify for a different message.
+		badHash := hash
+		badHash[rng.Intn(len(badHash))] ^= 1 << uint(rng.Intn(7))
+		if parsedSig.Verify(badHash[:], pubKey) {
+			t.Fatal("verified aggregate signature for bad hash")
+		}
+	}
+}
+
+// TestMuSig2BadPartialSig ensures a corrupted partial signature is identified
+// by partial signature verification and results in an aggregate signature that
+// does not verify.
+func TestMuSig2BadPartialSig(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	hash := make([]byte, 32)
+	rng.Read(hash)
+
+	signers, aggKey, aggNonce := musigSetup(t, rng, 3, hash)
+	partialSigs := make([]*PartialSignature, len(signers))
+	for i, signer := range signers {
+		partialSig, err := PartialSign(signer.secNonce, signer.privKey, aggKey,
+			aggNonce, hash)
+		if err != nil {
+			t.Fatalf("failed to partially sign: %v", err)
+		}
+		partialSigs[i] = partialSig
+	}
+
+	var one secp256k1.ModNScalar
+	partialSigs[1].s.Add(one.SetInt(1))
+	err := verifyPartialSig(partialSigs[1], signers[1].pubNonce,
+		signers[1].privKey.PubKey(), aggKey, aggNonce, hash)
+	if !errors.Is(err, ErrPartialSigMismatch) {
+		t.Fatalf("unexpected error -- got %v, want %v", err,
+			ErrPartialSigMismatch)
+	}
+
+	sig, err := AggregatePartialSignatures(aggKey, aggNonce, hash, partialSigs)
+	if err != nil {
+		t.Fatalf("failed to aggregate partial signatures: %v", err)
+	}
+	if sig.Verify(hash, aggKey.PubKey()) {
+		t.Fatal("verified aggregate signature with bad partial signature")
+	}
+}
+
+// TestMuSig2Errors ensures the various error paths of the MuSig2 functions
+// are detected as expected.
+func TestMuSig2Errors(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	hash := make([]byte, 32)
+	rng.Read(hash)
+	signers, aggKey, aggNonce := musigSetup(t, rng, 2, hash)
+	outsider := randPrivKey(t, rng)
+	pubKey0 := signers[0].privKey.PubKey()
+
+	// Key aggregation errors.
+	_, err := AggregateKeys(nil)
+	if !errors.Is(err, ErrNoPubKeys) {
+		t.Errorf("no keys: unexpected error -- got %v, want %v", err,
+			ErrNoPubKeys)
+	}
+	_, err = AggregateKeys([]*secp256k1.PublicKey{pubKey0, pubKey0})
+	if !errors.Is(err, ErrDuplicatePubKey) {
+		t.Errorf("duplicate keys: unexpected error -- got %v, want %v", err,
+			ErrDuplicatePubKey)
+	}
+
+	// Nonce errors.
+	_, _, err = GenerateNonce(nil, nil, hash[:31])
+	if !errors.Is(err, ErrInvalidHashLen) {
+		t.Errorf("short nonce hash: unexpected error -- got %v, want %v", err,
+			ErrInvalidHashLen)
+	}
+	if _, _, err := GenerateNonce(nil, nil, nil); err != nil {
+		t.Errorf("nonce without optional data: unexpected error: %v", err)
+	}
+	_, err = AggregateNonces(nil)
+	if !errors.Is(err, ErrNoPubNonces) {
+		t.Errorf("no nonces: unexpected error -- got %v, want %v", err,
+			ErrNoPubNonces)
+	}
+	var badNonce PubNonce
+	copy(badNonce[:], signers[0].pubNonce[:])
+	badNonce[0] = 0x04
+	_, err = AggregateNonces([]*PubNonce{&badNonce})
+	if !errors.Is(err, ErrInvalidPubNonce) {
+		t.Errorf("bad nonce: unexpected error -- got %v, want %v", err,
+			ErrInvalidPubNonce)
+	}
+
+	// Signing errors.
+	_, err = PartialSign(signers[1].secNonce, outsider, aggKey, aggNonce, hash)
+	if !errors.Is(err, ErrPubKeyNotInSet) {
+		t.Errorf("outsider: unexpected error -- got %v, want %v", err,
+			ErrPubKeyNotInSet)
+	}
+	_, err = PartialSign(signers[0].secNonce, signers[0].privKey, aggKey,
+		aggNonce, hash[:31])
+	if !errors.Is(err, ErrInvalidHashLen) {
+		t.Errorf("short hash: unexpected error -- got %v, want %v", err,
+			ErrInvalidHashLen)
+	}
+	_, err = PartialSign(signers[0].secNonce, signers[0].privKey, aggKey,
+		aggNonce, hash)
+	if !errors.Is(err, ErrNonceReused) {
+		t.Errorf("reused nonce: unexpected error -- got %v, want %v", err,
+			ErrNonceReused)
+	}
+
+	// Partial signature errors.
+	_, err = ParsePartialSignature(make([]byte, PartialSignatureSize-1))
+	if !errors.Is(err, ErrInvalidPartialSig) {
+		t.Errorf("short partial sig: unexpected error -- got %v, want %v",
+			err, ErrInvalidPartialSig)
+	}
+	overflow := make([]byte, PartialSignatureSize)
+	for i := range overflow {
+		overflow[i] = 0xff
+	}
+	_, err = ParsePartialSignature(overflow)
+	if !errors.Is(err, ErrInvalidPartialSig) {
+		t.Errorf("overflow partial sig: unexpected error -- got %v, want %v",
+			err, ErrInvalidPartialSig)
+	}
+	_, err = AggregatePartialSignatures(aggKey, aggNonce, hash,
+		[]*PartialSignature{{}})
+	if !errors.Is(err, ErrWrongNumPartialSigs) {
+		t.Errorf("partial sig count: unexpected error -- got %v, want %v",
+			err, ErrWrongNumPartialSigs)
+	}
+}
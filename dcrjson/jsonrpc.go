@@ -18,9 +18,13 @@ type RPCErrorCode int
 
 // RPCError represents an error that is used as a part of a JSON-RPC Response
 // object.
+//
+// The optional Data field holds additional machine-readable information about
+// the error.  Its format depends on the error and the method that produced it.
 type RPCError struct {
-	Code    RPCErrorCode `json:"code,omitempty"`
-	Message string       `json:"message,omitempty"`
+	Code    RPCErrorCode    `json:"code,omitempty"`
+	Message string          `json:"message,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
 }
 
 // Guarantee RPCError satisfies the builtin error interface.
@@ -41,6 +45,21 @@ func NewRPCError(code RPCErrorCode, message string) *RPCError {
 	}
 }
 
+// NewRPCErrorWithData constructs and returns a new JSON-RPC error that is
+// suitable for use in a JSON-RPC Response object and includes the JSON
+// encoding of the passed data as additional information about the error.
+func NewRPCErrorWithData(code RPCErrorCode, message string, data interface{}) (*RPCError, error) {
+	marshalledData, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return &RPCError{
+		Code:    code,
+		Message: message,
+		Data:    marshalledData,
+	}, nil
+}
+
 // IsValidIDType checks that the ID field (which can go in any of the JSON-RPC
 // requests, responses, or notifications) is valid.  JSON-RPC 1.0 allows any
 // valid JSON type.  JSON-RPC 2.0 (which bitcoind follows for some parts) only
@@ -75,6 +75,20 @@ func TestMarshalResponse(t *testing.T) {
 			}(),
 			expected: []byte(`{"jsonrpc":"1.0","result":null,"error":{"code":-5,"message":"123 not found"},"id":1}`),
 		},
+		{
+			name:   "result with error and data",
+			result: nil,
+			jsonErr: func() *RPCError {
+				data := map[string]int{"index": 1}
+				rpcErr, err := NewRPCErrorWithData(ErrRPCMisc,
+					"rejected", data)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return rpcErr
+			}(),
+			expected: []byte(`{"jsonrpc":"1.0","result":null,"error":{"code":-1,"message":"rejected","data":{"index":1}},"id":1}`),
+		},
 	}
 
 	t.Logf("Running %d tests", len(tests))
@@ -0,0 +1,148 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/decred/dcrd/internal/rpcserver"
+)
+
+const (
+	// diskSpaceLevelOK, diskSpaceLevelLow, and diskSpaceLevelCritical are
+	// the levels the free disk space available to the data directory can be
+	// at relative to the configured thresholds.
+	diskSpaceLevelOK       = "ok"
+	diskSpaceLevelLow      = "low"
+	diskSpaceLevelCritical = "critical"
+
+	// diskSpaceCheckInterval is the interval at which the free disk space
+	// available to the data directory is checked.
+	diskSpaceCheckInterval = time.Minute
+)
+
+// errDiskSpaceUnsupported is returned when querying the free disk space is not
+// supported on the current platform.
+var errDiskSpaceUnsupported = errors.New("querying free disk space is not " +
+	"supported on this platform")
+
+// diskSpaceLevel returns the level of the provided free disk space relative to
+// the provided thresholds.  A threshold of zero is disabled.
+func diskSpaceLevel(free, warnThreshold, stopThreshold uint64) string {
+	switch {
+	case stopThreshold != 0 && free < stopThreshold:
+		return diskSpaceLevelCritical
+	case warnThreshold != 0 && free < warnThreshold:
+		return diskSpaceLevelLow
+	}
+	return diskSpaceLevelOK
+}
+
+// diskSpaceMonitor periodically checks the free disk space available to the
+// data directory so that warnings can be issued when it runs low and the
+// server can be shut down gracefully before it runs out entirely rather than
+// failing in the middle of writing to the database.
+type diskSpaceMonitor struct {
+	path          string
+	warnThreshold uint64
+	stopThreshold uint64
+
+	// freeDiskSpace returns the free disk space available to the process
+	// and the total size of the volume that houses the provided path.  It
+	// is a field so it can be mocked by tests.
+	freeDiskSpace func(path string) (free, total uint64, err error)
+}
+
+// newDiskSpaceMonitor returns a disk space monitor for the provided data
+// directory with the provided thresholds in bytes.  A threshold of zero is
+// disabled.
+func newDiskSpaceMonitor(path string, warnThreshold, stopThreshold uint64) *diskSpaceMonitor {
+	return &diskSpaceMonitor{
+		path:          path,
+		warnThreshold: warnThreshold,
+		stopThreshold: stopThreshold,
+		freeDiskSpace: freeDiskSpace,
+	}
+}
+
+// DiskSpace returns the current disk space status of the volume that houses
+// the data directory.
+//
+// This function is safe for concurrent access and is part of the
+// rpcserver.DiskSpaceMonitor interface implementation.
+func (m *diskSpaceMonitor) DiskSpace() (*rpcserver.DiskSpaceStatus, error) {
+	free, total, err := m.freeDiskSpace(m.path)
+	if err != nil {
+		return nil, err
+	}
+	return &rpcserver.DiskSpaceStatus{
+		Path:          m.path,
+		Free:          free,
+		Total:         total,
+		WarnThreshold: m.warnThreshold,
+		StopThreshold: m.stopThreshold,
+		Level:         diskSpaceLevel(free, m.warnThreshold, m.stopThreshold),
+	}, nil
+}
+
+// Run checks the free disk space available to the data directory immediately
+// and then periodically until the provided context is cancelled.  The notify
+// function is invoked with the status whenever the level changes.  A graceful
+// shutdown is requested once the free disk space falls below the stop
+// threshold.
+//
+// This must be run as a goroutine.
+func (m *diskSpaceMonitor) Run(ctx context.Context, notify func(*rpcserver.DiskSpaceStatus)) {
+	ticker := time.NewTicker(diskSpaceCheckInterval)
+	defer ticker.Stop()
+
+	level := diskSpaceLevelOK
+	for {
+		status, err := m.DiskSpace()
+		if errors.Is(err, errDiskSpaceUnsupported) {
+			srvrLog.Warnf("Disk space monitoring disabled: %v", err)
+			return
+		}
+		if err != nil {
+			srvrLog.Errorf("Unable to query free disk space: %v", err)
+		}
+		if err == nil && status.Level != level {
+			level = status.Level
+			switch level {
+			case diskSpaceLevelOK:
+				srvrLog.Infof("Free disk space available to %s recovered "+
+					"to %d MiB", m.path, status.Free>>20)
+			case diskSpaceLevelLow:
+				srvrLog.Warnf("Free disk space available to %s is low: %d "+
+					"MiB remaining (warning threshold %d MiB, shutdown "+
+					"threshold %d MiB)", m.path, status.Free>>20,
+					m.warnThreshold>>20, m.stopThreshold>>20)
+			case diskSpaceLevelCritical:
+				srvrLog.Errorf("Free disk space available to %s is "+
+					"critically low: %d MiB remaining (shutdown threshold "+
+					"%d MiB) -- shutting down to avoid running out of "+
+					"space mid-write", m.path, status.Free>>20,
+					m.stopThreshold>>20)
+			}
+			notify(status)
+
+			if level == diskSpaceLevelCritical {
+				select {
+				case shutdownRequestChannel <- struct{}{}:
+				case <-ctx.Done():
+				}
+				return
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
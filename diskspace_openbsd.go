@@ -0,0 +1,21 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"syscall"
+)
+
+// freeDiskSpace returns the free disk space available to the process and the
+// total size of the volume that houses the provided path.
+func freeDiskSpace(path string) (uint64, uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, 0, err
+	}
+	free := uint64(stat.F_bavail) * uint64(stat.F_bsize)
+	total := uint64(stat.F_blocks) * uint64(stat.F_bsize)
+	return free, total, nil
+}
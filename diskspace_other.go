@@ -0,0 +1,13 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// +build !darwin,!dragonfly,!freebsd,!linux,!openbsd,!windows
+
+package main
+
+// freeDiskSpace returns errDiskSpaceUnsupported since querying the free disk
+// space is not supported on this platform.
+func freeDiskSpace(path string) (uint64, uint64, error) {
+	return 0, 0, errDiskSpaceUnsupported
+}
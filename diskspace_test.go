@@ -0,0 +1,95 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestDiskSpaceMonitor ensures the disk space monitor reports the expected
+// level for free disk space relative to the configured thresholds.
+func TestDiskSpaceMonitor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		free  uint64
+		warn  uint64
+		stop  uint64
+		level string
+	}{{
+		name:  "both thresholds disabled",
+		free:  0,
+		level: diskSpaceLevelOK,
+	}, {
+		name:  "above warn threshold",
+		free:  2048 << 20,
+		warn:  1024 << 20,
+		stop:  256 << 20,
+		level: diskSpaceLevelOK,
+	}, {
+		name:  "exactly at warn threshold",
+		free:  1024 << 20,
+		warn:  1024 << 20,
+		stop:  256 << 20,
+		level: diskSpaceLevelOK,
+	}, {
+		name:  "below warn threshold",
+		free:  1023 << 20,
+		warn:  1024 << 20,
+		stop:  256 << 20,
+		level: diskSpaceLevelLow,
+	}, {
+		name:  "below stop threshold",
+		free:  255 << 20,
+		warn:  1024 << 20,
+		stop:  256 << 20,
+		level: diskSpaceLevelCritical,
+	}, {
+		name:  "below stop threshold with warn disabled",
+		free:  255 << 20,
+		stop:  256 << 20,
+		level: diskSpaceLevelCritical,
+	}, {
+		name:  "below warn threshold with stop disabled",
+		free:  0,
+		warn:  1024 << 20,
+		level: diskSpaceLevelLow,
+	}}
+
+	for _, test := range tests {
+		free := test.free
+		m := newDiskSpaceMonitor("/data", test.warn, test.stop)
+		m.freeDiskSpace = func(path string) (uint64, uint64, error) {
+			return free, 4096 << 20, nil
+		}
+		status, err := m.DiskSpace()
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.name, err)
+			continue
+		}
+		if status.Level != test.level {
+			t.Errorf("%q: unexpected level -- got %s, want %s", test.name,
+				status.Level, test.level)
+		}
+		if status.Path != "/data" || status.Free != test.free ||
+			status.Total != 4096<<20 || status.WarnThreshold != test.warn ||
+			status.StopThreshold != test.stop {
+
+			t.Errorf("%q: unexpected status %+v", test.name, status)
+		}
+	}
+
+	// Ensure errors querying the free disk space are returned.
+	errStatfs := errors.New("statfs failed")
+	m := newDiskSpaceMonitor("/data", 1024<<20, 256<<20)
+	m.freeDiskSpace = func(path string) (uint64, uint64, error) {
+		return 0, 0, errStatfs
+	}
+	if _, err := m.DiskSpace(); !errors.Is(err, errStatfs) {
+		t.Errorf("unexpected error -- got %v, want %v", err, errStatfs)
+	}
+}
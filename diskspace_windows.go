@@ -0,0 +1,32 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// procGetDiskFreeSpaceExW is the kernel32 function used to query the free
+// disk space available to a directory.
+var procGetDiskFreeSpaceExW = syscall.NewLazyDLL("kernel32.dll").
+	NewProc("GetDiskFreeSpaceExW")
+
+// freeDiskSpace returns the free disk space available to the process and the
+// total size of the volume that houses the provided path.
+func freeDiskSpace(path string) (uint64, uint64, error) {
+	pathPtr, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	var free, total, totalFree uint64
+	r, _, err := procGetDiskFreeSpaceExW.Call(uintptr(unsafe.Pointer(pathPtr)),
+		uintptr(unsafe.Pointer(&free)), uintptr(unsafe.Pointer(&total)),
+		uintptr(unsafe.Pointer(&totalFree)))
+	if r == 0 {
+		return 0, 0, err
+	}
+	return free, total, nil
+}
@@ -45,12 +45,32 @@ Application Options:
                                Use show to list available subsystems (info)
       --sigcachemaxsize=       The maximum number of entries in the signature
                                verification cache (default: 100000)
+      --sharedsigcache=        Share the signature verification cache with
+                               other processes on this host via a shared
+                               memory segment backed by this file, typically
+                               under /dev/shm -- The segment is created with
+                               room for sigcachemaxsize entries when the file
+                               does not exist
+      --diskspacewarn=         Warn when the free disk space available to the
+                               data directory falls below this many MiB -- 0
+                               to disable (default: 1024)
+      --diskspacestop=         Gracefully shut down when the free disk space
+                               available to the data directory falls below
+                               this many MiB to avoid running out of space
+                               mid-write -- 0 to disable (default: 256)
       --norpc                  Disable built-in RPC server -- NOTE: The RPC
                                server is disabled by default if no
                                rpcuser/rpcpass or rpclimituser/rpclimitpass is
                                specified
       --rpclisten=             Add an interface/port to listen for RPC
                                connections (default port: 9109, testnet: 19109)
+      --rpcunixsocket=         Path of a unix domain socket to additionally
+                               listen for RPC connections on -- NOTE:
+                               Connections over the socket do not use TLS and
+                               requests without credentials are authorized as
+                               the admin user, so access is only restricted by
+                               the permissions of the socket file, which is
+                               only accessible by the owner
   -u, --rpcuser=               Username for RPC connections
   -P, --rpcpass=               Password for RPC connections
       --rpclimituser=          Username for limited RPC connections
@@ -71,6 +91,31 @@ Application Options:
                                25)
       --rpcmaxconcurrentreqs=  Max number of concurrent RPC requests that may be
                                processed concurrently (default: 20)
+      --rpcauditlog=           Append a record of every state-changing RPC,
+                               including the credentials and address of the
+                               client and the result, to the specified file
+      --enableexperimentalrpc  Enable the experimental RPCs, which are prefixed
+                               with x_ and come with no stability guarantees --
+                               NOTE: They may change or be removed in any
+                               release
+      --coordpeer=             Form a redundancy pair that shares a virtual IP
+                               address with the dcrd instance whose RPC server
+                               listens on the specified address so that only
+                               the elected leader of the pair serves miners and
+                               websocket clients -- NOTE: Both instances must
+                               use the same rpcuser and rpcpass
+      --coordnodeid=           Identifier of this instance within its
+                               redundancy pair which must differ from the
+                               identifier of the peer instance -- The instance
+                               with the lower identifier is preferred as the
+                               leader (default: hostname)
+      --coordcert=             File containing the certificate of the RPC
+                               server of the redundancy pair peer (default:
+                               rpccert)
+      --coordlease=            How long the standby instance of a redundancy
+                               pair waits without hearing from its peer before
+                               taking over as the leader.  Valid time units
+                               are {s, m, h}.  Minimum 3 seconds (default: 15s)
       --proxy=                 Connect via SOCKS5 proxy (eg. 127.0.0.1:9050)
       --proxyuser=             Username for proxy server
       --proxypass=             Password for proxy server
@@ -100,6 +145,35 @@ Application Options:
       --peeridletimeout        The duration of inactivity before a peer is timed
                                out. Valid time units are {s,m,h}. Minimum 15
                                seconds (default: 2m0s)
+      --peeridentity           Authenticate to peers that support it with a
+                               long-term identity key that is stored in the data
+                               directory and created if needed
+      --allowpeerkey=          Only allow connections with peers that
+                               authenticate with the specified hex-encoded
+                               identity public key -- may be specified multiple
+                               times (implies --peeridentity)
+      --msglimit=              Limit the payload size and optionally the rate of
+                               a type of message received from peers in the form
+                               <command>:<maxbytes>[:<maxrate>[:<burst>]] where
+                               the rate is in messages per second and 0 bytes
+                               means the protocol limit -- may be specified
+                               multiple times -- Only valid with the regnet and
+                               simnet options
+      --ancientblockrate=      Maximum rate in KiB/s at which blocks more than
+                               288 blocks deep in the chain are served to each
+                               peer that is not whitelisted -- 0 to disable
+                               (default: 4096)
+      --peertelemetry          Record anonymized peer connection lifecycle,
+                               address relay, and inventory announcement events
+                               that can be exported for network research with
+                               the dumppeertelemetry RPC
+      --ipv6only               Only connect to peers over IPv6
+      --preferipv4             Prefer IPv4 over IPv6 when racing connections to
+                               peers that have addresses in both families
+      --targetoutboundipv4=    Minimum number of automatic outbound peers to
+                               maintain over IPv4 -- 0 to disable
+      --targetoutboundipv6=    Minimum number of automatic outbound peers to
+                               maintain over IPv6 -- 0 to disable
       --noseeders              Disable seeding for peer discovery
       --nodnsseed              DEPRECATED: use --noseeders
       --externalip=            Add an ip to the list of local addresses we claim
@@ -107,6 +181,11 @@ Application Options:
       --nodiscoverip           Disable automatic network address discovery of
                                local external IPs
       --upnp                   Use UPnP to map our listening port outside of NAT
+      --mdns                   Discover and automatically connect to other nodes
+                               on the local network via multicast DNS -- Not
+                               allowed on mainnet without also specifying
+                               --allowmdnsmainnet
+      --allowmdnsmainnet       Allow --mdns to be used on mainnet
       --nobanning              Disable banning of misbehaving peers
       --banduration=           How long to ban misbehaving peers.  Valid time
                                units are {s, m, h}.  Minimum 1 second (default:
@@ -117,8 +196,40 @@ Application Options:
                                (eg. 192.168.1.0/24 or ::1)
       --nocheckpoints          Disable built-in checkpoints.  Don't do this
                                unless you know what you're doing
+      --assumevalid=           Hash of a block assumed to be valid such that
+                               script validation is skipped for it and its
+                               ancestors during the initial chain sync while
+                               all other checks are still enforced -- Defaults
+                               to a block built into the software for the
+                               active network, 0 to disable
       --dumpblockchain=        Write blockchain as a flat file of blocks for use
                                with addblock, to the specified filename
+      --coinbasematurity=      Override the number of blocks before newly mined
+                               coins can be spent -- Only valid with the regnet
+                               and simnet options
+      --ticketmaturity=        Override the number of blocks before newly
+                               purchased tickets are eligible to vote -- Only
+                               valid with the regnet and simnet options
+      --alertevent=            Record the specified kind of critical event to a
+                               durable outbox in the data directory and deliver
+                               it to the alert webhooks -- may be specified
+                               multiple times {reorg, ticketexhaustion,
+                               corruption}
+      --alertreorgdepth=       Minimum number of blocks a chain reorganization
+                               must disconnect to record a reorg alert
+                               (default: 6)
+      --alertwebhook=          POST recorded critical event alerts as JSON to
+                               the specified HTTP(S) URL, retrying until they
+                               are accepted -- may be specified multiple times
+      --webhook=               POST events of the specified category as JSON to
+                               the specified HTTP(S) URL in the form
+                               category=url -- may be specified multiple times
+                               {block, tx, peerban}
+      --webhooksecret=         Secret used to sign webhook payloads with
+                               HMAC-SHA256 in the X-Dcrd-Signature header
+      --webhooktxaddr=         Deliver tx webhook events for transactions that
+                               pay to the specified address -- may be specified
+                               multiple times
       --minrelaytxfee=         The minimum transaction fee in DCR/kB to be
                                considered a non-zero fee (default: 0.0001)
       --limitfreerelay=        Limit relay of transactions with no transaction
@@ -129,18 +240,69 @@ Application Options:
       --maxorphantx=           Max number of orphan transactions to keep in
                                memory (default: 100)
       --blocksonly             Do not accept transactions from remote peers
+      --noserveblocks          Do not advertise the full node network service or
+                               serve and announce full blocks to peers that are
+                               not whitelisted while still serving block headers
+                               and compact filters -- NOTE: Full blocks are still
+                               downloaded and the full UTXO set is still
+                               maintained in order to validate them
+      --txrecon                Announce transactions to peers that support it
+                               via set reconciliation instead of flooding
+      --bloomfilters           Serve BIP37-style bloom filtered blocks and
+                               transactions to legacy SPV clients that do not
+                               support version 2 compact filters
       --acceptnonstd           Accept and relay non-standard transactions to
                                the network regardless of the default settings
                                for the active network
       --rejectnonstd           Reject non-standard transactions regardless of
                                the default settings for the active network
+      --txtypepolicy=          Override the relay policy of a type of
+                               transaction in the form <type>:<policy> where
+                               type is one of {regular, ticket, vote,
+                               revocation} and policy is one of {std, nonstd,
+                               reject} -- may be specified multiple times --
+                               Only valid with the regnet and simnet options
       --allowoldvotes          Enable the addition of very old votes to the
                                mempool
+      --mempoolttl=            Evict regular transactions and ticket purchases
+                               that have been in the mempool longer than the
+                               given duration regardless of their expiry.  Valid
+                               time units are {s, m, h}.  Minimum 1 minute.  A
+                               value of 0 disables time-based eviction
+      --txreplacement          Allow regular transactions in the mempool to be
+                               replaced by conflicting regular transactions
+                               that pay sufficiently higher fees
+      --replacementfeeincrease=
+                               The minimum percentage by which the fee rate of
+                               a replacement transaction must exceed the fee
+                               rate of each transaction it replaces (default:
+                               10)
+      --feeexemptpeer=         Accept transactions relayed by peers from the
+                               specified IP network or IP regardless of the
+                               minimum relay fee -- may be specified multiple
+                               times (eg. 192.168.1.0/24 or ::1)
+      --feeexemptrpcuser=      Accept transactions submitted via
+                               sendrawtransaction by the specified RPC user
+                               regardless of the minimum relay fee -- must be
+                               the rpcuser or rpclimituser
+      --txpolicyurl=           Consult the external transaction policy service
+                               at the specified HTTP(S) URL, such as an
+                               anti-spam scoring service, before accepting new
+                               transactions to the mempool.  Transactions are
+                               accepted when the service does not respond in
+                               time
+      --txpolicytimeout=       Maximum amount of time to wait for the external
+                               transaction policy service to respond.  Valid
+                               time units are {ms, s}.  Maximum 5 seconds
+                               (default: 250ms)
       --generate               Generate (mine) bitcoins using the CPU
       --miningaddr=            Add the specified payment address to the list of
                                addresses to use for generated blocks -- At least
                                one address is required if the generate option is
                                set
+      --miningaddrpolicy=      Policy used to choose which of the mining
+                               addresses each block template pays to {random,
+                               roundrobin} (default: random)
       --blockminsize=          Minimum block size in bytes to be used when
                                creating a block
       --blockmaxsize=          Maximum block size in bytes to be used when
@@ -174,6 +336,11 @@ Application Options:
                                whether or not an address has even been used
       --dropexistsaddrindex    Deletes the exists address index from the
                                database on start up and then exits
+      --addractivityindex      Maintain an index of the first and last heights
+                               at which each address is seen in the main chain
+                               which makes the getaddressactivity RPC available
+      --dropaddractivityindex  Deletes the address activity index from the
+                               database on start up and then exits
       --nocfilters             (Deprecated) Disable compact filtering (CF)
                                support
       --dropcfindex            (Deprecated) Deletes the index used for compact
@@ -23,6 +23,7 @@ require (
 	github.com/decred/dcrd/dcrutil/v3 v3.0.0-20200503044000-76f6906e50e5
 	github.com/decred/dcrd/gcs/v2 v2.0.1
 	github.com/decred/dcrd/hdkeychain/v3 v3.0.0
+	github.com/decred/dcrd/headersync v1.0.0
 	github.com/decred/dcrd/lru v1.0.0
 	github.com/decred/dcrd/peer/v2 v2.1.0
 	github.com/decred/dcrd/rpc/jsonrpc/types/v2 v2.0.1-0.20200503044000-76f6906e50e5
@@ -59,6 +60,7 @@ replace (
 	github.com/decred/dcrd/dcrutil/v3 => ./dcrutil
 	github.com/decred/dcrd/gcs/v2 => ./gcs
 	github.com/decred/dcrd/hdkeychain/v3 => ./hdkeychain
+	github.com/decred/dcrd/headersync => ./headersync
 	github.com/decred/dcrd/limits => ./limits
 	github.com/decred/dcrd/lru => ./lru
 	github.com/decred/dcrd/peer/v2 => ./peer
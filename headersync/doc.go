@@ -0,0 +1,56 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package headersync implements a headers-first block download state machine with
+checkpoint and minimum known chain work enforcement.
+
+Headers-First Download
+
+When the current tip is before a known checkpoint, block headers can be used to
+learn which blocks comprise the chain up to the checkpoint before downloading
+the blocks themselves.  Since each header commits to the hash of the previous
+header and a merkle root, verifying the received headers link together properly
+and that the checkpoint hashes match proves the hashes of the blocks in between
+are accurate.  Further, once the full blocks are downloaded, the merkle root is
+computed and compared against the value in the header which proves the full
+block has not been tampered with.  This allows the blocks to be processed with
+less validation.
+
+The download proceeds in phases.  Each phase downloads the block headers up to
+the next checkpoint, followed by the blocks they describe.  Optionally, a final
+phase downloads the block headers after the final checkpoint until the remote
+peer runs out of headers to provide and ensures the resulting header chain has
+at least the minimum known chain work so that a remote peer can not stall the
+download by claiming to have a less-worked chain.
+
+External Use
+
+This package has intentionally been designed so it can be used as a standalone
+package for any projects needing to download the Decred block chain, such as
+lightweight clients and block explorers.  The downloader does not perform any
+network communication itself.  Instead, the caller sends the getheaders and
+getdata requests it describes to a remote peer and passes it the responses.
+
+The following is a rough outline of the typical usage:
+
+ - Create a downloader with New for the current tip
+ - Call Start and send a getheaders message for the returned stop hash
+ - Pass received headers to ProcessHeaders and send the request described by
+   HeadersRequest until it indicates the headers for the phase are complete
+ - Request the blocks returned by NextBlocks and pass the hash of each received
+   block to BlockReceived
+ - Call AdvancePhase once the final block of the phase is processed and request
+   the headers described by HeadersRequest when it indicates there are more
+ - Call Reset with the current tip whenever the remote peer changes
+
+Errors
+
+Errors returned by this package are of type headersync.RuleError.  This allows
+the caller to differentiate between errors further up the call stack through
+type assertions.  In addition, callers can programmatically determine the
+specific rule violation by examining the ErrorCode field of the type asserted
+headersync.RuleError.
+*/
+package headersync
@@ -0,0 +1,410 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package headersync
+
+import (
+	"container/list"
+	"fmt"
+	"math/big"
+
+	"github.com/decred/dcrd/blockchain/standalone/v2"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/wire"
+)
+
+// zeroHash is the zero value hash (all zeros).  It is used as the stop hash to
+// request as many headers as possible.
+var zeroHash chainhash.Hash
+
+// Config houses the parameters used to configure a Downloader.
+type Config struct {
+	// Checkpoints are the checkpoints, ordered from oldest to newest, that
+	// the downloaded block headers must match.  No checkpoints disables the
+	// checkpoint phases of the download.
+	Checkpoints []chaincfg.Checkpoint
+
+	// PowLimit is the highest proof of work target permitted.  Block
+	// headers that do not satisfy the proof of work implied by their target
+	// difficulty bits are rejected when it is set.
+	PowLimit *big.Int
+
+	// MinKnownChainWork is the minimum amount of known total work for the
+	// chain.  A remote peer that runs out of block headers to provide after
+	// the final checkpoint before the header chain has at least this much
+	// cumulative work is rejected when it is set and the work of the tip the
+	// download started from is known.
+	MinKnownChainWork *big.Int
+
+	// SyncToTip specifies whether or not to continue downloading block
+	// headers after the final checkpoint until the remote peer runs out of
+	// headers to provide.  Full nodes typically disable it since they fully
+	// validate the blocks after the final checkpoint, while lightweight
+	// clients that only require the header chain enable it.
+	SyncToTip bool
+}
+
+// headerNode is used as a node in a list of headers that are linked together
+// between checkpoints.
+type headerNode struct {
+	height int64
+	hash   chainhash.Hash
+}
+
+// Downloader implements a headers-first download state machine.  The block
+// headers from a known tip up to the next checkpoint are downloaded from a
+// remote peer and verified to link together and match the checkpoint before
+// the blocks they describe are downloaded.  Since each header commits to the
+// previous header and a merkle root, the blocks between checkpoints are then
+// known to be accurate and are eligible for less validation.  This process is
+// repeated for each checkpoint and, optionally, the block headers after the
+// final checkpoint are downloaded until the remote peer runs out of headers
+// to provide while enforcing the minimum known chain work.
+//
+// The downloader does not perform any network communication itself.  Instead,
+// callers send the getheaders and getdata requests it describes to a remote
+// peer and pass it the responses.  This allows it to be embedded in any
+// application that downloads the block chain, such as full nodes, lightweight
+// clients, and block explorers.
+//
+// The downloader is NOT safe for concurrent access.  It is intended to be
+// driven by a single goroutine, such as the one that handles sync messages.
+type Downloader struct {
+	cfg Config
+
+	active         bool
+	headerList     *list.List
+	startHeader    *list.Element
+	nextCheckpoint *chaincfg.Checkpoint
+	tipPhase       bool
+	phaseComplete  bool
+	workSum        *big.Int
+}
+
+// New returns a downloader configured with the provided parameters that starts
+// from the provided tip.  See Reset for details regarding the tip.
+func New(cfg *Config, tipHash *chainhash.Hash, tipHeight int64, tipWork *big.Int) *Downloader {
+	d := &Downloader{
+		cfg:        *cfg,
+		headerList: list.New(),
+	}
+	d.Reset(tipHash, tipHeight, tipWork)
+	return d
+}
+
+// findNextCheckpoint returns the next checkpoint after the passed height.  It
+// returns nil when there is not one either because the height is already later
+// than the final checkpoint or there are no checkpoints.
+func (d *Downloader) findNextCheckpoint(height int64) *chaincfg.Checkpoint {
+	checkpoints := d.cfg.Checkpoints
+	if len(checkpoints) == 0 {
+		return nil
+	}
+
+	// There is no next checkpoint if the height is already after the final
+	// checkpoint.
+	finalCheckpoint := &checkpoints[len(checkpoints)-1]
+	if height >= finalCheckpoint.Height {
+		return nil
+	}
+
+	// Find the next checkpoint.
+	nextCheckpoint := finalCheckpoint
+	for i := len(checkpoints) - 2; i >= 0; i-- {
+		if height >= checkpoints[i].Height {
+			break
+		}
+		nextCheckpoint = &checkpoints[i]
+	}
+	return nextCheckpoint
+}
+
+// Reset discards all downloaded block headers and sets the state to values
+// appropriate for downloading from a new remote peer starting from the provided
+// tip.  It must be called with the current tip whenever the remote peer the
+// download is being performed from changes.
+//
+// The tip work is the cumulative work of the chain up to and including the tip.
+// It may be nil when it is unknown, in which case the minimum known chain work
+// is not enforced.
+func (d *Downloader) Reset(tipHash *chainhash.Hash, tipHeight int64, tipWork *big.Int) {
+	d.active = false
+	d.headerList.Init()
+	d.startHeader = nil
+	d.nextCheckpoint = d.findNextCheckpoint(tipHeight)
+	d.tipPhase = d.nextCheckpoint == nil && d.cfg.SyncToTip
+	d.phaseComplete = false
+	d.workSum = nil
+	if tipWork != nil {
+		d.workSum = new(big.Int).Set(tipWork)
+	}
+
+	// Add an entry for the tip into the header list when there are headers
+	// to download.  This allows the next downloaded header to prove it links
+	// to the chain properly.
+	if d.nextCheckpoint != nil || d.tipPhase {
+		d.headerList.PushBack(&headerNode{height: tipHeight, hash: *tipHash})
+	}
+}
+
+// Start begins downloading block headers when there are any to download and
+// returns the stop hash to request them with.  The caller must send a
+// getheaders message with a block locator for its tip and the returned stop
+// hash to the remote peer.  False is returned when there are no headers to
+// download because the tip is already at or after the final checkpoint and
+// downloading headers to the tip of the remote peer is disabled, in which case
+// the caller should download the blocks normally.
+func (d *Downloader) Start() (*chainhash.Hash, bool) {
+	if d.nextCheckpoint == nil && !d.tipPhase {
+		return nil, false
+	}
+	d.active = true
+	return d.hashStop(), true
+}
+
+// Active returns whether or not block headers or the blocks they describe are
+// being downloaded.  Blocks should be downloaded normally when it is not.
+func (d *Downloader) Active() bool {
+	return d.active
+}
+
+// NextCheckpoint returns the checkpoint the headers currently being downloaded
+// lead to.  It returns nil when there is none because the tip is after the
+// final checkpoint or there are no checkpoints.
+func (d *Downloader) NextCheckpoint() *chaincfg.Checkpoint {
+	return d.nextCheckpoint
+}
+
+// hashStop returns the stop hash for requesting headers for the current phase.
+func (d *Downloader) hashStop() *chainhash.Hash {
+	if d.nextCheckpoint != nil {
+		return d.nextCheckpoint.Hash
+	}
+	return &zeroHash
+}
+
+// HeadersRequest returns the block locator and stop hash the caller must send
+// in a getheaders message to the remote peer to request the next batch of
+// block headers.  It must only be called when ProcessHeaders indicates more
+// headers are required or after starting a new phase via AdvancePhase.
+func (d *Downloader) HeadersRequest() ([]chainhash.Hash, *chainhash.Hash) {
+	var locator []chainhash.Hash
+	if e := d.headerList.Back(); e != nil {
+		locator = []chainhash.Hash{e.Value.(*headerNode).hash}
+	}
+	return locator, d.hashStop()
+}
+
+// ProcessHeaders verifies the provided block headers, which are expected to be
+// the response to the most recent headers request, link together with the
+// previously downloaded headers, satisfy their proof of work when enabled, and
+// match the next checkpoint.
+//
+// It returns true when the headers for the current phase are complete, meaning
+// either the next checkpoint was reached or the remote peer ran out of headers
+// to provide after the final checkpoint, in which case the caller must fetch
+// the blocks they describe via NextBlocks.  The download is complete instead
+// when Active subsequently returns false because the remote peer did not have
+// any new headers.  Otherwise, the caller must request more headers via
+// HeadersRequest unless no headers were provided.
+//
+// The returned error is a RuleError when the headers violate any of the rules
+// and the caller should typically disconnect the remote peer in that case.
+func (d *Downloader) ProcessHeaders(headers []*wire.BlockHeader) (bool, error) {
+	// The remote peer is misbehaving if headers were not requested.
+	if !d.active || d.phaseComplete {
+		str := fmt.Sprintf("received %d unrequested block headers",
+			len(headers))
+		return false, ruleError(ErrUnrequestedHeaders, str)
+	}
+
+	// Nothing to do for an empty headers message in a checkpoint phase.
+	// However, the remote peer does not have any more headers to provide
+	// when downloading headers to its tip.
+	if len(headers) == 0 && !d.tipPhase {
+		return false, nil
+	}
+
+	// Process all of the received headers ensuring each one connects to the
+	// previous and that checkpoints match.
+	for _, header := range headers {
+		// Ensure the header properly connects to the previous one and
+		// add it to the list of headers.  Note that the list is never
+		// empty while active since it always contains at least the
+		// header that the next one must connect to.
+		hash := header.BlockHash()
+		prevNode := d.headerList.Back().Value.(*headerNode)
+		if prevNode.hash != header.PrevBlock {
+			str := fmt.Sprintf("block header %s does not connect to "+
+				"previous header %s", hash, prevNode.hash)
+			return false, ruleError(ErrDisconnectedHeader, str)
+		}
+
+		// Ensure the header satisfies its claimed proof of work when
+		// enabled.
+		if d.cfg.PowLimit != nil {
+			err := standalone.CheckProofOfWork(&hash, header.Bits,
+				d.cfg.PowLimit)
+			if err != nil {
+				str := fmt.Sprintf("block header %s: %v", hash, err)
+				return false, ruleError(ErrBadProofOfWork, str)
+			}
+		}
+
+		node := &headerNode{height: prevNode.height + 1, hash: hash}
+		e := d.headerList.PushBack(node)
+		if d.startHeader == nil {
+			d.startHeader = e
+		}
+		if d.workSum != nil {
+			d.workSum.Add(d.workSum, standalone.CalcWork(header.Bits))
+		}
+
+		// Verify the header at the next checkpoint height matches.
+		if d.nextCheckpoint != nil && node.height == d.nextCheckpoint.Height {
+			if node.hash != *d.nextCheckpoint.Hash {
+				str := fmt.Sprintf("block header at height %d/hash %s "+
+					"does not match expected checkpoint hash of %s",
+					node.height, node.hash, d.nextCheckpoint.Hash)
+				return false, ruleError(ErrCheckpointMismatch, str)
+			}
+			d.completePhase()
+			return true, nil
+		}
+	}
+
+	// The remote peer has run out of headers to provide when it sends fewer
+	// than the maximum allowed number of headers in response to a request for
+	// headers up to its tip.  Ensure the resulting header chain has at least
+	// the minimum known chain work when enforced.
+	if d.tipPhase && len(headers) < wire.MaxBlockHeadersPerMsg {
+		minWork := d.cfg.MinKnownChainWork
+		if minWork != nil && d.workSum != nil && d.workSum.Cmp(minWork) < 0 {
+			str := fmt.Sprintf("header chain work of %v is less than the "+
+				"minimum known chain work of %v", d.workSum, minWork)
+			return false, ruleError(ErrInsufficientWork, str)
+		}
+
+		// The download is complete when there are no new headers since
+		// there are no blocks to fetch.
+		if d.headerList.Len() == 1 {
+			d.headerList.Init()
+			d.active = false
+			d.tipPhase = false
+			return true, nil
+		}
+		d.completePhase()
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// completePhase marks the headers for the current phase as complete so the
+// blocks they describe can be fetched.
+func (d *Downloader) completePhase() {
+	// Since the first entry of the list is always the final block of the
+	// previous phase that is already known and is only used to ensure the
+	// next header links properly, it is removed before fetching the blocks.
+	d.phaseComplete = true
+	d.headerList.Remove(d.headerList.Front())
+}
+
+// HasPendingBlocks returns whether or not there are blocks described by the
+// downloaded block headers that have not been returned by NextBlocks yet.
+func (d *Downloader) HasPendingBlocks() bool {
+	return d.phaseComplete && d.startHeader != nil
+}
+
+// NextBlocks returns up to the provided maximum number of hashes of blocks
+// described by the downloaded block headers that the caller must request from
+// the remote peer in order.  The provided function is used to skip blocks the
+// caller already has.  Each block is only returned once.
+func (d *Downloader) NextBlocks(max int, have func(hash *chainhash.Hash) bool) []chainhash.Hash {
+	if !d.phaseComplete || max <= 0 {
+		return nil
+	}
+
+	sizeHint := d.headerList.Len()
+	if sizeHint > max {
+		sizeHint = max
+	}
+	hashes := make([]chainhash.Hash, 0, sizeHint)
+	for e := d.startHeader; e != nil; e = e.Next() {
+		node := e.Value.(*headerNode)
+		if !have(&node.hash) {
+			hashes = append(hashes, node.hash)
+		}
+		d.startHeader = e.Next()
+		if len(hashes) >= max {
+			break
+		}
+	}
+	return hashes
+}
+
+// BlockReceived updates the state for the block with the provided hash that was
+// received from the remote peer.  It returns whether or not the block is
+// described by a block header that was verified against a checkpoint, and is
+// therefore eligible for less validation, and whether or not it is the final
+// block of the current phase.  The caller must invoke AdvancePhase after
+// processing the final block.
+func (d *Downloader) BlockReceived(hash *chainhash.Hash) (bool, bool) {
+	if !d.active || !d.phaseComplete {
+		return false, false
+	}
+
+	// Only the block that matches the first header in the list is
+	// considered.  Remove the list entry for all blocks except the final
+	// block of the phase since it is needed to verify the next round of
+	// headers links properly.
+	firstNodeEl := d.headerList.Front()
+	if firstNodeEl == nil {
+		return false, false
+	}
+	firstNode := firstNodeEl.Value.(*headerNode)
+	if firstNode.hash != *hash {
+		return false, false
+	}
+	verified := !d.tipPhase
+	if firstNodeEl == d.headerList.Back() {
+		return verified, true
+	}
+	d.headerList.Remove(firstNodeEl)
+	return verified, false
+}
+
+// AdvancePhase advances the download past the final block of the current phase
+// once it has been processed.  It returns true when there are more block
+// headers to download, in which case the caller must request them via
+// HeadersRequest.  Otherwise, the download is complete and the caller should
+// download any further blocks normally.
+func (d *Downloader) AdvancePhase() bool {
+	lastEl := d.headerList.Back()
+	if !d.active || !d.phaseComplete || lastEl == nil {
+		return false
+	}
+	lastNode := lastEl.Value.(*headerNode)
+
+	// Download the headers for the next checkpoint, or to the tip of the
+	// remote peer when enabled after the final checkpoint, starting from the
+	// block after the final block of this phase.
+	d.headerList.Init()
+	d.startHeader = nil
+	d.phaseComplete = false
+	if !d.tipPhase {
+		d.nextCheckpoint = d.findNextCheckpoint(lastNode.height)
+		d.tipPhase = d.nextCheckpoint == nil && d.cfg.SyncToTip
+		if d.nextCheckpoint != nil || d.tipPhase {
+			d.headerList.PushBack(lastNode)
+			return true
+		}
+	}
+
+	// There are no more headers to download.
+	d.active = false
+	d.tipPhase = false
+	return false
+}
@@ -0,0 +1,388 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package headersync
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/blockchain/standalone/v2"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/wire"
+)
+
+// simNetPowLimitBits is the compact target difficulty of the simulation test
+// network proof of work limit.  Roughly half of all hashes satisfy it.
+const simNetPowLimitBits = 0x207fffff
+
+// genHeaders returns the requested number of block headers that build on the
+// provided tip and satisfy the simulation test network proof of work limit.
+// The tip header is the first returned header.
+func genHeaders(t *testing.T, numHeaders int) []*wire.BlockHeader {
+	t.Helper()
+
+	powLimit := chaincfg.SimNetParams().PowLimit
+	timestamp := time.Unix(1592918788, 0)
+	headers := make([]*wire.BlockHeader, 0, numHeaders+1)
+	headers = append(headers, &wire.BlockHeader{
+		Bits:      simNetPowLimitBits,
+		Timestamp: timestamp,
+	})
+	for i := 1; i <= numHeaders; i++ {
+		header := &wire.BlockHeader{
+			PrevBlock: headers[i-1].BlockHash(),
+			Bits:      simNetPowLimitBits,
+			Height:    uint32(i),
+			Timestamp: timestamp.Add(time.Duration(i) * time.Minute),
+		}
+		for {
+			hash := header.BlockHash()
+			err := standalone.CheckProofOfWork(&hash, header.Bits, powLimit)
+			if err == nil {
+				break
+			}
+			header.Nonce++
+		}
+		headers = append(headers, header)
+	}
+	return headers
+}
+
+// hashesOf returns the hashes of the provided block headers.
+func hashesOf(headers []*wire.BlockHeader) []chainhash.Hash {
+	hashes := make([]chainhash.Hash, 0, len(headers))
+	for _, header := range headers {
+		hashes = append(hashes, header.BlockHash())
+	}
+	return hashes
+}
+
+// checkpointFor returns a checkpoint for the provided block header.
+func checkpointFor(header *wire.BlockHeader) chaincfg.Checkpoint {
+	hash := header.BlockHash()
+	return chaincfg.Checkpoint{Height: int64(header.Height), Hash: &hash}
+}
+
+// haveNone is a function to use with NextBlocks that does not have any blocks.
+func haveNone(*chainhash.Hash) bool {
+	return false
+}
+
+// TestDownloaderCheckpoints ensures the downloader proceeds through the
+// checkpoint phases as expected.
+func TestDownloaderCheckpoints(t *testing.T) {
+	headers := genHeaders(t, 12)
+	tipHash := headers[0].BlockHash()
+	cfg := &Config{
+		Checkpoints: []chaincfg.Checkpoint{
+			checkpointFor(headers[5]),
+			checkpointFor(headers[12]),
+		},
+		PowLimit: chaincfg.SimNetParams().PowLimit,
+	}
+	d := New(cfg, &tipHash, 0, nil)
+	if d.Active() {
+		t.Fatal("downloader is active before starting")
+	}
+	if cp := d.NextCheckpoint(); cp == nil || cp.Height != 5 {
+		t.Fatalf("unexpected next checkpoint %v", cp)
+	}
+
+	// Ensure the first request is for the headers up to the first
+	// checkpoint.
+	hashStop, ok := d.Start()
+	if !ok || *hashStop != headers[5].BlockHash() {
+		t.Fatalf("unexpected start result -- got %v, %v", hashStop, ok)
+	}
+
+	// Ensure headers that do not reach the checkpoint require another
+	// request starting from the final provided header.
+	done, err := d.ProcessHeaders(headers[1:4])
+	if err != nil || done {
+		t.Fatalf("unexpected process result -- got %v, %v", done, err)
+	}
+	locator, hashStop := d.HeadersRequest()
+	wantLocator := hashesOf(headers[3:4])
+	if !reflect.DeepEqual(locator, wantLocator) ||
+		*hashStop != headers[5].BlockHash() {
+
+		t.Fatalf("unexpected headers request -- got %v, %v", locator,
+			hashStop)
+	}
+
+	// Ensure headers after the checkpoint are ignored and that reaching the
+	// checkpoint completes the phase.
+	done, err = d.ProcessHeaders(headers[4:7])
+	if err != nil || !done {
+		t.Fatalf("unexpected process result -- got %v, %v", done, err)
+	}
+
+	// Ensure the blocks are returned in order while respecting the maximum
+	// and skipping those that are already available.
+	if !d.HasPendingBlocks() {
+		t.Fatal("no pending blocks after completing headers")
+	}
+	blocks := d.NextBlocks(2, haveNone)
+	if !reflect.DeepEqual(blocks, hashesOf(headers[1:3])) {
+		t.Fatalf("unexpected blocks -- got %v", blocks)
+	}
+	have3 := func(hash *chainhash.Hash) bool {
+		return *hash == headers[3].BlockHash()
+	}
+	blocks = d.NextBlocks(10, have3)
+	if !reflect.DeepEqual(blocks, hashesOf(headers[4:6])) {
+		t.Fatalf("unexpected blocks -- got %v", blocks)
+	}
+	if d.HasPendingBlocks() {
+		t.Fatal("pending blocks after all were returned")
+	}
+
+	// Ensure all received blocks are eligible for less validation and only
+	// the checkpoint is the final block of the phase.
+	unknownHash := chainhash.Hash{0x01}
+	if fastAdd, final := d.BlockReceived(&unknownHash); fastAdd || final {
+		t.Fatalf("unexpected result for unknown block -- got %v, %v",
+			fastAdd, final)
+	}
+	for i := 1; i <= 5; i++ {
+		hash := headers[i].BlockHash()
+		fastAdd, final := d.BlockReceived(&hash)
+		if !fastAdd || final != (i == 5) {
+			t.Fatalf("unexpected result for block %d -- got %v, %v", i,
+				fastAdd, final)
+		}
+	}
+
+	// Ensure advancing past the checkpoint requests the headers up to the
+	// next checkpoint starting from it.
+	if !d.AdvancePhase() {
+		t.Fatal("did not advance to the next checkpoint")
+	}
+	locator, hashStop = d.HeadersRequest()
+	wantLocator = hashesOf(headers[5:6])
+	if !reflect.DeepEqual(locator, wantLocator) ||
+		*hashStop != headers[12].BlockHash() {
+
+		t.Fatalf("unexpected headers request -- got %v, %v", locator,
+			hashStop)
+	}
+	done, err = d.ProcessHeaders(headers[6:])
+	if err != nil || !done {
+		t.Fatalf("unexpected process result -- got %v, %v", done, err)
+	}
+	blocks = d.NextBlocks(10, haveNone)
+	if !reflect.DeepEqual(blocks, hashesOf(headers[6:])) {
+		t.Fatalf("unexpected blocks -- got %v", blocks)
+	}
+	for i := 6; i <= 12; i++ {
+		hash := headers[i].BlockHash()
+		fastAdd, final := d.BlockReceived(&hash)
+		if !fastAdd || final != (i == 12) {
+			t.Fatalf("unexpected result for block %d -- got %v, %v", i,
+				fastAdd, final)
+		}
+	}
+
+	// Ensure the download is complete after the final checkpoint.
+	if d.AdvancePhase() {
+		t.Fatal("advanced past the final checkpoint")
+	}
+	if d.Active() {
+		t.Fatal("downloader is active after the final checkpoint")
+	}
+	if _, err := d.ProcessHeaders(headers[1:2]); !IsErrorCode(err,
+		ErrUnrequestedHeaders) {
+
+		t.Fatalf("unexpected error for unrequested headers -- got %v", err)
+	}
+
+	// Ensure there is nothing to download when starting after the final
+	// checkpoint.
+	finalHash := headers[12].BlockHash()
+	d.Reset(&finalHash, 12, nil)
+	if _, ok := d.Start(); ok {
+		t.Fatal("started after the final checkpoint")
+	}
+}
+
+// TestDownloaderErrors ensures the downloader rejects block headers that
+// violate the rules with the expected error codes.
+func TestDownloaderErrors(t *testing.T) {
+	headers := genHeaders(t, 5)
+	tipHash := headers[0].BlockHash()
+
+	// Create a header that does not satisfy its proof of work by finding a
+	// nonce for which the hash is too high.
+	badPow := *headers[1]
+	powLimit := chaincfg.SimNetParams().PowLimit
+	for {
+		hash := badPow.BlockHash()
+		if standalone.CheckProofOfWork(&hash, badPow.Bits, powLimit) != nil {
+			break
+		}
+		badPow.Nonce++
+	}
+
+	// Create a checkpoint at the final height for a different header.
+	wrongHash := chainhash.Hash{0x01}
+	checkpoints := []chaincfg.Checkpoint{{Height: 5, Hash: &wrongHash}}
+
+	tests := []struct {
+		name    string
+		headers []*wire.BlockHeader
+		err     ErrorCode
+	}{{
+		name:    "header does not connect",
+		headers: []*wire.BlockHeader{headers[1], headers[3]},
+		err:     ErrDisconnectedHeader,
+	}, {
+		name:    "header with bad proof of work",
+		headers: []*wire.BlockHeader{&badPow},
+		err:     ErrBadProofOfWork,
+	}, {
+		name:    "header does not match checkpoint",
+		headers: headers[1:],
+		err:     ErrCheckpointMismatch,
+	}}
+	for _, test := range tests {
+		d := New(&Config{Checkpoints: checkpoints, PowLimit: powLimit},
+			&tipHash, 0, nil)
+		if _, ok := d.Start(); !ok {
+			t.Fatalf("%s: failed to start", test.name)
+		}
+		_, err := d.ProcessHeaders(test.headers)
+		if !IsErrorCode(err, test.err) {
+			t.Errorf("%s: unexpected error -- got %v, want %v", test.name,
+				err, test.err)
+		}
+	}
+
+	// Ensure headers are not accepted before starting.
+	d := New(&Config{Checkpoints: checkpoints}, &tipHash, 0, nil)
+	_, err := d.ProcessHeaders(headers[1:])
+	if !IsErrorCode(err, ErrUnrequestedHeaders) {
+		t.Fatalf("unexpected error for unrequested headers -- got %v", err)
+	}
+}
+
+// TestDownloaderSyncToTip ensures the downloader continues downloading block
+// headers after the final checkpoint when enabled and enforces the minimum
+// known chain work.
+func TestDownloaderSyncToTip(t *testing.T) {
+	headers := genHeaders(t, 6)
+	tipHash := headers[0].BlockHash()
+	workPerHeader := standalone.CalcWork(simNetPowLimitBits)
+	minWork := new(big.Int).Mul(workPerHeader, big.NewInt(6))
+	cfg := &Config{
+		Checkpoints:       []chaincfg.Checkpoint{checkpointFor(headers[2])},
+		MinKnownChainWork: minWork,
+		SyncToTip:         true,
+	}
+
+	// Ensure downloading headers after the final checkpoint is not possible
+	// without enabling it.
+	noTipCfg := *cfg
+	noTipCfg.SyncToTip = false
+	d := New(&noTipCfg, &tipHash, 0, nil)
+	d.Start()
+	if _, err := d.ProcessHeaders(headers[1:3]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d.NextBlocks(10, haveNone)
+	for _, hash := range hashesOf(headers[1:3]) {
+		d.BlockReceived(&hash)
+	}
+	if d.AdvancePhase() || d.Active() {
+		t.Fatal("advanced past the final checkpoint when disabled")
+	}
+
+	// Ensure the tip phase follows the checkpoint phase when enabled and
+	// requests headers up to the tip of the remote peer.
+	tipWork := new(big.Int).Set(workPerHeader)
+	d = New(cfg, &tipHash, 0, tipWork)
+	d.Start()
+	if _, err := d.ProcessHeaders(headers[1:3]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d.NextBlocks(10, haveNone)
+	for _, hash := range hashesOf(headers[1:3]) {
+		d.BlockReceived(&hash)
+	}
+	if !d.AdvancePhase() {
+		t.Fatal("did not advance to the tip phase")
+	}
+	if d.NextCheckpoint() != nil {
+		t.Fatal("unexpected next checkpoint in the tip phase")
+	}
+	locator, hashStop := d.HeadersRequest()
+	if !reflect.DeepEqual(locator, hashesOf(headers[2:3])) ||
+		*hashStop != zeroHash {
+
+		t.Fatalf("unexpected headers request -- got %v, %v", locator,
+			hashStop)
+	}
+
+	// Ensure a remote peer that runs out of headers before reaching the
+	// minimum known chain work is rejected.  The tip work accounts for the
+	// first header, so the chain through the fourth header has 5 headers
+	// worth of work.
+	_, err := d.ProcessHeaders(headers[3:5])
+	if !IsErrorCode(err, ErrInsufficientWork) {
+		t.Fatalf("unexpected error -- got %v, want %v", err,
+			ErrInsufficientWork)
+	}
+
+	// Ensure reaching the minimum known chain work completes the phase and
+	// that the blocks are not eligible for less validation.
+	d = New(cfg, &headers[2].PrevBlock, 1, new(big.Int).Mul(workPerHeader,
+		big.NewInt(2)))
+	d.Start()
+	if _, err := d.ProcessHeaders(headers[2:3]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d.NextBlocks(10, haveNone)
+	hash := headers[2].BlockHash()
+	d.BlockReceived(&hash)
+	if !d.AdvancePhase() {
+		t.Fatal("did not advance to the tip phase")
+	}
+	done, err := d.ProcessHeaders(headers[3:])
+	if err != nil || !done {
+		t.Fatalf("unexpected process result -- got %v, %v", done, err)
+	}
+	blocks := d.NextBlocks(10, haveNone)
+	if !reflect.DeepEqual(blocks, hashesOf(headers[3:])) {
+		t.Fatalf("unexpected blocks -- got %v", blocks)
+	}
+	for i := 3; i <= 6; i++ {
+		hash := headers[i].BlockHash()
+		fastAdd, final := d.BlockReceived(&hash)
+		if fastAdd || final != (i == 6) {
+			t.Fatalf("unexpected result for block %d -- got %v, %v", i,
+				fastAdd, final)
+		}
+	}
+	if d.AdvancePhase() || d.Active() {
+		t.Fatal("downloader is active after the tip phase")
+	}
+
+	// Ensure the download completes without any blocks to fetch when the
+	// remote peer does not have any new headers.
+	finalHash := headers[6].BlockHash()
+	d.Reset(&finalHash, 6, nil)
+	if _, ok := d.Start(); !ok {
+		t.Fatal("failed to start the tip phase")
+	}
+	done, err = d.ProcessHeaders(nil)
+	if err != nil || !done {
+		t.Fatalf("unexpected process result -- got %v, %v", done, err)
+	}
+	if d.Active() || d.HasPendingBlocks() {
+		t.Fatal("downloader is active without any new headers")
+	}
+}
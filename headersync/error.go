@@ -0,0 +1,81 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package headersync
+
+import (
+	"fmt"
+)
+
+// ErrorCode identifies a kind of error.
+type ErrorCode int
+
+// These constants are used to identify a specific RuleError.
+const (
+	// ErrUnrequestedHeaders indicates block headers were provided while
+	// the downloader was not expecting any.
+	ErrUnrequestedHeaders ErrorCode = iota
+
+	// ErrDisconnectedHeader indicates a block header does not reference
+	// the previous header in the header list.
+	ErrDisconnectedHeader
+
+	// ErrBadProofOfWork indicates a block header does not satisfy the proof
+	// of work requirements implied by its target difficulty bits.
+	ErrBadProofOfWork
+
+	// ErrCheckpointMismatch indicates the block header at the height of a
+	// checkpoint does not have the expected hash.
+	ErrCheckpointMismatch
+
+	// ErrInsufficientWork indicates a remote peer ran out of block headers
+	// to provide before the cumulative work of the header chain reached the
+	// minimum known chain work.
+	ErrInsufficientWork
+
+	// numErrorCodes is the maximum error code number used in tests.
+	numErrorCodes
+)
+
+// Map of ErrorCode values back to their constant names for pretty printing.
+var errorCodeStrings = map[ErrorCode]string{
+	ErrUnrequestedHeaders: "ErrUnrequestedHeaders",
+	ErrDisconnectedHeader: "ErrDisconnectedHeader",
+	ErrBadProofOfWork:     "ErrBadProofOfWork",
+	ErrCheckpointMismatch: "ErrCheckpointMismatch",
+	ErrInsufficientWork:   "ErrInsufficientWork",
+}
+
+// String returns the ErrorCode as a human-readable name.
+func (e ErrorCode) String() string {
+	if s := errorCodeStrings[e]; s != "" {
+		return s
+	}
+	return fmt.Sprintf("Unknown ErrorCode (%d)", int(e))
+}
+
+// RuleError identifies a rule violation.  The caller can use type assertions to
+// determine if a failure was specifically due to a rule violation and access
+// the ErrorCode field to ascertain the specific reason for the rule violation.
+type RuleError struct {
+	ErrorCode   ErrorCode // Describes the kind of error
+	Description string    // Human readable description of the issue
+}
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e RuleError) Error() string {
+	return e.Description
+}
+
+// ruleError creates an RuleError given a set of arguments.
+func ruleError(c ErrorCode, desc string) RuleError {
+	return RuleError{ErrorCode: c, Description: desc}
+}
+
+// IsErrorCode returns whether or not the provided error is a rule error with
+// the provided error code.
+func IsErrorCode(err error, c ErrorCode) bool {
+	e, ok := err.(RuleError)
+	return ok && e.ErrorCode == c
+}
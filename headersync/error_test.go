@@ -0,0 +1,72 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package headersync
+
+import (
+	"testing"
+)
+
+// TestErrorCodeStringer tests the stringized output for the ErrorCode type.
+func TestErrorCodeStringer(t *testing.T) {
+	tests := []struct {
+		in   ErrorCode
+		want string
+	}{
+		{ErrUnrequestedHeaders, "ErrUnrequestedHeaders"},
+		{ErrDisconnectedHeader, "ErrDisconnectedHeader"},
+		{ErrBadProofOfWork, "ErrBadProofOfWork"},
+		{ErrCheckpointMismatch, "ErrCheckpointMismatch"},
+		{ErrInsufficientWork, "ErrInsufficientWork"},
+		{0xffff, "Unknown ErrorCode (65535)"},
+	}
+
+	// Detect additional error codes that don't have the stringer added.
+	if len(tests)-1 != int(numErrorCodes) {
+		t.Errorf("It appears an error code was added without adding an " +
+			"associated stringer test")
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		result := test.in.String()
+		if result != test.want {
+			t.Errorf("String #%d\n got: %s want: %s", i, result, test.want)
+			continue
+		}
+	}
+}
+
+// TestIsErrorCode ensures IsErrorCode works as intended.
+func TestIsErrorCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code ErrorCode
+		want bool
+	}{{
+		name: "ErrCheckpointMismatch testing for ErrCheckpointMismatch",
+		err:  ruleError(ErrCheckpointMismatch, ""),
+		code: ErrCheckpointMismatch,
+		want: true,
+	}, {
+		name: "ErrCheckpointMismatch testing for ErrDisconnectedHeader",
+		err:  ruleError(ErrCheckpointMismatch, ""),
+		code: ErrDisconnectedHeader,
+		want: false,
+	}, {
+		name: "nil error testing for ErrCheckpointMismatch",
+		err:  nil,
+		code: ErrCheckpointMismatch,
+		want: false,
+	}}
+	for _, test := range tests {
+		result := IsErrorCode(test.err, test.code)
+		if result != test.want {
+			t.Errorf("%s: unexpected result -- got: %v want: %v", test.name,
+				result, test.want)
+			continue
+		}
+	}
+}
@@ -0,0 +1,15 @@
+module github.com/decred/dcrd/headersync
+
+go 1.11
+
+require (
+	github.com/decred/dcrd/blockchain/standalone/v2 v2.0.0
+	github.com/decred/dcrd/chaincfg/chainhash v1.0.2
+	github.com/decred/dcrd/chaincfg/v3 v3.0.0-20200215031403-6b2ce76f0986
+	github.com/decred/dcrd/wire v1.3.0
+)
+
+replace (
+	github.com/decred/dcrd/blockchain/standalone/v2 => ../blockchain/standalone
+	github.com/decred/dcrd/chaincfg/v3 => ../chaincfg
+)
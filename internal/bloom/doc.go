@@ -0,0 +1,42 @@
+// Copyright (c) 2014-2016 The btcsuite developers
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package bloom implements BIP37-style bloom filters for legacy SPV clients.
+
+Clients load a bloom filter into the connection with a peer by sending it a
+filterload message after which the peer only relays transactions that match the
+filter and serves merkleblock messages that prove which transactions of a block
+match it.  Since the data a client adds to its filter reveals which
+transactions it is interested in and the filter must be matched against every
+transaction by the serving peer, bloom filters are both less private and more
+expensive to serve than version 2 committed filters, which clients should
+prefer whenever possible.
+
+Filters
+
+A filter is a bit field along with a number of independent hash functions that
+are derived from MurmurHash3 seeded with a caller-provided tweak.  Data is added
+to a filter by setting the bits selected by each hash function and matches when
+all of them are set, so a filter never produces false negatives, but may
+produce false positives at a rate that depends on the proportion of bits that
+are set.
+
+A transaction matches a filter when its hash, any outpoint it spends, or any
+data pushed by its scripts matches.  When data pushed by an output script
+matches, the outpoint of the output may also be added to the filter, depending
+on the bloom update flags of the filter, so transactions which later spend the
+output also match without the client needing to update the filter.
+
+Merkle Blocks
+
+NewMerkleBlock creates a merkleblock message for a block that contains separate
+partial merkle trees for the regular and stake transaction trees.  Each partial
+tree contains the hashes and flag bits required to recompute the merkle root of
+its transaction tree while proving the inclusion of the matched transactions.
+The leaves of the trees are the full hashes of the transactions, including
+witness data, as required for transaction tree merkle roots.
+*/
+package bloom
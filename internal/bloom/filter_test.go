@@ -0,0 +1,255 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package bloom
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/decred/dcrd/wire"
+)
+
+// testPubKey is a compressed public key used to create pay-to-pubkey scripts
+// in the tests.
+var testPubKey = append([]byte{0x02}, bytes.Repeat([]byte{0x5a}, 32)...)
+
+// payToPubKeyScript returns a pay-to-pubkey script for testPubKey.
+func payToPubKeyScript() []byte {
+	script := append([]byte{0x21}, testPubKey...) // OP_DATA_33 <pubkey>
+	return append(script, 0xac)                   // OP_CHECKSIG
+}
+
+// payToPubKeyHashScript returns a pay-to-pubkey-hash script for the passed
+// 20-byte hash.
+func payToPubKeyHashScript(hash []byte) []byte {
+	script := []byte{0x76, 0xa9, 0x14} // OP_DUP OP_HASH160 OP_DATA_20
+	script = append(script, hash...)
+	return append(script, 0x88, 0xac) // OP_EQUALVERIFY OP_CHECKSIG
+}
+
+// testTx returns a regular transaction that spends the passed outpoint to an
+// output paying to the passed script.
+func testTx(prevOut *wire.OutPoint, pkScript []byte) *dcrutil.Tx {
+	msgTx := wire.NewMsgTx()
+	msgTx.AddTxIn(wire.NewTxIn(prevOut, 0, []byte{0x01, 0x01}))
+	msgTx.AddTxOut(wire.NewTxOut(1e8, pkScript))
+	return dcrutil.NewTx(msgTx)
+}
+
+// TestFilterLarge ensures a maximum sized filter can be created.
+func TestFilterLarge(t *testing.T) {
+	f := NewFilter(100000000, 0, 0.01, wire.BloomUpdateNone)
+	msg := f.MsgFilterLoad()
+	if len(msg.Filter) > wire.MaxFilterLoadFilterSize {
+		t.Fatalf("TestFilterLarge test failed: %d > %d", len(msg.Filter),
+			wire.MaxFilterLoadFilterSize)
+	}
+	if msg.HashFuncs > wire.MaxFilterLoadHashFuncs {
+		t.Fatalf("TestFilterLarge test failed: %d > %d", msg.HashFuncs,
+			wire.MaxFilterLoadHashFuncs)
+	}
+}
+
+// TestFilterLoad ensures loading, reloading, and unloading filters works as
+// expected and that filters without any data never match.
+func TestFilterLoad(t *testing.T) {
+	merkle := wire.MsgFilterLoad{}
+
+	f := LoadFilter(&merkle)
+	if !f.IsLoaded() {
+		t.Fatal("TestFilterLoad IsLoaded test failed: want true, got false")
+	}
+	if f.Matches([]byte{0x01}) {
+		t.Fatal("TestFilterLoad empty filter matched data")
+	}
+	f.Add([]byte{0x01})
+
+	f.Reload(NewFilter(10, 0, 0.000001, wire.BloomUpdateNone).MsgFilterLoad())
+	f.Add([]byte{0x01})
+	if !f.Matches([]byte{0x01}) {
+		t.Fatal("TestFilterLoad reloaded filter did not match added data")
+	}
+
+	f.Unload()
+	if f.IsLoaded() {
+		t.Fatal("TestFilterLoad IsLoaded test failed: want false, got true")
+	}
+	if f.Matches([]byte{0x01}) {
+		t.Fatal("TestFilterLoad unloaded filter matched data")
+	}
+}
+
+// TestFilterInsert ensures added data, hashes, and outpoints match the filter
+// while other data does not.
+func TestFilterInsert(t *testing.T) {
+	f := NewFilter(3, 0, 0.000001, wire.BloomUpdateAll)
+
+	data := []byte{0x99, 0x10, 0x8a, 0xd8, 0xed, 0x9b, 0xb6, 0x27, 0x4d, 0x39}
+	f.Add(data)
+	if !f.Matches(data) {
+		t.Fatal("TestFilterInsert added data does not match")
+	}
+	if f.Matches([]byte{0x19, 0x10, 0x8a, 0xd8, 0xed, 0x9b, 0xb6, 0x27}) {
+		t.Fatal("TestFilterInsert matched data that was not added")
+	}
+
+	hash := chainhash.HashH([]byte("hash"))
+	f.AddHash(&hash)
+	if !f.Matches(hash[:]) {
+		t.Fatal("TestFilterInsert added hash does not match")
+	}
+
+	outpoint := wire.NewOutPoint(&hash, 1, wire.TxTreeRegular)
+	if f.MatchesOutPoint(outpoint) {
+		t.Fatal("TestFilterInsert matched outpoint that was not added")
+	}
+	f.AddOutPoint(outpoint)
+	if !f.MatchesOutPoint(outpoint) {
+		t.Fatal("TestFilterInsert added outpoint does not match")
+	}
+	otherOutpoint := wire.NewOutPoint(&hash, 2, wire.TxTreeRegular)
+	if f.MatchesOutPoint(otherOutpoint) {
+		t.Fatal("TestFilterInsert matched outpoint that was not added")
+	}
+}
+
+// TestFilterFalsePositiveRate ensures the estimated false positive rate of a
+// filter reflects the proportion of bits that are set.
+func TestFilterFalsePositiveRate(t *testing.T) {
+	f := NewFilter(10, 0, 0.0001, wire.BloomUpdateNone)
+	if rate := f.FalsePositiveRate(); rate != 0 {
+		t.Fatalf("unexpected rate for empty filter: got %v, want 0", rate)
+	}
+
+	for i := 0; i < 10; i++ {
+		f.Add([]byte{byte(i)})
+	}
+	if rate := f.FalsePositiveRate(); rate <= 0 || rate > 0.001 {
+		t.Fatalf("unexpected rate for filter at capacity: got %v", rate)
+	}
+
+	// A filter with every bit set matches everything.
+	msg := f.MsgFilterLoad()
+	f.Reload(wire.NewMsgFilterLoad(bytes.Repeat([]byte{0xff},
+		len(msg.Filter)), msg.HashFuncs, 0, wire.BloomUpdateNone))
+	if rate := f.FalsePositiveRate(); rate != 1 {
+		t.Fatalf("unexpected rate for full filter: got %v, want 1", rate)
+	}
+
+	f.Unload()
+	if rate := f.FalsePositiveRate(); rate != 0 {
+		t.Fatalf("unexpected rate for unloaded filter: got %v, want 0", rate)
+	}
+}
+
+// TestFilterMatchTxAndUpdate ensures transactions are matched by their hash,
+// the data pushed by their scripts, and the outpoints they spend, and that the
+// filter is updated according to its bloom update flags.
+func TestFilterMatchTxAndUpdate(t *testing.T) {
+	fundingHash := chainhash.HashH([]byte("funding"))
+	fundingOut := wire.NewOutPoint(&fundingHash, 0, wire.TxTreeRegular)
+	pkHash := bytes.Repeat([]byte{0x11}, 20)
+	tx := testTx(fundingOut, payToPubKeyHashScript(pkHash))
+	p2pkTx := testTx(fundingOut, payToPubKeyScript())
+
+	tests := []struct {
+		name       string
+		flags      wire.BloomUpdateType
+		tx         *dcrutil.Tx
+		add        []byte
+		addOut     *wire.OutPoint
+		wantMatch  bool
+		wantUpdate bool
+	}{{
+		name:      "no match",
+		flags:     wire.BloomUpdateAll,
+		tx:        tx,
+		add:       []byte{0x01, 0x02},
+		wantMatch: false,
+	}, {
+		name:      "tx hash",
+		flags:     wire.BloomUpdateNone,
+		tx:        tx,
+		add:       tx.Hash()[:],
+		wantMatch: true,
+	}, {
+		name:      "spent outpoint",
+		flags:     wire.BloomUpdateNone,
+		tx:        tx,
+		addOut:    fundingOut,
+		wantMatch: true,
+	}, {
+		name:      "signature script data",
+		flags:     wire.BloomUpdateNone,
+		tx:        tx,
+		add:       []byte{0x01},
+		wantMatch: true,
+	}, {
+		name:       "pubkey hash without update",
+		flags:      wire.BloomUpdateNone,
+		tx:         tx,
+		add:        pkHash,
+		wantMatch:  true,
+		wantUpdate: false,
+	}, {
+		name:       "pubkey hash with update all",
+		flags:      wire.BloomUpdateAll,
+		tx:         tx,
+		add:        pkHash,
+		wantMatch:  true,
+		wantUpdate: true,
+	}, {
+		name:       "pubkey hash with update p2pubkey only",
+		flags:      wire.BloomUpdateP2PubkeyOnly,
+		tx:         tx,
+		add:        pkHash,
+		wantMatch:  true,
+		wantUpdate: false,
+	}, {
+		name:       "pubkey with update p2pubkey only",
+		flags:      wire.BloomUpdateP2PubkeyOnly,
+		tx:         p2pkTx,
+		add:        testPubKey,
+		wantMatch:  true,
+		wantUpdate: true,
+	}}
+
+	for _, test := range tests {
+		f := NewFilter(10, 0, 0.000001, test.flags)
+		if test.add != nil {
+			f.Add(test.add)
+		}
+		if test.addOut != nil {
+			f.AddOutPoint(test.addOut)
+		}
+
+		if match := f.MatchTxAndUpdate(test.tx); match != test.wantMatch {
+			t.Errorf("%q: unexpected match result -- got %v, want %v",
+				test.name, match, test.wantMatch)
+			continue
+		}
+
+		// Ensure the filter was only updated with the matched output when
+		// expected.
+		out := wire.NewOutPoint(test.tx.Hash(), 0, wire.TxTreeRegular)
+		if updated := f.MatchesOutPoint(out); updated != test.wantUpdate {
+			t.Errorf("%q: unexpected filter update -- got %v, want %v",
+				test.name, updated, test.wantUpdate)
+			continue
+		}
+
+		// Ensure a transaction spending the matched output matches the
+		// filter when it was updated.
+		if test.wantUpdate {
+			spendTx := testTx(out, []byte{0x51})
+			if !f.MatchTxAndUpdate(spendTx) {
+				t.Errorf("%q: spending tx does not match", test.name)
+			}
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package bloom
+
+import (
+	"github.com/decred/dcrd/blockchain/standalone/v2"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/decred/dcrd/wire"
+)
+
+// merkleTree is used to house intermediate information needed to generate a
+// partial merkle tree of a single transaction tree.
+type merkleTree struct {
+	numTx       uint32
+	allHashes   []chainhash.Hash
+	finalHashes []*chainhash.Hash
+	matchedBits []byte
+	bits        []byte
+}
+
+// calcTreeWidth calculates and returns the number of nodes (width) of a merkle
+// tree at the given depth-first height.
+func (m *merkleTree) calcTreeWidth(height uint32) uint32 {
+	return (m.numTx + (1 << height) - 1) >> height
+}
+
+// calcHash returns the hash for a sub-tree given a depth-first height and
+// node position.
+func (m *merkleTree) calcHash(height, pos uint32) chainhash.Hash {
+	if height == 0 {
+		return m.allHashes[pos]
+	}
+
+	// Combine the left and right hashes of the children to form the
+	// parent.  When there is no right child, the left child is
+	// concatenated with itself.
+	var right chainhash.Hash
+	left := m.calcHash(height-1, pos*2)
+	if pos*2+1 < m.calcTreeWidth(height-1) {
+		right = m.calcHash(height-1, pos*2+1)
+	} else {
+		right = left
+	}
+	return standalone.CalcMerkleRoot([]chainhash.Hash{left, right})
+}
+
+// traverseAndBuild builds a partial merkle tree using a recursive depth-first
+// approach.  As it calculates the hashes, it also saves whether or not each
+// node is a parent node and a list of final hashes to be included in the
+// merkle block.
+func (m *merkleTree) traverseAndBuild(height, pos uint32) {
+	// Determine whether this node is a parent of a matched node.
+	var isParent byte
+	for i := pos << height; i < (pos+1)<<height && i < m.numTx; i++ {
+		isParent |= m.matchedBits[i]
+	}
+	m.bits = append(m.bits, isParent)
+
+	// When the node is a leaf node or there is no match, add its hash to
+	// the final hashes.
+	if height == 0 || isParent == 0 {
+		hash := m.calcHash(height, pos)
+		m.finalHashes = append(m.finalHashes, &hash)
+		return
+	}
+
+	// At this point, the node is an internal node and it is the parent of
+	// an included leaf node.
+
+	// Descend into the left child and process its sub-tree.
+	m.traverseAndBuild(height-1, pos*2)
+
+	// Descend into the right child and process its sub-tree if
+	// there is one.
+	if pos*2+1 < m.calcTreeWidth(height-1) {
+		m.traverseAndBuild(height-1, pos*2+1)
+	}
+}
+
+// flags returns the bits of the partial merkle tree packed into bytes in the
+// order they were generated with the least significant bit first.
+func (m *merkleTree) flags() []byte {
+	flags := make([]byte, (len(m.bits)+7)/8)
+	for i, bit := range m.bits {
+		flags[i/8] |= bit << (uint(i) % 8)
+	}
+	return flags
+}
+
+// buildMerkleTree returns the partial merkle tree of the passed transactions
+// that only includes the branches needed to prove the transactions that match
+// the passed filter are part of the tree along with the matched transactions.
+func buildMerkleTree(txns []*dcrutil.Tx, filter *Filter) (*merkleTree, []*dcrutil.Tx) {
+	mTree := merkleTree{
+		numTx:       uint32(len(txns)),
+		allHashes:   make([]chainhash.Hash, 0, len(txns)),
+		matchedBits: make([]byte, 0, len(txns)),
+	}
+
+	// Find and keep track of any transactions that match the filter.
+	var matched []*dcrutil.Tx
+	for _, tx := range txns {
+		if filter.MatchTxAndUpdate(tx) {
+			mTree.matchedBits = append(mTree.matchedBits, 0x01)
+			matched = append(matched, tx)
+		} else {
+			mTree.matchedBits = append(mTree.matchedBits, 0x00)
+		}
+		mTree.allHashes = append(mTree.allHashes, tx.MsgTx().TxHashFull())
+	}
+
+	// An empty tree has no branches to prove.
+	if mTree.numTx == 0 {
+		return &mTree, matched
+	}
+
+	// Calculate the number of merkle branches (height) in the tree.
+	height := uint32(0)
+	for mTree.calcTreeWidth(height) > 1 {
+		height++
+	}
+
+	// Build the depth-first partial merkle tree.
+	mTree.traverseAndBuild(height, 0)
+	return &mTree, matched
+}
+
+// NewMerkleBlock returns a new *wire.MsgMerkleBlock and an array of the
+// matched transactions from both the regular and stake transaction trees of
+// the passed block given the passed filter.  The filter is updated with the
+// matched transactions according to its bloom update flags.
+//
+// The partial merkle trees included in the message are built from the full
+// hashes of the transactions, including witness data, as required for
+// transaction tree merkle roots.
+func NewMerkleBlock(block *dcrutil.Block, filter *Filter) (*wire.MsgMerkleBlock, []*dcrutil.Tx) {
+	regular, matched := buildMerkleTree(block.Transactions(), filter)
+	stake, sMatched := buildMerkleTree(block.STransactions(), filter)
+
+	msgMerkleBlock := wire.MsgMerkleBlock{
+		Header:        block.MsgBlock().Header,
+		Transactions:  regular.numTx,
+		Hashes:        regular.finalHashes,
+		STransactions: stake.numTx,
+		SHashes:       stake.finalHashes,
+		Flags:         regular.flags(),
+		SFlags:        stake.flags(),
+	}
+	return &msgMerkleBlock, append(matched, sMatched...)
+}
@@ -0,0 +1,190 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package bloom
+
+import (
+	"testing"
+
+	"github.com/decred/dcrd/blockchain/standalone/v2"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/decred/dcrd/wire"
+)
+
+// partialMerkleTree is used to extract the matched transactions and merkle
+// root from a partial merkle tree in the tests.
+type partialMerkleTree struct {
+	numTx   uint32
+	hashes  []*chainhash.Hash
+	flags   []byte
+	bitIdx  int
+	hashIdx int
+	matched []chainhash.Hash
+	err     bool
+}
+
+// width returns the number of nodes of the tree at the given height.
+func (p *partialMerkleTree) width(height uint32) uint32 {
+	return (p.numTx + (1 << height) - 1) >> height
+}
+
+// extract traverses the partial merkle tree depth-first in the same manner it
+// was built and returns the hash of the node at the given height and position.
+func (p *partialMerkleTree) extract(height, pos uint32) chainhash.Hash {
+	if p.bitIdx >= len(p.flags)*8 {
+		p.err = true
+		return chainhash.Hash{}
+	}
+	isParent := p.flags[p.bitIdx/8]&(1<<(uint(p.bitIdx)%8)) != 0
+	p.bitIdx++
+
+	if height == 0 || !isParent {
+		if p.hashIdx >= len(p.hashes) {
+			p.err = true
+			return chainhash.Hash{}
+		}
+		hash := *p.hashes[p.hashIdx]
+		p.hashIdx++
+		if height == 0 && isParent {
+			p.matched = append(p.matched, hash)
+		}
+		return hash
+	}
+
+	left := p.extract(height-1, pos*2)
+	right := left
+	if pos*2+1 < p.width(height-1) {
+		right = p.extract(height-1, pos*2+1)
+	}
+	return standalone.CalcMerkleRoot([]chainhash.Hash{left, right})
+}
+
+// root returns the merkle root committed to by the partial merkle tree along
+// with the full hashes of the matched transactions.
+func (p *partialMerkleTree) root() (chainhash.Hash, []chainhash.Hash, bool) {
+	if p.numTx == 0 {
+		return chainhash.Hash{}, nil, len(p.hashes) == 0 && len(p.flags) == 0
+	}
+	var height uint32
+	for p.width(height) > 1 {
+		height++
+	}
+	root := p.extract(height, 0)
+	ok := !p.err && p.hashIdx == len(p.hashes) &&
+		(p.bitIdx+7)/8 == len(p.flags)
+	return root, p.matched, ok
+}
+
+// TestMerkleBlock ensures the partial merkle trees of merkle blocks created
+// for various filters commit to the merkle roots of the transaction trees and
+// only include the transactions which match the filter.
+func TestMerkleBlock(t *testing.T) {
+	// makeTxns returns the requested number of unique transactions.
+	makeTxns := func(prefix byte, n int) []*wire.MsgTx {
+		txns := make([]*wire.MsgTx, 0, n)
+		for i := 0; i < n; i++ {
+			prevHash := chainhash.HashH([]byte{prefix, byte(i)})
+			prevOut := wire.NewOutPoint(&prevHash, 0, wire.TxTreeRegular)
+			msgTx := wire.NewMsgTx()
+			msgTx.AddTxIn(wire.NewTxIn(prevOut, 0, nil))
+			msgTx.AddTxOut(wire.NewTxOut(int64(i), []byte{0x51}))
+			txns = append(txns, msgTx)
+		}
+		return txns
+	}
+
+	tests := []struct {
+		name     string
+		numTxns  int
+		numSTxns int
+		match    []int // Indices into the regular txns
+		sMatch   []int // Indices into the stake txns
+	}{
+		{name: "no matches", numTxns: 7, numSTxns: 5},
+		{name: "single tx", numTxns: 1, numSTxns: 0, match: []int{0}},
+		{name: "first and last", numTxns: 7, numSTxns: 5, match: []int{0, 6},
+			sMatch: []int{4}},
+		{name: "all", numTxns: 4, numSTxns: 3, match: []int{0, 1, 2, 3},
+			sMatch: []int{0, 1, 2}},
+		{name: "odd stake", numTxns: 10, numSTxns: 9, match: []int{5},
+			sMatch: []int{8}},
+	}
+
+	for _, test := range tests {
+		msgBlock := wire.MsgBlock{
+			Transactions:  makeTxns(0x00, test.numTxns),
+			STransactions: makeTxns(0x01, test.numSTxns),
+		}
+		block := dcrutil.NewBlock(&msgBlock)
+
+		// Create a filter that matches the hashes of the transactions that
+		// are expected to match.
+		f := NewFilter(20, 0, 0.000001, wire.BloomUpdateNone)
+		var wantMatched []chainhash.Hash
+		var wantSMatched []chainhash.Hash
+		for _, idx := range test.match {
+			f.AddHash(block.Transactions()[idx].Hash())
+			wantMatched = append(wantMatched,
+				msgBlock.Transactions[idx].TxHashFull())
+		}
+		for _, idx := range test.sMatch {
+			f.AddHash(block.STransactions()[idx].Hash())
+			wantSMatched = append(wantSMatched,
+				msgBlock.STransactions[idx].TxHashFull())
+		}
+
+		msg, matchedTxns := NewMerkleBlock(block, f)
+		if len(matchedTxns) != len(test.match)+len(test.sMatch) {
+			t.Errorf("%q: unexpected number of matched txns -- got %d, "+
+				"want %d", test.name, len(matchedTxns),
+				len(test.match)+len(test.sMatch))
+			continue
+		}
+
+		// Ensure the partial merkle trees commit to the merkle roots of the
+		// transaction trees and only prove the expected transactions.
+		checkTree := func(treeName string, numTx uint32, hashes []*chainhash.Hash, flags []byte, txns []*wire.MsgTx, wantMatched []chainhash.Hash) {
+			t.Helper()
+
+			if numTx != uint32(len(txns)) {
+				t.Errorf("%q: unexpected %s tx count -- got %d, want %d",
+					test.name, treeName, numTx, len(txns))
+				return
+			}
+			pmt := partialMerkleTree{numTx: numTx, hashes: hashes,
+				flags: flags}
+			root, matched, ok := pmt.root()
+			if !ok {
+				t.Errorf("%q: malformed %s partial merkle tree", test.name,
+					treeName)
+				return
+			}
+			wantRoot := standalone.CalcTxTreeMerkleRoot(txns)
+			if root != wantRoot {
+				t.Errorf("%q: unexpected %s merkle root -- got %v, want %v",
+					test.name, treeName, root, wantRoot)
+				return
+			}
+			if len(matched) != len(wantMatched) {
+				t.Errorf("%q: unexpected number of proven %s txns -- got "+
+					"%d, want %d", test.name, treeName, len(matched),
+					len(wantMatched))
+				return
+			}
+			for i := range matched {
+				if matched[i] != wantMatched[i] {
+					t.Errorf("%q: unexpected proven %s tx %d -- got %v, "+
+						"want %v", test.name, treeName, i, matched[i],
+						wantMatched[i])
+					return
+				}
+			}
+		}
+		checkTree("regular", msg.Transactions, msg.Hashes, msg.Flags,
+			msgBlock.Transactions, wantMatched)
+		checkTree("stake", msg.STransactions, msg.SHashes, msg.SFlags,
+			msgBlock.STransactions, wantSMatched)
+	}
+}
@@ -53,8 +53,50 @@ const (
 	ErrInsufficientPriority
 	ErrFeeTooHigh
 	ErrOrphan
+	ErrInsufficientReplacementFee
+	ErrReplacementPolicyViolation
+	ErrExternalPolicy
+
+	// numErrorCodes is the maximum error code number used in tests.
+	numErrorCodes
 )
 
+// Map of ErrorCode values back to their constant names for pretty printing.
+// The names are stable and are surfaced to RPC clients, so they must not be
+// changed once released.
+var errorCodeStrings = map[ErrorCode]string{
+	ErrOther:                      "ErrOther",
+	ErrInvalid:                    "ErrInvalid",
+	ErrOrphanPolicyViolation:      "ErrOrphanPolicyViolation",
+	ErrMempoolDoubleSpend:         "ErrMempoolDoubleSpend",
+	ErrAlreadyVoted:               "ErrAlreadyVoted",
+	ErrDuplicate:                  "ErrDuplicate",
+	ErrCoinbase:                   "ErrCoinbase",
+	ErrExpired:                    "ErrExpired",
+	ErrNonStandard:                "ErrNonStandard",
+	ErrDustOutput:                 "ErrDustOutput",
+	ErrInsufficientFee:            "ErrInsufficientFee",
+	ErrTooManyVotes:               "ErrTooManyVotes",
+	ErrDuplicateRevocation:        "ErrDuplicateRevocation",
+	ErrOldVote:                    "ErrOldVote",
+	ErrAlreadyExists:              "ErrAlreadyExists",
+	ErrSeqLockUnmet:               "ErrSeqLockUnmet",
+	ErrInsufficientPriority:       "ErrInsufficientPriority",
+	ErrFeeTooHigh:                 "ErrFeeTooHigh",
+	ErrOrphan:                     "ErrOrphan",
+	ErrInsufficientReplacementFee: "ErrInsufficientReplacementFee",
+	ErrReplacementPolicyViolation: "ErrReplacementPolicyViolation",
+	ErrExternalPolicy:             "ErrExternalPolicy",
+}
+
+// String returns the ErrorCode as a human-readable name.
+func (e ErrorCode) String() string {
+	if s := errorCodeStrings[e]; s != "" {
+		return s
+	}
+	return fmt.Sprintf("Unknown ErrorCode (%d)", int(e))
+}
+
 // TxRuleError identifies a rule violation.  It is used to indicate that
 // processing of a transaction failed due to one of the many validation
 // rules.  The caller can use type assertions to determine if a failure was
@@ -0,0 +1,57 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mempool
+
+import (
+	"testing"
+)
+
+// TestErrorCodeStringer tests the stringized output for the ErrorCode type.
+func TestErrorCodeStringer(t *testing.T) {
+	tests := []struct {
+		in   ErrorCode
+		want string
+	}{
+		{ErrOther, "ErrOther"},
+		{ErrInvalid, "ErrInvalid"},
+		{ErrOrphanPolicyViolation, "ErrOrphanPolicyViolation"},
+		{ErrMempoolDoubleSpend, "ErrMempoolDoubleSpend"},
+		{ErrAlreadyVoted, "ErrAlreadyVoted"},
+		{ErrDuplicate, "ErrDuplicate"},
+		{ErrCoinbase, "ErrCoinbase"},
+		{ErrExpired, "ErrExpired"},
+		{ErrNonStandard, "ErrNonStandard"},
+		{ErrDustOutput, "ErrDustOutput"},
+		{ErrInsufficientFee, "ErrInsufficientFee"},
+		{ErrTooManyVotes, "ErrTooManyVotes"},
+		{ErrDuplicateRevocation, "ErrDuplicateRevocation"},
+		{ErrOldVote, "ErrOldVote"},
+		{ErrAlreadyExists, "ErrAlreadyExists"},
+		{ErrSeqLockUnmet, "ErrSeqLockUnmet"},
+		{ErrInsufficientPriority, "ErrInsufficientPriority"},
+		{ErrFeeTooHigh, "ErrFeeTooHigh"},
+		{ErrOrphan, "ErrOrphan"},
+		{ErrInsufficientReplacementFee, "ErrInsufficientReplacementFee"},
+		{ErrReplacementPolicyViolation, "ErrReplacementPolicyViolation"},
+		{ErrExternalPolicy, "ErrExternalPolicy"},
+		{0xffff, "Unknown ErrorCode (65535)"},
+	}
+
+	// Detect additional error codes that don't have the stringer added.
+	if len(tests)-1 != int(numErrorCodes) {
+		t.Errorf("It appears an error code was added without adding an " +
+			"associated stringer test")
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		result := test.in.String()
+		if result != test.want {
+			t.Errorf("String #%d\n got: %s want: %s", i, result,
+				test.want)
+			continue
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mempool
+
+import (
+	"math"
+	"time"
+
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/decred/dcrd/txscript/v3"
+)
+
+// LockStatus describes the absolute and relative lock time constraints of a
+// transaction as of the next block.
+//
+// Heights are the minimum height of a block the transaction may be included
+// in and times are the minimum past median time, as a unix timestamp, of the
+// chain the block extends.  They are zero when the respective lock does not
+// constrain the transaction.
+type LockStatus struct {
+	// NextHeight is the height of the next block.
+	NextHeight int64
+
+	// MedianTime is the past median time of the current best chain.
+	MedianTime time.Time
+
+	// LockTimeHeight and LockTimeMedianTime are the constraints imposed by
+	// the lock time of the transaction.
+	LockTimeHeight     int64
+	LockTimeMedianTime int64
+
+	// SequenceLockHeight and SequenceLockMedianTime are the constraints
+	// imposed by the relative lock times of the input sequence numbers of
+	// the transaction.
+	SequenceLockHeight     int64
+	SequenceLockMedianTime int64
+
+	// EarliestHeight and EarliestMedianTime are the combined constraints
+	// that must be satisfied for the transaction to be final.  The earliest
+	// height is never less than the height of the next block.
+	EarliestHeight     int64
+	EarliestMedianTime int64
+
+	// Final is whether or not the transaction satisfies all of its lock
+	// time constraints as of the next block.
+	Final bool
+}
+
+// maxInt64 returns the largest of the provided values.
+func maxInt64(values ...int64) int64 {
+	max := int64(math.MinInt64)
+	for _, value := range values {
+		if value > max {
+			max = value
+		}
+	}
+	return max
+}
+
+// EvaluateLocks evaluates the lock time and the relative lock times of the
+// input sequence numbers of the provided transaction against the current best
+// chain in order to determine the earliest height and past median time at
+// which the transaction becomes final.
+//
+// The relative lock times are calculated with the outputs the transaction
+// spends from the point of view of the main chain and the pool, so
+// transactions that spend outputs of unconfirmed transactions are evaluated as
+// if those transactions were included in the next block.  An error is returned
+// when an input with a relative lock time spends an output that is not
+// available.  Note that the transaction is not otherwise validated.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) EvaluateLocks(tx *dcrutil.Tx) (*LockStatus, error) {
+	mp.mtx.RLock()
+	utxoView, err := mp.fetchInputUtxos(tx)
+	mp.mtx.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	seqLock, err := mp.cfg.CalcSequenceLock(tx, utxoView)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &LockStatus{
+		NextHeight: mp.cfg.BestHeight() + 1,
+		MedianTime: mp.cfg.PastMedianTime(),
+	}
+
+	// The lock time is ignored when it is zero or all of the inputs have
+	// the maximum sequence number.  Otherwise, it is either a block height
+	// or a timestamp depending on whether or not it is under the threshold,
+	// and it must be less than the height of the block or past median time,
+	// respectively.  See blockchain.IsFinalizedTransaction.
+	msgTx := tx.MsgTx()
+	if lockTime := msgTx.LockTime; lockTime != 0 {
+		for _, txIn := range msgTx.TxIn {
+			if txIn.Sequence == math.MaxUint32 {
+				continue
+			}
+			if lockTime < txscript.LockTimeThreshold {
+				status.LockTimeHeight = int64(lockTime) + 1
+			} else {
+				status.LockTimeMedianTime = int64(lockTime) + 1
+			}
+			break
+		}
+	}
+
+	// The sequence lock must be exceeded by both the height of the block
+	// and past median time.  See blockchain.SequenceLockActive.
+	if seqLock.MinHeight >= 0 {
+		status.SequenceLockHeight = seqLock.MinHeight + 1
+	}
+	if seqLock.MinTime >= 0 {
+		status.SequenceLockMedianTime = seqLock.MinTime + 1
+	}
+
+	status.EarliestHeight = maxInt64(status.NextHeight, status.LockTimeHeight,
+		status.SequenceLockHeight)
+	status.EarliestMedianTime = maxInt64(status.LockTimeMedianTime,
+		status.SequenceLockMedianTime)
+	status.Final = status.EarliestHeight == status.NextHeight &&
+		status.MedianTime.Unix() >= status.EarliestMedianTime
+	return status, nil
+}
@@ -160,6 +160,11 @@ type Policy struct {
 	// network. Otherwise, all non-standard transactions will be rejected.
 	AcceptNonStd bool
 
+	// TxTypePolicies defines overrides of the relay policy for specific
+	// types of transactions.  Types without an entry use the default policy
+	// which is governed by AcceptNonStd.
+	TxTypePolicies map[stake.TxType]TxTypePolicy
+
 	// FreeTxRelayLimit defines the given amount in thousands of bytes
 	// per minute that transactions with no fee are rate limited to.
 	FreeTxRelayLimit float64
@@ -205,6 +210,48 @@ type Policy struct {
 	//
 	// This function must be safe for concurrent access.
 	AcceptSequenceLocks func() (bool, error)
+
+	// IsAgendaActive defines the function to determine whether or not the
+	// agenda with the provided id is active for the block after the current
+	// best block.  It is used to determine which script version
+	// standardness policies apply.  Only the policies that do not depend on
+	// an agenda apply when it is nil.
+	//
+	// This function must be safe for concurrent access.
+	IsAgendaActive func(agendaID string) (bool, error)
+
+	// MaxTxAge defines the maximum amount of time regular transactions and
+	// ticket purchases may remain in the pool before they are evicted
+	// regardless of their expiry.  Transactions that exceed it are evicted
+	// along with expired transactions.  A value of zero disables time-based
+	// eviction.
+	MaxTxAge time.Duration
+
+	// EnableReplacement defines whether or not regular transactions in the
+	// pool may be replaced by conflicting regular transactions that pay
+	// sufficiently higher fees.
+	EnableReplacement bool
+
+	// MinReplacementFeeRateIncrease defines the minimum percentage by which
+	// the fee rate of a replacement transaction must exceed the fee rate of
+	// each transaction it directly conflicts with.  Replacements must also
+	// pay a fee that covers the fees of all transactions they evict plus the
+	// minimum relay fee for their own size.
+	MinReplacementFeeRateIncrease float64
+
+	// ExternalPolicy defines an optional function to consult an external
+	// policy, such as an anti-spam scoring service, about whether or not to
+	// accept a new transaction that satisfies all other policy and consensus
+	// rules.  The transaction is rejected when it returns an error.  Votes
+	// and transactions that are added back to the pool from disconnected
+	// blocks are not subject to it.
+	//
+	// It is invoked with the pool locked, so it must return quickly and
+	// should accept the transaction when the external policy is not
+	// available.
+	//
+	// This function must be safe for concurrent access.
+	ExternalPolicy func(tx *dcrutil.Tx, txType stake.TxType, fee int64) error
 }
 
 // TxDesc is a descriptor containing a transaction in the mempool along with
@@ -272,6 +319,14 @@ type TxPool struct {
 	// the scan will only run when an orphan is added to the pool as opposed
 	// to on an unconditional timer.
 	nextExpireScan time.Time
+
+	// stats tracks rolling statistics about the pool such as its high-water
+	// marks.
+	stats poolStats
+
+	// replacements houses the most recent replacement decisions ordered from
+	// oldest to newest.
+	replacements []ReplacementDecision
 }
 
 // insertVote inserts a vote into the map of block votes.
@@ -713,7 +768,7 @@ func (mp *TxPool) MaybeAcceptDependents(tx *dcrutil.Tx) []*dcrutil.Tx {
 				"stage pool", *redeemer.Hash())
 			mp.removeStagedTransaction(redeemer)
 			_, err := mp.maybeAcceptTransaction(
-				redeemer, true, true, true, true)
+				redeemer, true, true, true, true, false)
 
 			if err != nil {
 				log.Debugf("Failed to add previously staged "+
@@ -834,6 +889,8 @@ func (mp *TxPool) removeTransaction(tx *dcrutil.Tx, removeRedeemers bool) {
 		for _, txIn := range txDesc.Tx.MsgTx().TxIn {
 			delete(mp.outpoints, txIn.PreviousOutPoint)
 		}
+		mp.stats.removed(time.Now(), len(mp.pool),
+			int64(txDesc.Tx.MsgTx().SerializeSize()))
 		delete(mp.pool, *txHash)
 		atomic.StoreInt64(&mp.lastUpdated, time.Now().Unix())
 
@@ -898,9 +955,19 @@ func (mp *TxPool) addTransaction(utxoView *blockchain.UtxoViewpoint,
 		mp.cfg.OnVoteReceived(tx)
 	}
 
+	// Update the pool statistics.  Only regular transactions are considered
+	// for the minimum accepted fee rate since the fees of stake transactions
+	// are subject to different policy.
+	msgTx := tx.MsgTx()
+	txSize := int64(msgTx.SerializeSize())
+	txFeeRate := int64(noFeeRate)
+	if txType == stake.TxTypeRegular {
+		txFeeRate = feeRate(fee, txSize)
+	}
+	mp.stats.added(time.Now(), len(mp.pool), txSize, txFeeRate)
+
 	// Add the transaction to the pool and mark the referenced outpoints
 	// as spent by the pool.
-	msgTx := tx.MsgTx()
 	mp.pool[*tx.Hash()] = &TxDesc{
 		TxDesc: mining.TxDesc{
 			Tx:     tx,
@@ -928,8 +995,7 @@ func (mp *TxPool) addTransaction(utxoView *blockchain.UtxoViewpoint,
 	// Inform the associated fee estimator that a new transaction has been added
 	// to the mempool
 	if mp.cfg.AddTxToFeeEstimation != nil {
-		mp.cfg.AddTxToFeeEstimation(tx.Hash(), fee, int64(msgTx.SerializeSize()),
-			txType)
+		mp.cfg.AddTxToFeeEstimation(tx.Hash(), fee, txSize, txType)
 	}
 }
 
@@ -1064,6 +1130,22 @@ func (mp *TxPool) fetchInputUtxos(tx *dcrutil.Tx) (*blockchain.UtxoViewpoint, er
 	return utxoView, nil
 }
 
+// FetchTxDesc returns the descriptor for the requested transaction from the
+// main transaction pool.  It does not include orphans or staged transactions.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) FetchTxDesc(txHash *chainhash.Hash) (*TxDesc, error) {
+	mp.mtx.RLock()
+	txDesc, exists := mp.pool[*txHash]
+	mp.mtx.RUnlock()
+
+	if exists {
+		return txDesc, nil
+	}
+
+	return nil, fmt.Errorf("transaction is not in the pool")
+}
+
 // FetchTransaction returns the requested transaction from the transaction pool.
 // This only fetches from the main transaction pool and does not include
 // orphans.
@@ -1094,6 +1176,10 @@ func (mp *TxPool) FetchTransaction(txHash *chainhash.Hash) (*dcrutil.Tx, error)
 // MaybeAcceptTransaction.  See the comment for MaybeAcceptTransaction for
 // more details.
 //
+// The feeExempt flag exempts the transaction from the minimum relay fee policy
+// along with the priority and rate limiting requirements for transactions that
+// do not pay it.
+//
 // This function MUST be called with the mempool lock held (for writes).
 //
 // DECRED - TODO
@@ -1101,7 +1187,7 @@ func (mp *TxPool) FetchTransaction(txHash *chainhash.Hash) (*dcrutil.Tx, error)
 // so that we can easily pick different stake tx types from the mempool later.
 // This should probably be done at the bottom using "IsSStx" etc functions.
 // It should also set the dcrutil tree type for the tx as well.
-func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, rateLimit, allowHighFees, rejectDupOrphans bool) ([]*chainhash.Hash, error) {
+func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, rateLimit, allowHighFees, rejectDupOrphans, feeExempt bool) ([]*chainhash.Hash, error) {
 	msgTx := tx.MsgTx()
 	txHash := tx.Hash()
 	// Don't accept the transaction if it already exists in the pool.  This
@@ -1202,13 +1288,25 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, rateLimit, allow
 		return nil, txRuleError(ErrInvalid, str)
 	}
 
+	// Don't allow transactions of types the mempool config forbids the
+	// acceptance and relaying of.
+	if mp.cfg.Policy.TxTypePolicies[txType] == TxTypePolicyReject {
+		str := fmt.Sprintf("transaction %v is a %s transaction which is "+
+			"rejected by policy", txHash, txTypeNames[txType])
+		return nil, txRuleError(ErrNonStandard, str)
+	}
+
 	// Don't allow non-standard transactions if the mempool config forbids
 	// their acceptance and relaying.
 	medianTime := mp.cfg.PastMedianTime()
-	if !mp.cfg.Policy.AcceptNonStd {
-		err := checkTransactionStandard(tx, txType, nextBlockHeight,
+	if !mp.cfg.Policy.acceptNonStd(txType) {
+		policies, err := mp.activeScriptPolicies()
+		if err != nil {
+			return nil, err
+		}
+		err = checkTransactionStandard(tx, txType, nextBlockHeight,
 			medianTime, mp.cfg.Policy.MinRelayTxFee,
-			mp.cfg.Policy.MaxTxVersion)
+			mp.cfg.Policy.MaxTxVersion, policies)
 		if err != nil {
 			str := fmt.Sprintf("transaction %v is not standard: %v",
 				txHash, err)
@@ -1244,8 +1342,13 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, rateLimit, allow
 	// that happens later after fetching the referenced transaction inputs from
 	// the main chain which examines the actual spend data and prevents double
 	// spends.
+	//
+	// Regular transactions that conflict with other regular transactions in
+	// the pool are evaluated as replacements for them later once their fees
+	// are known when replacements are enabled.
+	var conflicts map[chainhash.Hash]*TxDesc
 	if !isVote && !isRevocation {
-		err = mp.checkPoolDoubleSpend(tx, txType)
+		conflicts, err = mp.replacementConflicts(tx, txType)
 		if err != nil {
 			return nil, err
 		}
@@ -1357,6 +1460,13 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, rateLimit, allow
 	}
 
 	if len(missingParents) > 0 {
+		// Orphans are not allowed to replace transactions since their fees
+		// are unknown.
+		if len(conflicts) > 0 {
+			str := fmt.Sprintf("orphan transaction %v spends the same coins "+
+				"as transactions in the pool", txHash)
+			return nil, txRuleError(ErrMempoolDoubleSpend, str)
+		}
 		return missingParents, nil
 	}
 
@@ -1393,8 +1503,12 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, rateLimit, allow
 
 	// Don't allow transactions with non-standard inputs if the mempool config
 	// forbids their acceptance and relaying.
-	if !mp.cfg.Policy.AcceptNonStd {
-		err := checkInputsStandard(tx, txType, utxoView)
+	if !mp.cfg.Policy.acceptNonStd(txType) {
+		policies, err := mp.activeScriptPolicies()
+		if err != nil {
+			return nil, err
+		}
+		err = checkInputsStandard(tx, txType, utxoView, policies)
 		if err != nil {
 			str := fmt.Sprintf("transaction %v has a non-standard "+
 				"input: %v", txHash, err)
@@ -1443,6 +1557,9 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, rateLimit, allow
 	serializedSize := int64(msgTx.SerializeSize())
 	minFee := calcMinRequiredTxRelayFee(serializedSize,
 		mp.cfg.Policy.MinRelayTxFee)
+	if feeExempt {
+		minFee = 0
+	}
 	if txType == stake.TxTypeRegular { // Non-stake only
 		if serializedSize >= (DefaultBlockPrioritySize-1000) &&
 			txFee < minFee {
@@ -1502,7 +1619,7 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, rateLimit, allow
 	// also performed on regular transactions above, but fees lower than the
 	// minimum may be allowed when there is sufficient priority, and these
 	// checks aren't desired for ticket purchases.
-	if isTicket {
+	if isTicket && !feeExempt {
 		minTicketFee := calcMinRequiredTxRelayFee(serializedSize,
 			mp.cfg.Policy.MinRelayTxFee)
 		if txFee < minTicketFee {
@@ -1543,6 +1660,37 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, rateLimit, allow
 		return nil, err
 	}
 
+	// Consult the external policy, if any, about whether or not to accept the
+	// new transaction now that it is otherwise known to be acceptable.
+	if isNew && !isVote && mp.cfg.Policy.ExternalPolicy != nil {
+		err := mp.cfg.Policy.ExternalPolicy(tx, txType, txFee)
+		if err != nil {
+			str := fmt.Sprintf("transaction %v rejected by external "+
+				"policy: %v", txHash, err)
+			return nil, txRuleError(ErrExternalPolicy, str)
+		}
+	}
+
+	// Ensure transactions that conflict with transactions in the pool satisfy
+	// the replacement policy and evict the transactions they replace along
+	// with all of their descendants.
+	if len(conflicts) > 0 {
+		decision, evictions, err := mp.checkReplacement(tx, txFee,
+			serializedSize, conflicts)
+		mp.recordReplacement(decision)
+		if err != nil {
+			return nil, err
+		}
+
+		numTxns := len(mp.pool)
+		for _, txDesc := range conflicts {
+			mp.removeTransaction(txDesc.Tx, true)
+		}
+		mp.stats.evicted(time.Now(), len(mp.pool), numTxns-len(mp.pool))
+		log.Debugf("Transaction %v replaced %d transactions (%d evicted)",
+			txHash, len(conflicts), len(evictions))
+	}
+
 	// Tickets cannot be included in a block until all inputs have
 	// been approved by stakeholders. Consensus rules dictate that stake
 	// transactions must precede regular transactions, and that inputs for any
@@ -1602,7 +1750,8 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, rateLimit, allow
 func (mp *TxPool) MaybeAcceptTransaction(tx *dcrutil.Tx, isNew, rateLimit bool) ([]*chainhash.Hash, error) {
 	// Protect concurrent access.
 	mp.mtx.Lock()
-	hashes, err := mp.maybeAcceptTransaction(tx, isNew, rateLimit, true, true)
+	hashes, err := mp.maybeAcceptTransaction(tx, isNew, rateLimit, true, true,
+		false)
 	mp.mtx.Unlock()
 
 	return hashes, err
@@ -1651,7 +1800,7 @@ func (mp *TxPool) processOrphans(acceptedTx *dcrutil.Tx) []*dcrutil.Tx {
 			// Potentially accept an orphan into the tx pool.
 			for _, tx := range orphans {
 				missing, err := mp.maybeAcceptTransaction(
-					tx, true, true, true, false)
+					tx, true, true, true, false, false)
 				if err != nil {
 					// The orphan is now invalid, so there
 					// is no way any other orphans which
@@ -1711,6 +1860,7 @@ func (mp *TxPool) PruneStakeTx(requiredStakeDifficulty, height int64) {
 }
 
 func (mp *TxPool) pruneStakeTx(requiredStakeDifficulty, height int64) {
+	numTxns := len(mp.pool)
 	for _, tx := range mp.pool {
 		txType := stake.DetermineTxType(tx.Tx.MsgTx())
 		if txType == stake.TxTypeSStx &&
@@ -1726,6 +1876,8 @@ func (mp *TxPool) pruneStakeTx(requiredStakeDifficulty, height int64) {
 			mp.removeTransaction(tx.Tx, true)
 		}
 	}
+	mp.stats.evicted(time.Now(), len(mp.pool), numTxns-len(mp.pool))
+
 	for _, tx := range mp.staged {
 		txType := stake.DetermineTxType(tx.MsgTx())
 		if txType == stake.TxTypeSStx &&
@@ -1738,19 +1890,35 @@ func (mp *TxPool) pruneStakeTx(requiredStakeDifficulty, height int64) {
 }
 
 // pruneExpiredTx prunes expired transactions from the mempool that are no
-// longer able to be included into a block.
+// longer able to be included into a block along with transactions that have
+// been in the mempool longer than the maximum allowed age when it is enabled.
 //
 // This function MUST be called with the mempool lock held (for writes).
 func (mp *TxPool) pruneExpiredTx() {
 	nextBlockHeight := mp.cfg.BestHeight() + 1
 
+	numTxns := len(mp.pool)
+	now := time.Now()
+	maxTxAge := mp.cfg.Policy.MaxTxAge
 	for _, tx := range mp.pool {
 		if blockchain.IsExpired(tx.Tx, nextBlockHeight) {
 			log.Debugf("Pruning expired transaction %v from the mempool",
 				tx.Tx.Hash())
 			mp.removeTransaction(tx.Tx, true)
+			continue
+		}
+
+		// Evict regular transactions and ticket purchases that have been in
+		// the pool longer than the maximum allowed age when enabled.
+		isAgeLimited := tx.Type == stake.TxTypeRegular ||
+			tx.Type == stake.TxTypeSStx
+		if maxTxAge > 0 && isAgeLimited && now.Sub(tx.Added) > maxTxAge {
+			log.Debugf("Pruning transaction %v that exceeded the maximum "+
+				"age of %v from the mempool", tx.Tx.Hash(), maxTxAge)
+			mp.removeTransaction(tx.Tx, true)
 		}
 	}
+	mp.stats.evicted(now, len(mp.pool), numTxns-len(mp.pool))
 
 	for _, tx := range mp.staged {
 		if blockchain.IsExpired(tx, nextBlockHeight) {
@@ -1762,7 +1930,8 @@ func (mp *TxPool) pruneExpiredTx() {
 }
 
 // PruneExpiredTx prunes expired transactions from the mempool that may no longer
-// be able to be included into a block.
+// be able to be included into a block along with transactions that have been in
+// the mempool longer than the maximum allowed age when it is enabled.
 //
 // This function is safe for concurrent access.
 func (mp *TxPool) PruneExpiredTx() {
@@ -1801,6 +1970,31 @@ func (mp *TxPool) ProcessOrphans(acceptedTx *dcrutil.Tx) []*dcrutil.Tx {
 //
 // This function is safe for concurrent access.
 func (mp *TxPool) ProcessTransaction(tx *dcrutil.Tx, allowOrphan, rateLimit, allowHighFees bool, tag Tag) ([]*dcrutil.Tx, error) {
+	return mp.processTransaction(tx, allowOrphan, rateLimit, allowHighFees,
+		false, tag)
+}
+
+// ProcessFeeExemptTransaction is identical to ProcessTransaction except the
+// transaction is exempt from the minimum relay fee policy along with the
+// priority and rate limiting requirements for transactions that do not pay it.
+// All other policy and consensus rules still apply.
+//
+// It is intended for transactions from sources the operator trusts, such as
+// their own low-fee consolidation transactions.  Note that orphans are subject
+// to the minimum relay fee policy once their parents are accepted.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) ProcessFeeExemptTransaction(tx *dcrutil.Tx, allowOrphan, allowHighFees bool, tag Tag) ([]*dcrutil.Tx, error) {
+	return mp.processTransaction(tx, allowOrphan, false, allowHighFees, true,
+		tag)
+}
+
+// processTransaction is the internal function which implements the public
+// ProcessTransaction and ProcessFeeExemptTransaction.  See the comments for
+// them for more details.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) processTransaction(tx *dcrutil.Tx, allowOrphan, rateLimit, allowHighFees, feeExempt bool, tag Tag) ([]*dcrutil.Tx, error) {
 	// Protect concurrent access.
 	mp.mtx.Lock()
 	defer mp.mtx.Unlock()
@@ -1814,7 +2008,7 @@ func (mp *TxPool) ProcessTransaction(tx *dcrutil.Tx, allowOrphan, rateLimit, all
 
 	// Potentially accept the transaction to the memory pool.
 	missingParents, err := mp.maybeAcceptTransaction(tx, true, rateLimit,
-		allowHighFees, true)
+		allowHighFees, true, feeExempt)
 	if err != nil {
 		return nil, err
 	}
@@ -1968,6 +2162,18 @@ func (mp *TxPool) MiningDescs() []*mining.TxDesc {
 	return descs
 }
 
+// Stats returns the current size of the mempool along with rolling statistics
+// about it over the last hour and day, such as its high-water marks, the number
+// of evicted transactions, and the minimum accepted fee rate.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) Stats() *PoolStats {
+	mp.mtx.RLock()
+	stats := mp.stats.stats(time.Now(), len(mp.pool))
+	mp.mtx.RUnlock()
+	return stats
+}
+
 // LastUpdated returns the last time a transaction was added to or removed from
 // the main pool.  It does not include the orphan pool.
 //
@@ -8,7 +8,9 @@ package mempool
 import (
 	"encoding/binary"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"reflect"
 	"runtime"
 	"sync"
 	"testing"
@@ -1154,7 +1156,7 @@ func TestOrphanReject(t *testing.T) {
 
 		// Ensure no transactions were reported as accepted.
 		if len(acceptedTxns) != 0 {
-			t.Fatal("ProcessTransaction: reported %d accepted "+
+			t.Fatalf("ProcessTransaction: reported %d accepted "+
 				"transactions from failed orphan attempt",
 				len(acceptedTxns))
 		}
@@ -1306,6 +1308,24 @@ func TestExpirationPruning(t *testing.T) {
 		harness.txPool.PruneExpiredTx()
 		testPoolMembership(tc, tx, false, false)
 	}
+
+	// Ensure the pool statistics reflect the pruned transactions as evicted
+	// and only account for the remaining transaction.
+	stats := harness.txPool.Stats()
+	wantBytes := int64(multiOutputTx.MsgTx().SerializeSize())
+	if stats.Count != 1 || stats.Bytes != wantBytes {
+		t.Fatalf("unexpected pool size -- got %d txns (%d bytes), want 1 "+
+			"txn (%d bytes)", stats.Count, stats.Bytes, wantBytes)
+	}
+	if stats.NumEvicted != numTxns || stats.LastHour.NumEvicted != numTxns {
+		t.Fatalf("unexpected number of evicted txns -- got %d (%d in last "+
+			"hour), want %d", stats.NumEvicted, stats.LastHour.NumEvicted,
+			numTxns)
+	}
+	if stats.LastHour.MaxCount != 2 {
+		t.Fatalf("unexpected max pool size -- got %d, want 2",
+			stats.LastHour.MaxCount)
+	}
 }
 
 // TestBasicOrphanRemoval ensure that orphan removal works as expected when an
@@ -2278,3 +2298,638 @@ func TestRemoveDoubleSpends(t *testing.T) {
 			"exist in pool.", ticket.Hash())
 	}
 }
+
+// TestTxReplacement ensures regular transactions that conflict with regular
+// transactions in the pool are only accepted as replacements when replacements
+// are enabled and they satisfy the replacement policy, that accepted
+// replacements evict the transactions they replace along with all of their
+// descendants, and that the decisions are recorded.
+func TestTxReplacement(t *testing.T) {
+	t.Parallel()
+
+	harness, outputs, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	tc := &testContext{t, harness}
+	harness.txPool.cfg.Policy.EnableReplacement = true
+	harness.txPool.cfg.Policy.MinReplacementFeeRateIncrease = 25
+
+	// createTx creates a transaction that spends the provided outputs to a
+	// single output while paying the provided fee.
+	createTx := func(inputs []spendableOutput, fee int64) *dcrutil.Tx {
+		t.Helper()
+		tx, err := harness.CreateSignedTx(inputs, 1, func(tx *wire.MsgTx) {
+			tx.TxOut[0].Value -= fee
+		})
+		if err != nil {
+			t.Fatalf("unable to create transaction: %v", err)
+		}
+		return tx
+	}
+
+	// Create a parent transaction with multiple outputs, a transaction that
+	// spends one of them, and a descendant of that transaction and add them
+	// all to the pool.
+	parent, err := harness.CreateSignedTx([]spendableOutput{outputs[0]}, 2)
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	parentOut := txOutToSpendableOut(parent, 0, wire.TxTreeRegular)
+	original := createTx([]spendableOutput{parentOut}, 10000)
+	descendant := createTx([]spendableOutput{txOutToSpendableOut(original, 0,
+		wire.TxTreeRegular)}, 50000)
+	for _, tx := range []*dcrutil.Tx{parent, original, descendant} {
+		_, err := harness.txPool.ProcessTransaction(tx, false, false, true, 0)
+		if err != nil {
+			t.Fatalf("ProcessTransaction: failed to accept tx %v: %v",
+				tx.Hash(), err)
+		}
+		testPoolMembership(tc, tx, false, true)
+	}
+
+	// Ensure a replacement with a fee rate that does not exceed the fee rate
+	// of the transaction it conflicts with by the required percentage is
+	// rejected.
+	lowFeeRate := createTx([]spendableOutput{parentOut}, 11000)
+	_, err = harness.txPool.ProcessTransaction(lowFeeRate, false, false, true, 0)
+	if !IsErrorCode(err, ErrInsufficientReplacementFee) {
+		t.Fatalf("ProcessTransaction: did not get expected "+
+			"ErrInsufficientReplacementFee for low fee rate: %v", err)
+	}
+	testPoolMembership(tc, lowFeeRate, false, false)
+
+	// Ensure a replacement with a sufficient fee rate that does not pay enough
+	// to cover the fees of all of the transactions it evicts is rejected.
+	lowFee := createTx([]spendableOutput{parentOut}, 20000)
+	_, err = harness.txPool.ProcessTransaction(lowFee, false, false, true, 0)
+	if !IsErrorCode(err, ErrInsufficientReplacementFee) {
+		t.Fatalf("ProcessTransaction: did not get expected "+
+			"ErrInsufficientReplacementFee for low fee: %v", err)
+	}
+	testPoolMembership(tc, lowFee, false, false)
+	testPoolMembership(tc, original, false, true)
+	testPoolMembership(tc, descendant, false, true)
+
+	// Ensure a replacement that satisfies the policy is accepted and evicts
+	// the transaction it conflicts with along with its descendant.
+	replacement := createTx([]spendableOutput{parentOut}, 70000)
+	_, err = harness.txPool.ProcessTransaction(replacement, false, false, true,
+		0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept replacement: %v", err)
+	}
+	testPoolMembership(tc, replacement, false, true)
+	testPoolMembership(tc, original, false, false)
+	testPoolMembership(tc, descendant, false, false)
+	testPoolMembership(tc, parent, false, true)
+	if numEvicted := harness.txPool.Stats().NumEvicted; numEvicted != 2 {
+		t.Fatalf("unexpected number of evicted transactions -- got %d, "+
+			"want 2", numEvicted)
+	}
+
+	// Ensure a replacement that spends an output of the transaction it
+	// replaces is rejected.
+	spendsReplaced := createTx([]spendableOutput{parentOut,
+		txOutToSpendableOut(replacement, 0, wire.TxTreeRegular)}, 1000000)
+	_, err = harness.txPool.ProcessTransaction(spendsReplaced, false, false,
+		true, 0)
+	if !IsErrorCode(err, ErrReplacementPolicyViolation) {
+		t.Fatalf("ProcessTransaction: did not get expected "+
+			"ErrReplacementPolicyViolation: %v", err)
+	}
+	testPoolMembership(tc, spendsReplaced, false, false)
+	testPoolMembership(tc, replacement, false, true)
+
+	// Ensure conflicting transactions are rejected as double spends without
+	// being evaluated as replacements when replacements are disabled.
+	harness.txPool.cfg.Policy.EnableReplacement = false
+	highFee := createTx([]spendableOutput{parentOut}, 1000000)
+	_, err = harness.txPool.ProcessTransaction(highFee, false, false, true, 0)
+	if !IsErrorCode(err, ErrMempoolDoubleSpend) {
+		t.Fatalf("ProcessTransaction: did not get expected "+
+			"ErrMempoolDoubleSpend: %v", err)
+	}
+	testPoolMembership(tc, highFee, false, false)
+	testPoolMembership(tc, replacement, false, true)
+
+	// Ensure the decisions were recorded in order with the expected details.
+	history := harness.txPool.Replacements()
+	if history.Enabled || history.MinFeeRateIncrease != 25 {
+		t.Fatalf("unexpected replacement policy -- got enabled %v, increase "+
+			"%v, want enabled false, increase 25", history.Enabled,
+			history.MinFeeRateIncrease)
+	}
+	wantDecisions := []struct {
+		hash       chainhash.Hash
+		conflict   chainhash.Hash
+		numEvicted int
+		accepted   bool
+	}{
+		{*lowFeeRate.Hash(), *original.Hash(), 2, false},
+		{*lowFee.Hash(), *original.Hash(), 2, false},
+		{*replacement.Hash(), *original.Hash(), 2, true},
+		{*spendsReplaced.Hash(), *replacement.Hash(), 1, false},
+	}
+	if len(history.Decisions) != len(wantDecisions) {
+		t.Fatalf("unexpected number of decisions -- got %d, want %d",
+			len(history.Decisions), len(wantDecisions))
+	}
+	for i, want := range wantDecisions {
+		got := &history.Decisions[i]
+		if got.Hash != want.hash || len(got.Conflicts) != 1 ||
+			got.Conflicts[0] != want.conflict ||
+			got.NumEvicted != want.numEvicted || got.Accepted != want.accepted {
+
+			t.Fatalf("unexpected decision %d -- got %+v", i, got)
+		}
+		if got.Accepted != (got.Reason == "") {
+			t.Fatalf("unexpected reason for decision %d -- got %q", i,
+				got.Reason)
+		}
+		if got.Fee < got.MinFee && got.Accepted {
+			t.Fatalf("accepted decision %d pays fee %d under the minimum %d",
+				i, got.Fee, got.MinFee)
+		}
+	}
+}
+
+// TestMaxTxAge ensures transactions that have been in the pool longer than the
+// maximum allowed age are evicted when it is enabled.
+func TestMaxTxAge(t *testing.T) {
+	t.Parallel()
+
+	harness, outputs, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	tc := &testContext{t, harness}
+
+	// Add two transactions that spend the outputs of a multi-output
+	// transaction to the pool and make the first one appear as if it was added
+	// two hours ago.
+	multiOutputTx, err := harness.CreateSignedTx([]spendableOutput{outputs[0]},
+		2)
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	harness.AddFakeUTXO(multiOutputTx, harness.chain.BestHeight())
+	var txns []*dcrutil.Tx
+	for i := uint32(0); i < 2; i++ {
+		tx, err := harness.CreateTx(txOutToSpendableOut(multiOutputTx, i,
+			wire.TxTreeRegular))
+		if err != nil {
+			t.Fatalf("unable to create transaction: %v", err)
+		}
+		_, err = harness.txPool.ProcessTransaction(tx, false, false, true, 0)
+		if err != nil {
+			t.Fatalf("ProcessTransaction: failed to accept tx: %v", err)
+		}
+		txns = append(txns, tx)
+	}
+	harness.txPool.pool[*txns[0].Hash()].Added = time.Now().Add(-2 * time.Hour)
+
+	// Ensure no transactions are evicted when the maximum age is disabled.
+	harness.txPool.PruneExpiredTx()
+	testPoolMembership(tc, txns[0], false, true)
+	testPoolMembership(tc, txns[1], false, true)
+
+	// Ensure only the transaction that exceeds the maximum age is evicted once
+	// it is enabled.
+	harness.txPool.cfg.Policy.MaxTxAge = time.Hour
+	harness.txPool.PruneExpiredTx()
+	testPoolMembership(tc, txns[0], false, false)
+	testPoolMembership(tc, txns[1], false, true)
+	if numEvicted := harness.txPool.Stats().NumEvicted; numEvicted != 1 {
+		t.Fatalf("unexpected number of evicted transactions -- got %d, "+
+			"want 1", numEvicted)
+	}
+}
+
+// TestFeeExemptTransaction ensures transactions that do not pay the minimum
+// relay fee are rejected unless they are exempt from it while other policy is
+// still enforced for exempt transactions.
+func TestFeeExemptTransaction(t *testing.T) {
+	t.Parallel()
+
+	harness, outputs, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	tc := &testContext{t, harness}
+
+	// Disallow all free transactions so that any transaction that does not
+	// pay the minimum relay fee is rejected by the rate limiter.
+	harness.txPool.cfg.Policy.FreeTxRelayLimit = 0
+
+	// Ensure a transaction without fees is rejected when it is not exempt.
+	tx, err := harness.CreateTx(outputs[0])
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	_, err = harness.txPool.ProcessTransaction(tx, false, true, true, 0)
+	if !IsErrorCode(err, ErrInsufficientFee) {
+		t.Fatalf("ProcessTransaction: did not get expected "+
+			"ErrInsufficientFee: %v", err)
+	}
+	testPoolMembership(tc, tx, false, false)
+
+	// Ensure the same transaction is accepted when it is exempt.
+	_, err = harness.txPool.ProcessFeeExemptTransaction(tx, false, true, 0)
+	if err != nil {
+		t.Fatalf("ProcessFeeExemptTransaction: failed to accept tx: %v", err)
+	}
+	testPoolMembership(tc, tx, false, true)
+
+	// Ensure exempt transactions are still subject to other policy.
+	txOut := txOutToSpendableOut(tx, 0, wire.TxTreeRegular)
+	nonStd, err := harness.CreateSignedTx([]spendableOutput{txOut}, 1,
+		func(tx *wire.MsgTx) {
+			tx.Version = ^uint16(0)
+		})
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	_, err = harness.txPool.ProcessFeeExemptTransaction(nonStd, false, true, 0)
+	if !IsErrorCode(err, ErrNonStandard) {
+		t.Fatalf("ProcessFeeExemptTransaction: did not get expected "+
+			"ErrNonStandard: %v", err)
+	}
+	testPoolMembership(tc, nonStd, false, false)
+}
+
+// TestExternalPolicy ensures new transactions are only accepted when the
+// external policy accepts them while transactions that are added back to the
+// pool are not subject to it.
+func TestExternalPolicy(t *testing.T) {
+	t.Parallel()
+
+	harness, outputs, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	tc := &testContext{t, harness}
+
+	// Configure an external policy that rejects all transactions that spend
+	// outputs of a rejected transaction.
+	var consulted []chainhash.Hash
+	var rejected *dcrutil.Tx
+	harness.txPool.cfg.Policy.ExternalPolicy = func(tx *dcrutil.Tx, txType stake.TxType, fee int64) error {
+		consulted = append(consulted, *tx.Hash())
+		if rejected == nil {
+			return nil
+		}
+		for _, txIn := range tx.MsgTx().TxIn {
+			if txIn.PreviousOutPoint.Hash == *rejected.Hash() {
+				return errors.New("spam")
+			}
+		}
+		return nil
+	}
+
+	// Ensure a transaction accepted by the external policy is accepted.
+	tx, err := harness.CreateTx(outputs[0])
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	_, err = harness.txPool.ProcessTransaction(tx, false, false, true, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept tx: %v", err)
+	}
+	testPoolMembership(tc, tx, false, true)
+
+	// Ensure a transaction rejected by the external policy is rejected with
+	// the expected error code.
+	rejected = tx
+	txOut := txOutToSpendableOut(tx, 0, wire.TxTreeRegular)
+	spendTx, err := harness.CreateSignedTx([]spendableOutput{txOut}, 1)
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	_, err = harness.txPool.ProcessTransaction(spendTx, false, false, true, 0)
+	if !IsErrorCode(err, ErrExternalPolicy) {
+		t.Fatalf("ProcessTransaction: did not get expected "+
+			"ErrExternalPolicy: %v", err)
+	}
+	testPoolMembership(tc, spendTx, false, false)
+
+	// Ensure transactions that are added back to the pool are not subject to
+	// the external policy.
+	numConsulted := len(consulted)
+	_, err = harness.txPool.MaybeAcceptTransaction(spendTx, false, false)
+	if err != nil {
+		t.Fatalf("MaybeAcceptTransaction: failed to accept tx: %v", err)
+	}
+	testPoolMembership(tc, spendTx, false, true)
+	if len(consulted) != numConsulted {
+		t.Fatal("external policy consulted for transaction added back to " +
+			"the pool")
+	}
+}
+
+// TestCheckTransactionStandardViolations ensures all violations of the
+// standardness policy rules by a transaction are reported along with the
+// inputs and outputs that violate them.
+func TestCheckTransactionStandardViolations(t *testing.T) {
+	t.Parallel()
+
+	harness, outputs, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+
+	// Ensure a standard transaction does not have any violations.
+	tx, err := harness.CreateTx(outputs[0])
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	violations, err := harness.txPool.CheckTransactionStandard(tx)
+	if err != nil {
+		t.Fatalf("CheckTransactionStandard: unexpected error: %v", err)
+	}
+	if len(violations) != 0 {
+		t.Fatalf("unexpected violations for standard tx: %v", violations)
+	}
+
+	// Create a fake utxo with a non-standard script form along with a
+	// transaction that spends it and violates several other rules.
+	fundingTx := wire.NewMsgTx()
+	fundingTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 1}, 0, nil))
+	fundingTx.AddTxOut(wire.NewTxOut(1e8, []byte{txscript.OP_TRUE}))
+	funding := dcrutil.NewTx(fundingTx)
+	harness.AddFakeUTXO(funding, harness.chain.BestHeight())
+
+	nullData, err := txscript.NewScriptBuilder().AddOp(txscript.OP_RETURN).
+		AddData([]byte{0x01}).Script()
+	if err != nil {
+		t.Fatalf("unable to create null data script: %v", err)
+	}
+	nonStd := wire.NewMsgTx()
+	nonStd.Version = 0
+	nonStd.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Hash: *funding.Hash()},
+		Sequence:         wire.MaxTxInSequenceNum,
+		ValueIn:          1e8,
+		SignatureScript:  []byte{txscript.OP_NOP},
+	})
+	nonStd.AddTxOut(wire.NewTxOut(1, harness.payScript))
+	for i := 0; i < maxNullDataOutputs+1; i++ {
+		nonStd.AddTxOut(wire.NewTxOut(0, nullData))
+	}
+
+	violations, err = harness.txPool.CheckTransactionStandard(
+		dcrutil.NewTx(nonStd))
+	if err != nil {
+		t.Fatalf("CheckTransactionStandard: unexpected error: %v", err)
+	}
+	want := []struct {
+		rule   StandardRule
+		input  int
+		output int
+	}{
+		{RuleTxVersion, -1, -1},
+		{RuleSigScriptPushOnly, 0, -1},
+		{RuleDust, -1, 0},
+		{RuleNullDataOutputs, -1, -1},
+		{RuleScriptForm, 0, -1},
+	}
+	if len(violations) != len(want) {
+		t.Fatalf("unexpected number of violations -- got %d, want %d: %v",
+			len(violations), len(want), violations)
+	}
+	for i, v := range violations {
+		if v.Rule != want[i].rule || v.Input != want[i].input ||
+			v.Output != want[i].output {
+
+			t.Errorf("violation %d: got rule %s (input %d, output %d), "+
+				"want rule %s (input %d, output %d)", i, v.Rule, v.Input,
+				v.Output, want[i].rule, want[i].input, want[i].output)
+		}
+	}
+}
+
+// TestTxTypePolicies ensures the relay policy overrides for transaction types
+// take precedence over the policy for accepting non-standard transactions and
+// that transactions of rejected types are not accepted.
+func TestTxTypePolicies(t *testing.T) {
+	t.Parallel()
+
+	harness, outputs, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	tc := &testContext{t, harness}
+	policy := &harness.txPool.cfg.Policy
+
+	// Create a transaction with several outputs to spend and mark it as
+	// mined.
+	fundingTx, err := harness.CreateSignedTx(outputs, 3)
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	harness.AddFakeUTXO(fundingTx, harness.chain.BestHeight())
+	var spendable [3]spendableOutput
+	for i := range spendable {
+		spendable[i] = txOutToSpendableOut(fundingTx, uint32(i),
+			wire.TxTreeRegular)
+	}
+
+	// Create regular transactions that are non-standard due to their
+	// version.
+	nonStdVersion := func(tx *wire.MsgTx) { tx.Version = policy.MaxTxVersion + 1 }
+	nonStdTx1, err := harness.CreateSignedTx(spendable[:1], 1, nonStdVersion)
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	nonStdTx2, err := harness.CreateSignedTx(spendable[1:2], 1, nonStdVersion)
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+
+	// Ensure the non-standard transaction is accepted when the policy for
+	// regular transactions accepts non-standard transactions even though
+	// the pool does not.
+	policy.AcceptNonStd = false
+	policy.TxTypePolicies = map[stake.TxType]TxTypePolicy{
+		stake.TxTypeRegular: TxTypePolicyNonStandard,
+	}
+	_, err = harness.txPool.ProcessTransaction(nonStdTx1, false, false, true, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept non-standard "+
+			"transaction: %v", err)
+	}
+	testPoolMembership(tc, nonStdTx1, false, true)
+
+	// Ensure the non-standard transaction is rejected when the policy for
+	// regular transactions only accepts standard transactions even though
+	// the pool accepts non-standard transactions.
+	policy.AcceptNonStd = true
+	policy.TxTypePolicies[stake.TxTypeRegular] = TxTypePolicyStandard
+	_, err = harness.txPool.ProcessTransaction(nonStdTx2, false, false, true, 0)
+	if !IsErrorCode(err, ErrNonStandard) {
+		t.Fatalf("ProcessTransaction: did not get expected ErrNonStandard "+
+			"-- got %v", err)
+	}
+	testPoolMembership(tc, nonStdTx2, false, false)
+
+	// Ensure ticket purchases are rejected when their type is rejected and
+	// accepted once it no longer is.
+	tx, err := harness.CreateTx(spendable[2])
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	ticket, err := harness.CreateTicketPurchase(tx, 40000)
+	if err != nil {
+		t.Fatalf("unable to create ticket purchase transaction: %v", err)
+	}
+	policy.TxTypePolicies[stake.TxTypeSStx] = TxTypePolicyReject
+	_, err = harness.txPool.ProcessTransaction(ticket, true, false, true, 0)
+	if !IsErrorCode(err, ErrNonStandard) {
+		t.Fatalf("ProcessTransaction: did not get expected ErrNonStandard "+
+			"-- got %v", err)
+	}
+	testPoolMembership(tc, ticket, false, false)
+	delete(policy.TxTypePolicies, stake.TxTypeSStx)
+	_, err = harness.txPool.ProcessTransaction(ticket, true, false, true, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept valid orphan: %v", err)
+	}
+	testPoolMembership(tc, ticket, true, false)
+}
+
+// TestEvaluateLocks ensures the lock time and relative lock times of the input
+// sequence numbers of transactions are evaluated to the expected earliest
+// height and past median time at which the transactions become final.
+func TestEvaluateLocks(t *testing.T) {
+	t.Parallel()
+
+	harness, _, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+
+	// Create a mock utxo at the current height with a known median time.
+	baseHeight := harness.chain.BestHeight()
+	baseTime := time.Unix(1592918788, 0)
+	harness.chain.SetPastMedianTime(baseTime)
+	inputMsgTx := wire.NewMsgTx()
+	inputMsgTx.AddTxOut(wire.NewTxOut(1e9, harness.payScript))
+	inputTx := dcrutil.NewTx(inputMsgTx)
+	harness.AddFakeUTXO(inputTx, baseHeight)
+	harness.chain.AddFakeUtxoMedianTime(inputTx, 0, baseTime)
+	input := txOutToSpendableOut(inputTx, 0, wire.TxTreeRegular)
+	nextHeight := baseHeight + 1
+
+	tests := []struct {
+		name       string
+		version    uint16
+		sequence   uint32
+		lockTime   uint32
+		wantStatus LockStatus
+	}{{
+		name:     "no locks",
+		version:  2,
+		sequence: wire.MaxTxInSequenceNum,
+		wantStatus: LockStatus{
+			EarliestHeight: nextHeight,
+			Final:          true,
+		},
+	}, {
+		name:     "lock time ignored with max sequence",
+		version:  2,
+		sequence: wire.MaxTxInSequenceNum,
+		lockTime: uint32(nextHeight + 10),
+		wantStatus: LockStatus{
+			EarliestHeight: nextHeight,
+			Final:          true,
+		},
+	}, {
+		name:     "unsatisfied height lock time",
+		version:  1,
+		sequence: 0,
+		lockTime: uint32(nextHeight + 10),
+		wantStatus: LockStatus{
+			LockTimeHeight: nextHeight + 11,
+			EarliestHeight: nextHeight + 11,
+		},
+	}, {
+		name:     "satisfied time lock time",
+		version:  1,
+		sequence: 0,
+		lockTime: uint32(baseTime.Unix() - 1),
+		wantStatus: LockStatus{
+			LockTimeMedianTime: baseTime.Unix(),
+			EarliestHeight:     nextHeight,
+			EarliestMedianTime: baseTime.Unix(),
+			Final:              true,
+		},
+	}, {
+		name:     "unsatisfied by-height sequence lock",
+		version:  2,
+		sequence: mustLockTimeToSeq(false, 5),
+		wantStatus: LockStatus{
+			SequenceLockHeight: baseHeight + 5,
+			EarliestHeight:     baseHeight + 5,
+		},
+	}, {
+		name:     "sequence lock ignored for version 1",
+		version:  1,
+		sequence: mustLockTimeToSeq(false, 5),
+		wantStatus: LockStatus{
+			EarliestHeight: nextHeight,
+			Final:          true,
+		},
+	}, {
+		name:     "unsatisfied by-time sequence lock and height lock time",
+		version:  2,
+		sequence: mustLockTimeToSeq(true, seqIntervalToSecs(2)),
+		lockTime: uint32(baseHeight + 10),
+		wantStatus: LockStatus{
+			LockTimeHeight:         baseHeight + 11,
+			SequenceLockMedianTime: baseTime.Unix() + 1024,
+			EarliestHeight:         baseHeight + 11,
+			EarliestMedianTime:     baseTime.Unix() + 1024,
+		},
+	}}
+
+	for _, test := range tests {
+		tx, err := harness.CreateSignedTx([]spendableOutput{input}, 1,
+			func(tx *wire.MsgTx) {
+				tx.Version = test.version
+				tx.TxIn[0].Sequence = test.sequence
+				tx.LockTime = test.lockTime
+			})
+		if err != nil {
+			t.Fatalf("%s: unable to create tx: %v", test.name, err)
+		}
+
+		status, err := harness.txPool.EvaluateLocks(tx)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		want := test.wantStatus
+		want.NextHeight = nextHeight
+		want.MedianTime = baseTime
+		if !reflect.DeepEqual(*status, want) {
+			t.Errorf("%s: mismatched status -- got %+v, want %+v", test.name,
+				*status, want)
+		}
+	}
+
+	// Ensure an error is returned when an input with a relative lock time
+	// spends an output that is not available.
+	tx, err := harness.CreateSignedTx([]spendableOutput{{
+		outPoint: wire.OutPoint{Index: 1},
+		amount:   1e8,
+	}}, 1, func(tx *wire.MsgTx) {
+		tx.Version = 2
+		tx.TxIn[0].Sequence = mustLockTimeToSeq(false, 1)
+	})
+	if err != nil {
+		t.Fatalf("unable to create tx: %v", err)
+	}
+	if _, err := harness.txPool.EvaluateLocks(tx); err == nil {
+		t.Fatal("did not get expected error for missing input")
+	}
+}
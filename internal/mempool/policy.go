@@ -69,6 +69,54 @@ const (
 		txscript.ScriptVerifyCheckSequenceVerify
 )
 
+// TxTypePolicy overrides the relay policy that applies to a type of
+// transaction.  It is intended to allow private network operators to test how
+// transaction types behave before the deployments that govern them activate,
+// or without them, without requiring custom builds.
+type TxTypePolicy uint8
+
+const (
+	// TxTypePolicyDefault applies the relay policy of the pool to the
+	// transaction type.
+	TxTypePolicyDefault TxTypePolicy = iota
+
+	// TxTypePolicyStandard only accepts and relays transactions of the type
+	// when they are standard regardless of whether or not the pool accepts
+	// non-standard transactions.
+	TxTypePolicyStandard
+
+	// TxTypePolicyNonStandard accepts and relays transactions of the type
+	// even when they are non-standard regardless of whether or not the pool
+	// accepts non-standard transactions.
+	TxTypePolicyNonStandard
+
+	// TxTypePolicyReject rejects all transactions of the type so they are
+	// neither accepted to the pool nor relayed.
+	TxTypePolicyReject
+)
+
+// txTypeNames houses the human-readable names of the transaction types for
+// use in rejection messages.
+var txTypeNames = map[stake.TxType]string{
+	stake.TxTypeRegular: "regular",
+	stake.TxTypeSStx:    "ticket",
+	stake.TxTypeSSGen:   "vote",
+	stake.TxTypeSSRtx:   "revocation",
+}
+
+// acceptNonStd returns whether or not non-standard transactions of the passed
+// type are accepted taking any relay policy override for the type into
+// account.
+func (p *Policy) acceptNonStd(txType stake.TxType) bool {
+	switch p.TxTypePolicies[txType] {
+	case TxTypePolicyStandard:
+		return false
+	case TxTypePolicyNonStandard:
+		return true
+	}
+	return p.AcceptNonStd
+}
+
 // calcMinRequiredTxRelayFee returns the minimum transaction fee required for a
 // transaction with the passed serialized size to be accepted into the memory
 // pool and relayed.
@@ -93,117 +141,259 @@ func calcMinRequiredTxRelayFee(serializedSize int64, minRelayTxFee dcrutil.Amoun
 	return minFee
 }
 
-// checkInputsStandard performs a series of checks on a transaction's inputs
-// to ensure they are "standard".  A standard transaction input within the
-// context of this function is one whose referenced public key script is of a
-// standard form and, for pay-to-script-hash, does not have more than
-// maxStandardP2SHSigOps signature operations.  However, it should also be noted
-// that standard inputs also are those which have a clean stack after execution
-// and only contain pushed data in their signature scripts.  This function does
-// not perform those checks because the script engine already does this more
-// accurately and concisely via the txscript.ScriptVerifyCleanStack and
-// txscript.ScriptVerifySigPushOnly flags.
-//
-// Note: all non-nil errors MUST be RuleError with an underlying TxRuleError
-// instance.
-func checkInputsStandard(tx *dcrutil.Tx, txType stake.TxType, utxoView *blockchain.UtxoViewpoint) error {
-	// NOTE: The reference implementation also does a coinbase check here,
-	// but coinbases have already been rejected prior to calling this
-	// function so no need to recheck.
+// StandardRule identifies a standardness policy rule.
+type StandardRule string
+
+// These constants identify the standardness policy rules a transaction may
+// violate.
+const (
+	// RuleSerType requires transactions to be serialized with all of their
+	// data.
+	RuleSerType StandardRule = "sertype"
+
+	// RuleTxVersion requires transactions to have a version in the range
+	// considered standard.
+	RuleTxVersion StandardRule = "txversion"
+
+	// RuleFinalized requires transactions to be finalized.
+	RuleFinalized StandardRule = "finalized"
+
+	// RuleTxSize requires transactions to not exceed the maximum standard
+	// serialized size.
+	RuleTxSize StandardRule = "txsize"
+
+	// RuleSigScriptSize requires input signature scripts to not exceed the
+	// maximum standard size.
+	RuleSigScriptSize StandardRule = "sigscriptsize"
+
+	// RuleSigScriptPushOnly requires input signature scripts to only push
+	// data.
+	RuleSigScriptPushOnly StandardRule = "sigscriptpushonly"
+
+	// RuleScriptVersion requires output scripts and the scripts spent by
+	// inputs to be of a script version with an active policy.
+	RuleScriptVersion StandardRule = "scriptversion"
 
+	// RuleScriptForm requires output scripts and the scripts spent by inputs
+	// to be of a form the policy for their script version considers standard.
+	RuleScriptForm StandardRule = "scriptform"
+
+	// RuleMultiSig requires multi-signature output scripts to have a number
+	// of public keys and signatures allowed by the policy.
+	RuleMultiSig StandardRule = "multisig"
+
+	// RuleP2SHSigOps requires inputs that spend pay-to-script-hash outputs
+	// to not exceed the maximum number of signature operations allowed by
+	// the policy.
+	RuleP2SHSigOps StandardRule = "p2shsigops"
+
+	// RuleDust requires regular transaction outputs to not be dust.
+	RuleDust StandardRule = "dust"
+
+	// RuleNullDataOutputs requires regular transactions to not exceed the
+	// maximum number of null data outputs.
+	RuleNullDataOutputs StandardRule = "nulldataoutputs"
+)
+
+// StandardViolation describes a violation of a standardness policy rule by a
+// transaction.
+type StandardViolation struct {
+	// Rule is the policy rule that is violated.
+	Rule StandardRule
+
+	// Input is the index of the input that violates the rule.  It is -1
+	// when the rule does not apply to a specific input.
+	Input int
+
+	// Output is the index of the output that violates the rule.  It is -1
+	// when the rule does not apply to a specific output.
+	Output int
+
+	// Description is a human readable description of the violation.
+	Description string
+}
+
+// txViolation returns a violation of the provided rule by a transaction as a
+// whole.
+func txViolation(rule StandardRule, desc string) StandardViolation {
+	return StandardViolation{Rule: rule, Input: -1, Output: -1, Description: desc}
+}
+
+// inputViolation returns a violation of the provided rule by the input of a
+// transaction with the provided index.
+func inputViolation(rule StandardRule, input int, desc string) StandardViolation {
+	return StandardViolation{Rule: rule, Input: input, Output: -1, Description: desc}
+}
+
+// outputViolation returns a violation of the provided rule by the output of a
+// transaction with the provided index.
+func outputViolation(rule StandardRule, output int, desc string) StandardViolation {
+	return StandardViolation{Rule: rule, Input: -1, Output: output, Description: desc}
+}
+
+// violationError returns the provided violation as a RuleError with an
+// underlying TxRuleError instance.
+func violationError(v *StandardViolation) error {
+	code := ErrNonStandard
+	if v.Rule == RuleDust {
+		code = ErrDustOutput
+	}
+	return txRuleError(code, v.Description)
+}
+
+// lintInputsStandard returns all violations of the standardness policy rules
+// by the inputs of a transaction.  See checkInputsStandard for details.
+//
+// Inputs that spend outputs which are not available or already spent in the
+// provided view are skipped.
+func lintInputsStandard(tx *dcrutil.Tx, txType stake.TxType, utxoView *blockchain.UtxoViewpoint, policies activeScriptPolicies) []StandardViolation {
+	var violations []StandardViolation
 	for i, txIn := range tx.MsgTx().TxIn {
 		if i == 0 && txType == stake.TxTypeSSGen {
 			continue
 		}
 
-		// It is safe to elide existence and index checks here since
-		// they have already been checked prior to calling this
-		// function.
 		prevOut := txIn.PreviousOutPoint
 		entry := utxoView.LookupEntry(&prevOut.Hash)
+		if entry == nil || entry.IsOutputSpent(prevOut.Index) {
+			continue
+		}
 		originPkScriptVer := entry.ScriptVersionByIndex(prevOut.Index)
 		originPkScript := entry.PkScriptByIndex(prevOut.Index)
-		switch txscript.GetScriptClass(originPkScriptVer, originPkScript) {
-		case txscript.ScriptHashTy:
+		policy, ok := policies[originPkScriptVer]
+		if !ok {
+			str := fmt.Sprintf("transaction input #%d has a "+
+				"non-standard script version %d", i,
+				originPkScriptVer)
+			violations = append(violations, inputViolation(
+				RuleScriptVersion, i, str))
+			continue
+		}
+		scriptClass := txscript.GetScriptClass(originPkScriptVer,
+			originPkScript)
+		if !policy.isStandardClass(scriptClass) {
+			str := fmt.Sprintf("transaction input #%d has a "+
+				"non-standard script form", i)
+			violations = append(violations, inputViolation(RuleScriptForm,
+				i, str))
+			continue
+		}
+		if scriptClass == txscript.ScriptHashTy {
 			numSigOps := txscript.GetPreciseSigOpCount(
 				txIn.SignatureScript, originPkScript)
-			if numSigOps > maxStandardP2SHSigOps {
+			if numSigOps > policy.MaxP2SHSigOps {
 				str := fmt.Sprintf("transaction input #%d has "+
 					"%d signature operations which is more "+
 					"than the allowed max amount of %d",
-					i, numSigOps, maxStandardP2SHSigOps)
-				return txRuleError(ErrNonStandard, str)
+					i, numSigOps, policy.MaxP2SHSigOps)
+				violations = append(violations, inputViolation(
+					RuleP2SHSigOps, i, str))
 			}
-
-		case txscript.NonStandardTy:
-			str := fmt.Sprintf("transaction input #%d has a "+
-				"non-standard script form", i)
-			return txRuleError(ErrNonStandard, str)
 		}
 	}
 
-	return nil
+	return violations
 }
 
-// checkPkScriptStandard performs a series of checks on a transaction output
-// script (public key script) to ensure it is a "standard" public key script.
-// A standard public key script is one that is a recognized form, and for
-// multi-signature scripts, only contains from 1 to maxStandardMultiSigKeys
-// public keys.
+// checkInputsStandard performs a series of checks on a transaction's inputs
+// to ensure they are "standard".  A standard transaction input within the
+// context of this function is one whose referenced public key script is of a
+// standard version and form according to the provided active script policies
+// and, for pay-to-script-hash, does not have more than the maximum number of
+// signature operations allowed by the policy.  However, it should also be noted
+// that standard inputs also are those which have a clean stack after execution
+// and only contain pushed data in their signature scripts.  This function does
+// not perform those checks because the script engine already does this more
+// accurately and concisely via the txscript.ScriptVerifyCleanStack and
+// txscript.ScriptVerifySigPushOnly flags.
 //
 // Note: all non-nil errors MUST be RuleError with an underlying TxRuleError
 // instance.
-func checkPkScriptStandard(version uint16, pkScript []byte,
-	scriptClass txscript.ScriptClass) error {
-	// Only default Bitcoin-style script is standard except for
-	// null data outputs.
-	if version != wire.DefaultPkScriptVersion {
-		str := fmt.Sprintf("versions other than default pkscript version " +
-			"are currently non-standard except for provably unspendable " +
-			"outputs")
-		return txRuleError(ErrNonStandard, str)
+func checkInputsStandard(tx *dcrutil.Tx, txType stake.TxType, utxoView *blockchain.UtxoViewpoint, policies activeScriptPolicies) error {
+	// NOTE: The reference implementation also does a coinbase check here,
+	// but coinbases have already been rejected prior to calling this
+	// function so no need to recheck.
+	//
+	// It is safe to rely on the inputs being available in the view here
+	// since their existence has already been checked prior to calling this
+	// function.
+	violations := lintInputsStandard(tx, txType, utxoView, policies)
+	if len(violations) > 0 {
+		return violationError(&violations[0])
+	}
+	return nil
+}
+
+// pkScriptViolation returns the rule violated by the provided public key
+// script along with a description of the violation.  The returned rule is
+// empty when the script is standard.  See checkPkScriptStandard for details.
+func pkScriptViolation(version uint16, pkScript []byte,
+	scriptClass txscript.ScriptClass, policies activeScriptPolicies) (StandardRule, string) {
+
+	policy, ok := policies[version]
+	if !ok {
+		str := fmt.Sprintf("script version %d is not currently standard",
+			version)
+		return RuleScriptVersion, str
+	}
+	if !policy.isStandardClass(scriptClass) {
+		return RuleScriptForm, "non-standard script form"
 	}
 
-	switch scriptClass {
-	case txscript.MultiSigTy:
+	if scriptClass == txscript.MultiSigTy {
 		numPubKeys, numSigs, err := txscript.CalcMultiSigStats(pkScript)
 		if err != nil {
 			str := fmt.Sprintf("multi-signature script parse "+
 				"failure: %v", err)
-			return txRuleError(ErrNonStandard, str)
+			return RuleMultiSig, str
 		}
 
 		// A standard multi-signature public key script must contain
-		// from 1 to maxStandardMultiSigKeys public keys.
+		// from 1 to the maximum number of public keys allowed by the
+		// policy.
 		if numPubKeys < 1 {
-			str := "multi-signature script with no pubkeys"
-			return txRuleError(ErrNonStandard, str)
+			return RuleMultiSig, "multi-signature script with no pubkeys"
 		}
-		if numPubKeys > maxStandardMultiSigKeys {
+		if numPubKeys > policy.MaxMultiSigKeys {
 			str := fmt.Sprintf("multi-signature script with %d "+
 				"public keys which is more than the allowed "+
-				"max of %d", numPubKeys, maxStandardMultiSigKeys)
-			return txRuleError(ErrNonStandard, str)
+				"max of %d", numPubKeys, policy.MaxMultiSigKeys)
+			return RuleMultiSig, str
 		}
 
 		// A standard multi-signature public key script must have at
 		// least 1 signature and no more signatures than available
 		// public keys.
 		if numSigs < 1 {
-			return txRuleError(ErrNonStandard,
-				"multi-signature script with no signatures")
+			return RuleMultiSig, "multi-signature script with no signatures"
 		}
 		if numSigs > numPubKeys {
 			str := fmt.Sprintf("multi-signature script with %d "+
 				"signatures which is more than the available "+
 				"%d public keys", numSigs, numPubKeys)
-			return txRuleError(ErrNonStandard, str)
+			return RuleMultiSig, str
 		}
-
-	case txscript.NonStandardTy:
-		return txRuleError(ErrNonStandard, "non-standard script form")
 	}
 
+	return "", ""
+}
+
+// checkPkScriptStandard performs a series of checks on a transaction output
+// script (public key script) to ensure it is a "standard" public key script.
+// A standard public key script is one that has a version with an active
+// policy in the provided active script policies, is of a form the policy
+// considers standard, and for multi-signature scripts, only contains from 1 to
+// the maximum number of public keys allowed by the policy.
+//
+// Note: all non-nil errors MUST be RuleError with an underlying TxRuleError
+// instance.
+func checkPkScriptStandard(version uint16, pkScript []byte,
+	scriptClass txscript.ScriptClass, policies activeScriptPolicies) error {
+
+	rule, desc := pkScriptViolation(version, pkScript, scriptClass, policies)
+	if rule != "" {
+		return txRuleError(ErrNonStandard, desc)
+	}
 	return nil
 }
 
@@ -280,19 +470,13 @@ func isDust(txOut *wire.TxOut, minRelayTxFee dcrutil.Amount) bool {
 	return txOut.Value*1000/(3*int64(totalSize)) < int64(minRelayTxFee)
 }
 
-// checkTransactionStandard performs a series of checks on a transaction to
-// ensure it is a "standard" transaction.  A standard transaction is one that
-// conforms to several additional limiting cases over what is considered a
-// "sane" transaction such as having a version in the supported range, being
-// finalized, conforming to more stringent size constraints, having scripts
-// of recognized forms, and not containing "dust" outputs (those that are
-// so small it costs more to process them than they are worth).
-//
-// Note: all non-nil errors MUST be RuleError with an underlying TxRuleError
-// instance.
-func checkTransactionStandard(tx *dcrutil.Tx, txType stake.TxType, height int64,
+// lintTransactionStandard returns all violations of the standardness policy
+// rules by a transaction.  See checkTransactionStandard for details.
+func lintTransactionStandard(tx *dcrutil.Tx, txType stake.TxType, height int64,
 	medianTime time.Time, minRelayTxFee dcrutil.Amount,
-	maxTxVersion uint16) error {
+	maxTxVersion uint16, policies activeScriptPolicies) []StandardViolation {
+
+	var violations []StandardViolation
 
 	// The transaction must be a currently supported version and serialize
 	// type.
@@ -300,18 +484,19 @@ func checkTransactionStandard(tx *dcrutil.Tx, txType stake.TxType, height int64,
 	if msgTx.SerType != wire.TxSerializeFull {
 		str := fmt.Sprintf("transaction is not serialized with all "+
 			"required data -- type %v", msgTx.SerType)
-		return txRuleError(ErrNonStandard, str)
+		violations = append(violations, txViolation(RuleSerType, str))
 	}
 	if msgTx.Version > maxTxVersion || msgTx.Version < 1 {
 		str := fmt.Sprintf("transaction version %d is not in the "+
 			"valid range of %d-%d", msgTx.Version, 1, maxTxVersion)
-		return txRuleError(ErrNonStandard, str)
+		violations = append(violations, txViolation(RuleTxVersion, str))
 	}
 
 	// The transaction must be finalized to be standard and therefore
 	// considered for inclusion in a block.
 	if !blockchain.IsFinalizedTransaction(tx, height, medianTime) {
-		return txRuleError(ErrNonStandard, "transaction is not finalized")
+		violations = append(violations, txViolation(RuleFinalized,
+			"transaction is not finalized"))
 	}
 
 	// Since extremely large transactions with a lot of inputs can cost
@@ -322,7 +507,7 @@ func checkTransactionStandard(tx *dcrutil.Tx, txType stake.TxType, height int64,
 	if serializedLen > MaxStandardTxSize {
 		str := fmt.Sprintf("transaction size of %v is larger than max "+
 			"allowed size of %v", serializedLen, MaxStandardTxSize)
-		return txRuleError(ErrNonStandard, str)
+		violations = append(violations, txViolation(RuleTxSize, str))
 	}
 
 	for i, txIn := range msgTx.TxIn {
@@ -335,7 +520,8 @@ func checkTransactionStandard(tx *dcrutil.Tx, txType stake.TxType, height int64,
 				"script size of %d bytes is large than max "+
 				"allowed size of %d bytes", i, sigScriptLen,
 				maxStandardSigScriptSize)
-			return txRuleError(ErrNonStandard, str)
+			violations = append(violations, inputViolation(
+				RuleSigScriptSize, i, str))
 		}
 
 		// Each transaction input signature script must only contain
@@ -343,9 +529,9 @@ func checkTransactionStandard(tx *dcrutil.Tx, txType stake.TxType, height int64,
 		if !txscript.IsPushOnlyScript(txIn.SignatureScript) {
 			str := fmt.Sprintf("transaction input %d: signature "+
 				"script is not push only", i)
-			return txRuleError(ErrNonStandard, str)
+			violations = append(violations, inputViolation(
+				RuleSigScriptPushOnly, i, str))
 		}
-
 	}
 
 	// None of the output public key scripts can be a non-standard script or
@@ -353,10 +539,12 @@ func checkTransactionStandard(tx *dcrutil.Tx, txType stake.TxType, height int64,
 	numNullDataOutputs := 0
 	for i, txOut := range msgTx.TxOut {
 		scriptClass := txscript.GetScriptClass(txOut.Version, txOut.PkScript)
-		err := checkPkScriptStandard(txOut.Version, txOut.PkScript, scriptClass)
-		if err != nil {
-			str := fmt.Sprintf("transaction output %d: %v", i, err)
-			return wrapTxRuleError(ErrNonStandard, str, err)
+		rule, desc := pkScriptViolation(txOut.Version, txOut.PkScript,
+			scriptClass, policies)
+		if rule != "" {
+			str := fmt.Sprintf("transaction output %d: %v", i, desc)
+			violations = append(violations, outputViolation(rule, i, str))
+			continue
 		}
 
 		// Accumulate the number of outputs which only carry data.  For
@@ -367,7 +555,8 @@ func checkTransactionStandard(tx *dcrutil.Tx, txType stake.TxType, height int64,
 		} else if txType == stake.TxTypeRegular && isDust(txOut, minRelayTxFee) {
 			str := fmt.Sprintf("transaction output %d: payment "+
 				"of %d is dust", i, txOut.Value)
-			return txRuleError(ErrDustOutput, str)
+			violations = append(violations, outputViolation(RuleDust, i,
+				str))
 		}
 	}
 
@@ -378,8 +567,66 @@ func checkTransactionStandard(tx *dcrutil.Tx, txType stake.TxType, height int64,
 	if numNullDataOutputs > maxNullDataOutputs && txType == stake.TxTypeRegular {
 		str := "more than one transaction output in a nulldata script for a " +
 			"regular type tx"
-		return txRuleError(ErrNonStandard, str)
+		violations = append(violations, txViolation(RuleNullDataOutputs,
+			str))
 	}
 
+	return violations
+}
+
+// checkTransactionStandard performs a series of checks on a transaction to
+// ensure it is a "standard" transaction.  A standard transaction is one that
+// conforms to several additional limiting cases over what is considered a
+// "sane" transaction such as having a version in the supported range, being
+// finalized, conforming to more stringent size constraints, having scripts
+// of versions and forms that are standard according to the provided active
+// script policies, and not containing "dust" outputs (those that are so small
+// it costs more to process them than they are worth).
+//
+// Note: all non-nil errors MUST be RuleError with an underlying TxRuleError
+// instance.
+func checkTransactionStandard(tx *dcrutil.Tx, txType stake.TxType, height int64,
+	medianTime time.Time, minRelayTxFee dcrutil.Amount,
+	maxTxVersion uint16, policies activeScriptPolicies) error {
+
+	violations := lintTransactionStandard(tx, txType, height, medianTime,
+		minRelayTxFee, maxTxVersion, policies)
+	if len(violations) > 0 {
+		return violationError(&violations[0])
+	}
 	return nil
 }
+
+// CheckTransactionStandard returns all violations of the standardness policy
+// rules by the provided transaction, as opposed to only the first one, in
+// order to allow callers to determine everything that would prevent the
+// transaction from being accepted as standard.  No violations are returned
+// when the transaction is standard.
+//
+// Standardness is determined as of the next block regardless of whether or
+// not the pool accepts non-standard transactions.  Inputs that spend outputs
+// which are not unspent in either the main chain or the pool are skipped.  Note that
+// the transaction is not otherwise validated.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) CheckTransactionStandard(tx *dcrutil.Tx) ([]StandardViolation, error) {
+	policies, err := mp.activeScriptPolicies()
+	if err != nil {
+		return nil, err
+	}
+
+	txType := stake.DetermineTxType(tx.MsgTx())
+	nextBlockHeight := mp.cfg.BestHeight() + 1
+	violations := lintTransactionStandard(tx, txType, nextBlockHeight,
+		mp.cfg.PastMedianTime(), mp.cfg.Policy.MinRelayTxFee,
+		mp.cfg.Policy.MaxTxVersion, policies)
+
+	mp.mtx.RLock()
+	utxoView, err := mp.fetchInputUtxos(tx)
+	mp.mtx.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	inputViolations := lintInputsStandard(tx, txType, utxoView, policies)
+	return append(violations, inputViolations...), nil
+}
@@ -183,6 +183,10 @@ func TestCheckPkScriptStandard(t *testing.T) {
 		},
 	}
 
+	policies, err := activateScriptPolicies(scriptVersionPolicies, nil)
+	if err != nil {
+		t.Fatalf("activateScriptPolicies: unexpected error: %v", err)
+	}
 	for _, test := range tests {
 		script, err := test.script.Script()
 		if err != nil {
@@ -191,7 +195,7 @@ func TestCheckPkScriptStandard(t *testing.T) {
 			continue
 		}
 		scriptClass := txscript.GetScriptClass(0, script)
-		got := checkPkScriptStandard(0, script, scriptClass)
+		got := checkPkScriptStandard(0, script, scriptClass, policies)
 		if (test.isStandard && got != nil) ||
 			(!test.isStandard && got == nil) {
 
@@ -526,13 +530,17 @@ func TestCheckTransactionStandard(t *testing.T) {
 		},
 	}
 
+	policies, err := activateScriptPolicies(scriptVersionPolicies, nil)
+	if err != nil {
+		t.Fatalf("activateScriptPolicies: unexpected error: %v", err)
+	}
 	medianTime := time.Now()
 	for _, test := range tests {
 		// Ensure standardness is as expected.
 		tx := dcrutil.NewTx(&test.tx)
 		err := checkTransactionStandard(tx, stake.DetermineTxType(&test.tx),
 			test.height, medianTime, DefaultMinRelayTxFee,
-			maxTxVersion)
+			maxTxVersion, policies)
 		if err == nil && test.isStandard {
 			// Test passes since function returned standard for a
 			// transaction which is intended to be standard.
@@ -0,0 +1,204 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mempool
+
+import (
+	"time"
+)
+
+const (
+	// statsBucketInterval is the duration of time covered by each bucket of
+	// the rolling mempool statistics.  It is the granularity of the windows
+	// the statistics are reported for.
+	statsBucketInterval = time.Minute
+
+	// numStatsBuckets is the number of buckets of rolling mempool statistics
+	// that are retained.  It must be large enough to cover the longest window
+	// the statistics are reported for.
+	numStatsBuckets = int64(24 * time.Hour / statsBucketInterval)
+
+	// noFeeRate is the value used for the minimum fee rate of a bucket when
+	// no transactions that pay a fee rate were accepted during it.
+	noFeeRate = -1
+)
+
+// statsBucket houses the mempool statistics for a single interval of time.
+type statsBucket struct {
+	interval   int64
+	maxCount   int
+	maxBytes   int64
+	numEvicted uint64
+	minFeeRate int64
+}
+
+// poolStats tracks rolling mempool statistics in fixed intervals of time so
+// the high-water marks, number of evicted transactions, and minimum accepted
+// fee rate can be reported for recent windows of time.
+//
+// The buckets are filled forward whenever the pool is modified, so every
+// retained bucket reflects the largest size of the pool during its interval
+// even when the pool was not modified during it.
+type poolStats struct {
+	buckets      [numStatsBuckets]statsBucket
+	lastInterval int64
+	numBytes     int64
+	numEvicted   uint64
+}
+
+// bucket returns the bucket for the interval that includes the provided time.
+// Any buckets for the intervals since the most recently updated bucket are
+// reset with the provided pool size, which must be the size of the pool prior
+// to the modification that is about to be recorded.
+func (s *poolStats) bucket(now time.Time, count int) *statsBucket {
+	interval := now.UnixNano() / int64(statsBucketInterval)
+
+	// Use the most recent bucket when the clock moves backwards.
+	if interval < s.lastInterval {
+		interval = s.lastInterval
+	}
+	if interval > s.lastInterval {
+		start := s.lastInterval + 1
+		if interval-start >= numStatsBuckets {
+			start = interval - numStatsBuckets + 1
+		}
+		for i := start; i <= interval; i++ {
+			s.buckets[i%numStatsBuckets] = statsBucket{
+				interval:   i,
+				maxCount:   count,
+				maxBytes:   s.numBytes,
+				minFeeRate: noFeeRate,
+			}
+		}
+		s.lastInterval = interval
+	}
+	return &s.buckets[interval%numStatsBuckets]
+}
+
+// added records that a transaction with the provided size and fee rate was
+// added to the pool.  The count is the number of transactions in the pool
+// prior to adding the transaction.  A negative fee rate indicates the
+// transaction does not pay a fee rate that applies to the minimum accepted fee
+// rate.
+func (s *poolStats) added(now time.Time, count int, size, feeRate int64) {
+	b := s.bucket(now, count)
+	s.numBytes += size
+	if count+1 > b.maxCount {
+		b.maxCount = count + 1
+	}
+	if s.numBytes > b.maxBytes {
+		b.maxBytes = s.numBytes
+	}
+	if feeRate >= 0 && (b.minFeeRate == noFeeRate || feeRate < b.minFeeRate) {
+		b.minFeeRate = feeRate
+	}
+}
+
+// removed records that a transaction with the provided size was removed from
+// the pool.  The count is the number of transactions in the pool prior to
+// removing the transaction.
+func (s *poolStats) removed(now time.Time, count int, size int64) {
+	s.bucket(now, count)
+	s.numBytes -= size
+}
+
+// evicted records that the provided number of transactions were evicted from
+// the pool without being included in a block.  The count is the number of
+// transactions in the pool after they were evicted.
+func (s *poolStats) evicted(now time.Time, count int, numEvicted int) {
+	if numEvicted == 0 {
+		return
+	}
+	b := s.bucket(now, count)
+	b.numEvicted += uint64(numEvicted)
+	s.numEvicted += uint64(numEvicted)
+}
+
+// PoolWatermarks describes the mempool over a recent window of time.
+type PoolWatermarks struct {
+	// MaxCount is the maximum number of transactions in the pool.
+	MaxCount int
+
+	// MaxBytes is the maximum total serialized size of the transactions in
+	// the pool.
+	MaxBytes int64
+
+	// NumEvicted is the number of transactions that were evicted from the
+	// pool without being included in a block.
+	NumEvicted uint64
+
+	// MinFeeRate is the minimum fee rate, in atoms/kB, of the regular
+	// transactions that were accepted to the pool.  It is -1 when no regular
+	// transactions were accepted.
+	MinFeeRate int64
+}
+
+// PoolStats describes the current state of the mempool along with rolling
+// statistics over recent windows of time.
+type PoolStats struct {
+	// Count is the number of transactions in the pool.
+	Count int
+
+	// Bytes is the total serialized size of the transactions in the pool.
+	Bytes int64
+
+	// NumEvicted is the number of transactions that were evicted from the
+	// pool without being included in a block since the pool was created.
+	NumEvicted uint64
+
+	// LastHour and LastDay describe the pool over the last hour and day,
+	// respectively.
+	LastHour PoolWatermarks
+	LastDay  PoolWatermarks
+}
+
+// watermarks returns the watermarks for the window of the provided duration
+// that ends at the provided time given the provided current number of
+// transactions in the pool.  The window is rounded to the bucket interval.
+func (s *poolStats) watermarks(now time.Time, window time.Duration, count int) PoolWatermarks {
+	wm := PoolWatermarks{
+		MaxCount:   count,
+		MaxBytes:   s.numBytes,
+		MinFeeRate: noFeeRate,
+	}
+	end := now.UnixNano() / int64(statsBucketInterval)
+	if end < s.lastInterval {
+		end = s.lastInterval
+	}
+	numIntervals := int64(window / statsBucketInterval)
+	for i := end - numIntervals + 1; i <= end; i++ {
+		// Skip buckets that are outside of the retained history or that have
+		// not been reached yet since the pool has not been modified since the
+		// most recently updated bucket.  The latter are accounted for by the
+		// current pool size.
+		b := &s.buckets[((i%numStatsBuckets)+numStatsBuckets)%numStatsBuckets]
+		if b.interval != i {
+			continue
+		}
+		if b.maxCount > wm.MaxCount {
+			wm.MaxCount = b.maxCount
+		}
+		if b.maxBytes > wm.MaxBytes {
+			wm.MaxBytes = b.maxBytes
+		}
+		wm.NumEvicted += b.numEvicted
+		if b.minFeeRate != noFeeRate && (wm.MinFeeRate == noFeeRate ||
+			b.minFeeRate < wm.MinFeeRate) {
+			wm.MinFeeRate = b.minFeeRate
+		}
+	}
+	return wm
+}
+
+// stats returns the current mempool statistics given the provided current
+// number of transactions in the pool.
+func (s *poolStats) stats(now time.Time, count int) *PoolStats {
+	return &PoolStats{
+		Count:      count,
+		Bytes:      s.numBytes,
+		NumEvicted: s.numEvicted,
+		LastHour:   s.watermarks(now, time.Hour, count),
+		LastDay:    s.watermarks(now, 24*time.Hour, count),
+	}
+}
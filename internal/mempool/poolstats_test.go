@@ -0,0 +1,132 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mempool
+
+import (
+	"testing"
+	"time"
+)
+
+// TestPoolStats ensures the rolling mempool statistics report the expected
+// high-water marks, number of evicted transactions, and minimum accepted fee
+// rates for the last hour and day as the pool is modified over time.
+func TestPoolStats(t *testing.T) {
+	t.Parallel()
+
+	var stats poolStats
+	var count int
+	start := time.Unix(1600000000, 0)
+	add := func(offset time.Duration, size, feeRate int64) {
+		stats.added(start.Add(offset), count, size, feeRate)
+		count++
+	}
+	remove := func(offset time.Duration, size int64) {
+		stats.removed(start.Add(offset), count, size)
+		count--
+	}
+	evict := func(offset time.Duration, size int64) {
+		remove(offset, size)
+		stats.evicted(start.Add(offset), count, 1)
+	}
+
+	// Grow the pool to three transactions, evict one, and mine the others to
+	// empty it.  Only the first and third transactions are regular
+	// transactions that count towards the minimum fee rate.
+	add(0, 1000, 20000)
+	add(time.Minute, 2000, -1)
+	add(2*time.Minute, 500, 10000)
+	evict(3*time.Minute, 2000)
+	remove(4*time.Minute, 1000)
+	remove(4*time.Minute, 500)
+
+	// Ensure the watermarks include the maximum size of the pool for both
+	// windows shortly after the pool was emptied.
+	want := PoolStats{
+		NumEvicted: 1,
+		LastHour: PoolWatermarks{
+			MaxCount:   3,
+			MaxBytes:   3500,
+			NumEvicted: 1,
+			MinFeeRate: 10000,
+		},
+		LastDay: PoolWatermarks{
+			MaxCount:   3,
+			MaxBytes:   3500,
+			NumEvicted: 1,
+			MinFeeRate: 10000,
+		},
+	}
+	if got := stats.stats(start.Add(5*time.Minute), count); *got != want {
+		t.Fatalf("unexpected stats -- got %+v, want %+v", *got, want)
+	}
+
+	// Add a single transaction two hours later and ensure the hourly window no
+	// longer includes the earlier activity while the daily window does.
+	add(2*time.Hour, 800, 30000)
+	want = PoolStats{
+		Count:      1,
+		Bytes:      800,
+		NumEvicted: 1,
+		LastHour: PoolWatermarks{
+			MaxCount:   1,
+			MaxBytes:   800,
+			MinFeeRate: 30000,
+		},
+		LastDay: PoolWatermarks{
+			MaxCount:   3,
+			MaxBytes:   3500,
+			NumEvicted: 1,
+			MinFeeRate: 10000,
+		},
+	}
+	if got := stats.stats(start.Add(2*time.Hour), count); *got != want {
+		t.Fatalf("unexpected stats -- got %+v, want %+v", *got, want)
+	}
+
+	// Ensure the pool size that persists without modifications is reflected
+	// in the watermarks once the previous activity is outside of both windows.
+	want = PoolStats{
+		Count:      1,
+		Bytes:      800,
+		NumEvicted: 1,
+		LastHour: PoolWatermarks{
+			MaxCount:   1,
+			MaxBytes:   800,
+			MinFeeRate: noFeeRate,
+		},
+		LastDay: PoolWatermarks{
+			MaxCount:   1,
+			MaxBytes:   800,
+			MinFeeRate: noFeeRate,
+		},
+	}
+	if got := stats.stats(start.Add(48*time.Hour), count); *got != want {
+		t.Fatalf("unexpected stats -- got %+v, want %+v", *got, want)
+	}
+
+	// Ensure a modification after the pool was unmodified for longer than the
+	// retained history resets the buckets with the persisted pool size and
+	// that a clock that moves backwards is attributed to the latest bucket.
+	remove(72*time.Hour, 800)
+	add(71*time.Hour, 300, 5000)
+	want = PoolStats{
+		Count:      1,
+		Bytes:      300,
+		NumEvicted: 1,
+		LastHour: PoolWatermarks{
+			MaxCount:   1,
+			MaxBytes:   800,
+			MinFeeRate: 5000,
+		},
+		LastDay: PoolWatermarks{
+			MaxCount:   1,
+			MaxBytes:   800,
+			MinFeeRate: 5000,
+		},
+	}
+	if got := stats.stats(start.Add(72*time.Hour), count); *got != want {
+		t.Fatalf("unexpected stats -- got %+v, want %+v", *got, want)
+	}
+}
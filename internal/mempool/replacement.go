@@ -0,0 +1,301 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mempool
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/decred/dcrd/blockchain/stake/v3"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/decred/dcrd/wire"
+)
+
+const (
+	// maxReplacementEvictions is the maximum number of transactions that a
+	// single replacement transaction may evict from the pool, including the
+	// transactions it directly conflicts with along with all of their
+	// descendants.  This bounds the amount of work an attacker can cause with
+	// a single transaction.
+	maxReplacementEvictions = 100
+
+	// maxReplacementHistory is the maximum number of replacement decisions
+	// that are retained.
+	maxReplacementHistory = 100
+)
+
+// ReplacementDecision describes the outcome of evaluating a transaction that
+// conflicts with transactions in the pool as a replacement for them.
+type ReplacementDecision struct {
+	// Time is when the decision was made.
+	Time time.Time
+
+	// Hash is the hash of the replacement transaction.
+	Hash chainhash.Hash
+
+	// Conflicts are the hashes of the transactions in the pool that the
+	// replacement directly conflicts with.
+	Conflicts []chainhash.Hash
+
+	// NumEvicted is the number of transactions that were, or would have been,
+	// evicted by the replacement including the descendants of the conflicts.
+	NumEvicted int
+
+	// Fee and FeeRate are the fee and fee rate, in atoms and atoms/kB, of the
+	// replacement transaction.
+	Fee     int64
+	FeeRate int64
+
+	// MinFee and MinFeeRate are the minimum fee and fee rate, in atoms and
+	// atoms/kB, the replacement transaction was required to pay.
+	MinFee     int64
+	MinFeeRate int64
+
+	// Accepted is whether or not the replacement was accepted.
+	Accepted bool
+
+	// Reason describes why the replacement was rejected.  It is empty when
+	// the replacement was accepted.
+	Reason string
+}
+
+// ReplacementHistory describes the replacement policy of the pool along with
+// the most recent replacement decisions.
+type ReplacementHistory struct {
+	// Enabled is whether or not transactions may be replaced.
+	Enabled bool
+
+	// MinFeeRateIncrease is the minimum percentage by which the fee rate of a
+	// replacement must exceed the fee rate of each transaction it directly
+	// conflicts with.
+	MinFeeRateIncrease float64
+
+	// Decisions are the most recent replacement decisions ordered from oldest
+	// to newest.
+	Decisions []ReplacementDecision
+}
+
+// feeRate returns the fee rate in atoms/kB for the provided fee and size.
+func feeRate(fee, size int64) int64 {
+	return fee * 1000 / size
+}
+
+// containsHash returns whether or not the provided hashes contain the provided
+// hash.
+func containsHash(hashes []chainhash.Hash, hash chainhash.Hash) bool {
+	for i := range hashes {
+		if hashes[i] == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// replacementConflicts returns the transactions in the main pool that the
+// provided regular transaction conflicts with when replacements are enabled.
+//
+// An error is returned when the transaction conflicts with any transactions
+// that are not eligible to be replaced, which is the case for all
+// transactions when replacements are disabled.  Only regular transactions in
+// the main pool may be replaced by other regular transactions.
+//
+// This function MUST be called with the mempool lock held (for reads).
+func (mp *TxPool) replacementConflicts(tx *dcrutil.Tx, txType stake.TxType) (map[chainhash.Hash]*TxDesc, error) {
+	if !mp.cfg.Policy.EnableReplacement || txType != stake.TxTypeRegular {
+		return nil, mp.checkPoolDoubleSpend(tx, txType)
+	}
+
+	var conflicts map[chainhash.Hash]*TxDesc
+	for _, txIn := range tx.MsgTx().TxIn {
+		if txR, exists := mp.stagedOutpoints[txIn.PreviousOutPoint]; exists {
+			str := fmt.Sprintf("staged transaction %v in the pool "+
+				"already spends the same coins", txR.Hash())
+			return nil, txRuleError(ErrMempoolDoubleSpend, str)
+		}
+
+		txR, exists := mp.outpoints[txIn.PreviousOutPoint]
+		if !exists {
+			continue
+		}
+		txDesc := mp.pool[*txR.Hash()]
+		if txDesc.Type != stake.TxTypeRegular {
+			str := fmt.Sprintf("transaction %v in the pool already spends "+
+				"the same coins and is not eligible to be replaced",
+				txR.Hash())
+			return nil, txRuleError(ErrMempoolDoubleSpend, str)
+		}
+		if conflicts == nil {
+			conflicts = make(map[chainhash.Hash]*TxDesc)
+		}
+		conflicts[*txR.Hash()] = txDesc
+	}
+
+	return conflicts, nil
+}
+
+// replacementEvictions returns the transactions in the main pool that would
+// be evicted when replacing the provided conflicting transactions, which
+// consist of the conflicts themselves along with all of their descendants.
+// The staged transactions that descend from them are not included since they
+// do not affect the fee requirements.
+//
+// Note that the search stops once the maximum number of evictions allowed for
+// a replacement is exceeded.
+//
+// This function MUST be called with the mempool lock held (for reads).
+func (mp *TxPool) replacementEvictions(conflicts map[chainhash.Hash]*TxDesc) map[chainhash.Hash]*TxDesc {
+	evictions := make(map[chainhash.Hash]*TxDesc, len(conflicts))
+	queue := make([]*TxDesc, 0, len(conflicts))
+	for hash, txDesc := range conflicts {
+		evictions[hash] = txDesc
+		queue = append(queue, txDesc)
+	}
+	for len(queue) > 0 && len(evictions) <= maxReplacementEvictions {
+		txDesc := queue[0]
+		queue = queue[1:]
+
+		prevOut := wire.OutPoint{Hash: *txDesc.Tx.Hash(), Tree: txDesc.Tx.Tree()}
+		for i := range txDesc.Tx.MsgTx().TxOut {
+			prevOut.Index = uint32(i)
+			txR, exists := mp.outpoints[prevOut]
+			if !exists {
+				continue
+			}
+			if _, ok := evictions[*txR.Hash()]; ok {
+				continue
+			}
+			redeemerDesc := mp.pool[*txR.Hash()]
+			evictions[*txR.Hash()] = redeemerDesc
+			queue = append(queue, redeemerDesc)
+		}
+	}
+	return evictions
+}
+
+// checkReplacement ensures the provided transaction, which conflicts with the
+// provided transactions in the pool, satisfies the replacement policy.  It
+// returns the decision that describes the outcome along with the transactions
+// that must be evicted from the pool to accept the replacement.  The returned
+// error is nil when the replacement is accepted.
+//
+// The replacement policy requires that the replacement:
+//
+//  - Does not spend any outputs of the transactions it would evict
+//  - Does not evict more than maxReplacementEvictions transactions
+//  - Pays a fee rate that exceeds the fee rate of each transaction it directly
+//    conflicts with by at least the configured percentage
+//  - Pays a fee that covers the fees of all transactions it evicts plus the
+//    minimum relay fee for its own size
+//
+// This function MUST be called with the mempool lock held (for reads).
+func (mp *TxPool) checkReplacement(tx *dcrutil.Tx, txFee, txSize int64, conflicts map[chainhash.Hash]*TxDesc) (*ReplacementDecision, map[chainhash.Hash]*TxDesc, error) {
+	decision := &ReplacementDecision{
+		Time:      time.Now(),
+		Hash:      *tx.Hash(),
+		Conflicts: make([]chainhash.Hash, 0, len(conflicts)),
+		Fee:       txFee,
+		FeeRate:   feeRate(txFee, txSize),
+	}
+	reject := func(code ErrorCode, reason string) (*ReplacementDecision, map[chainhash.Hash]*TxDesc, error) {
+		decision.Reason = reason
+		str := fmt.Sprintf("replacement transaction %v rejected: %s",
+			tx.Hash(), reason)
+		return decision, nil, txRuleError(code, str)
+	}
+
+	// Determine the minimum fee rate required by the transactions the
+	// replacement directly conflicts with.  They are iterated in the order of
+	// the inputs that conflict with them so the decision is deterministic.
+	increase := 1 + mp.cfg.Policy.MinReplacementFeeRateIncrease/100
+	for _, txIn := range tx.MsgTx().TxIn {
+		txR, exists := mp.outpoints[txIn.PreviousOutPoint]
+		if !exists {
+			continue
+		}
+		hash := *txR.Hash()
+		txDesc, ok := conflicts[hash]
+		if !ok || containsHash(decision.Conflicts, hash) {
+			continue
+		}
+		decision.Conflicts = append(decision.Conflicts, hash)
+		conflictSize := int64(txDesc.Tx.MsgTx().SerializeSize())
+		conflictRate := feeRate(txDesc.Fee, conflictSize)
+		minFeeRate := int64(float64(conflictRate) * increase)
+		if minFeeRate > decision.MinFeeRate {
+			decision.MinFeeRate = minFeeRate
+		}
+	}
+
+	// Determine all of the transactions the replacement would evict and the
+	// minimum fee required to cover their fees along with the relay fee of
+	// the replacement itself.
+	evictions := mp.replacementEvictions(conflicts)
+	decision.NumEvicted = len(evictions)
+	decision.MinFee = calcMinRequiredTxRelayFee(txSize,
+		mp.cfg.Policy.MinRelayTxFee)
+	for _, txDesc := range evictions {
+		decision.MinFee += txDesc.Fee
+	}
+
+	if len(evictions) > maxReplacementEvictions {
+		reason := fmt.Sprintf("evicts more than the maximum allowed %d "+
+			"transactions", maxReplacementEvictions)
+		return reject(ErrReplacementPolicyViolation, reason)
+	}
+	for _, txIn := range tx.MsgTx().TxIn {
+		if _, ok := evictions[txIn.PreviousOutPoint.Hash]; ok {
+			reason := fmt.Sprintf("spends an output of transaction %v that "+
+				"it replaces", txIn.PreviousOutPoint.Hash)
+			return reject(ErrReplacementPolicyViolation, reason)
+		}
+	}
+	if decision.FeeRate < decision.MinFeeRate {
+		reason := fmt.Sprintf("fee rate of %d atoms/kB is under the "+
+			"required %d atoms/kB", decision.FeeRate, decision.MinFeeRate)
+		return reject(ErrInsufficientReplacementFee, reason)
+	}
+	if txFee < decision.MinFee {
+		reason := fmt.Sprintf("fee of %v is under the required %v to cover "+
+			"the fees of the %d evicted transactions and its relay fee",
+			dcrutil.Amount(txFee), dcrutil.Amount(decision.MinFee),
+			len(evictions))
+		return reject(ErrInsufficientReplacementFee, reason)
+	}
+
+	decision.Accepted = true
+	return decision, evictions, nil
+}
+
+// recordReplacement adds the provided replacement decision to the history of
+// recent decisions while discarding the oldest decision when the maximum
+// number of decisions is reached.
+//
+// This function MUST be called with the mempool lock held (for writes).
+func (mp *TxPool) recordReplacement(decision *ReplacementDecision) {
+	if len(mp.replacements) == maxReplacementHistory {
+		copy(mp.replacements, mp.replacements[1:])
+		mp.replacements = mp.replacements[:maxReplacementHistory-1]
+	}
+	mp.replacements = append(mp.replacements, *decision)
+}
+
+// Replacements returns the replacement policy of the pool along with the most
+// recent decisions about transactions that were evaluated as replacements for
+// conflicting transactions in the pool.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) Replacements() *ReplacementHistory {
+	mp.mtx.RLock()
+	decisions := make([]ReplacementDecision, len(mp.replacements))
+	copy(decisions, mp.replacements)
+	mp.mtx.RUnlock()
+
+	return &ReplacementHistory{
+		Enabled:            mp.cfg.Policy.EnableReplacement,
+		MinFeeRateIncrease: mp.cfg.Policy.MinReplacementFeeRateIncrease,
+		Decisions:          decisions,
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mempool
+
+import (
+	"github.com/decred/dcrd/txscript/v3"
+	"github.com/decred/dcrd/wire"
+)
+
+// ScriptVersionPolicy describes the standardness policy for the public key
+// scripts of a single script version.
+type ScriptVersionPolicy struct {
+	// Version is the script version the policy applies to.
+	Version uint16
+
+	// Agenda is the id of the agenda that must be active for the policy to
+	// apply.  It is empty when the policy always applies.
+	Agenda string
+
+	// Classes are the script classes that are considered standard.
+	Classes []txscript.ScriptClass
+
+	// MaxMultiSigKeys is the maximum number of public keys allowed in a
+	// standard multi-signature script.
+	MaxMultiSigKeys int
+
+	// MaxP2SHSigOps is the maximum number of signature operations allowed
+	// in a standard pay-to-script-hash redeem script.
+	MaxP2SHSigOps int
+}
+
+// isStandardClass returns whether or not the provided script class is
+// considered standard by the policy.
+func (p *ScriptVersionPolicy) isStandardClass(class txscript.ScriptClass) bool {
+	for _, standardClass := range p.Classes {
+		if class == standardClass {
+			return true
+		}
+	}
+	return false
+}
+
+// scriptVersionPolicies houses the standardness policies for all script
+// versions that are either standard now or may become standard once an agenda
+// activates.  The policies are applied in order, so an active policy replaces
+// any earlier policy for the same script version.
+//
+// Standardness for a new script version, or a change to the policy of an
+// existing one, that depends on the result of an agenda vote is added by
+// appending an entry with the id of the agenda.
+var scriptVersionPolicies = []ScriptVersionPolicy{{
+	Version: wire.DefaultPkScriptVersion,
+	Classes: []txscript.ScriptClass{
+		txscript.PubKeyTy,
+		txscript.PubkeyAltTy,
+		txscript.PubKeyHashTy,
+		txscript.PubkeyHashAltTy,
+		txscript.ScriptHashTy,
+		txscript.MultiSigTy,
+		txscript.NullDataTy,
+		txscript.StakeSubmissionTy,
+		txscript.StakeGenTy,
+		txscript.StakeRevocationTy,
+		txscript.StakeSubChangeTy,
+	},
+	MaxMultiSigKeys: maxStandardMultiSigKeys,
+	MaxP2SHSigOps:   maxStandardP2SHSigOps,
+}}
+
+// activeScriptPolicies maps script versions to the standardness policy that
+// applies to them.  Scripts with versions that do not have an entry are
+// non-standard.
+type activeScriptPolicies map[uint16]*ScriptVersionPolicy
+
+// activateScriptPolicies returns the standardness policies from the provided
+// policies that apply given the state of the agendas reported by the provided
+// function.  Only the policies that do not depend on an agenda apply when the
+// function is nil.
+func activateScriptPolicies(policies []ScriptVersionPolicy, isAgendaActive func(string) (bool, error)) (activeScriptPolicies, error) {
+	active := make(activeScriptPolicies, len(policies))
+	for i := range policies {
+		policy := &policies[i]
+		if policy.Agenda != "" {
+			if isAgendaActive == nil {
+				continue
+			}
+			isActive, err := isAgendaActive(policy.Agenda)
+			if err != nil {
+				return nil, err
+			}
+			if !isActive {
+				continue
+			}
+		}
+		active[policy.Version] = policy
+	}
+	return active, nil
+}
+
+// ScriptVersionStatus describes the standardness policy for a script version
+// along with whether or not it currently applies.
+type ScriptVersionStatus struct {
+	ScriptVersionPolicy
+
+	// Active is whether or not the policy currently applies.
+	Active bool
+}
+
+// StandardPolicy describes the policy the pool uses to determine whether or
+// not transactions are standard.
+type StandardPolicy struct {
+	// AcceptNonStd is whether or not non-standard transactions are accepted.
+	AcceptNonStd bool
+
+	// MaxTxVersion is the maximum standard transaction version.
+	MaxTxVersion uint16
+
+	// MaxTxSize is the maximum serialized size of a standard transaction.
+	MaxTxSize int
+
+	// MaxSigScriptSize is the maximum size of a standard signature script.
+	MaxSigScriptSize int
+
+	// MaxNullDataOutputs is the maximum number of null data outputs allowed
+	// in a standard regular transaction.
+	MaxNullDataOutputs int
+
+	// ScriptVersions are the standardness policies for all known script
+	// versions in the order they are applied.
+	ScriptVersions []ScriptVersionStatus
+}
+
+// activeScriptPolicies returns the standardness policies that apply to the
+// scripts of transactions that are candidates for the next block.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) activeScriptPolicies() (activeScriptPolicies, error) {
+	return activateScriptPolicies(scriptVersionPolicies,
+		mp.cfg.Policy.IsAgendaActive)
+}
+
+// StandardPolicy returns the policy used to determine whether or not
+// transactions are standard including the standardness policies for all known
+// script versions and whether or not they currently apply.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) StandardPolicy() (*StandardPolicy, error) {
+	active, err := mp.activeScriptPolicies()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]ScriptVersionStatus, 0, len(scriptVersionPolicies))
+	for i := range scriptVersionPolicies {
+		policy := &scriptVersionPolicies[i]
+		statuses = append(statuses, ScriptVersionStatus{
+			ScriptVersionPolicy: *policy,
+			Active:              active[policy.Version] == policy,
+		})
+	}
+
+	return &StandardPolicy{
+		AcceptNonStd:       mp.cfg.Policy.AcceptNonStd,
+		MaxTxVersion:       mp.cfg.Policy.MaxTxVersion,
+		MaxTxSize:          MaxStandardTxSize,
+		MaxSigScriptSize:   maxStandardSigScriptSize,
+		MaxNullDataOutputs: maxNullDataOutputs,
+		ScriptVersions:     statuses,
+	}, nil
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mempool
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
+	"github.com/decred/dcrd/txscript/v3"
+)
+
+// TestActivateScriptPolicies ensures the script version standardness policies
+// that depend on agendas only apply once their agendas are active, that active
+// policies replace earlier policies for the same script version, and that the
+// resulting policies are enforced by the public key script standardness checks.
+func TestActivateScriptPolicies(t *testing.T) {
+	t.Parallel()
+
+	// Create a table with a version 0 policy that raises the maximum number
+	// of multi-signature keys and a version 1 policy that both depend on
+	// agendas.  Note that scripts with nonzero versions are not classified
+	// by txscript, so the version 1 policy accepts the non-standard class.
+	policies := []ScriptVersionPolicy{scriptVersionPolicies[0], {
+		Version:         0,
+		Agenda:          "multisig",
+		Classes:         scriptVersionPolicies[0].Classes,
+		MaxMultiSigKeys: 4,
+		MaxP2SHSigOps:   maxStandardP2SHSigOps,
+	}, {
+		Version:         1,
+		Agenda:          "scriptv1",
+		Classes:         []txscript.ScriptClass{txscript.NonStandardTy},
+		MaxMultiSigKeys: maxStandardMultiSigKeys,
+		MaxP2SHSigOps:   maxStandardP2SHSigOps,
+	}}
+	agendas := func(activeIDs ...string) func(string) (bool, error) {
+		return func(agendaID string) (bool, error) {
+			for _, id := range activeIDs {
+				if id == agendaID {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+	}
+
+	// Create a 1-of-4 multi-signature script which is only standard once
+	// the policy that raises the maximum number of keys applies.
+	builder := txscript.NewScriptBuilder().AddOp(txscript.OP_1)
+	for i := 0; i < 4; i++ {
+		pk := secp256k1.NewPrivateKey(new(secp256k1.ModNScalar).SetInt(1))
+		builder.AddData(pk.PubKey().SerializeCompressed())
+	}
+	multiSigScript, err := builder.AddOp(txscript.OP_4).
+		AddOp(txscript.OP_CHECKMULTISIG).Script()
+	if err != nil {
+		t.Fatalf("unexpected script builder error: %v", err)
+	}
+
+	tests := []struct {
+		name         string                     // test description
+		active       func(string) (bool, error) // agenda state
+		wantPolicies []*ScriptVersionPolicy     // expected active policies
+		multiSig     bool                       // 1-of-4 multisig standard
+		version1     bool                       // version 1 standard
+	}{{
+		name:         "no agenda state",
+		active:       nil,
+		wantPolicies: []*ScriptVersionPolicy{&policies[0]},
+	}, {
+		name:         "no active agendas",
+		active:       agendas(),
+		wantPolicies: []*ScriptVersionPolicy{&policies[0]},
+	}, {
+		name:         "multisig agenda active",
+		active:       agendas("multisig"),
+		wantPolicies: []*ScriptVersionPolicy{&policies[1]},
+		multiSig:     true,
+	}, {
+		name:         "all agendas active",
+		active:       agendas("multisig", "scriptv1"),
+		wantPolicies: []*ScriptVersionPolicy{&policies[1], &policies[2]},
+		multiSig:     true,
+		version1:     true,
+	}}
+
+	for _, test := range tests {
+		active, err := activateScriptPolicies(policies, test.active)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.name, err)
+			continue
+		}
+		if len(active) != len(test.wantPolicies) {
+			t.Errorf("%q: unexpected number of active policies -- got %d, "+
+				"want %d", test.name, len(active), len(test.wantPolicies))
+			continue
+		}
+		for _, want := range test.wantPolicies {
+			if got := active[want.Version]; got != want {
+				t.Errorf("%q: unexpected policy for version %d -- got %+v, "+
+					"want %+v", test.name, want.Version, got, want)
+			}
+		}
+
+		err = checkPkScriptStandard(0, multiSigScript, txscript.MultiSigTy,
+			active)
+		if test.multiSig != (err == nil) {
+			t.Errorf("%q: unexpected multisig standardness -- got %v, "+
+				"want standard %v", test.name, err, test.multiSig)
+		}
+		if err != nil && !IsErrorCode(err, ErrNonStandard) {
+			t.Errorf("%q: unexpected error code -- got %v", test.name, err)
+		}
+
+		err = checkPkScriptStandard(1, []byte{txscript.OP_TRUE},
+			txscript.NonStandardTy, active)
+		if test.version1 != (err == nil) {
+			t.Errorf("%q: unexpected version 1 standardness -- got %v, "+
+				"want standard %v", test.name, err, test.version1)
+		}
+	}
+
+	// Ensure errors determining the state of agendas are returned.
+	errAgenda := errors.New("agenda state unavailable")
+	_, err = activateScriptPolicies(policies, func(string) (bool, error) {
+		return false, errAgenda
+	})
+	if !errors.Is(err, errAgenda) {
+		t.Fatalf("unexpected error -- got %v, want %v", err, errAgenda)
+	}
+}
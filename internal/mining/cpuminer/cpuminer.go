@@ -666,6 +666,26 @@ func (m *CPUMiner) NumWorkers() int32 {
 // added to a side chain if it happens to be solved around the same time another
 // one shows up.
 func (m *CPUMiner) GenerateNBlocks(ctx context.Context, n uint32) ([]*chainhash.Hash, error) {
+	return m.generateNBlocks(ctx, n, nil)
+}
+
+// GenerateNBlocksToAddress generates the requested number of blocks that pay
+// the coinbase to the provided address instead of the configured mining
+// addresses in the discrete mining mode and returns a list of the hashes of
+// generated blocks that were added to the main chain.
+//
+// The templates provided by the background block template generator are only
+// used to determine when a template that includes all available votes can be
+// created, so it otherwise behaves the same as GenerateNBlocks.
+func (m *CPUMiner) GenerateNBlocksToAddress(ctx context.Context, n uint32, payToAddr dcrutil.Address) ([]*chainhash.Hash, error) {
+	return m.generateNBlocks(ctx, n, payToAddr)
+}
+
+// generateNBlocks generates the requested number of blocks in the discrete
+// mining mode that pay to the provided address or the address of the templates
+// provided by the background block template generator when it is nil.  See
+// GenerateNBlocks for more details.
+func (m *CPUMiner) generateNBlocks(ctx context.Context, n uint32, payToAddr dcrutil.Address) ([]*chainhash.Hash, error) {
 	// Nothing to do.
 	if n == 0 {
 		return nil, nil
@@ -698,6 +718,7 @@ func (m *CPUMiner) GenerateNBlocks(ctx context.Context, n uint32) ([]*chainhash.
 
 	blockHashes := make([]*chainhash.Hash, 0, n)
 	var stats speedStats
+	var genErr error
 out:
 	for {
 		// Wait for a new template update notification or early shutdown.
@@ -721,6 +742,24 @@ out:
 			continue
 		}
 
+		// Generate a template that pays to the requested address when one
+		// was provided.  Wait for the next template update when it does not
+		// build on the same parent as the background template since the
+		// chain tip changed in the mean time.
+		template := templateNtfn.Template
+		if payToAddr != nil {
+			var err error
+			template, err = m.g.NewBlockTemplate(payToAddr)
+			if err != nil {
+				genErr = err
+				break out
+			}
+			prevBlock := templateNtfn.Template.Block.Header.PrevBlock
+			if template == nil || template.Block.Header.PrevBlock != prevBlock {
+				continue
+			}
+		}
+
 		// Attempt to solve the block.
 		//
 		// The function will exit with false if the block was not solved for any
@@ -736,7 +775,7 @@ out:
 		//
 		// The block in the template is shallow copied to avoid mutating the
 		// data of the shared template.
-		shallowBlockCopy := *templateNtfn.Template.Block
+		shallowBlockCopy := *template.Block
 		if m.solveBlock(ctx, &shallowBlockCopy.Header, &stats, ticker) {
 			block := dcrutil.NewBlock(&shallowBlockCopy)
 			if m.submitBlock(block) {
@@ -758,7 +797,7 @@ out:
 	m.Lock()
 	m.discreteMining = false
 	m.Unlock()
-	return blockHashes, nil
+	return blockHashes, genErr
 }
 
 // New returns a new instance of a CPU miner for the provided configuration
@@ -9,6 +9,7 @@ import (
 	"container/heap"
 	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"math"
 	"math/rand"
@@ -120,10 +121,16 @@ const (
 	// a block header and max possible transaction count.
 	blockHeaderOverhead = wire.MaxBlockHeaderPayload + wire.MaxVarIntPayload
 
-	// coinbaseFlags is some extra data appended to the coinbase script
-	// sig.
+	// coinbaseFlags is the default extra data appended to the coinbase
+	// script sig.
 	coinbaseFlags = "/dcrd/"
 
+	// MaxCoinbaseExtraDataLen is the maximum length of the extra data that
+	// may be appended to the coinbase script sig.  The script sig starts
+	// with two zero bytes that are followed by the extra data and its total
+	// length is limited by consensus.
+	MaxCoinbaseExtraDataLen = blockchain.MaxCoinbaseScriptLen - 2
+
 	// kilobyte is the size of a kilobyte.
 	kilobyte = 1000
 
@@ -771,8 +778,7 @@ func (g *BlkTmplGenerator) handleTooFewVoters(nextHeight int64, miningAddress dc
 		if err != nil {
 			return nil, err
 		}
-		coinbaseScript := make([]byte, len(coinbaseFlags)+2)
-		copy(coinbaseScript[2:], coinbaseFlags)
+		coinbaseScript := g.coinbaseScript()
 		opReturnPkScript, err := standardCoinbaseOpReturn(tipHeader.Height, rand)
 		if err != nil {
 			return nil, err
@@ -898,6 +904,16 @@ type BlkTmplGenerator struct {
 	blockManager     blockManagerFacade
 	timeSource       blockchain.MedianTimeSource
 	miningTimeOffset int
+
+	// extraData is the extra data appended to the coinbase script sig of
+	// generated block templates.  It may be changed at runtime and is
+	// protected by the associated mutex.
+	extraDataMtx sync.RWMutex
+	extraData    []byte
+
+	// decisions houses the transaction selection decisions made for the
+	// most recently generated block templates.
+	decisions *templateDecisionLog
 }
 
 // NewBlkTmplGenerator returns a new block template generator for the given
@@ -918,9 +934,65 @@ func NewBlkTmplGenerator(policy *Policy, txSource TxSource,
 		blockManager:     blockManager,
 		timeSource:       timeSource,
 		miningTimeOffset: miningTimeOffset,
+		extraData:        []byte(coinbaseFlags),
+		decisions:        newTemplateDecisionLog(MaxTemplateDecisions),
 	}
 }
 
+// TemplateDecisions returns the transaction selection decisions made for up to
+// the provided number of the most recently generated block templates ordered
+// from newest to oldest.
+//
+// This function is safe for concurrent access.
+func (g *BlkTmplGenerator) TemplateDecisions(count int) []*TemplateDecisions {
+	return g.decisions.latest(count)
+}
+
+// CoinbaseExtraData returns the extra data that is appended to the coinbase
+// script sig of generated block templates.
+//
+// This function is safe for concurrent access.
+func (g *BlkTmplGenerator) CoinbaseExtraData() []byte {
+	g.extraDataMtx.RLock()
+	extraData := make([]byte, len(g.extraData))
+	copy(extraData, g.extraData)
+	g.extraDataMtx.RUnlock()
+	return extraData
+}
+
+// SetCoinbaseExtraData sets the extra data that is appended to the coinbase
+// script sig of block templates generated from now on, such as a pool tag.
+// An error is returned when the data exceeds MaxCoinbaseExtraDataLen.
+//
+// This function is safe for concurrent access.
+func (g *BlkTmplGenerator) SetCoinbaseExtraData(extraData []byte) error {
+	if len(extraData) > MaxCoinbaseExtraDataLen {
+		return fmt.Errorf("coinbase extra data is %d bytes which exceeds "+
+			"the maximum allowed length of %d bytes", len(extraData),
+			MaxCoinbaseExtraDataLen)
+	}
+
+	extraDataCopy := make([]byte, len(extraData))
+	copy(extraDataCopy, extraData)
+	g.extraDataMtx.Lock()
+	g.extraData = extraDataCopy
+	g.extraDataMtx.Unlock()
+	return nil
+}
+
+// coinbaseScript returns the script sig to use for coinbase transactions of
+// generated block templates.  It consists of two zero bytes followed by the
+// current coinbase extra data.
+//
+// This function is safe for concurrent access.
+func (g *BlkTmplGenerator) coinbaseScript() []byte {
+	g.extraDataMtx.RLock()
+	script := make([]byte, 2, 2+len(g.extraData))
+	script = append(script, g.extraData...)
+	g.extraDataMtx.RUnlock()
+	return script
+}
+
 // NewBlockTemplate returns a new block template that is ready to be solved
 // using the transactions from the passed transaction source pool and a coinbase
 // that either pays to the passed address if it is not nil, or a coinbase that
@@ -1132,19 +1204,27 @@ func (g *BlkTmplGenerator) NewBlockTemplate(payToAddress dcrutil.Address) (*Bloc
 		len(sourceTxns))
 	knownDisapproved := g.txSource.IsRegTxTreeKnownDisapproved(&prevHash)
 
+	// Track why each of the source transactions is selected or skipped so
+	// the decisions can be audited.
+	decisions := newTxDecisionSet(len(sourceTxns))
+
 mempoolLoop:
 	for _, txDesc := range sourceTxns {
+		decisions.consider(txDesc)
+
 		// A block can't have more than one coinbase or contain
 		// non-finalized transactions.
 		tx := txDesc.Tx
 		msgTx := tx.MsgTx()
 		if standalone.IsCoinBaseTx(msgTx) {
 			log.Tracef("Skipping coinbase tx %s", tx.Hash())
+			decisions.decide(tx.Hash(), TxSkippedCoinbase)
 			continue
 		}
 		if !blockchain.IsFinalizedTransaction(tx, nextBlockHeight,
 			best.MedianTime) {
 			log.Tracef("Skipping non-finalized tx %s", tx.Hash())
+			decisions.decide(tx.Hash(), TxSkippedNonFinal)
 			continue
 		}
 
@@ -1157,6 +1237,7 @@ mempoolLoop:
 				(int64(blockHeight) == nextBlockHeight-1)) {
 				log.Tracef("Skipping ssgen tx %s because it does "+
 					"not vote on the correct block", tx.Hash())
+				decisions.decide(tx.Hash(), TxSkippedWrongVoteBlock)
 				continue
 			}
 		}
@@ -1169,6 +1250,7 @@ mempoolLoop:
 		if err != nil {
 			log.Warnf("Unable to fetch utxo view for tx %s: "+
 				"%v", tx.Hash(), err)
+			decisions.decide(tx.Hash(), TxSkippedInputsUnavailable)
 			continue
 		}
 
@@ -1193,6 +1275,8 @@ mempoolLoop:
 						"it references unspent output "+
 						"%s which is not available",
 						tx.Hash(), txIn.PreviousOutPoint)
+					decisions.decide(tx.Hash(),
+						TxSkippedInputsUnavailable)
 					continue mempoolLoop
 				}
 
@@ -1294,12 +1378,14 @@ mempoolLoop:
 			log.Tracef("Skipping sstx %s because it would exceed "+
 				"the max number of sstx allowed in a block", tx.Hash())
 			logSkippedDeps(tx, deps)
+			decisions.decide(tx.Hash(), TxSkippedMaxFreshStake)
 			continue
 		}
 
 		// Skip if the SStx commit value is below the value required by the
 		// stake diff.
 		if isSStx && (tx.MsgTx().TxOut[0].Value < best.NextStakeDiff) {
+			decisions.decide(tx.Hash(), TxSkippedTicketPrice)
 			continue
 		}
 
@@ -1308,6 +1394,7 @@ mempoolLoop:
 			ticketHash := &tx.MsgTx().TxIn[0].PreviousOutPoint.Hash
 
 			if !hashInSlice(*ticketHash, best.MissedTickets) {
+				decisions.decide(tx.Hash(), TxSkippedUnknownRevocation)
 				continue
 			}
 		}
@@ -1322,6 +1409,7 @@ mempoolLoop:
 				"size %v, cur num tx %v", tx.Hash(), txSize,
 				blockSize, len(blockTxns))
 			logSkippedDeps(tx, deps)
+			decisions.decide(tx.Hash(), TxSkippedBlockSize)
 			continue
 		}
 
@@ -1333,6 +1421,7 @@ mempoolLoop:
 			log.Tracef("Skipping tx %s because it would "+
 				"exceed the maximum sigops per block", tx.Hash())
 			logSkippedDeps(tx, deps)
+			decisions.decide(tx.Hash(), TxSkippedSigOps)
 			continue
 		}
 
@@ -1344,6 +1433,7 @@ mempoolLoop:
 			log.Tracef("Skipping tx %s due to error in "+
 				"CountP2SHSigOps: %v", tx.Hash(), err)
 			logSkippedDeps(tx, deps)
+			decisions.decide(tx.Hash(), TxSkippedInvalid)
 			continue
 		}
 		numSigOps += int64(numP2SHSigOps)
@@ -1353,6 +1443,7 @@ mempoolLoop:
 				"exceed the maximum sigops per block (p2sh)",
 				tx.Hash())
 			logSkippedDeps(tx, deps)
+			decisions.decide(tx.Hash(), TxSkippedSigOps)
 			continue
 		}
 
@@ -1360,6 +1451,7 @@ mempoolLoop:
 		// valid for the next block.
 		if isSSGen {
 			if foundWinningTickets[tx.MsgTx().TxIn[1].PreviousOutPoint.Hash] {
+				decisions.decide(tx.Hash(), TxSkippedIneligibleVote)
 				continue
 			}
 			msgTx := tx.MsgTx()
@@ -1371,6 +1463,7 @@ mempoolLoop:
 			}
 
 			if !isEligible {
+				decisions.decide(tx.Hash(), TxSkippedIneligibleVote)
 				continue
 			}
 		}
@@ -1388,6 +1481,7 @@ mempoolLoop:
 				g.policy.TxMinFreeFee, blockPlusTxSize,
 				g.policy.BlockMinSize)
 			logSkippedDeps(tx, deps)
+			decisions.decide(tx.Hash(), TxSkippedLowFee)
 			continue
 		}
 
@@ -1430,6 +1524,7 @@ mempoolLoop:
 			log.Tracef("Skipping tx %s due to error in "+
 				"CheckTransactionInputs: %v", tx.Hash(), err)
 			logSkippedDeps(tx, deps)
+			decisions.decide(tx.Hash(), TxSkippedInvalid)
 			continue
 		}
 		err = blockchain.ValidateTransactionScripts(tx, blockUtxos,
@@ -1438,6 +1533,7 @@ mempoolLoop:
 			log.Tracef("Skipping tx %s due to error in "+
 				"ValidateTransactionScripts: %v", tx.Hash(), err)
 			logSkippedDeps(tx, deps)
+			decisions.decide(tx.Hash(), TxSkippedInvalid)
 			continue
 		}
 
@@ -1465,6 +1561,7 @@ mempoolLoop:
 
 		txFeesMap[*tx.Hash()] = prioItem.fee
 		txSigOpCountsMap[*tx.Hash()] = numSigOps
+		decisions.decide(tx.Hash(), TxSelected)
 
 		log.Tracef("Adding tx %s (priority %.2f, feePerKB %.2f)",
 			prioItem.tx.Hash(), prioItem.priority, prioItem.feePerKB)
@@ -1651,8 +1748,7 @@ mempoolLoop:
 	// identical transaction for block version 1).
 	// Decred: We need to move this downwards because of the requirements
 	// to incorporate voters and potential voters.
-	coinbaseScript := []byte{0x00, 0x00}
-	coinbaseScript = append(coinbaseScript, []byte(coinbaseFlags)...)
+	coinbaseScript := g.coinbaseScript()
 
 	// Add a random coinbase nonce to ensure that tx prefix hash
 	// so that our merkle root is unique for lookups needed for
@@ -1940,6 +2036,32 @@ mempoolLoop:
 		standalone.CompactToBig(msgBlock.Header.Bits),
 		dcrutil.Amount(msgBlock.Header.SBits).ToCoin())
 
+	// Record the transaction selection decisions for the template.  Any
+	// transactions that were selected above, but did not make it into the
+	// final block were removed while assembling the stake tree or due to
+	// the regular tree of the parent being disapproved.
+	finalTxns := make(map[chainhash.Hash]struct{}, len(blockTxnsRegular)+
+		len(blockTxnsStake))
+	for _, tx := range blockTxnsRegular {
+		finalTxns[*tx.Hash()] = struct{}{}
+	}
+	for _, tx := range blockTxnsStake {
+		finalTxns[*tx.Hash()] = struct{}{}
+	}
+	for i := range decisions.txns {
+		txd := &decisions.txns[i]
+		if _, ok := finalTxns[txd.Hash]; txd.Selected() && !ok {
+			txd.Reason = TxSkippedRemoved
+		}
+	}
+	g.decisions.add(&TemplateDecisions{
+		Height:    nextBlockHeight,
+		PrevBlock: prevHash,
+		BlockHash: msgBlock.BlockHash(),
+		Generated: time.Now(),
+		Txns:      decisions.txns,
+	})
+
 	blockTemplate := &BlockTemplate{
 		Block:           &msgBlock,
 		Fees:            txFees,
@@ -2163,10 +2285,23 @@ type BgBlkTmplGenerator struct {
 	chain               *blockchain.BlockChain
 	tg                  *BlkTmplGenerator
 	allowUnsyncedMining bool
-	miningAddrs         []dcrutil.Address
 	maxVotesPerBlock    uint16
 	minVotesRequired    uint16
 
+	// These fields determine which address each generated template pays to
+	// and are protected by the mining address mutex since they may be changed
+	// at runtime.
+	//
+	// nextMiningAddr is the index of the address the next template pays to
+	// under the round robin policy.
+	//
+	// prng chooses the address each template pays to under the random policy.
+	miningAddrsMtx   sync.Mutex
+	miningAddrs      []dcrutil.Address
+	miningAddrPolicy MiningAddrPolicy
+	nextMiningAddr   int
+	prng             *rand.Rand
+
 	// These fields deal with providing a stream of template updates to
 	// subscribers.
 	//
@@ -2223,16 +2358,60 @@ type BgBlkTmplGenerator struct {
 	cancelTemplate    func()
 }
 
+// MiningAddrPolicy defines the policy used to choose which of the mining
+// addresses each block template pays to.
+type MiningAddrPolicy uint8
+
+const (
+	// MiningAddrRandom chooses one of the mining addresses at random for each
+	// block template.
+	MiningAddrRandom MiningAddrPolicy = iota
+
+	// MiningAddrRoundRobin cycles through the mining addresses in order so
+	// each block template pays to the address after the one used by the
+	// previous template.
+	MiningAddrRoundRobin
+)
+
+// miningAddrPolicyStrings is a map of mining address policies back to their
+// constant names for pretty printing.
+var miningAddrPolicyStrings = map[MiningAddrPolicy]string{
+	MiningAddrRandom:     "random",
+	MiningAddrRoundRobin: "roundrobin",
+}
+
+// String returns the MiningAddrPolicy in human-readable form.
+func (p MiningAddrPolicy) String() string {
+	if s, ok := miningAddrPolicyStrings[p]; ok {
+		return s
+	}
+	return fmt.Sprintf("Unknown MiningAddrPolicy (%d)", uint8(p))
+}
+
+// ParseMiningAddrPolicy returns the mining address policy with the provided
+// human-readable name.  An error is returned when the name is unknown.
+func ParseMiningAddrPolicy(name string) (MiningAddrPolicy, error) {
+	for policy, policyName := range miningAddrPolicyStrings {
+		if policyName == name {
+			return policy, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown mining address policy %q", name)
+}
+
 // NewBgBlkTmplGenerator initializes a background block template generator with
-// the provided parameters.  The returned instance must be started with the Run
-// method to allowing processing.
-func NewBgBlkTmplGenerator(tg *BlkTmplGenerator, addrs []dcrutil.Address, allowUnsynced bool) *BgBlkTmplGenerator {
+// the provided parameters.  The generated templates pay to the provided mining
+// addresses as chosen by the provided policy.  The returned instance must be
+// started with the Run method to allowing processing.
+func NewBgBlkTmplGenerator(tg *BlkTmplGenerator, addrs []dcrutil.Address, policy MiningAddrPolicy, allowUnsynced bool) *BgBlkTmplGenerator {
 	return &BgBlkTmplGenerator{
 		quit:                make(chan struct{}),
 		chain:               tg.chain,
 		tg:                  tg,
 		allowUnsyncedMining: allowUnsynced,
 		miningAddrs:         addrs,
+		miningAddrPolicy:    policy,
+		prng:                rand.New(rand.NewSource(time.Now().UnixNano())),
 		maxVotesPerBlock:    tg.chainParams.TicketsPerBlock,
 		minVotesRequired:    (tg.chainParams.TicketsPerBlock / 2) + 1,
 		subscriptions:       make(map[*TemplateSubscription]struct{}),
@@ -2255,6 +2434,25 @@ func (g *BgBlkTmplGenerator) UpdateBlockTime(header *wire.BlockHeader) error {
 	return g.tg.UpdateBlockTime(header)
 }
 
+// NewBlockTemplate returns a new block template that pays to the provided
+// address independently of the templates generated in the background.  It
+// returns nil, nil if there are not enough voters on any of the current top
+// blocks to create a new block template.
+//
+// This function is safe for concurrent access.
+func (g *BgBlkTmplGenerator) NewBlockTemplate(payToAddress dcrutil.Address) (*BlockTemplate, error) {
+	return g.tg.NewBlockTemplate(payToAddress)
+}
+
+// TemplateDecisions returns the transaction selection decisions made for up to
+// the provided number of the most recently generated block templates ordered
+// from newest to oldest.
+//
+// This function is safe for concurrent access.
+func (g *BgBlkTmplGenerator) TemplateDecisions(count int) []*TemplateDecisions {
+	return g.tg.TemplateDecisions(count)
+}
+
 // sendQueueRegenEvent sends the provided regen event on the internal queue
 // regen event channel while respecting the quit channel.  The allows orderly
 // shutdown when the generator is shutdown.
@@ -2621,11 +2819,9 @@ func (g *BgBlkTmplGenerator) genTemplateAsync(ctx context.Context, reason Templa
 			defer g.staleTemplateWg.Done()
 		}
 
-		// Pick a mining address at random and generate a block template that
-		// pays to it.
-		prng := rand.New(rand.NewSource(time.Now().Unix()))
-		payToAddr := g.miningAddrs[prng.Intn(len(g.miningAddrs))]
-		template, err := g.tg.NewBlockTemplate(payToAddr)
+		// Pick a mining address according to the policy and generate a block
+		// template that pays to it.
+		template, err := g.tg.NewBlockTemplate(g.payToAddr())
 		// NOTE: err is handled below.
 
 		// Don't update the state or notify subscribers when the template
@@ -3365,6 +3561,82 @@ func (g *BgBlkTmplGenerator) VoteReceived(tx *dcrutil.Tx) {
 	g.sendQueueRegenEvent(regenEvent{rtVote, tx})
 }
 
+// CoinbaseExtraData returns the extra data that is appended to the coinbase
+// script sig of generated block templates.
+//
+// This function is safe for concurrent access.
+func (g *BgBlkTmplGenerator) CoinbaseExtraData() []byte {
+	return g.tg.CoinbaseExtraData()
+}
+
+// SetCoinbaseExtraData sets the extra data that is appended to the coinbase
+// script sig of generated block templates and requests a new template so it
+// takes effect promptly.  An error is returned when the data exceeds
+// MaxCoinbaseExtraDataLen.
+//
+// This function is safe for concurrent access.
+func (g *BgBlkTmplGenerator) SetCoinbaseExtraData(extraData []byte) error {
+	if err := g.tg.SetCoinbaseExtraData(extraData); err != nil {
+		return err
+	}
+	g.ForceRegen()
+	return nil
+}
+
+// payToAddr returns the mining address the next generated template pays to
+// according to the mining address policy.
+//
+// This function is safe for concurrent access.
+func (g *BgBlkTmplGenerator) payToAddr() dcrutil.Address {
+	g.miningAddrsMtx.Lock()
+	defer g.miningAddrsMtx.Unlock()
+
+	if g.miningAddrPolicy == MiningAddrRoundRobin {
+		addr := g.miningAddrs[g.nextMiningAddr%len(g.miningAddrs)]
+		g.nextMiningAddr = (g.nextMiningAddr + 1) % len(g.miningAddrs)
+		return addr
+	}
+	return g.miningAddrs[g.prng.Intn(len(g.miningAddrs))]
+}
+
+// MiningAddrs returns the mining addresses generated templates pay to along
+// with the policy used to choose between them.
+//
+// This function is safe for concurrent access.
+func (g *BgBlkTmplGenerator) MiningAddrs() ([]dcrutil.Address, MiningAddrPolicy) {
+	g.miningAddrsMtx.Lock()
+	addrs := make([]dcrutil.Address, len(g.miningAddrs))
+	copy(addrs, g.miningAddrs)
+	policy := g.miningAddrPolicy
+	g.miningAddrsMtx.Unlock()
+	return addrs, policy
+}
+
+// SetMiningAddrs sets the mining addresses generated templates pay to along
+// with the policy used to choose between them and requests a new template so
+// the change takes effect promptly.  An error is returned when no addresses
+// are provided.
+//
+// This function is safe for concurrent access.
+func (g *BgBlkTmplGenerator) SetMiningAddrs(addrs []dcrutil.Address, policy MiningAddrPolicy) error {
+	if len(addrs) == 0 {
+		return errors.New("at least one mining address is required")
+	}
+	if _, ok := miningAddrPolicyStrings[policy]; !ok {
+		return fmt.Errorf("unknown mining address policy %d", uint8(policy))
+	}
+
+	addrsCopy := make([]dcrutil.Address, len(addrs))
+	copy(addrsCopy, addrs)
+	g.miningAddrsMtx.Lock()
+	g.miningAddrs = addrsCopy
+	g.miningAddrPolicy = policy
+	g.nextMiningAddr = 0
+	g.miningAddrsMtx.Unlock()
+	g.ForceRegen()
+	return nil
+}
+
 // ForceRegen asks the background block template generator to generate a new
 // template, independently of most of its internal timers.
 //
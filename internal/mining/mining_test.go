@@ -6,11 +6,14 @@
 package mining
 
 import (
+	"bytes"
 	"container/heap"
 	"math/rand"
 	"testing"
 
 	"github.com/decred/dcrd/blockchain/stake/v3"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/dcrutil/v3"
 )
 
 // TestStakeTxFeePrioHeap tests the priority heaps including the stake types for
@@ -126,3 +129,128 @@ func TestStakeTxFeePrioHeap(t *testing.T) {
 		}
 	}
 }
+
+// TestCoinbaseExtraData ensures the coinbase extra data of the block template
+// generator can be changed and is limited in length.
+func TestCoinbaseExtraData(t *testing.T) {
+	g := NewBlkTmplGenerator(nil, nil, nil, nil, nil, nil, nil, nil, 0)
+
+	// Ensure the default extra data is used for the coinbase script.
+	wantScript := append([]byte{0x00, 0x00}, coinbaseFlags...)
+	if script := g.coinbaseScript(); !bytes.Equal(script, wantScript) {
+		t.Fatalf("unexpected default coinbase script -- got %x, want %x",
+			script, wantScript)
+	}
+
+	tests := []struct {
+		name      string
+		extraData []byte
+		wantErr   bool
+	}{{
+		name:      "pool tag",
+		extraData: []byte("/pool/"),
+	}, {
+		name:      "empty",
+		extraData: nil,
+	}, {
+		name:      "max length",
+		extraData: bytes.Repeat([]byte{0x01}, MaxCoinbaseExtraDataLen),
+	}, {
+		name:      "too long",
+		extraData: bytes.Repeat([]byte{0x01}, MaxCoinbaseExtraDataLen+1),
+		wantErr:   true,
+	}}
+	for _, test := range tests {
+		prevExtraData := g.CoinbaseExtraData()
+		err := g.SetCoinbaseExtraData(test.extraData)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: unexpected error -- got %v, want error %v",
+				test.name, err, test.wantErr)
+			continue
+		}
+
+		// Ensure the extra data is unchanged on error and updated otherwise.
+		wantExtraData := test.extraData
+		if test.wantErr {
+			wantExtraData = prevExtraData
+		}
+		if got := g.CoinbaseExtraData(); !bytes.Equal(got, wantExtraData) {
+			t.Errorf("%s: unexpected extra data -- got %x, want %x",
+				test.name, got, wantExtraData)
+			continue
+		}
+		wantScript := append([]byte{0x00, 0x00}, wantExtraData...)
+		if script := g.coinbaseScript(); !bytes.Equal(script, wantScript) {
+			t.Errorf("%s: unexpected coinbase script -- got %x, want %x",
+				test.name, script, wantScript)
+		}
+	}
+}
+
+// TestMiningAddrPolicy ensures the background block template generator chooses
+// the mining addresses according to the policy and that the addresses and
+// policy can be changed.
+func TestMiningAddrPolicy(t *testing.T) {
+	params := chaincfg.RegNetParams()
+	var addrs []dcrutil.Address
+	for i := byte(0); i < 3; i++ {
+		addr, err := dcrutil.NewAddressScriptHashFromHash(
+			bytes.Repeat([]byte{i}, 20), params)
+		if err != nil {
+			t.Fatalf("unexpected error creating address: %v", err)
+		}
+		addrs = append(addrs, addr)
+	}
+	tg := NewBlkTmplGenerator(nil, nil, nil, nil, nil, params, nil, nil, 0)
+	g := NewBgBlkTmplGenerator(tg, addrs, MiningAddrRoundRobin, false)
+
+	// Prevent forced template regeneration from blocking since the generator
+	// is not running.
+	close(g.quit)
+
+	// Ensure the round robin policy cycles through the addresses in order.
+	for i := 0; i < 2*len(addrs); i++ {
+		want := addrs[i%len(addrs)]
+		if got := g.payToAddr(); got != want {
+			t.Fatalf("unexpected address for template %d -- got %v, want %v",
+				i, got, want)
+		}
+	}
+
+	// Ensure the random policy only chooses configured addresses.
+	if err := g.SetMiningAddrs(addrs[1:], MiningAddrRandom); err != nil {
+		t.Fatalf("unexpected error setting addresses: %v", err)
+	}
+	gotAddrs, gotPolicy := g.MiningAddrs()
+	if len(gotAddrs) != 2 || gotPolicy != MiningAddrRandom {
+		t.Fatalf("unexpected addresses %v with policy %v", gotAddrs, gotPolicy)
+	}
+	for i := 0; i < 20; i++ {
+		if got := g.payToAddr(); got != addrs[1] && got != addrs[2] {
+			t.Fatalf("unexpected address %v", got)
+		}
+	}
+
+	// Ensure invalid changes are rejected without modifying the addresses.
+	if err := g.SetMiningAddrs(nil, MiningAddrRandom); err == nil {
+		t.Fatal("expected error setting no addresses")
+	}
+	if err := g.SetMiningAddrs(addrs, MiningAddrPolicy(255)); err == nil {
+		t.Fatal("expected error setting unknown policy")
+	}
+	if gotAddrs, _ := g.MiningAddrs(); len(gotAddrs) != 2 {
+		t.Fatalf("unexpected addresses after invalid changes: %v", gotAddrs)
+	}
+
+	// Ensure the policies round trip through their names.
+	for _, policy := range []MiningAddrPolicy{MiningAddrRandom, MiningAddrRoundRobin} {
+		got, err := ParseMiningAddrPolicy(policy.String())
+		if err != nil || got != policy {
+			t.Fatalf("unexpected parsed policy %v (err %v), want %v", got,
+				err, policy)
+		}
+	}
+	if _, err := ParseMiningAddrPolicy("sequential"); err == nil {
+		t.Fatal("expected error parsing unknown policy")
+	}
+}
@@ -0,0 +1,381 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mining
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/blockchain/stake/v3"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+const (
+	// MaxTemplateDecisions is the maximum number of the most recently
+	// generated block templates the transaction selection decisions are
+	// retained for.
+	MaxTemplateDecisions = 100
+
+	// templateDecisionsHeaderSize is the number of bytes the serialized
+	// header of the transaction selection decisions for a template consumes.
+	// It consists of the template height (4 bytes) + the previous block hash
+	// (32 bytes) + the template block hash (32 bytes) + the unix time the
+	// template was generated (8 bytes) + the number of transactions (4
+	// bytes).
+	templateDecisionsHeaderSize = 4 + chainhash.HashSize*2 + 8 + 4
+
+	// txDecisionSize is the number of bytes a serialized transaction
+	// selection decision consumes.  It consists of the transaction hash (32
+	// bytes) + the transaction type (1 byte) + the decision reason (1 byte)
+	// + the serialized size of the transaction (4 bytes) + the fee it pays
+	// (8 bytes).
+	txDecisionSize = chainhash.HashSize + 1 + 1 + 4 + 8
+)
+
+// TxDecisionReason identifies why a transaction that was considered for
+// inclusion in a block template was either selected or skipped.
+type TxDecisionReason uint8
+
+// These constants define the reasons a transaction considered for inclusion
+// in a block template was selected or skipped.
+const (
+	// TxSelected indicates the transaction was included in the template.
+	TxSelected TxDecisionReason = iota
+
+	// TxSkippedCoinbase indicates the transaction was skipped because it is
+	// a coinbase.
+	TxSkippedCoinbase
+
+	// TxSkippedNonFinal indicates the transaction was skipped because it is
+	// not finalized as of the template height.
+	TxSkippedNonFinal
+
+	// TxSkippedWrongVoteBlock indicates the vote was skipped because it does
+	// not vote on the block the template builds on.
+	TxSkippedWrongVoteBlock
+
+	// TxSkippedInputsUnavailable indicates the transaction was skipped
+	// because the outputs it spends could not be fetched or are not
+	// available.
+	TxSkippedInputsUnavailable
+
+	// TxSkippedDependency indicates the transaction was skipped because a
+	// transaction it depends on was not included in the template.
+	TxSkippedDependency
+
+	// TxSkippedMaxFreshStake indicates the ticket was skipped because the
+	// template already contains the maximum number of tickets allowed.
+	TxSkippedMaxFreshStake
+
+	// TxSkippedTicketPrice indicates the ticket was skipped because it
+	// commits less than the required stake difficulty.
+	TxSkippedTicketPrice
+
+	// TxSkippedUnknownRevocation indicates the revocation was skipped
+	// because it revokes a ticket that is not known to have been missed.
+	TxSkippedUnknownRevocation
+
+	// TxSkippedBlockSize indicates the transaction was skipped because it
+	// would exceed the maximum block size.
+	TxSkippedBlockSize
+
+	// TxSkippedSigOps indicates the transaction was skipped because it would
+	// exceed the maximum number of signature operations per block.
+	TxSkippedSigOps
+
+	// TxSkippedIneligibleVote indicates the vote was skipped because its
+	// ticket is not eligible to vote on the next block or another vote for
+	// the same ticket was already included.
+	TxSkippedIneligibleVote
+
+	// TxSkippedLowFee indicates the transaction was skipped because its fee
+	// rate is below the minimum once the block exceeds the minimum block
+	// size.
+	TxSkippedLowFee
+
+	// TxSkippedInvalid indicates the transaction was skipped because it
+	// failed validation against the template.
+	TxSkippedInvalid
+
+	// TxSkippedRemoved indicates the transaction was initially selected, but
+	// later removed from the template, such as when the regular transaction
+	// tree of the parent block is disapproved.
+	TxSkippedRemoved
+
+	// numTxDecisionReasons is the number of defined decision reasons.
+	numTxDecisionReasons
+)
+
+// txDecisionReasonStrings is a map of transaction decision reasons back to
+// their constant names for pretty printing.
+var txDecisionReasonStrings = map[TxDecisionReason]string{
+	TxSelected:                 "selected",
+	TxSkippedCoinbase:          "coinbase",
+	TxSkippedNonFinal:          "nonfinal",
+	TxSkippedWrongVoteBlock:    "wrongvoteblock",
+	TxSkippedInputsUnavailable: "inputsunavailable",
+	TxSkippedDependency:        "dependency",
+	TxSkippedMaxFreshStake:     "maxfreshstake",
+	TxSkippedTicketPrice:       "ticketprice",
+	TxSkippedUnknownRevocation: "unknownrevocation",
+	TxSkippedBlockSize:         "blocksize",
+	TxSkippedSigOps:            "sigops",
+	TxSkippedIneligibleVote:    "ineligiblevote",
+	TxSkippedLowFee:            "lowfee",
+	TxSkippedInvalid:           "invalid",
+	TxSkippedRemoved:           "removed",
+}
+
+// String returns the TxDecisionReason in human-readable form.
+func (r TxDecisionReason) String() string {
+	if s, ok := txDecisionReasonStrings[r]; ok {
+		return s
+	}
+	return fmt.Sprintf("Unknown TxDecisionReason (%d)", uint8(r))
+}
+
+// TxDecision describes whether a transaction that was considered for
+// inclusion in a block template was selected along with the details that
+// factored into the decision.
+type TxDecision struct {
+	// Hash is the hash of the transaction.
+	Hash chainhash.Hash
+
+	// Type is the stake type of the transaction.
+	Type stake.TxType
+
+	// Reason is why the transaction was selected or skipped.
+	Reason TxDecisionReason
+
+	// Size is the serialized size of the transaction.
+	Size uint32
+
+	// Fee is the total fee the transaction pays.
+	Fee int64
+}
+
+// Selected returns whether or not the transaction was included in the
+// template.
+func (d *TxDecision) Selected() bool {
+	return d.Reason == TxSelected
+}
+
+// FeePerKB returns the fee rate of the transaction in atoms per kilobyte.
+func (d *TxDecision) FeePerKB() float64 {
+	if d.Size == 0 {
+		return 0
+	}
+	return float64(d.Fee) * float64(kilobyte) / float64(d.Size)
+}
+
+// TemplateDecisions houses the transaction selection decisions made while
+// generating a block template.
+type TemplateDecisions struct {
+	// Height is the height of the block the template is for.
+	Height int64
+
+	// PrevBlock is the hash of the block the template builds on.
+	PrevBlock chainhash.Hash
+
+	// BlockHash is the hash of the template block at the time it was
+	// generated.
+	BlockHash chainhash.Hash
+
+	// Generated is the time the template was generated.
+	Generated time.Time
+
+	// Txns are the decisions for every transaction considered for inclusion
+	// in the template in the order they were provided by the transaction
+	// source.
+	Txns []TxDecision
+}
+
+// serializeTemplateDecisions returns the compact binary serialization of the
+// provided template decisions.
+func serializeTemplateDecisions(d *TemplateDecisions) []byte {
+	serialized := make([]byte, templateDecisionsHeaderSize+
+		len(d.Txns)*txDecisionSize)
+	binary.LittleEndian.PutUint32(serialized[0:4], uint32(d.Height))
+	offset := 4
+	copy(serialized[offset:], d.PrevBlock[:])
+	offset += chainhash.HashSize
+	copy(serialized[offset:], d.BlockHash[:])
+	offset += chainhash.HashSize
+	binary.LittleEndian.PutUint64(serialized[offset:],
+		uint64(d.Generated.Unix()))
+	offset += 8
+	binary.LittleEndian.PutUint32(serialized[offset:], uint32(len(d.Txns)))
+	offset += 4
+	for i := range d.Txns {
+		txd := &d.Txns[i]
+		copy(serialized[offset:], txd.Hash[:])
+		offset += chainhash.HashSize
+		serialized[offset] = byte(txd.Type)
+		serialized[offset+1] = byte(txd.Reason)
+		offset += 2
+		binary.LittleEndian.PutUint32(serialized[offset:], txd.Size)
+		offset += 4
+		binary.LittleEndian.PutUint64(serialized[offset:], uint64(txd.Fee))
+		offset += 8
+	}
+	return serialized
+}
+
+// errDeserializeTemplateDecisions is returned when serialized template
+// decisions are malformed.
+var errDeserializeTemplateDecisions = errors.New("malformed serialized " +
+	"template decisions")
+
+// deserializeTemplateDecisions decodes the provided compact binary
+// serialization of template decisions.
+func deserializeTemplateDecisions(serialized []byte) (*TemplateDecisions, error) {
+	if len(serialized) < templateDecisionsHeaderSize {
+		return nil, errDeserializeTemplateDecisions
+	}
+
+	var d TemplateDecisions
+	d.Height = int64(binary.LittleEndian.Uint32(serialized[0:4]))
+	offset := 4
+	copy(d.PrevBlock[:], serialized[offset:])
+	offset += chainhash.HashSize
+	copy(d.BlockHash[:], serialized[offset:])
+	offset += chainhash.HashSize
+	d.Generated = time.Unix(int64(binary.LittleEndian.Uint64(
+		serialized[offset:])), 0)
+	offset += 8
+	numTxns := binary.LittleEndian.Uint32(serialized[offset:])
+	offset += 4
+	if uint64(len(serialized)-offset) != uint64(numTxns)*txDecisionSize {
+		return nil, errDeserializeTemplateDecisions
+	}
+
+	d.Txns = make([]TxDecision, numTxns)
+	for i := range d.Txns {
+		txd := &d.Txns[i]
+		copy(txd.Hash[:], serialized[offset:])
+		offset += chainhash.HashSize
+		txd.Type = stake.TxType(serialized[offset])
+		txd.Reason = TxDecisionReason(serialized[offset+1])
+		if txd.Reason >= numTxDecisionReasons {
+			return nil, errDeserializeTemplateDecisions
+		}
+		offset += 2
+		txd.Size = binary.LittleEndian.Uint32(serialized[offset:])
+		offset += 4
+		txd.Fee = int64(binary.LittleEndian.Uint64(serialized[offset:]))
+		offset += 8
+	}
+	return &d, nil
+}
+
+// txDecisionSet tracks the decisions made for the transactions considered
+// while generating a single block template.
+type txDecisionSet struct {
+	txns  []TxDecision
+	index map[chainhash.Hash]int
+}
+
+// newTxDecisionSet returns an empty transaction decision set with space for
+// the provided number of transactions.
+func newTxDecisionSet(sizeHint int) *txDecisionSet {
+	return &txDecisionSet{
+		txns:  make([]TxDecision, 0, sizeHint),
+		index: make(map[chainhash.Hash]int, sizeHint),
+	}
+}
+
+// consider adds the provided transaction to the set.  Transactions are
+// initially marked as skipped due to a dependency since the only way a
+// considered transaction never has a decision made for it is when a
+// transaction it depends on is never included.
+func (s *txDecisionSet) consider(txDesc *TxDesc) {
+	hash := txDesc.Tx.Hash()
+	s.index[*hash] = len(s.txns)
+	s.txns = append(s.txns, TxDecision{
+		Hash:   *hash,
+		Type:   txDesc.Type,
+		Reason: TxSkippedDependency,
+		Size:   uint32(txDesc.Tx.MsgTx().SerializeSize()),
+		Fee:    txDesc.Fee,
+	})
+}
+
+// decide records the provided reason for the transaction with the provided
+// hash.  Transactions that were not considered are ignored.
+func (s *txDecisionSet) decide(hash *chainhash.Hash, reason TxDecisionReason) {
+	if i, ok := s.index[*hash]; ok {
+		s.txns[i].Reason = reason
+	}
+}
+
+// templateDecisionLog houses the serialized transaction selection decisions
+// for a bounded number of the most recently generated block templates.
+//
+// The decisions are stored in their compact binary form since a template may
+// consider a large number of transactions and the log is kept for many
+// templates.
+type templateDecisionLog struct {
+	mtx     sync.Mutex
+	entries [][]byte
+	next    int
+}
+
+// newTemplateDecisionLog returns a template decision log that retains the
+// decisions for up to the provided number of templates.
+func newTemplateDecisionLog(maxEntries int) *templateDecisionLog {
+	return &templateDecisionLog{
+		entries: make([][]byte, 0, maxEntries),
+	}
+}
+
+// add records the provided template decisions, evicting the oldest entry when
+// the log is full.
+//
+// This function is safe for concurrent access.
+func (l *templateDecisionLog) add(d *TemplateDecisions) {
+	serialized := serializeTemplateDecisions(d)
+
+	l.mtx.Lock()
+	if len(l.entries) < cap(l.entries) {
+		l.entries = append(l.entries, serialized)
+	} else {
+		l.entries[l.next] = serialized
+	}
+	l.next = (l.next + 1) % cap(l.entries)
+	l.mtx.Unlock()
+}
+
+// latest returns up to the provided number of the most recently recorded
+// template decisions ordered from newest to oldest.
+//
+// This function is safe for concurrent access.
+func (l *templateDecisionLog) latest(count int) []*TemplateDecisions {
+	l.mtx.Lock()
+	if count > len(l.entries) {
+		count = len(l.entries)
+	}
+	entries := make([][]byte, 0, count)
+	for i := 1; i <= count; i++ {
+		idx := (l.next - i + cap(l.entries)) % cap(l.entries)
+		entries = append(entries, l.entries[idx])
+	}
+	l.mtx.Unlock()
+
+	decisions := make([]*TemplateDecisions, 0, len(entries))
+	for _, serialized := range entries {
+		d, err := deserializeTemplateDecisions(serialized)
+		if err != nil {
+			// This should never happen since the log only contains
+			// entries it serialized itself, but be paranoid.
+			log.Errorf("Unable to decode template decisions: %v", err)
+			continue
+		}
+		decisions = append(decisions, d)
+	}
+	return decisions
+}
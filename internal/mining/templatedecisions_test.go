@@ -0,0 +1,96 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mining
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/blockchain/stake/v3"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+// TestTemplateDecisionLog ensures template decisions survive the round trip
+// through their binary serialization and that the log only retains the most
+// recent templates.
+func TestTemplateDecisionLog(t *testing.T) {
+	// makeDecisions returns template decisions for the provided height with
+	// a selected and a skipped transaction.
+	makeDecisions := func(height int64) *TemplateDecisions {
+		return &TemplateDecisions{
+			Height:    height,
+			PrevBlock: chainhash.Hash{byte(height - 1)},
+			BlockHash: chainhash.Hash{byte(height)},
+			Generated: time.Unix(1600000000+height, 0),
+			Txns: []TxDecision{{
+				Hash:   chainhash.Hash{0x01, byte(height)},
+				Type:   stake.TxTypeRegular,
+				Reason: TxSelected,
+				Size:   250,
+				Fee:    2500,
+			}, {
+				Hash:   chainhash.Hash{0x02, byte(height)},
+				Type:   stake.TxTypeSStx,
+				Reason: TxSkippedMaxFreshStake,
+				Size:   300,
+				Fee:    3000,
+			}},
+		}
+	}
+
+	// Ensure malformed serializations are rejected.
+	serialized := serializeTemplateDecisions(makeDecisions(1))
+	if _, err := deserializeTemplateDecisions(serialized[:len(serialized)-1]); err == nil {
+		t.Fatal("deserialized truncated template decisions without error")
+	}
+	serialized[templateDecisionsHeaderSize+chainhash.HashSize+1] = byte(numTxDecisionReasons)
+	if _, err := deserializeTemplateDecisions(serialized); err == nil {
+		t.Fatal("deserialized unknown decision reason without error")
+	}
+
+	const maxEntries = 3
+	l := newTemplateDecisionLog(maxEntries)
+	if got := l.latest(maxEntries); len(got) != 0 {
+		t.Fatalf("unexpected decisions in empty log: %d", len(got))
+	}
+
+	// Add more templates than the log retains and ensure only the newest are
+	// returned in order from newest to oldest.
+	for height := int64(1); height <= maxEntries+2; height++ {
+		l.add(makeDecisions(height))
+	}
+	tests := []struct {
+		count       int
+		wantHeights []int64
+	}{
+		{count: 1, wantHeights: []int64{5}},
+		{count: 2, wantHeights: []int64{5, 4}},
+		{count: maxEntries + 10, wantHeights: []int64{5, 4, 3}},
+	}
+	for _, test := range tests {
+		got := l.latest(test.count)
+		if len(got) != len(test.wantHeights) {
+			t.Fatalf("count %d: unexpected number of templates -- got %d, "+
+				"want %d", test.count, len(got), len(test.wantHeights))
+		}
+		for i, d := range got {
+			want := makeDecisions(test.wantHeights[i])
+			if !reflect.DeepEqual(d, want) {
+				t.Fatalf("count %d: mismatched decisions -- got %+v, want "+
+					"%+v", test.count, d, want)
+			}
+		}
+	}
+
+	// Ensure the derived fields of the decisions are correct.
+	txd := makeDecisions(1).Txns[0]
+	if !txd.Selected() {
+		t.Fatal("selected transaction decision is not reported as selected")
+	}
+	if got := txd.FeePerKB(); got != 10000 {
+		t.Fatalf("unexpected fee rate -- got %v, want 10000", got)
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/decred/dcrd/rpc/jsonrpc/types/v2"
+)
+
+const (
+	// maxGetWorkClients is the maximum number of clients getwork statistics
+	// are tracked for.  The least recently active client is discarded to
+	// make room for new clients once it is reached.
+	maxGetWorkClients = 100
+
+	// getWorkRateInterval is the duration of time covered by each bucket
+	// used to calculate the getwork request rate of a client.
+	getWorkRateInterval = time.Minute
+
+	// numGetWorkRateIntervals is the number of intervals the getwork request
+	// rate of a client is calculated over.
+	numGetWorkRateIntervals = 10
+)
+
+// These constants define the possible outcomes of a getwork submission.
+const (
+	workAccepted = iota
+	workRejected
+	workStale
+)
+
+// clientAddrKey is the type of the context key used to store the address of
+// the client that issued an RPC.
+type clientAddrKey struct{}
+
+// withClientAddr returns a copy of the provided context that records the
+// provided address as the address of the client that issued the RPC.
+func withClientAddr(ctx context.Context, addr string) context.Context {
+	return context.WithValue(ctx, clientAddrKey{}, addr)
+}
+
+// clientAddr returns the address of the client that issued the RPC associated
+// with the provided context.  An empty string is returned when it is unknown.
+func clientAddr(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	addr, _ := ctx.Value(clientAddrKey{}).(string)
+	return addr
+}
+
+// getWorkRateBucket houses the number of getwork requests a client made
+// during a single interval of time.
+type getWorkRateBucket struct {
+	interval int64
+	count    uint64
+}
+
+// getWorkClient houses the getwork statistics for a single client.
+type getWorkClient struct {
+	addr          string
+	firstSeen     time.Time
+	lastActive    time.Time
+	numRequests   uint64
+	lastRequest   time.Time
+	rateBuckets   [numGetWorkRateIntervals]getWorkRateBucket
+	numSubmitted  uint64
+	numAccepted   uint64
+	numRejected   uint64
+	numStale      uint64
+	lastSubmitted time.Time
+	lastAccepted  time.Time
+}
+
+// rateInterval returns the getwork request rate interval that includes the
+// provided time.
+func rateInterval(t time.Time) int64 {
+	return t.Unix() / int64(getWorkRateInterval/time.Second)
+}
+
+// requested records that the client requested work.
+func (c *getWorkClient) requested(now time.Time) {
+	c.numRequests++
+	c.lastRequest = now
+
+	interval := rateInterval(now)
+	idx := ((interval % numGetWorkRateIntervals) + numGetWorkRateIntervals) %
+		numGetWorkRateIntervals
+	b := &c.rateBuckets[idx]
+	if b.interval != interval {
+		*b = getWorkRateBucket{interval: interval}
+	}
+	b.count++
+}
+
+// submitted records that the client submitted a solution with the provided
+// outcome.
+func (c *getWorkClient) submitted(now time.Time, outcome int) {
+	c.numSubmitted++
+	c.lastSubmitted = now
+	switch outcome {
+	case workAccepted:
+		c.numAccepted++
+		c.lastAccepted = now
+	case workStale:
+		c.numStale++
+	default:
+		c.numRejected++
+	}
+}
+
+// requestRate returns the average number of getwork requests per minute the
+// client made over the most recent intervals.
+func (c *getWorkClient) requestRate(now time.Time) float64 {
+	end := rateInterval(now)
+	var numRequests uint64
+	for i := range c.rateBuckets {
+		b := &c.rateBuckets[i]
+		if b.interval > end-numGetWorkRateIntervals && b.interval <= end {
+			numRequests += b.count
+		}
+	}
+	window := numGetWorkRateIntervals * getWorkRateInterval
+	return float64(numRequests) / window.Minutes()
+}
+
+// getWorkStats tracks the getwork requests and solution submissions of the
+// clients that use getwork.
+//
+// It is not safe for concurrent access and is protected by the RPC work state
+// lock.
+type getWorkStats struct {
+	clients map[string]*getWorkClient
+}
+
+// client returns the statistics for the client with the provided address while
+// creating them when needed.  The least recently active client is discarded
+// when the maximum number of clients is reached.
+func (s *getWorkStats) client(now time.Time, addr string) *getWorkClient {
+	if c, ok := s.clients[addr]; ok {
+		c.lastActive = now
+		return c
+	}
+
+	if s.clients == nil {
+		s.clients = make(map[string]*getWorkClient)
+	}
+	if len(s.clients) >= maxGetWorkClients {
+		var oldest *getWorkClient
+		for _, c := range s.clients {
+			if oldest == nil || c.lastActive.Before(oldest.lastActive) {
+				oldest = c
+			}
+		}
+		delete(s.clients, oldest.addr)
+	}
+	c := &getWorkClient{addr: addr, firstSeen: now, lastActive: now}
+	s.clients[addr] = c
+	return c
+}
+
+// requested records that the client with the provided address requested work.
+func (s *getWorkStats) requested(now time.Time, addr string) {
+	s.client(now, addr).requested(now)
+}
+
+// submitted records that the client with the provided address submitted a
+// solution with the provided outcome.
+func (s *getWorkStats) submitted(now time.Time, addr string, outcome int) {
+	s.client(now, addr).submitted(now, outcome)
+}
+
+// unixTime returns the provided time as a unix timestamp or zero when it is not
+// set.
+func unixTime(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+// results returns the statistics for all tracked clients sorted by their
+// address.
+func (s *getWorkStats) results(now time.Time) []types.GetWorkClientResult {
+	results := make([]types.GetWorkClientResult, 0, len(s.clients))
+	for _, c := range s.clients {
+		results = append(results, types.GetWorkClientResult{
+			Addr:          c.addr,
+			FirstSeen:     unixTime(c.firstSeen),
+			Requests:      c.numRequests,
+			RequestRate:   c.requestRate(now),
+			LastRequest:   unixTime(c.lastRequest),
+			Submitted:     c.numSubmitted,
+			Accepted:      c.numAccepted,
+			Rejected:      c.numRejected,
+			Stale:         c.numStale,
+			LastSubmitted: unixTime(c.lastSubmitted),
+			LastAccepted:  unixTime(c.lastAccepted),
+		})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Addr < results[j].Addr
+	})
+	return results
+}
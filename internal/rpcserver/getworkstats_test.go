@@ -0,0 +1,69 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// TestGetWorkStats ensures the getwork request rate of a client only accounts
+// for the requests made during the most recent intervals and that the least
+// recently active client is discarded once the maximum number of clients is
+// reached.
+func TestGetWorkStats(t *testing.T) {
+	t.Parallel()
+
+	var stats getWorkStats
+	now := time.Unix(1592931300, 0)
+
+	// Ensure the request rate only includes the requests within the window.
+	for i := 0; i < 30; i++ {
+		stats.requested(now.Add(time.Duration(i)*time.Minute), "client")
+	}
+	client := stats.clients["client"]
+	end := now.Add(29 * time.Minute)
+	if got, want := client.requestRate(end), 1.0; got != want {
+		t.Fatalf("unexpected request rate -- got %v, want %v", got, want)
+	}
+	if got, want := client.requestRate(end.Add(5*time.Minute)), 0.5; got != want {
+		t.Fatalf("unexpected request rate -- got %v, want %v", got, want)
+	}
+	if got := client.requestRate(end.Add(time.Hour)); got != 0 {
+		t.Fatalf("unexpected request rate -- got %v, want 0", got)
+	}
+
+	// Ensure the least recently active client is discarded once the maximum
+	// number of clients is reached.
+	stats.submitted(end.Add(time.Minute), "client", workRejected)
+	for i := 0; i < maxGetWorkClients; i++ {
+		addr := fmt.Sprintf("127.0.0.1:%d", 50000+i)
+		stats.requested(now.Add(time.Duration(i)*time.Second), addr)
+	}
+	if len(stats.clients) != maxGetWorkClients {
+		t.Fatalf("unexpected number of clients -- got %d, want %d",
+			len(stats.clients), maxGetWorkClients)
+	}
+	if _, ok := stats.clients["127.0.0.1:50000"]; ok {
+		t.Fatal("least recently active client was not discarded")
+	}
+	if c, ok := stats.clients["client"]; !ok || c.numRejected != 1 {
+		t.Fatal("recently active client was discarded")
+	}
+
+	// Ensure the client address is only available when it was recorded in
+	// the context.
+	ctx := context.Background()
+	if addr := clientAddr(ctx); addr != "" {
+		t.Fatalf("unexpected client address %q", addr)
+	}
+	if addr := clientAddr(withClientAddr(ctx, "127.0.0.1:50000")); addr !=
+		"127.0.0.1:50000" {
+
+		t.Fatalf("unexpected client address %q", addr)
+	}
+}
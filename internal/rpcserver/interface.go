@@ -12,6 +12,7 @@ import (
 
 	"github.com/decred/dcrd/addrmgr"
 	"github.com/decred/dcrd/blockchain/stake/v3"
+	"github.com/decred/dcrd/blockchain/standalone/v2"
 	"github.com/decred/dcrd/blockchain/v3"
 	"github.com/decred/dcrd/blockchain/v3/indexers"
 	"github.com/decred/dcrd/chaincfg/chainhash"
@@ -20,6 +21,7 @@ import (
 	"github.com/decred/dcrd/gcs/v2"
 	"github.com/decred/dcrd/internal/mempool"
 	"github.com/decred/dcrd/internal/mining"
+	"github.com/decred/dcrd/internal/txrecon"
 	"github.com/decred/dcrd/peer/v2"
 	"github.com/decred/dcrd/wire"
 )
@@ -57,6 +59,31 @@ type Peer interface {
 	// BanScore returns the current integer value that represents how close
 	// the peer is to being banned.
 	BanScore() uint32
+
+	// TCPInfo returns the transport statistics most recently sampled from
+	// the TCP socket of the peer connection or nil when none are available.
+	TCPInfo() *PeerTCPInfo
+}
+
+// PeerTCPInfo houses transport statistics sampled from the TCP socket of a peer
+// connection.
+type PeerTCPInfo struct {
+	// RTT and RTTVar are the smoothed round trip time of the connection
+	// and its variance.
+	RTT    time.Duration
+	RTTVar time.Duration
+
+	// Retransmits is the number of retransmissions of the currently
+	// unacknowledged data and TotalRetrans is the total number of
+	// retransmitted segments over the lifetime of the connection.
+	Retransmits  uint32
+	TotalRetrans uint32
+
+	// SndCwnd is the size of the send congestion window in segments.
+	SndCwnd uint32
+
+	// SampleTime is the time the statistics were sampled.
+	SampleTime time.Time
 }
 
 // AddrManager represents an address manager for use with the RPC server.
@@ -108,9 +135,18 @@ type ConnManager interface {
 	// network for all peers.
 	NetTotals() (uint64, uint64)
 
+	// TxReconStats returns statistics about the transaction inventory
+	// reconciliation rounds performed with all peers.  The second return
+	// value is false when reconciliation is disabled.
+	TxReconStats() (txrecon.Stats, bool)
+
 	// ConnectedPeers returns an array consisting of all connected peers.
 	ConnectedPeers() []Peer
 
+	// BannedPeers returns the hosts that are currently banned along with
+	// when their bans expire.
+	BannedPeers() map[string]time.Time
+
 	// PersistentPeers returns an array consisting of all the persistent
 	// peers.
 	PersistentPeers() []Peer
@@ -165,9 +201,105 @@ type SyncManager interface {
 	SyncHeight() int64
 
 	// ProcessTransaction relays the provided transaction validation and
-	// insertion into the memory pool.
+	// insertion into the memory pool.  The feeExempt flag exempts the
+	// transaction from the minimum relay fee policy.
 	ProcessTransaction(tx *dcrutil.Tx, allowOrphans bool, rateLimit bool,
-		allowHighFees bool, tag mempool.Tag) ([]*dcrutil.Tx, error)
+		allowHighFees bool, feeExempt bool,
+		tag mempool.Tag) ([]*dcrutil.Tx, error)
+
+	// TxRelayStats returns statistics about the latency of the transactions
+	// processed via the priority lane used by votes and revocations and the
+	// regular lane used by all other transactions.
+	TxRelayStats() (priority, regular TxRelayLaneStats)
+
+	// RejectedTransactions returns the transactions most recently rejected
+	// from peers along with the reasons they were rejected ordered from
+	// newest to oldest.
+	RejectedTransactions() []RejectedTx
+
+	// SyncPeerInfo returns details about the current sync peer along with
+	// the most recent changes to it.
+	SyncPeerInfo() *SyncPeerInfo
+}
+
+// SyncPeerSwitch describes a change to the sync peer.
+type SyncPeerSwitch struct {
+	// Time is when the change was made.
+	Time time.Time
+
+	// PrevID and PrevAddr identify the previous sync peer.  They are zero
+	// and empty, respectively, when there was no previous sync peer.
+	PrevID   int32
+	PrevAddr string
+
+	// ID and Addr identify the new sync peer.
+	ID   int32
+	Addr string
+
+	// Reason is why the change was made.  It is one of "initial",
+	// "disconnected", "stalled", or "underperforming".
+	Reason string
+}
+
+// SyncPeerInfo describes the current sync peer along with the most recent
+// changes to it.
+type SyncPeerInfo struct {
+	// ID and Addr identify the current sync peer.  They are zero and empty,
+	// respectively, when there is no sync peer.
+	ID   int32
+	Addr string
+
+	// SyncHeight is the latest known block being synced to.
+	SyncHeight int64
+
+	// BlocksInFlight is the number of blocks requested from the sync peer
+	// that it has not delivered yet.
+	BlocksInFlight int
+
+	// Deadline is the time by which the sync peer must deliver the next
+	// requested block before it is considered stalled.  It is the zero
+	// time when there are no blocks in flight to the sync peer.
+	Deadline time.Time
+
+	// Switches are the most recent changes to the sync peer ordered from
+	// oldest to newest.
+	Switches []SyncPeerSwitch
+}
+
+// RejectedTx describes a transaction received from a peer that was rejected
+// along with the reason it was rejected.
+type RejectedTx struct {
+	// Hash is the hash of the rejected transaction.
+	Hash chainhash.Hash
+
+	// Code is the reject code sent to the peer that relayed the transaction.
+	Code wire.RejectCode
+
+	// Reason is a description of the reason the transaction was rejected.
+	Reason string
+
+	// Time is when the transaction was rejected.
+	Time time.Time
+
+	// Height is the height of the best chain when the transaction was
+	// rejected.
+	Height int64
+}
+
+// TxRelayLaneStats houses statistics about the latency between receiving
+// transactions and accepting them to the mempool and announcing them for relay
+// for a relay lane.
+type TxRelayLaneStats struct {
+	// Count is the number of transactions accepted via the lane.
+	Count uint64
+
+	// TotalLatency is the sum of the latencies of all transactions accepted
+	// via the lane.
+	TotalLatency time.Duration
+
+	// MaxLatency is the highest latency of any transaction accepted via the
+	// lane.
+	MaxLatency time.Duration
 }
 
 // UtxoEntry represents a utxo entry for use with the RPC server.
@@ -250,6 +382,11 @@ type Chain interface {
 	// main chain.
 	BlockHeightByHash(hash *chainhash.Hash) (int64, error)
 
+	// BlockReceiveTime returns the local time the block with the given hash was
+	// received.  A zero time is returned when the receive time of the block is
+	// not known.
+	BlockReceiveTime(hash *chainhash.Hash) (time.Time, error)
+
 	// CalcNextRequiredStakeDifficulty calculates the required stake difficulty for
 	// the block after the end of the current best chain based on the active stake
 	// difficulty retarget rules.
@@ -267,6 +404,11 @@ type Chain interface {
 	// provided block hash.
 	ChainWork(hash *chainhash.Hash) (*big.Int, error)
 
+	// CheckConnectBlockTemplate fully validates that connecting the passed block
+	// to either the tip of the main chain or its parent does not violate any
+	// consensus rules, aside from the proof of work requirement.
+	CheckConnectBlockTemplate(block *dcrutil.Block) error
+
 	// CheckExpiredTicket returns whether or not a ticket was ever expired.
 	CheckExpiredTickets(hashes []chainhash.Hash) []bool
 
@@ -321,6 +463,11 @@ type Chain interface {
 	// deployment identifier for the current rule change activation interval.
 	GetVoteCounts(version uint32, deploymentID string) (blockchain.VoteCounts, error)
 
+	// GetVoteCountsAt returns the vote counts for the specified version and
+	// deployment identifier for the rule change activation interval that
+	// contains the block with the provided hash up to and including that block.
+	GetVoteCountsAt(hash *chainhash.Hash, version uint32, deploymentID string) (blockchain.VoteCounts, error)
+
 	// GetVoteInfo returns information on consensus deployment agendas
 	// and their respective states at the provided hash, for the provided
 	// deployment version.
@@ -331,6 +478,13 @@ type Chain interface {
 	// main chain and any side chains.
 	HeaderByHash(hash *chainhash.Hash) (wire.BlockHeader, error)
 
+	// MedianTimeByHash returns the median time of the block identified by the
+	// given hash, as calculated from the timestamps of the block and the blocks
+	// preceding it, or an error if it doesn't exist.  Note that this will
+	// return the median time of blocks from both the main chain and any side
+	// chains.
+	MedianTimeByHash(hash *chainhash.Hash) (time.Time, error)
+
 	// HeaderByHeight returns the block header at the given height in the main
 	// chain.
 	HeaderByHeight(height int64) (wire.BlockHeader, error)
@@ -385,12 +539,29 @@ type Chain interface {
 	// given deployment ID for the block AFTER the provided block hash.
 	NextThresholdState(hash *chainhash.Hash, version uint32, deploymentID string) (blockchain.ThresholdStateTuple, error)
 
+	// RollbackToHeight disconnects all blocks of the main chain after the
+	// provided height, making the block at that height the new tip.
+	RollbackToHeight(height int64) error
+
+	// SimulateRequiredDifficulty projects the required difficulty for the
+	// provided number of blocks after the end of the current best chain assuming
+	// every block is found by a constant hash rate of the provided number of
+	// hashes per second.  The projected difficulty is returned for every
+	// simulated block that is at a difficulty retarget point.
+	SimulateRequiredDifficulty(hashesPerSec *big.Int, numBlocks int64) []standalone.SimulatedWorkDiff
+
 	// StateLastChangedHeight returns the height at which the provided consensus
 	// deployment agenda last changed state.  Note that, unlike the
 	// NextThresholdState function, this function returns the information as of the
 	// passed block hash.
 	StateLastChangedHeight(hash *chainhash.Hash, version uint32, deploymentID string) (int64, error)
 
+	// DeploymentVoteResult returns the outcome of the vote on the provided
+	// consensus deployment agenda as of the passed block hash along with the
+	// vote counts of the interval it was decided in.  Nil is returned when the
+	// vote has not concluded as of the passed block.
+	DeploymentVoteResult(hash *chainhash.Hash, version uint32, deploymentID string) (*blockchain.DeploymentVoteResult, error)
+
 	// TicketPoolValue returns the current value of all the locked funds in the
 	// ticket pool.
 	TicketPoolValue() (dcrutil.Amount, error)
@@ -465,6 +636,10 @@ type CPUMiner interface {
 	// GenerateNBlocks generates the requested number of blocks.
 	GenerateNBlocks(ctx context.Context, n uint32) ([]*chainhash.Hash, error)
 
+	// GenerateNBlocksToAddress generates the requested number of blocks that
+	// pay to the provided address.
+	GenerateNBlocksToAddress(ctx context.Context, n uint32, payToAddr dcrutil.Address) ([]*chainhash.Hash, error)
+
 	// IsMining returns whether or not the CPU miner has been started and is
 	// therefore currently mining.
 	IsMining() bool
@@ -516,6 +691,25 @@ type BlockTemplater interface {
 	// UpdateBlockTime updates the timestamp in the passed header to the current
 	// time while taking into account the consensus rules.
 	UpdateBlockTime(header *wire.BlockHeader) error
+
+	// SetCoinbaseExtraData sets the extra data that is appended to the
+	// coinbase script sig of generated block templates.  An error is returned
+	// when the data exceeds mining.MaxCoinbaseExtraDataLen.
+	SetCoinbaseExtraData(extraData []byte) error
+
+	// MiningAddrs returns the mining addresses generated block templates pay
+	// to along with the policy used to choose between them.
+	MiningAddrs() ([]dcrutil.Address, mining.MiningAddrPolicy)
+
+	// SetMiningAddrs sets the mining addresses generated block templates pay
+	// to along with the policy used to choose between them.  An error is
+	// returned when no addresses are provided.
+	SetMiningAddrs(addrs []dcrutil.Address, policy mining.MiningAddrPolicy) error
+
+	// TemplateDecisions returns the transaction selection decisions made for
+	// up to the provided number of the most recently generated block
+	// templates ordered from newest to oldest.
+	TemplateDecisions(count int) []*mining.TemplateDecisions
 }
 
 // Filterer provides an interface for retrieving a block's committed filter or
@@ -596,6 +790,30 @@ type TxMempooler interface {
 	// transaction pool. This only fetches from the main transaction pool
 	// and does not include orphans.
 	FetchTransaction(txHash *chainhash.Hash) (*dcrutil.Tx, error)
+
+	// Stats returns the current size of the main pool along with rolling
+	// statistics about it over the last hour and day.
+	Stats() *mempool.PoolStats
+
+	// Replacements returns the transaction replacement policy of the pool
+	// along with the most recent replacement decisions.
+	Replacements() *mempool.ReplacementHistory
+
+	// StandardPolicy returns the policy used to determine whether or not
+	// transactions are standard including the standardness policies for all
+	// known script versions and whether or not they currently apply.
+	StandardPolicy() (*mempool.StandardPolicy, error)
+
+	// CheckTransactionStandard returns all violations of the standardness
+	// policy rules by the provided transaction.  No violations are returned
+	// when the transaction is standard.
+	CheckTransactionStandard(tx *dcrutil.Tx) ([]mempool.StandardViolation, error)
+
+	// EvaluateLocks evaluates the lock time and relative lock times of the
+	// provided transaction against the current best chain in order to
+	// determine the earliest height and past median time at which it
+	// becomes final.
+	EvaluateLocks(tx *dcrutil.Tx) (*mempool.LockStatus, error)
 }
 
 // AddrIndexer provides an interface for retrieving transactions for a given
@@ -634,3 +852,107 @@ type TxIndexer interface {
 	// must be returned for the both the entry and the error.
 	Entry(hash *chainhash.Hash) (*indexers.TxIndexEntry, error)
 }
+
+// AddrActivityIndexer provides an interface for retrieving the first and last
+// heights at which an address was seen in the main chain.
+//
+// The interface contract requires that all of these methods are safe for
+// concurrent access.
+type AddrActivityIndexer interface {
+	// AddressActivity returns the first and last heights at which the
+	// provided address was seen in the main chain.  Nil is returned when the
+	// address has not been seen.
+	AddressActivity(addr dcrutil.Address) (*indexers.AddrActivity, error)
+}
+
+// DiskSpaceMonitor provides an interface for querying the free disk space
+// available to the data directory.
+//
+// The interface contract requires that all of these methods are safe for
+// concurrent access.
+type DiskSpaceMonitor interface {
+	// DiskSpace returns the current disk space status of the volume that
+	// houses the data directory.
+	DiskSpace() (*DiskSpaceStatus, error)
+}
+
+// DiskSpaceStatus describes the free disk space available to the data
+// directory relative to the configured thresholds.  All sizes are in bytes.
+type DiskSpaceStatus struct {
+	// Path is the data directory the status applies to.
+	Path string
+
+	// Free is the disk space available to the process.
+	Free uint64
+
+	// Total is the total size of the volume.
+	Total uint64
+
+	// WarnThreshold is the free disk space below which warnings are issued.
+	// It is zero when warnings are disabled.
+	WarnThreshold uint64
+
+	// StopThreshold is the free disk space below which the node shuts down
+	// gracefully.  It is zero when the shutdown is disabled.
+	StopThreshold uint64
+
+	// Level is the level the free disk space is at relative to the
+	// thresholds.  It is one of "ok", "low", or "critical".
+	Level string
+}
+
+// PeerTelemetry provides an interface for exporting the anonymized peer
+// telemetry recorded by the server.
+//
+// The interface contract requires that all of these methods are safe for
+// concurrent access.
+type PeerTelemetry interface {
+	// Dump writes all events recorded since the previous dump to a new file
+	// and resets the recorded events.
+	Dump() (*PeerTelemetryDump, error)
+}
+
+// PeerTelemetryDump describes a file written when dumping the peer telemetry.
+type PeerTelemetryDump struct {
+	// Path is the path of the written file.
+	Path string
+
+	// NumEvents is the number of events written to the file.
+	NumEvents int
+
+	// NumDropped is the number of older events that were discarded since
+	// the previous dump due to the limit on the number of retained events.
+	NumDropped uint64
+}
+
+// Coordinator provides an interface for the leader election of a redundancy
+// pair of nodes that share a virtual IP address such that only the elected
+// leader actively serves miners and websocket clients.
+//
+// The interface contract requires that all of these methods are safe for
+// concurrent access.
+type Coordinator interface {
+	// IsLeader returns whether or not the local node is currently the
+	// leader of the pair.
+	IsLeader() bool
+
+	// Heartbeat processes a heartbeat received from the peer node of the
+	// pair with the provided election state and returns the election state
+	// of the local node after doing so.
+	Heartbeat(peer *CoordinationState) (*CoordinationState, error)
+}
+
+// CoordinationState describes the election state of a node of a redundancy
+// pair.
+type CoordinationState struct {
+	// NodeID uniquely identifies the node within the pair.
+	NodeID string
+
+	// Term is the election term the node is in.  It increases every time a
+	// node promotes itself to leader.
+	Term uint64
+
+	// Leader is whether or not the node considers itself the leader of the
+	// pair for the term.
+	Leader bool
+}
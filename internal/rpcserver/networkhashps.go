@@ -0,0 +1,139 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"math"
+	"math/big"
+	"time"
+)
+
+const (
+	// hashRateMethodWindow is the network hash rate estimation method that
+	// divides the total work of all blocks in the window by the time it took
+	// to produce them.
+	hashRateMethodWindow = "window"
+
+	// hashRateMethodEMA is the network hash rate estimation method that
+	// divides exponential moving averages of the work and solve time of the
+	// blocks in the window so that more recent blocks carry more weight.
+	hashRateMethodEMA = "ema"
+
+	// hashRateConfidence is the confidence level of the network hash rate
+	// confidence intervals.
+	hashRateConfidence = 0.95
+
+	// hashRateConfidenceZ is the standard normal quantile that corresponds to
+	// the upper bound of a two-sided interval at the hash rate confidence
+	// level.
+	hashRateConfidenceZ = 1.959964
+)
+
+// hashRateSample houses the work and timestamp of a block used to estimate
+// the network hash rate.
+type hashRateSample struct {
+	work      *big.Int
+	timestamp time.Time
+}
+
+// windowHashRate returns the estimated number of network hashes per second
+// calculated by dividing the total work of all samples after the first by the
+// difference between the minimum and maximum timestamps of all samples.  Zero
+// is returned when there is no time difference.
+func windowHashRate(samples []hashRateSample) int64 {
+	if len(samples) == 0 {
+		return 0
+	}
+
+	minTimestamp := samples[0].timestamp
+	maxTimestamp := minTimestamp
+	totalWork := big.NewInt(0)
+	for _, sample := range samples[1:] {
+		totalWork.Add(totalWork, sample.work)
+		if minTimestamp.After(sample.timestamp) {
+			minTimestamp = sample.timestamp
+		}
+		if maxTimestamp.Before(sample.timestamp) {
+			maxTimestamp = sample.timestamp
+		}
+	}
+
+	// Avoid division by zero in the case where there is no time difference.
+	timeDiff := int64(maxTimestamp.Sub(minTimestamp) / time.Second)
+	if timeDiff == 0 {
+		return 0
+	}
+	return new(big.Int).Div(totalWork, big.NewInt(timeDiff)).Int64()
+}
+
+// emaHashRate returns the estimated number of network hashes per second
+// calculated by dividing the exponential moving average of the work of all
+// samples after the first by the exponential moving average of the time
+// between them.  The smoothing factor is based on the number of samples so
+// the averages span the entire window while weighting recent samples more
+// heavily.  Zero is returned when the average time between samples is not
+// positive.
+func emaHashRate(samples []hashRateSample) int64 {
+	if len(samples) < 2 {
+		return 0
+	}
+
+	alpha := 2 / float64(len(samples))
+	var avgWork, avgSolveTime float64
+	for i := 1; i < len(samples); i++ {
+		work, _ := new(big.Float).SetInt(samples[i].work).Float64()
+		solveTime := samples[i].timestamp.Sub(samples[i-1].timestamp).Seconds()
+		if i == 1 {
+			avgWork, avgSolveTime = work, solveTime
+			continue
+		}
+		avgWork += alpha * (work - avgWork)
+		avgSolveTime += alpha * (solveTime - avgSolveTime)
+	}
+	if avgSolveTime <= 0 {
+		return 0
+	}
+	return clampHashRate(avgWork / avgSolveTime)
+}
+
+// chiSquareQuantile returns an approximation of the quantile of the chi-square
+// distribution with the provided degrees of freedom that corresponds to the
+// provided standard normal quantile using the Wilson-Hilferty transformation.
+func chiSquareQuantile(degrees, z float64) float64 {
+	v := 2 / (9 * degrees)
+	q := 1 - v + z*math.Sqrt(v)
+	if q <= 0 {
+		return 0
+	}
+	return degrees * q * q * q
+}
+
+// hashRateInterval returns the lower and upper bounds of the confidence
+// interval for the provided estimated network hash rate given the number of
+// blocks it is based on.
+//
+// Blocks are found according to a Poisson process, so the time to find n
+// blocks scaled by twice the rate follows a chi-square distribution with 2n
+// degrees of freedom, which bounds the true rate relative to the estimate.
+func hashRateInterval(hashesPerSec int64, numBlocks int64) (int64, int64) {
+	if hashesPerSec <= 0 || numBlocks <= 0 {
+		return 0, 0
+	}
+
+	degrees := float64(2 * numBlocks)
+	lower := chiSquareQuantile(degrees, -hashRateConfidenceZ) / degrees
+	upper := chiSquareQuantile(degrees, hashRateConfidenceZ) / degrees
+	estimate := float64(hashesPerSec)
+	return clampHashRate(estimate * lower), clampHashRate(estimate * upper)
+}
+
+// clampHashRate converts the provided hash rate to an int64 while limiting it
+// to the maximum value an int64 can represent.
+func clampHashRate(hashesPerSec float64) int64 {
+	if hashesPerSec >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return int64(hashesPerSec)
+}
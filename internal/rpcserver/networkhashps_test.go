@@ -0,0 +1,141 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"math"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// TestNetworkHashRateEstimates ensures the window and exponential moving
+// average network hash rate estimation methods produce the expected results.
+func TestNetworkHashRateEstimates(t *testing.T) {
+	t.Parallel()
+
+	// makeSamples returns samples with the provided work that are spaced by
+	// the provided solve times in seconds.
+	makeSamples := func(work int64, solveTimes ...int64) []hashRateSample {
+		timestamp := time.Unix(1592918788, 0)
+		samples := []hashRateSample{{work: big.NewInt(work), timestamp: timestamp}}
+		for _, solveTime := range solveTimes {
+			timestamp = timestamp.Add(time.Duration(solveTime) * time.Second)
+			samples = append(samples, hashRateSample{
+				work:      big.NewInt(work),
+				timestamp: timestamp,
+			})
+		}
+		return samples
+	}
+
+	tests := []struct {
+		name       string           // test description
+		samples    []hashRateSample // block samples
+		wantWindow int64            // expected window estimate
+		wantEMA    int64            // expected ema estimate
+	}{{
+		name:       "no samples",
+		samples:    nil,
+		wantWindow: 0,
+		wantEMA:    0,
+	}, {
+		name:       "single sample",
+		samples:    makeSamples(600),
+		wantWindow: 0,
+		wantEMA:    0,
+	}, {
+		name:       "constant solve times",
+		samples:    makeSamples(600, 300, 300, 300, 300),
+		wantWindow: 2,
+		wantEMA:    2,
+	}, {
+		name:       "recent blocks slower",
+		samples:    makeSamples(600, 100, 100, 100, 500),
+		wantWindow: 3,
+		wantEMA:    2,
+	}, {
+		name:       "no time difference",
+		samples:    makeSamples(600, 0, 0),
+		wantWindow: 0,
+		wantEMA:    0,
+	}, {
+		name:       "out of order timestamps",
+		samples:    makeSamples(600, 300, -600),
+		wantWindow: 2,
+		wantEMA:    0,
+	}}
+
+	for _, test := range tests {
+		if got := windowHashRate(test.samples); got != test.wantWindow {
+			t.Errorf("%q: unexpected window estimate -- got %d, want %d",
+				test.name, got, test.wantWindow)
+		}
+		if got := emaHashRate(test.samples); got != test.wantEMA {
+			t.Errorf("%q: unexpected ema estimate -- got %d, want %d",
+				test.name, got, test.wantEMA)
+		}
+	}
+}
+
+// TestHashRateInterval ensures the confidence intervals of network hash rate
+// estimates closely match the exact chi-square based intervals and narrow as
+// the number of blocks increases.
+func TestHashRateInterval(t *testing.T) {
+	t.Parallel()
+
+	const estimate = 1e15
+	tests := []struct {
+		name      string  // test description
+		numBlocks int64   // number of blocks
+		wantLower float64 // exact lower bound factor
+		wantUpper float64 // exact upper bound factor
+	}{{
+		name:      "10 blocks",
+		numBlocks: 10,
+		wantLower: 9.590777 / 20,
+		wantUpper: 34.169607 / 20,
+	}, {
+		name:      "100 blocks",
+		numBlocks: 100,
+		wantLower: 162.728 / 200,
+		wantUpper: 241.058 / 200,
+	}, {
+		name:      "1000 blocks",
+		numBlocks: 1000,
+		wantLower: 1878.8 / 2000,
+		wantUpper: 2126.0 / 2000,
+	}}
+
+	prevWidth := int64(math.MaxInt64)
+	for _, test := range tests {
+		lower, upper := hashRateInterval(estimate, test.numBlocks)
+		gotLower, gotUpper := float64(lower)/estimate, float64(upper)/estimate
+		if math.Abs(gotLower-test.wantLower) > 0.005 {
+			t.Errorf("%q: unexpected lower bound factor -- got %f, want %f",
+				test.name, gotLower, test.wantLower)
+		}
+		if math.Abs(gotUpper-test.wantUpper) > 0.005 {
+			t.Errorf("%q: unexpected upper bound factor -- got %f, want %f",
+				test.name, gotUpper, test.wantUpper)
+		}
+		if width := upper - lower; width >= prevWidth {
+			t.Errorf("%q: interval did not narrow -- got width %d, previous "+
+				"width %d", test.name, width, prevWidth)
+		} else {
+			prevWidth = width
+		}
+	}
+
+	// Ensure there is no interval without an estimate or blocks.
+	if lower, upper := hashRateInterval(0, 100); lower != 0 || upper != 0 {
+		t.Errorf("unexpected interval without estimate -- got [%d, %d]",
+			lower, upper)
+	}
+	if lower, upper := hashRateInterval(estimate, 0); lower != 0 || upper != 0 {
+		t.Errorf("unexpected interval without blocks -- got [%d, %d]",
+			lower, upper)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+
+	"github.com/decred/dcrd/rpc/jsonrpc/types/v2"
+)
+
+const (
+	// defaultPageLimit is the number of results returned per page by
+	// commands that support pagination when no limit is specified.
+	defaultPageLimit = 100
+
+	// maxPageLimit is the maximum number of results returned per page by
+	// commands that support pagination.  Larger limits are reduced to it.
+	maxPageLimit = 1000
+)
+
+// encodePageCursor returns an opaque cursor that encodes the provided
+// positions.  The cursor is returned to the caller to request the next page of
+// results and its format is not part of the API, however, it is simply the
+// hex-encoded big-endian positions.
+func encodePageCursor(positions ...uint32) string {
+	b := make([]byte, 4*len(positions))
+	for i, pos := range positions {
+		binary.BigEndian.PutUint32(b[4*i:], pos)
+	}
+	return hex.EncodeToString(b)
+}
+
+// decodePageCursor decodes the provided cursor that was created by
+// encodePageCursor and returns the provided number of positions it encodes.
+// An empty cursor is the cursor of the first page and therefore decodes to
+// all zero positions.
+func decodePageCursor(cursor string, numPositions int) ([]uint32, error) {
+	positions := make([]uint32, numPositions)
+	if cursor == "" {
+		return positions, nil
+	}
+
+	b, err := hex.DecodeString(cursor)
+	if err != nil || len(b) != 4*numPositions {
+		return nil, rpcInvalidError("Invalid cursor: %q", cursor)
+	}
+	for i := range positions {
+		positions[i] = binary.BigEndian.Uint32(b[4*i:])
+	}
+	return positions, nil
+}
+
+// pageLimit returns the number of results to return per page given the
+// provided optional requested limit.  The limit is clamped to the range of
+// one to the maximum page limit.
+func pageLimit(limit *int) int {
+	if limit == nil {
+		return defaultPageLimit
+	}
+	switch {
+	case *limit < 1:
+		return 1
+	case *limit > maxPageLimit:
+		return maxPageLimit
+	}
+	return *limit
+}
+
+// makePageInfo returns the pagination details for a page of results given the
+// provided limit, the number of results that precede the page, the number of
+// results in the page, and the cursor of the next page, if any.
+func makePageInfo(limit int, offset, numResults int64, nextCursor string) types.PageInfo {
+	totalEstimate := offset + numResults
+	if nextCursor != "" {
+		// There is at least one more result when there is a next page.
+		totalEstimate++
+	}
+	return types.PageInfo{
+		NextCursor:    nextCursor,
+		Limit:         limit,
+		TotalEstimate: totalEstimate,
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"time"
+)
+
+// maxProfileDuration is the maximum amount of time a profile captured via the
+// captureprofile RPC samples the process for.
+const maxProfileDuration = 5 * time.Minute
+
+// sampledProfileTypes houses the profile types that are only sampled while
+// being captured and therefore are captured over a duration as opposed to
+// being a snapshot of the current state of the process.
+var sampledProfileTypes = map[string]struct{}{
+	"cpu":   {},
+	"block": {},
+	"mutex": {},
+}
+
+// validProfileType returns whether or not the passed profile type is able to
+// be captured.
+func validProfileType(profileType string) bool {
+	if _, ok := sampledProfileTypes[profileType]; ok {
+		return true
+	}
+	return pprof.Lookup(profileType) != nil
+}
+
+// captureProfile captures a profile of the provided type and writes it in the
+// pprof format to a new file in the provided directory, which is created if
+// needed, and returns its path.  Sampled profiles sample the process for the
+// provided duration or until the provided context is done, while the remaining
+// profile types are a snapshot of the current state of the process.
+//
+// Block and mutex profiling is only enabled while the profile is captured.
+// Callers must ensure only a single profile is captured at a time.
+func captureProfile(ctx context.Context, dir, profileType string, duration time.Duration) (string, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	fileName := fmt.Sprintf("%s-%d.pprof", profileType, time.Now().UnixNano())
+	path := filepath.Join(dir, fileName)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return "", err
+	}
+
+	// wait waits for the duration to elapse or the context to be done.
+	wait := func() {
+		t := time.NewTimer(duration)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+		}
+	}
+
+	switch profileType {
+	case "cpu":
+		err = pprof.StartCPUProfile(f)
+		if err == nil {
+			wait()
+			pprof.StopCPUProfile()
+		}
+
+	case "block":
+		runtime.SetBlockProfileRate(1)
+		wait()
+		runtime.SetBlockProfileRate(0)
+		err = pprof.Lookup(profileType).WriteTo(f, 0)
+
+	case "mutex":
+		prevFraction := runtime.SetMutexProfileFraction(1)
+		wait()
+		runtime.SetMutexProfileFraction(prevFraction)
+		err = pprof.Lookup(profileType).WriteTo(f, 0)
+
+	default:
+		err = pprof.Lookup(profileType).WriteTo(f, 0)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
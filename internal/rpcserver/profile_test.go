@@ -0,0 +1,57 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestCaptureProfile ensures both sampled and snapshot profiles are written to
+// new files in the requested directory.
+func TestCaptureProfile(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "captureprofile")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	dir := filepath.Join(tmpDir, "profiles")
+
+	ctx := context.Background()
+	for _, profileType := range []string{"heap", "goroutine", "cpu", "mutex"} {
+		path, err := captureProfile(ctx, dir, profileType, time.Millisecond*10)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", profileType, err)
+		}
+		if filepath.Dir(path) != dir ||
+			!strings.HasPrefix(filepath.Base(path), profileType+"-") {
+
+			t.Fatalf("%s: unexpected profile path %q", profileType, path)
+		}
+		fi, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("%s: unable to stat profile: %v", profileType, err)
+		}
+		if fi.Size() == 0 {
+			t.Fatalf("%s: empty profile", profileType)
+		}
+	}
+
+	// Ensure a sampled profile stops sampling once the context is done.
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	start := time.Now()
+	if _, err := captureProfile(ctx, dir, "block", time.Minute); err != nil {
+		t.Fatalf("unexpected error for canceled capture: %v", err)
+	}
+	if time.Since(start) > time.Second*30 {
+		t.Fatal("canceled capture did not stop sampling")
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// rpcAuditable houses the RPC commands that change the state of the server
+// and are therefore recorded to the audit log when one is configured.
+var rpcAuditable = map[string]struct{}{
+	"addnode":             {},
+	"captureprofile":      {},
+	"debuglevel":          {},
+	"disconnectrpcclient": {},
+	"generate":            {},
+	"generatetoaddress":   {},
+	"node":                {},
+	"regentemplate":       {},
+	"rollbackchain":       {},
+	"sendrawtransaction":  {},
+	"setgenerate":         {},
+	"setminingaddrs":      {},
+	"setminingextradata":  {},
+	"stop":                {},
+	"submitblock":         {},
+}
+
+// auditEntry describes a single state-changing RPC in the audit log.  Entries
+// are written to the log as JSON objects separated by newlines.
+type auditEntry struct {
+	Time   string            `json:"time"`
+	User   string            `json:"user"`
+	Source string            `json:"source"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+	Result json.RawMessage   `json:"result,omitempty"`
+	Error  string            `json:"error,omitempty"`
+}
+
+// clientAdminKey is the type of the context key used to store whether the
+// client that issued an RPC authenticated with the admin credentials.
+type clientAdminKey struct{}
+
+// withClientAdmin returns a copy of the provided context that records whether
+// the client that issued the RPC authenticated with the admin credentials.
+func withClientAdmin(ctx context.Context, isAdmin bool) context.Context {
+	return context.WithValue(ctx, clientAdminKey{}, isAdmin)
+}
+
+// clientUser returns the username of the credentials the client that issued
+// the RPC associated with the provided context authenticated with.  An empty
+// string is returned when it is unknown.
+func (s *Server) clientUser(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	isAdmin, ok := ctx.Value(clientAdminKey{}).(bool)
+	if !ok {
+		return ""
+	}
+	return s.rpcUser(isAdmin)
+}
+
+// rpcUser returns the username of the admin credentials when isAdmin is set
+// and the username of the limited credentials otherwise.
+func (s *Server) rpcUser(isAdmin bool) string {
+	if isAdmin {
+		return s.cfg.RPCUser
+	}
+	return s.cfg.RPCLimitUser
+}
+
+// auditRPC records the passed RPC along with the credentials and source address
+// of the client that issued it and its outcome to the audit log when one is
+// configured and the method changes the state of the server.  Failure to
+// write to the audit log is logged, but otherwise does not affect the request.
+//
+// This function is safe for concurrent access.
+func (s *Server) auditRPC(method string, params []json.RawMessage, isAdmin bool, source string, result interface{}, replyErr error) {
+	if s.cfg.AuditLog == nil {
+		return
+	}
+	if _, ok := rpcAuditable[method]; !ok {
+		return
+	}
+
+	entry := auditEntry{
+		Time:   s.cfg.Clock.Now().UTC().Format(time.RFC3339Nano),
+		User:   s.rpcUser(isAdmin),
+		Source: source,
+		Method: method,
+		Params: params,
+	}
+	if replyErr != nil {
+		entry.Error = replyErr.Error()
+	} else if result != nil {
+		if res, err := json.Marshal(result); err == nil {
+			entry.Result = res
+		}
+	}
+
+	line, err := json.Marshal(&entry)
+	if err != nil {
+		log.Errorf("Failed to marshal RPC audit log entry: %v", err)
+		return
+	}
+	line = append(line, '\n')
+
+	s.auditMtx.Lock()
+	_, err = s.cfg.AuditLog.Write(line)
+	s.auditMtx.Unlock()
+	if err != nil {
+		log.Errorf("Failed to write RPC audit log entry: %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrjson/v3"
+)
+
+// TestAuditRPC ensures state-changing RPCs are recorded to the audit log with
+// the expected credentials, source, parameters, and outcome while other RPCs
+// are not recorded.
+func TestAuditRPC(t *testing.T) {
+	t.Parallel()
+
+	var auditLog bytes.Buffer
+	s := &Server{
+		cfg: Config{
+			AuditLog:     &auditLog,
+			Clock:        &testClock{now: time.Unix(1592918788, 0)},
+			RPCUser:      "admin",
+			RPCLimitUser: "limited",
+		},
+		requestProcessShutdown: make(chan struct{}, 1),
+	}
+
+	// Issue a stop request as both the limited user, which is not allowed
+	// to call it, and the admin user.
+	request := &dcrjson.Request{Jsonrpc: "1.0", Method: "stop", ID: 1}
+	s.processRequest(context.Background(), request, false, "127.0.0.1:1000")
+	s.processRequest(context.Background(), request, true, "127.0.0.1:1001")
+
+	// Ensure requests that do not change the state of the server are not
+	// recorded.
+	params := []json.RawMessage{json.RawMessage(`"deadbeef"`)}
+	s.auditRPC("getblock", params, true, "127.0.0.1:1002", nil, nil)
+
+	// Ensure parameters are recorded as provided.
+	s.auditRPC("sendrawtransaction", params, false, "127.0.0.1:1003",
+		"txhash", nil)
+
+	want := []auditEntry{{
+		Time:   "2020-06-23T13:26:28Z",
+		User:   "limited",
+		Source: "127.0.0.1:1000",
+		Method: "stop",
+		Error:  "-8: limited user not authorized for this method",
+	}, {
+		Time:   "2020-06-23T13:26:28Z",
+		User:   "admin",
+		Source: "127.0.0.1:1001",
+		Method: "stop",
+		Result: json.RawMessage(`"dcrd stopping."`),
+	}, {
+		Time:   "2020-06-23T13:26:28Z",
+		User:   "limited",
+		Source: "127.0.0.1:1003",
+		Method: "sendrawtransaction",
+		Params: params,
+		Result: json.RawMessage(`"txhash"`),
+	}}
+	lines := strings.Split(strings.TrimSuffix(auditLog.String(), "\n"), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("unexpected number of audit log entries -- got %d, want %d",
+			len(lines), len(want))
+	}
+	for i, line := range lines {
+		var got auditEntry
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("entry %d: unable to parse audit log entry: %v", i, err)
+		}
+		if !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("entry %d: mismatched audit log entry -- got %+v, "+
+				"want %+v", i, got, want[i])
+		}
+	}
+}
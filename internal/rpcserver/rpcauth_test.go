@@ -0,0 +1,101 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"io/ioutil"
+	"net"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestCheckAuthUnixSocket ensures requests received over a unix socket are
+// authorized as the admin user without credentials while requests received
+// over other connections require them.
+func TestCheckAuthUnixSocket(t *testing.T) {
+	t.Parallel()
+
+	login := "limited:pass"
+	auth := "Basic " + base64.StdEncoding.EncodeToString([]byte(login))
+	s := &Server{
+		limitauthsha: sha256.Sum256([]byte(auth)),
+	}
+
+	// Accept a connection over a unix socket to mark a context with.
+	dir, err := ioutil.TempDir("", "rpcauth")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	l, err := net.Listen("unix", filepath.Join(dir, "rpc.sock"))
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+	defer l.Close()
+	clientConn, err := net.Dial("unix", l.Addr().String())
+	if err != nil {
+		t.Fatalf("unable to dial: %v", err)
+	}
+	defer clientConn.Close()
+	conn, err := l.Accept()
+	if err != nil {
+		t.Fatalf("unable to accept: %v", err)
+	}
+	defer conn.Close()
+	unixCtx := unixSocketConnContext(context.Background(), conn)
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		auth      string
+		wantAuth  bool
+		wantAdmin bool
+		wantErr   bool
+	}{{
+		name:    "tcp without credentials",
+		ctx:     context.Background(),
+		wantErr: true,
+	}, {
+		name:     "tcp with limited credentials",
+		ctx:      context.Background(),
+		auth:     auth,
+		wantAuth: true,
+	}, {
+		name:      "unix socket without credentials",
+		ctx:       unixCtx,
+		wantAuth:  true,
+		wantAdmin: true,
+	}, {
+		name:     "unix socket with limited credentials",
+		ctx:      unixCtx,
+		auth:     auth,
+		wantAuth: true,
+	}, {
+		name:    "unix socket with invalid credentials",
+		ctx:     unixCtx,
+		auth:    "Basic invalid",
+		wantErr: true,
+	}}
+	for _, test := range tests {
+		r := httptest.NewRequest("POST", "/", nil).WithContext(test.ctx)
+		if test.auth != "" {
+			r.Header.Set("Authorization", test.auth)
+		}
+		gotAuth, gotAdmin, err := s.checkAuth(r, true)
+		if test.wantErr != (err != nil) {
+			t.Fatalf("%q: unexpected error -- got %v, want error %v",
+				test.name, err, test.wantErr)
+		}
+		if gotAuth != test.wantAuth || gotAdmin != test.wantAdmin {
+			t.Fatalf("%q: unexpected result -- got auth %v admin %v, "+
+				"want auth %v admin %v", test.name, gotAuth, gotAdmin,
+				test.wantAuth, test.wantAdmin)
+		}
+	}
+}
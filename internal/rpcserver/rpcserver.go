@@ -38,7 +38,10 @@ import (
 	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/chaincfg/v3"
 	"github.com/decred/dcrd/database/v2"
+	"github.com/decred/dcrd/dcrec"
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
 	"github.com/decred/dcrd/dcrec/secp256k1/v3/ecdsa"
+	"github.com/decred/dcrd/dcrec/secp256k1/v3/schnorr"
 	"github.com/decred/dcrd/dcrjson/v3"
 	"github.com/decred/dcrd/dcrutil/v3"
 	"github.com/decred/dcrd/internal/mempool"
@@ -93,6 +96,14 @@ const (
 	// merkleRootPairSize is the size in bytes of the merkle root + stake root
 	// of a block.
 	merkleRootPairSize = 64
+
+	// maxSimulateDifficultyScenarios is the maximum number of hash rate
+	// scenarios that may be requested by a single simulatedifficulty RPC.
+	maxSimulateDifficultyScenarios = 16
+
+	// maxSimulateDifficultyBlocks is the maximum number of blocks that may be
+	// simulated for each scenario requested by the simulatedifficulty RPC.
+	maxSimulateDifficultyBlocks = 100000
 )
 
 var (
@@ -131,6 +142,22 @@ var (
 		Code:    dcrjson.ErrRPCNoWallet,
 		Message: "This implementation does not implement wallet commands",
 	}
+
+	// ErrRPCExperimentalDisabled is an error returned to RPC clients when
+	// the provided command is an experimental RPC and experimental RPCs
+	// are not enabled.
+	ErrRPCExperimentalDisabled = &dcrjson.RPCError{
+		Code:    dcrjson.ErrRPCMethodNotFound.Code,
+		Message: "Experimental RPCs are not enabled",
+	}
+
+	// ErrRPCStandby is an error returned to RPC clients when the provided
+	// command serves miners and the node is the standby of a redundancy
+	// pair.
+	ErrRPCStandby = &dcrjson.RPCError{
+		Code:    dcrjson.ErrRPCMisc,
+		Message: "Node is on standby -- mining is served by the leader",
+	}
 )
 
 type commandHandler func(context.Context, *Server, interface{}) (interface{}, error)
@@ -140,80 +167,112 @@ type commandHandler func(context.Context, *Server, interface{}) (interface{}, er
 // a dependency loop.
 var rpcHandlers map[types.Method]commandHandler
 var rpcHandlersBeforeInit = map[types.Method]commandHandler{
-	"addnode":               handleAddNode,
-	"createrawsstx":         handleCreateRawSStx,
-	"createrawssrtx":        handleCreateRawSSRtx,
-	"createrawtransaction":  handleCreateRawTransaction,
-	"debuglevel":            handleDebugLevel,
-	"decoderawtransaction":  handleDecodeRawTransaction,
-	"decodescript":          handleDecodeScript,
-	"estimatefee":           handleEstimateFee,
-	"estimatesmartfee":      handleEstimateSmartFee,
-	"estimatestakediff":     handleEstimateStakeDiff,
-	"existsaddress":         handleExistsAddress,
-	"existsaddresses":       handleExistsAddresses,
-	"existsexpiredtickets":  handleExistsExpiredTickets,
-	"existsliveticket":      handleExistsLiveTicket,
-	"existslivetickets":     handleExistsLiveTickets,
-	"existsmempooltxs":      handleExistsMempoolTxs,
-	"existsmissedtickets":   handleExistsMissedTickets,
-	"generate":              handleGenerate,
-	"getaddednodeinfo":      handleGetAddedNodeInfo,
-	"getbestblock":          handleGetBestBlock,
-	"getbestblockhash":      handleGetBestBlockHash,
-	"getblock":              handleGetBlock,
-	"getblockchaininfo":     handleGetBlockchainInfo,
-	"getblockcount":         handleGetBlockCount,
-	"getblockhash":          handleGetBlockHash,
-	"getblockheader":        handleGetBlockHeader,
-	"getblocksubsidy":       handleGetBlockSubsidy,
-	"getcfilter":            handleGetCFilter,
-	"getcfilterheader":      handleGetCFilterHeader,
-	"getcfilterv2":          handleGetCFilterV2,
-	"getchaintips":          handleGetChainTips,
-	"getcoinsupply":         handleGetCoinSupply,
-	"getconnectioncount":    handleGetConnectionCount,
-	"getcurrentnet":         handleGetCurrentNet,
-	"getdifficulty":         handleGetDifficulty,
-	"getgenerate":           handleGetGenerate,
-	"gethashespersec":       handleGetHashesPerSec,
-	"getheaders":            handleGetHeaders,
-	"getinfo":               handleGetInfo,
-	"getmempoolinfo":        handleGetMempoolInfo,
-	"getmininginfo":         handleGetMiningInfo,
-	"getnettotals":          handleGetNetTotals,
-	"getnetworkhashps":      handleGetNetworkHashPS,
-	"getnetworkinfo":        handleGetNetworkInfo,
-	"getpeerinfo":           handleGetPeerInfo,
-	"getrawmempool":         handleGetRawMempool,
-	"getrawtransaction":     handleGetRawTransaction,
-	"getstakedifficulty":    handleGetStakeDifficulty,
-	"getstakeversioninfo":   handleGetStakeVersionInfo,
-	"getstakeversions":      handleGetStakeVersions,
-	"getticketpoolvalue":    handleGetTicketPoolValue,
-	"getvoteinfo":           handleGetVoteInfo,
-	"gettxout":              handleGetTxOut,
-	"gettxoutsetinfo":       handleGetTxOutSetInfo,
-	"getwork":               handleGetWork,
-	"help":                  handleHelp,
-	"livetickets":           handleLiveTickets,
-	"missedtickets":         handleMissedTickets,
-	"node":                  handleNode,
-	"ping":                  handlePing,
-	"regentemplate":         handleRegenTemplate,
-	"searchrawtransactions": handleSearchRawTransactions,
-	"sendrawtransaction":    handleSendRawTransaction,
-	"setgenerate":           handleSetGenerate,
-	"stop":                  handleStop,
-	"submitblock":           handleSubmitBlock,
-	"ticketfeeinfo":         handleTicketFeeInfo,
-	"ticketsforaddress":     handleTicketsForAddress,
-	"ticketvwap":            handleTicketVWAP,
-	"txfeeinfo":             handleTxFeeInfo,
-	"validateaddress":       handleValidateAddress,
-	"verifychain":           handleVerifyChain,
-	"verifymessage":         handleVerifyMessage,
-	"version":               handleVersion,
+	"addnode":                  handleAddNode,
+	"captureprofile":           handleCaptureProfile,
+	"checktransactionstandard": handleCheckTransactionStandard,
+	"comparechainwork":         handleCompareChainWork,
+	"coordheartbeat":           handleCoordHeartbeat,
+	"createrawsstx":            handleCreateRawSStx,
+	"createrawssrtx":           handleCreateRawSSRtx,
+	"createrawtransaction":     handleCreateRawTransaction,
+	"createrevocation":         handleCreateRevocation,
+	"debuglevel":               handleDebugLevel,
+	"decoderawtransaction":     handleDecodeRawTransaction,
+	"decodescript":             handleDecodeScript,
+	"disconnectrpcclient":      handleDisconnectRPCClient,
+	"dumppeertelemetry":        handleDumpPeerTelemetry,
+	"estimatefee":              handleEstimateFee,
+	"estimatesmartfee":         handleEstimateSmartFee,
+	"estimatestakediff":        handleEstimateStakeDiff,
+	"evaluatetxlocks":          handleEvaluateTxLocks,
+	"existsaddress":            handleExistsAddress,
+	"existsaddresses":          handleExistsAddresses,
+	"existsexpiredtickets":     handleExistsExpiredTickets,
+	"existsliveticket":         handleExistsLiveTicket,
+	"existslivetickets":        handleExistsLiveTickets,
+	"existsmempooltxs":         handleExistsMempoolTxs,
+	"existsmissedtickets":      handleExistsMissedTickets,
+	"generate":                 handleGenerate,
+	"generatetoaddress":        handleGenerateToAddress,
+	"getaddednodeinfo":         handleGetAddedNodeInfo,
+	"getaddressactivity":       handleGetAddressActivity,
+	"getaddressutxos":          handleGetAddressUtxos,
+	"getbestblock":             handleGetBestBlock,
+	"getbestblockhash":         handleGetBestBlockHash,
+	"getblock":                 handleGetBlock,
+	"getblockchaininfo":        handleGetBlockchainInfo,
+	"getblockcount":            handleGetBlockCount,
+	"getblockhash":             handleGetBlockHash,
+	"getblockheader":           handleGetBlockHeader,
+	"getblockstats":            handleGetBlockStats,
+	"getblocksubsidy":          handleGetBlockSubsidy,
+	"getblocktemplate":         handleGetBlockTemplate,
+	"getcfilter":               handleGetCFilter,
+	"getcfilterheader":         handleGetCFilterHeader,
+	"getcfilterv2":             handleGetCFilterV2,
+	"getchainparams":           handleGetChainParams,
+	"getchaintips":             handleGetChainTips,
+	"getcoinsupply":            handleGetCoinSupply,
+	"getconnectioncount":       handleGetConnectionCount,
+	"getcurrentnet":            handleGetCurrentNet,
+	"getdifficulty":            handleGetDifficulty,
+	"getdiskspaceinfo":         handleGetDiskSpaceInfo,
+	"getgenerate":              handleGetGenerate,
+	"gethashespersec":          handleGetHashesPerSec,
+	"getheaders":               handleGetHeaders,
+	"getinfo":                  handleGetInfo,
+	"getmempoolinfo":           handleGetMempoolInfo,
+	"getmempoolreplacements":   handleGetMempoolReplacements,
+	"getminingaddrs":           handleGetMiningAddrs,
+	"getmininginfo":            handleGetMiningInfo,
+	"getnettotals":             handleGetNetTotals,
+	"getnetworkhashps":         handleGetNetworkHashPS,
+	"getnetworkinfo":           handleGetNetworkInfo,
+	"getpeerinfo":              handleGetPeerInfo,
+	"getrawmempool":            handleGetRawMempool,
+	"getrawtransaction":        handleGetRawTransaction,
+	"getrejectedtransactions":  handleGetRejectedTransactions,
+	"getsignalingstats":        handleGetSignalingStats,
+	"getstakedifficulty":       handleGetStakeDifficulty,
+	"getstakeversioninfo":      handleGetStakeVersionInfo,
+	"getstakeversions":         handleGetStakeVersions,
+	"getstandardpolicy":        handleGetStandardPolicy,
+	"getsyncpeer":              handleGetSyncPeer,
+	"gettemplatedecisions":     handleGetTemplateDecisions,
+	"getticketpoolvalue":       handleGetTicketPoolValue,
+	"gettimeinfo":              handleGetTimeInfo,
+	"getvoteinfo":              handleGetVoteInfo,
+	"gettxout":                 handleGetTxOut,
+	"gettxoutsetinfo":          handleGetTxOutSetInfo,
+	"gettxscriptcost":          handleGetTxScriptCost,
+	"getwork":                  handleGetWork,
+	"getworkstats":             handleGetWorkStats,
+	"help":                     handleHelp,
+	"listbanned":               handleListBanned,
+	"listrpcclients":           handleListRPCClients,
+	"livetickets":              handleLiveTickets,
+	"missedtickets":            handleMissedTickets,
+	"node":                     handleNode,
+	"ping":                     handlePing,
+	"proposeblock":             handleProposeBlock,
+	"regentemplate":            handleRegenTemplate,
+	"rollbackchain":            handleRollbackChain,
+	"searchrawtransactions":    handleSearchRawTransactions,
+	"sendrawtransaction":       handleSendRawTransaction,
+	"setgenerate":              handleSetGenerate,
+	"setminingaddrs":           handleSetMiningAddrs,
+	"setminingextradata":       handleSetMiningExtraData,
+	"simulatedifficulty":       handleSimulateDifficulty,
+	"stop":                     handleStop,
+	"submitblock":              handleSubmitBlock,
+	"ticketfeeinfo":            handleTicketFeeInfo,
+	"ticketsforaddress":        handleTicketsForAddress,
+	"ticketvwap":               handleTicketVWAP,
+	"txfeeinfo":                handleTxFeeInfo,
+	"validateaddress":          handleValidateAddress,
+	"verifychain":              handleVerifyChain,
+	"verifymessage":            handleVerifyMessage,
+	"version":                  handleVersion,
 }
 
 // list of commands that we recognize, but for which dcrd has no support because
@@ -287,18 +346,46 @@ var rpcAskWallet = map[string]struct{}{
 	"walletpassphrasechange":  {},
 }
 
+// experimentalRPCPrefix is the prefix of the methods of experimental RPCs.
+//
+// Experimental RPCs provide a path to iterate on the design of new APIs, such
+// as new index queries, with real users before they are frozen.  They are only
+// available when enabled via the configuration of the server and come with no
+// stability guarantees, so they may change or be removed in any release.  They
+// are otherwise handled like any other RPC and are renamed without the prefix
+// once their design is considered stable.
+const experimentalRPCPrefix = "x_"
+
+// isExperimentalRPC returns whether or not the provided method is an
+// experimental RPC.
+func isExperimentalRPC(method types.Method) bool {
+	return strings.HasPrefix(string(method), experimentalRPCPrefix)
+}
+
 // Commands that are currently unimplemented, but should ultimately be.
 var rpcUnimplemented = map[string]struct{}{
 	"estimatepriority": {},
 }
 
+// Commands that serve miners and are therefore only available when the server
+// is not the standby of a redundancy pair.
+var rpcLeaderOnly = map[string]struct{}{
+	"getblocktemplate": {},
+	"getwork":          {},
+	"proposeblock":     {},
+	"regentemplate":    {},
+	"submitblock":      {},
+}
+
 // Commands that are available to a limited user
 var rpcLimited = map[string]struct{}{
 	// Websockets commands
 	"notifyblocks":          {},
+	"notifyfilteredblocks":  {},
 	"notifynewtransactions": {},
 	"notifyreceived":        {},
 	"notifyspent":           {},
+	"registerclient":        {},
 	"rescan":                {},
 	"session":               {},
 	"rebroadcastmissed":     {},
@@ -308,60 +395,77 @@ var rpcLimited = map[string]struct{}{
 	"help": {},
 
 	// HTTP/S-only commands
-	"createrawsstx":         {},
-	"createrawssrtx":        {},
-	"createrawtransaction":  {},
-	"decoderawtransaction":  {},
-	"decodescript":          {},
-	"estimatefee":           {},
-	"estimatesmartfee":      {},
-	"estimatestakediff":     {},
-	"existsaddress":         {},
-	"existsaddresses":       {},
-	"existsexpiredtickets":  {},
-	"existsliveticket":      {},
-	"existslivetickets":     {},
-	"existsmempooltxs":      {},
-	"existsmissedtickets":   {},
-	"getbestblock":          {},
-	"getbestblockhash":      {},
-	"getblock":              {},
-	"getblockchaininfo":     {},
-	"getblockcount":         {},
-	"getblockhash":          {},
-	"getblockheader":        {},
-	"getblocksubsidy":       {},
-	"getcfilter":            {},
-	"getcfilterv2":          {},
-	"getchaintips":          {},
-	"getcoinsupply":         {},
-	"getcurrentnet":         {},
-	"getdifficulty":         {},
-	"getheaders":            {},
-	"getinfo":               {},
-	"getnettotals":          {},
-	"getnetworkhashps":      {},
-	"getnetworkinfo":        {},
-	"getrawmempool":         {},
-	"getstakedifficulty":    {},
-	"getstakeversioninfo":   {},
-	"getstakeversions":      {},
-	"getrawtransaction":     {},
-	"gettxout":              {},
-	"getvoteinfo":           {},
-	"livetickets":           {},
-	"missedtickets":         {},
-	"regentemplate":         {},
-	"searchrawtransactions": {},
-	"sendrawtransaction":    {},
-	"submitblock":           {},
-	"ticketfeeinfo":         {},
-	"ticketsforaddress":     {},
-	"ticketvwap":            {},
-	"txfeeinfo":             {},
-	"validateaddress":       {},
-	"verifymessage":         {},
-	"version":               {},
+	"checktransactionstandard": {},
+	"comparechainwork":         {},
+	"createrawsstx":            {},
+	"createrawssrtx":           {},
+	"createrawtransaction":     {},
+	"createrevocation":         {},
+	"decoderawtransaction":     {},
+	"decodescript":             {},
+	"estimatefee":              {},
+	"estimatesmartfee":         {},
+	"estimatestakediff":        {},
+	"evaluatetxlocks":          {},
+	"existsaddress":            {},
+	"existsaddresses":          {},
+	"existsexpiredtickets":     {},
+	"existsliveticket":         {},
+	"existslivetickets":        {},
+	"existsmempooltxs":         {},
+	"existsmissedtickets":      {},
+	"getaddressactivity":       {},
+	"getaddressutxos":          {},
+	"getbestblock":             {},
+	"getbestblockhash":         {},
+	"getblock":                 {},
+	"getblockchaininfo":        {},
+	"getblockcount":            {},
+	"getblockhash":             {},
+	"getblockheader":           {},
+	"getblockstats":            {},
+	"getblocksubsidy":          {},
+	"getcfilter":               {},
+	"getcfilterv2":             {},
+	"getchainparams":           {},
+	"getchaintips":             {},
+	"getcoinsupply":            {},
+	"getcurrentnet":            {},
+	"getdifficulty":            {},
+	"getdiskspaceinfo":         {},
+	"getheaders":               {},
+	"getinfo":                  {},
+	"getmempoolreplacements":   {},
+	"getnettotals":             {},
+	"getnetworkhashps":         {},
+	"getnetworkinfo":           {},
+	"getrawmempool":            {},
+	"getsignalingstats":        {},
+	"getstakedifficulty":       {},
+	"getstakeversioninfo":      {},
+	"getstakeversions":         {},
+	"getstandardpolicy":        {},
+	"getrawtransaction":        {},
+	"getrejectedtransactions":  {},
+	"gettimeinfo":              {},
+	"gettxout":                 {},
+	"gettxscriptcost":          {},
+	"getvoteinfo":              {},
+	"livetickets":              {},
+	"missedtickets":            {},
+	"proposeblock":             {},
+	"regentemplate":            {},
+	"searchrawtransactions":    {},
+	"sendrawtransaction":       {},
+	"simulatedifficulty":       {},
+	"submitblock":              {},
+	"ticketfeeinfo":            {},
+	"ticketsforaddress":        {},
+	"ticketvwap":               {},
+	"txfeeinfo":                {},
+	"validateaddress":          {},
+	"verifymessage":            {},
+	"version":                  {},
 }
 
 // rpcInternalError is a convenience function to convert an internal error to
@@ -392,18 +496,56 @@ func rpcDeserializationError(fmtStr string, args ...interface{}) *dcrjson.RPCErr
 		fmt.Sprintf(fmtStr, args...))
 }
 
-// rpcRuleError is a convenience function to convert a
-// rule error to an RPC error with the appropriate code set.
-func rpcRuleError(fmtStr string, args ...interface{}) *dcrjson.RPCError {
-	return dcrjson.NewRPCError(dcrjson.ErrRPCMisc,
-		fmt.Sprintf(fmtStr, args...))
+// ruleViolationData returns machine-readable details about the passed error
+// when it is a blockchain or mempool rule error.  Nil is returned for all
+// other errors.
+func ruleViolationData(err error) *types.RuleViolationData {
+	// Unwrap mempool rule errors which may contain either a mempool or
+	// blockchain rule error.
+	var mErr mempool.RuleError
+	if errors.As(err, &mErr) {
+		err = mErr.Err
+	}
+
+	var txErr mempool.TxRuleError
+	if errors.As(err, &txErr) {
+		return &types.RuleViolationData{
+			Category: types.RuleViolationPolicy,
+			Code:     txErr.ErrorCode.String(),
+			Message:  txErr.Description,
+		}
+	}
+
+	var cErr blockchain.RuleError
+	if errors.As(err, &cErr) {
+		data := &types.RuleViolationData{
+			Category: types.RuleViolationConsensus,
+			Code:     cErr.ErrorCode.String(),
+			Message:  cErr.Description,
+		}
+		if idx, ok := cErr.InputIndex(); ok {
+			data.InputIndex = &idx
+		}
+		return data
+	}
+
+	return nil
 }
 
-// rpcDuplicateTxError is a convenience function to convert a
-// rejected duplicate tx error to an RPC error with the appropriate code set.
-func rpcDuplicateTxError(fmtStr string, args ...interface{}) *dcrjson.RPCError {
-	return dcrjson.NewRPCError(dcrjson.ErrRPCDuplicateTx,
-		fmt.Sprintf(fmtStr, args...))
+// rpcRuleViolationError is a convenience function to convert a rule error to
+// an RPC error with the provided code set.  Machine-readable details about the
+// violation are included in the data field of the RPC error when available.
+func rpcRuleViolationError(code dcrjson.RPCErrorCode, ruleErr error, fmtStr string, args ...interface{}) *dcrjson.RPCError {
+	message := fmt.Sprintf(fmtStr, args...)
+	data := ruleViolationData(ruleErr)
+	if data == nil {
+		return dcrjson.NewRPCError(code, message)
+	}
+	rpcErr, err := dcrjson.NewRPCErrorWithData(code, message, data)
+	if err != nil {
+		return dcrjson.NewRPCError(code, message)
+	}
+	return rpcErr
 }
 
 // rpcAddressKeyError is a convenience function to convert an address/key error to
@@ -465,6 +607,7 @@ type workState struct {
 	sync.Mutex
 	prevHash     *chainhash.Hash
 	templatePool map[[merkleRootPairSize]byte]*wire.MsgBlock
+	stats        getWorkStats
 }
 
 // newWorkState returns a new instance of a workState with all internal fields
@@ -729,6 +872,32 @@ func handleCreateRawTransaction(_ context.Context, s *Server, cmd interface{}) (
 	return mtxHex, nil
 }
 
+// handleCoordHeartbeat implements the coordheartbeat command.
+func handleCoordHeartbeat(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	if s.cfg.Coordinator == nil {
+		return nil, &dcrjson.RPCError{
+			Code:    dcrjson.ErrRPCMisc,
+			Message: "Coordination is not enabled",
+		}
+	}
+
+	c := cmd.(*types.CoordHeartbeatCmd)
+	state, err := s.cfg.Coordinator.Heartbeat(&CoordinationState{
+		NodeID: c.NodeID,
+		Term:   c.Term,
+		Leader: c.Leader,
+	})
+	if err != nil {
+		return nil, rpcInvalidError("Invalid heartbeat: %v", err)
+	}
+
+	return &types.CoordHeartbeatResult{
+		NodeID: state.NodeID,
+		Term:   state.Term,
+		Leader: state.Leader,
+	}, nil
+}
+
 // handleCreateRawSStx handles createrawsstx commands.
 func handleCreateRawSStx(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
 	c := cmd.(*types.CreateRawSStxCmd)
@@ -920,6 +1089,211 @@ func handleCreateRawSStx(_ context.Context, s *Server, cmd interface{}) (interfa
 	return mtxHex, nil
 }
 
+// handleCaptureProfile implements the captureprofile command.
+func handleCaptureProfile(ctx context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.CaptureProfileCmd)
+
+	if s.cfg.ProfileDir == "" {
+		return nil, &dcrjson.RPCError{
+			Code:    dcrjson.ErrRPCMisc,
+			Message: "Profile capture is not available",
+		}
+	}
+	if !validProfileType(c.Type) {
+		return nil, rpcInvalidError("Invalid profile type: %q", c.Type)
+	}
+	duration := time.Duration(*c.Duration) * time.Second
+	_, sampled := sampledProfileTypes[c.Type]
+	if sampled && (duration <= 0 || duration > maxProfileDuration) {
+		return nil, rpcInvalidError("Duration must be between 1 and %d "+
+			"seconds", int64(maxProfileDuration/time.Second))
+	}
+
+	// Only allow a single profile to be captured at a time since the
+	// sampled profiles are process-wide.
+	if !atomic.CompareAndSwapInt32(&s.capturingProfile, 0, 1) {
+		return nil, rpcMiscError("A profile capture is already in progress")
+	}
+	defer atomic.StoreInt32(&s.capturingProfile, 0)
+
+	path, err := captureProfile(ctx, s.cfg.ProfileDir, c.Type, duration)
+	if err != nil {
+		context := "Failed to capture profile"
+		return nil, rpcInternalError(err.Error(), context)
+	}
+	return &types.CaptureProfileResult{Type: c.Type, Path: path}, nil
+}
+
+// handleCheckTransactionStandard implements the checktransactionstandard
+// command.
+func handleCheckTransactionStandard(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.CheckTransactionStandardCmd)
+
+	hexStr := c.HexTx
+	if len(hexStr)%2 != 0 {
+		hexStr = "0" + hexStr
+	}
+	serializedTx, err := hex.DecodeString(hexStr)
+	if err != nil {
+		return nil, rpcDecodeHexError(hexStr)
+	}
+	msgTx := wire.NewMsgTx()
+	err = msgTx.Deserialize(bytes.NewReader(serializedTx))
+	if err != nil {
+		return nil, rpcDeserializationError("Could not decode Tx: %v",
+			err)
+	}
+
+	violations, err := s.cfg.TxMempooler.CheckTransactionStandard(
+		dcrutil.NewTx(msgTx))
+	if err != nil {
+		return nil, rpcInternalError(err.Error(),
+			"Could not check transaction standardness")
+	}
+
+	results := make([]types.StandardViolationResult, 0, len(violations))
+	for i := range violations {
+		v := &violations[i]
+		result := types.StandardViolationResult{
+			Rule:        string(v.Rule),
+			Description: v.Description,
+		}
+		if v.Input >= 0 {
+			input := v.Input
+			result.Input = &input
+		}
+		if v.Output >= 0 {
+			output := v.Output
+			result.Output = &output
+		}
+		results = append(results, result)
+	}
+
+	return &types.CheckTransactionStandardResult{
+		Standard:   len(results) == 0,
+		Violations: results,
+	}, nil
+}
+
+// handleCompareChainWork implements the comparechainwork command.
+func handleCompareChainWork(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.CompareChainWorkCmd)
+
+	if len(c.Headers) == 0 {
+		return nil, rpcInvalidError("At least one header must be provided")
+	}
+	if len(c.Headers) > wire.MaxBlockHeadersPerMsg {
+		return nil, rpcInvalidError("Too many headers: %d > %d",
+			len(c.Headers), wire.MaxBlockHeadersPerMsg)
+	}
+
+	// Deserialize the headers and ensure they form a chain with valid proof
+	// of work for their claimed difficulties.  Note that the difficulties
+	// themselves are not validated against the retarget rules since that
+	// requires the full chain context, however the work of each header is
+	// only counted according to the proof of work it actually provides.
+	powLimit := s.cfg.ChainParams.PowLimit
+	headers := make([]wire.BlockHeader, len(c.Headers))
+	hashes := make([]chainhash.Hash, len(c.Headers))
+	for i, hexStr := range c.Headers {
+		if len(hexStr)%2 != 0 {
+			hexStr = "0" + hexStr
+		}
+		serializedHeader, err := hex.DecodeString(hexStr)
+		if err != nil {
+			return nil, rpcDecodeHexError(hexStr)
+		}
+		header := &headers[i]
+		err = header.Deserialize(bytes.NewReader(serializedHeader))
+		if err != nil {
+			return nil, rpcDeserializationError("Could not decode header "+
+				"%d: %v", i, err)
+		}
+		hashes[i] = header.BlockHash()
+
+		if i > 0 && (header.PrevBlock != hashes[i-1] ||
+			header.Height != headers[i-1].Height+1) {
+
+			return nil, rpcInvalidError("Header %d (%s) does not connect to "+
+				"the previous header", i, hashes[i])
+		}
+		err = standalone.CheckProofOfWork(&hashes[i], header.Bits, powLimit)
+		if err != nil {
+			return nil, rpcInvalidError("Header %d (%s) has invalid proof "+
+				"of work: %v", i, hashes[i], err)
+		}
+	}
+
+	// The first header must connect to a block that is known locally.
+	chain := s.cfg.Chain
+	parentHash := headers[0].PrevBlock
+	parentHeader, err := chain.HeaderByHash(&parentHash)
+	if err != nil {
+		return nil, &dcrjson.RPCError{
+			Code: dcrjson.ErrRPCBlockNotFound,
+			Message: fmt.Sprintf("Parent of the first header not found: %v",
+				parentHash),
+		}
+	}
+	if headers[0].Height != parentHeader.Height+1 {
+		return nil, rpcInvalidError("Header 0 (%s) has height %d instead "+
+			"of %d", hashes[0], headers[0].Height, parentHeader.Height+1)
+	}
+
+	// Determine the fork point between the foreign chain and the local best
+	// chain.  When the first header connects to a block in a side chain, the
+	// fork point is the most recent ancestor of that block in the best chain.
+	// Otherwise, it is the final header that is already part of the best
+	// chain, if any.
+	forkHash, forkHeader := parentHash, parentHeader
+	for !chain.MainChainHasBlock(&forkHash) {
+		forkHash = forkHeader.PrevBlock
+		forkHeader, err = chain.HeaderByHash(&forkHash)
+		if err != nil {
+			context := "Failed to retrieve block header"
+			return nil, rpcInternalError(err.Error(), context)
+		}
+	}
+	forkHeight := int64(forkHeader.Height)
+	if forkHash == parentHash {
+		for i := range hashes {
+			if !chain.MainChainHasBlock(&hashes[i]) {
+				break
+			}
+			forkHash, forkHeight = hashes[i], int64(headers[i].Height)
+		}
+	}
+
+	// Calculate the cumulative work of the foreign chain and compare it to
+	// the work of the local best chain.
+	chainWork, err := chain.ChainWork(&parentHash)
+	if err != nil {
+		return nil, rpcInternalError(err.Error(), "Failed to retrieve work")
+	}
+	chainWork = new(big.Int).Set(chainWork)
+	for i := range headers {
+		chainWork.Add(chainWork, standalone.CalcWork(headers[i].Bits))
+	}
+	best := chain.BestSnapshot()
+	bestChainWork, err := chain.ChainWork(&best.Hash)
+	if err != nil {
+		return nil, rpcInternalError(err.Error(), "Failed to retrieve work")
+	}
+
+	tip := len(headers) - 1
+	return &types.CompareChainWorkResult{
+		ForkHash:      forkHash.String(),
+		ForkHeight:    forkHeight,
+		TipHash:       hashes[tip].String(),
+		TipHeight:     int64(headers[tip].Height),
+		ChainWork:     fmt.Sprintf("%064x", chainWork),
+		BestHash:      best.Hash.String(),
+		BestHeight:    best.Height,
+		BestChainWork: fmt.Sprintf("%064x", bestChainWork),
+		MoreWork:      chainWork.Cmp(bestChainWork) > 0,
+	}, nil
+}
+
 // handleCreateRawSSRtx handles createrawssrtx commands.
 func handleCreateRawSSRtx(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
 	c := cmd.(*types.CreateRawSSRtxCmd)
@@ -1045,6 +1419,74 @@ func handleCreateRawSSRtx(_ context.Context, s *Server, cmd interface{}) (interf
 	return mtxHex, nil
 }
 
+// handleCreateRevocation implements the createrevocation command.
+func handleCreateRevocation(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.CreateRevocationCmd)
+
+	ticketHash, err := chainhash.NewHashFromStr(c.TicketHash)
+	if err != nil {
+		return nil, rpcDecodeHexError(c.TicketHash)
+	}
+
+	// Decode the fee as coins.  Revocations are relayed without any fees by
+	// default, so no fee is paid unless requested.
+	var feeAmt dcrutil.Amount
+	if c.Fee != nil {
+		feeAmt, err = dcrutil.NewAmount(*c.Fee)
+		if err != nil {
+			return nil, rpcInvalidError("Invalid fee amount: %v", err)
+		}
+		if feeAmt < 0 {
+			return nil, rpcInvalidError("Fee amount must not be negative")
+		}
+	}
+
+	// Ensure the ticket exists and has not already been revoked.
+	ticketUtx, err := s.cfg.Chain.FetchUtxoEntry(ticketHash)
+	if ticketUtx == nil || err != nil {
+		return nil, rpcNoTxInfoError(ticketHash)
+	}
+	if t := ticketUtx.TransactionType(); t != stake.TxTypeSStx {
+		return nil, rpcInvalidError("Transaction %v is not a ticket",
+			ticketHash)
+	}
+	if ticketUtx.IsOutputSpent(0) {
+		return nil, rpcInvalidError("Ticket %v has already been spent",
+			ticketHash)
+	}
+
+	// Only tickets that missed their vote or expired may be revoked.
+	hashes := []chainhash.Hash{*ticketHash}
+	missed := s.cfg.Chain.CheckMissedTickets(hashes)
+	expired := s.cfg.Chain.CheckExpiredTickets(hashes)
+	if len(missed) != 1 || len(expired) != 1 {
+		return nil, rpcInternalError("Invalid ticket status count",
+			"Revocation")
+	}
+	if !missed[0] && !expired[0] {
+		return nil, rpcInvalidError("Ticket %v is neither missed nor "+
+			"expired", ticketHash)
+	}
+
+	minimalOutputs := s.cfg.Chain.ConvertUtxosToMinimalOutputs(ticketUtx)
+	mtx, err := stake.CreateRevocationFromTicket(ticketHash, minimalOutputs,
+		feeAmt)
+	if err != nil {
+		var sErr stake.RuleError
+		if errors.As(err, &sErr) && sErr.ErrorCode == stake.ErrSSRtxFeeTooHigh {
+			return nil, rpcInvalidError(err.Error())
+		}
+		return nil, rpcInternalError(err.Error(), "Invalid SSRtx")
+	}
+
+	// Return the serialized and hex-encoded transaction.
+	mtxHex, err := s.messageToHex(mtx)
+	if err != nil {
+		return nil, err
+	}
+	return mtxHex, nil
+}
+
 // handleDebugLevel handles debuglevel commands.
 func handleDebugLevel(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
 	c := cmd.(*types.DebugLevelCmd)
@@ -1330,6 +1772,38 @@ func handleDecodeScript(_ context.Context, s *Server, cmd interface{}) (interfac
 	return reply, nil
 }
 
+// handleDisconnectRPCClient implements the disconnectrpcclient command.
+func handleDisconnectRPCClient(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.DisconnectRPCClientCmd)
+	if !s.ntfnMgr.DisconnectClient(c.SessionID) {
+		return nil, rpcInvalidError("No websocket client with session "+
+			"id %d", c.SessionID)
+	}
+	return nil, nil
+}
+
+// handleDumpPeerTelemetry implements the dumppeertelemetry command.
+func handleDumpPeerTelemetry(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	if s.cfg.PeerTelemetry == nil {
+		return nil, &dcrjson.RPCError{
+			Code:    dcrjson.ErrRPCMisc,
+			Message: "Peer telemetry is not enabled",
+		}
+	}
+
+	dump, err := s.cfg.PeerTelemetry.Dump()
+	if err != nil {
+		context := "Failed to dump peer telemetry"
+		return nil, rpcInternalError(err.Error(), context)
+	}
+
+	return &types.DumpPeerTelemetryResult{
+		File:    dump.Path,
+		Events:  dump.NumEvents,
+		Dropped: dump.NumDropped,
+	}, nil
+}
+
 // handleEstimateFee implements the estimatefee command.
 // TODO this is a very basic implementation.  It should be
 // modified to match the bitcoin-core one.
@@ -1431,6 +1905,55 @@ func handleEstimateStakeDiff(_ context.Context, s *Server, cmd interface{}) (int
 	}, nil
 }
 
+// handleEvaluateTxLocks implements the evaluatetxlocks command.
+func handleEvaluateTxLocks(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.EvaluateTxLocksCmd)
+
+	hexStr := c.HexTx
+	if len(hexStr)%2 != 0 {
+		hexStr = "0" + hexStr
+	}
+	serializedTx, err := hex.DecodeString(hexStr)
+	if err != nil {
+		return nil, rpcDecodeHexError(hexStr)
+	}
+	msgTx := wire.NewMsgTx()
+	err = msgTx.Deserialize(bytes.NewReader(serializedTx))
+	if err != nil {
+		return nil, rpcDeserializationError("Could not decode Tx: %v",
+			err)
+	}
+
+	tx := dcrutil.NewTx(msgTx)
+	status, err := s.cfg.TxMempooler.EvaluateLocks(tx)
+	if err != nil {
+		// A rule error means the lock times could not be evaluated due
+		// to the transaction itself, such as when it spends an output
+		// that is not available, as opposed to something actually going
+		// wrong.
+		var rErr blockchain.RuleError
+		if errors.As(err, &rErr) {
+			return nil, rpcRuleViolationError(dcrjson.ErrRPCMisc, rErr,
+				"unable to evaluate locks for transaction %v: %v",
+				tx.Hash(), err)
+		}
+		return nil, rpcInternalError(err.Error(),
+			"Could not evaluate transaction locks")
+	}
+
+	return &types.EvaluateTxLocksResult{
+		Final:                  status.Final,
+		NextHeight:             status.NextHeight,
+		MedianTime:             status.MedianTime.Unix(),
+		LockTimeHeight:         status.LockTimeHeight,
+		LockTimeMedianTime:     status.LockTimeMedianTime,
+		SequenceLockHeight:     status.SequenceLockHeight,
+		SequenceLockMedianTime: status.SequenceLockMedianTime,
+		EarliestHeight:         status.EarliestHeight,
+		EarliestMedianTime:     status.EarliestMedianTime,
+	}, nil
+}
+
 // handleExistsAddress implements the existsaddress command.
 func handleExistsAddress(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
 	if s.cfg.ExistsAddresser == nil {
@@ -1694,6 +2217,47 @@ func handleGenerate(ctx context.Context, s *Server, cmd interface{}) (interface{
 	return reply, nil
 }
 
+// handleGenerateToAddress handles generatetoaddress commands.
+func handleGenerateToAddress(ctx context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.GenerateToAddressCmd)
+
+	// Respond with an error when not on a private network since blocks paying
+	// to arbitrary addresses are only useful for funding test wallets.
+	params := s.cfg.ChainParams
+	if params.Net != wire.SimNet && params.Net != wire.RegNet {
+		return nil, &dcrjson.RPCError{
+			Code: dcrjson.ErrRPCDifficulty,
+			Message: fmt.Sprintf("No support for `generatetoaddress` on "+
+				"the current network, %s, as it is only available on "+
+				"simnet and regnet.", params.Net),
+		}
+	}
+
+	// Respond with an error if the client is requesting 0 blocks to be
+	// generated.
+	if c.NumBlocks == 0 {
+		return nil, rpcInvalidError("Invalid number of blocks")
+	}
+
+	addr, err := dcrutil.DecodeAddress(c.Address, params)
+	if err != nil {
+		return nil, rpcAddressKeyError("Could not decode address: %v", err)
+	}
+
+	// Mine the correct number of blocks, assigning the hex representation of
+	// the hash of each one to its place in the reply.
+	blockHashes, err := s.cfg.CPUMiner.GenerateNBlocksToAddress(ctx,
+		c.NumBlocks, addr)
+	if err != nil {
+		return nil, rpcInternalError(err.Error(), "Could not generate blocks")
+	}
+	reply := make([]string, 0, len(blockHashes))
+	for _, hash := range blockHashes {
+		reply = append(reply, hash.String())
+	}
+	return reply, nil
+}
+
 // handleGetAddedNodeInfo handles getaddednodeinfo commands.
 func handleGetAddedNodeInfo(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
 	c := cmd.(*types.GetAddedNodeInfoCmd)
@@ -1714,6 +2278,13 @@ func handleGetAddedNodeInfo(_ context.Context, s *Server, cmd interface{}) (inte
 		}
 	}
 
+	// The connection manager does not provide the added peers in a stable
+	// order, so sort them by address to ensure the same added peers always
+	// result in the same response.
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].Addr() < peers[j].Addr()
+	})
+
 	// Without the dns flag, the result is just a slice of the addresses as
 	// strings.
 	if !c.DNS {
@@ -1774,22 +2345,219 @@ func handleGetAddedNodeInfo(_ context.Context, s *Server, cmd interface{}) (inte
 	return results, nil
 }
 
-// handleGetBestBlock implements the getbestblock command.
-func handleGetBestBlock(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
-	// All other "get block" commands give either the height, the hash, or
-	// both but require the block SHA.  This gets both for the best block.
-	best := s.cfg.Chain.BestSnapshot()
-	result := &types.GetBestBlockResult{
-		Hash:   best.Hash.String(),
-		Height: best.Height,
+// handleGetAddressActivity implements the getaddressactivity command.
+func handleGetAddressActivity(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	// Respond with an error if the address activity index is not enabled.
+	if s.cfg.AddrActivityIndexer == nil {
+		return nil, rpcInternalError("Address activity index must be "+
+			"enabled (--addractivityindex)", "Configuration")
+	}
+
+	// Attempt to decode the supplied address.  This also ensures the network
+	// encoded with the address matches the network the server is currently on.
+	c := cmd.(*types.GetAddressActivityCmd)
+	addr, err := dcrutil.DecodeAddress(c.Address, s.cfg.ChainParams)
+	if err != nil {
+		return nil, rpcAddressKeyError("Could not decode address: %v",
+			err)
+	}
+
+	activity, err := s.cfg.AddrActivityIndexer.AddressActivity(addr)
+	if err != nil {
+		return nil, rpcInvalidError("Could not query address: %v", err)
+	}
+	result := &types.GetAddressActivityResult{Address: c.Address}
+	if activity == nil {
+		return result, nil
+	}
+
+	chain := s.cfg.Chain
+	firstHash, err := chain.BlockHashByHeight(activity.FirstSeen)
+	if err != nil {
+		context := fmt.Sprintf("Failed to fetch block hash for height %d",
+			activity.FirstSeen)
+		return nil, rpcInternalError(err.Error(), context)
+	}
+	lastHash, err := chain.BlockHashByHeight(activity.LastSeen)
+	if err != nil {
+		context := fmt.Sprintf("Failed to fetch block hash for height %d",
+			activity.LastSeen)
+		return nil, rpcInternalError(err.Error(), context)
 	}
+	result.Seen = true
+	result.FirstSeenHeight = activity.FirstSeen
+	result.FirstSeenHash = firstHash.String()
+	result.LastSeenHeight = activity.LastSeen
+	result.LastSeenHash = lastHash.String()
 	return result, nil
 }
 
-// handleGetBestBlockHash implements the getbestblockhash command.
-func handleGetBestBlockHash(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
-	best := s.cfg.Chain.BestSnapshot()
-	return best.Hash.String(), nil
+// addrUtxoScanBatchSize is the number of address index entries that are
+// loaded at a time while scanning for the unspent outputs of an address.
+const addrUtxoScanBatchSize = 100
+
+// handleGetAddressUtxos implements the getaddressutxos command.
+func handleGetAddressUtxos(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	// Respond with an error if the address index is not enabled.
+	if s.cfg.AddrIndexer == nil {
+		return nil, rpcInternalError("Address index must be "+
+			"enabled (--addrindex)", "Configuration")
+	}
+
+	// Attempt to decode the supplied address.  This also ensures the network
+	// encoded with the address matches the network the server is currently on.
+	c := cmd.(*types.GetAddressUtxosCmd)
+	addr, err := dcrutil.DecodeAddress(c.Address, s.cfg.ChainParams)
+	if err != nil {
+		return nil, rpcAddressKeyError("Could not decode address: %v",
+			err)
+	}
+
+	// The cursor encodes the offset of the address index entry and the
+	// output index to resume scanning from along with the number of unspent
+	// outputs returned by the previous pages.
+	limit := pageLimit(c.Limit)
+	var cursor string
+	if c.Cursor != nil {
+		cursor = *c.Cursor
+	}
+	positions, err := decodePageCursor(cursor, 3)
+	if err != nil {
+		return nil, err
+	}
+	txOffset, startVout, numPreceding := positions[0], positions[1],
+		positions[2]
+
+	// Scan the transactions that involve the address in the order they were
+	// confirmed for unspent outputs that pay to it.  The scan continues past
+	// the limit until the next unspent output is found so that the next
+	// cursor is only provided when there is a next page.
+	//
+	// NOTE: Only confirmed outputs are considered since outputs in the
+	// mempool do not have a stable position.
+	best := s.cfg.Chain.BestSnapshot()
+	encodedAddr := addr.Address()
+	utxos := make([]types.AddressUtxo, 0, limit)
+	var nextCursor string
+	for nextCursor == "" {
+		var serializedTxns [][]byte
+		err := s.cfg.DB.View(func(dbTx database.Tx) error {
+			idxEntries, _, err := s.cfg.AddrIndexer.EntriesForAddress(
+				dbTx, addr, txOffset, addrUtxoScanBatchSize, false)
+			if err != nil {
+				return err
+			}
+			regions := make([]database.BlockRegion, 0, len(idxEntries))
+			for i := 0; i < len(idxEntries); i++ {
+				regions = append(regions, idxEntries[i].BlockRegion)
+			}
+			serializedTxns, err = dbTx.FetchBlockRegions(regions)
+			return err
+		})
+		if err != nil {
+			context := "Failed to load address index entries"
+			return nil, rpcInternalError(err.Error(), context)
+		}
+
+		for _, serializedTx := range serializedTxns {
+			var mtx wire.MsgTx
+			err := mtx.Deserialize(bytes.NewReader(serializedTx))
+			if err != nil {
+				context := "Failed to deserialize transaction"
+				return nil, rpcInternalError(err.Error(), context)
+			}
+
+			// The utxo entry is only loaded once an output that pays to
+			// the address is found.
+			txHash := mtx.TxHash()
+			var entry UtxoEntry
+			var fetchedEntry bool
+			for vout := startVout; vout < uint32(len(mtx.TxOut)); vout++ {
+				txOut := mtx.TxOut[vout]
+				_, addrs, _, _ := txscript.ExtractPkScriptAddrs(
+					txOut.Version, txOut.PkScript, s.cfg.ChainParams)
+				var paysToAddr bool
+				for _, outAddr := range addrs {
+					if outAddr.Address() == encodedAddr {
+						paysToAddr = true
+						break
+					}
+				}
+				if !paysToAddr {
+					continue
+				}
+
+				if !fetchedEntry {
+					entry, err = s.cfg.Chain.FetchUtxoEntry(&txHash)
+					if err != nil {
+						context := "Failed to retrieve utxo entry"
+						return nil, rpcInternalError(err.Error(),
+							context)
+					}
+					fetchedEntry = true
+				}
+				if entry == nil || entry.IsOutputSpent(vout) {
+					continue
+				}
+
+				if len(utxos) == limit {
+					nextCursor = encodePageCursor(txOffset, vout,
+						numPreceding+uint32(limit))
+					break
+				}
+
+				tree := wire.TxTreeRegular
+				if entry.TransactionType() != stake.TxTypeRegular {
+					tree = wire.TxTreeStake
+				}
+				height := entry.BlockHeight()
+				utxos = append(utxos, types.AddressUtxo{
+					Txid:          txHash.String(),
+					Vout:          vout,
+					Tree:          tree,
+					Amount:        dcrutil.Amount(txOut.Value).ToCoin(),
+					ScriptPubKey:  hex.EncodeToString(txOut.PkScript),
+					Height:        height,
+					Confirmations: 1 + best.Height - height,
+				})
+			}
+			if nextCursor != "" {
+				break
+			}
+			txOffset++
+			startVout = 0
+		}
+
+		// There are no more entries to scan when the final batch was not
+		// full.
+		if len(serializedTxns) < addrUtxoScanBatchSize {
+			break
+		}
+	}
+
+	return &types.GetAddressUtxosResult{
+		Utxos: utxos,
+		Page: makePageInfo(limit, int64(numPreceding), int64(len(utxos)),
+			nextCursor),
+	}, nil
+}
+
+// handleGetBestBlock implements the getbestblock command.
+func handleGetBestBlock(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	// All other "get block" commands give either the height, the hash, or
+	// both but require the block SHA.  This gets both for the best block.
+	best := s.cfg.Chain.BestSnapshot()
+	result := &types.GetBestBlockResult{
+		Hash:   best.Hash.String(),
+		Height: best.Height,
+	}
+	return result, nil
+}
+
+// handleGetBestBlockHash implements the getbestblockhash command.
+func handleGetBestBlockHash(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	best := s.cfg.Chain.BestSnapshot()
+	return best.Hash.String(), nil
 }
 
 // getDifficultyRatio returns the proof-of-work difficulty as a multiple of the
@@ -1848,6 +2616,12 @@ func handleGetBlock(_ context.Context, s *Server, cmd interface{}) (interface{},
 		return nil, rpcInternalError(err.Error(), "Failed to retrieve work")
 	}
 
+	receiveTime, err := chain.BlockReceiveTime(hash)
+	if err != nil {
+		return nil, rpcInternalError(err.Error(),
+			"Failed to retrieve receive time")
+	}
+
 	best := chain.BestSnapshot()
 
 	// Get next block hash unless there are none.
@@ -1881,6 +2655,7 @@ func handleGetBlock(_ context.Context, s *Server, cmd interface{}) (interface{},
 		Revocations:   blockHeader.Revocations,
 		PoolSize:      blockHeader.PoolSize,
 		Time:          blockHeader.Timestamp.Unix(),
+		ReceivedTime:  unixTime(receiveTime),
 		StakeVersion:  blockHeader.StakeVersion,
 		Confirmations: confirmations,
 		Height:        int64(blockHeader.Height),
@@ -1945,6 +2720,26 @@ func handleGetBlock(_ context.Context, s *Server, cmd interface{}) (interface{},
 	return blockReply, nil
 }
 
+// agendaVoteResult converts the provided vote result for a consensus
+// deployment agenda to its RPC representation.
+func agendaVoteResult(agenda *chaincfg.ConsensusDeployment, state blockchain.ThresholdStateTuple, result *blockchain.DeploymentVoteResult) *types.AgendaVoteResult {
+	voteResult := &types.AgendaVoteResult{
+		DecisionHeight: result.DecisionHeight,
+		TotalVotes:     result.Counts.Total,
+		AbstainVotes:   result.Counts.TotalAbstain,
+		Choices:        make(map[string]uint32, len(agenda.Vote.Choices)),
+	}
+	for i, choice := range agenda.Vote.Choices {
+		if i < len(result.Counts.VoteChoices) {
+			voteResult.Choices[choice.Id] = result.Counts.VoteChoices[i]
+		}
+	}
+	if state.Choice < uint32(len(agenda.Vote.Choices)) {
+		voteResult.Choice = agenda.Vote.Choices[state.Choice].Id
+	}
+	return voteResult
+}
+
 // handleGetBlockchainInfo implements the getblockchaininfo command.
 func handleGetBlockchainInfo(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
 	chain := s.cfg.Chain
@@ -1983,6 +2778,7 @@ func handleGetBlockchainInfo(_ context.Context, s *Server, cmd interface{}) (int
 				StartTime:  agenda.StartTime,
 				ExpireTime: agenda.ExpireTime,
 				Status:     defaultStatus,
+				Version:    version,
 			}
 
 			// If the best block is the genesis block, continue without attempting to
@@ -2008,6 +2804,21 @@ func handleGetBlockchainInfo(_ context.Context, s *Server, cmd interface{}) (int
 						"height for agenda with id (%v).", agenda.Vote.Id))
 			}
 
+			// Include the final vote tally and activation height for
+			// agendas for which voting has concluded.
+			voteResult, err := chain.DeploymentVoteResult(&best.Hash,
+				version, agenda.Vote.Id)
+			if err != nil {
+				return nil, rpcInternalError(err.Error(),
+					fmt.Sprintf("Could not fetch vote result "+
+						"for agenda with id (%v).", agenda.Vote.Id))
+			}
+			if voteResult != nil {
+				aInfo.ActivationHeight = voteResult.ActivationHeight
+				aInfo.VoteResult = agendaVoteResult(&agenda, state,
+					voteResult)
+			}
+
 			aInfo.Since = stateChangedHeight
 			aInfo.Status = state.String()
 			dInfo[agenda.Vote.Id] = aInfo
@@ -2139,6 +2950,49 @@ func handleGetBlockHeader(_ context.Context, s *Server, cmd interface{}) (interf
 	return blockHeaderReply, nil
 }
 
+// handleGetBlockStats implements the getblockstats command.
+func handleGetBlockStats(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.GetBlockStatsCmd)
+	hash, err := chainhash.NewHashFromStr(c.Hash)
+	if err != nil {
+		return nil, rpcDecodeHexError(c.Hash)
+	}
+
+	chain := s.cfg.Chain
+	blk, err := chain.BlockByHash(hash)
+	if err != nil {
+		return nil, &dcrjson.RPCError{
+			Code:    dcrjson.ErrRPCBlockNotFound,
+			Message: fmt.Sprintf("Block not found: %v", hash),
+		}
+	}
+	receiveTime, err := chain.BlockReceiveTime(hash)
+	if err != nil {
+		return nil, rpcInternalError(err.Error(),
+			"Failed to retrieve receive time")
+	}
+
+	msgBlock := blk.MsgBlock()
+	header := &msgBlock.Header
+	result := &types.GetBlockStatsResult{
+		Hash:        c.Hash,
+		Height:      int64(header.Height),
+		MainChain:   chain.MainChainHasBlock(hash),
+		Time:        header.Timestamp.Unix(),
+		Size:        int32(header.Size),
+		NumTx:       len(msgBlock.Transactions),
+		NumSTx:      len(msgBlock.STransactions),
+		Voters:      header.Voters,
+		FreshStake:  header.FreshStake,
+		Revocations: header.Revocations,
+	}
+	if !receiveTime.IsZero() {
+		result.ReceivedTime = receiveTime.Unix()
+		result.ReceiveDelay = receiveTime.Sub(header.Timestamp).Seconds()
+	}
+	return result, nil
+}
+
 // handleGetBlockSubsidy implements the getblocksubsidy command.
 func handleGetBlockSubsidy(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
 	c := cmd.(*types.GetBlockSubsidyCmd)
@@ -2161,6 +3015,304 @@ func handleGetBlockSubsidy(_ context.Context, s *Server, cmd interface{}) (inter
 	return rep, nil
 }
 
+// These constants define the modes of the getblocktemplate command.
+const (
+	gbtModeTemplate = "template"
+	gbtModeProposal = "proposal"
+)
+
+// gbtCapabilities describes the optional getblocktemplate capabilities
+// supported by the server.
+var gbtCapabilities = []string{"longpoll", "proposal"}
+
+// gbtLongPollTimeout is the maximum amount of time a getblocktemplate long
+// poll waits for a new template before responding with the current one.
+const gbtLongPollTimeout = 5 * time.Minute
+
+// templateLongPollID returns the long poll id for the provided block template.
+// It changes whenever a template with a different header is generated.
+func templateLongPollID(template *mining.BlockTemplate) string {
+	return template.Block.Header.BlockHash().String()
+}
+
+// handleGetBlockTemplate implements the getblocktemplate command.
+func handleGetBlockTemplate(ctx context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.GetBlockTemplateCmd)
+	request := c.Request
+	if request == nil {
+		request = &types.TemplateRequest{}
+	}
+
+	switch request.Mode {
+	case "", gbtModeTemplate:
+		return handleGetBlockTemplateRequest(ctx, s, request)
+
+	case gbtModeProposal:
+		if request.Data == "" {
+			return nil, rpcInvalidError("Data must contain the " +
+				"hex-encoded serialized block that is being proposed")
+		}
+		result, err := proposeBlock(s, request.Data)
+		if err != nil {
+			return nil, err
+		}
+		if !result.Valid {
+			return fmt.Sprintf("rejected: %s", result.Reason), nil
+		}
+		return nil, nil
+	}
+
+	return nil, rpcInvalidError("Invalid mode %q", request.Mode)
+}
+
+// handleGetBlockTemplateRequest is a helper for handleGetBlockTemplate which
+// deals with generating and returning block templates to the caller.  When the
+// request has a long poll id that matches the current template, it waits for a
+// new template to be generated before returning.
+func handleGetBlockTemplateRequest(ctx context.Context, s *Server, request *types.TemplateRequest) (interface{}, error) {
+	bt := s.cfg.BlockTemplater
+	if bt == nil {
+		return nil, rpcInternalError("Node is not configured for mining", "")
+	}
+
+	// Return an error if there are no peers connected since there is no way to
+	// relay a found block or receive transactions to work on unless
+	// unsynchronized mining has specifically been allowed.
+	if !s.cfg.AllowUnsyncedMining && s.cfg.ConnMgr.ConnectedCount() == 0 {
+		return nil, &dcrjson.RPCError{
+			Code:    dcrjson.ErrRPCClientNotConnected,
+			Message: "Decred is not connected",
+		}
+	}
+
+	// No point in generating templates before the chain is synced unless
+	// unsynchronized mining has specifically been allowed.
+	bestHeight := s.cfg.Chain.BestSnapshot().Height
+	if !s.cfg.AllowUnsyncedMining && bestHeight != 0 && !s.cfg.Chain.IsCurrent() {
+		return nil, &dcrjson.RPCError{
+			Code:    dcrjson.ErrRPCClientInInitialDownload,
+			Message: "Decred is downloading blocks...",
+		}
+	}
+
+	// Wait for a template with a different long poll id when the caller
+	// provided one.  Since the subscription immediately sends the current
+	// template, this returns right away when the caller is not up to date.
+	var template *mining.BlockTemplate
+	if request.LongPollID != "" {
+		templateSub := bt.Subscribe()
+		timeout := time.NewTimer(gbtLongPollTimeout)
+	waitLoop:
+		for {
+			select {
+			case templateNtfn := <-templateSub.C():
+				if templateNtfn.Template == nil {
+					continue
+				}
+				template = templateNtfn.Template
+				if templateLongPollID(template) != request.LongPollID {
+					break waitLoop
+				}
+
+			case <-timeout.C:
+				break waitLoop
+
+			case <-ctx.Done():
+				timeout.Stop()
+				templateSub.Stop()
+				return nil, rpcMiscError("Long poll was canceled")
+			}
+		}
+		timeout.Stop()
+		templateSub.Stop()
+	}
+	if template == nil {
+		var err error
+		template, err = bt.CurrentTemplate()
+		if err != nil {
+			context := "Unable to retrieve block template"
+			return nil, rpcInternalError(err.Error(), context)
+		}
+		if template == nil {
+			return nil, rpcInternalError("No block template is available",
+				"")
+		}
+	}
+
+	// Update the time of the block template to the current time while
+	// accounting for the median time of the past several blocks per the chain
+	// consensus rules.  Note that the header is copied to avoid mutating the
+	// shared block template.
+	headerCopy := template.Block.Header
+	err := bt.UpdateBlockTime(&headerCopy)
+	if err != nil {
+		context := "Failed to update block time"
+		return nil, rpcInternalError(err.Error(), context)
+	}
+	headerBytes, err := headerCopy.Bytes()
+	if err != nil {
+		context := "Failed to serialize block header"
+		return nil, rpcInternalError(err.Error(), context)
+	}
+
+	// templateTxns returns the provided transactions in the form they are
+	// returned to the caller.
+	templateTxns := func(txns []*wire.MsgTx) ([]types.GetBlockTemplateResultTx, error) {
+		results := make([]types.GetBlockTemplateResultTx, 0, len(txns))
+		for _, tx := range txns {
+			txBytes, err := tx.Bytes()
+			if err != nil {
+				context := "Failed to serialize transaction"
+				return nil, rpcInternalError(err.Error(), context)
+			}
+			results = append(results, types.GetBlockTemplateResultTx{
+				Data: hex.EncodeToString(txBytes),
+				Hash: tx.TxHash().String(),
+			})
+		}
+		return results, nil
+	}
+	txns, err := templateTxns(template.Block.Transactions)
+	if err != nil {
+		return nil, err
+	}
+	stxns, err := templateTxns(template.Block.STransactions)
+	if err != nil {
+		return nil, err
+	}
+
+	target := standalone.CompactToBig(headerCopy.Bits)
+	return &types.GetBlockTemplateResult{
+		Header:        hex.EncodeToString(headerBytes),
+		Transactions:  txns,
+		STransactions: stxns,
+		Height:        int64(headerCopy.Height),
+		CurTime:       headerCopy.Timestamp.Unix(),
+		Target:        fmt.Sprintf("%064x", target),
+		LongPollID:    templateLongPollID(template),
+		Capabilities:  gbtCapabilities,
+	}, nil
+}
+
+// handleGetChainParams implements the getchainparams command.
+func handleGetChainParams(_ context.Context, s *Server, _ interface{}) (interface{}, error) {
+	params := s.cfg.ChainParams
+	seconds := func(d time.Duration) int64 {
+		return int64(d / time.Second)
+	}
+	result := &types.GetChainParamsResult{
+		Name:             params.Name,
+		Net:              uint32(params.Net),
+		DefaultPort:      params.DefaultPort,
+		GenesisHash:      params.GenesisHash.String(),
+		MaxBlockSizes:    params.MaximumBlockSizes,
+		MaxTxSize:        params.MaxTxSize,
+		CoinbaseMaturity: params.CoinbaseMaturity,
+		PoW: types.ChainParamsPoW{
+			PowLimit:                 fmt.Sprintf("%064x", params.PowLimit),
+			PowLimitBits:             params.PowLimitBits,
+			ReduceMinDifficulty:      params.ReduceMinDifficulty,
+			MinDiffReductionTime:     seconds(params.MinDiffReductionTime),
+			TargetTimePerBlock:       seconds(params.TargetTimePerBlock),
+			TargetTimespan:           seconds(params.TargetTimespan),
+			RetargetAdjustmentFactor: params.RetargetAdjustmentFactor,
+			WorkDiffAlpha:            params.WorkDiffAlpha,
+			WorkDiffWindowSize:       params.WorkDiffWindowSize,
+			WorkDiffWindows:          params.WorkDiffWindows,
+		},
+		Subsidy: types.ChainParamsSubsidy{
+			BaseSubsidy:              params.BaseSubsidy,
+			MulSubsidy:               params.MulSubsidy,
+			DivSubsidy:               params.DivSubsidy,
+			SubsidyReductionInterval: params.SubsidyReductionInterval,
+			WorkRewardProportion:     params.WorkRewardProportion,
+			StakeRewardProportion:    params.StakeRewardProportion,
+			BlockTaxProportion:       params.BlockTaxProportion,
+		},
+		Stake: types.ChainParamsStake{
+			MinimumStakeDiff:        params.MinimumStakeDiff,
+			TicketPoolSize:          params.TicketPoolSize,
+			TicketsPerBlock:         params.TicketsPerBlock,
+			TicketMaturity:          params.TicketMaturity,
+			TicketExpiry:            params.TicketExpiry,
+			SStxChangeMaturity:      params.SStxChangeMaturity,
+			TicketPoolSizeWeight:    params.TicketPoolSizeWeight,
+			StakeDiffAlpha:          params.StakeDiffAlpha,
+			StakeDiffWindowSize:     params.StakeDiffWindowSize,
+			StakeDiffWindows:        params.StakeDiffWindows,
+			StakeVersionInterval:    params.StakeVersionInterval,
+			MaxFreshStakePerBlock:   params.MaxFreshStakePerBlock,
+			StakeEnabledHeight:      params.StakeEnabledHeight,
+			StakeValidationHeight:   params.StakeValidationHeight,
+			StakeMajorityMultiplier: params.StakeMajorityMultiplier,
+			StakeMajorityDivisor:    params.StakeMajorityDivisor,
+		},
+		AddressPrefixes: types.ChainParamsAddressPrefixes{
+			NetworkAddressPrefix: params.NetworkAddressPrefix,
+			PubKeyAddrID:         hex.EncodeToString(params.PubKeyAddrID[:]),
+			PubKeyHashAddrID:     hex.EncodeToString(params.PubKeyHashAddrID[:]),
+			PKHEdwardsAddrID:     hex.EncodeToString(params.PKHEdwardsAddrID[:]),
+			PKHSchnorrAddrID:     hex.EncodeToString(params.PKHSchnorrAddrID[:]),
+			ScriptHashAddrID:     hex.EncodeToString(params.ScriptHashAddrID[:]),
+			PrivateKeyID:         hex.EncodeToString(params.PrivateKeyID[:]),
+			HDPrivateKeyID:       hex.EncodeToString(params.HDPrivateKeyID[:]),
+			HDPublicKeyID:        hex.EncodeToString(params.HDPublicKeyID[:]),
+			SLIP0044CoinType:     params.SLIP0044CoinType,
+			LegacyCoinType:       params.LegacyCoinType,
+		},
+		RuleChange: types.ChainParamsRuleChange{
+			Quorum:     params.RuleChangeActivationQuorum,
+			Multiplier: params.RuleChangeActivationMultiplier,
+			Divisor:    params.RuleChangeActivationDivisor,
+			Interval:   params.RuleChangeActivationInterval,
+		},
+		Deployments: make([]types.ChainParamsDeployment, 0,
+			len(params.Deployments)),
+	}
+
+	// Add the deployments ordered by their stake version.
+	versions := make([]uint32, 0, len(params.Deployments))
+	for version := range params.Deployments {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i] < versions[j]
+	})
+	for _, version := range versions {
+		deployments := params.Deployments[version]
+		agendas := make([]types.ChainParamsAgenda, 0, len(deployments))
+		for i := range deployments {
+			deployment := &deployments[i]
+			vote := &deployment.Vote
+			choices := make([]types.ChainParamsChoice, 0, len(vote.Choices))
+			for _, choice := range vote.Choices {
+				choices = append(choices, types.ChainParamsChoice{
+					ID:          choice.Id,
+					Description: choice.Description,
+					Bits:        choice.Bits,
+					IsAbstain:   choice.IsAbstain,
+					IsNo:        choice.IsNo,
+				})
+			}
+			agendas = append(agendas, types.ChainParamsAgenda{
+				ID:          vote.Id,
+				Description: vote.Description,
+				Mask:        vote.Mask,
+				StartTime:   deployment.StartTime,
+				ExpireTime:  deployment.ExpireTime,
+				Choices:     choices,
+			})
+		}
+		result.Deployments = append(result.Deployments,
+			types.ChainParamsDeployment{
+				Version: version,
+				Agendas: agendas,
+			})
+	}
+
+	return result, nil
+}
+
 // handleGetChainTips implements the getchaintips command.
 func handleGetChainTips(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
 	chainTips := s.cfg.Chain.ChainTips()
@@ -2197,6 +3349,31 @@ func handleGetDifficulty(_ context.Context, s *Server, cmd interface{}) (interfa
 	return getDifficultyRatio(best.Bits, s.cfg.ChainParams), nil
 }
 
+// handleGetDiskSpaceInfo implements the getdiskspaceinfo command.
+func handleGetDiskSpaceInfo(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	if s.cfg.DiskSpaceMonitor == nil {
+		return nil, &dcrjson.RPCError{
+			Code:    dcrjson.ErrRPCMisc,
+			Message: "Disk space monitoring is not available",
+		}
+	}
+
+	status, err := s.cfg.DiskSpaceMonitor.DiskSpace()
+	if err != nil {
+		context := "Failed to query disk space"
+		return nil, rpcInternalError(err.Error(), context)
+	}
+
+	return &types.GetDiskSpaceInfoResult{
+		Path:          status.Path,
+		Free:          status.Free,
+		Total:         status.Total,
+		WarnThreshold: status.WarnThreshold,
+		StopThreshold: status.StopThreshold,
+		Level:         status.Level,
+	}, nil
+}
+
 // handleGetGenerate implements the getgenerate command.
 func handleGetGenerate(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
 	return s.cfg.CPUMiner.IsMining(), nil
@@ -2406,27 +3583,93 @@ func handleGetInfo(_ context.Context, s *Server, cmd interface{}) (interface{},
 
 // handleGetMempoolInfo implements the getmempoolinfo command.
 func handleGetMempoolInfo(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
-	mempoolTxns := s.cfg.TxMempooler.TxDescs()
+	stats := s.cfg.TxMempooler.Stats()
 
-	var numBytes int64
-	for _, txD := range mempoolTxns {
-		numBytes += int64(txD.Tx.MsgTx().SerializeSize())
+	// mempoolWatermark converts the provided mempool watermarks to the result
+	// type while converting the minimum fee rate from atoms/kB to DCR/kB.
+	mempoolWatermark := func(wm *mempool.PoolWatermarks) types.GetMempoolInfoWatermark {
+		result := types.GetMempoolInfoWatermark{
+			MaxSize:  int64(wm.MaxCount),
+			MaxBytes: wm.MaxBytes,
+			Evicted:  wm.NumEvicted,
+		}
+		if wm.MinFeeRate >= 0 {
+			minFeeRate := dcrutil.Amount(wm.MinFeeRate).ToCoin()
+			result.MinFeeRate = &minFeeRate
+		}
+		return result
 	}
 
 	ret := &types.GetMempoolInfoResult{
-		Size:  int64(len(mempoolTxns)),
-		Bytes: numBytes,
+		Size:     int64(stats.Count),
+		Bytes:    stats.Bytes,
+		Evicted:  stats.NumEvicted,
+		LastHour: mempoolWatermark(&stats.LastHour),
+		LastDay:  mempoolWatermark(&stats.LastDay),
 	}
 
 	return ret, nil
 }
 
+// handleGetMempoolReplacements implements the getmempoolreplacements command.
+func handleGetMempoolReplacements(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	history := s.cfg.TxMempooler.Replacements()
+
+	// Convert the decisions to the result type while converting the fees and
+	// fee rates from atoms and atoms/kB to DCR and DCR/kB.
+	replacements := make([]types.MempoolReplacementResult, 0,
+		len(history.Decisions))
+	for i := range history.Decisions {
+		decision := &history.Decisions[i]
+		conflicts := make([]string, 0, len(decision.Conflicts))
+		for j := range decision.Conflicts {
+			conflicts = append(conflicts, decision.Conflicts[j].String())
+		}
+		replacements = append(replacements, types.MempoolReplacementResult{
+			Time:       decision.Time.Unix(),
+			TxID:       decision.Hash.String(),
+			Conflicts:  conflicts,
+			Evicted:    int64(decision.NumEvicted),
+			Fee:        dcrutil.Amount(decision.Fee).ToCoin(),
+			FeeRate:    dcrutil.Amount(decision.FeeRate).ToCoin(),
+			MinFee:     dcrutil.Amount(decision.MinFee).ToCoin(),
+			MinFeeRate: dcrutil.Amount(decision.MinFeeRate).ToCoin(),
+			Accepted:   decision.Accepted,
+			Reason:     decision.Reason,
+		})
+	}
+
+	return &types.GetMempoolReplacementsResult{
+		Enabled:            history.Enabled,
+		MinFeeRateIncrease: history.MinFeeRateIncrease,
+		Replacements:       replacements,
+	}, nil
+}
+
+// handleGetMiningAddrs implements the getminingaddrs command.
+func handleGetMiningAddrs(_ context.Context, s *Server, _ interface{}) (interface{}, error) {
+	bt := s.cfg.BlockTemplater
+	if bt == nil {
+		return nil, rpcInternalError("Node is not configured for mining", "")
+	}
+
+	addrs, policy := bt.MiningAddrs()
+	result := &types.GetMiningAddrsResult{
+		Addresses: make([]string, 0, len(addrs)),
+		Policy:    policy.String(),
+	}
+	for _, addr := range addrs {
+		result.Addresses = append(result.Addresses, addr.Address())
+	}
+	return result, nil
+}
+
 // handleGetMiningInfo implements the getmininginfo command. We only return the
 // fields that are not related to wallet functionality.
 func handleGetMiningInfo(ctx context.Context, s *Server, cmd interface{}) (interface{}, error) {
 	// Create a default getnetworkhashps command to use defaults and make
 	// use of the existing getnetworkhashps handler.
-	gnhpsCmd := types.NewGetNetworkHashPSCmd(nil, nil)
+	gnhpsCmd := types.NewGetNetworkHashPSCmd(nil, nil, nil, nil)
 	networkHashesPerSecIface, err := handleGetNetworkHashPS(ctx, s, gnhpsCmd)
 	if err != nil {
 		return nil, err
@@ -2469,9 +3712,37 @@ func handleGetNetTotals(_ context.Context, s *Server, cmd interface{}) (interfac
 		TotalBytesSent: totalBytesSent,
 		TimeMillis:     s.cfg.Clock.Now().UTC().UnixNano() / int64(time.Millisecond),
 	}
+	if stats, ok := s.cfg.ConnMgr.TxReconStats(); ok {
+		reply.TxRecon = &types.TxReconStatsResult{
+			Rounds:     stats.Rounds,
+			Failures:   stats.Failures,
+			ReconBytes: stats.ReconBytes,
+			FloodBytes: stats.FloodBytes,
+		}
+	}
+	priority, regular := s.cfg.SyncMgr.TxRelayStats()
+	reply.TxRelay = &types.TxRelayStatsResult{
+		Priority: txRelayLaneResult(&priority),
+		Regular:  txRelayLaneResult(&regular),
+	}
 	return reply, nil
 }
 
+// txRelayLaneResult converts the provided relay lane statistics to the
+// representation returned by the getnettotals command.
+func txRelayLaneResult(stats *TxRelayLaneStats) types.TxRelayLaneResult {
+	const millisPerDuration = float64(time.Millisecond)
+	result := types.TxRelayLaneResult{
+		Count:            stats.Count,
+		MaxLatencyMillis: float64(stats.MaxLatency) / millisPerDuration,
+	}
+	if stats.Count > 0 {
+		avgLatency := float64(stats.TotalLatency) / float64(stats.Count)
+		result.AvgLatencyMillis = avgLatency / millisPerDuration
+	}
+	return result
+}
+
 // handleGetNetworkHashPS implements the getnetworkhashps command.
 func handleGetNetworkHashPS(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
 	// Note: All valid error return paths should return an int64.  Literal
@@ -2480,6 +3751,36 @@ func handleGetNetworkHashPS(_ context.Context, s *Server, cmd interface{}) (inte
 
 	c := cmd.(*types.GetNetworkHashPSCmd)
 
+	// Determine the estimation method and create the result in the requested
+	// format.
+	method := hashRateMethodWindow
+	if c.Method != nil {
+		method = *c.Method
+	}
+	switch method {
+	case hashRateMethodWindow, hashRateMethodEMA:
+	default:
+		return nil, rpcInvalidError("Unknown estimation method %q -- "+
+			"supported methods are %q and %q", method, hashRateMethodWindow,
+			hashRateMethodEMA)
+	}
+	verbose := c.Verbose != nil && *c.Verbose
+	result := func(hashesPerSec, startHeight, endHeight int64) interface{} {
+		if !verbose {
+			return hashesPerSec
+		}
+		lower, upper := hashRateInterval(hashesPerSec, endHeight-startHeight)
+		return &types.GetNetworkHashPSResult{
+			HashesPerSec: hashesPerSec,
+			Lower:        lower,
+			Upper:        upper,
+			Confidence:   hashRateConfidence,
+			Method:       method,
+			StartHeight:  startHeight,
+			EndHeight:    endHeight,
+		}
+	}
+
 	// When the passed height is too high or zero, just return 0 now since
 	// we can't reasonably calculate the number of network hashes per
 	// second from invalid values.  When it's negative, use the current
@@ -2491,7 +3792,7 @@ func handleGetNetworkHashPS(_ context.Context, s *Server, cmd interface{}) (inte
 		endHeight = int64(*c.Height)
 	}
 	if endHeight > best.Height || endHeight == 0 {
-		return int64(0), nil
+		return result(0, 0, 0), nil
 	}
 	if endHeight < 0 {
 		endHeight = best.Height
@@ -2521,13 +3822,12 @@ func handleGetNetworkHashPS(_ context.Context, s *Server, cmd interface{}) (inte
 	if startHeight < 0 {
 		startHeight = 0
 	}
-	log.Debugf("Calculating network hashes per second from %d to %d",
-		startHeight, endHeight)
+	log.Debugf("Calculating network hashes per second from %d to %d using "+
+		"the %s method", startHeight, endHeight, method)
 
-	// Find the min and max block timestamps as well as calculate the total
-	// amount of work that happened between the start and end blocks.
-	var minTimestamp, maxTimestamp time.Time
-	totalWork := big.NewInt(0)
+	// Collect the work and timestamp of all blocks between the start and end
+	// blocks.
+	samples := make([]hashRateSample, 0, endHeight-startHeight+1)
 	for curHeight := startHeight; curHeight <= endHeight; curHeight++ {
 		hash, err := chain.BlockHashByHeight(curHeight)
 		if err != nil {
@@ -2538,35 +3838,24 @@ func handleGetNetworkHashPS(_ context.Context, s *Server, cmd interface{}) (inte
 		// Fetch the header from chain.
 		header, err := chain.HeaderByHash(hash)
 		if err != nil {
-			context := "Failed to fetch block header"
-			return nil, rpcInternalError(err.Error(), context)
-		}
-
-		if curHeight == startHeight {
-			minTimestamp = header.Timestamp
-			maxTimestamp = minTimestamp
-		} else {
-			totalWork.Add(totalWork, standalone.CalcWork(header.Bits))
-
-			if minTimestamp.After(header.Timestamp) {
-				minTimestamp = header.Timestamp
-			}
-			if maxTimestamp.Before(header.Timestamp) {
-				maxTimestamp = header.Timestamp
-			}
+			context := "Failed to fetch block header"
+			return nil, rpcInternalError(err.Error(), context)
 		}
-	}
 
-	// Calculate the difference in seconds between the min and max block
-	// timestamps and avoid division by zero in the case where there is no
-	// time difference.
-	timeDiff := int64(maxTimestamp.Sub(minTimestamp) / time.Second)
-	if timeDiff == 0 {
-		return int64(0), nil
+		samples = append(samples, hashRateSample{
+			work:      standalone.CalcWork(header.Bits),
+			timestamp: header.Timestamp,
+		})
 	}
 
-	hashesPerSec := new(big.Int).Div(totalWork, big.NewInt(timeDiff))
-	return hashesPerSec.Int64(), nil
+	var hashesPerSec int64
+	switch method {
+	case hashRateMethodEMA:
+		hashesPerSec = emaHashRate(samples)
+	default:
+		hashesPerSec = windowHashRate(samples)
+	}
+	return result(hashesPerSec, startHeight, endHeight), nil
 }
 
 // handleGetNetworkInfo implements the getnetworkinfo command.
@@ -2625,6 +3914,32 @@ func handleGetPeerInfo(_ context.Context, s *Server, cmd interface{}) (interface
 			BanScore:       int32(p.BanScore()),
 			SyncNode:       p.ID() == syncPeerID,
 		}
+		if statsSnap.IdentityKey != nil {
+			info.IdentityKey = hex.EncodeToString(
+				statsSnap.IdentityKey.SerializeCompressed())
+		}
+		if len(statsSnap.MsgStats) > 0 {
+			info.MsgStats = make(map[string]types.PeerMsgStats,
+				len(statsSnap.MsgStats))
+			for command, stats := range statsSnap.MsgStats {
+				info.MsgStats[command] = types.PeerMsgStats{
+					Received:    stats.Received,
+					BytesRecv:   stats.BytesRecv,
+					Oversized:   stats.Oversized,
+					RateLimited: stats.RateLimited,
+				}
+			}
+		}
+		if tcpInfo := p.TCPInfo(); tcpInfo != nil {
+			info.TCPInfo = &types.PeerTCPInfo{
+				RTT:          uint32(tcpInfo.RTT / time.Microsecond),
+				RTTVar:       uint32(tcpInfo.RTTVar / time.Microsecond),
+				Retransmits:  tcpInfo.Retransmits,
+				TotalRetrans: tcpInfo.TotalRetrans,
+				SndCwnd:      tcpInfo.SndCwnd,
+				SampleTime:   tcpInfo.SampleTime.Unix(),
+			}
+		}
 		if p.LastPingNonce() != 0 {
 			wait := float64(s.cfg.Clock.Since(statsSnap.LastPingTime).Nanoseconds())
 			// We actually want microseconds.
@@ -2710,6 +4025,11 @@ func handleGetRawMempool(_ context.Context, s *Server, cmd interface{}) (interfa
 		}
 		hashStrings = append(hashStrings, descs[i].Tx.Hash().String())
 	}
+
+	// The mempool does not provide the transactions in a stable order, so
+	// sort the hashes to ensure the same mempool contents always result in
+	// the same response.
+	sort.Strings(hashStrings)
 	return hashStrings, nil
 }
 
@@ -2834,6 +4154,130 @@ func handleGetRawTransaction(_ context.Context, s *Server, cmd interface{}) (int
 	return *rawTxn, nil
 }
 
+// handleGetRejectedTransactions implements the getrejectedtransactions command.
+func handleGetRejectedTransactions(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.GetRejectedTransactionsCmd)
+
+	rejected := s.cfg.SyncMgr.RejectedTransactions()
+	if c.Count != nil {
+		if *c.Count < 0 {
+			return nil, rpcInvalidError("Count must not be negative")
+		}
+		if int(*c.Count) < len(rejected) {
+			rejected = rejected[:*c.Count]
+		}
+	}
+
+	result := make([]types.GetRejectedTransactionsResult, 0, len(rejected))
+	for i := range rejected {
+		tx := &rejected[i]
+		result = append(result, types.GetRejectedTransactionsResult{
+			TxID:   tx.Hash.String(),
+			Code:   tx.Code.String(),
+			Reason: tx.Reason,
+			Time:   tx.Time.Unix(),
+			Height: tx.Height,
+		})
+	}
+	return result, nil
+}
+
+// signalingBits returns the number of blocks or votes that set each bit with a
+// nonzero count along with the percentage of the provided total they represent.
+func signalingBits(counts []uint32, total uint32) []types.SignalingBit {
+	bits := make([]types.SignalingBit, 0, len(counts))
+	for bit, count := range counts {
+		if count == 0 {
+			continue
+		}
+		bits = append(bits, types.SignalingBit{
+			Bit:     uint8(bit),
+			Count:   count,
+			Percent: float64(count) * 100 / float64(total),
+		})
+	}
+	return bits
+}
+
+// handleGetSignalingStats implements the getsignalingstats command.
+func handleGetSignalingStats(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.GetSignalingStatsCmd)
+
+	chain := s.cfg.Chain
+	hash := &chain.BestSnapshot().Hash
+	if c.Hash != nil {
+		var err error
+		hash, err = chainhash.NewHashFromStr(*c.Hash)
+		if err != nil {
+			return nil, rpcDecodeHexError(*c.Hash)
+		}
+	}
+
+	// Default to the number of blocks in a rule change interval since that is
+	// the window agenda votes are tallied over.
+	numBlocks := int32(s.cfg.ChainParams.RuleChangeActivationInterval)
+	if c.Blocks != nil {
+		numBlocks = *c.Blocks
+		if numBlocks <= 0 {
+			return nil, rpcInvalidError("Blocks must be > 0")
+		}
+	}
+
+	sv, err := chain.GetStakeVersions(hash, numBlocks)
+	if err != nil {
+		return nil, rpcInternalError(err.Error(),
+			"Could not obtain stake versions")
+	}
+
+	// Tally the block versions and the bits they set along with the versions
+	// and bits of the votes in each block.  The vote bits are only tallied for
+	// the votes with the requested version when one is provided.
+	result := types.GetSignalingStatsResult{
+		Hash:             hash.String(),
+		BlockVersions:    []types.VersionCount{},
+		BlockVersionBits: []types.SignalingBit{},
+		VoteVersions:     []types.VersionCount{},
+		VoteBits:         []types.SignalingBit{},
+	}
+	blockVersions := make(map[int]int)
+	voteVersions := make(map[int]int)
+	var blockVersionBits [32]uint32
+	var voteBits [16]uint32
+	for _, v := range sv {
+		blockVersions[int(v.BlockVersion)]++
+		for bit := range blockVersionBits {
+			if uint32(v.BlockVersion)&(1<<uint(bit)) != 0 {
+				blockVersionBits[bit]++
+			}
+		}
+		for _, vote := range v.Votes {
+			voteVersions[int(vote.Version)]++
+			if c.VoteVersion != nil && vote.Version != *c.VoteVersion {
+				continue
+			}
+			result.NumVotes++
+			for bit := range voteBits {
+				if vote.Bits&(1<<uint(bit)) != 0 {
+					voteBits[bit]++
+				}
+			}
+		}
+	}
+	if len(sv) == 0 {
+		return result, nil
+	}
+
+	result.StartHeight = sv[len(sv)-1].Height
+	result.EndHeight = sv[0].Height
+	result.NumBlocks = uint32(len(sv))
+	result.BlockVersions = convertVersionMap(blockVersions)
+	result.BlockVersionBits = signalingBits(blockVersionBits[:],
+		result.NumBlocks)
+	result.VoteVersions = convertVersionMap(voteVersions)
+	result.VoteBits = signalingBits(voteBits[:], result.NumVotes)
+	return result, nil
+}
+
 // handleGetStakeDifficulty implements the getstakedifficulty command.
 func handleGetStakeDifficulty(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
 	chain := s.cfg.Chain
@@ -2960,6 +4404,26 @@ func handleGetStakeVersionInfo(_ context.Context, s *Server, cmd interface{}) (i
 	return result, nil
 }
 
+// aggregateStakeVersions returns the stake and vote versions of the provided
+// blocks aggregated into a version interval.  The blocks must be ordered from
+// newest to oldest as returned by GetStakeVersions.
+func aggregateStakeVersions(sv []blockchain.StakeVersions) types.VersionInterval {
+	posVersions := make(map[int]int)
+	voteVersions := make(map[int]int)
+	for _, v := range sv {
+		posVersions[int(v.StakeVersion)]++
+		for _, vote := range v.Votes {
+			voteVersions[int(vote.Version)]++
+		}
+	}
+	return types.VersionInterval{
+		StartHeight:  sv[len(sv)-1].Height,
+		EndHeight:    sv[0].Height,
+		PoSVersions:  convertVersionMap(posVersions),
+		VoteVersions: convertVersionMap(voteVersions),
+	}
+}
+
 // handleGetStakeVersions implements the getstakeversions command.
 func handleGetStakeVersions(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
 	c := cmd.(*types.GetStakeVersionsCmd)
@@ -2972,16 +4436,72 @@ func handleGetStakeVersions(_ context.Context, s *Server, cmd interface{}) (inte
 		return nil, rpcInvalidError("Invalid parameter, count must " +
 			"be > 0")
 	}
+	var intervalSize int32
+	if c.IntervalSize != nil {
+		intervalSize = *c.IntervalSize
+		if intervalSize < 0 {
+			return nil, rpcInvalidError("Invalid parameter, interval " +
+				"size must not be negative")
+		}
+	}
+
+	// fetchPage returns the stake versions of up to the requested number of
+	// blocks starting with the provided hash along with the hash of the block
+	// that precedes them, if any, so the caller can continue from there.
+	chain := s.cfg.Chain
+	fetchPage := func(hash *chainhash.Hash, count int32) ([]blockchain.StakeVersions, *chainhash.Hash, error) {
+		sv, err := chain.GetStakeVersions(hash, count+1)
+		if err != nil {
+			return nil, nil, rpcInternalError(err.Error(),
+				"Could not obtain stake versions")
+		}
+		if int32(len(sv)) <= count {
+			return sv, nil, nil
+		}
+		return sv[:count], &sv[count].Hash, nil
+	}
+
+	// Aggregate the versions into intervals when requested.  The blocks are
+	// fetched one interval at a time to avoid holding the stake versions of
+	// every requested block in memory at once.
+	if intervalSize > 0 {
+		result := types.GetStakeVersionsResult{
+			StakeVersions: []types.StakeVersions{},
+		}
+		for remaining := c.Count; remaining > 0 && hash != nil; {
+			count := intervalSize
+			if count > remaining {
+				count = remaining
+			}
+			sv, next, err := fetchPage(hash, count)
+			if err != nil {
+				return nil, err
+			}
+			if len(sv) == 0 {
+				break
+			}
+			result.Intervals = append(result.Intervals,
+				aggregateStakeVersions(sv))
+			remaining -= int32(len(sv))
+			hash = next
+		}
+		if hash != nil {
+			result.NextHash = hash.String()
+		}
+		return result, nil
+	}
 
-	sv, err := s.cfg.Chain.GetStakeVersions(hash, c.Count)
+	sv, next, err := fetchPage(hash, c.Count)
 	if err != nil {
-		return nil, rpcInternalError(err.Error(),
-			"Could not obtain stake versions")
+		return nil, err
 	}
 
 	result := types.GetStakeVersionsResult{
 		StakeVersions: make([]types.StakeVersions, 0, len(sv)),
 	}
+	if next != nil {
+		result.NextHash = next.String()
+	}
 	for _, v := range sv {
 		nsv := types.StakeVersions{
 			Hash:         v.Hash.String(),
@@ -3002,6 +4522,141 @@ func handleGetStakeVersions(_ context.Context, s *Server, cmd interface{}) (inte
 	return result, nil
 }
 
+// handleGetStandardPolicy implements the getstandardpolicy command.
+func handleGetStandardPolicy(_ context.Context, s *Server, _ interface{}) (interface{}, error) {
+	policy, err := s.cfg.TxMempooler.StandardPolicy()
+	if err != nil {
+		return nil, rpcInternalError(err.Error(),
+			"Could not determine standardness policy")
+	}
+
+	scriptVersions := make([]types.StandardScriptVersionResult, 0,
+		len(policy.ScriptVersions))
+	for i := range policy.ScriptVersions {
+		status := &policy.ScriptVersions[i]
+		classes := make([]string, 0, len(status.Classes))
+		for _, class := range status.Classes {
+			classes = append(classes, class.String())
+		}
+		scriptVersions = append(scriptVersions,
+			types.StandardScriptVersionResult{
+				Version:         status.Version,
+				Agenda:          status.Agenda,
+				Active:          status.Active,
+				Classes:         classes,
+				MaxMultiSigKeys: status.MaxMultiSigKeys,
+				MaxP2SHSigOps:   status.MaxP2SHSigOps,
+			})
+	}
+
+	return &types.GetStandardPolicyResult{
+		AcceptNonStd:       policy.AcceptNonStd,
+		MaxTxVersion:       policy.MaxTxVersion,
+		MaxTxSize:          policy.MaxTxSize,
+		MaxSigScriptSize:   policy.MaxSigScriptSize,
+		MaxNullDataOutputs: policy.MaxNullDataOutputs,
+		ScriptVersions:     scriptVersions,
+	}, nil
+}
+
+// handleGetSyncPeer implements the getsyncpeer command.
+func handleGetSyncPeer(_ context.Context, s *Server, _ interface{}) (interface{}, error) {
+	info := s.cfg.SyncMgr.SyncPeerInfo()
+	result := &types.GetSyncPeerResult{
+		ID:             info.ID,
+		Addr:           info.Addr,
+		SyncHeight:     info.SyncHeight,
+		BlocksInFlight: info.BlocksInFlight,
+		Switches:       make([]types.SyncPeerSwitchResult, 0, len(info.Switches)),
+	}
+	if !info.Deadline.IsZero() {
+		result.Deadline = info.Deadline.Unix()
+	}
+	for i := range info.Switches {
+		sw := &info.Switches[i]
+		result.Switches = append(result.Switches, types.SyncPeerSwitchResult{
+			Time:         sw.Time.Unix(),
+			PreviousID:   sw.PrevID,
+			PreviousAddr: sw.PrevAddr,
+			ID:           sw.ID,
+			Addr:         sw.Addr,
+			Reason:       sw.Reason,
+		})
+	}
+	return result, nil
+}
+
+// stakeTxTypeString returns a human-readable name for the provided stake
+// transaction type.
+func stakeTxTypeString(txType stake.TxType) string {
+	switch txType {
+	case stake.TxTypeSStx:
+		return "ticket"
+	case stake.TxTypeSSGen:
+		return "vote"
+	case stake.TxTypeSSRtx:
+		return "revocation"
+	}
+	return "regular"
+}
+
+// handleGetTemplateDecisions implements the gettemplatedecisions command.
+func handleGetTemplateDecisions(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.GetTemplateDecisionsCmd)
+
+	bt := s.cfg.BlockTemplater
+	if bt == nil {
+		return nil, rpcInternalError("Node is not configured for mining", "")
+	}
+
+	count := int32(1)
+	if c.Count != nil {
+		count = *c.Count
+	}
+	if count < 1 || count > mining.MaxTemplateDecisions {
+		return nil, rpcInvalidError("Count must be between 1 and %d",
+			mining.MaxTemplateDecisions)
+	}
+
+	templates := bt.TemplateDecisions(int(count))
+	result := make([]types.GetTemplateDecisionsResult, 0, len(templates))
+	for _, template := range templates {
+		var selected int
+		var totalFees int64
+		txns := make([]types.TemplateTxDecisionResult, 0, len(template.Txns))
+		for i := range template.Txns {
+			txd := &template.Txns[i]
+			txResult := types.TemplateTxDecisionResult{
+				TxID:     txd.Hash.String(),
+				Type:     stakeTxTypeString(txd.Type),
+				Decision: "selected",
+				Size:     txd.Size,
+				Fee:      dcrutil.Amount(txd.Fee).ToCoin(),
+				FeeRate:  dcrutil.Amount(txd.FeePerKB()).ToCoin(),
+			}
+			if txd.Selected() {
+				selected++
+				totalFees += txd.Fee
+			} else {
+				txResult.Decision = "skipped"
+				txResult.Reason = txd.Reason.String()
+			}
+			txns = append(txns, txResult)
+		}
+		result = append(result, types.GetTemplateDecisionsResult{
+			Hash:         template.BlockHash.String(),
+			PreviousHash: template.PrevBlock.String(),
+			Height:       template.Height,
+			Time:         template.Generated.Unix(),
+			Considered:   len(template.Txns),
+			Selected:     selected,
+			TotalFees:    dcrutil.Amount(totalFees).ToCoin(),
+			Transactions: txns,
+		})
+	}
+	return result, nil
+}
+
 // handleGetTicketPoolValue implements the getticketpoolvalue command.
 func handleGetTicketPoolValue(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
 	amt, err := s.cfg.Chain.TicketPoolValue()
@@ -3013,6 +4668,43 @@ func handleGetTicketPoolValue(_ context.Context, s *Server, cmd interface{}) (in
 	return amt.ToCoin(), nil
 }
 
+// handleGetTimeInfo implements the gettimeinfo command.
+func handleGetTimeInfo(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.GetTimeInfoCmd)
+
+	// Use the median time of the current best chain tip unless a block is
+	// specified.
+	chain := s.cfg.Chain
+	best := chain.BestSnapshot()
+	hash, height, medianTime := best.Hash, best.Height, best.MedianTime
+	if c.BlockHash != nil {
+		blockHash, err := chainhash.NewHashFromStr(*c.BlockHash)
+		if err != nil {
+			return nil, rpcDecodeHexError(*c.BlockHash)
+		}
+		header, err := chain.HeaderByHash(blockHash)
+		if err == nil {
+			medianTime, err = chain.MedianTimeByHash(blockHash)
+		}
+		if err != nil {
+			return nil, &dcrjson.RPCError{
+				Code:    dcrjson.ErrRPCBlockNotFound,
+				Message: fmt.Sprintf("Block not found: %v", blockHash),
+			}
+		}
+		hash, height = *blockHash, int64(header.Height)
+	}
+
+	return &types.GetTimeInfoResult{
+		Hash:         hash.String(),
+		Height:       height,
+		MedianTime:   medianTime.Unix(),
+		LocalTime:    s.cfg.Clock.Now().Unix(),
+		AdjustedTime: s.cfg.TimeSource.AdjustedTime().Unix(),
+		TimeOffset:   int64(s.cfg.TimeSource.Offset().Seconds()),
+	}, nil
+}
+
 // handleGetVoteInfo implements the getvoteinfo command.
 func handleGetVoteInfo(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
 	c := cmd.(*types.GetVoteInfoCmd)
@@ -3023,6 +4715,14 @@ func handleGetVoteInfo(_ context.Context, s *Server, cmd interface{}) (interface
 	chain := s.cfg.Chain
 	snapshot := chain.BestSnapshot()
 
+	var count int32
+	if c.Count != nil {
+		count = *c.Count
+		if count < 0 {
+			return nil, rpcInvalidError("Count must not be negative")
+		}
+	}
+
 	vi, err := chain.GetVoteInfo(&snapshot.Hash, c.Version)
 	if err != nil {
 		var vErr blockchain.VoteVersionError
@@ -3111,6 +4811,45 @@ func handleGetVoteInfo(_ context.Context, s *Server, cmd interface{}) (interface
 		result.Agendas = append(result.Agendas, a)
 	}
 
+	// Add the vote counts for the requested number of completed prior rule
+	// change intervals.  There can't possibly be any votes in intervals prior
+	// to the stake validation height.
+	svh := s.cfg.ChainParams.StakeValidationHeight
+	endHeight := result.StartHeight - 1
+	for i := int32(0); i < count && endHeight >= svh; i++ {
+		hash, err := chain.BlockHashByHeight(endHeight)
+		if err != nil {
+			context := fmt.Sprintf("Failed to get block hash for height %d",
+				endHeight)
+			return nil, rpcInternalError(err.Error(), context)
+		}
+
+		voteInterval := types.VoteInterval{
+			StartHeight: endHeight - interval + 1,
+			EndHeight:   endHeight,
+			Hash:        hash.String(),
+			Agendas:     make([]types.AgendaVoteCounts, 0, len(vi.Agendas)),
+		}
+		for _, agenda := range vi.Agendas {
+			counts, err := chain.GetVoteCountsAt(hash, c.Version,
+				agenda.Vote.Id)
+			if err != nil {
+				return nil, rpcInternalError(err.Error(),
+					"could not obtain vote count")
+			}
+			voteInterval.Agendas = append(voteInterval.Agendas,
+				types.AgendaVoteCounts{
+					ID:           agenda.Vote.Id,
+					Total:        counts.Total,
+					TotalAbstain: counts.TotalAbstain,
+					ChoiceCounts: counts.VoteChoices,
+				})
+		}
+		result.PriorIntervals = append(result.PriorIntervals, voteInterval)
+
+		endHeight -= interval
+	}
+
 	return result, nil
 }
 
@@ -3266,9 +5005,146 @@ func handleGetTxOutSetInfo(_ context.Context, s *Server, cmd interface{}) (inter
 		DiskSize:       stats.Size,
 		TotalAmount:    stats.Total,
 		SerializedHash: stats.SerializedHash.String(),
+		UtxoSetHash:    stats.SetHash.String(),
 	}, nil
 }
 
+// handleGetTxScriptCost implements the gettxscriptcost command.
+func handleGetTxScriptCost(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.GetTxScriptCostCmd)
+
+	// Convert the provided transaction hash hex to a Hash.
+	txHash, err := chainhash.NewHashFromStr(c.Txid)
+	if err != nil {
+		return nil, rpcDecodeHexError(c.Txid)
+	}
+
+	// Try to fetch the transaction from the memory pool and if that fails,
+	// try the block database.
+	var mtx *wire.MsgTx
+	tx, err := s.cfg.TxMempooler.FetchTransaction(txHash)
+	if err == nil {
+		mtx = tx.MsgTx()
+	} else {
+		if s.cfg.TxIndexer == nil {
+			return nil, rpcInternalError("The transaction index "+
+				"must be enabled to query the blockchain "+
+				"(specify --txindex)", "Configuration")
+		}
+
+		// Look up the location of the transaction.
+		idxEntry, err := s.cfg.TxIndexer.Entry(txHash)
+		if err != nil {
+			context := "Failed to retrieve transaction location"
+			return nil, rpcInternalError(err.Error(), context)
+		}
+		if idxEntry == nil {
+			return nil, rpcNoTxInfoError(txHash)
+		}
+
+		// Load the raw transaction bytes from the database.
+		var txBytes []byte
+		err = s.cfg.DB.View(func(dbTx database.Tx) error {
+			var err error
+			txBytes, err = dbTx.FetchBlockRegion(&idxEntry.BlockRegion)
+			return err
+		})
+		if err != nil {
+			return nil, rpcNoTxInfoError(txHash)
+		}
+
+		// Deserialize the transaction
+		var msgTx wire.MsgTx
+		err = msgTx.Deserialize(bytes.NewReader(txBytes))
+		if err != nil {
+			context := "Failed to deserialize transaction"
+			return nil, rpcInternalError(err.Error(), context)
+		}
+		mtx = &msgTx
+	}
+
+	result := types.GetTxScriptCostResult{
+		Txid:   txHash.String(),
+		Inputs: make([]types.ScriptCostInputResult, 0, len(mtx.TxIn)),
+	}
+
+	// Coinbase transactions do not have any scripts to execute.
+	if standalone.IsCoinBaseTx(mtx) {
+		return result, nil
+	}
+
+	// Fetch the previous outputs referenced by the inputs.  Note that the
+	// stakebase input of votes is skipped since it does not reference a
+	// previous output.
+	prevOuts, err := fetchInputTxos(s, mtx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Execute the scripts of every input one opcode at a time with the flags
+	// used to determine if a transaction is standard, counting the executed
+	// opcodes and timing the execution.
+	scriptFlags := mempool.BaseStandardVerifyFlags
+	if s.cfg.StandardVerifyFlags != nil {
+		scriptFlags, err = s.cfg.StandardVerifyFlags()
+		if err != nil {
+			context := "Failed to retrieve script flags"
+			return nil, rpcInternalError(err.Error(), context)
+		}
+	}
+	isVote := stake.IsSSGen(mtx)
+	for txInIdx, txIn := range mtx.TxIn {
+		if isVote && txInIdx == 0 {
+			continue
+		}
+		prevOut, ok := prevOuts[txIn.PreviousOutPoint]
+		if !ok {
+			return nil, rpcNoTxInfoError(&txIn.PreviousOutPoint.Hash)
+		}
+
+		input := types.ScriptCostInputResult{
+			Index: uint32(txInIdx),
+			SigOps: txscript.GetPreciseSigOpCount(txIn.SignatureScript,
+				prevOut.PkScript),
+		}
+		start := time.Now()
+		err := executeScript(&prevOut, mtx, txInIdx, scriptFlags,
+			&input.Opcodes)
+		input.Duration = time.Since(start).Nanoseconds()
+		input.Valid = err == nil
+		if err != nil {
+			input.Error = err.Error()
+		}
+		result.Inputs = append(result.Inputs, input)
+	}
+
+	return result, nil
+}
+
+// executeScript executes the scripts of the provided transaction input that
+// spends the provided previous output with the provided flags one opcode at a
+// time and adds the number of executed opcodes to the provided count.  Scripts
+// with versions other than 0 are not executed since they currently always
+// succeed.
+func executeScript(prevOut *wire.TxOut, tx *wire.MsgTx, txInIdx int, flags txscript.ScriptFlags, numOpcodes *int64) error {
+	vm, err := txscript.NewEngine(prevOut.PkScript, tx, txInIdx, flags,
+		prevOut.Version, nil)
+	if err != nil {
+		return err
+	}
+	if prevOut.Version != 0 {
+		return nil
+	}
+	for done := false; !done; {
+		done, err = vm.Step()
+		if err != nil {
+			return err
+		}
+		*numOpcodes++
+	}
+	return vm.CheckErrorCondition(true)
+}
+
 // pruneOldBlockTemplates prunes all old block templates from the templatePool
 // map.
 //
@@ -3364,10 +5240,10 @@ func handleGetWorkRequest(s *Server) (interface{}, error) {
 	// retuned as part of the data below.
 	//
 	// For reference (0-index based, end value is exclusive):
-	// data[115:119] --> Bits
-	// data[135:139] --> Timestamp
-	// data[139:143] --> Nonce
-	// data[143:151] --> ExtraNonce
+	// data[116:120] --> Bits
+	// data[136:140] --> Timestamp
+	// data[140:144] --> Nonce
+	// data[144:152] --> ExtraNonce
 	data := make([]byte, 0, getworkDataLen)
 	buf := bytes.NewBuffer(data)
 	err = headerCopy.Serialize(buf)
@@ -3402,10 +5278,18 @@ func handleGetWorkRequest(s *Server) (interface{}, error) {
 }
 
 // handleGetWorkSubmission is a helper for handleGetWork which deals with
-// the calling submitting work to be verified and processed.
+// the calling submitting work to be verified and processed.  The outcome of the
+// submission is recorded in the getwork statistics of the client.
 //
 // This function MUST be called with the RPC workstate locked.
-func handleGetWorkSubmission(_ context.Context, s *Server, hexData string) (interface{}, error) {
+func handleGetWorkSubmission(ctx context.Context, s *Server, hexData string) (interface{}, error) {
+	// Record the submission as rejected unless it is determined to be stale
+	// or it is accepted.
+	outcome := workRejected
+	defer func(now time.Time) {
+		s.workState.stats.submitted(now, clientAddr(ctx), outcome)
+	}(s.cfg.Clock.Now())
+
 	// Ensure the provided data is sane.
 	if len(hexData)%2 != 0 {
 		hexData = "0" + hexData
@@ -3451,6 +5335,7 @@ func handleGetWorkSubmission(_ context.Context, s *Server, hexData string) (inte
 	templateKey := getWorkTemplateKey(&submittedHeader)
 	templateBlock, ok := s.workState.templatePool[templateKey]
 	if !ok || templateBlock == nil {
+		outcome = workStale
 		log.Errorf("Block submitted via getwork has no matching template "+
 			"for merkle root %s, stake root %s",
 			submittedHeader.MerkleRoot, submittedHeader.StakeRoot)
@@ -3481,12 +5366,14 @@ func handleGetWorkSubmission(_ context.Context, s *Server, hexData string) (inte
 	}
 
 	if isOrphan {
+		outcome = workStale
 		log.Infof("Block submitted via getwork rejected: an orphan "+
 			"building on parent %v", block.MsgBlock().Header.PrevBlock)
 		return false, nil
 	}
 
 	// The block was accepted.
+	outcome = workAccepted
 	log.Infof("Block submitted via getwork accepted: %s (height %d)",
 		block.Hash(), msgBlock.Header.Height)
 	return true, nil
@@ -3542,9 +5429,20 @@ func handleGetWork(ctx context.Context, s *Server, cmd interface{}) (interface{}
 	}
 
 	// No data was provided, so the caller is requesting work.
+	s.workState.stats.requested(s.cfg.Clock.Now(), clientAddr(ctx))
 	return handleGetWorkRequest(s)
 }
 
+// handleGetWorkStats implements the getworkstats command.
+func handleGetWorkStats(_ context.Context, s *Server, _ interface{}) (interface{}, error) {
+	now := s.cfg.Clock.Now()
+	s.workState.Lock()
+	clients := s.workState.stats.results(now)
+	s.workState.Unlock()
+
+	return &types.GetWorkStatsResult{Clients: clients}, nil
+}
+
 // handleHelp implements the help command.
 func handleHelp(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
 	c := cmd.(*types.HelpCmd)
@@ -3571,6 +5469,9 @@ func handleHelp(_ context.Context, s *Server, cmd interface{}) (interface{}, err
 	if _, ok := rpcHandlers[method]; !ok {
 		return nil, rpcInvalidError("Unknown method: %v", method)
 	}
+	if isExperimentalRPC(method) && !s.cfg.EnableExperimentalRPC {
+		return nil, ErrRPCExperimentalDisabled
+	}
 
 	// Get the help for the command.
 	help, err := s.helpCacher.rpcMethodHelp(method)
@@ -3581,6 +5482,81 @@ func handleHelp(_ context.Context, s *Server, cmd interface{}) (interface{}, err
 	return help, nil
 }
 
+// handleListBanned implements the listbanned command.
+func handleListBanned(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.ListBannedCmd)
+	limit := pageLimit(c.Limit)
+	var cursor string
+	if c.Cursor != nil {
+		cursor = *c.Cursor
+	}
+	positions, err := decodePageCursor(cursor, 1)
+	if err != nil {
+		return nil, err
+	}
+	offset := int(positions[0])
+
+	// Sort the banned hosts so the pages are stable.  Hosts that are banned
+	// or unbanned between requests might result in a host being skipped or
+	// repeated across pages, which is acceptable for this purpose.
+	bannedPeers := s.cfg.ConnMgr.BannedPeers()
+	hosts := make([]string, 0, len(bannedPeers))
+	for host := range bannedPeers {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	if offset > len(hosts) {
+		offset = len(hosts)
+	}
+	end := offset + limit
+	var nextCursor string
+	if end < len(hosts) {
+		nextCursor = encodePageCursor(uint32(end))
+	} else {
+		end = len(hosts)
+	}
+
+	banned := make([]types.BannedPeer, 0, end-offset)
+	for _, host := range hosts[offset:end] {
+		banned = append(banned, types.BannedPeer{
+			Host:        host,
+			BannedUntil: bannedPeers[host].Unix(),
+		})
+	}
+	// The total is known exactly since all banned hosts are available.
+	page := makePageInfo(limit, int64(offset), int64(len(banned)), nextCursor)
+	page.TotalEstimate = int64(len(hosts))
+	return &types.ListBannedResult{Banned: banned, Page: page}, nil
+}
+
+// handleListRPCClients implements the listrpcclients command.
+func handleListRPCClients(_ context.Context, s *Server, _ interface{}) (interface{}, error) {
+	infos := s.ntfnMgr.Clients()
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].client.connTime.Before(infos[j].client.connTime)
+	})
+
+	results := make([]types.ListRPCClientsResult, 0, len(infos))
+	for _, info := range infos {
+		wsc := info.client
+		wsc.Lock()
+		name, version := wsc.name, wsc.version
+		authenticated, isAdmin := wsc.authenticated, wsc.isAdmin
+		wsc.Unlock()
+		results = append(results, types.ListRPCClientsResult{
+			SessionID:     wsc.sessionID,
+			Addr:          wsc.addr,
+			Name:          name,
+			Version:       version,
+			Authenticated: authenticated,
+			Admin:         isAdmin,
+			ConnTime:      wsc.connTime.Unix(),
+			Subscriptions: info.subscriptions,
+		})
+	}
+	return results, nil
+}
+
 // handleLiveTickets implements the livetickets command.
 func handleLiveTickets(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
 	lt, err := s.cfg.Chain.LiveTickets()
@@ -3626,6 +5602,53 @@ func handlePing(_ context.Context, s *Server, cmd interface{}) (interface{}, err
 	return nil, nil
 }
 
+// handleProposeBlock implements the proposeblock command.
+func handleProposeBlock(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.ProposeBlockCmd)
+	return proposeBlock(s, c.HexBlock)
+}
+
+// proposeBlock fully validates the provided serialized, hex-encoded block as if
+// it were connected to the current tip of the main chain or its parent without
+// the proof of work requirement and returns the result.
+func proposeBlock(s *Server, hexStr string) (*types.ProposeBlockResult, error) {
+	// Deserialize the proposed block.
+	if len(hexStr)%2 != 0 {
+		hexStr = "0" + hexStr
+	}
+	serializedBlock, err := hex.DecodeString(hexStr)
+	if err != nil {
+		return nil, rpcDecodeHexError(hexStr)
+	}
+	block, err := dcrutil.NewBlockFromBytes(serializedBlock)
+	if err != nil {
+		return nil, rpcDeserializationError("Could not decode block: %v",
+			err)
+	}
+
+	// Fully validate the block as if it were connected to the current tip of
+	// the main chain or its parent without the proof of work requirement.  A
+	// rule error means the block is invalid as opposed to something actually
+	// going wrong.
+	result := &types.ProposeBlockResult{
+		Hash:  block.Hash().String(),
+		Valid: true,
+	}
+	err = s.cfg.Chain.CheckConnectBlockTemplate(block)
+	if err != nil {
+		var rErr blockchain.RuleError
+		if !errors.As(err, &rErr) {
+			return nil, rpcInternalError(err.Error(),
+				"Could not validate block")
+		}
+		result.Valid = false
+		result.RejectCode = rErr.ErrorCode.String()
+		result.Reason = rErr.Description
+	}
+
+	return result, nil
+}
+
 // handleRegenTemplate implements the regentemplate command.
 func handleRegenTemplate(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
 	bt := s.cfg.BlockTemplater
@@ -3636,6 +5659,36 @@ func handleRegenTemplate(_ context.Context, s *Server, cmd interface{}) (interfa
 	return nil, nil
 }
 
+// handleRollbackChain implements the rollbackchain command.
+func handleRollbackChain(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.RollbackChainCmd)
+
+	// Respond with an error when not on a private network since discarding
+	// blocks of the main chain is only useful for testing.
+	params := s.cfg.ChainParams
+	if params.Net != wire.SimNet && params.Net != wire.RegNet {
+		return nil, &dcrjson.RPCError{
+			Code: dcrjson.ErrRPCDifficulty,
+			Message: fmt.Sprintf("No support for `rollbackchain` on "+
+				"the current network, %s, as it is only available on "+
+				"simnet and regnet.", params.Net),
+		}
+	}
+
+	best := s.cfg.Chain.BestSnapshot()
+	if c.Height < 0 || c.Height >= best.Height {
+		return nil, rpcInvalidError("Height %d must be less than the "+
+			"current best height %d and not negative", c.Height,
+			best.Height)
+	}
+
+	err := s.cfg.Chain.RollbackToHeight(c.Height)
+	if err != nil {
+		return nil, rpcInternalError(err.Error(), "Could not roll back chain")
+	}
+	return nil, nil
+}
+
 // retrievedTx represents a transaction that was either loaded from the
 // transaction memory pool or from the database.  When a transaction is loaded
 // from the database, it is loaded with the raw serialized bytes while the
@@ -3919,6 +5972,7 @@ func handleSearchRawTransactions(_ context.Context, s *Server, cmd interface{})
 	// Override the default number of requested entries if needed.  Also,
 	// just return now if the number of requested entries is zero to avoid
 	// extra work.
+	paginate := c.Cursor != nil
 	numRequested := 100
 	if c.Count != nil {
 		numRequested = *c.Count
@@ -3926,11 +5980,15 @@ func handleSearchRawTransactions(_ context.Context, s *Server, cmd interface{})
 			numRequested = 1
 		}
 	}
+	if paginate {
+		numRequested = pageLimit(c.Count)
+	}
 	if numRequested == 0 {
 		return nil, nil
 	}
 
-	// Override the default number of entries to skip if needed.
+	// Override the default number of entries to skip if needed.  The
+	// number to skip is instead encoded in the cursor when paginating.
 	var numToSkip int
 	if c.Skip != nil {
 		numToSkip = *c.Skip
@@ -3938,6 +5996,18 @@ func handleSearchRawTransactions(_ context.Context, s *Server, cmd interface{})
 			numToSkip = 0
 		}
 	}
+	limit := numRequested
+	if paginate {
+		positions, err := decodePageCursor(*c.Cursor, 1)
+		if err != nil {
+			return nil, err
+		}
+		numToSkip = int(positions[0])
+
+		// Request an extra entry to determine whether or not there is a
+		// next page.
+		numRequested++
+	}
 
 	// Override the reverse flag if needed.
 	var reverse bool
@@ -4029,10 +6099,25 @@ func handleSearchRawTransactions(_ context.Context, s *Server, cmd interface{})
 	}
 
 	// Address has never been used if neither source yielded any results.
-	if len(addressTxns) == 0 {
+	// An empty page is returned instead when paginating since it is also
+	// the result of paging past the final entry.
+	if len(addressTxns) == 0 && !paginate {
 		return nil, rpcInternalError("No Txns available", "")
 	}
 
+	// Determine the pagination details when paginating and remove the extra
+	// entry that was requested to detect the next page.
+	var page types.PageInfo
+	if paginate {
+		var nextCursor string
+		if len(addressTxns) > limit {
+			addressTxns = addressTxns[:limit]
+			nextCursor = encodePageCursor(uint32(numToSkip + limit))
+		}
+		page = makePageInfo(limit, int64(numSkipped),
+			int64(len(addressTxns)), nextCursor)
+	}
+
 	// Serialize all of the transactions to hex.
 	hexTxns := make([]string, len(addressTxns))
 	for i := range addressTxns {
@@ -4054,6 +6139,12 @@ func handleSearchRawTransactions(_ context.Context, s *Server, cmd interface{})
 
 	// When not in verbose mode, simply return a list of serialized txns.
 	if c.Verbose != nil && *c.Verbose == 0 {
+		if paginate {
+			return &types.SearchRawTransactionsPageResult{
+				Hex:  hexTxns,
+				Page: page,
+			}, nil
+		}
 		return hexTxns, nil
 	}
 
@@ -4148,11 +6239,17 @@ func handleSearchRawTransactions(_ context.Context, s *Server, cmd interface{})
 		}
 	}
 
+	if paginate {
+		return &types.SearchRawTransactionsPageResult{
+			Transactions: srtList,
+			Page:         page,
+		}, nil
+	}
 	return srtList, nil
 }
 
 // handleSendRawTransaction implements the sendrawtransaction command.
-func handleSendRawTransaction(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+func handleSendRawTransaction(ctx context.Context, s *Server, cmd interface{}) (interface{}, error) {
 	c := cmd.(*types.SendRawTransactionCmd)
 	// Deserialize and send off to tx relay
 
@@ -4172,10 +6269,22 @@ func handleSendRawTransaction(_ context.Context, s *Server, cmd interface{}) (in
 			err)
 	}
 
+	// Transactions submitted by clients that authenticated with credentials
+	// the operator designated as exempt bypass the minimum relay fee.
+	var feeExempt bool
+	if user := s.clientUser(ctx); user != "" {
+		for _, exemptUser := range s.cfg.FeeExemptRPCUsers {
+			if user == exemptUser {
+				feeExempt = true
+				break
+			}
+		}
+	}
+
 	// Use 0 for the tag to represent local node.
 	tx := dcrutil.NewTx(msgtx)
 	acceptedTxs, err := s.cfg.SyncMgr.ProcessTransaction(tx, false,
-		false, allowHighFees, 0)
+		false, allowHighFees, feeExempt, 0)
 	if err != nil {
 		// When the error is a rule error, it means the transaction was
 		// simply rejected as opposed to something actually going
@@ -4185,17 +6294,18 @@ func handleSendRawTransaction(_ context.Context, s *Server, cmd interface{}) (in
 		// deserialization error code (to match bitcoind behavior).
 		var rErr mempool.RuleError
 		if errors.As(err, &rErr) {
-			err = fmt.Errorf("rejected transaction %v: %v", tx.Hash(),
-				err)
-			log.Debugf("%v", err)
+			log.Debugf("rejected transaction %v: %v", tx.Hash(), err)
 			if mempool.IsErrorCode(rErr, mempool.ErrDuplicate) {
 				// This is an actual exact duplicate tx, so
 				// return the specific duplicate tx error.
-				return nil, rpcDuplicateTxError("%v", err)
+				return nil, rpcRuleViolationError(dcrjson.ErrRPCDuplicateTx,
+					rErr, "rejected transaction %v: %v", tx.Hash(), err)
 			}
 
-			// return a generic rule error
-			return nil, rpcRuleError("%v", err)
+			// Return a generic rule error along with the details of
+			// the specific violation.
+			return nil, rpcRuleViolationError(dcrjson.ErrRPCMisc, rErr,
+				"rejected transaction %v: %v", tx.Hash(), err)
 		}
 
 		err = fmt.Errorf("failed to process transaction %v: %v",
@@ -4256,6 +6366,167 @@ func handleSetGenerate(_ context.Context, s *Server, cmd interface{}) (interface
 	return nil, nil
 }
 
+// handleSetMiningAddrs implements the setminingaddrs command.
+func handleSetMiningAddrs(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.SetMiningAddrsCmd)
+
+	bt := s.cfg.BlockTemplater
+	if bt == nil {
+		return nil, rpcInternalError("Node is not configured for mining", "")
+	}
+
+	if len(c.Addresses) == 0 {
+		return nil, rpcInvalidError("At least one mining address must be " +
+			"specified")
+	}
+	addrs := make([]dcrutil.Address, 0, len(c.Addresses))
+	for _, encodedAddr := range c.Addresses {
+		addr, err := dcrutil.DecodeAddress(encodedAddr, s.cfg.ChainParams)
+		if err != nil {
+			return nil, rpcAddressKeyError("Could not decode address: %v",
+				err)
+		}
+		addrs = append(addrs, addr)
+	}
+
+	// Keep the current policy when one is not specified.
+	_, policy := bt.MiningAddrs()
+	if c.Policy != nil {
+		var err error
+		policy, err = mining.ParseMiningAddrPolicy(*c.Policy)
+		if err != nil {
+			return nil, rpcInvalidError("Policy must be one of random or "+
+				"roundrobin -- got %q", *c.Policy)
+		}
+	}
+
+	if err := bt.SetMiningAddrs(addrs, policy); err != nil {
+		return nil, rpcInternalError(err.Error(), "Set mining addresses")
+	}
+	return nil, nil
+}
+
+// handleSetMiningExtraData implements the setminingextradata command.
+func handleSetMiningExtraData(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.SetMiningExtraDataCmd)
+
+	bt := s.cfg.BlockTemplater
+	if bt == nil {
+		return nil, rpcInternalError("Node is not configured for mining", "")
+	}
+
+	extraData, err := hex.DecodeString(c.ExtraData)
+	if err != nil {
+		return nil, rpcDecodeHexError(c.ExtraData)
+	}
+	if len(extraData) > mining.MaxCoinbaseExtraDataLen {
+		return nil, rpcInvalidError("Extra data is %d bytes which exceeds "+
+			"the maximum allowed length of %d bytes", len(extraData),
+			mining.MaxCoinbaseExtraDataLen)
+	}
+	if err := bt.SetCoinbaseExtraData(extraData); err != nil {
+		return nil, rpcInternalError(err.Error(), "Set coinbase extra data")
+	}
+	return nil, nil
+}
+
+// handleSimulateDifficulty implements the simulatedifficulty command.
+func handleSimulateDifficulty(ctx context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.SimulateDifficultyCmd)
+
+	numScenarios := len(c.HashRateMultipliers)
+	if numScenarios == 0 {
+		return nil, rpcInvalidError("At least one hash rate multiplier " +
+			"must be specified")
+	}
+	if numScenarios > maxSimulateDifficultyScenarios {
+		return nil, rpcInvalidError("Number of hash rate multipliers %d "+
+			"exceeds the maximum of %d", numScenarios,
+			maxSimulateDifficultyScenarios)
+	}
+	for _, multiplier := range c.HashRateMultipliers {
+		if !(multiplier > 0) || math.IsInf(multiplier, 0) {
+			return nil, rpcInvalidError("Hash rate multiplier %v is not a "+
+				"positive number", multiplier)
+		}
+	}
+
+	// Default to simulating the number of blocks examined by the difficulty
+	// retarget rules.
+	params := s.cfg.ChainParams
+	numBlocks := params.WorkDiffWindowSize * params.WorkDiffWindows
+	if c.Blocks != nil {
+		if *c.Blocks <= 0 || *c.Blocks > maxSimulateDifficultyBlocks {
+			return nil, rpcInvalidError("Number of blocks must be between 1 "+
+				"and %d", maxSimulateDifficultyBlocks)
+		}
+		numBlocks = int64(*c.Blocks)
+	}
+
+	// Create a default getnetworkhashps command to use defaults and make use
+	// of the existing getnetworkhashps handler to estimate the current network
+	// hash rate the multipliers are applied to.
+	gnhpsCmd := types.NewGetNetworkHashPSCmd(nil, nil, nil, nil)
+	networkHashesPerSecIface, err := handleGetNetworkHashPS(ctx, s, gnhpsCmd)
+	if err != nil {
+		return nil, err
+	}
+	networkHashesPerSec, ok := networkHashesPerSecIface.(int64)
+	if !ok {
+		return nil, rpcInternalError("invalid network hashes per sec",
+			fmt.Sprintf("Invalid type: %q",
+				networkHashesPerSecIface))
+	}
+	if networkHashesPerSec <= 0 {
+		return nil, rpcMiscError("Unable to estimate the current network " +
+			"hash rate")
+	}
+
+	chain := s.cfg.Chain
+	best := chain.BestSnapshot()
+	scenarios := make([]types.SimulateDifficultyScenario, 0, numScenarios)
+	for _, multiplier := range c.HashRateMultipliers {
+		// Apply the multiplier to the estimated network hash rate while
+		// ensuring the resulting hash rate is at least one hash per second.
+		hashesPerSec, _ := new(big.Float).Mul(
+			new(big.Float).SetInt64(networkHashesPerSec),
+			big.NewFloat(multiplier)).Int(nil)
+		if hashesPerSec.Sign() <= 0 {
+			hashesPerSec.SetInt64(1)
+		}
+
+		simulated := chain.SimulateRequiredDifficulty(hashesPerSec, numBlocks)
+		retargets := make([]types.SimulateDifficultyRetarget, 0,
+			len(simulated))
+		for _, retarget := range simulated {
+			retargets = append(retargets, types.SimulateDifficultyRetarget{
+				Height:     retarget.Height,
+				Bits:       strconv.FormatInt(int64(retarget.Bits), 16),
+				Difficulty: getDifficultyRatio(retarget.Bits, params),
+				Time:       retarget.Timestamp,
+			})
+		}
+
+		var hashesPerSecInt64 int64 = math.MaxInt64
+		if hashesPerSec.IsInt64() {
+			hashesPerSecInt64 = hashesPerSec.Int64()
+		}
+		scenarios = append(scenarios, types.SimulateDifficultyScenario{
+			HashRateMultiplier: multiplier,
+			HashesPerSec:       hashesPerSecInt64,
+			Retargets:          retargets,
+		})
+	}
+
+	return &types.SimulateDifficultyResult{
+		Height:        best.Height,
+		Bits:          strconv.FormatInt(int64(best.Bits), 16),
+		Difficulty:    getDifficultyRatio(best.Bits, params),
+		NetworkHashPS: networkHashesPerSec,
+		Scenarios:     scenarios,
+	}, nil
+}
+
 // handleStop implements the stop command.
 func handleStop(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
 	select {
@@ -4828,6 +7099,171 @@ func handleVerifyChain(ctx context.Context, s *Server, cmd interface{}) (interfa
 	return err == nil, nil
 }
 
+// signedMessageHash returns the hash that is signed to prove ownership of an
+// address for the provided message.
+func signedMessageHash(message string) []byte {
+	var buf bytes.Buffer
+	wire.WriteVarString(&buf, 0, "Decred Signed Message:\n")
+	wire.WriteVarString(&buf, 0, message)
+	return chainhash.HashB(buf.Bytes())
+}
+
+// verifyPubKeyHashMessage verifies the provided signature of the message with
+// the given hash proves ownership of the passed pay-to-pubkey-hash address.
+//
+// Signatures for addresses that commit to ECDSA public keys are the usual
+// 65-byte compact signatures the public key is recovered from.  Schnorr
+// signatures are not recoverable, so signatures for addresses that commit to
+// secp256k1 Schnorr public keys are the 33-byte compressed public key followed
+// by the 64-byte signature.
+func verifyPubKeyHashMessage(addr *dcrutil.AddressPubKeyHash, sig, msgHash []byte) *types.VerifyMessageResult {
+	result := &types.VerifyMessageResult{
+		Address:    addr.Address(),
+		ScriptType: txscript.PubKeyHashTy.String(),
+	}
+
+	var serializedPK []byte
+	switch addr.DSA() {
+	case dcrec.STEcdsaSecp256k1:
+		result.SigType = "ecdsa"
+		pk, wasCompressed, err := ecdsa.RecoverCompact(sig, msgHash)
+		if err != nil {
+			// Mirror Bitcoin Core behavior, which treats error in
+			// RecoverCompact as invalid signature.
+			result.Reason = fmt.Sprintf("unable to recover public key: %v",
+				err)
+			return result
+		}
+		if wasCompressed {
+			serializedPK = pk.SerializeCompressed()
+		} else {
+			serializedPK = pk.SerializeUncompressed()
+		}
+
+	case dcrec.STSchnorrSecp256k1:
+		result.SigType = "schnorr"
+		const pkLen = secp256k1.PubKeyBytesLenCompressed
+		if len(sig) != pkLen+schnorr.SignatureSize {
+			result.Reason = fmt.Sprintf("schnorr ownership proofs must be "+
+				"%d bytes", pkLen+schnorr.SignatureSize)
+			return result
+		}
+		pk, err := schnorr.ParsePubKey(sig[:pkLen])
+		if err != nil {
+			result.Reason = fmt.Sprintf("malformed public key: %v", err)
+			return result
+		}
+		schnorrSig, err := schnorr.ParseSignature(sig[pkLen:])
+		if err != nil {
+			result.Reason = fmt.Sprintf("malformed signature: %v", err)
+			return result
+		}
+		if !schnorrSig.Verify(msgHash, pk) {
+			result.Reason = "signature is invalid"
+			return result
+		}
+		serializedPK = sig[:pkLen]
+
+	default:
+		result.Reason = "unsupported signature algorithm"
+		return result
+	}
+
+	// Ensure the public key is the one committed to by the address.
+	if !bytes.Equal(dcrutil.Hash160(serializedPK), addr.ScriptAddress()) {
+		result.Reason = "signature was not produced by the key for the address"
+		return result
+	}
+
+	result.Verified = true
+	result.Signers = []string{hex.EncodeToString(serializedPK)}
+	return result
+}
+
+// verifyScriptHashMessage verifies the provided signatures of the message with
+// the given hash prove ownership of the passed pay-to-script-hash address with
+// the given multisig redeem script.
+//
+// The signatures are the concatenation of 65-byte compact signatures, each of
+// which must be produced by a distinct public key in the redeem script, and
+// there must be at least as many as the number of signatures the script
+// requires.
+func verifyScriptHashMessage(addr *dcrutil.AddressScriptHash, redeemScript, sig, msgHash []byte, params *chaincfg.Params) *types.VerifyMessageResult {
+	result := &types.VerifyMessageResult{
+		Address:    addr.Address(),
+		ScriptType: txscript.ScriptHashTy.String(),
+	}
+
+	// Ensure the redeem script is the one committed to by the address.
+	if !bytes.Equal(dcrutil.Hash160(redeemScript), addr.ScriptAddress()) {
+		result.Reason = "redeem script does not match the address"
+		return result
+	}
+
+	// Only multisig redeem scripts are supported.
+	class, keyAddrs, numRequired, _ := txscript.ExtractPkScriptAddrs(0,
+		redeemScript, params)
+	if class != txscript.MultiSigTy {
+		result.Reason = "redeem script is not a multisig script"
+		return result
+	}
+	result.SigType = "ecdsa"
+	result.SigsRequired = int32(numRequired)
+
+	const sigLen = 65
+	if len(sig) == 0 || len(sig)%sigLen != 0 {
+		result.Reason = fmt.Sprintf("multisig ownership proofs must be a "+
+			"concatenation of %d-byte signatures", sigLen)
+		return result
+	}
+	signed := make(map[int]struct{}, len(keyAddrs))
+	for i := 0; i < len(sig)/sigLen; i++ {
+		pk, wasCompressed, err := ecdsa.RecoverCompact(
+			sig[i*sigLen:(i+1)*sigLen], msgHash)
+		if err != nil {
+			result.Reason = fmt.Sprintf("unable to recover public key for "+
+				"signature %d: %v", i, err)
+			return result
+		}
+		var serializedPK []byte
+		if wasCompressed {
+			serializedPK = pk.SerializeCompressed()
+		} else {
+			serializedPK = pk.SerializeUncompressed()
+		}
+
+		// Find the key in the redeem script that produced the signature.
+		keyIdx := -1
+		for j, keyAddr := range keyAddrs {
+			if bytes.Equal(keyAddr.ScriptAddress(), serializedPK) {
+				keyIdx = j
+				break
+			}
+		}
+		if keyIdx == -1 {
+			result.Reason = fmt.Sprintf("signature %d was not produced by a "+
+				"key in the redeem script", i)
+			return result
+		}
+		if _, ok := signed[keyIdx]; ok {
+			result.Reason = fmt.Sprintf("signature %d is a duplicate "+
+				"signature for key %x", i, serializedPK)
+			return result
+		}
+		signed[keyIdx] = struct{}{}
+		result.Signers = append(result.Signers,
+			hex.EncodeToString(serializedPK))
+	}
+	if len(signed) < numRequired {
+		result.Reason = fmt.Sprintf("%d signatures provided, but the redeem "+
+			"script requires %d", len(signed), numRequired)
+		return result
+	}
+
+	result.Verified = true
+	return result
+}
+
 // handleVerifyMessage implements the verifymessage command.
 func handleVerifyMessage(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
 	c := cmd.(*types.VerifyMessageCmd)
@@ -4840,14 +7276,6 @@ func handleVerifyMessage(_ context.Context, s *Server, cmd interface{}) (interfa
 			err)
 	}
 
-	// Only P2PKH addresses are valid for signing.
-	if _, ok := addr.(*dcrutil.AddressPubKeyHash); !ok {
-		return nil, &dcrjson.RPCError{
-			Code:    dcrjson.ErrRPCType,
-			Message: "Address is not a pay-to-pubkey-hash address",
-		}
-	}
-
 	// Decode base64 signature.
 	sig, err := base64.StdEncoding.DecodeString(c.Signature)
 	if err != nil {
@@ -4857,38 +7285,37 @@ func handleVerifyMessage(_ context.Context, s *Server, cmd interface{}) (interfa
 		}
 	}
 
-	// Validate the signature - this just shows that it was valid at all.
-	// we will compare it with the key next.
-	var buf bytes.Buffer
-	wire.WriteVarString(&buf, 0, "Decred Signed Message:\n")
-	wire.WriteVarString(&buf, 0, c.Message)
-	expectedMessageHash := chainhash.HashB(buf.Bytes())
-	pk, wasCompressed, err := ecdsa.RecoverCompact(sig,
-		expectedMessageHash)
-	if err != nil {
-		// Mirror Bitcoin Core behavior, which treats error in
-		// RecoverCompact as invalid signature.
-		return false, nil
-	}
+	// Only P2PKH and P2SH addresses are valid for signing.
+	msgHash := signedMessageHash(c.Message)
+	var result *types.VerifyMessageResult
+	switch addr := addr.(type) {
+	case *dcrutil.AddressPubKeyHash:
+		result = verifyPubKeyHashMessage(addr, sig, msgHash)
 
-	// Reconstruct the pubkey hash.
-	dcrPK := pk
-	var serializedPK []byte
-	if wasCompressed {
-		serializedPK = dcrPK.SerializeCompressed()
-	} else {
-		serializedPK = dcrPK.SerializeUncompressed()
-	}
-	address, err := dcrutil.NewAddressSecpPubKey(serializedPK,
-		s.cfg.ChainParams)
-	if err != nil {
-		// Again mirror Bitcoin Core behavior, which treats error in
-		// public key reconstruction as invalid signature.
-		return false, nil
+	case *dcrutil.AddressScriptHash:
+		if c.RedeemScript == nil {
+			return nil, rpcInvalidError("A redeem script is required to " +
+				"verify messages signed for pay-to-script-hash addresses")
+		}
+		redeemScript, err := hex.DecodeString(*c.RedeemScript)
+		if err != nil {
+			return nil, rpcDecodeHexError(*c.RedeemScript)
+		}
+		result = verifyScriptHashMessage(addr, redeemScript, sig, msgHash,
+			s.cfg.ChainParams)
+
+	default:
+		return nil, &dcrjson.RPCError{
+			Code: dcrjson.ErrRPCType,
+			Message: "Address is not a pay-to-pubkey-hash or " +
+				"pay-to-script-hash address",
+		}
 	}
 
-	// Return boolean if addresses match.
-	return address.Address() == c.Address, nil
+	if c.Verbose == nil || !*c.Verbose {
+		return result.Verified, nil
+	}
+	return result, nil
 }
 
 // handleVersion implements the version command.
@@ -4931,6 +7358,8 @@ type Server struct {
 	workState              *workState
 	helpCacher             *helpCacher
 	requestProcessShutdown chan struct{}
+	auditMtx               sync.Mutex // serializes audit log writes
+	capturingProfile       int32      // atomic
 }
 
 // httpStatusLine returns a response Status-Line (RFC 2616 Section 6.1) for the
@@ -5020,6 +7449,16 @@ func (s *Server) NotifyNewTransactions(txns []*dcrutil.Tx) {
 	}
 }
 
+// NotifyUnminedTransactions notifies websocket clients of the passed
+// transactions which were returned to the mempool because the blocks that
+// mined them were disconnected from the main chain.  Clients with a transaction
+// filter that matches any of them are notified that they are unmined again.
+func (s *Server) NotifyUnminedTransactions(txns []*dcrutil.Tx) {
+	for _, tx := range txns {
+		s.ntfnMgr.NotifyMempoolTx(tx, false)
+	}
+}
+
 // NotifyWork notifies websocket clients that have registered for new mining
 // work.
 func (s *Server) NotifyWork(templateNtfn *mining.TemplateNtfn) {
@@ -5062,12 +7501,34 @@ func (s *Server) NotifyReorganization(rd *blockchain.ReorganizationNtfnsData) {
 	s.ntfnMgr.NotifyReorganization(rd)
 }
 
+// NotifyDiskSpace notifies websocket clients that have registered for disk
+// space updates when the free disk space available to the data directory
+// crosses one of the configured thresholds.
+func (s *Server) NotifyDiskSpace(status *DiskSpaceStatus) {
+	s.ntfnMgr.NotifyDiskSpace(status)
+}
+
 // NotifyWinningTickets notifies websocket clients that have registered for
 // winning ticket updates.
 func (s *Server) NotifyWinningTickets(wtnd *WinningTicketsNtfnData) {
 	s.ntfnMgr.NotifyWinningTickets(wtnd)
 }
 
+// isStandby returns whether or not the server is the standby of a redundancy
+// pair and therefore must not serve miners and websocket clients.
+func (s *Server) isStandby() bool {
+	return s.cfg.Coordinator != nil && !s.cfg.Coordinator.IsLeader()
+}
+
+// DisconnectWebsocketClients disconnects all websocket clients actively being
+// served.  This function should be called whenever the server becomes the
+// standby of a redundancy pair so the clients reconnect to the leader.
+func (s *Server) DisconnectWebsocketClients() {
+	for _, info := range s.ntfnMgr.Clients() {
+		info.client.Disconnect()
+	}
+}
+
 // limitConnections responds with a 503 service unavailable and returns true if
 // adding another client would exceed the maximum allow RPC clients.
 //
@@ -5115,6 +7576,13 @@ func (s *Server) decrementClients() {
 func (s *Server) checkAuth(r *http.Request, require bool) (bool, bool, error) {
 	authhdr := r.Header["Authorization"]
 	if len(authhdr) <= 0 {
+		// Requests received over a unix socket are authorized as the admin
+		// user since access to the socket is already restricted by the
+		// permissions of the socket file.
+		if isUnixSocketRequest(r) {
+			return true, true, nil
+		}
+
 		if require {
 			log.Warnf("RPC authentication failure from %s",
 				r.RemoteAddr)
@@ -5144,15 +7612,37 @@ func (s *Server) checkAuth(r *http.Request, require bool) (bool, bool, error) {
 	return false, false, errors.New("auth failure")
 }
 
+// unixSocketCtxKey is the context key used to mark the connections accepted by
+// unix socket listeners.
+type unixSocketCtxKey struct{}
+
+// unixSocketConnContext returns the context for a new connection accepted by
+// one of the listeners of the RPC server, which is marked when the connection
+// was accepted by a unix socket listener.
+func unixSocketConnContext(ctx context.Context, conn net.Conn) context.Context {
+	if conn.LocalAddr().Network() == "unix" {
+		return context.WithValue(ctx, unixSocketCtxKey{}, true)
+	}
+	return ctx
+}
+
+// isUnixSocketRequest returns whether or not the provided request was received
+// over a unix socket.
+func isUnixSocketRequest(r *http.Request) bool {
+	isUnix, _ := r.Context().Value(unixSocketCtxKey{}).(bool)
+	return isUnix
+}
+
 // parsedRPCCmd represents a JSON-RPC request object that has been parsed into
 // a known concrete command along with any error that might have happened while
 // parsing it.
 type parsedRPCCmd struct {
-	jsonrpc string
-	id      interface{}
-	method  types.Method
-	params  interface{}
-	err     *dcrjson.RPCError
+	jsonrpc   string
+	id        interface{}
+	method    types.Method
+	params    interface{}
+	rawParams []json.RawMessage
+	err       *dcrjson.RPCError
 }
 
 // standardCmdResult checks that a parsed command is a standard Bitcoin
@@ -5160,6 +7650,16 @@ type parsedRPCCmd struct {
 // Any commands which are not recognized or not implemented will return an
 // error suitable for use in replies.
 func (s *Server) standardCmdResult(ctx context.Context, cmd *parsedRPCCmd) (interface{}, error) {
+	// Experimental RPCs are only available when enabled.
+	if isExperimentalRPC(cmd.method) && !s.cfg.EnableExperimentalRPC {
+		return nil, ErrRPCExperimentalDisabled
+	}
+
+	// Mining is only served by the leader of a redundancy pair.
+	if _, ok := rpcLeaderOnly[string(cmd.method)]; ok && s.isStandby() {
+		return nil, ErrRPCStandby
+	}
+
 	handler, ok := rpcHandlers[cmd.method]
 	if ok {
 		goto handled
@@ -5185,9 +7685,10 @@ handled:
 // an unregistered command or invalid parameters.
 func parseCmd(request *dcrjson.Request) *parsedRPCCmd {
 	parsedCmd := parsedRPCCmd{
-		jsonrpc: request.Jsonrpc,
-		id:      request.ID,
-		method:  types.Method(request.Method),
+		jsonrpc:   request.Jsonrpc,
+		id:        request.ID,
+		method:    types.Method(request.Method),
+		rawParams: request.Params,
 	}
 
 	params, err := dcrjson.ParseParams(types.Method(request.Method), request.Params)
@@ -5224,8 +7725,10 @@ func createMarshalledReply(rpcVersion string, id interface{}, result interface{}
 }
 
 // processRequest determines the incoming request type (single or batched),
-// parses it and returns a marshalled response.
-func (s *Server) processRequest(ctx context.Context, request *dcrjson.Request, isAdmin bool) []byte {
+// parses it and returns a marshalled response.  State-changing requests are
+// recorded to the audit log along with the address of the client that issued
+// them.
+func (s *Server) processRequest(ctx context.Context, request *dcrjson.Request, isAdmin bool, remoteAddr string) []byte {
 	var result interface{}
 	var jsonErr error
 
@@ -5262,9 +7765,13 @@ func (s *Server) processRequest(ctx context.Context, request *dcrjson.Request, i
 		if parsedCmd.err != nil {
 			jsonErr = parsedCmd.err
 		} else {
-			result, jsonErr = s.standardCmdResult(ctx, parsedCmd)
+			cmdCtx := withClientAdmin(withClientAddr(ctx, remoteAddr),
+				isAdmin)
+			result, jsonErr = s.standardCmdResult(cmdCtx, parsedCmd)
 		}
 	}
+	s.auditRPC(request.Method, request.Params, isAdmin, remoteAddr, result,
+		jsonErr)
 
 	// Marshal the response.
 	msg, err := createMarshalledReply(request.Jsonrpc, request.ID, result, jsonErr)
@@ -5358,7 +7865,7 @@ func (s *Server) jsonRPCRead(sCtx context.Context, w http.ResponseWriter, r *htt
 				log.Errorf("Failed to create reply: %v", err)
 			}
 		} else {
-			resp = s.processRequest(ctx, &req, isAdmin)
+			resp = s.processRequest(ctx, &req, isAdmin, r.RemoteAddr)
 		}
 
 		if resp != nil {
@@ -5427,7 +7934,7 @@ func (s *Server) jsonRPCRead(sCtx context.Context, w http.ResponseWriter, r *htt
 						continue
 					}
 
-					resp = s.processRequest(ctx, &req, isAdmin)
+					resp = s.processRequest(ctx, &req, isAdmin, r.RemoteAddr)
 					if resp != nil {
 						results = append(results, resp)
 					}
@@ -5493,6 +8000,10 @@ func (s *Server) route(ctx context.Context) *http.Server {
 		// Timeout connections which don't complete the initial
 		// handshake within the allowed timeframe.
 		ReadTimeout: time.Second * rpcAuthTimeoutSeconds,
+
+		// Mark connections accepted by unix socket listeners so their
+		// requests are authorized without credentials.
+		ConnContext: unixSocketConnContext,
 	}
 	rpcServeMux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Connection", "close")
@@ -5525,6 +8036,14 @@ func (s *Server) route(ctx context.Context) *http.Server {
 			return
 		}
 
+		// Websocket clients are only served by the leader of a
+		// redundancy pair.
+		if s.isStandby() {
+			http.Error(w, "503 Service Unavailable: node is on standby",
+				http.StatusServiceUnavailable)
+			return
+		}
+
 		// Attempt to upgrade the connection to a websocket connection
 		// using the default size for read/write buffers.
 		ws, err := websocket.Upgrade(w, r, nil, 0, 0)
@@ -5586,7 +8105,8 @@ type Config struct {
 	// Listeners defines a slice of listeners for which the RPC server will
 	// take ownership of and accept connections.  Since the RPC server takes
 	// ownership of these listeners, they will be closed when the RPC server
-	// is stopped.
+	// is stopped.  Requests received by unix socket listeners are
+	// authorized as the admin user when they do not provide credentials.
 	Listeners []net.Listener
 
 	// StartupTime is the unix timestamp for when the server that is hosting
@@ -5645,6 +8165,10 @@ type Config struct {
 	// AddrIndexer defines the optional address indexer for the RPC server to use.
 	AddrIndexer AddrIndexer
 
+	// AddrActivityIndexer defines the optional address activity indexer for
+	// the RPC server to use.
+	AddrActivityIndexer AddrActivityIndexer
+
 	// NetInfo defines a slice of the available networks.
 	NetInfo []types.NetworksResult
 
@@ -5662,6 +8186,11 @@ type Config struct {
 	RPCLimitUser string
 	RPCLimitPass string
 
+	// FeeExemptRPCUsers defines the usernames of the RPC credentials whose
+	// transactions submitted via sendrawtransaction are exempt from the
+	// minimum relay fee policy.
+	FeeExemptRPCUsers []string
+
 	// RPCMaxClients defines the max number of RPC clients for standard
 	// connections.
 	RPCMaxClients int
@@ -5693,11 +8222,44 @@ type Config struct {
 	// LogManager defines the log manager for the RPC server to use.
 	LogManager LogManager
 
+	// AuditLog defines an optional writer to record all state-changing RPCs
+	// to along with the credentials and address of the client that issued
+	// them and their results.  Each entry is written with a single call to
+	// Write as a newline-terminated JSON object.
+	AuditLog io.Writer
+
 	// Filterer defines the filterer for the RPC server to use.
 	Filterer Filterer
 
 	// FiltererV2 defines the V2 filterer for the RPC server to use.
 	FiltererV2 FiltererV2
+
+	// DiskSpaceMonitor defines the disk space monitor for the RPC server to
+	// use.  It may be nil when disk space monitoring is not available.
+	DiskSpaceMonitor DiskSpaceMonitor
+
+	// PeerTelemetry defines the peer telemetry for the RPC server to use.  It
+	// may be nil when peer telemetry is not enabled.
+	PeerTelemetry PeerTelemetry
+
+	// ProfileDir defines the directory that profiles captured via the
+	// captureprofile RPC are written to.  Profile capture is not available
+	// when it is empty.
+	ProfileDir string
+
+	// StandardVerifyFlags defines the function to retrieve the flags used to
+	// execute transaction scripts via the gettxscriptcost RPC.  The base
+	// standard verification flags of the mempool are used when it is nil.
+	StandardVerifyFlags func() (txscript.ScriptFlags, error)
+
+	// EnableExperimentalRPC indicates whether or not the experimental RPCs,
+	// which are those with methods that have the x_ prefix, are available.
+	EnableExperimentalRPC bool
+
+	// Coordinator defines the leader election of the redundancy pair the
+	// server is part of.  It may be nil when the server is not part of a
+	// pair, in which case it always serves miners and websocket clients.
+	Coordinator Coordinator
 }
 
 // New returns a new instance of the Server struct.
@@ -5706,7 +8268,7 @@ func New(config *Config) (*Server, error) {
 		cfg:                    *config,
 		statusLines:            make(map[int]string),
 		workState:              newWorkState(),
-		helpCacher:             newHelpCacher(),
+		helpCacher:             newHelpCacher(config.EnableExperimentalRPC),
 		requestProcessShutdown: make(chan struct{}),
 	}
 	if config.RPCUser != "" && config.RPCPass != "" {
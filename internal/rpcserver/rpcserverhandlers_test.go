@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"compress/bzip2"
 	"context"
+	"encoding/base64"
 	"encoding/gob"
 	"encoding/hex"
 	"errors"
@@ -16,10 +17,13 @@ import (
 	"math"
 	"math/big"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -32,16 +36,23 @@ import (
 	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/chaincfg/v3"
 	"github.com/decred/dcrd/database/v2"
+	"github.com/decred/dcrd/dcrec"
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
+	"github.com/decred/dcrd/dcrec/secp256k1/v3/ecdsa"
+	"github.com/decred/dcrd/dcrec/secp256k1/v3/schnorr"
 	"github.com/decred/dcrd/dcrjson/v3"
 	"github.com/decred/dcrd/dcrutil/v3"
 	"github.com/decred/dcrd/gcs/v2"
 	"github.com/decred/dcrd/gcs/v2/blockcf2"
 	"github.com/decred/dcrd/internal/mempool"
 	"github.com/decred/dcrd/internal/mining"
+	"github.com/decred/dcrd/internal/txrecon"
 	"github.com/decred/dcrd/internal/version"
 	"github.com/decred/dcrd/peer/v2"
 	"github.com/decred/dcrd/rpc/jsonrpc/types/v2"
+	"github.com/decred/dcrd/txscript/v3"
 	"github.com/decred/dcrd/wire"
+	"github.com/gorilla/websocket"
 )
 
 // testDataPath is the path where all rpcserver test fixtures reside.
@@ -121,11 +132,14 @@ type testRPCChain struct {
 	blockHashByHeightErr            error
 	blockHeightByHash               int64
 	blockHeightByHashErr            error
+	blockReceiveTime                time.Time
+	blockReceiveTimeErr             error
 	calcNextRequiredStakeDifficulty int64
 	calcWantHeight                  int64
 	chainTips                       []blockchain.ChainTipInfo
 	chainWork                       *big.Int
 	chainWorkErr                    error
+	checkConnectBlockTemplateErr    error
 	checkExpiredTickets             []bool
 	checkLiveTicket                 bool
 	checkLiveTickets                []bool
@@ -140,6 +154,7 @@ type testRPCChain struct {
 	getVoteCounts                   blockchain.VoteCounts
 	getVoteInfo                     *blockchain.VoteInfo
 	headerByHash                    wire.BlockHeader
+	headerByHashFn                  func(hash *chainhash.Hash) (wire.BlockHeader, error)
 	headerByHashErr                 error
 	headerByHeight                  wire.BlockHeader
 	headerByHeightErr               error
@@ -150,14 +165,21 @@ type testRPCChain struct {
 	locateHeaders                   []wire.BlockHeader
 	lotteryDataForBlock             []chainhash.Hash
 	mainChainHasBlock               bool
+	mainChainHasBlockFn             func(hash *chainhash.Hash) bool
 	maxBlockSize                    int64
 	maxBlockSizeErr                 error
+	medianTimeByHash                time.Time
+	medianTimeByHashErr             error
 	missedTickets                   []chainhash.Hash
 	missedTicketsErr                error
 	nextThresholdState              blockchain.ThresholdStateTuple
 	nextThresholdStateErr           error
+	rollbackToHeightErr             error
+	simulateRequiredDifficultyFn    func(hashesPerSec *big.Int, numBlocks int64) []standalone.SimulatedWorkDiff
 	stateLastChangedHeight          int64
 	stateLastChangedHeightErr       error
+	deploymentVoteResult            *blockchain.DeploymentVoteResult
+	deploymentVoteResultErr         error
 	ticketPoolValue                 dcrutil.Amount
 	ticketPoolValueErr              error
 	ticketsWithAddress              []chainhash.Hash
@@ -189,6 +211,12 @@ func (c *testRPCChain) BlockHeightByHash(hash *chainhash.Hash) (int64, error) {
 	return c.blockHeightByHash, c.blockHeightByHashErr
 }
 
+// BlockReceiveTime returns a mocked local time the block with the given hash
+// was received.
+func (c *testRPCChain) BlockReceiveTime(hash *chainhash.Hash) (time.Time, error) {
+	return c.blockReceiveTime, c.blockReceiveTimeErr
+}
+
 // CalcNextRequiredStakeDifficulty returns a mocked required stake difficulty.
 func (c *testRPCChain) CalcNextRequiredStakeDifficulty() (int64, error) {
 	return c.calcNextRequiredStakeDifficulty, nil
@@ -211,6 +239,12 @@ func (c *testRPCChain) ChainWork(hash *chainhash.Hash) (*big.Int, error) {
 	return c.chainWork, c.chainWorkErr
 }
 
+// CheckConnectBlockTemplate returns a mocked error from validating a block
+// template.
+func (c *testRPCChain) CheckConnectBlockTemplate(block *dcrutil.Block) error {
+	return c.checkConnectBlockTemplateErr
+}
+
 // CheckExpiredTickets returns a mocked slice of bools representing
 // whether each ticket hash has expired.
 func (c *testRPCChain) CheckExpiredTickets(hashes []chainhash.Hash) []bool {
@@ -272,6 +306,13 @@ func (c *testRPCChain) GetVoteCounts(version uint32, deploymentID string) (block
 	return c.getVoteCounts, nil
 }
 
+// GetVoteCountsAt returns a mocked blockchain.VoteCounts for the specified
+// version and deployment identifier for the rule change activation interval
+// that contains the block with the provided hash.
+func (c *testRPCChain) GetVoteCountsAt(hash *chainhash.Hash, version uint32, deploymentID string) (blockchain.VoteCounts, error) {
+	return c.getVoteCounts, nil
+}
+
 // GetVoteInfo returns mocked information on consensus deployment agendas and
 // their respective states at the provided hash, for the provided deployment
 // version.
@@ -281,6 +322,9 @@ func (c *testRPCChain) GetVoteInfo(hash *chainhash.Hash, version uint32) (*block
 
 // HeaderByHash returns a mocked block header identified by the given hash.
 func (c *testRPCChain) HeaderByHash(hash *chainhash.Hash) (wire.BlockHeader, error) {
+	if c.headerByHashFn != nil {
+		return c.headerByHashFn(hash)
+	}
 	return c.headerByHash, c.headerByHashErr
 }
 
@@ -322,6 +366,9 @@ func (c *testRPCChain) LotteryDataForBlock(hash *chainhash.Hash) ([]chainhash.Ha
 // MainChainHasBlock returns a mocked bool representing whether or not the block
 // with the given hash is in the main chain.
 func (c *testRPCChain) MainChainHasBlock(hash *chainhash.Hash) bool {
+	if c.mainChainHasBlockFn != nil {
+		return c.mainChainHasBlockFn(hash)
+	}
 	return c.mainChainHasBlock
 }
 
@@ -331,6 +378,12 @@ func (c *testRPCChain) MaxBlockSize() (int64, error) {
 	return c.maxBlockSize, c.maxBlockSizeErr
 }
 
+// MedianTimeByHash returns a mocked median time of the block identified by the
+// given hash.
+func (c *testRPCChain) MedianTimeByHash(hash *chainhash.Hash) (time.Time, error) {
+	return c.medianTimeByHash, c.medianTimeByHashErr
+}
+
 // MissedTickets returns a mocked slice of all currently missed tickets.
 func (c *testRPCChain) MissedTickets() ([]chainhash.Hash, error) {
 	return c.missedTickets, c.missedTicketsErr
@@ -342,12 +395,28 @@ func (c *testRPCChain) NextThresholdState(hash *chainhash.Hash, version uint32,
 	return c.nextThresholdState, c.nextThresholdStateErr
 }
 
+// RollbackToHeight returns a mocked error for rolling back the main chain.
+func (c *testRPCChain) RollbackToHeight(height int64) error {
+	return c.rollbackToHeightErr
+}
+
+// SimulateRequiredDifficulty returns mocked projected difficulty retargets.
+func (c *testRPCChain) SimulateRequiredDifficulty(hashesPerSec *big.Int, numBlocks int64) []standalone.SimulatedWorkDiff {
+	return c.simulateRequiredDifficultyFn(hashesPerSec, numBlocks)
+}
+
 // StateLastChangedHeight returns a mocked height at which the provided
 // consensus deployment agenda last changed state.
 func (c *testRPCChain) StateLastChangedHeight(hash *chainhash.Hash, version uint32, deploymentID string) (int64, error) {
 	return c.stateLastChangedHeight, c.stateLastChangedHeightErr
 }
 
+// DeploymentVoteResult returns a mocked outcome of the vote on the provided
+// consensus deployment agenda.
+func (c *testRPCChain) DeploymentVoteResult(hash *chainhash.Hash, version uint32, deploymentID string) (*blockchain.DeploymentVoteResult, error) {
+	return c.deploymentVoteResult, c.deploymentVoteResultErr
+}
+
 // TicketPoolValue returns a mocked current value of all the locked funds in the
 // ticket pool.
 func (c *testRPCChain) TicketPoolValue() (dcrutil.Amount, error) {
@@ -377,6 +446,7 @@ type testPeer struct {
 	isTxRelayDisabled bool
 	banScore          uint32
 	statsSnapshot     *peer.StatsSnap
+	tcpInfo           *PeerTCPInfo
 }
 
 // Addr returns a mocked peer address.
@@ -428,6 +498,11 @@ func (p *testPeer) BanScore() uint32 {
 	return p.banScore
 }
 
+// TCPInfo returns mocked transport statistics of the peer connection.
+func (p *testPeer) TCPInfo() *PeerTCPInfo {
+	return p.tcpInfo
+}
+
 // testAddrManager provides a mock address manager by implementing the
 // AddrManager interface.
 type testAddrManager struct {
@@ -451,6 +526,11 @@ type testSyncManager struct {
 	tipGeneration      []chainhash.Hash
 	syncHeight         int64
 	processTransaction []*dcrutil.Tx
+	txRelayPriority    TxRelayLaneStats
+	txRelayRegular     TxRelayLaneStats
+	rejectedTxns       []RejectedTx
+	feeExempt          bool
+	syncPeerInfo       *SyncPeerInfo
 }
 
 // IsCurrent returns a mocked bool representing whether or not the sync manager
@@ -491,10 +571,31 @@ func (s *testSyncManager) SyncHeight() int64 {
 // ProcessTransaction provides a mock implementation for relaying the provided
 // transaction validation and insertion into the memory pool.
 func (s *testSyncManager) ProcessTransaction(tx *dcrutil.Tx, allowOrphans bool,
-	rateLimit bool, allowHighFees bool, tag mempool.Tag) ([]*dcrutil.Tx, error) {
+	rateLimit bool, allowHighFees bool, feeExempt bool,
+	tag mempool.Tag) ([]*dcrutil.Tx, error) {
+
+	s.feeExempt = feeExempt
 	return s.processTransaction, nil
 }
 
+// TxRelayStats returns mocked statistics about the latency of the transactions
+// processed via the priority and regular relay lanes.
+func (s *testSyncManager) TxRelayStats() (priority, regular TxRelayLaneStats) {
+	return s.txRelayPriority, s.txRelayRegular
+}
+
+// RejectedTransactions returns mocked transactions recently rejected from
+// peers.
+func (s *testSyncManager) RejectedTransactions() []RejectedTx {
+	return s.rejectedTxns
+}
+
+// SyncPeerInfo returns mocked details about the current sync peer and the
+// most recent changes to it.
+func (s *testSyncManager) SyncPeerInfo() *SyncPeerInfo {
+	return s.syncPeerInfo
+}
+
 // testExistsAddresser provides a mock exists addresser by implementing the
 // ExistsAddresser interface.
 type testExistsAddresser struct {
@@ -516,6 +617,19 @@ func (e *testExistsAddresser) ExistsAddresses(addrs []dcrutil.Address) ([]bool,
 	return e.existsAddresses, e.existsAddressesErr
 }
 
+// testAddrActivityIndexer provides a mock address activity indexer by
+// implementing the AddrActivityIndexer interface.
+type testAddrActivityIndexer struct {
+	activity *indexers.AddrActivity
+	err      error
+}
+
+// AddressActivity returns mocked first and last heights at which an address
+// was seen.
+func (i *testAddrActivityIndexer) AddressActivity(addr dcrutil.Address) (*indexers.AddrActivity, error) {
+	return i.activity, i.err
+}
+
 // testAddrIndexer provides a mock address indexer by implementing the
 // AddrIndexer interface.
 type testAddrIndexer struct {
@@ -693,7 +807,9 @@ type testConnManager struct {
 	connectedCount      int32
 	netTotalReceived    uint64
 	netTotalSent        uint64
+	txReconStats        *txrecon.Stats
 	connectedPeers      []Peer
+	bannedPeers         map[string]time.Time
 	persistentPeers     []Peer
 	addedNodeInfo       []Peer
 	lookup              func(host string) ([]net.IP, error)
@@ -740,11 +856,24 @@ func (c *testConnManager) NetTotals() (uint64, uint64) {
 	return c.netTotalReceived, c.netTotalSent
 }
 
+// TxReconStats returns mocked transaction inventory reconciliation statistics.
+func (c *testConnManager) TxReconStats() (txrecon.Stats, bool) {
+	if c.txReconStats == nil {
+		return txrecon.Stats{}, false
+	}
+	return *c.txReconStats, true
+}
+
 // ConnectedPeers returns a mocked slice of all connected peers.
 func (c *testConnManager) ConnectedPeers() []Peer {
 	return c.connectedPeers
 }
 
+// BannedPeers returns a mocked map of banned hosts to when their bans expire.
+func (c *testConnManager) BannedPeers() map[string]time.Time {
+	return c.bannedPeers
+}
+
 // PersistentPeers returns a mocked slice of all persistent peers.
 func (c *testConnManager) PersistentPeers() []Peer {
 	return c.persistentPeers
@@ -789,6 +918,12 @@ func (c *testCPUMiner) GenerateNBlocks(ctx context.Context, n uint32) ([]*chainh
 	return c.generatedBlocks, c.generateNBlocksErr
 }
 
+// GenerateNBlocksToAddress returns a mock implementatation of generating a
+// requested number of blocks that pay to an address.
+func (c *testCPUMiner) GenerateNBlocksToAddress(ctx context.Context, n uint32, payToAddr dcrutil.Address) ([]*chainhash.Hash, error) {
+	return c.generatedBlocks, c.generateNBlocksErr
+}
+
 // IsMining returns a mocked mining state of the CPU miner.
 func (c *testCPUMiner) IsMining() bool {
 	return c.isMining
@@ -841,6 +976,27 @@ func (c *testClock) Since(t time.Time) time.Duration {
 	return c.since
 }
 
+// testTimeSource provides a mock median time source by implementing the
+// blockchain.MedianTimeSource interface.
+type testTimeSource struct {
+	adjustedTime time.Time
+	offset       time.Duration
+}
+
+// AdjustedTime returns a mocked current time adjusted by the median time
+// offset.
+func (s *testTimeSource) AdjustedTime() time.Time {
+	return s.adjustedTime
+}
+
+// AddTimeSample is a no-op since the mocked median time offset is fixed.
+func (s *testTimeSource) AddTimeSample(id string, timeVal time.Time) {}
+
+// Offset returns a mocked median time offset.
+func (s *testTimeSource) Offset() time.Duration {
+	return s.offset
+}
+
 // testFeeEstimator provides a mock fee estimator by implementing the
 // FeeEstimator interface.
 type testFeeEstimator struct {
@@ -916,6 +1072,48 @@ func (f *testFiltererV2) FilterByBlockHash(hash *chainhash.Hash) (*gcs.FilterV2,
 	return f.filterByBlockHash, f.filterByBlockHashErr
 }
 
+// testDiskSpaceMonitor provides a mock disk space monitor by implementing the
+// DiskSpaceMonitor interface.
+type testDiskSpaceMonitor struct {
+	status *DiskSpaceStatus
+	err    error
+}
+
+// DiskSpace returns a mocked disk space status.
+func (m *testDiskSpaceMonitor) DiskSpace() (*DiskSpaceStatus, error) {
+	return m.status, m.err
+}
+
+// testPeerTelemetry provides a mock peer telemetry by implementing the
+// PeerTelemetry interface.
+type testPeerTelemetry struct {
+	dump *PeerTelemetryDump
+	err  error
+}
+
+// Dump returns a mocked peer telemetry dump.
+func (m *testPeerTelemetry) Dump() (*PeerTelemetryDump, error) {
+	return m.dump, m.err
+}
+
+// testCoordinator provides a mock coordinator by implementing the Coordinator
+// interface.
+type testCoordinator struct {
+	leader bool
+	state  *CoordinationState
+	err    error
+}
+
+// IsLeader returns a mocked leader status.
+func (m *testCoordinator) IsLeader() bool {
+	return m.leader
+}
+
+// Heartbeat returns a mocked election state.
+func (m *testCoordinator) Heartbeat(peer *CoordinationState) (*CoordinationState, error) {
+	return m.state, m.err
+}
+
 // testMiningState provides a mock mining state.
 type testMiningState struct {
 	allowUnsyncedMining bool
@@ -957,11 +1155,50 @@ type testBlockTemplater struct {
 	currTemplateErr    error
 	updateBlockTimeErr error
 	simulateNewNtfn    bool
+	extraData          []byte
+	setExtraDataErr    error
+	miningAddrs        []dcrutil.Address
+	miningAddrPolicy   mining.MiningAddrPolicy
+	setMiningAddrsErr  error
+	templateDecisions  []*mining.TemplateDecisions
 }
 
 // ForceRegen asks the block templater to generate a new template immediately.
 func (b *testBlockTemplater) ForceRegen() {}
 
+// SetCoinbaseExtraData sets the mocked extra data that is appended to the
+// coinbase script sig of generated block templates.
+func (b *testBlockTemplater) SetCoinbaseExtraData(extraData []byte) error {
+	b.extraData = extraData
+	return b.setExtraDataErr
+}
+
+// MiningAddrs returns the mocked mining addresses along with the policy used to
+// choose between them.
+func (b *testBlockTemplater) MiningAddrs() ([]dcrutil.Address, mining.MiningAddrPolicy) {
+	return b.miningAddrs, b.miningAddrPolicy
+}
+
+// SetMiningAddrs sets the mocked mining addresses along with the policy used to
+// choose between them.
+func (b *testBlockTemplater) SetMiningAddrs(addrs []dcrutil.Address, policy mining.MiningAddrPolicy) error {
+	if b.setMiningAddrsErr != nil {
+		return b.setMiningAddrsErr
+	}
+	b.miningAddrs = addrs
+	b.miningAddrPolicy = policy
+	return nil
+}
+
+// TemplateDecisions returns up to the provided number of the mocked template
+// decisions.
+func (b *testBlockTemplater) TemplateDecisions(count int) []*mining.TemplateDecisions {
+	if count < len(b.templateDecisions) {
+		return b.templateDecisions[:count]
+	}
+	return b.templateDecisions
+}
+
 // Subscribe subscribes a client for block template updates.  The returned
 // template subscription contains functions to retrieve a channel that produces
 // the stream of block templates and to stop the stream when the caller no
@@ -1007,6 +1244,14 @@ type testTxMempooler struct {
 	count               int
 	fetchTransaction    *dcrutil.Tx
 	fetchTransactionErr error
+	stats               *mempool.PoolStats
+	replacements        *mempool.ReplacementHistory
+	standardPolicy      *mempool.StandardPolicy
+	standardPolicyErr   error
+	violations          []mempool.StandardViolation
+	violationsErr       error
+	lockStatus          *mempool.LockStatus
+	lockStatusErr       error
 }
 
 // HaveTransactions returns a mocked bool slice representing whether or not the
@@ -1038,6 +1283,31 @@ func (mp *testTxMempooler) FetchTransaction(txHash *chainhash.Hash) (*dcrutil.Tx
 	return mp.fetchTransaction, mp.fetchTransactionErr
 }
 
+// Stats returns mocked mempool statistics.
+func (mp *testTxMempooler) Stats() *mempool.PoolStats {
+	return mp.stats
+}
+
+// Replacements returns a mocked replacement policy and decision history.
+func (mp *testTxMempooler) Replacements() *mempool.ReplacementHistory {
+	return mp.replacements
+}
+
+// StandardPolicy returns a mocked standardness policy.
+func (mp *testTxMempooler) StandardPolicy() (*mempool.StandardPolicy, error) {
+	return mp.standardPolicy, mp.standardPolicyErr
+}
+
+// CheckTransactionStandard returns mocked standardness policy violations.
+func (mp *testTxMempooler) CheckTransactionStandard(tx *dcrutil.Tx) ([]mempool.StandardViolation, error) {
+	return mp.violations, mp.violationsErr
+}
+
+// EvaluateLocks returns a mocked lock time evaluation.
+func (mp *testTxMempooler) EvaluateLocks(tx *dcrutil.Tx) (*mempool.LockStatus, error) {
+	return mp.lockStatus, mp.lockStatusErr
+}
+
 // mustParseHash converts the passed big-endian hex string into a
 // chainhash.Hash and will panic if there is an error.  It only differs from the
 // one available in chainhash in that it will panic so errors in the source code
@@ -1137,17 +1407,23 @@ type rpcTest struct {
 	mockExistsAddresser   *testExistsAddresser
 	setExistsAddresserNil bool
 	mockAddrIndexer       *testAddrIndexer
+	mockAddrActivity      *testAddrActivityIndexer
 	setAddrIndexerNil     bool
 	mockTxIndexer         *testTxIndexer
 	setTxIndexerNil       bool
 	mockDB                *testDB
 	mockConnManager       *testConnManager
 	mockClock             *testClock
+	mockTimeSource        *testTimeSource
 	mockLogManager        *testLogManager
 	mockFilterer          *testFilterer
 	mockFiltererV2        *testFiltererV2
+	mockDiskSpaceMonitor  *testDiskSpaceMonitor
+	mockPeerTelemetry     *testPeerTelemetry
+	mockCoordinator       *testCoordinator
 	mockTxMempooler       *testTxMempooler
 	mockMiningAddrs       []dcrutil.Address
+	profileDir            string
 	result                interface{}
 	wantErr               bool
 	errCode               dcrjson.RPCErrorCode
@@ -1265,6 +1541,7 @@ func defaultMockRPCChain() *testRPCChain {
 			Size:           36441617,
 			Total:          1154067750680149,
 			SerializedHash: *mustParseHash("fe7b32aa188800f07268b17f3bead5f3d8a1b6d18654182066436efce6effa86"),
+			SetHash:        *mustParseHash("3f7de3f1d2e61c7aa5d8b0ab44e2a7f3b1c9e0a4d6c8f2b1e3a5c7d9f1b3d5e7"),
 		},
 		getStakeVersions: []blockchain.StakeVersions{{
 			Hash:         *blkHash,
@@ -1403,8 +1680,9 @@ func defaultMockDB() *testDB {
 // *testSyncManager.
 func defaultMockSyncManager() *testSyncManager {
 	return &testSyncManager{
-		isOrphan:   false,
-		syncHeight: 463074,
+		isOrphan:     false,
+		syncHeight:   463074,
+		syncPeerInfo: &SyncPeerInfo{SyncHeight: 463074},
 	}
 }
 
@@ -1638,6 +1916,337 @@ func TestHandleAddNode(t *testing.T) {
 	}})
 }
 
+func TestHandleCheckTransactionStandard(t *testing.T) {
+	t.Parallel()
+
+	tx := wire.NewMsgTx()
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, 0, nil))
+	tx.AddTxOut(wire.NewTxOut(1, nil))
+	txBytes, err := tx.Bytes()
+	if err != nil {
+		t.Fatalf("unexpected error serializing tx: %v", err)
+	}
+	hexTx := hex.EncodeToString(txBytes)
+
+	nonStandard := defaultMockTxMempooler()
+	nonStandard.violations = []mempool.StandardViolation{{
+		Rule:        mempool.RuleTxVersion,
+		Input:       -1,
+		Output:      -1,
+		Description: "transaction version 0 is not in the valid range of 1-2",
+	}, {
+		Rule:        mempool.RuleDust,
+		Input:       -1,
+		Output:      0,
+		Description: "transaction output 0: payment of 1 is dust",
+	}}
+	failing := defaultMockTxMempooler()
+	failing.violationsErr = errors.New("unable to fetch utxos")
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleCheckTransactionStandard: invalid hex",
+		handler: handleCheckTransactionStandard,
+		cmd:     &types.CheckTransactionStandardCmd{HexTx: "zz"},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCDecodeHexString,
+	}, {
+		name:    "handleCheckTransactionStandard: invalid tx",
+		handler: handleCheckTransactionStandard,
+		cmd:     &types.CheckTransactionStandardCmd{HexTx: "01"},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCDeserialization,
+	}, {
+		name:            "handleCheckTransactionStandard: mempool error",
+		handler:         handleCheckTransactionStandard,
+		cmd:             &types.CheckTransactionStandardCmd{HexTx: hexTx},
+		mockTxMempooler: failing,
+		wantErr:         true,
+		errCode:         dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleCheckTransactionStandard: standard",
+		handler: handleCheckTransactionStandard,
+		cmd:     &types.CheckTransactionStandardCmd{HexTx: hexTx},
+		result: &types.CheckTransactionStandardResult{
+			Standard:   true,
+			Violations: []types.StandardViolationResult{},
+		},
+	}, {
+		name:            "handleCheckTransactionStandard: violations",
+		handler:         handleCheckTransactionStandard,
+		cmd:             &types.CheckTransactionStandardCmd{HexTx: hexTx},
+		mockTxMempooler: nonStandard,
+		result: &types.CheckTransactionStandardResult{
+			Standard: false,
+			Violations: []types.StandardViolationResult{{
+				Rule:        "txversion",
+				Description: "transaction version 0 is not in the valid range of 1-2",
+			}, {
+				Rule:        "dust",
+				Output:      dcrjson.Int(0),
+				Description: "transaction output 0: payment of 1 is dust",
+			}},
+		},
+	}})
+}
+
+func TestHandleCompareChainWork(t *testing.T) {
+	t.Parallel()
+
+	// mineHeader returns a header at the provided height that builds on the
+	// provided parent with a nonce that satisfies the minimum regnet
+	// difficulty.
+	params := chaincfg.RegNetParams()
+	bits := params.PowLimitBits
+	mineHeader := func(prevHash chainhash.Hash, height uint32) wire.BlockHeader {
+		header := wire.BlockHeader{
+			Version:   7,
+			PrevBlock: prevHash,
+			Bits:      bits,
+			Height:    height,
+			Timestamp: time.Unix(1592918788, 0),
+		}
+		for {
+			hash := header.BlockHash()
+			if standalone.CheckProofOfWork(&hash, bits, params.PowLimit) == nil {
+				return header
+			}
+			header.Nonce++
+		}
+	}
+	headerHex := func(header *wire.BlockHeader) string {
+		bytes, err := header.Bytes()
+		if err != nil {
+			t.Fatalf("unexpected error serializing header: %v", err)
+		}
+		return hex.EncodeToString(bytes)
+	}
+
+	// Create a local chain that consists of a grandparent in the main chain
+	// and a parent that is in either the main chain or a side chain along with
+	// a foreign chain of two headers that builds on the parent.
+	grandparent := wire.BlockHeader{Height: 99, Bits: bits}
+	grandparentHash := grandparent.BlockHash()
+	parent := wire.BlockHeader{PrevBlock: grandparentHash, Height: 100, Bits: bits}
+	parentHash := parent.BlockHash()
+	header1 := mineHeader(parentHash, 101)
+	header1Hash := header1.BlockHash()
+	header2 := mineHeader(header1Hash, 102)
+	header2Hash := header2.BlockHash()
+	hexHeaders := []string{headerHex(&header1), headerHex(&header2)}
+
+	// The mock chain work is the same for all blocks, so the foreign chain
+	// has the work of the parent plus that of the two headers.
+	bestChainWork, _ := new(big.Int).SetString("0e805fb85284503581c57c", 16)
+	chainWork := new(big.Int).Add(bestChainWork, standalone.CalcWork(bits))
+	chainWork.Add(chainWork, standalone.CalcWork(bits))
+	blk := dcrutil.NewBlock(&block432100)
+
+	// mockChain returns a mock chain that knows the grandparent and parent
+	// and where only the provided blocks are in the main chain.
+	mockChain := func(mainChain ...chainhash.Hash) *testRPCChain {
+		chain := defaultMockRPCChain()
+		chain.headerByHashFn = func(hash *chainhash.Hash) (wire.BlockHeader, error) {
+			switch *hash {
+			case grandparentHash:
+				return grandparent, nil
+			case parentHash:
+				return parent, nil
+			}
+			return wire.BlockHeader{}, fmt.Errorf("block %s not found", hash)
+		}
+		chain.mainChainHasBlockFn = func(hash *chainhash.Hash) bool {
+			for i := range mainChain {
+				if *hash == mainChain[i] {
+					return true
+				}
+			}
+			return false
+		}
+		return chain
+	}
+	result := func(forkHash *chainhash.Hash, forkHeight int64) *types.CompareChainWorkResult {
+		return &types.CompareChainWorkResult{
+			ForkHash:      forkHash.String(),
+			ForkHeight:    forkHeight,
+			TipHash:       header2Hash.String(),
+			TipHeight:     102,
+			ChainWork:     fmt.Sprintf("%064x", chainWork),
+			BestHash:      blk.Hash().String(),
+			BestHeight:    blk.Height(),
+			BestChainWork: fmt.Sprintf("%064x", bestChainWork),
+			MoreWork:      true,
+		}
+	}
+
+	badPoW := header1
+	badPoW.Bits = params.PowLimitBits - 0x01000000
+	testRPCServerHandler(t, []rpcTest{{
+		name:            "handleCompareChainWork: no headers",
+		handler:         handleCompareChainWork,
+		cmd:             &types.CompareChainWorkCmd{},
+		mockChainParams: params,
+		wantErr:         true,
+		errCode:         dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleCompareChainWork: invalid hex",
+		handler: handleCompareChainWork,
+		cmd: &types.CompareChainWorkCmd{
+			Headers: []string{"zz"},
+		},
+		mockChainParams: params,
+		wantErr:         true,
+		errCode:         dcrjson.ErrRPCDecodeHexString,
+	}, {
+		name:    "handleCompareChainWork: invalid header",
+		handler: handleCompareChainWork,
+		cmd: &types.CompareChainWorkCmd{
+			Headers: []string{"01"},
+		},
+		mockChainParams: params,
+		wantErr:         true,
+		errCode:         dcrjson.ErrRPCDeserialization,
+	}, {
+		name:    "handleCompareChainWork: headers do not connect",
+		handler: handleCompareChainWork,
+		cmd: &types.CompareChainWorkCmd{
+			Headers: []string{hexHeaders[0], hexHeaders[0]},
+		},
+		mockChainParams: params,
+		wantErr:         true,
+		errCode:         dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleCompareChainWork: invalid proof of work",
+		handler: handleCompareChainWork,
+		cmd: &types.CompareChainWorkCmd{
+			Headers: []string{headerHex(&badPoW)},
+		},
+		mockChainParams: params,
+		wantErr:         true,
+		errCode:         dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleCompareChainWork: unknown parent",
+		handler: handleCompareChainWork,
+		cmd: &types.CompareChainWorkCmd{
+			Headers: hexHeaders[1:],
+		},
+		mockChainParams: params,
+		mockChain:       mockChain(grandparentHash, parentHash),
+		wantErr:         true,
+		errCode:         dcrjson.ErrRPCBlockNotFound,
+	}, {
+		name:    "handleCompareChainWork: fork at parent",
+		handler: handleCompareChainWork,
+		cmd: &types.CompareChainWorkCmd{
+			Headers: hexHeaders,
+		},
+		mockChainParams: params,
+		mockChain:       mockChain(grandparentHash, parentHash),
+		result:          result(&parentHash, 100),
+	}, {
+		name:    "handleCompareChainWork: fork at first header",
+		handler: handleCompareChainWork,
+		cmd: &types.CompareChainWorkCmd{
+			Headers: hexHeaders,
+		},
+		mockChainParams: params,
+		mockChain:       mockChain(grandparentHash, parentHash, header1Hash),
+		result:          result(&header1Hash, 101),
+	}, {
+		name:    "handleCompareChainWork: parent in side chain",
+		handler: handleCompareChainWork,
+		cmd: &types.CompareChainWorkCmd{
+			Headers: hexHeaders,
+		},
+		mockChainParams: params,
+		mockChain:       mockChain(grandparentHash),
+		result:          result(&grandparentHash, 99),
+	}})
+}
+
+func TestHandleCoordHeartbeat(t *testing.T) {
+	t.Parallel()
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleCoordHeartbeat: ok",
+		handler: handleCoordHeartbeat,
+		cmd: &types.CoordHeartbeatCmd{
+			NodeID: "node2",
+			Term:   4,
+			Leader: false,
+		},
+		mockCoordinator: &testCoordinator{
+			leader: true,
+			state: &CoordinationState{
+				NodeID: "node1",
+				Term:   4,
+				Leader: true,
+			},
+		},
+		result: &types.CoordHeartbeatResult{
+			NodeID: "node1",
+			Term:   4,
+			Leader: true,
+		},
+	}, {
+		name:    "handleCoordHeartbeat: coordination not enabled",
+		handler: handleCoordHeartbeat,
+		cmd: &types.CoordHeartbeatCmd{
+			NodeID: "node2",
+			Term:   4,
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCMisc,
+	}, {
+		name:    "handleCoordHeartbeat: invalid heartbeat",
+		handler: handleCoordHeartbeat,
+		cmd: &types.CoordHeartbeatCmd{
+			NodeID: "node1",
+			Term:   4,
+		},
+		mockCoordinator: &testCoordinator{
+			err: errors.New("peer has the same node id"),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}})
+}
+
+// TestStandbyRPC ensures the commands that serve miners are rejected while the
+// server is the standby of a redundancy pair and that other commands are still
+// dispatched.
+func TestStandbyRPC(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	coordinator := &testCoordinator{leader: false}
+	s := &Server{cfg: Config{Coordinator: coordinator}}
+	for method := range rpcLeaderOnly {
+		cmd := &parsedRPCCmd{method: types.Method(method)}
+		_, err := s.standardCmdResult(ctx, cmd)
+		if !errors.Is(err, ErrRPCStandby) {
+			t.Fatalf("%s: unexpected dispatch error -- got %v, want %v",
+				method, err, ErrRPCStandby)
+		}
+	}
+	cmd := &parsedRPCCmd{method: "version", params: &types.VersionCmd{}}
+	if _, err := s.standardCmdResult(ctx, cmd); err != nil {
+		t.Fatalf("unexpected dispatch error: %v", err)
+	}
+
+	// Ensure the server only considers itself on standby when it is part
+	// of a pair and not the leader.
+	if !s.isStandby() {
+		t.Fatal("server is not on standby while not the leader")
+	}
+	coordinator.leader = true
+	if s.isStandby() {
+		t.Fatal("server is on standby while the leader")
+	}
+	s = &Server{}
+	if s.isStandby() {
+		t.Fatal("server is on standby without a coordinator")
+	}
+}
+
 func TestHandleCreateRawSStx(t *testing.T) {
 	t.Parallel()
 
@@ -2261,6 +2870,132 @@ func TestHandleCreateRawTransaction(t *testing.T) {
 	}})
 }
 
+func TestHandleCreateRevocation(t *testing.T) {
+	t.Parallel()
+
+	ticketHash := "1189cbe656c2ef1e0fcb91f107624d9aa8f0db7b28e6a86f694a4cf49abc5e39"
+	missedChain := func() *testRPCChain {
+		chain := defaultMockRPCChain()
+		chain.checkMissedTickets = []bool{true}
+		chain.checkExpiredTickets = []bool{false}
+		return chain
+	}
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleCreateRevocation: ok missed",
+		handler: handleCreateRevocation,
+		cmd: &types.CreateRevocationCmd{
+			TicketHash: ticketHash,
+		},
+		mockChain: missedChain(),
+		result: "0100000001395ebc9af44c4a696fa8e6287bdbf0a89a4d6207f191cb0f1eefc25" +
+			"6e6cb89110000000001ffffffff0100e1f5050000000000001abc76a914355c96f" +
+			"48612d57509140e9a049981d5f9970f9488ac00000000000000000100e1f505000" +
+			"0000000000000ffffffff00",
+	}, {
+		name:    "handleCreateRevocation: ok expired with fee",
+		handler: handleCreateRevocation,
+		cmd: &types.CreateRevocationCmd{
+			TicketHash: ticketHash,
+			Fee:        dcrjson.Float64(0.0001),
+		},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.checkMissedTickets = []bool{false}
+			chain.checkExpiredTickets = []bool{true}
+			return chain
+		}(),
+		result: "0100000001395ebc9af44c4a696fa8e6287bdbf0a89a4d6207f191cb0f1eefc25" +
+			"6e6cb89110000000001ffffffff01f0b9f5050000000000001abc76a914355c96f" +
+			"48612d57509140e9a049981d5f9970f9488ac00000000000000000100e1f505000" +
+			"0000000000000ffffffff00",
+	}, {
+		name:    "handleCreateRevocation: invalid ticket hash",
+		handler: handleCreateRevocation,
+		cmd: &types.CreateRevocationCmd{
+			TicketHash: "g",
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCDecodeHexString,
+	}, {
+		name:    "handleCreateRevocation: invalid fee amount",
+		handler: handleCreateRevocation,
+		cmd: &types.CreateRevocationCmd{
+			TicketHash: ticketHash,
+			Fee:        dcrjson.Float64(math.Inf(1)),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleCreateRevocation: no tx info",
+		handler: handleCreateRevocation,
+		cmd: &types.CreateRevocationCmd{
+			TicketHash: ticketHash,
+		},
+		mockChain: func() *testRPCChain {
+			chain := missedChain()
+			chain.fetchUtxoEntry = nil
+			return chain
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCNoTxInfo,
+	}, {
+		name:    "handleCreateRevocation: not a ticket",
+		handler: handleCreateRevocation,
+		cmd: &types.CreateRevocationCmd{
+			TicketHash: ticketHash,
+		},
+		mockChain: func() *testRPCChain {
+			chain := missedChain()
+			chain.fetchUtxoEntry = &testRPCUtxoEntry{
+				txType: stake.TxTypeRegular,
+			}
+			return chain
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleCreateRevocation: ticket already spent",
+		handler: handleCreateRevocation,
+		cmd: &types.CreateRevocationCmd{
+			TicketHash: ticketHash,
+		},
+		mockChain: func() *testRPCChain {
+			chain := missedChain()
+			chain.fetchUtxoEntry = &testRPCUtxoEntry{
+				txType:        stake.TxTypeSStx,
+				isOutputSpent: true,
+			}
+			return chain
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleCreateRevocation: ticket neither missed nor expired",
+		handler: handleCreateRevocation,
+		cmd: &types.CreateRevocationCmd{
+			TicketHash: ticketHash,
+		},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.checkMissedTickets = []bool{false}
+			chain.checkExpiredTickets = []bool{false}
+			return chain
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleCreateRevocation: fee exceeds limits",
+		handler: handleCreateRevocation,
+		cmd: &types.CreateRevocationCmd{
+			TicketHash: ticketHash,
+			Fee:        dcrjson.Float64(1),
+		},
+		mockChain: missedChain(),
+		wantErr:   true,
+		errCode:   dcrjson.ErrRPCInvalidParameter,
+	}})
+}
+
 func TestHandleDebugLevel(t *testing.T) {
 	t.Parallel()
 
@@ -2505,6 +3240,43 @@ func TestHandleDecodeScript(t *testing.T) {
 	}})
 }
 
+func TestHandleDumpPeerTelemetry(t *testing.T) {
+	t.Parallel()
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleDumpPeerTelemetry: ok",
+		handler: handleDumpPeerTelemetry,
+		cmd:     &types.DumpPeerTelemetryCmd{},
+		mockPeerTelemetry: &testPeerTelemetry{
+			dump: &PeerTelemetryDump{
+				Path:       "/data/peertelemetry/peertelemetry.jsonl",
+				NumEvents:  1500,
+				NumDropped: 25,
+			},
+		},
+		result: &types.DumpPeerTelemetryResult{
+			File:    "/data/peertelemetry/peertelemetry.jsonl",
+			Events:  1500,
+			Dropped: 25,
+		},
+	}, {
+		name:    "handleDumpPeerTelemetry: telemetry not enabled",
+		handler: handleDumpPeerTelemetry,
+		cmd:     &types.DumpPeerTelemetryCmd{},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCMisc,
+	}, {
+		name:    "handleDumpPeerTelemetry: dump failed",
+		handler: handleDumpPeerTelemetry,
+		cmd:     &types.DumpPeerTelemetryCmd{},
+		mockPeerTelemetry: &testPeerTelemetry{
+			err: errors.New("permission denied"),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}})
+}
+
 func TestHandleEstimateFee(t *testing.T) {
 	t.Parallel()
 
@@ -2687,11 +3459,105 @@ func TestHandleEstimateStakeDiff(t *testing.T) {
 	}})
 }
 
-func TestHandleExistsAddress(t *testing.T) {
+func TestHandleEvaluateTxLocks(t *testing.T) {
 	t.Parallel()
 
-	validAddr := "DcurAwesomeAddressmqDctW5wJCW1Cn2MF"
-	testRPCServerHandler(t, []rpcTest{{
+	tx := wire.NewMsgTx()
+	tx.Version = 2
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, 0, nil))
+	tx.AddTxOut(wire.NewTxOut(1, nil))
+	tx.LockTime = 100
+	txBytes, err := tx.Bytes()
+	if err != nil {
+		t.Fatalf("unexpected error serializing tx: %v", err)
+	}
+	hexTx := hex.EncodeToString(txBytes)
+
+	medianTime := time.Unix(1592918788, 0)
+	locked := defaultMockTxMempooler()
+	locked.lockStatus = &mempool.LockStatus{
+		NextHeight:             90,
+		MedianTime:             medianTime,
+		LockTimeHeight:         101,
+		SequenceLockMedianTime: medianTime.Unix() + 512,
+		EarliestHeight:         101,
+		EarliestMedianTime:     medianTime.Unix() + 512,
+	}
+	final := defaultMockTxMempooler()
+	final.lockStatus = &mempool.LockStatus{
+		NextHeight:     101,
+		MedianTime:     medianTime,
+		LockTimeHeight: 101,
+		EarliestHeight: 101,
+		Final:          true,
+	}
+	missingInput := defaultMockTxMempooler()
+	missingInput.lockStatusErr = blockchain.RuleError{
+		ErrorCode:   blockchain.ErrMissingTxOut,
+		Description: "output is either already spent or does not exist",
+	}
+	failing := defaultMockTxMempooler()
+	failing.lockStatusErr = errors.New("unable to fetch utxos")
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleEvaluateTxLocks: invalid hex",
+		handler: handleEvaluateTxLocks,
+		cmd:     &types.EvaluateTxLocksCmd{HexTx: "zz"},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCDecodeHexString,
+	}, {
+		name:    "handleEvaluateTxLocks: invalid tx",
+		handler: handleEvaluateTxLocks,
+		cmd:     &types.EvaluateTxLocksCmd{HexTx: "01"},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCDeserialization,
+	}, {
+		name:            "handleEvaluateTxLocks: missing input",
+		handler:         handleEvaluateTxLocks,
+		cmd:             &types.EvaluateTxLocksCmd{HexTx: hexTx},
+		mockTxMempooler: missingInput,
+		wantErr:         true,
+		errCode:         dcrjson.ErrRPCMisc,
+	}, {
+		name:            "handleEvaluateTxLocks: mempool error",
+		handler:         handleEvaluateTxLocks,
+		cmd:             &types.EvaluateTxLocksCmd{HexTx: hexTx},
+		mockTxMempooler: failing,
+		wantErr:         true,
+		errCode:         dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:            "handleEvaluateTxLocks: locked",
+		handler:         handleEvaluateTxLocks,
+		cmd:             &types.EvaluateTxLocksCmd{HexTx: hexTx},
+		mockTxMempooler: locked,
+		result: &types.EvaluateTxLocksResult{
+			Final:                  false,
+			NextHeight:             90,
+			MedianTime:             medianTime.Unix(),
+			LockTimeHeight:         101,
+			SequenceLockMedianTime: medianTime.Unix() + 512,
+			EarliestHeight:         101,
+			EarliestMedianTime:     medianTime.Unix() + 512,
+		},
+	}, {
+		name:            "handleEvaluateTxLocks: final",
+		handler:         handleEvaluateTxLocks,
+		cmd:             &types.EvaluateTxLocksCmd{HexTx: hexTx},
+		mockTxMempooler: final,
+		result: &types.EvaluateTxLocksResult{
+			Final:          true,
+			NextHeight:     101,
+			MedianTime:     medianTime.Unix(),
+			LockTimeHeight: 101,
+			EarliestHeight: 101,
+		},
+	}})
+}
+
+func TestHandleExistsAddress(t *testing.T) {
+	t.Parallel()
+
+	validAddr := "DcurAwesomeAddressmqDctW5wJCW1Cn2MF"
+	testRPCServerHandler(t, []rpcTest{{
 		name:    "handleExistsAddress: ok",
 		handler: handleExistsAddress,
 		cmd: &types.ExistsAddressCmd{
@@ -3189,6 +4055,76 @@ func TestHandleGenerate(t *testing.T) {
 	}})
 }
 
+func TestHandleGenerateToAddress(t *testing.T) {
+	t.Parallel()
+
+	hashStrOne := "00000000000000001e6ec1501c858506de1de4703d1be8bab4061126e8f61480"
+	hashStrTwo := "00000000000000001a1ec2becd0dd90bfbd0c65f42fdaf608dd9ceac2a3aee1d"
+	generatedBlocks := []*chainhash.Hash{mustParseHash(hashStrOne), mustParseHash(hashStrTwo)}
+	res := []string{hashStrOne, hashStrTwo}
+	chainParams := chaincfg.SimNetParams()
+	const addr = "SsWKp7wtdTZYabYFYSc9cnxhwFEjA5g4pFc"
+	cpu := defaultMockCPUMiner()
+	cpu.generatedBlocks = generatedBlocks
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleGenerateToAddress: ok",
+		handler: handleGenerateToAddress,
+		cmd: &types.GenerateToAddressCmd{
+			NumBlocks: 2,
+			Address:   addr,
+		},
+		mockChainParams: chainParams,
+		mockCPUMiner:    cpu,
+		result:          res,
+	}, {
+		name:    "handleGenerateToAddress: not supported for network",
+		handler: handleGenerateToAddress,
+		cmd: &types.GenerateToAddressCmd{
+			NumBlocks: 2,
+			Address:   addr,
+		},
+		mockCPUMiner: cpu,
+		wantErr:      true,
+		errCode:      dcrjson.ErrRPCDifficulty,
+	}, {
+		name:    "handleGenerateToAddress: generate 0 blocks",
+		handler: handleGenerateToAddress,
+		cmd: &types.GenerateToAddressCmd{
+			Address: addr,
+		},
+		mockChainParams: chainParams,
+		mockCPUMiner:    cpu,
+		wantErr:         true,
+		errCode:         dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleGenerateToAddress: invalid address",
+		handler: handleGenerateToAddress,
+		cmd: &types.GenerateToAddressCmd{
+			NumBlocks: 2,
+			Address:   "DcurAwesomeAddressmqDctW5wJCW1Cn2MF",
+		},
+		mockChainParams: chainParams,
+		mockCPUMiner:    cpu,
+		wantErr:         true,
+		errCode:         dcrjson.ErrRPCInvalidAddressOrKey,
+	}, {
+		name:    "handleGenerateToAddress: generate n blocks error",
+		handler: handleGenerateToAddress,
+		cmd: &types.GenerateToAddressCmd{
+			NumBlocks: 2,
+			Address:   addr,
+		},
+		mockChainParams: chainParams,
+		mockCPUMiner: func() *testCPUMiner {
+			cpu := defaultMockCPUMiner()
+			cpu.generateNBlocksErr = errors.New("")
+			return cpu
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}})
+}
+
 func TestHandleGetAddedNodeInfo(t *testing.T) {
 	t.Parallel()
 
@@ -3200,6 +4136,21 @@ func TestHandleGetAddedNodeInfo(t *testing.T) {
 		},
 		result: []string{"127.0.0.210:9108", "127.0.0.211:9108",
 			"mydomain.org:9108", "nonexistentdomain.org:9108"},
+	}, {
+		name:    "handleGetAddedNodeInfo: ok sorted without DNS",
+		handler: handleGetAddedNodeInfo,
+		cmd: &types.GetAddedNodeInfoCmd{
+			DNS: false,
+		},
+		mockConnManager: func() *testConnManager {
+			connManager := defaultMockConnManager()
+			peers := connManager.addedNodeInfo
+			connManager.addedNodeInfo = []Peer{peers[3], peers[1],
+				peers[0], peers[2]}
+			return connManager
+		}(),
+		result: []string{"127.0.0.210:9108", "127.0.0.211:9108",
+			"mydomain.org:9108", "nonexistentdomain.org:9108"},
 	}, {
 		name:    "handleGetAddedNodeInfo: found without DNS and with address filter",
 		handler: handleGetAddedNodeInfo,
@@ -3270,6 +4221,238 @@ func TestHandleGetAddedNodeInfo(t *testing.T) {
 	}})
 }
 
+func TestHandleGetAddressActivity(t *testing.T) {
+	t.Parallel()
+
+	const address = "DsbjabD32RuS1deAj2uTjKfFZ6nSza5qVf3"
+	blkHash := defaultMockRPCChain().blockHashByHeight.String()
+	activity := &testAddrActivityIndexer{
+		activity: &indexers.AddrActivity{FirstSeen: 432098, LastSeen: 432100},
+	}
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleGetAddressActivity: ok",
+		handler: handleGetAddressActivity,
+		cmd: &types.GetAddressActivityCmd{
+			Address: address,
+		},
+		mockAddrActivity: activity,
+		result: &types.GetAddressActivityResult{
+			Address:         address,
+			Seen:            true,
+			FirstSeenHeight: 432098,
+			FirstSeenHash:   blkHash,
+			LastSeenHeight:  432100,
+			LastSeenHash:    blkHash,
+		},
+	}, {
+		name:    "handleGetAddressActivity: not seen",
+		handler: handleGetAddressActivity,
+		cmd: &types.GetAddressActivityCmd{
+			Address: address,
+		},
+		mockAddrActivity: &testAddrActivityIndexer{},
+		result: &types.GetAddressActivityResult{
+			Address: address,
+		},
+	}, {
+		name:    "handleGetAddressActivity: index disabled",
+		handler: handleGetAddressActivity,
+		cmd: &types.GetAddressActivityCmd{
+			Address: address,
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleGetAddressActivity: invalid address",
+		handler: handleGetAddressActivity,
+		cmd: &types.GetAddressActivityCmd{
+			Address: "invalid",
+		},
+		mockAddrActivity: activity,
+		wantErr:          true,
+		errCode:          dcrjson.ErrRPCInvalidAddressOrKey,
+	}, {
+		name:    "handleGetAddressActivity: query error",
+		handler: handleGetAddressActivity,
+		cmd: &types.GetAddressActivityCmd{
+			Address: address,
+		},
+		mockAddrActivity: &testAddrActivityIndexer{
+			err: errors.New("query error"),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleGetAddressActivity: block hash error",
+		handler: handleGetAddressActivity,
+		cmd: &types.GetAddressActivityCmd{
+			Address: address,
+		},
+		mockAddrActivity: activity,
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.blockHashByHeightErr = errors.New("no block")
+			return chain
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}})
+}
+
+func TestHandleGetAddressUtxos(t *testing.T) {
+	t.Parallel()
+
+	// The first two outputs of the test transaction pay to the test address.
+	address := "DsbjabD32RuS1deAj2uTjKfFZ6nSza5qVf3"
+	txHex := hexFromFile("tx432098-11.hex")
+	msgTx := hexToMsgTx(txHex)
+	txHash := msgTx.TxHash().String()
+	addrIndexer := defaultMockAddrIndexer()
+	addrIndexer.entriesForAddress = []indexers.TxIndexEntry{{
+		BlockRegion: database.BlockRegion{
+			Hash: mustParseHash("00000000000000001fc4c4c7a3f2ec6d552dda16a3a928f27bd6" +
+				"bd16d8f1e9b3"),
+			Offset: 52508,
+			Len:    453,
+		},
+		BlockIndex: 11,
+	}}
+	db := defaultMockDB()
+	db.viewTx = &testDatabaseTx{
+		fetchBlockRegions: func(regions []database.BlockRegion) ([][]byte, error) {
+			return [][]byte{hexToBytes(txHex)}, nil
+		},
+	}
+	chain := defaultMockRPCChain()
+	chain.fetchUtxoEntry = &testRPCUtxoEntry{
+		txType: stake.TxTypeRegular,
+		height: 432098,
+	}
+	confirmations := 1 + chain.bestSnapshot.Height - 432098
+	utxo := func(vout uint32) types.AddressUtxo {
+		txOut := msgTx.TxOut[vout]
+		return types.AddressUtxo{
+			Txid:          txHash,
+			Vout:          vout,
+			Tree:          wire.TxTreeRegular,
+			Amount:        dcrutil.Amount(txOut.Value).ToCoin(),
+			ScriptPubKey:  hex.EncodeToString(txOut.PkScript),
+			Height:        432098,
+			Confirmations: confirmations,
+		}
+	}
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleGetAddressUtxos: ok",
+		handler: handleGetAddressUtxos,
+		cmd: &types.GetAddressUtxosCmd{
+			Address: address,
+		},
+		mockAddrIndexer: addrIndexer,
+		mockDB:          db,
+		mockChain:       chain,
+		result: &types.GetAddressUtxosResult{
+			Utxos: []types.AddressUtxo{utxo(0), utxo(1)},
+			Page: types.PageInfo{
+				Limit:         100,
+				TotalEstimate: 2,
+			},
+		},
+	}, {
+		name:    "handleGetAddressUtxos: ok first page",
+		handler: handleGetAddressUtxos,
+		cmd: &types.GetAddressUtxosCmd{
+			Address: address,
+			Limit:   dcrjson.Int(1),
+		},
+		mockAddrIndexer: addrIndexer,
+		mockDB:          db,
+		mockChain:       chain,
+		result: &types.GetAddressUtxosResult{
+			Utxos: []types.AddressUtxo{utxo(0)},
+			Page: types.PageInfo{
+				NextCursor:    "000000000000000100000001",
+				Limit:         1,
+				TotalEstimate: 2,
+			},
+		},
+	}, {
+		name:    "handleGetAddressUtxos: ok final page",
+		handler: handleGetAddressUtxos,
+		cmd: &types.GetAddressUtxosCmd{
+			Address: address,
+			Cursor:  dcrjson.String("000000000000000100000001"),
+			Limit:   dcrjson.Int(1),
+		},
+		mockAddrIndexer: addrIndexer,
+		mockDB:          db,
+		mockChain:       chain,
+		result: &types.GetAddressUtxosResult{
+			Utxos: []types.AddressUtxo{utxo(1)},
+			Page: types.PageInfo{
+				Limit:         1,
+				TotalEstimate: 2,
+			},
+		},
+	}, {
+		name:    "handleGetAddressUtxos: ok spent outputs",
+		handler: handleGetAddressUtxos,
+		cmd: &types.GetAddressUtxosCmd{
+			Address: address,
+		},
+		mockAddrIndexer: addrIndexer,
+		mockDB:          db,
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.fetchUtxoEntry = &testRPCUtxoEntry{isOutputSpent: true}
+			return chain
+		}(),
+		result: &types.GetAddressUtxosResult{
+			Utxos: []types.AddressUtxo{},
+			Page:  types.PageInfo{Limit: 100},
+		},
+	}, {
+		name:    "handleGetAddressUtxos: address index not enabled",
+		handler: handleGetAddressUtxos,
+		cmd: &types.GetAddressUtxosCmd{
+			Address: address,
+		},
+		setAddrIndexerNil: true,
+		wantErr:           true,
+		errCode:           dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleGetAddressUtxos: invalid address",
+		handler: handleGetAddressUtxos,
+		cmd: &types.GetAddressUtxosCmd{
+			Address: "invalid",
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidAddressOrKey,
+	}, {
+		name:    "handleGetAddressUtxos: invalid cursor",
+		handler: handleGetAddressUtxos,
+		cmd: &types.GetAddressUtxosCmd{
+			Address: address,
+			Cursor:  dcrjson.String("00000001"),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleGetAddressUtxos: entries for address err",
+		handler: handleGetAddressUtxos,
+		cmd: &types.GetAddressUtxosCmd{
+			Address: address,
+		},
+		mockAddrIndexer: func() *testAddrIndexer {
+			addrIndexer := defaultMockAddrIndexer()
+			addrIndexer.entriesForAddressErr = errors.New("entries for address err")
+			return addrIndexer
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}})
+}
+
 func TestHandleGetBestBlock(t *testing.T) {
 	t.Parallel()
 
@@ -3339,6 +4522,73 @@ func TestHandleGetBlockchainInfo(t *testing.T) {
 					Since:      int64(149248),
 					StartTime:  uint64(1567641600),
 					ExpireTime: uint64(1599264000),
+					Version:    7,
+				},
+			},
+		},
+	}, {
+		name:    "handleGetBlockchainInfo: ok with concluded vote",
+		handler: handleGetBlockchainInfo,
+		cmd:     &types.GetBlockChainInfoCmd{},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.bestSnapshot = &blockchain.BestState{
+				Height:   463073,
+				Bits:     404696953,
+				Hash:     *hash,
+				PrevHash: *prevHash,
+			}
+			chain.chainWork = big.NewInt(0).SetBytes([]byte{0x11, 0x5d, 0x28, 0x33, 0x84,
+				0x90, 0x90, 0xb0, 0x02, 0x65, 0x06})
+			chain.isCurrent = false
+			chain.maxBlockSize = 393216
+			chain.nextThresholdState = blockchain.ThresholdStateTuple{
+				State:  blockchain.ThresholdActive,
+				Choice: 2,
+			}
+			chain.stateLastChangedHeight = int64(431488)
+			chain.deploymentVoteResult = &blockchain.DeploymentVoteResult{
+				DecisionHeight:   423423,
+				ActivationHeight: 431488,
+				Counts: blockchain.VoteCounts{
+					Total:        40319,
+					TotalAbstain: 11,
+					VoteChoices:  []uint32{11, 117, 40191},
+				},
+			}
+			return chain
+		}(),
+		result: types.GetBlockChainInfoResult{
+			Chain:                "mainnet",
+			Blocks:               int64(463073),
+			Headers:              int64(463073),
+			SyncHeight:           int64(463074),
+			ChainWork:            "000000000000000000000000000000000000000000115d2833849090b0026506",
+			InitialBlockDownload: true,
+			VerificationProgress: float64(0.9999978405179302),
+			BestBlockHash:        "00000000000000001e6ec1501c858506de1de4703d1be8bab4061126e8f61480",
+			Difficulty:           uint32(404696953),
+			DifficultyRatio:      float64(35256672611.3862),
+			MaxBlockSize:         int64(393216),
+			Deployments: map[string]types.AgendaInfo{
+				"headercommitments": {
+					Status:           "active",
+					Since:            int64(431488),
+					StartTime:        uint64(1567641600),
+					ExpireTime:       uint64(1599264000),
+					Version:          7,
+					ActivationHeight: 431488,
+					VoteResult: &types.AgendaVoteResult{
+						DecisionHeight: 423423,
+						Choice:         "yes",
+						TotalVotes:     40319,
+						AbstainVotes:   11,
+						Choices: map[string]uint32{
+							"abstain": 11,
+							"no":      117,
+							"yes":     40191,
+						},
+					},
 				},
 			},
 		},
@@ -3388,6 +4638,7 @@ func TestHandleGetBlockchainInfo(t *testing.T) {
 					Since:      int64(0),
 					StartTime:  uint64(1567641600),
 					ExpireTime: uint64(1599264000),
+					Version:    7,
 				},
 			},
 		},
@@ -3462,6 +4713,26 @@ func TestHandleGetBlockchainInfo(t *testing.T) {
 		}(),
 		wantErr: true,
 		errCode: dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleGetBlockchainInfo: could not fetch vote result",
+		handler: handleGetBlockchainInfo,
+		cmd:     &types.GetBlockChainInfoCmd{},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.bestSnapshot = &blockchain.BestState{
+				Height:   463073,
+				Bits:     404696953,
+				Hash:     *hash,
+				PrevHash: *prevHash,
+			}
+			chain.chainWork = big.NewInt(0).SetBytes([]byte{0x11, 0x5d, 0x28, 0x33, 0x84,
+				0x90, 0x90, 0xb0, 0x02, 0x65, 0x06})
+			chain.maxBlockSize = 393216
+			chain.deploymentVoteResultErr = errors.New("could not fetch vote result")
+			return chain
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
 	}})
 }
 
@@ -3534,6 +4805,7 @@ func TestHandleGetBlock(t *testing.T) {
 			}
 			chain.blockByHash = blk
 			chain.blockHashByHeight = nextHash
+			chain.blockReceiveTime = blkHeader.Timestamp.Add(2 * time.Second)
 			return chain
 		}(),
 		result: types.GetBlockVerboseResult{
@@ -3550,6 +4822,7 @@ func TestHandleGetBlock(t *testing.T) {
 			Revocations:   blkHeader.Revocations,
 			PoolSize:      blkHeader.PoolSize,
 			Time:          blkHeader.Timestamp.Unix(),
+			ReceivedTime:  blkHeader.Timestamp.Unix() + 2,
 			StakeVersion:  blkHeader.StakeVersion,
 			Confirmations: confirmations,
 			Height:        int64(blkHeader.Height),
@@ -3656,6 +4929,21 @@ func TestHandleGetBlock(t *testing.T) {
 		}(),
 		wantErr: true,
 		errCode: dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleGetBlock: could not fetch receive time",
+		handler: handleGetBlock,
+		cmd: &types.GetBlockCmd{
+			Hash:      blkHashString,
+			Verbose:   dcrjson.Bool(true),
+			VerboseTx: dcrjson.Bool(false),
+		},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.blockReceiveTimeErr = errors.New("could not fetch receive time")
+			return chain
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
 	}, {
 		name:    "handleGetBlock: no next block",
 		handler: handleGetBlock,
@@ -3838,12 +5126,79 @@ func TestHandleGetBlockHeader(t *testing.T) {
 	}})
 }
 
-func TestHandleGetBlockSubsidy(t *testing.T) {
+func TestHandleGetBlockStats(t *testing.T) {
 	t.Parallel()
 
+	blkHeader := block432100.Header
+	blkHashString := block432100.BlockHash().String()
+	result := types.GetBlockStatsResult{
+		Hash:        blkHashString,
+		Height:      int64(blkHeader.Height),
+		MainChain:   true,
+		Time:        blkHeader.Timestamp.Unix(),
+		Size:        int32(blkHeader.Size),
+		NumTx:       len(block432100.Transactions),
+		NumSTx:      len(block432100.STransactions),
+		Voters:      blkHeader.Voters,
+		FreshStake:  blkHeader.FreshStake,
+		Revocations: blkHeader.Revocations,
+	}
+	receivedResult := result
+	receivedResult.ReceivedTime = blkHeader.Timestamp.Unix() + 1
+	receivedResult.ReceiveDelay = 1.5
 	testRPCServerHandler(t, []rpcTest{{
-		name:    "handleGetBlockSubsidy: ok",
-		handler: handleGetBlockSubsidy,
+		name:    "handleGetBlockStats: ok",
+		handler: handleGetBlockStats,
+		cmd:     &types.GetBlockStatsCmd{Hash: blkHashString},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.blockReceiveTime = blkHeader.Timestamp.Add(1500 *
+				time.Millisecond)
+			return chain
+		}(),
+		result: &receivedResult,
+	}, {
+		name:    "handleGetBlockStats: unknown receive time",
+		handler: handleGetBlockStats,
+		cmd:     &types.GetBlockStatsCmd{Hash: blkHashString},
+		result:  &result,
+	}, {
+		name:    "handleGetBlockStats: invalid hash",
+		handler: handleGetBlockStats,
+		cmd:     &types.GetBlockStatsCmd{Hash: "invalid"},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCDecodeHexString,
+	}, {
+		name:    "handleGetBlockStats: block not found",
+		handler: handleGetBlockStats,
+		cmd:     &types.GetBlockStatsCmd{Hash: blkHashString},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.blockByHashErr = errors.New("block not found")
+			return chain
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCBlockNotFound,
+	}, {
+		name:    "handleGetBlockStats: could not fetch receive time",
+		handler: handleGetBlockStats,
+		cmd:     &types.GetBlockStatsCmd{Hash: blkHashString},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.blockReceiveTimeErr = errors.New("could not fetch receive time")
+			return chain
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}})
+}
+
+func TestHandleGetBlockSubsidy(t *testing.T) {
+	t.Parallel()
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleGetBlockSubsidy: ok",
+		handler: handleGetBlockSubsidy,
 		cmd: &types.GetBlockSubsidyCmd{
 			Height: 463073,
 			Voters: 5,
@@ -3857,6 +5212,147 @@ func TestHandleGetBlockSubsidy(t *testing.T) {
 	}})
 }
 
+func TestHandleGetBlockTemplate(t *testing.T) {
+	t.Parallel()
+
+	// Create the expected result for the default mock template.
+	tmplTxns := func(txns []*wire.MsgTx) []types.GetBlockTemplateResultTx {
+		results := make([]types.GetBlockTemplateResultTx, 0, len(txns))
+		for _, tx := range txns {
+			txBytes, err := tx.Bytes()
+			if err != nil {
+				t.Fatalf("unexpected error serializing tx: %v", err)
+			}
+			results = append(results, types.GetBlockTemplateResultTx{
+				Data: hex.EncodeToString(txBytes),
+				Hash: tx.TxHash().String(),
+			})
+		}
+		return results
+	}
+	headerBytes, err := block432100.Header.Bytes()
+	if err != nil {
+		t.Fatalf("unexpected error serializing header: %v", err)
+	}
+	longPollID := block432100.Header.BlockHash().String()
+	templateResult := &types.GetBlockTemplateResult{
+		Header:        hex.EncodeToString(headerBytes),
+		Transactions:  tmplTxns(block432100.Transactions),
+		STransactions: tmplTxns(block432100.STransactions),
+		Height:        int64(block432100.Header.Height),
+		CurTime:       block432100.Header.Timestamp.Unix(),
+		Target: fmt.Sprintf("%064x",
+			standalone.CompactToBig(block432100.Header.Bits)),
+		LongPollID:   longPollID,
+		Capabilities: []string{"longpoll", "proposal"},
+	}
+
+	blk := dcrutil.NewBlock(&block432100)
+	blkBytes, err := blk.Bytes()
+	if err != nil {
+		t.Fatalf("unexpected error serializing block: %v", err)
+	}
+	blkHex := hex.EncodeToString(blkBytes)
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleGetBlockTemplate: default template request",
+		handler: handleGetBlockTemplate,
+		cmd:     &types.GetBlockTemplateCmd{},
+		result:  templateResult,
+	}, {
+		name:                 "handleGetBlockTemplate: not configured for mining",
+		handler:              handleGetBlockTemplate,
+		cmd:                  &types.GetBlockTemplateCmd{},
+		setBlockTemplaterNil: true,
+		wantErr:              true,
+		errCode:              dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleGetBlockTemplate: chain not current",
+		handler: handleGetBlockTemplate,
+		cmd:     &types.GetBlockTemplateCmd{},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.isCurrent = false
+			return chain
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCClientInInitialDownload,
+	}, {
+		name:    "handleGetBlockTemplate: invalid mode",
+		handler: handleGetBlockTemplate,
+		cmd: &types.GetBlockTemplateCmd{
+			Request: &types.TemplateRequest{Mode: "bogus"},
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleGetBlockTemplate: proposal without data",
+		handler: handleGetBlockTemplate,
+		cmd: &types.GetBlockTemplateCmd{
+			Request: &types.TemplateRequest{Mode: "proposal"},
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleGetBlockTemplate: proposal accepted",
+		handler: handleGetBlockTemplate,
+		cmd: &types.GetBlockTemplateCmd{
+			Request: &types.TemplateRequest{
+				Mode: "proposal",
+				Data: blkHex,
+			},
+		},
+		result: nil,
+	}, {
+		name:    "handleGetBlockTemplate: proposal rejected",
+		handler: handleGetBlockTemplate,
+		cmd: &types.GetBlockTemplateCmd{
+			Request: &types.TemplateRequest{
+				Mode: "proposal",
+				Data: blkHex,
+			},
+		},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.checkConnectBlockTemplateErr = blockchain.RuleError{
+				ErrorCode:   blockchain.ErrBadMerkleRoot,
+				Description: "block merkle root is invalid",
+			}
+			return chain
+		}(),
+		result: "rejected: block merkle root is invalid",
+	}})
+
+	// Ensure a long poll with an outdated id returns the current template
+	// immediately.
+	cfg := defaultMockConfig(defaultChainParams)
+	s := &Server{cfg: *cfg}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	result, err := handleGetBlockTemplate(ctx, s, &types.GetBlockTemplateCmd{
+		Request: &types.TemplateRequest{LongPollID: "outdated"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error for outdated long poll: %v", err)
+	}
+	if !reflect.DeepEqual(result, templateResult) {
+		t.Fatalf("unexpected result for outdated long poll -- got %v, "+
+			"want %v", spew.Sdump(result), spew.Sdump(templateResult))
+	}
+
+	// Ensure a long poll with the id of the current template waits until the
+	// context is done when no new template is generated.
+	ctx, cancel = context.WithTimeout(context.Background(),
+		time.Millisecond*50)
+	defer cancel()
+	_, err = handleGetBlockTemplate(ctx, s, &types.GetBlockTemplateCmd{
+		Request: &types.TemplateRequest{LongPollID: longPollID},
+	})
+	var rpcErr *dcrjson.RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != dcrjson.ErrRPCMisc {
+		t.Fatalf("unexpected error for canceled long poll: %v", err)
+	}
+}
+
 func TestHandleGetCFilter(t *testing.T) {
 	t.Parallel()
 
@@ -4111,6 +5607,140 @@ func TestHandleGetCFilterV2(t *testing.T) {
 	}})
 }
 
+func TestHandleGetChainParams(t *testing.T) {
+	t.Parallel()
+
+	mainNetResult := types.GetChainParamsResult{
+		Name:             "mainnet",
+		Net:              3652452601,
+		DefaultPort:      "9108",
+		GenesisHash:      "298e5cc3d985bfe7f81dc135f360abe089edd4396b86d2de66b0cef42b21d980",
+		MaxBlockSizes:    []int{393216},
+		MaxTxSize:        393216,
+		CoinbaseMaturity: 256,
+		PoW: types.ChainParamsPoW{
+			PowLimit:                 "00000000ffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+			PowLimitBits:             486604799,
+			TargetTimePerBlock:       300,
+			TargetTimespan:           43200,
+			RetargetAdjustmentFactor: 4,
+			WorkDiffAlpha:            1,
+			WorkDiffWindowSize:       144,
+			WorkDiffWindows:          20,
+		},
+		Subsidy: types.ChainParamsSubsidy{
+			BaseSubsidy:              3119582664,
+			MulSubsidy:               100,
+			DivSubsidy:               101,
+			SubsidyReductionInterval: 6144,
+			WorkRewardProportion:     6,
+			StakeRewardProportion:    3,
+			BlockTaxProportion:       1,
+		},
+		Stake: types.ChainParamsStake{
+			MinimumStakeDiff:        200000000,
+			TicketPoolSize:          8192,
+			TicketsPerBlock:         5,
+			TicketMaturity:          256,
+			TicketExpiry:            40960,
+			SStxChangeMaturity:      1,
+			TicketPoolSizeWeight:    4,
+			StakeDiffAlpha:          1,
+			StakeDiffWindowSize:     144,
+			StakeDiffWindows:        20,
+			StakeVersionInterval:    2016,
+			MaxFreshStakePerBlock:   20,
+			StakeEnabledHeight:      512,
+			StakeValidationHeight:   4096,
+			StakeMajorityMultiplier: 3,
+			StakeMajorityDivisor:    4,
+		},
+		AddressPrefixes: types.ChainParamsAddressPrefixes{
+			NetworkAddressPrefix: "D",
+			PubKeyAddrID:         "1386",
+			PubKeyHashAddrID:     "073f",
+			PKHEdwardsAddrID:     "071f",
+			PKHSchnorrAddrID:     "0701",
+			ScriptHashAddrID:     "071a",
+			PrivateKeyID:         "22de",
+			HDPrivateKeyID:       "02fda4e8",
+			HDPublicKeyID:        "02fda926",
+			SLIP0044CoinType:     42,
+			LegacyCoinType:       20,
+		},
+		RuleChange: types.ChainParamsRuleChange{
+			Quorum:     4032,
+			Multiplier: 3,
+			Divisor:    4,
+			Interval:   8064,
+		},
+		Deployments: []types.ChainParamsDeployment{{
+			Version: 7,
+			Agendas: []types.ChainParamsAgenda{{
+				ID:          chaincfg.VoteIDHeaderCommitments,
+				Description: "Enable header commitments as defined in DCP0005",
+				Mask:        0x0006,
+				StartTime:   1567641600,
+				ExpireTime:  1599264000,
+				Choices: []types.ChainParamsChoice{{
+					ID:          "abstain",
+					Description: "abstain voting for change",
+					Bits:        0x0000,
+					IsAbstain:   true,
+				}, {
+					ID:          "no",
+					Description: "keep the existing consensus rules",
+					Bits:        0x0002,
+					IsNo:        true,
+				}, {
+					ID:          "yes",
+					Description: "change to the new consensus rules",
+					Bits:        0x0004,
+				}},
+			}},
+		}},
+	}
+
+	// Create params with deployments for multiple stake versions to ensure
+	// they are ordered by version.
+	multiVersionParams := cloneParams(defaultChainParams)
+	multiVersionParams.Deployments = map[uint32][]chaincfg.ConsensusDeployment{
+		9: {{Vote: chaincfg.Vote{Id: "agenda9"}, StartTime: 3, ExpireTime: 4}},
+		8: {{Vote: chaincfg.Vote{Id: "agenda8"}, StartTime: 1, ExpireTime: 2}},
+	}
+	multiVersionResult := mainNetResult
+	multiVersionResult.Deployments = []types.ChainParamsDeployment{{
+		Version: 8,
+		Agendas: []types.ChainParamsAgenda{{
+			ID:         "agenda8",
+			StartTime:  1,
+			ExpireTime: 2,
+			Choices:    []types.ChainParamsChoice{},
+		}},
+	}, {
+		Version: 9,
+		Agendas: []types.ChainParamsAgenda{{
+			ID:         "agenda9",
+			StartTime:  3,
+			ExpireTime: 4,
+			Choices:    []types.ChainParamsChoice{},
+		}},
+	}}
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleGetChainParams: ok",
+		handler: handleGetChainParams,
+		cmd:     &types.GetChainParamsCmd{},
+		result:  &mainNetResult,
+	}, {
+		name:            "handleGetChainParams: deployments ordered by version",
+		handler:         handleGetChainParams,
+		cmd:             &types.GetChainParamsCmd{},
+		mockChainParams: multiVersionParams,
+		result:          &multiVersionResult,
+	}})
+}
+
 func TestHandleGetChainTips(t *testing.T) {
 	t.Parallel()
 
@@ -4176,6 +5806,145 @@ func TestHandleGetDifficulty(t *testing.T) {
 	}})
 }
 
+func TestHandleCaptureProfile(t *testing.T) {
+	t.Parallel()
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleCaptureProfile: capture not available",
+		handler: handleCaptureProfile,
+		cmd: &types.CaptureProfileCmd{
+			Type:     "heap",
+			Duration: dcrjson.Int(30),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCMisc,
+	}, {
+		name:    "handleCaptureProfile: invalid type",
+		handler: handleCaptureProfile,
+		cmd: &types.CaptureProfileCmd{
+			Type:     "bogus",
+			Duration: dcrjson.Int(30),
+		},
+		profileDir: "profiles",
+		wantErr:    true,
+		errCode:    dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleCaptureProfile: invalid duration",
+		handler: handleCaptureProfile,
+		cmd: &types.CaptureProfileCmd{
+			Type:     "cpu",
+			Duration: dcrjson.Int(301),
+		},
+		profileDir: "profiles",
+		wantErr:    true,
+		errCode:    dcrjson.ErrRPCInvalidParameter,
+	}})
+}
+
+func TestHandleGetDiskSpaceInfo(t *testing.T) {
+	t.Parallel()
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleGetDiskSpaceInfo: ok",
+		handler: handleGetDiskSpaceInfo,
+		cmd:     &types.GetDiskSpaceInfoCmd{},
+		mockDiskSpaceMonitor: &testDiskSpaceMonitor{
+			status: &DiskSpaceStatus{
+				Path:          "/data",
+				Free:          900 << 20,
+				Total:         100 << 30,
+				WarnThreshold: 1 << 30,
+				StopThreshold: 256 << 20,
+				Level:         "low",
+			},
+		},
+		result: &types.GetDiskSpaceInfoResult{
+			Path:          "/data",
+			Free:          900 << 20,
+			Total:         100 << 30,
+			WarnThreshold: 1 << 30,
+			StopThreshold: 256 << 20,
+			Level:         "low",
+		},
+	}, {
+		name:    "handleGetDiskSpaceInfo: monitor not available",
+		handler: handleGetDiskSpaceInfo,
+		cmd:     &types.GetDiskSpaceInfoCmd{},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCMisc,
+	}, {
+		name:    "handleGetDiskSpaceInfo: query failed",
+		handler: handleGetDiskSpaceInfo,
+		cmd:     &types.GetDiskSpaceInfoCmd{},
+		mockDiskSpaceMonitor: &testDiskSpaceMonitor{
+			err: errors.New("statfs failed"),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}})
+}
+
+func TestHandleGetHeaders(t *testing.T) {
+	t.Parallel()
+
+	blkHeader := block432100.Header
+	blkHeaderBytes, err := blkHeader.Bytes()
+	if err != nil {
+		t.Fatalf("error serializing block header: %+v", err)
+	}
+	blkHeaderHexString := hex.EncodeToString(blkHeaderBytes)
+	locator := block432100.Header.PrevBlock.String()
+	hashStop := block432100.BlockHash().String()
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleGetHeaders: ok",
+		handler: handleGetHeaders,
+		cmd: &types.GetHeadersCmd{
+			BlockLocators: []string{locator},
+			HashStop:      hashStop,
+		},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.locateHeaders = []wire.BlockHeader{blkHeader}
+			return chain
+		}(),
+		result: &types.GetHeadersResult{
+			Headers: []string{blkHeaderHexString},
+		},
+	}, {
+		name:    "handleGetHeaders: no headers located",
+		handler: handleGetHeaders,
+		cmd: &types.GetHeadersCmd{
+			BlockLocators: []string{locator},
+		},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.locateHeaders = nil
+			return chain
+		}(),
+		result: &types.GetHeadersResult{
+			Headers: []string{},
+		},
+	}, {
+		name:    "handleGetHeaders: invalid block locator",
+		handler: handleGetHeaders,
+		cmd: &types.GetHeadersCmd{
+			BlockLocators: []string{"invalid"},
+			HashStop:      hashStop,
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCDecodeHexString,
+	}, {
+		name:    "handleGetHeaders: invalid hash stop",
+		handler: handleGetHeaders,
+		cmd: &types.GetHeadersCmd{
+			BlockLocators: []string{locator},
+			HashStop:      "invalid",
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}})
+}
+
 func TestHandleGetInfo(t *testing.T) {
 	t.Parallel()
 
@@ -4200,6 +5969,136 @@ func TestHandleGetInfo(t *testing.T) {
 	}})
 }
 
+func TestHandleGetMempoolInfo(t *testing.T) {
+	t.Parallel()
+
+	minFeeRate := 0.0001
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleGetMempoolInfo: ok",
+		handler: handleGetMempoolInfo,
+		cmd:     &types.GetMempoolInfoCmd{},
+		mockTxMempooler: func() *testTxMempooler {
+			mp := defaultMockTxMempooler()
+			mp.stats = &mempool.PoolStats{
+				Count:      157,
+				Bytes:      310768,
+				NumEvicted: 12,
+				LastHour: mempool.PoolWatermarks{
+					MaxCount:   201,
+					MaxBytes:   402311,
+					NumEvicted: 2,
+					MinFeeRate: -1,
+				},
+				LastDay: mempool.PoolWatermarks{
+					MaxCount:   350,
+					MaxBytes:   702156,
+					NumEvicted: 12,
+					MinFeeRate: 10000,
+				},
+			}
+			return mp
+		}(),
+		result: &types.GetMempoolInfoResult{
+			Size:    157,
+			Bytes:   310768,
+			Evicted: 12,
+			LastHour: types.GetMempoolInfoWatermark{
+				MaxSize:  201,
+				MaxBytes: 402311,
+				Evicted:  2,
+			},
+			LastDay: types.GetMempoolInfoWatermark{
+				MaxSize:    350,
+				MaxBytes:   702156,
+				Evicted:    12,
+				MinFeeRate: &minFeeRate,
+			},
+		},
+	}})
+}
+
+func TestHandleGetMempoolReplacements(t *testing.T) {
+	t.Parallel()
+
+	replacement := mustParseHash("3a1dc1d7a13b6d6d4bd2cd8dcbd7e3d3ec8b1c3ab9ab3c1a2dbd6d7f0e1e2f3a")
+	original := mustParseHash("7d6bc5e6d4ddc3e7f5f1e76b49ab47a24c5bb6f5cfc2b4ea1c8b2d1c7f5a4b3c")
+	rejected := mustParseHash("b54ad6f0c06ab06dd4e6bda8c2f2e4a7c1ac8e5e8b2f7f0b1d6c9d4e3a2b1c0d")
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleGetMempoolReplacements: no decisions",
+		handler: handleGetMempoolReplacements,
+		cmd:     &types.GetMempoolReplacementsCmd{},
+		mockTxMempooler: func() *testTxMempooler {
+			mp := defaultMockTxMempooler()
+			mp.replacements = &mempool.ReplacementHistory{
+				MinFeeRateIncrease: 10,
+			}
+			return mp
+		}(),
+		result: &types.GetMempoolReplacementsResult{
+			MinFeeRateIncrease: 10,
+			Replacements:       []types.MempoolReplacementResult{},
+		},
+	}, {
+		name:    "handleGetMempoolReplacements: ok",
+		handler: handleGetMempoolReplacements,
+		cmd:     &types.GetMempoolReplacementsCmd{},
+		mockTxMempooler: func() *testTxMempooler {
+			mp := defaultMockTxMempooler()
+			mp.replacements = &mempool.ReplacementHistory{
+				Enabled:            true,
+				MinFeeRateIncrease: 25,
+				Decisions: []mempool.ReplacementDecision{{
+					Time:       time.Unix(1600000000, 0),
+					Hash:       *rejected,
+					Conflicts:  []chainhash.Hash{*original},
+					NumEvicted: 2,
+					Fee:        11000,
+					FeeRate:    50000,
+					MinFee:     60220,
+					MinFeeRate: 57500,
+					Reason:     "fee rate of 50000 atoms/kB is under the required 57500 atoms/kB",
+				}, {
+					Time:       time.Unix(1600000060, 0),
+					Hash:       *replacement,
+					Conflicts:  []chainhash.Hash{*original},
+					NumEvicted: 2,
+					Fee:        70000,
+					FeeRate:    318181,
+					MinFee:     60220,
+					MinFeeRate: 57500,
+					Accepted:   true,
+				}},
+			}
+			return mp
+		}(),
+		result: &types.GetMempoolReplacementsResult{
+			Enabled:            true,
+			MinFeeRateIncrease: 25,
+			Replacements: []types.MempoolReplacementResult{{
+				Time:       1600000000,
+				TxID:       rejected.String(),
+				Conflicts:  []string{original.String()},
+				Evicted:    2,
+				Fee:        0.00011,
+				FeeRate:    0.0005,
+				MinFee:     0.0006022,
+				MinFeeRate: 0.000575,
+				Reason:     "fee rate of 50000 atoms/kB is under the required 57500 atoms/kB",
+			}, {
+				Time:       1600000060,
+				TxID:       replacement.String(),
+				Conflicts:  []string{original.String()},
+				Evicted:    2,
+				Fee:        0.0007,
+				FeeRate:    0.00318181,
+				MinFee:     0.0006022,
+				MinFeeRate: 0.000575,
+				Accepted:   true,
+			}},
+		},
+	}})
+}
+
 func TestHandleGetNetTotals(t *testing.T) {
 	t.Parallel()
 
@@ -4214,21 +6113,144 @@ func TestHandleGetNetTotals(t *testing.T) {
 			TotalBytesRecv: uint64(9598159),
 			TotalBytesSent: uint64(4783802),
 			TimeMillis:     int64(1592931302000),
+			TxRelay:        &types.TxRelayStatsResult{},
+		},
+	}, {
+		name:    "handleGetNetTotals: ok with txrecon",
+		handler: handleGetNetTotals,
+		cmd:     &types.GetNetTotalsCmd{},
+		mockClock: &testClock{
+			now: time.Unix(1592931302, 0),
+		},
+		mockConnManager: func() *testConnManager {
+			connManager := defaultMockConnManager()
+			connManager.txReconStats = &txrecon.Stats{
+				Rounds:     120,
+				Failures:   2,
+				ReconBytes: 51200,
+				FloodBytes: 204800,
+			}
+			return connManager
+		}(),
+		result: &types.GetNetTotalsResult{
+			TotalBytesRecv: uint64(9598159),
+			TotalBytesSent: uint64(4783802),
+			TimeMillis:     int64(1592931302000),
+			TxRecon: &types.TxReconStatsResult{
+				Rounds:     120,
+				Failures:   2,
+				ReconBytes: 51200,
+				FloodBytes: 204800,
+			},
+			TxRelay: &types.TxRelayStatsResult{},
+		},
+	}, {
+		name:    "handleGetNetTotals: ok with txrelay",
+		handler: handleGetNetTotals,
+		cmd:     &types.GetNetTotalsCmd{},
+		mockClock: &testClock{
+			now: time.Unix(1592931302, 0),
+		},
+		mockSyncManager: func() *testSyncManager {
+			syncManager := defaultMockSyncManager()
+			syncManager.txRelayPriority = TxRelayLaneStats{
+				Count:        4,
+				TotalLatency: 10 * time.Millisecond,
+				MaxLatency:   5 * time.Millisecond,
+			}
+			syncManager.txRelayRegular = TxRelayLaneStats{
+				Count:        2,
+				TotalLatency: 3 * time.Second,
+				MaxLatency:   2500 * time.Millisecond,
+			}
+			return syncManager
+		}(),
+		result: &types.GetNetTotalsResult{
+			TotalBytesRecv: uint64(9598159),
+			TotalBytesSent: uint64(4783802),
+			TimeMillis:     int64(1592931302000),
+			TxRelay: &types.TxRelayStatsResult{
+				Priority: types.TxRelayLaneResult{
+					Count:            4,
+					AvgLatencyMillis: 2.5,
+					MaxLatencyMillis: 5,
+				},
+				Regular: types.TxRelayLaneResult{
+					Count:            2,
+					AvgLatencyMillis: 1500,
+					MaxLatencyMillis: 2500,
+				},
+			},
 		},
 	}})
 }
 
-func TestHandleGetNetworkInfo(t *testing.T) {
+func TestHandleGetNetworkHashPS(t *testing.T) {
 	t.Parallel()
 
+	bestHeight := int64(block432100.Header.Height)
 	testRPCServerHandler(t, []rpcTest{{
-		name:    "handleGetNetworkInfo: ok",
-		handler: handleGetNetworkInfo,
-		cmd:     &types.GetNetworkInfoCmd{},
-		result: types.GetNetworkInfoResult{
-			Version: int32(1000000*version.Major + 10000*version.Minor +
-				100*version.Patch),
-			SubVersion: fmt.Sprintf("%d.%d.%d", version.Major, version.Minor,
+		name:    "handleGetNetworkHashPS: ok",
+		handler: handleGetNetworkHashPS,
+		cmd:     &types.GetNetworkHashPSCmd{},
+		result:  int64(0),
+	}, {
+		name:    "handleGetNetworkHashPS: ok verbose ema",
+		handler: handleGetNetworkHashPS,
+		cmd: &types.GetNetworkHashPSCmd{
+			Method:  dcrjson.String("ema"),
+			Verbose: dcrjson.Bool(true),
+		},
+		result: &types.GetNetworkHashPSResult{
+			Confidence:  0.95,
+			Method:      "ema",
+			StartHeight: bestHeight - 120,
+			EndHeight:   bestHeight,
+		},
+	}, {
+		name:    "handleGetNetworkHashPS: verbose height too high",
+		handler: handleGetNetworkHashPS,
+		cmd: &types.GetNetworkHashPSCmd{
+			Height:  dcrjson.Int(int(bestHeight + 1)),
+			Verbose: dcrjson.Bool(true),
+		},
+		result: &types.GetNetworkHashPSResult{
+			Confidence: 0.95,
+			Method:     "window",
+		},
+	}, {
+		name:    "handleGetNetworkHashPS: unknown method",
+		handler: handleGetNetworkHashPS,
+		cmd: &types.GetNetworkHashPSCmd{
+			Method: dcrjson.String("median"),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleGetNetworkHashPS: could not fetch block header",
+		handler: handleGetNetworkHashPS,
+		cmd:     &types.GetNetworkHashPSCmd{},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.headerByHashErr = errors.New("could not fetch header")
+			return chain
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}})
+}
+
+func TestHandleGetNetworkInfo(t *testing.T) {
+	t.Parallel()
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleGetNetworkInfo: ok",
+		handler: handleGetNetworkInfo,
+		cmd:     &types.GetNetworkInfoCmd{},
+		result: types.GetNetworkInfoResult{
+			Version: int32(1000000*version.Major + 10000*version.Minor +
+				100*version.Patch),
+			SubVersion: fmt.Sprintf("%d.%d.%d", version.Major, version.Minor,
 				version.Patch),
 			ProtocolVersion: int32(wire.CFilterV2Version),
 			TimeOffset:      int64(0),
@@ -4331,6 +6353,241 @@ func TestHandleGetPeerInfo(t *testing.T) {
 			BanScore:       int32(0),
 			SyncNode:       false,
 		}},
+	}, {
+		name:    "handleGetPeerInfo: tcp info",
+		handler: handleGetPeerInfo,
+		cmd:     &types.GetPeerInfoCmd{},
+		mockConnManager: func() *testConnManager {
+			connManager := defaultMockConnManager()
+			connManager.connectedPeers = []Peer{
+				&testPeer{
+					localAddr: testAddr{
+						net:  "tcp",
+						addr: "172.17.0.2:51060",
+					},
+					isTxRelayDisabled: false,
+					banScore:          uint32(0),
+					id:                int32(5),
+					addr:              "106.14.238.184:19108",
+					lastPingNonce:     uint64(10),
+					tcpInfo: &PeerTCPInfo{
+						RTT:          time.Millisecond * 85,
+						RTTVar:       time.Microsecond * 1250,
+						Retransmits:  1,
+						TotalRetrans: 7,
+						SndCwnd:      10,
+						SampleTime:   time.Unix(1592918790, 0),
+					},
+					statsSnapshot: &peer.StatsSnap{
+						ID:             int32(5),
+						Addr:           "106.14.238.184:19108",
+						Services:       wire.SFNodeNetwork | wire.SFNodeCF,
+						LastSend:       time.Unix(1592918788, 0),
+						LastRecv:       time.Unix(1592918788, 0),
+						BytesSent:      uint64(3406),
+						BytesRecv:      uint64(2498),
+						ConnTime:       time.Unix(1592918784, 0),
+						TimeOffset:     int64(-75),
+						Version:        uint32(6),
+						UserAgent:      "/dcrwire:0.3.0/dcrd:1.5.0(pre)/",
+						Inbound:        false,
+						StartingHeight: int64(323327),
+						LastBlock:      int64(323327),
+						LastPingNonce:  uint64(10),
+						LastPingTime:   time.Unix(1592918788, 0),
+						LastPingMicros: int64(0),
+					},
+				},
+			}
+			return connManager
+		}(),
+		mockClock: &testClock{
+			since: time.Duration(2000),
+		},
+		result: []*types.GetPeerInfoResult{{
+			ID:             int32(5),
+			Addr:           "106.14.238.184:19108",
+			AddrLocal:      "172.17.0.2:51060",
+			Services:       "00000005",
+			RelayTxes:      true,
+			LastSend:       int64(1592918788),
+			LastRecv:       int64(1592918788),
+			BytesSent:      uint64(3406),
+			BytesRecv:      uint64(2498),
+			ConnTime:       int64(1592918784),
+			TimeOffset:     int64(-75),
+			PingTime:       float64(0),
+			PingWait:       float64(2),
+			Version:        uint32(6),
+			SubVer:         "/dcrwire:0.3.0/dcrd:1.5.0(pre)/",
+			Inbound:        false,
+			StartingHeight: int64(323327),
+			CurrentHeight:  int64(323327),
+			BanScore:       int32(0),
+			SyncNode:       false,
+			TCPInfo: &types.PeerTCPInfo{
+				RTT:          85000,
+				RTTVar:       1250,
+				Retransmits:  1,
+				TotalRetrans: 7,
+				SndCwnd:      10,
+				SampleTime:   1592918790,
+			},
+		}},
+	}, {
+		name:    "handleGetPeerInfo: authenticated peer",
+		handler: handleGetPeerInfo,
+		cmd:     &types.GetPeerInfoCmd{},
+		mockConnManager: func() *testConnManager {
+			connManager := defaultMockConnManager()
+			connManager.connectedPeers = []Peer{
+				&testPeer{
+					localAddr: testAddr{
+						net:  "tcp",
+						addr: "172.17.0.2:51060",
+					},
+					isTxRelayDisabled: false,
+					banScore:          uint32(0),
+					id:                int32(5),
+					addr:              "106.14.238.184:19108",
+					lastPingNonce:     uint64(10),
+					statsSnapshot: &peer.StatsSnap{
+						ID:             int32(5),
+						Addr:           "106.14.238.184:19108",
+						Services:       wire.SFNodeNetwork | wire.SFNodeCF,
+						LastSend:       time.Unix(1592918788, 0),
+						LastRecv:       time.Unix(1592918788, 0),
+						BytesSent:      uint64(3406),
+						BytesRecv:      uint64(2498),
+						ConnTime:       time.Unix(1592918784, 0),
+						TimeOffset:     int64(-75),
+						Version:        uint32(6),
+						UserAgent:      "/dcrwire:0.3.0/dcrd:1.5.0(pre)/",
+						Inbound:        false,
+						StartingHeight: int64(323327),
+						LastBlock:      int64(323327),
+						LastPingNonce:  uint64(10),
+						LastPingTime:   time.Unix(1592918788, 0),
+						LastPingMicros: int64(0),
+						IdentityKey: secp256k1.PrivKeyFromBytes(
+							[]byte{0x01}).PubKey(),
+						MsgStats: map[string]peer.MsgStats{
+							wire.CmdPing: {
+								Received:    10,
+								BytesRecv:   320,
+								RateLimited: 1,
+							},
+						},
+					},
+				},
+			}
+			return connManager
+		}(),
+		mockClock: &testClock{
+			since: time.Duration(2000),
+		},
+		result: []*types.GetPeerInfoResult{{
+			ID:             int32(5),
+			Addr:           "106.14.238.184:19108",
+			AddrLocal:      "172.17.0.2:51060",
+			Services:       "00000005",
+			RelayTxes:      true,
+			LastSend:       int64(1592918788),
+			LastRecv:       int64(1592918788),
+			BytesSent:      uint64(3406),
+			BytesRecv:      uint64(2498),
+			ConnTime:       int64(1592918784),
+			TimeOffset:     int64(-75),
+			PingTime:       float64(0),
+			PingWait:       float64(2),
+			Version:        uint32(6),
+			SubVer:         "/dcrwire:0.3.0/dcrd:1.5.0(pre)/",
+			Inbound:        false,
+			StartingHeight: int64(323327),
+			CurrentHeight:  int64(323327),
+			BanScore:       int32(0),
+			SyncNode:       false,
+			IdentityKey: "0279be667ef9dcbbac55a06295ce870b07029bfcd" +
+				"b2dce28d959f2815b16f81798",
+			MsgStats: map[string]types.PeerMsgStats{
+				"ping": {
+					Received:    10,
+					BytesRecv:   320,
+					RateLimited: 1,
+				},
+			},
+		}},
+	}})
+}
+
+func TestHandleGetRejectedTransactions(t *testing.T) {
+	t.Parallel()
+
+	rejectedTxns := []RejectedTx{{
+		Hash:   *mustParseHash("4c4d6b9c6b2b4e15b6c8c7b0e07ba0ddc0a9b81bc8b6c6e3e1e31d7c84b1a3b6"),
+		Code:   wire.RejectInsufficientFee,
+		Reason: "transaction has insufficient fee",
+		Time:   time.Unix(1592918788, 0),
+		Height: 432100,
+	}, {
+		Hash:   *mustParseHash("2ff4c7b131b701aafad03e2da7254d0c8968602238dfbe8c72e4e1e70cb9b232"),
+		Code:   wire.RejectDuplicate,
+		Reason: "already have transaction",
+		Time:   time.Unix(1592918700, 0),
+		Height: 432099,
+	}}
+	syncManager := defaultMockSyncManager()
+	syncManager.rejectedTxns = rejectedTxns
+	wantResults := []types.GetRejectedTransactionsResult{{
+		TxID:   "4c4d6b9c6b2b4e15b6c8c7b0e07ba0ddc0a9b81bc8b6c6e3e1e31d7c84b1a3b6",
+		Code:   "REJECT_INSUFFICIENTFEE",
+		Reason: "transaction has insufficient fee",
+		Time:   1592918788,
+		Height: 432100,
+	}, {
+		TxID:   "2ff4c7b131b701aafad03e2da7254d0c8968602238dfbe8c72e4e1e70cb9b232",
+		Code:   "REJECT_DUPLICATE",
+		Reason: "already have transaction",
+		Time:   1592918700,
+		Height: 432099,
+	}}
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:            "handleGetRejectedTransactions: ok",
+		handler:         handleGetRejectedTransactions,
+		cmd:             &types.GetRejectedTransactionsCmd{},
+		mockSyncManager: syncManager,
+		result:          wantResults,
+	}, {
+		name:    "handleGetRejectedTransactions: ok with count",
+		handler: handleGetRejectedTransactions,
+		cmd: &types.GetRejectedTransactionsCmd{
+			Count: dcrjson.Int32(1),
+		},
+		mockSyncManager: syncManager,
+		result:          wantResults[:1],
+	}, {
+		name:    "handleGetRejectedTransactions: ok with count larger than cache",
+		handler: handleGetRejectedTransactions,
+		cmd: &types.GetRejectedTransactionsCmd{
+			Count: dcrjson.Int32(10),
+		},
+		mockSyncManager: syncManager,
+		result:          wantResults,
+	}, {
+		name:    "handleGetRejectedTransactions: no rejected transactions",
+		handler: handleGetRejectedTransactions,
+		cmd:     &types.GetRejectedTransactionsCmd{},
+		result:  []types.GetRejectedTransactionsResult{},
+	}, {
+		name:    "handleGetRejectedTransactions: negative count",
+		handler: handleGetRejectedTransactions,
+		cmd: &types.GetRejectedTransactionsCmd{
+			Count: dcrjson.Int32(-1),
+		},
+		mockSyncManager: syncManager,
+		wantErr:         true,
+		errCode:         dcrjson.ErrRPCInvalidParameter,
 	}})
 }
 
@@ -4452,6 +6709,136 @@ func TestHandleGetStakeVersionInfo(t *testing.T) {
 	}})
 }
 
+func TestHandleGetSignalingStats(t *testing.T) {
+	t.Parallel()
+
+	blk := dcrutil.NewBlock(&block432100)
+	blkHashString := blk.Hash().String()
+	blkHeight := blk.Height()
+	signalingChain := func() *testRPCChain {
+		chain := defaultMockRPCChain()
+		chain.getStakeVersions = []blockchain.StakeVersions{{
+			Hash:         *blk.Hash(),
+			Height:       blkHeight,
+			BlockVersion: 8,
+			StakeVersion: 8,
+			Votes: []stake.VoteVersionTuple{{
+				Version: 8,
+				Bits:    0x0005,
+			}, {
+				Version: 8,
+				Bits:    0x0001,
+			}, {
+				Version: 7,
+				Bits:    0x0003,
+			}},
+		}, {
+			Hash:         block432100.Header.PrevBlock,
+			Height:       blkHeight - 1,
+			BlockVersion: 7,
+			StakeVersion: 7,
+			Votes: []stake.VoteVersionTuple{{
+				Version: 8,
+				Bits:    0x0004,
+			}},
+		}}
+		return chain
+	}
+	testRPCServerHandler(t, []rpcTest{{
+		name:      "handleGetSignalingStats: ok",
+		handler:   handleGetSignalingStats,
+		cmd:       &types.GetSignalingStatsCmd{},
+		mockChain: signalingChain(),
+		result: types.GetSignalingStatsResult{
+			Hash:        defaultMockRPCChain().bestSnapshot.Hash.String(),
+			StartHeight: blkHeight - 1,
+			EndHeight:   blkHeight,
+			NumBlocks:   2,
+			NumVotes:    4,
+			BlockVersions: []types.VersionCount{
+				{Version: 7, Count: 1},
+				{Version: 8, Count: 1},
+			},
+			BlockVersionBits: []types.SignalingBit{
+				{Bit: 0, Count: 1, Percent: 50},
+				{Bit: 1, Count: 1, Percent: 50},
+				{Bit: 2, Count: 1, Percent: 50},
+				{Bit: 3, Count: 1, Percent: 50},
+			},
+			VoteVersions: []types.VersionCount{
+				{Version: 7, Count: 1},
+				{Version: 8, Count: 3},
+			},
+			VoteBits: []types.SignalingBit{
+				{Bit: 0, Count: 3, Percent: 75},
+				{Bit: 1, Count: 1, Percent: 25},
+				{Bit: 2, Count: 2, Percent: 50},
+			},
+		},
+	}, {
+		name:    "handleGetSignalingStats: vote version",
+		handler: handleGetSignalingStats,
+		cmd: &types.GetSignalingStatsCmd{
+			Blocks:      dcrjson.Int32(2),
+			Hash:        dcrjson.String(blkHashString),
+			VoteVersion: dcrjson.Uint32(8),
+		},
+		mockChain: signalingChain(),
+		result: types.GetSignalingStatsResult{
+			Hash:        blkHashString,
+			StartHeight: blkHeight - 1,
+			EndHeight:   blkHeight,
+			NumBlocks:   2,
+			NumVotes:    3,
+			BlockVersions: []types.VersionCount{
+				{Version: 7, Count: 1},
+				{Version: 8, Count: 1},
+			},
+			BlockVersionBits: []types.SignalingBit{
+				{Bit: 0, Count: 1, Percent: 50},
+				{Bit: 1, Count: 1, Percent: 50},
+				{Bit: 2, Count: 1, Percent: 50},
+				{Bit: 3, Count: 1, Percent: 50},
+			},
+			VoteVersions: []types.VersionCount{
+				{Version: 7, Count: 1},
+				{Version: 8, Count: 3},
+			},
+			VoteBits: []types.SignalingBit{
+				{Bit: 0, Count: 2, Percent: 200.0 / 3},
+				{Bit: 2, Count: 2, Percent: 200.0 / 3},
+			},
+		},
+	}, {
+		name:    "handleGetSignalingStats: invalid hash",
+		handler: handleGetSignalingStats,
+		cmd: &types.GetSignalingStatsCmd{
+			Hash: dcrjson.String("invalid"),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCDecodeHexString,
+	}, {
+		name:    "handleGetSignalingStats: invalid number of blocks",
+		handler: handleGetSignalingStats,
+		cmd: &types.GetSignalingStatsCmd{
+			Blocks: dcrjson.Int32(0),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleGetSignalingStats: could not obtain stake versions",
+		handler: handleGetSignalingStats,
+		cmd:     &types.GetSignalingStatsCmd{},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.getStakeVersionsErr = errors.New("could not obtain stake versions")
+			return chain
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}})
+}
+
 func TestHandleGetStakeVersions(t *testing.T) {
 	t.Parallel()
 
@@ -4477,6 +6864,88 @@ func TestHandleGetStakeVersions(t *testing.T) {
 				}},
 			}},
 		},
+	}, {
+		name:    "handleGetStakeVersions: more blocks available",
+		handler: handleGetStakeVersions,
+		cmd: &types.GetStakeVersionsCmd{
+			Hash:  blkHashString,
+			Count: 1,
+		},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.getStakeVersions = append(chain.getStakeVersions,
+				blockchain.StakeVersions{
+					Hash:         block432100.Header.PrevBlock,
+					Height:       blkHeight - 1,
+					BlockVersion: block432100.Header.Version,
+					StakeVersion: block432100.Header.StakeVersion,
+				})
+			return chain
+		}(),
+		result: types.GetStakeVersionsResult{
+			StakeVersions: []types.StakeVersions{{
+				Hash:         blkHashString,
+				Height:       blkHeight,
+				BlockVersion: block432100.Header.Version,
+				StakeVersion: block432100.Header.StakeVersion,
+				Votes: []types.VersionBits{{
+					Version: 7,
+					Bits:    1,
+				}},
+			}},
+			NextHash: block432100.Header.PrevBlock.String(),
+		},
+	}, {
+		name:    "handleGetStakeVersions: aggregate intervals",
+		handler: handleGetStakeVersions,
+		cmd: &types.GetStakeVersionsCmd{
+			Hash:         blkHashString,
+			Count:        2,
+			IntervalSize: dcrjson.Int32(2),
+		},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.getStakeVersions = append(chain.getStakeVersions,
+				blockchain.StakeVersions{
+					Hash:         block432100.Header.PrevBlock,
+					Height:       blkHeight - 1,
+					BlockVersion: block432100.Header.Version,
+					StakeVersion: 6,
+					Votes: []stake.VoteVersionTuple{{
+						Version: 6,
+						Bits:    1,
+					}, {
+						Version: 7,
+						Bits:    1,
+					}},
+				})
+			return chain
+		}(),
+		result: types.GetStakeVersionsResult{
+			StakeVersions: []types.StakeVersions{},
+			Intervals: []types.VersionInterval{{
+				StartHeight: blkHeight - 1,
+				EndHeight:   blkHeight,
+				PoSVersions: []types.VersionCount{
+					{Version: 6, Count: 1},
+					{Version: block432100.Header.StakeVersion, Count: 1},
+				},
+				VoteVersions: []types.VersionCount{
+					{Version: 6, Count: 1},
+					{Version: 7, Count: 2},
+				},
+			}},
+		},
+	}, {
+		name:    "handleGetStakeVersions: invalid interval size",
+		handler: handleGetStakeVersions,
+		cmd: &types.GetStakeVersionsCmd{
+			Hash:         blkHashString,
+			Count:        1,
+			IntervalSize: dcrjson.Int32(-1),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
 	}, {
 		name:    "handleGetStakeVersions: invalid hash",
 		handler: handleGetStakeVersions,
@@ -4512,6 +6981,240 @@ func TestHandleGetStakeVersions(t *testing.T) {
 	}})
 }
 
+func TestHandleGetStandardPolicy(t *testing.T) {
+	t.Parallel()
+
+	standardPolicy := &mempool.StandardPolicy{
+		MaxTxVersion:       2,
+		MaxTxSize:          100000,
+		MaxSigScriptSize:   1650,
+		MaxNullDataOutputs: 4,
+		ScriptVersions: []mempool.ScriptVersionStatus{{
+			ScriptVersionPolicy: mempool.ScriptVersionPolicy{
+				Classes: []txscript.ScriptClass{
+					txscript.PubKeyHashTy,
+					txscript.ScriptHashTy,
+					txscript.NullDataTy,
+				},
+				MaxMultiSigKeys: 3,
+				MaxP2SHSigOps:   15,
+			},
+			Active: true,
+		}, {
+			ScriptVersionPolicy: mempool.ScriptVersionPolicy{
+				Version:         1,
+				Agenda:          "scriptv1",
+				Classes:         []txscript.ScriptClass{txscript.PubKeyHashTy},
+				MaxMultiSigKeys: 3,
+				MaxP2SHSigOps:   15,
+			},
+		}},
+	}
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleGetStandardPolicy: ok",
+		handler: handleGetStandardPolicy,
+		cmd:     &types.GetStandardPolicyCmd{},
+		mockTxMempooler: func() *testTxMempooler {
+			mp := defaultMockTxMempooler()
+			mp.standardPolicy = standardPolicy
+			return mp
+		}(),
+		result: &types.GetStandardPolicyResult{
+			MaxTxVersion:       2,
+			MaxTxSize:          100000,
+			MaxSigScriptSize:   1650,
+			MaxNullDataOutputs: 4,
+			ScriptVersions: []types.StandardScriptVersionResult{{
+				Active:          true,
+				Classes:         []string{"pubkeyhash", "scripthash", "nulldata"},
+				MaxMultiSigKeys: 3,
+				MaxP2SHSigOps:   15,
+			}, {
+				Version:         1,
+				Agenda:          "scriptv1",
+				Classes:         []string{"pubkeyhash"},
+				MaxMultiSigKeys: 3,
+				MaxP2SHSigOps:   15,
+			}},
+		},
+	}, {
+		name:    "handleGetStandardPolicy: agenda state error",
+		handler: handleGetStandardPolicy,
+		cmd:     &types.GetStandardPolicyCmd{},
+		mockTxMempooler: func() *testTxMempooler {
+			mp := defaultMockTxMempooler()
+			mp.standardPolicyErr = errors.New("unable to determine agenda state")
+			return mp
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}})
+}
+
+func TestHandleGetSyncPeer(t *testing.T) {
+	t.Parallel()
+
+	syncManager := defaultMockSyncManager()
+	syncManager.syncPeerInfo = &SyncPeerInfo{
+		ID:             3,
+		Addr:           "127.0.0.211:9108",
+		SyncHeight:     463074,
+		BlocksInFlight: 12,
+		Deadline:       time.Unix(1592918830, 0),
+		Switches: []SyncPeerSwitch{{
+			Time:   time.Unix(1592918700, 0),
+			ID:     2,
+			Addr:   "127.0.0.210:9108",
+			Reason: "initial",
+		}, {
+			Time:     time.Unix(1592918800, 0),
+			PrevID:   2,
+			PrevAddr: "127.0.0.210:9108",
+			ID:       3,
+			Addr:     "127.0.0.211:9108",
+			Reason:   "stalled",
+		}},
+	}
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:            "handleGetSyncPeer: ok",
+		handler:         handleGetSyncPeer,
+		cmd:             &types.GetSyncPeerCmd{},
+		mockSyncManager: syncManager,
+		result: &types.GetSyncPeerResult{
+			ID:             3,
+			Addr:           "127.0.0.211:9108",
+			SyncHeight:     463074,
+			BlocksInFlight: 12,
+			Deadline:       1592918830,
+			Switches: []types.SyncPeerSwitchResult{{
+				Time:   1592918700,
+				ID:     2,
+				Addr:   "127.0.0.210:9108",
+				Reason: "initial",
+			}, {
+				Time:         1592918800,
+				PreviousID:   2,
+				PreviousAddr: "127.0.0.210:9108",
+				ID:           3,
+				Addr:         "127.0.0.211:9108",
+				Reason:       "stalled",
+			}},
+		},
+	}, {
+		name:    "handleGetSyncPeer: no sync peer",
+		handler: handleGetSyncPeer,
+		cmd:     &types.GetSyncPeerCmd{},
+		result: &types.GetSyncPeerResult{
+			SyncHeight: 463074,
+			Switches:   []types.SyncPeerSwitchResult{},
+		},
+	}})
+}
+
+func TestHandleGetTemplateDecisions(t *testing.T) {
+	t.Parallel()
+
+	templater := defaultMockBlockTemplater()
+	templater.templateDecisions = []*mining.TemplateDecisions{{
+		Height:    432101,
+		PrevBlock: *mustParseHash("000000000000000020fd67cb8b9bebe3ef8f0bfc5e1bfcc67f3ce2e9f06ae2f4"),
+		BlockHash: *mustParseHash("00000000000000001e0fd8a2bb8b2ad5b8c0db4c4ff2f6b4f0bc2a8e3b1c5f96"),
+		Generated: time.Unix(1592918788, 0),
+		Txns: []mining.TxDecision{{
+			Hash:   *mustParseHash("4c4d6b9c6b2b4e15b6c8c7b0e07ba0ddc0a9b81bc8b6c6e3e1e31d7c84b1a3b6"),
+			Type:   stake.TxTypeRegular,
+			Reason: mining.TxSelected,
+			Size:   250,
+			Fee:    7500,
+		}, {
+			Hash:   *mustParseHash("2ff4c7b131b701aafad03e2da7254d0c8968602238dfbe8c72e4e1e70cb9b232"),
+			Type:   stake.TxTypeSStx,
+			Reason: mining.TxSkippedMaxFreshStake,
+			Size:   500,
+			Fee:    5000,
+		}},
+	}, {
+		Height:    432100,
+		PrevBlock: *mustParseHash("000000000000000011b5e5df45eb1e7c2e9f7ad7bb6c3ce52e38a2c4b1f3b4a7"),
+		BlockHash: *mustParseHash("0000000000000000020fd67cb8b9bebe3ef8f0bfc5e1bfcc67f3ce2e9f06ae2f"),
+		Generated: time.Unix(1592918700, 0),
+		Txns:      nil,
+	}}
+	wantResults := []types.GetTemplateDecisionsResult{{
+		Hash:         "00000000000000001e0fd8a2bb8b2ad5b8c0db4c4ff2f6b4f0bc2a8e3b1c5f96",
+		PreviousHash: "000000000000000020fd67cb8b9bebe3ef8f0bfc5e1bfcc67f3ce2e9f06ae2f4",
+		Height:       432101,
+		Time:         1592918788,
+		Considered:   2,
+		Selected:     1,
+		TotalFees:    0.000075,
+		Transactions: []types.TemplateTxDecisionResult{{
+			TxID:     "4c4d6b9c6b2b4e15b6c8c7b0e07ba0ddc0a9b81bc8b6c6e3e1e31d7c84b1a3b6",
+			Type:     "regular",
+			Decision: "selected",
+			Size:     250,
+			Fee:      0.000075,
+			FeeRate:  0.0003,
+		}, {
+			TxID:     "2ff4c7b131b701aafad03e2da7254d0c8968602238dfbe8c72e4e1e70cb9b232",
+			Type:     "ticket",
+			Decision: "skipped",
+			Reason:   "maxfreshstake",
+			Size:     500,
+			Fee:      0.00005,
+			FeeRate:  0.0001,
+		}},
+	}, {
+		Hash:         "0000000000000000020fd67cb8b9bebe3ef8f0bfc5e1bfcc67f3ce2e9f06ae2f",
+		PreviousHash: "000000000000000011b5e5df45eb1e7c2e9f7ad7bb6c3ce52e38a2c4b1f3b4a7",
+		Height:       432100,
+		Time:         1592918700,
+		Transactions: []types.TemplateTxDecisionResult{},
+	}}
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:                 "handleGetTemplateDecisions: node is not configured for mining",
+		handler:              handleGetTemplateDecisions,
+		cmd:                  &types.GetTemplateDecisionsCmd{},
+		setBlockTemplaterNil: true,
+		wantErr:              true,
+		errCode:              dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:               "handleGetTemplateDecisions: ok",
+		handler:            handleGetTemplateDecisions,
+		cmd:                &types.GetTemplateDecisionsCmd{},
+		mockBlockTemplater: templater,
+		result:             wantResults[:1],
+	}, {
+		name:    "handleGetTemplateDecisions: ok with count",
+		handler: handleGetTemplateDecisions,
+		cmd: &types.GetTemplateDecisionsCmd{
+			Count: dcrjson.Int32(10),
+		},
+		mockBlockTemplater: templater,
+		result:             wantResults,
+	}, {
+		name:    "handleGetTemplateDecisions: invalid count",
+		handler: handleGetTemplateDecisions,
+		cmd: &types.GetTemplateDecisionsCmd{
+			Count: dcrjson.Int32(0),
+		},
+		mockBlockTemplater: templater,
+		wantErr:            true,
+		errCode:            dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleGetTemplateDecisions: count too large",
+		handler: handleGetTemplateDecisions,
+		cmd: &types.GetTemplateDecisionsCmd{
+			Count: dcrjson.Int32(mining.MaxTemplateDecisions + 1),
+		},
+		mockBlockTemplater: templater,
+		wantErr:            true,
+		errCode:            dcrjson.ErrRPCInvalidParameter,
+	}})
+}
+
 func TestHandleGetTicketPoolValue(t *testing.T) {
 	t.Parallel()
 
@@ -4534,6 +7237,78 @@ func TestHandleGetTicketPoolValue(t *testing.T) {
 	}})
 }
 
+func TestHandleGetTimeInfo(t *testing.T) {
+	t.Parallel()
+
+	blk := dcrutil.NewBlock(&block432100)
+	blkHashString := blk.Hash().String()
+	bestMedianTime := time.Unix(1584246683, 0)
+	medianTime := time.Unix(1584240000, 0)
+	localTime := time.Unix(1584247000, 0)
+	timeSource := &testTimeSource{
+		adjustedTime: localTime.Add(-3 * time.Second),
+		offset:       -3 * time.Second,
+	}
+	testRPCServerHandler(t, []rpcTest{{
+		name:           "handleGetTimeInfo: ok best chain tip",
+		handler:        handleGetTimeInfo,
+		cmd:            &types.GetTimeInfoCmd{},
+		mockClock:      &testClock{now: localTime},
+		mockTimeSource: timeSource,
+		result: &types.GetTimeInfoResult{
+			Hash:         blkHashString,
+			Height:       blk.Height(),
+			MedianTime:   bestMedianTime.Unix(),
+			LocalTime:    localTime.Unix(),
+			AdjustedTime: localTime.Unix() - 3,
+			TimeOffset:   -3,
+		},
+	}, {
+		name:    "handleGetTimeInfo: ok block",
+		handler: handleGetTimeInfo,
+		cmd: &types.GetTimeInfoCmd{
+			BlockHash: dcrjson.String(blkHashString),
+		},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.headerByHash = block432100.Header
+			chain.medianTimeByHash = medianTime
+			return chain
+		}(),
+		mockClock:      &testClock{now: localTime},
+		mockTimeSource: timeSource,
+		result: &types.GetTimeInfoResult{
+			Hash:         blkHashString,
+			Height:       int64(block432100.Header.Height),
+			MedianTime:   medianTime.Unix(),
+			LocalTime:    localTime.Unix(),
+			AdjustedTime: localTime.Unix() - 3,
+			TimeOffset:   -3,
+		},
+	}, {
+		name:    "handleGetTimeInfo: invalid block hash",
+		handler: handleGetTimeInfo,
+		cmd: &types.GetTimeInfoCmd{
+			BlockHash: dcrjson.String("invalid"),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCDecodeHexString,
+	}, {
+		name:    "handleGetTimeInfo: block not found",
+		handler: handleGetTimeInfo,
+		cmd: &types.GetTimeInfoCmd{
+			BlockHash: dcrjson.String(blkHashString),
+		},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.headerByHashErr = errors.New("block not found")
+			return chain
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCBlockNotFound,
+	}})
+}
+
 func TestHandleGetTxOutSetInfo(t *testing.T) {
 	t.Parallel()
 
@@ -4547,12 +7322,184 @@ func TestHandleGetTxOutSetInfo(t *testing.T) {
 			Transactions:   689819,
 			TxOuts:         1593879,
 			SerializedHash: "fe7b32aa188800f07268b17f3bead5f3d8a1b6d18654182066436efce6effa86",
+			UtxoSetHash:    "3f7de3f1d2e61c7aa5d8b0ab44e2a7f3b1c9e0a4d6c8f2b1e3a5c7d9f1b3d5e7",
 			DiskSize:       36441617,
 			TotalAmount:    1154067750680149,
 		},
 	}})
 }
 
+func TestHandleGetTxScriptCost(t *testing.T) {
+	t.Parallel()
+
+	// Create a transaction that spends two outputs of a transaction in the
+	// mempool where the scripts of the first input execute successfully and
+	// those of the second one fail.  Note that the mock mempool returns the
+	// same transaction for all hashes.
+	prevHash := chainhash.Hash{0x01}
+	tx := wire.NewMsgTx()
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&prevHash, 0, wire.TxTreeRegular),
+		0, []byte{txscript.OP_1}))
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&prevHash, 1, wire.TxTreeRegular),
+		0, []byte{txscript.OP_1}))
+	tx.AddTxOut(wire.NewTxOut(1, []byte{txscript.OP_DROP, txscript.OP_TRUE}))
+	tx.AddTxOut(wire.NewTxOut(1, []byte{txscript.OP_DROP, txscript.OP_FALSE}))
+	mempooler := defaultMockTxMempooler()
+	mempooler.fetchTransaction = dcrutil.NewTx(tx)
+	mempooler.fetchTransactionErr = nil
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleGetTxScriptCost: invalid hash",
+		handler: handleGetTxScriptCost,
+		cmd: &types.GetTxScriptCostCmd{
+			Txid: "invalid",
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCDecodeHexString,
+	}, {
+		name:    "handleGetTxScriptCost: transaction index disabled",
+		handler: handleGetTxScriptCost,
+		cmd: &types.GetTxScriptCostCmd{
+			Txid: tx.TxHash().String(),
+		},
+		setTxIndexerNil: true,
+		wantErr:         true,
+		errCode:         dcrjson.ErrRPCInternal.Code,
+	}})
+
+	// Ensure the executed opcodes, signature operations, and validity of the
+	// scripts of each input are reported.  The durations are not checked
+	// since they vary between runs.
+	cfg := defaultMockConfig(defaultChainParams)
+	cfg.TxMempooler = mempooler
+	s := &Server{cfg: *cfg}
+	result, err := handleGetTxScriptCost(nil, s, &types.GetTxScriptCostCmd{
+		Txid: tx.TxHash().String(),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	costs := result.(types.GetTxScriptCostResult)
+	if costs.Txid != tx.TxHash().String() || len(costs.Inputs) != 2 {
+		t.Fatalf("unexpected result: %+v", costs)
+	}
+	for i := range costs.Inputs {
+		costs.Inputs[i].Duration = 0
+	}
+	wantInputs := []types.ScriptCostInputResult{{
+		Index:   0,
+		Opcodes: 3,
+		Valid:   true,
+	}, {
+		Index:   1,
+		Opcodes: 3,
+		Valid:   false,
+		Error:   costs.Inputs[1].Error,
+	}}
+	if !reflect.DeepEqual(costs.Inputs, wantInputs) {
+		t.Fatalf("unexpected inputs -- got %+v, want %+v", costs.Inputs,
+			wantInputs)
+	}
+	if costs.Inputs[1].Error == "" {
+		t.Fatal("missing error for failing scripts")
+	}
+}
+
+func TestHandleListBanned(t *testing.T) {
+	t.Parallel()
+
+	banEnd := time.Unix(1592918788, 0)
+	connManager := defaultMockConnManager()
+	connManager.bannedPeers = map[string]time.Time{
+		"127.0.0.3": banEnd.Add(2 * time.Hour),
+		"127.0.0.1": banEnd,
+		"127.0.0.2": banEnd.Add(time.Hour),
+	}
+	testRPCServerHandler(t, []rpcTest{{
+		name:            "handleListBanned: ok",
+		handler:         handleListBanned,
+		cmd:             &types.ListBannedCmd{},
+		mockConnManager: connManager,
+		result: &types.ListBannedResult{
+			Banned: []types.BannedPeer{{
+				Host:        "127.0.0.1",
+				BannedUntil: banEnd.Unix(),
+			}, {
+				Host:        "127.0.0.2",
+				BannedUntil: banEnd.Add(time.Hour).Unix(),
+			}, {
+				Host:        "127.0.0.3",
+				BannedUntil: banEnd.Add(2 * time.Hour).Unix(),
+			}},
+			Page: types.PageInfo{
+				Limit:         100,
+				TotalEstimate: 3,
+			},
+		},
+	}, {
+		name:    "handleListBanned: ok first page",
+		handler: handleListBanned,
+		cmd: &types.ListBannedCmd{
+			Limit: dcrjson.Int(2),
+		},
+		mockConnManager: connManager,
+		result: &types.ListBannedResult{
+			Banned: []types.BannedPeer{{
+				Host:        "127.0.0.1",
+				BannedUntil: banEnd.Unix(),
+			}, {
+				Host:        "127.0.0.2",
+				BannedUntil: banEnd.Add(time.Hour).Unix(),
+			}},
+			Page: types.PageInfo{
+				NextCursor:    "00000002",
+				Limit:         2,
+				TotalEstimate: 3,
+			},
+		},
+	}, {
+		name:    "handleListBanned: ok final page",
+		handler: handleListBanned,
+		cmd: &types.ListBannedCmd{
+			Cursor: dcrjson.String("00000002"),
+			Limit:  dcrjson.Int(2),
+		},
+		mockConnManager: connManager,
+		result: &types.ListBannedResult{
+			Banned: []types.BannedPeer{{
+				Host:        "127.0.0.3",
+				BannedUntil: banEnd.Add(2 * time.Hour).Unix(),
+			}},
+			Page: types.PageInfo{
+				Limit:         2,
+				TotalEstimate: 3,
+			},
+		},
+	}, {
+		name:    "handleListBanned: ok past final page",
+		handler: handleListBanned,
+		cmd: &types.ListBannedCmd{
+			Cursor: dcrjson.String("00000009"),
+		},
+		mockConnManager: connManager,
+		result: &types.ListBannedResult{
+			Banned: []types.BannedPeer{},
+			Page: types.PageInfo{
+				Limit:         100,
+				TotalEstimate: 3,
+			},
+		},
+	}, {
+		name:    "handleListBanned: invalid cursor",
+		handler: handleListBanned,
+		cmd: &types.ListBannedCmd{
+			Cursor: dcrjson.String("not hex"),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}})
+}
+
 func TestHandleLiveTickets(t *testing.T) {
 	t.Parallel()
 
@@ -4805,18 +7752,79 @@ func TestHandleNode(t *testing.T) {
 			Target: "127.0.0.210:9108",
 		},
 		wantErr: true,
-		errCode: dcrjson.ErrRPCInvalidParameter,
-	}})
-}
-
-func TestHandlePing(t *testing.T) {
-	t.Parallel()
-
-	testRPCServerHandler(t, []rpcTest{{
-		name:    "handlePing: ok",
-		handler: handlePing,
-		cmd:     &types.PingCmd{},
-		result:  nil,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}})
+}
+
+func TestHandlePing(t *testing.T) {
+	t.Parallel()
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handlePing: ok",
+		handler: handlePing,
+		cmd:     &types.PingCmd{},
+		result:  nil,
+	}})
+}
+
+func TestHandleProposeBlock(t *testing.T) {
+	t.Parallel()
+
+	blk := dcrutil.NewBlock(&block432100)
+	blkBytes, err := blk.Bytes()
+	if err != nil {
+		t.Fatalf("unexpected error serializing block: %v", err)
+	}
+	blkHex := hex.EncodeToString(blkBytes)
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleProposeBlock: invalid hex",
+		handler: handleProposeBlock,
+		cmd:     &types.ProposeBlockCmd{HexBlock: "zz"},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCDecodeHexString,
+	}, {
+		name:    "handleProposeBlock: invalid block",
+		handler: handleProposeBlock,
+		cmd:     &types.ProposeBlockCmd{HexBlock: "01"},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCDeserialization,
+	}, {
+		name:    "handleProposeBlock: valid",
+		handler: handleProposeBlock,
+		cmd:     &types.ProposeBlockCmd{HexBlock: blkHex},
+		result: &types.ProposeBlockResult{
+			Hash:  blk.Hash().String(),
+			Valid: true,
+		},
+	}, {
+		name:    "handleProposeBlock: rule violation",
+		handler: handleProposeBlock,
+		cmd:     &types.ProposeBlockCmd{HexBlock: blkHex},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.checkConnectBlockTemplateErr = blockchain.RuleError{
+				ErrorCode:   blockchain.ErrBadMerkleRoot,
+				Description: "block merkle root is invalid",
+			}
+			return chain
+		}(),
+		result: &types.ProposeBlockResult{
+			Hash:       blk.Hash().String(),
+			Valid:      false,
+			RejectCode: "ErrBadMerkleRoot",
+			Reason:     "block merkle root is invalid",
+		},
+	}, {
+		name:    "handleProposeBlock: validation error",
+		handler: handleProposeBlock,
+		cmd:     &types.ProposeBlockCmd{HexBlock: blkHex},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.checkConnectBlockTemplateErr = errors.New("db error")
+			return chain
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
 	}})
 }
 
@@ -4976,6 +7984,21 @@ func TestHandleSearchRawTransactions(t *testing.T) {
 		},
 	}
 
+	// Mock an address index and DB that have no confirmed transactions left
+	// after skipping for paging past the confirmed transactions.
+	mempoolAddrIndexer := defaultMockAddrIndexer()
+	mempoolAddrIndexer.entriesForAddressSkipped = uint32(len(entriesForAddress))
+	mempoolAddrIndexer.unconfirmedTxnsForAddress = unconfirmedTxnsForAddress
+	emptyDB := defaultMockDB()
+	emptyDB.viewTx = &testDatabaseTx{
+		fetchBlockRegion: func(region *database.BlockRegion) ([]byte, error) {
+			return fetchBlockRegionMap[*region], nil
+		},
+		fetchBlockRegions: func(regions []database.BlockRegion) ([][]byte, error) {
+			return nil, nil
+		},
+	}
+
 	// Set result and filteredResult to the results that are expected.
 	result := make([]types.SearchRawTransactionsResult, len(testTxs))
 	filteredResult := make([]types.SearchRawTransactionsResult, len(testTxs))
@@ -5090,6 +8113,74 @@ func TestHandleSearchRawTransactions(t *testing.T) {
 			tx1TestTx.hex,
 			tx0TestTx.hex,
 		},
+	}, {
+		name:    "handleSearchRawTransactions: ok first page",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address:  address,
+			VinExtra: dcrjson.Int(1),
+			Verbose:  dcrjson.Int(0),
+			Count:    dcrjson.Int(1),
+			Cursor:   dcrjson.String(""),
+		},
+		mockAddrIndexer: addrIndexer,
+		mockDB:          db,
+		result: &types.SearchRawTransactionsPageResult{
+			Hex: []string{tx0TestTx.hex},
+			Page: types.PageInfo{
+				NextCursor:    "00000001",
+				Limit:         1,
+				TotalEstimate: 2,
+			},
+		},
+	}, {
+		name:    "handleSearchRawTransactions: ok final page verbose",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address:  address,
+			VinExtra: dcrjson.Int(1),
+			Verbose:  dcrjson.Int(1),
+			Count:    dcrjson.Int(1),
+			Cursor:   dcrjson.String("00000001"),
+		},
+		mockAddrIndexer: mempoolAddrIndexer,
+		mockDB:          emptyDB,
+		mockTxIndexer:   txIndexer,
+		result: &types.SearchRawTransactionsPageResult{
+			Transactions: result[1:],
+			Page: types.PageInfo{
+				Limit:         1,
+				TotalEstimate: 2,
+			},
+		},
+	}, {
+		name:    "handleSearchRawTransactions: ok past final page",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address:  address,
+			VinExtra: dcrjson.Int(1),
+			Verbose:  dcrjson.Int(0),
+			Cursor:   dcrjson.String("00000005"),
+		},
+		mockAddrIndexer: mempoolAddrIndexer,
+		mockDB:          emptyDB,
+		result: &types.SearchRawTransactionsPageResult{
+			Hex: []string{},
+			Page: types.PageInfo{
+				Limit:         100,
+				TotalEstimate: 2,
+			},
+		},
+	}, {
+		name:    "handleSearchRawTransactions: invalid cursor",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address:  address,
+			VinExtra: dcrjson.Int(1),
+			Cursor:   dcrjson.String("zz"),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
 	}, {
 		name:    "handleSearchRawTransactions: address index not enabled",
 		handler: handleSearchRawTransactions,
@@ -5257,6 +8348,146 @@ func TestHandleSearchRawTransactions(t *testing.T) {
 	}})
 }
 
+func TestHandleSimulateDifficulty(t *testing.T) {
+	t.Parallel()
+
+	// Mock a chain where each header fetched while estimating the network hash
+	// rate is five minutes after the previous one so the estimate is the work
+	// per block divided by 300 seconds.
+	blkHeader := block432100.Header
+	mockChain := func() *testRPCChain {
+		chain := defaultMockRPCChain()
+		var numFetched int64
+		chain.headerByHashFn = func(hash *chainhash.Hash) (wire.BlockHeader, error) {
+			header := blkHeader
+			header.Timestamp = blkHeader.Timestamp.Add(time.Duration(
+				numFetched) * 300 * time.Second)
+			numFetched++
+			return header, nil
+		}
+		chain.simulateRequiredDifficultyFn = func(hashesPerSec *big.Int, numBlocks int64) []standalone.SimulatedWorkDiff {
+			// Project a halving of the target for doubled hash rates and
+			// the current difficulty otherwise.
+			bits := blkHeader.Bits
+			if hashesPerSec.Cmp(big.NewInt(0).Div(standalone.CalcWork(
+				blkHeader.Bits), big.NewInt(300))) > 0 {
+
+				target := standalone.CompactToBig(bits)
+				bits = standalone.BigToCompact(target.Rsh(target, 1))
+			}
+			return []standalone.SimulatedWorkDiff{{
+				Height:    int64(blkHeader.Height) + numBlocks,
+				Bits:      bits,
+				Timestamp: 1584300000,
+			}}
+		}
+		return chain
+	}
+	networkHashPS := new(big.Int).Div(standalone.CalcWork(blkHeader.Bits),
+		big.NewInt(300)).Int64()
+	halvedTarget := standalone.CompactToBig(blkHeader.Bits)
+	halvedTargetBits := standalone.BigToCompact(halvedTarget.Rsh(halvedTarget, 1))
+	defaultNumBlocks := int64(defaultChainParams.WorkDiffWindowSize *
+		defaultChainParams.WorkDiffWindows)
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:      "handleSimulateDifficulty: ok",
+		handler:   handleSimulateDifficulty,
+		mockChain: mockChain(),
+		cmd: &types.SimulateDifficultyCmd{
+			HashRateMultipliers: []float64{1, 2},
+		},
+		result: &types.SimulateDifficultyResult{
+			Height:        int64(blkHeader.Height),
+			Bits:          strconv.FormatInt(int64(blkHeader.Bits), 16),
+			Difficulty:    getDifficultyRatio(blkHeader.Bits, defaultChainParams),
+			NetworkHashPS: networkHashPS,
+			Scenarios: []types.SimulateDifficultyScenario{{
+				HashRateMultiplier: 1,
+				HashesPerSec:       networkHashPS,
+				Retargets: []types.SimulateDifficultyRetarget{{
+					Height:     int64(blkHeader.Height) + defaultNumBlocks,
+					Bits:       strconv.FormatInt(int64(blkHeader.Bits), 16),
+					Difficulty: getDifficultyRatio(blkHeader.Bits, defaultChainParams),
+					Time:       1584300000,
+				}},
+			}, {
+				HashRateMultiplier: 2,
+				HashesPerSec:       networkHashPS * 2,
+				Retargets: []types.SimulateDifficultyRetarget{{
+					Height:     int64(blkHeader.Height) + defaultNumBlocks,
+					Bits:       strconv.FormatInt(int64(halvedTargetBits), 16),
+					Difficulty: getDifficultyRatio(halvedTargetBits, defaultChainParams),
+					Time:       1584300000,
+				}},
+			}},
+		},
+	}, {
+		name:      "handleSimulateDifficulty: ok with blocks",
+		handler:   handleSimulateDifficulty,
+		mockChain: mockChain(),
+		cmd: &types.SimulateDifficultyCmd{
+			HashRateMultipliers: []float64{1},
+			Blocks:              dcrjson.Int32(144),
+		},
+		result: &types.SimulateDifficultyResult{
+			Height:        int64(blkHeader.Height),
+			Bits:          strconv.FormatInt(int64(blkHeader.Bits), 16),
+			Difficulty:    getDifficultyRatio(blkHeader.Bits, defaultChainParams),
+			NetworkHashPS: networkHashPS,
+			Scenarios: []types.SimulateDifficultyScenario{{
+				HashRateMultiplier: 1,
+				HashesPerSec:       networkHashPS,
+				Retargets: []types.SimulateDifficultyRetarget{{
+					Height:     int64(blkHeader.Height) + 144,
+					Bits:       strconv.FormatInt(int64(blkHeader.Bits), 16),
+					Difficulty: getDifficultyRatio(blkHeader.Bits, defaultChainParams),
+					Time:       1584300000,
+				}},
+			}},
+		},
+	}, {
+		name:    "handleSimulateDifficulty: no multipliers",
+		handler: handleSimulateDifficulty,
+		cmd:     &types.SimulateDifficultyCmd{},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleSimulateDifficulty: too many multipliers",
+		handler: handleSimulateDifficulty,
+		cmd: &types.SimulateDifficultyCmd{
+			HashRateMultipliers: make([]float64, maxSimulateDifficultyScenarios+1),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleSimulateDifficulty: non-positive multiplier",
+		handler: handleSimulateDifficulty,
+		cmd: &types.SimulateDifficultyCmd{
+			HashRateMultipliers: []float64{1, 0},
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleSimulateDifficulty: too many blocks",
+		handler: handleSimulateDifficulty,
+		cmd: &types.SimulateDifficultyCmd{
+			HashRateMultipliers: []float64{1},
+			Blocks:              dcrjson.Int32(maxSimulateDifficultyBlocks + 1),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleSimulateDifficulty: unknown network hash rate",
+		handler: handleSimulateDifficulty,
+		cmd: &types.SimulateDifficultyCmd{
+			HashRateMultipliers: []float64{1},
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCMisc,
+	}})
+}
+
 func TestHandleSubmitBlock(t *testing.T) {
 	t.Parallel()
 
@@ -5351,54 +8582,417 @@ func TestHandleVerifyChain(t *testing.T) {
 	hash := mustParseHash("00000000000000000c07ff0178ad600db22c18f8f47fa423fa144b2ca919475d")
 
 	testRPCServerHandler(t, []rpcTest{{
-		name:    "handleVerifyChain: ok",
-		handler: handleVerifyChain,
-		cmd:     &types.VerifyChainCmd{},
-		result:  true,
+		name:    "handleVerifyChain: ok",
+		handler: handleVerifyChain,
+		cmd:     &types.VerifyChainCmd{},
+		result:  true,
+	}, {
+		name:    "handleVerifyChain: ok with depth=50",
+		handler: handleVerifyChain,
+		cmd: &types.VerifyChainCmd{
+			CheckDepth: dcrjson.Int64(50),
+		},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.bestSnapshot = &blockchain.BestState{
+				Height: 1,
+				Hash:   *hash,
+			}
+			return chain
+		}(),
+		result: true,
+	}, {
+		name:    "handleVerifyChain: invalid block with checklevel=1",
+		handler: handleVerifyChain,
+		cmd: &types.VerifyChainCmd{
+			CheckLevel: dcrjson.Int64(1),
+			CheckDepth: dcrjson.Int64(50),
+		},
+		mockSanityChecker: func() *testSanityChecker {
+			checker := defaultMockSanityChecker()
+			checker.checkBlockSanityErr = errors.New("invalid block")
+			return checker
+		}(),
+		result: false,
+	}, {
+		name:    "handleVerifyChain: no block exists",
+		handler: handleVerifyChain,
+		cmd: &types.VerifyChainCmd{
+			CheckDepth: dcrjson.Int64(50),
+		},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.bestSnapshot = &blockchain.BestState{
+				Height: 100,
+				Hash:   *hash,
+			}
+			chain.blockByHeightErr = errors.New("no block exists")
+			return chain
+		}(),
+		result: false,
+	}})
+}
+
+func TestHandleVerifyMessage(t *testing.T) {
+	t.Parallel()
+
+	// Create deterministic keys along with the pay-to-pubkey-hash addresses
+	// for them and a 2-of-3 multisig pay-to-script-hash address.
+	params := defaultChainParams
+	privKeys := make([]*secp256k1.PrivateKey, 3)
+	pubKeyAddrs := make([]*dcrutil.AddressSecpPubKey, 3)
+	for i := range privKeys {
+		privKeys[i] = secp256k1.PrivKeyFromBytes([]byte{byte(i + 1)})
+		addr, err := dcrutil.NewAddressSecpPubKeyCompressed(
+			privKeys[i].PubKey(), params)
+		if err != nil {
+			t.Fatalf("unable to create pubkey address: %v", err)
+		}
+		pubKeyAddrs[i] = addr
+	}
+	pubKey := privKeys[0].PubKey().SerializeCompressed()
+	pubKeyHex := hex.EncodeToString(pubKey)
+	ecdsaAddr := pubKeyAddrs[0].AddressPubKeyHash().Address()
+	schnorrAddr, err := dcrutil.NewAddressPubKeyHash(dcrutil.Hash160(pubKey),
+		params, dcrec.STSchnorrSecp256k1)
+	if err != nil {
+		t.Fatalf("unable to create schnorr address: %v", err)
+	}
+	redeemScript, err := txscript.MultiSigScript(pubKeyAddrs, 2)
+	if err != nil {
+		t.Fatalf("unable to create multisig script: %v", err)
+	}
+	redeemScriptHex := hex.EncodeToString(redeemScript)
+	scriptAddr, err := dcrutil.NewAddressScriptHash(redeemScript, params)
+	if err != nil {
+		t.Fatalf("unable to create script hash address: %v", err)
+	}
+
+	// Create signatures of the message with the keys.
+	const message = "proof of ownership"
+	msgHash := signedMessageHash(message)
+	compactSig := func(i int) []byte {
+		return ecdsa.SignCompact(privKeys[i], msgHash, true)
+	}
+	schnorrSig, err := schnorr.Sign(privKeys[0], msgHash)
+	if err != nil {
+		t.Fatalf("unable to create schnorr signature: %v", err)
+	}
+	schnorrProof := append(append([]byte(nil), pubKey...),
+		schnorrSig.Serialize()...)
+	b64 := base64.StdEncoding.EncodeToString
+	multisigProof := append(compactSig(2), compactSig(0)...)
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleVerifyMessage: ok ecdsa",
+		handler: handleVerifyMessage,
+		cmd: &types.VerifyMessageCmd{
+			Address:   ecdsaAddr,
+			Signature: b64(compactSig(0)),
+			Message:   message,
+		},
+		result: true,
+	}, {
+		name:    "handleVerifyMessage: wrong message",
+		handler: handleVerifyMessage,
+		cmd: &types.VerifyMessageCmd{
+			Address:   ecdsaAddr,
+			Signature: b64(compactSig(0)),
+			Message:   "other message",
+		},
+		result: false,
+	}, {
+		name:    "handleVerifyMessage: ok ecdsa verbose",
+		handler: handleVerifyMessage,
+		cmd: &types.VerifyMessageCmd{
+			Address:   ecdsaAddr,
+			Signature: b64(compactSig(0)),
+			Message:   message,
+			Verbose:   dcrjson.Bool(true),
+		},
+		result: &types.VerifyMessageResult{
+			Verified:   true,
+			Address:    ecdsaAddr,
+			ScriptType: "pubkeyhash",
+			SigType:    "ecdsa",
+			Signers:    []string{pubKeyHex},
+		},
+	}, {
+		name:    "handleVerifyMessage: wrong key verbose",
+		handler: handleVerifyMessage,
+		cmd: &types.VerifyMessageCmd{
+			Address:   ecdsaAddr,
+			Signature: b64(compactSig(1)),
+			Message:   message,
+			Verbose:   dcrjson.Bool(true),
+		},
+		result: &types.VerifyMessageResult{
+			Address:    ecdsaAddr,
+			ScriptType: "pubkeyhash",
+			SigType:    "ecdsa",
+			Reason:     "signature was not produced by the key for the address",
+		},
+	}, {
+		name:    "handleVerifyMessage: ok schnorr verbose",
+		handler: handleVerifyMessage,
+		cmd: &types.VerifyMessageCmd{
+			Address:   schnorrAddr.Address(),
+			Signature: b64(schnorrProof),
+			Message:   message,
+			Verbose:   dcrjson.Bool(true),
+		},
+		result: &types.VerifyMessageResult{
+			Verified:   true,
+			Address:    schnorrAddr.Address(),
+			ScriptType: "pubkeyhash",
+			SigType:    "schnorr",
+			Signers:    []string{pubKeyHex},
+		},
+	}, {
+		name:    "handleVerifyMessage: schnorr proof without public key",
+		handler: handleVerifyMessage,
+		cmd: &types.VerifyMessageCmd{
+			Address:   schnorrAddr.Address(),
+			Signature: b64(schnorrSig.Serialize()),
+			Message:   message,
+		},
+		result: false,
+	}, {
+		name:    "handleVerifyMessage: ok multisig verbose",
+		handler: handleVerifyMessage,
+		cmd: &types.VerifyMessageCmd{
+			Address:      scriptAddr.Address(),
+			Signature:    b64(multisigProof),
+			Message:      message,
+			RedeemScript: dcrjson.String(redeemScriptHex),
+			Verbose:      dcrjson.Bool(true),
+		},
+		result: &types.VerifyMessageResult{
+			Verified:     true,
+			Address:      scriptAddr.Address(),
+			ScriptType:   "scripthash",
+			SigType:      "ecdsa",
+			SigsRequired: 2,
+			Signers: []string{
+				hex.EncodeToString(pubKeyAddrs[2].ScriptAddress()),
+				pubKeyHex,
+			},
+		},
+	}, {
+		name:    "handleVerifyMessage: multisig too few signatures",
+		handler: handleVerifyMessage,
+		cmd: &types.VerifyMessageCmd{
+			Address:      scriptAddr.Address(),
+			Signature:    b64(compactSig(1)),
+			Message:      message,
+			RedeemScript: dcrjson.String(redeemScriptHex),
+		},
+		result: false,
+	}, {
+		name:    "handleVerifyMessage: multisig duplicate signatures",
+		handler: handleVerifyMessage,
+		cmd: &types.VerifyMessageCmd{
+			Address:      scriptAddr.Address(),
+			Signature:    b64(append(compactSig(1), compactSig(1)...)),
+			Message:      message,
+			RedeemScript: dcrjson.String(redeemScriptHex),
+		},
+		result: false,
+	}, {
+		name:    "handleVerifyMessage: mismatched redeem script",
+		handler: handleVerifyMessage,
+		cmd: &types.VerifyMessageCmd{
+			Address:      scriptAddr.Address(),
+			Signature:    b64(multisigProof),
+			Message:      message,
+			RedeemScript: dcrjson.String("51"),
+		},
+		result: false,
+	}, {
+		name:    "handleVerifyMessage: missing redeem script",
+		handler: handleVerifyMessage,
+		cmd: &types.VerifyMessageCmd{
+			Address:   scriptAddr.Address(),
+			Signature: b64(multisigProof),
+			Message:   message,
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleVerifyMessage: invalid redeem script hex",
+		handler: handleVerifyMessage,
+		cmd: &types.VerifyMessageCmd{
+			Address:      scriptAddr.Address(),
+			Signature:    b64(multisigProof),
+			Message:      message,
+			RedeemScript: dcrjson.String("zz"),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCDecodeHexString,
+	}, {
+		name:    "handleVerifyMessage: invalid address",
+		handler: handleVerifyMessage,
+		cmd: &types.VerifyMessageCmd{
+			Address:   "invalid",
+			Signature: b64(compactSig(0)),
+			Message:   message,
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidAddressOrKey,
+	}, {
+		name:    "handleVerifyMessage: unsupported address type",
+		handler: handleVerifyMessage,
+		cmd: &types.VerifyMessageCmd{
+			Address:   pubKeyAddrs[0].String(),
+			Signature: b64(compactSig(0)),
+			Message:   message,
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCType,
+	}, {
+		name:    "handleVerifyMessage: malformed signature encoding",
+		handler: handleVerifyMessage,
+		cmd: &types.VerifyMessageCmd{
+			Address:   ecdsaAddr,
+			Signature: "***",
+			Message:   message,
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCParse.Code,
+	}})
+}
+
+func TestHandleGetVoteInfo(t *testing.T) {
+	t.Parallel()
+
+	blk := dcrutil.NewBlock(&block432100)
+	blkHashString := blk.Hash().String()
+	blkHeight := blk.Height()
+	deployment := defaultChainParams.Deployments[7][0]
+	voteCounts := blockchain.VoteCounts{
+		Total:        100,
+		TotalAbstain: 10,
+		VoteChoices:  []uint32{10, 20, 70},
+	}
+	mockChain := func() *testRPCChain {
+		chain := defaultMockRPCChain()
+		chain.countVoteVersion = 100
+		chain.getVoteCounts = voteCounts
+		chain.getVoteInfo = &blockchain.VoteInfo{
+			Agendas: []chaincfg.ConsensusDeployment{deployment},
+			AgendaStatus: []blockchain.ThresholdStateTuple{{
+				State:  blockchain.ThresholdStarted,
+				Choice: uint32(0xffffffff),
+			}},
+		}
+		return chain
+	}
+	agendas := []types.Agenda{{
+		ID:             deployment.Vote.Id,
+		Description:    deployment.Vote.Description,
+		Mask:           deployment.Vote.Mask,
+		StartTime:      deployment.StartTime,
+		ExpireTime:     deployment.ExpireTime,
+		Status:         "started",
+		QuorumProgress: float64(90) / float64(4032),
+		Choices: []types.Choice{{
+			ID:          "abstain",
+			Description: "abstain voting for change",
+			Bits:        0x0000,
+			IsAbstain:   true,
+			Count:       10,
+			Progress:    0.1,
+		}, {
+			ID:          "no",
+			Description: "keep the existing consensus rules",
+			Bits:        0x0002,
+			IsNo:        true,
+			Count:       20,
+			Progress:    0.2,
+		}, {
+			ID:          "yes",
+			Description: "change to the new consensus rules",
+			Bits:        0x0004,
+			Count:       70,
+			Progress:    0.7,
+		}},
+	}}
+	priorInterval := func(endHeight int64) types.VoteInterval {
+		return types.VoteInterval{
+			StartHeight: endHeight - 8064 + 1,
+			EndHeight:   endHeight,
+			Hash:        blkHashString,
+			Agendas: []types.AgendaVoteCounts{{
+				ID:           deployment.Vote.Id,
+				Total:        100,
+				TotalAbstain: 10,
+				ChoiceCounts: []uint32{10, 20, 70},
+			}},
+		}
+	}
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleGetVoteInfo: ok",
+		handler: handleGetVoteInfo,
+		cmd: &types.GetVoteInfoCmd{
+			Version: 7,
+		},
+		mockChain: mockChain(),
+		result: types.GetVoteInfoResult{
+			CurrentHeight: blkHeight,
+			StartHeight:   431488,
+			EndHeight:     439551,
+			Hash:          blkHashString,
+			VoteVersion:   7,
+			Quorum:        4032,
+			TotalVotes:    100,
+			Agendas:       agendas,
+		},
 	}, {
-		name:    "handleVerifyChain: ok with depth=50",
-		handler: handleVerifyChain,
-		cmd: &types.VerifyChainCmd{
-			CheckDepth: dcrjson.Int64(50),
+		name:    "handleGetVoteInfo: prior intervals",
+		handler: handleGetVoteInfo,
+		cmd: &types.GetVoteInfoCmd{
+			Version: 7,
+			Count:   dcrjson.Int32(2),
+		},
+		mockChain: mockChain(),
+		result: types.GetVoteInfoResult{
+			CurrentHeight: blkHeight,
+			StartHeight:   431488,
+			EndHeight:     439551,
+			Hash:          blkHashString,
+			VoteVersion:   7,
+			Quorum:        4032,
+			TotalVotes:    100,
+			Agendas:       agendas,
+			PriorIntervals: []types.VoteInterval{
+				priorInterval(431487),
+				priorInterval(423423),
+			},
 		},
-		mockChain: func() *testRPCChain {
-			chain := defaultMockRPCChain()
-			chain.bestSnapshot = &blockchain.BestState{
-				Height: 1,
-				Hash:   *hash,
-			}
-			return chain
-		}(),
-		result: true,
 	}, {
-		name:    "handleVerifyChain: invalid block with checklevel=1",
-		handler: handleVerifyChain,
-		cmd: &types.VerifyChainCmd{
-			CheckLevel: dcrjson.Int64(1),
-			CheckDepth: dcrjson.Int64(50),
+		name:    "handleGetVoteInfo: invalid count",
+		handler: handleGetVoteInfo,
+		cmd: &types.GetVoteInfoCmd{
+			Version: 7,
+			Count:   dcrjson.Int32(-1),
 		},
-		mockSanityChecker: func() *testSanityChecker {
-			checker := defaultMockSanityChecker()
-			checker.checkBlockSanityErr = errors.New("invalid block")
-			return checker
-		}(),
-		result: false,
+		mockChain: mockChain(),
+		wantErr:   true,
+		errCode:   dcrjson.ErrRPCInvalidParameter,
 	}, {
-		name:    "handleVerifyChain: no block exists",
-		handler: handleVerifyChain,
-		cmd: &types.VerifyChainCmd{
-			CheckDepth: dcrjson.Int64(50),
+		name:    "handleGetVoteInfo: unable to get prior interval hash",
+		handler: handleGetVoteInfo,
+		cmd: &types.GetVoteInfoCmd{
+			Version: 7,
+			Count:   dcrjson.Int32(1),
 		},
 		mockChain: func() *testRPCChain {
-			chain := defaultMockRPCChain()
-			chain.bestSnapshot = &blockchain.BestState{
-				Height: 100,
-				Hash:   *hash,
-			}
-			chain.blockByHeightErr = errors.New("no block exists")
+			chain := mockChain()
+			chain.blockHashByHeightErr = errors.New("no block exists")
 			return chain
 		}(),
-		result: false,
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
 	}})
 }
 
@@ -5645,6 +9239,65 @@ func TestHandleGetWork(t *testing.T) {
 	}})
 }
 
+func TestHandleGetWorkStats(t *testing.T) {
+	t.Parallel()
+
+	// Create a work state with the statistics of two clients where the first
+	// client submitted an accepted and a stale solution and the second client
+	// only requested work.
+	now := time.Unix(1592931302, 0)
+	workState := newWorkState()
+	for i := 9; i >= 0; i-- {
+		reqTime := now.Add(-time.Duration(i) * time.Minute)
+		workState.stats.requested(reqTime, "127.0.0.1:50001")
+	}
+	workState.stats.submitted(now.Add(-2*time.Minute), "127.0.0.1:50001",
+		workAccepted)
+	workState.stats.submitted(now.Add(-time.Minute), "127.0.0.1:50001",
+		workStale)
+	workState.stats.requested(now.Add(-time.Hour), "10.0.0.2:50002")
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleGetWorkStats: no clients",
+		handler: handleGetWorkStats,
+		cmd:     &types.GetWorkStatsCmd{},
+		result: &types.GetWorkStatsResult{
+			Clients: []types.GetWorkClientResult{},
+		},
+	}, {
+		name:    "handleGetWorkStats: ok",
+		handler: handleGetWorkStats,
+		cmd:     &types.GetWorkStatsCmd{},
+		mockClock: &testClock{
+			now: now,
+		},
+		mockMiningState: func() *testMiningState {
+			ms := defaultMockMiningState()
+			ms.workState = workState
+			return ms
+		}(),
+		result: &types.GetWorkStatsResult{
+			Clients: []types.GetWorkClientResult{{
+				Addr:        "10.0.0.2:50002",
+				FirstSeen:   now.Add(-time.Hour).Unix(),
+				Requests:    1,
+				LastRequest: now.Add(-time.Hour).Unix(),
+			}, {
+				Addr:          "127.0.0.1:50001",
+				FirstSeen:     now.Add(-9 * time.Minute).Unix(),
+				Requests:      10,
+				RequestRate:   1,
+				LastRequest:   now.Unix(),
+				Submitted:     2,
+				Accepted:      1,
+				Stale:         1,
+				LastSubmitted: now.Add(-time.Minute).Unix(),
+				LastAccepted:  now.Add(-2 * time.Minute).Unix(),
+			}},
+		},
+	}})
+}
+
 func TestHandleSetGenerate(t *testing.T) {
 	t.Parallel()
 
@@ -5708,6 +9361,403 @@ func TestHandleRegenTemplate(t *testing.T) {
 	}})
 }
 
+func TestHandleRollbackChain(t *testing.T) {
+	t.Parallel()
+
+	chainParams := chaincfg.SimNetParams()
+	bestHeight := defaultMockRPCChain().bestSnapshot.Height
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleRollbackChain: ok",
+		handler: handleRollbackChain,
+		cmd: &types.RollbackChainCmd{
+			Height: bestHeight - 1,
+		},
+		mockChainParams: chainParams,
+	}, {
+		name:    "handleRollbackChain: not supported for network",
+		handler: handleRollbackChain,
+		cmd: &types.RollbackChainCmd{
+			Height: bestHeight - 1,
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCDifficulty,
+	}, {
+		name:    "handleRollbackChain: height is best height",
+		handler: handleRollbackChain,
+		cmd: &types.RollbackChainCmd{
+			Height: bestHeight,
+		},
+		mockChainParams: chainParams,
+		wantErr:         true,
+		errCode:         dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleRollbackChain: negative height",
+		handler: handleRollbackChain,
+		cmd: &types.RollbackChainCmd{
+			Height: -1,
+		},
+		mockChainParams: chainParams,
+		wantErr:         true,
+		errCode:         dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleRollbackChain: rollback error",
+		handler: handleRollbackChain,
+		cmd: &types.RollbackChainCmd{
+			Height: bestHeight - 1,
+		},
+		mockChainParams: chainParams,
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.rollbackToHeightErr = errors.New("rollback error")
+			return chain
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}})
+}
+
+func TestHandleMiningAddrs(t *testing.T) {
+	t.Parallel()
+
+	const encodedAddr = "DcurAwesomeAddressmqDctW5wJCW1Cn2MF"
+	addr, err := dcrutil.DecodeAddress(encodedAddr, defaultChainParams)
+	if err != nil {
+		t.Fatalf("[DecodeAddress] unexpected error: %v", err)
+	}
+	templater := defaultMockBlockTemplater()
+	templater.miningAddrs = []dcrutil.Address{addr}
+	templater.miningAddrPolicy = mining.MiningAddrRoundRobin
+	testRPCServerHandler(t, []rpcTest{{
+		name:                 "handleGetMiningAddrs: node is not configured for mining",
+		handler:              handleGetMiningAddrs,
+		cmd:                  &types.GetMiningAddrsCmd{},
+		setBlockTemplaterNil: true,
+		wantErr:              true,
+		errCode:              dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:               "handleGetMiningAddrs: ok",
+		handler:            handleGetMiningAddrs,
+		cmd:                &types.GetMiningAddrsCmd{},
+		mockBlockTemplater: templater,
+		result: &types.GetMiningAddrsResult{
+			Addresses: []string{encodedAddr},
+			Policy:    "roundrobin",
+		},
+	}, {
+		name:                 "handleSetMiningAddrs: node is not configured for mining",
+		handler:              handleSetMiningAddrs,
+		cmd:                  &types.SetMiningAddrsCmd{Addresses: []string{encodedAddr}},
+		setBlockTemplaterNil: true,
+		wantErr:              true,
+		errCode:              dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleSetMiningAddrs: no addresses",
+		handler: handleSetMiningAddrs,
+		cmd:     &types.SetMiningAddrsCmd{},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleSetMiningAddrs: invalid address",
+		handler: handleSetMiningAddrs,
+		cmd: &types.SetMiningAddrsCmd{
+			Addresses: []string{"SsWKp7wtdTZYabYFYSc9cnxhwFEjA5g4pFc"},
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidAddressOrKey,
+	}, {
+		name:    "handleSetMiningAddrs: invalid policy",
+		handler: handleSetMiningAddrs,
+		cmd: &types.SetMiningAddrsCmd{
+			Addresses: []string{encodedAddr},
+			Policy:    dcrjson.String("sequential"),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleSetMiningAddrs: templater error",
+		handler: handleSetMiningAddrs,
+		cmd:     &types.SetMiningAddrsCmd{Addresses: []string{encodedAddr}},
+		mockBlockTemplater: func() *testBlockTemplater {
+			templater := defaultMockBlockTemplater()
+			templater.setMiningAddrsErr = errors.New("set addresses failed")
+			return templater
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleSetMiningAddrs: ok",
+		handler: handleSetMiningAddrs,
+		cmd: &types.SetMiningAddrsCmd{
+			Addresses: []string{encodedAddr},
+			Policy:    dcrjson.String("roundrobin"),
+		},
+	}})
+
+	// Ensure the policy is unchanged when one is not specified.
+	testRPCServerHandler(t, []rpcTest{{
+		name:               "handleSetMiningAddrs: keep policy",
+		handler:            handleSetMiningAddrs,
+		cmd:                &types.SetMiningAddrsCmd{Addresses: []string{encodedAddr}},
+		mockBlockTemplater: templater,
+	}})
+	if templater.miningAddrPolicy != mining.MiningAddrRoundRobin {
+		t.Fatalf("unexpected policy -- got %v, want %v",
+			templater.miningAddrPolicy, mining.MiningAddrRoundRobin)
+	}
+}
+
+// TestHandleSendRawTransactionFeeExempt ensures transactions submitted by
+// clients that authenticated with credentials designated as exempt from the
+// minimum relay fee are processed as such while others are not.
+func TestHandleSendRawTransactionFeeExempt(t *testing.T) {
+	t.Parallel()
+
+	tx := wire.NewMsgTx()
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, 0, nil))
+	tx.AddTxOut(wire.NewTxOut(1e8, nil))
+	txBytes, err := tx.Bytes()
+	if err != nil {
+		t.Fatalf("unexpected error serializing tx: %v", err)
+	}
+	cmd := types.NewSendRawTransactionCmd(hex.EncodeToString(txBytes),
+		dcrjson.Bool(false))
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		exempt bool
+	}{{
+		name:   "exempt admin",
+		ctx:    withClientAdmin(context.Background(), true),
+		exempt: true,
+	}, {
+		name: "limited user",
+		ctx:  withClientAdmin(context.Background(), false),
+	}, {
+		name: "unknown credentials",
+		ctx:  context.Background(),
+	}}
+	for _, test := range tests {
+		syncMgr := defaultMockSyncManager()
+		cfg := defaultMockConfig(defaultChainParams)
+		cfg.RPCUser = "admin"
+		cfg.RPCLimitUser = "limited"
+		cfg.FeeExemptRPCUsers = []string{"admin"}
+		cfg.SyncMgr = syncMgr
+		s := &Server{cfg: *cfg}
+		s.ntfnMgr = newWsNotificationManager(s)
+
+		_, err := handleSendRawTransaction(test.ctx, s, cmd)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if syncMgr.feeExempt != test.exempt {
+			t.Errorf("%s: unexpected fee exemption -- got %v, want %v",
+				test.name, syncMgr.feeExempt, test.exempt)
+		}
+	}
+}
+
+func TestHandleSetMiningExtraData(t *testing.T) {
+	t.Parallel()
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:                 "handleSetMiningExtraData: node is not configured for mining",
+		handler:              handleSetMiningExtraData,
+		cmd:                  &types.SetMiningExtraDataCmd{ExtraData: "2f706f6f6c2f"},
+		setBlockTemplaterNil: true,
+		wantErr:              true,
+		errCode:              dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleSetMiningExtraData: invalid hex",
+		handler: handleSetMiningExtraData,
+		cmd:     &types.SetMiningExtraDataCmd{ExtraData: "2f706f6f6c2"},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCDecodeHexString,
+	}, {
+		name:    "handleSetMiningExtraData: too long",
+		handler: handleSetMiningExtraData,
+		cmd: &types.SetMiningExtraDataCmd{
+			ExtraData: strings.Repeat("01", mining.MaxCoinbaseExtraDataLen+1),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleSetMiningExtraData: templater error",
+		handler: handleSetMiningExtraData,
+		cmd:     &types.SetMiningExtraDataCmd{ExtraData: "2f706f6f6c2f"},
+		mockBlockTemplater: func() *testBlockTemplater {
+			templater := defaultMockBlockTemplater()
+			templater.setExtraDataErr = errors.New("set extra data failed")
+			return templater
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleSetMiningExtraData: ok",
+		handler: handleSetMiningExtraData,
+		cmd:     &types.SetMiningExtraDataCmd{ExtraData: "2f706f6f6c2f"},
+	}, {
+		name:    "handleSetMiningExtraData: ok empty",
+		handler: handleSetMiningExtraData,
+		cmd:     &types.SetMiningExtraDataCmd{ExtraData: ""},
+	}})
+}
+
+// TestHandleRPCClients ensures websocket clients are able to register their
+// name and version, are reported along with their subscriptions by the
+// listrpcclients handler, and may be disconnected by session ID.
+func TestHandleRPCClients(t *testing.T) {
+	t.Parallel()
+
+	s := &Server{cfg: *defaultMockConfig(defaultChainParams)}
+	s.ntfnMgr = newWsNotificationManager(s)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.ntfnMgr.Run(ctx)
+
+	// Create a websocket client backed by a real websocket connection so it
+	// can be disconnected.
+	conns := make(chan *websocket.Conn, 1)
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("unable to upgrade connection: %v", err)
+			return
+		}
+		conns <- conn
+	}))
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	clientConn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("unable to dial websocket server: %v", err)
+	}
+	defer clientConn.Close()
+	wsc, err := newWebsocketClient(s, <-conns, "127.0.0.1:19000", true, true)
+	if err != nil {
+		t.Fatalf("unable to create websocket client: %v", err)
+	}
+	s.ntfnMgr.AddClient(wsc)
+	s.ntfnMgr.RegisterBlockUpdates(wsc)
+	s.ntfnMgr.RegisterWorkUpdates(wsc)
+
+	// Ensure invalid client names are rejected.
+	badNames := []string{"", strings.Repeat("a", maxClientNameLen+1)}
+	for _, name := range badNames {
+		cmd := types.NewRegisterClientCmd(name, nil)
+		_, err := handleRegisterClient(wsc, cmd)
+		var rpcErr *dcrjson.RPCError
+		if !errors.As(err, &rpcErr) ||
+			rpcErr.Code != dcrjson.ErrRPCInvalidParameter {
+
+			t.Fatalf("unexpected error registering name %q -- got %v, "+
+				"want %v", name, err, dcrjson.ErrRPCInvalidParameter)
+		}
+	}
+	cmd := types.NewRegisterClientCmd("dcrdata", dcrjson.String("6.0.0"))
+	if _, err := handleRegisterClient(wsc, cmd); err != nil {
+		t.Fatalf("unexpected error registering client: %v", err)
+	}
+
+	// Ensure the client is listed with its details and subscriptions.
+	result, err := handleListRPCClients(ctx, s, types.NewListRPCClientsCmd())
+	if err != nil {
+		t.Fatalf("unexpected error listing clients: %v", err)
+	}
+	want := []types.ListRPCClientsResult{{
+		SessionID:     wsc.sessionID,
+		Addr:          "127.0.0.1:19000",
+		Name:          "dcrdata",
+		Version:       "6.0.0",
+		Authenticated: true,
+		Admin:         true,
+		ConnTime:      wsc.connTime.Unix(),
+		Subscriptions: []string{"blocks", "work"},
+	}}
+	if !reflect.DeepEqual(result, want) {
+		t.Fatalf("unexpected clients -- got %s, want %s", spew.Sdump(result),
+			spew.Sdump(want))
+	}
+
+	// Ensure disconnecting an unknown client fails while disconnecting the
+	// client by its session ID succeeds.
+	_, err = handleDisconnectRPCClient(ctx, s,
+		types.NewDisconnectRPCClientCmd(wsc.sessionID+1))
+	var rpcErr *dcrjson.RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != dcrjson.ErrRPCInvalidParameter {
+		t.Fatalf("unexpected error disconnecting unknown client -- got %v, "+
+			"want %v", err, dcrjson.ErrRPCInvalidParameter)
+	}
+	_, err = handleDisconnectRPCClient(ctx, s,
+		types.NewDisconnectRPCClientCmd(wsc.sessionID))
+	if err != nil {
+		t.Fatalf("unexpected error disconnecting client: %v", err)
+	}
+	if !wsc.Disconnected() {
+		t.Fatal("client was not disconnected")
+	}
+}
+
+// TestRuleViolationData ensures rule errors are converted to the expected
+// machine-readable rule violation details.
+func TestRuleViolationData(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want *types.RuleViolationData
+	}{{
+		name: "mempool policy violation",
+		err: mempool.RuleError{Err: mempool.TxRuleError{
+			ErrorCode:   mempool.ErrInsufficientFee,
+			Description: "insufficient fee",
+		}},
+		want: &types.RuleViolationData{
+			Category: types.RuleViolationPolicy,
+			Code:     "ErrInsufficientFee",
+			Message:  "insufficient fee",
+		},
+	}, {
+		name: "wrapped consensus violation",
+		err: mempool.RuleError{Err: blockchain.RuleError{
+			ErrorCode:   blockchain.ErrExpiredTx,
+			Description: "expired",
+		}},
+		want: &types.RuleViolationData{
+			Category: types.RuleViolationConsensus,
+			Code:     "ErrExpiredTx",
+			Message:  "expired",
+		},
+	}, {
+		name: "consensus violation",
+		err: blockchain.RuleError{
+			ErrorCode:   blockchain.ErrDuplicateBlock,
+			Description: "duplicate",
+		},
+		want: &types.RuleViolationData{
+			Category: types.RuleViolationConsensus,
+			Code:     "ErrDuplicateBlock",
+			Message:  "duplicate",
+		},
+	}, {
+		name: "not a rule error",
+		err:  errors.New("other"),
+		want: nil,
+	}}
+
+	for _, test := range tests {
+		got := ruleViolationData(test.err)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: mismatched data -- got %+v, want %+v",
+				test.name, got, test.want)
+		}
+	}
+}
+
 func testRPCServerHandler(t *testing.T, tests []rpcTest) {
 	t.Helper()
 
@@ -5745,6 +9795,9 @@ func testRPCServerHandler(t *testing.T, tests []rpcTest) {
 			if test.setAddrIndexerNil {
 				rpcserverConfig.AddrIndexer = nil
 			}
+			if test.mockAddrActivity != nil {
+				rpcserverConfig.AddrActivityIndexer = test.mockAddrActivity
+			}
 			if test.mockTxIndexer != nil {
 				rpcserverConfig.TxIndexer = test.mockTxIndexer
 			}
@@ -5760,6 +9813,9 @@ func testRPCServerHandler(t *testing.T, tests []rpcTest) {
 			if test.mockClock != nil {
 				rpcserverConfig.Clock = test.mockClock
 			}
+			if test.mockTimeSource != nil {
+				rpcserverConfig.TimeSource = test.mockTimeSource
+			}
 			if test.mockFeeEstimator != nil {
 				rpcserverConfig.FeeEstimator = test.mockFeeEstimator
 			}
@@ -5775,6 +9831,16 @@ func testRPCServerHandler(t *testing.T, tests []rpcTest) {
 			if test.mockFiltererV2 != nil {
 				rpcserverConfig.FiltererV2 = test.mockFiltererV2
 			}
+			if test.mockDiskSpaceMonitor != nil {
+				rpcserverConfig.DiskSpaceMonitor = test.mockDiskSpaceMonitor
+			}
+			if test.mockPeerTelemetry != nil {
+				rpcserverConfig.PeerTelemetry = test.mockPeerTelemetry
+			}
+			if test.mockCoordinator != nil {
+				rpcserverConfig.Coordinator = test.mockCoordinator
+			}
+			rpcserverConfig.ProfileDir = test.profileDir
 			if test.mockCPUMiner != nil {
 				rpcserverConfig.CPUMiner = test.mockCPUMiner
 			}
@@ -5822,3 +9888,130 @@ func testRPCServerHandler(t *testing.T, tests []rpcTest) {
 		})
 	}
 }
+
+// TestBlockStakeEvents ensures the summary of stake events included in block
+// connected notifications describes the stake transactions in a block and the
+// choices made by its votes.
+func TestBlockStakeEvents(t *testing.T) {
+	t.Parallel()
+
+	// Modify a copy of the first vote in the block to disapprove of the
+	// parent block and vote yes on the header commitments agenda.
+	msgBlock := block432100
+	msgBlock.STransactions = make([]*wire.MsgTx, len(block432100.STransactions))
+	copy(msgBlock.STransactions, block432100.STransactions)
+	for i, tx := range msgBlock.STransactions {
+		if !stake.IsSSGen(tx) {
+			continue
+		}
+		voteTx := tx.Copy()
+		voteTx.TxOut[1].PkScript[2] = 0x04
+		msgBlock.STransactions[i] = voteTx
+		break
+	}
+	events := blockStakeEvents(dcrutil.NewBlock(&msgBlock), defaultChainParams)
+
+	header := &msgBlock.Header
+	if len(events.Tickets) != int(header.FreshStake) {
+		t.Fatalf("unexpected number of tickets -- got %d, want %d",
+			len(events.Tickets), header.FreshStake)
+	}
+	if len(events.Votes) != int(header.Voters) {
+		t.Fatalf("unexpected number of votes -- got %d, want %d",
+			len(events.Votes), header.Voters)
+	}
+	if len(events.Revocations) != int(header.Revocations) {
+		t.Fatalf("unexpected number of revocations -- got %d, want %d",
+			len(events.Revocations), header.Revocations)
+	}
+
+	wantTicket := types.TicketPurchaseEvent{
+		TxHash: "343cfa39bb122171b758edfe378e222ab702d78ca8ac6ad4b797e8353fe70f34",
+		Price:  144.2816259,
+	}
+	if !reflect.DeepEqual(events.Tickets[0], wantTicket) {
+		t.Fatalf("unexpected ticket -- got %+v, want %+v", events.Tickets[0],
+			wantTicket)
+	}
+	wantVotes := []types.VoteEvent{{
+		Ticket:   "2ff4c7b131b701aafad03e2da7254d0c8968602238dfbe8c72e4e1e70cb9b232",
+		Version:  7,
+		VoteBits: 0x0004,
+		Approves: false,
+		Choices: []types.VoteChoiceEvent{{
+			AgendaID: chaincfg.VoteIDHeaderCommitments,
+			ChoiceID: "yes",
+		}},
+	}, {
+		TxHash:   "439ea206a41a6d374f0fc88b68af434b58499579850b885e79bc657a2a5f88b8",
+		Ticket:   "18ca2d46475ed3a498dfae786b6d212578fed545d87ec23e3364dfe32c161c39",
+		Version:  7,
+		VoteBits: 0x0001,
+		Approves: true,
+		Choices: []types.VoteChoiceEvent{{
+			AgendaID: chaincfg.VoteIDHeaderCommitments,
+			ChoiceID: "abstain",
+		}},
+	}}
+	for i, want := range wantVotes {
+		// The hash of the modified vote changes.
+		if want.TxHash == "" {
+			want.TxHash = events.Votes[i].TxHash
+		}
+		if !reflect.DeepEqual(events.Votes[i], want) {
+			t.Fatalf("unexpected vote %d -- got %+v, want %+v", i,
+				events.Votes[i], want)
+		}
+	}
+}
+
+// TestBlockTxProver ensures the merkle inclusion proofs generated for the
+// transactions in a block prove their inclusion in the merkle roots committed
+// to by the block header both before and after the header commitments agenda.
+func TestBlockTxProver(t *testing.T) {
+	t.Parallel()
+
+	// verifyProofs ensures the proofs for all transactions in the provided
+	// block verify against the provided roots for each tree.
+	verifyProofs := func(name string, msgBlock *wire.MsgBlock, regularRoot, stakeRoot *chainhash.Hash) {
+		t.Helper()
+
+		prover := newBlockTxProver(msgBlock)
+		trees := []struct {
+			tree int8
+			txns []*wire.MsgTx
+			root *chainhash.Hash
+		}{
+			{wire.TxTreeRegular, msgBlock.Transactions, regularRoot},
+			{wire.TxTreeStake, msgBlock.STransactions, stakeRoot},
+		}
+		for _, tree := range trees {
+			for i, tx := range tree.txns {
+				proof, proofIndex := prover.proof(tree.tree, uint32(i))
+				leaf := tx.TxHashFull()
+				if !standalone.VerifyInclusionProof(tree.root, &leaf,
+					proofIndex, proof) {
+
+					t.Fatalf("%s: invalid proof for tx %d of tree %d", name,
+						i, tree.tree)
+				}
+			}
+		}
+	}
+
+	// Ensure the proofs for both trees verify against the merkle root of a
+	// block that commits to the combined merkle root.
+	msgBlock := block432100
+	verifyProofs("combined root", &msgBlock, &msgBlock.Header.MerkleRoot,
+		&msgBlock.Header.MerkleRoot)
+
+	// Ensure the proofs verify against the regular and stake roots of a block
+	// prior to the header commitments agenda.  Note that the stake root field
+	// of the header is repurposed by the agenda, so it is replaced as well.
+	msgBlock.Header.MerkleRoot = standalone.CalcTxTreeMerkleRoot(
+		msgBlock.Transactions)
+	msgBlock.Header.StakeRoot = standalone.CalcTxTreeMerkleRoot(
+		msgBlock.STransactions)
+	verifyProofs("separate roots", &msgBlock, &msgBlock.Header.MerkleRoot,
+		&msgBlock.Header.StakeRoot)
+}
@@ -7,6 +7,7 @@ package rpcserver
 
 import (
 	"errors"
+	"fmt"
 	"sort"
 	"strings"
 	"sync"
@@ -41,6 +42,61 @@ var helpDescsEnUS = map[string]string{
 	"node-target":        "Either the IP address and port of the peer to operate on, or a valid peer ID.",
 	"node-connectsubcmd": "'perm' to make the connected peer a permanent one, 'temp' to try a single connect to a peer",
 
+	// CaptureProfileCmd help.
+	"captureprofile--synopsis": "Captures a profile of the process in the pprof format to a new file in the profiles directory of the data directory and returns its path.\n" +
+		"The cpu, block, and mutex profiles sample the process for the specified duration before returning while the remaining profiles are a snapshot of its current state.\n" +
+		"Only a single profile may be captured at a time.",
+	"captureprofile-type":     "The type of profile to capture (cpu, heap, allocs, goroutine, threadcreate, block, mutex)",
+	"captureprofile-duration": "The number of seconds to sample the process for when capturing a cpu, block, or mutex profile (max 300)",
+
+	// CaptureProfileResult help.
+	"captureprofileresult-type": "The type of the captured profile",
+	"captureprofileresult-path": "The path of the file the profile was written to",
+
+	// CheckTransactionStandardCmd help.
+	"checktransactionstandard--synopsis": "Returns every violation of the policy used to determine whether or not transactions are accepted to the memory pool as standard transactions by the provided transaction.\n" +
+		"Standardness is determined as of the next block regardless of whether or not non-standard transactions are accepted.\n" +
+		"Inputs that spend outputs which are not unspent in either the main chain or the memory pool are skipped and the transaction is not otherwise validated.",
+	"checktransactionstandard-hextx": "Serialized, hex-encoded transaction",
+
+	// CheckTransactionStandardResult help.
+	"checktransactionstandardresult-standard":   "Whether or not the transaction is standard",
+	"checktransactionstandardresult-violations": "The violations of the standardness policy rules by the transaction",
+
+	// StandardViolationResult help.
+	"standardviolationresult-rule":        "The policy rule that is violated (sertype, txversion, finalized, txsize, sigscriptsize, sigscriptpushonly, scriptversion, scriptform, multisig, p2shsigops, dust, nulldataoutputs)",
+	"standardviolationresult-input":       "The index of the input that violates the rule (omitted when the rule does not apply to a specific input)",
+	"standardviolationresult-output":      "The index of the output that violates the rule (omitted when the rule does not apply to a specific output)",
+	"standardviolationresult-description": "A human readable description of the violation",
+
+	// CompareChainWorkCmd help.
+	"comparechainwork--synopsis": "Compares the cumulative proof of work of a chain of block headers that connects to a locally known block with the work of the current best chain and reports the point at which they fork.\n" +
+		"The proof of work of each header is verified against its claimed difficulty, however the difficulties themselves are not validated against the retarget rules.",
+	"comparechainwork-headers": "Serialized, hex-encoded block headers ordered from oldest to newest with each one building on the previous one",
+
+	// CompareChainWorkResult help.
+	"comparechainworkresult-forkhash":      "The hash of the most recent block the provided chain and the best chain have in common",
+	"comparechainworkresult-forkheight":    "The height of the most recent block the provided chain and the best chain have in common",
+	"comparechainworkresult-tiphash":       "The hash of the final provided header",
+	"comparechainworkresult-tipheight":     "The height of the final provided header",
+	"comparechainworkresult-chainwork":     "Hex encoded total work of the provided chain",
+	"comparechainworkresult-besthash":      "The hash of the current best chain tip",
+	"comparechainworkresult-bestheight":    "The height of the current best chain tip",
+	"comparechainworkresult-bestchainwork": "Hex encoded total work of the current best chain",
+	"comparechainworkresult-morework":      "Whether or not the provided chain has more cumulative work than the current best chain",
+
+	// CoordHeartbeatCmd help.
+	"coordheartbeat--synopsis": "Exchanges the leader election state with the peer node of a redundancy pair that shares a virtual IP address.\n" +
+		"This is used by the nodes of the pair to agree which one of them serves miners and websocket clients and is not intended to be called manually.",
+	"coordheartbeat-nodeid": "The identifier of the node sending the heartbeat",
+	"coordheartbeat-term":   "The election term of the node sending the heartbeat",
+	"coordheartbeat-leader": "Whether or not the node sending the heartbeat considers itself the leader for the term",
+
+	// CoordHeartbeatResult help.
+	"coordheartbeatresult-nodeid": "The identifier of the local node",
+	"coordheartbeatresult-term":   "The election term of the local node after processing the heartbeat",
+	"coordheartbeatresult-leader": "Whether or not the local node considers itself the leader for the term after processing the heartbeat",
+
 	// TransactionInput help.
 	"transactioninput-amount": "The previous output amount in coins",
 	"transactioninput-txid":   "The hash of the input transaction",
@@ -75,6 +131,15 @@ var helpDescsEnUS = map[string]string{
 	"createrawssrtx-inputs":   "The input to the transaction",
 	"createrawssrtx-fee":      "The fee to apply to the revocation in coins",
 
+	// CreateRevocationCmd help.
+	"createrevocation--synopsis": "Returns a new unsigned revocation of a ticket that missed its vote or expired.\n" +
+		"The revocation pays the original contributions back to the addresses committed to by the ticket.\n" +
+		"The fee is deducted from the payments in order according to the revocation fee limits committed to by the ticket.\n" +
+		"The signrawtransaction RPC command provided by wallet must be used to sign the resulting transaction.",
+	"createrevocation--result0":   "Hex-encoded bytes of the serialized unsigned revocation",
+	"createrevocation-tickethash": "The hash of the missed or expired ticket to revoke",
+	"createrevocation-fee":        "The fee to pay in DCR (revocations are relayed without fees by default)",
+
 	// CreateRawTransactionCmd help.
 	"createrawtransaction--synopsis": "Returns a new transaction spending the provided inputs and sending to the provided addresses.\n" +
 		"The transaction inputs are not signed in the created transaction.\n" +
@@ -159,6 +224,33 @@ var helpDescsEnUS = map[string]string{
 	"decodescript-hexscript": "Hex-encoded script",
 	"decodescript-version":   "The script version, defaults to version 0 if not set.",
 
+	// DisconnectRPCClientCmd help.
+	"disconnectrpcclient--synopsis": "Disconnect the websocket client with the provided session ID.",
+	"disconnectrpcclient-sessionid": "The session ID of the websocket client to disconnect as returned by listrpcclients",
+
+	// DumpPeerTelemetryCmd help.
+	"dumppeertelemetry--synopsis":     "Writes the anonymized peer telemetry events recorded since the previous dump to a new file in the peertelemetry directory of the data directory and resets the recorded events.\nThe file contains one JSON object per line, the first of which describes the events that follow it.\nThe server must be started with --peertelemetry.",
+	"dumppeertelemetryresult-file":    "The path of the written file",
+	"dumppeertelemetryresult-events":  "The number of events written to the file",
+	"dumppeertelemetryresult-dropped": "The number of older events discarded since the previous dump due to the limit on the number of retained events",
+
+	// EvaluateTxLocksCmd help.
+	"evaluatetxlocks--synopsis": "Evaluates the lock time and the relative lock times of the input sequence numbers of the provided transaction against the current best chain.\n" +
+		"Returns the earliest block height and past median time at which the transaction becomes final.\n" +
+		"Inputs that spend outputs of transactions in the memory pool are evaluated as if those transactions were included in the next block.",
+	"evaluatetxlocks-hextx": "Serialized, hex-encoded transaction",
+
+	// EvaluateTxLocksResult help.
+	"evaluatetxlocksresult-final":                  "Whether or not the transaction satisfies all of its lock time constraints as of the next block",
+	"evaluatetxlocksresult-nextheight":             "The height of the next block",
+	"evaluatetxlocksresult-mediantime":             "The past median time of the current best chain",
+	"evaluatetxlocksresult-locktimeheight":         "The minimum height of a block the transaction may be included in due to its lock time (omitted when not constrained)",
+	"evaluatetxlocksresult-locktimemediantime":     "The minimum past median time of the chain a block including the transaction extends due to its lock time (omitted when not constrained)",
+	"evaluatetxlocksresult-sequencelockheight":     "The minimum height of a block the transaction may be included in due to the relative lock times of its inputs (omitted when not constrained)",
+	"evaluatetxlocksresult-sequencelockmediantime": "The minimum past median time of the chain a block including the transaction extends due to the relative lock times of its inputs (omitted when not constrained)",
+	"evaluatetxlocksresult-earliestheight":         "The earliest height of a block the transaction may be included in",
+	"evaluatetxlocksresult-earliestmediantime":     "The earliest past median time of the chain a block including the transaction extends or 0 when not constrained",
+
 	// ExistsAddressCmd help.
 	"existsaddress--synopsis": "Test for the existence of the provided address",
 	"existsaddress-address":   "The address to check",
@@ -200,6 +292,13 @@ var helpDescsEnUS = map[string]string{
 	"generate-numblocks": "Number of blocks to generate",
 	"generate--result0":  "The hashes, in order, of blocks generated by the call",
 
+	// GenerateToAddressCmd help
+	"generatetoaddress--synopsis": "Generates a set number of blocks that pay the coinbase to the provided address (simnet or regnet only) and returns a JSON\n" +
+		" array of their hashes.",
+	"generatetoaddress-numblocks": "Number of blocks to generate",
+	"generatetoaddress-address":   "The address to pay the coinbase of the generated blocks to",
+	"generatetoaddress--result0":  "The hashes, in order, of blocks generated by the call",
+
 	// GetAddedNodeInfoResultAddr help.
 	"getaddednodeinforesultaddr-address":   "The ip address for this DNS entry",
 	"getaddednodeinforesultaddr-connected": "The connection 'direction' (inbound/outbound/false)",
@@ -217,6 +316,39 @@ var helpDescsEnUS = map[string]string{
 	"getaddednodeinfo--condition1": "dns=true",
 	"getaddednodeinfo--result0":    "List of added peers",
 
+	// GetAddressActivityCmd help.
+	"getaddressactivity--synopsis": "Returns the first and last heights at which the passed address was seen in the main chain, either as the recipient of an output or by spending one.\n" +
+		"Usage of this RPC requires the optional --addractivityindex flag to be activated.",
+	"getaddressactivity-address": "The Decred address to return the activity of",
+
+	// GetAddressActivityResult help.
+	"getaddressactivityresult-address":         "The address",
+	"getaddressactivityresult-seen":            "Whether or not the address has been seen in the main chain",
+	"getaddressactivityresult-firstseenheight": "The height of the first block that involves the address (only meaningful when seen)",
+	"getaddressactivityresult-firstseenhash":   "The hash of the first block that involves the address (only when seen)",
+	"getaddressactivityresult-lastseenheight":  "The height of the most recent block that involves the address (only meaningful when seen)",
+	"getaddressactivityresult-lastseenhash":    "The hash of the most recent block that involves the address (only when seen)",
+
+	// GetAddressUtxosCmd help.
+	"getaddressutxos--synopsis": "Returns the confirmed unspent transaction outputs that pay to the passed address a page at a time in the order they were confirmed.\n" +
+		"Usage of this RPC requires the optional --addrindex flag to be activated.",
+	"getaddressutxos-address": "The Decred address to return the unspent outputs of",
+	"getaddressutxos-cursor":  "The next cursor from the previous response to request the next page (omitted or empty for the first page)",
+	"getaddressutxos-limit":   "The maximum number of unspent outputs to return (at most 1000)",
+
+	// GetAddressUtxosResult help.
+	"getaddressutxosresult-utxos": "The unspent outputs that pay to the address",
+	"getaddressutxosresult-page":  "The pagination details",
+
+	// AddressUtxo help.
+	"addressutxo-txid":          "The hash of the transaction that contains the output",
+	"addressutxo-vout":          "The index of the output",
+	"addressutxo-tree":          "The tree of the transaction that contains the output",
+	"addressutxo-amount":        "The amount of the output in DCR",
+	"addressutxo-scriptpubkey":  "The hex-encoded public key script of the output",
+	"addressutxo-height":        "The height of the block that contains the output",
+	"addressutxo-confirmations": "The number of confirmations of the output",
+
 	// GetBestBlockResult help.
 	"getbestblockresult-hash":   "Hex-encoded bytes of the best block hash",
 	"getbestblockresult-height": "Height of the best block",
@@ -259,10 +391,23 @@ var helpDescsEnUS = map[string]string{
 	"getblockchaininforesult-deployments--value":   "The consensus deployment agenda information.",
 
 	// AgendaInfo help.
-	"agendainfo-status":     "The deployment agenda's current status.",
-	"agendainfo-since":      "The block height of the first block to which the status applies.",
-	"agendainfo-starttime":  "The start time of the voting period for the agenda.",
-	"agendainfo-expiretime": "The expiry time of the voting period for the agenda.",
+	"agendainfo-status":           "The deployment agenda's current status.",
+	"agendainfo-since":            "The block height of the first block to which the status applies.",
+	"agendainfo-starttime":        "The start time of the voting period for the agenda.",
+	"agendainfo-expiretime":       "The expiry time of the voting period for the agenda.",
+	"agendainfo-version":          "The stake version of the consensus deployment the agenda belongs to.",
+	"agendainfo-activationheight": "The block height at which the agenda's rules became or will become active (omitted unless locked in or active).",
+	"agendainfo-voteresult":       "The final vote tally of the agenda (omitted unless voting has concluded).",
+
+	// AgendaVoteResult help.
+	"agendavoteresult-decisionheight": "The block height of the final block of the rule change interval in which the outcome of the vote was decided.",
+	"agendavoteresult-choice":         "The id of the winning vote choice (omitted when the vote failed without a majority).",
+	"agendavoteresult-totalvotes":     "The total number of votes cast in the deciding rule change interval.",
+	"agendavoteresult-abstainvotes":   "The number of votes that abstained in the deciding rule change interval.",
+	"agendavoteresult-choices":        "The number of votes cast for each choice in the deciding rule change interval.",
+	"agendavoteresult-choices--desc":  "The vote counts keyed by choice id.",
+	"agendavoteresult-choices--key":   "The vote choice id.",
+	"agendavoteresult-choices--value": "The number of votes cast for the choice.",
 
 	// TxRawResult help.
 	"txrawresult-hex":           "Hex-encoded transaction",
@@ -304,6 +449,7 @@ var helpDescsEnUS = map[string]string{
 	"getblockverboseresult-tx":                "The transaction hashes (only when verbosetx=false)",
 	"getblockverboseresult-rawtx":             "The transactions as JSON objects (only when verbosetx=true)",
 	"getblockverboseresult-time":              "The block time in seconds since 1 Jan 1970 GMT",
+	"getblockverboseresult-receivedtime":      "The local time the block was received in seconds since 1 Jan 1970 GMT (omitted when not known)",
 	"getblockverboseresult-nonce":             "The block nonce",
 	"getblockverboseresult-bits":              "The bits which represent the block difficulty",
 	"getblockverboseresult-difficulty":        "The proof-of-work difficulty as a multiple of the minimum difficulty",
@@ -365,6 +511,24 @@ var helpDescsEnUS = map[string]string{
 	"getblockheaderverboseresult-extradata":         "Extra data field for the requested block",
 	"getblockheaderverboseresult-stakeversion":      "The stake version of the block",
 
+	// GetBlockStatsCmd help.
+	"getblockstats--synopsis": "Returns statistics about a block given its hash including the local time it was received.",
+	"getblockstats-hash":      "The hash of the block",
+
+	// GetBlockStatsResult help.
+	"getblockstatsresult-hash":         "The hash of the block",
+	"getblockstatsresult-height":       "The height of the block",
+	"getblockstatsresult-mainchain":    "Whether or not the block is in the main chain",
+	"getblockstatsresult-time":         "The block time in seconds since 1 Jan 1970 GMT",
+	"getblockstatsresult-receivedtime": "The local time the block was received in seconds since 1 Jan 1970 GMT (omitted when not known)",
+	"getblockstatsresult-receivedelay": "The number of seconds between the block time and the local time the block was received (omitted when not known)",
+	"getblockstatsresult-size":         "The size of the block in bytes",
+	"getblockstatsresult-numtx":        "The number of regular transactions in the block",
+	"getblockstatsresult-numstx":       "The number of stake transactions in the block",
+	"getblockstatsresult-voters":       "The number of votes in the block",
+	"getblockstatsresult-freshstake":   "The number of ticket purchases in the block",
+	"getblockstatsresult-revocations":  "The number of revocations in the block",
+
 	// GetBlockSubsidyCmd help.
 	"getblocksubsidy--synopsis": "Returns information regarding subsidy amounts.",
 	"getblocksubsidy-height":    "The block height",
@@ -376,6 +540,36 @@ var helpDescsEnUS = map[string]string{
 	"getblocksubsidyresult-pow":       "The Proof-of-Work subsidy",
 	"getblocksubsidyresult-total":     "The total subsidy",
 
+	// TemplateRequest help.
+	"templaterequest-mode":         "This is 'template', 'proposal', or omitted",
+	"templaterequest-capabilities": "List of capabilities supported by the caller, such as 'longpoll' and 'proposal'",
+	"templaterequest-longpollid":   "The long poll id of a previously returned template to wait for a new template",
+	"templaterequest-data":         "Serialized, hex-encoded block to validate (proposal mode only)",
+
+	// GetBlockTemplateCmd help.
+	"getblocktemplate--synopsis": "Returns a block template for external mining software to build a block from or validates a proposed block.\n" +
+		"In template mode, the call waits for a new template when the long poll id of the current template is provided.\n" +
+		"In proposal mode, the proposed block is fully validated as if it were connected to the current tip of the main chain or its parent without the proof-of-work requirement.",
+	"getblocktemplate-request":     "Request object which controls the mode and the related parameters",
+	"getblocktemplate--condition0": "mode=template",
+	"getblocktemplate--condition1": "mode=proposal, rejected",
+	"getblocktemplate--condition2": "mode=proposal, accepted",
+	"getblocktemplate--result1":    "The reason the proposed block was rejected",
+
+	// GetBlockTemplateResultTx help.
+	"getblocktemplateresulttx-data": "Serialized, hex-encoded transaction",
+	"getblocktemplateresulttx-hash": "Hex-encoded transaction hash",
+
+	// GetBlockTemplateResult help.
+	"getblocktemplateresult-header":        "Serialized, hex-encoded block header with the time updated to the current time",
+	"getblocktemplateresult-transactions":  "The regular transactions, including the coinbase, to include in the block",
+	"getblocktemplateresult-stransactions": "The stake transactions to include in the block",
+	"getblocktemplateresult-height":        "The height of the block the template builds",
+	"getblocktemplateresult-curtime":       "The time of the block header in seconds since 1 Jan 1970 GMT",
+	"getblocktemplateresult-target":        "Hex-encoded big-endian hash target",
+	"getblocktemplateresult-longpollid":    "The id to provide to wait for a template that differs from this one",
+	"getblocktemplateresult-capabilities":  "List of the optional capabilities supported by the server",
+
 	// GetCFilterCmd help.
 	"getcfilter--synopsis": "Returns the committed filter for a block.\n\n" +
 		"Deprecated: Use handleGetCFilterV2 instead.",
@@ -400,6 +594,102 @@ var helpDescsEnUS = map[string]string{
 	"getcfilterv2result-proofindex":  "The index of the leaf that represents the filter hash in the header commitment",
 	"getcfilterv2result-proofhashes": "The hashes needed to prove the filter is committed to by the header commitment",
 
+	// GetChainParamsCmd help.
+	"getchainparams--synopsis": "Returns the consensus parameters of the active network so client software can configure itself without embedding them.\n" +
+		"All durations are in seconds.",
+
+	// GetChainParamsResult help.
+	"getchainparamsresult-name":             "The name of the network",
+	"getchainparamsresult-net":              "The magic number that identifies the network",
+	"getchainparamsresult-defaultport":      "The default port for peer-to-peer connections",
+	"getchainparamsresult-genesishash":      "The hash of the genesis block",
+	"getchainparamsresult-maxblocksizes":    "The maximum serialized block sizes for each successive block version",
+	"getchainparamsresult-maxtxsize":        "The maximum serialized transaction size",
+	"getchainparamsresult-coinbasematurity": "The number of blocks required before newly mined coins can be spent",
+	"getchainparamsresult-pow":              "The proof-of-work and block time parameters",
+	"getchainparamsresult-subsidy":          "The block subsidy parameters",
+	"getchainparamsresult-stake":            "The stake parameters",
+	"getchainparamsresult-addressprefixes":  "The address and key encoding parameters",
+	"getchainparamsresult-rulechange":       "The parameters that govern voting on consensus rule changes",
+	"getchainparamsresult-deployments":      "The consensus deployment agendas ordered by stake version",
+
+	// ChainParamsPoW help.
+	"chainparamspow-powlimit":                 "The highest allowed proof-of-work target as a 256-bit hex-encoded number",
+	"chainparamspow-powlimitbits":             "The highest allowed proof-of-work target in compact form",
+	"chainparamspow-reducemindifficulty":      "Whether or not the required difficulty is reduced when no blocks are found for the minimum difficulty reduction time",
+	"chainparamspow-mindiffreductiontime":     "The amount of time after which the minimum difficulty applies when reducing the difficulty is enabled",
+	"chainparamspow-targettimeperblock":       "The desired amount of time to generate each block",
+	"chainparamspow-targettimespan":           "The desired amount of time to generate each difficulty window of blocks",
+	"chainparamspow-retargetadjustmentfactor": "The maximum factor the difficulty may change by in each retarget",
+	"chainparamspow-workdiffalpha":            "The proof-of-work difficulty exponential moving average smoothing factor",
+	"chainparamspow-workdiffwindowsize":       "The number of blocks in each proof-of-work difficulty window",
+	"chainparamspow-workdiffwindows":          "The number of windows used to calculate the proof-of-work difficulty",
+
+	// ChainParamsSubsidy help.
+	"chainparamssubsidy-basesubsidy":              "The starting subsidy amount for mined blocks in atoms",
+	"chainparamssubsidy-mulsubsidy":               "The multiplier applied to the subsidy at each reduction interval",
+	"chainparamssubsidy-divsubsidy":               "The divisor applied to the subsidy at each reduction interval",
+	"chainparamssubsidy-subsidyreductioninterval": "The number of blocks between each subsidy reduction",
+	"chainparamssubsidy-workrewardproportion":     "The proportion of the subsidy paid to proof-of-work miners out of 10",
+	"chainparamssubsidy-stakerewardproportion":    "The proportion of the subsidy paid to voters out of 10",
+	"chainparamssubsidy-blocktaxproportion":       "The proportion of the subsidy paid to the treasury out of 10",
+
+	// ChainParamsStake help.
+	"chainparamsstake-minimumstakediff":        "The minimum amount of atoms required to purchase a ticket",
+	"chainparamsstake-ticketpoolsize":          "The target size of the ticket pool in multiples of the number of tickets per block",
+	"chainparamsstake-ticketsperblock":         "The number of tickets selected to vote on each block",
+	"chainparamsstake-ticketmaturity":          "The number of blocks required before a ticket is eligible to vote",
+	"chainparamsstake-ticketexpiry":            "The number of blocks after maturity after which an unselected ticket expires",
+	"chainparamsstake-sstxchangematurity":      "The number of blocks required before the change of a ticket purchase can be spent",
+	"chainparamsstake-ticketpoolsizeweight":    "The weight of the ticket pool size in the stake difficulty calculation",
+	"chainparamsstake-stakediffalpha":          "The stake difficulty exponential moving average smoothing factor",
+	"chainparamsstake-stakediffwindowsize":     "The number of blocks in each stake difficulty window",
+	"chainparamsstake-stakediffwindows":        "The number of windows used to calculate the stake difficulty",
+	"chainparamsstake-stakeversioninterval":    "The number of blocks in each stake version interval",
+	"chainparamsstake-maxfreshstakeperblock":   "The maximum number of new tickets allowed in each block",
+	"chainparamsstake-stakeenabledheight":      "The height at which ticket purchases are enabled",
+	"chainparamsstake-stakevalidationheight":   "The height at which votes are required to approve blocks",
+	"chainparamsstake-stakemajoritymultiplier": "The multiplier used to calculate the majority of a stake version",
+	"chainparamsstake-stakemajoritydivisor":    "The divisor used to calculate the majority of a stake version",
+
+	// ChainParamsAddressPrefixes help.
+	"chainparamsaddressprefixes-networkaddressprefix": "The first character of encoded addresses that identifies the network",
+	"chainparamsaddressprefixes-pubkeyaddrid":         "The hex-encoded identifier of pay-to-pubkey addresses",
+	"chainparamsaddressprefixes-pubkeyhashaddrid":     "The hex-encoded identifier of pay-to-pubkey-hash addresses",
+	"chainparamsaddressprefixes-pkhedwardsaddrid":     "The hex-encoded identifier of Ed25519 pay-to-pubkey-hash addresses",
+	"chainparamsaddressprefixes-pkhschnorraddrid":     "The hex-encoded identifier of secp256k1 Schnorr pay-to-pubkey-hash addresses",
+	"chainparamsaddressprefixes-scripthashaddrid":     "The hex-encoded identifier of pay-to-script-hash addresses",
+	"chainparamsaddressprefixes-privatekeyid":         "The hex-encoded identifier of WIF private keys",
+	"chainparamsaddressprefixes-hdprivatekeyid":       "The hex-encoded identifier of extended private keys",
+	"chainparamsaddressprefixes-hdpublickeyid":        "The hex-encoded identifier of extended public keys",
+	"chainparamsaddressprefixes-slip0044cointype":     "The SLIP0044 coin type used in hierarchical deterministic key derivation",
+	"chainparamsaddressprefixes-legacycointype":       "The legacy coin type used in hierarchical deterministic key derivation",
+
+	// ChainParamsRuleChange help.
+	"chainparamsrulechange-quorum":     "The number of votes required for a rule change vote to be valid",
+	"chainparamsrulechange-multiplier": "The multiplier used to calculate the majority required to change the rules",
+	"chainparamsrulechange-divisor":    "The divisor used to calculate the majority required to change the rules",
+	"chainparamsrulechange-interval":   "The number of blocks in each rule change interval",
+
+	// ChainParamsDeployment help.
+	"chainparamsdeployment-version": "The stake version of the agendas",
+	"chainparamsdeployment-agendas": "The consensus deployment agendas of the stake version",
+
+	// ChainParamsAgenda help.
+	"chainparamsagenda-id":          "Unique identifier of the agenda",
+	"chainparamsagenda-description": "Description of the agenda",
+	"chainparamsagenda-mask":        "The bits of the vote bits used by the agenda",
+	"chainparamsagenda-starttime":   "The median block time after which voting on the agenda starts",
+	"chainparamsagenda-expiretime":  "The median block time after which the agenda expires",
+	"chainparamsagenda-choices":     "The choices of the agenda",
+
+	// ChainParamsChoice help.
+	"chainparamschoice-id":          "Unique identifier of the choice",
+	"chainparamschoice-description": "Description of the choice",
+	"chainparamschoice-bits":        "The vote bits that select the choice",
+	"chainparamschoice-isabstain":   "Whether or not the choice is to abstain",
+	"chainparamschoice-isno":        "Whether or not the choice is to reject the agenda",
+
 	// GetChainTips help.
 	"getchaintips--synopsis": "Returns information about all known chain tips the in the block tree.\n\n" +
 		"The statuses in the result have the following meanings:\n" +
@@ -428,6 +718,33 @@ var helpDescsEnUS = map[string]string{
 	"getdifficulty--synopsis": "Returns the proof-of-work difficulty as a multiple of the minimum difficulty.",
 	"getdifficulty--result0":  "The difficulty",
 
+	// GetDiskSpaceInfoCmd help.
+	"getdiskspaceinfo--synopsis":           "Returns the free disk space available to the data directory along with the configured thresholds.",
+	"getdiskspaceinforesult-path":          "The data directory",
+	"getdiskspaceinforesult-free":          "The free disk space in bytes available to the data directory",
+	"getdiskspaceinforesult-total":         "The total size in bytes of the volume that houses the data directory",
+	"getdiskspaceinforesult-warnthreshold": "The free disk space in bytes below which warnings are issued (0 when disabled)",
+	"getdiskspaceinforesult-stopthreshold": "The free disk space in bytes below which the server shuts down gracefully (0 when disabled)",
+	"getdiskspaceinforesult-level":         "The level of the free disk space relative to the thresholds (ok, low, or critical)",
+
+	// GetSignalingStatsCmd help.
+	"getsignalingstats--synopsis":              "Returns statistics about the block version and vote bits signaled by the blocks in a window so upgrade adoption by miners and voters can be observed.",
+	"getsignalingstats-blocks":                 "The number of blocks in the window (default: the rule change activation interval of the active network).",
+	"getsignalingstats-hash":                   "The hash of the newest block in the window (default: the current best block).",
+	"getsignalingstats-voteversion":            "Only tally the bits of the votes with this version since the meaning of vote bits depends on the vote version (default: all votes).",
+	"getsignalingstatsresult-hash":             "The hash of the newest block in the window",
+	"getsignalingstatsresult-startheight":      "The height of the oldest block in the window",
+	"getsignalingstatsresult-endheight":        "The height of the newest block in the window",
+	"getsignalingstatsresult-numblocks":        "The number of blocks in the window",
+	"getsignalingstatsresult-numvotes":         "The number of votes the vote bits are tallied over",
+	"getsignalingstatsresult-blockversions":    "Tally of the block versions",
+	"getsignalingstatsresult-blockversionbits": "The number and percentage of blocks that set each block version bit",
+	"getsignalingstatsresult-voteversions":     "Tally of the versions of all votes",
+	"getsignalingstatsresult-votebits":         "The number and percentage of tallied votes that set each vote bit",
+	"signalingbit-bit":                         "The bit position",
+	"signalingbit-count":                       "The number of blocks or votes that set the bit",
+	"signalingbit-percent":                     "The percentage of blocks or votes that set the bit",
+
 	// GetStakeDifficultyCmd help.
 	"getstakedifficulty--synopsis":     "Returns the proof-of-stake difficulty.",
 	"getstakedifficultyresult-current": "The current top block's stake difficulty",
@@ -450,7 +767,10 @@ var helpDescsEnUS = map[string]string{
 	"getstakeversions--synopsis":           "Returns the stake versions statistics.",
 	"getstakeversions-hash":                "The start block hash.",
 	"getstakeversions-count":               "The number of blocks that will be returned.",
-	"getstakeversionsresult-stakeversions": "Array of stake versions per block.",
+	"getstakeversions-intervalsize":        "Aggregate the versions of the blocks into intervals of this many blocks instead of returning them per block (0 to disable).",
+	"getstakeversionsresult-stakeversions": "Array of stake versions per block (empty when aggregating into intervals).",
+	"getstakeversionsresult-intervals":     "Tally of the versions of each interval of blocks ordered from newest to oldest (only when aggregating into intervals).",
+	"getstakeversionsresult-nexthash":      "Hash of the block that precedes the oldest returned block to continue from, if any.",
 	"stakeversions-hash":                   "Hash of the block.",
 	"stakeversions-height":                 "Height of the block.",
 	"stakeversions-blockversion":           "The block version",
@@ -460,31 +780,41 @@ var helpDescsEnUS = map[string]string{
 	"versionbits-bits":                     "The bits assigned by the vote.",
 
 	// GetVoteInfo
-	"getvoteinfo--synopsis":           "Returns the vote info statistics.",
-	"getvoteinfo-version":             "The stake version.",
-	"getvoteinforesult-currentheight": "Top of the chain height.",
-	"getvoteinforesult-startheight":   "The start height of this voting window.",
-	"getvoteinforesult-endheight":     "The end height of this voting window.",
-	"getvoteinforesult-hash":          "The hash of the current height block.",
-	"getvoteinforesult-voteversion":   "Selected vote version.",
-	"getvoteinforesult-quorum":        "Minimum amount of votes required.",
-	"getvoteinforesult-totalvotes":    "Total votes.",
-	"getvoteinforesult-agendas":       "All agendas for this stake version.",
-	"agenda-id":                       "Unique identifier of this agenda.",
-	"agenda-description":              "Description of this agenda.",
-	"agenda-mask":                     "Agenda mask.",
-	"agenda-starttime":                "Time agenda becomes valid.",
-	"agenda-expiretime":               "Time agenda becomes invalid.",
-	"agenda-status":                   "Agenda status.",
-	"agenda-quorumprogress":           "Progress of quorum reached.",
-	"agenda-choices":                  "All choices in this agenda.",
-	"choice-id":                       "Unique identifier of this choice.",
-	"choice-description":              "Description of this choice.",
-	"choice-bits":                     "Bits that identify this choice.",
-	"choice-isabstain":                "This choice is to abstain from change.",
-	"choice-isno":                     "Hard no choice (1 and only 1 per agenda).",
-	"choice-count":                    "How many votes received.",
-	"choice-progress":                 "Progress of the overall count.",
+	"getvoteinfo--synopsis":            "Returns the vote info statistics.",
+	"getvoteinfo-version":              "The stake version.",
+	"getvoteinfo-count":                "The number of completed prior rule change intervals to also return the vote counts for.",
+	"getvoteinforesult-currentheight":  "Top of the chain height.",
+	"getvoteinforesult-startheight":    "The start height of this voting window.",
+	"getvoteinforesult-endheight":      "The end height of this voting window.",
+	"getvoteinforesult-hash":           "The hash of the current height block.",
+	"getvoteinforesult-voteversion":    "Selected vote version.",
+	"getvoteinforesult-quorum":         "Minimum amount of votes required.",
+	"getvoteinforesult-totalvotes":     "Total votes.",
+	"getvoteinforesult-agendas":        "All agendas for this stake version.",
+	"getvoteinforesult-priorintervals": "The vote counts for the requested prior rule change intervals ordered from newest to oldest.",
+	"voteinterval-startheight":         "The start height of the rule change interval.",
+	"voteinterval-endheight":           "The end height of the rule change interval.",
+	"voteinterval-hash":                "The hash of the final block of the rule change interval.",
+	"voteinterval-agendas":             "The vote counts of all agendas for this stake version.",
+	"agendavotecounts-id":              "Unique identifier of the agenda.",
+	"agendavotecounts-total":           "Total number of votes for this stake version.",
+	"agendavotecounts-totalabstain":    "Total number of votes that abstained.",
+	"agendavotecounts-choicecounts":    "Number of votes for each choice in the same order as the choices of the agenda.",
+	"agenda-id":                        "Unique identifier of this agenda.",
+	"agenda-description":               "Description of this agenda.",
+	"agenda-mask":                      "Agenda mask.",
+	"agenda-starttime":                 "Time agenda becomes valid.",
+	"agenda-expiretime":                "Time agenda becomes invalid.",
+	"agenda-status":                    "Agenda status.",
+	"agenda-quorumprogress":            "Progress of quorum reached.",
+	"agenda-choices":                   "All choices in this agenda.",
+	"choice-id":                        "Unique identifier of this choice.",
+	"choice-description":               "Description of this choice.",
+	"choice-bits":                      "Bits that identify this choice.",
+	"choice-isabstain":                 "This choice is to abstain from change.",
+	"choice-isno":                      "Hard no choice (1 and only 1 per agenda).",
+	"choice-count":                     "How many votes received.",
+	"choice-progress":                  "Progress of the overall count.",
 
 	// GetGenerateCmd help.
 	"getgenerate--synopsis": "Returns if the server is set to generate coins (mine) or not.",
@@ -539,8 +869,37 @@ var helpDescsEnUS = map[string]string{
 	"getmempoolinfo--synopsis": "Returns memory pool information",
 
 	// GetMempoolInfoResult help.
-	"getmempoolinforesult-bytes": "Size in bytes of the mempool",
-	"getmempoolinforesult-size":  "Number of transactions in the mempool",
+	"getmempoolinforesult-bytes":    "Size in bytes of the mempool",
+	"getmempoolinforesult-size":     "Number of transactions in the mempool",
+	"getmempoolinforesult-evicted":  "Number of transactions evicted from the mempool without being mined, due to expiration, exceeding the maximum age, replacement, or stale stake transactions, since the server started",
+	"getmempoolinforesult-lasthour": "Mempool high-water marks and pressure over the last hour",
+	"getmempoolinforesult-lastday":  "Mempool high-water marks and pressure over the last day",
+
+	// GetMempoolInfoWatermark help.
+	"getmempoolinfowatermark-maxsize":    "Maximum number of transactions in the mempool",
+	"getmempoolinfowatermark-maxbytes":   "Maximum size in bytes of the mempool",
+	"getmempoolinfowatermark-evicted":    "Number of transactions evicted from the mempool without being mined",
+	"getmempoolinfowatermark-minfeerate": "Minimum fee rate in DCR/kB of the regular transactions accepted to the mempool (omitted when none were accepted)",
+
+	// GetMempoolReplacementsCmd help.
+	"getmempoolreplacements--synopsis": "Returns the transaction replacement policy of the memory pool along with the most recent decisions about transactions that were evaluated as replacements for conflicting transactions in the memory pool, ordered from oldest to newest",
+
+	// GetMempoolReplacementsResult help.
+	"getmempoolreplacementsresult-enabled":            "Whether or not transactions in the mempool may be replaced",
+	"getmempoolreplacementsresult-minfeerateincrease": "Minimum percentage by which the fee rate of a replacement must exceed the fee rate of each transaction it directly conflicts with",
+	"getmempoolreplacementsresult-replacements":       "The most recent replacement decisions",
+
+	// MempoolReplacementResult help.
+	"mempoolreplacementresult-time":       "Local time the decision was made in seconds since 1 Jan 1970 GMT",
+	"mempoolreplacementresult-txid":       "The hash of the replacement transaction",
+	"mempoolreplacementresult-conflicts":  "The hashes of the transactions in the mempool the replacement directly conflicts with",
+	"mempoolreplacementresult-evicted":    "Number of transactions that were, or would have been, evicted by the replacement including the descendants of the conflicts",
+	"mempoolreplacementresult-fee":        "Fee in DCR paid by the replacement",
+	"mempoolreplacementresult-feerate":    "Fee rate in DCR/kB paid by the replacement",
+	"mempoolreplacementresult-minfee":     "Minimum fee in DCR the replacement was required to pay to cover the fees of the evicted transactions and its relay fee",
+	"mempoolreplacementresult-minfeerate": "Minimum fee rate in DCR/kB the replacement was required to pay",
+	"mempoolreplacementresult-accepted":   "Whether or not the replacement was accepted",
+	"mempoolreplacementresult-reason":     "The reason the replacement was rejected (omitted when accepted)",
 
 	// GetMiningInfoResult help.
 	"getmininginforesult-blocks":           "Height of the latest best block",
@@ -556,14 +915,34 @@ var helpDescsEnUS = map[string]string{
 	"getmininginforesult-pooledtx":         "Number of transactions in the memory pool",
 	"getmininginforesult-testnet":          "Whether or not server is using testnet",
 
+	// GetMiningAddrsCmd help.
+	"getminingaddrs--synopsis": "Returns the mining addresses generated block templates pay to along with the policy used to choose between them.",
+
+	// GetMiningAddrsResult help.
+	"getminingaddrsresult-addresses": "The mining addresses",
+	"getminingaddrsresult-policy":    "The policy used to choose which address each block template pays to (random or roundrobin)",
+
 	// GetMiningInfoCmd help.
 	"getmininginfo--synopsis": "Returns a JSON object containing mining-related information.",
 
 	// GetNetworkHashPSCmd help.
-	"getnetworkhashps--synopsis": "Returns the estimated network hashes per second for the block heights provided by the parameters.",
-	"getnetworkhashps-blocks":    "The number of blocks, or -1 for blocks since last difficulty change",
-	"getnetworkhashps-height":    "Perform estimate ending with this height or -1 for current best chain block height",
-	"getnetworkhashps--result0":  "Estimated hashes per second",
+	"getnetworkhashps--synopsis":   "Returns the estimated network hashes per second for the block heights provided by the parameters.",
+	"getnetworkhashps-blocks":      "The number of blocks, or -1 for blocks since last difficulty change",
+	"getnetworkhashps-height":      "Perform estimate ending with this height or -1 for current best chain block height",
+	"getnetworkhashps-method":      "The estimation method: \"window\" divides the total work by the time it took to produce the blocks and \"ema\" divides exponential moving averages of the work and solve times so recent blocks carry more weight",
+	"getnetworkhashps-verbose":     "Return an object with the confidence interval of the estimate instead of only the estimate",
+	"getnetworkhashps--condition0": "verbose=false",
+	"getnetworkhashps--condition1": "verbose=true",
+	"getnetworkhashps--result0":    "Estimated hashes per second",
+
+	// GetNetworkHashPSResult help.
+	"getnetworkhashpsresult-hashespersec": "Estimated hashes per second",
+	"getnetworkhashpsresult-lower":        "The lower bound of the confidence interval of the estimate",
+	"getnetworkhashpsresult-upper":        "The upper bound of the confidence interval of the estimate",
+	"getnetworkhashpsresult-confidence":   "The confidence level of the interval",
+	"getnetworkhashpsresult-method":       "The estimation method",
+	"getnetworkhashpsresult-startheight":  "The height of the first block the estimate is based on",
+	"getnetworkhashpsresult-endheight":    "The height of the last block the estimate is based on",
 
 	// GetNetworkInfoCmd help.
 	"getnetworkinfo--synopsis": "Returns a JSON object containing network-related information.",
@@ -598,28 +977,65 @@ var helpDescsEnUS = map[string]string{
 	"getnettotalsresult-totalbytesrecv": "Total bytes received",
 	"getnettotalsresult-totalbytessent": "Total bytes sent",
 	"getnettotalsresult-timemillis":     "Number of milliseconds since 1 Jan 1970 GMT",
+	"getnettotalsresult-txrecon":        "Transaction inventory reconciliation statistics (only when reconciliation is enabled)",
+	"getnettotalsresult-txrelay":        "Latency statistics for the transaction relay lanes",
+
+	// TxRelayStatsResult help.
+	"txrelaystatsresult-priority": "Statistics for the priority lane used by votes and revocations",
+	"txrelaystatsresult-regular":  "Statistics for the regular lane used by all other transactions",
+
+	// TxRelayLaneResult help.
+	"txrelaylaneresult-count":            "Number of transactions accepted to the mempool via the lane",
+	"txrelaylaneresult-avglatencymillis": "Average milliseconds between receiving a transaction and announcing it for relay",
+	"txrelaylaneresult-maxlatencymillis": "Maximum milliseconds between receiving a transaction and announcing it for relay",
+
+	// TxReconStatsResult help.
+	"txreconstatsresult-rounds":     "Number of reconciliation rounds initiated with outbound peers",
+	"txreconstatsresult-failures":   "Number of initiated reconciliation rounds that fell back to flooding",
+	"txreconstatsresult-reconbytes": "Bytes sent for reconciliation including the resulting announcements",
+	"txreconstatsresult-floodbytes": "Bytes that announcing the same transactions via flooding would have sent",
 
 	// GetPeerInfoResult help.
-	"getpeerinforesult-id":             "A unique node ID",
-	"getpeerinforesult-addr":           "The ip address and port of the peer",
-	"getpeerinforesult-addrlocal":      "Local address",
-	"getpeerinforesult-services":       "Services bitmask which represents the services supported by the peer",
-	"getpeerinforesult-relaytxes":      "Peer has requested transactions be relayed to it",
-	"getpeerinforesult-lastsend":       "Time the last message was received in seconds since 1 Jan 1970 GMT",
-	"getpeerinforesult-lastrecv":       "Time the last message was sent in seconds since 1 Jan 1970 GMT",
-	"getpeerinforesult-bytessent":      "Total bytes sent",
-	"getpeerinforesult-bytesrecv":      "Total bytes received",
-	"getpeerinforesult-conntime":       "Time the connection was made in seconds since 1 Jan 1970 GMT",
-	"getpeerinforesult-timeoffset":     "The time offset of the peer",
-	"getpeerinforesult-pingtime":       "Number of microseconds the last ping took",
-	"getpeerinforesult-pingwait":       "Number of microseconds a queued ping has been waiting for a response",
-	"getpeerinforesult-version":        "The protocol version of the peer",
-	"getpeerinforesult-subver":         "The user agent of the peer",
-	"getpeerinforesult-inbound":        "Whether or not the peer is an inbound connection",
-	"getpeerinforesult-startingheight": "The latest block height the peer knew about when the connection was established",
-	"getpeerinforesult-currentheight":  "The current height of the peer",
-	"getpeerinforesult-banscore":       "The ban score",
-	"getpeerinforesult-syncnode":       "Whether or not the peer is the sync peer",
+	"getpeerinforesult-id":              "A unique node ID",
+	"getpeerinforesult-addr":            "The ip address and port of the peer",
+	"getpeerinforesult-addrlocal":       "Local address",
+	"getpeerinforesult-services":        "Services bitmask which represents the services supported by the peer",
+	"getpeerinforesult-relaytxes":       "Peer has requested transactions be relayed to it",
+	"getpeerinforesult-lastsend":        "Time the last message was received in seconds since 1 Jan 1970 GMT",
+	"getpeerinforesult-lastrecv":        "Time the last message was sent in seconds since 1 Jan 1970 GMT",
+	"getpeerinforesult-bytessent":       "Total bytes sent",
+	"getpeerinforesult-bytesrecv":       "Total bytes received",
+	"getpeerinforesult-conntime":        "Time the connection was made in seconds since 1 Jan 1970 GMT",
+	"getpeerinforesult-timeoffset":      "The time offset of the peer",
+	"getpeerinforesult-pingtime":        "Number of microseconds the last ping took",
+	"getpeerinforesult-pingwait":        "Number of microseconds a queued ping has been waiting for a response",
+	"getpeerinforesult-version":         "The protocol version of the peer",
+	"getpeerinforesult-subver":          "The user agent of the peer",
+	"getpeerinforesult-inbound":         "Whether or not the peer is an inbound connection",
+	"getpeerinforesult-startingheight":  "The latest block height the peer knew about when the connection was established",
+	"getpeerinforesult-currentheight":   "The current height of the peer",
+	"getpeerinforesult-banscore":        "The ban score",
+	"getpeerinforesult-syncnode":        "Whether or not the peer is the sync peer",
+	"getpeerinforesult-identitykey":     "The hex-encoded identity public key the peer authenticated with (only when authenticated)",
+	"getpeerinforesult-msgstats":        "Statistics about the messages received from the peer by message type (omitted when no messages have been received)",
+	"getpeerinforesult-msgstats--desc":  "Statistics about received messages keyed by message type",
+	"getpeerinforesult-msgstats--key":   "The message type",
+	"getpeerinforesult-msgstats--value": "The statistics for the message type",
+	"getpeerinforesult-tcpinfo":         "Transport statistics most recently sampled from the TCP socket of the connection (omitted when not supported by the platform or not yet sampled)",
+
+	// PeerMsgStats help.
+	"peermsgstats-received":    "Number of messages successfully received",
+	"peermsgstats-bytesrecv":   "Total bytes of the successfully received messages",
+	"peermsgstats-oversized":   "Number of messages rejected for exceeding the configured maximum payload size",
+	"peermsgstats-ratelimited": "Number of messages rejected for exceeding the configured maximum rate",
+
+	// PeerTCPInfo help.
+	"peertcpinfo-rtt":          "Smoothed round trip time of the connection in microseconds",
+	"peertcpinfo-rttvar":       "Round trip time variance of the connection in microseconds",
+	"peertcpinfo-retransmits":  "Number of retransmissions of the currently unacknowledged data",
+	"peertcpinfo-totalretrans": "Total number of retransmitted segments over the lifetime of the connection",
+	"peertcpinfo-sndcwnd":      "Current size of the send congestion window in segments",
+	"peertcpinfo-sampletime":   "Time the statistics were sampled in seconds since 1 Jan 1970 GMT",
 
 	// GetPeerInfoCmd help.
 	"getpeerinfo--synopsis": "Returns data about each connected network peer as an array of json objects.",
@@ -649,10 +1065,99 @@ var helpDescsEnUS = map[string]string{
 	"getrawtransaction--condition1": "verbose=true",
 	"getrawtransaction--result0":    "Hex-encoded bytes of the serialized transaction",
 
+	// GetRejectedTransactionsCmd help.
+	"getrejectedtransactions--synopsis": "Returns the transactions most recently rejected from peers along with the reasons they were rejected ordered from newest to oldest.",
+	"getrejectedtransactions-count":     "The maximum number of transactions to return (default: all)",
+
+	// GetRejectedTransactionsResult help.
+	"getrejectedtransactionsresult-txid":   "The hash of the rejected transaction",
+	"getrejectedtransactionsresult-code":   "The reject code sent to the peer that relayed the transaction",
+	"getrejectedtransactionsresult-reason": "The reason the transaction was rejected",
+	"getrejectedtransactionsresult-time":   "The time the transaction was rejected in seconds since 1 Jan 1970 GMT",
+	"getrejectedtransactionsresult-height": "The height of the best chain when the transaction was rejected",
+
+	// GetStandardPolicyCmd help.
+	"getstandardpolicy--synopsis": "Returns the policy used to determine whether or not transactions are accepted to the memory pool as standard transactions along with the standardness policies for all known script versions.\n" +
+		"The policies for script versions are applied in order, so an active policy replaces any earlier policy for the same script version.",
+
+	// GetStandardPolicyResult help.
+	"getstandardpolicyresult-acceptnonstd":       "Whether or not non-standard transactions are accepted",
+	"getstandardpolicyresult-maxtxversion":       "The maximum standard transaction version",
+	"getstandardpolicyresult-maxtxsize":          "The maximum serialized size in bytes of a standard transaction",
+	"getstandardpolicyresult-maxsigscriptsize":   "The maximum size in bytes of a standard signature script",
+	"getstandardpolicyresult-maxnulldataoutputs": "The maximum number of null data outputs in a standard regular transaction",
+	"getstandardpolicyresult-scriptversions":     "The standardness policies for all known script versions in the order they are applied",
+
+	// StandardScriptVersionResult help.
+	"standardscriptversionresult-version":         "The script version the policy applies to",
+	"standardscriptversionresult-agenda":          "The id of the agenda that must be active for the policy to apply (omitted when the policy always applies)",
+	"standardscriptversionresult-active":          "Whether or not the policy currently applies",
+	"standardscriptversionresult-classes":         "The script classes that are standard",
+	"standardscriptversionresult-maxmultisigkeys": "The maximum number of public keys in a standard multi-signature script",
+	"standardscriptversionresult-maxp2shsigops":   "The maximum number of signature operations in a standard pay-to-script-hash redeem script",
+
+	// GetSyncPeerCmd help.
+	"getsyncpeer--synopsis": "Returns the peer currently being synced with along with the most recent changes to it.\n" +
+		"The sync peer is changed when it disconnects, misses the deadline to deliver the next requested block, or delivers blocks under the minimum rate for a sustained period while the chain is not current.",
+
+	// GetSyncPeerResult help.
+	"getsyncpeerresult-id":             "The id of the sync peer (0 when there is no sync peer)",
+	"getsyncpeerresult-addr":           "The address of the sync peer (empty when there is no sync peer)",
+	"getsyncpeerresult-syncheight":     "The latest known block being synced to",
+	"getsyncpeerresult-blocksinflight": "The number of blocks requested from the sync peer that it has not delivered yet",
+	"getsyncpeerresult-deadline":       "The time by which the sync peer must deliver the next requested block before it is considered stalled in seconds since 1 Jan 1970 GMT (0 when there are no blocks in flight)",
+	"getsyncpeerresult-switches":       "The most recent changes to the sync peer ordered from oldest to newest",
+
+	// SyncPeerSwitchResult help.
+	"syncpeerswitchresult-time":         "The time the sync peer was changed in seconds since 1 Jan 1970 GMT",
+	"syncpeerswitchresult-previousid":   "The id of the previous sync peer (0 when there was none)",
+	"syncpeerswitchresult-previousaddr": "The address of the previous sync peer (empty when there was none)",
+	"syncpeerswitchresult-id":           "The id of the new sync peer",
+	"syncpeerswitchresult-addr":         "The address of the new sync peer",
+	"syncpeerswitchresult-reason":       "The reason the sync peer was changed (initial, disconnected, stalled, or underperforming)",
+
+	// GetTemplateDecisionsCmd help.
+	"gettemplatedecisions--synopsis": "Returns which of the transactions considered for the most recently generated block templates were selected or skipped along with why, ordered from newest to oldest template.\n" +
+		"The decisions are retained for the last 100 templates.",
+	"gettemplatedecisions-count": "The number of templates to return (1-100)",
+
+	// GetTemplateDecisionsResult help.
+	"gettemplatedecisionsresult-hash":              "The hash of the template block at the time it was generated",
+	"gettemplatedecisionsresult-previousblockhash": "The hash of the block the template builds on",
+	"gettemplatedecisionsresult-height":            "The height of the block the template is for",
+	"gettemplatedecisionsresult-time":              "The time the template was generated in seconds since 1 Jan 1970 GMT",
+	"gettemplatedecisionsresult-considered":        "The number of transactions considered for inclusion",
+	"gettemplatedecisionsresult-selected":          "The number of transactions included in the template",
+	"gettemplatedecisionsresult-totalfees":         "The total fees paid by the included transactions in DCR",
+	"gettemplatedecisionsresult-transactions":      "The decision made for each considered transaction",
+
+	// TemplateTxDecisionResult help.
+	"templatetxdecisionresult-txid":     "The hash of the transaction",
+	"templatetxdecisionresult-type":     "The type of the transaction (regular, ticket, vote, or revocation)",
+	"templatetxdecisionresult-decision": "Whether the transaction was selected or skipped",
+	"templatetxdecisionresult-reason":   "Why the transaction was skipped (coinbase, nonfinal, wrongvoteblock, inputsunavailable, dependency, maxfreshstake, ticketprice, unknownrevocation, blocksize, sigops, ineligiblevote, lowfee, invalid, or removed; omitted when selected)",
+	"templatetxdecisionresult-size":     "The serialized size of the transaction in bytes",
+	"templatetxdecisionresult-fee":      "The fee paid by the transaction in DCR",
+	"templatetxdecisionresult-feerate":  "The fee rate of the transaction in DCR/kB",
+
 	// GetTicketPoolValue help.
 	"getticketpoolvalue--synopsis": "Return the current value of all locked funds in the ticket pool",
 	"getticketpoolvalue--result0":  "Total value of ticket pool",
 
+	// GetTimeInfoCmd help.
+	"gettimeinfo--synopsis": "Returns the median time of the current best chain tip or the specified block along with the local time, the time adjusted by the median offset of the clocks of connected peers, and that offset.\n" +
+		"The median time of a block is the median of the timestamps of the block and the blocks preceding it.\n" +
+		"Transaction lock times specified as a time are evaluated against the median time of the block prior to the one the transaction is included in.",
+	"gettimeinfo-blockhash": "The hash of the block to return the median time of instead of the current best chain tip",
+
+	// GetTimeInfoResult help.
+	"gettimeinforesult-hash":         "The hash of the block",
+	"gettimeinforesult-height":       "The height of the block",
+	"gettimeinforesult-mediantime":   "The median time of the block in seconds since 1 Jan 1970 GMT",
+	"gettimeinforesult-localtime":    "The current local time of the node in seconds since 1 Jan 1970 GMT",
+	"gettimeinforesult-adjustedtime": "The current local time adjusted by the median time offset of connected peers in seconds since 1 Jan 1970 GMT",
+	"gettimeinforesult-timeoffset":   "The median time offset of connected peers in seconds",
+
 	// GetTxOutResult help.
 	"gettxoutresult-bestblock":     "The block hash that contains the transaction output",
 	"gettxoutresult-confirmations": "The number of confirmations",
@@ -676,9 +1181,27 @@ var helpDescsEnUS = map[string]string{
 	"gettxoutsetinforesult-transactions":   "The number of unique transactions referenced by outputs.",
 	"gettxoutsetinforesult-txouts":         "The number of transaction outputs.",
 	"gettxoutsetinforesult-serializedhash": "The merklized hash of the utxo set.",
+	"gettxoutsetinforesult-utxosethash":    "The order-independent multiset hash of the utxo set which is incrementally maintained and verified against the set when calculated.",
 	"gettxoutsetinforesult-disksize":       "The size of the utxo set on disk, in bytes.",
 	"gettxoutsetinforesult-totalamount":    "The total value of the utxo set.",
 
+	// GetTxScriptCostCmd help.
+	"gettxscriptcost--synopsis": "Executes the scripts of all inputs of a transaction in the mempool or the blockchain with the flags used to determine whether transactions are standard and reports the cost of executing them.\n" +
+		"The transaction index must be enabled to query transactions in the blockchain (--txindex).",
+	"gettxscriptcost-txid": "The hash of the transaction",
+
+	// GetTxScriptCostResult help.
+	"gettxscriptcostresult-txid":   "The hash of the transaction",
+	"gettxscriptcostresult-inputs": "The cost of executing the scripts of each input that spends a previous output",
+
+	// ScriptCostInputResult help.
+	"scriptcostinputresult-index":    "The index of the input",
+	"scriptcostinputresult-opcodes":  "The number of opcodes executed",
+	"scriptcostinputresult-sigops":   "The number of signature operations",
+	"scriptcostinputresult-duration": "The time it took to execute the scripts in nanoseconds",
+	"scriptcostinputresult-valid":    "Whether or not the scripts executed successfully",
+	"scriptcostinputresult-error":    "The reason the scripts failed to execute successfully",
+
 	// GetWorkResult help.
 	"getworkresult-data":     "Hex-encoded block data",
 	"getworkresult-hash1":    "(DEPRECATED) Hex-encoded formatted hash buffer",
@@ -692,6 +1215,27 @@ var helpDescsEnUS = map[string]string{
 	"getwork--condition1": "data provided",
 	"getwork--result1":    "Whether or not the solved data is valid and was added to the chain",
 
+	// GetWorkStatsCmd help.
+	"getworkstats--synopsis": "Returns statistics about the work requests and solution submissions of the clients that use getwork ordered by client address.\n" +
+		"Submissions for work that is no longer available or that build on an unknown block are stale, while all other submissions that are not accepted are rejected.\n" +
+		"Statistics are retained for up to 100 clients and the least recently active client is discarded once that number is reached.",
+
+	// GetWorkStatsResult help.
+	"getworkstatsresult-clients": "The getwork statistics of each client",
+
+	// GetWorkClientResult help.
+	"getworkclientresult-addr":          "The address and port of the client",
+	"getworkclientresult-firstseen":     "The time the client was first seen in seconds since 1 Jan 1970 GMT",
+	"getworkclientresult-requests":      "The number of requests for work made by the client",
+	"getworkclientresult-requestrate":   "The average number of requests for work per minute made by the client over the last 10 minutes",
+	"getworkclientresult-lastrequest":   "The time of the most recent request for work in seconds since 1 Jan 1970 GMT (0 when none)",
+	"getworkclientresult-submitted":     "The number of solutions submitted by the client",
+	"getworkclientresult-accepted":      "The number of submitted solutions that were accepted",
+	"getworkclientresult-rejected":      "The number of submitted solutions that were rejected",
+	"getworkclientresult-stale":         "The number of submitted solutions that were stale",
+	"getworkclientresult-lastsubmitted": "The time of the most recent submitted solution in seconds since 1 Jan 1970 GMT (0 when none)",
+	"getworkclientresult-lastaccepted":  "The time of the most recent accepted solution in seconds since 1 Jan 1970 GMT (0 when none)",
+
 	// HelpCmd help.
 	"help--synopsis":   "Returns a list of all commands or help for a specified command.",
 	"help-command":     "The command to retrieve help for",
@@ -700,10 +1244,25 @@ var helpDescsEnUS = map[string]string{
 	"help--result0":    "List of commands",
 	"help--result1":    "Help for specified command",
 
+	// The label of the result schema version included in the help for each
+	// command.
+	"help-result-schema-version": "Result schema version",
+
 	// PingCmd help.
 	"ping--synopsis": "Queues a ping to be sent to each connected peer.\n" +
 		"Ping times are provided by getpeerinfo via the pingtime and pingwait fields.",
 
+	// ProposeBlockCmd help.
+	"proposeblock--synopsis": "Fully validates a proposed block as if it were connected to the current tip of the main chain or its parent without adding it to the chain or relaying it.\n" +
+		"The proof of work is not checked so that mining software can ensure assembled blocks are valid before working on them.",
+	"proposeblock-hexblock": "Serialized, hex-encoded block",
+
+	// ProposeBlockResult help.
+	"proposeblockresult-hash":       "The hash of the proposed block",
+	"proposeblockresult-valid":      "Whether or not the block is valid",
+	"proposeblockresult-rejectcode": "The code of the consensus rule the block violates (omitted when valid)",
+	"proposeblockresult-reason":     "The detailed reason the block is invalid (omitted when valid)",
+
 	// RebroadcastMissed help.
 	"rebroadcastmissed--synopsis": "Asks the daemon to rebroadcast missed votes.\n",
 
@@ -720,13 +1279,25 @@ var helpDescsEnUS = map[string]string{
 	"searchrawtransactions-verbose":     "Specifies the transaction is returned as a JSON object instead of hex-encoded string",
 	"searchrawtransactions--condition0": "verbose=0",
 	"searchrawtransactions--condition1": "verbose=1",
-	"searchrawtransactions-skip":        "The number of leading transactions to leave out of the final response",
-	"searchrawtransactions-count":       "The maximum number of transactions to return",
+	"searchrawtransactions--condition2": "cursor specified",
+	"searchrawtransactions-skip":        "The number of leading transactions to leave out of the final response (ignored when a cursor is specified)",
+	"searchrawtransactions-count":       "The maximum number of transactions to return (at most 1000 when a cursor is specified)",
 	"searchrawtransactions-vinextra":    "Specify that extra data from previous output will be returned in vin",
 	"searchrawtransactions-reverse":     "Specifies that the transactions should be returned in reverse chronological order",
 	"searchrawtransactions-filteraddrs": "Address list.  Only inputs or outputs with matching address will be returned",
+	"searchrawtransactions-cursor":      "Returns the transactions a page at a time along with pagination details when specified.  An empty string requests the first page and the next cursor from the previous response requests the next page",
 	"searchrawtransactions--result0":    "Hex-encoded serialized transaction",
 
+	// SearchRawTransactionsPageResult help.
+	"searchrawtransactionspageresult-hex":          "Hex-encoded serialized transactions (only when verbose=0)",
+	"searchrawtransactionspageresult-transactions": "The transactions as JSON objects (only when verbose=1)",
+	"searchrawtransactionspageresult-page":         "The pagination details",
+
+	// PageInfo help.
+	"pageinfo-nextcursor":    "The cursor that requests the next page (omitted when there is no next page)",
+	"pageinfo-limit":         "The maximum number of results per page",
+	"pageinfo-totalestimate": "The estimated total number of results, which is exact when there is no next page and a lower bound otherwise",
+
 	// SendRawTransactionCmd help.
 	"sendrawtransaction--synopsis":     "Submits the serialized, hex-encoded transaction to the local peer and relays it to the network.",
 	"sendrawtransaction-hextx":         "Serialized, hex-encoded signed transaction",
@@ -738,6 +1309,40 @@ var helpDescsEnUS = map[string]string{
 	"setgenerate-generate":     "Use true to enable generation, false to disable it",
 	"setgenerate-genproclimit": "The number of processors (cores) to limit generation to or -1 for default",
 
+	// SetMiningAddrsCmd help.
+	"setminingaddrs--synopsis": "Set the mining addresses generated block templates pay to along with the policy used to choose between them.\n" +
+		"The block template is regenerated so the change takes effect promptly.",
+	"setminingaddrs-addresses": "The mining addresses",
+	"setminingaddrs-policy":    "The policy used to choose which address each block template pays to (random or roundrobin, default: the current policy)",
+
+	// SetMiningExtraDataCmd help.
+	"setminingextradata--synopsis": "Set the extra data, such as a pool tag, that is appended to the coinbase signature script of generated block templates.\n" +
+		"The block template is regenerated so the change takes effect promptly.",
+	"setminingextradata-extradata": "The hex-encoded extra data (maximum 98 bytes)",
+
+	// SimulateDifficultyCmd help.
+	"simulatedifficulty--synopsis":           "Projects the required proof-of-work difficulty at each upcoming difficulty retarget assuming blocks are found by hypothetical hash rates relative to the current estimated network hash rate.",
+	"simulatedifficulty-hashratemultipliers": "The hash rates to simulate as multiples of the current estimated network hash rate, such as 0.5 for half and 2 for double (maximum 16)",
+	"simulatedifficulty-blocks":              "The number of blocks to simulate after the current best block (default: the number of blocks examined by the difficulty retarget rules, maximum 100000)",
+
+	// SimulateDifficultyResult help.
+	"simulatedifficultyresult-height":        "The height of the current best block the simulation starts from",
+	"simulatedifficultyresult-bits":          "The difficulty bits of the current best block",
+	"simulatedifficultyresult-difficulty":    "The difficulty of the current best block",
+	"simulatedifficultyresult-networkhashps": "The estimated network hashes per second the multipliers are applied to",
+	"simulatedifficultyresult-scenarios":     "The projected difficulty retargets for each hash rate multiplier in the order they were specified",
+
+	// SimulateDifficultyScenario help.
+	"simulatedifficultyscenario-hashratemultiplier": "The hash rate multiplier of the scenario",
+	"simulatedifficultyscenario-hashespersec":       "The simulated hashes per second",
+	"simulatedifficultyscenario-retargets":          "The projected difficulty at each retarget point within the simulated blocks",
+
+	// SimulateDifficultyRetarget help.
+	"simulatedifficultyretarget-height":     "The height of the first block the difficulty applies to",
+	"simulatedifficultyretarget-bits":       "The projected difficulty bits",
+	"simulatedifficultyretarget-difficulty": "The projected difficulty",
+	"simulatedifficultyretarget-time":       "The projected timestamp of the block in seconds since 1 Jan 1970 GMT",
+
 	// StopCmd help.
 	"stop--synopsis": "Shutdown dcrd.",
 	"stop--result0":  "The string 'dcrd stopping.'",
@@ -772,14 +1377,34 @@ var helpDescsEnUS = map[string]string{
 	"verifychain--result0":   "Whether or not the chain verified",
 
 	// VerifyMessageCmd help.
-	"verifymessage--synopsis": "Verify a signed message.",
-	"verifymessage-address":   "The Decred address to use for the signature",
-	"verifymessage-signature": "The base-64 encoded signature provided by the signer",
-	"verifymessage-message":   "The signed message",
-	"verifymessage--result0":  "Whether or not the signature verified",
+	"verifymessage--synopsis": "Verify a signed message.\n" +
+		"Pay-to-pubkey-hash addresses for ECDSA keys use 65-byte compact signatures and addresses for secp256k1 Schnorr keys use the 33-byte compressed public key followed by the 64-byte signature.\n" +
+		"Pay-to-script-hash addresses require the multisig redeem script and use the concatenation of 65-byte compact signatures from distinct keys in the script.",
+	"verifymessage-address":      "The Decred address to use for the signature",
+	"verifymessage-signature":    "The base-64 encoded signature provided by the signer",
+	"verifymessage-message":      "The signed message",
+	"verifymessage-redeemscript": "The hex-encoded multisig redeem script (required for pay-to-script-hash addresses)",
+	"verifymessage-verbose":      "Specifies the result is returned as a JSON object describing the outcome instead of a boolean",
+	"verifymessage--condition0":  "verbose=false",
+	"verifymessage--condition1":  "verbose=true",
+	"verifymessage--result0":     "Whether or not the signature verified",
+
+	// VerifyMessageResult help.
+	"verifymessageresult-verified":     "Whether or not the signature verified",
+	"verifymessageresult-address":      "The Decred address the signature was verified against",
+	"verifymessageresult-scripttype":   "The type of the address (pubkeyhash or scripthash)",
+	"verifymessageresult-sigtype":      "The signature algorithm of the signature (ecdsa or schnorr)",
+	"verifymessageresult-sigsrequired": "The number of signatures required by the redeem script (scripthash only)",
+	"verifymessageresult-signers":      "The hex-encoded public keys that produced valid signatures",
+	"verifymessageresult-reason":       "The reason the signature failed to verify (only when verified is false)",
 
 	// -------- Websocket-specific help --------
 
+	// RegisterClientCmd help.
+	"registerclient--synopsis": "Identify the software of a websocket client to the server.  The name and version are reported by listrpcclients for the remainder of the connection.",
+	"registerclient-name":      "The name of the client software",
+	"registerclient-version":   "The version of the client software",
+
 	// Session help.
 	"session--synopsis":       "Return details regarding a websocket client's current connection session.",
 	"sessionresult-sessionid": "The unique session ID for a client's websocket connection.",
@@ -791,13 +1416,20 @@ var helpDescsEnUS = map[string]string{
 	"notifynewtickets--synopsis": "Request notifications for whenever new tickets are found.",
 
 	// NotifyStakeDifficultyCmd help
-	"notifystakedifficulty--synopsis": "Request notifications for whenever stake difficulty goes up.",
+	"notifystakedifficulty--synopsis": "Request notifications for whenever stake difficulty is updated as well as when it is retargeted.",
+
+	// NotifyDiskSpaceCmd help
+	"notifydiskspace--synopsis": "Request notifications for whenever the free disk space available to the data directory crosses one of the configured thresholds.",
 
 	// NotifyWinningTicketsCmd help
 	"notifywinningtickets--synopsis": "Request notifications for whenever any tickets are chosen to vote.",
 
 	// NotifyBlocksCmd help.
-	"notifyblocks--synopsis": "Request notifications for whenever a block is connected or disconnected from the main (best) chain.",
+	"notifyblocks--synopsis":   "Request notifications for whenever a block is connected or disconnected from the main (best) chain.",
+	"notifyblocks-stakeevents": "Specifies blockconnected notifications include a summary of the ticket purchases, votes and their choices, and revocations in the block",
+
+	// NotifyFilteredBlocksCmd help.
+	"notifyfilteredblocks--synopsis": "Request filteredblockconnected notifications for whenever a block is connected to the main (best) chain, which only include the transactions that match the loaded transaction filter along with merkle inclusion proofs for them, as well as blockdisconnected notifications for whenever a block is disconnected from it.",
 
 	// NotifyWorkCmd help.
 	"notifywork--synopsis": "Request notifications for whenever a new block template is generated.",
@@ -805,6 +1437,9 @@ var helpDescsEnUS = map[string]string{
 	// StopNotifyBlocksCmd help.
 	"stopnotifyblocks--synopsis": "Cancel registered notifications for whenever a block is connected or disconnected from the main (best) chain.",
 
+	// StopNotifyFilteredBlocksCmd help.
+	"stopnotifyfilteredblocks--synopsis": "Cancel registered filtered notifications for whenever a block is connected or disconnected from the main (best) chain.",
+
 	// StopNotifyWorkCmd help.
 	"stopnotifywork--synopsis": "Cancel registered notifications for whenever a new block template is generated.",
 
@@ -855,6 +1490,30 @@ var helpDescsEnUS = map[string]string{
 	"getcoinsupply--synopsis": "Returns current total coin supply in atoms",
 	"getcoinsupply--result0":  "Current coin supply in atoms",
 
+	// ListBannedCmd help.
+	"listbanned--synopsis": "Returns the hosts that are currently banned a page at a time sorted by host.",
+	"listbanned-cursor":    "The next cursor from the previous response to request the next page (omitted or empty for the first page)",
+	"listbanned-limit":     "The maximum number of banned hosts to return (at most 1000)",
+
+	// ListBannedResult help.
+	"listbannedresult-banned": "The banned hosts",
+	"listbannedresult-page":   "The pagination details",
+
+	// BannedPeer help.
+	"bannedpeer-host":        "The banned host",
+	"bannedpeer-banneduntil": "The time the ban expires in seconds since 1 Jan 1970 GMT",
+
+	// ListRPCClientsCmd help.
+	"listrpcclients--synopsis":           "Returns data about each connected websocket client along with the notifications it is subscribed to.",
+	"listrpcclientsresult-sessionid":     "The unique session ID for the client's websocket connection",
+	"listrpcclientsresult-addr":          "The remote address of the client",
+	"listrpcclientsresult-name":          "The name the client registered itself with via registerclient (omitted if not registered)",
+	"listrpcclientsresult-version":       "The version the client registered itself with via registerclient (omitted if not provided)",
+	"listrpcclientsresult-authenticated": "Whether or not the client has authenticated",
+	"listrpcclientsresult-admin":         "Whether or not the client has administrative privileges",
+	"listrpcclientsresult-conntime":      "The time the client connected in seconds since 1 Jan 1970 GMT",
+	"listrpcclientsresult-subscriptions": "The notifications the client is subscribed to (blocks, work, winningtickets, spentandmissedtickets, newtickets, stakedifficulty, newtransactions, diskspace, txfilter)",
+
 	// LiveTickets help.
 	"livetickets--synopsis":     "Returns live ticket hashes from the ticket database",
 	"liveticketsresult-tickets": "List of live tickets",
@@ -942,86 +1601,123 @@ var helpDescsEnUS = map[string]string{
 
 	// regentemplate help
 	"regentemplate--synopsis": "Asks the node to regenerate its block mining template.",
+
+	// RollbackChainCmd help.
+	"rollbackchain--synopsis": "Disconnects all blocks of the main chain after the provided height (simnet or regnet only).\n" +
+		"The disconnected blocks are kept as a side chain and their transactions are returned to the mempool.",
+	"rollbackchain-height": "The height of the block to make the new tip of the main chain",
 }
 
 // rpcResultTypes specifies the result types that each RPC command can return.
 // This information is used to generate the help.  Each result type must be a
 // pointer to the type (or nil to indicate no return value).
 var rpcResultTypes = map[types.Method][]interface{}{
-	"addnode":               nil,
-	"createrawsstx":         {(*string)(nil)},
-	"createrawssrtx":        {(*string)(nil)},
-	"createrawtransaction":  {(*string)(nil)},
-	"debuglevel":            {(*string)(nil), (*string)(nil)},
-	"decoderawtransaction":  {(*types.TxRawDecodeResult)(nil)},
-	"decodescript":          {(*types.DecodeScriptResult)(nil)},
-	"estimatefee":           {(*float64)(nil)},
-	"estimatesmartfee":      {(*float64)(nil)},
-	"estimatestakediff":     {(*types.EstimateStakeDiffResult)(nil)},
-	"existsaddress":         {(*bool)(nil)},
-	"existsaddresses":       {(*string)(nil)},
-	"existsmissedtickets":   {(*string)(nil)},
-	"existsexpiredtickets":  {(*string)(nil)},
-	"existsliveticket":      {(*bool)(nil)},
-	"existslivetickets":     {(*string)(nil)},
-	"existsmempooltxs":      {(*string)(nil)},
-	"getaddednodeinfo":      {(*[]string)(nil), (*[]types.GetAddedNodeInfoResult)(nil)},
-	"getbestblock":          {(*types.GetBestBlockResult)(nil)},
-	"generate":              {(*[]string)(nil)},
-	"getbestblockhash":      {(*string)(nil)},
-	"getblock":              {(*string)(nil), (*types.GetBlockVerboseResult)(nil)},
-	"getblockchaininfo":     {(*types.GetBlockChainInfoResult)(nil)},
-	"getblockcount":         {(*int64)(nil)},
-	"getblockhash":          {(*string)(nil)},
-	"getblockheader":        {(*string)(nil), (*types.GetBlockHeaderVerboseResult)(nil)},
-	"getblocksubsidy":       {(*types.GetBlockSubsidyResult)(nil)},
-	"getcfilter":            {(*string)(nil)},
-	"getcfilterheader":      {(*string)(nil)},
-	"getcfilterv2":          {(*types.GetCFilterV2Result)(nil)},
-	"getchaintips":          {(*[]types.GetChainTipsResult)(nil)},
-	"getconnectioncount":    {(*int32)(nil)},
-	"getcurrentnet":         {(*uint32)(nil)},
-	"getdifficulty":         {(*float64)(nil)},
-	"getstakedifficulty":    {(*types.GetStakeDifficultyResult)(nil)},
-	"getstakeversioninfo":   {(*types.GetStakeVersionInfoResult)(nil)},
-	"getstakeversions":      {(*types.GetStakeVersionsResult)(nil)},
-	"getgenerate":           {(*bool)(nil)},
-	"gethashespersec":       {(*float64)(nil)},
-	"getheaders":            {(*types.GetHeadersResult)(nil)},
-	"getinfo":               {(*types.InfoChainResult)(nil)},
-	"getmempoolinfo":        {(*types.GetMempoolInfoResult)(nil)},
-	"getmininginfo":         {(*types.GetMiningInfoResult)(nil)},
-	"getnettotals":          {(*types.GetNetTotalsResult)(nil)},
-	"getnetworkhashps":      {(*int64)(nil)},
-	"getnetworkinfo":        {(*[]types.GetNetworkInfoResult)(nil)},
-	"getpeerinfo":           {(*[]types.GetPeerInfoResult)(nil)},
-	"getrawmempool":         {(*[]string)(nil), (*types.GetRawMempoolVerboseResult)(nil)},
-	"getrawtransaction":     {(*string)(nil), (*types.TxRawResult)(nil)},
-	"getticketpoolvalue":    {(*float64)(nil)},
-	"gettxout":              {(*types.GetTxOutResult)(nil)},
-	"gettxoutsetinfo":       {(*types.GetTxOutSetInfoResult)(nil)},
-	"getvoteinfo":           {(*types.GetVoteInfoResult)(nil)},
-	"getwork":               {(*types.GetWorkResult)(nil), (*bool)(nil)},
-	"getcoinsupply":         {(*int64)(nil)},
-	"help":                  {(*string)(nil), (*string)(nil)},
-	"livetickets":           {(*types.LiveTicketsResult)(nil)},
-	"missedtickets":         {(*types.MissedTicketsResult)(nil)},
-	"node":                  nil,
-	"ping":                  nil,
-	"regentemplate":         nil,
-	"searchrawtransactions": {(*string)(nil), (*[]types.SearchRawTransactionsResult)(nil)},
-	"sendrawtransaction":    {(*string)(nil)},
-	"setgenerate":           nil,
-	"stop":                  {(*string)(nil)},
-	"submitblock":           {nil, (*string)(nil)},
-	"ticketfeeinfo":         {(*types.TicketFeeInfoResult)(nil)},
-	"ticketsforaddress":     {(*types.TicketsForAddressResult)(nil)},
-	"ticketvwap":            {(*float64)(nil)},
-	"txfeeinfo":             {(*types.TxFeeInfoResult)(nil)},
-	"validateaddress":       {(*types.ValidateAddressChainResult)(nil)},
-	"verifychain":           {(*bool)(nil)},
-	"verifymessage":         {(*bool)(nil)},
-	"version":               {(*map[string]types.VersionResult)(nil)},
+	"addnode":                  nil,
+	"captureprofile":           {(*types.CaptureProfileResult)(nil)},
+	"checktransactionstandard": {(*types.CheckTransactionStandardResult)(nil)},
+	"comparechainwork":         {(*types.CompareChainWorkResult)(nil)},
+	"coordheartbeat":           {(*types.CoordHeartbeatResult)(nil)},
+	"createrawsstx":            {(*string)(nil)},
+	"createrawssrtx":           {(*string)(nil)},
+	"createrawtransaction":     {(*string)(nil)},
+	"createrevocation":         {(*string)(nil)},
+	"debuglevel":               {(*string)(nil), (*string)(nil)},
+	"decoderawtransaction":     {(*types.TxRawDecodeResult)(nil)},
+	"decodescript":             {(*types.DecodeScriptResult)(nil)},
+	"disconnectrpcclient":      nil,
+	"dumppeertelemetry":        {(*types.DumpPeerTelemetryResult)(nil)},
+	"estimatefee":              {(*float64)(nil)},
+	"estimatesmartfee":         {(*float64)(nil)},
+	"estimatestakediff":        {(*types.EstimateStakeDiffResult)(nil)},
+	"evaluatetxlocks":          {(*types.EvaluateTxLocksResult)(nil)},
+	"existsaddress":            {(*bool)(nil)},
+	"existsaddresses":          {(*string)(nil)},
+	"existsmissedtickets":      {(*string)(nil)},
+	"existsexpiredtickets":     {(*string)(nil)},
+	"existsliveticket":         {(*bool)(nil)},
+	"existslivetickets":        {(*string)(nil)},
+	"existsmempooltxs":         {(*string)(nil)},
+	"getaddednodeinfo":         {(*[]string)(nil), (*[]types.GetAddedNodeInfoResult)(nil)},
+	"getaddressactivity":       {(*types.GetAddressActivityResult)(nil)},
+	"getaddressutxos":          {(*types.GetAddressUtxosResult)(nil)},
+	"getbestblock":             {(*types.GetBestBlockResult)(nil)},
+	"generate":                 {(*[]string)(nil)},
+	"generatetoaddress":        {(*[]string)(nil)},
+	"getbestblockhash":         {(*string)(nil)},
+	"getblock":                 {(*string)(nil), (*types.GetBlockVerboseResult)(nil)},
+	"getblockchaininfo":        {(*types.GetBlockChainInfoResult)(nil)},
+	"getblockcount":            {(*int64)(nil)},
+	"getblockhash":             {(*string)(nil)},
+	"getblockheader":           {(*string)(nil), (*types.GetBlockHeaderVerboseResult)(nil)},
+	"getblockstats":            {(*types.GetBlockStatsResult)(nil)},
+	"getblocksubsidy":          {(*types.GetBlockSubsidyResult)(nil)},
+	"getblocktemplate":         {(*types.GetBlockTemplateResult)(nil), (*string)(nil), nil},
+	"getcfilter":               {(*string)(nil)},
+	"getcfilterheader":         {(*string)(nil)},
+	"getcfilterv2":             {(*types.GetCFilterV2Result)(nil)},
+	"getchainparams":           {(*types.GetChainParamsResult)(nil)},
+	"getchaintips":             {(*[]types.GetChainTipsResult)(nil)},
+	"getconnectioncount":       {(*int32)(nil)},
+	"getcurrentnet":            {(*uint32)(nil)},
+	"getdifficulty":            {(*float64)(nil)},
+	"getsignalingstats":        {(*types.GetSignalingStatsResult)(nil)},
+	"getstakedifficulty":       {(*types.GetStakeDifficultyResult)(nil)},
+	"getstakeversioninfo":      {(*types.GetStakeVersionInfoResult)(nil)},
+	"getstakeversions":         {(*types.GetStakeVersionsResult)(nil)},
+	"getstandardpolicy":        {(*types.GetStandardPolicyResult)(nil)},
+	"getsyncpeer":              {(*types.GetSyncPeerResult)(nil)},
+	"gettemplatedecisions":     {(*[]types.GetTemplateDecisionsResult)(nil)},
+	"getdiskspaceinfo":         {(*types.GetDiskSpaceInfoResult)(nil)},
+	"getgenerate":              {(*bool)(nil)},
+	"gethashespersec":          {(*float64)(nil)},
+	"getheaders":               {(*types.GetHeadersResult)(nil)},
+	"getinfo":                  {(*types.InfoChainResult)(nil)},
+	"getmempoolinfo":           {(*types.GetMempoolInfoResult)(nil)},
+	"getmempoolreplacements":   {(*types.GetMempoolReplacementsResult)(nil)},
+	"getminingaddrs":           {(*types.GetMiningAddrsResult)(nil)},
+	"getmininginfo":            {(*types.GetMiningInfoResult)(nil)},
+	"getnettotals":             {(*types.GetNetTotalsResult)(nil)},
+	"getnetworkhashps":         {(*int64)(nil), (*types.GetNetworkHashPSResult)(nil)},
+	"getnetworkinfo":           {(*[]types.GetNetworkInfoResult)(nil)},
+	"getpeerinfo":              {(*[]types.GetPeerInfoResult)(nil)},
+	"getrawmempool":            {(*[]string)(nil), (*types.GetRawMempoolVerboseResult)(nil)},
+	"getrawtransaction":        {(*string)(nil), (*types.TxRawResult)(nil)},
+	"getrejectedtransactions":  {(*[]types.GetRejectedTransactionsResult)(nil)},
+	"getticketpoolvalue":       {(*float64)(nil)},
+	"gettimeinfo":              {(*types.GetTimeInfoResult)(nil)},
+	"gettxout":                 {(*types.GetTxOutResult)(nil)},
+	"gettxoutsetinfo":          {(*types.GetTxOutSetInfoResult)(nil)},
+	"gettxscriptcost":          {(*types.GetTxScriptCostResult)(nil)},
+	"getvoteinfo":              {(*types.GetVoteInfoResult)(nil)},
+	"getwork":                  {(*types.GetWorkResult)(nil), (*bool)(nil)},
+	"getworkstats":             {(*types.GetWorkStatsResult)(nil)},
+	"getcoinsupply":            {(*int64)(nil)},
+	"help":                     {(*string)(nil), (*string)(nil)},
+	"listbanned":               {(*types.ListBannedResult)(nil)},
+	"listrpcclients":           {(*[]types.ListRPCClientsResult)(nil)},
+	"livetickets":              {(*types.LiveTicketsResult)(nil)},
+	"missedtickets":            {(*types.MissedTicketsResult)(nil)},
+	"node":                     nil,
+	"ping":                     nil,
+	"proposeblock":             {(*types.ProposeBlockResult)(nil)},
+	"regentemplate":            nil,
+	"rollbackchain":            nil,
+	"searchrawtransactions":    {(*string)(nil), (*[]types.SearchRawTransactionsResult)(nil), (*types.SearchRawTransactionsPageResult)(nil)},
+	"sendrawtransaction":       {(*string)(nil)},
+	"setgenerate":              nil,
+	"setminingaddrs":           nil,
+	"setminingextradata":       nil,
+	"simulatedifficulty":       {(*types.SimulateDifficultyResult)(nil)},
+	"stop":                     {(*string)(nil)},
+	"submitblock":              {nil, (*string)(nil)},
+	"ticketfeeinfo":            {(*types.TicketFeeInfoResult)(nil)},
+	"ticketsforaddress":        {(*types.TicketsForAddressResult)(nil)},
+	"ticketvwap":               {(*float64)(nil)},
+	"txfeeinfo":                {(*types.TxFeeInfoResult)(nil)},
+	"validateaddress":          {(*types.ValidateAddressChainResult)(nil)},
+	"verifychain":              {(*bool)(nil)},
+	"verifymessage":            {(*bool)(nil), (*types.VerifyMessageResult)(nil)},
+	"version":                  {(*map[string]types.VersionResult)(nil)},
 
 	// Websocket commands.
 	"loadtxfilter":                nil,
@@ -1030,27 +1726,175 @@ var rpcResultTypes = map[types.Method][]interface{}{
 	"notifynewtickets":            nil,
 	"notifystakedifficulty":       nil,
 	"notifyblocks":                nil,
+	"notifydiskspace":             nil,
+	"notifyfilteredblocks":        nil,
 	"notifywork":                  nil,
 	"notifynewtransactions":       nil,
 	"notifyreceived":              nil,
 	"notifyspent":                 nil,
 	"rebroadcastmissed":           nil,
 	"rebroadcastwinners":          nil,
+	"registerclient":              nil,
 	"rescan":                      nil,
 	"session":                     {(*types.SessionResult)(nil)},
 	"stopnotifyblocks":            nil,
+	"stopnotifyfilteredblocks":    nil,
 	"stopnotifywork":              nil,
 	"stopnotifynewtransactions":   nil,
 	"stopnotifyreceived":          nil,
 	"stopnotifyspent":             nil,
 }
 
+// rpcResultSchemaVersions specifies the version of the schema of the results
+// that each RPC command can return.  The version is included in the help so
+// that tooling which compares responses across node versions can tell whether
+// the results of a command are expected to differ.  It must be incremented
+// whenever the results of a command change in a way that alters their
+// marshalled form, such as adding, removing, renaming, or reordering fields.
+var rpcResultSchemaVersions = map[types.Method]uint32{
+	"addnode":                  1,
+	"captureprofile":           1,
+	"checktransactionstandard": 1,
+	"comparechainwork":         1,
+	"coordheartbeat":           1,
+	"createrawsstx":            1,
+	"createrawssrtx":           1,
+	"createrawtransaction":     1,
+	"createrevocation":         1,
+	"debuglevel":               1,
+	"decoderawtransaction":     1,
+	"decodescript":             1,
+	"disconnectrpcclient":      1,
+	"dumppeertelemetry":        1,
+	"estimatefee":              1,
+	"estimatesmartfee":         1,
+	"estimatestakediff":        1,
+	"evaluatetxlocks":          1,
+	"existsaddress":            1,
+	"existsaddresses":          1,
+	"existsmissedtickets":      1,
+	"existsexpiredtickets":     1,
+	"existsliveticket":         1,
+	"existslivetickets":        1,
+	"existsmempooltxs":         1,
+	"getaddednodeinfo":         1,
+	"getaddressactivity":       1,
+	"getaddressutxos":          1,
+	"getbestblock":             1,
+	"generate":                 1,
+	"generatetoaddress":        1,
+	"getbestblockhash":         1,
+	"getblock":                 1,
+	"getblockchaininfo":        1,
+	"getblockcount":            1,
+	"getblockhash":             1,
+	"getblockheader":           1,
+	"getblockstats":            1,
+	"getblocksubsidy":          1,
+	"getblocktemplate":         1,
+	"getcfilter":               1,
+	"getcfilterheader":         1,
+	"getcfilterv2":             1,
+	"getchainparams":           1,
+	"getchaintips":             1,
+	"getconnectioncount":       1,
+	"getcurrentnet":            1,
+	"getdifficulty":            1,
+	"getsignalingstats":        1,
+	"getstakedifficulty":       1,
+	"getstakeversioninfo":      1,
+	"getstakeversions":         1,
+	"getstandardpolicy":        1,
+	"getsyncpeer":              1,
+	"gettemplatedecisions":     1,
+	"getdiskspaceinfo":         1,
+	"getgenerate":              1,
+	"gethashespersec":          1,
+	"getheaders":               1,
+	"getinfo":                  1,
+	"getmempoolinfo":           1,
+	"getmempoolreplacements":   1,
+	"getminingaddrs":           1,
+	"getmininginfo":            1,
+	"getnettotals":             1,
+	"getnetworkhashps":         1,
+	"getnetworkinfo":           1,
+	"getpeerinfo":              1,
+	"getrawmempool":            1,
+	"getrawtransaction":        1,
+	"getrejectedtransactions":  1,
+	"getticketpoolvalue":       1,
+	"gettimeinfo":              1,
+	"gettxout":                 1,
+	"gettxoutsetinfo":          1,
+	"gettxscriptcost":          1,
+	"getvoteinfo":              1,
+	"getwork":                  1,
+	"getworkstats":             1,
+	"getcoinsupply":            1,
+	"help":                     1,
+	"listbanned":               1,
+	"listrpcclients":           1,
+	"livetickets":              1,
+	"missedtickets":            1,
+	"node":                     1,
+	"ping":                     1,
+	"proposeblock":             1,
+	"regentemplate":            1,
+	"rollbackchain":            1,
+	"searchrawtransactions":    1,
+	"sendrawtransaction":       1,
+	"setgenerate":              1,
+	"setminingaddrs":           1,
+	"setminingextradata":       1,
+	"simulatedifficulty":       1,
+	"stop":                     1,
+	"submitblock":              1,
+	"ticketfeeinfo":            1,
+	"ticketsforaddress":        1,
+	"ticketvwap":               1,
+	"txfeeinfo":                1,
+	"validateaddress":          1,
+	"verifychain":              1,
+	"verifymessage":            1,
+	"version":                  1,
+
+	// Websocket commands.
+	"loadtxfilter":                1,
+	"notifywinningtickets":        1,
+	"notifyspentandmissedtickets": 1,
+	"notifynewtickets":            1,
+	"notifystakedifficulty":       1,
+	"notifyblocks":                1,
+	"notifydiskspace":             1,
+	"notifyfilteredblocks":        1,
+	"notifywork":                  1,
+	"notifynewtransactions":       1,
+	"notifyreceived":              1,
+	"notifyspent":                 1,
+	"rebroadcastmissed":           1,
+	"rebroadcastwinners":          1,
+	"registerclient":              1,
+	"rescan":                      1,
+	"session":                     1,
+	"stopnotifyblocks":            1,
+	"stopnotifyfilteredblocks":    1,
+	"stopnotifywork":              1,
+	"stopnotifynewtransactions":   1,
+	"stopnotifyreceived":          1,
+	"stopnotifyspent":             1,
+}
+
 // helpCacher provides a concurrent safe type that provides help and usage for
 // the RPC server commands and caches the results for future calls.
 type helpCacher struct {
 	sync.Mutex
 	usage      string
 	methodHelp map[types.Method]string
+
+	// includeExperimental indicates whether or not the usage includes the
+	// experimental RPCs.
+	includeExperimental bool
 }
 
 // rpcMethodHelp returns an RPC help string for the provided method.
@@ -1072,11 +1916,21 @@ func (c *helpCacher) rpcMethodHelp(method types.Method) (string, error) {
 			string(method))
 	}
 
-	// Generate, cache, and return the help.
+	// Look up the version of the schema of the results for the method.
+	schemaVersion, ok := rpcResultSchemaVersions[method]
+	if !ok {
+		return "", errors.New("no result schema version specified for " +
+			"method " + string(method))
+	}
+
+	// Generate, cache, and return the help along with the result schema
+	// version.
 	help, err := dcrjson.GenerateHelp(method, helpDescsEnUS, resultTypes...)
 	if err != nil {
 		return "", err
 	}
+	help += fmt.Sprintf("\n%s: %d\n",
+		helpDescsEnUS["help-result-schema-version"], schemaVersion)
 	c.methodHelp[method] = help
 	return help, nil
 }
@@ -1096,6 +1950,9 @@ func (c *helpCacher) rpcUsage(includeWebsockets bool) (string, error) {
 	// Generate a list of one-line usage for every command.
 	usageTexts := make([]string, 0, len(rpcHandlers))
 	for k := range rpcHandlers {
+		if isExperimentalRPC(k) && !c.includeExperimental {
+			continue
+		}
 		usage, err := dcrjson.MethodUsageText(k)
 		if err != nil {
 			return "", err
@@ -1121,8 +1978,11 @@ func (c *helpCacher) rpcUsage(includeWebsockets bool) (string, error) {
 
 // newHelpCacher returns a new instance of a help cacher which provides help and
 // usage for the RPC server commands and caches the results for future calls.
-func newHelpCacher() *helpCacher {
+// The usage only includes the experimental RPCs when includeExperimental is
+// set.
+func newHelpCacher(includeExperimental bool) *helpCacher {
 	return &helpCacher{
-		methodHelp: make(map[types.Method]string),
+		methodHelp:          make(map[types.Method]string),
+		includeExperimental: includeExperimental,
 	}
 }
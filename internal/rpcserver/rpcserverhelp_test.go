@@ -5,7 +5,15 @@
 
 package rpcserver
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrd/dcrjson/v3"
+	"github.com/decred/dcrd/rpc/jsonrpc/types/v2"
+)
 
 // TestHelp ensures the help is reasonably accurate by checking that every
 // command specified also has result types defined and the one-line usage and
@@ -18,6 +26,11 @@ func TestHelp(t *testing.T) {
 				"also specifying result types", k)
 			continue
 		}
+		if _, ok := rpcResultSchemaVersions[k]; !ok {
+			t.Errorf("RPC handler defined for method '%v' without "+
+				"also specifying a result schema version", k)
+			continue
+		}
 	}
 	for k := range wsHandlers {
 		if _, ok := rpcResultTypes[k]; !ok {
@@ -25,10 +38,15 @@ func TestHelp(t *testing.T) {
 				"also specifying result types", k)
 			continue
 		}
+		if _, ok := rpcResultSchemaVersions[k]; !ok {
+			t.Errorf("RPC handler defined for method '%v' without "+
+				"also specifying a result schema version", k)
+			continue
+		}
 	}
 
 	// Ensure the usage for every command can be generated without errors.
-	helpCacher := newHelpCacher()
+	helpCacher := newHelpCacher(true)
 	if _, err := helpCacher.rpcUsage(true); err != nil {
 		t.Fatalf("Failed to generate one-line usage: %v", err)
 	}
@@ -61,4 +79,63 @@ func TestHelp(t *testing.T) {
 			continue
 		}
 	}
+
+	// Ensure the help includes the result schema version.
+	help, err := helpCacher.rpcMethodHelp("getbestblock")
+	if err != nil {
+		t.Fatalf("Failed to generate help for getbestblock: %v", err)
+	}
+	const wantVersion = "\nResult schema version: 1\n"
+	if !strings.HasSuffix(help, wantVersion) {
+		t.Fatalf("help for getbestblock does not include the result "+
+			"schema version:\n%s", help)
+	}
+}
+
+// TestExperimentalRPC ensures experimental RPCs are only dispatched and only
+// provide help when they are enabled and that they are excluded from the usage
+// otherwise.
+//
+// It is intentionally not run in parallel since it temporarily adds a handler
+// for an experimental RPC.
+func TestExperimentalRPC(t *testing.T) {
+	const method = types.Method(experimentalRPCPrefix + "test")
+	rpcHandlers[method] = func(context.Context, *Server, interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	defer delete(rpcHandlers, method)
+
+	cmd := &parsedRPCCmd{method: method}
+	helpCmd := &types.HelpCmd{Command: dcrjson.String(string(method))}
+	ctx := context.Background()
+
+	// Ensure the RPC is not available when experimental RPCs are disabled.
+	s := &Server{helpCacher: newHelpCacher(false)}
+	_, err := s.standardCmdResult(ctx, cmd)
+	if !errors.Is(err, ErrRPCExperimentalDisabled) {
+		t.Fatalf("unexpected dispatch error -- got %v, want %v", err,
+			ErrRPCExperimentalDisabled)
+	}
+	_, err = handleHelp(ctx, s, helpCmd)
+	if !errors.Is(err, ErrRPCExperimentalDisabled) {
+		t.Fatalf("unexpected help error -- got %v, want %v", err,
+			ErrRPCExperimentalDisabled)
+	}
+	usage, err := s.helpCacher.rpcUsage(false)
+	if err != nil {
+		t.Fatalf("unexpected usage error: %v", err)
+	}
+	if strings.Contains(usage, string(method)) {
+		t.Fatalf("usage includes disabled experimental RPC %q", method)
+	}
+
+	// Ensure the RPC is dispatched when experimental RPCs are enabled.
+	s = &Server{cfg: Config{EnableExperimentalRPC: true}}
+	result, err := s.standardCmdResult(ctx, cmd)
+	if err != nil {
+		t.Fatalf("unexpected dispatch error: %v", err)
+	}
+	if result != "ok" {
+		t.Fatalf("unexpected result -- got %v, want ok", result)
+	}
 }
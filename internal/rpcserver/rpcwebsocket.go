@@ -28,6 +28,8 @@ import (
 	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/chaincfg/v3"
 	"github.com/decred/dcrd/crypto/ripemd160"
+	"github.com/decred/dcrd/database/v2"
+	"github.com/decred/dcrd/dcrec"
 	"github.com/decred/dcrd/dcrjson/v3"
 	"github.com/decred/dcrd/dcrutil/v3"
 	"github.com/decred/dcrd/internal/mining"
@@ -43,6 +45,15 @@ const (
 	// handler since notifications have their own queuing mechanism
 	// independent of the send channel buffer.
 	websocketSendBufferSize = 50
+
+	// maxClientNameLen is the maximum length of the name and version a
+	// websocket client may identify itself with via registerclient.
+	maxClientNameLen = 64
+
+	// rescanIndexBatchSize is the number of address index entries that are
+	// requested at a time when determining which blocks a rescan must
+	// examine.
+	rescanIndexBatchSize = 1000
 )
 
 type semaphore chan struct{}
@@ -70,6 +81,8 @@ var wsHandlersBeforeInit = map[types.Method]wsCommandHandler{
 	"help":                        handleWebsocketHelp,
 	"loadtxfilter":                handleLoadTxFilter,
 	"notifyblocks":                handleNotifyBlocks,
+	"notifydiskspace":             handleNotifyDiskSpace,
+	"notifyfilteredblocks":        handleNotifyFilteredBlocks,
 	"notifywork":                  handleNotifyWork,
 	"notifywinningtickets":        handleWinningTickets,
 	"notifyspentandmissedtickets": handleSpentAndMissedTickets,
@@ -78,9 +91,11 @@ var wsHandlersBeforeInit = map[types.Method]wsCommandHandler{
 	"notifynewtransactions":       handleNotifyNewTransactions,
 	"rebroadcastmissed":           handleRebroadcastMissed,
 	"rebroadcastwinners":          handleRebroadcastWinners,
+	"registerclient":              handleRegisterClient,
 	"rescan":                      handleRescan,
 	"session":                     handleSession,
 	"stopnotifyblocks":            handleStopNotifyBlocks,
+	"stopnotifyfilteredblocks":    handleStopNotifyFilteredBlocks,
 	"stopnotifywork":              handleStopNotifyWork,
 	"stopnotifynewtransactions":   handleStopNotifyNewTransactions,
 }
@@ -236,6 +251,16 @@ func (m *wsNotificationManager) NotifyReorganization(rd *blockchain.Reorganizati
 	}
 }
 
+// NotifyDiskSpace passes a change in the level of the free disk space
+// available to the data directory to the notification manager for disk space
+// notification processing.
+func (m *wsNotificationManager) NotifyDiskSpace(status *DiskSpaceStatus) {
+	select {
+	case m.queueNotification <- (*notificationDiskSpace)(status):
+	case <-m.quit:
+	}
+}
+
 // NotifyWinningTickets passes newly winning tickets for an incoming block
 // to the notification manager for further processing.
 func (m *wsNotificationManager) NotifyWinningTickets(wtnd *WinningTicketsNtfnData) {
@@ -420,6 +445,53 @@ func (f *wsClientFilter) existsAddress(a dcrutil.Address) bool {
 	return ok
 }
 
+// addresses returns all of the addresses in the filter.  The filter does not
+// retain the signature scheme of pay-to-pubkey-hash addresses, so an address
+// is returned for each supported signature scheme of every pubkey hash.
+//
+// This function MUST be called with the filter mutex held.
+func (f *wsClientFilter) addresses() []dcrutil.Address {
+	sigTypes := []dcrec.SignatureType{dcrec.STEcdsaSecp256k1,
+		dcrec.STEd25519, dcrec.STSchnorrSecp256k1}
+	addrs := make([]dcrutil.Address, 0, len(f.pubKeyHashes)*len(sigTypes)+
+		len(f.scriptHashes)+len(f.compressedPubKeys)+
+		len(f.uncompressedPubKeys)+len(f.otherAddresses))
+	for hash := range f.pubKeyHashes {
+		for _, sigType := range sigTypes {
+			a, err := dcrutil.NewAddressPubKeyHash(hash[:], f.params,
+				sigType)
+			if err == nil {
+				addrs = append(addrs, a)
+			}
+		}
+	}
+	for hash := range f.scriptHashes {
+		a, err := dcrutil.NewAddressScriptHashFromHash(hash[:], f.params)
+		if err == nil {
+			addrs = append(addrs, a)
+		}
+	}
+	for pubKey := range f.compressedPubKeys {
+		a, err := dcrutil.NewAddressSecpPubKey(pubKey[:], f.params)
+		if err == nil {
+			addrs = append(addrs, a)
+		}
+	}
+	for pubKey := range f.uncompressedPubKeys {
+		a, err := dcrutil.NewAddressSecpPubKey(pubKey[:], f.params)
+		if err == nil {
+			addrs = append(addrs, a)
+		}
+	}
+	for addrStr := range f.otherAddresses {
+		a, err := dcrutil.DecodeAddress(addrStr, f.params)
+		if err == nil {
+			addrs = append(addrs, a)
+		}
+	}
+	return addrs
+}
+
 func (f *wsClientFilter) addUnspentOutPoint(op *wire.OutPoint) {
 	f.unspent[*op] = struct{}{}
 }
@@ -438,6 +510,7 @@ type notificationWinningTickets WinningTicketsNtfnData
 type notificationSpentAndMissedTickets blockchain.TicketNotificationsData
 type notificationNewTickets blockchain.TicketNotificationsData
 type notificationStakeDifficulty StakeDifficultyNtfnData
+type notificationDiskSpace DiskSpaceStatus
 type notificationTxAcceptedByMempool struct {
 	isNew bool
 	tx    *dcrutil.Tx
@@ -448,6 +521,8 @@ type notificationRegisterClient wsClient
 type notificationUnregisterClient wsClient
 type notificationRegisterBlocks wsClient
 type notificationUnregisterBlocks wsClient
+type notificationRegisterFilteredBlocks wsClient
+type notificationUnregisterFilteredBlocks wsClient
 type notificationRegisterWork wsClient
 type notificationUnregisterWork wsClient
 type notificationRegisterWinningTickets wsClient
@@ -458,8 +533,18 @@ type notificationRegisterNewTickets wsClient
 type notificationUnregisterNewTickets wsClient
 type notificationRegisterStakeDifficulty wsClient
 type notificationUnregisterStakeDifficulty wsClient
+type notificationRegisterDiskSpace wsClient
+type notificationUnregisterDiskSpace wsClient
 type notificationRegisterNewMempoolTxs wsClient
 type notificationUnregisterNewMempoolTxs wsClient
+type notificationQueryClients chan []*wsClientInfo
+
+// wsClientInfo houses details about a connected websocket client along with
+// the names of the notifications it is subscribed to.
+type wsClientInfo struct {
+	client        *wsClient
+	subscriptions []string
+}
 
 // notificationHandler reads notifications and control messages from the queue
 // handler and processes one at a time.
@@ -475,12 +560,14 @@ func (m *wsNotificationManager) notificationHandler(ctx context.Context) {
 	// Where possible, the quit channel is used as the unique id for a client
 	// since it is quite a bit more efficient than using the entire struct.
 	blockNotifications := make(map[chan struct{}]*wsClient)
+	filteredBlockNotifications := make(map[chan struct{}]*wsClient)
 	workNotifications := make(map[chan struct{}]*wsClient)
 	winningTicketNotifications := make(map[chan struct{}]*wsClient)
 	ticketSMNotifications := make(map[chan struct{}]*wsClient)
 	ticketNewNotifications := make(map[chan struct{}]*wsClient)
 	stakeDifficultyNotifications := make(map[chan struct{}]*wsClient)
 	txNotifications := make(map[chan struct{}]*wsClient)
+	diskSpaceNotifications := make(map[chan struct{}]*wsClient)
 
 out:
 	for {
@@ -500,15 +587,30 @@ out:
 
 				// Skip iterating through all txs if no tx
 				// notification requests exist.
-				if len(blockNotifications) == 0 {
-					continue
+				if len(blockNotifications) != 0 {
+					m.notifyBlockConnected(blockNotifications, block)
+				}
+				if len(filteredBlockNotifications) != 0 {
+					m.notifyFilteredBlockConnected(filteredBlockNotifications,
+						block)
 				}
-
-				m.notifyBlockConnected(blockNotifications, block)
 
 			case *notificationBlockDisconnected:
-				m.notifyBlockDisconnected(blockNotifications,
-					(*dcrutil.Block)(n))
+				// Clients registered for both block and filtered block
+				// notifications are only notified once.
+				clients := blockNotifications
+				if len(filteredBlockNotifications) != 0 {
+					clients = make(map[chan struct{}]*wsClient,
+						len(blockNotifications)+
+							len(filteredBlockNotifications))
+					for quit, wsc := range blockNotifications {
+						clients[quit] = wsc
+					}
+					for quit, wsc := range filteredBlockNotifications {
+						clients[quit] = wsc
+					}
+				}
+				m.notifyBlockDisconnected(clients, (*dcrutil.Block)(n))
 
 			case *notificationWork:
 				m.notifyWork(workNotifications, (*mining.TemplateNtfn)(n))
@@ -533,11 +635,15 @@ out:
 				m.notifyStakeDifficulty(stakeDifficultyNotifications,
 					(*StakeDifficultyNtfnData)(n))
 
+			case *notificationDiskSpace:
+				m.notifyDiskSpace(diskSpaceNotifications,
+					(*DiskSpaceStatus)(n))
+
 			case *notificationTxAcceptedByMempool:
 				if n.isNew && len(txNotifications) != 0 {
 					m.notifyForNewTx(txNotifications, n.tx)
 				}
-				m.notifyRelevantTxAccepted(n.tx, clients)
+				m.notifyRelevantTxAccepted(n.tx, n.isNew, clients)
 
 			case *notificationRegisterBlocks:
 				wsc := (*wsClient)(n)
@@ -547,6 +653,14 @@ out:
 				wsc := (*wsClient)(n)
 				delete(blockNotifications, wsc.quit)
 
+			case *notificationRegisterFilteredBlocks:
+				wsc := (*wsClient)(n)
+				filteredBlockNotifications[wsc.quit] = wsc
+
+			case *notificationUnregisterFilteredBlocks:
+				wsc := (*wsClient)(n)
+				delete(filteredBlockNotifications, wsc.quit)
+
 			case *notificationRegisterWork:
 				wsc := (*wsClient)(n)
 				workNotifications[wsc.quit] = wsc
@@ -587,6 +701,14 @@ out:
 				wsc := (*wsClient)(n)
 				delete(stakeDifficultyNotifications, wsc.quit)
 
+			case *notificationRegisterDiskSpace:
+				wsc := (*wsClient)(n)
+				diskSpaceNotifications[wsc.quit] = wsc
+
+			case *notificationUnregisterDiskSpace:
+				wsc := (*wsClient)(n)
+				delete(diskSpaceNotifications, wsc.quit)
+
 			case *notificationRegisterClient:
 				wsc := (*wsClient)(n)
 				clients[wsc.quit] = wsc
@@ -596,12 +718,14 @@ out:
 				// Remove any requests made by the client as well as
 				// the client itself.
 				delete(blockNotifications, wsc.quit)
+				delete(filteredBlockNotifications, wsc.quit)
 				delete(workNotifications, wsc.quit)
 				delete(txNotifications, wsc.quit)
 				delete(winningTicketNotifications, wsc.quit)
 				delete(ticketSMNotifications, wsc.quit)
 				delete(ticketNewNotifications, wsc.quit)
 				delete(stakeDifficultyNotifications, wsc.quit)
+				delete(diskSpaceNotifications, wsc.quit)
 				delete(clients, wsc.quit)
 
 			case *notificationRegisterNewMempoolTxs:
@@ -612,6 +736,44 @@ out:
 				wsc := (*wsClient)(n)
 				delete(txNotifications, wsc.quit)
 
+			case notificationQueryClients:
+				subscriptions := []struct {
+					name    string
+					clients map[chan struct{}]*wsClient
+				}{
+					{"blocks", blockNotifications},
+					{"filteredblocks", filteredBlockNotifications},
+					{"work", workNotifications},
+					{"winningtickets", winningTicketNotifications},
+					{"spentandmissedtickets", ticketSMNotifications},
+					{"newtickets", ticketNewNotifications},
+					{"stakedifficulty", stakeDifficultyNotifications},
+					{"newtransactions", txNotifications},
+					{"diskspace", diskSpaceNotifications},
+				}
+				infos := make([]*wsClientInfo, 0, len(clients))
+				for quit, wsc := range clients {
+					info := &wsClientInfo{
+						client:        wsc,
+						subscriptions: make([]string, 0, len(subscriptions)+1),
+					}
+					for _, sub := range subscriptions {
+						if _, ok := sub.clients[quit]; ok {
+							info.subscriptions = append(info.subscriptions,
+								sub.name)
+						}
+					}
+					wsc.Lock()
+					hasFilter := wsc.filterData != nil
+					wsc.Unlock()
+					if hasFilter {
+						info.subscriptions = append(info.subscriptions,
+							"txfilter")
+					}
+					infos = append(infos, info)
+				}
+				n <- infos
+
 			default:
 				log.Warn("Unhandled notification type")
 			}
@@ -636,6 +798,36 @@ func (m *wsNotificationManager) NumClients() int {
 	return n
 }
 
+// Clients returns details about all clients actively being served along with
+// the notifications each of them is subscribed to.
+func (m *wsNotificationManager) Clients() []*wsClientInfo {
+	reply := make(notificationQueryClients, 1)
+	select {
+	case m.queueNotification <- reply:
+	case <-m.quit:
+		return nil
+	}
+
+	select {
+	case infos := <-reply:
+		return infos
+	case <-m.quit:
+		return nil
+	}
+}
+
+// DisconnectClient disconnects the client actively being served with the
+// passed session ID.  It returns whether or not such a client was found.
+func (m *wsNotificationManager) DisconnectClient(sessionID uint64) bool {
+	for _, info := range m.Clients() {
+		if info.client.sessionID == sessionID {
+			info.client.Disconnect()
+			return true
+		}
+	}
+	return false
+}
+
 // RegisterBlockUpdates requests block update notifications to the passed
 // websocket client.
 func (m *wsNotificationManager) RegisterBlockUpdates(wsc *wsClient) {
@@ -648,6 +840,18 @@ func (m *wsNotificationManager) UnregisterBlockUpdates(wsc *wsClient) {
 	m.queueNotification <- (*notificationUnregisterBlocks)(wsc)
 }
 
+// RegisterFilteredBlockUpdates requests filtered block update notifications to
+// the passed websocket client.
+func (m *wsNotificationManager) RegisterFilteredBlockUpdates(wsc *wsClient) {
+	m.queueNotification <- (*notificationRegisterFilteredBlocks)(wsc)
+}
+
+// UnregisterFilteredBlockUpdates removes filtered block update notifications
+// for the passed websocket client.
+func (m *wsNotificationManager) UnregisterFilteredBlockUpdates(wsc *wsClient) {
+	m.queueNotification <- (*notificationUnregisterFilteredBlocks)(wsc)
+}
+
 // RegisterWorkUpdates requests work update notifications to the passed
 // websocket client.
 func (m *wsNotificationManager) RegisterWorkUpdates(wsc *wsClient) {
@@ -738,8 +942,64 @@ func (m *wsNotificationManager) subscribedClients(tx *dcrutil.Tx, clients map[ch
 	return subscribed
 }
 
+// blockStakeEvents returns a summary of the ticket purchases, votes, and
+// revocations in the provided block.  The choices made by each vote are
+// decoded using the agendas the provided parameters define for its version.
+func blockStakeEvents(block *dcrutil.Block, params *chaincfg.Params) *types.BlockStakeEvents {
+	events := &types.BlockStakeEvents{
+		Tickets:     []types.TicketPurchaseEvent{},
+		Votes:       []types.VoteEvent{},
+		Revocations: []types.RevocationEvent{},
+	}
+	for _, stx := range block.STransactions() {
+		tx := stx.MsgTx()
+		switch stake.DetermineTxType(tx) {
+		case stake.TxTypeSStx:
+			events.Tickets = append(events.Tickets, types.TicketPurchaseEvent{
+				TxHash: stx.Hash().String(),
+				Price:  dcrutil.Amount(tx.TxOut[0].Value).ToCoin(),
+			})
+
+		case stake.TxTypeSSGen:
+			voteBits := stake.SSGenVoteBits(tx)
+			vote := types.VoteEvent{
+				TxHash:   stx.Hash().String(),
+				Ticket:   tx.TxIn[1].PreviousOutPoint.Hash.String(),
+				Version:  stake.SSGenVersion(tx),
+				VoteBits: voteBits,
+				Approves: dcrutil.IsFlagSet16(voteBits, dcrutil.BlockValid),
+			}
+			for _, deployment := range params.Deployments[vote.Version] {
+				agenda := &deployment.Vote
+				bits := voteBits & agenda.Mask
+				for _, choice := range agenda.Choices {
+					if choice.Bits == bits {
+						vote.Choices = append(vote.Choices,
+							types.VoteChoiceEvent{
+								AgendaID: agenda.Id,
+								ChoiceID: choice.Id,
+							})
+						break
+					}
+				}
+			}
+			events.Votes = append(events.Votes, vote)
+
+		case stake.TxTypeSSRtx:
+			events.Revocations = append(events.Revocations,
+				types.RevocationEvent{
+					TxHash: stx.Hash().String(),
+					Ticket: tx.TxIn[0].PreviousOutPoint.Hash.String(),
+				})
+		}
+	}
+	return events
+}
+
 // notifyBlockConnected notifies websocket clients that have registered for
-// block updates when a block is connected to the main chain.
+// block updates when a block is connected to the main chain.  Clients that
+// requested stake events also receive a summary of the stake events in the
+// block.
 func (m *wsNotificationManager) notifyBlockConnected(clients map[chan struct{}]*wsClient, block *dcrutil.Block) {
 	// Create the common portion of the notification that is the same for
 	// every client.
@@ -777,11 +1037,24 @@ func (m *wsNotificationManager) notifyBlockConnected(clients map[chan struct{}]*
 		}
 	}
 
+	// The stake events are only calculated once when at least one client has
+	// requested them.
+	var stakeEvents *types.BlockStakeEvents
 	for quitChan, client := range clients {
 		// Add all previously discovered relevant transactions for this client,
 		// if any.
 		ntfn.SubscribedTxs = subscribedTxs[quitChan]
 
+		// Add the stake events when requested by the client.
+		ntfn.StakeEvents = nil
+		if client.stakeEventUpdates {
+			if stakeEvents == nil {
+				stakeEvents = blockStakeEvents(block,
+					m.server.cfg.ChainParams)
+			}
+			ntfn.StakeEvents = stakeEvents
+		}
+
 		// Marshal and queue notification.
 		marshalledJSON, err := dcrjson.MarshalCmd("1.0", nil, &ntfn)
 		if err != nil {
@@ -793,6 +1066,138 @@ func (m *wsNotificationManager) notifyBlockConnected(clients map[chan struct{}]*
 	}
 }
 
+// blockTxProver generates merkle inclusion proofs for the transactions in a
+// block that prove their inclusion in the merkle root the block header commits
+// to.
+type blockTxProver struct {
+	regularLeaves []chainhash.Hash
+	stakeLeaves   []chainhash.Hash
+	regularRoot   chainhash.Hash
+	stakeRoot     chainhash.Hash
+
+	// combined specifies whether the merkle root of the block header is the
+	// combined merkle root of both transaction trees per DCP0005.
+	combined bool
+}
+
+// newBlockTxProver returns a prover for the transactions in the provided block.
+func newBlockTxProver(block *wire.MsgBlock) *blockTxProver {
+	txLeaves := func(txns []*wire.MsgTx) []chainhash.Hash {
+		leaves := make([]chainhash.Hash, 0, len(txns))
+		for _, tx := range txns {
+			leaves = append(leaves, tx.TxHashFull())
+		}
+		return leaves
+	}
+	p := &blockTxProver{
+		regularLeaves: txLeaves(block.Transactions),
+		stakeLeaves:   txLeaves(block.STransactions),
+	}
+	p.regularRoot = standalone.CalcMerkleRoot(p.regularLeaves)
+	p.stakeRoot = standalone.CalcMerkleRoot(p.stakeLeaves)
+
+	// The merkle root of blocks that commit to the combined merkle root differs
+	// from the merkle root of the regular transaction tree.
+	p.combined = block.Header.MerkleRoot != p.regularRoot
+	return p
+}
+
+// proof returns the merkle inclusion proof for the transaction at the provided
+// index of the provided transaction tree along with the leaf index to verify
+// it with.
+//
+// When the block commits to the combined merkle root, the proof is extended
+// with the merkle root of the other transaction tree so it proves inclusion in
+// the combined merkle root.  Otherwise, it proves inclusion in the merkle root
+// of the transaction tree, which is the merkle root of the header for regular
+// transactions and the stake root for stake transactions.
+func (p *blockTxProver) proof(tree int8, index uint32) ([]chainhash.Hash, uint32) {
+	leaves, otherRoot := p.regularLeaves, p.stakeRoot
+	if tree == wire.TxTreeStake {
+		leaves, otherRoot = p.stakeLeaves, p.regularRoot
+	}
+	proof := standalone.GenerateInclusionProof(leaves, index)
+	if !p.combined {
+		return proof, index
+	}
+
+	// The regular and stake tree roots are the left and right leaves of the
+	// combined merkle tree, respectively.
+	proofIndex := index
+	if tree == wire.TxTreeStake {
+		proofIndex |= 1 << uint(len(proof))
+	}
+	return append(proof, otherRoot), proofIndex
+}
+
+// notifyFilteredBlockConnected notifies websocket clients that have registered
+// for filtered block updates when a block is connected to the main chain.  The
+// notification only includes the transactions that match the transaction
+// filter of each client along with merkle inclusion proofs for them.
+func (m *wsNotificationManager) notifyFilteredBlockConnected(clients map[chan struct{}]*wsClient, block *dcrutil.Block) {
+	msgBlock := block.MsgBlock()
+	headerBytes, err := msgBlock.Header.Bytes()
+	if err != nil {
+		// This should never error.  The header is written to an
+		// in-memory expandable buffer, and given that the block was
+		// just accepted, there should be no issues serializing it.
+		panic(err)
+	}
+
+	// Search for relevant transactions for each client while generating the
+	// proof for each of them once regardless of the number of clients it is
+	// relevant to.
+	var prover *blockTxProver
+	matchedTxs := make(map[chan struct{}][]types.FilteredBlockTx)
+	for _, txns := range [][]*dcrutil.Tx{block.STransactions(),
+		block.Transactions()} {
+
+		for _, tx := range txns {
+			subscribed := m.subscribedClients(tx, clients)
+			if len(subscribed) == 0 {
+				continue
+			}
+
+			if prover == nil {
+				prover = newBlockTxProver(msgBlock)
+			}
+			index := uint32(tx.Index())
+			proof, proofIndex := prover.proof(tx.Tree(), index)
+			proofStrs := make([]string, 0, len(proof))
+			for i := range proof {
+				proofStrs = append(proofStrs, proof[i].String())
+			}
+			filteredTx := types.FilteredBlockTx{
+				Transaction: txHexString(tx.MsgTx()),
+				Tree:        tx.Tree(),
+				Index:       index,
+				ProofIndex:  proofIndex,
+				Proof:       proofStrs,
+			}
+			for quitChan := range subscribed {
+				matchedTxs[quitChan] = append(matchedTxs[quitChan],
+					filteredTx)
+			}
+		}
+	}
+
+	for quitChan, client := range clients {
+		transactions := matchedTxs[quitChan]
+		if transactions == nil {
+			transactions = []types.FilteredBlockTx{}
+		}
+		ntfn := types.NewFilteredBlockConnectedNtfn(
+			hex.EncodeToString(headerBytes), transactions)
+		marshalledJSON, err := dcrjson.MarshalCmd("1.0", nil, ntfn)
+		if err != nil {
+			log.Errorf("Failed to marshal filtered block connected "+
+				"notification: %v", err)
+			continue
+		}
+		client.QueueNotification(marshalledJSON)
+	}
+}
+
 // notifyBlockDisconnected notifies websocket clients that have registered for
 // block updates when a block is disconnected from the main chain (due to a
 // reorganize).
@@ -856,10 +1261,10 @@ func (m *wsNotificationManager) notifyWork(clients map[chan struct{}]*wsClient,
 	// retuned as part of the data below.
 	//
 	// For reference (0-index based, end value is exclusive):
-	// data[115:119] --> Bits
-	// data[135:139] --> Timestamp
-	// data[139:143] --> Nonce
-	// data[143:151] --> ExtraNonce
+	// data[116:120] --> Bits
+	// data[136:140] --> Timestamp
+	// data[140:144] --> Nonce
+	// data[144:152] --> ExtraNonce
 	header := &templateNtfn.Template.Block.Header
 	data := make([]byte, 0, getworkDataLen)
 	buf := bytes.NewBuffer(data)
@@ -978,6 +1383,42 @@ func (*wsNotificationManager) notifyWinningTickets(
 	}
 }
 
+// RegisterDiskSpace requests disk space update notifications to the passed
+// websocket client.
+func (m *wsNotificationManager) RegisterDiskSpace(wsc *wsClient) {
+	m.queueNotification <- (*notificationRegisterDiskSpace)(wsc)
+}
+
+// UnregisterDiskSpace removes disk space notifications for the passed websocket
+// client.
+func (m *wsNotificationManager) UnregisterDiskSpace(wsc *wsClient) {
+	m.queueNotification <- (*notificationUnregisterDiskSpace)(wsc)
+}
+
+// notifyDiskSpace notifies websocket clients that have registered for disk
+// space updates.
+func (*wsNotificationManager) notifyDiskSpace(clients map[chan struct{}]*wsClient,
+	status *DiskSpaceStatus) {
+
+	// Skip notification creation if no clients have requested disk space
+	// notifications.
+	if len(clients) == 0 {
+		return
+	}
+
+	ntfn := types.NewDiskSpaceNtfn(status.Level, status.Free,
+		status.WarnThreshold, status.StopThreshold)
+	marshalledJSON, err := dcrjson.MarshalCmd("1.0", nil, ntfn)
+	if err != nil {
+		log.Errorf("Failed to marshal disk space notification: %v", err)
+		return
+	}
+
+	for _, wsc := range clients {
+		wsc.QueueNotification(marshalledJSON)
+	}
+}
+
 // RegisterSpentAndMissedTickets requests spent/missed tickets update notifications
 // to the passed websocket client.
 func (m *wsNotificationManager) RegisterSpentAndMissedTickets(wsc *wsClient) {
@@ -1067,8 +1508,10 @@ func (*wsNotificationManager) notifyNewTickets(clients map[chan struct{}]*wsClie
 }
 
 // notifyStakeDifficulty notifies websocket clients that have registered for
-// maturing ticket updates.
-func (*wsNotificationManager) notifyStakeDifficulty(clients map[chan struct{}]*wsClient, sdnd *StakeDifficultyNtfnData) {
+// stake difficulty updates.  Clients are additionally sent a
+// stakedifficultychange notification when the stake difficulty of the next
+// block is the result of a retarget.
+func (m *wsNotificationManager) notifyStakeDifficulty(clients map[chan struct{}]*wsClient, sdnd *StakeDifficultyNtfnData) {
 	// Notify interested websocket clients about the connected block.
 	ntfn := types.NewStakeDifficultyNtfn(sdnd.BlockHash.String(),
 		int32(sdnd.BlockHeight),
@@ -1083,6 +1526,44 @@ func (*wsNotificationManager) notifyStakeDifficulty(clients map[chan struct{}]*w
 	for _, wsc := range clients {
 		wsc.QueueNotification(marshalledJSON)
 	}
+
+	m.notifyStakeDifficultyChange(clients, sdnd)
+}
+
+// notifyStakeDifficultyChange notifies websocket clients that have registered
+// for stake difficulty updates when the passed data is for the final block of a
+// stake difficulty window, meaning the next block is the first to require the
+// newly calculated stake difficulty.
+func (m *wsNotificationManager) notifyStakeDifficultyChange(clients map[chan struct{}]*wsClient, sdnd *StakeDifficultyNtfnData) {
+	// Nothing to do when there are no clients or the next block does not
+	// begin a new stake difficulty window.
+	windowSize := m.server.cfg.ChainParams.StakeDiffWindowSize
+	nextHeight := sdnd.BlockHeight + 1
+	if len(clients) == 0 || nextHeight%windowSize != 0 {
+		return
+	}
+
+	// The stake difficulty required by the block itself is the one being
+	// replaced.
+	header, err := m.server.cfg.Chain.HeaderByHash(&sdnd.BlockHash)
+	if err != nil {
+		log.Errorf("Failed to fetch header for block %v: %v",
+			sdnd.BlockHash, err)
+		return
+	}
+
+	ntfn := types.NewStakeDifficultyChangeNtfn(sdnd.BlockHash.String(),
+		int32(sdnd.BlockHeight), header.SBits, sdnd.StakeDifficulty,
+		int32(nextHeight+windowSize))
+	marshalledJSON, err := dcrjson.MarshalCmd("1.0", nil, ntfn)
+	if err != nil {
+		log.Errorf("Failed to marshal stake difficulty change "+
+			"notification: %v", err)
+		return
+	}
+	for _, wsc := range clients {
+		wsc.QueueNotification(marshalledJSON)
+	}
 }
 
 // RegisterNewMempoolTxsUpdates requests notifications to the passed websocket
@@ -1160,9 +1641,11 @@ func txHexString(tx *wire.MsgTx) string {
 // transaction, notifying websocket clients of outputs spending to a watched
 // address and inputs spending a watched outpoint.  Any outputs paying to a
 // watched address result in the output being watched as well for future
-// notifications.
+// notifications.  Transactions that are not new were returned to the mempool
+// from a block disconnected from the main chain, so clients are instead
+// notified that they are unmined again.
 func (m *wsNotificationManager) notifyRelevantTxAccepted(tx *dcrutil.Tx,
-	clients map[chan struct{}]*wsClient) {
+	isNew bool, clients map[chan struct{}]*wsClient) {
 
 	var clientsToNotify map[chan struct{}]*wsClient
 
@@ -1212,7 +1695,11 @@ func (m *wsNotificationManager) notifyRelevantTxAccepted(tx *dcrutil.Tx,
 	}
 
 	if len(clientsToNotify) != 0 {
-		n := types.NewRelevantTxAcceptedNtfn(txHexString(msgTx))
+		txHex := txHexString(msgTx)
+		var n interface{} = types.NewRelevantTxAcceptedNtfn(txHex)
+		if !isNew {
+			n = types.NewRelevantTxUnminedNtfn(txHex)
+		}
 		marshalled, err := dcrjson.MarshalCmd("1.0", nil, n)
 		if err != nil {
 			log.Errorf("Failed to marshal notification: %v", err)
@@ -1306,6 +1793,9 @@ type wsClient struct {
 
 	// isAdmin specifies whether a client may change the state of the server;
 	// false means its access is only to the limited set of RPC calls.
+	//
+	// Both authenticated and isAdmin are only modified by the input handler
+	// while holding the mutex, so other goroutines must hold it to read them.
 	isAdmin bool
 
 	// sessionID is a random ID generated for each client when connected.
@@ -1313,10 +1803,22 @@ type wsClient struct {
 	// to the session ID indicates that the client reconnected.
 	sessionID uint64
 
+	// connTime is the time the client connected.
+	connTime time.Time
+
+	// name and version identify the software of the client.  They are
+	// optionally provided by the client via the registerclient RPC.
+	name    string
+	version string
+
 	// verboseTxUpdates specifies whether a client has requested verbose
 	// information about all new transactions.
 	verboseTxUpdates bool
 
+	// stakeEventUpdates specifies whether a client has requested a summary
+	// of the stake events in connected blocks.
+	stakeEventUpdates bool
+
 	filterData *wsClientFilter
 
 	// Networking infrastructure.
@@ -1447,8 +1949,10 @@ out:
 					log.Warnf("Auth failure.")
 					break out
 				}
+				c.Lock()
 				c.authenticated = true
 				c.isAdmin = cmp == 1
+				c.Unlock()
 
 				// Marshal and send response.
 				reply, err = createMarshalledReply(cmd.jsonrpc, cmd.id, nil, nil)
@@ -1469,6 +1973,8 @@ out:
 						Code:    dcrjson.ErrRPCInvalidParams.Code,
 						Message: "limited user not authorized for this method",
 					}
+					c.audit(req.Method, req.Params, nil, jsonErr)
+
 					// Marshal and send response.
 					reply, err = createMarshalledReply("", req.ID, nil, jsonErr)
 					if err != nil {
@@ -1660,8 +2166,10 @@ out:
 								break out
 							}
 
+							c.Lock()
 							c.authenticated = true
 							c.isAdmin = cmp == 1
+							c.Unlock()
 
 							// Marshal and send response.
 							reply, err = createMarshalledReply(cmd.jsonrpc, cmd.id, nil, nil)
@@ -1685,6 +2193,8 @@ out:
 									Code:    dcrjson.ErrRPCInvalidParams.Code,
 									Message: "limited user not authorized for this method",
 								}
+								c.audit(req.Method, req.Params, nil, jsonErr)
+
 								// Marshal and send response.
 								reply, err = createMarshalledReply(req.Jsonrpc, req.ID, nil, jsonErr)
 								if err != nil {
@@ -1707,9 +2217,10 @@ out:
 						if ok {
 							resp, err = wsHandler(c, cmd.params)
 						} else {
-							resp, err = c.rpcServer.standardCmdResult(ctx,
-								cmd)
+							resp, err = c.rpcServer.standardCmdResult(
+								c.cmdContext(ctx), cmd)
 						}
+						c.audit(string(cmd.method), cmd.rawParams, resp, err)
 
 						// Marshal request output.
 						reply, err := createMarshalledReply(cmd.jsonrpc, cmd.id, resp, err)
@@ -1764,6 +2275,26 @@ out:
 	log.Tracef("Websocket client input handler done for %s", c.addr)
 }
 
+// audit records the passed RPC issued by the websocket client along with its
+// outcome to the audit log of the RPC server.
+func (c *wsClient) audit(method string, params []json.RawMessage, result interface{}, err error) {
+	c.Lock()
+	isAdmin := c.isAdmin
+	c.Unlock()
+
+	c.rpcServer.auditRPC(method, params, isAdmin, c.addr, result, err)
+}
+
+// cmdContext returns a copy of the provided context that records the address
+// and credentials of the client for use by standard command handlers.
+func (c *wsClient) cmdContext(ctx context.Context) context.Context {
+	c.Lock()
+	isAdmin := c.isAdmin
+	c.Unlock()
+
+	return withClientAdmin(withClientAddr(ctx, c.addr), isAdmin)
+}
+
 // serviceRequest services a parsed RPC request by looking up and executing the
 // appropriate RPC handler.  The response is marshalled and sent to the websocket
 // client.
@@ -1779,8 +2310,9 @@ func (c *wsClient) serviceRequest(ctx context.Context, r *parsedRPCCmd) {
 	if ok {
 		result, err = wsHandler(c, r.params)
 	} else {
-		result, err = c.rpcServer.standardCmdResult(ctx, r)
+		result, err = c.rpcServer.standardCmdResult(c.cmdContext(ctx), r)
 	}
+	c.audit(string(r.method), r.rawParams, result, err)
 	reply, err := createMarshalledReply(r.jsonrpc, r.id, result, err)
 	if err != nil {
 		log.Errorf("Failed to marshal reply for <%s> "+
@@ -2024,6 +2556,7 @@ func newWebsocketClient(server *Server, conn *websocket.Conn,
 		authenticated:     authenticated,
 		isAdmin:           isAdmin,
 		sessionID:         sessionID,
+		connTime:          time.Now(),
 		rpcServer:         server,
 		serviceRequestSem: makeSemaphore(server.cfg.RPCMaxConcurrentReqs),
 		ntfnChan:          make(chan []byte, 1), // nonblocking sync
@@ -2124,10 +2657,23 @@ func handleLoadTxFilter(wsc *wsClient, icmd interface{}) (interface{}, error) {
 // handleNotifyBlocks implements the notifyblocks command extension for
 // websocket connections.
 func handleNotifyBlocks(wsc *wsClient, icmd interface{}) (interface{}, error) {
+	cmd, ok := icmd.(*types.NotifyBlocksCmd)
+	if !ok {
+		return nil, dcrjson.ErrRPCInternal
+	}
+
+	wsc.stakeEventUpdates = cmd.StakeEvents != nil && *cmd.StakeEvents
 	wsc.rpcServer.ntfnMgr.RegisterBlockUpdates(wsc)
 	return nil, nil
 }
 
+// handleNotifyFilteredBlocks implements the notifyfilteredblocks command
+// extension for websocket connections.
+func handleNotifyFilteredBlocks(wsc *wsClient, icmd interface{}) (interface{}, error) {
+	wsc.rpcServer.ntfnMgr.RegisterFilteredBlockUpdates(wsc)
+	return nil, nil
+}
+
 // handleRebroadcastMissed implements the rebroadcastmissed command.
 func handleRebroadcastMissed(wsc *wsClient, icmd interface{}) (interface{}, error) {
 	cfg := wsc.rpcServer.cfg
@@ -2193,6 +2739,40 @@ func handleNotifyWork(wsc *wsClient, icmd interface{}) (interface{}, error) {
 	return nil, nil
 }
 
+// handleRegisterClient implements the registerclient command extension for
+// websocket connections.
+func handleRegisterClient(wsc *wsClient, icmd interface{}) (interface{}, error) {
+	cmd, ok := icmd.(*types.RegisterClientCmd)
+	if !ok {
+		return nil, dcrjson.ErrRPCInternal
+	}
+
+	var version string
+	if cmd.Version != nil {
+		version = *cmd.Version
+	}
+	if cmd.Name == "" {
+		return nil, rpcInvalidError("Client name must not be empty")
+	}
+	if len(cmd.Name) > maxClientNameLen {
+		return nil, rpcInvalidError("Client name exceeds the maximum "+
+			"length of %d bytes", maxClientNameLen)
+	}
+	if len(version) > maxClientNameLen {
+		return nil, rpcInvalidError("Client version exceeds the maximum "+
+			"length of %d bytes", maxClientNameLen)
+	}
+
+	wsc.Lock()
+	wsc.name = cmd.Name
+	wsc.version = version
+	wsc.Unlock()
+
+	log.Debugf("Websocket client %s (session %d) registered as %q "+
+		"version %q", wsc.addr, wsc.sessionID, cmd.Name, version)
+	return nil, nil
+}
+
 // handleSession implements the session command extension for websocket
 // connections.
 func handleSession(wsc *wsClient, icmd interface{}) (interface{}, error) {
@@ -2206,6 +2786,13 @@ func handleWinningTickets(wsc *wsClient, icmd interface{}) (interface{}, error)
 	return nil, nil
 }
 
+// handleNotifyDiskSpace implements the notifydiskspace command extension for
+// websocket connections.
+func handleNotifyDiskSpace(wsc *wsClient, icmd interface{}) (interface{}, error) {
+	wsc.rpcServer.ntfnMgr.RegisterDiskSpace(wsc)
+	return nil, nil
+}
+
 // handleSpentAndMissedTickets implements the notifyspentandmissedtickets command
 // extension for websocket connections.
 func handleSpentAndMissedTickets(wsc *wsClient, icmd interface{}) (interface{}, error) {
@@ -2234,6 +2821,13 @@ func handleStopNotifyBlocks(wsc *wsClient, icmd interface{}) (interface{}, error
 	return nil, nil
 }
 
+// handleStopNotifyFilteredBlocks implements the stopnotifyfilteredblocks
+// command extension for websocket connections.
+func handleStopNotifyFilteredBlocks(wsc *wsClient, icmd interface{}) (interface{}, error) {
+	wsc.rpcServer.ntfnMgr.UnregisterFilteredBlockUpdates(wsc)
+	return nil, nil
+}
+
 // handleStopNotifyWork implements the stopnotifywork command extension for
 // websocket connections.
 func handleStopNotifyWork(wsc *wsClient, icmd interface{}) (interface{}, error) {
@@ -2343,6 +2937,138 @@ func rescanBlock(filter *wsClientFilter, block *dcrutil.Block, params *chaincfg.
 	return transactions
 }
 
+// indexedRescanBlocks uses the exists address and address indexes, when they
+// are available, to determine which main chain blocks may contain transactions
+// that are relevant to the provided filter so a rescan only needs to load and
+// examine those blocks.
+//
+// The address index includes both the outputs that pay to an address and the
+// inputs that spend them, so the returned blocks include every main chain block
+// with a relevant transaction, including those that spend outputs discovered
+// during the rescan.  The addresses of the unspent outputs in the filter are
+// determined via the transaction index.
+//
+// A nil map is returned when the indexes are not able to determine the
+// relevant blocks, in which case every block must be scanned.
+func indexedRescanBlocks(s *Server, filter *wsClientFilter) map[chainhash.Hash]struct{} {
+	filter.mu.Lock()
+	addrs := filter.addresses()
+	outPoints := make([]wire.OutPoint, 0, len(filter.unspent))
+	for op := range filter.unspent {
+		outPoints = append(outPoints, op)
+	}
+	filter.mu.Unlock()
+
+	// Discard the addresses that have never been seen when the exists
+	// address index is available since they are not relevant to any blocks.
+	cfg := &s.cfg
+	if cfg.ExistsAddresser != nil && len(addrs) > 0 {
+		exists, err := cfg.ExistsAddresser.ExistsAddresses(addrs)
+		if err != nil || len(exists) != len(addrs) {
+			log.Debugf("Unable to determine seen rescan addresses: %v", err)
+		} else {
+			seen := addrs[:0]
+			for i, addr := range addrs {
+				if exists[i] {
+					seen = append(seen, addr)
+				}
+			}
+			addrs = seen
+		}
+	}
+
+	// There are no relevant blocks when there are no addresses or unspent
+	// outputs to look for.
+	if len(addrs) == 0 && len(outPoints) == 0 {
+		return make(map[chainhash.Hash]struct{})
+	}
+	if cfg.AddrIndexer == nil {
+		return nil
+	}
+	if len(outPoints) > 0 && cfg.TxIndexer == nil {
+		log.Debugf("Scanning all blocks for rescan since the transaction " +
+			"index is required to look up the unspent outputs in the filter")
+		return nil
+	}
+
+	// Look up the locations of the transactions that created the unspent
+	// outputs in the filter.
+	regions := make([]database.BlockRegion, 0, len(outPoints))
+	for i := range outPoints {
+		entry, err := cfg.TxIndexer.Entry(&outPoints[i].Hash)
+		if err != nil || entry == nil {
+			log.Debugf("Scanning all blocks for rescan since transaction "+
+				"%v is not in the transaction index", outPoints[i].Hash)
+			return nil
+		}
+		regions = append(regions, entry.BlockRegion)
+	}
+
+	blocks := make(map[chainhash.Hash]struct{})
+	err := cfg.DB.View(func(dbTx database.Tx) error {
+		// Add the addresses the unspent outputs in the filter pay to.  The
+		// spends of outputs that do not pay to an address are not in the
+		// address index, so every block must be scanned in that case.
+		if len(regions) > 0 {
+			serializedTxns, err := dbTx.FetchBlockRegions(regions)
+			if err != nil {
+				return err
+			}
+			for i, serializedTx := range serializedTxns {
+				var tx wire.MsgTx
+				err := tx.Deserialize(bytes.NewReader(serializedTx))
+				if err != nil {
+					return err
+				}
+				op := &outPoints[i]
+				if op.Index >= uint32(len(tx.TxOut)) {
+					return fmt.Errorf("output %v does not exist", op)
+				}
+				txOut := tx.TxOut[op.Index]
+				_, opAddrs, _, err := txscript.ExtractPkScriptAddrs(
+					txOut.Version, txOut.PkScript, cfg.ChainParams)
+				if err != nil || len(opAddrs) == 0 {
+					return fmt.Errorf("output %v does not pay to an "+
+						"address", op)
+				}
+				addrs = append(addrs, opAddrs...)
+			}
+		}
+
+		// Add the blocks of all transactions that involve the addresses.
+		queried := make(map[string]struct{}, len(addrs))
+		for _, addr := range addrs {
+			if _, ok := queried[addr.Address()]; ok {
+				continue
+			}
+			queried[addr.Address()] = struct{}{}
+
+			var numToSkip uint32
+			for {
+				entries, _, err := cfg.AddrIndexer.EntriesForAddress(dbTx,
+					addr, numToSkip, rescanIndexBatchSize, false)
+				if err != nil {
+					return err
+				}
+				for i := range entries {
+					blocks[*entries[i].BlockRegion.Hash] = struct{}{}
+				}
+				if len(entries) < rescanIndexBatchSize {
+					break
+				}
+				numToSkip += uint32(len(entries))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Debugf("Scanning all blocks for rescan since the relevant blocks "+
+			"could not be determined from the address index: %v", err)
+		return nil
+	}
+	return blocks
+}
+
 // handleRescan implements the rescan command extension for websocket
 // connections.
 func handleRescan(wsc *wsClient, icmd interface{}) (interface{}, error) {
@@ -2369,20 +3095,25 @@ func handleRescan(wsc *wsClient, icmd interface{}) (interface{}, error) {
 
 	discoveredData := make([]types.RescannedBlock, 0, len(blockHashes))
 
+	// Determine which blocks may contain relevant transactions from the
+	// indexes when they are available so the other main chain blocks do not
+	// need to be loaded and scanned.
+	relevantBlocks := indexedRescanBlocks(wsc.rpcServer, filter)
+
 	// Iterate over each block in the request and rescan.  When a block
 	// contains relevant transactions, add it to the response.
 	cfg := wsc.rpcServer.cfg
 	bc := cfg.Chain
 	var lastBlockHash *chainhash.Hash
 	for i := range blockHashes {
-		block, err := bc.BlockByHash(&blockHashes[i])
+		header, err := bc.HeaderByHash(&blockHashes[i])
 		if err != nil {
 			return nil, &dcrjson.RPCError{
 				Code:    dcrjson.ErrRPCBlockNotFound,
 				Message: "Failed to fetch block: " + err.Error(),
 			}
 		}
-		if lastBlockHash != nil && block.MsgBlock().Header.PrevBlock != *lastBlockHash {
+		if lastBlockHash != nil && header.PrevBlock != *lastBlockHash {
 			return nil, &dcrjson.RPCError{
 				Code: dcrjson.ErrRPCInvalidParameter,
 				Message: fmt.Sprintf("Block %v is not a child of %v",
@@ -2391,6 +3122,19 @@ func handleRescan(wsc *wsClient, icmd interface{}) (interface{}, error) {
 		}
 		lastBlockHash = &blockHashes[i]
 
+		if relevantBlocks != nil && bc.MainChainHasBlock(&blockHashes[i]) {
+			if _, ok := relevantBlocks[blockHashes[i]]; !ok {
+				continue
+			}
+		}
+
+		block, err := bc.BlockByHash(&blockHashes[i])
+		if err != nil {
+			return nil, &dcrjson.RPCError{
+				Code:    dcrjson.ErrRPCBlockNotFound,
+				Message: "Failed to fetch block: " + err.Error(),
+			}
+		}
 		transactions := rescanBlock(filter, block, cfg.ChainParams)
 		if len(transactions) != 0 {
 			discoveredData = append(discoveredData, types.RescannedBlock{
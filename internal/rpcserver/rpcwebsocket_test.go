@@ -0,0 +1,354 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/decred/dcrd/blockchain/v3/indexers"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/database/v2"
+	"github.com/decred/dcrd/dcrec"
+	"github.com/decred/dcrd/dcrjson/v3"
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/decred/dcrd/rpc/jsonrpc/types/v2"
+	"github.com/decred/dcrd/txscript/v3"
+	"github.com/decred/dcrd/wire"
+)
+
+// TestIndexedRescanBlocks ensures the blocks a rescan must examine are
+// determined from the address related indexes when they are available and
+// that every block is scanned when they are not able to determine them.
+func TestIndexedRescanBlocks(t *testing.T) {
+	t.Parallel()
+
+	params := chaincfg.MainNetParams()
+	addr, err := dcrutil.NewAddressPubKeyHash(make([]byte, 20), params,
+		dcrec.STEcdsaSecp256k1)
+	if err != nil {
+		t.Fatalf("unexpected address error: %v", err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatalf("unexpected script error: %v", err)
+	}
+	nullData, err := txscript.GenerateProvablyPruneableOut([]byte{0x01})
+	if err != nil {
+		t.Fatalf("unexpected script error: %v", err)
+	}
+
+	// Create serialized transactions that have an output that pays to the
+	// address and a null data output.
+	serializeTx := func(pkScript []byte) []byte {
+		tx := wire.NewMsgTx()
+		tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, 0, nil))
+		tx.AddTxOut(wire.NewTxOut(1e8, pkScript))
+		var buf bytes.Buffer
+		if err := tx.Serialize(&buf); err != nil {
+			t.Fatalf("unexpected serialize error: %v", err)
+		}
+		return buf.Bytes()
+	}
+	payToAddrTx := serializeTx(pkScript)
+	nullDataTx := serializeTx(nullData)
+
+	block1 := chainhash.Hash{0x01}
+	block2 := chainhash.Hash{0x02}
+	entries := []indexers.TxIndexEntry{
+		{BlockRegion: database.BlockRegion{Hash: &block1}},
+		{BlockRegion: database.BlockRegion{Hash: &block2}},
+		{BlockRegion: database.BlockRegion{Hash: &block2}},
+	}
+	txIndexer := &testTxIndexer{
+		entry: func(hash *chainhash.Hash) (*indexers.TxIndexEntry, error) {
+			return &indexers.TxIndexEntry{
+				BlockRegion: database.BlockRegion{Hash: &block1},
+			}, nil
+		},
+	}
+	db := func(serializedTx []byte) *testDB {
+		return &testDB{viewTx: &testDatabaseTx{
+			fetchBlockRegions: func(regions []database.BlockRegion) ([][]byte, error) {
+				txns := make([][]byte, 0, len(regions))
+				for range regions {
+					txns = append(txns, serializedTx)
+				}
+				return txns, nil
+			},
+		}}
+	}
+	outPoint := &wire.OutPoint{Hash: chainhash.Hash{0x03}}
+
+	tests := []struct {
+		name            string
+		addrs           []string
+		outPoints       []*wire.OutPoint
+		existsAddresser ExistsAddresser
+		addrIndexer     AddrIndexer
+		txIndexer       TxIndexer
+		db              database.DB
+		want            []chainhash.Hash // nil means scan all blocks
+	}{{
+		name: "empty filter",
+		want: []chainhash.Hash{},
+	}, {
+		name:  "no seen addresses",
+		addrs: []string{addr.Address()},
+		existsAddresser: &testExistsAddresser{
+			existsAddresses: []bool{false, false, false},
+		},
+		want: []chainhash.Hash{},
+	}, {
+		name:            "no address index",
+		addrs:           []string{addr.Address()},
+		existsAddresser: &testExistsAddresser{},
+	}, {
+		name:            "address index entries",
+		addrs:           []string{addr.Address()},
+		existsAddresser: &testExistsAddresser{},
+		addrIndexer:     &testAddrIndexer{entriesForAddress: entries},
+		db:              db(nil),
+		want:            []chainhash.Hash{block1, block2},
+	}, {
+		name:            "address index error",
+		addrs:           []string{addr.Address()},
+		existsAddresser: &testExistsAddresser{},
+		addrIndexer: &testAddrIndexer{
+			entriesForAddressErr: errors.New("address index error"),
+		},
+		db: db(nil),
+	}, {
+		name:        "unspent output without transaction index",
+		outPoints:   []*wire.OutPoint{outPoint},
+		addrIndexer: &testAddrIndexer{entriesForAddress: entries},
+	}, {
+		name:        "unspent output pays to address",
+		outPoints:   []*wire.OutPoint{outPoint},
+		addrIndexer: &testAddrIndexer{entriesForAddress: entries[:1]},
+		txIndexer:   txIndexer,
+		db:          db(payToAddrTx),
+		want:        []chainhash.Hash{block1},
+	}, {
+		name:        "unspent output does not pay to address",
+		outPoints:   []*wire.OutPoint{outPoint},
+		addrIndexer: &testAddrIndexer{entriesForAddress: entries},
+		txIndexer:   txIndexer,
+		db:          db(nullDataTx),
+	}}
+
+	for _, test := range tests {
+		s := &Server{cfg: *defaultMockConfig(params)}
+		s.cfg.ExistsAddresser = test.existsAddresser
+		s.cfg.AddrIndexer = test.addrIndexer
+		s.cfg.TxIndexer = test.txIndexer
+		s.cfg.DB = test.db
+		filter := makeWSClientFilter(test.addrs, test.outPoints, params)
+
+		got := indexedRescanBlocks(s, filter)
+		if test.want == nil {
+			if got != nil {
+				t.Errorf("%q: unexpected blocks -- got %v, want all blocks",
+					test.name, got)
+			}
+			continue
+		}
+		if got == nil {
+			t.Errorf("%q: unexpectedly scanning all blocks", test.name)
+			continue
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("%q: unexpected number of blocks -- got %d, want %d",
+				test.name, len(got), len(test.want))
+			continue
+		}
+		for _, hash := range test.want {
+			if _, ok := got[hash]; !ok {
+				t.Errorf("%q: block %v is missing", test.name, hash)
+			}
+		}
+	}
+}
+
+// TestNotifyRelevantTxAccepted ensures websocket clients with a transaction
+// filter that matches a transaction accepted to the mempool are notified that
+// it was accepted when it is new and that it is unmined again when it returned
+// to the mempool from a disconnected block.
+func TestNotifyRelevantTxAccepted(t *testing.T) {
+	t.Parallel()
+
+	params := chaincfg.MainNetParams()
+	addr, err := dcrutil.NewAddressPubKeyHash(make([]byte, 20), params,
+		dcrec.STEcdsaSecp256k1)
+	if err != nil {
+		t.Fatalf("unexpected address error: %v", err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatalf("unexpected script error: %v", err)
+	}
+	msgTx := wire.NewMsgTx()
+	msgTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, 0, nil))
+	msgTx.AddTxOut(wire.NewTxOut(1e8, pkScript))
+	tx := dcrutil.NewTx(msgTx)
+
+	m := &wsNotificationManager{server: &Server{cfg: *defaultMockConfig(params)}}
+	newClient := func(addrs []string) *wsClient {
+		return &wsClient{
+			filterData: makeWSClientFilter(addrs, nil, params),
+			ntfnChan:   make(chan []byte, 1),
+			quit:       make(chan struct{}),
+		}
+	}
+	watching := newClient([]string{addr.Address()})
+	notWatching := newClient(nil)
+	clients := map[chan struct{}]*wsClient{
+		watching.quit:    watching,
+		notWatching.quit: notWatching,
+	}
+
+	tests := []struct {
+		name       string
+		isNew      bool
+		wantMethod string
+	}{{
+		name:       "new transaction",
+		isNew:      true,
+		wantMethod: "relevanttxaccepted",
+	}, {
+		name:       "transaction from disconnected block",
+		isNew:      false,
+		wantMethod: "relevanttxunmined",
+	}}
+
+	for _, test := range tests {
+		m.notifyRelevantTxAccepted(tx, test.isNew, clients)
+
+		select {
+		case marshalled := <-watching.ntfnChan:
+			var ntfn dcrjson.Request
+			if err := json.Unmarshal(marshalled, &ntfn); err != nil {
+				t.Fatalf("%q: unexpected unmarshal error: %v",
+					test.name, err)
+			}
+			if ntfn.Method != test.wantMethod {
+				t.Fatalf("%q: unexpected notification -- got %s, want %s",
+					test.name, ntfn.Method, test.wantMethod)
+			}
+			var txHex string
+			if len(ntfn.Params) != 1 ||
+				json.Unmarshal(ntfn.Params[0], &txHex) != nil ||
+				txHex != txHexString(msgTx) {
+
+				t.Fatalf("%q: unexpected params %s", test.name,
+					ntfn.Params)
+			}
+		default:
+			t.Fatalf("%q: watching client was not notified", test.name)
+		}
+		select {
+		case <-notWatching.ntfnChan:
+			t.Fatalf("%q: client without a matching filter was "+
+				"notified", test.name)
+		default:
+		}
+	}
+}
+
+// TestNotifyStakeDifficultyChange ensures clients registered for stake
+// difficulty updates are additionally notified of the retarget once the final
+// block of a stake difficulty window is connected and only then.
+func TestNotifyStakeDifficultyChange(t *testing.T) {
+	t.Parallel()
+
+	params := chaincfg.MainNetParams()
+	cfg := defaultMockConfig(params)
+	cfg.Chain = &testRPCChain{headerByHash: wire.BlockHeader{SBits: 100}}
+	m := &wsNotificationManager{server: &Server{cfg: *cfg}}
+	wsc := &wsClient{
+		ntfnChan: make(chan []byte, 2),
+		quit:     make(chan struct{}),
+	}
+	clients := map[chan struct{}]*wsClient{wsc.quit: wsc}
+
+	windowSize := params.StakeDiffWindowSize
+	blockHash := chainhash.Hash{0x01}
+	tests := []struct {
+		name       string
+		height     int64
+		wantChange bool
+	}{{
+		name:       "final block of window",
+		height:     windowSize - 1,
+		wantChange: true,
+	}, {
+		name:       "first block of window",
+		height:     windowSize,
+		wantChange: false,
+	}, {
+		name:       "middle of window",
+		height:     windowSize + windowSize/2,
+		wantChange: false,
+	}, {
+		name:       "final block of later window",
+		height:     3*windowSize - 1,
+		wantChange: true,
+	}}
+
+	for _, test := range tests {
+		m.notifyStakeDifficulty(clients, &StakeDifficultyNtfnData{
+			BlockHash:       blockHash,
+			BlockHeight:     test.height,
+			StakeDifficulty: 200,
+		})
+
+		// The stake difficulty notification is always sent first.
+		select {
+		case marshalled := <-wsc.ntfnChan:
+			var ntfn dcrjson.Request
+			if err := json.Unmarshal(marshalled, &ntfn); err != nil {
+				t.Fatalf("%q: unexpected unmarshal error: %v",
+					test.name, err)
+			}
+			if ntfn.Method != "stakedifficulty" {
+				t.Fatalf("%q: unexpected notification -- got %s, want %s",
+					test.name, ntfn.Method, "stakedifficulty")
+			}
+		default:
+			t.Fatalf("%q: client was not notified", test.name)
+		}
+
+		if !test.wantChange {
+			select {
+			case marshalled := <-wsc.ntfnChan:
+				t.Fatalf("%q: unexpected notification %s", test.name,
+					marshalled)
+			default:
+			}
+			continue
+		}
+
+		want, err := dcrjson.MarshalCmd("1.0", nil,
+			types.NewStakeDifficultyChangeNtfn(blockHash.String(),
+				int32(test.height), 100, 200,
+				int32(test.height+1+windowSize)))
+		if err != nil {
+			t.Fatalf("%q: unexpected marshal error: %v", test.name, err)
+		}
+		select {
+		case marshalled := <-wsc.ntfnChan:
+			if !bytes.Equal(marshalled, want) {
+				t.Fatalf("%q: unexpected notification -- got %s, want %s",
+					test.name, marshalled, want)
+			}
+		default:
+			t.Fatalf("%q: client was not notified of the stake "+
+				"difficulty change", test.name)
+		}
+	}
+}
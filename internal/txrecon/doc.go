@@ -0,0 +1,42 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package txrecon implements set reconciliation of transaction inventory between
+peers.
+
+Flooding announces every transaction to every peer, so most announcements a
+node sends or receives are for transactions the other side already knows.
+Reconciliation instead has each side of a connection accumulate the
+transactions it would have announced to the other side and periodically
+reconcile the two sets so that only the transactions missing from either side
+are announced.
+
+Protocol Overview
+
+A reconciliation round is always started by the outbound side of a connection,
+referred to as the initiator, and proceeds as follows:
+
+  1. The initiator sends a reqrecon message containing the size of its set and
+     a random salt used to derive 32-bit short ids for the round
+  2. The responder replies with a reconsketch message containing a sketch of
+     the short ids of its set sized according to the estimated difference
+     between both sets
+  3. The initiator subtracts a sketch of its own set and decodes the result
+     which yields the short ids that are only known to one of the sides
+  4. The initiator replies with a recondiff message requesting the short ids it
+     is missing and announces the transactions the responder is missing
+  5. The responder announces the requested transactions
+
+When the difference is larger than the capacity of the sketch, decoding fails
+and both sides fall back to announcing their entire set.
+
+Sketches
+
+Sketches are implemented as invertible bloom lookup tables, which may be
+subtracted from one another such that entries common to both tables cancel
+out.  The remaining entries can be recovered as long as their number is
+sufficiently small relative to the number of cells in the table.
+*/
+package txrecon
@@ -0,0 +1,393 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txrecon
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"sync"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/wire"
+)
+
+const (
+	// MaxSetSize is the maximum number of transactions that may be pending
+	// reconciliation with a single peer.  Transactions beyond this limit
+	// must be announced via flooding instead.
+	MaxSetSize = 10000
+
+	// maxSketchCapacity is the maximum capacity of a sketch such that its
+	// serialized form fits in a reconsketch message.
+	maxSketchCapacity = (wire.MaxReconSketchSize/sketchCellSize -
+		numSketchHashes*3) * 2 / 3
+
+	// reqReconPayloadSize is the serialized size of a reqrecon message.
+	reqReconPayloadSize = 12
+
+	// invVectSize is the serialized size of an inventory vector which is
+	// used to estimate the number of bytes an announcement via flooding
+	// requires.
+	invVectSize = 4 + chainhash.HashSize
+)
+
+// ErrUnexpectedMessage is returned when a reconciliation message is received
+// that is not valid for the current state of the reconciliation round.
+var ErrUnexpectedMessage = errors.New("unexpected reconciliation message")
+
+// ShortID returns the 32-bit short id of the passed transaction hash for the
+// provided reconciliation round salt.
+func ShortID(salt uint64, hash *chainhash.Hash) uint32 {
+	var b [8 + chainhash.HashSize]byte
+	binary.LittleEndian.PutUint64(b[:], salt)
+	copy(b[8:], hash[:])
+	return binary.LittleEndian.Uint32(chainhash.HashB(b[:]))
+}
+
+// estimateDifference returns the estimated number of entries in the symmetric
+// difference of two sets with the provided sizes.  The estimate accounts for
+// the transactions that are in both sets without being in their intersection
+// because they were announced on other connections in between rounds.
+func estimateDifference(localSize, remoteSize int) int {
+	diff := localSize - remoteSize
+	if diff < 0 {
+		diff = -diff
+	}
+	minSize := localSize
+	if remoteSize < minSize {
+		minSize = remoteSize
+	}
+	capacity := diff + minSize/4 + 4
+	if capacity > maxSketchCapacity {
+		capacity = maxSketchCapacity
+	}
+	return capacity
+}
+
+// Stats houses statistics about the reconciliation rounds performed with all
+// peers.  The byte counts only include the data sent by the local node.
+type Stats struct {
+	// Rounds is the number of reconciliation rounds initiated.
+	Rounds uint64
+
+	// Failures is the number of initiated reconciliation rounds where the
+	// sketch could not be decoded and flooding was used instead.
+	Failures uint64
+
+	// ReconBytes is the number of bytes sent for reconciliation, including
+	// the resulting announcements.
+	ReconBytes uint64
+
+	// FloodBytes is the number of bytes that would have been sent had the
+	// same transactions been announced via flooding.
+	FloodBytes uint64
+}
+
+// Metrics aggregates the statistics of the reconciliation rounds of multiple
+// reconcilers.  The zero value is ready for use.
+//
+// It is safe for concurrent access.
+type Metrics struct {
+	mtx   sync.Mutex
+	stats Stats
+}
+
+// Stats returns a snapshot of the current statistics.
+//
+// This function is safe for concurrent access.
+func (m *Metrics) Stats() Stats {
+	m.mtx.Lock()
+	stats := m.stats
+	m.mtx.Unlock()
+	return stats
+}
+
+// record adds the result of one side of a reconciliation round to the
+// statistics.  The round is only counted for the initiator.
+func (m *Metrics) record(initiator, success bool, setSize, reconBytes int) {
+	if m == nil {
+		return
+	}
+	m.mtx.Lock()
+	if initiator {
+		m.stats.Rounds++
+		if !success {
+			m.stats.Failures++
+		}
+	}
+	m.stats.ReconBytes += uint64(reconBytes)
+	m.stats.FloodBytes += uint64(setSize * invVectSize)
+	m.mtx.Unlock()
+}
+
+// Reconciler tracks the transactions pending announcement to a single peer via
+// reconciliation along with the state of the current reconciliation round.
+//
+// It is safe for concurrent access.
+type Reconciler struct {
+	initiator bool
+	metrics   *Metrics
+
+	mtx     sync.Mutex
+	pending map[chainhash.Hash]struct{}
+
+	// roundActive is set while a reconciliation round is in progress, in
+	// which case inFlight holds the transactions that are part of the round
+	// keyed by their short id for the salt of the round.
+	roundActive bool
+	inFlight    map[uint32]chainhash.Hash
+
+	// sketchBytes is the serialized size of the sketch sent by the
+	// responder for the active round.
+	sketchBytes int
+}
+
+// NewReconciler returns a new reconciler for a connection.  The initiator flag
+// must be set for the outbound side of the connection, which is the only side
+// that starts reconciliation rounds.  The statistics of all rounds are added to
+// the passed metrics which may be nil.
+func NewReconciler(initiator bool, metrics *Metrics) *Reconciler {
+	return &Reconciler{
+		initiator: initiator,
+		metrics:   metrics,
+		pending:   make(map[chainhash.Hash]struct{}),
+	}
+}
+
+// IsInitiator returns whether the reconciler starts reconciliation rounds.
+func (r *Reconciler) IsInitiator() bool {
+	return r.initiator
+}
+
+// AddTx adds the passed transaction hash to the set of transactions pending
+// reconciliation.  It returns false when the set is full, in which case the
+// transaction must be announced via flooding instead.
+//
+// This function is safe for concurrent access.
+func (r *Reconciler) AddTx(hash *chainhash.Hash) bool {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if _, ok := r.pending[*hash]; ok {
+		return true
+	}
+	if len(r.pending) >= MaxSetSize {
+		return false
+	}
+	r.pending[*hash] = struct{}{}
+	return true
+}
+
+// RemoveTx removes the passed transaction hash from the set of transactions
+// pending reconciliation.  It is typically called when the peer is already
+// known to have the transaction.
+//
+// This function is safe for concurrent access.
+func (r *Reconciler) RemoveTx(hash *chainhash.Hash) {
+	r.mtx.Lock()
+	delete(r.pending, *hash)
+	r.mtx.Unlock()
+}
+
+// PendingCount returns the number of transactions pending reconciliation.
+//
+// This function is safe for concurrent access.
+func (r *Reconciler) PendingCount() int {
+	r.mtx.Lock()
+	count := len(r.pending)
+	r.mtx.Unlock()
+	return count
+}
+
+// snapshot moves the pending transactions to the in flight set keyed by their
+// short id for the passed salt and marks a round as active.  Transactions with
+// a short id that collides with another transaction remain pending for the next
+// round.
+//
+// This function MUST be called with the mutex held (for writes).
+func (r *Reconciler) snapshot(salt uint64) {
+	r.inFlight = make(map[uint32]chainhash.Hash, len(r.pending))
+	for hash := range r.pending {
+		hash := hash
+		id := ShortID(salt, &hash)
+		if _, ok := r.inFlight[id]; ok {
+			continue
+		}
+		r.inFlight[id] = hash
+		delete(r.pending, hash)
+	}
+	r.roundActive = true
+}
+
+// finishRound concludes the active round and returns the hashes of all in
+// flight transactions.
+//
+// This function MUST be called with the mutex held (for writes).
+func (r *Reconciler) finishRound() []chainhash.Hash {
+	hashes := make([]chainhash.Hash, 0, len(r.inFlight))
+	for _, hash := range r.inFlight {
+		hashes = append(hashes, hash)
+	}
+	r.inFlight = nil
+	r.roundActive = false
+	return hashes
+}
+
+// StartRound starts a new reconciliation round and returns the reqrecon
+// message to send to the peer.  Any round that is still active is abandoned
+// and the hashes of its transactions are returned so they can be announced via
+// flooding instead.
+//
+// ErrUnexpectedMessage is returned when the reconciler is not the initiator.
+//
+// This function is safe for concurrent access.
+func (r *Reconciler) StartRound() (*wire.MsgReqRecon, []chainhash.Hash, error) {
+	if !r.initiator {
+		return nil, nil, ErrUnexpectedMessage
+	}
+
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return nil, nil, err
+	}
+	salt := binary.LittleEndian.Uint64(b[:])
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	var abandoned []chainhash.Hash
+	if r.roundActive {
+		abandoned = r.finishRound()
+	}
+	r.snapshot(salt)
+	return wire.NewMsgReqRecon(uint32(len(r.inFlight)), salt), abandoned, nil
+}
+
+// HandleReqRecon processes a reqrecon message received from the initiator of
+// a round and returns the reconsketch message to send in response.
+//
+// ErrUnexpectedMessage is returned when the reconciler is the initiator or a
+// round is already active.
+//
+// This function is safe for concurrent access.
+func (r *Reconciler) HandleReqRecon(msg *wire.MsgReqRecon) (*wire.MsgReconSketch, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r.initiator || r.roundActive {
+		return nil, ErrUnexpectedMessage
+	}
+
+	r.snapshot(msg.Salt)
+	sketch := NewSketch(estimateDifference(len(r.inFlight),
+		int(msg.SetSize)))
+	for id := range r.inFlight {
+		sketch.Add(id)
+	}
+	serialized := sketch.Serialize()
+	r.sketchBytes = wire.VarIntSerializeSize(uint64(len(serialized))) +
+		len(serialized)
+	return wire.NewMsgReconSketch(serialized), nil
+}
+
+// HandleSketch processes a reconsketch message received in response to the
+// active round.  It returns the recondiff message to send in response along
+// with the hashes of the transactions that must be announced to the peer.
+//
+// ErrUnexpectedMessage is returned when the reconciler is not the initiator or
+// no round is active.
+//
+// This function is safe for concurrent access.
+func (r *Reconciler) HandleSketch(msg *wire.MsgReconSketch) (*wire.MsgReconDiff, []chainhash.Hash, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if !r.initiator || !r.roundActive {
+		return nil, nil, ErrUnexpectedMessage
+	}
+	setSize := len(r.inFlight)
+
+	// Subtract a sketch of the local set from the remote sketch and attempt
+	// to decode the difference.  Entries that remain with a positive count
+	// are only known to the peer while those with a negative count are only
+	// known locally.
+	var added, removed []uint32
+	remote, err := ParseSketch(msg.Sketch)
+	ok := err == nil
+	if ok {
+		local := &Sketch{cells: make([]cell, remote.NumCells())}
+		for id := range r.inFlight {
+			local.Add(id)
+		}
+		if err := remote.Subtract(local); err != nil {
+			return nil, nil, err
+		}
+		added, removed, ok = remote.Decode()
+		ok = ok && len(added) <= wire.MaxReconShortIDs
+	}
+
+	// Fall back to announcing the entire set when decoding failed.
+	var diff *wire.MsgReconDiff
+	var announce []chainhash.Hash
+	if !ok {
+		diff = wire.NewMsgReconDiff(false, nil)
+		announce = r.finishRound()
+	} else {
+		diff = wire.NewMsgReconDiff(true, added)
+		announce = make([]chainhash.Hash, 0, len(removed))
+		for _, id := range removed {
+			if hash, ok := r.inFlight[id]; ok {
+				announce = append(announce, hash)
+			}
+		}
+		r.finishRound()
+	}
+
+	// The bytes sent for the round consist of the reqrecon and recondiff
+	// messages along with the resulting announcements.
+	reconBytes := reqReconPayloadSize + 1 +
+		wire.VarIntSerializeSize(uint64(len(diff.AskShortIDs))) +
+		len(diff.AskShortIDs)*4 + len(announce)*invVectSize
+	r.metrics.record(true, ok, setSize, reconBytes)
+	return diff, announce, nil
+}
+
+// HandleReconDiff processes a recondiff message received from the initiator of
+// the active round and returns the hashes of the transactions that must be
+// announced to the peer.
+//
+// ErrUnexpectedMessage is returned when the reconciler is the initiator or no
+// round is active.
+//
+// This function is safe for concurrent access.
+func (r *Reconciler) HandleReconDiff(msg *wire.MsgReconDiff) ([]chainhash.Hash, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r.initiator || !r.roundActive {
+		return nil, ErrUnexpectedMessage
+	}
+	setSize := len(r.inFlight)
+
+	var announce []chainhash.Hash
+	if !msg.Success {
+		announce = r.finishRound()
+	} else {
+		announce = make([]chainhash.Hash, 0, len(msg.AskShortIDs))
+		for _, id := range msg.AskShortIDs {
+			if hash, ok := r.inFlight[id]; ok {
+				announce = append(announce, hash)
+			}
+		}
+		r.finishRound()
+	}
+
+	// The bytes sent for the round consist of the reconsketch message along
+	// with the resulting announcements.
+	reconBytes := r.sketchBytes + len(announce)*invVectSize
+	r.metrics.record(false, msg.Success, setSize, reconBytes)
+	return announce, nil
+}
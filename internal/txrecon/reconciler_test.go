@@ -0,0 +1,226 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txrecon
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/wire"
+)
+
+// testHash returns a deterministic transaction hash for the passed index.
+func testHash(i int) chainhash.Hash {
+	return chainhash.HashH([]byte{byte(i), byte(i >> 8), byte(i >> 16)})
+}
+
+// hashSet converts the passed hashes to a set.
+func hashSet(hashes []chainhash.Hash) map[chainhash.Hash]struct{} {
+	set := make(map[chainhash.Hash]struct{}, len(hashes))
+	for _, hash := range hashes {
+		set[hash] = struct{}{}
+	}
+	return set
+}
+
+// TestReconcilerRound ensures a full reconciliation round between an initiator
+// and a responder results in each side announcing exactly the transactions the
+// other side is missing.
+func TestReconcilerRound(t *testing.T) {
+	var metrics Metrics
+	initiator := NewReconciler(true, &metrics)
+	responder := NewReconciler(false, &metrics)
+
+	// Transactions 0-99 are known to both sides, 100-109 only to the
+	// initiator, and 110-124 only to the responder.
+	for i := 0; i < 110; i++ {
+		hash := testHash(i)
+		initiator.AddTx(&hash)
+	}
+	for i := 0; i < 125; i++ {
+		if i >= 100 && i < 110 {
+			continue
+		}
+		hash := testHash(i)
+		responder.AddTx(&hash)
+	}
+
+	reqRecon, abandoned, err := initiator.StartRound()
+	if err != nil {
+		t.Fatalf("StartRound: unexpected error: %v", err)
+	}
+	if len(abandoned) != 0 {
+		t.Fatalf("StartRound: unexpected abandoned txns: %d",
+			len(abandoned))
+	}
+	if reqRecon.SetSize != 110 {
+		t.Fatalf("StartRound: unexpected set size: got %d, want 110",
+			reqRecon.SetSize)
+	}
+	if initiator.PendingCount() != 0 {
+		t.Fatalf("StartRound: unexpected pending count: %d",
+			initiator.PendingCount())
+	}
+
+	sketch, err := responder.HandleReqRecon(reqRecon)
+	if err != nil {
+		t.Fatalf("HandleReqRecon: unexpected error: %v", err)
+	}
+
+	diff, initiatorAnnounce, err := initiator.HandleSketch(sketch)
+	if err != nil {
+		t.Fatalf("HandleSketch: unexpected error: %v", err)
+	}
+	if !diff.Success {
+		t.Fatal("HandleSketch: reconciliation unexpectedly failed")
+	}
+
+	responderAnnounce, err := responder.HandleReconDiff(diff)
+	if err != nil {
+		t.Fatalf("HandleReconDiff: unexpected error: %v", err)
+	}
+
+	// Ensure each side announces exactly what the other side is missing.
+	var wantInitiator, wantResponder []chainhash.Hash
+	for i := 100; i < 110; i++ {
+		wantInitiator = append(wantInitiator, testHash(i))
+	}
+	for i := 110; i < 125; i++ {
+		wantResponder = append(wantResponder, testHash(i))
+	}
+	if len(initiatorAnnounce) != len(wantInitiator) {
+		t.Fatalf("unexpected initiator announcements: got %d, want %d",
+			len(initiatorAnnounce), len(wantInitiator))
+	}
+	got := hashSet(initiatorAnnounce)
+	for _, hash := range wantInitiator {
+		if _, ok := got[hash]; !ok {
+			t.Fatalf("initiator did not announce %v", hash)
+		}
+	}
+	if len(responderAnnounce) != len(wantResponder) {
+		t.Fatalf("unexpected responder announcements: got %d, want %d",
+			len(responderAnnounce), len(wantResponder))
+	}
+	got = hashSet(responderAnnounce)
+	for _, hash := range wantResponder {
+		if _, ok := got[hash]; !ok {
+			t.Fatalf("responder did not announce %v", hash)
+		}
+	}
+
+	// Ensure the round was recorded and used less bandwidth than flooding.
+	stats := metrics.Stats()
+	if stats.Rounds != 1 || stats.Failures != 0 {
+		t.Fatalf("unexpected round stats: %+v", stats)
+	}
+	if stats.FloodBytes != (110+115)*invVectSize {
+		t.Fatalf("unexpected flood bytes: got %d, want %d",
+			stats.FloodBytes, (110+115)*invVectSize)
+	}
+	if stats.ReconBytes >= stats.FloodBytes {
+		t.Fatalf("reconciliation used more bytes than flooding: %+v",
+			stats)
+	}
+}
+
+// TestReconcilerFailure ensures both sides announce their entire set when the
+// sketch can't be decoded.
+func TestReconcilerFailure(t *testing.T) {
+	var metrics Metrics
+	initiator := NewReconciler(true, &metrics)
+	responder := NewReconciler(false, &metrics)
+
+	// Use disjoint sets while claiming an identical set size so the sketch
+	// is far too small to decode the difference.
+	for i := 0; i < 200; i++ {
+		hash := testHash(i)
+		initiator.AddTx(&hash)
+		hash = testHash(i + 1000)
+		responder.AddTx(&hash)
+	}
+
+	reqRecon, _, err := initiator.StartRound()
+	if err != nil {
+		t.Fatalf("StartRound: unexpected error: %v", err)
+	}
+	sketch, err := responder.HandleReqRecon(reqRecon)
+	if err != nil {
+		t.Fatalf("HandleReqRecon: unexpected error: %v", err)
+	}
+	diff, initiatorAnnounce, err := initiator.HandleSketch(sketch)
+	if err != nil {
+		t.Fatalf("HandleSketch: unexpected error: %v", err)
+	}
+	if diff.Success {
+		t.Fatal("HandleSketch: reconciliation unexpectedly succeeded")
+	}
+	responderAnnounce, err := responder.HandleReconDiff(diff)
+	if err != nil {
+		t.Fatalf("HandleReconDiff: unexpected error: %v", err)
+	}
+	if len(initiatorAnnounce) != 200 || len(responderAnnounce) != 200 {
+		t.Fatalf("unexpected announcements: initiator %d, responder %d",
+			len(initiatorAnnounce), len(responderAnnounce))
+	}
+	if stats := metrics.Stats(); stats.Rounds != 1 || stats.Failures != 1 {
+		t.Fatalf("unexpected round stats: %+v", stats)
+	}
+}
+
+// TestReconcilerUnexpected ensures messages that are not valid for the state of
+// the reconciler are rejected and that abandoned rounds are returned.
+func TestReconcilerUnexpected(t *testing.T) {
+	initiator := NewReconciler(true, nil)
+	responder := NewReconciler(false, nil)
+
+	if _, _, err := responder.StartRound(); !errors.Is(err, ErrUnexpectedMessage) {
+		t.Errorf("responder StartRound: got %v, want %v", err,
+			ErrUnexpectedMessage)
+	}
+	reqRecon := wire.NewMsgReqRecon(0, 0)
+	if _, err := initiator.HandleReqRecon(reqRecon); !errors.Is(err, ErrUnexpectedMessage) {
+		t.Errorf("initiator HandleReqRecon: got %v, want %v", err,
+			ErrUnexpectedMessage)
+	}
+	sketch := wire.NewMsgReconSketch(NewSketch(0).Serialize())
+	if _, _, err := initiator.HandleSketch(sketch); !errors.Is(err, ErrUnexpectedMessage) {
+		t.Errorf("initiator HandleSketch without round: got %v, want %v",
+			err, ErrUnexpectedMessage)
+	}
+	diff := wire.NewMsgReconDiff(true, nil)
+	if _, err := responder.HandleReconDiff(diff); !errors.Is(err, ErrUnexpectedMessage) {
+		t.Errorf("responder HandleReconDiff without round: got %v, want %v",
+			err, ErrUnexpectedMessage)
+	}
+
+	// Ensure starting a new round while one is active returns the
+	// transactions of the abandoned round.
+	hash := testHash(1)
+	initiator.AddTx(&hash)
+	if _, _, err := initiator.StartRound(); err != nil {
+		t.Fatalf("StartRound: unexpected error: %v", err)
+	}
+	_, abandoned, err := initiator.StartRound()
+	if err != nil {
+		t.Fatalf("StartRound: unexpected error: %v", err)
+	}
+	if len(abandoned) != 1 || abandoned[0] != hash {
+		t.Fatalf("unexpected abandoned txns: %v", abandoned)
+	}
+
+	// Ensure the set size is limited.
+	for i := 0; i < MaxSetSize; i++ {
+		hash := testHash(i)
+		if !responder.AddTx(&hash) {
+			t.Fatalf("AddTx: unexpectedly rejected tx %d", i)
+		}
+	}
+	hash = testHash(MaxSetSize)
+	if responder.AddTx(&hash) {
+		t.Fatal("AddTx: unexpectedly accepted tx beyond max set size")
+	}
+}
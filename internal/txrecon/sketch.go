@@ -0,0 +1,211 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txrecon
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	// numSketchHashes is the number of cells each short id is added to.
+	// The cells of a sketch are split into this many subtables of equal
+	// size with one cell per subtable so that the cells an entry maps to
+	// are always distinct.
+	numSketchHashes = 3
+
+	// sketchCellSize is the number of bytes a single serialized cell
+	// occupies.  It consists of the count, the sum of the short ids, and
+	// the sum of the checksums of the short ids of all entries in the cell.
+	sketchCellSize = 12
+
+	// minSketchCells is the minimum number of cells in a sketch.
+	minSketchCells = numSketchHashes * 4
+)
+
+var (
+	// ErrSketchSizeMismatch is returned when attempting to subtract sketches
+	// with a different number of cells.
+	ErrSketchSizeMismatch = errors.New("sketches have a different number " +
+		"of cells")
+
+	// ErrMalformedSketch is returned when a serialized sketch can not be
+	// parsed.
+	ErrMalformedSketch = errors.New("malformed sketch")
+)
+
+// cell is a single cell of a sketch.
+type cell struct {
+	count   int32
+	idSum   uint32
+	hashSum uint32
+}
+
+// pure returns whether the cell contains exactly one entry, either added or
+// removed, which can then be recovered.
+func (c *cell) pure() bool {
+	return (c.count == 1 || c.count == -1) && checksum(c.idSum) == c.hashSum
+}
+
+// empty returns whether the cell contains no entries.
+func (c *cell) empty() bool {
+	return c.count == 0 && c.idSum == 0 && c.hashSum == 0
+}
+
+// Sketch is an invertible bloom lookup table of 32-bit short ids.  Subtracting
+// the sketch of one set from the sketch of another set yields a sketch of
+// their symmetric difference, which may be decoded as long as it is small
+// enough relative to the number of cells.
+type Sketch struct {
+	cells []cell
+}
+
+// SketchCells returns the number of cells a sketch needs in order to be able
+// to decode a difference of the provided number of entries with a high
+// probability.
+func SketchCells(capacity int) int {
+	// Invertible bloom lookup tables with three hash functions decode
+	// reliably once the number of cells exceeds roughly 1.23 times the
+	// number of entries for large tables, however, small tables require
+	// more overhead, so use a more conservative factor.
+	numCells := capacity*3/2 + numSketchHashes*2
+	if numCells < minSketchCells {
+		numCells = minSketchCells
+	}
+
+	// Round up to a multiple of the number of subtables.
+	if rem := numCells % numSketchHashes; rem != 0 {
+		numCells += numSketchHashes - rem
+	}
+	return numCells
+}
+
+// NewSketch returns an empty sketch that is able to decode a difference of
+// the provided number of entries with a high probability.
+func NewSketch(capacity int) *Sketch {
+	return &Sketch{cells: make([]cell, SketchCells(capacity))}
+}
+
+// mix returns a well-distributed 32-bit value derived from the passed value
+// and seed.
+func mix(v, seed uint32) uint32 {
+	h := v ^ (seed * 0x9e3779b9)
+	h ^= h >> 16
+	h *= 0x85ebca6b
+	h ^= h >> 13
+	h *= 0xc2b2ae35
+	h ^= h >> 16
+	return h
+}
+
+// checksum returns the checksum of the passed short id which is used to
+// identify pure cells.
+func checksum(id uint32) uint32 {
+	return mix(id, numSketchHashes+1)
+}
+
+// update adds the short id to the sketch when count is 1 and removes it when
+// count is -1.
+func (s *Sketch) update(id uint32, count int32) {
+	hash := checksum(id)
+	subSize := uint32(len(s.cells) / numSketchHashes)
+	for i := uint32(0); i < numSketchHashes; i++ {
+		c := &s.cells[i*subSize+mix(id, i)%subSize]
+		c.count += count
+		c.idSum ^= id
+		c.hashSum ^= hash
+	}
+}
+
+// Add adds the passed short id to the sketch.
+func (s *Sketch) Add(id uint32) {
+	s.update(id, 1)
+}
+
+// NumCells returns the number of cells in the sketch.
+func (s *Sketch) NumCells() int {
+	return len(s.cells)
+}
+
+// Subtract removes all entries of the passed sketch from the sketch, leaving
+// the entries that are only in the sketch with a positive count and the entries
+// that are only in the passed sketch with a negative count.
+func (s *Sketch) Subtract(other *Sketch) error {
+	if len(s.cells) != len(other.cells) {
+		return ErrSketchSizeMismatch
+	}
+	for i := range s.cells {
+		s.cells[i].count -= other.cells[i].count
+		s.cells[i].idSum ^= other.cells[i].idSum
+		s.cells[i].hashSum ^= other.cells[i].hashSum
+	}
+	return nil
+}
+
+// Decode attempts to recover all entries from the sketch.  It returns the short
+// ids with a positive count as added and those with a negative count as
+// removed.  The final return value is false when the sketch could not be fully
+// decoded, in which case the other return values must be ignored.
+//
+// Decoding consumes the sketch.
+func (s *Sketch) Decode() (added, removed []uint32, ok bool) {
+	// Repeatedly peel pure cells until no more remain.
+	for progress := true; progress; {
+		progress = false
+		for i := range s.cells {
+			c := &s.cells[i]
+			if !c.pure() {
+				continue
+			}
+			id, count := c.idSum, c.count
+			if count > 0 {
+				added = append(added, id)
+			} else {
+				removed = append(removed, id)
+			}
+			s.update(id, -count)
+			progress = true
+		}
+	}
+
+	// The sketch was only decoded successfully when all cells are empty.
+	for i := range s.cells {
+		if !s.cells[i].empty() {
+			return nil, nil, false
+		}
+	}
+	return added, removed, true
+}
+
+// Serialize returns the serialized sketch.
+func (s *Sketch) Serialize() []byte {
+	b := make([]byte, len(s.cells)*sketchCellSize)
+	for i, c := range s.cells {
+		offset := i * sketchCellSize
+		binary.LittleEndian.PutUint32(b[offset:], uint32(c.count))
+		binary.LittleEndian.PutUint32(b[offset+4:], c.idSum)
+		binary.LittleEndian.PutUint32(b[offset+8:], c.hashSum)
+	}
+	return b
+}
+
+// ParseSketch parses a sketch serialized with Serialize.
+func ParseSketch(b []byte) (*Sketch, error) {
+	if len(b) == 0 || len(b)%(sketchCellSize*numSketchHashes) != 0 {
+		return nil, fmt.Errorf("%w: serialized length %d is not a "+
+			"multiple of %d", ErrMalformedSketch, len(b),
+			sketchCellSize*numSketchHashes)
+	}
+
+	cells := make([]cell, len(b)/sketchCellSize)
+	for i := range cells {
+		offset := i * sketchCellSize
+		cells[i].count = int32(binary.LittleEndian.Uint32(b[offset:]))
+		cells[i].idSum = binary.LittleEndian.Uint32(b[offset+4:])
+		cells[i].hashSum = binary.LittleEndian.Uint32(b[offset+8:])
+	}
+	return &Sketch{cells: cells}, nil
+}
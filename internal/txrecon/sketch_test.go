@@ -0,0 +1,158 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txrecon
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// sortIDs sorts the passed short ids in place and returns them.
+func sortIDs(ids []uint32) []uint32 {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// TestSketchCells ensures the number of cells for a given capacity is at least
+// the minimum and always a multiple of the number of subtables.
+func TestSketchCells(t *testing.T) {
+	tests := []struct {
+		capacity int
+		want     int
+	}{
+		{capacity: 0, want: minSketchCells},
+		{capacity: 1, want: minSketchCells},
+		{capacity: 4, want: minSketchCells},
+		{capacity: 5, want: 15},
+		{capacity: 100, want: 156},
+		{capacity: 1001, want: 1509},
+	}
+
+	for _, test := range tests {
+		got := SketchCells(test.capacity)
+		if got != test.want {
+			t.Errorf("SketchCells(%d): got %d, want %d", test.capacity,
+				got, test.want)
+			continue
+		}
+		if got%numSketchHashes != 0 {
+			t.Errorf("SketchCells(%d): %d is not a multiple of %d",
+				test.capacity, got, numSketchHashes)
+		}
+	}
+}
+
+// TestSketchDecode ensures the symmetric difference of two sets is recovered
+// from the difference of their sketches.
+func TestSketchDecode(t *testing.T) {
+	tests := []struct {
+		name       string
+		common     int // number of entries in both sets
+		onlyLocal  int // number of entries only in the local set
+		onlyRemote int // number of entries only in the remote set
+	}{
+		{name: "identical empty sets"},
+		{name: "identical sets", common: 500},
+		{name: "only remote entries", common: 200, onlyRemote: 10},
+		{name: "only local entries", common: 200, onlyLocal: 10},
+		{name: "both sides differ", common: 1000, onlyLocal: 40, onlyRemote: 60},
+		{name: "disjoint sets", onlyLocal: 50, onlyRemote: 50},
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for _, test := range tests {
+		capacity := test.onlyLocal + test.onlyRemote
+		local, remote := NewSketch(capacity), NewSketch(capacity)
+		var wantAdded, wantRemoved []uint32
+		for i := 0; i < test.common; i++ {
+			id := rng.Uint32()
+			local.Add(id)
+			remote.Add(id)
+		}
+		for i := 0; i < test.onlyLocal; i++ {
+			id := rng.Uint32()
+			local.Add(id)
+			wantRemoved = append(wantRemoved, id)
+		}
+		for i := 0; i < test.onlyRemote; i++ {
+			id := rng.Uint32()
+			remote.Add(id)
+			wantAdded = append(wantAdded, id)
+		}
+
+		if err := remote.Subtract(local); err != nil {
+			t.Errorf("%q: unexpected subtract error: %v", test.name, err)
+			continue
+		}
+		added, removed, ok := remote.Decode()
+		if !ok {
+			t.Errorf("%q: failed to decode sketch", test.name)
+			continue
+		}
+		if !reflect.DeepEqual(sortIDs(added), sortIDs(wantAdded)) {
+			t.Errorf("%q: mismatched added ids: got %v, want %v",
+				test.name, added, wantAdded)
+		}
+		if !reflect.DeepEqual(sortIDs(removed), sortIDs(wantRemoved)) {
+			t.Errorf("%q: mismatched removed ids: got %v, want %v",
+				test.name, removed, wantRemoved)
+		}
+	}
+}
+
+// TestSketchDecodeOverCapacity ensures decoding a sketch with a difference far
+// larger than its capacity fails.
+func TestSketchDecodeOverCapacity(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	sketch := NewSketch(10)
+	for i := 0; i < 1000; i++ {
+		sketch.Add(rng.Uint32())
+	}
+	if _, _, ok := sketch.Decode(); ok {
+		t.Fatal("decoding sketch over capacity unexpectedly succeeded")
+	}
+}
+
+// TestSketchSerialize ensures sketches round trip through serialization and
+// that malformed and mismatched sketches are rejected.
+func TestSketchSerialize(t *testing.T) {
+	sketch := NewSketch(20)
+	for id := uint32(1); id <= 10; id++ {
+		sketch.Add(id * 0x01010101)
+	}
+
+	serialized := sketch.Serialize()
+	if len(serialized) != sketch.NumCells()*sketchCellSize {
+		t.Fatalf("unexpected serialized length: got %d, want %d",
+			len(serialized), sketch.NumCells()*sketchCellSize)
+	}
+	parsed, err := ParseSketch(serialized)
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	if !reflect.DeepEqual(parsed, sketch) {
+		t.Fatal("parsed sketch does not match original")
+	}
+
+	// Ensure malformed serialized sketches are rejected.
+	for _, b := range [][]byte{nil, serialized[:sketchCellSize]} {
+		_, err := ParseSketch(b)
+		if !errors.Is(err, ErrMalformedSketch) {
+			t.Errorf("ParseSketch(%d bytes): got error %v, want %v",
+				len(b), err, ErrMalformedSketch)
+		}
+	}
+
+	// Ensure sketches with a different number of cells can't be
+	// subtracted.
+	err = parsed.Subtract(NewSketch(100))
+	if !errors.Is(err, ErrSketchSizeMismatch) {
+		t.Errorf("Subtract: got error %v, want %v", err,
+			ErrSketchSizeMismatch)
+	}
+}
@@ -0,0 +1,524 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/connmgr/v3"
+)
+
+const (
+	// mdnsService is the DNS-SD service name nodes advertise themselves
+	// under on the local network.
+	mdnsService = "_dcrd._tcp.local."
+
+	// mdnsQueryInterval is the interval between queries for other nodes on
+	// the local network.
+	mdnsQueryInterval = 2 * time.Minute
+
+	// mdnsTTL is the time to live in seconds of the records advertised by
+	// the local node.
+	mdnsTTL = 120
+
+	// mdnsMaxPacketSize is the maximum size of received multicast DNS
+	// packets.
+	mdnsMaxPacketSize = 9000
+
+	// These constants define the DNS resource record types, class, and
+	// header flags used by multicast DNS discovery.
+	dnsTypePTR           = 12
+	dnsTypeTXT           = 16
+	dnsTypeSRV           = 33
+	dnsClassIN           = 1
+	dnsClassMask         = 0x7fff
+	dnsFlagResponse      = 0x8000
+	dnsFlagAuthoritative = 0x0400
+
+	// dnsMaxPointers is the maximum number of compression pointers followed
+	// while reading a single name in order to prevent loops.
+	dnsMaxPointers = 16
+)
+
+var (
+	// mdnsGroupAddr is the IPv4 multicast DNS group address and port.
+	mdnsGroupAddr = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+
+	// errDNSTruncated is returned when a DNS message ends before all of its
+	// contents have been read.
+	errDNSTruncated = errors.New("dns message truncated")
+)
+
+// dnsRecord is a question or resource record of a DNS message.  The time to
+// live and data are not used for questions.
+type dnsRecord struct {
+	name   string
+	rrType uint16
+	class  uint16
+	ttl    uint32
+	data   []byte
+}
+
+// dnsMessage is a DNS message.  The answer, authority, and additional records
+// of received messages are all treated as answers since multicast DNS
+// responders commonly provide the related records in any of them.
+type dnsMessage struct {
+	flags     uint16
+	questions []dnsRecord
+	answers   []dnsRecord
+}
+
+// appendDNSName appends the passed fully-qualified name to the passed buffer
+// in DNS wire format without compression.
+func appendDNSName(b []byte, name string) ([]byte, error) {
+	name = strings.TrimSuffix(name, ".")
+	if name != "" {
+		for _, label := range strings.Split(name, ".") {
+			if len(label) == 0 || len(label) > 63 {
+				return nil, fmt.Errorf("invalid dns label %q", label)
+			}
+			b = append(b, byte(len(label)))
+			b = append(b, label...)
+		}
+	}
+	return append(b, 0), nil
+}
+
+// readDNSName reads the possibly compressed name at the passed offset of the
+// passed DNS message and returns it along with the offset immediately after
+// it.
+func readDNSName(msg []byte, off int) (string, int, error) {
+	var labels []string
+	next := -1
+	for pointers := 0; ; {
+		if off >= len(msg) {
+			return "", 0, errDNSTruncated
+		}
+		n := int(msg[off])
+		switch {
+		case n == 0:
+			if next == -1 {
+				next = off + 1
+			}
+			return strings.Join(labels, ".") + ".", next, nil
+
+		case n&0xc0 == 0xc0:
+			if off+1 >= len(msg) {
+				return "", 0, errDNSTruncated
+			}
+			pointers++
+			if pointers > dnsMaxPointers {
+				return "", 0, errors.New("too many dns compression pointers")
+			}
+			if next == -1 {
+				next = off + 2
+			}
+			off = int(binary.BigEndian.Uint16(msg[off:]) & 0x3fff)
+
+		case n&0xc0 != 0:
+			return "", 0, fmt.Errorf("invalid dns label length %#x", n)
+
+		default:
+			if off+1+n > len(msg) {
+				return "", 0, errDNSTruncated
+			}
+			labels = append(labels, string(msg[off+1:off+1+n]))
+			off += 1 + n
+		}
+	}
+}
+
+// marshal returns the DNS wire format of the message.
+func (m *dnsMessage) marshal() ([]byte, error) {
+	b := make([]byte, 12, 512)
+	binary.BigEndian.PutUint16(b[2:], m.flags)
+	binary.BigEndian.PutUint16(b[4:], uint16(len(m.questions)))
+	binary.BigEndian.PutUint16(b[6:], uint16(len(m.answers)))
+	var err error
+	for _, q := range m.questions {
+		if b, err = appendDNSName(b, q.name); err != nil {
+			return nil, err
+		}
+		b = append(b, byte(q.rrType>>8), byte(q.rrType), byte(q.class>>8),
+			byte(q.class))
+	}
+	for _, rr := range m.answers {
+		if b, err = appendDNSName(b, rr.name); err != nil {
+			return nil, err
+		}
+		var fixed [10]byte
+		binary.BigEndian.PutUint16(fixed[0:], rr.rrType)
+		binary.BigEndian.PutUint16(fixed[2:], rr.class)
+		binary.BigEndian.PutUint32(fixed[4:], rr.ttl)
+		binary.BigEndian.PutUint16(fixed[8:], uint16(len(rr.data)))
+		b = append(b, fixed[:]...)
+		b = append(b, rr.data...)
+	}
+	return b, nil
+}
+
+// parseDNSMessage parses the passed DNS message in wire format.
+func parseDNSMessage(msg []byte) (*dnsMessage, error) {
+	if len(msg) < 12 {
+		return nil, errDNSTruncated
+	}
+	m := &dnsMessage{flags: binary.BigEndian.Uint16(msg[2:])}
+	numQuestions := int(binary.BigEndian.Uint16(msg[4:]))
+	numRecords := int(binary.BigEndian.Uint16(msg[6:])) +
+		int(binary.BigEndian.Uint16(msg[8:])) +
+		int(binary.BigEndian.Uint16(msg[10:]))
+	off := 12
+	for i := 0; i < numQuestions; i++ {
+		name, next, err := readDNSName(msg, off)
+		if err != nil {
+			return nil, err
+		}
+		if next+4 > len(msg) {
+			return nil, errDNSTruncated
+		}
+		m.questions = append(m.questions, dnsRecord{
+			name:   name,
+			rrType: binary.BigEndian.Uint16(msg[next:]),
+			class:  binary.BigEndian.Uint16(msg[next+2:]) & dnsClassMask,
+		})
+		off = next + 4
+	}
+	for i := 0; i < numRecords; i++ {
+		name, next, err := readDNSName(msg, off)
+		if err != nil {
+			return nil, err
+		}
+		if next+10 > len(msg) {
+			return nil, errDNSTruncated
+		}
+		rr := dnsRecord{
+			name:   name,
+			rrType: binary.BigEndian.Uint16(msg[next:]),
+			class:  binary.BigEndian.Uint16(msg[next+2:]) & dnsClassMask,
+			ttl:    binary.BigEndian.Uint32(msg[next+4:]),
+		}
+		dataLen := int(binary.BigEndian.Uint16(msg[next+8:]))
+		off = next + 10
+		if off+dataLen > len(msg) {
+			return nil, errDNSTruncated
+		}
+
+		// PTR and SRV records contain names that may be compressed
+		// relative to the entire message, so store them uncompressed.
+		switch rr.rrType {
+		case dnsTypePTR:
+			target, _, err := readDNSName(msg, off)
+			if err != nil {
+				return nil, err
+			}
+			rr.data = []byte(target)
+
+		case dnsTypeSRV:
+			if dataLen < 7 {
+				return nil, errDNSTruncated
+			}
+			target, _, err := readDNSName(msg, off+6)
+			if err != nil {
+				return nil, err
+			}
+			rr.data = append(append([]byte(nil), msg[off:off+6]...), target...)
+
+		default:
+			rr.data = msg[off : off+dataLen]
+		}
+		m.answers = append(m.answers, rr)
+		off += dataLen
+	}
+	return m, nil
+}
+
+// mdnsAnnouncement describes a node advertised via multicast DNS.
+type mdnsAnnouncement struct {
+	// id is the random identifier the node chose at startup.  It is used to
+	// ignore the announcements of the local node and to determine which of
+	// two nodes initiates the connection between them.
+	id string
+
+	// network is the name of the network the node is running on.
+	network string
+
+	// port is the port the node is listening for connections on.
+	port uint16
+}
+
+// mdnsQuery returns a multicast DNS query for nodes on the local network.
+func mdnsQuery() *dnsMessage {
+	return &dnsMessage{
+		questions: []dnsRecord{{
+			name:   mdnsService,
+			rrType: dnsTypePTR,
+			class:  dnsClassIN,
+		}},
+	}
+}
+
+// mdnsResponse returns a multicast DNS response that advertises the passed
+// node.
+func mdnsResponse(a *mdnsAnnouncement) (*dnsMessage, error) {
+	instance := a.id + "." + mdnsService
+	ptr, err := appendDNSName(nil, instance)
+	if err != nil {
+		return nil, err
+	}
+	srv := make([]byte, 6, 64)
+	binary.BigEndian.PutUint16(srv[4:], a.port)
+	srv, err = appendDNSName(srv, a.id+".local.")
+	if err != nil {
+		return nil, err
+	}
+	var txt []byte
+	for _, kv := range []string{"net=" + a.network, "id=" + a.id} {
+		txt = append(txt, byte(len(kv)))
+		txt = append(txt, kv...)
+	}
+	return &dnsMessage{
+		flags: dnsFlagResponse | dnsFlagAuthoritative,
+		answers: []dnsRecord{
+			{mdnsService, dnsTypePTR, dnsClassIN, mdnsTTL, ptr},
+			{instance, dnsTypeSRV, dnsClassIN, mdnsTTL, srv},
+			{instance, dnsTypeTXT, dnsClassIN, mdnsTTL, txt},
+		},
+	}, nil
+}
+
+// isMDNSQuery returns whether or not the passed message is a query for nodes
+// on the local network.
+func isMDNSQuery(m *dnsMessage) bool {
+	if m.flags&dnsFlagResponse != 0 {
+		return false
+	}
+	for _, q := range m.questions {
+		if q.rrType == dnsTypePTR && strings.EqualFold(q.name, mdnsService) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseMDNSAnnouncement returns the node advertised by the passed multicast
+// DNS response.  False is returned when the message does not advertise a node
+// or advertises it incompletely.
+func parseMDNSAnnouncement(m *dnsMessage) (*mdnsAnnouncement, bool) {
+	if m.flags&dnsFlagResponse == 0 {
+		return nil, false
+	}
+	var instance string
+	for _, rr := range m.answers {
+		if rr.rrType == dnsTypePTR && strings.EqualFold(rr.name, mdnsService) {
+			instance = string(rr.data)
+			break
+		}
+	}
+	if instance == "" {
+		return nil, false
+	}
+
+	var a mdnsAnnouncement
+	for _, rr := range m.answers {
+		if !strings.EqualFold(rr.name, instance) {
+			continue
+		}
+		switch rr.rrType {
+		case dnsTypeSRV:
+			a.port = binary.BigEndian.Uint16(rr.data[4:])
+
+		case dnsTypeTXT:
+			for data := rr.data; len(data) > 0; {
+				n := int(data[0])
+				if 1+n > len(data) {
+					break
+				}
+				kv := string(data[1 : 1+n])
+				data = data[1+n:]
+				switch {
+				case strings.HasPrefix(kv, "net="):
+					a.network = kv[len("net="):]
+				case strings.HasPrefix(kv, "id="):
+					a.id = kv[len("id="):]
+				}
+			}
+		}
+	}
+	if a.id == "" || a.network == "" || a.port == 0 {
+		return nil, false
+	}
+	return &a, true
+}
+
+// mdnsDiscovery discovers other nodes on the same network running on the local
+// network via multicast DNS and automatically connects to them.  The local
+// node is also advertised in response to the queries of other nodes when it is
+// listening for connections.
+type mdnsDiscovery struct {
+	// local describes the local node.  The port is zero when the local node
+	// is not listening for connections.
+	local mdnsAnnouncement
+
+	// connect requests a connection to a discovered node.
+	connect func(ctx context.Context, c *connmgr.ConnReq)
+
+	// send writes the passed multicast DNS message to the local network.
+	send func(msg []byte) error
+
+	// connReqs houses the most recent connection request to every
+	// discovered node by address so discovered nodes are only reconnected
+	// once their connection is lost or failed.
+	connReqsMtx sync.Mutex
+	connReqs    map[string]*connmgr.ConnReq
+}
+
+// newMDNSDiscovery returns a multicast DNS discovery for nodes running on the
+// passed network that requests connections via the passed function.  The port
+// must be the port the local node is listening for connections on or zero when
+// it is not listening.
+func newMDNSDiscovery(network string, port uint16, connect func(context.Context, *connmgr.ConnReq)) (*mdnsDiscovery, error) {
+	var id [8]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return nil, err
+	}
+	return &mdnsDiscovery{
+		local: mdnsAnnouncement{
+			id:      hex.EncodeToString(id[:]),
+			network: network,
+			port:    port,
+		},
+		connect:  connect,
+		connReqs: make(map[string]*connmgr.ConnReq),
+	}, nil
+}
+
+// sendMessage writes the passed multicast DNS message to the local network.
+func (d *mdnsDiscovery) sendMessage(m *dnsMessage) {
+	msg, err := m.marshal()
+	if err == nil {
+		err = d.send(msg)
+	}
+	if err != nil {
+		srvrLog.Debugf("Unable to send mDNS message: %v", err)
+	}
+}
+
+// announce advertises the local node to the local network when it is
+// listening for connections.
+func (d *mdnsDiscovery) announce() {
+	if d.local.port == 0 {
+		return
+	}
+	resp, err := mdnsResponse(&d.local)
+	if err != nil {
+		srvrLog.Debugf("Unable to create mDNS response: %v", err)
+		return
+	}
+	d.sendMessage(resp)
+}
+
+// handleMessage handles the passed multicast DNS message received from the
+// passed source address by advertising the local node in response to queries
+// and connecting to the nodes advertised by responses.
+func (d *mdnsDiscovery) handleMessage(ctx context.Context, msg []byte, src *net.UDPAddr) {
+	m, err := parseDNSMessage(msg)
+	if err != nil {
+		srvrLog.Tracef("Ignoring invalid mDNS message from %v: %v", src, err)
+		return
+	}
+	if isMDNSQuery(m) {
+		d.announce()
+		return
+	}
+	a, ok := parseMDNSAnnouncement(m)
+	if !ok || a.id == d.local.id || a.network != d.local.network {
+		return
+	}
+
+	// Only the node with the lower id initiates the connection when both
+	// nodes are listening in order to avoid duplicate connections.
+	if d.local.port != 0 && d.local.id > a.id {
+		return
+	}
+
+	addr := net.JoinHostPort(src.IP.String(), strconv.Itoa(int(a.port)))
+	d.connReqsMtx.Lock()
+	if c, ok := d.connReqs[addr]; ok {
+		state := c.State()
+		if state == connmgr.ConnPending || state == connmgr.ConnEstablished {
+			d.connReqsMtx.Unlock()
+			return
+		}
+	}
+	c := &connmgr.ConnReq{
+		Addr:      &net.TCPAddr{IP: src.IP, Port: int(a.port)},
+		Permanent: false,
+	}
+	d.connReqs[addr] = c
+	d.connReqsMtx.Unlock()
+
+	srvrLog.Infof("Discovered node %s on the local network via mDNS", addr)
+	go d.connect(ctx, c)
+}
+
+// Run joins the multicast DNS group and discovers and advertises nodes until
+// the passed context is canceled.
+//
+// This function MUST be run as a goroutine.
+func (d *mdnsDiscovery) Run(ctx context.Context) {
+	conn, err := net.ListenMulticastUDP("udp4", nil, mdnsGroupAddr)
+	if err != nil {
+		srvrLog.Warnf("Unable to start mDNS discovery: %v", err)
+		return
+	}
+	d.send = func(msg []byte) error {
+		_, err := conn.WriteToUDP(msg, mdnsGroupAddr)
+		return err
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		buf := make([]byte, mdnsMaxPacketSize)
+		for {
+			n, src, err := conn.ReadFromUDP(buf)
+			if err != nil {
+				if ctx.Err() == nil {
+					srvrLog.Warnf("mDNS discovery stopped: %v", err)
+				}
+				return
+			}
+			d.handleMessage(ctx, buf[:n], src)
+		}
+	}()
+
+	srvrLog.Infof("Discovering nodes on the local network via mDNS")
+	d.announce()
+	d.sendMessage(mdnsQuery())
+	ticker := time.NewTicker(mdnsQueryInterval)
+out:
+	for {
+		select {
+		case <-ticker.C:
+			d.sendMessage(mdnsQuery())
+		case <-ctx.Done():
+			break out
+		}
+	}
+	ticker.Stop()
+	conn.Close()
+	wg.Wait()
+}
@@ -0,0 +1,201 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/decred/dcrd/connmgr/v3"
+)
+
+// TestMDNSMessages ensures multicast DNS queries and responses round trip
+// through the DNS wire format and that the nodes advertised by responses are
+// parsed as expected, including those with compressed names.
+func TestMDNSMessages(t *testing.T) {
+	t.Parallel()
+
+	query, err := mdnsQuery().marshal()
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %v", err)
+	}
+	m, err := parseDNSMessage(query)
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	if !isMDNSQuery(m) {
+		t.Fatal("query was not detected as an mDNS query")
+	}
+	if _, ok := parseMDNSAnnouncement(m); ok {
+		t.Fatal("query was parsed as an announcement")
+	}
+
+	want := &mdnsAnnouncement{id: "0123456789abcdef", network: "simnet",
+		port: 18555}
+	resp, err := mdnsResponse(want)
+	if err != nil {
+		t.Fatalf("unexpected response error: %v", err)
+	}
+	respBytes, err := resp.marshal()
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %v", err)
+	}
+	m, err = parseDNSMessage(respBytes)
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	if isMDNSQuery(m) {
+		t.Fatal("response was detected as an mDNS query")
+	}
+	got, ok := parseMDNSAnnouncement(m)
+	if !ok || !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected announcement -- got %+v, want %+v", got, want)
+	}
+
+	// Ensure responses with compressed names, as commonly produced by other
+	// responders, and the cache flush bit set are parsed.
+	compressed := []byte{
+		0x00, 0x00, 0x84, 0x00, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x00,
+		// PTR _dcrd._tcp.local. -> a._dcrd._tcp.local.
+		0x05, '_', 'd', 'c', 'r', 'd', 0x04, '_', 't', 'c', 'p',
+		0x05, 'l', 'o', 'c', 'a', 'l', 0x00,
+		0x00, 0x0c, 0x00, 0x01, 0x00, 0x00, 0x00, 0x78, 0x00, 0x04,
+		0x01, 'a', 0xc0, 0x0c,
+		// SRV a._dcrd._tcp.local. port 9108 target a.local.
+		0xc0, 0x28,
+		0x00, 0x21, 0x80, 0x01, 0x00, 0x00, 0x00, 0x78, 0x00, 0x0a,
+		0x00, 0x00, 0x00, 0x00, 0x23, 0x94, 0x01, 'a', 0xc0, 0x17,
+		// TXT a._dcrd._tcp.local. net=mainnet id=a
+		0xc0, 0x28,
+		0x00, 0x10, 0x80, 0x01, 0x00, 0x00, 0x00, 0x78, 0x00, 0x11,
+		0x0b, 'n', 'e', 't', '=', 'm', 'a', 'i', 'n', 'n', 'e', 't',
+		0x04, 'i', 'd', '=', 'a',
+	}
+	m, err = parseDNSMessage(compressed)
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	got, ok = parseMDNSAnnouncement(m)
+	want = &mdnsAnnouncement{id: "a", network: "mainnet", port: 9108}
+	if !ok || !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected announcement -- got %+v, want %+v", got, want)
+	}
+
+	// Ensure truncated messages and compression loops are rejected.
+	if _, err := parseDNSMessage(respBytes[:len(respBytes)-1]); err == nil {
+		t.Fatal("did not receive error for truncated message")
+	}
+	loop := []byte{
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0xc0, 0x0c, 0x00, 0x0c, 0x00, 0x01,
+	}
+	if _, err := parseDNSMessage(loop); err == nil {
+		t.Fatal("did not receive error for compression loop")
+	}
+}
+
+// TestMDNSDiscovery ensures the local node is advertised in response to
+// queries only when it is listening and that connections to discovered nodes
+// are requested as expected.
+func TestMDNSDiscovery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		localID     string
+		localPort   uint16
+		remote      mdnsAnnouncement
+		wantConnect bool
+	}{{
+		name:        "lower id dials",
+		localID:     "1",
+		localPort:   18555,
+		remote:      mdnsAnnouncement{id: "2", network: "simnet", port: 18556},
+		wantConnect: true,
+	}, {
+		name:      "higher id waits",
+		localID:   "2",
+		localPort: 18555,
+		remote:    mdnsAnnouncement{id: "1", network: "simnet", port: 18556},
+	}, {
+		name:        "not listening dials",
+		localID:     "2",
+		remote:      mdnsAnnouncement{id: "1", network: "simnet", port: 18556},
+		wantConnect: true,
+	}, {
+		name:      "other network ignored",
+		localID:   "1",
+		localPort: 18555,
+		remote:    mdnsAnnouncement{id: "2", network: "regnet", port: 18556},
+	}, {
+		name:      "local node ignored",
+		localID:   "1",
+		localPort: 18555,
+		remote:    mdnsAnnouncement{id: "1", network: "simnet", port: 18555},
+	}}
+
+	ctx := context.Background()
+	src := &net.UDPAddr{IP: net.IPv4(192, 168, 1, 20), Port: 5353}
+	query, err := mdnsQuery().marshal()
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %v", err)
+	}
+	for _, test := range tests {
+		connected := make(chan string, 2)
+		d := &mdnsDiscovery{
+			local: mdnsAnnouncement{
+				id:      test.localID,
+				network: "simnet",
+				port:    test.localPort,
+			},
+			connect: func(_ context.Context, c *connmgr.ConnReq) {
+				connected <- c.Addr.String()
+			},
+			connReqs: make(map[string]*connmgr.ConnReq),
+		}
+		var sent [][]byte
+		d.send = func(msg []byte) error {
+			sent = append(sent, msg)
+			return nil
+		}
+
+		// Ensure the local node is only advertised when listening.
+		d.handleMessage(ctx, query, src)
+		if wantSent := test.localPort != 0; (len(sent) == 1) != wantSent {
+			t.Errorf("%q: unexpected number of responses sent: %d",
+				test.name, len(sent))
+			continue
+		}
+
+		// Ensure the connection to the remote node is only requested once
+		// while it is pending.
+		resp, err := mdnsResponse(&test.remote)
+		if err != nil {
+			t.Fatalf("%q: unexpected response error: %v", test.name, err)
+		}
+		respBytes, err := resp.marshal()
+		if err != nil {
+			t.Fatalf("%q: unexpected marshal error: %v", test.name, err)
+		}
+		d.handleMessage(ctx, respBytes, src)
+		d.handleMessage(ctx, respBytes, src)
+		if !test.wantConnect {
+			if len(d.connReqs) != 0 {
+				t.Errorf("%q: unexpected connection request", test.name)
+			}
+			continue
+		}
+		if addr := <-connected; addr != "192.168.1.20:18556" {
+			t.Errorf("%q: unexpected connection address %s", test.name,
+				addr)
+		}
+		if len(d.connReqs) != 1 {
+			t.Errorf("%q: unexpected number of connection requests: %d",
+				test.name, len(d.connReqs))
+		}
+	}
+}
@@ -35,6 +35,8 @@ A quick overview of the major features peer provides are as follows:
    and avoidance
  - Automatic periodic keep-alive pinging and pong responses
  - Random nonce generation and self connection detection
+ - Optional authentication of long-term peer identity keys
+ - Optional per-message-type payload size and rate limits
  - Snapshottable peer statistics such as the total number of bytes read and
    written, the remote address, user agent, and negotiated protocol version
  - Helper functions pushing addresses, getblocks, getheaders, and reject
@@ -68,6 +70,26 @@ Disconnect to disconnect from the peer and clean up all resources.
 WaitForDisconnect can be used to block until peer disconnection and resource
 cleanup has completed.
 
+Peer Authentication
+
+Peers that are configured with a long-term identity key and advertise the
+SFNodePeerAuth service prove ownership of their keys to each other via peerauth
+messages immediately after exchanging version messages.  The signatures commit
+to the version nonces of both sides of the connection, the addresses both sides
+reported for each other in their version messages, and the identity keys of
+both peers, so they can't be replayed on other connections or in the other
+direction.  The AuthorizePeer callback is then invoked with the authenticated
+key, or nil when the remote peer did not authenticate, which allows the caller
+to restrict connections to a set of known peers.
+
+Note that peer authentication does NOT protect against an active
+man-in-the-middle.  No session key is derived and the messages that follow the
+handshake are neither encrypted nor authenticated, so an attacker that is able
+to intercept the connection can relay the handshake between both peers
+unmodified and then read, drop, or inject messages at will.  Peer
+authentication only proves that the holders of the identity keys took part in
+the handshake.
+
 Callbacks
 
 In order to do anything useful with a peer, it is necessary to react to decred
@@ -129,6 +151,16 @@ A snapshot of the current peer statistics can be obtained with the StatsSnapshot
 function.  This includes statistics such as the total number of bytes read and
 written, the remote address, user agent, and negotiated protocol version.
 
+Message Limits
+
+The MessageLimits field of the Config struct may be used to impose limits on
+the maximum payload size and rate of messages of specific types received from
+the remote peer that are stricter than those imposed by the protocol.  Messages
+that exceed the maximum payload size are rejected before their payload is read
+into memory and the peer is disconnected when any of the limits are exceeded.
+The MsgStats function provides the number of messages and bytes received along
+with the number of limit violations per message type to help diagnose abuse.
+
 Logging
 
 This package provides extensive logging capabilities through the UseLogger
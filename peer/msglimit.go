@@ -0,0 +1,158 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peer
+
+import (
+	"fmt"
+	"time"
+)
+
+// MessageLimit defines limits that are enforced on the messages of a given
+// type received from remote peers in addition to the limits imposed by the
+// protocol.  Peers that exceed any of the limits are disconnected.
+type MessageLimit struct {
+	// MaxPayload is the maximum payload size, in bytes, of the messages.
+	// Messages that exceed it are rejected before their payload is read into
+	// memory.  A value of zero means only the maximum payload size imposed by
+	// the protocol for the message type is enforced.
+	MaxPayload uint32
+
+	// MaxRate is the maximum sustained rate, in messages per second, at
+	// which the messages may be received.  A value of zero disables the rate
+	// limit.
+	MaxRate float64
+
+	// Burst is the maximum number of messages that may be received in quick
+	// succession before the sustained rate is enforced.  A value of zero is
+	// treated as one.  It has no effect when MaxRate is zero.
+	Burst uint32
+}
+
+// MsgStats houses statistics about the messages of a given type received from
+// a remote peer which are useful for diagnosing abuse.
+type MsgStats struct {
+	// Received is the number of messages successfully received.
+	Received uint64
+
+	// BytesRecv is the total number of bytes of the successfully received
+	// messages including their headers.
+	BytesRecv uint64
+
+	// Oversized is the number of messages rejected due to exceeding the
+	// configured maximum payload size.
+	Oversized uint64
+
+	// RateLimited is the number of messages rejected due to exceeding the
+	// configured maximum rate.
+	RateLimited uint64
+}
+
+// msgLimitError describes a message from a remote peer that was rejected due
+// to exceeding a configured message limit.
+type msgLimitError struct {
+	command     string
+	description string
+}
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e *msgLimitError) Error() string {
+	return e.description
+}
+
+// msgRateLimiter implements a token bucket that limits the rate at which
+// messages of a given type are received.
+type msgRateLimiter struct {
+	tokens     float64
+	lastUpdate time.Time
+}
+
+// allow refills the bucket according to the time elapsed since the last update
+// and the provided limit and then attempts to consume a token for a message
+// received at the provided time.  It returns whether or not the message is
+// within the limit.
+func (l *msgRateLimiter) allow(limit *MessageLimit, now time.Time) bool {
+	burst := float64(limit.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	if l.lastUpdate.IsZero() {
+		l.tokens = burst
+	} else if elapsed := now.Sub(l.lastUpdate); elapsed > 0 {
+		l.tokens += elapsed.Seconds() * limit.MaxRate
+		if l.tokens > burst {
+			l.tokens = burst
+		}
+	}
+	l.lastUpdate = now
+
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// msgPayloadLimit returns the configured maximum payload size for messages
+// with the provided command or zero when there is no configured limit.
+//
+// This function is safe for concurrent access.
+func (p *Peer) msgPayloadLimit(command string) uint32 {
+	return p.cfg.MessageLimits[command].MaxPayload
+}
+
+// recordMsgStats updates the statistics for the messages with the provided
+// command via the provided function.
+//
+// This function is safe for concurrent access.
+func (p *Peer) recordMsgStats(command string, update func(stats *MsgStats)) {
+	p.statsMtx.Lock()
+	stats, ok := p.msgStats[command]
+	if !ok {
+		stats = new(MsgStats)
+		p.msgStats[command] = stats
+	}
+	update(stats)
+	p.statsMtx.Unlock()
+}
+
+// checkMsgRate returns an error when receiving a message with the provided
+// command at the provided time exceeds the configured maximum rate for the
+// message type.
+//
+// This function MUST only be called from the goroutine that reads messages
+// from the remote peer.
+func (p *Peer) checkMsgRate(command string, now time.Time) error {
+	limit, ok := p.cfg.MessageLimits[command]
+	if !ok || limit.MaxRate == 0 {
+		return nil
+	}
+
+	limiter, ok := p.msgRateLimiters[command]
+	if !ok {
+		limiter = new(msgRateLimiter)
+		p.msgRateLimiters[command] = limiter
+	}
+	if limiter.allow(&limit, now) {
+		return nil
+	}
+
+	str := fmt.Sprintf("received %s messages faster than the limit of %v "+
+		"per second", command, limit.MaxRate)
+	return &msgLimitError{command: command, description: str}
+}
+
+// MsgStats returns a snapshot of the statistics about the messages received
+// from the remote peer keyed by their command.
+//
+// This function is safe for concurrent access.
+func (p *Peer) MsgStats() map[string]MsgStats {
+	p.statsMtx.RLock()
+	msgStats := make(map[string]MsgStats, len(p.msgStats))
+	for command, stats := range p.msgStats {
+		msgStats[command] = *stats
+	}
+	p.statsMtx.RUnlock()
+	return msgStats
+}
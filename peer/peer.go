@@ -7,6 +7,7 @@ package peer
 
 import (
 	"bytes"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
@@ -19,6 +20,8 @@ import (
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
+	"github.com/decred/dcrd/dcrec/secp256k1/v3/schnorr"
 	"github.com/decred/dcrd/lru"
 	"github.com/decred/dcrd/wire"
 	"github.com/decred/go-socks/socks"
@@ -27,7 +30,7 @@ import (
 
 const (
 	// MaxProtocolVersion is the max protocol version the peer supports.
-	MaxProtocolVersion = wire.CFilterV2Version
+	MaxProtocolVersion = wire.BloomFilterVersion
 
 	// outputBufferSize is the number of elements the output channels use.
 	outputBufferSize = 5000
@@ -177,6 +180,31 @@ type MessageListeners struct {
 	// OnFeeFilter is invoked when a peer receives a feefilter wire message.
 	OnFeeFilter func(p *Peer, msg *wire.MsgFeeFilter)
 
+	// OnReqRecon is invoked when a peer receives a reqrecon wire message.
+	OnReqRecon func(p *Peer, msg *wire.MsgReqRecon)
+
+	// OnReconSketch is invoked when a peer receives a reconsketch wire
+	// message.
+	OnReconSketch func(p *Peer, msg *wire.MsgReconSketch)
+
+	// OnReconDiff is invoked when a peer receives a recondiff wire message.
+	OnReconDiff func(p *Peer, msg *wire.MsgReconDiff)
+
+	// OnFilterLoad is invoked when a peer receives a filterload wire
+	// message.
+	OnFilterLoad func(p *Peer, msg *wire.MsgFilterLoad)
+
+	// OnFilterAdd is invoked when a peer receives a filteradd wire message.
+	OnFilterAdd func(p *Peer, msg *wire.MsgFilterAdd)
+
+	// OnFilterClear is invoked when a peer receives a filterclear wire
+	// message.
+	OnFilterClear func(p *Peer, msg *wire.MsgFilterClear)
+
+	// OnMerkleBlock is invoked when a peer receives a merkleblock wire
+	// message.
+	OnMerkleBlock func(p *Peer, msg *wire.MsgMerkleBlock)
+
 	// OnVersion is invoked when a peer receives a version wire message.
 	// The caller may return a reject message in which case the message will
 	// be sent to the peer and the peer will be disconnected.
@@ -267,6 +295,25 @@ type Config struct {
 	// IdleTimeout is the duration of inactivity before a peer is timed
 	// out in seconds.
 	IdleTimeout time.Duration
+
+	// IdentityKey specifies the long-term identity key used to authenticate
+	// the local peer to remote peers.  It must be specified when Services
+	// includes SFNodePeerAuth and is otherwise ignored.
+	IdentityKey *secp256k1.PrivateKey
+
+	// AuthorizePeer is invoked once the remote peer has been authenticated,
+	// which only happens when both peers advertise the SFNodePeerAuth
+	// service, and before the OnVersion listener.  The identity key is nil
+	// when the remote peer was not authenticated.  The peer is disconnected
+	// when it returns false.  This field can be omitted in which case all
+	// peers are authorized.
+	AuthorizePeer func(p *Peer, identityKey *secp256k1.PublicKey) bool
+
+	// MessageLimits specifies additional limits to enforce on the messages
+	// received from the remote peer keyed by their command.  This field can
+	// be omitted in which case only the limits imposed by the protocol are
+	// enforced.
+	MessageLimits map[string]MessageLimit
 }
 
 // minUint32 is a helper function to return the minimum of two uint32s.
@@ -369,6 +416,8 @@ type StatsSnap struct {
 	LastPingNonce  uint64
 	LastPingTime   time.Time
 	LastPingMicros int64
+	IdentityKey    *secp256k1.PublicKey
+	MsgStats       map[string]MsgStats
 }
 
 // HashFunc is a function which returns a block hash, height and error
@@ -437,6 +486,11 @@ type Peer struct {
 	sendHeadersPreferred bool   // peer sent a sendheaders message
 	versionSent          bool
 	verAckReceived       bool
+	localNonce           uint64               // nonce of local version msg
+	remoteNonce          uint64               // nonce of remote version msg
+	localAddrYou         wire.NetAddress      // addryou of local version msg
+	remoteAddrYou        wire.NetAddress      // addryou of remote version msg
+	identityKey          *secp256k1.PublicKey // authenticated remote identity
 
 	knownInventory     lru.Cache
 	prevGetBlocksMtx   sync.Mutex
@@ -457,6 +511,12 @@ type Peer struct {
 	lastPingNonce      uint64    // Set to nonce if we have a pending ping.
 	lastPingTime       time.Time // Time we sent last ping.
 	lastPingMicros     int64     // Time for last ping to return.
+	msgStats           map[string]*MsgStats
+
+	// msgRateLimiters tracks the rate of messages received for the message
+	// types with a configured rate limit.  It is only accessed by the
+	// goroutine that reads messages from the remote peer.
+	msgRateLimiters map[string]*msgRateLimiter
 
 	stallControl  chan stallControlMsg
 	outputQueue   chan outMsg
@@ -532,6 +592,7 @@ func (p *Peer) StatsSnapshot() *StatsSnap {
 	userAgent := p.userAgent
 	services := p.services
 	protocolVersion := p.advertisedProtoVer
+	identityKey := p.identityKey
 	p.flagsMtx.Unlock()
 
 	// Get a copy of all relevant flags and stats.
@@ -553,9 +614,12 @@ func (p *Peer) StatsSnapshot() *StatsSnap {
 		LastPingNonce:  p.lastPingNonce,
 		LastPingMicros: p.lastPingMicros,
 		LastPingTime:   p.lastPingTime,
+		IdentityKey:    identityKey,
 	}
 
 	p.statsMtx.RUnlock()
+
+	statsSnap.MsgStats = p.MsgStats()
 	return statsSnap
 }
 
@@ -675,6 +739,19 @@ func (p *Peer) VersionKnown() bool {
 	return versionKnown
 }
 
+// IdentityKey returns the long-term identity key the remote peer
+// authenticated with during protocol negotiation.  It is nil when the remote
+// peer was not authenticated.
+//
+// This function is safe for concurrent access.
+func (p *Peer) IdentityKey() *secp256k1.PublicKey {
+	p.flagsMtx.Lock()
+	identityKey := p.identityKey
+	p.flagsMtx.Unlock()
+
+	return identityKey
+}
+
 // VerAckReceived returns whether or not a verack message was received by the
 // peer.
 //
@@ -985,15 +1062,48 @@ func (p *Peer) readMessage() (wire.Message, []byte, error) {
 	if err != nil {
 		return nil, nil, err
 	}
-	n, msg, buf, err := wire.ReadMessageN(p.conn, p.ProtocolVersion(),
-		p.cfg.Net)
+
+	// Keep track of the command of the message when there are configured
+	// message limits so violations can be attributed to the message type.
+	var command string
+	var limit wire.PayloadLimitFunc
+	if len(p.cfg.MessageLimits) > 0 {
+		limit = func(cmd string) uint32 {
+			command = cmd
+			return p.msgPayloadLimit(cmd)
+		}
+	}
+	n, msg, buf, err := wire.ReadMessageLimitedN(p.conn, p.ProtocolVersion(),
+		p.cfg.Net, limit)
 	atomic.AddUint64(&p.bytesReceived, uint64(n))
 	if p.cfg.Listeners.OnRead != nil {
 		p.cfg.Listeners.OnRead(p, n, msg, err)
 	}
 	if err != nil {
+		// The payload size limits imposed by the protocol are checked before
+		// the configured limit, so a payload that is too large for a known
+		// command is due to the configured limit.
+		if command != "" && errors.Is(err, wire.ErrPayloadTooLarge) {
+			p.recordMsgStats(command, func(stats *MsgStats) {
+				stats.Oversized++
+			})
+		}
+		return nil, nil, err
+	}
+
+	// Update the statistics for the message type and enforce any configured
+	// rate limit for it.
+	command = msg.Command()
+	if err := p.checkMsgRate(command, time.Now()); err != nil {
+		p.recordMsgStats(command, func(stats *MsgStats) {
+			stats.RateLimited++
+		})
 		return nil, nil, err
 	}
+	p.recordMsgStats(command, func(stats *MsgStats) {
+		stats.Received++
+		stats.BytesRecv += uint64(n)
+	})
 
 	// Only construct expensive log strings when the logging level requires it.
 	if log.Level() <= slog.LevelDebug {
@@ -1290,9 +1400,14 @@ out:
 				// NOTE: Ideally this would include the command in the header if
 				// at least that much of the message was valid, but that is not
 				// currently exposed by wire, so just used malformed for the
-				// command.
-				p.PushRejectMsg("malformed", wire.RejectMalformed, errMsg, nil,
-					true)
+				// command unless the message was rejected due to exceeding a
+				// configured rate limit.
+				command, code := "malformed", wire.RejectMalformed
+				var lErr *msgLimitError
+				if errors.As(err, &lErr) {
+					command, code = lErr.command, wire.RejectInvalid
+				}
+				p.PushRejectMsg(command, code, errMsg, nil, true)
 			}
 
 			if nErr, ok := err.(net.Error); ok && nErr.Timeout() {
@@ -1314,6 +1429,13 @@ out:
 				"duplicate version message", nil, true)
 			break out
 
+		case *wire.MsgPeerAuth:
+			// Peer authentication only happens during protocol
+			// negotiation.
+			log.Debugf("Received unexpected peerauth message from peer "+
+				"%v -- disconnecting", p)
+			break out
+
 		case *wire.MsgVerAck:
 
 			// No read lock is necessary because verAckReceived is not written
@@ -1466,6 +1588,41 @@ out:
 				p.cfg.Listeners.OnCFilterV2(p, msg)
 			}
 
+		case *wire.MsgReqRecon:
+			if p.cfg.Listeners.OnReqRecon != nil {
+				p.cfg.Listeners.OnReqRecon(p, msg)
+			}
+
+		case *wire.MsgReconSketch:
+			if p.cfg.Listeners.OnReconSketch != nil {
+				p.cfg.Listeners.OnReconSketch(p, msg)
+			}
+
+		case *wire.MsgReconDiff:
+			if p.cfg.Listeners.OnReconDiff != nil {
+				p.cfg.Listeners.OnReconDiff(p, msg)
+			}
+
+		case *wire.MsgFilterLoad:
+			if p.cfg.Listeners.OnFilterLoad != nil {
+				p.cfg.Listeners.OnFilterLoad(p, msg)
+			}
+
+		case *wire.MsgFilterAdd:
+			if p.cfg.Listeners.OnFilterAdd != nil {
+				p.cfg.Listeners.OnFilterAdd(p, msg)
+			}
+
+		case *wire.MsgFilterClear:
+			if p.cfg.Listeners.OnFilterClear != nil {
+				p.cfg.Listeners.OnFilterClear(p, msg)
+			}
+
+		case *wire.MsgMerkleBlock:
+			if p.cfg.Listeners.OnMerkleBlock != nil {
+				p.cfg.Listeners.OnMerkleBlock(p, msg)
+			}
+
 		default:
 			log.Debugf("Received unhandled message of type %v "+
 				"from %v", rmsg.Command(), p)
@@ -1798,11 +1955,11 @@ func (p *Peer) Disconnect() {
 // readRemoteVersionMsg waits for the next message to arrive from the remote
 // peer.  If the next message is not a version message or the version is not
 // acceptable then return an error.
-func (p *Peer) readRemoteVersionMsg() error {
+func (p *Peer) readRemoteVersionMsg() (*wire.MsgVersion, error) {
 	// Read their version message.
 	remoteMsg, _, err := p.readMessage()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Notify and disconnect clients if the first message is not a version
@@ -1813,12 +1970,12 @@ func (p *Peer) readRemoteVersionMsg() error {
 		rejectMsg := wire.NewMsgReject(msg.Command(), wire.RejectMalformed,
 			reason)
 		_ = p.writeMessage(rejectMsg)
-		return errors.New(reason)
+		return nil, errors.New(reason)
 	}
 
 	// Detect self connections.
 	if !allowSelfConns && sentNonces.Contains(msg.Nonce) {
-		return errors.New("disconnecting peer connected to self")
+		return nil, errors.New("disconnecting peer connected to self")
 	}
 
 	// Negotiate the protocol version and set the services to what the remote
@@ -1829,6 +1986,8 @@ func (p *Peer) readRemoteVersionMsg() error {
 	p.versionKnown = true
 	p.services = msg.Services
 	p.na.Services = msg.Services
+	p.remoteNonce = msg.Nonce
+	p.remoteAddrYou = msg.AddrYou
 	p.flagsMtx.Unlock()
 	log.Debugf("Negotiated protocol version %d for peer %s",
 		p.protocolVersion, p)
@@ -1848,6 +2007,14 @@ func (p *Peer) readRemoteVersionMsg() error {
 	p.userAgent = msg.UserAgent
 	p.flagsMtx.Unlock()
 
+	return msg, nil
+}
+
+// handleRemoteVersionMsg invokes the version callback for the passed version
+// message received from the remote peer and ensures its protocol version is
+// not too old.  It must only be called once the remote peer has been
+// authorized.
+func (p *Peer) handleRemoteVersionMsg(msg *wire.MsgVersion) error {
 	// Invoke the callback if specified.  In the case the callback returns a
 	// reject message, notify and disconnect the peer accordingly.
 	if p.cfg.Listeners.OnVersion != nil {
@@ -1875,9 +2042,210 @@ func (p *Peer) readRemoteVersionMsg() error {
 	return nil
 }
 
+// peerAuthHashTag is prefixed to the data signed by peers to authenticate
+// their identity keys in order to separate it from other uses of the keys.
+const peerAuthHashTag = "decred-peerauth"
+
+// peerAuthTranscript houses the details of a connection that the peerauth
+// signatures of both sides commit to.  The fields are named after the direction
+// of the connection from the point of view of the peer that established it so
+// both sides construct the same transcript.
+type peerAuthTranscript struct {
+	outboundNonce uint64
+	inboundNonce  uint64
+
+	// outboundAddrYou and inboundAddrYou are the addresses the outbound and
+	// inbound peer respectively reported for the other side of the
+	// connection in their version messages.
+	outboundAddrYou wire.NetAddress
+	inboundAddrYou  wire.NetAddress
+
+	// outboundKey and inboundKey are the identity keys of the outbound and
+	// inbound peer respectively.  The inbound key is not known until the
+	// inbound peer authenticates.
+	outboundKey *secp256k1.PublicKey
+	inboundKey  *secp256k1.PublicKey
+}
+
+// newPeerAuthTranscript returns the transcript of the connection to the remote
+// peer that the peerauth signatures of both sides commit to.  It must only be
+// called after the version messages have been exchanged.
+func (p *Peer) newPeerAuthTranscript() *peerAuthTranscript {
+	p.flagsMtx.Lock()
+	defer p.flagsMtx.Unlock()
+
+	if p.inbound {
+		return &peerAuthTranscript{
+			outboundNonce:   p.remoteNonce,
+			inboundNonce:    p.localNonce,
+			outboundAddrYou: p.remoteAddrYou,
+			inboundAddrYou:  p.localAddrYou,
+		}
+	}
+	return &peerAuthTranscript{
+		outboundNonce:   p.localNonce,
+		inboundNonce:    p.remoteNonce,
+		outboundAddrYou: p.localAddrYou,
+		inboundAddrYou:  p.remoteAddrYou,
+	}
+}
+
+// setKey sets the identity key of the outbound or inbound peer of the
+// transcript depending on the provided flag.
+func (t *peerAuthTranscript) setKey(inbound bool, key *secp256k1.PublicKey) {
+	if inbound {
+		t.inboundKey = key
+		return
+	}
+	t.outboundKey = key
+}
+
+// hash returns the hash the outbound or inbound peer, depending on the provided
+// flag, signs with its identity key to authenticate to the remote peer.
+//
+// The hash commits to the direction of the signer, the version message nonces
+// and reported addresses of both sides, and the identity key of the outbound
+// peer, so the signature is only valid for a single connection and direction.
+// Since the outbound peer authenticates first, only the signature of the
+// inbound peer additionally commits to the identity key of the inbound peer,
+// which in turn confirms to the outbound peer that both sides agree on both
+// keys.
+func (t *peerAuthTranscript) hash(inboundSigner bool) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(peerAuthHashTag)
+	if inboundSigner {
+		buf.WriteByte(1)
+	} else {
+		buf.WriteByte(0)
+	}
+	var scratch [8]byte
+	binary.LittleEndian.PutUint64(scratch[:], t.outboundNonce)
+	buf.Write(scratch[:])
+	binary.LittleEndian.PutUint64(scratch[:], t.inboundNonce)
+	buf.Write(scratch[:])
+	for _, na := range []*wire.NetAddress{&t.outboundAddrYou, &t.inboundAddrYou} {
+		var ip [16]byte
+		copy(ip[:], na.IP.To16())
+		buf.Write(ip[:])
+		binary.LittleEndian.PutUint16(scratch[:2], na.Port)
+		buf.Write(scratch[:2])
+	}
+	buf.Write(t.outboundKey.SerializeCompressed())
+	if inboundSigner {
+		buf.Write(t.inboundKey.SerializeCompressed())
+	}
+	return chainhash.HashB(buf.Bytes())
+}
+
+// writeLocalPeerAuthMsg sends a peerauth message that proves ownership of the
+// local identity key to the remote peer.
+func (p *Peer) writeLocalPeerAuthMsg(transcript *peerAuthTranscript) error {
+	transcript.setKey(p.inbound, p.cfg.IdentityKey.PubKey())
+	sig, err := schnorr.Sign(p.cfg.IdentityKey, transcript.hash(p.inbound))
+	if err != nil {
+		return err
+	}
+
+	var pubKey [wire.PeerAuthPubKeySize]byte
+	var sigBytes [wire.PeerAuthSignatureSize]byte
+	copy(pubKey[:], p.cfg.IdentityKey.PubKey().SerializeCompressed())
+	copy(sigBytes[:], sig.Serialize())
+	return p.writeMessage(wire.NewMsgPeerAuth(&pubKey, &sigBytes))
+}
+
+// readRemotePeerAuthMsg waits to receive a peerauth message from the remote
+// peer and verifies it proves ownership of the identity key it specifies.
+func (p *Peer) readRemotePeerAuthMsg(transcript *peerAuthTranscript) error {
+	remoteMsg, _, err := p.readMessage()
+	if err != nil {
+		return err
+	}
+
+	// Notify and disconnect clients that advertise support for peer
+	// authentication when the next message is not a peerauth message.
+	msg, ok := remoteMsg.(*wire.MsgPeerAuth)
+	if !ok {
+		reason := "a peerauth message must follow the version message " +
+			"when the SFNodePeerAuth service is advertised"
+		rejectMsg := wire.NewMsgReject(remoteMsg.Command(),
+			wire.RejectMalformed, reason)
+		_ = p.writeMessage(rejectMsg)
+		return errors.New(reason)
+	}
+
+	pubKey, err := secp256k1.ParsePubKey(msg.PubKey[:])
+	if err != nil {
+		return fmt.Errorf("invalid peer identity key: %v", err)
+	}
+	sig, err := schnorr.ParseSignature(msg.Signature[:])
+	if err != nil {
+		return fmt.Errorf("invalid peer identity signature: %v", err)
+	}
+	transcript.setKey(!p.inbound, pubKey)
+	if !sig.Verify(transcript.hash(!p.inbound), pubKey) {
+		return errors.New("peer identity signature does not verify")
+	}
+
+	p.flagsMtx.Lock()
+	p.identityKey = pubKey
+	p.flagsMtx.Unlock()
+	log.Debugf("Authenticated peer %s with identity key %x", p,
+		pubKey.SerializeCompressed())
+	return nil
+}
+
+// authenticate exchanges peerauth messages with the remote peer when both
+// peers advertise the SFNodePeerAuth service and then ensures the remote peer
+// is authorized via the AuthorizePeer callback when one is specified.  It must
+// only be called after the version messages have been exchanged.
+func (p *Peer) authenticate() error {
+	p.flagsMtx.Lock()
+	pver := p.protocolVersion
+	remoteServices := p.services
+	p.flagsMtx.Unlock()
+
+	if pver >= wire.PeerAuthVersion &&
+		p.cfg.Services&wire.SFNodePeerAuth == wire.SFNodePeerAuth &&
+		remoteServices&wire.SFNodePeerAuth == wire.SFNodePeerAuth {
+
+		// Inbound peers wait for the outbound peer to authenticate first
+		// so the exchange can't deadlock on unbuffered connections.
+		var err error
+		transcript := p.newPeerAuthTranscript()
+		if p.inbound {
+			err = p.readRemotePeerAuthMsg(transcript)
+			if err == nil {
+				err = p.writeLocalPeerAuthMsg(transcript)
+			}
+		} else {
+			err = p.writeLocalPeerAuthMsg(transcript)
+			if err == nil {
+				err = p.readRemotePeerAuthMsg(transcript)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if p.cfg.AuthorizePeer != nil && !p.cfg.AuthorizePeer(p, p.IdentityKey()) {
+		return errors.New("peer is not authorized")
+	}
+	return nil
+}
+
 // localVersionMsg creates a version message that can be used to send to the
 // remote peer.
 func (p *Peer) localVersionMsg() (*wire.MsgVersion, error) {
+	// Peers must have an identity key in order to advertise support for
+	// authenticating it.
+	if p.cfg.Services&wire.SFNodePeerAuth == wire.SFNodePeerAuth &&
+		p.cfg.IdentityKey == nil {
+
+		return nil, errors.New("the SFNodePeerAuth service requires an " +
+			"identity key")
+	}
+
 	var blockNum int64
 	if p.cfg.NewestBlock != nil {
 		var err error
@@ -1953,30 +2321,46 @@ func (p *Peer) writeLocalVersionMsg() error {
 
 	p.flagsMtx.Lock()
 	p.versionSent = true
+	p.localNonce = localVerMsg.Nonce
+	p.localAddrYou = localVerMsg.AddrYou
 	p.flagsMtx.Unlock()
 	return nil
 }
 
 // negotiateInboundProtocol waits to receive a version message from the peer
-// then sends our version message. If the events do not occur in that order then
-// it returns an error.
+// then sends our version message and authenticates the peer. If the events do
+// not occur in that order then it returns an error.
 func (p *Peer) negotiateInboundProtocol() error {
-	if err := p.readRemoteVersionMsg(); err != nil {
+	remoteVerMsg, err := p.readRemoteVersionMsg()
+	if err != nil {
+		return err
+	}
+	if err := p.writeLocalVersionMsg(); err != nil {
+		return err
+	}
+	if err := p.authenticate(); err != nil {
 		return err
 	}
 
-	return p.writeLocalVersionMsg()
+	return p.handleRemoteVersionMsg(remoteVerMsg)
 }
 
 // negotiateOutboundProtocol sends our version message then waits to receive a
-// version message from the peer.  If the events do not occur in that order then
-// it returns an error.
+// version message from the peer and authenticates it.  If the events do not
+// occur in that order then it returns an error.
 func (p *Peer) negotiateOutboundProtocol() error {
 	if err := p.writeLocalVersionMsg(); err != nil {
 		return err
 	}
+	remoteVerMsg, err := p.readRemoteVersionMsg()
+	if err != nil {
+		return err
+	}
+	if err := p.authenticate(); err != nil {
+		return err
+	}
 
-	return p.readRemoteVersionMsg()
+	return p.handleRemoteVersionMsg(remoteVerMsg)
 }
 
 // start begins processing input and output messages.
@@ -2100,6 +2484,8 @@ func newPeerBase(cfgOrig *Config, inbound bool) *Peer {
 		cfg:             cfg,
 		services:        cfg.Services,
 		protocolVersion: protocolVersion,
+		msgStats:        make(map[string]*MsgStats),
+		msgRateLimiters: make(map[string]*msgRateLimiter),
 	}
 	return &p
 }
@@ -6,16 +6,19 @@
 package peer
 
 import (
+	"bytes"
 	"encoding/binary"
 	"errors"
 	"io"
 	"net"
+	"reflect"
 	"strconv"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
 	"github.com/decred/dcrd/wire"
 	"github.com/decred/go-socks/socks"
 )
@@ -405,6 +408,27 @@ func TestPeerListeners(t *testing.T) {
 			OnCFilterV2: func(p *Peer, msg *wire.MsgCFilterV2) {
 				ok <- msg
 			},
+			OnReqRecon: func(p *Peer, msg *wire.MsgReqRecon) {
+				ok <- msg
+			},
+			OnReconSketch: func(p *Peer, msg *wire.MsgReconSketch) {
+				ok <- msg
+			},
+			OnReconDiff: func(p *Peer, msg *wire.MsgReconDiff) {
+				ok <- msg
+			},
+			OnFilterLoad: func(p *Peer, msg *wire.MsgFilterLoad) {
+				ok <- msg
+			},
+			OnFilterAdd: func(p *Peer, msg *wire.MsgFilterAdd) {
+				ok <- msg
+			},
+			OnFilterClear: func(p *Peer, msg *wire.MsgFilterClear) {
+				ok <- msg
+			},
+			OnMerkleBlock: func(p *Peer, msg *wire.MsgMerkleBlock) {
+				ok <- msg
+			},
 		},
 		UserAgentName:    "peer",
 		UserAgentVersion: "1.0",
@@ -544,6 +568,36 @@ func TestPeerListeners(t *testing.T) {
 			"OnCFilterV2",
 			wire.NewMsgCFilterV2(&chainhash.Hash{}, nil, 0, nil),
 		},
+		{
+			"OnReqRecon",
+			wire.NewMsgReqRecon(10, 20),
+		},
+		{
+			"OnReconSketch",
+			wire.NewMsgReconSketch([]byte{0x01}),
+		},
+		{
+			"OnReconDiff",
+			wire.NewMsgReconDiff(true, nil),
+		},
+		{
+			"OnFilterLoad",
+			wire.NewMsgFilterLoad([]byte{0x01}, 10, 0, wire.BloomUpdateNone),
+		},
+		{
+			"OnFilterAdd",
+			wire.NewMsgFilterAdd([]byte{0x01}),
+		},
+		{
+			"OnFilterClear",
+			wire.NewMsgFilterClear(),
+		},
+		{
+			"OnMerkleBlock",
+			wire.NewMsgMerkleBlock(wire.NewBlockHeader(0, &chainhash.Hash{},
+				&chainhash.Hash{}, &chainhash.Hash{}, 1, [6]byte{},
+				1, 1, 1, 1, 1, 1, 1, 1, 1, [32]byte{}, 1)),
+		},
 		// only one version message is allowed
 		// only one verack message is allowed
 		{
@@ -865,6 +919,387 @@ func TestUpdateLastBlockHeight(t *testing.T) {
 	}
 }
 
+// TestPeerAuth ensures peers that advertise the SFNodePeerAuth service
+// authenticate their identity keys to each other during protocol negotiation
+// and that peers rejected by the authorization callback are disconnected.
+func TestPeerAuth(t *testing.T) {
+	inKey := secp256k1.PrivKeyFromBytes([]byte{0x01})
+	outKey := secp256k1.PrivKeyFromBytes([]byte{0x02})
+	otherKey := secp256k1.PrivKeyFromBytes([]byte{0x03})
+
+	// allowKey returns an authorization callback that only allows peers that
+	// authenticated with the provided key.
+	allowKey := func(key *secp256k1.PrivateKey) func(*Peer, *secp256k1.PublicKey) bool {
+		return func(p *Peer, identityKey *secp256k1.PublicKey) bool {
+			return identityKey != nil && identityKey.IsEqual(key.PubKey())
+		}
+	}
+
+	tests := []struct {
+		name       string
+		inCfg      Config
+		outCfg     Config
+		wantInKey  *secp256k1.PrivateKey // identity key seen by inbound peer
+		wantOutKey *secp256k1.PrivateKey // identity key seen by outbound peer
+		wantConn   bool
+	}{{
+		name: "both authenticated and authorized",
+		inCfg: Config{
+			Services:      wire.SFNodePeerAuth,
+			IdentityKey:   inKey,
+			AuthorizePeer: allowKey(outKey),
+		},
+		outCfg: Config{
+			Services:      wire.SFNodePeerAuth,
+			IdentityKey:   outKey,
+			AuthorizePeer: allowKey(inKey),
+		},
+		wantInKey:  outKey,
+		wantOutKey: inKey,
+		wantConn:   true,
+	}, {
+		name: "authentication not supported by outbound peer",
+		inCfg: Config{
+			Services:    wire.SFNodePeerAuth,
+			IdentityKey: inKey,
+		},
+		outCfg:   Config{},
+		wantConn: true,
+	}, {
+		name: "outbound peer not in allowed keys",
+		inCfg: Config{
+			Services:      wire.SFNodePeerAuth,
+			IdentityKey:   inKey,
+			AuthorizePeer: allowKey(otherKey),
+		},
+		outCfg: Config{
+			Services:    wire.SFNodePeerAuth,
+			IdentityKey: outKey,
+		},
+		wantConn: false,
+	}, {
+		name: "unauthenticated outbound peer with allowed keys",
+		inCfg: Config{
+			Services:      wire.SFNodePeerAuth,
+			IdentityKey:   inKey,
+			AuthorizePeer: allowKey(outKey),
+		},
+		outCfg:   Config{IdentityKey: outKey},
+		wantConn: false,
+	}, {
+		name: "old protocol version with allowed keys",
+		inCfg: Config{
+			Services:      wire.SFNodePeerAuth,
+			IdentityKey:   inKey,
+			AuthorizePeer: allowKey(outKey),
+		},
+		outCfg: Config{
+			Services:        wire.SFNodePeerAuth,
+			IdentityKey:     outKey,
+			ProtocolVersion: wire.PeerAuthVersion - 1,
+		},
+		wantConn: false,
+	}, {
+		name:     "missing identity key",
+		inCfg:    Config{Services: wire.SFNodePeerAuth},
+		outCfg:   Config{},
+		wantConn: false,
+	}}
+
+	for _, test := range tests {
+		verack := make(chan struct{}, 2)
+		onVerAck := func(p *Peer, msg *wire.MsgVerAck) {
+			verack <- struct{}{}
+		}
+		inCfg, outCfg := test.inCfg, test.outCfg
+		for _, cfg := range []*Config{&inCfg, &outCfg} {
+			cfg.Listeners.OnVerAck = onVerAck
+			cfg.UserAgentName = "peer"
+			cfg.UserAgentVersion = "1.0"
+			cfg.Net = wire.MainNet
+		}
+
+		inConn, outConn := pipe(
+			&conn{laddr: "10.0.0.1:9108", raddr: "10.0.0.2:9108"},
+			&conn{laddr: "10.0.0.2:9108", raddr: "10.0.0.1:9108"},
+		)
+		inPeer := NewInboundPeer(&inCfg)
+		inPeer.AssociateConnection(inConn)
+		outPeer, err := NewOutboundPeer(&outCfg, inConn.laddr)
+		if err != nil {
+			t.Fatalf("%q: NewOutboundPeer: unexpected err: %v", test.name,
+				err)
+		}
+		outPeer.AssociateConnection(outConn)
+
+		if !test.wantConn {
+			// Ensure the inbound peer is disconnected without ever
+			// receiving a verack.
+			disconnected := make(chan struct{})
+			go func() {
+				inPeer.WaitForDisconnect()
+				close(disconnected)
+			}()
+			select {
+			case <-disconnected:
+			case <-verack:
+				t.Errorf("%q: unexpected verack", test.name)
+			case <-time.After(time.Second):
+				t.Errorf("%q: peer did not disconnect", test.name)
+			}
+			outPeer.Disconnect()
+			inPeer.Disconnect()
+			outPeer.WaitForDisconnect()
+			continue
+		}
+
+		// Wait for the veracks from the protocol version negotiation.
+		for i := 0; i < 2; i++ {
+			select {
+			case <-verack:
+			case <-time.After(time.Second):
+				t.Fatalf("%q: verack timeout", test.name)
+			}
+		}
+
+		// Ensure the identity keys each peer authenticated are the
+		// expected values.
+		checkKey := func(p *Peer, want *secp256k1.PrivateKey) {
+			got := p.IdentityKey()
+			switch {
+			case want == nil && got != nil:
+				t.Errorf("%q: unexpected identity key for %v", test.name, p)
+			case want != nil && (got == nil || !got.IsEqual(want.PubKey())):
+				t.Errorf("%q: wrong identity key for %v", test.name, p)
+			}
+		}
+		checkKey(inPeer, test.wantInKey)
+		checkKey(outPeer, test.wantOutKey)
+
+		inPeer.Disconnect()
+		outPeer.Disconnect()
+		inPeer.WaitForDisconnect()
+		outPeer.WaitForDisconnect()
+	}
+}
+
+// TestPeerAuthTranscript ensures the hashes signed by peers to authenticate
+// commit to the direction of the signer and every detail of the connection
+// transcript known to the signer.
+func TestPeerAuthTranscript(t *testing.T) {
+	newTranscript := func() *peerAuthTranscript {
+		return &peerAuthTranscript{
+			outboundNonce:   1,
+			inboundNonce:    2,
+			outboundAddrYou: *wire.NewNetAddressIPPort(net.ParseIP("10.0.0.1"), 9108, 0),
+			inboundAddrYou:  *wire.NewNetAddressIPPort(net.ParseIP("10.0.0.2"), 9108, 0),
+			outboundKey:     secp256k1.PrivKeyFromBytes([]byte{0x01}).PubKey(),
+			inboundKey:      secp256k1.PrivKeyFromBytes([]byte{0x02}).PubKey(),
+		}
+	}
+	base := newTranscript()
+	if bytes.Equal(base.hash(false), base.hash(true)) {
+		t.Fatal("outbound and inbound signers commit to the same hash")
+	}
+
+	otherKey := secp256k1.PrivKeyFromBytes([]byte{0x03}).PubKey()
+	tests := []struct {
+		name         string
+		modify       func(*peerAuthTranscript)
+		wantOutbound bool // whether the outbound signer hash changes
+	}{{
+		name:         "outbound nonce",
+		modify:       func(tr *peerAuthTranscript) { tr.outboundNonce++ },
+		wantOutbound: true,
+	}, {
+		name:         "inbound nonce",
+		modify:       func(tr *peerAuthTranscript) { tr.inboundNonce++ },
+		wantOutbound: true,
+	}, {
+		name: "swapped nonces",
+		modify: func(tr *peerAuthTranscript) {
+			tr.outboundNonce, tr.inboundNonce = tr.inboundNonce,
+				tr.outboundNonce
+		},
+		wantOutbound: true,
+	}, {
+		name: "outbound addryou ip",
+		modify: func(tr *peerAuthTranscript) {
+			tr.outboundAddrYou.IP = net.ParseIP("10.0.0.3")
+		},
+		wantOutbound: true,
+	}, {
+		name:         "inbound addryou port",
+		modify:       func(tr *peerAuthTranscript) { tr.inboundAddrYou.Port++ },
+		wantOutbound: true,
+	}, {
+		name:         "outbound key",
+		modify:       func(tr *peerAuthTranscript) { tr.outboundKey = otherKey },
+		wantOutbound: true,
+	}, {
+		// The outbound peer authenticates before it knows the identity
+		// key of the inbound peer.
+		name:         "inbound key",
+		modify:       func(tr *peerAuthTranscript) { tr.inboundKey = otherKey },
+		wantOutbound: false,
+	}}
+	for _, test := range tests {
+		tr := newTranscript()
+		test.modify(tr)
+		outboundChanged := !bytes.Equal(tr.hash(false), base.hash(false))
+		if outboundChanged != test.wantOutbound {
+			t.Errorf("%q: outbound signer hash changed %v, want %v",
+				test.name, outboundChanged, test.wantOutbound)
+		}
+		if bytes.Equal(tr.hash(true), base.hash(true)) {
+			t.Errorf("%q: inbound signer hash did not change", test.name)
+		}
+	}
+}
+
+// TestMessageLimits ensures peers that send messages which exceed the
+// configured message limits are disconnected and that the message statistics
+// account for the received and rejected messages.
+func TestMessageLimits(t *testing.T) {
+	tests := []struct {
+		name       string                  // test description
+		limits     map[string]MessageLimit // limits imposed by inbound peer
+		msgs       []wire.Message          // messages sent by outbound peer
+		command    string                  // command of the sent messages
+		wantStats  MsgStats                // expected stats for the command
+		disconnect bool                    // whether inbound peer disconnects
+	}{{
+		name: "within rate limit",
+		limits: map[string]MessageLimit{
+			wire.CmdGetAddr: {MaxRate: 0.001, Burst: 2},
+		},
+		msgs:       []wire.Message{wire.NewMsgGetAddr(), wire.NewMsgGetAddr()},
+		command:    wire.CmdGetAddr,
+		wantStats:  MsgStats{Received: 2, BytesRecv: 48},
+		disconnect: false,
+	}, {
+		name: "exceeds rate limit",
+		limits: map[string]MessageLimit{
+			wire.CmdGetAddr: {MaxRate: 0.001, Burst: 2},
+		},
+		msgs: []wire.Message{wire.NewMsgGetAddr(), wire.NewMsgGetAddr(),
+			wire.NewMsgGetAddr()},
+		command:    wire.CmdGetAddr,
+		wantStats:  MsgStats{Received: 2, BytesRecv: 48, RateLimited: 1},
+		disconnect: true,
+	}, {
+		name: "exceeds max payload",
+		limits: map[string]MessageLimit{
+			wire.CmdPing: {MaxPayload: 4},
+		},
+		msgs:       []wire.Message{wire.NewMsgPing(1)},
+		command:    wire.CmdPing,
+		wantStats:  MsgStats{Oversized: 1},
+		disconnect: true,
+	}}
+
+	for _, test := range tests {
+		// Create a pair of peers that are connected to each other using a
+		// fake connection with only the inbound peer enforcing the limits.
+		verack := make(chan struct{}, 2)
+		received := make(chan struct{}, len(test.msgs))
+		outPeerCfg := Config{
+			Listeners: MessageListeners{
+				OnVerAck: func(p *Peer, msg *wire.MsgVerAck) {
+					verack <- struct{}{}
+				},
+			},
+			UserAgentName:    "peer",
+			UserAgentVersion: "1.0",
+			Net:              wire.MainNet,
+		}
+		inPeerCfg := outPeerCfg
+		inPeerCfg.Listeners.OnRead = func(p *Peer, n int, msg wire.Message, err error) {
+			if msg != nil && msg.Command() == test.command {
+				received <- struct{}{}
+			}
+		}
+		inPeerCfg.MessageLimits = test.limits
+		inConn, outConn := pipe(
+			&conn{laddr: "10.0.0.1:9108", raddr: "10.0.0.2:9108"},
+			&conn{laddr: "10.0.0.2:9108", raddr: "10.0.0.1:9108"},
+		)
+		outPeer, err := NewOutboundPeer(&outPeerCfg, inConn.laddr)
+		if err != nil {
+			t.Fatalf("%q: unexpected err: %v", test.name, err)
+		}
+		outPeer.AssociateConnection(outConn)
+		inPeer := NewInboundPeer(&inPeerCfg)
+		inPeer.AssociateConnection(inConn)
+
+		// Wait for the veracks from the initial protocol version negotiation.
+		for i := 0; i < 2; i++ {
+			select {
+			case <-verack:
+			case <-time.After(time.Second):
+				t.Fatalf("%q: verack timeout", test.name)
+			}
+		}
+
+		// Send the messages from the outbound peer and wait for the inbound
+		// peer to either read them all or disconnect.
+		for _, msg := range test.msgs {
+			outPeer.QueueMessage(msg, nil)
+		}
+		disconnected := make(chan struct{})
+		go func() {
+			inPeer.WaitForDisconnect()
+			close(disconnected)
+		}()
+		numReceived := 0
+		for numReceived < len(test.msgs) {
+			select {
+			case <-received:
+				numReceived++
+				continue
+			case <-disconnected:
+			case <-time.After(time.Second):
+				t.Fatalf("%q: timeout waiting for messages", test.name)
+			}
+			break
+		}
+
+		// Ensure the inbound peer only disconnects when a limit is exceeded.
+		select {
+		case <-disconnected:
+			if !test.disconnect {
+				t.Fatalf("%q: unexpected disconnect", test.name)
+			}
+		case <-time.After(100 * time.Millisecond):
+			if test.disconnect {
+				t.Fatalf("%q: peer did not disconnect", test.name)
+			}
+		}
+
+		// Ensure the stats for the version negotiation and the sent messages
+		// are as expected.
+		msgStats := inPeer.MsgStats()
+		if msgStats[wire.CmdVersion].Received != 1 {
+			t.Fatalf("%q: unexpected version stats: %+v", test.name,
+				msgStats[wire.CmdVersion])
+		}
+		if got := msgStats[test.command]; got != test.wantStats {
+			t.Fatalf("%q: unexpected %s stats -- got %+v, want %+v",
+				test.name, test.command, got, test.wantStats)
+		}
+		snap := inPeer.StatsSnapshot()
+		if !reflect.DeepEqual(snap.MsgStats, msgStats) {
+			t.Fatalf("%q: mismatched snapshot stats -- got %+v, want %+v",
+				test.name, snap.MsgStats, msgStats)
+		}
+
+		inPeer.Disconnect()
+		outPeer.Disconnect()
+		inPeer.WaitForDisconnect()
+		outPeer.WaitForDisconnect()
+	}
+}
+
 func init() {
 	// Allow self connection when running the tests.
 	allowSelfConns = true
@@ -0,0 +1,331 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/internal/rpcserver"
+	"github.com/decred/dcrd/wire"
+)
+
+const (
+	// maxPeerTelemetryEvents is the maximum number of peer telemetry events
+	// that are retained between dumps.  The oldest events are discarded once
+	// the limit is reached.
+	maxPeerTelemetryEvents = 100000
+
+	// peerTelemetryVersion is the version of the format of the files written
+	// when dumping the peer telemetry.  It must be increased whenever the
+	// format changes in a way that is not backwards compatible.
+	peerTelemetryVersion = 1
+
+	// peerTelemetryDirName is the name of the directory within the data
+	// directory that houses dumped peer telemetry files.
+	peerTelemetryDirName = "peertelemetry"
+
+	// These constants define the kinds of peer telemetry events.
+	telemetryEventConnect    = "connect"
+	telemetryEventDisconnect = "disconnect"
+	telemetryEventAddr       = "addr"
+	telemetryEventInv        = "inv"
+
+	// These constants define the directions of relayed messages.
+	telemetryDirRecv = "recv"
+	telemetryDirSent = "sent"
+
+	// These constants define the networks that peer and relayed addresses
+	// are classified as.
+	telemetryNetIPv4 = "ipv4"
+	telemetryNetIPv6 = "ipv6"
+	telemetryNetTor  = "tor"
+)
+
+// onionCatNet is the IPv6 address block used to encode Tor addresses
+// (fd87:d87e:eb43::/48).
+var onionCatNet = &net.IPNet{
+	IP:   net.ParseIP("fd87:d87e:eb43::"),
+	Mask: net.CIDRMask(48, 128),
+}
+
+// telemetryNetwork returns the network the provided address belongs to.
+func telemetryNetwork(na *wire.NetAddress) string {
+	switch {
+	case na.IP.To4() != nil:
+		return telemetryNetIPv4
+	case onionCatNet.Contains(na.IP):
+		return telemetryNetTor
+	}
+	return telemetryNetIPv6
+}
+
+// peerTelemetryEvent is a single anonymized peer telemetry event.  The fields
+// that apply depend on the kind of event.
+type peerTelemetryEvent struct {
+	// Time is the time of the event in milliseconds since the Unix epoch.
+	Time int64 `json:"time"`
+
+	// Event is the kind of event.
+	Event string `json:"event"`
+
+	// Conn is the locally-assigned identifier of the connection.  It is
+	// unique for the lifetime of the process.
+	Conn int32 `json:"conn"`
+
+	// Peer is an anonymized identifier of the remote address.  It remains
+	// the same for all connections to and from the same address for the
+	// lifetime of the process, but it can not be linked to the address.
+	Peer string `json:"peer"`
+
+	// The following fields only apply to connect events.
+	Inbound         bool   `json:"inbound,omitempty"`
+	Network         string `json:"network,omitempty"`
+	ProtocolVersion uint32 `json:"protocolversion,omitempty"`
+	Services        uint64 `json:"services,omitempty"`
+	UserAgent       string `json:"useragent,omitempty"`
+
+	// DurationMillis only applies to disconnect events and is the amount of
+	// time the connection was established.
+	DurationMillis int64 `json:"durationms,omitempty"`
+
+	// Direction only applies to addr events and is either "recv" or "sent".
+	Direction string `json:"direction,omitempty"`
+
+	// The following fields only apply to addr events and are the total number
+	// of relayed addresses along with the number for each network.
+	NumAddrs int `json:"numaddrs,omitempty"`
+	NumIPv4  int `json:"numipv4,omitempty"`
+	NumIPv6  int `json:"numipv6,omitempty"`
+	NumTor   int `json:"numtor,omitempty"`
+
+	// The following fields only apply to inv events and are the number of
+	// announced inventory vectors of each type.
+	NumBlocks int `json:"numblocks,omitempty"`
+	NumTxns   int `json:"numtxns,omitempty"`
+	NumOther  int `json:"numother,omitempty"`
+}
+
+// peerTelemetryHeader is the first entry of a dumped peer telemetry file and
+// describes the events that follow it.
+type peerTelemetryHeader struct {
+	Version    int    `json:"version"`
+	Network    string `json:"network"`
+	Start      int64  `json:"start"`
+	End        int64  `json:"end"`
+	NumEvents  int    `json:"numevents"`
+	NumDropped uint64 `json:"numdropped"`
+}
+
+// peerTelemetry records anonymized peer connection lifecycle events, address
+// relay patterns, and inventory announcement timing so they can be exported to
+// a structured file for network health research without packet captures.
+//
+// Remote addresses are never recorded.  Instead, each address is identified by
+// a keyed hash with a random key that is generated when the process starts and
+// is never exported.
+type peerTelemetry struct {
+	dataDir string
+	network string
+	key     [32]byte
+
+	// now returns the current time.  It is a field so it can be mocked by
+	// tests.
+	now func() time.Time
+
+	mtx        sync.Mutex
+	start      time.Time
+	events     []peerTelemetryEvent
+	next       int
+	numDropped uint64
+}
+
+// newPeerTelemetry returns a new peer telemetry recorder that writes dumped
+// files to a subdirectory of the provided data directory.
+func newPeerTelemetry(dataDir, network string) (*peerTelemetry, error) {
+	t := &peerTelemetry{
+		dataDir: dataDir,
+		network: network,
+		now:     time.Now,
+	}
+	if _, err := rand.Read(t.key[:]); err != nil {
+		return nil, err
+	}
+	t.start = t.now()
+	return t, nil
+}
+
+// peerID returns the anonymized identifier for the provided remote address.
+func (t *peerTelemetry) peerID(addr string) string {
+	mac := hmac.New(sha256.New, t.key[:])
+	mac.Write([]byte(addr))
+	return hex.EncodeToString(mac.Sum(nil)[:8])
+}
+
+// record adds the provided event for the given peer connection while
+// discarding the oldest event when the maximum number of events is reached.
+//
+// This function is safe for concurrent access.
+func (t *peerTelemetry) record(sp *serverPeer, event peerTelemetryEvent) {
+	event.Time = t.now().UnixNano() / int64(time.Millisecond)
+	event.Conn = sp.ID()
+	event.Peer = t.peerID(sp.Addr())
+
+	t.mtx.Lock()
+	if len(t.events) < maxPeerTelemetryEvents {
+		t.events = append(t.events, event)
+	} else {
+		t.events[t.next] = event
+		t.next = (t.next + 1) % maxPeerTelemetryEvents
+		t.numDropped++
+	}
+	t.mtx.Unlock()
+}
+
+// Connected records that the provided peer completed version negotiation.
+//
+// This function is safe for concurrent access.
+func (t *peerTelemetry) Connected(sp *serverPeer) {
+	event := peerTelemetryEvent{
+		Event:           telemetryEventConnect,
+		Inbound:         sp.Inbound(),
+		ProtocolVersion: sp.ProtocolVersion(),
+		Services:        uint64(sp.Services()),
+		UserAgent:       sp.UserAgent(),
+	}
+	if na := sp.NA(); na != nil {
+		event.Network = telemetryNetwork(na)
+	}
+	t.record(sp, event)
+}
+
+// Disconnected records that the provided peer, which previously completed
+// version negotiation, disconnected.
+//
+// This function is safe for concurrent access.
+func (t *peerTelemetry) Disconnected(sp *serverPeer) {
+	duration := t.now().Sub(sp.TimeConnected())
+	t.record(sp, peerTelemetryEvent{
+		Event:          telemetryEventDisconnect,
+		DurationMillis: int64(duration / time.Millisecond),
+	})
+}
+
+// AddrRelayed records that the provided addresses were either received from or
+// sent to the provided peer depending on the direction.
+//
+// This function is safe for concurrent access.
+func (t *peerTelemetry) AddrRelayed(sp *serverPeer, direction string, addrs []*wire.NetAddress) {
+	event := peerTelemetryEvent{
+		Event:     telemetryEventAddr,
+		Direction: direction,
+		NumAddrs:  len(addrs),
+	}
+	for _, na := range addrs {
+		switch telemetryNetwork(na) {
+		case telemetryNetIPv4:
+			event.NumIPv4++
+		case telemetryNetIPv6:
+			event.NumIPv6++
+		case telemetryNetTor:
+			event.NumTor++
+		}
+	}
+	t.record(sp, event)
+}
+
+// InvReceived records that the provided inventory vectors were announced by
+// the provided peer.
+//
+// This function is safe for concurrent access.
+func (t *peerTelemetry) InvReceived(sp *serverPeer, invList []*wire.InvVect) {
+	event := peerTelemetryEvent{Event: telemetryEventInv}
+	for _, iv := range invList {
+		switch iv.Type {
+		case wire.InvTypeBlock:
+			event.NumBlocks++
+		case wire.InvTypeTx:
+			event.NumTxns++
+		default:
+			event.NumOther++
+		}
+	}
+	t.record(sp, event)
+}
+
+// Dump writes all events recorded since the previous dump, ordered from oldest
+// to newest, to a new file in the peer telemetry directory and resets the
+// recorded events.  The file consists of a header line that describes the
+// events followed by one line per event, each of which is a JSON object.
+//
+// The recorded events are retained when writing the file fails.
+//
+// This function is safe for concurrent access and is part of the
+// rpcserver.PeerTelemetry interface implementation.
+func (t *peerTelemetry) Dump() (*rpcserver.PeerTelemetryDump, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	end := t.now()
+	dir := filepath.Join(t.dataDir, peerTelemetryDirName)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	fileName := fmt.Sprintf("peertelemetry-%s.jsonl",
+		end.UTC().Format("20060102-150405.000"))
+	path := filepath.Join(dir, fileName)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	// Write the header followed by the events ordered from oldest to newest.
+	// The oldest event is at the next index to be overwritten once the
+	// maximum number of events has been reached.
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	err = enc.Encode(&peerTelemetryHeader{
+		Version:    peerTelemetryVersion,
+		Network:    t.network,
+		Start:      t.start.Unix(),
+		End:        end.Unix(),
+		NumEvents:  len(t.events),
+		NumDropped: t.numDropped,
+	})
+	for i := 0; i < len(t.events) && err == nil; i++ {
+		err = enc.Encode(&t.events[(t.next+i)%len(t.events)])
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+
+	dump := &rpcserver.PeerTelemetryDump{
+		Path:       path,
+		NumEvents:  len(t.events),
+		NumDropped: t.numDropped,
+	}
+	t.start = end
+	t.events = nil
+	t.next = 0
+	t.numDropped = 0
+	return dump, nil
+}
@@ -0,0 +1,267 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/peer/v2"
+	"github.com/decred/dcrd/wire"
+)
+
+// newTestTelemetryPeer returns an unconnected server peer with the provided
+// remote address for use in the peer telemetry tests.
+func newTestTelemetryPeer(t *testing.T, addr string) *serverPeer {
+	t.Helper()
+
+	p, err := peer.NewOutboundPeer(&peer.Config{}, addr)
+	if err != nil {
+		t.Fatalf("failed to create peer: %v", err)
+	}
+	return &serverPeer{Peer: p}
+}
+
+// newTestPeerTelemetry returns a peer telemetry recorder that writes dumped
+// files to a new temporary directory along with a function to remove it.
+func newTestPeerTelemetry(t *testing.T) (*peerTelemetry, string, func()) {
+	t.Helper()
+
+	dataDir, err := ioutil.TempDir("", "peertelemetry")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	telemetry, err := newPeerTelemetry(dataDir, "simnet")
+	if err != nil {
+		os.RemoveAll(dataDir)
+		t.Fatalf("failed to create peer telemetry: %v", err)
+	}
+	return telemetry, dataDir, func() { os.RemoveAll(dataDir) }
+}
+
+// readPeerTelemetryDump reads the peer telemetry file at the provided path and
+// returns its header and events.
+func readPeerTelemetryDump(t *testing.T, path string) (*peerTelemetryHeader, []peerTelemetryEvent) {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open dump: %v", err)
+	}
+	defer f.Close()
+
+	var header peerTelemetryHeader
+	var events []peerTelemetryEvent
+	scanner := bufio.NewScanner(f)
+	for i := 0; scanner.Scan(); i++ {
+		if i == 0 {
+			if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+				t.Fatalf("failed to decode header: %v", err)
+			}
+			continue
+		}
+		var event peerTelemetryEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("failed to decode event %d: %v", i, err)
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("failed to read dump: %v", err)
+	}
+	return &header, events
+}
+
+// TestTelemetryNetwork ensures addresses are classified into the expected
+// networks.
+func TestTelemetryNetwork(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		ip   string
+		want string
+	}{
+		{ip: "10.0.0.1", want: telemetryNetIPv4},
+		{ip: "::ffff:10.0.0.1", want: telemetryNetIPv4},
+		{ip: "2001:db8::1", want: telemetryNetIPv6},
+		{ip: "fd87:d87e:eb43:1234::1", want: telemetryNetTor},
+		{ip: "fd87:d87e:eb44::1", want: telemetryNetIPv6},
+	}
+	for _, test := range tests {
+		na := &wire.NetAddress{IP: net.ParseIP(test.ip)}
+		if got := telemetryNetwork(na); got != test.want {
+			t.Errorf("%s: unexpected network -- got %s, want %s", test.ip,
+				got, test.want)
+		}
+	}
+}
+
+// TestPeerTelemetry ensures the peer telemetry records the expected anonymized
+// events and that dumping them writes the expected file and resets the
+// recorded events.
+func TestPeerTelemetry(t *testing.T) {
+	t.Parallel()
+
+	telemetry, dataDir, teardown := newTestPeerTelemetry(t)
+	defer teardown()
+	now := time.Unix(1600000000, 0)
+	telemetry.now = func() time.Time { return now }
+	telemetry.start = now
+
+	const addr1, addr2 = "10.0.0.1:9108", "10.0.0.2:9108"
+	sp1 := newTestTelemetryPeer(t, addr1)
+	sp2 := newTestTelemetryPeer(t, addr2)
+	telemetry.Connected(sp1)
+	telemetry.AddrRelayed(sp1, telemetryDirRecv, []*wire.NetAddress{
+		{IP: net.ParseIP("10.1.1.1")},
+		{IP: net.ParseIP("2001:db8::1")},
+		{IP: net.ParseIP("fd87:d87e:eb43::1")},
+		{IP: net.ParseIP("10.1.1.2")},
+	})
+	now = now.Add(time.Second)
+	telemetry.InvReceived(sp2, []*wire.InvVect{
+		{Type: wire.InvTypeBlock},
+		{Type: wire.InvTypeTx},
+		{Type: wire.InvTypeTx},
+		{Type: wire.InvTypeFilteredBlock},
+	})
+
+	dump, err := telemetry.Dump()
+	if err != nil {
+		t.Fatalf("failed to dump peer telemetry: %v", err)
+	}
+	if dump.NumEvents != 3 || dump.NumDropped != 0 {
+		t.Fatalf("unexpected dump counts -- got %d events, %d dropped, "+
+			"want 3 events, 0 dropped", dump.NumEvents, dump.NumDropped)
+	}
+	wantDir := filepath.Join(dataDir, peerTelemetryDirName)
+	if filepath.Dir(dump.Path) != wantDir {
+		t.Fatalf("unexpected dump directory -- got %s, want %s",
+			filepath.Dir(dump.Path), wantDir)
+	}
+
+	// Ensure the remote addresses are not included in the file.
+	contents, err := ioutil.ReadFile(dump.Path)
+	if err != nil {
+		t.Fatalf("failed to read dump: %v", err)
+	}
+	for _, addr := range []string{addr1, addr2, "10.0.0.1", "10.0.0.2"} {
+		if strings.Contains(string(contents), addr) {
+			t.Fatalf("dump contains remote address %s", addr)
+		}
+	}
+
+	header, events := readPeerTelemetryDump(t, dump.Path)
+	wantHeader := peerTelemetryHeader{
+		Version:   peerTelemetryVersion,
+		Network:   "simnet",
+		Start:     1600000000,
+		End:       1600000001,
+		NumEvents: 3,
+	}
+	if *header != wantHeader {
+		t.Fatalf("unexpected header -- got %+v, want %+v", *header,
+			wantHeader)
+	}
+
+	peer1, peer2 := telemetry.peerID(addr1), telemetry.peerID(addr2)
+	if peer1 == peer2 {
+		t.Fatalf("peer identifiers for different addresses are the same")
+	}
+	wantEvents := []peerTelemetryEvent{{
+		Time:            1600000000000,
+		Event:           telemetryEventConnect,
+		Peer:            peer1,
+		Network:         telemetryNetIPv4,
+		ProtocolVersion: wire.ProtocolVersion,
+	}, {
+		Time:      1600000000000,
+		Event:     telemetryEventAddr,
+		Peer:      peer1,
+		Direction: telemetryDirRecv,
+		NumAddrs:  4,
+		NumIPv4:   2,
+		NumIPv6:   1,
+		NumTor:    1,
+	}, {
+		Time:      1600000001000,
+		Event:     telemetryEventInv,
+		Peer:      peer2,
+		NumBlocks: 1,
+		NumTxns:   2,
+		NumOther:  1,
+	}}
+	if len(events) != len(wantEvents) {
+		t.Fatalf("unexpected number of events -- got %d, want %d",
+			len(events), len(wantEvents))
+	}
+	for i := range events {
+		if events[i] != wantEvents[i] {
+			t.Fatalf("unexpected event %d -- got %+v, want %+v", i,
+				events[i], wantEvents[i])
+		}
+	}
+
+	// Ensure the recorded events were reset by the dump.
+	now = now.Add(time.Second)
+	dump, err = telemetry.Dump()
+	if err != nil {
+		t.Fatalf("failed to dump peer telemetry: %v", err)
+	}
+	header, events = readPeerTelemetryDump(t, dump.Path)
+	if dump.NumEvents != 0 || len(events) != 0 || header.Start != 1600000001 {
+		t.Fatalf("recorded events were not reset -- got %d events, start %d",
+			len(events), header.Start)
+	}
+}
+
+// TestPeerTelemetryLimit ensures the oldest events are discarded once the
+// maximum number of events is reached and that the remaining events are
+// dumped from oldest to newest.
+func TestPeerTelemetryLimit(t *testing.T) {
+	t.Parallel()
+
+	telemetry, _, teardown := newTestPeerTelemetry(t)
+	defer teardown()
+
+	var now time.Time
+	telemetry.now = func() time.Time { return now }
+
+	// Record events with increasing timestamps so their order is known.
+	const numExtra = 5
+	sp := newTestTelemetryPeer(t, "10.0.0.1:9108")
+	for i := 0; i < maxPeerTelemetryEvents+numExtra; i++ {
+		now = time.Unix(0, int64(i)*int64(time.Millisecond))
+		telemetry.InvReceived(sp, []*wire.InvVect{{Type: wire.InvTypeTx}})
+	}
+
+	dump, err := telemetry.Dump()
+	if err != nil {
+		t.Fatalf("failed to dump peer telemetry: %v", err)
+	}
+	if dump.NumEvents != maxPeerTelemetryEvents || dump.NumDropped != numExtra {
+		t.Fatalf("unexpected dump counts -- got %d events, %d dropped, "+
+			"want %d events, %d dropped", dump.NumEvents, dump.NumDropped,
+			maxPeerTelemetryEvents, numExtra)
+	}
+	header, events := readPeerTelemetryDump(t, dump.Path)
+	if header.NumDropped != numExtra {
+		t.Fatalf("unexpected header dropped count -- got %d, want %d",
+			header.NumDropped, numExtra)
+	}
+	for i, event := range events {
+		if event.Time != int64(i+numExtra) {
+			t.Fatalf("unexpected event time at index %d -- got %d, want %d",
+				i, event.Time, i+numExtra)
+		}
+	}
+}
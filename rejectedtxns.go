@@ -0,0 +1,121 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/internal/rpcserver"
+	"github.com/decred/dcrd/wire"
+)
+
+// rejectedTxEntry houses a transaction in the rejected transaction cache.
+type rejectedTxEntry struct {
+	info rpcserver.RejectedTx
+
+	// tipHash is the hash of the tip of the best chain when the transaction
+	// was rejected.
+	tipHash chainhash.Hash
+
+	// permanent indicates the reason the transaction was rejected does not
+	// depend on the state of the chain, so it will continue to be rejected
+	// after new blocks are connected.
+	permanent bool
+}
+
+// rejectedTxCache is a bounded cache of the transactions most recently
+// rejected from peers along with the reasons they were rejected.  Once the
+// cache is full, the oldest entries are evicted to make room for new ones.
+//
+// It is consulted to avoid repeatedly validating transactions that peers
+// announce again after they were rejected.  Since most transactions are
+// rejected for reasons that depend on the state of the chain, such as
+// spending outputs that do not exist yet, entries only prevent transactions
+// from being validated again until a new block is connected unless they were
+// rejected for a reason that does not depend on the state of the chain.
+// Entries remain in the cache after that so they can still be queried.
+//
+// It is safe for concurrent access.
+type rejectedTxCache struct {
+	mtx     sync.Mutex
+	limit   int
+	entries map[chainhash.Hash]*list.Element
+	order   *list.List // Most recently rejected at the front.
+}
+
+// newRejectedTxCache returns a rejected transaction cache that holds up to the
+// provided number of entries.
+func newRejectedTxCache(limit int) *rejectedTxCache {
+	return &rejectedTxCache{
+		limit:   limit,
+		entries: make(map[chainhash.Hash]*list.Element, limit),
+		order:   list.New(),
+	}
+}
+
+// add records the provided rejected transaction along with the tip of the best
+// chain when it was rejected and whether the reason it was rejected depends on
+// the state of the chain.  It replaces any existing entry for the transaction.
+//
+// This function is safe for concurrent access.
+func (c *rejectedTxCache) add(info *rpcserver.RejectedTx, tipHash *chainhash.Hash, permanent bool) {
+	entry := &rejectedTxEntry{
+		info:      *info,
+		tipHash:   *tipHash,
+		permanent: permanent,
+	}
+
+	c.mtx.Lock()
+	if elem, ok := c.entries[info.Hash]; ok {
+		c.order.Remove(elem)
+		delete(c.entries, info.Hash)
+	}
+	if c.order.Len() >= c.limit {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*rejectedTxEntry).info.Hash)
+	}
+	c.entries[info.Hash] = c.order.PushFront(entry)
+	c.mtx.Unlock()
+}
+
+// isRejected returns whether or not the transaction with the provided hash is
+// known to be rejected when the best chain has the provided tip.  Transactions
+// that were rejected for paying insufficient fees are not reported as rejected
+// when feeExempt is set since peers that are exempt from the minimum relay fee
+// bypass the checks that rejected them.
+//
+// This function is safe for concurrent access.
+func (c *rejectedTxCache) isRejected(hash, tipHash *chainhash.Hash, feeExempt bool) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	elem, ok := c.entries[*hash]
+	if !ok {
+		return false
+	}
+	entry := elem.Value.(*rejectedTxEntry)
+	if feeExempt && entry.info.Code == wire.RejectInsufficientFee {
+		return false
+	}
+	return entry.permanent || entry.tipHash == *tipHash
+}
+
+// Entries returns the rejected transactions in the cache ordered from the most
+// recently rejected to the least recently rejected.
+//
+// This function is safe for concurrent access.
+func (c *rejectedTxCache) Entries() []rpcserver.RejectedTx {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	entries := make([]rpcserver.RejectedTx, 0, c.order.Len())
+	for elem := c.order.Front(); elem != nil; elem = elem.Next() {
+		entries = append(entries, elem.Value.(*rejectedTxEntry).info)
+	}
+	return entries
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/internal/mempool"
+	"github.com/decred/dcrd/internal/rpcserver"
+	"github.com/decred/dcrd/wire"
+)
+
+// TestRejectedTxCache ensures the rejected transaction cache evicts the oldest
+// entries once it is full, returns entries from newest to oldest, and only
+// reports transactions rejected for reasons that depend on the state of the
+// chain as rejected until the tip changes.
+func TestRejectedTxCache(t *testing.T) {
+	t.Parallel()
+
+	tip1 := chainhash.Hash{0x01}
+	tip2 := chainhash.Hash{0x02}
+	rejected := func(i byte) *rpcserver.RejectedTx {
+		return &rpcserver.RejectedTx{
+			Hash:   chainhash.Hash{0xff, i},
+			Code:   wire.RejectInvalid,
+			Reason: "rejected",
+			Time:   time.Unix(1592918788+int64(i), 0),
+			Height: 1,
+		}
+	}
+
+	cache := newRejectedTxCache(3)
+	cache.add(rejected(0), &tip1, false)
+	cache.add(rejected(1), &tip1, true)
+	cache.add(rejected(2), &tip1, false)
+	cache.add(rejected(3), &tip1, false)
+
+	// Ensure the oldest entry was evicted and the remaining entries are
+	// returned from newest to oldest.
+	entries := cache.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("unexpected number of entries -- got %d, want 3",
+			len(entries))
+	}
+	for i, want := range []byte{3, 2, 1} {
+		if entries[i] != *rejected(want) {
+			t.Fatalf("unexpected entry %d -- got %+v, want %+v", i,
+				entries[i], *rejected(want))
+		}
+	}
+	if cache.isRejected(&rejected(0).Hash, &tip1, false) {
+		t.Fatal("evicted entry reported as rejected")
+	}
+
+	// Ensure entries are only reported as rejected with a different tip
+	// when the reason they were rejected does not depend on the state of
+	// the chain.
+	tests := []struct {
+		name string
+		tx   byte
+		tip  *chainhash.Hash
+		want bool
+	}{
+		{name: "state dependent, same tip", tx: 2, tip: &tip1, want: true},
+		{name: "state dependent, new tip", tx: 2, tip: &tip2, want: false},
+		{name: "permanent, same tip", tx: 1, tip: &tip1, want: true},
+		{name: "permanent, new tip", tx: 1, tip: &tip2, want: true},
+	}
+	for _, test := range tests {
+		got := cache.isRejected(&rejected(test.tx).Hash, test.tip, false)
+		if got != test.want {
+			t.Errorf("%q: unexpected result -- got %v, want %v", test.name,
+				got, test.want)
+		}
+	}
+
+	// Ensure rejecting a transaction again moves it to the front.
+	cache.add(rejected(1), &tip2, false)
+	if entries := cache.Entries(); entries[0].Hash != rejected(1).Hash {
+		t.Fatalf("unexpected newest entry -- got %v, want %v",
+			entries[0].Hash, rejected(1).Hash)
+	}
+	if cache.isRejected(&rejected(1).Hash, &tip1, false) {
+		t.Fatal("re-rejected entry reported as rejected with old tip")
+	}
+
+	// Ensure transactions rejected for paying insufficient fees are not
+	// reported as rejected for peers that are exempt from the minimum relay
+	// fee.
+	lowFee := rejected(4)
+	lowFee.Code = wire.RejectInsufficientFee
+	cache.add(lowFee, &tip1, false)
+	if !cache.isRejected(&lowFee.Hash, &tip1, false) {
+		t.Fatal("low fee entry not reported as rejected")
+	}
+	if cache.isRejected(&lowFee.Hash, &tip1, true) {
+		t.Fatal("low fee entry reported as rejected for fee exempt peer")
+	}
+	if !cache.isRejected(&rejected(3).Hash, &tip1, true) {
+		t.Fatal("invalid entry not reported as rejected for fee exempt peer")
+	}
+}
+
+// TestIsPermanentTxRejection ensures only transaction rejections that do not
+// depend on the state of the chain or time are considered permanent.
+func TestIsPermanentTxRejection(t *testing.T) {
+	t.Parallel()
+
+	txRuleError := func(code mempool.ErrorCode, desc string) error {
+		return mempool.RuleError{
+			Err: mempool.TxRuleError{ErrorCode: code, Description: desc},
+		}
+	}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{{
+		name: "dust output",
+		err: txRuleError(mempool.ErrDustOutput, "transaction output 0: "+
+			"payment of 1 is dust"),
+		want: true,
+	}, {
+		name: "rate limited low fee",
+		err: txRuleError(mempool.ErrInsufficientFee, "transaction has "+
+			"been rejected by the rate limiter due to low fees"),
+		want: false,
+	}, {
+		name: "ticket below stake difficulty",
+		err: txRuleError(mempool.ErrInsufficientFee, "transaction has "+
+			"a ticket price of 1 that is less than the next stake "+
+			"difficulty of 2"),
+		want: false,
+	}, {
+		name: "missing inputs",
+		err:  txRuleError(mempool.ErrOrphan, "orphan transaction"),
+		want: false,
+	}}
+	for _, test := range tests {
+		if got := isPermanentTxRejection(test.err); got != test.want {
+			t.Errorf("%q: unexpected result -- got %v, want %v", test.name,
+				got, test.want)
+		}
+	}
+}
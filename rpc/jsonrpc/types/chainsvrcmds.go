@@ -78,6 +78,68 @@ type SStxCommitOut struct {
 	ChangeAmt  int64  `json:"changeamt"`
 }
 
+// CaptureProfileCmd defines the captureprofile JSON-RPC command.
+type CaptureProfileCmd struct {
+	Type     string
+	Duration *int `jsonrpcdefault:"30"`
+}
+
+// NewCaptureProfileCmd returns a new instance which can be used to issue a
+// captureprofile JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewCaptureProfileCmd(profileType string, duration *int) *CaptureProfileCmd {
+	return &CaptureProfileCmd{
+		Type:     profileType,
+		Duration: duration,
+	}
+}
+
+// CheckTransactionStandardCmd defines the checktransactionstandard JSON-RPC
+// command.
+type CheckTransactionStandardCmd struct {
+	HexTx string
+}
+
+// NewCheckTransactionStandardCmd returns a new instance which can be used to
+// issue a checktransactionstandard JSON-RPC command.
+func NewCheckTransactionStandardCmd(hexTx string) *CheckTransactionStandardCmd {
+	return &CheckTransactionStandardCmd{
+		HexTx: hexTx,
+	}
+}
+
+// CompareChainWorkCmd defines the comparechainwork JSON-RPC command.
+type CompareChainWorkCmd struct {
+	Headers []string
+}
+
+// NewCompareChainWorkCmd returns a new instance which can be used to issue a
+// comparechainwork JSON-RPC command.
+func NewCompareChainWorkCmd(headers []string) *CompareChainWorkCmd {
+	return &CompareChainWorkCmd{
+		Headers: headers,
+	}
+}
+
+// CoordHeartbeatCmd defines the coordheartbeat JSON-RPC command.
+type CoordHeartbeatCmd struct {
+	NodeID string
+	Term   uint64
+	Leader bool
+}
+
+// NewCoordHeartbeatCmd returns a new instance which can be used to issue a
+// coordheartbeat JSON-RPC command.
+func NewCoordHeartbeatCmd(nodeID string, term uint64, leader bool) *CoordHeartbeatCmd {
+	return &CoordHeartbeatCmd{
+		NodeID: nodeID,
+		Term:   term,
+		Leader: leader,
+	}
+}
+
 // CreateRawSStxCmd is a type handling custom marshaling and
 // unmarshaling of createrawsstx JSON RPC commands.
 type CreateRawSStxCmd struct {
@@ -143,6 +205,23 @@ func NewCreateRawTransactionCmd(inputs []TransactionInput, amounts map[string]fl
 	}
 }
 
+// CreateRevocationCmd defines the createrevocation JSON-RPC command.
+type CreateRevocationCmd struct {
+	TicketHash string
+	Fee        *float64 // In DCR
+}
+
+// NewCreateRevocationCmd returns a new instance which can be used to issue a
+// createrevocation JSON-RPC command.
+//
+// The fee is in DCR.
+func NewCreateRevocationCmd(ticketHash string, fee *float64) *CreateRevocationCmd {
+	return &CreateRevocationCmd{
+		TicketHash: ticketHash,
+		Fee:        fee,
+	}
+}
+
 // DebugLevelCmd defines the debuglevel JSON-RPC command.  This command is not a
 // standard Bitcoin command.  It is an extension for btcd.
 type DebugLevelCmd struct {
@@ -185,6 +264,28 @@ func NewDecodeScriptCmd(hexScript string) *DecodeScriptCmd {
 	}
 }
 
+// DisconnectRPCClientCmd defines the disconnectrpcclient JSON-RPC command.
+type DisconnectRPCClientCmd struct {
+	SessionID uint64
+}
+
+// NewDisconnectRPCClientCmd returns a new instance which can be used to issue
+// a disconnectrpcclient JSON-RPC command.
+func NewDisconnectRPCClientCmd(sessionID uint64) *DisconnectRPCClientCmd {
+	return &DisconnectRPCClientCmd{
+		SessionID: sessionID,
+	}
+}
+
+// DumpPeerTelemetryCmd defines the dumppeertelemetry JSON-RPC command.
+type DumpPeerTelemetryCmd struct{}
+
+// NewDumpPeerTelemetryCmd returns a new instance which can be used to issue a
+// dumppeertelemetry JSON-RPC command.
+func NewDumpPeerTelemetryCmd() *DumpPeerTelemetryCmd {
+	return &DumpPeerTelemetryCmd{}
+}
+
 // EstimateFeeCmd defines the estimatefee JSON-RPC command.
 type EstimateFeeCmd struct {
 	NumBlocks int64
@@ -239,6 +340,19 @@ func NewEstimateStakeDiffCmd(tickets *uint32) *EstimateStakeDiffCmd {
 	}
 }
 
+// EvaluateTxLocksCmd defines the evaluatetxlocks JSON-RPC command.
+type EvaluateTxLocksCmd struct {
+	HexTx string
+}
+
+// NewEvaluateTxLocksCmd returns a new instance which can be used to issue an
+// evaluatetxlocks JSON-RPC command.
+func NewEvaluateTxLocksCmd(hexTx string) *EvaluateTxLocksCmd {
+	return &EvaluateTxLocksCmd{
+		HexTx: hexTx,
+	}
+}
+
 // ExistsAddressCmd defines the existsaddress JSON-RPC command.
 type ExistsAddressCmd struct {
 	Address string
@@ -343,6 +457,21 @@ func NewGenerateCmd(numBlocks uint32) *GenerateCmd {
 	}
 }
 
+// GenerateToAddressCmd defines the generatetoaddress JSON-RPC command.
+type GenerateToAddressCmd struct {
+	NumBlocks uint32
+	Address   string
+}
+
+// NewGenerateToAddressCmd returns a new instance which can be used to issue a
+// generatetoaddress JSON-RPC command.
+func NewGenerateToAddressCmd(numBlocks uint32, address string) *GenerateToAddressCmd {
+	return &GenerateToAddressCmd{
+		NumBlocks: numBlocks,
+		Address:   address,
+	}
+}
+
 // GetAddedNodeInfoCmd defines the getaddednodeinfo JSON-RPC command.
 type GetAddedNodeInfoCmd struct {
 	DNS  bool
@@ -361,6 +490,39 @@ func NewGetAddedNodeInfoCmd(dns bool, node *string) *GetAddedNodeInfoCmd {
 	}
 }
 
+// GetAddressActivityCmd defines the getaddressactivity JSON-RPC command.
+type GetAddressActivityCmd struct {
+	Address string
+}
+
+// NewGetAddressActivityCmd returns a new instance which can be used to issue a
+// getaddressactivity JSON-RPC command.
+func NewGetAddressActivityCmd(address string) *GetAddressActivityCmd {
+	return &GetAddressActivityCmd{
+		Address: address,
+	}
+}
+
+// GetAddressUtxosCmd defines the getaddressutxos JSON-RPC command.
+type GetAddressUtxosCmd struct {
+	Address string
+	Cursor  *string
+	Limit   *int `jsonrpcdefault:"100"`
+}
+
+// NewGetAddressUtxosCmd returns a new instance which can be used to issue a
+// getaddressutxos JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetAddressUtxosCmd(address string, cursor *string, limit *int) *GetAddressUtxosCmd {
+	return &GetAddressUtxosCmd{
+		Address: address,
+		Cursor:  cursor,
+		Limit:   limit,
+	}
+}
+
 // GetBestBlockCmd defines the getbestblock JSON-RPC command.
 type GetBestBlockCmd struct{}
 
@@ -445,6 +607,19 @@ func NewGetBlockHeaderCmd(hash string, verbose *bool) *GetBlockHeaderCmd {
 	}
 }
 
+// GetBlockStatsCmd defines the getblockstats JSON-RPC command.
+type GetBlockStatsCmd struct {
+	Hash string
+}
+
+// NewGetBlockStatsCmd returns a new instance which can be used to issue a
+// getblockstats JSON-RPC command.
+func NewGetBlockStatsCmd(hash string) *GetBlockStatsCmd {
+	return &GetBlockStatsCmd{
+		Hash: hash,
+	}
+}
+
 // GetBlockSubsidyCmd defines the getblocksubsidy JSON-RPC command.
 type GetBlockSubsidyCmd struct {
 	Height int64
@@ -460,6 +635,43 @@ func NewGetBlockSubsidyCmd(height int64, voters uint16) *GetBlockSubsidyCmd {
 	}
 }
 
+// TemplateRequest is a request object as defined in BIP22 and BIP23.  It is
+// optionally provided as a pointer argument to GetBlockTemplateCmd.
+type TemplateRequest struct {
+	// Mode is either "template" to request a block template or "proposal" to
+	// propose a block for validation.  It defaults to "template".
+	Mode string `json:"mode,omitempty"`
+
+	// Capabilities are the optional capabilities supported by the caller,
+	// such as "longpoll" and "proposal".
+	Capabilities []string `json:"capabilities,omitempty"`
+
+	// LongPollID is the long poll id of a template previously returned to
+	// the caller.  When it matches the current template, the server waits
+	// for a new template before responding.
+	LongPollID string `json:"longpollid,omitempty"`
+
+	// Data is the serialized, hex-encoded block to validate.  It is only
+	// used in proposal mode.
+	Data string `json:"data,omitempty"`
+}
+
+// GetBlockTemplateCmd defines the getblocktemplate JSON-RPC command.
+type GetBlockTemplateCmd struct {
+	Request *TemplateRequest
+}
+
+// NewGetBlockTemplateCmd returns a new instance which can be used to issue a
+// getblocktemplate JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetBlockTemplateCmd(request *TemplateRequest) *GetBlockTemplateCmd {
+	return &GetBlockTemplateCmd{
+		Request: request,
+	}
+}
+
 // GetCFilterCmd defines the getcfilter JSON-RPC command.
 type GetCFilterCmd struct {
 	Hash       string
@@ -503,6 +715,15 @@ func NewGetCFilterV2Cmd(hash string) *GetCFilterV2Cmd {
 	}
 }
 
+// GetChainParamsCmd defines the getchainparams JSON-RPC command.
+type GetChainParamsCmd struct{}
+
+// NewGetChainParamsCmd returns a new instance which can be used to issue a
+// getchainparams JSON-RPC command.
+func NewGetChainParamsCmd() *GetChainParamsCmd {
+	return &GetChainParamsCmd{}
+}
+
 // GetChainTipsCmd defines the getchaintips JSON-RPC command.
 type GetChainTipsCmd struct{}
 
@@ -548,6 +769,15 @@ func NewGetDifficultyCmd() *GetDifficultyCmd {
 	return &GetDifficultyCmd{}
 }
 
+// GetDiskSpaceInfoCmd defines the getdiskspaceinfo JSON-RPC command.
+type GetDiskSpaceInfoCmd struct{}
+
+// NewGetDiskSpaceInfoCmd returns a new instance which can be used to issue a
+// getdiskspaceinfo JSON-RPC command.
+func NewGetDiskSpaceInfoCmd() *GetDiskSpaceInfoCmd {
+	return &GetDiskSpaceInfoCmd{}
+}
+
 // GetGenerateCmd defines the getgenerate JSON-RPC command.
 type GetGenerateCmd struct{}
 
@@ -599,6 +829,25 @@ func NewGetMempoolInfoCmd() *GetMempoolInfoCmd {
 	return &GetMempoolInfoCmd{}
 }
 
+// GetMempoolReplacementsCmd defines the getmempoolreplacements JSON-RPC
+// command.
+type GetMempoolReplacementsCmd struct{}
+
+// NewGetMempoolReplacementsCmd returns a new instance which can be used to
+// issue a getmempoolreplacements JSON-RPC command.
+func NewGetMempoolReplacementsCmd() *GetMempoolReplacementsCmd {
+	return &GetMempoolReplacementsCmd{}
+}
+
+// GetMiningAddrsCmd defines the getminingaddrs JSON-RPC command.
+type GetMiningAddrsCmd struct{}
+
+// NewGetMiningAddrsCmd returns a new instance which can be used to issue a
+// getminingaddrs JSON-RPC command.
+func NewGetMiningAddrsCmd() *GetMiningAddrsCmd {
+	return &GetMiningAddrsCmd{}
+}
+
 // GetMiningInfoCmd defines the getmininginfo JSON-RPC command.
 type GetMiningInfoCmd struct{}
 
@@ -628,8 +877,10 @@ func NewGetNetTotalsCmd() *GetNetTotalsCmd {
 
 // GetNetworkHashPSCmd defines the getnetworkhashps JSON-RPC command.
 type GetNetworkHashPSCmd struct {
-	Blocks *int `jsonrpcdefault:"120"`
-	Height *int `jsonrpcdefault:"-1"`
+	Blocks  *int    `jsonrpcdefault:"120"`
+	Height  *int    `jsonrpcdefault:"-1"`
+	Method  *string `jsonrpcdefault:"\"window\""`
+	Verbose *bool   `jsonrpcdefault:"false"`
 }
 
 // NewGetNetworkHashPSCmd returns a new instance which can be used to issue a
@@ -637,10 +888,12 @@ type GetNetworkHashPSCmd struct {
 //
 // The parameters which are pointers indicate they are optional.  Passing nil
 // for optional parameters will use the default value.
-func NewGetNetworkHashPSCmd(numBlocks, height *int) *GetNetworkHashPSCmd {
+func NewGetNetworkHashPSCmd(numBlocks, height *int, method *string, verbose *bool) *GetNetworkHashPSCmd {
 	return &GetNetworkHashPSCmd{
-		Blocks: numBlocks,
-		Height: height,
+		Blocks:  numBlocks,
+		Height:  height,
+		Method:  method,
+		Verbose: verbose,
 	}
 }
 
@@ -713,6 +966,50 @@ func NewGetRawTransactionCmd(txHash string, verbose *int) *GetRawTransactionCmd
 	}
 }
 
+// GetRejectedTransactionsCmd defines the getrejectedtransactions JSON-RPC
+// command.
+type GetRejectedTransactionsCmd struct {
+	Count *int32
+}
+
+// NewGetRejectedTransactionsCmd returns a new instance which can be used to
+// issue a getrejectedtransactions JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetRejectedTransactionsCmd(count *int32) *GetRejectedTransactionsCmd {
+	return &GetRejectedTransactionsCmd{
+		Count: count,
+	}
+}
+
+// GetSignalingStatsCmd defines the getsignalingstats JSON-RPC command.
+//
+// Blocks is the number of blocks walked backwards from the block with the
+// provided hash, or the current best block when it is not specified, and
+// defaults to the rule change activation interval of the active network when
+// it is not specified.  Optionally, VoteVersion restricts the vote bits
+// statistics to the votes of the given version since the meaning of the vote
+// bits depends on the vote version.
+type GetSignalingStatsCmd struct {
+	Blocks      *int32
+	Hash        *string
+	VoteVersion *uint32
+}
+
+// NewGetSignalingStatsCmd returns a new instance which can be used to issue a
+// getsignalingstats JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetSignalingStatsCmd(blocks *int32, hash *string, voteVersion *uint32) *GetSignalingStatsCmd {
+	return &GetSignalingStatsCmd{
+		Blocks:      blocks,
+		Hash:        hash,
+		VoteVersion: voteVersion,
+	}
+}
+
 // GetStakeDifficultyCmd is a type handling custom marshaling and
 // unmarshaling of getstakedifficulty JSON RPC commands.
 type GetStakeDifficultyCmd struct{}
@@ -738,10 +1035,13 @@ func NewGetStakeVersionInfoCmd(count int32) *GetStakeVersionInfoCmd {
 }
 
 // GetStakeVersionsCmd returns stake version for a range of blocks.
-// Count indicates how many blocks are walked backwards.
+// Count indicates how many blocks are walked backwards.  Optionally,
+// IntervalSize aggregates the versions of the walked blocks into intervals of
+// the given number of blocks instead of returning them per block.
 type GetStakeVersionsCmd struct {
-	Hash  string
-	Count int32
+	Hash         string
+	Count        int32
+	IntervalSize *int32 `jsonrpcdefault:"0"`
 }
 
 // NewGetStakeVersionsCmd returns a new instance which can be used to
@@ -753,6 +1053,43 @@ func NewGetStakeVersionsCmd(hash string, count int32) *GetStakeVersionsCmd {
 	}
 }
 
+// GetStandardPolicyCmd defines the getstandardpolicy JSON-RPC command.
+type GetStandardPolicyCmd struct{}
+
+// NewGetStandardPolicyCmd returns a new instance which can be used to issue a
+// getstandardpolicy JSON-RPC command.
+func NewGetStandardPolicyCmd() *GetStandardPolicyCmd {
+	return &GetStandardPolicyCmd{}
+}
+
+// GetSyncPeerCmd defines the getsyncpeer JSON-RPC command.
+type GetSyncPeerCmd struct{}
+
+// NewGetSyncPeerCmd returns a new instance which can be used to issue a
+// getsyncpeer JSON-RPC command.
+func NewGetSyncPeerCmd() *GetSyncPeerCmd {
+	return &GetSyncPeerCmd{}
+}
+
+// GetTemplateDecisionsCmd defines the gettemplatedecisions JSON-RPC command.
+//
+// Count is the number of the most recently generated block templates to
+// return the transaction selection decisions for.
+type GetTemplateDecisionsCmd struct {
+	Count *int32
+}
+
+// NewGetTemplateDecisionsCmd returns a new instance which can be used to issue
+// a gettemplatedecisions JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetTemplateDecisionsCmd(count *int32) *GetTemplateDecisionsCmd {
+	return &GetTemplateDecisionsCmd{
+		Count: count,
+	}
+}
+
 // GetTicketPoolValueCmd defines the getticketpoolvalue JSON-RPC command.
 type GetTicketPoolValueCmd struct{}
 
@@ -762,6 +1099,22 @@ func NewGetTicketPoolValueCmd() *GetTicketPoolValueCmd {
 	return &GetTicketPoolValueCmd{}
 }
 
+// GetTimeInfoCmd defines the gettimeinfo JSON-RPC command.
+type GetTimeInfoCmd struct {
+	BlockHash *string
+}
+
+// NewGetTimeInfoCmd returns a new instance which can be used to issue a
+// gettimeinfo JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetTimeInfoCmd(blockHash *string) *GetTimeInfoCmd {
+	return &GetTimeInfoCmd{
+		BlockHash: blockHash,
+	}
+}
+
 // GetTxOutCmd defines the gettxout JSON-RPC command.
 type GetTxOutCmd struct {
 	Txid           string
@@ -791,10 +1144,25 @@ func NewGetTxOutSetInfoCmd() *GetTxOutSetInfoCmd {
 	return &GetTxOutSetInfoCmd{}
 }
 
-// GetVoteInfoCmd returns voting results over a range of blocks.  Count
-// indicates how many blocks are walked backwards.
+// GetTxScriptCostCmd defines the gettxscriptcost JSON-RPC command.
+type GetTxScriptCostCmd struct {
+	Txid string
+}
+
+// NewGetTxScriptCostCmd returns a new instance which can be used to issue a
+// gettxscriptcost JSON-RPC command.
+func NewGetTxScriptCostCmd(txHash string) *GetTxScriptCostCmd {
+	return &GetTxScriptCostCmd{
+		Txid: txHash,
+	}
+}
+
+// GetVoteInfoCmd returns voting results over a range of blocks.  Optionally,
+// Count indicates how many prior rule change intervals to also return the vote
+// counts for.
 type GetVoteInfoCmd struct {
 	Version uint32
+	Count   *int32 `jsonrpcdefault:"0"`
 }
 
 // NewGetVoteInfoCmd returns a new instance which can be used to
@@ -821,6 +1189,15 @@ func NewGetWorkCmd(data *string) *GetWorkCmd {
 	}
 }
 
+// GetWorkStatsCmd defines the getworkstats JSON-RPC command.
+type GetWorkStatsCmd struct{}
+
+// NewGetWorkStatsCmd returns a new instance which can be used to issue a
+// getworkstats JSON-RPC command.
+func NewGetWorkStatsCmd() *GetWorkStatsCmd {
+	return &GetWorkStatsCmd{}
+}
+
 // RegenTemplateCmd defines the regentemplate JSON-RPC command.
 type RegenTemplateCmd struct{}
 
@@ -830,6 +1207,19 @@ func NewRegenTemplateCmd() *RegenTemplateCmd {
 	return &RegenTemplateCmd{}
 }
 
+// RollbackChainCmd defines the rollbackchain JSON-RPC command.
+type RollbackChainCmd struct {
+	Height int64
+}
+
+// NewRollbackChainCmd returns a new instance which can be used to issue a
+// rollbackchain JSON-RPC command.
+func NewRollbackChainCmd(height int64) *RollbackChainCmd {
+	return &RollbackChainCmd{
+		Height: height,
+	}
+}
+
 // HelpCmd defines the help JSON-RPC command.
 type HelpCmd struct {
 	Command *string
@@ -846,6 +1236,33 @@ func NewHelpCmd(command *string) *HelpCmd {
 	}
 }
 
+// ListBannedCmd defines the listbanned JSON-RPC command.
+type ListBannedCmd struct {
+	Cursor *string
+	Limit  *int `jsonrpcdefault:"100"`
+}
+
+// NewListBannedCmd returns a new instance which can be used to issue a
+// listbanned JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewListBannedCmd(cursor *string, limit *int) *ListBannedCmd {
+	return &ListBannedCmd{
+		Cursor: cursor,
+		Limit:  limit,
+	}
+}
+
+// ListRPCClientsCmd defines the listrpcclients JSON-RPC command.
+type ListRPCClientsCmd struct{}
+
+// NewListRPCClientsCmd returns a new instance which can be used to issue a
+// listrpcclients JSON-RPC command.
+func NewListRPCClientsCmd() *ListRPCClientsCmd {
+	return &ListRPCClientsCmd{}
+}
+
 // LiveTicketsCmd is a type handling custom marshaling and
 // unmarshaling of livetickets JSON RPC commands.
 type LiveTicketsCmd struct{}
@@ -895,6 +1312,19 @@ func NewPingCmd() *PingCmd {
 	return &PingCmd{}
 }
 
+// ProposeBlockCmd defines the proposeblock JSON-RPC command.
+type ProposeBlockCmd struct {
+	HexBlock string
+}
+
+// NewProposeBlockCmd returns a new instance which can be used to issue a
+// proposeblock JSON-RPC command.
+func NewProposeBlockCmd(hexBlock string) *ProposeBlockCmd {
+	return &ProposeBlockCmd{
+		HexBlock: hexBlock,
+	}
+}
+
 // RebroadcastMissedCmd is a type handling custom marshaling and
 // unmarshaling of rebroadcastmissed JSON RPC commands.
 type RebroadcastMissedCmd struct{}
@@ -924,6 +1354,7 @@ type SearchRawTransactionsCmd struct {
 	VinExtra    *int  `jsonrpcdefault:"0"`
 	Reverse     *bool `jsonrpcdefault:"false"`
 	FilterAddrs *[]string
+	Cursor      *string
 }
 
 // NewSearchRawTransactionsCmd returns a new instance which can be used to issue a
@@ -931,7 +1362,7 @@ type SearchRawTransactionsCmd struct {
 //
 // The parameters which are pointers indicate they are optional.  Passing nil
 // for optional parameters will use the default value.
-func NewSearchRawTransactionsCmd(address string, verbose, skip, count *int, vinExtra *int, reverse *bool, filterAddrs *[]string) *SearchRawTransactionsCmd {
+func NewSearchRawTransactionsCmd(address string, verbose, skip, count *int, vinExtra *int, reverse *bool, filterAddrs *[]string, cursor *string) *SearchRawTransactionsCmd {
 	return &SearchRawTransactionsCmd{
 		Address:     address,
 		Verbose:     verbose,
@@ -940,6 +1371,7 @@ func NewSearchRawTransactionsCmd(address string, verbose, skip, count *int, vinE
 		VinExtra:    vinExtra,
 		Reverse:     reverse,
 		FilterAddrs: filterAddrs,
+		Cursor:      cursor,
 	}
 }
 
@@ -979,6 +1411,55 @@ func NewSetGenerateCmd(generate bool, genProcLimit *int) *SetGenerateCmd {
 	}
 }
 
+// SetMiningAddrsCmd defines the setminingaddrs JSON-RPC command.
+type SetMiningAddrsCmd struct {
+	Addresses []string
+	Policy    *string
+}
+
+// NewSetMiningAddrsCmd returns a new instance which can be used to issue a
+// setminingaddrs JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewSetMiningAddrsCmd(addresses []string, policy *string) *SetMiningAddrsCmd {
+	return &SetMiningAddrsCmd{
+		Addresses: addresses,
+		Policy:    policy,
+	}
+}
+
+// SetMiningExtraDataCmd defines the setminingextradata JSON-RPC command.
+type SetMiningExtraDataCmd struct {
+	ExtraData string
+}
+
+// NewSetMiningExtraDataCmd returns a new instance which can be used to issue a
+// setminingextradata JSON-RPC command.
+func NewSetMiningExtraDataCmd(extraData string) *SetMiningExtraDataCmd {
+	return &SetMiningExtraDataCmd{
+		ExtraData: extraData,
+	}
+}
+
+// SimulateDifficultyCmd defines the simulatedifficulty JSON-RPC command.
+type SimulateDifficultyCmd struct {
+	HashRateMultipliers []float64
+	Blocks              *int32
+}
+
+// NewSimulateDifficultyCmd returns a new instance which can be used to issue a
+// simulatedifficulty JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewSimulateDifficultyCmd(hashRateMultipliers []float64, blocks *int32) *SimulateDifficultyCmd {
+	return &SimulateDifficultyCmd{
+		HashRateMultipliers: hashRateMultipliers,
+		Blocks:              blocks,
+	}
+}
+
 // StopCmd defines the stop JSON-RPC command.
 type StopCmd struct{}
 
@@ -1104,18 +1585,25 @@ func NewVerifyChainCmd(checkLevel, checkDepth *int64) *VerifyChainCmd {
 
 // VerifyMessageCmd defines the verifymessage JSON-RPC command.
 type VerifyMessageCmd struct {
-	Address   string
-	Signature string
-	Message   string
+	Address      string
+	Signature    string
+	Message      string
+	RedeemScript *string
+	Verbose      *bool `jsonrpcdefault:"false"`
 }
 
 // NewVerifyMessageCmd returns a new instance which can be used to issue a
 // verifymessage JSON-RPC command.
-func NewVerifyMessageCmd(address, signature, message string) *VerifyMessageCmd {
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewVerifyMessageCmd(address, signature, message string, redeemScript *string, verbose *bool) *VerifyMessageCmd {
 	return &VerifyMessageCmd{
-		Address:   address,
-		Signature: signature,
-		Message:   message,
+		Address:      address,
+		Signature:    signature,
+		Message:      message,
+		RedeemScript: redeemScript,
+		Verbose:      verbose,
 	}
 }
 
@@ -1131,15 +1619,23 @@ func init() {
 	flags := dcrjson.UsageFlag(0)
 
 	dcrjson.MustRegister(Method("addnode"), (*AddNodeCmd)(nil), flags)
+	dcrjson.MustRegister(Method("captureprofile"), (*CaptureProfileCmd)(nil), flags)
+	dcrjson.MustRegister(Method("checktransactionstandard"), (*CheckTransactionStandardCmd)(nil), flags)
+	dcrjson.MustRegister(Method("comparechainwork"), (*CompareChainWorkCmd)(nil), flags)
+	dcrjson.MustRegister(Method("coordheartbeat"), (*CoordHeartbeatCmd)(nil), flags)
 	dcrjson.MustRegister(Method("createrawssrtx"), (*CreateRawSSRtxCmd)(nil), flags)
 	dcrjson.MustRegister(Method("createrawsstx"), (*CreateRawSStxCmd)(nil), flags)
 	dcrjson.MustRegister(Method("createrawtransaction"), (*CreateRawTransactionCmd)(nil), flags)
+	dcrjson.MustRegister(Method("createrevocation"), (*CreateRevocationCmd)(nil), flags)
 	dcrjson.MustRegister(Method("debuglevel"), (*DebugLevelCmd)(nil), flags)
 	dcrjson.MustRegister(Method("decoderawtransaction"), (*DecodeRawTransactionCmd)(nil), flags)
 	dcrjson.MustRegister(Method("decodescript"), (*DecodeScriptCmd)(nil), flags)
+	dcrjson.MustRegister(Method("disconnectrpcclient"), (*DisconnectRPCClientCmd)(nil), flags)
+	dcrjson.MustRegister(Method("dumppeertelemetry"), (*DumpPeerTelemetryCmd)(nil), flags)
 	dcrjson.MustRegister(Method("estimatefee"), (*EstimateFeeCmd)(nil), flags)
 	dcrjson.MustRegister(Method("estimatesmartfee"), (*EstimateSmartFeeCmd)(nil), flags)
 	dcrjson.MustRegister(Method("estimatestakediff"), (*EstimateStakeDiffCmd)(nil), flags)
+	dcrjson.MustRegister(Method("evaluatetxlocks"), (*EvaluateTxLocksCmd)(nil), flags)
 	dcrjson.MustRegister(Method("existsaddress"), (*ExistsAddressCmd)(nil), flags)
 	dcrjson.MustRegister(Method("existsaddresses"), (*ExistsAddressesCmd)(nil), flags)
 	dcrjson.MustRegister(Method("existsmissedtickets"), (*ExistsMissedTicketsCmd)(nil), flags)
@@ -1148,7 +1644,10 @@ func init() {
 	dcrjson.MustRegister(Method("existslivetickets"), (*ExistsLiveTicketsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("existsmempooltxs"), (*ExistsMempoolTxsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("generate"), (*GenerateCmd)(nil), flags)
+	dcrjson.MustRegister(Method("generatetoaddress"), (*GenerateToAddressCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getaddednodeinfo"), (*GetAddedNodeInfoCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getaddressactivity"), (*GetAddressActivityCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getaddressutxos"), (*GetAddressUtxosCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getbestblock"), (*GetBestBlockCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getbestblockhash"), (*GetBestBlockHashCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getblock"), (*GetBlockCmd)(nil), flags)
@@ -1156,20 +1655,26 @@ func init() {
 	dcrjson.MustRegister(Method("getblockcount"), (*GetBlockCountCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getblockhash"), (*GetBlockHashCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getblockheader"), (*GetBlockHeaderCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getblockstats"), (*GetBlockStatsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getblocksubsidy"), (*GetBlockSubsidyCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getblocktemplate"), (*GetBlockTemplateCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getcfilter"), (*GetCFilterCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getcfilterheader"), (*GetCFilterHeaderCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getcfilterv2"), (*GetCFilterV2Cmd)(nil), flags)
+	dcrjson.MustRegister(Method("getchainparams"), (*GetChainParamsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getchaintips"), (*GetChainTipsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getcoinsupply"), (*GetCoinSupplyCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getconnectioncount"), (*GetConnectionCountCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getcurrentnet"), (*GetCurrentNetCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getdifficulty"), (*GetDifficultyCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getdiskspaceinfo"), (*GetDiskSpaceInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getgenerate"), (*GetGenerateCmd)(nil), flags)
 	dcrjson.MustRegister(Method("gethashespersec"), (*GetHashesPerSecCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getheaders"), (*GetHeadersCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getinfo"), (*GetInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getmempoolinfo"), (*GetMempoolInfoCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getmempoolreplacements"), (*GetMempoolReplacementsCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getminingaddrs"), (*GetMiningAddrsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getmininginfo"), (*GetMiningInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getnetworkinfo"), (*GetNetworkInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getnettotals"), (*GetNetTotalsCmd)(nil), flags)
@@ -1177,25 +1682,40 @@ func init() {
 	dcrjson.MustRegister(Method("getpeerinfo"), (*GetPeerInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getrawmempool"), (*GetRawMempoolCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getrawtransaction"), (*GetRawTransactionCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getrejectedtransactions"), (*GetRejectedTransactionsCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getsignalingstats"), (*GetSignalingStatsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getstakedifficulty"), (*GetStakeDifficultyCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getstakeversioninfo"), (*GetStakeVersionInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getstakeversions"), (*GetStakeVersionsCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getstandardpolicy"), (*GetStandardPolicyCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getsyncpeer"), (*GetSyncPeerCmd)(nil), flags)
+	dcrjson.MustRegister(Method("gettemplatedecisions"), (*GetTemplateDecisionsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getticketpoolvalue"), (*GetTicketPoolValueCmd)(nil), flags)
+	dcrjson.MustRegister(Method("gettimeinfo"), (*GetTimeInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("gettxout"), (*GetTxOutCmd)(nil), flags)
 	dcrjson.MustRegister(Method("gettxoutsetinfo"), (*GetTxOutSetInfoCmd)(nil), flags)
+	dcrjson.MustRegister(Method("gettxscriptcost"), (*GetTxScriptCostCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getvoteinfo"), (*GetVoteInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getwork"), (*GetWorkCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getworkstats"), (*GetWorkStatsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("help"), (*HelpCmd)(nil), flags)
+	dcrjson.MustRegister(Method("listbanned"), (*ListBannedCmd)(nil), flags)
+	dcrjson.MustRegister(Method("listrpcclients"), (*ListRPCClientsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("livetickets"), (*LiveTicketsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("missedtickets"), (*MissedTicketsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("node"), (*NodeCmd)(nil), flags)
 	dcrjson.MustRegister(Method("ping"), (*PingCmd)(nil), flags)
+	dcrjson.MustRegister(Method("proposeblock"), (*ProposeBlockCmd)(nil), flags)
 	dcrjson.MustRegister(Method("rebroadcastmissed"), (*RebroadcastMissedCmd)(nil), flags)
 	dcrjson.MustRegister(Method("rebroadcastwinners"), (*RebroadcastWinnersCmd)(nil), flags)
 	dcrjson.MustRegister(Method("regentemplate"), (*RegenTemplateCmd)(nil), flags)
+	dcrjson.MustRegister(Method("rollbackchain"), (*RollbackChainCmd)(nil), flags)
 	dcrjson.MustRegister(Method("searchrawtransactions"), (*SearchRawTransactionsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("sendrawtransaction"), (*SendRawTransactionCmd)(nil), flags)
 	dcrjson.MustRegister(Method("setgenerate"), (*SetGenerateCmd)(nil), flags)
+	dcrjson.MustRegister(Method("setminingaddrs"), (*SetMiningAddrsCmd)(nil), flags)
+	dcrjson.MustRegister(Method("setminingextradata"), (*SetMiningExtraDataCmd)(nil), flags)
+	dcrjson.MustRegister(Method("simulatedifficulty"), (*SimulateDifficultyCmd)(nil), flags)
 	dcrjson.MustRegister(Method("stop"), (*StopCmd)(nil), flags)
 	dcrjson.MustRegister(Method("submitblock"), (*SubmitBlockCmd)(nil), flags)
 	dcrjson.MustRegister(Method("ticketfeeinfo"), (*TicketFeeInfoCmd)(nil), flags)
@@ -41,6 +41,71 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"addnode","params":["127.0.0.1","remove"],"id":1}`,
 			unmarshalled: &AddNodeCmd{Addr: "127.0.0.1", SubCmd: ANRemove},
 		},
+		{
+			name: "captureprofile",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("captureprofile"), "heap")
+			},
+			staticCmd: func() interface{} {
+				return NewCaptureProfileCmd("heap", nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"captureprofile","params":["heap"],"id":1}`,
+			unmarshalled: &CaptureProfileCmd{
+				Type:     "heap",
+				Duration: dcrjson.Int(30),
+			},
+		},
+		{
+			name: "captureprofile optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("captureprofile"), "cpu", 10)
+			},
+			staticCmd: func() interface{} {
+				return NewCaptureProfileCmd("cpu", dcrjson.Int(10))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"captureprofile","params":["cpu",10],"id":1}`,
+			unmarshalled: &CaptureProfileCmd{
+				Type:     "cpu",
+				Duration: dcrjson.Int(10),
+			},
+		},
+		{
+			name: "checktransactionstandard",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("checktransactionstandard"), "123")
+			},
+			staticCmd: func() interface{} {
+				return NewCheckTransactionStandardCmd("123")
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"checktransactionstandard","params":["123"],"id":1}`,
+			unmarshalled: &CheckTransactionStandardCmd{HexTx: "123"},
+		},
+		{
+			name: "comparechainwork",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("comparechainwork"), `["00","01"]`)
+			},
+			staticCmd: func() interface{} {
+				return NewCompareChainWorkCmd([]string{"00", "01"})
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"comparechainwork","params":[["00","01"]],"id":1}`,
+			unmarshalled: &CompareChainWorkCmd{Headers: []string{"00", "01"}},
+		},
+		{
+			name: "coordheartbeat",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("coordheartbeat"), "node1", 3, true)
+			},
+			staticCmd: func() interface{} {
+				return NewCoordHeartbeatCmd("node1", 3, true)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"coordheartbeat","params":["node1",3,true],"id":1}`,
+			unmarshalled: &CoordHeartbeatCmd{
+				NodeID: "node1",
+				Term:   3,
+				Leader: true,
+			},
+		},
 		{
 			name: "createrawtransaction",
 			newCmd: func() (interface{}, error) {
@@ -81,6 +146,34 @@ func TestChainSvrCmds(t *testing.T) {
 				Expiry:   dcrjson.Int64(12312333333),
 			},
 		},
+		{
+			name: "createrevocation",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("createrevocation"), "123")
+			},
+			staticCmd: func() interface{} {
+				return NewCreateRevocationCmd("123", nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"createrevocation","params":["123"],"id":1}`,
+			unmarshalled: &CreateRevocationCmd{
+				TicketHash: "123",
+				Fee:        nil,
+			},
+		},
+		{
+			name: "createrevocation optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("createrevocation"), "123", 0.0001)
+			},
+			staticCmd: func() interface{} {
+				return NewCreateRevocationCmd("123", dcrjson.Float64(0.0001))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"createrevocation","params":["123",0.0001],"id":1}`,
+			unmarshalled: &CreateRevocationCmd{
+				TicketHash: "123",
+				Fee:        dcrjson.Float64(0.0001),
+			},
+		},
 		{
 			name: "debuglevel",
 			newCmd: func() (interface{}, error) {
@@ -129,6 +222,30 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"decodescript","params":["00",1],"id":1}`,
 			unmarshalled: &DecodeScriptCmd{HexScript: "00", Version: dcrjson.Uint16(1)},
 		},
+		{
+			name: "disconnectrpcclient",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("disconnectrpcclient"), 1234)
+			},
+			staticCmd: func() interface{} {
+				return NewDisconnectRPCClientCmd(1234)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"disconnectrpcclient","params":[1234],"id":1}`,
+			unmarshalled: &DisconnectRPCClientCmd{
+				SessionID: 1234,
+			},
+		},
+		{
+			name: "dumppeertelemetry",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("dumppeertelemetry"))
+			},
+			staticCmd: func() interface{} {
+				return NewDumpPeerTelemetryCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"dumppeertelemetry","params":[],"id":1}`,
+			unmarshalled: &DumpPeerTelemetryCmd{},
+		},
 		{
 			name: "estimatefee",
 			newCmd: func() (interface{}, error) {
@@ -171,6 +288,17 @@ func TestChainSvrCmds(t *testing.T) {
 				Mode:          EstimateSmartFeeModeAddr(EstimateSmartFeeConservative),
 			},
 		},
+		{
+			name: "evaluatetxlocks",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("evaluatetxlocks"), "123")
+			},
+			staticCmd: func() interface{} {
+				return NewEvaluateTxLocksCmd("123")
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"evaluatetxlocks","params":["123"],"id":1}`,
+			unmarshalled: &EvaluateTxLocksCmd{HexTx: "123"},
+		},
 		{
 			name: "generate",
 			newCmd: func() (interface{}, error) {
@@ -184,6 +312,22 @@ func TestChainSvrCmds(t *testing.T) {
 				NumBlocks: 1,
 			},
 		},
+		{
+			name: "generatetoaddress",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("generatetoaddress"), 1,
+					"SsWKp7wtdTZYabYFYSc9cnxhwFEjA5g4pFc")
+			},
+			staticCmd: func() interface{} {
+				return NewGenerateToAddressCmd(1,
+					"SsWKp7wtdTZYabYFYSc9cnxhwFEjA5g4pFc")
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"generatetoaddress","params":[1,"SsWKp7wtdTZYabYFYSc9cnxhwFEjA5g4pFc"],"id":1}`,
+			unmarshalled: &GenerateToAddressCmd{
+				NumBlocks: 1,
+				Address:   "SsWKp7wtdTZYabYFYSc9cnxhwFEjA5g4pFc",
+			},
+		},
 		{
 			name: "getaddednodeinfo",
 			newCmd: func() (interface{}, error) {
@@ -209,6 +353,49 @@ func TestChainSvrCmds(t *testing.T) {
 				Node: dcrjson.String("127.0.0.1"),
 			},
 		},
+		{
+			name: "getaddressactivity",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getaddressactivity"), "1Address")
+			},
+			staticCmd: func() interface{} {
+				return NewGetAddressActivityCmd("1Address")
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getaddressactivity","params":["1Address"],"id":1}`,
+			unmarshalled: &GetAddressActivityCmd{
+				Address: "1Address",
+			},
+		},
+		{
+			name: "getaddressutxos",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getaddressutxos"), "1Address")
+			},
+			staticCmd: func() interface{} {
+				return NewGetAddressUtxosCmd("1Address", nil, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getaddressutxos","params":["1Address"],"id":1}`,
+			unmarshalled: &GetAddressUtxosCmd{
+				Address: "1Address",
+				Limit:   dcrjson.Int(100),
+			},
+		},
+		{
+			name: "getaddressutxos optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getaddressutxos"), "1Address", "0000000a00000001", 10)
+			},
+			staticCmd: func() interface{} {
+				return NewGetAddressUtxosCmd("1Address",
+					dcrjson.String("0000000a00000001"), dcrjson.Int(10))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getaddressutxos","params":["1Address","0000000a00000001",10],"id":1}`,
+			unmarshalled: &GetAddressUtxosCmd{
+				Address: "1Address",
+				Cursor:  dcrjson.String("0000000a00000001"),
+				Limit:   dcrjson.Int(10),
+			},
+		},
 		{
 			name: "getbestblock",
 			newCmd: func() (interface{}, error) {
@@ -327,6 +514,19 @@ func TestChainSvrCmds(t *testing.T) {
 				Verbose: dcrjson.Bool(true),
 			},
 		},
+		{
+			name: "getblockstats",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getblockstats"), "123")
+			},
+			staticCmd: func() interface{} {
+				return NewGetBlockStatsCmd("123")
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getblockstats","params":["123"],"id":1}`,
+			unmarshalled: &GetBlockStatsCmd{
+				Hash: "123",
+			},
+		},
 		{
 			name: "getblocksubsidy",
 			newCmd: func() (interface{}, error) {
@@ -341,6 +541,59 @@ func TestChainSvrCmds(t *testing.T) {
 				Voters: 256,
 			},
 		},
+		{
+			name: "getblocktemplate",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getblocktemplate"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetBlockTemplateCmd(nil)
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getblocktemplate","params":[],"id":1}`,
+			unmarshalled: &GetBlockTemplateCmd{Request: nil},
+		},
+		{
+			name: "getblocktemplate optional - template request",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getblocktemplate"),
+					`{"mode":"template","capabilities":["longpoll"],"longpollid":"123"}`)
+			},
+			staticCmd: func() interface{} {
+				return NewGetBlockTemplateCmd(&TemplateRequest{
+					Mode:         "template",
+					Capabilities: []string{"longpoll"},
+					LongPollID:   "123",
+				})
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getblocktemplate","params":[{"mode":"template","capabilities":["longpoll"],"longpollid":"123"}],"id":1}`,
+			unmarshalled: &GetBlockTemplateCmd{
+				Request: &TemplateRequest{
+					Mode:         "template",
+					Capabilities: []string{"longpoll"},
+					LongPollID:   "123",
+				},
+			},
+		},
+		{
+			name: "getblocktemplate optional - proposal request",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getblocktemplate"),
+					`{"mode":"proposal","data":"00"}`)
+			},
+			staticCmd: func() interface{} {
+				return NewGetBlockTemplateCmd(&TemplateRequest{
+					Mode: "proposal",
+					Data: "00",
+				})
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getblocktemplate","params":[{"mode":"proposal","data":"00"}],"id":1}`,
+			unmarshalled: &GetBlockTemplateCmd{
+				Request: &TemplateRequest{
+					Mode: "proposal",
+					Data: "00",
+				},
+			},
+		},
 		{
 			name: "getcfilter",
 			newCmd: func() (interface{}, error) {
@@ -382,6 +635,17 @@ func TestChainSvrCmds(t *testing.T) {
 				BlockHash: "123",
 			},
 		},
+		{
+			name: "getchainparams",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getchainparams"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetChainParamsCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getchainparams","params":[],"id":1}`,
+			unmarshalled: &GetChainParamsCmd{},
+		},
 		{
 			name: "getchaintips",
 			newCmd: func() (interface{}, error) {
@@ -426,6 +690,17 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"getdifficulty","params":[],"id":1}`,
 			unmarshalled: &GetDifficultyCmd{},
 		},
+		{
+			name: "getdiskspaceinfo",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getdiskspaceinfo"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetDiskSpaceInfoCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getdiskspaceinfo","params":[],"id":1}`,
+			unmarshalled: &GetDiskSpaceInfoCmd{},
+		},
 		{
 			name: "getgenerate",
 			newCmd: func() (interface{}, error) {
@@ -470,6 +745,28 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"getmempoolinfo","params":[],"id":1}`,
 			unmarshalled: &GetMempoolInfoCmd{},
 		},
+		{
+			name: "getmempoolreplacements",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getmempoolreplacements"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetMempoolReplacementsCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getmempoolreplacements","params":[],"id":1}`,
+			unmarshalled: &GetMempoolReplacementsCmd{},
+		},
+		{
+			name: "getminingaddrs",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getminingaddrs"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetMiningAddrsCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getminingaddrs","params":[],"id":1}`,
+			unmarshalled: &GetMiningAddrsCmd{},
+		},
 		{
 			name: "getmininginfo",
 			newCmd: func() (interface{}, error) {
@@ -509,12 +806,14 @@ func TestChainSvrCmds(t *testing.T) {
 				return dcrjson.NewCmd(Method("getnetworkhashps"))
 			},
 			staticCmd: func() interface{} {
-				return NewGetNetworkHashPSCmd(nil, nil)
+				return NewGetNetworkHashPSCmd(nil, nil, nil, nil)
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"getnetworkhashps","params":[],"id":1}`,
 			unmarshalled: &GetNetworkHashPSCmd{
-				Blocks: dcrjson.Int(120),
-				Height: dcrjson.Int(-1),
+				Blocks:  dcrjson.Int(120),
+				Height:  dcrjson.Int(-1),
+				Method:  dcrjson.String("window"),
+				Verbose: dcrjson.Bool(false),
 			},
 		},
 		{
@@ -523,12 +822,14 @@ func TestChainSvrCmds(t *testing.T) {
 				return dcrjson.NewCmd(Method("getnetworkhashps"), 200)
 			},
 			staticCmd: func() interface{} {
-				return NewGetNetworkHashPSCmd(dcrjson.Int(200), nil)
+				return NewGetNetworkHashPSCmd(dcrjson.Int(200), nil, nil, nil)
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"getnetworkhashps","params":[200],"id":1}`,
 			unmarshalled: &GetNetworkHashPSCmd{
-				Blocks: dcrjson.Int(200),
-				Height: dcrjson.Int(-1),
+				Blocks:  dcrjson.Int(200),
+				Height:  dcrjson.Int(-1),
+				Method:  dcrjson.String("window"),
+				Verbose: dcrjson.Bool(false),
 			},
 		},
 		{
@@ -537,12 +838,31 @@ func TestChainSvrCmds(t *testing.T) {
 				return dcrjson.NewCmd(Method("getnetworkhashps"), 200, 123)
 			},
 			staticCmd: func() interface{} {
-				return NewGetNetworkHashPSCmd(dcrjson.Int(200), dcrjson.Int(123))
+				return NewGetNetworkHashPSCmd(dcrjson.Int(200), dcrjson.Int(123), nil, nil)
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"getnetworkhashps","params":[200,123],"id":1}`,
 			unmarshalled: &GetNetworkHashPSCmd{
-				Blocks: dcrjson.Int(200),
-				Height: dcrjson.Int(123),
+				Blocks:  dcrjson.Int(200),
+				Height:  dcrjson.Int(123),
+				Method:  dcrjson.String("window"),
+				Verbose: dcrjson.Bool(false),
+			},
+		},
+		{
+			name: "getnetworkhashps optional3",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getnetworkhashps"), 200, 123, "ema", true)
+			},
+			staticCmd: func() interface{} {
+				return NewGetNetworkHashPSCmd(dcrjson.Int(200), dcrjson.Int(123),
+					dcrjson.String("ema"), dcrjson.Bool(true))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getnetworkhashps","params":[200,123,"ema",true],"id":1}`,
+			unmarshalled: &GetNetworkHashPSCmd{
+				Blocks:  dcrjson.Int(200),
+				Height:  dcrjson.Int(123),
+				Method:  dcrjson.String("ema"),
+				Verbose: dcrjson.Bool(true),
 			},
 		},
 		{
@@ -624,6 +944,61 @@ func TestChainSvrCmds(t *testing.T) {
 				Verbose: dcrjson.Int(1),
 			},
 		},
+		{
+			name: "getrejectedtransactions",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getrejectedtransactions"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetRejectedTransactionsCmd(nil)
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getrejectedtransactions","params":[],"id":1}`,
+			unmarshalled: &GetRejectedTransactionsCmd{},
+		},
+		{
+			name: "getrejectedtransactions optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getrejectedtransactions"), 10)
+			},
+			staticCmd: func() interface{} {
+				return NewGetRejectedTransactionsCmd(dcrjson.Int32(10))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getrejectedtransactions","params":[10],"id":1}`,
+			unmarshalled: &GetRejectedTransactionsCmd{
+				Count: dcrjson.Int32(10),
+			},
+		},
+		{
+			name: "getsignalingstats",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getsignalingstats"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetSignalingStatsCmd(nil, nil, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getsignalingstats","params":[],"id":1}`,
+			unmarshalled: &GetSignalingStatsCmd{
+				Blocks:      nil,
+				Hash:        nil,
+				VoteVersion: nil,
+			},
+		},
+		{
+			name: "getsignalingstats optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getsignalingstats"), 4032, "deadbeef", 8)
+			},
+			staticCmd: func() interface{} {
+				return NewGetSignalingStatsCmd(dcrjson.Int32(4032),
+					dcrjson.String("deadbeef"), dcrjson.Uint32(8))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getsignalingstats","params":[4032,"deadbeef",8],"id":1}`,
+			unmarshalled: &GetSignalingStatsCmd{
+				Blocks:      dcrjson.Int32(4032),
+				Hash:        dcrjson.String("deadbeef"),
+				VoteVersion: dcrjson.Uint32(8),
+			},
+		},
 		{
 			name: "getstakeversions",
 			newCmd: func() (interface{}, error) {
@@ -634,8 +1009,96 @@ func TestChainSvrCmds(t *testing.T) {
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"getstakeversions","params":["deadbeef",1],"id":1}`,
 			unmarshalled: &GetStakeVersionsCmd{
-				Hash:  "deadbeef",
-				Count: 1,
+				Hash:         "deadbeef",
+				Count:        1,
+				IntervalSize: dcrjson.Int32(0),
+			},
+		},
+		{
+			name: "getstakeversions optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getstakeversions"), "deadbeef", 100, 10)
+			},
+			staticCmd: func() interface{} {
+				cmd := NewGetStakeVersionsCmd("deadbeef", 100)
+				cmd.IntervalSize = dcrjson.Int32(10)
+				return cmd
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getstakeversions","params":["deadbeef",100,10],"id":1}`,
+			unmarshalled: &GetStakeVersionsCmd{
+				Hash:         "deadbeef",
+				Count:        100,
+				IntervalSize: dcrjson.Int32(10),
+			},
+		},
+		{
+			name: "getstandardpolicy",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getstandardpolicy"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetStandardPolicyCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getstandardpolicy","params":[],"id":1}`,
+			unmarshalled: &GetStandardPolicyCmd{},
+		},
+		{
+			name: "getsyncpeer",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getsyncpeer"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetSyncPeerCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getsyncpeer","params":[],"id":1}`,
+			unmarshalled: &GetSyncPeerCmd{},
+		},
+		{
+			name: "gettemplatedecisions",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("gettemplatedecisions"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetTemplateDecisionsCmd(nil)
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"gettemplatedecisions","params":[],"id":1}`,
+			unmarshalled: &GetTemplateDecisionsCmd{},
+		},
+		{
+			name: "gettemplatedecisions optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("gettemplatedecisions"), 5)
+			},
+			staticCmd: func() interface{} {
+				return NewGetTemplateDecisionsCmd(dcrjson.Int32(5))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"gettemplatedecisions","params":[5],"id":1}`,
+			unmarshalled: &GetTemplateDecisionsCmd{
+				Count: dcrjson.Int32(5),
+			},
+		},
+		{
+			name: "gettimeinfo",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("gettimeinfo"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetTimeInfoCmd(nil)
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"gettimeinfo","params":[],"id":1}`,
+			unmarshalled: &GetTimeInfoCmd{},
+		},
+		{
+			name: "gettimeinfo optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("gettimeinfo"), "123")
+			},
+			staticCmd: func() interface{} {
+				return NewGetTimeInfoCmd(dcrjson.String("123"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"gettimeinfo","params":["123"],"id":1}`,
+			unmarshalled: &GetTimeInfoCmd{
+				BlockHash: dcrjson.String("123"),
 			},
 		},
 		{
@@ -679,6 +1142,19 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"gettxoutsetinfo","params":[],"id":1}`,
 			unmarshalled: &GetTxOutSetInfoCmd{},
 		},
+		{
+			name: "gettxscriptcost",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("gettxscriptcost"), "123")
+			},
+			staticCmd: func() interface{} {
+				return NewGetTxScriptCostCmd("123")
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"gettxscriptcost","params":["123"],"id":1}`,
+			unmarshalled: &GetTxScriptCostCmd{
+				Txid: "123",
+			},
+		},
 		{
 			name: "getvoteinfo",
 			newCmd: func() (interface{}, error) {
@@ -690,6 +1166,23 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled: `{"jsonrpc":"1.0","method":"getvoteinfo","params":[1],"id":1}`,
 			unmarshalled: &GetVoteInfoCmd{
 				Version: 1,
+				Count:   dcrjson.Int32(0),
+			},
+		},
+		{
+			name: "getvoteinfo optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getvoteinfo"), 1, 2)
+			},
+			staticCmd: func() interface{} {
+				cmd := NewGetVoteInfoCmd(1)
+				cmd.Count = dcrjson.Int32(2)
+				return cmd
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getvoteinfo","params":[1,2],"id":1}`,
+			unmarshalled: &GetVoteInfoCmd{
+				Version: 1,
+				Count:   dcrjson.Int32(2),
 			},
 		},
 		{
@@ -718,6 +1211,17 @@ func TestChainSvrCmds(t *testing.T) {
 				Data: dcrjson.String("00112233"),
 			},
 		},
+		{
+			name: "getworkstats",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getworkstats"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetWorkStatsCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getworkstats","params":[],"id":1}`,
+			unmarshalled: &GetWorkStatsCmd{},
+		},
 		{
 			name: "help",
 			newCmd: func() (interface{}, error) {
@@ -744,6 +1248,45 @@ func TestChainSvrCmds(t *testing.T) {
 				Command: dcrjson.String("getblock"),
 			},
 		},
+		{
+			name: "listbanned",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("listbanned"))
+			},
+			staticCmd: func() interface{} {
+				return NewListBannedCmd(nil, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"listbanned","params":[],"id":1}`,
+			unmarshalled: &ListBannedCmd{
+				Limit: dcrjson.Int(100),
+			},
+		},
+		{
+			name: "listbanned optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("listbanned"), "0000000a", 10)
+			},
+			staticCmd: func() interface{} {
+				return NewListBannedCmd(dcrjson.String("0000000a"),
+					dcrjson.Int(10))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"listbanned","params":["0000000a",10],"id":1}`,
+			unmarshalled: &ListBannedCmd{
+				Cursor: dcrjson.String("0000000a"),
+				Limit:  dcrjson.Int(10),
+			},
+		},
+		{
+			name: "listrpcclients",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("listrpcclients"))
+			},
+			staticCmd: func() interface{} {
+				return NewListRPCClientsCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"listrpcclients","params":[],"id":1}`,
+			unmarshalled: &ListRPCClientsCmd{},
+		},
 		{
 			name: "node option remove",
 			newCmd: func() (interface{}, error) {
@@ -798,13 +1341,35 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"ping","params":[],"id":1}`,
 			unmarshalled: &PingCmd{},
 		},
+		{
+			name: "proposeblock",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("proposeblock"), "00112233")
+			},
+			staticCmd: func() interface{} {
+				return NewProposeBlockCmd("00112233")
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"proposeblock","params":["00112233"],"id":1}`,
+			unmarshalled: &ProposeBlockCmd{HexBlock: "00112233"},
+		},
+		{
+			name: "rollbackchain",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("rollbackchain"), 100)
+			},
+			staticCmd: func() interface{} {
+				return NewRollbackChainCmd(100)
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"rollbackchain","params":[100],"id":1}`,
+			unmarshalled: &RollbackChainCmd{Height: 100},
+		},
 		{
 			name: "searchrawtransactions",
 			newCmd: func() (interface{}, error) {
 				return dcrjson.NewCmd(Method("searchrawtransactions"), "1Address")
 			},
 			staticCmd: func() interface{} {
-				return NewSearchRawTransactionsCmd("1Address", nil, nil, nil, nil, nil, nil)
+				return NewSearchRawTransactionsCmd("1Address", nil, nil, nil, nil, nil, nil, nil)
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"searchrawtransactions","params":["1Address"],"id":1}`,
 			unmarshalled: &SearchRawTransactionsCmd{
@@ -824,7 +1389,7 @@ func TestChainSvrCmds(t *testing.T) {
 			},
 			staticCmd: func() interface{} {
 				return NewSearchRawTransactionsCmd("1Address",
-					dcrjson.Int(0), nil, nil, nil, nil, nil)
+					dcrjson.Int(0), nil, nil, nil, nil, nil, nil)
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"searchrawtransactions","params":["1Address",0],"id":1}`,
 			unmarshalled: &SearchRawTransactionsCmd{
@@ -844,7 +1409,7 @@ func TestChainSvrCmds(t *testing.T) {
 			},
 			staticCmd: func() interface{} {
 				return NewSearchRawTransactionsCmd("1Address",
-					dcrjson.Int(0), dcrjson.Int(5), nil, nil, nil, nil)
+					dcrjson.Int(0), dcrjson.Int(5), nil, nil, nil, nil, nil)
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"searchrawtransactions","params":["1Address",0,5],"id":1}`,
 			unmarshalled: &SearchRawTransactionsCmd{
@@ -864,7 +1429,8 @@ func TestChainSvrCmds(t *testing.T) {
 			},
 			staticCmd: func() interface{} {
 				return NewSearchRawTransactionsCmd("1Address",
-					dcrjson.Int(0), dcrjson.Int(5), dcrjson.Int(10), nil, nil, nil)
+					dcrjson.Int(0), dcrjson.Int(5), dcrjson.Int(10), nil, nil, nil,
+					nil)
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"searchrawtransactions","params":["1Address",0,5,10],"id":1}`,
 			unmarshalled: &SearchRawTransactionsCmd{
@@ -884,7 +1450,8 @@ func TestChainSvrCmds(t *testing.T) {
 			},
 			staticCmd: func() interface{} {
 				return NewSearchRawTransactionsCmd("1Address",
-					dcrjson.Int(0), dcrjson.Int(5), dcrjson.Int(10), dcrjson.Int(1), nil, nil)
+					dcrjson.Int(0), dcrjson.Int(5), dcrjson.Int(10), dcrjson.Int(1), nil,
+					nil, nil)
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"searchrawtransactions","params":["1Address",0,5,10,1],"id":1}`,
 			unmarshalled: &SearchRawTransactionsCmd{
@@ -905,7 +1472,7 @@ func TestChainSvrCmds(t *testing.T) {
 			staticCmd: func() interface{} {
 				return NewSearchRawTransactionsCmd("1Address",
 					dcrjson.Int(0), dcrjson.Int(5), dcrjson.Int(10),
-					dcrjson.Int(1), dcrjson.Bool(true), nil)
+					dcrjson.Int(1), dcrjson.Bool(true), nil, nil)
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"searchrawtransactions","params":["1Address",0,5,10,1,true],"id":1}`,
 			unmarshalled: &SearchRawTransactionsCmd{
@@ -926,7 +1493,8 @@ func TestChainSvrCmds(t *testing.T) {
 			staticCmd: func() interface{} {
 				return NewSearchRawTransactionsCmd("1Address",
 					dcrjson.Int(0), dcrjson.Int(5), dcrjson.Int(10),
-					dcrjson.Int(1), dcrjson.Bool(true), &[]string{"1Address"})
+					dcrjson.Int(1), dcrjson.Bool(true), &[]string{"1Address"},
+					nil)
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"searchrawtransactions","params":["1Address",0,5,10,1,true,["1Address"]],"id":1}`,
 			unmarshalled: &SearchRawTransactionsCmd{
@@ -939,6 +1507,29 @@ func TestChainSvrCmds(t *testing.T) {
 				FilterAddrs: &[]string{"1Address"},
 			},
 		},
+		{
+			name: "searchrawtransactions",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("searchrawtransactions"), "1Address", 0, 5, 10, 1, true, []string{"1Address"}, "00000064")
+			},
+			staticCmd: func() interface{} {
+				return NewSearchRawTransactionsCmd("1Address",
+					dcrjson.Int(0), dcrjson.Int(5), dcrjson.Int(10),
+					dcrjson.Int(1), dcrjson.Bool(true), &[]string{"1Address"},
+					dcrjson.String("00000064"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"searchrawtransactions","params":["1Address",0,5,10,1,true,["1Address"],"00000064"],"id":1}`,
+			unmarshalled: &SearchRawTransactionsCmd{
+				Address:     "1Address",
+				Verbose:     dcrjson.Int(0),
+				Skip:        dcrjson.Int(5),
+				Count:       dcrjson.Int(10),
+				VinExtra:    dcrjson.Int(1),
+				Reverse:     dcrjson.Bool(true),
+				FilterAddrs: &[]string{"1Address"},
+				Cursor:      dcrjson.String("00000064"),
+			},
+		},
 		{
 			name: "sendrawtransaction",
 			newCmd: func() (interface{}, error) {
@@ -995,6 +1586,79 @@ func TestChainSvrCmds(t *testing.T) {
 				GenProcLimit: dcrjson.Int(6),
 			},
 		},
+		{
+			name: "setminingaddrs",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("setminingaddrs"),
+					[]string{"DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu"})
+			},
+			staticCmd: func() interface{} {
+				return NewSetMiningAddrsCmd(
+					[]string{"DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu"}, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"setminingaddrs","params":[["DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu"]],"id":1}`,
+			unmarshalled: &SetMiningAddrsCmd{
+				Addresses: []string{"DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu"},
+			},
+		},
+		{
+			name: "setminingaddrs optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("setminingaddrs"),
+					[]string{"DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu"},
+					"roundrobin")
+			},
+			staticCmd: func() interface{} {
+				return NewSetMiningAddrsCmd(
+					[]string{"DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu"},
+					dcrjson.String("roundrobin"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"setminingaddrs","params":[["DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu"],"roundrobin"],"id":1}`,
+			unmarshalled: &SetMiningAddrsCmd{
+				Addresses: []string{"DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu"},
+				Policy:    dcrjson.String("roundrobin"),
+			},
+		},
+		{
+			name: "setminingextradata",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("setminingextradata"), "2f706f6f6c2f")
+			},
+			staticCmd: func() interface{} {
+				return NewSetMiningExtraDataCmd("2f706f6f6c2f")
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"setminingextradata","params":["2f706f6f6c2f"],"id":1}`,
+			unmarshalled: &SetMiningExtraDataCmd{
+				ExtraData: "2f706f6f6c2f",
+			},
+		},
+		{
+			name: "simulatedifficulty",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("simulatedifficulty"), []float64{0.5, 2})
+			},
+			staticCmd: func() interface{} {
+				return NewSimulateDifficultyCmd([]float64{0.5, 2}, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"simulatedifficulty","params":[[0.5,2]],"id":1}`,
+			unmarshalled: &SimulateDifficultyCmd{
+				HashRateMultipliers: []float64{0.5, 2},
+			},
+		},
+		{
+			name: "simulatedifficulty optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("simulatedifficulty"), []float64{1.5}, 288)
+			},
+			staticCmd: func() interface{} {
+				return NewSimulateDifficultyCmd([]float64{1.5}, dcrjson.Int32(288))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"simulatedifficulty","params":[[1.5],288],"id":1}`,
+			unmarshalled: &SimulateDifficultyCmd{
+				HashRateMultipliers: []float64{1.5},
+				Blocks:              dcrjson.Int32(288),
+			},
+		},
 		{
 			name: "stop",
 			newCmd: func() (interface{}, error) {
@@ -1100,13 +1764,32 @@ func TestChainSvrCmds(t *testing.T) {
 				return dcrjson.NewCmd(Method("verifymessage"), "1Address", "301234", "test")
 			},
 			staticCmd: func() interface{} {
-				return NewVerifyMessageCmd("1Address", "301234", "test")
+				return NewVerifyMessageCmd("1Address", "301234", "test", nil, nil)
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"verifymessage","params":["1Address","301234","test"],"id":1}`,
 			unmarshalled: &VerifyMessageCmd{
 				Address:   "1Address",
 				Signature: "301234",
 				Message:   "test",
+				Verbose:   dcrjson.Bool(false),
+			},
+		},
+		{
+			name: "verifymessage optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("verifymessage"), "3Address", "301234", "test", "5221", true)
+			},
+			staticCmd: func() interface{} {
+				return NewVerifyMessageCmd("3Address", "301234", "test",
+					dcrjson.String("5221"), dcrjson.Bool(true))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"verifymessage","params":["3Address","301234","test","5221",true],"id":1}`,
+			unmarshalled: &VerifyMessageCmd{
+				Address:      "3Address",
+				Signature:    "301234",
+				Message:      "test",
+				RedeemScript: dcrjson.String("5221"),
+				Verbose:      dcrjson.Bool(true),
 			},
 		},
 	}
@@ -17,6 +17,53 @@ type TxRawDecodeResult struct {
 	Vout     []Vout `json:"vout"`
 }
 
+// StandardViolationResult models a violation of a standardness policy rule
+// returned from the checktransactionstandard command.  The input and output
+// indexes are omitted when the rule does not apply to a specific input or
+// output.
+type StandardViolationResult struct {
+	Rule        string `json:"rule"`
+	Input       *int   `json:"input,omitempty"`
+	Output      *int   `json:"output,omitempty"`
+	Description string `json:"description"`
+}
+
+// CaptureProfileResult models the data returned from the captureprofile
+// command.
+type CaptureProfileResult struct {
+	Type string `json:"type"`
+	Path string `json:"path"`
+}
+
+// CheckTransactionStandardResult models the data returned from the
+// checktransactionstandard command.
+type CheckTransactionStandardResult struct {
+	Standard   bool                      `json:"standard"`
+	Violations []StandardViolationResult `json:"violations"`
+}
+
+// CompareChainWorkResult models the data returned from the comparechainwork
+// command.
+type CompareChainWorkResult struct {
+	ForkHash      string `json:"forkhash"`
+	ForkHeight    int64  `json:"forkheight"`
+	TipHash       string `json:"tiphash"`
+	TipHeight     int64  `json:"tipheight"`
+	ChainWork     string `json:"chainwork"`
+	BestHash      string `json:"besthash"`
+	BestHeight    int64  `json:"bestheight"`
+	BestChainWork string `json:"bestchainwork"`
+	MoreWork      bool   `json:"morework"`
+}
+
+// CoordHeartbeatResult models the data returned from the coordheartbeat
+// command.
+type CoordHeartbeatResult struct {
+	NodeID string `json:"nodeid"`
+	Term   uint64 `json:"term"`
+	Leader bool   `json:"leader"`
+}
+
 // DecodeScriptResult models the data returned from the decodescript command.
 type DecodeScriptResult struct {
 	Asm       string   `json:"asm"`
@@ -26,6 +73,14 @@ type DecodeScriptResult struct {
 	P2sh      string   `json:"p2sh,omitempty"`
 }
 
+// DumpPeerTelemetryResult models the data returned from the dumppeertelemetry
+// command.
+type DumpPeerTelemetryResult struct {
+	File    string `json:"file"`
+	Events  int    `json:"events"`
+	Dropped uint64 `json:"dropped"`
+}
+
 // EstimateSmartFeeResult models the data returned from the estimatesmartfee
 // command.
 //
@@ -48,6 +103,21 @@ type EstimateStakeDiffResult struct {
 	User     *float64 `json:"user,omitempty"`
 }
 
+// EvaluateTxLocksResult models the data returned from the evaluatetxlocks
+// command.  The lock time and sequence lock fields are omitted when the
+// respective lock does not constrain the transaction.
+type EvaluateTxLocksResult struct {
+	Final                  bool  `json:"final"`
+	NextHeight             int64 `json:"nextheight"`
+	MedianTime             int64 `json:"mediantime"`
+	LockTimeHeight         int64 `json:"locktimeheight,omitempty"`
+	LockTimeMedianTime     int64 `json:"locktimemediantime,omitempty"`
+	SequenceLockHeight     int64 `json:"sequencelockheight,omitempty"`
+	SequenceLockMedianTime int64 `json:"sequencelockmediantime,omitempty"`
+	EarliestHeight         int64 `json:"earliestheight"`
+	EarliestMedianTime     int64 `json:"earliestmediantime"`
+}
+
 // GetAddedNodeInfoResultAddr models the data of the addresses portion of the
 // getaddednodeinfo command.
 type GetAddedNodeInfoResultAddr struct {
@@ -78,6 +148,7 @@ type GetBlockVerboseResult struct {
 	STx           []string      `json:"stx,omitempty"`
 	RawSTx        []TxRawResult `json:"rawstx,omitempty"`
 	Time          int64         `json:"time"`
+	ReceivedTime  int64         `json:"receivedtime,omitempty"`
 	Nonce         uint32        `json:"nonce"`
 	VoteBits      uint16        `json:"votebits"`
 	FinalState    string        `json:"finalstate"`
@@ -97,10 +168,54 @@ type GetBlockVerboseResult struct {
 
 // AgendaInfo provides an overview of an agenda in a consensus deployment.
 type AgendaInfo struct {
-	Status     string `json:"status"`
-	Since      int64  `json:"since,omitempty"`
-	StartTime  uint64 `json:"starttime"`
-	ExpireTime uint64 `json:"expiretime"`
+	Status           string            `json:"status"`
+	Since            int64             `json:"since,omitempty"`
+	StartTime        uint64            `json:"starttime"`
+	ExpireTime       uint64            `json:"expiretime"`
+	Version          uint32            `json:"version"`
+	ActivationHeight int64             `json:"activationheight,omitempty"`
+	VoteResult       *AgendaVoteResult `json:"voteresult,omitempty"`
+}
+
+// AgendaVoteResult models the final vote tally of a consensus deployment
+// agenda for which voting has concluded.
+type AgendaVoteResult struct {
+	DecisionHeight int64             `json:"decisionheight"`
+	Choice         string            `json:"choice,omitempty"`
+	TotalVotes     uint32            `json:"totalvotes"`
+	AbstainVotes   uint32            `json:"abstainvotes"`
+	Choices        map[string]uint32 `json:"choices"`
+}
+
+// GetAddressActivityResult models the data returned from the
+// getaddressactivity command.  The heights and hashes are only set when the
+// address has been seen in the main chain.
+type GetAddressActivityResult struct {
+	Address         string `json:"address"`
+	Seen            bool   `json:"seen"`
+	FirstSeenHeight int64  `json:"firstseenheight"`
+	FirstSeenHash   string `json:"firstseenhash,omitempty"`
+	LastSeenHeight  int64  `json:"lastseenheight"`
+	LastSeenHash    string `json:"lastseenhash,omitempty"`
+}
+
+// AddressUtxo models an unspent transaction output paying to an address as
+// part of the data returned from the getaddressutxos command.
+type AddressUtxo struct {
+	Txid          string  `json:"txid"`
+	Vout          uint32  `json:"vout"`
+	Tree          int8    `json:"tree"`
+	Amount        float64 `json:"amount"`
+	ScriptPubKey  string  `json:"scriptpubkey"`
+	Height        int64   `json:"height"`
+	Confirmations int64   `json:"confirmations"`
+}
+
+// GetAddressUtxosResult models the data returned from the getaddressutxos
+// command.
+type GetAddressUtxosResult struct {
+	Utxos []AddressUtxo `json:"utxos"`
+	Page  PageInfo      `json:"page"`
 }
 
 // GetBestBlockResult models the data from the getbestblock command.
@@ -155,6 +270,24 @@ type GetBlockHeaderVerboseResult struct {
 	NextHash      string  `json:"nextblockhash,omitempty"`
 }
 
+// GetBlockStatsResult models the data returned from the getblockstats command.
+// The receive time and delay are omitted when the local time the block was
+// received is not known.
+type GetBlockStatsResult struct {
+	Hash         string  `json:"hash"`
+	Height       int64   `json:"height"`
+	MainChain    bool    `json:"mainchain"`
+	Time         int64   `json:"time"`
+	ReceivedTime int64   `json:"receivedtime,omitempty"`
+	ReceiveDelay float64 `json:"receivedelay,omitempty"`
+	Size         int32   `json:"size"`
+	NumTx        int     `json:"numtx"`
+	NumSTx       int     `json:"numstx"`
+	Voters       uint16  `json:"voters"`
+	FreshStake   uint8   `json:"freshstake"`
+	Revocations  uint8   `json:"revocations"`
+}
+
 // GetBlockSubsidyResult models the data returned from the getblocksubsidy
 // command.
 type GetBlockSubsidyResult struct {
@@ -164,6 +297,145 @@ type GetBlockSubsidyResult struct {
 	Total     int64 `json:"total"`
 }
 
+// GetBlockTemplateResultTx models the transactions field of the
+// getblocktemplate command.
+type GetBlockTemplateResultTx struct {
+	Data string `json:"data"`
+	Hash string `json:"hash"`
+}
+
+// GetBlockTemplateResult models the data returned from the getblocktemplate
+// command.
+type GetBlockTemplateResult struct {
+	Header        string                     `json:"header"`
+	Transactions  []GetBlockTemplateResultTx `json:"transactions"`
+	STransactions []GetBlockTemplateResultTx `json:"stransactions"`
+	Height        int64                      `json:"height"`
+	CurTime       int64                      `json:"curtime"`
+	Target        string                     `json:"target"`
+	LongPollID    string                     `json:"longpollid"`
+	Capabilities  []string                   `json:"capabilities"`
+}
+
+// ChainParamsPoW models the proof-of-work and block time parameters returned
+// from the getchainparams command.  All durations are in seconds.
+type ChainParamsPoW struct {
+	PowLimit                 string `json:"powlimit"`
+	PowLimitBits             uint32 `json:"powlimitbits"`
+	ReduceMinDifficulty      bool   `json:"reducemindifficulty"`
+	MinDiffReductionTime     int64  `json:"mindiffreductiontime"`
+	TargetTimePerBlock       int64  `json:"targettimeperblock"`
+	TargetTimespan           int64  `json:"targettimespan"`
+	RetargetAdjustmentFactor int64  `json:"retargetadjustmentfactor"`
+	WorkDiffAlpha            int64  `json:"workdiffalpha"`
+	WorkDiffWindowSize       int64  `json:"workdiffwindowsize"`
+	WorkDiffWindows          int64  `json:"workdiffwindows"`
+}
+
+// ChainParamsSubsidy models the subsidy parameters returned from the
+// getchainparams command.
+type ChainParamsSubsidy struct {
+	BaseSubsidy              int64  `json:"basesubsidy"`
+	MulSubsidy               int64  `json:"mulsubsidy"`
+	DivSubsidy               int64  `json:"divsubsidy"`
+	SubsidyReductionInterval int64  `json:"subsidyreductioninterval"`
+	WorkRewardProportion     uint16 `json:"workrewardproportion"`
+	StakeRewardProportion    uint16 `json:"stakerewardproportion"`
+	BlockTaxProportion       uint16 `json:"blocktaxproportion"`
+}
+
+// ChainParamsStake models the stake parameters returned from the
+// getchainparams command.
+type ChainParamsStake struct {
+	MinimumStakeDiff        int64  `json:"minimumstakediff"`
+	TicketPoolSize          uint16 `json:"ticketpoolsize"`
+	TicketsPerBlock         uint16 `json:"ticketsperblock"`
+	TicketMaturity          uint16 `json:"ticketmaturity"`
+	TicketExpiry            uint32 `json:"ticketexpiry"`
+	SStxChangeMaturity      uint16 `json:"sstxchangematurity"`
+	TicketPoolSizeWeight    uint16 `json:"ticketpoolsizeweight"`
+	StakeDiffAlpha          int64  `json:"stakediffalpha"`
+	StakeDiffWindowSize     int64  `json:"stakediffwindowsize"`
+	StakeDiffWindows        int64  `json:"stakediffwindows"`
+	StakeVersionInterval    int64  `json:"stakeversioninterval"`
+	MaxFreshStakePerBlock   uint8  `json:"maxfreshstakeperblock"`
+	StakeEnabledHeight      int64  `json:"stakeenabledheight"`
+	StakeValidationHeight   int64  `json:"stakevalidationheight"`
+	StakeMajorityMultiplier int32  `json:"stakemajoritymultiplier"`
+	StakeMajorityDivisor    int32  `json:"stakemajoritydivisor"`
+}
+
+// ChainParamsAddressPrefixes models the address and key encoding parameters
+// returned from the getchainparams command.  The identifiers are hex encoded.
+type ChainParamsAddressPrefixes struct {
+	NetworkAddressPrefix string `json:"networkaddressprefix"`
+	PubKeyAddrID         string `json:"pubkeyaddrid"`
+	PubKeyHashAddrID     string `json:"pubkeyhashaddrid"`
+	PKHEdwardsAddrID     string `json:"pkhedwardsaddrid"`
+	PKHSchnorrAddrID     string `json:"pkhschnorraddrid"`
+	ScriptHashAddrID     string `json:"scripthashaddrid"`
+	PrivateKeyID         string `json:"privatekeyid"`
+	HDPrivateKeyID       string `json:"hdprivatekeyid"`
+	HDPublicKeyID        string `json:"hdpublickeyid"`
+	SLIP0044CoinType     uint32 `json:"slip0044cointype"`
+	LegacyCoinType       uint32 `json:"legacycointype"`
+}
+
+// ChainParamsRuleChange models the parameters that govern voting on consensus
+// rule changes returned from the getchainparams command.
+type ChainParamsRuleChange struct {
+	Quorum     uint32 `json:"quorum"`
+	Multiplier uint32 `json:"multiplier"`
+	Divisor    uint32 `json:"divisor"`
+	Interval   uint32 `json:"interval"`
+}
+
+// ChainParamsChoice models a choice of a consensus deployment agenda returned
+// from the getchainparams command.
+type ChainParamsChoice struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	Bits        uint16 `json:"bits"`
+	IsAbstain   bool   `json:"isabstain"`
+	IsNo        bool   `json:"isno"`
+}
+
+// ChainParamsAgenda models a consensus deployment agenda returned from the
+// getchainparams command.
+type ChainParamsAgenda struct {
+	ID          string              `json:"id"`
+	Description string              `json:"description"`
+	Mask        uint16              `json:"mask"`
+	StartTime   uint64              `json:"starttime"`
+	ExpireTime  uint64              `json:"expiretime"`
+	Choices     []ChainParamsChoice `json:"choices"`
+}
+
+// ChainParamsDeployment models the consensus deployment agendas of a stake
+// version returned from the getchainparams command.
+type ChainParamsDeployment struct {
+	Version uint32              `json:"version"`
+	Agendas []ChainParamsAgenda `json:"agendas"`
+}
+
+// GetChainParamsResult models the data returned from the getchainparams
+// command.
+type GetChainParamsResult struct {
+	Name             string                     `json:"name"`
+	Net              uint32                     `json:"net"`
+	DefaultPort      string                     `json:"defaultport"`
+	GenesisHash      string                     `json:"genesishash"`
+	MaxBlockSizes    []int                      `json:"maxblocksizes"`
+	MaxTxSize        int                        `json:"maxtxsize"`
+	CoinbaseMaturity uint16                     `json:"coinbasematurity"`
+	PoW              ChainParamsPoW             `json:"pow"`
+	Subsidy          ChainParamsSubsidy         `json:"subsidy"`
+	Stake            ChainParamsStake           `json:"stake"`
+	AddressPrefixes  ChainParamsAddressPrefixes `json:"addressprefixes"`
+	RuleChange       ChainParamsRuleChange      `json:"rulechange"`
+	Deployments      []ChainParamsDeployment    `json:"deployments"`
+}
+
 // GetChainTipsResult models the data returns from the getchaintips command.
 type GetChainTipsResult struct {
 	Height    int64  `json:"height"`
@@ -180,6 +452,18 @@ type GetCFilterV2Result struct {
 	ProofHashes []string `json:"proofhashes"`
 }
 
+// GetDiskSpaceInfoResult models the data returned from the getdiskspaceinfo
+// command.  All sizes are in bytes and a threshold of zero indicates it is
+// disabled.
+type GetDiskSpaceInfoResult struct {
+	Path          string `json:"path"`
+	Free          uint64 `json:"free"`
+	Total         uint64 `json:"total"`
+	WarnThreshold uint64 `json:"warnthreshold"`
+	StopThreshold uint64 `json:"stopthreshold"`
+	Level         string `json:"level"`
+}
+
 // GetHeadersResult models the data returned by the chain server getheaders
 // command.
 type GetHeadersResult struct {
@@ -205,8 +489,53 @@ type InfoChainResult struct {
 // GetMempoolInfoResult models the data returned from the getmempoolinfo
 // command.
 type GetMempoolInfoResult struct {
-	Size  int64 `json:"size"`
-	Bytes int64 `json:"bytes"`
+	Size     int64                   `json:"size"`
+	Bytes    int64                   `json:"bytes"`
+	Evicted  uint64                  `json:"evicted"`
+	LastHour GetMempoolInfoWatermark `json:"lasthour"`
+	LastDay  GetMempoolInfoWatermark `json:"lastday"`
+}
+
+// GetMempoolInfoWatermark models the data that describes the mempool over a
+// recent window of time as returned from the getmempoolinfo command.  The
+// minimum fee rate is omitted when no regular transactions were accepted during
+// the window.
+type GetMempoolInfoWatermark struct {
+	MaxSize    int64    `json:"maxsize"`
+	MaxBytes   int64    `json:"maxbytes"`
+	Evicted    uint64   `json:"evicted"`
+	MinFeeRate *float64 `json:"minfeerate,omitempty"`
+}
+
+// GetMempoolReplacementsResult models the data returned from the
+// getmempoolreplacements command.
+type GetMempoolReplacementsResult struct {
+	Enabled            bool                       `json:"enabled"`
+	MinFeeRateIncrease float64                    `json:"minfeerateincrease"`
+	Replacements       []MempoolReplacementResult `json:"replacements"`
+}
+
+// MempoolReplacementResult models the data that describes the outcome of
+// evaluating a transaction as a replacement for the transactions in the
+// mempool it conflicts with as returned from the getmempoolreplacements
+// command.  The reason is omitted when the replacement was accepted.
+type MempoolReplacementResult struct {
+	Time       int64    `json:"time"`
+	TxID       string   `json:"txid"`
+	Conflicts  []string `json:"conflicts"`
+	Evicted    int64    `json:"evicted"`
+	Fee        float64  `json:"fee"`
+	FeeRate    float64  `json:"feerate"`
+	MinFee     float64  `json:"minfee"`
+	MinFeeRate float64  `json:"minfeerate"`
+	Accepted   bool     `json:"accepted"`
+	Reason     string   `json:"reason,omitempty"`
+}
+
+// GetMiningAddrsResult models the data from the getminingaddrs command.
+type GetMiningAddrsResult struct {
+	Addresses []string `json:"addresses"`
+	Policy    string   `json:"policy"`
 }
 
 // GetMiningInfoResult models the data from the getmininginfo command.
@@ -243,6 +572,18 @@ type NetworksResult struct {
 	ProxyRandomizeCredentials bool   `json:"proxyrandomizecredentials"`
 }
 
+// GetNetworkHashPSResult models the data returned from the getnetworkhashps
+// command when the verbose flag is set.
+type GetNetworkHashPSResult struct {
+	HashesPerSec int64   `json:"hashespersec"`
+	Lower        int64   `json:"lower"`
+	Upper        int64   `json:"upper"`
+	Confidence   float64 `json:"confidence"`
+	Method       string  `json:"method"`
+	StartHeight  int64   `json:"startheight"`
+	EndHeight    int64   `json:"endheight"`
+}
+
 // GetNetworkInfoResult models the data returned from the getnetworkinfo
 // command.
 type GetNetworkInfoResult struct {
@@ -259,33 +600,83 @@ type GetNetworkInfoResult struct {
 
 // GetNetTotalsResult models the data returned from the getnettotals command.
 type GetNetTotalsResult struct {
-	TotalBytesRecv uint64 `json:"totalbytesrecv"`
-	TotalBytesSent uint64 `json:"totalbytessent"`
-	TimeMillis     int64  `json:"timemillis"`
+	TotalBytesRecv uint64              `json:"totalbytesrecv"`
+	TotalBytesSent uint64              `json:"totalbytessent"`
+	TimeMillis     int64               `json:"timemillis"`
+	TxRecon        *TxReconStatsResult `json:"txrecon,omitempty"`
+	TxRelay        *TxRelayStatsResult `json:"txrelay,omitempty"`
+}
+
+// TxRelayLaneResult models the latency statistics of a transaction relay lane
+// returned as part of the getnettotals command.
+type TxRelayLaneResult struct {
+	Count            uint64  `json:"count"`
+	AvgLatencyMillis float64 `json:"avglatencymillis"`
+	MaxLatencyMillis float64 `json:"maxlatencymillis"`
+}
+
+// TxRelayStatsResult models the transaction relay latency statistics of the
+// priority lane used by votes and revocations and the regular lane used by all
+// other transactions returned as part of the getnettotals command.
+type TxRelayStatsResult struct {
+	Priority TxRelayLaneResult `json:"priority"`
+	Regular  TxRelayLaneResult `json:"regular"`
+}
+
+// TxReconStatsResult models the transaction inventory reconciliation
+// statistics returned as part of the getnettotals command.
+type TxReconStatsResult struct {
+	Rounds     uint64 `json:"rounds"`
+	Failures   uint64 `json:"failures"`
+	ReconBytes uint64 `json:"reconbytes"`
+	FloodBytes uint64 `json:"floodbytes"`
 }
 
 // GetPeerInfoResult models the data returned from the getpeerinfo command.
 type GetPeerInfoResult struct {
-	ID             int32   `json:"id"`
-	Addr           string  `json:"addr"`
-	AddrLocal      string  `json:"addrlocal,omitempty"`
-	Services       string  `json:"services"`
-	RelayTxes      bool    `json:"relaytxes"`
-	LastSend       int64   `json:"lastsend"`
-	LastRecv       int64   `json:"lastrecv"`
-	BytesSent      uint64  `json:"bytessent"`
-	BytesRecv      uint64  `json:"bytesrecv"`
-	ConnTime       int64   `json:"conntime"`
-	TimeOffset     int64   `json:"timeoffset"`
-	PingTime       float64 `json:"pingtime"`
-	PingWait       float64 `json:"pingwait,omitempty"`
-	Version        uint32  `json:"version"`
-	SubVer         string  `json:"subver"`
-	Inbound        bool    `json:"inbound"`
-	StartingHeight int64   `json:"startingheight"`
-	CurrentHeight  int64   `json:"currentheight,omitempty"`
-	BanScore       int32   `json:"banscore"`
-	SyncNode       bool    `json:"syncnode"`
+	ID             int32                   `json:"id"`
+	Addr           string                  `json:"addr"`
+	AddrLocal      string                  `json:"addrlocal,omitempty"`
+	Services       string                  `json:"services"`
+	RelayTxes      bool                    `json:"relaytxes"`
+	LastSend       int64                   `json:"lastsend"`
+	LastRecv       int64                   `json:"lastrecv"`
+	BytesSent      uint64                  `json:"bytessent"`
+	BytesRecv      uint64                  `json:"bytesrecv"`
+	ConnTime       int64                   `json:"conntime"`
+	TimeOffset     int64                   `json:"timeoffset"`
+	PingTime       float64                 `json:"pingtime"`
+	PingWait       float64                 `json:"pingwait,omitempty"`
+	Version        uint32                  `json:"version"`
+	SubVer         string                  `json:"subver"`
+	Inbound        bool                    `json:"inbound"`
+	StartingHeight int64                   `json:"startingheight"`
+	CurrentHeight  int64                   `json:"currentheight,omitempty"`
+	BanScore       int32                   `json:"banscore"`
+	SyncNode       bool                    `json:"syncnode"`
+	IdentityKey    string                  `json:"identitykey,omitempty"`
+	MsgStats       map[string]PeerMsgStats `json:"msgstats,omitempty"`
+	TCPInfo        *PeerTCPInfo            `json:"tcpinfo,omitempty"`
+}
+
+// PeerMsgStats models statistics about the messages of a given type received
+// from a peer as returned by the getpeerinfo command.
+type PeerMsgStats struct {
+	Received    uint64 `json:"received"`
+	BytesRecv   uint64 `json:"bytesrecv"`
+	Oversized   uint64 `json:"oversized"`
+	RateLimited uint64 `json:"ratelimited"`
+}
+
+// PeerTCPInfo models transport statistics sampled from the TCP socket of a
+// peer connection as returned by the getpeerinfo command.
+type PeerTCPInfo struct {
+	RTT          uint32 `json:"rtt"`
+	RTTVar       uint32 `json:"rttvar"`
+	Retransmits  uint32 `json:"retransmits"`
+	TotalRetrans uint32 `json:"totalretrans"`
+	SndCwnd      uint32 `json:"sndcwnd"`
+	SampleTime   int64  `json:"sampletime"`
 }
 
 // GetRawMempoolVerboseResult models the data returned from the getrawmempool
@@ -318,6 +709,39 @@ type TxRawResult struct {
 	Blocktime     int64  `json:"blocktime,omitempty"`
 }
 
+// GetRejectedTransactionsResult models the data returned from the
+// getrejectedtransactions command.
+type GetRejectedTransactionsResult struct {
+	TxID   string `json:"txid"`
+	Code   string `json:"code"`
+	Reason string `json:"reason"`
+	Time   int64  `json:"time"`
+	Height int64  `json:"height"`
+}
+
+// SignalingBit models the number and percentage of blocks or votes in a window
+// that set a version or vote bit.
+type SignalingBit struct {
+	Bit     uint8   `json:"bit"`
+	Count   uint32  `json:"count"`
+	Percent float64 `json:"percent"`
+}
+
+// GetSignalingStatsResult models the data returned from the getsignalingstats
+// command.  Only the bits that are set by at least one block or vote in the
+// window are included.
+type GetSignalingStatsResult struct {
+	Hash             string         `json:"hash"`
+	StartHeight      int64          `json:"startheight"`
+	EndHeight        int64          `json:"endheight"`
+	NumBlocks        uint32         `json:"numblocks"`
+	NumVotes         uint32         `json:"numvotes"`
+	BlockVersions    []VersionCount `json:"blockversions"`
+	BlockVersionBits []SignalingBit `json:"blockversionbits"`
+	VoteVersions     []VersionCount `json:"voteversions"`
+	VoteBits         []SignalingBit `json:"votebits"`
+}
+
 // GetStakeDifficultyResult models the data returned from the
 // getstakedifficulty command.
 type GetStakeDifficultyResult struct {
@@ -365,7 +789,90 @@ type StakeVersions struct {
 // GetStakeVersionsResult models the data returned from the getstakeversions
 // command.
 type GetStakeVersionsResult struct {
-	StakeVersions []StakeVersions `json:"stakeversions"`
+	StakeVersions []StakeVersions   `json:"stakeversions"`
+	Intervals     []VersionInterval `json:"intervals,omitempty"`
+	NextHash      string            `json:"nexthash,omitempty"`
+}
+
+// StandardScriptVersionResult models the standardness policy for a script
+// version returned from the getstandardpolicy command.  The agenda is omitted
+// when the policy does not depend on an agenda.
+type StandardScriptVersionResult struct {
+	Version         uint16   `json:"version"`
+	Agenda          string   `json:"agenda,omitempty"`
+	Active          bool     `json:"active"`
+	Classes         []string `json:"classes"`
+	MaxMultiSigKeys int      `json:"maxmultisigkeys"`
+	MaxP2SHSigOps   int      `json:"maxp2shsigops"`
+}
+
+// GetStandardPolicyResult models the data returned from the getstandardpolicy
+// command.
+type GetStandardPolicyResult struct {
+	AcceptNonStd       bool                          `json:"acceptnonstd"`
+	MaxTxVersion       uint16                        `json:"maxtxversion"`
+	MaxTxSize          int                           `json:"maxtxsize"`
+	MaxSigScriptSize   int                           `json:"maxsigscriptsize"`
+	MaxNullDataOutputs int                           `json:"maxnulldataoutputs"`
+	ScriptVersions     []StandardScriptVersionResult `json:"scriptversions"`
+}
+
+// SyncPeerSwitchResult models a change to the sync peer returned from the
+// getsyncpeer command.
+type SyncPeerSwitchResult struct {
+	Time         int64  `json:"time"`
+	PreviousID   int32  `json:"previousid"`
+	PreviousAddr string `json:"previousaddr"`
+	ID           int32  `json:"id"`
+	Addr         string `json:"addr"`
+	Reason       string `json:"reason"`
+}
+
+// GetSyncPeerResult models the data returned from the getsyncpeer command.
+type GetSyncPeerResult struct {
+	ID             int32                  `json:"id"`
+	Addr           string                 `json:"addr"`
+	SyncHeight     int64                  `json:"syncheight"`
+	BlocksInFlight int                    `json:"blocksinflight"`
+	Deadline       int64                  `json:"deadline"`
+	Switches       []SyncPeerSwitchResult `json:"switches"`
+}
+
+// TemplateTxDecisionResult models the decision made for a transaction
+// considered for inclusion in a block template as part of the data returned
+// from the gettemplatedecisions command.  The reason is omitted for selected
+// transactions.
+type TemplateTxDecisionResult struct {
+	TxID     string  `json:"txid"`
+	Type     string  `json:"type"`
+	Decision string  `json:"decision"`
+	Reason   string  `json:"reason,omitempty"`
+	Size     uint32  `json:"size"`
+	Fee      float64 `json:"fee"`
+	FeeRate  float64 `json:"feerate"`
+}
+
+// GetTemplateDecisionsResult models the transaction selection decisions for a
+// block template returned from the gettemplatedecisions command.
+type GetTemplateDecisionsResult struct {
+	Hash         string                     `json:"hash"`
+	PreviousHash string                     `json:"previousblockhash"`
+	Height       int64                      `json:"height"`
+	Time         int64                      `json:"time"`
+	Considered   int                        `json:"considered"`
+	Selected     int                        `json:"selected"`
+	TotalFees    float64                    `json:"totalfees"`
+	Transactions []TemplateTxDecisionResult `json:"transactions"`
+}
+
+// GetTimeInfoResult models the data returned from the gettimeinfo command.
+type GetTimeInfoResult struct {
+	Hash         string `json:"hash"`
+	Height       int64  `json:"height"`
+	MedianTime   int64  `json:"mediantime"`
+	LocalTime    int64  `json:"localtime"`
+	AdjustedTime int64  `json:"adjustedtime"`
+	TimeOffset   int64  `json:"timeoffset"`
 }
 
 // GetTxOutResult models the data from the gettxout command.
@@ -385,10 +892,29 @@ type GetTxOutSetInfoResult struct {
 	Transactions   int64  `json:"transactions"`
 	TxOuts         int64  `json:"txouts"`
 	SerializedHash string `json:"serializedhash"`
+	UtxoSetHash    string `json:"utxosethash"`
 	DiskSize       int64  `json:"disksize"`
 	TotalAmount    int64  `json:"totalamount"`
 }
 
+// GetTxScriptCostResult models the data returned from the gettxscriptcost
+// command.
+type GetTxScriptCostResult struct {
+	Txid   string                  `json:"txid"`
+	Inputs []ScriptCostInputResult `json:"inputs"`
+}
+
+// ScriptCostInputResult models the cost of executing the scripts of a single
+// transaction input as returned by the gettxscriptcost command.
+type ScriptCostInputResult struct {
+	Index    uint32 `json:"index"`
+	Opcodes  int64  `json:"opcodes"`
+	SigOps   int    `json:"sigops"`
+	Duration int64  `json:"duration"`
+	Valid    bool   `json:"valid"`
+	Error    string `json:"error,omitempty"`
+}
+
 // Choice models an individual choice inside an Agenda.
 type Choice struct {
 	ID          string  `json:"id"`
@@ -412,16 +938,35 @@ type Agenda struct {
 	Choices        []Choice `json:"choices"`
 }
 
+// AgendaVoteCounts models the vote counts for an agenda over a rule change
+// interval.
+type AgendaVoteCounts struct {
+	ID           string   `json:"id"`
+	Total        uint32   `json:"total"`
+	TotalAbstain uint32   `json:"totalabstain"`
+	ChoiceCounts []uint32 `json:"choicecounts"`
+}
+
+// VoteInterval models the vote counts for all agendas of a vote version over a
+// completed rule change interval.
+type VoteInterval struct {
+	StartHeight int64              `json:"startheight"`
+	EndHeight   int64              `json:"endheight"`
+	Hash        string             `json:"hash"`
+	Agendas     []AgendaVoteCounts `json:"agendas"`
+}
+
 // GetVoteInfoResult models the data returned from the getvoteinfo command.
 type GetVoteInfoResult struct {
-	CurrentHeight int64    `json:"currentheight"`
-	StartHeight   int64    `json:"startheight"`
-	EndHeight     int64    `json:"endheight"`
-	Hash          string   `json:"hash"`
-	VoteVersion   uint32   `json:"voteversion"`
-	Quorum        uint32   `json:"quorum"`
-	TotalVotes    uint32   `json:"totalvotes"`
-	Agendas       []Agenda `json:"agendas,omitempty"`
+	CurrentHeight  int64          `json:"currentheight"`
+	StartHeight    int64          `json:"startheight"`
+	EndHeight      int64          `json:"endheight"`
+	Hash           string         `json:"hash"`
+	VoteVersion    uint32         `json:"voteversion"`
+	Quorum         uint32         `json:"quorum"`
+	TotalVotes     uint32         `json:"totalvotes"`
+	Agendas        []Agenda       `json:"agendas,omitempty"`
+	PriorIntervals []VoteInterval `json:"priorintervals,omitempty"`
 }
 
 // GetWorkResult models the data from the getwork command.
@@ -430,12 +975,59 @@ type GetWorkResult struct {
 	Target string `json:"target"`
 }
 
+// GetWorkClientResult models the getwork statistics of a client returned from
+// the getworkstats command.
+type GetWorkClientResult struct {
+	Addr          string  `json:"addr"`
+	FirstSeen     int64   `json:"firstseen"`
+	Requests      uint64  `json:"requests"`
+	RequestRate   float64 `json:"requestrate"`
+	LastRequest   int64   `json:"lastrequest"`
+	Submitted     uint64  `json:"submitted"`
+	Accepted      uint64  `json:"accepted"`
+	Rejected      uint64  `json:"rejected"`
+	Stale         uint64  `json:"stale"`
+	LastSubmitted int64   `json:"lastsubmitted"`
+	LastAccepted  int64   `json:"lastaccepted"`
+}
+
+// GetWorkStatsResult models the data returned from the getworkstats command.
+type GetWorkStatsResult struct {
+	Clients []GetWorkClientResult `json:"clients"`
+}
+
 // Ticket is the structure representing a ticket.
 type Ticket struct {
 	Hash  string `json:"hash"`
 	Owner string `json:"owner"`
 }
 
+// BannedPeer models a banned host as part of the data returned from the
+// listbanned command.
+type BannedPeer struct {
+	Host        string `json:"host"`
+	BannedUntil int64  `json:"banneduntil"`
+}
+
+// ListBannedResult models the data returned from the listbanned command.
+type ListBannedResult struct {
+	Banned []BannedPeer `json:"banned"`
+	Page   PageInfo     `json:"page"`
+}
+
+// ListRPCClientsResult models the data returned for each connected websocket
+// client from the listrpcclients command.
+type ListRPCClientsResult struct {
+	SessionID     uint64   `json:"sessionid"`
+	Addr          string   `json:"addr"`
+	Name          string   `json:"name,omitempty"`
+	Version       string   `json:"version,omitempty"`
+	Authenticated bool     `json:"authenticated"`
+	Admin         bool     `json:"admin"`
+	ConnTime      int64    `json:"conntime"`
+	Subscriptions []string `json:"subscriptions"`
+}
+
 // LiveTicketsResult models the data returned from the livetickets
 // command.
 type LiveTicketsResult struct {
@@ -448,6 +1040,15 @@ type MissedTicketsResult struct {
 	Tickets []string `json:"tickets"`
 }
 
+// ProposeBlockResult models the data returned from the proposeblock command.
+// The reject code and reason are omitted when the block is valid.
+type ProposeBlockResult struct {
+	Hash       string `json:"hash"`
+	Valid      bool   `json:"valid"`
+	RejectCode string `json:"rejectcode,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+}
+
 // FeeInfoBlock is ticket fee information about a block.
 type FeeInfoBlock struct {
 	Height uint32  `json:"height"`
@@ -499,6 +1100,34 @@ type TicketFeeInfoResult struct {
 	FeeInfoWindows []FeeInfoWindow `json:"feeinfowindows"`
 }
 
+// These constants define the categories of rule violations reported via
+// RuleViolationData.
+const (
+	// RuleViolationConsensus indicates a violation of the consensus rules.
+	// Items that violate consensus rules are invalid and will never be
+	// accepted by the network.
+	RuleViolationConsensus = "consensus"
+
+	// RuleViolationPolicy indicates a violation of the local standardness
+	// and relay policy of the server.  Items that violate policy might
+	// still be valid and accepted by other nodes or at a later time.
+	RuleViolationPolicy = "policy"
+)
+
+// RuleViolationData models the data included with JSON-RPC errors that are
+// returned when a transaction is rejected due to a rule violation.
+//
+// The Code field is a stable machine-readable identifier for the specific
+// violation within its category, such as ErrExpiredTx for consensus violations
+// or ErrInsufficientFee for policy violations.  InputIndex is only set when the
+// violation is attributable to a specific transaction input.
+type RuleViolationData struct {
+	Category   string `json:"category"`
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+	InputIndex *int   `json:"inputindex,omitempty"`
+}
+
 // SearchRawTransactionsResult models the data from the searchrawtransaction
 // command.
 type SearchRawTransactionsResult struct {
@@ -517,6 +1146,54 @@ type SearchRawTransactionsResult struct {
 	Blocktime     int64        `json:"blocktime,omitempty"`
 }
 
+// PageInfo models the pagination details of the data returned from commands
+// that return large lists of results a page at a time.  The next page is
+// requested by passing the next cursor to the same command with otherwise
+// identical parameters.  The total estimate is exact when there is no next
+// page and a lower bound otherwise.
+type PageInfo struct {
+	NextCursor    string `json:"nextcursor,omitempty"`
+	Limit         int    `json:"limit"`
+	TotalEstimate int64  `json:"totalestimate"`
+}
+
+// SearchRawTransactionsPageResult models the data returned from the
+// searchrawtransactions command when a cursor is provided.  Only one of the
+// hex-encoded or verbose transactions is set depending on the verbose flag.
+type SearchRawTransactionsPageResult struct {
+	Hex          []string                      `json:"hex,omitempty"`
+	Transactions []SearchRawTransactionsResult `json:"transactions,omitempty"`
+	Page         PageInfo                      `json:"page"`
+}
+
+// SimulateDifficultyRetarget describes a projected difficulty retarget as part
+// of the data returned from the simulatedifficulty command.
+type SimulateDifficultyRetarget struct {
+	Height     int64   `json:"height"`
+	Bits       string  `json:"bits"`
+	Difficulty float64 `json:"difficulty"`
+	Time       int64   `json:"time"`
+}
+
+// SimulateDifficultyScenario describes the projected difficulty retargets for
+// a single hypothetical hash rate as part of the data returned from the
+// simulatedifficulty command.
+type SimulateDifficultyScenario struct {
+	HashRateMultiplier float64                      `json:"hashratemultiplier"`
+	HashesPerSec       int64                        `json:"hashespersec"`
+	Retargets          []SimulateDifficultyRetarget `json:"retargets"`
+}
+
+// SimulateDifficultyResult models the data returned from the
+// simulatedifficulty command.
+type SimulateDifficultyResult struct {
+	Height        int64                        `json:"height"`
+	Bits          string                       `json:"bits"`
+	Difficulty    float64                      `json:"difficulty"`
+	NetworkHashPS int64                        `json:"networkhashps"`
+	Scenarios     []SimulateDifficultyScenario `json:"scenarios"`
+}
+
 // TxFeeInfoResult models the data returned from the ticketfeeinfo command.
 // command.
 type TxFeeInfoResult struct {
@@ -538,6 +1215,18 @@ type ValidateAddressChainResult struct {
 	Address string `json:"address,omitempty"`
 }
 
+// VerifyMessageResult models the data returned by the chain server
+// verifymessage command when the verbose flag is set.
+type VerifyMessageResult struct {
+	Verified     bool     `json:"verified"`
+	Address      string   `json:"address"`
+	ScriptType   string   `json:"scripttype"`
+	SigType      string   `json:"sigtype,omitempty"`
+	SigsRequired int32    `json:"sigsrequired,omitempty"`
+	Signers      []string `json:"signers,omitempty"`
+	Reason       string   `json:"reason,omitempty"`
+}
+
 // VersionResult models objects included in the version response.  In the actual
 // result, these objects are keyed by the program or API name.
 type VersionResult struct {
@@ -52,12 +52,28 @@ func NewLoadTxFilterCmd(reload bool, addresses []string, outPoints []OutPoint) *
 }
 
 // NotifyBlocksCmd defines the notifyblocks JSON-RPC command.
-type NotifyBlocksCmd struct{}
+type NotifyBlocksCmd struct {
+	StakeEvents *bool `jsonrpcdefault:"false"`
+}
 
 // NewNotifyBlocksCmd returns a new instance which can be used to issue a
 // notifyblocks JSON-RPC command.
-func NewNotifyBlocksCmd() *NotifyBlocksCmd {
-	return &NotifyBlocksCmd{}
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewNotifyBlocksCmd(stakeEvents *bool) *NotifyBlocksCmd {
+	return &NotifyBlocksCmd{
+		StakeEvents: stakeEvents,
+	}
+}
+
+// NotifyFilteredBlocksCmd defines the notifyfilteredblocks JSON-RPC command.
+type NotifyFilteredBlocksCmd struct{}
+
+// NewNotifyFilteredBlocksCmd returns a new instance which can be used to issue
+// a notifyfilteredblocks JSON-RPC command.
+func NewNotifyFilteredBlocksCmd() *NotifyFilteredBlocksCmd {
+	return &NotifyFilteredBlocksCmd{}
 }
 
 // NotifyWorkCmd defines the notifywork JSON-RPC command.
@@ -69,6 +85,15 @@ func NewNotifyWorkCmd() *NotifyWorkCmd {
 	return &NotifyWorkCmd{}
 }
 
+// NotifyDiskSpaceCmd defines the notifydiskspace JSON-RPC command.
+type NotifyDiskSpaceCmd struct{}
+
+// NewNotifyDiskSpaceCmd returns a new instance which can be used to issue a
+// notifydiskspace JSON-RPC command.
+func NewNotifyDiskSpaceCmd() *NotifyDiskSpaceCmd {
+	return &NotifyDiskSpaceCmd{}
+}
+
 // NotifyWinningTicketsCmd is a type handling custom marshaling and
 // unmarshaling of notifywinningtickets JSON websocket extension
 // commands.
@@ -122,6 +147,16 @@ func NewStopNotifyBlocksCmd() *StopNotifyBlocksCmd {
 	return &StopNotifyBlocksCmd{}
 }
 
+// StopNotifyFilteredBlocksCmd defines the stopnotifyfilteredblocks JSON-RPC
+// command.
+type StopNotifyFilteredBlocksCmd struct{}
+
+// NewStopNotifyFilteredBlocksCmd returns a new instance which can be used to
+// issue a stopnotifyfilteredblocks JSON-RPC command.
+func NewStopNotifyFilteredBlocksCmd() *StopNotifyFilteredBlocksCmd {
+	return &StopNotifyFilteredBlocksCmd{}
+}
+
 // StopNotifyWorkCmd defines the stopnotifywork JSON-RPC command.
 type StopNotifyWorkCmd struct{}
 
@@ -168,6 +203,24 @@ func NewStopNotifyNewTransactionsCmd() *StopNotifyNewTransactionsCmd {
 	return &StopNotifyNewTransactionsCmd{}
 }
 
+// RegisterClientCmd defines the registerclient JSON-RPC command.
+type RegisterClientCmd struct {
+	Name    string
+	Version *string
+}
+
+// NewRegisterClientCmd returns a new instance which can be used to issue a
+// registerclient JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewRegisterClientCmd(name string, version *string) *RegisterClientCmd {
+	return &RegisterClientCmd{
+		Name:    name,
+		Version: version,
+	}
+}
+
 // RescanCmd defines the rescan JSON-RPC command.
 type RescanCmd struct {
 	BlockHashes []string
@@ -186,6 +239,8 @@ func init() {
 	dcrjson.MustRegister(Method("authenticate"), (*AuthenticateCmd)(nil), flags)
 	dcrjson.MustRegister(Method("loadtxfilter"), (*LoadTxFilterCmd)(nil), flags)
 	dcrjson.MustRegister(Method("notifyblocks"), (*NotifyBlocksCmd)(nil), flags)
+	dcrjson.MustRegister(Method("notifydiskspace"), (*NotifyDiskSpaceCmd)(nil), flags)
+	dcrjson.MustRegister(Method("notifyfilteredblocks"), (*NotifyFilteredBlocksCmd)(nil), flags)
 	dcrjson.MustRegister(Method("notifywork"), (*NotifyWorkCmd)(nil), flags)
 	dcrjson.MustRegister(Method("notifynewtransactions"), (*NotifyNewTransactionsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("notifynewtickets"), (*NotifyNewTicketsCmd)(nil), flags)
@@ -195,8 +250,10 @@ func init() {
 		(*NotifyStakeDifficultyCmd)(nil), flags)
 	dcrjson.MustRegister(Method("notifywinningtickets"),
 		(*NotifyWinningTicketsCmd)(nil), flags)
+	dcrjson.MustRegister(Method("registerclient"), (*RegisterClientCmd)(nil), flags)
 	dcrjson.MustRegister(Method("session"), (*SessionCmd)(nil), flags)
 	dcrjson.MustRegister(Method("stopnotifyblocks"), (*StopNotifyBlocksCmd)(nil), flags)
+	dcrjson.MustRegister(Method("stopnotifyfilteredblocks"), (*StopNotifyFilteredBlocksCmd)(nil), flags)
 	dcrjson.MustRegister(Method("stopnotifywork"), (*StopNotifyWorkCmd)(nil), flags)
 	dcrjson.MustRegister(Method("stopnotifynewtransactions"), (*StopNotifyNewTransactionsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("rescan"), (*RescanCmd)(nil), flags)
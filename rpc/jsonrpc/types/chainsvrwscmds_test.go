@@ -91,10 +91,47 @@ func TestChainSvrWsCmds(t *testing.T) {
 				return dcrjson.NewCmd(Method("notifyblocks"))
 			},
 			staticCmd: func() interface{} {
-				return NewNotifyBlocksCmd()
+				return NewNotifyBlocksCmd(nil)
 			},
-			marshalled:   `{"jsonrpc":"1.0","method":"notifyblocks","params":[],"id":1}`,
-			unmarshalled: &NotifyBlocksCmd{},
+			marshalled: `{"jsonrpc":"1.0","method":"notifyblocks","params":[],"id":1}`,
+			unmarshalled: &NotifyBlocksCmd{
+				StakeEvents: dcrjson.Bool(false),
+			},
+		},
+		{
+			name: "notifyblocks optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("notifyblocks"), true)
+			},
+			staticCmd: func() interface{} {
+				return NewNotifyBlocksCmd(dcrjson.Bool(true))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"notifyblocks","params":[true],"id":1}`,
+			unmarshalled: &NotifyBlocksCmd{
+				StakeEvents: dcrjson.Bool(true),
+			},
+		},
+		{
+			name: "notifydiskspace",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("notifydiskspace"))
+			},
+			staticCmd: func() interface{} {
+				return NewNotifyDiskSpaceCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"notifydiskspace","params":[],"id":1}`,
+			unmarshalled: &NotifyDiskSpaceCmd{},
+		},
+		{
+			name: "notifyfilteredblocks",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("notifyfilteredblocks"))
+			},
+			staticCmd: func() interface{} {
+				return NewNotifyFilteredBlocksCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"notifyfilteredblocks","params":[],"id":1}`,
+			unmarshalled: &NotifyFilteredBlocksCmd{},
 		},
 		{
 			name: "notifywork",
@@ -118,6 +155,17 @@ func TestChainSvrWsCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"stopnotifyblocks","params":[],"id":1}`,
 			unmarshalled: &StopNotifyBlocksCmd{},
 		},
+		{
+			name: "stopnotifyfilteredblocks",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("stopnotifyfilteredblocks"))
+			},
+			staticCmd: func() interface{} {
+				return NewStopNotifyFilteredBlocksCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"stopnotifyfilteredblocks","params":[],"id":1}`,
+			unmarshalled: &StopNotifyFilteredBlocksCmd{},
+		},
 		{
 			name: "stopnotifywork",
 			newCmd: func() (interface{}, error) {
@@ -166,6 +214,34 @@ func TestChainSvrWsCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"stopnotifynewtransactions","params":[],"id":1}`,
 			unmarshalled: &StopNotifyNewTransactionsCmd{},
 		},
+		{
+			name: "registerclient",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("registerclient"), "dcrdata", "6.0.0")
+			},
+			staticCmd: func() interface{} {
+				return NewRegisterClientCmd("dcrdata", dcrjson.String("6.0.0"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"registerclient","params":["dcrdata","6.0.0"],"id":1}`,
+			unmarshalled: &RegisterClientCmd{
+				Name:    "dcrdata",
+				Version: dcrjson.String("6.0.0"),
+			},
+		},
+		{
+			name: "registerclient optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("registerclient"), "dcrdata")
+			},
+			staticCmd: func() interface{} {
+				return NewRegisterClientCmd("dcrdata", nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"registerclient","params":["dcrdata"],"id":1}`,
+			unmarshalled: &RegisterClientCmd{
+				Name:    "dcrdata",
+				Version: nil,
+			},
+		},
 		{
 			name: "rescan",
 			newCmd: func() (interface{}, error) {
@@ -19,6 +19,16 @@ const (
 	// the chain server that a block has been disconnected.
 	BlockDisconnectedNtfnMethod Method = "blockdisconnected"
 
+	// FilteredBlockConnectedNtfnMethod is the method used for notifications
+	// from the chain server that a block has been connected that only include
+	// the transactions that match the client's transaction filter.
+	FilteredBlockConnectedNtfnMethod Method = "filteredblockconnected"
+
+	// DiskSpaceNtfnMethod is the method used for notifications from the
+	// chain server that the free disk space available to its data directory
+	// crossed one of the configured thresholds.
+	DiskSpaceNtfnMethod Method = "diskspace"
+
 	// NewTicketsNtfnMethod is the method of the daemon newtickets notification.
 	NewTicketsNtfnMethod Method = "newtickets"
 
@@ -45,6 +55,12 @@ const (
 	// transaction was accepted by the mempool.
 	RelevantTxAcceptedNtfnMethod Method = "relevanttxaccepted"
 
+	// RelevantTxUnminedNtfnMethod is the method used for notifications
+	// from the chain server that inform a client that a relevant
+	// transaction returned to the mempool because the block that mined it
+	// was disconnected from the main chain.
+	RelevantTxUnminedNtfnMethod Method = "relevanttxunmined"
+
 	// SpentAndMissedTicketsNtfnMethod is the method of the daemon
 	// spentandmissedtickets notification.
 	SpentAndMissedTicketsNtfnMethod Method = "spentandmissedtickets"
@@ -53,23 +69,48 @@ const (
 	// notification.
 	StakeDifficultyNtfnMethod Method = "stakedifficulty"
 
+	// StakeDifficultyChangeNtfnMethod is the method of the daemon
+	// stakedifficultychange notification.
+	StakeDifficultyChangeNtfnMethod Method = "stakedifficultychange"
+
 	// WinningTicketsNtfnMethod is the method of the daemon winningtickets
 	// notification.
 	WinningTicketsNtfnMethod Method = "winningtickets"
 )
 
 // BlockConnectedNtfn defines the blockconnected JSON-RPC notification.
+//
+// The stake events are only included for clients that requested them when
+// registering for block notifications.
 type BlockConnectedNtfn struct {
-	Header        string   `json:"header"`
-	SubscribedTxs []string `json:"subscribedtxs"`
+	Header        string            `json:"header"`
+	SubscribedTxs []string          `json:"subscribedtxs"`
+	StakeEvents   *BlockStakeEvents `json:"stakeevents,omitempty"`
 }
 
 // NewBlockConnectedNtfn returns a new instance which can be used to issue a
 // blockconnected JSON-RPC notification.
-func NewBlockConnectedNtfn(header string, subscribedTxs []string) *BlockConnectedNtfn {
+func NewBlockConnectedNtfn(header string, subscribedTxs []string, stakeEvents *BlockStakeEvents) *BlockConnectedNtfn {
 	return &BlockConnectedNtfn{
 		Header:        header,
 		SubscribedTxs: subscribedTxs,
+		StakeEvents:   stakeEvents,
+	}
+}
+
+// FilteredBlockConnectedNtfn defines the filteredblockconnected JSON-RPC
+// notification.
+type FilteredBlockConnectedNtfn struct {
+	Header       string            `json:"header"`
+	Transactions []FilteredBlockTx `json:"transactions"`
+}
+
+// NewFilteredBlockConnectedNtfn returns a new instance which can be used to
+// issue a filteredblockconnected JSON-RPC notification.
+func NewFilteredBlockConnectedNtfn(header string, transactions []FilteredBlockTx) *FilteredBlockConnectedNtfn {
+	return &FilteredBlockConnectedNtfn{
+		Header:       header,
+		Transactions: transactions,
 	}
 }
 
@@ -121,6 +162,26 @@ func NewWorkNtfn(data string, target string) *WorkNtfn {
 	}
 }
 
+// DiskSpaceNtfn defines the diskspace JSON-RPC notification.  All sizes are in
+// bytes.
+type DiskSpaceNtfn struct {
+	Level         string `json:"level"`
+	Free          uint64 `json:"free"`
+	WarnThreshold uint64 `json:"warnthreshold"`
+	StopThreshold uint64 `json:"stopthreshold"`
+}
+
+// NewDiskSpaceNtfn returns a new instance which can be used to issue a
+// diskspace JSON-RPC notification.
+func NewDiskSpaceNtfn(level string, free, warnThreshold, stopThreshold uint64) *DiskSpaceNtfn {
+	return &DiskSpaceNtfn{
+		Level:         level,
+		Free:          free,
+		WarnThreshold: warnThreshold,
+		StopThreshold: stopThreshold,
+	}
+}
+
 // ReorganizationNtfn defines the reorganization JSON-RPC notification.
 type ReorganizationNtfn struct {
 	OldHash   string `json:"oldhash"`
@@ -177,6 +238,27 @@ func NewStakeDifficultyNtfn(hash string, height int32, stakeDiff int64) *StakeDi
 	}
 }
 
+// StakeDifficultyChangeNtfn is a type handling custom marshaling and
+// unmarshaling of stakedifficultychange JSON websocket notifications.
+type StakeDifficultyChangeNtfn struct {
+	BlockHash          string
+	BlockHeight        int32
+	OldStakeDiff       int64
+	NewStakeDiff       int64
+	NextRetargetHeight int32
+}
+
+// NewStakeDifficultyChangeNtfn creates a new StakeDifficultyChangeNtfn.
+func NewStakeDifficultyChangeNtfn(hash string, height int32, oldStakeDiff, newStakeDiff int64, nextRetargetHeight int32) *StakeDifficultyChangeNtfn {
+	return &StakeDifficultyChangeNtfn{
+		BlockHash:          hash,
+		BlockHeight:        height,
+		OldStakeDiff:       oldStakeDiff,
+		NewStakeDiff:       newStakeDiff,
+		NextRetargetHeight: nextRetargetHeight,
+	}
+}
+
 // TxAcceptedNtfn defines the txaccepted JSON-RPC notification.
 type TxAcceptedNtfn struct {
 	TxID   string  `json:"txid"`
@@ -217,6 +299,18 @@ func NewRelevantTxAcceptedNtfn(txHex string) *RelevantTxAcceptedNtfn {
 	return &RelevantTxAcceptedNtfn{Transaction: txHex}
 }
 
+// RelevantTxUnminedNtfn defines the parameters to the relevanttxunmined
+// JSON-RPC notification.
+type RelevantTxUnminedNtfn struct {
+	Transaction string `json:"transaction"`
+}
+
+// NewRelevantTxUnminedNtfn returns a new instance which can be used to issue a
+// relevanttxunmined JSON-RPC notification.
+func NewRelevantTxUnminedNtfn(txHex string) *RelevantTxUnminedNtfn {
+	return &RelevantTxUnminedNtfn{Transaction: txHex}
+}
+
 // WinningTicketsNtfn is a type handling custom marshaling and
 // unmarshaling of blockconnected JSON websocket notifications.
 type WinningTicketsNtfn struct {
@@ -241,13 +335,17 @@ func init() {
 
 	dcrjson.MustRegister(BlockConnectedNtfnMethod, (*BlockConnectedNtfn)(nil), flags)
 	dcrjson.MustRegister(BlockDisconnectedNtfnMethod, (*BlockDisconnectedNtfn)(nil), flags)
+	dcrjson.MustRegister(DiskSpaceNtfnMethod, (*DiskSpaceNtfn)(nil), flags)
+	dcrjson.MustRegister(FilteredBlockConnectedNtfnMethod, (*FilteredBlockConnectedNtfn)(nil), flags)
 	dcrjson.MustRegister(WorkNtfnMethod, (*WorkNtfn)(nil), flags)
 	dcrjson.MustRegister(NewTicketsNtfnMethod, (*NewTicketsNtfn)(nil), flags)
 	dcrjson.MustRegister(ReorganizationNtfnMethod, (*ReorganizationNtfn)(nil), flags)
 	dcrjson.MustRegister(TxAcceptedNtfnMethod, (*TxAcceptedNtfn)(nil), flags)
 	dcrjson.MustRegister(TxAcceptedVerboseNtfnMethod, (*TxAcceptedVerboseNtfn)(nil), flags)
 	dcrjson.MustRegister(RelevantTxAcceptedNtfnMethod, (*RelevantTxAcceptedNtfn)(nil), flags)
+	dcrjson.MustRegister(RelevantTxUnminedNtfnMethod, (*RelevantTxUnminedNtfn)(nil), flags)
 	dcrjson.MustRegister(SpentAndMissedTicketsNtfnMethod, (*SpentAndMissedTicketsNtfn)(nil), flags)
 	dcrjson.MustRegister(StakeDifficultyNtfnMethod, (*StakeDifficultyNtfn)(nil), flags)
+	dcrjson.MustRegister(StakeDifficultyChangeNtfnMethod, (*StakeDifficultyChangeNtfn)(nil), flags)
 	dcrjson.MustRegister(WinningTicketsNtfnMethod, (*WinningTicketsNtfn)(nil), flags)
 }
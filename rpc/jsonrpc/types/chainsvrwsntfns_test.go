@@ -35,7 +35,7 @@ func TestChainSvrWsNtfns(t *testing.T) {
 				return dcrjson.NewCmd(Method("blockconnected"), "header", []string{"tx0", "tx1"})
 			},
 			staticNtfn: func() interface{} {
-				return NewBlockConnectedNtfn("header", []string{"tx0", "tx1"})
+				return NewBlockConnectedNtfn("header", []string{"tx0", "tx1"}, nil)
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"blockconnected","params":["header",["tx0","tx1"]],"id":null}`,
 			unmarshalled: &BlockConnectedNtfn{
@@ -43,6 +43,90 @@ func TestChainSvrWsNtfns(t *testing.T) {
 				SubscribedTxs: []string{"tx0", "tx1"},
 			},
 		},
+		{
+			name: "blockconnected with stake events",
+			newNtfn: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("blockconnected"), "header",
+					[]string{}, `{"tickets":[{"txhash":"t1","price":0.5}],"votes":[{"txhash":"v1","ticket":"t0","version":8,"votebits":5,"approves":true,"choices":[{"agendaid":"a","choiceid":"yes"}]}],"revocations":[{"txhash":"r1","ticket":"t2"}]}`)
+			},
+			staticNtfn: func() interface{} {
+				return NewBlockConnectedNtfn("header", []string{},
+					&BlockStakeEvents{
+						Tickets: []TicketPurchaseEvent{{
+							TxHash: "t1",
+							Price:  0.5,
+						}},
+						Votes: []VoteEvent{{
+							TxHash:   "v1",
+							Ticket:   "t0",
+							Version:  8,
+							VoteBits: 5,
+							Approves: true,
+							Choices: []VoteChoiceEvent{{
+								AgendaID: "a",
+								ChoiceID: "yes",
+							}},
+						}},
+						Revocations: []RevocationEvent{{
+							TxHash: "r1",
+							Ticket: "t2",
+						}},
+					})
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"blockconnected","params":["header",[],{"tickets":[{"txhash":"t1","price":0.5}],"votes":[{"txhash":"v1","ticket":"t0","version":8,"votebits":5,"approves":true,"choices":[{"agendaid":"a","choiceid":"yes"}]}],"revocations":[{"txhash":"r1","ticket":"t2"}]}],"id":null}`,
+			unmarshalled: &BlockConnectedNtfn{
+				Header:        "header",
+				SubscribedTxs: []string{},
+				StakeEvents: &BlockStakeEvents{
+					Tickets: []TicketPurchaseEvent{{
+						TxHash: "t1",
+						Price:  0.5,
+					}},
+					Votes: []VoteEvent{{
+						TxHash:   "v1",
+						Ticket:   "t0",
+						Version:  8,
+						VoteBits: 5,
+						Approves: true,
+						Choices: []VoteChoiceEvent{{
+							AgendaID: "a",
+							ChoiceID: "yes",
+						}},
+					}},
+					Revocations: []RevocationEvent{{
+						TxHash: "r1",
+						Ticket: "t2",
+					}},
+				},
+			},
+		},
+		{
+			name: "filteredblockconnected",
+			newNtfn: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("filteredblockconnected"), "header",
+					`[{"transaction":"tx0","tree":1,"index":2,"proofindex":6,"proof":["h0","h1","h2"]}]`)
+			},
+			staticNtfn: func() interface{} {
+				return NewFilteredBlockConnectedNtfn("header", []FilteredBlockTx{{
+					Transaction: "tx0",
+					Tree:        1,
+					Index:       2,
+					ProofIndex:  6,
+					Proof:       []string{"h0", "h1", "h2"},
+				}})
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"filteredblockconnected","params":["header",[{"transaction":"tx0","tree":1,"index":2,"proofindex":6,"proof":["h0","h1","h2"]}]],"id":null}`,
+			unmarshalled: &FilteredBlockConnectedNtfn{
+				Header: "header",
+				Transactions: []FilteredBlockTx{{
+					Transaction: "tx0",
+					Tree:        1,
+					Index:       2,
+					ProofIndex:  6,
+					Proof:       []string{"h0", "h1", "h2"},
+				}},
+			},
+		},
 		{
 			name: "blockdisconnected",
 			newNtfn: func() (interface{}, error) {
@@ -56,6 +140,22 @@ func TestChainSvrWsNtfns(t *testing.T) {
 				Header: "header",
 			},
 		},
+		{
+			name: "diskspace",
+			newNtfn: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("diskspace"), "low", 1000, 2000, 500)
+			},
+			staticNtfn: func() interface{} {
+				return NewDiskSpaceNtfn("low", 1000, 2000, 500)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"diskspace","params":["low",1000,2000,500],"id":null}`,
+			unmarshalled: &DiskSpaceNtfn{
+				Level:         "low",
+				Free:          1000,
+				WarnThreshold: 2000,
+				StopThreshold: 500,
+			},
+		},
 		{
 			name: "newtickets",
 			newNtfn: func() (interface{}, error) {
@@ -85,6 +185,19 @@ func TestChainSvrWsNtfns(t *testing.T) {
 				Transaction: "001122",
 			},
 		},
+		{
+			name: "relevanttxunmined",
+			newNtfn: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("relevanttxunmined"), "001122")
+			},
+			staticNtfn: func() interface{} {
+				return NewRelevantTxUnminedNtfn("001122")
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"relevanttxunmined","params":["001122"],"id":null}`,
+			unmarshalled: &RelevantTxUnminedNtfn{
+				Transaction: "001122",
+			},
+		},
 		{
 			name: "spentandmissedtickets",
 			newNtfn: func() (interface{}, error) {
@@ -101,6 +214,23 @@ func TestChainSvrWsNtfns(t *testing.T) {
 				Tickets:   map[string]string{"a": "b"},
 			},
 		},
+		{
+			name: "stakedifficultychange",
+			newNtfn: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("stakedifficultychange"), "123", 143, 100, 200, 287)
+			},
+			staticNtfn: func() interface{} {
+				return NewStakeDifficultyChangeNtfn("123", 143, 100, 200, 287)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"stakedifficultychange","params":["123",143,100,200,287],"id":null}`,
+			unmarshalled: &StakeDifficultyChangeNtfn{
+				BlockHash:          "123",
+				BlockHeight:        143,
+				OldStakeDiff:       100,
+				NewStakeDiff:       200,
+				NextRetargetHeight: 287,
+			},
+		},
 		{
 			name: "txaccepted",
 			newNtfn: func() (interface{}, error) {
@@ -21,3 +21,59 @@ type RescannedBlock struct {
 	Hash         string   `json:"hash"`
 	Transactions []string `json:"transactions"`
 }
+
+// BlockStakeEvents models the summary of the stake events in a block that is
+// included in blockconnected notifications when requested.
+type BlockStakeEvents struct {
+	Tickets     []TicketPurchaseEvent `json:"tickets"`
+	Votes       []VoteEvent           `json:"votes"`
+	Revocations []RevocationEvent     `json:"revocations"`
+}
+
+// TicketPurchaseEvent describes a ticket purchased in a block.
+type TicketPurchaseEvent struct {
+	TxHash string  `json:"txhash"`
+	Price  float64 `json:"price"`
+}
+
+// VoteEvent describes a vote cast in a block along with the choices it made
+// for each agenda of its vote version.
+type VoteEvent struct {
+	TxHash   string            `json:"txhash"`
+	Ticket   string            `json:"ticket"`
+	Version  uint32            `json:"version"`
+	VoteBits uint16            `json:"votebits"`
+	Approves bool              `json:"approves"`
+	Choices  []VoteChoiceEvent `json:"choices,omitempty"`
+}
+
+// VoteChoiceEvent describes the choice a vote made for an agenda.
+type VoteChoiceEvent struct {
+	AgendaID string `json:"agendaid"`
+	ChoiceID string `json:"choiceid"`
+}
+
+// RevocationEvent describes a ticket revoked in a block.
+type RevocationEvent struct {
+	TxHash string `json:"txhash"`
+	Ticket string `json:"ticket"`
+}
+
+// FilteredBlockTx models a transaction that matched the transaction filter of
+// a client along with a merkle inclusion proof for it as included in
+// filteredblockconnected notifications.
+//
+// The proof consists of the hex-encoded sibling hashes from the full hash of
+// the transaction through the merkle root committed to by the block header and
+// is verified with the proof index.  When the block commits to the combined
+// merkle root of both transaction trees per DCP0005, the root is the merkle
+// root of the header for transactions in either tree.  Otherwise, it is the
+// merkle root of the header for regular transactions and the stake root for
+// stake transactions.
+type FilteredBlockTx struct {
+	Transaction string   `json:"transaction"`
+	Tree        int8     `json:"tree"`
+	Index       uint32   `json:"index"`
+	ProofIndex  uint32   `json:"proofindex"`
+	Proof       []string `json:"proof"`
+}
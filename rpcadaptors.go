@@ -20,6 +20,7 @@ import (
 	"github.com/decred/dcrd/internal/mining"
 	"github.com/decred/dcrd/internal/mining/cpuminer"
 	"github.com/decred/dcrd/internal/rpcserver"
+	"github.com/decred/dcrd/internal/txrecon"
 	"github.com/decred/dcrd/peer/v2"
 	"github.com/decred/dcrd/wire"
 )
@@ -105,6 +106,19 @@ func (p *rpcPeer) BanScore() uint32 {
 	return (*serverPeer)(p).banScore.Int()
 }
 
+// TCPInfo returns the transport statistics most recently sampled from the TCP
+// socket of the peer connection or nil when none are available.
+//
+// This function is safe for concurrent access and is part of the rpcserver.Peer
+// interface implementation.
+func (p *rpcPeer) TCPInfo() *rpcserver.PeerTCPInfo {
+	sp := (*serverPeer)(p)
+	sp.tcpInfoMtx.Lock()
+	info := sp.tcpInfo
+	sp.tcpInfoMtx.Unlock()
+	return info
+}
+
 // rpcConnManager provides a connection manager for use with the RPC server and
 // implements the rpcserver.ConnManager interface.
 type rpcConnManager struct {
@@ -219,6 +233,27 @@ func (cm *rpcConnManager) NetTotals() (uint64, uint64) {
 	return cm.server.NetTotals()
 }
 
+// TxReconStats returns statistics about the transaction inventory
+// reconciliation rounds performed with all peers.  The second return value is
+// false when reconciliation is disabled.
+//
+// This function is safe for concurrent access and is part of the
+// rpcserver.ConnManager interface implementation.
+func (cm *rpcConnManager) TxReconStats() (txrecon.Stats, bool) {
+	return cm.server.TxReconStats()
+}
+
+// BannedPeers returns the hosts that are currently banned along with when
+// their bans expire.
+//
+// This function is safe for concurrent access and is part of the
+// rpcserver.ConnManager interface implementation.
+func (cm *rpcConnManager) BannedPeers() map[string]time.Time {
+	replyChan := make(chan map[string]time.Time)
+	cm.server.query <- getBannedMsg{reply: replyChan}
+	return <-replyChan
+}
+
 // ConnectedPeers returns an array consisting of all connected peers.
 //
 // This function is safe for concurrent access and is part of the
@@ -365,9 +400,31 @@ func (b *rpcSyncMgr) SyncHeight() int64 {
 // ProcessTransaction relays the provided transaction validation and insertion
 // into the memory pool.
 func (b *rpcSyncMgr) ProcessTransaction(tx *dcrutil.Tx, allowOrphans bool,
-	rateLimit bool, allowHighFees bool, tag mempool.Tag) ([]*dcrutil.Tx, error) {
+	rateLimit bool, allowHighFees bool, feeExempt bool,
+	tag mempool.Tag) ([]*dcrutil.Tx, error) {
+
 	return b.blockMgr.ProcessTransaction(tx, allowOrphans,
-		rateLimit, allowHighFees, tag)
+		rateLimit, allowHighFees, feeExempt, tag)
+}
+
+// TxRelayStats returns statistics about the latency of the transactions
+// processed via the priority lane used by votes and revocations and the
+// regular lane used by all other transactions.
+func (b *rpcSyncMgr) TxRelayStats() (priority, regular rpcserver.TxRelayLaneStats) {
+	return b.blockMgr.TxRelayStats()
+}
+
+// RejectedTransactions returns the transactions most recently rejected from
+// peers along with the reasons they were rejected ordered from newest to
+// oldest.
+func (b *rpcSyncMgr) RejectedTransactions() []rpcserver.RejectedTx {
+	return b.blockMgr.RejectedTransactions()
+}
+
+// SyncPeerInfo returns details about the current sync peer along with the most
+// recent changes to it.
+func (b *rpcSyncMgr) SyncPeerInfo() *rpcserver.SyncPeerInfo {
+	return b.blockMgr.SyncPeerInfo()
 }
 
 // rpcUtxoEntry represents a utxo entry for use with the RPC server and
@@ -519,6 +576,17 @@ func (c *rpcCPUMiner) GenerateNBlocks(ctx context.Context, n uint32) ([]*chainha
 	return c.miner.GenerateNBlocks(ctx, n)
 }
 
+// GenerateNBlocksToAddress generates the requested number of blocks that pay to
+// the provided address.
+func (c *rpcCPUMiner) GenerateNBlocksToAddress(ctx context.Context, n uint32, payToAddr dcrutil.Address) ([]*chainhash.Hash, error) {
+	if c.miner == nil {
+		return nil, errors.New("Block generation is disallowed without a " +
+			"CPU miner.")
+	}
+
+	return c.miner.GenerateNBlocksToAddress(ctx, n, payToAddr)
+}
+
 // IsMining returns whether or not the CPU miner has been started and is
 // therefore currently mining.
 func (c *rpcCPUMiner) IsMining() bool {
@@ -0,0 +1,185 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"context"
+	"errors"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v2"
+)
+
+// ErrBlockRangeReorganized is returned by BlockRangeIterator.Next when the main
+// chain of the RPC server was reorganized while iterating such that a block no
+// longer connects to the block previously returned.
+var ErrBlockRangeReorganized = errors.New("main chain was reorganized " +
+	"while iterating the block range")
+
+// rangeBlock is a block request that has been issued by a block range iterator
+// but not yet returned to the caller.  The block itself is only requested once
+// its hash is known.
+type rangeBlock struct {
+	height      int64
+	hashFuture  *FutureGetBlockHashResult
+	blockFuture *FutureGetBlockVerboseResult
+}
+
+// BlockRangeIterator returns the verbose blocks of a range of heights of the
+// main chain of the RPC server in order of ascending height.  The hash and block
+// requests for upcoming heights are pipelined with a bounded number of requests
+// in flight so that the round trip latency of the RPC server is largely hidden.
+//
+// Unlike ChainIterator, a BlockRangeIterator does not roll back blocks when the
+// main chain is reorganized and instead fails with ErrBlockRangeReorganized.
+//
+// A BlockRangeIterator is not safe for concurrent access.
+type BlockRangeIterator struct {
+	c          *Client
+	ctx        context.Context
+	nextHeight int64
+	endHeight  int64
+	prevHash   string
+	pending    []rangeBlock
+}
+
+// BlockRange returns a new BlockRangeIterator which returns the verbose blocks,
+// including the details of their transactions, of the main chain from
+// startHeight to endHeight inclusive.  The passed context is used for all of
+// the requests made by the iterator.  No requests are made until the first call
+// to Next.
+func (c *Client) BlockRange(ctx context.Context, startHeight, endHeight int64) *BlockRangeIterator {
+	return &BlockRangeIterator{
+		c:          c,
+		ctx:        ctx,
+		nextHeight: startHeight,
+		endHeight:  endHeight,
+	}
+}
+
+// Next returns the block at the next height of the range.  ErrChainIteratorEnd
+// is returned once all blocks of the range have been returned.
+func (it *BlockRangeIterator) Next() (*chainjson.GetBlockVerboseResult, error) {
+	// Keep the pipeline full by requesting the hashes of upcoming heights
+	// and then the blocks for all of the hashes that are known.
+	for len(it.pending) < defaultIterPrefetch && it.nextHeight <= it.endHeight {
+		it.pending = append(it.pending, rangeBlock{
+			height:     it.nextHeight,
+			hashFuture: it.c.GetBlockHashAsync(it.ctx, it.nextHeight),
+		})
+		it.nextHeight++
+	}
+	if len(it.pending) == 0 {
+		return nil, ErrChainIteratorEnd
+	}
+	for i := range it.pending {
+		rb := &it.pending[i]
+		if rb.blockFuture != nil {
+			continue
+		}
+		hash, err := rb.hashFuture.Receive()
+		if err != nil {
+			it.pending = nil
+			return nil, err
+		}
+		rb.blockFuture = it.c.GetBlockVerboseAsync(it.ctx, hash, true)
+	}
+
+	rb := it.pending[0]
+	it.pending = it.pending[1:]
+	block, err := rb.blockFuture.Receive()
+	if err != nil {
+		it.pending = nil
+		return nil, err
+	}
+
+	// Ensure the block connects to the block previously returned since the
+	// hashes of the heights are no longer consistent otherwise.
+	if it.prevHash != "" && block.PreviousHash != it.prevHash {
+		it.pending = nil
+		return nil, ErrBlockRangeReorganized
+	}
+	it.prevHash = block.Hash
+	return block, nil
+}
+
+// AncestorHeaders returns the verbose headers of up to n ancestors of the block
+// with the passed hash in order of descending height starting with its parent.
+// Fewer headers are returned when the genesis block is reached.
+//
+// The headers of ancestors that are part of the main chain are requested by
+// height with a bounded number of requests in flight.  Ancestors that are not
+// part of the main chain, such as those of a block on a side chain, are instead
+// requested one at a time by following the previous block hash of each header.
+func (c *Client) AncestorHeaders(ctx context.Context, hash *chainhash.Hash, n int) ([]*chainjson.GetBlockHeaderVerboseResult, error) {
+	header, err := c.GetBlockHeaderVerbose(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := make([]*chainjson.GetBlockHeaderVerboseResult, 0, n)
+	for len(headers) < n && header.Height > 0 {
+		prevHash, err := chainhash.NewHashFromStr(header.PreviousHash)
+		if err != nil {
+			return nil, err
+		}
+
+		// Request the hashes of the next batch of ancestors by height.
+		height := int64(header.Height) - 1
+		batch := n - len(headers)
+		if batch > defaultIterPrefetch {
+			batch = defaultIterPrefetch
+		}
+		if int64(batch) > height+1 {
+			batch = int(height + 1)
+		}
+		hashFutures := make([]*FutureGetBlockHashResult, 0, batch)
+		for i := 0; i < batch; i++ {
+			hashFutures = append(hashFutures, c.GetBlockHashAsync(ctx,
+				height-int64(i)))
+		}
+		hashes := make([]*chainhash.Hash, len(hashFutures))
+		for i, f := range hashFutures {
+			hashes[i], err = f.Receive()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// Request the parent alone when it is not part of the main
+		// chain.
+		if *hashes[0] != *prevHash {
+			header, err = c.GetBlockHeaderVerbose(ctx, prevHash)
+			if err != nil {
+				return nil, err
+			}
+			headers = append(headers, header)
+			continue
+		}
+
+		headerFutures := make([]*FutureGetBlockHeaderVerboseResult, 0,
+			len(hashes))
+		for _, hash := range hashes {
+			headerFutures = append(headerFutures,
+				c.GetBlockHeaderVerboseAsync(ctx, hash))
+		}
+		for _, f := range headerFutures {
+			ancestor, err := f.Receive()
+			if err != nil {
+				return nil, err
+			}
+
+			// Discard the remainder of the batch when the main chain
+			// was reorganized in the meantime so the ancestors are
+			// requested again starting from the last known one.
+			if ancestor.Hash != header.PreviousHash {
+				break
+			}
+			headers = append(headers, ancestor)
+			header = ancestor
+		}
+	}
+	return headers, nil
+}
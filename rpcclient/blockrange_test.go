@@ -0,0 +1,246 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v2"
+	"github.com/decred/dcrd/wire"
+)
+
+// testChain houses the blocks served by a test chain server.  Blocks remain
+// known after they are removed from the main chain.
+type testChain struct {
+	mtx     sync.Mutex
+	main    []string
+	headers map[string]chainjson.GetBlockHeaderVerboseResult
+
+	// hashRequests is the number of getblockhash requests served.
+	hashRequests int
+}
+
+// extend adds blocks with hashes derived from the passed salt to the chain up to
+// the provided height, replacing the blocks of the main chain from the passed
+// height onwards.  The new blocks connect to the main chain block at the height
+// prior to the passed height.  When side is true, the main chain is not
+// modified.
+func (c *testChain) extend(fromHeight, toHeight int64, salt int64, side bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	var prevHash string
+	if fromHeight > 0 {
+		prevHash = c.main[fromHeight-1]
+	}
+	if !side {
+		c.main = c.main[:fromHeight]
+	}
+	for height := fromHeight; height <= toHeight; height++ {
+		hash := fmt.Sprintf("%064x", salt+height+1)
+		c.headers[hash] = chainjson.GetBlockHeaderVerboseResult{
+			Hash:         hash,
+			Height:       uint32(height),
+			PreviousHash: prevHash,
+		}
+		if !side {
+			c.main = append(c.main, hash)
+		}
+		prevHash = hash
+	}
+}
+
+// newTestChainClient returns a server that serves the block count, hashes,
+// blocks, and verbose headers of a test chain with a main chain of the provided
+// number of blocks along with a client that makes requests to it in HTTP POST
+// mode.
+func newTestChainClient(t *testing.T, numBlocks int64) (*httptest.Server, *Client, *testChain) {
+	chain := &testChain{
+		headers: make(map[string]chainjson.GetBlockHeaderVerboseResult),
+	}
+	chain.extend(0, numBlocks-1, 0, false)
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		chain.mtx.Lock()
+		defer chain.mtx.Unlock()
+		var result interface{}
+		switch req.Method {
+		case "getblockcount":
+			result = len(chain.main) - 1
+		case "getblockhash":
+			chain.hashRequests++
+			var height int64
+			json.Unmarshal(req.Params[0], &height)
+			if height >= int64(len(chain.main)) {
+				break
+			}
+			result = chain.main[height]
+		case "getblock", "getblockheader":
+			var hash string
+			json.Unmarshal(req.Params[0], &hash)
+			h, ok := chain.headers[hash]
+			if !ok {
+				break
+			}
+			result = &h
+			verbose := true
+			if len(req.Params) > 1 {
+				json.Unmarshal(req.Params[1], &verbose)
+			}
+			if req.Method == "getblock" && !verbose {
+				// Serialized blocks only need to commit to the
+				// previous block since the hash of a block is
+				// taken from the test chain.
+				var block wire.MsgBlock
+				block.Header.Height = h.Height
+				if h.PreviousHash != "" {
+					prev, _ := chainhash.NewHashFromStr(h.PreviousHash)
+					block.Header.PrevBlock = *prev
+				}
+				var buf bytes.Buffer
+				block.Serialize(&buf)
+				result = hex.EncodeToString(buf.Bytes())
+			} else if req.Method == "getblock" {
+				result = &chainjson.GetBlockVerboseResult{
+					Hash:         h.Hash,
+					Height:       int64(h.Height),
+					PreviousHash: h.PreviousHash,
+				}
+			}
+		}
+		if result == nil {
+			w.Write([]byte(`{"result":null,"error":{"code":-5,` +
+				`"message":"Block not found"},"id":1}`))
+			return
+		}
+		resultJSON, _ := json.Marshal(result)
+		fmt.Fprintf(w, `{"result":%s,"error":null,"id":1}`, resultJSON)
+	}
+	server, c := newTestHTTPClient(t, handler, &ConnConfig{MaxConns: 4})
+	return server, c, chain
+}
+
+// TestBlockRange ensures the block range iterator returns the blocks of the
+// requested range in order and detects reorganizations.
+func TestBlockRange(t *testing.T) {
+	server, c, chain := newTestChainClient(t, 50)
+	defer server.Close()
+	defer c.Shutdown()
+
+	ctx := context.Background()
+	mainChain := chain.main
+	it := c.BlockRange(ctx, 3, 40)
+	for height := int64(3); height <= 40; height++ {
+		block, err := it.Next()
+		if err != nil {
+			t.Fatalf("unexpected error at height %d: %v", height, err)
+		}
+		if block.Height != height || block.Hash != mainChain[height] {
+			t.Fatalf("unexpected block -- got %s (height %d), want %s "+
+				"(height %d)", block.Hash, block.Height,
+				mainChain[height], height)
+		}
+	}
+	if _, err := it.Next(); !errors.Is(err, ErrChainIteratorEnd) {
+		t.Fatalf("unexpected error at end of range: %v", err)
+	}
+
+	// Ensure a block that no longer connects to the previous block is
+	// detected as a reorganization once the main chain is reorganized
+	// after the hashes of the first heights were already requested.
+	it = c.BlockRange(ctx, 0, 40)
+	if _, err := it.Next(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	chain.extend(5, 49, 1000, false)
+	var err error
+	for err == nil {
+		_, err = it.Next()
+	}
+	if !errors.Is(err, ErrBlockRangeReorganized) {
+		t.Fatalf("unexpected error after reorganization: %v", err)
+	}
+}
+
+// TestAncestorHeaders ensures the ancestors of blocks on both the main chain
+// and side chains are returned in order.
+func TestAncestorHeaders(t *testing.T) {
+	// Create a side chain that forks from the main chain after height 30.
+	server, c, chain := newTestChainClient(t, 50)
+	defer server.Close()
+	defer c.Shutdown()
+	chain.extend(31, 33, 1000, true)
+	mainChain := chain.main
+
+	tests := []struct {
+		name string
+		tip  string
+		n    int
+		want []string
+	}{{
+		name: "main chain",
+		tip:  mainChain[45],
+		n:    40,
+		want: mainChain[5:45],
+	}, {
+		name: "reaches genesis",
+		tip:  mainChain[3],
+		n:    10,
+		want: mainChain[:3],
+	}, {
+		name: "genesis",
+		tip:  mainChain[0],
+		n:    10,
+	}, {
+		name: "side chain",
+		tip:  fmt.Sprintf("%064x", 1034),
+		n:    25,
+		want: append(mainChain[8:31:31], fmt.Sprintf("%064x", 1032),
+			fmt.Sprintf("%064x", 1033)),
+	}}
+
+	for _, test := range tests {
+		tip, err := chainhash.NewHashFromStr(test.tip)
+		if err != nil {
+			t.Fatalf("%q: unable to parse tip: %v", test.name, err)
+		}
+		headers, err := c.AncestorHeaders(context.Background(), tip, test.n)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", test.name, err)
+		}
+
+		// The expected ancestors are listed in ascending order.
+		if len(headers) != len(test.want) {
+			t.Fatalf("%q: unexpected number of headers -- got %d, want %d",
+				test.name, len(headers), len(test.want))
+		}
+		for i, header := range headers {
+			want := test.want[len(test.want)-1-i]
+			if header.Hash != want {
+				t.Fatalf("%q: unexpected header %d -- got %s, want %s",
+					test.name, i, header.Hash, want)
+			}
+		}
+	}
+}
@@ -233,6 +233,45 @@ func (c *Client) GetDifficulty(ctx context.Context) (float64, error) {
 	return c.GetDifficultyAsync(ctx).Receive()
 }
 
+// FutureGetDiskSpaceInfoResult is a future promise to deliver the result of a
+// GetDiskSpaceInfoAsync RPC invocation (or an applicable error).
+type FutureGetDiskSpaceInfoResult cmdRes
+
+// Receive waits for the response promised by the future and returns the free
+// disk space available to the data directory of the server along with the
+// configured thresholds.
+func (r *FutureGetDiskSpaceInfoResult) Receive() (*chainjson.GetDiskSpaceInfoResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getdiskspaceinfo result object.
+	var diskSpaceInfo chainjson.GetDiskSpaceInfoResult
+	err = json.Unmarshal(res, &diskSpaceInfo)
+	if err != nil {
+		return nil, err
+	}
+	return &diskSpaceInfo, nil
+}
+
+// GetDiskSpaceInfoAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetDiskSpaceInfo for the blocking version and more details.
+func (c *Client) GetDiskSpaceInfoAsync(ctx context.Context) *FutureGetDiskSpaceInfoResult {
+	cmd := chainjson.NewGetDiskSpaceInfoCmd()
+	return (*FutureGetDiskSpaceInfoResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetDiskSpaceInfo returns the free disk space available to the data directory
+// of the server along with the thresholds below which it issues warnings and
+// shuts down gracefully.
+func (c *Client) GetDiskSpaceInfo(ctx context.Context) (*chainjson.GetDiskSpaceInfoResult, error) {
+	return c.GetDiskSpaceInfoAsync(ctx).Receive()
+}
+
 // FutureGetBlockChainInfoResult is a future promise to deliver the result of a
 // GetBlockChainInfoAsync RPC invocation (or an applicable error).
 type FutureGetBlockChainInfoResult cmdRes
@@ -434,6 +473,52 @@ func (c *Client) GetBlockHeaderVerbose(ctx context.Context, hash *chainhash.Hash
 	return c.GetBlockHeaderVerboseAsync(ctx, hash).Receive()
 }
 
+// FutureGetBlockStatsResult is a future promise to deliver the result of a
+// GetBlockStatsAsync RPC invocation (or an applicable error).
+type FutureGetBlockStatsResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// statistics of the requested block.
+func (r *FutureGetBlockStatsResult) Receive() (*chainjson.GetBlockStatsResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the result
+	var stats chainjson.GetBlockStatsResult
+	err = json.Unmarshal(res, &stats)
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// GetBlockStatsAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See GetBlockStats for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetBlockStatsAsync(ctx context.Context, blockHash *chainhash.Hash) *FutureGetBlockStatsResult {
+	hash := ""
+	if blockHash != nil {
+		hash = blockHash.String()
+	}
+
+	cmd := chainjson.NewGetBlockStatsCmd(hash)
+	return (*FutureGetBlockStatsResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetBlockStats returns statistics about the block with the given hash,
+// including the local time the server received it.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetBlockStats(ctx context.Context, blockHash *chainhash.Hash) (*chainjson.GetBlockStatsResult, error) {
+	return c.GetBlockStatsAsync(ctx, blockHash).Receive()
+}
+
 // FutureGetBlockSubsidyResult is a future promise to deliver the result of a
 // GetBlockSubsidyAsync RPC invocation (or an applicable error).
 type FutureGetBlockSubsidyResult cmdRes
@@ -603,6 +688,46 @@ func (c *Client) GetRawMempoolVerbose(ctx context.Context, txType chainjson.GetR
 	return c.GetRawMempoolVerboseAsync(ctx, txType).Receive()
 }
 
+// FutureGetRejectedTransactionsResult is a future promise to deliver the result
+// of a GetRejectedTransactionsAsync RPC invocation (or an applicable error).
+type FutureGetRejectedTransactionsResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// transactions most recently rejected from peers along with the reasons they
+// were rejected.
+func (r *FutureGetRejectedTransactionsResult) Receive() ([]chainjson.GetRejectedTransactionsResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the result as an array of rejected transaction objects.
+	var rejected []chainjson.GetRejectedTransactionsResult
+	err = json.Unmarshal(res, &rejected)
+	if err != nil {
+		return nil, err
+	}
+
+	return rejected, nil
+}
+
+// GetRejectedTransactionsAsync returns an instance of a type that can be used
+// to get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See GetRejectedTransactions for the blocking version and more details.
+func (c *Client) GetRejectedTransactionsAsync(ctx context.Context, count int32) *FutureGetRejectedTransactionsResult {
+	cmd := chainjson.NewGetRejectedTransactionsCmd(&count)
+	return (*FutureGetRejectedTransactionsResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetRejectedTransactions returns up to the provided number of transactions
+// most recently rejected from peers along with the reasons they were rejected
+// ordered from newest to oldest.
+func (c *Client) GetRejectedTransactions(ctx context.Context, count int32) ([]chainjson.GetRejectedTransactionsResult, error) {
+	return c.GetRejectedTransactionsAsync(ctx, count).Receive()
+}
+
 // FutureValidateAddressResult is a future promise to deliver the result of a
 // ValidateAddressAsync RPC invocation (or an applicable error).
 type FutureValidateAddressResult cmdRes
@@ -670,7 +795,7 @@ func (r *FutureVerifyMessageResult) Receive() (bool, error) {
 // See VerifyMessage for the blocking version and more details.
 func (c *Client) VerifyMessageAsync(ctx context.Context, address dcrutil.Address, signature, message string) *FutureVerifyMessageResult {
 	addr := address.Address()
-	cmd := chainjson.NewVerifyMessageCmd(addr, signature, message)
+	cmd := chainjson.NewVerifyMessageCmd(addr, signature, message, nil, nil)
 	return (*FutureVerifyMessageResult)(c.sendCmd(ctx, cmd))
 }
 
@@ -682,6 +807,56 @@ func (c *Client) VerifyMessage(ctx context.Context, address dcrutil.Address, sig
 	return c.VerifyMessageAsync(ctx, address, signature, message).Receive()
 }
 
+// FutureVerifyMessageVerboseResult is a future promise to deliver the result of
+// a VerifyMessageVerboseAsync RPC invocation (or an applicable error).
+type FutureVerifyMessageVerboseResult cmdRes
+
+// Receive waits for the response promised by the future and returns a data
+// structure describing the outcome of verifying the message.
+func (r *FutureVerifyMessageVerboseResult) Receive() (*chainjson.VerifyMessageResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a verifymessage result object.
+	var result chainjson.VerifyMessageResult
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// VerifyMessageVerboseAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See VerifyMessageVerbose for the blocking version and more details.
+func (c *Client) VerifyMessageVerboseAsync(ctx context.Context, address dcrutil.Address, signature, message string, redeemScript []byte) *FutureVerifyMessageVerboseResult {
+	addr := address.Address()
+	var script *string
+	if redeemScript != nil {
+		scriptHex := hex.EncodeToString(redeemScript)
+		script = &scriptHex
+	}
+	cmd := chainjson.NewVerifyMessageCmd(addr, signature, message, script,
+		dcrjson.Bool(true))
+	return (*FutureVerifyMessageVerboseResult)(c.sendCmd(ctx, cmd))
+}
+
+// VerifyMessageVerbose verifies a signed message and returns a data structure
+// describing the outcome, including the type of signature and the public keys
+// of the signers.  The redeem script must be provided for pay-to-script-hash
+// addresses and is ignored otherwise.
+//
+// See VerifyMessage to only retrieve whether or not the message verified.
+func (c *Client) VerifyMessageVerbose(ctx context.Context, address dcrutil.Address, signature, message string, redeemScript []byte) (*chainjson.VerifyMessageResult, error) {
+	return c.VerifyMessageVerboseAsync(ctx, address, signature, message,
+		redeemScript).Receive()
+}
+
 // FutureVerifyChainResult is a future promise to deliver the result of a
 // VerifyChainAsync, VerifyChainLevelAsyncRPC, or VerifyChainBlocksAsync
 // invocation (or an applicable error).
@@ -705,6 +880,48 @@ func (r *FutureVerifyChainResult) Receive() (bool, error) {
 	return verified, nil
 }
 
+// FutureGetChainParamsResult is a future promise to deliver the result of a
+// GetChainParamsAsync RPC invocation (or an applicable error).
+type FutureGetChainParamsResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// consensus parameters of the network the server is running on.
+func (r *FutureGetChainParamsResult) Receive() (*chainjson.GetChainParamsResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the result as a getchainparams result object.
+	var params chainjson.GetChainParamsResult
+	err = json.Unmarshal(res, &params)
+	if err != nil {
+		return nil, err
+	}
+	return &params, nil
+}
+
+// GetChainParamsAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetChainParams for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetChainParamsAsync(ctx context.Context) *FutureGetChainParamsResult {
+	cmd := chainjson.NewGetChainParamsCmd()
+	return (*FutureGetChainParamsResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetChainParams returns the consensus parameters of the network the server is
+// running on, such as the block time targets, subsidy and stake parameters,
+// address prefixes, and consensus deployment schedule.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetChainParams(ctx context.Context) (*chainjson.GetChainParamsResult, error) {
+	return c.GetChainParamsAsync(ctx).Receive()
+}
+
 // FutureGetChainTipsResult is a future promise to deliver the result of a
 // GetChainTipsAsync RPC invocation (or an applicable error).
 type FutureGetChainTipsResult cmdRes
@@ -895,6 +1112,10 @@ func (c *Client) RescanAsync(ctx context.Context, blockHashes []chainhash.Hash)
 // Rescan rescans the blocks identified by blockHashes, in order, using the
 // client's loaded transaction filter.  The blocks do not need to be on the main
 // chain, but they do need to be adjacent to each other.
+//
+// Note that the notifications for the rescanned blocks are delivered to the
+// notification handlers asynchronously, so this may return before all of them
+// have been delivered.
 func (c *Client) Rescan(ctx context.Context, blockHashes []chainhash.Hash) (*chainjson.RescanResult, error) {
 	return c.RescanAsync(ctx, blockHashes).Receive()
 }
@@ -1115,3 +1336,32 @@ func (c *Client) EstimateSmartFeeAsync(ctx context.Context, confirmations int64,
 func (c *Client) EstimateSmartFee(ctx context.Context, confirmations int64, mode chainjson.EstimateSmartFeeMode) (float64, error) {
 	return c.EstimateSmartFeeAsync(ctx, confirmations, mode).Receive()
 }
+
+// FutureRollbackChainResult is a future promise to deliver the result of a
+// RollbackChainAsync RPC invocation (or an applicable error).
+type FutureRollbackChainResult cmdRes
+
+// Receive waits for the response and returns an error if any has occurred.
+func (r *FutureRollbackChainResult) Receive() error {
+	_, err := receiveFuture(r.ctx, r.c)
+	return err
+}
+
+// RollbackChainAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See RollbackChain for the blocking version and more details.
+func (c *Client) RollbackChainAsync(ctx context.Context, height int64) *FutureRollbackChainResult {
+	cmd := chainjson.NewRollbackChainCmd(height)
+	return (*FutureRollbackChainResult)(c.sendCmd(ctx, cmd))
+}
+
+// RollbackChain asks the node to disconnect all blocks of the main chain after
+// the provided height.  The disconnected blocks are kept as a side chain, so
+// tests can exercise reorganizations by extending either chain afterwards.
+//
+// NOTE: This is a dcrd extension that is only available on simnet and regnet.
+func (c *Client) RollbackChain(ctx context.Context, height int64) error {
+	return c.RollbackChainAsync(ctx, height).Receive()
+}
@@ -0,0 +1,365 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"context"
+	"errors"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrjson/v3"
+	"github.com/decred/dcrd/wire"
+)
+
+const (
+	// defaultIterPrefetch is the default number of blocks a chain iterator
+	// requests ahead of the block most recently returned to the caller.
+	defaultIterPrefetch = 16
+
+	// defaultIterMaxRollback is the default maximum number of previously
+	// returned blocks a forward chain iterator remembers in order to roll
+	// them back when a reorganization is detected.
+	defaultIterMaxRollback = 256
+)
+
+var (
+	// ErrChainIteratorEnd is returned by ChainIterator.Next when there are
+	// no more blocks to iterate.  For a forward iterator this means the
+	// current best chain tip has been reached and Next may be called again
+	// once new blocks are connected.  For a reverse iterator it means the
+	// genesis block has already been returned.
+	ErrChainIteratorEnd = errors.New("no more blocks to iterate")
+
+	// ErrReorgTooDeep is returned by ChainIterator.Next when a chain
+	// reorganization is detected that disconnects more blocks than the
+	// iterator has retained for rollback.
+	ErrReorgTooDeep = errors.New("chain reorganization is deeper than " +
+		"the maximum rollback depth of the iterator")
+)
+
+// ChainEventType describes the kind of event returned by a ChainIterator.
+type ChainEventType int
+
+const (
+	// ChainEventBlock indicates the event carries the next block in the
+	// direction of iteration.
+	ChainEventBlock ChainEventType = iota
+
+	// ChainEventRollback indicates a block previously returned by the
+	// iterator is no longer part of the main chain and must be undone by
+	// the caller.  Rollback events are always delivered in order from the
+	// most recent block backwards.
+	ChainEventRollback
+)
+
+// String returns the ChainEventType as a human-readable name.
+func (t ChainEventType) String() string {
+	switch t {
+	case ChainEventBlock:
+		return "block"
+	case ChainEventRollback:
+		return "rollback"
+	}
+	return "unknown"
+}
+
+// ChainEvent is a single step returned by a ChainIterator.
+type ChainEvent struct {
+	// Type is the kind of event.
+	Type ChainEventType
+
+	// Height and Hash identify the block the event applies to.
+	Height int64
+	Hash   chainhash.Hash
+
+	// Block is the full block for ChainEventBlock events.  It is nil for
+	// ChainEventRollback events.
+	Block *wire.MsgBlock
+}
+
+// ChainIteratorConfig houses the parameters used to create a ChainIterator.
+type ChainIteratorConfig struct {
+	// StartHeight is the height of the first block returned by the
+	// iterator.  A negative value for a reverse iterator starts at the
+	// current best chain tip.
+	StartHeight int64
+
+	// Reverse specifies that the iterator walks towards the genesis block
+	// instead of towards the chain tip.
+	Reverse bool
+
+	// Prefetch is the number of blocks requested ahead of the block most
+	// recently returned.  Zero selects a sensible default.
+	Prefetch int
+
+	// MaxRollback is the number of previously returned blocks a forward
+	// iterator retains in order to emit rollback events on a chain
+	// reorganization.  Zero selects a sensible default.  It has no effect
+	// on reverse iterators.
+	MaxRollback int
+}
+
+// iterBlock is a block request that has been issued by a chain iterator but
+// not yet returned to the caller.
+type iterBlock struct {
+	height int64
+	hash   *chainhash.Hash
+	future *FutureGetBlockResult
+}
+
+// ChainIterator walks the blocks of the main chain of the RPC server in either
+// direction.  Blocks are requested ahead of the caller in batches, where all
+// hashes of a batch are resolved before any of the blocks are requested, so
+// that the round trip latency of the RPC server is largely hidden.
+//
+// Forward iterators additionally detect chain reorganizations by verifying
+// each block connects to the one previously returned.  When a block does not
+// connect, the iterator returns rollback events for each previously returned
+// block that is no longer part of the main chain before resuming with the
+// blocks of the new main chain.
+//
+// A ChainIterator is not safe for concurrent access.
+type ChainIterator struct {
+	c           *Client
+	reverse     bool
+	prefetch    int
+	maxRollback int
+	started     bool
+
+	// nextHeight is the height of the next block to return.
+	nextHeight int64
+
+	// history holds the hashes of the most recently returned blocks in
+	// order of increasing height for forward iterators.  The final entry
+	// is the hash of the block at nextHeight-1.
+	history []chainhash.Hash
+
+	// historyParent is the hash of the parent of the first block in the
+	// history.  It is the hash the next block must connect to once all
+	// blocks in the history have been rolled back, at which point the
+	// iterator is no longer able to roll back any further.
+	historyParent *chainhash.Hash
+
+	// expectedHash is the hash the next block returned by a reverse
+	// iterator must have.  It is nil until the first block is returned.
+	expectedHash *chainhash.Hash
+
+	// pending holds the prefetched blocks in the order they will be
+	// returned.
+	pending []iterBlock
+}
+
+// NewChainIterator returns a new ChainIterator which walks the main chain of
+// the RPC server using the passed configuration.  No requests are made until
+// the first call to Next.
+func (c *Client) NewChainIterator(cfg *ChainIteratorConfig) *ChainIterator {
+	prefetch := cfg.Prefetch
+	if prefetch <= 0 {
+		prefetch = defaultIterPrefetch
+	}
+	maxRollback := cfg.MaxRollback
+	if maxRollback <= 0 {
+		maxRollback = defaultIterMaxRollback
+	}
+	return &ChainIterator{
+		c:           c,
+		reverse:     cfg.Reverse,
+		prefetch:    prefetch,
+		maxRollback: maxRollback,
+		nextHeight:  cfg.StartHeight,
+	}
+}
+
+// Height returns the height of the next block the iterator will attempt to
+// return.
+func (it *ChainIterator) Height() int64 {
+	return it.nextHeight
+}
+
+// fill issues requests for the next batch of blocks.  The hashes for all
+// heights in the batch are requested concurrently and only once all of them
+// are known are the blocks themselves requested.
+func (it *ChainIterator) fill(ctx context.Context) error {
+	bestHeight, err := it.c.GetBlockCount(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Determine the heights of the batch.
+	first, last := it.nextHeight, it.nextHeight+int64(it.prefetch)-1
+	if it.reverse {
+		if (!it.started && it.nextHeight < 0) || it.nextHeight > bestHeight {
+			it.nextHeight = bestHeight
+		}
+		if it.nextHeight < 0 {
+			return ErrChainIteratorEnd
+		}
+		first, last = it.nextHeight-int64(it.prefetch)+1, it.nextHeight
+		if first < 0 {
+			first = 0
+		}
+	} else {
+		if last > bestHeight {
+			last = bestHeight
+		}
+		if first > last {
+			return ErrChainIteratorEnd
+		}
+	}
+	it.started = true
+
+	// Resolve all hashes of the batch at once.
+	hashFutures := make([]*FutureGetBlockHashResult, 0, last-first+1)
+	for height := first; height <= last; height++ {
+		hashFutures = append(hashFutures, it.c.GetBlockHashAsync(ctx, height))
+	}
+	hashes := make([]*chainhash.Hash, len(hashFutures))
+	for i, f := range hashFutures {
+		hashes[i], err = f.Receive()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Request the blocks in the order they will be returned.
+	it.pending = it.pending[:0]
+	for i := range hashes {
+		idx := i
+		if it.reverse {
+			idx = len(hashes) - 1 - i
+		}
+		hash := hashes[idx]
+		it.pending = append(it.pending, iterBlock{
+			height: first + int64(idx),
+			hash:   hash,
+			future: it.c.GetBlockAsync(ctx, hash),
+		})
+	}
+	return nil
+}
+
+// rollback removes the most recently returned block from the history of a
+// forward iterator and returns a rollback event for it.  Any prefetched blocks
+// are discarded since they were requested against the old chain.
+func (it *ChainIterator) rollback() (*ChainEvent, error) {
+	it.pending = it.pending[:0]
+	if len(it.history) == 0 {
+		return nil, ErrReorgTooDeep
+	}
+	hash := it.history[len(it.history)-1]
+	it.history = it.history[:len(it.history)-1]
+	it.nextHeight--
+	log.Debugf("Chain iterator rolling back block %v (height %d)", hash,
+		it.nextHeight)
+	return &ChainEvent{
+		Type:   ChainEventRollback,
+		Height: it.nextHeight,
+		Hash:   hash,
+	}, nil
+}
+
+// tipHash returns the hash the next block returned by a forward iterator must
+// connect to or nil when no blocks have been returned yet.
+func (it *ChainIterator) tipHash() *chainhash.Hash {
+	if len(it.history) > 0 {
+		return &it.history[len(it.history)-1]
+	}
+	return it.historyParent
+}
+
+// tipRemoved returns whether the block most recently returned by a forward
+// iterator is no longer part of the main chain.  It is used to detect
+// reorganizations to a chain that is not longer than the chain the iterator
+// previously walked.
+func (it *ChainIterator) tipRemoved(ctx context.Context) (bool, error) {
+	tip := it.tipHash()
+	if tip == nil {
+		return false, nil
+	}
+	hash, err := it.c.GetBlockHash(ctx, it.nextHeight-1)
+	if err != nil {
+		// The height no longer exists in the main chain.
+		var rpcErr *dcrjson.RPCError
+		if errors.As(err, &rpcErr) {
+			return true, nil
+		}
+		return false, err
+	}
+	return *hash != *tip, nil
+}
+
+// Next returns the next event from the iterator.
+//
+// ErrChainIteratorEnd is returned when there are no more blocks in the
+// direction of iteration.  Forward iterators may continue to be used after
+// that error is returned in order to wait for new blocks, for example after
+// receiving a block connected notification.
+func (it *ChainIterator) Next(ctx context.Context) (*ChainEvent, error) {
+	if len(it.pending) == 0 {
+		err := it.fill(ctx)
+		if errors.Is(err, ErrChainIteratorEnd) && !it.reverse {
+			// Reaching the tip might also be the result of the
+			// main chain being replaced by one with less blocks.
+			removed, rerr := it.tipRemoved(ctx)
+			if rerr != nil {
+				return nil, rerr
+			}
+			if removed {
+				return it.rollback()
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	next := it.pending[0]
+	block, err := next.future.Receive()
+	if err != nil {
+		return nil, err
+	}
+	it.pending = it.pending[1:]
+
+	// Ensure the block connects to the previously returned block.
+	if it.reverse {
+		if it.expectedHash != nil && *next.hash != *it.expectedHash {
+			// The main chain was reorganized since the hashes of
+			// the batch were resolved.  Continue walking the
+			// chain of the previously returned block directly by
+			// hash.
+			it.pending = it.pending[:0]
+			next.hash = it.expectedHash
+			block, err = it.c.GetBlock(ctx, next.hash)
+			if err != nil {
+				return nil, err
+			}
+		}
+		prev := block.Header.PrevBlock
+		it.expectedHash = &prev
+		it.nextHeight = next.height - 1
+	} else {
+		tip := it.tipHash()
+		if tip != nil && block.Header.PrevBlock != *tip {
+			return it.rollback()
+		}
+		if len(it.history) == 0 {
+			prev := block.Header.PrevBlock
+			it.historyParent = &prev
+		}
+		it.history = append(it.history, *next.hash)
+		if len(it.history) > it.maxRollback {
+			it.historyParent = &it.history[0]
+			it.history = it.history[1:]
+		}
+		it.nextHeight = next.height + 1
+	}
+
+	return &ChainEvent{
+		Type:   ChainEventBlock,
+		Height: next.height,
+		Hash:   *next.hash,
+		Block:  block,
+	}, nil
+}
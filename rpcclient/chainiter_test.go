@@ -0,0 +1,206 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// checkChainEvent ensures the next event returned by the passed chain iterator
+// is of the provided type and identifies the block with the provided height and
+// hash.
+func checkChainEvent(t *testing.T, it *ChainIterator, wantType ChainEventType, wantHeight int64, wantHash string) {
+	t.Helper()
+
+	event, err := it.Next(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error waiting for %v event at height %d: %v",
+			wantType, wantHeight, err)
+	}
+	if event.Type != wantType || event.Height != wantHeight ||
+		event.Hash.String() != wantHash {
+		t.Fatalf("unexpected event -- got %v %s (height %d), want %v %s "+
+			"(height %d)", event.Type, event.Hash, event.Height, wantType,
+			wantHash, wantHeight)
+	}
+	if (event.Block != nil) != (wantType == ChainEventBlock) {
+		t.Fatalf("unexpected block for %v event at height %d", event.Type,
+			event.Height)
+	}
+	if event.Block != nil && event.Block.Header.Height != uint32(wantHeight) {
+		t.Fatalf("unexpected block height -- got %d, want %d",
+			event.Block.Header.Height, wantHeight)
+	}
+}
+
+// checkChainIterEnd ensures the passed chain iterator reports there are no more
+// blocks to iterate.
+func checkChainIterEnd(t *testing.T, it *ChainIterator) {
+	t.Helper()
+
+	_, err := it.Next(context.Background())
+	if !errors.Is(err, ErrChainIteratorEnd) {
+		t.Fatalf("unexpected error -- got %v, want %v", err,
+			ErrChainIteratorEnd)
+	}
+}
+
+// TestChainIteratorForward ensures a forward chain iterator returns the blocks
+// of the main chain in order and continues with new blocks once they are
+// added to the main chain.
+func TestChainIteratorForward(t *testing.T) {
+	server, c, chain := newTestChainClient(t, 10)
+	defer server.Close()
+	defer c.Shutdown()
+
+	it := c.NewChainIterator(&ChainIteratorConfig{StartHeight: 2, Prefetch: 3})
+	for height := int64(2); height < 10; height++ {
+		checkChainEvent(t, it, ChainEventBlock, height, chain.main[height])
+	}
+	checkChainIterEnd(t, it)
+
+	chain.extend(10, 12, 0, false)
+	for height := int64(10); height <= 12; height++ {
+		checkChainEvent(t, it, ChainEventBlock, height, chain.main[height])
+	}
+	checkChainIterEnd(t, it)
+	if it.Height() != 13 {
+		t.Fatalf("unexpected iterator height -- got %d, want %d",
+			it.Height(), 13)
+	}
+}
+
+// TestChainIteratorReverse ensures a reverse chain iterator returns the blocks
+// of the main chain from the requested height back to the genesis block.
+func TestChainIteratorReverse(t *testing.T) {
+	server, c, chain := newTestChainClient(t, 10)
+	defer server.Close()
+	defer c.Shutdown()
+
+	tests := []struct {
+		name        string
+		startHeight int64
+		wantFirst   int64
+	}{
+		{"from tip", -1, 9},
+		{"from height", 5, 5},
+		{"beyond tip", 20, 9},
+	}
+	for _, test := range tests {
+		it := c.NewChainIterator(&ChainIteratorConfig{
+			StartHeight: test.startHeight,
+			Reverse:     true,
+			Prefetch:    4,
+		})
+		for height := test.wantFirst; height >= 0; height-- {
+			checkChainEvent(t, it, ChainEventBlock, height,
+				chain.main[height])
+		}
+		checkChainIterEnd(t, it)
+	}
+}
+
+// TestChainIteratorPrefetch ensures a chain iterator requests blocks in batches
+// of the configured prefetch size.
+func TestChainIteratorPrefetch(t *testing.T) {
+	server, c, chain := newTestChainClient(t, 10)
+	defer server.Close()
+	defer c.Shutdown()
+
+	hashRequests := func() int {
+		chain.mtx.Lock()
+		defer chain.mtx.Unlock()
+		return chain.hashRequests
+	}
+
+	// The final batch is limited by the height of the main chain.
+	it := c.NewChainIterator(&ChainIteratorConfig{Prefetch: 4})
+	wantRequests := []int{4, 4, 4, 4, 8, 8, 8, 8, 10, 10}
+	for height := int64(0); height < 10; height++ {
+		checkChainEvent(t, it, ChainEventBlock, height, chain.main[height])
+		if got := hashRequests(); got != wantRequests[height] {
+			t.Fatalf("unexpected hash requests at height %d -- got %d, "+
+				"want %d", height, got, wantRequests[height])
+		}
+	}
+}
+
+// TestChainIteratorRollback ensures a forward chain iterator returns rollback
+// events for all blocks removed from the main chain by a reorganization to
+// both longer and shorter chains before resuming with the new main chain.
+func TestChainIteratorRollback(t *testing.T) {
+	server, c, chain := newTestChainClient(t, 10)
+	defer server.Close()
+	defer c.Shutdown()
+
+	// Reorganize to a longer chain that forks after height 6.  The
+	// rollback limit is exactly the depth of the reorganization.
+	it := c.NewChainIterator(&ChainIteratorConfig{Prefetch: 4, MaxRollback: 3})
+	for height := int64(0); height < 10; height++ {
+		checkChainEvent(t, it, ChainEventBlock, height, chain.main[height])
+	}
+	oldChain := append([]string(nil), chain.main...)
+	chain.extend(7, 11, 1000, false)
+	for height := int64(9); height >= 7; height-- {
+		checkChainEvent(t, it, ChainEventRollback, height, oldChain[height])
+	}
+	for height := int64(7); height <= 11; height++ {
+		checkChainEvent(t, it, ChainEventBlock, height, chain.main[height])
+	}
+	checkChainIterEnd(t, it)
+
+	// Reorganize to a shorter chain that forks after height 8.
+	oldChain = append([]string(nil), chain.main...)
+	chain.extend(9, 10, 2000, false)
+	for height := int64(11); height >= 9; height-- {
+		checkChainEvent(t, it, ChainEventRollback, height, oldChain[height])
+	}
+	for height := int64(9); height <= 10; height++ {
+		checkChainEvent(t, it, ChainEventBlock, height, chain.main[height])
+	}
+	checkChainIterEnd(t, it)
+}
+
+// TestChainIteratorReorgTooDeep ensures a forward chain iterator refuses to
+// continue once a reorganization removes more blocks than it is able to roll
+// back, both when the new chain is longer and when it is not.
+func TestChainIteratorReorgTooDeep(t *testing.T) {
+	tests := []struct {
+		name     string
+		toHeight int64
+	}{
+		{"longer chain", 12},
+		{"shorter chain", 8},
+	}
+	for _, test := range tests {
+		server, c, chain := newTestChainClient(t, 10)
+
+		it := c.NewChainIterator(&ChainIteratorConfig{MaxRollback: 2})
+		for height := int64(0); height < 10; height++ {
+			checkChainEvent(t, it, ChainEventBlock, height,
+				chain.main[height])
+		}
+
+		// Reorganize the chain after height 4, which requires rolling
+		// back more blocks than the iterator retains.  The retained
+		// blocks are rolled back before the error is returned.
+		oldChain := append([]string(nil), chain.main...)
+		chain.extend(5, test.toHeight, 1000, false)
+		for height := int64(9); height >= 8; height-- {
+			checkChainEvent(t, it, ChainEventRollback, height,
+				oldChain[height])
+		}
+		_, err := it.Next(context.Background())
+		if !errors.Is(err, ErrReorgTooDeep) {
+			t.Fatalf("%q: unexpected error -- got %v, want %v", test.name,
+				err, ErrReorgTooDeep)
+		}
+
+		c.Shutdown()
+		server.Close()
+	}
+}
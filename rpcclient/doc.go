@@ -23,12 +23,20 @@ dcrd or dcrwallet by default.  However, configuration options are provided to
 fall back to HTTP POST and disable TLS to support talking with inferior bitcoin
 core style RPC servers.
 
+Services that run on the same machine as the RPC server may instead connect over
+a unix domain socket by setting the SocketPath field of the connection config,
+which avoids the overhead of TLS and does not require the server to expose a TCP
+port.
+
 Websockets vs HTTP POST
 
 In HTTP POST-based JSON-RPC, every request creates a new HTTP connection,
 issues the call, waits for the response, and closes the connection.  This adds
 quite a bit of overhead to every call and lacks flexibility for features such as
-notifications.
+notifications.  Requests are also issued one at a time by default.  Setting the
+MaxConns field of the connection config to a value greater than one instead
+issues up to that many requests concurrently over a pool of connections that are
+kept alive for reuse, which is useful for high-throughput batch tooling.
 
 In contrast, the websocket-based JSON-RPC interface provided by dcrd and
 dcrwallet only uses a single connection that remains open and allows
@@ -72,15 +80,44 @@ Notifications are exposed by the client through the use of callback handlers
 which are setup via a NotificationHandlers instance that is specified by the
 caller when creating the client.
 
-It is important that these notification handlers complete quickly since they
-are intentionally in the main read loop and will block further reads until
-they complete.  This provides the caller with the flexibility to decide what to
-do when notifications are coming in faster than they are being handled.
-
-In particular this means issuing a blocking RPC call from a callback handler
-will cause a deadlock as more server responses won't be read until the callback
-returns, but the callback would be waiting for a response.   Thus, any
-additional RPCs must be issued an a completely decoupled manner.
+Notifications are delivered to the handlers, in the order they are received, by
+a goroutine that is separate from the main read loop.  It is still important
+that these notification handlers complete quickly since only a limited number of
+received notifications are queued, after which the main read loop will block
+further reads until the handlers catch up.  This provides the caller with the
+flexibility to decide what to do when notifications are coming in faster than
+they are being handled.
+
+In particular this means issuing many blocking RPC calls from a callback handler
+may cause a deadlock as more server responses won't be read once the queue is
+full, but the callback would be waiting for a response.  Thus, any additional
+RPCs should be issued in a completely decoupled manner.
+
+Since notifications are delivered by a separate goroutine, they are delivered in
+the order they were received relative to each other, but not relative to the
+responses to requests.  In other words, a request may complete before the
+notifications the server sent prior to its response have been delivered.  For
+example, Rescan may return before all of the notifications for the rescanned
+blocks have been delivered to the handlers, so callers that need to know when
+all of them have been processed should track that in the handlers themselves,
+such as by comparing the hash of the last notified block to the last rescanned
+block.
+
+When the client is shutdown, notifications that were already received continue
+to be delivered to the handlers until either all of them have been delivered or
+the NtfnDrainTimeout of the connection configuration elapses.
+
+Notification Channels
+
+As an alternative to the callback handlers, the NotifyBlocksChan,
+NotifyNewTransactionsChan, and NotifyWorkChannel functions register for the
+respective notifications and return typed channels, with a caller-specified
+buffer size, that the notifications are delivered to in the order they are
+received.  This allows the notifications to be consumed from select loops that
+are free to issue blocking RPC calls on the client.  The channels are closed
+once the context passed when subscribing is done or the client is shutdown.
+The client must still be created with a NotificationHandlers instance, although
+all of its handlers may be nil.
 
 Automatic Reconnection
 
@@ -88,24 +125,119 @@ By default, when running in websockets mode, this client will automatically
 keep trying to reconnect to the RPC server should the connection be lost.  There
 is a back-off in between each connection attempt until it reaches one try per
 minute.  Once a connection is re-established, all previously registered
-notifications are automatically re-registered and any in-flight commands are
-re-issued.  This means from the caller's perspective, the request simply takes
-longer to complete.
+notifications, including those registered via RawRequest, along with the
+transaction filter loaded via LoadTxFilter, are automatically re-registered and
+any in-flight commands are re-issued.  This means from the caller's perspective,
+the request simply takes longer to complete.  Since notifications are not
+delivered while the client is disconnected, the OnRegistrationReplayed
+notification handler is invoked once the registrations have been replayed so
+callers may resynchronize any state derived from them.
 
 The caller may invoke the Shutdown method on the client to force the client
 to cease reconnect attempts and return ErrClientShutdown for all outstanding
-commands.
+commands.  The WaitForShutdown method may then be used to wait, subject to the
+passed context, for the shutdown to complete.
 
 The automatic reconnection can be disabled by setting the DisableAutoReconnect
 flag to true in the connection config when creating the client.
 
+Automatic Failover
+
+Additional RPC servers may be specified via the FailoverHosts field of the
+connection config.  When the RPC server the client is using becomes
+unreachable, the client transparently fails over to the next server in turn.
+In websockets mode, this happens as part of the automatic reconnection process
+described above, so all previously registered notifications are re-registered
+with the new server and any in-flight commands are re-issued to it.  In HTTP
+POST mode, requests that fail to reach a server are re-issued to the next one.
+
+Request Timeouts
+
+Requests are bound to the context passed when making them, so a request that
+does not complete before the deadline of the context fails with
+ErrRequestTimeout, while one whose context is canceled fails with
+ErrRequestCanceled.  The RequestTimeout field of the connection config
+specifies a default timeout for requests made with contexts that do not have a
+deadline so that callers are not left waiting indefinitely on a stalled server.
+In websockets mode, requests that time out are no longer tracked and therefore
+are not re-issued when the client reconnects.
+
+Retries
+
+The RetryPolicy field of the connection config optionally configures requests
+that fail due to transient errors to be retried with exponential backoff and
+jitter in both websockets and HTTP POST mode.  By default, failures to
+communicate with the server and internal errors reported by the server, as
+classified by IsTransientError, are retried, but a custom classifier may be
+provided.  The request timeout applies to all attempts of a request combined and
+interceptors observe every attempt.
+
+Since requests that fail due to communication errors might have been processed
+by the server regardless, only requests for idempotent methods that merely read
+state, as classified by IsIdempotentMethod unless the policy provides its own
+classifier, are retried.  Requests for other methods, such as
+sendrawtransaction, that fail with an error which would otherwise be retried
+instead fail with a PermanentError that wraps the error:
+
+	txHash, err := client.SendRawTransaction(ctx, tx, false)
+	var permErr *rpcclient.PermanentError
+	if errors.As(err, &permErr) {
+		// The transaction might have been accepted by the server.
+	}
+
+Interceptors
+
+Request and response interceptors may be added to the client via the
+AddRequestInterceptor and AddResponseInterceptor methods in order to observe or
+modify every request the client sends and every result it receives, including
+those of RawRequest.  Request interceptors are provided with the method name
+and the marshalled parameters, which they may modify, and may fail the request
+by returning an error.  Response interceptors are additionally provided with the
+result or error, which they may modify, and the latency of the request.  This
+is useful for logging, metrics, request signing, and injecting faults in tests.
+
+Metrics
+
+A metrics registry created with NewMetrics may be provided via the Metrics
+field of the connection config to record the number of requests, failed
+requests, and in-flight requests along with a latency histogram for every RPC
+method, as well as the number of times websocket clients are disconnected and
+reconnected.  A single registry may be shared by multiple clients.  The recorded
+metrics are available via the Snapshot method of the registry and may be written
+in the Prometheus text exposition format with WritePrometheus or scraped
+directly by serving the registry, which implements http.Handler.
+
+Strict Result Validation
+
+The StrictResults field of the connection config optionally enables strictly
+validating the results of requests against the result types the client expects
+for the method.  Results that contain fields which are unknown to the client,
+numbers that overflow their type, or that otherwise do not match fail with a
+ResultSchemaError, which may be detected with errors.Is and ErrResultSchema.
+Since such mismatches are typically caused by using the client with a version
+of the RPC server that implements a different version of the JSON-RPC API, the
+error reports the API version of the server, which is requested once, when it
+differs from the version the client corresponds to.  This aids debugging, but
+also means that servers which add new fields to results are no longer
+compatible, so it is disabled by default.
+
 Interacting with Dcrwallet
 
-This package only provides methods for dcrd RPCs.  Using the websocket
+This package primarily provides methods for dcrd RPCs.  Using the websocket
 connection and request-response mapping provided by rpcclient with arbitrary
 methods or different servers is possible through the generic RawRequest and
 RawRequestAsync methods (each of which deal with json.RawMessage for parameters
-and return results).
+and return results).  The RawRequestStream method instead provides the raw
+result as an io.ReadCloser which, when running in HTTP POST mode, reads the
+result from the connection as it is consumed so that very large results are
+not buffered in their entirety.
+
+For applications that use dcrwallet, or a server which proxies its RPCs, as a
+combined endpoint, the client also provides typed methods for the most commonly
+used dcrwallet RPCs, namely GetBalance, ListUnspent, SendToAddress, and
+SignRawTransaction, along with their asynchronous forms.  These methods must be
+enabled via the WalletMethods field of the connection config and otherwise fail
+with ErrWalletMethodsDisabled.
 
 Previous versions of this package provided methods for dcrwallet's JSON-RPC
 server in addition to dcrd.  These were removed in major version 6 of this
@@ -131,6 +263,16 @@ This technique is valuable as dcrwallet (syncing in RPC mode) will passthrough
 any unknown RPCs to the backing dcrd server, proxying requests and responses for
 the client.
 
+Since the methods of dcrwallet.Client include those of the same name provided by
+this package for the commonly used dcrwallet RPCs, those methods must be
+selected via the embedded fields of such a type.
+
+The notifications sent by the websocket JSON-RPC server of dcrwallet, such as
+account balance changes, new transactions, and ticket purchases, votes, and
+revocations, are delivered to the typed handlers of the Wallet field of the
+NotificationHandlers so applications driving both daemons are provided with a
+uniform notification API.
+
 Errors
 
 There are 3 categories of errors that will be returned throughout this package:
@@ -155,19 +297,33 @@ The second category of errors typically indicates a programmer error and as such
 the type can vary, but usually will be best handled by simply showing/logging
 it.
 
-The third category of errors, that is errors returned by the server, can be
-detected by type asserting the error in a *dcrjson.RPCError.  For example, to
-detect if a command is unimplemented by the remote RPC server:
+The third category of errors, that is errors returned by the server, are of
+type *RPCError, which embeds the *dcrjson.RPCError sent by the server.  These
+errors are classified as one of the sentinel errors defined by this package,
+such as ErrBlockNotFound, ErrNoTxInfo, and ErrDuplicateTx, when possible so
+that specific conditions can be detected with errors.Is instead of inspecting
+error codes and messages.  For example, to detect if a requested block is not
+known by the remote RPC server:
+
+  block, err := client.GetBlock(ctx, blockHash)
+  if errors.Is(err, rpcclient.ErrBlockNotFound) {
+  	// Handle the block not being found.
+  }
+
+Errors that are not classified may still be inspected by using errors.As with
+either type.  For example, to detect if a command is unimplemented by the
+remote RPC server:
 
   block, err := client.GetBlock(ctx, blockHash)
   if err != nil {
-  	if jerr, ok := err.(*dcrjson.RPCError); ok {
+  	var jerr *dcrjson.RPCError
+  	if errors.As(err, &jerr) {
   		switch jerr.Code {
   		case dcrjson.ErrRPCUnimplemented:
   			// Handle not implemented error
 
   		// Handle other specific errors you care about
-		}
+  		}
   	}
 
   	// Log or otherwise handle the error knowing it was not one returned
@@ -0,0 +1,168 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/decred/dcrd/dcrjson/v3"
+)
+
+var (
+	// ErrMethodNotFound is an error to describe the condition where the RPC
+	// server does not recognize the requested method.
+	ErrMethodNotFound = errors.New("method not found")
+
+	// ErrInvalidParams is an error to describe the condition where the RPC
+	// server rejected the parameters of a request, either because they are
+	// of the wrong type or have invalid values.
+	ErrInvalidParams = errors.New("invalid parameters")
+
+	// ErrDeserialization is an error to describe the condition where the RPC
+	// server was unable to decode a serialized value, such as a transaction
+	// or a hex string, provided with a request.
+	ErrDeserialization = errors.New("deserialization failed")
+
+	// ErrBlockNotFound is an error to describe the condition where the RPC
+	// server does not know about a requested block.
+	ErrBlockNotFound = errors.New("block not found")
+
+	// ErrBlockHeightOutOfRange is an error to describe the condition where a
+	// requested block height is not part of the main chain of the RPC server.
+	ErrBlockHeightOutOfRange = errors.New("block height out of range")
+
+	// ErrNoTxInfo is an error to describe the condition where the RPC server
+	// does not have any information about a requested transaction.
+	ErrNoTxInfo = errors.New("no information available about transaction")
+
+	// ErrInvalidTxVout is an error to describe the condition where a
+	// requested transaction output index does not exist.
+	ErrInvalidTxVout = errors.New("transaction output index does not exist")
+
+	// ErrNoCFIndex is an error to describe the condition where a request
+	// requires compact filters which the RPC server does not maintain.
+	ErrNoCFIndex = errors.New("compact filters are not enabled")
+
+	// ErrDuplicateTx is an error to describe the condition where the RPC
+	// server rejected a transaction because it already has it.
+	ErrDuplicateTx = errors.New("duplicate transaction")
+
+	// ErrRuleViolation is an error to describe the condition where the RPC
+	// server rejected a transaction for violating a consensus rule or its
+	// policy.  The details of the violation are available via
+	// RuleViolation.  Rejected duplicate transactions are additionally
+	// classified as ErrDuplicateTx.
+	ErrRuleViolation = errors.New("transaction rule violation")
+
+	// ErrServerNotConnected is an error to describe the condition where the
+	// RPC server is not connected to any peers and therefore is unable to
+	// service the request.
+	ErrServerNotConnected = errors.New("server is not connected to the " +
+		"network")
+
+	// ErrServerInInitialDownload is an error to describe the condition where
+	// the RPC server is unable to service the request because it is still
+	// downloading the initial block chain.
+	ErrServerInInitialDownload = errors.New("server is downloading the " +
+		"initial block chain")
+)
+
+// rpcErrorKind associates the errors returned by the RPC server with a given
+// error code, and optionally one of the given message prefixes, with a
+// sentinel error.  The message prefixes are required since the same code is
+// used by the RPC server for many different conditions.
+type rpcErrorKind struct {
+	code     dcrjson.RPCErrorCode
+	prefixes []string
+	kind     error
+}
+
+// rpcErrorKinds houses the sentinel errors that errors returned by the RPC
+// server are classified as.
+var rpcErrorKinds = []rpcErrorKind{
+	{dcrjson.ErrRPCMethodNotFound.Code, nil, ErrMethodNotFound},
+	{dcrjson.ErrRPCInvalidParams.Code, nil, ErrInvalidParams},
+	{dcrjson.ErrRPCInvalidParameter, nil, ErrInvalidParams},
+	{dcrjson.ErrRPCDeserialization, nil, ErrDeserialization},
+	{dcrjson.ErrRPCBlockNotFound, []string{"block not found",
+		"failed to fetch block", "parent of the first header not found"},
+		ErrBlockNotFound},
+	{dcrjson.ErrRPCOutOfRange, []string{"block number out of range"},
+		ErrBlockHeightOutOfRange},
+	{dcrjson.ErrRPCNoTxInfo, []string{"no information available about " +
+		"transaction"}, ErrNoTxInfo},
+	{dcrjson.ErrRPCInvalidTxVout, []string{"output index number (vout) " +
+		"does not exist"}, ErrInvalidTxVout},
+	{dcrjson.ErrRPCNoCFIndex, []string{"compact filters must be enabled",
+		"the cf index must be enabled"}, ErrNoCFIndex},
+	{dcrjson.ErrRPCDuplicateTx, nil, ErrDuplicateTx},
+	{dcrjson.ErrRPCClientNotConnected, nil, ErrServerNotConnected},
+	{dcrjson.ErrRPCClientInInitialDownload, nil, ErrServerInInitialDownload},
+}
+
+// RPCError describes an error returned by the RPC server.  It embeds the
+// underlying dcrjson.RPCError, which is also returned when unwrapping it, so
+// the error code, message, and data of the error are directly accessible.
+//
+// Errors returned by the RPC server are classified as one of the sentinel
+// errors defined by this package when possible so that callers are able to
+// detect specific conditions with errors.Is instead of inspecting the error
+// code and message.  For example:
+//
+//	block, err := client.GetBlock(ctx, blockHash)
+//	if errors.Is(err, rpcclient.ErrBlockNotFound) {
+//		// Handle the block not being found.
+//	}
+type RPCError struct {
+	*dcrjson.RPCError
+
+	kinds []error
+}
+
+// Is implements the interface used by errors.Is to determine whether the error
+// is classified as the target sentinel error.
+func (e *RPCError) Is(target error) bool {
+	for _, kind := range e.kinds {
+		if kind == target {
+			return true
+		}
+	}
+	return false
+}
+
+// Unwrap returns the underlying dcrjson.RPCError of the error.
+func (e *RPCError) Unwrap() error {
+	return e.RPCError
+}
+
+// newRPCError returns the passed error returned by the RPC server as an
+// RPCError that is classified as the sentinel errors it matches.
+func newRPCError(err *dcrjson.RPCError) *RPCError {
+	var kinds []error
+	message := strings.ToLower(err.Message)
+	for i := range rpcErrorKinds {
+		k := &rpcErrorKinds[i]
+		if k.code != err.Code {
+			continue
+		}
+		matched := len(k.prefixes) == 0
+		for _, prefix := range k.prefixes {
+			if strings.HasPrefix(message, prefix) {
+				matched = true
+				break
+			}
+		}
+		if matched {
+			kinds = append(kinds, k.kind)
+		}
+	}
+
+	rpcErr := &RPCError{RPCError: err, kinds: kinds}
+	if _, ok := RuleViolation(rpcErr); ok {
+		rpcErr.kinds = append(rpcErr.kinds, ErrRuleViolation)
+	}
+	return rpcErr
+}
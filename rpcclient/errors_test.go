@@ -0,0 +1,156 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/decred/dcrd/dcrjson/v3"
+)
+
+// TestRPCErrorKinds ensures errors returned by the RPC server are classified as
+// the expected sentinel errors.
+func TestRPCErrorKinds(t *testing.T) {
+	allKinds := []error{ErrMethodNotFound, ErrInvalidParams,
+		ErrDeserialization, ErrBlockNotFound, ErrBlockHeightOutOfRange,
+		ErrNoTxInfo, ErrInvalidTxVout, ErrNoCFIndex, ErrDuplicateTx,
+		ErrRuleViolation, ErrServerNotConnected, ErrServerInInitialDownload}
+
+	ruleData := json.RawMessage(`{"category":"mempool",` +
+		`"code":"ErrDuplicate"}`)
+	tests := []struct {
+		name  string
+		err   *dcrjson.RPCError
+		kinds []error
+	}{{
+		name:  "method not found",
+		err:   dcrjson.ErrRPCMethodNotFound,
+		kinds: []error{ErrMethodNotFound},
+	}, {
+		name: "invalid parameter",
+		err: dcrjson.NewRPCError(dcrjson.ErrRPCInvalidParameter,
+			"invalid hash"),
+		kinds: []error{ErrInvalidParams},
+	}, {
+		name: "decode hex string",
+		err: dcrjson.NewRPCError(dcrjson.ErrRPCDecodeHexString,
+			"Argument must be hexadecimal string (not \"zz\")"),
+		kinds: []error{ErrDeserialization},
+	}, {
+		name: "block not found",
+		err: dcrjson.NewRPCError(dcrjson.ErrRPCBlockNotFound,
+			"Block not found: 0000"),
+		kinds: []error{ErrBlockNotFound},
+	}, {
+		name: "block fetch failure",
+		err: dcrjson.NewRPCError(dcrjson.ErrRPCBlockNotFound,
+			"Failed to fetch block: not found"),
+		kinds: []error{ErrBlockNotFound},
+	}, {
+		name: "block height out of range",
+		err: dcrjson.NewRPCError(dcrjson.ErrRPCOutOfRange,
+			"Block number out of range: 1000"),
+		kinds: []error{ErrBlockHeightOutOfRange},
+	}, {
+		name: "no tx info",
+		err: dcrjson.NewRPCError(dcrjson.ErrRPCNoTxInfo,
+			"No information available about transaction 0000"),
+		kinds: []error{ErrNoTxInfo},
+	}, {
+		name: "invalid tx vout",
+		err: dcrjson.NewRPCError(dcrjson.ErrRPCInvalidTxVout,
+			"Output index number (vout) does not exist for transaction."),
+		kinds: []error{ErrInvalidTxVout},
+	}, {
+		name: "no cf index",
+		err: dcrjson.NewRPCError(dcrjson.ErrRPCNoCFIndex,
+			"The CF index must be enabled for this command"),
+		kinds: []error{ErrNoCFIndex},
+	}, {
+		name: "duplicate tx with rule violation",
+		err: &dcrjson.RPCError{Code: dcrjson.ErrRPCDuplicateTx,
+			Message: "rejected transaction 0000: already have",
+			Data:    ruleData},
+		kinds: []error{ErrDuplicateTx, ErrRuleViolation},
+	}, {
+		name: "rule violation",
+		err: &dcrjson.RPCError{Code: dcrjson.ErrRPCMisc,
+			Message: "rejected transaction 0000: bad", Data: ruleData},
+		kinds: []error{ErrRuleViolation},
+	}, {
+		name: "not connected",
+		err: dcrjson.NewRPCError(dcrjson.ErrRPCClientNotConnected,
+			"Decred is not connected"),
+		kinds: []error{ErrServerNotConnected},
+	}, {
+		name: "initial download",
+		err: dcrjson.NewRPCError(dcrjson.ErrRPCClientInInitialDownload,
+			"Decred is downloading blocks..."),
+		kinds: []error{ErrServerInInitialDownload},
+	}, {
+		name: "invalid address with shared code",
+		err: dcrjson.NewRPCError(dcrjson.ErrRPCInvalidAddressOrKey,
+			"Invalid address or key"),
+	}, {
+		name: "unclassified misc",
+		err:  dcrjson.NewRPCError(dcrjson.ErrRPCMisc, "something failed"),
+	}}
+
+	for _, test := range tests {
+		err := error(newRPCError(test.err))
+		for _, kind := range allKinds {
+			want := false
+			for _, k := range test.kinds {
+				if k == kind {
+					want = true
+				}
+			}
+			if got := errors.Is(err, kind); got != want {
+				t.Errorf("%q: unexpected result for errors.Is(%q) -- got "+
+					"%v, want %v", test.name, kind, got, want)
+			}
+		}
+
+		// Ensure the underlying error is available.
+		var jerr *dcrjson.RPCError
+		if !errors.As(err, &jerr) || jerr != test.err {
+			t.Errorf("%q: underlying dcrjson error is not available",
+				test.name)
+		}
+		if err.Error() != test.err.Error() {
+			t.Errorf("%q: unexpected error string -- got %q, want %q",
+				test.name, err.Error(), test.err.Error())
+		}
+	}
+}
+
+// TestClientRPCErrors ensures errors returned by the RPC server are returned
+// by the client as classified RPC errors.
+func TestClientRPCErrors(t *testing.T) {
+	server, c := newTestHTTPClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result":null,"error":{"code":-5,` +
+			`"message":"Block not found: 0000"},"id":1}`))
+	}, nil)
+	defer server.Close()
+	defer c.Shutdown()
+
+	_, err := c.GetBlockCount(context.Background())
+	if !errors.Is(err, ErrBlockNotFound) {
+		t.Fatalf("unexpected error -- got %v, want %v", err,
+			ErrBlockNotFound)
+	}
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) {
+		t.Fatalf("error is not an RPC error: %T", err)
+	}
+	if rpcErr.Code != dcrjson.ErrRPCBlockNotFound {
+		t.Fatalf("unexpected error code -- got %v, want %v", rpcErr.Code,
+			dcrjson.ErrRPCBlockNotFound)
+	}
+}
@@ -74,5 +74,5 @@ func main() {
 
 	// Wait until the client either shuts down gracefully (or the user
 	// terminates the process with Ctrl+C).
-	client.WaitForShutdown()
+	client.WaitForShutdown(context.Background())
 }
@@ -6,8 +6,11 @@
 package rpcclient
 
 import (
+	"bytes"
 	"context"
+	"encoding/hex"
 	"encoding/json"
+	"time"
 
 	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/dcrutil/v3"
@@ -20,6 +23,158 @@ var (
 	zeroUint32 = uint32(0)
 )
 
+// FutureCaptureProfileResult is a future promise to deliver the result of a
+// CaptureProfileAsync RPC invocation (or an applicable error).
+type FutureCaptureProfileResult cmdRes
+
+// Receive waits for the response promised by the future and returns the type
+// of the captured profile and the path it was written to on the server.
+func (r *FutureCaptureProfileResult) Receive() (*chainjson.CaptureProfileResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a captureprofile result object.
+	var result chainjson.CaptureProfileResult
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CaptureProfileAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See CaptureProfile for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) CaptureProfileAsync(ctx context.Context, profileType string, duration time.Duration) *FutureCaptureProfileResult {
+	seconds := int(duration / time.Second)
+	cmd := chainjson.NewCaptureProfileCmd(profileType, &seconds)
+	return (*FutureCaptureProfileResult)(c.sendCmd(ctx, cmd))
+}
+
+// CaptureProfile instructs the server to capture a profile of the passed type,
+// such as cpu or heap, in the pprof format and returns the path of the file it
+// was written to on the server.  The cpu, block, and mutex profiles sample the
+// server for the passed duration, which is truncated to whole seconds, before
+// the result is returned, so the passed context must allow for it.  The
+// duration is ignored for the remaining profile types.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) CaptureProfile(ctx context.Context, profileType string, duration time.Duration) (*chainjson.CaptureProfileResult, error) {
+	return c.CaptureProfileAsync(ctx, profileType, duration).Receive()
+}
+
+// FutureCheckTransactionStandardResult is a future promise to deliver the
+// result of a CheckTransactionStandardAsync RPC invocation (or an applicable
+// error).
+type FutureCheckTransactionStandardResult cmdRes
+
+// Receive waits for the response promised by the future and returns all
+// violations of the standardness policy rules by the transaction.
+func (r *FutureCheckTransactionStandardResult) Receive() (*chainjson.CheckTransactionStandardResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a checktransactionstandard result object.
+	var result chainjson.CheckTransactionStandardResult
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CheckTransactionStandardAsync returns an instance of a type that can be used
+// to get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See CheckTransactionStandard for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) CheckTransactionStandardAsync(ctx context.Context, tx *wire.MsgTx) *FutureCheckTransactionStandardResult {
+	txHex := ""
+	if tx != nil {
+		// Serialize the transaction and convert to hex string.
+		buf := bytes.NewBuffer(make([]byte, 0, tx.SerializeSize()))
+		if err := tx.Serialize(buf); err != nil {
+			return (*FutureCheckTransactionStandardResult)(newFutureError(ctx, err))
+		}
+		txHex = hex.EncodeToString(buf.Bytes())
+	}
+
+	cmd := chainjson.NewCheckTransactionStandardCmd(txHex)
+	return (*FutureCheckTransactionStandardResult)(c.sendCmd(ctx, cmd))
+}
+
+// CheckTransactionStandard returns every violation of the policy the mempool
+// uses to determine whether or not transactions are standard by the passed
+// transaction along with the policy rule each one violates.  This allows
+// transactions to be checked prior to broadcasting them.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) CheckTransactionStandard(ctx context.Context, tx *wire.MsgTx) (*chainjson.CheckTransactionStandardResult, error) {
+	return c.CheckTransactionStandardAsync(ctx, tx).Receive()
+}
+
+// FutureCompareChainWorkResult is a future promise to deliver the result of a
+// CompareChainWorkAsync RPC invocation (or an applicable error).
+type FutureCompareChainWorkResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// comparison of the work of the provided chain with the best chain.
+func (r *FutureCompareChainWorkResult) Receive() (*chainjson.CompareChainWorkResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a comparechainwork result object.
+	var result chainjson.CompareChainWorkResult
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CompareChainWorkAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See CompareChainWork for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) CompareChainWorkAsync(ctx context.Context, headers []*wire.BlockHeader) *FutureCompareChainWorkResult {
+	hexHeaders := make([]string, 0, len(headers))
+	for _, header := range headers {
+		headerBytes, err := header.Bytes()
+		if err != nil {
+			return (*FutureCompareChainWorkResult)(newFutureError(ctx, err))
+		}
+		hexHeaders = append(hexHeaders, hex.EncodeToString(headerBytes))
+	}
+
+	cmd := chainjson.NewCompareChainWorkCmd(hexHeaders)
+	return (*FutureCompareChainWorkResult)(c.sendCmd(ctx, cmd))
+}
+
+// CompareChainWork compares the cumulative proof of work of the passed chain of
+// block headers, which must build on a block known to the server, with the
+// work of the current best chain of the server and returns the result along
+// with the point at which the chains fork.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) CompareChainWork(ctx context.Context, headers []*wire.BlockHeader) (*chainjson.CompareChainWorkResult, error) {
+	return c.CompareChainWorkAsync(ctx, headers).Receive()
+}
+
 // FutureDebugLevelResult is a future promise to deliver the result of a
 // DebugLevelAsync RPC invocation (or an applicable error).
 type FutureDebugLevelResult cmdRes
@@ -68,6 +223,80 @@ func (c *Client) DebugLevel(ctx context.Context, levelSpec string) (string, erro
 	return c.DebugLevelAsync(ctx, levelSpec).Receive()
 }
 
+// FutureDisconnectRPCClientResult is a future promise to deliver the result of
+// a DisconnectRPCClientAsync RPC invocation (or an applicable error).
+type FutureDisconnectRPCClientResult cmdRes
+
+// Receive waits for the response promised by the future and returns an error
+// if the client was not disconnected.
+func (r *FutureDisconnectRPCClientResult) Receive() error {
+	_, err := receiveFuture(r.ctx, r.c)
+	return err
+}
+
+// DisconnectRPCClientAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See DisconnectRPCClient for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) DisconnectRPCClientAsync(ctx context.Context, sessionID uint64) *FutureDisconnectRPCClientResult {
+	cmd := chainjson.NewDisconnectRPCClientCmd(sessionID)
+	return (*FutureDisconnectRPCClientResult)(c.sendCmd(ctx, cmd))
+}
+
+// DisconnectRPCClient disconnects the websocket client of the RPC server with
+// the passed session ID as returned by ListRPCClients.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) DisconnectRPCClient(ctx context.Context, sessionID uint64) error {
+	return c.DisconnectRPCClientAsync(ctx, sessionID).Receive()
+}
+
+// FutureDumpPeerTelemetryResult is a future promise to deliver the result of a
+// DumpPeerTelemetryAsync RPC invocation (or an applicable error).
+type FutureDumpPeerTelemetryResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// details of the file the peer telemetry was written to.
+func (r *FutureDumpPeerTelemetryResult) Receive() (*chainjson.DumpPeerTelemetryResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a dumppeertelemetry result object.
+	var dumpResult chainjson.DumpPeerTelemetryResult
+	err = json.Unmarshal(res, &dumpResult)
+	if err != nil {
+		return nil, err
+	}
+	return &dumpResult, nil
+}
+
+// DumpPeerTelemetryAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See DumpPeerTelemetry for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) DumpPeerTelemetryAsync(ctx context.Context) *FutureDumpPeerTelemetryResult {
+	cmd := chainjson.NewDumpPeerTelemetryCmd()
+	return (*FutureDumpPeerTelemetryResult)(c.sendCmd(ctx, cmd))
+}
+
+// DumpPeerTelemetry writes the anonymized peer telemetry recorded by the server
+// since the previous dump to a new file in its data directory and returns the
+// path of the file along with the number of events it contains.  The server
+// must be started with peer telemetry enabled.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) DumpPeerTelemetry(ctx context.Context) (*chainjson.DumpPeerTelemetryResult, error) {
+	return c.DumpPeerTelemetryAsync(ctx).Receive()
+}
+
 // FutureEstimateStakeDiffResult is a future promise to deliver the result of a
 // EstimateStakeDiffAsync RPC invocation (or an applicable error).
 type FutureEstimateStakeDiffResult cmdRes
@@ -110,6 +339,59 @@ func (c *Client) EstimateStakeDiff(ctx context.Context, tickets *uint32) (*chain
 	return c.EstimateStakeDiffAsync(ctx, tickets).Receive()
 }
 
+// FutureEvaluateTxLocksResult is a future promise to deliver the result of an
+// EvaluateTxLocksAsync RPC invocation (or an applicable error).
+type FutureEvaluateTxLocksResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// evaluation of the lock times of the transaction.
+func (r *FutureEvaluateTxLocksResult) Receive() (*chainjson.EvaluateTxLocksResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as an evaluatetxlocks result object.
+	var result chainjson.EvaluateTxLocksResult
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// EvaluateTxLocksAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See EvaluateTxLocks for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) EvaluateTxLocksAsync(ctx context.Context, tx *wire.MsgTx) *FutureEvaluateTxLocksResult {
+	txHex := ""
+	if tx != nil {
+		// Serialize the transaction and convert to hex string.
+		buf := bytes.NewBuffer(make([]byte, 0, tx.SerializeSize()))
+		if err := tx.Serialize(buf); err != nil {
+			return (*FutureEvaluateTxLocksResult)(newFutureError(ctx, err))
+		}
+		txHex = hex.EncodeToString(buf.Bytes())
+	}
+
+	cmd := chainjson.NewEvaluateTxLocksCmd(txHex)
+	return (*FutureEvaluateTxLocksResult)(c.sendCmd(ctx, cmd))
+}
+
+// EvaluateTxLocks evaluates the lock time and the relative lock times of the
+// input sequence numbers of the passed transaction against the current best
+// chain of the server and returns the earliest block height and past median
+// time at which the transaction becomes final.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) EvaluateTxLocks(ctx context.Context, tx *wire.MsgTx) (*chainjson.EvaluateTxLocksResult, error) {
+	return c.EvaluateTxLocksAsync(ctx, tx).Receive()
+}
+
 // FutureExistsAddressResult is a future promise to deliver the result
 // of a FutureExistsAddressResultAsync RPC invocation (or an applicable error).
 type FutureExistsAddressResult cmdRes
@@ -393,6 +675,92 @@ func (c *Client) ExistsMempoolTxs(ctx context.Context, hashes []*chainhash.Hash)
 	return c.ExistsMempoolTxsAsync(ctx, hashes).Receive()
 }
 
+// FutureGetAddressActivityResult is a future promise to deliver the result of
+// a GetAddressActivityAsync RPC invocation (or an applicable error).
+type FutureGetAddressActivityResult cmdRes
+
+// Receive waits for the response promised by the future and returns the first
+// and last heights at which the address was seen in the main chain.
+func (r *FutureGetAddressActivityResult) Receive() (*chainjson.GetAddressActivityResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getaddressactivity result object.
+	var activityResult chainjson.GetAddressActivityResult
+	err = json.Unmarshal(res, &activityResult)
+	if err != nil {
+		return nil, err
+	}
+	return &activityResult, nil
+}
+
+// GetAddressActivityAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetAddressActivity for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetAddressActivityAsync(ctx context.Context, address dcrutil.Address) *FutureGetAddressActivityResult {
+	cmd := chainjson.NewGetAddressActivityCmd(address.Address())
+	return (*FutureGetAddressActivityResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetAddressActivity returns the first and last heights at which the passed
+// address was seen in the main chain.
+//
+// NOTE: This is a dcrd extension and requires the address activity index.
+func (c *Client) GetAddressActivity(ctx context.Context, address dcrutil.Address) (*chainjson.GetAddressActivityResult, error) {
+	return c.GetAddressActivityAsync(ctx, address).Receive()
+}
+
+// FutureGetAddressUtxosResult is a future promise to deliver the result of a
+// GetAddressUtxosAsync RPC invocation (or an applicable error).
+type FutureGetAddressUtxosResult cmdRes
+
+// Receive waits for the response promised by the future and returns a page of
+// the unspent outputs that pay to the address along with the pagination
+// details.
+func (r *FutureGetAddressUtxosResult) Receive() (*chainjson.GetAddressUtxosResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getaddressutxos result object.
+	var utxosResult chainjson.GetAddressUtxosResult
+	err = json.Unmarshal(res, &utxosResult)
+	if err != nil {
+		return nil, err
+	}
+	return &utxosResult, nil
+}
+
+// GetAddressUtxosAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetAddressUtxos for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetAddressUtxosAsync(ctx context.Context, address dcrutil.Address, cursor string, limit int) *FutureGetAddressUtxosResult {
+	cmd := chainjson.NewGetAddressUtxosCmd(address.Address(), &cursor, &limit)
+	return (*FutureGetAddressUtxosResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetAddressUtxos returns a page of up to limit confirmed unspent outputs that
+// pay to the passed address in the order they were confirmed.  An empty cursor
+// requests the first page while the next cursor of the returned page requests
+// the page after it.  See NewAddressUtxoIterator for a convenient way to
+// iterate all of them.
+//
+// NOTE: This is a dcrd extension and requires the address index.
+func (c *Client) GetAddressUtxos(ctx context.Context, address dcrutil.Address, cursor string, limit int) (*chainjson.GetAddressUtxosResult, error) {
+	return c.GetAddressUtxosAsync(ctx, address, cursor, limit).Receive()
+}
+
 // FutureGetBestBlockResult is a future promise to deliver the result of a
 // GetBestBlockAsync RPC invocation (or an applicable error).
 type FutureGetBestBlockResult cmdRes
@@ -530,6 +898,105 @@ func (c *Client) GetHeaders(ctx context.Context, blockLocators []*chainhash.Hash
 	return c.GetHeadersAsync(ctx, blockLocators, hashStop).Receive()
 }
 
+// FutureGetMempoolReplacementsResult is a future promise to deliver the result
+// of a GetMempoolReplacementsAsync RPC invocation (or an applicable error).
+type FutureGetMempoolReplacementsResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// transaction replacement policy of the mempool along with the most recent
+// replacement decisions.
+func (r *FutureGetMempoolReplacementsResult) Receive() (*chainjson.GetMempoolReplacementsResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getmempoolreplacements result object.
+	var replacements chainjson.GetMempoolReplacementsResult
+	err = json.Unmarshal(res, &replacements)
+	if err != nil {
+		return nil, err
+	}
+	return &replacements, nil
+}
+
+// GetMempoolReplacementsAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See GetMempoolReplacements for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetMempoolReplacementsAsync(ctx context.Context) *FutureGetMempoolReplacementsResult {
+	cmd := chainjson.NewGetMempoolReplacementsCmd()
+	return (*FutureGetMempoolReplacementsResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetMempoolReplacements returns the transaction replacement policy of the
+// mempool along with the most recent decisions about transactions that were
+// evaluated as replacements for conflicting transactions in the mempool,
+// including the reasons rejected replacements were not accepted.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetMempoolReplacements(ctx context.Context) (*chainjson.GetMempoolReplacementsResult, error) {
+	return c.GetMempoolReplacementsAsync(ctx).Receive()
+}
+
+// FutureGetSignalingStatsResult is a future promise to deliver the result of a
+// GetSignalingStatsAsync RPC invocation (or an applicable error).
+type FutureGetSignalingStatsResult cmdRes
+
+// Receive waits for the response promised by the future and returns the block
+// version and vote bits signaling statistics of the requested window.
+func (r *FutureGetSignalingStatsResult) Receive() (*chainjson.GetSignalingStatsResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getsignalingstats result object.
+	var stats chainjson.GetSignalingStatsResult
+	err = json.Unmarshal(res, &stats)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+// GetSignalingStatsAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetSignalingStats for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetSignalingStatsAsync(ctx context.Context, blockHash *chainhash.Hash, blocks int32, voteVersion *uint32) *FutureGetSignalingStatsResult {
+	var hash *string
+	if blockHash != nil {
+		hashStr := blockHash.String()
+		hash = &hashStr
+	}
+	var numBlocks *int32
+	if blocks != 0 {
+		numBlocks = &blocks
+	}
+
+	cmd := chainjson.NewGetSignalingStatsCmd(numBlocks, hash, voteVersion)
+	return (*FutureGetSignalingStatsResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetSignalingStats returns the per-bit block version and vote bits signaling
+// statistics of the provided number of blocks ending with the block with the
+// given hash.  A nil hash uses the current best block and zero blocks uses the
+// rule change activation interval of the active network.  A nil vote version
+// tallies the bits of all votes.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetSignalingStats(ctx context.Context, blockHash *chainhash.Hash, blocks int32, voteVersion *uint32) (*chainjson.GetSignalingStatsResult, error) {
+	return c.GetSignalingStatsAsync(ctx, blockHash, blocks, voteVersion).Receive()
+}
+
 // FutureGetStakeDifficultyResult is a future promise to deliver the result of a
 // GetStakeDifficultyAsync RPC invocation (or an applicable error).
 type FutureGetStakeDifficultyResult cmdRes
@@ -653,6 +1120,71 @@ func (c *Client) GetStakeVersions(ctx context.Context, hash string, count int32)
 	return c.GetStakeVersionsAsync(ctx, hash, count).Receive()
 }
 
+// GetStakeVersionIntervalsAsync returns an instance of a type that can be used
+// to get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See GetStakeVersionIntervals for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetStakeVersionIntervalsAsync(ctx context.Context, hash string, count, intervalSize int32) *FutureGetStakeVersionsResult {
+	cmd := chainjson.NewGetStakeVersionsCmd(hash, count)
+	cmd.IntervalSize = &intervalSize
+	return (*FutureGetStakeVersionsResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetStakeVersionIntervals returns the stake versions and vote versions of past
+// requested blocks aggregated by the server into intervals of the provided
+// number of blocks.  The NextHash field of the result may be used as the hash
+// of a subsequent call to continue from where the result left off.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetStakeVersionIntervals(ctx context.Context, hash string, count, intervalSize int32) (*chainjson.GetStakeVersionsResult, error) {
+	return c.GetStakeVersionIntervalsAsync(ctx, hash, count, intervalSize).Receive()
+}
+
+// FutureGetStandardPolicyResult is a future promise to deliver the result of a
+// GetStandardPolicyAsync RPC invocation (or an applicable error).
+type FutureGetStandardPolicyResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// standardness policy of the mempool.
+func (r *FutureGetStandardPolicyResult) Receive() (*chainjson.GetStandardPolicyResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getstandardpolicy result object.
+	var policy chainjson.GetStandardPolicyResult
+	err = json.Unmarshal(res, &policy)
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// GetStandardPolicyAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetStandardPolicy for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetStandardPolicyAsync(ctx context.Context) *FutureGetStandardPolicyResult {
+	cmd := chainjson.NewGetStandardPolicyCmd()
+	return (*FutureGetStandardPolicyResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetStandardPolicy returns the policy the mempool uses to determine whether
+// or not transactions are standard along with the standardness policies for
+// all known script versions and whether or not they currently apply.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetStandardPolicy(ctx context.Context) (*chainjson.GetStandardPolicyResult, error) {
+	return c.GetStandardPolicyAsync(ctx).Receive()
+}
+
 // FutureGetTicketPoolValueResult is a future promise to deliver the result of a
 // GetTicketPoolValueAsync RPC invocation (or an applicable error).
 type FutureGetTicketPoolValueResult cmdRes
@@ -700,6 +1232,108 @@ func (c *Client) GetTicketPoolValue(ctx context.Context) (dcrutil.Amount, error)
 	return c.GetTicketPoolValueAsync(ctx).Receive()
 }
 
+// FutureGetTimeInfoResult is a future promise to deliver the result of a
+// GetTimeInfoAsync RPC invocation (or an applicable error).
+type FutureGetTimeInfoResult cmdRes
+
+// Receive waits for the response promised by the future and returns the median
+// time of the block along with the adjusted time and time offset of the server.
+func (r *FutureGetTimeInfoResult) Receive() (*chainjson.GetTimeInfoResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a gettimeinfo result object.
+	var result chainjson.GetTimeInfoResult
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetTimeInfoAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See GetTimeInfo for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetTimeInfoAsync(ctx context.Context, blockHash *chainhash.Hash) *FutureGetTimeInfoResult {
+	var hash *string
+	if blockHash != nil {
+		hashStr := blockHash.String()
+		hash = &hashStr
+	}
+
+	cmd := chainjson.NewGetTimeInfoCmd(hash)
+	return (*FutureGetTimeInfoResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetTimeInfo returns the median time of the block with the passed hash, or
+// the current best chain tip of the server when the hash is nil, along with
+// the local time of the server, its time adjusted by the median time offset of
+// its peers, and that offset.
+//
+// Transaction lock times specified as a time are evaluated against the median
+// time of the block prior to the one the transaction is included in, so the
+// median time of the best chain tip determines whether such transactions may
+// be included in the next block.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetTimeInfo(ctx context.Context, blockHash *chainhash.Hash) (*chainjson.GetTimeInfoResult, error) {
+	return c.GetTimeInfoAsync(ctx, blockHash).Receive()
+}
+
+// FutureGetTxScriptCostResult is a future promise to deliver the result of a
+// GetTxScriptCostAsync RPC invocation (or an applicable error).
+type FutureGetTxScriptCostResult cmdRes
+
+// Receive waits for the response promised by the future and returns the cost
+// of executing the scripts of each input of the transaction.
+func (r *FutureGetTxScriptCostResult) Receive() (*chainjson.GetTxScriptCostResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a gettxscriptcost result object.
+	var result chainjson.GetTxScriptCostResult
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetTxScriptCostAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetTxScriptCost for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetTxScriptCostAsync(ctx context.Context, txHash *chainhash.Hash) *FutureGetTxScriptCostResult {
+	hash := ""
+	if txHash != nil {
+		hash = txHash.String()
+	}
+
+	cmd := chainjson.NewGetTxScriptCostCmd(hash)
+	return (*FutureGetTxScriptCostResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetTxScriptCost instructs the server to execute the scripts of all inputs of
+// the transaction with the passed hash, which must either be in the mempool or
+// the server must have the transaction index enabled, and returns the number
+// of executed opcodes, signature operations, and execution time per input.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetTxScriptCost(ctx context.Context, txHash *chainhash.Hash) (*chainjson.GetTxScriptCostResult, error) {
+	return c.GetTxScriptCostAsync(ctx, txHash).Receive()
+}
+
 // FutureGetVoteInfoResult is a future promise to deliver the result of a
 // GetVoteInfoAsync RPC invocation (or an applicable error).
 type FutureGetVoteInfoResult cmdRes
@@ -742,6 +1376,114 @@ func (c *Client) GetVoteInfo(ctx context.Context, version uint32) (*chainjson.Ge
 	return c.GetVoteInfoAsync(ctx, version).Receive()
 }
 
+// GetVoteInfoPriorIntervalsAsync returns an instance of a type that can be used
+// to get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See GetVoteInfoPriorIntervals for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetVoteInfoPriorIntervalsAsync(ctx context.Context, version uint32, count int32) *FutureGetVoteInfoResult {
+	cmd := chainjson.NewGetVoteInfoCmd(version)
+	cmd.Count = &count
+	return (*FutureGetVoteInfoResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetVoteInfoPriorIntervals returns voting information for the specified stake
+// version along with the vote counts for the provided number of completed
+// prior rule change intervals.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetVoteInfoPriorIntervals(ctx context.Context, version uint32, count int32) (*chainjson.GetVoteInfoResult, error) {
+	return c.GetVoteInfoPriorIntervalsAsync(ctx, version, count).Receive()
+}
+
+// FutureListBannedResult is a future promise to deliver the result of a
+// ListBannedAsync RPC invocation (or an applicable error).
+type FutureListBannedResult cmdRes
+
+// Receive waits for the response promised by the future and returns a page of
+// the banned hosts along with the pagination details.
+func (r *FutureListBannedResult) Receive() (*chainjson.ListBannedResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a listbanned result object.
+	var bannedResult chainjson.ListBannedResult
+	err = json.Unmarshal(res, &bannedResult)
+	if err != nil {
+		return nil, err
+	}
+	return &bannedResult, nil
+}
+
+// ListBannedAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See ListBanned for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) ListBannedAsync(ctx context.Context, cursor string, limit int) *FutureListBannedResult {
+	cmd := chainjson.NewListBannedCmd(&cursor, &limit)
+	return (*FutureListBannedResult)(c.sendCmd(ctx, cmd))
+}
+
+// ListBanned returns a page of up to limit hosts that are currently banned by
+// the RPC server sorted by host.  An empty cursor requests the first page while
+// the next cursor of the returned page requests the page after it.  See
+// NewBannedPeerIterator for a convenient way to iterate all of them.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) ListBanned(ctx context.Context, cursor string, limit int) (*chainjson.ListBannedResult, error) {
+	return c.ListBannedAsync(ctx, cursor, limit).Receive()
+}
+
+// FutureListRPCClientsResult is a future promise to deliver the result of a
+// ListRPCClientsAsync RPC invocation (or an applicable error).
+type FutureListRPCClientsResult cmdRes
+
+// Receive waits for the response promised by the future and returns data about
+// each websocket client connected to the RPC server.
+func (r *FutureListRPCClientsResult) Receive() ([]chainjson.ListRPCClientsResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as an array of listrpcclients result objects.
+	var clients []chainjson.ListRPCClientsResult
+	err = json.Unmarshal(res, &clients)
+	if err != nil {
+		return nil, err
+	}
+
+	return clients, nil
+}
+
+// ListRPCClientsAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See ListRPCClients for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) ListRPCClientsAsync(ctx context.Context) *FutureListRPCClientsResult {
+	cmd := chainjson.NewListRPCClientsCmd()
+	return (*FutureListRPCClientsResult)(c.sendCmd(ctx, cmd))
+}
+
+// ListRPCClients returns data about each websocket client connected to the RPC
+// server, including the name and version it registered with and the
+// notifications it is subscribed to.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) ListRPCClients(ctx context.Context) ([]chainjson.ListRPCClientsResult, error) {
+	return c.ListRPCClientsAsync(ctx).Receive()
+}
+
 // FutureLiveTicketsResult is a future promise to deliver the result
 // of a FutureLiveTicketsResultAsync RPC invocation (or an applicable error).
 type FutureLiveTicketsResult cmdRes
@@ -836,6 +1578,100 @@ func (c *Client) MissedTickets(ctx context.Context) ([]*chainhash.Hash, error) {
 	return c.MissedTicketsAsync(ctx).Receive()
 }
 
+// FutureProposeBlockResult is a future promise to deliver the result of a
+// ProposeBlockAsync RPC invocation (or an applicable error).
+type FutureProposeBlockResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// result of validating the proposed block.
+func (r *FutureProposeBlockResult) Receive() (*chainjson.ProposeBlockResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a proposeblock result object.
+	var result chainjson.ProposeBlockResult
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ProposeBlockAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See ProposeBlock for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) ProposeBlockAsync(ctx context.Context, block *dcrutil.Block) *FutureProposeBlockResult {
+	blockHex := ""
+	if block != nil {
+		blockBytes, err := block.Bytes()
+		if err != nil {
+			return (*FutureProposeBlockResult)(newFutureError(ctx, err))
+		}
+
+		blockHex = hex.EncodeToString(blockBytes)
+	}
+
+	cmd := chainjson.NewProposeBlockCmd(blockHex)
+	return (*FutureProposeBlockResult)(c.sendCmd(ctx, cmd))
+}
+
+// ProposeBlock fully validates the passed block as if it were connected to the
+// current tip of the main chain of the server or its parent, aside from the
+// proof of work requirement, without adding it to the chain or relaying it.
+// The result indicates whether or not the block is valid along with the reason
+// it is invalid when it is not.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) ProposeBlock(ctx context.Context, block *dcrutil.Block) (*chainjson.ProposeBlockResult, error) {
+	return c.ProposeBlockAsync(ctx, block).Receive()
+}
+
+// FutureRegisterClientResult is a future promise to deliver the result of a
+// RegisterClientAsync RPC invocation (or an applicable error).
+type FutureRegisterClientResult cmdRes
+
+// Receive waits for the response promised by the future and returns an error
+// if the registration was not successful.
+func (r *FutureRegisterClientResult) Receive() error {
+	_, err := receiveFuture(r.ctx, r.c)
+	return err
+}
+
+// RegisterClientAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See RegisterClient for the blocking version and more details.
+//
+// NOTE: This is a Decred extension.
+func (c *Client) RegisterClientAsync(ctx context.Context, name, version string) *FutureRegisterClientResult {
+	// Not supported in HTTP POST mode.
+	if c.config.HTTPPostMode {
+		return (*FutureRegisterClientResult)(newFutureError(ctx, ErrWebsocketsRequired))
+	}
+
+	cmd := chainjson.NewRegisterClientCmd(name, &version)
+	return (*FutureRegisterClientResult)(c.sendCmd(ctx, cmd))
+}
+
+// RegisterClient identifies the software of the client to the RPC server for
+// the remainder of the current connection.  Clients configured with a
+// ClientName in the connection configuration automatically register each time
+// the connection is established.
+//
+// This RPC requires the client to be running in websocket mode.
+//
+// NOTE: This is a Decred extension.
+func (c *Client) RegisterClient(ctx context.Context, name, version string) error {
+	return c.RegisterClientAsync(ctx, name, version).Receive()
+}
+
 // FutureSessionResult is a future promise to deliver the result of a
 // SessionAsync RPC invocation (or an applicable error).
 type FutureSessionResult cmdRes
@@ -17,6 +17,7 @@ require (
 replace (
 	github.com/decred/dcrd/chaincfg/v3 => ../chaincfg
 	github.com/decred/dcrd/dcrec/secp256k1/v3 => ../dcrec/secp256k1
+	github.com/decred/dcrd/dcrjson/v3 => ../dcrjson
 	github.com/decred/dcrd/dcrutil/v3 => ../dcrutil
 	github.com/decred/dcrd/hdkeychain/v3 => ../hdkeychain
 	github.com/decred/dcrd/rpc/jsonrpc/types/v2 => ../rpc/jsonrpc/types
@@ -58,8 +58,9 @@ var (
 
 	// ErrClientShutdown is an error to describe the condition where the
 	// client is either already shutdown, or in the process of shutting
-	// down.  Any outstanding futures when a client shutdown occurs will
-	// return this error as will any new requests.
+	// down.  Any outstanding futures when a client shutdown occurs,
+	// including HTTP POST requests that are in flight, will return this
+	// error as will any new requests.
 	ErrClientShutdown = errors.New("the client has been shutdown")
 
 	// ErrNotWebsocketClient is an error to describe the condition of
@@ -78,6 +79,11 @@ var (
 	// a request was canceled by the caller by terminating the passed
 	// context.
 	ErrRequestCanceled = errors.New("request was canceled by the caller")
+
+	// ErrRequestTimeout is an error to describe the condition where a
+	// request did not complete before either the deadline of the passed
+	// context or the default request timeout of the client elapsed.
+	ErrRequestTimeout = errors.New("request timed out")
 )
 
 const (
@@ -96,6 +102,16 @@ const (
 	// pingInterval is the amount of time between ping messages sent to
 	// the server.
 	pingInterval = time.Second * 10
+
+	// ntfnBufferSize is the number of received notifications that can be
+	// queued for delivery to the notification handlers before the
+	// websocket input handler blocks.
+	ntfnBufferSize = 100
+
+	// defaultNtfnDrainTimeout is the default maximum amount of time spent
+	// delivering queued notifications to the notification handlers once
+	// the client is shutdown.
+	defaultNtfnDrainTimeout = time.Second * 5
 )
 
 // cmdRes holds command results.
@@ -152,6 +168,11 @@ type Client struct {
 	// It is protected by mtx.
 	wsConn *websocket.Conn
 
+	// hostIdx is the index of the RPC server the client is currently using
+	// within the hosts returned by the hosts method of the connection
+	// configuration.  It is protected by mtx.
+	hostIdx int
+
 	// disconnected indicated whether or not the server is disconnected.
 	disconnected bool
 
@@ -160,10 +181,30 @@ type Client struct {
 	requestMap  map[uint64]*list.Element
 	requestList *list.List
 
-	// Notifications.
+	// Notifications.  Received notifications are queued on ntfnChan and
+	// delivered to the handlers by a separate goroutine so slow handlers
+	// do not hold up responses to requests.
 	ntfnHandlers  *NotificationHandlers
 	ntfnStateLock sync.Mutex
 	ntfnState     *notificationState
+	ntfnChan      chan *rawNotification
+
+	// ntfnSubs houses the subscriptions that deliver notifications to
+	// channels as opposed to the notification handlers.
+	ntfnSubsMtx sync.Mutex
+	ntfnSubs    map[*ntfnSubscription]struct{}
+
+	// reqInterceptors and respInterceptors house the interceptors that are
+	// invoked for every request and response, respectively.  The slices are
+	// replaced instead of modified when interceptors are added so that
+	// they can be used without holding the mutex.
+	interceptorsMtx  sync.RWMutex
+	reqInterceptors  []RequestInterceptor
+	respInterceptors []ResponseInterceptor
+
+	// serverVersion caches the JSON-RPC API version of the RPC server that
+	// is reported when results fail strict validation.
+	serverVersion serverVersionCache
 
 	// Networking infrastructure.
 	sendChan        chan []byte
@@ -179,20 +220,49 @@ type Client struct {
 func (c *Client) String() string {
 	var u url.URL
 	switch {
-	case c.config.HTTPPostMode && c.config.DisableTLS:
+	case c.config.HTTPPostMode && !c.config.useTLS():
 		u.Scheme = "http"
 	case c.config.HTTPPostMode:
 		u.Scheme = "https"
-	case c.config.DisableTLS:
+	case !c.config.useTLS():
 		u.Scheme = "ws"
 	default:
 		u.Scheme = "wss"
 	}
-	u.Host = c.config.Host
+	u.Host = c.host()
 	u.Path = c.config.Endpoint
 	return u.String()
 }
 
+// host returns the address of the RPC server the client is currently using.
+//
+// This function is safe for concurrent access.
+func (c *Client) host() string {
+	c.mtx.Lock()
+	host := c.config.hosts()[c.hostIdx]
+	c.mtx.Unlock()
+	return host
+}
+
+// failover switches the client to the next configured RPC server when the
+// provided host, which was found to be unreachable, is still the one the client
+// is currently using.  This prevents concurrent failures against the same host
+// from skipping over servers.
+//
+// This function is safe for concurrent access.
+func (c *Client) failover(failedHost string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	hosts := c.config.hosts()
+	if len(hosts) == 1 || hosts[c.hostIdx] != failedHost {
+		return
+	}
+	c.hostIdx = (c.hostIdx + 1) % len(hosts)
+	log.Infof("Failing over from RPC server %s to %s", failedHost,
+		hosts[c.hostIdx])
+}
+
 // NextID returns the next id to be used when sending a JSON-RPC message.  This
 // ID allows responses to be associated with particular requests per the
 // JSON-RPC specification.  Typically the consumer of the client does not need
@@ -276,6 +346,9 @@ func (c *Client) trackRegisteredNtfns(cmd interface{}) {
 	case *chainjson.NotifyWinningTicketsCmd:
 		c.ntfnState.notifyWinningTickets = true
 
+	case *chainjson.NotifyDiskSpaceCmd:
+		c.ntfnState.notifyDiskSpace = true
+
 	case *chainjson.NotifySpentAndMissedTicketsCmd:
 		c.ntfnState.notifySpentAndMissedTickets = true
 
@@ -286,7 +359,14 @@ func (c *Client) trackRegisteredNtfns(cmd interface{}) {
 		c.ntfnState.notifyStakeDifficulty = true
 
 	case *chainjson.NotifyBlocksCmd:
-		c.ntfnState.notifyBlocks = true
+		if bcmd.StakeEvents != nil && *bcmd.StakeEvents {
+			c.ntfnState.notifyBlocksStakeEvents = true
+		} else {
+			c.ntfnState.notifyBlocks = true
+		}
+
+	case *chainjson.NotifyFilteredBlocksCmd:
+		c.ntfnState.notifyFilteredBlocks = true
 
 	case *chainjson.NotifyNewTransactionsCmd:
 		if bcmd.Verbose != nil && *bcmd.Verbose {
@@ -297,6 +377,26 @@ func (c *Client) trackRegisteredNtfns(cmd interface{}) {
 
 	case *chainjson.NotifyWorkCmd:
 		c.ntfnState.notifyWork = true
+
+	case *chainjson.LoadTxFilterCmd:
+		if bcmd.Reload || c.ntfnState.txFilter == nil {
+			c.ntfnState.txFilter = newTxFilterState()
+		}
+		c.ntfnState.txFilter.add(bcmd)
+
+	case *chainjson.StopNotifyBlocksCmd:
+		c.ntfnState.notifyBlocks = false
+		c.ntfnState.notifyBlocksStakeEvents = false
+
+	case *chainjson.StopNotifyFilteredBlocksCmd:
+		c.ntfnState.notifyFilteredBlocks = false
+
+	case *chainjson.StopNotifyNewTransactionsCmd:
+		c.ntfnState.notifyNewTx = false
+		c.ntfnState.notifyNewTxVerbose = false
+
+	case *chainjson.StopNotifyWorkCmd:
+		c.ntfnState.notifyWork = false
 	}
 }
 
@@ -334,12 +434,12 @@ type response struct {
 }
 
 // result checks whether the unmarshaled response contains a non-nil error,
-// returning an unmarshaled dcrjson.RPCError (or an unmarshaling error) if so.
-// If the response is not an error, the raw bytes of the request are
-// returned for further unmarshalling into specific result types.
+// returning it as an RPCError if so.  If the response is not an error, the raw
+// bytes of the request are returned for further unmarshalling into specific
+// result types.
 func (r rawResponse) result() (result []byte, err error) {
 	if r.Error != nil {
-		return nil, r.Error
+		return nil, newRPCError(r.Error)
 	}
 	return r.Result, nil
 }
@@ -374,9 +474,9 @@ func (c *Client) handleMessage(msg []byte) {
 			log.Warn("Malformed notification: missing params")
 			return
 		}
-		// Deliver the notification.
+		// Queue the notification for delivery.
 		log.Tracef("Received notification [%s]", in.Method)
-		c.handleNotification(in.rawNotification)
+		c.queueNotification(in.rawNotification)
 		return
 	}
 
@@ -457,7 +557,7 @@ out:
 			// Log the error if it's not due to disconnecting.
 			if c.shouldLogReadError(err) {
 				log.Errorf("Websocket receive error from "+
-					"%s: %v", c.config.Host, err)
+					"%s: %v", c.host(), err)
 			}
 			break out
 		}
@@ -470,6 +570,68 @@ out:
 	log.Tracef("RPC client input handler done for %s", c.config.Host)
 }
 
+// queueNotification queues the passed notification for delivery to the
+// notification handlers by the notification handler goroutine.  Notifications
+// received once the client is shutting down are dropped.
+func (c *Client) queueNotification(ntfn *rawNotification) {
+	// Nothing to do if the caller is not interested in notifications.
+	if c.ntfnHandlers == nil {
+		return
+	}
+
+	select {
+	case c.ntfnChan <- ntfn:
+	case <-c.shutdown:
+	}
+}
+
+// ntfnHandler delivers queued notifications to the notification handlers in
+// the order they were received.  Once the client is shutdown, any notifications
+// that are still queued continue to be delivered until either the queue is
+// empty or the configured drain timeout elapses, at which point the remaining
+// notifications are dropped.  It must be run as a goroutine.
+func (c *Client) ntfnHandler() {
+out:
+	for {
+		select {
+		case ntfn := <-c.ntfnChan:
+			c.handleNotification(ntfn)
+
+		case <-c.shutdown:
+			break out
+		}
+	}
+
+	drainTimeout := c.config.NtfnDrainTimeout
+	if drainTimeout == 0 {
+		drainTimeout = defaultNtfnDrainTimeout
+	}
+	deadline := time.NewTimer(drainTimeout)
+	defer deadline.Stop()
+drain:
+	for drainTimeout > 0 {
+		select {
+		case <-deadline.C:
+			break drain
+		default:
+		}
+
+		select {
+		case ntfn := <-c.ntfnChan:
+			c.handleNotification(ntfn)
+		default:
+			break drain
+		}
+	}
+	if n := len(c.ntfnChan); n > 0 {
+		log.Warnf("Dropping %d undelivered notifications from %s", n,
+			c.config.Host)
+	}
+	c.wg.Done()
+	log.Tracef("RPC client notification handler done for %s",
+		c.config.Host)
+}
+
 // disconnectChan returns a copy of the current disconnect channel.  The channel
 // is read protected by the client mutex, and is safe to call while the channel
 // is being reassigned during a reconnect.
@@ -531,12 +693,13 @@ func (c *Client) sendMessage(marshalledJSON []byte) {
 }
 
 // reregisterNtfns creates and sends commands needed to re-establish the current
-// notification state associated with the client.  It should only be called on
-// on reconnect by the resendRequests function.
-func (c *Client) reregisterNtfns(ctx context.Context) error {
+// notification state associated with the client, including the transaction
+// filter, and returns the methods of the registrations that were replayed.  It
+// should only be called on on reconnect by the resendRequests function.
+func (c *Client) reregisterNtfns(ctx context.Context) ([]string, error) {
 	// Nothing to do if the caller is not interested in notifications.
 	if c.ntfnHandlers == nil {
-		return nil
+		return nil, nil
 	}
 
 	// In order to avoid holding the lock on the notification state for the
@@ -551,52 +714,75 @@ func (c *Client) reregisterNtfns(ctx context.Context) error {
 	stateCopy := c.ntfnState.Copy()
 	c.ntfnStateLock.Unlock()
 
+	var replayed []string
+
 	// Reregister notifyblocks if needed.
-	if stateCopy.notifyBlocks {
+	if stateCopy.notifyBlocksStakeEvents {
+		log.Debugf("Reregistering [notifyblocks] (stakeevents=true)")
+		if err := c.NotifyBlocksStakeEvents(ctx); err != nil {
+			return nil, err
+		}
+		replayed = append(replayed, "notifyblocks")
+	} else if stateCopy.notifyBlocks {
 		log.Debugf("Reregistering [notifyblocks]")
 		if err := c.NotifyBlocks(ctx); err != nil {
-			return err
+			return nil, err
 		}
+		replayed = append(replayed, "notifyblocks")
+	}
+
+	// Reregister notifyfilteredblocks if needed.
+	if stateCopy.notifyFilteredBlocks {
+		log.Debugf("Reregistering [notifyfilteredblocks]")
+		if err := c.NotifyFilteredBlocks(ctx); err != nil {
+			return nil, err
+		}
+		replayed = append(replayed, "notifyfilteredblocks")
 	}
 
 	// Reregister notifywork if needed.
 	if stateCopy.notifyWork {
 		log.Debugf("Reregistering [notifywork]")
 		if err := c.NotifyWork(ctx); err != nil {
-			return err
+			return nil, err
 		}
+		replayed = append(replayed, "notifywork")
 	}
 
 	// Reregister notifywinningtickets if needed.
 	if stateCopy.notifyWinningTickets {
 		log.Debugf("Reregistering [notifywinningtickets]")
 		if err := c.NotifyWinningTickets(ctx); err != nil {
-			return err
+			return nil, err
 		}
+		replayed = append(replayed, "notifywinningtickets")
 	}
 
 	// Reregister notifyspendandmissedtickets if needed.
 	if stateCopy.notifySpentAndMissedTickets {
 		log.Debugf("Reregistering [notifyspentandmissedtickets]")
 		if err := c.NotifySpentAndMissedTickets(ctx); err != nil {
-			return err
+			return nil, err
 		}
+		replayed = append(replayed, "notifyspentandmissedtickets")
 	}
 
 	// Reregister notifynewtickets if needed.
 	if stateCopy.notifyNewTickets {
 		log.Debugf("Reregistering [notifynewtickets]")
 		if err := c.NotifyNewTickets(ctx); err != nil {
-			return err
+			return nil, err
 		}
+		replayed = append(replayed, "notifynewtickets")
 	}
 
 	// Reregister notifystakedifficulty if needed.
 	if stateCopy.notifyStakeDifficulty {
 		log.Debugf("Reregistering [notifystakedifficulty]")
 		if err := c.NotifyStakeDifficulty(ctx); err != nil {
-			return err
+			return nil, err
 		}
+		replayed = append(replayed, "notifystakedifficulty")
 	}
 
 	// Reregister notifynewtransactions if needed.
@@ -605,11 +791,33 @@ func (c *Client) reregisterNtfns(ctx context.Context) error {
 			stateCopy.notifyNewTxVerbose)
 		err := c.NotifyNewTransactions(ctx, stateCopy.notifyNewTxVerbose)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		replayed = append(replayed, "notifynewtransactions")
 	}
 
-	return nil
+	// Reregister notifydiskspace if needed.
+	if stateCopy.notifyDiskSpace {
+		log.Debugf("Reregistering [notifydiskspace]")
+		if err := c.NotifyDiskSpace(ctx); err != nil {
+			return nil, err
+		}
+		replayed = append(replayed, "notifydiskspace")
+	}
+
+	// Reload the transaction filter if needed.
+	if stateCopy.txFilter != nil {
+		cmd := stateCopy.txFilter.loadCmd()
+		log.Debugf("Reregistering [loadtxfilter] (addresses=%d, outpoints=%d)",
+			len(cmd.Addresses), len(cmd.OutPoints))
+		err := (*FutureLoadTxFilterResult)(c.sendCmd(ctx, cmd)).Receive()
+		if err != nil {
+			return nil, err
+		}
+		replayed = append(replayed, "loadtxfilter")
+	}
+
+	return replayed, nil
 }
 
 // ignoreResends is a set of all methods for requests that are "long running"
@@ -624,12 +832,26 @@ var ignoreResends = map[string]struct{}{
 func (c *Client) resendRequests(ctx context.Context) {
 	// Set the notification state back up.  If anything goes wrong,
 	// disconnect the client.
-	if err := c.reregisterNtfns(ctx); err != nil {
+	replayed, err := c.reregisterNtfns(ctx)
+	if err != nil {
 		log.Warnf("Unable to re-establish notification state: %v", err)
 		c.Disconnect()
 		return
 	}
 
+	// Notify the caller that the notification state has been replayed so
+	// any state derived from notifications can be resynchronized.
+	if c.ntfnHandlers != nil && c.ntfnHandlers.OnRegistrationReplayed != nil {
+		if replayed == nil {
+			replayed = []string{}
+		}
+		c.wg.Add(1)
+		go func() {
+			c.ntfnHandlers.OnRegistrationReplayed(replayed)
+			c.wg.Done()
+		}()
+	}
+
 	// Since it's possible to block on send and more requests might be
 	// added by the caller while resending, make a copy of all of the
 	// requests that need to be resent now and work from the copy.  This
@@ -685,6 +907,9 @@ out:
 		case <-c.disconnectChan():
 			// On disconnect, fallthrough to reestablish the
 			// connection.
+			if m := c.config.Metrics; m != nil {
+				m.disconnected()
+			}
 
 		case <-c.shutdown:
 			break out
@@ -698,11 +923,19 @@ out:
 			default:
 			}
 
-			wsConn, err := dial(c.config)
+			// Attempt to connect to each of the configured RPC
+			// servers, starting with the one that was most recently
+			// in use, and only back off once all of them have been
+			// tried.
+			c.mtx.Lock()
+			startIdx := c.hostIdx
+			c.mtx.Unlock()
+			wsConn, hostIdx, err := dialHosts(c.config, startIdx)
+			if m := c.config.Metrics; m != nil {
+				m.reconnectAttempted(err == nil)
+			}
 			if err != nil {
 				retryCount++
-				log.Infof("Failed to connect to %s: %v",
-					c.config.Host, err)
 
 				// Scale the retry interval by the number of
 				// retries so there is a backoff up to a max
@@ -713,18 +946,22 @@ out:
 					scaledDuration = time.Minute
 				}
 				log.Infof("Retrying connection to %s in "+
-					"%s", c.config.Host, scaledDuration)
+					"%s", c.host(), scaledDuration)
 				time.Sleep(scaledDuration)
 				continue reconnect
 			}
 
+			host := c.config.hosts()[hostIdx]
 			log.Infof("Reestablished connection to RPC server %s",
-				c.config.Host)
+				host)
 
 			// Reset the connection state and signal the reconnect
-			// has happened.
+			// has happened.  Any previously registered
+			// notifications are registered with the new server when
+			// the pending requests are resent.
 			retryCount = 0
 			c.mtx.Lock()
+			c.hostIdx = hostIdx
 			c.wsConn = wsConn
 			c.disconnect = make(chan struct{})
 			c.disconnected = false
@@ -744,7 +981,7 @@ out:
 		}
 	}
 	c.wg.Done()
-	log.Tracef("RPC client reconnect handler done for %s", c.config.Host)
+	log.Tracef("RPC client reconnect handler done for %s", c.host())
 }
 
 // handleSendPostMessage handles performing the passed HTTP request, reading the
@@ -753,8 +990,26 @@ out:
 func (c *Client) handleSendPostMessage(details *sendPostDetails) {
 	jReq := details.jsonRequest
 	log.Tracef("Sending command [%s] with id %d", jReq.method, jReq.id)
-	httpResponse, err := c.httpClient.Do(details.httpRequest)
+
+	// Abort the request when the client is shutdown while it is in flight
+	// so the caller is not left waiting on a reply that will never be
+	// delivered.
+	ctx, cancel := context.WithCancel(details.httpRequest.Context())
+	defer cancel()
+	go func() {
+		select {
+		case <-c.shutdown:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	httpResponse, err := c.doPostFailover(ctx, details.httpRequest)
 	if err != nil {
+		select {
+		case <-c.shutdown:
+			err = ErrClientShutdown
+		default:
+		}
 		jReq.responseChan <- &response{err: err}
 		return
 	}
@@ -763,7 +1018,7 @@ func (c *Client) handleSendPostMessage(details *sendPostDetails) {
 	respBytes, err := ioutil.ReadAll(httpResponse.Body)
 	httpResponse.Body.Close()
 	if err != nil {
-		err = fmt.Errorf("error reading json reply: %v", err)
+		err = fmt.Errorf("error reading json reply: %w", err)
 		jReq.responseChan <- &response{err: err}
 		return
 	}
@@ -775,8 +1030,10 @@ func (c *Client) handleSendPostMessage(details *sendPostDetails) {
 		// When the response itself isn't a valid JSON-RPC response
 		// return an error which includes the HTTP status code and raw
 		// response bytes.
-		err = fmt.Errorf("status code: %d, response: %q",
-			httpResponse.StatusCode, string(respBytes))
+		err = &HTTPStatusError{
+			StatusCode: httpResponse.StatusCode,
+			Response:   respBytes,
+		}
 		jReq.responseChan <- &response{err: err}
 		return
 	}
@@ -785,10 +1042,41 @@ func (c *Client) handleSendPostMessage(details *sendPostDetails) {
 	jReq.responseChan <- &response{result: res, err: err}
 }
 
+// doPostFailover performs the passed HTTP request against the RPC server the
+// client is currently using and, when it is unreachable, fails over to the
+// remaining configured RPC servers in turn until one of them responds or all of
+// them have been tried.
+func (c *Client) doPostFailover(ctx context.Context, httpReq *http.Request) (*http.Response, error) {
+	host := httpReq.URL.Host
+	httpResponse, err := c.httpClient.Do(httpReq.WithContext(ctx))
+	for i := 1; err != nil && i < len(c.config.hosts()); i++ {
+		// Don't fail over when the request was canceled.
+		if ctx.Err() != nil {
+			break
+		}
+		log.Infof("Failed to connect to %s: %v", host, err)
+		c.failover(host)
+
+		// Reissue the request to the next server with a fresh copy of
+		// the body since the previous attempt consumed it.
+		host = c.host()
+		retryReq := httpReq.Clone(ctx)
+		retryReq.URL.Host = host
+		retryReq.Host = host
+		retryReq.Body, err = httpReq.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		httpResponse, err = c.httpClient.Do(retryReq)
+	}
+	return httpResponse, err
+}
+
 // sendPostHandler handles all outgoing messages when the client is running
 // in HTTP POST mode.  It uses a buffered channel to serialize output messages
-// while allowing the sender to continue running asynchronously.  It must be run
-// as a goroutine.
+// while allowing the sender to continue running asynchronously.  Multiple
+// handlers service the channel concurrently when the client is configured with
+// a connection pool.  It must be run as a goroutine.
 func (c *Client) sendPostHandler() {
 out:
 	for {
@@ -805,7 +1093,15 @@ out:
 
 	// Drain any wait channels before exiting so nothing is left waiting
 	// around to send.
-cleanup:
+	c.drainSendPostChan()
+	c.wg.Done()
+	log.Tracef("RPC client send handler done for %s", c.config.Host)
+
+}
+
+// drainSendPostChan sends ErrClientShutdown to all requests that are queued on
+// the send channel.  It is only used once the client is shutdown.
+func (c *Client) drainSendPostChan() {
 	for {
 		select {
 		case details := <-c.sendPostChan:
@@ -815,29 +1111,48 @@ cleanup:
 			}
 
 		default:
-			break cleanup
+			return
 		}
 	}
-	c.wg.Done()
-	log.Tracef("RPC client send handler done for %s", c.config.Host)
-
 }
 
 // sendPostRequest sends the passed HTTP request to the RPC server using the
 // HTTP client associated with the client.  It is backed by a buffered channel,
-// so it will not block until the send channel is full.
+// so it will not block until the send channel is full, and it stops blocking
+// once the client is shutdown.
 func (c *Client) sendPostRequest(httpReq *http.Request, jReq *jsonRequest) {
-	// Don't send the message if shutting down.
+	// Don't send the message if shutting down.  The request lock is only
+	// held for the check so Shutdown is never blocked by a full send
+	// channel.
+	c.requestLock.Lock()
 	select {
 	case <-c.shutdown:
+		c.requestLock.Unlock()
 		jReq.responseChan <- &response{result: nil, err: ErrClientShutdown}
+		return
 	default:
 	}
+	c.requestLock.Unlock()
 
-	c.sendPostChan <- &sendPostDetails{
+	details := &sendPostDetails{
 		jsonRequest: jReq,
 		httpRequest: httpReq,
 	}
+	select {
+	case c.sendPostChan <- details:
+	case <-c.shutdown:
+		jReq.responseChan <- &response{result: nil, err: ErrClientShutdown}
+		return
+	}
+
+	// The send handler might have already drained the send channel when
+	// the client was shutdown while the request was being queued, so drain
+	// it again in that case to ensure the request is not left waiting.
+	select {
+	case <-c.shutdown:
+		c.drainSendPostChan()
+	default:
+	}
 }
 
 // newFutureError returns a new future result channel that already has the
@@ -855,13 +1170,72 @@ func newFutureError(ctx context.Context, err error) *cmdRes {
 // until the result is available on the passed channel or the passed context
 // is done.
 func receiveFuture(ctx context.Context, f chan *response) ([]byte, error) {
-	// Wait for a response on the returned channel or context done.
+	// Wait for a response on the returned channel or context done.  Errors
+	// that result from the context being done while the request was in
+	// flight are replaced so callers can reliably detect timeouts.
 	select {
 	case r := <-f:
+		if r.err != nil && ctx.Err() != nil {
+			return nil, contextError(ctx)
+		}
 		return r.result, r.err
 	case <-ctx.Done():
-		return nil, ErrRequestCanceled
+		return nil, contextError(ctx)
+	}
+}
+
+// contextError returns the error that describes why a request made with the
+// passed context, which must be done, did not complete.
+func contextError(ctx context.Context) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return ErrRequestTimeout
+	}
+	return ErrRequestCanceled
+}
+
+// requestContext returns the context to make a request with given the context
+// passed by the caller.  The default request timeout of the client is applied
+// when it is configured and the passed context does not already have a
+// deadline, in which case the returned cancel function is non-nil.
+func (c *Client) requestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.config.RequestTimeout <= 0 {
+		return ctx, nil
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, nil
 	}
+	return context.WithTimeout(ctx, c.config.RequestTimeout)
+}
+
+// watchRequest returns the response channel to send the request with the
+// passed id on given the channel the caller receives the response from.  When
+// the passed context can be done, the returned channel is serviced by a
+// goroutine that forwards the response to the caller and, should the context
+// be done first, instead stops tracking the request so that it is not re-issued
+// and fails it with the reason the context is done.  The cancel function, when
+// non-nil, is invoked once the request is complete.
+func (c *Client) watchRequest(ctx context.Context, cancel context.CancelFunc, id uint64, responseChan chan *response) chan *response {
+	if ctx.Done() == nil {
+		return responseChan
+	}
+
+	watchChan := make(chan *response, 1)
+	go func() {
+		select {
+		case r := <-watchChan:
+			responseChan <- r
+		case <-ctx.Done():
+			// Requests sent via HTTP POST are not tracked and are
+			// failed by the HTTP client instead.
+			if jReq := c.removeRequest(id); jReq != nil {
+				jReq.responseChan <- &response{err: contextError(ctx)}
+			}
+		}
+		if cancel != nil {
+			cancel()
+		}
+	}()
+	return watchChan
 }
 
 // sendPost sends the passed request to the server by issuing an HTTP POST
@@ -872,17 +1246,17 @@ func receiveFuture(ctx context.Context, f chan *response) ([]byte, error) {
 func (c *Client) sendPost(ctx context.Context, jReq *jsonRequest) {
 	// Generate a request to the configured RPC server.
 	protocol := "http"
-	if !c.config.DisableTLS {
+	if c.config.useTLS() {
 		protocol = "https"
 	}
-	url := protocol + "://" + c.config.Host
+	url := protocol + "://" + c.host()
 	bodyReader := bytes.NewReader(jReq.marshalledJSON)
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bodyReader)
 	if err != nil {
 		jReq.responseChan <- &response{result: nil, err: err}
 		return
 	}
-	httpReq.Close = true
+	httpReq.Close = !c.config.pooled()
 	httpReq.Header.Set("Content-Type", "application/json")
 
 	// Configure basic access authorization.
@@ -896,6 +1270,12 @@ func (c *Client) sendPost(ctx context.Context, jReq *jsonRequest) {
 // provided response channel for the reply.  It handles both websocket and HTTP
 // POST mode depending on the configuration of the client.
 func (c *Client) sendRequest(ctx context.Context, jReq *jsonRequest) {
+	// Give the interceptors a chance to examine and modify the request.
+	if err := c.interceptRequest(ctx, jReq); err != nil {
+		jReq.responseChan <- &response{err: err}
+		return
+	}
+
 	// Choose which marshal and send function to use depending on whether
 	// the client running in HTTP POST mode or not.  When running in HTTP
 	// POST mode, the command is issued via an HTTP client.  Otherwise,
@@ -945,15 +1325,17 @@ func (c *Client) sendCmd(ctx context.Context, cmd interface{}) *cmdRes {
 	}
 
 	// Generate the request and send it along with a channel to respond on.
+	ctx, cancel := c.requestContext(ctx)
 	responseChan := make(chan *response, 1)
 	jReq := &jsonRequest{
 		id:             id,
 		method:         method,
 		cmd:            cmd,
 		marshalledJSON: marshalledJSON,
-		responseChan:   responseChan,
+		responseChan: c.validateResult(ctx, method, c.observeRequest(method,
+			c.watchRequest(ctx, cancel, id, responseChan))),
 	}
-	c.sendRequest(ctx, jReq)
+	c.sendRequestWithRetry(ctx, jReq)
 
 	return &cmdRes{ctx: ctx, c: responseChan}
 }
@@ -1049,6 +1431,17 @@ func (c *Client) Disconnect() {
 // Shutdown shuts down the client by disconnecting any connections associated
 // with the client and, when automatic reconnect is enabled, preventing future
 // attempts to reconnect.  It also stops all goroutines.
+//
+// All outstanding futures, including HTTP POST requests that are in flight,
+// return ErrClientShutdown as do any requests made after Shutdown is called.
+// Notifications that were received prior to shutting down, but not yet
+// delivered, continue to be delivered to the notification handlers for up to
+// the NtfnDrainTimeout specified in the connection configuration.
+//
+// Shutdown does not block.  Use WaitForShutdown to wait for the client
+// goroutines to finish.
+//
+// This function is safe for concurrent access.
 func (c *Client) Shutdown() {
 	// Do the shutdown under the request lock to prevent clients from
 	// adding new requests while the client shutdown process is initiated.
@@ -1082,8 +1475,16 @@ func (c *Client) start() {
 	// Start the I/O processing handlers depending on whether the client is
 	// in HTTP POST mode or the default websocket mode.
 	if c.config.HTTPPostMode {
-		c.wg.Add(1)
-		go c.sendPostHandler()
+		// Issue requests concurrently from multiple handlers when
+		// configured with a connection pool.
+		numHandlers := 1
+		if c.config.pooled() {
+			numHandlers = c.config.MaxConns
+		}
+		c.wg.Add(numHandlers)
+		for i := 0; i < numHandlers; i++ {
+			go c.sendPostHandler()
+		}
 	} else {
 		c.wg.Add(3)
 		go func() {
@@ -1096,13 +1497,42 @@ func (c *Client) start() {
 		}()
 		go c.wsInHandler()
 		go c.wsOutHandler()
+
+		// Identify the client to the server when configured.
+		if c.config.ClientName != "" {
+			c.wg.Add(1)
+			go func() {
+				err := c.RegisterClient(context.Background(),
+					c.config.ClientName, c.config.ClientVersion)
+				if err != nil && !errors.Is(err, ErrClientShutdown) &&
+					!errors.Is(err, ErrClientDisconnect) {
+
+					log.Warnf("Unable to register client with %s: %v",
+						c.host(), err)
+				}
+				c.wg.Done()
+			}()
+		}
 	}
 }
 
-// WaitForShutdown blocks until the client goroutines are stopped and the
-// connection is closed.
-func (c *Client) WaitForShutdown() {
-	c.wg.Wait()
+// WaitForShutdown blocks until the client goroutines are stopped, which
+// includes delivering any remaining notifications, and the connection is
+// closed, or the passed context is done.  The context error is returned when
+// the context is done before the shutdown completes.
+func (c *Client) WaitForShutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // ConnConfig describes the connection configuration parameters for the client.
@@ -1112,6 +1542,14 @@ type ConnConfig struct {
 	// to.
 	Host string
 
+	// FailoverHosts are the IP addresses and ports of additional RPC
+	// servers the client transparently fails over to, in order, when the
+	// server it is currently using becomes unreachable.  The servers must
+	// all accept the same endpoint, credentials, and certificates as Host.
+	// In websocket mode, all previously registered notifications are
+	// registered with the new server once the client has failed over.
+	FailoverHosts []string
+
 	// Endpoint is the websocket endpoint on the RPC server.  This is
 	// typically "ws".
 	Endpoint string
@@ -1133,8 +1571,11 @@ type ConnConfig struct {
 	// is true.
 	Certificates []byte
 
-	// Proxy specifies to connect through a SOCKS 5 proxy server.  It may
-	// be an empty string if a proxy is not required.
+	// Proxy specifies the host:port of a SOCKS 5 proxy server to connect
+	// through in both HTTP POST and websocket modes, such as the SOCKS port
+	// of a Tor daemon.  Host names, including onion addresses, are resolved
+	// by the proxy server.  It may be an empty string if a proxy is not
+	// required.
 	Proxy string
 
 	// ProxyUser is an optional username to use for the proxy server if it
@@ -1164,24 +1605,146 @@ type ConnConfig struct {
 	// however, not all servers support the websocket extensions, so this
 	// flag can be set to true to use basic HTTP POST requests instead.
 	HTTPPostMode bool
+
+	// MaxConns is the maximum number of requests that are issued
+	// concurrently, and therefore the maximum number of connections to the
+	// RPC servers, when running in HTTP POST mode.  Zero or one selects the
+	// default of issuing requests one at a time over a new connection each.
+	// Larger values also keep the connections alive for reuse, which allows
+	// high-throughput tooling to issue concurrent requests with a single
+	// client.  Concurrent requests may complete in any order.  It has no
+	// effect in websocket mode.
+	MaxConns int
+
+	// MaxIdleConns is the maximum number of idle connections that are kept
+	// alive for reuse across all RPC servers when MaxConns is greater than
+	// one.  Zero selects MaxConns.
+	MaxIdleConns int
+
+	// MaxConnsPerHost is the maximum number of connections to each RPC
+	// server when MaxConns is greater than one.  Zero means the connections
+	// are only limited by MaxConns.
+	MaxConnsPerHost int
+
+	// ClientName and ClientVersion optionally identify the software of
+	// the client to the RPC server.  When ClientName is set, the client
+	// is registered with the server via the registerclient RPC each time
+	// the websocket connection is established so that operators can
+	// identify it.  They have no effect in HTTP POST mode.
+	ClientName    string
+	ClientVersion string
+
+	// NtfnDrainTimeout is the maximum amount of time spent delivering
+	// notifications that were received, but not yet delivered to the
+	// notification handlers, once the client is shutdown.  Zero selects
+	// a default of 5 seconds.  A negative value drops any undelivered
+	// notifications immediately.
+	NtfnDrainTimeout time.Duration
+
+	// RequestTimeout is the default maximum amount of time to wait for the
+	// response to a request made with a context that does not have a
+	// deadline.  Requests that do not complete in time fail with
+	// ErrRequestTimeout.  Zero or a negative value means requests made with
+	// such contexts wait for a response indefinitely.
+	RequestTimeout time.Duration
+
+	// SocketPath is the path of a unix domain socket the RPC server listens
+	// on.  When set, all connections are made over the socket in both HTTP
+	// POST and websocket modes instead of to Host, transport layer
+	// security and the proxy are not used, and failover hosts are ignored.
+	// Host is then only used as the host of the requests and defaults to
+	// localhost when empty.
+	SocketPath string
+
+	// RetryPolicy optionally configures requests that fail due to
+	// transient errors, such as connection failures and internal server
+	// errors, to be retried with exponential backoff.  The request timeout
+	// applies to all attempts of a request combined.  Nil disables
+	// retries.
+	RetryPolicy *RetryPolicy
+
+	// Metrics optionally specifies a registry to record the per-method
+	// request counts, error counts, in-flight requests, and latencies of
+	// the client along with its websocket reconnects in.  The same registry
+	// may be shared by multiple clients.  Nil disables metrics.
+	Metrics *Metrics
+
+	// StrictResults specifies that the results of requests made via the
+	// methods of the client should be strictly validated against the
+	// result types expected for the method.  Results that contain unknown
+	// fields, numbers that overflow their type, or otherwise do not match
+	// fail with a ResultSchemaError which reports the JSON-RPC API version
+	// of the RPC server when it differs from the version the client
+	// corresponds to.  This aids debugging when the client is used with
+	// mismatched versions of the RPC server.  Results of RawRequest are not
+	// validated.
+	StrictResults bool
+
+	// WalletMethods enables the methods of the client which wrap the
+	// commonly used RPCs of dcrwallet, such as GetBalance and
+	// SendToAddress, for use with dcrwallet or a server which proxies its
+	// RPCs.  The methods fail with ErrWalletMethodsDisabled when it is not
+	// set.
+	WalletMethods bool
 }
 
-// newHTTPClient returns a new http client that is configured according to the
-// proxy and TLS settings in the associated connection configuration.
-func newHTTPClient(config *ConnConfig) (*http.Client, error) {
-	// Set proxy function if there is a proxy configured.
-	var proxyFunc func(*http.Request) (*url.URL, error)
-	if config.Proxy != "" {
-		proxyURL, err := url.Parse(config.Proxy)
-		if err != nil {
-			return nil, err
+// hosts returns the IP addresses and ports of all of the configured RPC servers
+// in the order they are tried.
+func (config *ConnConfig) hosts() []string {
+	if config.SocketPath != "" {
+		if config.Host == "" {
+			return []string{"localhost"}
 		}
-		proxyFunc = http.ProxyURL(proxyURL)
+		return []string{config.Host}
+	}
+	if len(config.FailoverHosts) == 0 {
+		return []string{config.Host}
+	}
+	hosts := make([]string, 0, len(config.FailoverHosts)+1)
+	hosts = append(hosts, config.Host)
+	return append(hosts, config.FailoverHosts...)
+}
+
+// useTLS returns whether or not connections to the RPC server use transport
+// layer security.  Connections over a unix socket never do.
+func (config *ConnConfig) useTLS() bool {
+	return !config.DisableTLS && config.SocketPath == ""
+}
+
+// dialSocket opens a connection to the unix socket of the RPC server.  The
+// network and address that would otherwise be dialed are ignored.
+func (config *ConnConfig) dialSocket(ctx context.Context, _, _ string) (net.Conn, error) {
+	var d net.Dialer
+	return d.DialContext(ctx, "unix", config.SocketPath)
+}
+
+// pooled returns whether or not the client issues concurrent HTTP POST
+// requests over a pool of connections that are kept alive for reuse.
+func (config *ConnConfig) pooled() bool {
+	return config.MaxConns > 1
+}
+
+// proxy returns the SOCKS 5 proxy connections to the RPC server are made
+// through according to the proxy settings in the connection configuration or
+// nil when no proxy is configured.
+func (config *ConnConfig) proxy() *socks.Proxy {
+	if config.Proxy == "" {
+		return nil
+	}
+	return &socks.Proxy{
+		Addr:     config.Proxy,
+		Username: config.ProxyUser,
+		Password: config.ProxyPass,
 	}
+}
 
+// newHTTPClient returns a new http client that is configured according to the
+// proxy, TLS, and connection pool settings in the associated connection
+// configuration.
+func newHTTPClient(config *ConnConfig) (*http.Client, error) {
 	// Configure TLS if needed.
 	var tlsConfig *tls.Config
-	if !config.DisableTLS {
+	if config.useTLS() {
 		if len(config.Certificates) > 0 {
 			pool := x509.NewCertPool()
 			pool.AppendCertsFromPEM(config.Certificates)
@@ -1191,23 +1754,38 @@ func newHTTPClient(config *ConnConfig) (*http.Client, error) {
 		}
 	}
 
-	client := http.Client{
-		Transport: &http.Transport{
-			Proxy:           proxyFunc,
-			TLSClientConfig: tlsConfig,
-		},
+	transport := &http.Transport{
+		TLSClientConfig: tlsConfig,
 	}
 
+	// Dial connections over the unix socket or through the proxy if either
+	// is configured.
+	if config.SocketPath != "" {
+		transport.DialContext = config.dialSocket
+	} else if proxy := config.proxy(); proxy != nil {
+		transport.DialContext = proxy.DialContext
+	}
+	if config.pooled() {
+		maxIdleConns := config.MaxIdleConns
+		if maxIdleConns == 0 {
+			maxIdleConns = config.MaxConns
+		}
+		transport.MaxIdleConns = maxIdleConns
+		transport.MaxIdleConnsPerHost = maxIdleConns
+		transport.MaxConnsPerHost = config.MaxConnsPerHost
+	}
+	client := http.Client{Transport: transport}
+
 	return &client, nil
 }
 
-// dial opens a websocket connection using the passed connection configuration
-// details.
-func dial(config *ConnConfig) (*websocket.Conn, error) {
+// dial opens a websocket connection to the RPC server at the provided host using
+// the passed connection configuration details.
+func dial(config *ConnConfig, host string) (*websocket.Conn, error) {
 	// Setup TLS if not disabled.
 	var tlsConfig *tls.Config
 	var scheme = "ws"
-	if !config.DisableTLS {
+	if config.useTLS() {
 		tlsConfig = &tls.Config{
 			MinVersion: tls.VersionTLS12,
 		}
@@ -1223,13 +1801,13 @@ func dial(config *ConnConfig) (*websocket.Conn, error) {
 	// It is modified by the proxy setting below as needed.
 	dialer := websocket.Dialer{TLSClientConfig: tlsConfig}
 
-	// Setup the proxy if one is configured.
-	if config.Proxy != "" {
-		proxy := &socks.Proxy{
-			Addr:     config.Proxy,
-			Username: config.ProxyUser,
-			Password: config.ProxyPass,
+	// Connect over the unix socket or setup the proxy if either is
+	// configured.
+	if config.SocketPath != "" {
+		dialer.NetDial = func(network, addr string) (net.Conn, error) {
+			return config.dialSocket(context.Background(), network, addr)
 		}
+	} else if proxy := config.proxy(); proxy != nil {
 		dialer.NetDial = proxy.Dial
 	}
 
@@ -1241,7 +1819,7 @@ func dial(config *ConnConfig) (*websocket.Conn, error) {
 	requestHeader.Add("Authorization", auth)
 
 	// Dial the connection.
-	url := fmt.Sprintf("%s://%s/%s", scheme, config.Host, config.Endpoint)
+	url := fmt.Sprintf("%s://%s/%s", scheme, host, config.Endpoint)
 	wsConn, resp, err := dialer.Dial(url, requestHeader)
 	if resp != nil {
 		resp.Body.Close()
@@ -1271,6 +1849,26 @@ func dial(config *ConnConfig) (*websocket.Conn, error) {
 	return wsConn, nil
 }
 
+// dialHosts attempts to open a websocket connection to each of the configured
+// RPC servers in turn, starting with the one at the provided index within the
+// hosts of the passed connection configuration, until one of them succeeds.  It
+// returns the connection along with the index of the server it was opened to.
+// The error from the final attempt is returned when all of them fail.
+func dialHosts(config *ConnConfig, startIdx int) (*websocket.Conn, int, error) {
+	hosts := config.hosts()
+	var err error
+	for i := 0; i < len(hosts); i++ {
+		idx := (startIdx + i) % len(hosts)
+		var wsConn *websocket.Conn
+		wsConn, err = dial(config, hosts[idx])
+		if err == nil {
+			return wsConn, idx, nil
+		}
+		log.Infof("Failed to connect to %s: %v", hosts[idx], err)
+	}
+	return nil, 0, err
+}
+
 // keepAlive periodically sends ping messages to the server to keep the
 // established websocket connection alive.
 func (c *Client) keepAlive() {
@@ -1305,6 +1903,7 @@ func New(config *ConnConfig, ntfnHandlers *NotificationHandlers) (*Client, error
 	// on the HTTP POST mode.  Also, set the notification handlers to nil
 	// when running in HTTP POST mode.
 	var wsConn *websocket.Conn
+	var hostIdx int
 	var httpClient *http.Client
 	connEstablished := make(chan struct{})
 	var start bool
@@ -1320,7 +1919,7 @@ func New(config *ConnConfig, ntfnHandlers *NotificationHandlers) (*Client, error
 	} else {
 		if !config.DisableConnectOnNew {
 			var err error
-			wsConn, err = dial(config)
+			wsConn, hostIdx, err = dialHosts(config, 0)
 			if err != nil {
 				return nil, err
 			}
@@ -1331,11 +1930,13 @@ func New(config *ConnConfig, ntfnHandlers *NotificationHandlers) (*Client, error
 	client := &Client{
 		config:          config,
 		wsConn:          wsConn,
+		hostIdx:         hostIdx,
 		httpClient:      httpClient,
 		requestMap:      make(map[uint64]*list.Element),
 		requestList:     list.New(),
 		ntfnHandlers:    ntfnHandlers,
 		ntfnState:       newNotificationState(),
+		ntfnChan:        make(chan *rawNotification, ntfnBufferSize),
 		sendChan:        make(chan []byte, sendBufferSize),
 		sendPostChan:    make(chan *sendPostDetails, sendPostBufferSize),
 		connEstablished: connEstablished,
@@ -1343,8 +1944,16 @@ func New(config *ConnConfig, ntfnHandlers *NotificationHandlers) (*Client, error
 		shutdown:        make(chan struct{}),
 	}
 
+	// Start delivering notifications when running in websocket mode and
+	// the caller is interested in them.
+	if !config.HTTPPostMode && ntfnHandlers != nil {
+		client.wg.Add(1)
+		go client.ntfnHandler()
+	}
+
 	if start {
-		log.Infof("Established connection to RPC server %s", config.Host)
+		log.Infof("Established connection to RPC server %s",
+			config.hosts()[hostIdx])
 		close(connEstablished)
 		client.start()
 		if !client.config.HTTPPostMode && !client.config.DisableAutoReconnect {
@@ -1379,11 +1988,16 @@ func (c *Client) Connect(ctx context.Context, retry bool) error {
 		return ErrClientAlreadyConnected
 	}
 
-	// Shutdown the client on context cancellation.
+	// Shutdown the client on context cancellation.  The goroutine also
+	// exits when the client is shutdown by other means so it does not
+	// prevent WaitForShutdown from returning.
 	c.wg.Add(1)
 	go func() {
-		<-ctx.Done()
-		c.Shutdown()
+		select {
+		case <-ctx.Done():
+			c.Shutdown()
+		case <-c.shutdown:
+		}
 		c.wg.Done()
 	}()
 
@@ -1391,7 +2005,7 @@ func (c *Client) Connect(ctx context.Context, retry bool) error {
 	// attempt, up to a maximum of one minute.
 	var backoff time.Duration
 	for {
-		wsConn, err := dial(c.config)
+		wsConn, hostIdx, err := dialHosts(c.config, c.hostIdx)
 		if err != nil {
 			if !retry {
 				return err
@@ -1414,7 +2028,8 @@ func (c *Client) Connect(ctx context.Context, retry bool) error {
 		// member of the client and start the goroutines necessary
 		// to run the client.
 		log.Infof("Established connection to RPC server %s",
-			c.config.Host)
+			c.config.hosts()[hostIdx])
+		c.hostIdx = hostIdx
 		c.wsConn = wsConn
 		close(c.connEstablished)
 		c.start()
@@ -4,7 +4,25 @@
 
 package rpcclient
 
-import "testing"
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
 
 func TestClientStringer(t *testing.T) {
 	type test struct {
@@ -36,3 +54,614 @@ func TestClientStringer(t *testing.T) {
 		}
 	}
 }
+
+// newTestHTTPClient returns a test server that serves requests with the passed
+// handler along with a client that makes requests to it in HTTP POST mode.  The
+// passed connection config may be nil to use the defaults.  Its host, HTTP POST
+// mode, and TLS settings are always overridden.
+func newTestHTTPClient(t *testing.T, handler http.HandlerFunc, cfg *ConnConfig) (*httptest.Server, *Client) {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	if cfg == nil {
+		cfg = &ConnConfig{}
+	}
+	cfg.Host = strings.TrimPrefix(server.URL, "http://")
+	cfg.HTTPPostMode = true
+	cfg.DisableTLS = true
+	c, err := New(cfg, nil)
+	if err != nil {
+		server.Close()
+		t.Fatalf("unable to create client: %v", err)
+	}
+	return server, c
+}
+
+// TestShutdownInFlightRequest ensures requests that are in flight when the
+// client is shutdown, as well as requests made afterwards, complete with
+// ErrClientShutdown and that WaitForShutdown honors the passed context.
+func TestShutdownInFlightRequest(t *testing.T) {
+	received := make(chan struct{})
+	release := make(chan struct{})
+	server, c := newTestHTTPClient(t, func(w http.ResponseWriter, r *http.Request) {
+		close(received)
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}, nil)
+	defer server.Close()
+	defer close(release)
+
+	ctx := context.Background()
+	future := c.GetBlockCountAsync(ctx)
+	select {
+	case <-received:
+	case <-time.After(time.Second * 5):
+		t.Fatal("request was not received by the server")
+	}
+	c.Shutdown()
+	if _, err := future.Receive(); !errors.Is(err, ErrClientShutdown) {
+		t.Fatalf("unexpected error for in-flight request -- got %v, "+
+			"want %v", err, ErrClientShutdown)
+	}
+	if _, err := c.GetBlockCount(ctx); !errors.Is(err, ErrClientShutdown) {
+		t.Fatalf("unexpected error for request after shutdown -- got %v, "+
+			"want %v", err, ErrClientShutdown)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+	if err := c.WaitForShutdown(waitCtx); err != nil {
+		t.Fatalf("unexpected error waiting for shutdown: %v", err)
+	}
+}
+
+// TestShutdownFullSendQueue ensures shutting down a client while the server is
+// stalled and the queue of HTTP POST requests is full does not block and that
+// all of the queued and blocked requests complete with ErrClientShutdown.
+func TestShutdownFullSendQueue(t *testing.T) {
+	var receivedOnce sync.Once
+	received := make(chan struct{})
+	release := make(chan struct{})
+	server, c := newTestHTTPClient(t, func(w http.ResponseWriter, r *http.Request) {
+		receivedOnce.Do(func() { close(received) })
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}, nil)
+	defer server.Close()
+	defer close(release)
+
+	// Issue enough requests to fill the send queue while the first one is
+	// stalled at the server and block additional ones on queueing them.
+	ctx := context.Background()
+	const numRequests = sendPostBufferSize + 10
+	errs := make(chan error, numRequests)
+	for i := 0; i < numRequests; i++ {
+		go func() {
+			_, err := c.GetBlockCount(ctx)
+			errs <- err
+		}()
+	}
+	select {
+	case <-received:
+	case <-time.After(time.Second * 5):
+		t.Fatal("request was not received by the server")
+	}
+	deadline := time.Now().Add(time.Second * 5)
+	for len(c.sendPostChan) < cap(c.sendPostChan) {
+		if time.Now().After(deadline) {
+			t.Fatal("send queue did not fill")
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	shutdownDone := make(chan struct{})
+	go func() {
+		c.Shutdown()
+		close(shutdownDone)
+	}()
+	select {
+	case <-shutdownDone:
+	case <-time.After(time.Second * 5):
+		t.Fatal("shutdown blocked on the full send queue")
+	}
+	for i := 0; i < numRequests; i++ {
+		select {
+		case err := <-errs:
+			if !errors.Is(err, ErrClientShutdown) {
+				t.Fatalf("unexpected error -- got %v, want %v", err,
+					ErrClientShutdown)
+			}
+		case <-time.After(time.Second * 5):
+			t.Fatalf("request %d did not complete after shutdown", i)
+		}
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+	if err := c.WaitForShutdown(waitCtx); err != nil {
+		t.Fatalf("unexpected error waiting for shutdown: %v", err)
+	}
+}
+
+// TestShutdownDrainsNotifications ensures notifications that were received but
+// not yet delivered when the client is shutdown are delivered to the handlers
+// while a handler that does not return causes WaitForShutdown to return once
+// the passed context is done.
+func TestShutdownDrainsNotifications(t *testing.T) {
+	const numNtfns = 5
+	var delivered []string
+	block := make(chan struct{})
+	handlers := &NotificationHandlers{
+		OnUnknownNotification: func(method string, params []json.RawMessage) {
+			delivered = append(delivered, method)
+			if method == "block" {
+				<-block
+			}
+		},
+	}
+	newClient := func() *Client {
+		cfg := &ConnConfig{
+			Host:                "localhost:9109",
+			DisableConnectOnNew: true,
+		}
+		c, err := New(cfg, handlers)
+		if err != nil {
+			t.Fatalf("unable to create client: %v", err)
+		}
+		return c
+	}
+
+	// Queue the notifications prior to shutting down so they are only
+	// delivered while draining.
+	c := newClient()
+	c.Shutdown()
+	for i := 0; i < numNtfns; i++ {
+		c.ntfnChan <- &rawNotification{Method: "ntfn"}
+	}
+	ctx := context.Background()
+	waitCtx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+	if err := c.WaitForShutdown(waitCtx); err != nil {
+		t.Fatalf("unexpected error waiting for shutdown: %v", err)
+	}
+	if len(delivered) != numNtfns {
+		t.Fatalf("unexpected number of delivered notifications -- got "+
+			"%d, want %d", len(delivered), numNtfns)
+	}
+
+	// Ensure a handler that never returns does not block WaitForShutdown
+	// beyond the passed context.
+	delivered = nil
+	c = newClient()
+	c.ntfnChan <- &rawNotification{Method: "block"}
+	c.Shutdown()
+	waitCtx, cancel = context.WithTimeout(ctx, time.Millisecond*50)
+	defer cancel()
+	err := c.WaitForShutdown(waitCtx)
+	close(block)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("unexpected error waiting for shutdown -- got %v, want %v",
+			err, context.DeadlineExceeded)
+	}
+}
+
+// TestClientFailover ensures the client fails over to the configured failover
+// hosts, in order, when the RPC server it is using is unreachable in both HTTP
+// POST and websocket mode.
+func TestClientFailover(t *testing.T) {
+	// Create a server that is no longer listening to act as the unreachable
+	// server.
+	deadServer := httptest.NewServer(http.NotFoundHandler())
+	deadHost := strings.TrimPrefix(deadServer.URL, "http://")
+	deadServer.Close()
+
+	// Create a server that replies to all HTTP POST requests with a block
+	// count and upgrades all other requests to websockets.
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.Write([]byte(`{"result":5,"error":null,"id":1}`))
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	// Ensure requests made in HTTP POST mode fail over to the reachable
+	// server and the client continues to use it afterwards.
+	cfg := &ConnConfig{
+		Host:          deadHost,
+		FailoverHosts: []string{deadHost, host},
+		HTTPPostMode:  true,
+		DisableTLS:    true,
+	}
+	c, err := New(cfg, nil)
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+	defer c.Shutdown()
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		count, err := c.GetBlockCount(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error getting block count: %v", err)
+		}
+		if count != 5 {
+			t.Fatalf("unexpected block count -- got %d, want 5", count)
+		}
+	}
+	if want := "http://" + host; c.String() != want {
+		t.Fatalf("unexpected server -- got %q, want %q", c.String(), want)
+	}
+
+	// Ensure the initial websocket connection fails over to the reachable
+	// server.
+	cfg = &ConnConfig{
+		Host:                 deadHost,
+		FailoverHosts:        []string{host},
+		Endpoint:             "ws",
+		DisableTLS:           true,
+		DisableAutoReconnect: true,
+	}
+	c, err = New(cfg, nil)
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+	defer c.Shutdown()
+	if want := "ws://" + host + "/ws"; c.String() != want {
+		t.Fatalf("unexpected server -- got %q, want %q", c.String(), want)
+	}
+}
+
+// TestClientConnPool ensures clients configured with a connection pool issue
+// HTTP POST requests concurrently.
+func TestClientConnPool(t *testing.T) {
+	// Create a server that only replies once the expected number of
+	// requests are in flight concurrently.
+	const maxConns = 4
+	var wg sync.WaitGroup
+	wg.Add(maxConns)
+	cfg := &ConnConfig{MaxConns: maxConns}
+	server, c := newTestHTTPClient(t, func(w http.ResponseWriter, r *http.Request) {
+		wg.Done()
+		wg.Wait()
+		w.Write([]byte(`{"result":5,"error":null,"id":1}`))
+	}, cfg)
+	defer server.Close()
+	defer c.Shutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	futures := make([]*FutureGetBlockCountResult, maxConns)
+	for i := range futures {
+		futures[i] = c.GetBlockCountAsync(ctx)
+	}
+	for i, future := range futures {
+		count, err := future.Receive()
+		if err != nil {
+			t.Fatalf("unexpected error for request %d: %v", i, err)
+		}
+		if count != 5 {
+			t.Fatalf("unexpected block count -- got %d, want 5", count)
+		}
+	}
+}
+
+// TestRequestTimeout ensures requests that do not complete before either the
+// default request timeout of the client or the deadline of the passed context
+// fail with ErrRequestTimeout while canceled requests fail with
+// ErrRequestCanceled.
+func TestRequestTimeout(t *testing.T) {
+	// Create a server that does not reply until the test is complete.
+	release := make(chan struct{})
+	cfg := &ConnConfig{RequestTimeout: time.Millisecond * 50}
+	server, c := newTestHTTPClient(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}, cfg)
+	defer server.Close()
+	defer close(release)
+	defer c.Shutdown()
+
+	// Ensure the default request timeout applies to requests made with a
+	// context without a deadline, including raw requests.
+	ctx := context.Background()
+	if _, err := c.GetBlockCount(ctx); !errors.Is(err, ErrRequestTimeout) {
+		t.Fatalf("unexpected error with default timeout -- got %v, want %v",
+			err, ErrRequestTimeout)
+	}
+	_, err := c.RawRequest(ctx, "getblockcount", nil)
+	if !errors.Is(err, ErrRequestTimeout) {
+		t.Fatalf("unexpected error for raw request -- got %v, want %v",
+			err, ErrRequestTimeout)
+	}
+
+	// Ensure the deadline of the passed context is honored.
+	deadlineCtx, cancel := context.WithTimeout(ctx, time.Millisecond*10)
+	defer cancel()
+	if _, err := c.GetBlockCount(deadlineCtx); !errors.Is(err, ErrRequestTimeout) {
+		t.Fatalf("unexpected error with context deadline -- got %v, want %v",
+			err, ErrRequestTimeout)
+	}
+
+	// Ensure requests canceled by the caller are not reported as timeouts.
+	cancelCtx, cancel := context.WithCancel(ctx)
+	time.AfterFunc(time.Millisecond*10, cancel)
+	if _, err := c.GetBlockCount(cancelCtx); !errors.Is(err, ErrRequestCanceled) {
+		t.Fatalf("unexpected error for canceled request -- got %v, want %v",
+			err, ErrRequestCanceled)
+	}
+}
+
+// serveSOCKS5 accepts connections on the provided listener and acts as a
+// minimal SOCKS 5 proxy server which requires the provided credentials and
+// connects all requests to the target address regardless of their requested
+// destination.  The requested destinations are sent to the returned channel.
+func serveSOCKS5(l net.Listener, user, pass, target string) <-chan string {
+	dests := make(chan string, 10)
+	handle := func(conn net.Conn) error {
+		defer conn.Close()
+
+		// Greeting and username/password authentication.
+		buf := make([]byte, 256)
+		if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+			return err
+		}
+		if _, err := io.ReadFull(conn, buf[:buf[1]]); err != nil {
+			return err
+		}
+		if _, err := conn.Write([]byte{5, 2}); err != nil {
+			return err
+		}
+		if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+			return err
+		}
+		gotUser := make([]byte, buf[1])
+		if _, err := io.ReadFull(conn, gotUser); err != nil {
+			return err
+		}
+		if _, err := io.ReadFull(conn, buf[:1]); err != nil {
+			return err
+		}
+		gotPass := make([]byte, buf[0])
+		if _, err := io.ReadFull(conn, gotPass); err != nil {
+			return err
+		}
+		if string(gotUser) != user || string(gotPass) != pass {
+			conn.Write([]byte{1, 1})
+			return errors.New("invalid credentials")
+		}
+		if _, err := conn.Write([]byte{1, 0}); err != nil {
+			return err
+		}
+
+		// Connect request with a domain name destination.
+		if _, err := io.ReadFull(conn, buf[:5]); err != nil {
+			return err
+		}
+		host := make([]byte, buf[4])
+		if _, err := io.ReadFull(conn, host); err != nil {
+			return err
+		}
+		if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+			return err
+		}
+		port := int(buf[0])<<8 | int(buf[1])
+		dests <- net.JoinHostPort(string(host), strconv.Itoa(port))
+
+		targetConn, err := net.Dial("tcp", target)
+		if err != nil {
+			return err
+		}
+		defer targetConn.Close()
+		reply := []byte{5, 0, 0, 1, 127, 0, 0, 1, 0, 0}
+		if _, err := conn.Write(reply); err != nil {
+			return err
+		}
+		go io.Copy(targetConn, conn)
+		_, err = io.Copy(conn, targetConn)
+		return err
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go handle(conn)
+		}
+	}()
+	return dests
+}
+
+// TestClientProxy ensures the client connects to the RPC server through the
+// configured SOCKS 5 proxy with the configured credentials and lets the proxy
+// resolve the host in both HTTP POST and websocket mode.
+func TestClientProxy(t *testing.T) {
+	// Create a server that replies to all HTTP POST requests with a block
+	// count and upgrades all other requests to websockets.
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.Write([]byte(`{"result":5,"error":null,"id":1}`))
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+	defer l.Close()
+	const user, pass = "user", "pass"
+	target := strings.TrimPrefix(server.URL, "http://")
+	dests := serveSOCKS5(l, user, pass, target)
+
+	// The onion address is not resolvable, so requests only succeed when
+	// the proxy resolves it.
+	const onionHost = "dcrdxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx.onion:9109"
+	checkDest := func(mode string) {
+		t.Helper()
+		select {
+		case dest := <-dests:
+			if dest != onionHost {
+				t.Fatalf("%s: unexpected proxy destination -- got %q, "+
+					"want %q", mode, dest, onionHost)
+			}
+		default:
+			t.Fatalf("%s: connection was not made through the proxy", mode)
+		}
+	}
+
+	c, err := New(&ConnConfig{
+		Host:         onionHost,
+		HTTPPostMode: true,
+		DisableTLS:   true,
+		Proxy:        l.Addr().String(),
+		ProxyUser:    user,
+		ProxyPass:    pass,
+	}, nil)
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+	defer c.Shutdown()
+	count, err := c.GetBlockCount(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error getting block count: %v", err)
+	}
+	if count != 5 {
+		t.Fatalf("unexpected block count -- got %d, want 5", count)
+	}
+	checkDest("http post")
+
+	c, err = New(&ConnConfig{
+		Host:                 onionHost,
+		Endpoint:             "ws",
+		DisableTLS:           true,
+		DisableAutoReconnect: true,
+		Proxy:                l.Addr().String(),
+		ProxyUser:            user,
+		ProxyPass:            pass,
+	}, nil)
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+	defer c.Shutdown()
+	checkDest("websocket")
+
+	// Ensure invalid proxy credentials are rejected.
+	c, err = New(&ConnConfig{
+		Host:         onionHost,
+		HTTPPostMode: true,
+		DisableTLS:   true,
+		Proxy:        l.Addr().String(),
+		ProxyUser:    user,
+		ProxyPass:    "wrong",
+	}, nil)
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+	defer c.Shutdown()
+	if _, err := c.GetBlockCount(context.Background()); err == nil {
+		t.Fatal("expected error with invalid proxy credentials")
+	}
+}
+
+// TestClientSocketPath ensures the client connects to the RPC server over the
+// configured unix socket without TLS in both HTTP POST and websocket mode.
+func TestClientSocketPath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rpcclient")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	socketPath := filepath.Join(dir, "rpc.sock")
+	l, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+
+	// Create a server that replies to all HTTP POST requests with a block
+	// count and upgrades all other requests to websockets.
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.Write([]byte(`{"result":5,"error":null,"id":1}`))
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	server.Listener.Close()
+	server.Listener = l
+	server.Start()
+	defer server.Close()
+
+	// TLS is not disabled to ensure it is not used with unix sockets.
+	c, err := New(&ConnConfig{
+		SocketPath:   socketPath,
+		HTTPPostMode: true,
+	}, nil)
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+	defer c.Shutdown()
+	count, err := c.GetBlockCount(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error getting block count: %v", err)
+	}
+	if count != 5 {
+		t.Fatalf("unexpected block count -- got %d, want 5", count)
+	}
+	if want := "http://localhost"; c.String() != want {
+		t.Fatalf("unexpected server -- got %q, want %q", c.String(), want)
+	}
+
+	c, err = New(&ConnConfig{
+		SocketPath:           socketPath,
+		Endpoint:             "ws",
+		DisableAutoReconnect: true,
+	}, nil)
+	if err != nil {
+		t.Fatalf("unable to create websocket client: %v", err)
+	}
+	defer c.Shutdown()
+	if want := "ws://localhost/ws"; c.String() != want {
+		t.Fatalf("unexpected server -- got %q, want %q", c.String(), want)
+	}
+}
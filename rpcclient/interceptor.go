@@ -0,0 +1,202 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/decred/dcrd/dcrjson/v3"
+)
+
+// RequestInfo describes an outgoing request to the request interceptors.
+type RequestInfo struct {
+	// ID is the JSON-RPC id of the request.
+	ID uint64
+
+	// Method is the name of the RPC method.
+	Method string
+
+	// Params houses the marshalled parameters of the request.  Request
+	// interceptors may modify them in order to change the request that is
+	// sent to the RPC server.
+	Params []json.RawMessage
+}
+
+// ResponseInfo describes the outcome of a request to the response interceptors.
+type ResponseInfo struct {
+	// ID is the JSON-RPC id of the request.
+	ID uint64
+
+	// Method is the name of the RPC method.
+	Method string
+
+	// Params houses the marshalled parameters the request was sent with,
+	// including any modifications made by the request interceptors.
+	Params []json.RawMessage
+
+	// Result is the raw result of the request.  It is nil when Err is set.
+	// Response interceptors may modify it in order to change the result
+	// that is returned to the caller.
+	Result json.RawMessage
+
+	// Err is the error that resulted from the request, if any.  Response
+	// interceptors may modify it in order to change the error that is
+	// returned to the caller.
+	Err error
+
+	// Latency is the amount of time between sending the request and
+	// receiving its response.
+	Latency time.Duration
+}
+
+// RequestInterceptor is the interface that allows callers to observe and modify
+// every request the client sends before it is sent.
+type RequestInterceptor interface {
+	// InterceptRequest is invoked with the context the request was made
+	// with and a description of the request prior to sending it.  The
+	// request fails with the returned error without being sent when it is
+	// non-nil.
+	InterceptRequest(ctx context.Context, req *RequestInfo) error
+}
+
+// ResponseInterceptor is the interface that allows callers to observe and modify
+// the outcome of every request the client sends before it is delivered to the
+// caller.
+type ResponseInterceptor interface {
+	// InterceptResponse is invoked with the context the request was made
+	// with and a description of the outcome of the request.
+	InterceptResponse(ctx context.Context, resp *ResponseInfo)
+}
+
+// RequestInterceptorFunc is an adapter that allows the use of an ordinary
+// function as a RequestInterceptor.
+type RequestInterceptorFunc func(ctx context.Context, req *RequestInfo) error
+
+// InterceptRequest calls f(ctx, req).
+func (f RequestInterceptorFunc) InterceptRequest(ctx context.Context, req *RequestInfo) error {
+	return f(ctx, req)
+}
+
+// ResponseInterceptorFunc is an adapter that allows the use of an ordinary
+// function as a ResponseInterceptor.
+type ResponseInterceptorFunc func(ctx context.Context, resp *ResponseInfo)
+
+// InterceptResponse calls f(ctx, resp).
+func (f ResponseInterceptorFunc) InterceptResponse(ctx context.Context, resp *ResponseInfo) {
+	f(ctx, resp)
+}
+
+// AddRequestInterceptor adds the passed interceptor to the client so that it is
+// invoked for every request the client sends from then on, including those
+// made via RawRequest.  Interceptors are invoked in the order they were added
+// and each observes the modifications made by the previous ones.
+//
+// Interceptors are invoked by the goroutine making the request and must not
+// block for extended periods of time.
+//
+// This function is safe for concurrent access.
+func (c *Client) AddRequestInterceptor(interceptor RequestInterceptor) {
+	c.interceptorsMtx.Lock()
+	reqInterceptors := make([]RequestInterceptor, 0, len(c.reqInterceptors)+1)
+	reqInterceptors = append(reqInterceptors, c.reqInterceptors...)
+	c.reqInterceptors = append(reqInterceptors, interceptor)
+	c.interceptorsMtx.Unlock()
+}
+
+// AddResponseInterceptor adds the passed interceptor to the client so that it
+// is invoked with the outcome of every request the client sends from then on,
+// including those made via RawRequest.  Interceptors are invoked in the order
+// they were added and each observes the modifications made by the previous
+// ones.
+//
+// Interceptors are invoked by a separate goroutine for each request prior to
+// delivering its result and must not block for extended periods of time.
+//
+// This function is safe for concurrent access.
+func (c *Client) AddResponseInterceptor(interceptor ResponseInterceptor) {
+	c.interceptorsMtx.Lock()
+	respInterceptors := make([]ResponseInterceptor, 0, len(c.respInterceptors)+1)
+	respInterceptors = append(respInterceptors, c.respInterceptors...)
+	c.respInterceptors = append(respInterceptors, interceptor)
+	c.interceptorsMtx.Unlock()
+}
+
+// interceptors returns the request and response interceptors of the client.
+// The returned slices must not be modified.
+//
+// This function is safe for concurrent access.
+func (c *Client) interceptors() ([]RequestInterceptor, []ResponseInterceptor) {
+	c.interceptorsMtx.RLock()
+	reqInterceptors, respInterceptors := c.reqInterceptors, c.respInterceptors
+	c.interceptorsMtx.RUnlock()
+	return reqInterceptors, respInterceptors
+}
+
+// interceptRequest invokes the interceptors of the client for the passed
+// request prior to it being sent.  When there are response interceptors, the
+// response channel of the request is replaced with one that is serviced by a
+// goroutine which invokes them with the response before delivering it to the
+// original channel.  It returns an error when the request must not be sent
+// which the caller must deliver to the response channel of the request.
+func (c *Client) interceptRequest(ctx context.Context, jReq *jsonRequest) error {
+	reqInterceptors, respInterceptors := c.interceptors()
+	if len(reqInterceptors) == 0 && len(respInterceptors) == 0 {
+		return nil
+	}
+
+	// Unmarshal the parameters of the request so the interceptors can
+	// examine them.
+	var rawReq dcrjson.Request
+	if err := json.Unmarshal(jReq.marshalledJSON, &rawReq); err != nil {
+		return err
+	}
+	info := RequestInfo{
+		ID:     jReq.id,
+		Method: jReq.method,
+		Params: rawReq.Params,
+	}
+
+	// Invoke the request interceptors and marshal the request again since
+	// they might have modified the parameters.
+	var reqErr error
+	for _, interceptor := range reqInterceptors {
+		if reqErr = interceptor.InterceptRequest(ctx, &info); reqErr != nil {
+			break
+		}
+	}
+	if reqErr == nil && len(reqInterceptors) > 0 {
+		rawReq.ID = jReq.id
+		rawReq.Params = info.Params
+		jReq.marshalledJSON, reqErr = json.Marshal(&rawReq)
+	}
+
+	// Invoke the response interceptors with the response, which includes
+	// the error when the request is not sent, before delivering it.
+	if len(respInterceptors) > 0 {
+		callerChan := jReq.responseChan
+		responseChan := make(chan *response, 1)
+		jReq.responseChan = responseChan
+		start := time.Now()
+		go func() {
+			r := <-responseChan
+			resp := ResponseInfo{
+				ID:      info.ID,
+				Method:  info.Method,
+				Params:  info.Params,
+				Result:  r.result,
+				Err:     r.err,
+				Latency: time.Since(start),
+			}
+			for _, interceptor := range respInterceptors {
+				interceptor.InterceptResponse(ctx, &resp)
+			}
+			callerChan <- &response{result: resp.Result, err: resp.Err}
+		}()
+	}
+
+	return reqErr
+}
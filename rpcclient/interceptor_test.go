@@ -0,0 +1,103 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestClientInterceptors ensures the request interceptors can modify requests
+// and prevent them from being sent and that the response interceptors observe
+// and can modify the outcome of all requests.
+func TestClientInterceptors(t *testing.T) {
+	// Create a server that replies to all requests with the first parameter
+	// of the request or zero when there are no parameters.
+	server, c := newTestHTTPClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64            `json:"id"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		result := json.RawMessage("0")
+		if len(req.Params) > 0 {
+			result = req.Params[0]
+		}
+		fmt.Fprintf(w, `{"result":%s,"error":null,"id":%d}`, result, req.ID)
+	}, nil)
+	defer server.Close()
+	defer c.Shutdown()
+
+	// Add request interceptors that replace the height of getblockhash
+	// requests and fail getblockcount requests along with a response
+	// interceptor that records the responses and replaces errors.
+	errInjected := errors.New("injected fault")
+	errReplaced := errors.New("replaced fault")
+	c.AddRequestInterceptor(RequestInterceptorFunc(func(ctx context.Context, req *RequestInfo) error {
+		if req.Method == "getblockhash" {
+			req.Params[0] = json.RawMessage(`"` + strings.Repeat("ab", 32) + `"`)
+		}
+		return nil
+	}))
+	c.AddRequestInterceptor(RequestInterceptorFunc(func(ctx context.Context, req *RequestInfo) error {
+		if req.Method == "getblockcount" {
+			return errInjected
+		}
+		return nil
+	}))
+	var mtx sync.Mutex
+	var responses []ResponseInfo
+	c.AddResponseInterceptor(ResponseInterceptorFunc(func(ctx context.Context, resp *ResponseInfo) {
+		mtx.Lock()
+		responses = append(responses, *resp)
+		mtx.Unlock()
+		if resp.Err != nil {
+			resp.Err = errReplaced
+		}
+	}))
+
+	// Ensure the modified request is sent.
+	ctx := context.Background()
+	hash, err := c.GetBlockHash(ctx, 5)
+	if err != nil {
+		t.Fatalf("unexpected error getting block hash: %v", err)
+	}
+	if want := strings.Repeat("ab", 32); hash.String() != want {
+		t.Fatalf("unexpected block hash -- got %v, want %v", hash, want)
+	}
+
+	// Ensure the failed request is observed by the response interceptor
+	// and the error it replaced is returned.
+	if _, err := c.GetBlockCount(ctx); !errors.Is(err, errReplaced) {
+		t.Fatalf("unexpected error getting block count -- got %v, want %v",
+			err, errReplaced)
+	}
+
+	mtx.Lock()
+	defer mtx.Unlock()
+	if len(responses) != 2 {
+		t.Fatalf("unexpected number of responses -- got %d, want 2",
+			len(responses))
+	}
+	resp := responses[0]
+	if resp.Method != "getblockhash" || resp.Err != nil ||
+		string(resp.Params[0]) != string(resp.Result) || resp.Latency <= 0 {
+
+		t.Fatalf("unexpected getblockhash response %+v", resp)
+	}
+	resp = responses[1]
+	if resp.Method != "getblockcount" || !errors.Is(resp.Err, errInjected) {
+		t.Fatalf("unexpected getblockcount response %+v", resp)
+	}
+}
@@ -0,0 +1,325 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultLatencyBuckets are the default upper bounds, in seconds, of the
+// buckets of the request latency histograms.  They match the default buckets
+// used by Prometheus client libraries.
+var DefaultLatencyBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1,
+	2.5, 5, 10}
+
+// methodMetrics houses the metrics recorded for a single RPC method.
+type methodMetrics struct {
+	requests     uint64
+	errors       uint64
+	inFlight     int64
+	latencySum   time.Duration
+	latencyCount []uint64
+}
+
+// Metrics is a registry of metrics that describe the health of the RPC
+// requests made by one or more clients.  It records per-method request counts,
+// error counts, in-flight requests, and latency histograms along with the
+// number of times websocket clients have been disconnected and reconnected.
+//
+// A registry is enabled for a client by setting the Metrics field of the
+// connection configuration, and the same registry may be shared by several
+// clients.  The recorded metrics are available via Snapshot and may be exposed
+// to Prometheus with WritePrometheus or by serving the registry over HTTP.
+//
+// All methods are safe for concurrent access.
+type Metrics struct {
+	buckets []float64
+
+	mtx               sync.Mutex
+	methods           map[string]*methodMetrics
+	disconnects       uint64
+	reconnectAttempts uint64
+	reconnects        uint64
+}
+
+// NewMetrics returns a new empty metrics registry that records request latency
+// histograms with the provided bucket upper bounds in seconds.  Nil selects
+// DefaultLatencyBuckets.
+func NewMetrics(buckets []float64) *Metrics {
+	if buckets == nil {
+		buckets = DefaultLatencyBuckets
+	}
+	sorted := make([]float64, len(buckets))
+	copy(sorted, buckets)
+	sort.Float64s(sorted)
+	return &Metrics{
+		buckets: sorted,
+		methods: make(map[string]*methodMetrics),
+	}
+}
+
+// method returns the metrics for the provided method, creating them when
+// needed.
+//
+// This function MUST be called with the metrics mutex held.
+func (m *Metrics) method(method string) *methodMetrics {
+	mm, ok := m.methods[method]
+	if !ok {
+		mm = &methodMetrics{latencyCount: make([]uint64, len(m.buckets))}
+		m.methods[method] = mm
+	}
+	return mm
+}
+
+// requestStarted records that a request for the provided method was issued.
+func (m *Metrics) requestStarted(method string) {
+	m.mtx.Lock()
+	mm := m.method(method)
+	mm.requests++
+	mm.inFlight++
+	m.mtx.Unlock()
+}
+
+// requestDone records that a request for the provided method completed after
+// the provided amount of time with the provided error, if any.
+func (m *Metrics) requestDone(method string, latency time.Duration, err error) {
+	seconds := latency.Seconds()
+	m.mtx.Lock()
+	mm := m.method(method)
+	mm.inFlight--
+	if err != nil {
+		mm.errors++
+	}
+	mm.latencySum += latency
+	for i, bound := range m.buckets {
+		if seconds <= bound {
+			mm.latencyCount[i]++
+		}
+	}
+	m.mtx.Unlock()
+}
+
+// disconnected records that a websocket client lost its connection.
+func (m *Metrics) disconnected() {
+	m.mtx.Lock()
+	m.disconnects++
+	m.mtx.Unlock()
+}
+
+// reconnectAttempted records that a websocket client attempted to reestablish
+// its connection and whether or not the attempt succeeded.
+func (m *Metrics) reconnectAttempted(succeeded bool) {
+	m.mtx.Lock()
+	m.reconnectAttempts++
+	if succeeded {
+		m.reconnects++
+	}
+	m.mtx.Unlock()
+}
+
+// MethodMetrics describes the requests made for a single RPC method.
+type MethodMetrics struct {
+	// Method is the name of the RPC method.
+	Method string
+
+	// Requests is the total number of requests issued for the method.
+	Requests uint64
+
+	// Errors is the total number of requests for the method that failed,
+	// including those that failed due to being canceled or timing out.
+	Errors uint64
+
+	// InFlight is the number of requests for the method that are awaiting a
+	// response.
+	InFlight int64
+
+	// LatencySum is the combined amount of time taken by all completed
+	// requests for the method.
+	LatencySum time.Duration
+
+	// LatencyCounts are the cumulative number of completed requests for the
+	// method that took at most the corresponding upper bound of the latency
+	// buckets.
+	LatencyCounts []uint64
+}
+
+// MetricsSnapshot is a point-in-time copy of the metrics recorded by a metrics
+// registry.
+type MetricsSnapshot struct {
+	// LatencyBuckets are the upper bounds, in seconds, of the buckets of the
+	// request latency histograms.
+	LatencyBuckets []float64
+
+	// Methods are the metrics for each RPC method requests have been made
+	// for sorted by method name.
+	Methods []MethodMetrics
+
+	// Disconnects is the number of times websocket clients lost their
+	// connection.
+	Disconnects uint64
+
+	// ReconnectAttempts is the number of times websocket clients attempted
+	// to reestablish their connection.
+	ReconnectAttempts uint64
+
+	// Reconnects is the number of times websocket clients successfully
+	// reestablished their connection.
+	Reconnects uint64
+}
+
+// Snapshot returns a copy of the metrics recorded by the registry.
+func (m *Metrics) Snapshot() *MetricsSnapshot {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	methods := make([]MethodMetrics, 0, len(m.methods))
+	for method, mm := range m.methods {
+		counts := make([]uint64, len(mm.latencyCount))
+		copy(counts, mm.latencyCount)
+		methods = append(methods, MethodMetrics{
+			Method:        method,
+			Requests:      mm.requests,
+			Errors:        mm.errors,
+			InFlight:      mm.inFlight,
+			LatencySum:    mm.latencySum,
+			LatencyCounts: counts,
+		})
+	}
+	sort.Slice(methods, func(i, j int) bool {
+		return methods[i].Method < methods[j].Method
+	})
+
+	buckets := make([]float64, len(m.buckets))
+	copy(buckets, m.buckets)
+	return &MetricsSnapshot{
+		LatencyBuckets:    buckets,
+		Methods:           methods,
+		Disconnects:       m.disconnects,
+		ReconnectAttempts: m.reconnectAttempts,
+		Reconnects:        m.reconnects,
+	}
+}
+
+// formatFloat returns the provided value formatted for the Prometheus text
+// exposition format.
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// WritePrometheus writes the metrics recorded by the registry to the provided
+// writer in the Prometheus text exposition format.  All metric names are
+// prefixed with rpcclient_ and the per-method metrics are labeled with the
+// method name.
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	snap := m.Snapshot()
+
+	bw := bufio.NewWriter(w)
+	header := func(name, typ, help string) {
+		fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+	}
+
+	header("rpcclient_requests_total", "counter",
+		"Total number of RPC requests issued.")
+	for i := range snap.Methods {
+		mm := &snap.Methods[i]
+		fmt.Fprintf(bw, "rpcclient_requests_total{method=%q} %d\n",
+			mm.Method, mm.Requests)
+	}
+
+	header("rpcclient_request_errors_total", "counter",
+		"Total number of RPC requests that failed.")
+	for i := range snap.Methods {
+		mm := &snap.Methods[i]
+		fmt.Fprintf(bw, "rpcclient_request_errors_total{method=%q} %d\n",
+			mm.Method, mm.Errors)
+	}
+
+	header("rpcclient_requests_in_flight", "gauge",
+		"Number of RPC requests awaiting a response.")
+	for i := range snap.Methods {
+		mm := &snap.Methods[i]
+		fmt.Fprintf(bw, "rpcclient_requests_in_flight{method=%q} %d\n",
+			mm.Method, mm.InFlight)
+	}
+
+	header("rpcclient_request_duration_seconds", "histogram",
+		"Latency of completed RPC requests in seconds.")
+	for i := range snap.Methods {
+		mm := &snap.Methods[i]
+		for j, bound := range snap.LatencyBuckets {
+			fmt.Fprintf(bw, "rpcclient_request_duration_seconds_bucket"+
+				"{method=%q,le=%q} %d\n", mm.Method, formatFloat(bound),
+				mm.LatencyCounts[j])
+		}
+		completed := mm.Requests - uint64(mm.InFlight)
+		fmt.Fprintf(bw, "rpcclient_request_duration_seconds_bucket"+
+			"{method=%q,le=\"+Inf\"} %d\n", mm.Method, completed)
+		fmt.Fprintf(bw, "rpcclient_request_duration_seconds_sum{method=%q} "+
+			"%s\n", mm.Method, formatFloat(mm.LatencySum.Seconds()))
+		fmt.Fprintf(bw, "rpcclient_request_duration_seconds_count"+
+			"{method=%q} %d\n", mm.Method, completed)
+	}
+
+	header("rpcclient_websocket_disconnects_total", "counter",
+		"Total number of times websocket connections were lost.")
+	fmt.Fprintf(bw, "rpcclient_websocket_disconnects_total %d\n",
+		snap.Disconnects)
+	header("rpcclient_websocket_reconnect_attempts_total", "counter",
+		"Total number of attempts to reestablish websocket connections.")
+	fmt.Fprintf(bw, "rpcclient_websocket_reconnect_attempts_total %d\n",
+		snap.ReconnectAttempts)
+	header("rpcclient_websocket_reconnects_total", "counter",
+		"Total number of websocket connections that were reestablished.")
+	fmt.Fprintf(bw, "rpcclient_websocket_reconnects_total %d\n",
+		snap.Reconnects)
+
+	return bw.Flush()
+}
+
+// ServeHTTP implements http.Handler by writing the metrics recorded by the
+// registry in the Prometheus text exposition format so the registry can be
+// scraped directly.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	if err := m.WritePrometheus(w); err != nil {
+		log.Debugf("Unable to write metrics: %v", err)
+	}
+}
+
+// Metrics returns the metrics registry the client records its requests with or
+// nil when the client is not configured with one.
+func (c *Client) Metrics() *Metrics {
+	return c.config.Metrics
+}
+
+// observeRequest returns the response channel to send a request for the
+// provided method on given the channel the response is ultimately delivered
+// to.  When the client is configured with a metrics registry, the request is
+// recorded as in flight and the returned channel is serviced by a goroutine
+// that records the outcome and latency of the request before forwarding the
+// response.
+func (c *Client) observeRequest(method string, responseChan chan *response) chan *response {
+	m := c.config.Metrics
+	if m == nil {
+		return responseChan
+	}
+
+	start := time.Now()
+	m.requestStarted(method)
+	observeChan := make(chan *response, 1)
+	go func() {
+		r := <-observeChan
+		m.requestDone(method, time.Since(start), r.err)
+		responseChan <- r
+	}()
+	return observeChan
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestClientMetrics ensures the metrics registry of a client records the
+// requests it makes and that the metrics are written in the Prometheus text
+// exposition format.
+func TestClientMetrics(t *testing.T) {
+	// Create a server that replies with a block count, fails requests for the
+	// best block hash, and holds requests for the difficulty until released.
+	release := make(chan struct{})
+	metrics := NewMetrics([]float64{60, 0.000001})
+	cfg := &ConnConfig{
+		MaxConns: 2,
+		Metrics:  metrics,
+	}
+	server, c := newTestHTTPClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		switch req.Method {
+		case "getblockcount":
+			w.Write([]byte(`{"result":5,"error":null,"id":1}`))
+		case "getbestblockhash":
+			w.Write([]byte(`{"result":null,"error":{"code":-32603,` +
+				`"message":"Internal error"},"id":1}`))
+		case "getdifficulty":
+			<-release
+			w.Write([]byte(`{"result":1,"error":null,"id":1}`))
+		}
+	}, cfg)
+	defer server.Close()
+	defer c.Shutdown()
+	if c.Metrics() != metrics {
+		t.Fatal("client does not expose its metrics registry")
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := c.GetBlockCount(ctx); err != nil {
+			t.Fatalf("unexpected error getting block count: %v", err)
+		}
+	}
+	if _, err := c.GetBestBlockHash(ctx); err == nil {
+		t.Fatal("did not receive expected error for best block hash")
+	}
+
+	// Ensure requests awaiting a response are reported as in flight until
+	// they complete.
+	future := c.GetDifficultyAsync(ctx)
+	snap := metrics.Snapshot()
+	if len(snap.Methods) != 3 || snap.Methods[2].Method != "getdifficulty" ||
+		snap.Methods[2].InFlight != 1 {
+
+		t.Fatalf("unexpected in-flight metrics: %+v", snap.Methods)
+	}
+	close(release)
+	if _, err := future.Receive(); err != nil {
+		t.Fatalf("unexpected error getting difficulty: %v", err)
+	}
+
+	// Ensure the metrics for each method are sorted by method name and the
+	// latency buckets are sorted.
+	snap = metrics.Snapshot()
+	wantBuckets := []float64{0.000001, 60}
+	if len(snap.LatencyBuckets) != 2 ||
+		snap.LatencyBuckets[0] != wantBuckets[0] ||
+		snap.LatencyBuckets[1] != wantBuckets[1] {
+
+		t.Fatalf("unexpected latency buckets -- got %v, want %v",
+			snap.LatencyBuckets, wantBuckets)
+	}
+	wantMethods := []MethodMetrics{
+		{Method: "getbestblockhash", Requests: 1, Errors: 1},
+		{Method: "getblockcount", Requests: 2},
+		{Method: "getdifficulty", Requests: 1},
+	}
+	if len(snap.Methods) != len(wantMethods) {
+		t.Fatalf("unexpected number of methods -- got %d, want %d",
+			len(snap.Methods), len(wantMethods))
+	}
+	for i, want := range wantMethods {
+		got := snap.Methods[i]
+		if got.Method != want.Method || got.Requests != want.Requests ||
+			got.Errors != want.Errors || got.InFlight != 0 {
+
+			t.Fatalf("unexpected metrics for %s -- got %+v", want.Method, got)
+		}
+		if got.LatencyCounts[0] != 0 || got.LatencyCounts[1] != got.Requests {
+			t.Fatalf("unexpected latency counts for %s -- got %v",
+				want.Method, got.LatencyCounts)
+		}
+		if got.LatencySum <= 0 || got.LatencySum > time.Minute {
+			t.Fatalf("unexpected latency sum for %s -- got %v",
+				want.Method, got.LatencySum)
+		}
+	}
+
+	// Ensure the metrics are written in the Prometheus text exposition format
+	// including the websocket reconnect counters.
+	metrics.disconnected()
+	metrics.reconnectAttempted(false)
+	metrics.reconnectAttempted(true)
+	var buf bytes.Buffer
+	if err := metrics.WritePrometheus(&buf); err != nil {
+		t.Fatalf("unable to write metrics: %v", err)
+	}
+	wantLines := []string{
+		"# TYPE rpcclient_requests_total counter",
+		`rpcclient_requests_total{method="getblockcount"} 2`,
+		`rpcclient_request_errors_total{method="getbestblockhash"} 1`,
+		`rpcclient_requests_in_flight{method="getdifficulty"} 0`,
+		"# TYPE rpcclient_request_duration_seconds histogram",
+		`rpcclient_request_duration_seconds_bucket{method="getblockcount",le="1e-06"} 0`,
+		`rpcclient_request_duration_seconds_bucket{method="getblockcount",le="60"} 2`,
+		`rpcclient_request_duration_seconds_bucket{method="getblockcount",le="+Inf"} 2`,
+		`rpcclient_request_duration_seconds_count{method="getblockcount"} 2`,
+		"rpcclient_websocket_disconnects_total 1",
+		"rpcclient_websocket_reconnect_attempts_total 2",
+		"rpcclient_websocket_reconnects_total 1",
+	}
+	lines := strings.Split(buf.String(), "\n")
+	for _, want := range wantLines {
+		var found bool
+		for _, line := range lines {
+			if line == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("metrics output is missing line %q", want)
+		}
+	}
+
+	// Ensure the registry can be scraped over HTTP.
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Body.String() != buf.String() {
+		t.Fatalf("unexpected scraped metrics -- got %q, want %q",
+			rec.Body.String(), buf.String())
+	}
+}
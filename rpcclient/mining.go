@@ -7,11 +7,14 @@ package rpcclient
 
 import (
 	"context"
+	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 
 	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrjson/v3"
 	"github.com/decred/dcrd/dcrutil/v3"
 	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v2"
 )
@@ -63,6 +66,25 @@ func (c *Client) Generate(ctx context.Context, numBlocks uint32) ([]*chainhash.H
 	return c.GenerateAsync(ctx, numBlocks).Receive()
 }
 
+// GenerateToAddressAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GenerateToAddress for the blocking version and more details.
+func (c *Client) GenerateToAddressAsync(ctx context.Context, numBlocks uint32, address dcrutil.Address) *FutureGenerateResult {
+	cmd := chainjson.NewGenerateToAddressCmd(numBlocks, address.Address())
+	return (*FutureGenerateResult)(c.sendCmd(ctx, cmd))
+}
+
+// GenerateToAddress generates numBlocks blocks that pay the coinbase to the
+// provided address and returns their hashes.  It is only available on simnet
+// and regnet.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GenerateToAddress(ctx context.Context, numBlocks uint32, address dcrutil.Address) ([]*chainhash.Hash, error) {
+	return c.GenerateToAddressAsync(ctx, numBlocks, address).Receive()
+}
+
 // FutureGetGenerateResult is a future promise to deliver the result of a
 // GetGenerateAsync RPC invocation (or an applicable error).
 type FutureGetGenerateResult cmdRes
@@ -126,6 +148,33 @@ func (c *Client) SetGenerate(ctx context.Context, enable bool, numCPUs int) erro
 	return c.SetGenerateAsync(ctx, enable, numCPUs).Receive()
 }
 
+// FutureSetMiningExtraDataResult is a future promise to deliver the result of a
+// SetMiningExtraDataAsync RPC invocation (or an applicable error).
+type FutureSetMiningExtraDataResult cmdRes
+
+// Receive waits for the response promised by the future and returns an error if
+// any occurred when setting the coinbase extra data used by the server.
+func (r *FutureSetMiningExtraDataResult) Receive() error {
+	_, err := receiveFuture(r.ctx, r.c)
+	return err
+}
+
+// SetMiningExtraDataAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See SetMiningExtraData for the blocking version and more details.
+func (c *Client) SetMiningExtraDataAsync(ctx context.Context, extraData []byte) *FutureSetMiningExtraDataResult {
+	cmd := chainjson.NewSetMiningExtraDataCmd(hex.EncodeToString(extraData))
+	return (*FutureSetMiningExtraDataResult)(c.sendCmd(ctx, cmd))
+}
+
+// SetMiningExtraData sets the extra data, such as a pool tag, that the server
+// appends to the coinbase signature script of generated block templates.
+func (c *Client) SetMiningExtraData(ctx context.Context, extraData []byte) error {
+	return c.SetMiningExtraDataAsync(ctx, extraData).Receive()
+}
+
 // FutureGetHashesPerSecResult is a future promise to deliver the result of a
 // GetHashesPerSecAsync RPC invocation (or an applicable error).
 type FutureGetHashesPerSecResult cmdRes
@@ -166,6 +215,127 @@ func (c *Client) GetHashesPerSec(ctx context.Context) (int64, error) {
 	return c.GetHashesPerSecAsync(ctx).Receive()
 }
 
+// FutureGetMiningAddrsResult is a future promise to deliver the result of a
+// GetMiningAddrsAsync RPC invocation (or an applicable error).
+type FutureGetMiningAddrsResult cmdRes
+
+// Receive waits for the response promised by the future and returns the mining
+// addresses along with the policy used to choose between them.
+func (r *FutureGetMiningAddrsResult) Receive() (*chainjson.GetMiningAddrsResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getminingaddrs result object.
+	var addrsResult chainjson.GetMiningAddrsResult
+	err = json.Unmarshal(res, &addrsResult)
+	if err != nil {
+		return nil, err
+	}
+
+	return &addrsResult, nil
+}
+
+// GetMiningAddrsAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetMiningAddrs for the blocking version and more details.
+func (c *Client) GetMiningAddrsAsync(ctx context.Context) *FutureGetMiningAddrsResult {
+	cmd := chainjson.NewGetMiningAddrsCmd()
+	return (*FutureGetMiningAddrsResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetMiningAddrs returns the mining addresses the block templates generated by
+// the server pay to along with the policy used to choose between them.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetMiningAddrs(ctx context.Context) (*chainjson.GetMiningAddrsResult, error) {
+	return c.GetMiningAddrsAsync(ctx).Receive()
+}
+
+// FutureGetTemplateDecisionsResult is a future promise to deliver the result
+// of a GetTemplateDecisionsAsync RPC invocation (or an applicable error).
+type FutureGetTemplateDecisionsResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// transaction selection decisions for the most recently generated block
+// templates.
+func (r *FutureGetTemplateDecisionsResult) Receive() ([]chainjson.GetTemplateDecisionsResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the result as an array of template decisions objects.
+	var decisions []chainjson.GetTemplateDecisionsResult
+	err = json.Unmarshal(res, &decisions)
+	if err != nil {
+		return nil, err
+	}
+
+	return decisions, nil
+}
+
+// GetTemplateDecisionsAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See GetTemplateDecisions for the blocking version and more details.
+func (c *Client) GetTemplateDecisionsAsync(ctx context.Context, count int32) *FutureGetTemplateDecisionsResult {
+	cmd := chainjson.NewGetTemplateDecisionsCmd(&count)
+	return (*FutureGetTemplateDecisionsResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetTemplateDecisions returns which of the transactions considered for up to
+// the provided number of the most recently generated block templates were
+// selected or skipped along with why, ordered from newest to oldest template.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetTemplateDecisions(ctx context.Context, count int32) ([]chainjson.GetTemplateDecisionsResult, error) {
+	return c.GetTemplateDecisionsAsync(ctx, count).Receive()
+}
+
+// FutureSetMiningAddrsResult is a future promise to deliver the result of a
+// SetMiningAddrsAsync RPC invocation (or an applicable error).
+type FutureSetMiningAddrsResult cmdRes
+
+// Receive waits for the response promised by the future and returns an error if
+// any occurred when setting the mining addresses used by the server.
+func (r *FutureSetMiningAddrsResult) Receive() error {
+	_, err := receiveFuture(r.ctx, r.c)
+	return err
+}
+
+// SetMiningAddrsAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See SetMiningAddrs for the blocking version and more details.
+func (c *Client) SetMiningAddrsAsync(ctx context.Context, addrs []dcrutil.Address, policy string) *FutureSetMiningAddrsResult {
+	encodedAddrs := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		encodedAddrs = append(encodedAddrs, addr.Address())
+	}
+	var policyPtr *string
+	if policy != "" {
+		policyPtr = &policy
+	}
+	cmd := chainjson.NewSetMiningAddrsCmd(encodedAddrs, policyPtr)
+	return (*FutureSetMiningAddrsResult)(c.sendCmd(ctx, cmd))
+}
+
+// SetMiningAddrs sets the mining addresses the block templates generated by the
+// server pay to along with the policy used to choose between them, which is
+// either "random" or "roundrobin".  The current policy is kept when the policy
+// is empty.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) SetMiningAddrs(ctx context.Context, addrs []dcrutil.Address, policy string) error {
+	return c.SetMiningAddrsAsync(ctx, addrs, policy).Receive()
+}
+
 // FutureGetMiningInfoResult is a future promise to deliver the result of a
 // GetMiningInfoAsync RPC invocation (or an applicable error).
 type FutureGetMiningInfoResult cmdRes
@@ -204,7 +374,7 @@ func (c *Client) GetMiningInfo(ctx context.Context) (*chainjson.GetMiningInfoRes
 }
 
 // FutureGetNetworkHashPS is a future promise to deliver the result of a
-// GetNetworkHashPSAsync RPC invocation (or an applicable error).
+// GetNetworkHashPSOptAsync RPC invocation (or an applicable error).
 type FutureGetNetworkHashPS cmdRes
 
 // Receive waits for the response promised by the future and returns the
@@ -226,64 +396,155 @@ func (r *FutureGetNetworkHashPS) Receive() (int64, error) {
 	return result, nil
 }
 
-// GetNetworkHashPSAsync returns an instance of a type that can be used to get
-// the result of the RPC at some future time by invoking the Receive function on
-// the returned instance.
+// GetNetworkHashPSOptions specifies the optional parameters of requests for
+// the estimated network hashes per second.  Fields that are nil use the default
+// of the server.
+type GetNetworkHashPSOptions struct {
+	// Blocks is the number of blocks to use for the estimate working
+	// backwards from the height.  It can also be -1 in which case the
+	// number of blocks since the last difficulty change is used.  The
+	// server defaults to 120 blocks.
+	Blocks *int
+
+	// Height is the height of the block to calculate the estimate at.  It
+	// can also be -1 in which case the most recent block height is used,
+	// which is also the default of the server.
+	Height *int
+
+	// Method is the estimation method, which is either "window", which
+	// divides the total work by the time it took to produce the blocks, or
+	// "ema", which weights more recent blocks more heavily.  The server
+	// defaults to "window".
+	Method *string
+}
+
+// getNetworkHashPSCmd returns a getnetworkhashps command for the options and
+// the provided verbose flag.  Since optional parameters are positional, the
+// defaults of the server are filled in for any unset options that precede set
+// ones so that the latter are not silently dropped.  Nil options use the
+// defaults of the server.
+func (o *GetNetworkHashPSOptions) getNetworkHashPSCmd(verbose bool) *chainjson.GetNetworkHashPSCmd {
+	var opts GetNetworkHashPSOptions
+	if o != nil {
+		opts = *o
+	}
+	var verbosePtr *bool
+	if verbose {
+		verbosePtr = &verbose
+	}
+	if opts.Method == nil && verbosePtr != nil {
+		opts.Method = dcrjson.String("window")
+	}
+	if opts.Height == nil && opts.Method != nil {
+		opts.Height = dcrjson.Int(-1)
+	}
+	if opts.Blocks == nil && opts.Height != nil {
+		opts.Blocks = dcrjson.Int(120)
+	}
+	return chainjson.NewGetNetworkHashPSCmd(opts.Blocks, opts.Height,
+		opts.Method, verbosePtr)
+}
+
+// GetNetworkHashPSOptAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
 //
-// See GetNetworkHashPS for the blocking version and more details.
-func (c *Client) GetNetworkHashPSAsync(ctx context.Context) *FutureGetNetworkHashPS {
-	cmd := chainjson.NewGetNetworkHashPSCmd(nil, nil)
+// See GetNetworkHashPSOpt for the blocking version and more details.
+func (c *Client) GetNetworkHashPSOptAsync(ctx context.Context, opts *GetNetworkHashPSOptions) *FutureGetNetworkHashPS {
+	cmd := opts.getNetworkHashPSCmd(false)
 	return (*FutureGetNetworkHashPS)(c.sendCmd(ctx, cmd))
 }
 
-// GetNetworkHashPS returns the estimated network hashes per second using the
-// default number of blocks and the most recent block height.
+// GetNetworkHashPSOpt returns the estimated network hashes per second using the
+// provided options.  Nil options use the default number of blocks working
+// backwards from the most recent block height.
 //
-// See GetNetworkHashPS2 to override the number of blocks to use and
-// GetNetworkHashPS3 to override the height at which to calculate the estimate.
-func (c *Client) GetNetworkHashPS(ctx context.Context) (int64, error) {
-	return c.GetNetworkHashPSAsync(ctx).Receive()
+// See GetNetworkHashPSVerbose to also obtain the confidence interval of the
+// estimate.
+func (c *Client) GetNetworkHashPSOpt(ctx context.Context, opts *GetNetworkHashPSOptions) (int64, error) {
+	return c.GetNetworkHashPSOptAsync(ctx, opts).Receive()
 }
 
-// GetNetworkHashPS2Async returns an instance of a type that can be used to get
-// the result of the RPC at some future time by invoking the Receive function on
-// the returned instance.
-//
-// See GetNetworkHashPS2 for the blocking version and more details.
-func (c *Client) GetNetworkHashPS2Async(ctx context.Context, blocks int) *FutureGetNetworkHashPS {
-	cmd := chainjson.NewGetNetworkHashPSCmd(&blocks, nil)
-	return (*FutureGetNetworkHashPS)(c.sendCmd(ctx, cmd))
+// FutureGetNetworkHashPSVerboseResult is a future promise to deliver the
+// result of a GetNetworkHashPSVerboseAsync RPC invocation (or an applicable
+// error).
+type FutureGetNetworkHashPSVerboseResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// estimated network hashes per second along with its confidence interval.
+func (r *FutureGetNetworkHashPSVerboseResult) Receive() (*chainjson.GetNetworkHashPSResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getnetworkhashps result object.
+	var result chainjson.GetNetworkHashPSResult
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
 }
 
-// GetNetworkHashPS2 returns the estimated network hashes per second for the
-// specified previous number of blocks working backwards from the most recent
-// block height.  The blocks parameter can also be -1 in which case the number
-// of blocks since the last difficulty change will be used.
+// GetNetworkHashPSVerboseAsync returns an instance of a type that can be used
+// to get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
 //
-// See GetNetworkHashPS to use defaults and GetNetworkHashPS3 to override the
-// height at which to calculate the estimate.
-func (c *Client) GetNetworkHashPS2(ctx context.Context, blocks int) (int64, error) {
-	return c.GetNetworkHashPS2Async(ctx, blocks).Receive()
+// See GetNetworkHashPSVerbose for the blocking version and more details.
+func (c *Client) GetNetworkHashPSVerboseAsync(ctx context.Context, opts *GetNetworkHashPSOptions) *FutureGetNetworkHashPSVerboseResult {
+	cmd := opts.getNetworkHashPSCmd(true)
+	return (*FutureGetNetworkHashPSVerboseResult)(c.sendCmd(ctx, cmd))
 }
 
-// GetNetworkHashPS3Async returns an instance of a type that can be used to get
-// the result of the RPC at some future time by invoking the Receive function on
-// the returned instance.
+// GetNetworkHashPSVerbose returns the estimated network hashes per second using
+// the provided options along with the 95% confidence interval of the estimate.
+// Nil options use the defaults of the server.
 //
-// See GetNetworkHashPS3 for the blocking version and more details.
-func (c *Client) GetNetworkHashPS3Async(ctx context.Context, blocks, height int) *FutureGetNetworkHashPS {
-	cmd := chainjson.NewGetNetworkHashPSCmd(&blocks, &height)
-	return (*FutureGetNetworkHashPS)(c.sendCmd(ctx, cmd))
+// NOTE: This is a dcrd extension.
+func (c *Client) GetNetworkHashPSVerbose(ctx context.Context, opts *GetNetworkHashPSOptions) (*chainjson.GetNetworkHashPSResult, error) {
+	return c.GetNetworkHashPSVerboseAsync(ctx, opts).Receive()
 }
 
-// GetNetworkHashPS3 returns the estimated network hashes per second for the
-// specified previous number of blocks working backwards from the specified
-// block height.  The blocks parameter can also be -1 in which case the number
-// of blocks since the last difficulty change will be used.
+// FutureSimulateDifficultyResult is a future promise to deliver the result of
+// a SimulateDifficultyAsync RPC invocation (or an applicable error).
+type FutureSimulateDifficultyResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// projected difficulty retargets for each of the requested hash rates.
+func (r *FutureSimulateDifficultyResult) Receive() (*chainjson.SimulateDifficultyResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a simulatedifficulty result object.
+	var result chainjson.SimulateDifficultyResult
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// SimulateDifficultyAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
 //
-// See GetNetworkHashPS and GetNetworkHashPS2 to use defaults.
-func (c *Client) GetNetworkHashPS3(ctx context.Context, blocks, height int) (int64, error) {
-	return c.GetNetworkHashPS3Async(ctx, blocks, height).Receive()
+// See SimulateDifficulty for the blocking version and more details.
+func (c *Client) SimulateDifficultyAsync(ctx context.Context, hashRateMultipliers []float64, blocks int32) *FutureSimulateDifficultyResult {
+	cmd := chainjson.NewSimulateDifficultyCmd(hashRateMultipliers, &blocks)
+	return (*FutureSimulateDifficultyResult)(c.sendCmd(ctx, cmd))
+}
+
+// SimulateDifficulty returns the projected proof-of-work difficulty at each
+// difficulty retarget within the provided number of blocks after the current
+// best block for each of the provided hypothetical hash rates, which are
+// specified as multiples of the current estimated network hash rate.
+func (c *Client) SimulateDifficulty(ctx context.Context, hashRateMultipliers []float64, blocks int32) (*chainjson.SimulateDifficultyResult, error) {
+	return c.SimulateDifficultyAsync(ctx, hashRateMultipliers, blocks).Receive()
 }
 
 // FutureGetWork is a future promise to deliver the result of a
@@ -320,7 +581,8 @@ func (c *Client) GetWorkAsync(ctx context.Context) *FutureGetWork {
 
 // GetWork returns hash data to work on.
 //
-// See GetWorkSubmit to submit the found solution.
+// See DecodeWorkHeader to update the nonce, extra nonce, and timestamp of the
+// work and GetWorkSubmit to submit the found solution.
 func (c *Client) GetWork(ctx context.Context) (*chainjson.GetWorkResult, error) {
 	return c.GetWorkAsync(ctx).Receive()
 }
@@ -365,6 +627,127 @@ func (c *Client) GetWorkSubmit(ctx context.Context, data string) (bool, error) {
 	return c.GetWorkSubmitAsync(ctx, data).Receive()
 }
 
+const (
+	// getWorkExtraNonceOffset is the offset of the extra nonce in the
+	// decoded data of getwork results.  Decred miners roll the extra nonce
+	// in the extra data of the block header, which starts at this offset,
+	// as opposed to the coinbase transaction.
+	getWorkExtraNonceOffset = 144
+
+	// reservedExtraNonceLen is the number of bytes at the start of the
+	// extra nonce that are reserved by GetWorkWithExtraNonce.  The
+	// remaining bytes of the extra nonce are free for the miner to roll.
+	reservedExtraNonceLen = 4
+)
+
+// FutureGetWorkWithExtraNonce is a future promise to deliver the result of a
+// GetWorkWithExtraNonceAsync RPC invocation (or an applicable error).
+type FutureGetWorkWithExtraNonce struct {
+	future     *FutureGetWork
+	extraNonce uint32
+}
+
+// Receive waits for the response promised by the future and returns the hash
+// data to work on with the reserved extra nonce applied.
+func (r *FutureGetWorkWithExtraNonce) Receive() (*chainjson.GetWorkResult, error) {
+	work, err := r.future.Receive()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := hex.DecodeString(work.Data)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < getWorkExtraNonceOffset+reservedExtraNonceLen {
+		return nil, fmt.Errorf("getwork data is too short (%d bytes)",
+			len(data))
+	}
+	binary.LittleEndian.PutUint32(data[getWorkExtraNonceOffset:], r.extraNonce)
+	work.Data = hex.EncodeToString(data)
+	return work, nil
+}
+
+// GetWorkWithExtraNonceAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See GetWorkWithExtraNonce for the blocking version and more details.
+func (c *Client) GetWorkWithExtraNonceAsync(ctx context.Context, extraNonce uint32) *FutureGetWorkWithExtraNonce {
+	return &FutureGetWorkWithExtraNonce{
+		future:     c.GetWorkAsync(ctx),
+		extraNonce: extraNonce,
+	}
+}
+
+// GetWorkWithExtraNonce returns hash data to work on with the first four bytes
+// of the extra nonce in the block header set to the passed value.  This allows
+// pool software to share a single template among multiple downstream miners by
+// reserving a distinct extra nonce for each of them so that they search
+// disjoint ranges, while the remaining bytes of the extra nonce are rolled by
+// the miners themselves.  Solutions are submitted via GetWorkSubmit as usual
+// and ReservedExtraNonce identifies the miner that found them.
+func (c *Client) GetWorkWithExtraNonce(ctx context.Context, extraNonce uint32) (*chainjson.GetWorkResult, error) {
+	return c.GetWorkWithExtraNonceAsync(ctx, extraNonce).Receive()
+}
+
+// ReservedExtraNonce returns the extra nonce that was reserved via
+// GetWorkWithExtraNonce from the passed hex-encoded getwork data, such as a
+// solution submitted by a downstream miner.
+func ReservedExtraNonce(data string) (uint32, error) {
+	decoded, err := hex.DecodeString(data)
+	if err != nil {
+		return 0, err
+	}
+	if len(decoded) < getWorkExtraNonceOffset+reservedExtraNonceLen {
+		return 0, fmt.Errorf("getwork data is too short (%d bytes)",
+			len(decoded))
+	}
+	return binary.LittleEndian.Uint32(decoded[getWorkExtraNonceOffset:]), nil
+}
+
+// FutureGetWorkStatsResult is a future promise to deliver the result of a
+// GetWorkStatsAsync RPC invocation (or an applicable error).
+type FutureGetWorkStatsResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// getwork statistics of the clients that use getwork.
+func (r *FutureGetWorkStatsResult) Receive() (*chainjson.GetWorkStatsResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getworkstats result object.
+	var stats chainjson.GetWorkStatsResult
+	err = json.Unmarshal(res, &stats)
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// GetWorkStatsAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See GetWorkStats for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetWorkStatsAsync(ctx context.Context) *FutureGetWorkStatsResult {
+	cmd := chainjson.NewGetWorkStatsCmd()
+	return (*FutureGetWorkStatsResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetWorkStats returns the work request rates, solution submissions, and the
+// times of the most recent requests and accepted solutions of each client that
+// uses getwork.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetWorkStats(ctx context.Context) (*chainjson.GetWorkStatsResult, error) {
+	return c.GetWorkStatsAsync(ctx).Receive()
+}
+
 // FutureSubmitBlockResult is a future promise to deliver the result of a
 // SubmitBlockAsync RPC invocation (or an applicable error).
 type FutureSubmitBlockResult cmdRes
@@ -416,6 +799,112 @@ func (c *Client) SubmitBlock(ctx context.Context, block *dcrutil.Block, options
 	return c.SubmitBlockAsync(ctx, block, options).Receive()
 }
 
+// ErrBlockProposalRejected indicates a block proposed via SubmitBlockProposal
+// was rejected by the server.  The returned error wraps it and includes the
+// reason the block was rejected.
+var ErrBlockProposalRejected = errors.New("block proposal rejected")
+
+// FutureGetBlockTemplateResult is a future promise to deliver the result of a
+// GetBlockTemplateAsync RPC invocation (or an applicable error).
+type FutureGetBlockTemplateResult cmdRes
+
+// Receive waits for the response promised by the future and returns the block
+// template.
+func (r *FutureGetBlockTemplateResult) Receive() (*chainjson.GetBlockTemplateResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getblocktemplate result object.
+	var result chainjson.GetBlockTemplateResult
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetBlockTemplateAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetBlockTemplate for the blocking version and more details.
+func (c *Client) GetBlockTemplateAsync(ctx context.Context, request *chainjson.TemplateRequest) *FutureGetBlockTemplateResult {
+	cmd := chainjson.NewGetBlockTemplateCmd(request)
+	return (*FutureGetBlockTemplateResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetBlockTemplate returns a block template external mining software can build
+// a block from.  The request may be nil to use the defaults.
+//
+// When the request contains the long poll id of a previously returned
+// template, the server waits for a new template before responding, so the
+// passed context must allow for it.
+func (c *Client) GetBlockTemplate(ctx context.Context, request *chainjson.TemplateRequest) (*chainjson.GetBlockTemplateResult, error) {
+	return c.GetBlockTemplateAsync(ctx, request).Receive()
+}
+
+// FutureSubmitBlockProposalResult is a future promise to deliver the result of
+// a SubmitBlockProposalAsync RPC invocation (or an applicable error).
+type FutureSubmitBlockProposalResult cmdRes
+
+// Receive waits for the response promised by the future and returns an error
+// that wraps ErrBlockProposalRejected when the proposed block was rejected.
+func (r *FutureSubmitBlockProposalResult) Receive() error {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return err
+	}
+
+	if string(res) != "null" {
+		var reason string
+		err = json.Unmarshal(res, &reason)
+		if err != nil {
+			return err
+		}
+
+		return fmt.Errorf("%w: %s", ErrBlockProposalRejected, reason)
+	}
+
+	return nil
+}
+
+// SubmitBlockProposalAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See SubmitBlockProposal for the blocking version and more details.
+func (c *Client) SubmitBlockProposalAsync(ctx context.Context, block *dcrutil.Block) *FutureSubmitBlockProposalResult {
+	blockHex := ""
+	if block != nil {
+		blockBytes, err := block.Bytes()
+		if err != nil {
+			return (*FutureSubmitBlockProposalResult)(newFutureError(ctx, err))
+		}
+
+		blockHex = hex.EncodeToString(blockBytes)
+	}
+
+	request := &chainjson.TemplateRequest{
+		Mode: "proposal",
+		Data: blockHex,
+	}
+	cmd := chainjson.NewGetBlockTemplateCmd(request)
+	return (*FutureSubmitBlockProposalResult)(c.sendCmd(ctx, cmd))
+}
+
+// SubmitBlockProposal asks the server to fully validate the passed candidate
+// block, with the exception of the proof-of-work requirement, without
+// submitting it to the network.  This allows external mining software to
+// ensure a block it constructed is valid before it starts solving it.
+//
+// It returns nil when the block is valid and an error that wraps
+// ErrBlockProposalRejected along with the reason when it is invalid.
+func (c *Client) SubmitBlockProposal(ctx context.Context, block *dcrutil.Block) error {
+	return c.SubmitBlockProposalAsync(ctx, block).Receive()
+}
+
 // FutureRegenTemplateResult is a future promise to deliver the result of a
 // RegenTemplate RPC invocation (or an applicable error).
 type FutureRegenTemplateResult cmdRes
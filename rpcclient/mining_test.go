@@ -0,0 +1,211 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrd/dcrjson/v3"
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/decred/dcrd/wire"
+)
+
+// TestGetWorkWithExtraNonce ensures the reserved extra nonce is applied to the
+// start of the extra data of the block header in the work returned by
+// GetWorkWithExtraNonce without modifying the remaining data and that it is
+// recovered by ReservedExtraNonce.
+func TestGetWorkWithExtraNonce(t *testing.T) {
+	// Create a server that replies to getwork requests with data that is a
+	// serialized block header with a nonce and extra data that are distinct
+	// from one another padded to the getwork data length.
+	header := wire.BlockHeader{Version: 8, Height: 432100, Nonce: 0xaabbccdd}
+	for i := range header.ExtraData {
+		header.ExtraData[i] = byte(i + 1)
+	}
+	rawHeader, err := header.Bytes()
+	if err != nil {
+		t.Fatalf("unexpected header serialize error: %v", err)
+	}
+	data := make([]byte, 192)
+	copy(data, rawHeader)
+	server, c := newTestHTTPClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"result":{"data":"%x","target":"00"},"error":null,"id":1}`,
+			data)
+	}, nil)
+	defer server.Close()
+	defer c.Shutdown()
+
+	const extraNonce = 0x01020304
+	work, err := c.GetWorkWithExtraNonce(context.Background(), extraNonce)
+	if err != nil {
+		t.Fatalf("unexpected error getting work: %v", err)
+	}
+	workData, err := hex.DecodeString(work.Data)
+	if err != nil {
+		t.Fatalf("unexpected error decoding work data: %v", err)
+	}
+	var workHeader wire.BlockHeader
+	if err := workHeader.FromBytes(workData[:len(rawHeader)]); err != nil {
+		t.Fatalf("unexpected header deserialize error: %v", err)
+	}
+	if workHeader.Nonce != header.Nonce {
+		t.Fatalf("unexpected nonce -- got %x, want %x", workHeader.Nonce,
+			header.Nonce)
+	}
+	wantExtraData := header.ExtraData
+	copy(wantExtraData[:], []byte{0x04, 0x03, 0x02, 0x01})
+	if workHeader.ExtraData != wantExtraData {
+		t.Fatalf("unexpected extra data -- got %x, want %x",
+			workHeader.ExtraData, wantExtraData)
+	}
+	wantHeader := header
+	wantHeader.ExtraData = wantExtraData
+	wantRawHeader, err := wantHeader.Bytes()
+	if err != nil {
+		t.Fatalf("unexpected header serialize error: %v", err)
+	}
+	wantData := make([]byte, len(data))
+	copy(wantData, data)
+	copy(wantData, wantRawHeader)
+	if !bytes.Equal(workData, wantData) {
+		t.Fatalf("unexpected work data -- got %x, want %x", workData,
+			wantData)
+	}
+
+	gotExtraNonce, err := ReservedExtraNonce(work.Data)
+	if err != nil {
+		t.Fatalf("unexpected error getting reserved extra nonce: %v", err)
+	}
+	if gotExtraNonce != extraNonce {
+		t.Fatalf("unexpected reserved extra nonce -- got %x, want %x",
+			gotExtraNonce, extraNonce)
+	}
+	if _, err := ReservedExtraNonce("00"); err == nil {
+		t.Fatal("expected error for short data")
+	}
+}
+
+// TestSubmitBlockProposal ensures blocks are proposed via getblocktemplate in
+// proposal mode and that rejected proposals result in an error that wraps
+// ErrBlockProposalRejected.
+func TestSubmitBlockProposal(t *testing.T) {
+	// Create a server that rejects proposals when the reason is set and
+	// records the parameters of the requests.
+	var reason string
+	var params []json.RawMessage
+	server, c := newTestHTTPClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Params []json.RawMessage `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		params = req.Params
+		result := "null"
+		if reason != "" {
+			result = fmt.Sprintf("%q", reason)
+		}
+		fmt.Fprintf(w, `{"result":%s,"error":null,"id":1}`, result)
+	}, nil)
+	defer server.Close()
+	defer c.Shutdown()
+
+	block := dcrutil.NewBlock(&wire.MsgBlock{})
+	blockBytes, err := block.Bytes()
+	if err != nil {
+		t.Fatalf("unable to serialize block: %v", err)
+	}
+	ctx := context.Background()
+	if err := c.SubmitBlockProposal(ctx, block); err != nil {
+		t.Fatalf("unexpected error for accepted proposal: %v", err)
+	}
+	wantParams := fmt.Sprintf(`{"mode":"proposal","data":"%x"}`, blockBytes)
+	if len(params) != 1 || string(params[0]) != wantParams {
+		t.Fatalf("unexpected params -- got %s, want [%s]", params, wantParams)
+	}
+
+	reason = "block merkle root is invalid"
+	err = c.SubmitBlockProposal(ctx, block)
+	if !errors.Is(err, ErrBlockProposalRejected) ||
+		!strings.Contains(err.Error(), reason) {
+
+		t.Fatalf("unexpected error for rejected proposal: %v", err)
+	}
+}
+
+// TestGetNetworkHashPSOptions ensures getnetworkhashps requests created from
+// options include every set option along with the defaults of the server for
+// any unset options that precede them.
+func TestGetNetworkHashPSOptions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		opts    *GetNetworkHashPSOptions
+		verbose bool
+		want    string
+	}{{
+		name: "nil options",
+		want: `[]`,
+	}, {
+		name: "empty options",
+		opts: &GetNetworkHashPSOptions{},
+		want: `[]`,
+	}, {
+		name: "blocks only",
+		opts: &GetNetworkHashPSOptions{Blocks: dcrjson.Int(-1)},
+		want: `[-1]`,
+	}, {
+		name: "height only",
+		opts: &GetNetworkHashPSOptions{Height: dcrjson.Int(500)},
+		want: `[120,500]`,
+	}, {
+		name: "method only",
+		opts: &GetNetworkHashPSOptions{Method: dcrjson.String("ema")},
+		want: `[120,-1,"ema"]`,
+	}, {
+		name:    "verbose with nil options",
+		verbose: true,
+		want:    `[120,-1,"window",true]`,
+	}, {
+		name: "all options verbose",
+		opts: &GetNetworkHashPSOptions{
+			Blocks: dcrjson.Int(60),
+			Height: dcrjson.Int(1000),
+			Method: dcrjson.String("ema"),
+		},
+		verbose: true,
+		want:    `[60,1000,"ema",true]`,
+	}}
+
+	for _, test := range tests {
+		cmd := test.opts.getNetworkHashPSCmd(test.verbose)
+		marshalled, err := dcrjson.MarshalCmd("1.0", 1, cmd)
+		if err != nil {
+			t.Errorf("%q: unexpected marshal error: %v", test.name, err)
+			continue
+		}
+		var req dcrjson.Request
+		if err := json.Unmarshal(marshalled, &req); err != nil {
+			t.Errorf("%q: unexpected unmarshal error: %v", test.name, err)
+			continue
+		}
+		params, err := json.Marshal(req.Params)
+		if err != nil {
+			t.Errorf("%q: unexpected marshal error: %v", test.name, err)
+			continue
+		}
+		if string(params) != test.want {
+			t.Errorf("%q: unexpected params -- got %s, want %s",
+				test.name, params, test.want)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v2"
+	"github.com/decred/dcrd/wire"
+)
+
+const (
+	// getWorkDataLen is the length of the decoded data of getwork results.
+	// It consists of the serialized block header followed by the internal
+	// blake256 padding.
+	getWorkDataLen = 192
+
+	// getWorkPaddingLen is the length of the internal blake256 padding that
+	// follows the serialized block header in the decoded data of getwork
+	// results.
+	getWorkPaddingLen = getWorkDataLen - wire.MaxBlockHeaderPayload
+
+	// getWorkTargetLen is the length of the decoded target of getwork
+	// results.
+	getWorkTargetLen = 32
+)
+
+// WorkHeader is the data of a getwork result decoded into the block header to
+// solve along with its target.  Miners update the nonce and timestamp via the
+// respective fields of the embedded block header and the extra nonce via
+// SetExtraNonce, and then submit the result of Encode via GetWorkSubmit.
+//
+// Note that the timestamp is encoded with a precision of one second.
+type WorkHeader struct {
+	wire.BlockHeader
+
+	// Target is the target difficulty the hash of the block header must
+	// not exceed to be a solution.
+	Target *big.Int
+
+	// padding is the internal blake256 padding that follows the block
+	// header in the getwork data.
+	padding [getWorkPaddingLen]byte
+}
+
+// DecodeWorkHeader decodes the data and target of the passed getwork result.
+func DecodeWorkHeader(work *chainjson.GetWorkResult) (*WorkHeader, error) {
+	data, err := hex.DecodeString(work.Data)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != getWorkDataLen {
+		return nil, fmt.Errorf("getwork data must be %d bytes (not %d)",
+			getWorkDataLen, len(data))
+	}
+	target, err := hex.DecodeString(work.Target)
+	if err != nil {
+		return nil, err
+	}
+	if len(target) != getWorkTargetLen {
+		return nil, fmt.Errorf("getwork target must be %d bytes (not %d)",
+			getWorkTargetLen, len(target))
+	}
+
+	var h WorkHeader
+	err = h.BlockHeader.Deserialize(bytes.NewReader(
+		data[:wire.MaxBlockHeaderPayload]))
+	if err != nil {
+		return nil, err
+	}
+	copy(h.padding[:], data[wire.MaxBlockHeaderPayload:])
+
+	// The target is a little-endian uint256 so reverse it in order to
+	// convert it to a big integer.
+	for i, j := 0, len(target)-1; i < j; i, j = i+1, j-1 {
+		target[i], target[j] = target[j], target[i]
+	}
+	h.Target = new(big.Int).SetBytes(target)
+	return &h, nil
+}
+
+// ExtraNonce returns the extra nonce, which is stored in the first eight bytes
+// of the extra data of the block header.  The first four bytes of it are those
+// reserved by GetWorkWithExtraNonce.
+func (h *WorkHeader) ExtraNonce() uint64 {
+	return binary.LittleEndian.Uint64(h.ExtraData[:8])
+}
+
+// SetExtraNonce sets the extra nonce, which is stored in the first eight bytes
+// of the extra data of the block header.  Callers that obtained the work via
+// GetWorkWithExtraNonce must preserve the reserved lower 32 bits.
+func (h *WorkHeader) SetExtraNonce(extraNonce uint64) {
+	binary.LittleEndian.PutUint64(h.ExtraData[:8], extraNonce)
+}
+
+// Encode returns the hex-encoded getwork data for the block header, which is
+// suitable for submitting via GetWorkSubmit.
+func (h *WorkHeader) Encode() (string, error) {
+	buf := bytes.NewBuffer(make([]byte, 0, getWorkDataLen))
+	if err := h.BlockHeader.Serialize(buf); err != nil {
+		return "", err
+	}
+	buf.Write(h.padding[:])
+	return hex.EncodeToString(buf.Bytes()), nil
+}
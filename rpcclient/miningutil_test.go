@@ -0,0 +1,123 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v2"
+	"github.com/decred/dcrd/wire"
+)
+
+// TestWorkHeader ensures getwork data is decoded into the block header and
+// target it represents, that updating the nonce, extra nonce, and timestamp
+// is reflected in the encoded data, and that the padding is preserved.
+func TestWorkHeader(t *testing.T) {
+	t.Parallel()
+
+	header := wire.BlockHeader{
+		Version:      7,
+		PrevBlock:    chainhash.Hash{0x01},
+		MerkleRoot:   chainhash.Hash{0x02},
+		StakeRoot:    chainhash.Hash{0x03},
+		VoteBits:     1,
+		Voters:       5,
+		FreshStake:   2,
+		PoolSize:     40960,
+		Bits:         0x1b01ffff,
+		SBits:        2e8,
+		Height:       500000,
+		Size:         12345,
+		Timestamp:    time.Unix(1600000000, 0),
+		Nonce:        0xdeadbeef,
+		StakeVersion: 8,
+	}
+	serialize := func(header *wire.BlockHeader, padding []byte) string {
+		var buf bytes.Buffer
+		if err := header.Serialize(&buf); err != nil {
+			t.Fatalf("unexpected serialize error: %v", err)
+		}
+		buf.Write(padding)
+		return hex.EncodeToString(buf.Bytes())
+	}
+	padding := make([]byte, getWorkPaddingLen)
+	padding[0] = 0x80
+	padding[len(padding)-1] = 0xa0
+	work := &chainjson.GetWorkResult{
+		Data:   serialize(&header, padding),
+		Target: "ffff" + strings.Repeat("00", 30),
+	}
+
+	h, err := DecodeWorkHeader(work)
+	if err != nil {
+		t.Fatalf("unexpected decode error: %v", err)
+	}
+	if h.BlockHeader.BlockHash() != header.BlockHash() {
+		t.Fatalf("unexpected header -- got %+v, want %+v", h.BlockHeader,
+			header)
+	}
+	if h.Target.Cmp(big.NewInt(0xffff)) != 0 {
+		t.Fatalf("unexpected target -- got %x, want ffff", h.Target)
+	}
+	encoded, err := h.Encode()
+	if err != nil {
+		t.Fatalf("unexpected encode error: %v", err)
+	}
+	if encoded != work.Data {
+		t.Fatalf("unexpected unmodified data -- got %s, want %s", encoded,
+			work.Data)
+	}
+
+	// Ensure updates to the nonce, extra nonce, and timestamp are encoded
+	// at their expected locations.
+	h.Nonce = 0x01020304
+	h.Timestamp = time.Unix(1600000123, 0)
+	h.SetExtraNonce(0x1122334455667788)
+	if h.ExtraNonce() != 0x1122334455667788 {
+		t.Fatalf("unexpected extra nonce -- got %x", h.ExtraNonce())
+	}
+	encoded, err = h.Encode()
+	if err != nil {
+		t.Fatalf("unexpected encode error: %v", err)
+	}
+	want := header
+	want.Nonce = 0x01020304
+	want.Timestamp = time.Unix(1600000123, 0)
+	copy(want.ExtraData[:], []byte{0x88, 0x77, 0x66, 0x55, 0x44, 0x33,
+		0x22, 0x11})
+	if wantData := serialize(&want, padding); encoded != wantData {
+		t.Fatalf("unexpected data -- got %s, want %s", encoded, wantData)
+	}
+
+	// Ensure the extra nonce reserved via GetWorkWithExtraNonce is the
+	// lower 32 bits of the extra nonce.
+	reserved, err := ReservedExtraNonce(encoded)
+	if err != nil {
+		t.Fatalf("unexpected error getting reserved extra nonce: %v", err)
+	}
+	if reserved != 0x55667788 {
+		t.Fatalf("unexpected reserved extra nonce -- got %x, want "+
+			"55667788", reserved)
+	}
+
+	// Ensure invalid getwork results are rejected.
+	invalid := []*chainjson.GetWorkResult{
+		{Data: "zz", Target: work.Target},
+		{Data: work.Data[:len(work.Data)-2], Target: work.Target},
+		{Data: work.Data, Target: "zz"},
+		{Data: work.Data, Target: "ffff"},
+	}
+	for i, work := range invalid {
+		if _, err := DecodeWorkHeader(work); err == nil {
+			t.Errorf("#%d: did not receive expected error", i)
+		}
+	}
+}
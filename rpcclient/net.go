@@ -285,3 +285,45 @@ func (c *Client) GetNetTotalsAsync(ctx context.Context) *FutureGetNetTotalsResul
 func (c *Client) GetNetTotals(ctx context.Context) (*chainjson.GetNetTotalsResult, error) {
 	return c.GetNetTotalsAsync(ctx).Receive()
 }
+
+// FutureGetSyncPeerResult is a future promise to deliver the result of a
+// GetSyncPeerAsync RPC invocation (or an applicable error).
+type FutureGetSyncPeerResult cmdRes
+
+// Receive waits for the response promised by the future and returns details
+// about the current sync peer along with the most recent changes to it.
+func (r *FutureGetSyncPeerResult) Receive() (*chainjson.GetSyncPeerResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getsyncpeer result object.
+	var syncPeer chainjson.GetSyncPeerResult
+	err = json.Unmarshal(res, &syncPeer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &syncPeer, nil
+}
+
+// GetSyncPeerAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See GetSyncPeer for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetSyncPeerAsync(ctx context.Context) *FutureGetSyncPeerResult {
+	cmd := chainjson.NewGetSyncPeerCmd()
+	return (*FutureGetSyncPeerResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetSyncPeer returns details about the peer currently being synced with along
+// with the most recent changes to it.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetSyncPeer(ctx context.Context) (*chainjson.GetSyncPeerResult, error) {
+	return c.GetSyncPeerAsync(ctx).Receive()
+}
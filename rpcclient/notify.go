@@ -11,9 +11,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 
 	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrjson/v3"
 	"github.com/decred/dcrd/dcrutil/v3"
 	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v2"
 	"github.com/decred/dcrd/wire"
@@ -33,6 +35,8 @@ var (
 // reconnect.
 type notificationState struct {
 	notifyBlocks                bool
+	notifyBlocksStakeEvents     bool
+	notifyFilteredBlocks        bool
 	notifyWork                  bool
 	notifyWinningTickets        bool
 	notifySpentAndMissedTickets bool
@@ -40,12 +44,84 @@ type notificationState struct {
 	notifyStakeDifficulty       bool
 	notifyNewTx                 bool
 	notifyNewTxVerbose          bool
+	notifyDiskSpace             bool
+
+	// txFilter houses the addresses and outpoints loaded into the
+	// transaction filter of the client.  It is nil when no filter has been
+	// loaded.
+	txFilter *txFilterState
+}
+
+// txFilterState tracks the addresses and outpoints that have been loaded into
+// the transaction filter of a websocket client.
+type txFilterState struct {
+	addresses map[string]struct{}
+	outPoints map[chainjson.OutPoint]struct{}
+}
+
+// newTxFilterState returns a new empty transaction filter state.
+func newTxFilterState() *txFilterState {
+	return &txFilterState{
+		addresses: make(map[string]struct{}),
+		outPoints: make(map[chainjson.OutPoint]struct{}),
+	}
+}
+
+// add adds the addresses and outpoints of the passed loadtxfilter command to
+// the filter state.
+func (f *txFilterState) add(cmd *chainjson.LoadTxFilterCmd) {
+	for _, addr := range cmd.Addresses {
+		f.addresses[addr] = struct{}{}
+	}
+	for _, op := range cmd.OutPoints {
+		f.outPoints[op] = struct{}{}
+	}
+}
+
+// Copy returns a deep copy of the receiver.
+func (f *txFilterState) Copy() *txFilterState {
+	filterCopy := newTxFilterState()
+	for addr := range f.addresses {
+		filterCopy.addresses[addr] = struct{}{}
+	}
+	for op := range f.outPoints {
+		filterCopy.outPoints[op] = struct{}{}
+	}
+	return filterCopy
+}
+
+// loadCmd returns a loadtxfilter command that reloads the transaction filter
+// with all of the addresses and outpoints of the filter state.  The addresses
+// and outpoints are sorted so the command is deterministic.
+func (f *txFilterState) loadCmd() *chainjson.LoadTxFilterCmd {
+	addrs := make([]string, 0, len(f.addresses))
+	for addr := range f.addresses {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	outPoints := make([]chainjson.OutPoint, 0, len(f.outPoints))
+	for op := range f.outPoints {
+		outPoints = append(outPoints, op)
+	}
+	sort.Slice(outPoints, func(i, j int) bool {
+		a, b := &outPoints[i], &outPoints[j]
+		switch {
+		case a.Hash != b.Hash:
+			return a.Hash < b.Hash
+		case a.Index != b.Index:
+			return a.Index < b.Index
+		}
+		return a.Tree < b.Tree
+	})
+	return chainjson.NewLoadTxFilterCmd(true, addrs, outPoints)
 }
 
 // Copy returns a deep copy of the receiver.
 func (s *notificationState) Copy() *notificationState {
 	var stateCopy notificationState
 	stateCopy.notifyBlocks = s.notifyBlocks
+	stateCopy.notifyBlocksStakeEvents = s.notifyBlocksStakeEvents
+	stateCopy.notifyFilteredBlocks = s.notifyFilteredBlocks
 	stateCopy.notifyWork = s.notifyWork
 	stateCopy.notifyWinningTickets = s.notifyWinningTickets
 	stateCopy.notifySpentAndMissedTickets = s.notifySpentAndMissedTickets
@@ -53,6 +129,10 @@ func (s *notificationState) Copy() *notificationState {
 	stateCopy.notifyStakeDifficulty = s.notifyStakeDifficulty
 	stateCopy.notifyNewTx = s.notifyNewTx
 	stateCopy.notifyNewTxVerbose = s.notifyNewTxVerbose
+	stateCopy.notifyDiskSpace = s.notifyDiskSpace
+	if s.txFilter != nil {
+		stateCopy.txFilter = s.txFilter.Copy()
+	}
 
 	return &stateCopy
 }
@@ -72,33 +152,167 @@ func newNilFutureResult(ctx context.Context) *cmdRes {
 	return &cmdRes{ctx: ctx, c: responseChan}
 }
 
+// BlockHeaderNtfnData houses a serialized block header delivered by a
+// notification and decodes it on demand so handlers that do not need the
+// decoded header do not pay the cost of decoding it.
+//
+// It is not safe for concurrent access.
+type BlockHeaderNtfnData struct {
+	rawHeader []byte
+	header    *wire.BlockHeader
+}
+
+// RawHeader returns the serialized block header.
+func (d *BlockHeaderNtfnData) RawHeader() []byte {
+	return d.rawHeader
+}
+
+// Header returns the decoded block header.  The header is only decoded the
+// first time it is requested.
+func (d *BlockHeaderNtfnData) Header() (*wire.BlockHeader, error) {
+	if d.header == nil {
+		var header wire.BlockHeader
+		if err := header.FromBytes(d.rawHeader); err != nil {
+			return nil, err
+		}
+		d.header = &header
+	}
+	return d.header, nil
+}
+
+// BlockConnectedNtfnData houses the details of a blockconnected notification.
+// The block header and transactions are decoded on demand so handlers that do
+// not need them decoded do not pay the cost of decoding them.
+//
+// It is not safe for concurrent access.
+type BlockConnectedNtfnData struct {
+	BlockHeaderNtfnData
+	rawTxns [][]byte
+	txns    []*dcrutil.Tx
+}
+
+// RawTransactions returns the serialized transactions that matched the
+// transaction filter of the client.
+func (d *BlockConnectedNtfnData) RawTransactions() [][]byte {
+	return d.rawTxns
+}
+
+// Transactions returns the decoded transactions that matched the transaction
+// filter of the client.  The transactions are only decoded the first time they
+// are requested.
+func (d *BlockConnectedNtfnData) Transactions() ([]*dcrutil.Tx, error) {
+	if d.txns == nil {
+		txns := make([]*dcrutil.Tx, 0, len(d.rawTxns))
+		for _, rawTx := range d.rawTxns {
+			tx, err := dcrutil.NewTxFromBytes(rawTx)
+			if err != nil {
+				return nil, err
+			}
+			txns = append(txns, tx)
+		}
+		d.txns = txns
+	}
+	return d.txns, nil
+}
+
+// RelevantTxAcceptedNtfnData houses the details of a relevanttxaccepted
+// notification.  The transaction is decoded on demand so handlers that do not
+// need it decoded do not pay the cost of decoding it.
+//
+// It is not safe for concurrent access.
+type RelevantTxAcceptedNtfnData struct {
+	rawTx []byte
+	tx    *dcrutil.Tx
+}
+
+// RawTransaction returns the serialized transaction.
+func (d *RelevantTxAcceptedNtfnData) RawTransaction() []byte {
+	return d.rawTx
+}
+
+// Transaction returns the decoded transaction.  The transaction is only
+// decoded the first time it is requested.
+func (d *RelevantTxAcceptedNtfnData) Transaction() (*dcrutil.Tx, error) {
+	if d.tx == nil {
+		tx, err := dcrutil.NewTxFromBytes(d.rawTx)
+		if err != nil {
+			return nil, err
+		}
+		d.tx = tx
+	}
+	return d.tx, nil
+}
+
 // NotificationHandlers defines callback function pointers to invoke with
 // notifications.  Since all of the functions are nil by default, all
 // notifications are effectively ignored until their handlers are set to a
 // concrete callback.
 //
+// The handlers are invoked by a notification goroutine in the order the
+// notifications were received, but not in order with the responses to
+// requests, so a request may complete before the notifications the server sent
+// prior to its response have been delivered.
+//
 // NOTE: Unless otherwise documented, these handlers must NOT directly call any
 // blocking calls on the client instance since the input reader goroutine blocks
-// until the callback has completed.  Doing so will result in a deadlock
-// situation.
+// once the notification queue is full until the callbacks catch up.  Doing so
+// may result in a deadlock situation.
 type NotificationHandlers struct {
 	// OnClientConnected is invoked when the client connects or reconnects
 	// to the RPC server.  This callback is run async with the rest of the
 	// notification handlers, and is safe for blocking client requests.
 	OnClientConnected func()
 
+	// OnRegistrationReplayed is invoked after the client reconnects to the
+	// RPC server once all of the notifications that were previously
+	// registered, along with the transaction filter, have been registered
+	// with the server again.  It is provided with the methods of the
+	// replayed registrations, which is empty when there were none.  Since
+	// notifications are not delivered while the client is disconnected,
+	// this is the point at which callers should resynchronize any state
+	// derived from them.  This callback is run async with the rest of the
+	// notification handlers, and is safe for blocking client requests.
+	OnRegistrationReplayed func(methods []string)
+
 	// OnBlockConnected is invoked when a block is connected to the longest
 	// (best) chain.  It will only be invoked if a preceding call to
 	// NotifyBlocks has been made to register for the notification and the
 	// function is non-nil.
 	OnBlockConnected func(blockHeader []byte, transactions [][]byte)
 
+	// OnBlockConnectedData is invoked under the same conditions as
+	// OnBlockConnected, but provides the notification details as a single
+	// data structure that decodes the block header and transactions on
+	// demand.  Both handlers are invoked when they are non-nil.
+	OnBlockConnectedData func(data *BlockConnectedNtfnData)
+
+	// OnBlockStakeEvents is invoked when a block is connected to the longest
+	// (best) chain with a summary of the ticket purchases, votes, and
+	// revocations in the block.  It will only be invoked if a preceding call
+	// to NotifyBlocksStakeEvents has been made to register for the
+	// notification and the function is non-nil.
+	OnBlockStakeEvents func(blockHeader []byte, stakeEvents *chainjson.BlockStakeEvents)
+
+	// OnFilteredBlockConnected is invoked when a block is connected to the
+	// longest (best) chain with only the transactions that match the
+	// transaction filter of the client along with merkle inclusion proofs for
+	// them.  It will only be invoked if a preceding call to
+	// NotifyFilteredBlocks has been made to register for the notification and
+	// the function is non-nil.
+	OnFilteredBlockConnected func(blockHeader []byte, transactions []chainjson.FilteredBlockTx)
+
 	// OnBlockDisconnected is invoked when a block is disconnected from the
 	// longest (best) chain.  It will only be invoked if a preceding call to
-	// NotifyBlocks has been made to register for the notification and the
-	// function is non-nil.
+	// NotifyBlocks or NotifyFilteredBlocks has been made to register for the
+	// notification and the function is non-nil.
 	OnBlockDisconnected func(blockHeader []byte)
 
+	// OnBlockDisconnectedData is invoked under the same conditions as
+	// OnBlockDisconnected, but provides the notification details as a data
+	// structure that decodes the block header on demand.  Both handlers are
+	// invoked when they are non-nil.
+	OnBlockDisconnectedData func(data *BlockHeaderNtfnData)
+
 	// OnWork is invoked when a new block template is generated.
 	// It will only be invoked if a preceding call to NotifyWork has
 	// been made to register for the notification and the function is non-nil.
@@ -108,12 +322,32 @@ type NotificationHandlers struct {
 	// the client's transaction filter.
 	OnRelevantTxAccepted func(transaction []byte)
 
+	// OnRelevantTxAcceptedData is invoked under the same conditions as
+	// OnRelevantTxAccepted, but provides the notification details as a data
+	// structure that decodes the transaction on demand.  Both handlers are
+	// invoked when they are non-nil.
+	OnRelevantTxAcceptedData func(data *RelevantTxAcceptedNtfnData)
+
+	// OnRelevantTxUnmined is invoked when a transaction that passes the
+	// client's transaction filter returned to the mempool because the block
+	// that mined it was disconnected from the main chain.  When block
+	// notifications are requested, the handlers for the disconnected block
+	// are invoked first.
+	OnRelevantTxUnmined func(transaction []byte)
+
 	// OnReorganization is invoked when the blockchain begins reorganizing.
 	// It will only be invoked if a preceding call to NotifyBlocks has been
 	// made to register for the notification and the function is non-nil.
 	OnReorganization func(oldHash *chainhash.Hash, oldHeight int32,
 		newHash *chainhash.Hash, newHeight int32)
 
+	// OnDiskSpace is invoked when the free disk space available to the data
+	// directory of the server crosses one of its configured thresholds.  The
+	// level is one of "ok", "low", or "critical" and all sizes are in bytes.
+	// It will only be invoked if a preceding call to NotifyDiskSpace has been
+	// made to register for the notification and the function is non-nil.
+	OnDiskSpace func(level string, free, warnThreshold, stopThreshold uint64)
+
 	// OnWinningTickets is invoked when a block is connected and eligible tickets
 	// to be voted on for this chain are given.  It will only be invoked if a
 	// preceding call to NotifyWinningTickets has been made to register for the
@@ -148,6 +382,19 @@ type NotificationHandlers struct {
 		height int64,
 		stakeDiff int64)
 
+	// OnStakeDifficultyChange is invoked when a block that completes a
+	// stake difficulty window is connected to the longest (best) chain,
+	// meaning the next block requires a newly calculated stake difficulty.
+	// The old stake difficulty is the one required by the connected block.
+	// It will only be invoked if a preceding call to NotifyStakeDifficulty
+	// has been made to register for the notification and the function is
+	// non-nil.
+	OnStakeDifficultyChange func(hash *chainhash.Hash,
+		height int64,
+		oldStakeDiff int64,
+		newStakeDiff int64,
+		nextRetargetHeight int64)
+
 	// OnTxAccepted is invoked when a transaction is accepted into the
 	// memory pool.  It will only be invoked if a preceding call to
 	// NotifyNewTransactions with the verbose flag set to false has been
@@ -160,6 +407,11 @@ type NotificationHandlers struct {
 	// made to register for the notification and the function is non-nil.
 	OnTxAcceptedVerbose func(txDetails *chainjson.TxRawResult)
 
+	// Wallet houses the handlers for the notifications sent by the websocket
+	// JSON-RPC server of dcrwallet when the client is connected to it.  It
+	// may be nil when the client is connected to dcrd.
+	Wallet *WalletNotificationHandlers
+
 	// OnUnknownNotification is invoked when an unrecognized notification
 	// is received.  This typically means the notification handling code
 	// for this package needs to be updated for a new notification type or
@@ -179,30 +431,68 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 		return
 	}
 
+	// Deliver the notification to any channels subscribed to it.
+	c.notifySubscribers(ntfn)
+
 	// Handle chain notifications.
 	switch chainjson.Method(ntfn.Method) {
 	// OnBlockConnected
 	case chainjson.BlockConnectedNtfnMethod:
 		// Ignore the notification if the client is not interested in
 		// it.
-		if c.ntfnHandlers.OnBlockConnected == nil {
+		if c.ntfnHandlers.OnBlockConnected == nil &&
+			c.ntfnHandlers.OnBlockConnectedData == nil &&
+			c.ntfnHandlers.OnBlockStakeEvents == nil {
 			return
 		}
 
-		blockHeader, transactions, err := parseBlockConnectedParams(ntfn.Params)
+		blockHeader, transactions, stakeEvents, err :=
+			parseBlockConnectedParams(ntfn.Params)
 		if err != nil {
 			log.Warnf("Received invalid blockconnected "+
 				"notification: %v", err)
 			return
 		}
 
-		c.ntfnHandlers.OnBlockConnected(blockHeader, transactions)
+		if c.ntfnHandlers.OnBlockConnected != nil {
+			c.ntfnHandlers.OnBlockConnected(blockHeader, transactions)
+		}
+		if c.ntfnHandlers.OnBlockConnectedData != nil {
+			c.ntfnHandlers.OnBlockConnectedData(&BlockConnectedNtfnData{
+				BlockHeaderNtfnData: BlockHeaderNtfnData{
+					rawHeader: blockHeader,
+				},
+				rawTxns: transactions,
+			})
+		}
+		if c.ntfnHandlers.OnBlockStakeEvents != nil && stakeEvents != nil {
+			c.ntfnHandlers.OnBlockStakeEvents(blockHeader, stakeEvents)
+		}
+
+	// OnFilteredBlockConnected
+	case chainjson.FilteredBlockConnectedNtfnMethod:
+		// Ignore the notification if the client is not interested in
+		// it.
+		if c.ntfnHandlers.OnFilteredBlockConnected == nil {
+			return
+		}
+
+		blockHeader, transactions, err :=
+			parseFilteredBlockConnectedParams(ntfn.Params)
+		if err != nil {
+			log.Warnf("Received invalid filteredblockconnected "+
+				"notification: %v", err)
+			return
+		}
+
+		c.ntfnHandlers.OnFilteredBlockConnected(blockHeader, transactions)
 
 	// OnBlockDisconnected
 	case chainjson.BlockDisconnectedNtfnMethod:
 		// Ignore the notification if the client is not interested in
 		// it.
-		if c.ntfnHandlers.OnBlockDisconnected == nil {
+		if c.ntfnHandlers.OnBlockDisconnected == nil &&
+			c.ntfnHandlers.OnBlockDisconnectedData == nil {
 			return
 		}
 
@@ -213,7 +503,14 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 			return
 		}
 
-		c.ntfnHandlers.OnBlockDisconnected(blockHeader)
+		if c.ntfnHandlers.OnBlockDisconnected != nil {
+			c.ntfnHandlers.OnBlockDisconnected(blockHeader)
+		}
+		if c.ntfnHandlers.OnBlockDisconnectedData != nil {
+			c.ntfnHandlers.OnBlockDisconnectedData(&BlockHeaderNtfnData{
+				rawHeader: blockHeader,
+			})
+		}
 
 	// OnWork
 	case chainjson.WorkNtfnMethod:
@@ -234,7 +531,8 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 	case chainjson.RelevantTxAcceptedNtfnMethod:
 		// Ignore the notification if the client is not interested in
 		// it.
-		if c.ntfnHandlers.OnRelevantTxAccepted == nil {
+		if c.ntfnHandlers.OnRelevantTxAccepted == nil &&
+			c.ntfnHandlers.OnRelevantTxAcceptedData == nil {
 			return
 		}
 
@@ -245,7 +543,31 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 			return
 		}
 
-		c.ntfnHandlers.OnRelevantTxAccepted(transaction)
+		if c.ntfnHandlers.OnRelevantTxAccepted != nil {
+			c.ntfnHandlers.OnRelevantTxAccepted(transaction)
+		}
+		if c.ntfnHandlers.OnRelevantTxAcceptedData != nil {
+			c.ntfnHandlers.OnRelevantTxAcceptedData(&RelevantTxAcceptedNtfnData{
+				rawTx: transaction,
+			})
+		}
+
+	// OnRelevantTxUnmined
+	case chainjson.RelevantTxUnminedNtfnMethod:
+		// Ignore the notification if the client is not interested in
+		// it.
+		if c.ntfnHandlers.OnRelevantTxUnmined == nil {
+			return
+		}
+
+		transaction, err := parseRelevantTxUnminedParams(ntfn.Params)
+		if err != nil {
+			log.Warnf("Received invalid relevanttxunmined "+
+				"notification: %v", err)
+			return
+		}
+
+		c.ntfnHandlers.OnRelevantTxUnmined(transaction)
 
 	// OnReorganization
 	case chainjson.ReorganizationNtfnMethod:
@@ -265,6 +587,23 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 
 		c.ntfnHandlers.OnReorganization(oldHash, oldHeight, newHash, newHeight)
 
+	// OnDiskSpace
+	case chainjson.DiskSpaceNtfnMethod:
+		// Ignore the notification if the client is not interested in
+		// it.
+		if c.ntfnHandlers.OnDiskSpace == nil {
+			return
+		}
+
+		diskSpace, err := parseDiskSpaceNtfnParams(ntfn.Params)
+		if err != nil {
+			log.Warnf("Received invalid disk space notification: %v", err)
+			return
+		}
+
+		c.ntfnHandlers.OnDiskSpace(diskSpace.Level, diskSpace.Free,
+			diskSpace.WarnThreshold, diskSpace.StopThreshold)
+
 	// OnWinningTickets
 	case chainjson.WinningTicketsNtfnMethod:
 		// Ignore the notification if the client is not interested in
@@ -345,6 +684,29 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 			blockHeight,
 			stakeDiff)
 
+	// OnStakeDifficultyChange
+	case chainjson.StakeDifficultyChangeNtfnMethod:
+		// Ignore the notification if the client is not interested in
+		// it.
+		if c.ntfnHandlers.OnStakeDifficultyChange == nil {
+			return
+		}
+
+		blockHash, blockHeight, oldStakeDiff, newStakeDiff,
+			nextRetargetHeight, err :=
+			parseStakeDifficultyChangeNtfnParams(ntfn.Params)
+		if err != nil {
+			log.Warnf("Received invalid stake difficulty change "+
+				"notification: %v", err)
+			return
+		}
+
+		c.ntfnHandlers.OnStakeDifficultyChange(blockHash,
+			blockHeight,
+			oldStakeDiff,
+			newStakeDiff,
+			nextRetargetHeight)
+
 	// OnTxAccepted
 	case chainjson.TxAcceptedNtfnMethod:
 		// Ignore the notification if the client is not interested in
@@ -380,6 +742,10 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 		c.ntfnHandlers.OnTxAcceptedVerbose(rawTx)
 
 	default:
+		if c.handleWalletNotification(ntfn) {
+			return
+		}
+
 		if c.ntfnHandlers.OnUnknownNotification == nil {
 			log.Tracef("unknown notification received")
 			return
@@ -410,31 +776,40 @@ func parseHexParam(param json.RawMessage) ([]byte, error) {
 }
 
 // parseBlockConnectedParams parses out the parameters included in a
-// blockconnected notification.
-func parseBlockConnectedParams(params []json.RawMessage) (blockHeader []byte, transactions [][]byte, err error) {
-	if len(params) != 2 {
-		return nil, nil, wrongNumParams(len(params))
+// blockconnected notification.  The stake events are nil when the notification
+// does not include them.
+func parseBlockConnectedParams(params []json.RawMessage) (blockHeader []byte, transactions [][]byte, stakeEvents *chainjson.BlockStakeEvents, err error) {
+	if len(params) != 2 && len(params) != 3 {
+		return nil, nil, nil, wrongNumParams(len(params))
 	}
 
 	blockHeader, err = parseHexParam(params[0])
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	var hexTransactions []string
 	err = json.Unmarshal(params[1], &hexTransactions)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 	transactions = make([][]byte, len(hexTransactions))
 	for i, hexTx := range hexTransactions {
 		transactions[i], err = hex.DecodeString(hexTx)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 	}
 
-	return blockHeader, transactions, nil
+	if len(params) == 3 {
+		stakeEvents = new(chainjson.BlockStakeEvents)
+		err = json.Unmarshal(params[2], stakeEvents)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	return blockHeader, transactions, stakeEvents, nil
 }
 
 // parseWorkParams parses out the parameters included in a
@@ -462,6 +837,26 @@ func parseWorkParams(params []json.RawMessage) (data, target []byte, reason stri
 	return data, target, reason, nil
 }
 
+// parseFilteredBlockConnectedParams parses out the parameters included in a
+// filteredblockconnected notification.
+func parseFilteredBlockConnectedParams(params []json.RawMessage) (blockHeader []byte, transactions []chainjson.FilteredBlockTx, err error) {
+	if len(params) != 2 {
+		return nil, nil, wrongNumParams(len(params))
+	}
+
+	blockHeader, err = parseHexParam(params[0])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = json.Unmarshal(params[1], &transactions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return blockHeader, transactions, nil
+}
+
 // parseBlockDisconnectedParams parses out the parameters included in a
 // blockdisconnected notification.
 func parseBlockDisconnectedParams(params []json.RawMessage) (blockHeader []byte, err error) {
@@ -482,6 +877,42 @@ func parseRelevantTxAcceptedParams(params []json.RawMessage) (transaction []byte
 	return parseHexParam(params[0])
 }
 
+// parseRelevantTxUnminedParams parses out the parameter included in a
+// relevanttxunmined notification.
+func parseRelevantTxUnminedParams(params []json.RawMessage) (transaction []byte, err error) {
+	if len(params) != 1 {
+		return nil, wrongNumParams(len(params))
+	}
+
+	return parseHexParam(params[0])
+}
+
+// parseDiskSpaceNtfnParams parses out the level, free disk space, and
+// thresholds from the parameters of a diskspace notification.
+func parseDiskSpaceNtfnParams(params []json.RawMessage) (*chainjson.DiskSpaceNtfn, error) {
+	if len(params) != 4 {
+		return nil, wrongNumParams(len(params))
+	}
+
+	// Unmarshal first parameter as a string.
+	var ntfn chainjson.DiskSpaceNtfn
+	err := json.Unmarshal(params[0], &ntfn.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the remaining parameters as integers.
+	sizes := []*uint64{&ntfn.Free, &ntfn.WarnThreshold, &ntfn.StopThreshold}
+	for i, size := range sizes {
+		err = json.Unmarshal(params[i+1], size)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &ntfn, nil
+}
+
 func parseReorganizationNtfnParams(params []json.RawMessage) (*chainhash.Hash,
 	int32, *chainhash.Hash, int32, error) {
 	errorOut := func(err error) (*chainhash.Hash, int32, *chainhash.Hash,
@@ -588,9 +1019,18 @@ func parseWinningTicketsNtfnParams(params []json.RawMessage) (
 		if err != nil {
 			return nil, 0, nil, err
 		}
+		if itr < 0 || itr >= len(t) {
+			return nil, 0, nil, fmt.Errorf("ticket index %d out of "+
+				"range", itr)
+		}
 
 		t[itr] = ticketHash
 	}
+	for i := range t {
+		if t[i] == nil {
+			return nil, 0, nil, fmt.Errorf("missing ticket index %d", i)
+		}
+	}
 
 	return bHash, bHeight, t, nil
 }
@@ -761,6 +1201,63 @@ func parseStakeDifficultyNtfnParams(params []json.RawMessage) (
 	return bHash, bHeight, stakeDiff, nil
 }
 
+// parseStakeDifficultyChangeNtfnParams parses out the block hash, block
+// height, old and new stake difficulties, and the height of the next retarget
+// from a StakeDifficultyChange notification.
+func parseStakeDifficultyChangeNtfnParams(params []json.RawMessage) (
+	*chainhash.Hash,
+	int64,
+	int64,
+	int64,
+	int64,
+	error) {
+
+	if len(params) != 5 {
+		return nil, 0, 0, 0, 0, wrongNumParams(len(params))
+	}
+
+	// Unmarshal first parameter as a string.
+	var blockHashStr string
+	err := json.Unmarshal(params[0], &blockHashStr)
+	if err != nil {
+		return nil, 0, 0, 0, 0, err
+	}
+
+	// Create hash from block hash string.
+	bHash, err := chainhash.NewHashFromStr(blockHashStr)
+	if err != nil {
+		return nil, 0, 0, 0, 0, err
+	}
+
+	// Unmarshal second parameter as an integer.
+	var blockHeight int32
+	err = json.Unmarshal(params[1], &blockHeight)
+	if err != nil {
+		return nil, 0, 0, 0, 0, err
+	}
+
+	// Unmarshal third and fourth parameters as integers.
+	var oldStakeDiff, newStakeDiff int64
+	err = json.Unmarshal(params[2], &oldStakeDiff)
+	if err != nil {
+		return nil, 0, 0, 0, 0, err
+	}
+	err = json.Unmarshal(params[3], &newStakeDiff)
+	if err != nil {
+		return nil, 0, 0, 0, 0, err
+	}
+
+	// Unmarshal fifth parameter as an integer.
+	var nextRetargetHeight int32
+	err = json.Unmarshal(params[4], &nextRetargetHeight)
+	if err != nil {
+		return nil, 0, 0, 0, 0, err
+	}
+
+	return bHash, int64(blockHeight), oldStakeDiff, newStakeDiff,
+		int64(nextRetargetHeight), nil
+}
+
 // parseTxAcceptedNtfnParams parses out the transaction hash and total amount
 // from the parameters of a txaccepted notification.
 func parseTxAcceptedNtfnParams(params []json.RawMessage) (*chainhash.Hash,
@@ -862,10 +1359,82 @@ func (c *Client) NotifyBlocksAsync(ctx context.Context) *FutureNotifyBlocksResul
 		return (*FutureNotifyBlocksResult)(newNilFutureResult(ctx))
 	}
 
-	cmd := chainjson.NewNotifyBlocksCmd()
+	cmd := chainjson.NewNotifyBlocksCmd(nil)
+	return (*FutureNotifyBlocksResult)(c.sendCmd(ctx, cmd))
+}
+
+// NotifyBlocksStakeEventsAsync returns an instance of a type that can be used
+// to get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See NotifyBlocksStakeEvents for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension and requires a websocket connection.
+func (c *Client) NotifyBlocksStakeEventsAsync(ctx context.Context) *FutureNotifyBlocksResult {
+	// Not supported in HTTP POST mode.
+	if c.config.HTTPPostMode {
+		return (*FutureNotifyBlocksResult)(newFutureError(ctx, ErrWebsocketsRequired))
+	}
+
+	// Ignore the notification if the client is not interested in
+	// notifications.
+	if c.ntfnHandlers == nil {
+		return (*FutureNotifyBlocksResult)(newNilFutureResult(ctx))
+	}
+
+	cmd := chainjson.NewNotifyBlocksCmd(dcrjson.Bool(true))
 	return (*FutureNotifyBlocksResult)(c.sendCmd(ctx, cmd))
 }
 
+// FutureNotifyFilteredBlocksResult is a future promise to deliver the result
+// of a NotifyFilteredBlocksAsync RPC invocation (or an applicable error).
+type FutureNotifyFilteredBlocksResult cmdRes
+
+// Receive waits for the response promised by the future and returns an error
+// if the registration was not successful.
+func (r *FutureNotifyFilteredBlocksResult) Receive() error {
+	_, err := receiveFuture(r.ctx, r.c)
+	return err
+}
+
+// NotifyFilteredBlocksAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See NotifyFilteredBlocks for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension and requires a websocket connection.
+func (c *Client) NotifyFilteredBlocksAsync(ctx context.Context) *FutureNotifyFilteredBlocksResult {
+	// Not supported in HTTP POST mode.
+	if c.config.HTTPPostMode {
+		return (*FutureNotifyFilteredBlocksResult)(newFutureError(ctx, ErrWebsocketsRequired))
+	}
+
+	// Ignore the notification if the client is not interested in
+	// notifications.
+	if c.ntfnHandlers == nil {
+		return (*FutureNotifyFilteredBlocksResult)(newNilFutureResult(ctx))
+	}
+
+	cmd := chainjson.NewNotifyFilteredBlocksCmd()
+	return (*FutureNotifyFilteredBlocksResult)(c.sendCmd(ctx, cmd))
+}
+
+// NotifyFilteredBlocks registers the client to receive notifications when
+// blocks are connected and disconnected from the main chain.  The notifications
+// for connected blocks only include the transactions that match the
+// transaction filter loaded with LoadTxFilter along with merkle inclusion
+// proofs for them, which allows them to be used to incrementally sync the
+// state of a wallet.
+//
+// The notifications delivered as a result of this call will be via one of
+// OnFilteredBlockConnected or OnBlockDisconnected.
+//
+// NOTE: This is a dcrd extension and requires a websocket connection.
+func (c *Client) NotifyFilteredBlocks(ctx context.Context) error {
+	return c.NotifyFilteredBlocksAsync(ctx).Receive()
+}
+
 // NotifyWorkAsync returns an instance of a type that can be used to get the
 // result of the RPC at some future time by invoking the Receive function on
 // the returned instance.
@@ -903,6 +1472,20 @@ func (c *Client) NotifyBlocks(ctx context.Context) error {
 	return c.NotifyBlocksAsync(ctx).Receive()
 }
 
+// NotifyBlocksStakeEvents registers the client to receive notifications when
+// blocks are connected and disconnected from the main chain in the same way as
+// NotifyBlocks, except the notifications for connected blocks also include a
+// summary of the ticket purchases, votes and their choices, and revocations in
+// the block.
+//
+// The notifications delivered as a result of this call will be via one of
+// OnBlockConnected, OnBlockStakeEvents, or OnBlockDisconnected.
+//
+// NOTE: This is a dcrd extension and requires a websocket connection.
+func (c *Client) NotifyBlocksStakeEvents(ctx context.Context) error {
+	return c.NotifyBlocksStakeEventsAsync(ctx).Receive()
+}
+
 // NotifyWork registers the client to receive notifications when a new block
 // template has been generated.
 //
@@ -913,6 +1496,56 @@ func (c *Client) NotifyWork(ctx context.Context) error {
 	return c.NotifyWorkAsync(ctx).Receive()
 }
 
+// FutureNotifyDiskSpaceResult is a future promise to deliver the result of a
+// NotifyDiskSpaceAsync RPC invocation (or an applicable error).
+type FutureNotifyDiskSpaceResult cmdRes
+
+// Receive waits for the response promised by the future and returns an error
+// if the registration was not successful.
+func (r *FutureNotifyDiskSpaceResult) Receive() error {
+	_, err := receiveFuture(r.ctx, r.c)
+	return err
+}
+
+// NotifyDiskSpaceAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See NotifyDiskSpace for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension and requires a websocket connection.
+func (c *Client) NotifyDiskSpaceAsync(ctx context.Context) *FutureNotifyDiskSpaceResult {
+	// Not supported in HTTP POST mode.
+	if c.config.HTTPPostMode {
+		return (*FutureNotifyDiskSpaceResult)(newFutureError(ctx, ErrWebsocketsRequired))
+	}
+
+	// Ignore the notification if the client is not interested in
+	// notifications.
+	if c.ntfnHandlers == nil {
+		return (*FutureNotifyDiskSpaceResult)(newNilFutureResult(ctx))
+	}
+
+	cmd := chainjson.NewNotifyDiskSpaceCmd()
+
+	return (*FutureNotifyDiskSpaceResult)(c.sendCmd(ctx, cmd))
+}
+
+// NotifyDiskSpace registers the client to receive notifications when the free
+// disk space available to the data directory of the server crosses one of its
+// configured thresholds.  The notifications are delivered to the notification
+// handlers associated with the client.  Calling this function has no effect if
+// there are no notification handlers and will result in an error if the client
+// is configured to run in HTTP POST mode.
+//
+// The notifications delivered as a result of this call will be those from
+// OnDiskSpace.
+//
+// NOTE: This is a dcrd extension and requires a websocket connection.
+func (c *Client) NotifyDiskSpace(ctx context.Context) error {
+	return c.NotifyDiskSpaceAsync(ctx).Receive()
+}
+
 // FutureNotifyWinningTicketsResult is a future promise to deliver the result of a
 // NotifyWinningTicketsAsync RPC invocation (or an applicable error).
 type FutureNotifyWinningTicketsResult cmdRes
@@ -1104,7 +1737,8 @@ func (c *Client) NotifyStakeDifficultyAsync(ctx context.Context) *FutureNotifySt
 // HTTP POST mode.
 //
 // The notifications delivered as a result of this call will be via
-// OnStakeDifficulty.
+// OnStakeDifficulty and, for blocks that complete a stake difficulty window,
+// OnStakeDifficultyChange.
 //
 // NOTE: This is a dcrd extension and requires a websocket connection.
 func (c *Client) NotifyStakeDifficulty(ctx context.Context) error {
@@ -0,0 +1,543 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrjson/v3"
+	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v2"
+	"github.com/decred/dcrd/wire"
+	"github.com/gorilla/websocket"
+)
+
+// TestDecodedNotifications ensures the notification handlers that provide the
+// notification details as data structures are invoked along with the handlers
+// that provide raw bytes and that the data structures decode the block headers
+// and transactions on demand.
+func TestDecodedNotifications(t *testing.T) {
+	header := wire.BlockHeader{Version: 8, Height: 432100, Nonce: 1}
+	rawHeader, err := header.Bytes()
+	if err != nil {
+		t.Fatalf("unexpected header serialize error: %v", err)
+	}
+	tx := wire.NewMsgTx()
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, 0, nil))
+	tx.AddTxOut(wire.NewTxOut(1e8, []byte{0x51}))
+	rawTx, err := tx.Bytes()
+	if err != nil {
+		t.Fatalf("unexpected tx serialize error: %v", err)
+	}
+
+	// makeNtfn returns a raw notification for the provided method with the
+	// provided parameters.
+	makeNtfn := func(method chainjson.Method, params ...interface{}) *rawNotification {
+		ntfn := &rawNotification{Method: string(method)}
+		for _, param := range params {
+			rawParam, err := json.Marshal(param)
+			if err != nil {
+				t.Fatalf("unexpected marshal error: %v", err)
+			}
+			ntfn.Params = append(ntfn.Params, rawParam)
+		}
+		return ntfn
+	}
+	hexHeader := hex.EncodeToString(rawHeader)
+	hexTx := hex.EncodeToString(rawTx)
+
+	var numRaw int
+	var connected *BlockConnectedNtfnData
+	var disconnected *BlockHeaderNtfnData
+	var accepted *RelevantTxAcceptedNtfnData
+	var unmined []byte
+	c := &Client{ntfnHandlers: &NotificationHandlers{
+		OnBlockConnected: func([]byte, [][]byte) { numRaw++ },
+		OnBlockConnectedData: func(data *BlockConnectedNtfnData) {
+			connected = data
+		},
+		OnBlockDisconnectedData: func(data *BlockHeaderNtfnData) {
+			disconnected = data
+		},
+		OnRelevantTxAcceptedData: func(data *RelevantTxAcceptedNtfnData) {
+			accepted = data
+		},
+		OnRelevantTxUnmined: func(transaction []byte) {
+			unmined = transaction
+		},
+	}}
+	c.handleNotification(makeNtfn(chainjson.BlockConnectedNtfnMethod,
+		hexHeader, []string{hexTx}))
+	c.handleNotification(makeNtfn(chainjson.BlockDisconnectedNtfnMethod,
+		hexHeader))
+	c.handleNotification(makeNtfn(chainjson.RelevantTxAcceptedNtfnMethod,
+		hexTx))
+	c.handleNotification(makeNtfn(chainjson.RelevantTxUnminedNtfnMethod,
+		hexTx))
+
+	if numRaw != 1 {
+		t.Fatalf("raw block connected handler invoked %d times", numRaw)
+	}
+	if connected == nil || disconnected == nil || accepted == nil {
+		t.Fatal("data notification handlers were not invoked")
+	}
+	if !bytes.Equal(unmined, rawTx) {
+		t.Fatalf("unexpected unmined transaction -- got %x, want %x",
+			unmined, rawTx)
+	}
+
+	// Ensure nothing is decoded until it is requested.
+	if connected.header != nil || connected.txns != nil ||
+		disconnected.header != nil || accepted.tx != nil {
+		t.Fatal("notification details decoded before they were requested")
+	}
+
+	for _, data := range []*BlockHeaderNtfnData{
+		&connected.BlockHeaderNtfnData, disconnected,
+	} {
+		gotHeader, err := data.Header()
+		if err != nil {
+			t.Fatalf("unexpected header decode error: %v", err)
+		}
+		if gotHeader.BlockHash() != header.BlockHash() {
+			t.Fatalf("unexpected header -- got %v, want %v",
+				gotHeader.BlockHash(), header.BlockHash())
+		}
+		if again, _ := data.Header(); again != gotHeader {
+			t.Fatal("header was decoded more than once")
+		}
+	}
+
+	txns, err := connected.Transactions()
+	if err != nil {
+		t.Fatalf("unexpected transactions decode error: %v", err)
+	}
+	if len(txns) != 1 || *txns[0].Hash() != tx.TxHash() {
+		t.Fatalf("unexpected transactions -- got %v, want %v", txns,
+			tx.TxHash())
+	}
+	gotTx, err := accepted.Transaction()
+	if err != nil {
+		t.Fatalf("unexpected transaction decode error: %v", err)
+	}
+	if *gotTx.Hash() != tx.TxHash() {
+		t.Fatalf("unexpected transaction -- got %v, want %v", gotTx.Hash(),
+			tx.TxHash())
+	}
+
+	// Ensure invalid serialized data results in decode errors.
+	invalid := &BlockConnectedNtfnData{
+		BlockHeaderNtfnData: BlockHeaderNtfnData{rawHeader: []byte{0x01}},
+		rawTxns:             [][]byte{{0x01}},
+	}
+	if _, err := invalid.Header(); err == nil {
+		t.Fatal("expected error decoding invalid header")
+	}
+	if _, err := invalid.Transactions(); err == nil {
+		t.Fatal("expected error decoding invalid transactions")
+	}
+}
+
+// TestTicketNotifications ensures the winning tickets and spent and missed
+// tickets notifications are parsed and delivered to their handlers and that
+// winning tickets notifications with invalid ticket indices are rejected.
+func TestTicketNotifications(t *testing.T) {
+	blockHash := chainhash.Hash{0x01}
+	tickets := []chainhash.Hash{{0x02}, {0x03}, {0x04}}
+
+	// makeNtfn returns a raw notification for the provided method with the
+	// provided parameters.
+	makeNtfn := func(method chainjson.Method, params ...interface{}) *rawNotification {
+		ntfn := &rawNotification{Method: string(method)}
+		for _, param := range params {
+			rawParam, err := json.Marshal(param)
+			if err != nil {
+				t.Fatalf("unexpected marshal error: %v", err)
+			}
+			ntfn.Params = append(ntfn.Params, rawParam)
+		}
+		return ntfn
+	}
+
+	var gotWinners []*chainhash.Hash
+	var gotSpentAndMissed map[chainhash.Hash]bool
+	var gotHeight, gotStakeDiff int64
+	c := &Client{ntfnHandlers: &NotificationHandlers{
+		OnWinningTickets: func(hash *chainhash.Hash, height int64,
+			winners []*chainhash.Hash) {
+
+			if *hash != blockHash {
+				t.Fatalf("unexpected block hash -- got %v, want %v",
+					hash, blockHash)
+			}
+			gotHeight = height
+			gotWinners = winners
+		},
+		OnSpentAndMissedTickets: func(hash *chainhash.Hash, height,
+			stakeDiff int64, tickets map[chainhash.Hash]bool) {
+
+			if *hash != blockHash {
+				t.Fatalf("unexpected block hash -- got %v, want %v",
+					hash, blockHash)
+			}
+			gotHeight = height
+			gotStakeDiff = stakeDiff
+			gotSpentAndMissed = tickets
+		},
+	}}
+
+	// The winning tickets are delivered in the order of their indices
+	// regardless of the order they are encoded in.
+	winners := map[string]string{
+		"2": tickets[2].String(),
+		"0": tickets[0].String(),
+		"1": tickets[1].String(),
+	}
+	c.handleNotification(makeNtfn(chainjson.WinningTicketsNtfnMethod,
+		blockHash.String(), 1000, winners))
+	if gotHeight != 1000 || len(gotWinners) != len(tickets) {
+		t.Fatalf("unexpected winning tickets -- got %v (height %d)",
+			gotWinners, gotHeight)
+	}
+	for i, winner := range gotWinners {
+		if *winner != tickets[i] {
+			t.Fatalf("unexpected winning ticket %d -- got %v, want %v",
+				i, winner, tickets[i])
+		}
+	}
+
+	// Ensure notifications with out of range or missing indices are not
+	// delivered.
+	for _, invalid := range []map[string]string{
+		{"0": tickets[0].String(), "3": tickets[1].String()},
+		{"0": tickets[0].String(), "-1": tickets[1].String()},
+	} {
+		gotWinners = nil
+		c.handleNotification(makeNtfn(chainjson.WinningTicketsNtfnMethod,
+			blockHash.String(), 1000, invalid))
+		if gotWinners != nil {
+			t.Fatalf("winning tickets with indices %v were delivered",
+				invalid)
+		}
+	}
+
+	c.handleNotification(makeNtfn(chainjson.SpentAndMissedTicketsNtfnMethod,
+		blockHash.String(), 1001, 2e8, map[string]string{
+			tickets[0].String(): "spent",
+			tickets[1].String(): "missed",
+			tickets[2].String(): "spent",
+		}))
+	wantSpentAndMissed := map[chainhash.Hash]bool{
+		tickets[0]: true,
+		tickets[1]: false,
+		tickets[2]: true,
+	}
+	if gotHeight != 1001 || gotStakeDiff != 2e8 ||
+		!reflect.DeepEqual(gotSpentAndMissed, wantSpentAndMissed) {
+		t.Fatalf("unexpected spent and missed tickets -- got %v (height "+
+			"%d, stake diff %d), want %v (height %d, stake diff %d)",
+			gotSpentAndMissed, gotHeight, gotStakeDiff,
+			wantSpentAndMissed, 1001, int64(2e8))
+	}
+}
+
+// TestNotificationChannels ensures notifications are delivered to the channels
+// of the subscriptions interested in them in the order they are received and
+// that the channels are closed once the subscription context is done or the
+// client is shutdown.
+func TestNotificationChannels(t *testing.T) {
+	header := wire.BlockHeader{Version: 8, Height: 432100, Nonce: 1}
+	rawHeader, err := header.Bytes()
+	if err != nil {
+		t.Fatalf("unexpected header serialize error: %v", err)
+	}
+	hexHeader := hex.EncodeToString(rawHeader)
+	txHash := header.BlockHash()
+
+	// makeNtfn returns a raw notification for the provided method with the
+	// provided parameters.
+	makeNtfn := func(method chainjson.Method, params ...interface{}) *rawNotification {
+		ntfn := &rawNotification{Method: string(method)}
+		for _, param := range params {
+			rawParam, err := json.Marshal(param)
+			if err != nil {
+				t.Fatalf("unexpected marshal error: %v", err)
+			}
+			ntfn.Params = append(ntfn.Params, rawParam)
+		}
+		return ntfn
+	}
+
+	// Ensure subscribing fails when the client does not process
+	// notifications or is not connected.
+	ctx := context.Background()
+	c, err := New(&ConnConfig{
+		Host:                "localhost:9109",
+		HTTPPostMode:        true,
+		DisableConnectOnNew: true,
+	}, &NotificationHandlers{})
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+	c.Shutdown()
+	if _, err := c.NotifyBlocksChan(ctx, 0); err != ErrWebsocketsRequired {
+		t.Fatalf("unexpected error -- got %v, want %v", err,
+			ErrWebsocketsRequired)
+	}
+	newClient := func(handlers *NotificationHandlers) *Client {
+		c, err := New(&ConnConfig{
+			Host:                "localhost:9109",
+			DisableConnectOnNew: true,
+		}, handlers)
+		if err != nil {
+			t.Fatalf("unable to create client: %v", err)
+		}
+		return c
+	}
+	c = newClient(nil)
+	if _, err := c.NotifyBlocksChan(ctx, 0); err != ErrNotificationsDisabled {
+		t.Fatalf("unexpected error -- got %v, want %v", err,
+			ErrNotificationsDisabled)
+	}
+	c.Shutdown()
+	c = newClient(&NotificationHandlers{})
+	defer c.Shutdown()
+	_, err = c.NotifyNewTransactionsChan(ctx, false, 0)
+	if err != ErrClientNotConnected {
+		t.Fatalf("unexpected error -- got %v, want %v", err,
+			ErrClientNotConnected)
+	}
+	if len(c.ntfnSubs) != 0 {
+		t.Fatal("failed subscription was not removed")
+	}
+
+	// Subscribe to blocks, both kinds of transaction notifications, and
+	// work.
+	blocksCtx, cancelBlocks := context.WithCancel(ctx)
+	defer cancelBlocks()
+	blockSub := &ntfnSubscription{blocks: make(chan BlockNtfn, 2)}
+	txSub := &ntfnSubscription{txns: make(chan TxAcceptedNtfn, 1)}
+	verboseSub := &ntfnSubscription{
+		txns:    make(chan TxAcceptedNtfn, 1),
+		verbose: true,
+	}
+	c.addNtfnSub(blocksCtx, blockSub)
+	c.addNtfnSub(ctx, txSub)
+	workSub := &ntfnSubscription{work: make(chan WorkNtfn, 1)}
+	c.addNtfnSub(ctx, verboseSub)
+	c.addNtfnSub(ctx, workSub)
+
+	c.handleNotification(makeNtfn(chainjson.BlockConnectedNtfnMethod,
+		hexHeader, []string{}))
+	c.handleNotification(makeNtfn(chainjson.BlockDisconnectedNtfnMethod,
+		hexHeader))
+	c.handleNotification(makeNtfn(chainjson.TxAcceptedNtfnMethod,
+		txHash.String(), 1.5))
+	c.handleNotification(makeNtfn(chainjson.TxAcceptedVerboseNtfnMethod,
+		chainjson.TxRawResult{Txid: txHash.String()}))
+	c.handleNotification(makeNtfn(chainjson.WorkNtfnMethod, hexHeader,
+		"ff", "newparent"))
+
+	// Ensure the block notifications are delivered in order.
+	ntfn := <-blockSub.blocks
+	if ntfn.Connected == nil || ntfn.Disconnected != nil {
+		t.Fatalf("unexpected first block notification %+v", ntfn)
+	}
+	gotHeader, err := ntfn.Connected.Header()
+	if err != nil {
+		t.Fatalf("unexpected header decode error: %v", err)
+	}
+	if gotHeader.BlockHash() != header.BlockHash() {
+		t.Fatalf("unexpected header -- got %v, want %v",
+			gotHeader.BlockHash(), header.BlockHash())
+	}
+	ntfn = <-blockSub.blocks
+	if ntfn.Connected != nil || ntfn.Disconnected == nil {
+		t.Fatalf("unexpected second block notification %+v", ntfn)
+	}
+
+	// Ensure the transaction notifications are only delivered to the
+	// subscriptions with the matching verbosity.
+	txNtfn := <-txSub.txns
+	if txNtfn.Hash != txHash || txNtfn.Amount != 150000000 ||
+		txNtfn.Verbose != nil {
+
+		t.Fatalf("unexpected transaction notification %+v", txNtfn)
+	}
+	txNtfn = <-verboseSub.txns
+	if txNtfn.Hash != txHash || txNtfn.Verbose == nil {
+		t.Fatalf("unexpected verbose transaction notification %+v",
+			txNtfn)
+	}
+	if len(txSub.txns) != 0 || len(verboseSub.txns) != 0 {
+		t.Fatal("transaction notification delivered to wrong subscription")
+	}
+
+	// Ensure the work notification is delivered.
+	workNtfn := <-workSub.work
+	if workNtfn.Work.Data != hexHeader || workNtfn.Work.Target != "ff" ||
+		workNtfn.Reason != "newparent" {
+
+		t.Fatalf("unexpected work notification %+v", workNtfn)
+	}
+
+	// Ensure the channels are closed once the context is done or the
+	// client is shutdown.
+	cancelBlocks()
+	if _, ok := <-blockSub.blocks; ok {
+		t.Fatal("block channel not closed after context is done")
+	}
+	c.Shutdown()
+	if _, ok := <-txSub.txns; ok {
+		t.Fatal("transaction channel not closed after shutdown")
+	}
+	if _, ok := <-verboseSub.txns; ok {
+		t.Fatal("transaction channel not closed after shutdown")
+	}
+	if _, ok := <-workSub.work; ok {
+		t.Fatal("work channel not closed after shutdown")
+	}
+}
+
+// TestRegistrationReplay ensures all notification registrations, including the
+// transaction filter and those registered via raw requests, are replayed after
+// the client reconnects and that the caller is notified once they have been.
+func TestRegistrationReplay(t *testing.T) {
+	// Create a server that replies to all requests with a null result,
+	// forwards the requests it receives, and closes the first connection
+	// once released.
+	type serverRequest struct {
+		conn   int
+		method string
+		params []json.RawMessage
+	}
+	requests := make(chan serverRequest, 100)
+	release := make(chan struct{})
+	var numConns int32
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		connNum := int(atomic.AddInt32(&numConns, 1))
+		if connNum == 1 {
+			go func() {
+				<-release
+				conn.Close()
+			}()
+		}
+		for {
+			var req dcrjson.Request
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			requests <- serverRequest{connNum, req.Method, req.Params}
+			reply := fmt.Sprintf(`{"result":null,"error":null,"id":%v}`,
+				req.ID)
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(reply)); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	replayed := make(chan []string, 1)
+	c, err := New(&ConnConfig{
+		Host:       strings.TrimPrefix(server.URL, "http://"),
+		Endpoint:   "ws",
+		DisableTLS: true,
+	}, &NotificationHandlers{
+		OnRegistrationReplayed: func(methods []string) {
+			replayed <- methods
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+	defer c.Shutdown()
+
+	// rawRequest issues a raw request for the provided method with the
+	// provided parameters.
+	ctx := context.Background()
+	rawRequest := func(method string, params ...interface{}) {
+		t.Helper()
+		rawParams := make([]json.RawMessage, 0, len(params))
+		for _, param := range params {
+			rawParam, err := json.Marshal(param)
+			if err != nil {
+				t.Fatalf("unexpected marshal error: %v", err)
+			}
+			rawParams = append(rawParams, rawParam)
+		}
+		if _, err := c.RawRequest(ctx, method, rawParams); err != nil {
+			t.Fatalf("unexpected %s error: %v", method, err)
+		}
+	}
+
+	// Register for blocks and work, load a transaction filter in two parts,
+	// and stop the work notifications again.
+	if err := c.NotifyBlocks(ctx); err != nil {
+		t.Fatalf("unexpected notifyblocks error: %v", err)
+	}
+	rawRequest("notifywork")
+	outPoint := chainjson.OutPoint{Hash: strings.Repeat("11", 32), Index: 1}
+	rawRequest("loadtxfilter", true, []string{"DsB"}, nil)
+	rawRequest("loadtxfilter", false, []string{"DsA"},
+		[]chainjson.OutPoint{outPoint})
+	rawRequest("stopnotifywork")
+	for i := 0; i < 5; i++ {
+		<-requests
+	}
+
+	// Drop the connection and ensure the remaining registrations are
+	// replayed to the new connection with the transaction filter combined
+	// into a single reload.
+	close(release)
+	select {
+	case methods := <-replayed:
+		want := []string{"notifyblocks", "loadtxfilter"}
+		if !reflect.DeepEqual(methods, want) {
+			t.Fatalf("unexpected replayed methods -- got %v, want %v",
+				methods, want)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("registrations were not replayed")
+	}
+	for _, wantMethod := range []string{"notifyblocks", "loadtxfilter"} {
+		req := <-requests
+		if req.conn != 2 || req.method != wantMethod {
+			t.Fatalf("unexpected replayed request -- got %s on "+
+				"connection %d, want %s on connection 2", req.method,
+				req.conn, wantMethod)
+		}
+		if wantMethod != "loadtxfilter" {
+			continue
+		}
+		cmd, err := dcrjson.ParseParams(chainjson.Method(req.method),
+			req.params)
+		if err != nil {
+			t.Fatalf("unexpected loadtxfilter parse error: %v", err)
+		}
+		want := chainjson.NewLoadTxFilterCmd(true, []string{"DsA", "DsB"},
+			[]chainjson.OutPoint{outPoint})
+		if !reflect.DeepEqual(cmd, want) {
+			t.Fatalf("unexpected replayed transaction filter -- got "+
+				"%+v, want %+v", cmd, want)
+		}
+	}
+}
@@ -0,0 +1,375 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"context"
+	"errors"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil/v3"
+	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v2"
+)
+
+// ErrNotificationsDisabled is an error to describe the condition where the
+// caller is trying to subscribe to notifications via a channel when the client
+// was created without notification handlers and therefore does not process
+// notifications.
+var ErrNotificationsDisabled = errors.New("notifications are disabled since " +
+	"the client was created without notification handlers")
+
+// BlockNtfn describes a block that was either connected to or disconnected from
+// the main chain.  Exactly one of the fields is set.
+type BlockNtfn struct {
+	// Connected houses the details of a blockconnected notification when
+	// the block was connected to the main chain.
+	Connected *BlockConnectedNtfnData
+
+	// Disconnected houses the details of a blockdisconnected notification
+	// when the block was disconnected from the main chain.
+	Disconnected *BlockHeaderNtfnData
+}
+
+// TxAcceptedNtfn describes a transaction that was accepted into the memory pool
+// of the server.
+type TxAcceptedNtfn struct {
+	// Hash is the hash of the transaction.
+	Hash chainhash.Hash
+
+	// Amount is the total amount of the outputs of the transaction.  It is
+	// only set for subscriptions that are not verbose.
+	Amount dcrutil.Amount
+
+	// Verbose houses the details about the transaction.  It is only set
+	// for verbose subscriptions.
+	Verbose *chainjson.TxRawResult
+}
+
+// WorkNtfn describes new work for miners that was generated by the server.
+type WorkNtfn struct {
+	// Work houses the hex-encoded data to solve and the target it must be
+	// solved for in the same form as the result of the getwork RPC.
+	Work chainjson.GetWorkResult
+
+	// Reason is the reason the work was generated, such as a new parent
+	// block or new transactions.
+	Reason string
+}
+
+// ntfnSubscription houses a channel notifications are delivered to along with
+// the details needed to manage it.  Exactly one of the notification channels
+// is set.
+type ntfnSubscription struct {
+	// blocks is the channel block notifications are delivered to.
+	blocks chan BlockNtfn
+
+	// txns is the channel transaction notifications are delivered to and
+	// verbose indicates whether the verbose notifications are delivered.
+	txns    chan TxAcceptedNtfn
+	verbose bool
+
+	// work is the channel work notifications are delivered to.
+	work chan WorkNtfn
+
+	// done is the done channel of the context of the subscription and quit
+	// is closed once the subscription is removed.
+	done <-chan struct{}
+	quit chan struct{}
+}
+
+// closeChans closes the notification channel of the subscription.
+func (s *ntfnSubscription) closeChans() {
+	if s.blocks != nil {
+		close(s.blocks)
+	}
+	if s.txns != nil {
+		close(s.txns)
+	}
+	if s.work != nil {
+		close(s.work)
+	}
+}
+
+// addNtfnSub adds the provided subscription to the client and launches a
+// goroutine to remove it once the provided context is done or the client is
+// shutdown.
+//
+// This function is safe for concurrent access.
+func (c *Client) addNtfnSub(ctx context.Context, sub *ntfnSubscription) {
+	sub.done = ctx.Done()
+	sub.quit = make(chan struct{})
+
+	c.ntfnSubsMtx.Lock()
+	if c.ntfnSubs == nil {
+		c.ntfnSubs = make(map[*ntfnSubscription]struct{})
+	}
+	c.ntfnSubs[sub] = struct{}{}
+	c.ntfnSubsMtx.Unlock()
+
+	c.wg.Add(1)
+	go func() {
+		select {
+		case <-sub.done:
+			c.removeNtfnSub(sub)
+		case <-c.shutdown:
+			c.removeNtfnSub(sub)
+		case <-sub.quit:
+		}
+		c.wg.Done()
+	}()
+}
+
+// removeNtfnSub removes the provided subscription from the client and closes
+// its notification channel unless it was already removed.
+//
+// This function is safe for concurrent access.
+func (c *Client) removeNtfnSub(sub *ntfnSubscription) {
+	c.ntfnSubsMtx.Lock()
+	if _, ok := c.ntfnSubs[sub]; ok {
+		delete(c.ntfnSubs, sub)
+		close(sub.quit)
+		sub.closeChans()
+	}
+	c.ntfnSubsMtx.Unlock()
+}
+
+// notifySubscribers delivers the passed notification to the channels of all of
+// the subscriptions that are interested in it.  Delivery to a subscription
+// blocks until there is room in its channel, its context is done, or the client
+// is shutdown, so consumers that do not keep up delay the delivery of all
+// other notifications.
+//
+// The subscriptions lock is held while delivering so that the channels can't
+// be closed concurrently.
+func (c *Client) notifySubscribers(ntfn *rawNotification) {
+	c.ntfnSubsMtx.Lock()
+	defer c.ntfnSubsMtx.Unlock()
+
+	if len(c.ntfnSubs) == 0 {
+		return
+	}
+
+	switch chainjson.Method(ntfn.Method) {
+	case chainjson.BlockConnectedNtfnMethod:
+		blockHeader, transactions, _, err :=
+			parseBlockConnectedParams(ntfn.Params)
+		if err != nil {
+			return
+		}
+		for sub := range c.ntfnSubs {
+			if sub.blocks == nil {
+				continue
+			}
+			// Each subscription receives its own copy of the data
+			// since it decodes on demand and is not safe for
+			// concurrent access.
+			c.sendBlockNtfn(sub, BlockNtfn{
+				Connected: &BlockConnectedNtfnData{
+					BlockHeaderNtfnData: BlockHeaderNtfnData{
+						rawHeader: blockHeader,
+					},
+					rawTxns: transactions,
+				},
+			})
+		}
+
+	case chainjson.BlockDisconnectedNtfnMethod:
+		blockHeader, err := parseBlockDisconnectedParams(ntfn.Params)
+		if err != nil {
+			return
+		}
+		for sub := range c.ntfnSubs {
+			if sub.blocks == nil {
+				continue
+			}
+			c.sendBlockNtfn(sub, BlockNtfn{
+				Disconnected: &BlockHeaderNtfnData{rawHeader: blockHeader},
+			})
+		}
+
+	case chainjson.TxAcceptedNtfnMethod:
+		hash, amt, err := parseTxAcceptedNtfnParams(ntfn.Params)
+		if err != nil {
+			return
+		}
+		for sub := range c.ntfnSubs {
+			if sub.txns == nil || sub.verbose {
+				continue
+			}
+			c.sendTxNtfn(sub, TxAcceptedNtfn{Hash: *hash, Amount: amt})
+		}
+
+	case chainjson.TxAcceptedVerboseNtfnMethod:
+		rawTx, err := parseTxAcceptedVerboseNtfnParams(ntfn.Params)
+		if err != nil {
+			return
+		}
+		hash, err := chainhash.NewHashFromStr(rawTx.Txid)
+		if err != nil {
+			return
+		}
+		for sub := range c.ntfnSubs {
+			if sub.txns == nil || !sub.verbose {
+				continue
+			}
+			c.sendTxNtfn(sub, TxAcceptedNtfn{Hash: *hash, Verbose: rawTx})
+		}
+
+	case chainjson.WorkNtfnMethod:
+		var ntfnWork WorkNtfn
+		err := unmarshalNtfnParams(ntfn.Params, &ntfnWork.Work.Data,
+			&ntfnWork.Work.Target, &ntfnWork.Reason)
+		if err != nil {
+			return
+		}
+		for sub := range c.ntfnSubs {
+			if sub.work == nil {
+				continue
+			}
+			c.sendWorkNtfn(sub, ntfnWork)
+		}
+	}
+}
+
+// sendBlockNtfn delivers the passed block notification to the subscription.
+func (c *Client) sendBlockNtfn(sub *ntfnSubscription, ntfn BlockNtfn) {
+	select {
+	case sub.blocks <- ntfn:
+	case <-sub.done:
+	case <-c.shutdown:
+	}
+}
+
+// sendTxNtfn delivers the passed transaction notification to the subscription.
+func (c *Client) sendTxNtfn(sub *ntfnSubscription, ntfn TxAcceptedNtfn) {
+	select {
+	case sub.txns <- ntfn:
+	case <-sub.done:
+	case <-c.shutdown:
+	}
+}
+
+// sendWorkNtfn delivers the passed work notification to the subscription.
+func (c *Client) sendWorkNtfn(sub *ntfnSubscription, ntfn WorkNtfn) {
+	select {
+	case sub.work <- ntfn:
+	case <-sub.done:
+	case <-c.shutdown:
+	}
+}
+
+// NotifyBlocksChan registers the client to receive notifications when blocks
+// are connected to and disconnected from the main chain and returns a channel
+// with the provided buffer size the notifications are delivered to in the
+// order they are received.  It is an alternative to the OnBlockConnected and
+// OnBlockDisconnected notification handlers that allows the notifications to
+// be consumed in select loops which are free to make blocking calls on the
+// client.
+//
+// The channel is closed once the passed context is done or the client is
+// shutdown.  The notifications are re-registered when the client reconnects.
+// Consumers must keep receiving from the channel since notifications are
+// not dropped when it is full, which delays the delivery of all other
+// notifications.
+//
+// The client must have been created with notification handlers, which may all
+// be nil, otherwise ErrNotificationsDisabled is returned.
+//
+// NOTE: This is a dcrd extension and requires a websocket connection.
+func (c *Client) NotifyBlocksChan(ctx context.Context, bufferSize int) (<-chan BlockNtfn, error) {
+	if c.config.HTTPPostMode {
+		return nil, ErrWebsocketsRequired
+	}
+	if c.ntfnHandlers == nil {
+		return nil, ErrNotificationsDisabled
+	}
+
+	// Add the subscription prior to registering so that no notifications
+	// sent immediately after registration are missed.
+	sub := &ntfnSubscription{blocks: make(chan BlockNtfn, bufferSize)}
+	c.addNtfnSub(ctx, sub)
+	if err := c.NotifyBlocks(ctx); err != nil {
+		c.removeNtfnSub(sub)
+		return nil, err
+	}
+	return sub.blocks, nil
+}
+
+// NotifyNewTransactionsChan registers the client to receive notifications
+// every time a new transaction is accepted to the memory pool and returns a
+// channel with the provided buffer size the notifications are delivered to in
+// the order they are received.  The Verbose field of the notifications is only
+// set when verbose is true while the Amount field is only set when it is
+// false.  It is an alternative to the OnTxAccepted and OnTxAcceptedVerbose
+// notification handlers that allows the notifications to be consumed in select
+// loops which are free to make blocking calls on the client.
+//
+// The channel is closed once the passed context is done or the client is
+// shutdown.  The notifications are re-registered when the client reconnects.
+// Consumers must keep receiving from the channel since notifications are
+// not dropped when it is full, which delays the delivery of all other
+// notifications.
+//
+// The client must have been created with notification handlers, which may all
+// be nil, otherwise ErrNotificationsDisabled is returned.
+//
+// NOTE: This is a dcrd extension and requires a websocket connection.
+func (c *Client) NotifyNewTransactionsChan(ctx context.Context, verbose bool, bufferSize int) (<-chan TxAcceptedNtfn, error) {
+	if c.config.HTTPPostMode {
+		return nil, ErrWebsocketsRequired
+	}
+	if c.ntfnHandlers == nil {
+		return nil, ErrNotificationsDisabled
+	}
+
+	// Add the subscription prior to registering so that no notifications
+	// sent immediately after registration are missed.
+	sub := &ntfnSubscription{
+		txns:    make(chan TxAcceptedNtfn, bufferSize),
+		verbose: verbose,
+	}
+	c.addNtfnSub(ctx, sub)
+	if err := c.NotifyNewTransactions(ctx, verbose); err != nil {
+		c.removeNtfnSub(sub)
+		return nil, err
+	}
+	return sub.txns, nil
+}
+
+// NotifyWorkChannel registers the client to receive notifications when new work
+// is generated for miners, such as when a new block is connected or new
+// transactions are available, and returns a channel with the provided buffer
+// size the notifications are delivered to in the order they are received.  It
+// is an alternative to the OnWork notification handler that allows pool
+// software to receive work updates in select loops instead of polling GetWork.
+//
+// The channel is closed once the passed context is done or the client is
+// shutdown.  The notifications are re-registered when the client reconnects.
+// Consumers must keep receiving from the channel since notifications are
+// not dropped when it is full, which delays the delivery of all other
+// notifications.
+//
+// The client must have been created with notification handlers, which may all
+// be nil, otherwise ErrNotificationsDisabled is returned.
+//
+// NOTE: This is a dcrd extension and requires a websocket connection.
+func (c *Client) NotifyWorkChannel(ctx context.Context, bufferSize int) (<-chan WorkNtfn, error) {
+	if c.config.HTTPPostMode {
+		return nil, ErrWebsocketsRequired
+	}
+	if c.ntfnHandlers == nil {
+		return nil, ErrNotificationsDisabled
+	}
+
+	// Add the subscription prior to registering so that no notifications
+	// sent immediately after registration are missed.
+	sub := &ntfnSubscription{work: make(chan WorkNtfn, bufferSize)}
+	c.addNtfnSub(ctx, sub)
+	if err := c.NotifyWork(ctx); err != nil {
+		c.removeNtfnSub(sub)
+		return nil, err
+	}
+	return sub.work, nil
+}
@@ -0,0 +1,200 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"context"
+	"errors"
+
+	"github.com/decred/dcrd/dcrutil/v3"
+	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v2"
+)
+
+// defaultPageLimit is the default number of results requested per page by the
+// page iterators.
+const defaultPageLimit = 100
+
+// ErrPageIteratorEnd is returned by the Next method of the page iterators when
+// all results have been returned.
+var ErrPageIteratorEnd = errors.New("no more results to iterate")
+
+// pager tracks the progress of iterating the pages of results of a paginated
+// RPC.
+type pager struct {
+	limit  int
+	cursor string
+	done   bool
+}
+
+// newPager returns a pager that requests pages with the provided limit.  A
+// limit that is not positive selects the default limit.
+func newPager(limit int) pager {
+	if limit <= 0 {
+		limit = defaultPageLimit
+	}
+	return pager{limit: limit}
+}
+
+// advance records the pagination details of the page that was just fetched and
+// returns whether or not there are more results in it or later pages.  Fetching
+// stops once a page does not provide a next cursor.
+func (p *pager) advance(page *chainjson.PageInfo, numResults int) bool {
+	p.cursor = page.NextCursor
+	p.done = page.NextCursor == ""
+	return numResults > 0 || !p.done
+}
+
+// AddressUtxoIterator iterates all of the confirmed unspent outputs that pay to
+// an address by requesting them from the RPC server a page at a time via the
+// getaddressutxos RPC.
+//
+// An AddressUtxoIterator is not safe for concurrent access.
+type AddressUtxoIterator struct {
+	c       *Client
+	address dcrutil.Address
+	pager   pager
+	pending []chainjson.AddressUtxo
+}
+
+// NewAddressUtxoIterator returns a new AddressUtxoIterator which iterates the
+// unspent outputs that pay to the passed address requesting limit outputs per
+// page.  A limit that is not positive selects a sensible default.  No requests
+// are made until the first call to Next.
+//
+// NOTE: This is a dcrd extension and requires the address index.
+func (c *Client) NewAddressUtxoIterator(address dcrutil.Address, limit int) *AddressUtxoIterator {
+	return &AddressUtxoIterator{
+		c:       c,
+		address: address,
+		pager:   newPager(limit),
+	}
+}
+
+// Next returns the next unspent output.  ErrPageIteratorEnd is returned once
+// all unspent outputs have been returned.
+func (it *AddressUtxoIterator) Next(ctx context.Context) (*chainjson.AddressUtxo, error) {
+	for len(it.pending) == 0 {
+		if it.pager.done {
+			return nil, ErrPageIteratorEnd
+		}
+		res, err := it.c.GetAddressUtxos(ctx, it.address, it.pager.cursor,
+			it.pager.limit)
+		if err != nil {
+			return nil, err
+		}
+		it.pending = res.Utxos
+		if !it.pager.advance(&res.Page, len(res.Utxos)) {
+			return nil, ErrPageIteratorEnd
+		}
+	}
+
+	utxo := &it.pending[0]
+	it.pending = it.pending[1:]
+	return utxo, nil
+}
+
+// BannedPeerIterator iterates all of the hosts banned by the RPC server by
+// requesting them a page at a time via the listbanned RPC.
+//
+// A BannedPeerIterator is not safe for concurrent access.
+type BannedPeerIterator struct {
+	c       *Client
+	pager   pager
+	pending []chainjson.BannedPeer
+}
+
+// NewBannedPeerIterator returns a new BannedPeerIterator which iterates the
+// banned hosts requesting limit hosts per page.  A limit that is not positive
+// selects a sensible default.  No requests are made until the first call to
+// Next.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) NewBannedPeerIterator(limit int) *BannedPeerIterator {
+	return &BannedPeerIterator{c: c, pager: newPager(limit)}
+}
+
+// Next returns the next banned host.  ErrPageIteratorEnd is returned once all
+// banned hosts have been returned.
+func (it *BannedPeerIterator) Next(ctx context.Context) (*chainjson.BannedPeer, error) {
+	for len(it.pending) == 0 {
+		if it.pager.done {
+			return nil, ErrPageIteratorEnd
+		}
+		res, err := it.c.ListBanned(ctx, it.pager.cursor, it.pager.limit)
+		if err != nil {
+			return nil, err
+		}
+		it.pending = res.Banned
+		if !it.pager.advance(&res.Page, len(res.Banned)) {
+			return nil, ErrPageIteratorEnd
+		}
+	}
+
+	banned := &it.pending[0]
+	it.pending = it.pending[1:]
+	return banned, nil
+}
+
+// SearchRawTransactionsIterator iterates all of the transactions that involve
+// an address by requesting them from the RPC server a page at a time via the
+// searchrawtransactions RPC.
+//
+// A SearchRawTransactionsIterator is not safe for concurrent access.
+type SearchRawTransactionsIterator struct {
+	c              *Client
+	address        dcrutil.Address
+	includePrevOut bool
+	reverse        bool
+	filterAddrs    []string
+	pager          pager
+	pending        []chainjson.SearchRawTransactionsResult
+}
+
+// NewSearchRawTransactionsIterator returns a new SearchRawTransactionsIterator
+// which iterates data structures that describe the transactions which involve
+// the passed address requesting limit transactions per page.  A limit that is
+// not positive selects a sensible default.  See SearchRawTransactionsVerbose
+// for details regarding the remaining parameters.  No requests are made until
+// the first call to Next.
+//
+// NOTE: Chain servers do not typically provide this capability unless it has
+// specifically been enabled.
+func (c *Client) NewSearchRawTransactionsIterator(address dcrutil.Address,
+	limit int, includePrevOut, reverse bool,
+	filterAddrs []string) *SearchRawTransactionsIterator {
+
+	return &SearchRawTransactionsIterator{
+		c:              c,
+		address:        address,
+		includePrevOut: includePrevOut,
+		reverse:        reverse,
+		filterAddrs:    filterAddrs,
+		pager:          newPager(limit),
+	}
+}
+
+// Next returns the next transaction.  ErrPageIteratorEnd is returned once all
+// transactions have been returned.
+func (it *SearchRawTransactionsIterator) Next(ctx context.Context) (*chainjson.SearchRawTransactionsResult, error) {
+	for len(it.pending) == 0 {
+		if it.pager.done {
+			return nil, ErrPageIteratorEnd
+		}
+		res, err := it.c.SearchRawTransactionsPage(ctx, it.address,
+			it.pager.cursor, it.pager.limit, it.includePrevOut, it.reverse,
+			it.filterAddrs)
+		if err != nil {
+			return nil, err
+		}
+		it.pending = res.Transactions
+		if !it.pager.advance(&res.Page, len(res.Transactions)) {
+			return nil, ErrPageIteratorEnd
+		}
+	}
+
+	tx := &it.pending[0]
+	it.pending = it.pending[1:]
+	return tx, nil
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// TestBannedPeerIterator ensures the page iterators request each page with the
+// cursor of the previous one and return all of the results across the pages.
+func TestBannedPeerIterator(t *testing.T) {
+	// Create a server that replies to listbanned requests with pages of two
+	// hosts out of a total of three.
+	hosts := []string{"127.0.0.1", "127.0.0.2", "127.0.0.3"}
+	var cursors []string
+	server, c := newTestHTTPClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64        `json:"id"`
+			Params []interface{} `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		cursor := req.Params[0].(string)
+		cursors = append(cursors, cursor)
+		page := `{"banned":[{"host":"%s","banneduntil":1},{"host":"%s",` +
+			`"banneduntil":2}],"page":{"nextcursor":"00000002","limit":2,` +
+			`"totalestimate":3}}`
+		result := fmt.Sprintf(page, hosts[0], hosts[1])
+		if cursor == "00000002" {
+			page = `{"banned":[{"host":"%s","banneduntil":3}],"page":` +
+				`{"limit":2,"totalestimate":3}}`
+			result = fmt.Sprintf(page, hosts[2])
+		}
+		fmt.Fprintf(w, `{"result":%s,"error":null,"id":%d}`, result, req.ID)
+	}, nil)
+	defer server.Close()
+	defer c.Shutdown()
+
+	ctx := context.Background()
+	iter := c.NewBannedPeerIterator(2)
+	for i, want := range hosts {
+		banned, err := iter.Next(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error for host %d: %v", i, err)
+		}
+		if banned.Host != want || banned.BannedUntil != int64(i+1) {
+			t.Fatalf("unexpected banned host %d -- got %+v, want %s", i,
+				banned, want)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := iter.Next(ctx); !errors.Is(err, ErrPageIteratorEnd) {
+			t.Fatalf("unexpected error after final host -- got %v, want %v",
+				err, ErrPageIteratorEnd)
+		}
+	}
+	if len(cursors) != 2 || cursors[0] != "" || cursors[1] != "00000002" {
+		t.Fatalf("unexpected requested cursors %q", cursors)
+	}
+}
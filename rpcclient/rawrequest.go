@@ -11,6 +11,7 @@ import (
 	"errors"
 
 	"github.com/decred/dcrd/dcrjson/v3"
+	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v2"
 )
 
 // FutureRawResult is a future promise to deliver the result of a RawRequest RPC
@@ -56,16 +57,26 @@ func (c *Client) RawRequestAsync(ctx context.Context, method string, params []js
 		return (*FutureRawResult)(newFutureError(ctx, err))
 	}
 
+	// Parse the parameters of known commands so notification registrations
+	// made with raw requests are tracked and replayed on reconnect like
+	// those made with the typed methods.  Other methods are sent as is.
+	cmd, err := dcrjson.ParseParams(chainjson.Method(method), params)
+	if err != nil {
+		cmd = nil
+	}
+
 	// Generate the request and send it along with a channel to respond on.
+	ctx, cancel := c.requestContext(ctx)
 	responseChan := make(chan *response, 1)
 	jReq := &jsonRequest{
 		id:             id,
 		method:         method,
-		cmd:            nil,
+		cmd:            cmd,
 		marshalledJSON: marshalledJSON,
-		responseChan:   responseChan,
+		responseChan: c.observeRequest(method,
+			c.watchRequest(ctx, cancel, id, responseChan)),
 	}
-	c.sendRequest(ctx, jReq)
+	c.sendRequestWithRetry(ctx, jReq)
 
 	return &FutureRawResult{ctx: ctx, c: responseChan}
 }
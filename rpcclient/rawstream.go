@@ -0,0 +1,419 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/decred/dcrd/dcrjson/v3"
+)
+
+// errEnvelopeEnd is used internally to signal the end of the members of a
+// JSON-RPC response envelope.
+var errEnvelopeEnd = errors.New("end of response envelope")
+
+// byteDiscarder is an io.ByteWriter that discards all bytes written to it.
+type byteDiscarder struct{}
+
+// WriteByte discards the passed byte.
+func (byteDiscarder) WriteByte(byte) error {
+	return nil
+}
+
+// isJSONSpace returns whether or not the passed byte is insignificant
+// whitespace in JSON.
+func isJSONSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
+
+// readByte reads a single byte from the passed reader and converts an io.EOF
+// error to io.ErrUnexpectedEOF since all callers expect more data.
+func readByte(r *bufio.Reader) (byte, error) {
+	b, err := r.ReadByte()
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	return b, err
+}
+
+// peekNonSpace skips any whitespace in the passed reader and returns the next
+// byte without consuming it.
+func peekNonSpace(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := readByte(r)
+		if err != nil {
+			return 0, err
+		}
+		if !isJSONSpace(b) {
+			return b, r.UnreadByte()
+		}
+	}
+}
+
+// scanJSONValue copies exactly one JSON value, without any surrounding
+// whitespace, from the passed reader to the passed writer.  The value is only
+// checked for being well delimited.  Validating its contents is left to the
+// eventual consumer.
+func scanJSONValue(r *bufio.Reader, w io.ByteWriter) error {
+	b, err := peekNonSpace(r)
+	if err != nil {
+		return err
+	}
+
+	// Scalars other than strings end at the first delimiter.
+	if b != '{' && b != '[' && b != '"' {
+		var n int
+		for {
+			b, err := r.ReadByte()
+			if errors.Is(err, io.EOF) {
+				if n > 0 {
+					return nil
+				}
+				err = io.ErrUnexpectedEOF
+			}
+			if err != nil {
+				return err
+			}
+			if isJSONSpace(b) || b == ',' || b == '}' || b == ']' {
+				if n == 0 {
+					return fmt.Errorf("unexpected %q in JSON value", b)
+				}
+				return r.UnreadByte()
+			}
+			if err := w.WriteByte(b); err != nil {
+				return err
+			}
+			n++
+		}
+	}
+
+	// Objects, arrays, and strings end once all nested objects and arrays
+	// are closed outside of a string.
+	var depth int
+	var inString, escaped bool
+	for {
+		b, err := readByte(r)
+		if err != nil {
+			return err
+		}
+		if err := w.WriteByte(b); err != nil {
+			return err
+		}
+		switch {
+		case escaped:
+			escaped = false
+			continue
+		case inString && b == '\\':
+			escaped = true
+			continue
+		case inString && b == '"':
+			inString = false
+		case inString:
+			continue
+		case b == '"':
+			inString = true
+			continue
+		case b == '{' || b == '[':
+			depth++
+			continue
+		case b == '}' || b == ']':
+			depth--
+		}
+		if depth == 0 && !inString {
+			return nil
+		}
+	}
+}
+
+// nextEnvelopeMember advances the passed reader to the value of the next member
+// of a JSON-RPC response envelope and returns its key.  The first member must
+// be requested with first set so the opening brace of the envelope is consumed.
+// errEnvelopeEnd is returned once the closing brace of the envelope is reached.
+func nextEnvelopeMember(r *bufio.Reader, first bool) (string, error) {
+	b, err := peekNonSpace(r)
+	if err != nil {
+		return "", err
+	}
+	r.ReadByte()
+	switch {
+	case first && b != '{':
+		return "", fmt.Errorf("unexpected %q at start of response", b)
+	case !first && b == '}':
+		return "", errEnvelopeEnd
+	case !first && b != ',':
+		return "", fmt.Errorf("unexpected %q after response member", b)
+	}
+	if first {
+		b, err := peekNonSpace(r)
+		if err != nil {
+			return "", err
+		}
+		if b == '}' {
+			r.ReadByte()
+			return "", errEnvelopeEnd
+		}
+	}
+
+	var keyBuf bytes.Buffer
+	if err := scanJSONValue(r, &keyBuf); err != nil {
+		return "", err
+	}
+	var key string
+	if err := json.Unmarshal(keyBuf.Bytes(), &key); err != nil {
+		return "", err
+	}
+	b, err = peekNonSpace(r)
+	if err != nil {
+		return "", err
+	}
+	r.ReadByte()
+	if b != ':' {
+		return "", fmt.Errorf("unexpected %q after response member key", b)
+	}
+	if _, err := peekNonSpace(r); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// scanEnvelope reads the members of a JSON-RPC response envelope from the
+// passed reader until either the start of a non-null result value, in which
+// case true is returned, or the end of the envelope.  The first member must be
+// scanned with first set.  An error is returned when the envelope contains a
+// non-null error.
+func scanEnvelope(r *bufio.Reader, first bool) (bool, error) {
+	var rpcErr *dcrjson.RPCError
+	for ; ; first = false {
+		key, err := nextEnvelopeMember(r, first)
+		if errors.Is(err, errEnvelopeEnd) {
+			break
+		}
+		if err != nil {
+			return false, err
+		}
+
+		switch key {
+		case "result":
+			b, err := peekNonSpace(r)
+			if err != nil {
+				return false, err
+			}
+			if b != 'n' && rpcErr == nil {
+				return true, nil
+			}
+			err = scanJSONValue(r, byteDiscarder{})
+
+		case "error":
+			var errBuf bytes.Buffer
+			err = scanJSONValue(r, &errBuf)
+			if err == nil {
+				err = json.Unmarshal(errBuf.Bytes(), &rpcErr)
+			}
+
+		default:
+			err = scanJSONValue(r, byteDiscarder{})
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+	if rpcErr != nil {
+		return false, newRPCError(rpcErr)
+	}
+	return false, nil
+}
+
+// resultStream is an io.ReadCloser over the raw bytes of the result of a
+// JSON-RPC response that is read from the body of an HTTP response as the
+// result is consumed.
+type resultStream struct {
+	body   io.ReadCloser
+	cancel context.CancelFunc
+	pr     *io.PipeReader
+}
+
+// Read reads the next bytes of the result.  Any error in the remainder of the
+// response after the result is returned instead of io.EOF.
+func (s *resultStream) Read(p []byte) (int, error) {
+	return s.pr.Read(p)
+}
+
+// Close closes the underlying HTTP response and aborts the request when the
+// result was not consumed entirely.
+func (s *resultStream) Close() error {
+	s.pr.Close()
+	err := s.body.Close()
+	s.cancel()
+	return err
+}
+
+// newResultStream returns a reader over the raw bytes of the result in the
+// passed HTTP response to a JSON-RPC request.  An error is returned without
+// consuming the entire response when it contains a non-null error or is not a
+// JSON-RPC response.  The cancel function is invoked when the returned reader
+// is closed or an error is returned.
+func newResultStream(httpResponse *http.Response, cancel context.CancelFunc) (io.ReadCloser, error) {
+	r := bufio.NewReader(httpResponse.Body)
+	b, err := peekNonSpace(r)
+	if err == nil && b != '{' {
+		// When the response itself isn't a JSON-RPC response return an
+		// error which includes the HTTP status code and raw response
+		// bytes like non-streaming requests.
+		respBytes, _ := ioutil.ReadAll(r)
+		httpResponse.Body.Close()
+		cancel()
+		return nil, &HTTPStatusError{
+			StatusCode: httpResponse.StatusCode,
+			Response:   respBytes,
+		}
+	}
+	var found bool
+	if err == nil {
+		found, err = scanEnvelope(r, true)
+	}
+	if err != nil {
+		httpResponse.Body.Close()
+		cancel()
+		var rpcErr *RPCError
+		if !errors.As(err, &rpcErr) {
+			err = fmt.Errorf("error reading json reply: %w", err)
+		}
+		return nil, err
+	}
+
+	// Copy the result to the caller as it is read and then ensure the
+	// remainder of the response does not contain an error.  A null result
+	// is provided as is.
+	pr, pw := io.Pipe()
+	go func() {
+		if !found {
+			_, err := pw.Write([]byte("null"))
+			pw.CloseWithError(err)
+			return
+		}
+		w := bufio.NewWriter(pw)
+		err := scanJSONValue(r, w)
+		if flushErr := w.Flush(); err == nil {
+			err = flushErr
+		}
+		if err == nil {
+			_, err = scanEnvelope(r, false)
+		}
+		pw.CloseWithError(err)
+	}()
+	return &resultStream{body: httpResponse.Body, cancel: cancel, pr: pr}, nil
+}
+
+// RawRequestStream sends a raw or custom request to the server like RawRequest
+// and returns a reader over the raw bytes of the result.  When the client is
+// running in HTTP POST mode, the result is read from the connection as it is
+// consumed from the reader rather than buffering the entire response first,
+// which avoids holding multiple copies of very large results, such as verbose
+// blocks or mempool dumps, in memory.  The caller must close the reader once
+// it is done with it.
+//
+// An error is returned right away when the response contains an error, in which
+// case the result is null, or is not a valid JSON-RPC response.  Errors in the
+// remainder of the response are returned by the reader instead of io.EOF once
+// the result is consumed.  The request timeout of the client, when configured,
+// applies until the reader is closed.
+//
+// Since the result is never held by the client, these requests are not
+// provided to the interceptors, retried, or subject to strict result
+// validation.  Websocket clients receive each response in a single message, so
+// the reader is instead provided over the buffered result when the client is
+// not running in HTTP POST mode.
+func (c *Client) RawRequestStream(ctx context.Context, method string, params []json.RawMessage) (io.ReadCloser, error) {
+	if !c.config.HTTPPostMode {
+		result, err := c.RawRequest(ctx, method, params)
+		if err != nil {
+			return nil, err
+		}
+		return ioutil.NopCloser(bytes.NewReader(result)), nil
+	}
+
+	// Method may not be empty.
+	if method == "" {
+		return nil, errors.New("no method")
+	}
+
+	// Marshal parameters as "[]" instead of "null" when no parameters
+	// are passed.
+	if params == nil {
+		params = []json.RawMessage{}
+	}
+
+	// Create a raw JSON-RPC request using the provided method and params
+	// and issue it as an HTTP POST request to the configured RPC server.
+	id := c.NextID()
+	rawRequest := &dcrjson.Request{
+		Jsonrpc: "1.0",
+		ID:      id,
+		Method:  method,
+		Params:  params,
+	}
+	marshalledJSON, err := json.Marshal(rawRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, timeoutCancel := c.requestContext(ctx)
+	ctx, cancel := context.WithCancel(ctx)
+	if timeoutCancel != nil {
+		cancelStream := cancel
+		cancel = func() {
+			cancelStream()
+			timeoutCancel()
+		}
+	}
+	protocol := "http"
+	if c.config.useTLS() {
+		protocol = "https"
+	}
+	url := protocol + "://" + c.host()
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url,
+		bytes.NewReader(marshalledJSON))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	httpReq.Close = !c.config.pooled()
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.SetBasicAuth(c.config.User, c.config.Pass)
+
+	// Abort the request when the client is shutdown while it is in flight
+	// or the result is being read.
+	go func() {
+		select {
+		case <-c.shutdown:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	log.Tracef("Sending command [%s] with id %d", method, id)
+	httpResponse, err := c.doPostFailover(ctx, httpReq)
+	if err != nil {
+		select {
+		case <-c.shutdown:
+			err = ErrClientShutdown
+		default:
+			if ctx.Err() != nil {
+				err = contextError(ctx)
+			}
+		}
+		cancel()
+		return nil, err
+	}
+	return newResultStream(httpResponse, cancel)
+}
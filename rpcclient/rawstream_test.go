@@ -0,0 +1,210 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrd/dcrjson/v3"
+)
+
+// TestScanJSONValue ensures single JSON values are copied exactly and that the
+// reader is left positioned after them.
+func TestScanJSONValue(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		rest    string
+		wantErr bool
+	}{{
+		name:  "number",
+		input: `12.5e3,"id":1}`,
+		want:  `12.5e3`,
+		rest:  `,"id":1}`,
+	}, {
+		name:  "literal at end of input",
+		input: ` true`,
+		want:  `true`,
+	}, {
+		name:  "string with escapes",
+		input: `"a\"b\\" ,`,
+		want:  `"a\"b\\"`,
+		rest:  ` ,`,
+	}, {
+		name:  "nested object with braces in strings",
+		input: ` {"a":[1,{"b":"}]"}],"c":"\"{"}}`,
+		want:  `{"a":[1,{"b":"}]"}],"c":"\"{"}`,
+		rest:  `}`,
+	}, {
+		name:  "empty array",
+		input: `[]]`,
+		want:  `[]`,
+		rest:  `]`,
+	}, {
+		name:    "truncated object",
+		input:   `{"a":[1,2`,
+		wantErr: true,
+	}, {
+		name:    "missing value",
+		input:   ` ,`,
+		wantErr: true,
+	}, {
+		name:    "empty input",
+		input:   ``,
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		r := bufio.NewReader(strings.NewReader(test.input))
+		var buf bytes.Buffer
+		err := scanJSONValue(r, &buf)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%q: did not receive expected error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.name, err)
+			continue
+		}
+		if buf.String() != test.want {
+			t.Errorf("%q: unexpected value -- got %s, want %s", test.name,
+				buf.String(), test.want)
+		}
+		rest, _ := ioutil.ReadAll(r)
+		if string(rest) != test.rest {
+			t.Errorf("%q: unexpected remainder -- got %s, want %s",
+				test.name, rest, test.rest)
+		}
+	}
+}
+
+// TestRawRequestStream ensures the results of raw requests are streamed from
+// HTTP responses and that errors in the responses are detected.
+func TestRawRequestStream(t *testing.T) {
+	t.Parallel()
+
+	bigResult := `{"tx":["` + strings.Repeat("ab", 1<<20) + `"],"escaped":"\"}"}`
+	responses := map[string]string{
+		"big":         `{"jsonrpc":"1.0","result":` + bigResult + `,"error":null,"id":1}`,
+		"string":      `{ "result" : "a\"b" , "error" : null , "id" : 1 }`,
+		"null":        `{"result":null,"error":null,"id":1}`,
+		"rpcerror":    `{"result":null,"error":{"code":-5,"message":"Block not found"},"id":1}`,
+		"errorfirst":  `{"error":{"code":-5,"message":"Block not found"},"result":{"a":1},"id":1}`,
+		"errorafter":  `{"result":[1,2,3],"error":{"code":-32603,"message":"internal"},"id":1}`,
+		"truncated":   `{"result":[1,2,3`,
+		"unexpected":  `{"result":1 "error":null}`,
+		"nonjsonrpc":  `401 Unauthorized.`,
+		"emptyobject": `{}`,
+	}
+	server, c := newTestHTTPClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req dcrjson.Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		resp, ok := responses[req.Method]
+		if !ok {
+			http.Error(w, "unknown method", http.StatusNotFound)
+			return
+		}
+		if req.Method == "nonjsonrpc" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+		io.WriteString(w, resp)
+	}, nil)
+	defer server.Close()
+	defer c.Shutdown()
+
+	ctx := context.Background()
+	tests := []struct {
+		method      string
+		want        string
+		wantErr     bool
+		wantRPCErr  dcrjson.RPCErrorCode
+		wantReadErr bool
+	}{
+		{method: "big", want: bigResult},
+		{method: "string", want: `"a\"b"`},
+		{method: "null", want: `null`},
+		{method: "emptyobject", want: `null`},
+		{method: "rpcerror", wantErr: true, wantRPCErr: -5},
+		{method: "errorfirst", wantErr: true, wantRPCErr: -5},
+		{method: "errorafter", want: `[1,2,3]`, wantReadErr: true},
+		{method: "truncated", want: `[1,2,3`, wantReadErr: true},
+		{method: "unexpected", want: `1`, wantReadErr: true},
+		{method: "nonjsonrpc", wantErr: true},
+		{method: "", wantErr: true},
+	}
+	for _, test := range tests {
+		stream, err := c.RawRequestStream(ctx, test.method, nil)
+		if test.wantErr {
+			if err == nil {
+				stream.Close()
+				t.Errorf("%q: did not receive expected error", test.method)
+				continue
+			}
+			var rpcErr *RPCError
+			if test.wantRPCErr != 0 && (!errors.As(err, &rpcErr) ||
+				rpcErr.Code != test.wantRPCErr) {
+
+				t.Errorf("%q: unexpected error -- got %v, want code %d",
+					test.method, err, test.wantRPCErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.method, err)
+			continue
+		}
+
+		// Read the result in small chunks to ensure the partially
+		// available result is provided in order.
+		var got bytes.Buffer
+		_, err = io.CopyBuffer(&got, stream, make([]byte, 7))
+		stream.Close()
+		if test.wantReadErr != (err != nil) {
+			t.Errorf("%q: unexpected read error: %v", test.method, err)
+		}
+		if got.String() != test.want {
+			t.Errorf("%q: unexpected result -- got %d bytes, want %d",
+				test.method, got.Len(), len(test.want))
+		}
+	}
+
+	// Ensure non-JSON-RPC responses include the HTTP status code.
+	_, err := c.RawRequestStream(ctx, "nonjsonrpc", nil)
+	var statusErr *HTTPStatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("unexpected error -- got %v, want status code %d", err,
+			http.StatusUnauthorized)
+	}
+
+	// Ensure closing the reader before consuming the entire result does not
+	// block.
+	stream, err := c.RawRequestStream(ctx, "big", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := stream.Read(make([]byte, 16)); err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+}
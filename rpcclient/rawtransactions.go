@@ -10,6 +10,7 @@ import (
 	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 
 	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/dcrjson/v3"
@@ -345,6 +346,60 @@ func (c *Client) CreateRawSSRtx(ctx context.Context, inputs []chainjson.Transact
 	return c.CreateRawSSRtxAsync(ctx, inputs, fee).Receive()
 }
 
+// FutureCreateRevocationResult is a future promise to deliver the result of a
+// CreateRevocationAsync RPC invocation (or an applicable error).
+type FutureCreateRevocationResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// unsigned revocation of the ticket.
+func (r *FutureCreateRevocationResult) Receive() (*wire.MsgTx, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a string.
+	var txHex string
+	err = json.Unmarshal(res, &txHex)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode the serialized transaction hex to raw bytes.
+	serializedTx, err := hex.DecodeString(txHex)
+	if err != nil {
+		return nil, err
+	}
+
+	// Deserialize the transaction and return it.
+	var msgTx wire.MsgTx
+	if err := msgTx.Deserialize(bytes.NewReader(serializedTx)); err != nil {
+		return nil, err
+	}
+	return &msgTx, nil
+}
+
+// CreateRevocationAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function
+// on the returned instance.
+//
+// See CreateRevocation for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) CreateRevocationAsync(ctx context.Context, ticketHash *chainhash.Hash, fee dcrutil.Amount) *FutureCreateRevocationResult {
+	feeF64 := fee.ToCoin()
+	cmd := chainjson.NewCreateRevocationCmd(ticketHash.String(), &feeF64)
+	return (*FutureCreateRevocationResult)(c.sendCmd(ctx, cmd))
+}
+
+// CreateRevocation returns a new unsigned revocation of the provided missed or
+// expired ticket that pays the provided fee.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) CreateRevocation(ctx context.Context, ticketHash *chainhash.Hash, fee dcrutil.Amount) (*wire.MsgTx, error) {
+	return c.CreateRevocationAsync(ctx, ticketHash, fee).Receive()
+}
+
 // FutureSendRawTransactionResult is a future promise to deliver the result
 // of a SendRawTransactionAsync RPC invocation (or an applicable error).
 type FutureSendRawTransactionResult cmdRes
@@ -394,6 +449,28 @@ func (c *Client) SendRawTransaction(ctx context.Context, tx *wire.MsgTx, allowHi
 	return c.SendRawTransactionAsync(ctx, tx, allowHighFees).Receive()
 }
 
+// RuleViolation returns the machine-readable details of the rule violation
+// that caused the server to reject a transaction when the passed error, as
+// returned from methods such as SendRawTransaction, includes them.
+//
+// The returned bool is false when the error is not an RPC error or does not
+// include details about a rule violation.
+func RuleViolation(err error) (*chainjson.RuleViolationData, bool) {
+	var rpcErr *dcrjson.RPCError
+	if !errors.As(err, &rpcErr) || len(rpcErr.Data) == 0 {
+		return nil, false
+	}
+
+	var data chainjson.RuleViolationData
+	if err := json.Unmarshal(rpcErr.Data, &data); err != nil {
+		return nil, false
+	}
+	if data.Category == "" || data.Code == "" {
+		return nil, false
+	}
+	return &data, true
+}
+
 // FutureSearchRawTransactionsResult is a future promise to deliver the result
 // of the SearchRawTransactionsAsync RPC invocation (or an applicable error).
 type FutureSearchRawTransactionsResult cmdRes
@@ -447,7 +524,7 @@ func (c *Client) SearchRawTransactionsAsync(ctx context.Context,
 	verbose := dcrjson.Int(0)
 	prevOut := dcrjson.Int(0)
 	cmd := chainjson.NewSearchRawTransactionsCmd(addr, verbose, &skip, &count,
-		prevOut, &reverse, &filterAddrs)
+		prevOut, &reverse, &filterAddrs, nil)
 	return (*FutureSearchRawTransactionsResult)(c.sendCmd(ctx, cmd))
 }
 
@@ -505,7 +582,7 @@ func (c *Client) SearchRawTransactionsVerboseAsync(ctx context.Context,
 		prevOut = dcrjson.Int(1)
 	}
 	cmd := chainjson.NewSearchRawTransactionsCmd(addr, verbose, &skip, &count,
-		prevOut, &reverse, filterAddrs)
+		prevOut, &reverse, filterAddrs, nil)
 	return (*FutureSearchRawTransactionsVerboseResult)(c.sendCmd(ctx, cmd))
 }
 
@@ -523,3 +600,64 @@ func (c *Client) SearchRawTransactionsVerbose(ctx context.Context,
 	return c.SearchRawTransactionsVerboseAsync(ctx, address, skip, count,
 		includePrevOut, reverse, &filterAddrs).Receive()
 }
+
+// FutureSearchRawTransactionsPageResult is a future promise to deliver the
+// result of the SearchRawTransactionsPageAsync RPC invocation (or an
+// applicable error).
+type FutureSearchRawTransactionsPageResult cmdRes
+
+// Receive waits for the response promised by the future and returns a page of
+// the found transactions along with the pagination details.
+func (r *FutureSearchRawTransactionsPageResult) Receive() (*chainjson.SearchRawTransactionsPageResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal as a paginated searchrawtransactions result object.
+	var result chainjson.SearchRawTransactionsPageResult
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// SearchRawTransactionsPageAsync returns an instance of a type that can be
+// used to get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See SearchRawTransactionsPage for the blocking version and more details.
+func (c *Client) SearchRawTransactionsPageAsync(ctx context.Context,
+	address dcrutil.Address, cursor string, limit int, includePrevOut,
+	reverse bool, filterAddrs []string) *FutureSearchRawTransactionsPageResult {
+
+	addr := address.Address()
+	verbose := dcrjson.Int(1)
+	prevOut := dcrjson.Int(0)
+	if includePrevOut {
+		prevOut = dcrjson.Int(1)
+	}
+	skip := dcrjson.Int(0)
+	cmd := chainjson.NewSearchRawTransactionsCmd(addr, verbose, skip, &limit,
+		prevOut, &reverse, &filterAddrs, &cursor)
+	return (*FutureSearchRawTransactionsPageResult)(c.sendCmd(ctx, cmd))
+}
+
+// SearchRawTransactionsPage returns a page of up to limit data structures that
+// describe transactions which involve the passed address.  An empty cursor
+// requests the first page while the next cursor of the returned page requests
+// the page after it.  Unlike paging with SearchRawTransactionsVerbose, paging
+// past the final transaction returns an empty page instead of an error.  See
+// NewSearchRawTransactionsIterator for a convenient way to iterate all of them.
+//
+// NOTE: Chain servers do not typically provide this capability unless it has
+// specifically been enabled.
+func (c *Client) SearchRawTransactionsPage(ctx context.Context,
+	address dcrutil.Address, cursor string, limit int, includePrevOut,
+	reverse bool, filterAddrs []string) (*chainjson.SearchRawTransactionsPageResult, error) {
+
+	return c.SearchRawTransactionsPageAsync(ctx, address, cursor, limit,
+		includePrevOut, reverse, filterAddrs).Receive()
+}
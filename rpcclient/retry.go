@@ -0,0 +1,370 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/decred/dcrd/dcrjson/v3"
+)
+
+const (
+	// defaultRetryInitialBackoff is the default amount of time to wait
+	// before retrying a request that failed for the first time.
+	defaultRetryInitialBackoff = time.Millisecond * 100
+
+	// defaultRetryMaxBackoff is the default maximum amount of time to wait
+	// before retrying a failed request.
+	defaultRetryMaxBackoff = time.Second * 5
+)
+
+// RetryPolicy describes how requests that fail due to transient errors are
+// retried.  The amount of time to wait before each retry doubles after every
+// failed attempt up to the maximum and is randomized to avoid many clients
+// retrying in lockstep.
+//
+// Retries are independent of the automatic reconnect of websocket clients,
+// which only re-issues requests that are still awaiting a response when the
+// connection is lost, so they also apply to HTTP POST mode.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a request is attempted,
+	// including the initial attempt.  Values less than two disable
+	// retries.
+	MaxAttempts int
+
+	// InitialBackoff is the amount of time to wait before the first retry.
+	// Zero selects a default of 100 milliseconds.
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the maximum amount of time to wait before a retry.
+	// Zero selects a default of 5 seconds.
+	MaxBackoff time.Duration
+
+	// Retryable returns whether or not the provided error a request failed
+	// with is transient and therefore the request should be retried.  Nil
+	// selects IsTransientError.
+	//
+	// NOTE: Requests that fail due to transport errors might have been
+	// processed by the server, so only requests for idempotent methods, as
+	// determined by Idempotent, are retried.
+	Retryable func(err error) bool
+
+	// Idempotent returns whether or not requests for the provided method
+	// are safe to repeat and therefore may be retried.  Requests for other
+	// methods that fail due to a retryable error are not retried and fail
+	// with a PermanentError instead.  Nil selects IsIdempotentMethod.
+	Idempotent func(method string) bool
+}
+
+// idempotent returns whether or not requests for the provided method may be
+// retried according to the policy.
+func (p *RetryPolicy) idempotent(method string) bool {
+	if p.Idempotent != nil {
+		return p.Idempotent(method)
+	}
+	return IsIdempotentMethod(method)
+}
+
+// retryable returns whether or not a request that failed with the provided
+// error should be retried according to the policy.  Errors wrapped with
+// PermanentError are never retried.
+func (p *RetryPolicy) retryable(err error) bool {
+	var permErr *PermanentError
+	if errors.As(err, &permErr) {
+		return false
+	}
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return IsTransientError(err)
+}
+
+// backoff returns the amount of time to wait before retrying a request that
+// failed the provided number of attempts.  The returned duration is randomly
+// selected from the upper half of the exponential backoff for the attempt.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	initial := p.InitialBackoff
+	if initial <= 0 {
+		initial = defaultRetryInitialBackoff
+	}
+	max := p.MaxBackoff
+	if max <= 0 {
+		max = defaultRetryMaxBackoff
+	}
+
+	backoff := initial
+	for i := 1; i < attempt && backoff < max; i++ {
+		backoff *= 2
+	}
+	if backoff > max {
+		backoff = max
+	}
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(backoff-half)+1))
+}
+
+// idempotentMethods houses the methods of the RPC server, including the
+// dcrwallet methods wrapped by the client, that only read state, or that
+// otherwise have the same effect no matter how many times they are requested,
+// and therefore are safe to retry.
+var idempotentMethods = map[string]struct{}{
+	"checktransactionstandard": {},
+	"comparechainwork":         {},
+	"decoderawtransaction":     {},
+	"decodescript":             {},
+	"estimatefee":              {},
+	"estimatesmartfee":         {},
+	"estimatestakediff":        {},
+	"evaluatetxlocks":          {},
+	"existsaddress":            {},
+	"existsaddresses":          {},
+	"existsexpiredtickets":     {},
+	"existsliveticket":         {},
+	"existslivetickets":        {},
+	"existsmempooltxs":         {},
+	"existsmissedtickets":      {},
+	"getaddednodeinfo":         {},
+	"getaddressactivity":       {},
+	"getaddressutxos":          {},
+	"getbestblock":             {},
+	"getbestblockhash":         {},
+	"getblock":                 {},
+	"getblockchaininfo":        {},
+	"getblockcount":            {},
+	"getblockhash":             {},
+	"getblockheader":           {},
+	"getblockstats":            {},
+	"getblocksubsidy":          {},
+	"getbalance":               {},
+	"getblocktemplate":         {},
+	"getcfilter":               {},
+	"getcfilterheader":         {},
+	"getcfilterv2":             {},
+	"getchainparams":           {},
+	"getchaintips":             {},
+	"getcoinsupply":            {},
+	"getconnectioncount":       {},
+	"getcurrentnet":            {},
+	"getdifficulty":            {},
+	"getdiskspaceinfo":         {},
+	"getgenerate":              {},
+	"gethashespersec":          {},
+	"getheaders":               {},
+	"getinfo":                  {},
+	"getmempoolinfo":           {},
+	"getmempoolreplacements":   {},
+	"getminingaddrs":           {},
+	"getmininginfo":            {},
+	"getnettotals":             {},
+	"getnetworkhashps":         {},
+	"getnetworkinfo":           {},
+	"getpeerinfo":              {},
+	"getrawmempool":            {},
+	"getrawtransaction":        {},
+	"getrejectedtransactions":  {},
+	"getsignalingstats":        {},
+	"getstakedifficulty":       {},
+	"getstakeversioninfo":      {},
+	"getstakeversions":         {},
+	"getstandardpolicy":        {},
+	"getsyncpeer":              {},
+	"gettemplatedecisions":     {},
+	"getticketpoolvalue":       {},
+	"gettimeinfo":              {},
+	"gettxout":                 {},
+	"gettxoutsetinfo":          {},
+	"gettxscriptcost":          {},
+	"getvoteinfo":              {},
+	"getworkstats":             {},
+	"help":                     {},
+	"listbanned":               {},
+	"listrpcclients":           {},
+	"listunspent":              {},
+	"livetickets":              {},
+	"missedtickets":            {},
+	"ping":                     {},
+	"proposeblock":             {},
+	"searchrawtransactions":    {},
+	"session":                  {},
+	"signrawtransaction":       {},
+	"simulatedifficulty":       {},
+	"ticketfeeinfo":            {},
+	"ticketsforaddress":        {},
+	"ticketvwap":               {},
+	"txfeeinfo":                {},
+	"validateaddress":          {},
+	"verifychain":              {},
+	"verifymessage":            {},
+	"version":                  {},
+}
+
+// IsIdempotentMethod returns whether or not requests for the provided method of
+// the RPC server are safe to repeat, which is the case for methods that only
+// read state, such as getblock and getrawtransaction.  Methods that modify the
+// state of the server or the network, such as sendrawtransaction, submitblock,
+// and sendtoaddress, as well as methods that are unknown to this package, such
+// as most of those of dcrwallet requested via RawRequest, are not.
+func IsIdempotentMethod(method string) bool {
+	_, ok := idempotentMethods[method]
+	return ok
+}
+
+// PermanentError wraps an error a request failed with to indicate that the
+// request must not be retried.  Requests for methods that are not idempotent
+// which fail due to an error that would otherwise be retried fail with a
+// PermanentError when the client is configured with a retry policy, since the
+// server might have processed them regardless.  Request interceptors may also
+// wrap errors with it to prevent retries.
+type PermanentError struct {
+	// Err is the error the request failed with.
+	Err error
+}
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the error the request failed with.
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// HTTPStatusError describes a reply of an RPC server in HTTP POST mode that is
+// not a valid JSON-RPC response, such as those returned by a reverse proxy when
+// the server is temporarily unavailable.
+type HTTPStatusError struct {
+	// StatusCode is the HTTP status code of the reply.
+	StatusCode int
+
+	// Response is the raw body of the reply.
+	Response []byte
+}
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("status code: %d, response: %q", e.StatusCode,
+		string(e.Response))
+}
+
+// IsTransientError returns whether or not the provided error a request failed
+// with is likely to be transient, in which case retrying the request might
+// succeed.  This is the case for errors that occur while communicating with
+// the server, such as refused or reset connections, and for internal errors
+// reported by the server, along with HTTP status codes that indicate the
+// server is temporarily unavailable.
+//
+// Errors due to the request being canceled, timing out, or the client being
+// shutdown are never considered transient, nor are errors wrapped with
+// PermanentError.
+func IsTransientError(err error) bool {
+	var permErr *PermanentError
+	switch {
+	case err == nil, errors.As(err, &permErr):
+		return false
+	case errors.Is(err, ErrRequestCanceled), errors.Is(err, ErrRequestTimeout),
+		errors.Is(err, ErrClientShutdown), errors.Is(err, ErrClientDisconnect),
+		errors.Is(err, context.Canceled),
+		errors.Is(err, context.DeadlineExceeded):
+		return false
+	}
+
+	var rpcErr *dcrjson.RPCError
+	if errors.As(err, &rpcErr) {
+		return rpcErr.Code == dcrjson.ErrRPCInternal.Code
+	}
+
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET)
+}
+
+// sendRequestWithRetry sends the passed json request to the associated server
+// like sendRequest and, when the client is configured with a retry policy,
+// retries it in a separate goroutine until it succeeds, fails with an error
+// that is not retryable, the maximum number of attempts is reached, the passed
+// context is done, or the client is shutdown.  Only the final result is
+// delivered to the response channel of the request.
+func (c *Client) sendRequestWithRetry(ctx context.Context, jReq *jsonRequest) {
+	policy := c.config.RetryPolicy
+	if policy == nil || policy.MaxAttempts < 2 {
+		c.sendRequest(ctx, jReq)
+		return
+	}
+
+	go func() {
+		for attempt := 1; ; attempt++ {
+			// Send the request with a separate response channel so
+			// the result of failed attempts can be examined.
+			attemptChan := make(chan *response, 1)
+			attemptReq := *jReq
+			attemptReq.responseChan = attemptChan
+			c.sendRequest(ctx, &attemptReq)
+
+			var r *response
+			select {
+			case r = <-attemptChan:
+			case <-ctx.Done():
+				jReq.responseChan <- &response{err: contextError(ctx)}
+				return
+			}
+			if r.err == nil || !policy.retryable(r.err) {
+				jReq.responseChan <- r
+				return
+			}
+
+			// Requests that are not safe to repeat are not retried
+			// and their error is marked permanent so callers are
+			// able to detect that the request might have been
+			// processed by the server.
+			if !policy.idempotent(jReq.method) {
+				r.err = &PermanentError{Err: r.err}
+				jReq.responseChan <- r
+				return
+			}
+			if attempt >= policy.MaxAttempts {
+				jReq.responseChan <- r
+				return
+			}
+
+			backoff := policy.backoff(attempt)
+			log.Debugf("Retrying command [%s] with id %d in %v after "+
+				"attempt %d failed: %v", jReq.method, jReq.id, backoff,
+				attempt, r.err)
+			t := time.NewTimer(backoff)
+			select {
+			case <-t.C:
+			case <-ctx.Done():
+				t.Stop()
+				jReq.responseChan <- &response{err: contextError(ctx)}
+				return
+			case <-c.shutdown:
+				t.Stop()
+				jReq.responseChan <- &response{err: ErrClientShutdown}
+				return
+			}
+		}
+	}()
+}
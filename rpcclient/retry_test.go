@@ -0,0 +1,237 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrjson/v3"
+)
+
+// TestRetryPolicy ensures requests that fail due to transient errors are
+// retried up to the maximum number of attempts of the retry policy while
+// requests that fail due to other errors are not.
+func TestRetryPolicy(t *testing.T) {
+	// Create a server that fails the first requests it receives in the
+	// configured way and replies with a block count afterwards.
+	var numReqs, numFailures int32
+	var failure func(w http.ResponseWriter)
+	cfg := &ConnConfig{
+		RetryPolicy: &RetryPolicy{
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     time.Millisecond * 5,
+		},
+	}
+	server, c := newTestHTTPClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&numReqs, 1) <= atomic.LoadInt32(&numFailures) {
+			failure(w)
+			return
+		}
+		w.Write([]byte(`{"result":5,"error":null,"id":1}`))
+	}, cfg)
+	defer server.Close()
+	defer c.Shutdown()
+
+	internalError := func(w http.ResponseWriter) {
+		w.Write([]byte(`{"result":null,"error":{"code":-32603,` +
+			`"message":"Internal error"},"id":1}`))
+	}
+	tests := []struct {
+		name        string
+		failure     func(w http.ResponseWriter)
+		numFailures int32
+		wantReqs    int32
+		wantErr     bool
+	}{{
+		name:        "internal errors",
+		failure:     internalError,
+		numFailures: 2,
+		wantReqs:    3,
+	}, {
+		name: "dropped connection",
+		failure: func(w http.ResponseWriter) {
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+		},
+		numFailures: 1,
+		wantReqs:    2,
+	}, {
+		name:        "max attempts reached",
+		failure:     internalError,
+		numFailures: 5,
+		wantReqs:    3,
+		wantErr:     true,
+	}, {
+		name: "service unavailable",
+		failure: func(w http.ResponseWriter) {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		},
+		numFailures: 2,
+		wantReqs:    3,
+	}, {
+		name: "not retryable",
+		failure: func(w http.ResponseWriter) {
+			w.Write([]byte(`{"result":null,"error":{"code":-8,` +
+				`"message":"Invalid parameter"},"id":1}`))
+		},
+		numFailures: 1,
+		wantReqs:    1,
+		wantErr:     true,
+	}}
+
+	for _, test := range tests {
+		atomic.StoreInt32(&numReqs, 0)
+		atomic.StoreInt32(&numFailures, test.numFailures)
+		failure = test.failure
+
+		count, err := c.GetBlockCount(context.Background())
+		if test.wantErr != (err != nil) {
+			t.Fatalf("%q: unexpected error -- got %v, want error %v",
+				test.name, err, test.wantErr)
+		}
+		if !test.wantErr && count != 5 {
+			t.Fatalf("%q: unexpected block count -- got %d, want 5",
+				test.name, count)
+		}
+		if got := atomic.LoadInt32(&numReqs); got != test.wantReqs {
+			t.Fatalf("%q: unexpected number of requests -- got %d, "+
+				"want %d", test.name, got, test.wantReqs)
+		}
+	}
+
+	// Ensure requests for methods that are not idempotent are not retried
+	// and fail with a PermanentError.
+	atomic.StoreInt32(&numReqs, 0)
+	atomic.StoreInt32(&numFailures, 1)
+	failure = internalError
+	_, err := c.RawRequest(context.Background(), "sendrawtransaction", nil)
+	var permErr *PermanentError
+	if !errors.As(err, &permErr) {
+		t.Fatalf("unexpected error for non-idempotent request -- got %v, "+
+			"want PermanentError", err)
+	}
+	var rpcErr *dcrjson.RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != dcrjson.ErrRPCInternal.Code {
+		t.Fatalf("permanent error does not wrap the request error: %v", err)
+	}
+	if got := atomic.LoadInt32(&numReqs); got != 1 {
+		t.Fatalf("unexpected number of non-idempotent requests -- got %d, "+
+			"want 1", got)
+	}
+
+	// Ensure requests canceled while waiting to be retried fail with
+	// ErrRequestCanceled.
+	atomic.StoreInt32(&numReqs, 0)
+	atomic.StoreInt32(&numFailures, 5)
+	failure = internalError
+	c.config.RetryPolicy.InitialBackoff = time.Hour
+	c.config.RetryPolicy.MaxBackoff = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(time.Millisecond*50, cancel)
+	_, err = c.GetBlockCount(ctx)
+	if !errors.Is(err, ErrRequestCanceled) {
+		t.Fatalf("unexpected error for canceled retry -- got %v, want %v",
+			err, ErrRequestCanceled)
+	}
+}
+
+// TestRetryBackoff ensures the backoff of the retry policy grows exponentially
+// up to the maximum backoff and is randomized within the upper half.
+func TestRetryBackoff(t *testing.T) {
+	t.Parallel()
+
+	policy := &RetryPolicy{
+		InitialBackoff: time.Millisecond * 100,
+		MaxBackoff:     time.Millisecond * 1000,
+	}
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: time.Millisecond * 100},
+		{attempt: 2, want: time.Millisecond * 200},
+		{attempt: 4, want: time.Millisecond * 800},
+		{attempt: 5, want: time.Millisecond * 1000},
+		{attempt: 100, want: time.Millisecond * 1000},
+	}
+	for _, test := range tests {
+		for i := 0; i < 100; i++ {
+			got := policy.backoff(test.attempt)
+			if got < test.want/2 || got > test.want {
+				t.Fatalf("attempt %d: backoff %v is not in the range "+
+					"[%v, %v]", test.attempt, got, test.want/2,
+					test.want)
+			}
+		}
+	}
+}
+
+// TestIsTransientError ensures errors are classified as transient as intended.
+func TestIsTransientError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"internal error", dcrjson.ErrRPCInternal, true},
+		{"other rpc error", &dcrjson.RPCError{Code: -5}, false},
+		{"permanent", &PermanentError{Err: dcrjson.ErrRPCInternal}, false},
+		{"service unavailable", &HTTPStatusError{StatusCode: 503}, true},
+		{"bad gateway", &HTTPStatusError{StatusCode: 502}, true},
+		{"not found", &HTTPStatusError{StatusCode: 404}, false},
+		{"eof", io.EOF, true},
+		{"wrapped reset", fmt.Errorf("read: %w", syscall.ECONNRESET), true},
+		{"refused", syscall.ECONNREFUSED, true},
+		{"canceled", ErrRequestCanceled, false},
+		{"timeout", ErrRequestTimeout, false},
+		{"shutdown", ErrClientShutdown, false},
+		{"context canceled", context.Canceled, false},
+		{"other", errors.New("other"), false},
+	}
+	for _, test := range tests {
+		if got := IsTransientError(test.err); got != test.want {
+			t.Errorf("%q: unexpected result -- got %v, want %v",
+				test.name, got, test.want)
+		}
+	}
+}
+
+// TestIsIdempotentMethod ensures methods are classified as idempotent as
+// intended.
+func TestIsIdempotentMethod(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		method string
+		want   bool
+	}{
+		{"getblock", true},
+		{"getrawtransaction", true},
+		{"getblockcount", true},
+		{"sendrawtransaction", false},
+		{"submitblock", false},
+		{"getwork", false},
+		{"generate", false},
+		{"walletpassphrase", false},
+	}
+	for _, test := range tests {
+		if got := IsIdempotentMethod(test.method); got != test.want {
+			t.Errorf("%q: unexpected result -- got %v, want %v",
+				test.method, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,369 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"time"
+
+	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v2"
+)
+
+const (
+	// jsonrpcSemverMajor, jsonrpcSemverMinor, and jsonrpcSemverPatch are the
+	// components of the semantic version of the JSON-RPC API of the RPC
+	// server the result types used by this package correspond to.
+	jsonrpcSemverMajor = 6
+	jsonrpcSemverMinor = 1
+	jsonrpcSemverPatch = 2
+
+	// serverVersionTimeout is the maximum amount of time to wait for the
+	// RPC server to report its JSON-RPC API version when a result fails
+	// strict validation.
+	serverVersionTimeout = 10 * time.Second
+)
+
+// jsonrpcSemverString is the semantic version of the JSON-RPC API the result
+// types used by this package correspond to formatted as a string.
+var jsonrpcSemverString = fmt.Sprintf("%d.%d.%d", jsonrpcSemverMajor,
+	jsonrpcSemverMinor, jsonrpcSemverPatch)
+
+// ErrResultSchema is an error to describe the condition where the result of a
+// request does not match the result types expected for the method when the
+// client is configured to strictly validate results.  All such errors are
+// returned as a ResultSchemaError.
+var ErrResultSchema = errors.New("result does not match the expected schema")
+
+// strictResultTypes specifies the result types that each RPC method the
+// results are strictly validated for may return.  Each result type must be a
+// pointer to the type (or nil to indicate a null result).  It mirrors the
+// result types of the RPC server for the JSON-RPC API version this package
+// corresponds to.
+var strictResultTypes = map[string][]interface{}{
+	"captureprofile":           {(*chainjson.CaptureProfileResult)(nil)},
+	"checktransactionstandard": {(*chainjson.CheckTransactionStandardResult)(nil)},
+	"comparechainwork":         {(*chainjson.CompareChainWorkResult)(nil)},
+	"coordheartbeat":           {(*chainjson.CoordHeartbeatResult)(nil)},
+	"createrawsstx":            {(*string)(nil)},
+	"createrawssrtx":           {(*string)(nil)},
+	"createrawtransaction":     {(*string)(nil)},
+	"createrevocation":         {(*string)(nil)},
+	"debuglevel":               {(*string)(nil)},
+	"decoderawtransaction":     {(*chainjson.TxRawDecodeResult)(nil)},
+	"decodescript":             {(*chainjson.DecodeScriptResult)(nil)},
+	"dumppeertelemetry":        {(*chainjson.DumpPeerTelemetryResult)(nil)},
+	"estimatefee":              {(*float64)(nil)},
+	"estimatesmartfee":         {(*float64)(nil)},
+	"estimatestakediff":        {(*chainjson.EstimateStakeDiffResult)(nil)},
+	"evaluatetxlocks":          {(*chainjson.EvaluateTxLocksResult)(nil)},
+	"existsaddress":            {(*bool)(nil)},
+	"existsaddresses":          {(*string)(nil)},
+	"existsmissedtickets":      {(*string)(nil)},
+	"existsexpiredtickets":     {(*string)(nil)},
+	"existsliveticket":         {(*bool)(nil)},
+	"existslivetickets":        {(*string)(nil)},
+	"existsmempooltxs":         {(*string)(nil)},
+	"getaddednodeinfo":         {(*[]string)(nil), (*[]chainjson.GetAddedNodeInfoResult)(nil)},
+	"getaddressactivity":       {(*chainjson.GetAddressActivityResult)(nil)},
+	"getaddressutxos":          {(*chainjson.GetAddressUtxosResult)(nil)},
+	"getbestblock":             {(*chainjson.GetBestBlockResult)(nil)},
+	"generate":                 {(*[]string)(nil)},
+	"generatetoaddress":        {(*[]string)(nil)},
+	"getbestblockhash":         {(*string)(nil)},
+	"getblock":                 {(*string)(nil), (*chainjson.GetBlockVerboseResult)(nil)},
+	"getblockchaininfo":        {(*chainjson.GetBlockChainInfoResult)(nil)},
+	"getblockcount":            {(*int64)(nil)},
+	"getblockhash":             {(*string)(nil)},
+	"getblockheader":           {(*string)(nil), (*chainjson.GetBlockHeaderVerboseResult)(nil)},
+	"getblockstats":            {(*chainjson.GetBlockStatsResult)(nil)},
+	"getblocksubsidy":          {(*chainjson.GetBlockSubsidyResult)(nil)},
+	"getblocktemplate":         {(*chainjson.GetBlockTemplateResult)(nil), (*string)(nil), nil},
+	"getcfilter":               {(*string)(nil)},
+	"getcfilterheader":         {(*string)(nil)},
+	"getcfilterv2":             {(*chainjson.GetCFilterV2Result)(nil)},
+	"getchainparams":           {(*chainjson.GetChainParamsResult)(nil)},
+	"getchaintips":             {(*[]chainjson.GetChainTipsResult)(nil)},
+	"getconnectioncount":       {(*int32)(nil)},
+	"getcurrentnet":            {(*uint32)(nil)},
+	"getdifficulty":            {(*float64)(nil)},
+	"getsignalingstats":        {(*chainjson.GetSignalingStatsResult)(nil)},
+	"getstakedifficulty":       {(*chainjson.GetStakeDifficultyResult)(nil)},
+	"getstakeversioninfo":      {(*chainjson.GetStakeVersionInfoResult)(nil)},
+	"getstakeversions":         {(*chainjson.GetStakeVersionsResult)(nil)},
+	"getstandardpolicy":        {(*chainjson.GetStandardPolicyResult)(nil)},
+	"getsyncpeer":              {(*chainjson.GetSyncPeerResult)(nil)},
+	"gettemplatedecisions":     {(*[]chainjson.GetTemplateDecisionsResult)(nil)},
+	"getdiskspaceinfo":         {(*chainjson.GetDiskSpaceInfoResult)(nil)},
+	"getgenerate":              {(*bool)(nil)},
+	"gethashespersec":          {(*float64)(nil)},
+	"getheaders":               {(*chainjson.GetHeadersResult)(nil)},
+	"getinfo":                  {(*chainjson.InfoChainResult)(nil)},
+	"getmempoolinfo":           {(*chainjson.GetMempoolInfoResult)(nil)},
+	"getmempoolreplacements":   {(*chainjson.GetMempoolReplacementsResult)(nil)},
+	"getminingaddrs":           {(*chainjson.GetMiningAddrsResult)(nil)},
+	"getmininginfo":            {(*chainjson.GetMiningInfoResult)(nil)},
+	"getnettotals":             {(*chainjson.GetNetTotalsResult)(nil)},
+	"getnetworkhashps":         {(*int64)(nil), (*chainjson.GetNetworkHashPSResult)(nil)},
+	"getnetworkinfo":           {(*[]chainjson.GetNetworkInfoResult)(nil)},
+	"getpeerinfo":              {(*[]chainjson.GetPeerInfoResult)(nil)},
+	"getrawmempool":            {(*[]string)(nil), (*chainjson.GetRawMempoolVerboseResult)(nil)},
+	"getrawtransaction":        {(*string)(nil), (*chainjson.TxRawResult)(nil)},
+	"getrejectedtransactions":  {(*[]chainjson.GetRejectedTransactionsResult)(nil)},
+	"gettimeinfo":              {(*chainjson.GetTimeInfoResult)(nil)},
+	"getticketpoolvalue":       {(*float64)(nil)},
+	"gettxout":                 {(*chainjson.GetTxOutResult)(nil)},
+	"gettxoutsetinfo":          {(*chainjson.GetTxOutSetInfoResult)(nil)},
+	"gettxscriptcost":          {(*chainjson.GetTxScriptCostResult)(nil)},
+	"getvoteinfo":              {(*chainjson.GetVoteInfoResult)(nil)},
+	"getwork":                  {(*chainjson.GetWorkResult)(nil), (*bool)(nil)},
+	"getworkstats":             {(*chainjson.GetWorkStatsResult)(nil)},
+	"getcoinsupply":            {(*int64)(nil)},
+	"help":                     {(*string)(nil)},
+	"listbanned":               {(*chainjson.ListBannedResult)(nil)},
+	"listrpcclients":           {(*[]chainjson.ListRPCClientsResult)(nil)},
+	"livetickets":              {(*chainjson.LiveTicketsResult)(nil)},
+	"missedtickets":            {(*chainjson.MissedTicketsResult)(nil)},
+	"proposeblock":             {(*chainjson.ProposeBlockResult)(nil)},
+	"searchrawtransactions":    {(*string)(nil), (*[]chainjson.SearchRawTransactionsResult)(nil), (*chainjson.SearchRawTransactionsPageResult)(nil)},
+	"sendrawtransaction":       {(*string)(nil)},
+	"simulatedifficulty":       {(*chainjson.SimulateDifficultyResult)(nil)},
+	"stop":                     {(*string)(nil)},
+	"submitblock":              {nil, (*string)(nil)},
+	"ticketfeeinfo":            {(*chainjson.TicketFeeInfoResult)(nil)},
+	"ticketsforaddress":        {(*chainjson.TicketsForAddressResult)(nil)},
+	"ticketvwap":               {(*float64)(nil)},
+	"txfeeinfo":                {(*chainjson.TxFeeInfoResult)(nil)},
+	"validateaddress":          {(*chainjson.ValidateAddressChainResult)(nil)},
+	"verifychain":              {(*bool)(nil)},
+	"verifymessage":            {(*bool)(nil), (*chainjson.VerifyMessageResult)(nil)},
+	"version":                  {(*map[string]chainjson.VersionResult)(nil)},
+	"session":                  {(*chainjson.SessionResult)(nil)},
+}
+
+// ResultSchemaError describes a result returned by the RPC server that does not
+// match any of the result types expected for the method when the client is
+// configured to strictly validate results.  This typically happens when the
+// client and the RPC server implement different versions of the JSON-RPC API,
+// so the error reports both versions when they differ.  It is classified as
+// ErrResultSchema and unwraps to the error encountered while decoding the
+// result.
+type ResultSchemaError struct {
+	// Method is the method of the request the result was returned for.
+	Method string
+
+	// Result is the raw result that failed validation.
+	Result json.RawMessage
+
+	// Err is the error encountered while decoding the result into the
+	// expected result type that is the closest match.
+	Err error
+
+	// ClientVersion is the JSON-RPC API version the result types of the
+	// client correspond to.
+	ClientVersion chainjson.VersionResult
+
+	// ServerVersion is the JSON-RPC API version reported by the RPC server.
+	// It is nil when the version could not be determined.
+	ServerVersion *chainjson.VersionResult
+}
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e *ResultSchemaError) Error() string {
+	msg := fmt.Sprintf("result of %s does not match the expected schema: %v",
+		e.Method, e.Err)
+	switch {
+	case e.ServerVersion == nil:
+		msg += fmt.Sprintf(" (client API version %s, server API version "+
+			"unknown)", e.ClientVersion.VersionString)
+	case e.VersionSkew():
+		msg += fmt.Sprintf(" (client API version %s, server API version "+
+			"%s)", e.ClientVersion.VersionString,
+			e.ServerVersion.VersionString)
+	}
+	return msg
+}
+
+// Is implements the interface used by errors.Is to determine whether the error
+// is classified as the target sentinel error.
+func (e *ResultSchemaError) Is(target error) bool {
+	return target == ErrResultSchema
+}
+
+// Unwrap returns the error encountered while decoding the result.
+func (e *ResultSchemaError) Unwrap() error {
+	return e.Err
+}
+
+// VersionSkew returns whether or not the RPC server reported a JSON-RPC API
+// version that differs from the version the result types of the client
+// correspond to.  Differences in the patch version are ignored since they do
+// not change the API.
+func (e *ResultSchemaError) VersionSkew() bool {
+	if e.ServerVersion == nil {
+		return false
+	}
+	return e.ServerVersion.Major != e.ClientVersion.Major ||
+		e.ServerVersion.Minor != e.ClientVersion.Minor
+}
+
+// decodeStrict decodes the passed JSON into the value pointed to by v while
+// rejecting fields that are not part of the type and any data following the
+// value.  Numbers that overflow the type they are decoded into are rejected by
+// the decoder as well.
+func decodeStrict(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("unexpected data after result")
+	}
+	return nil
+}
+
+// validateResultSchema returns an error when the passed result does not
+// strictly match any of the result types expected for the provided method.
+// Results of methods without known result types are not validated.
+func validateResultSchema(method string, result []byte) error {
+	resultTypes, ok := strictResultTypes[method]
+	if !ok {
+		return nil
+	}
+
+	// Report the error of the first result type the result is able to be
+	// decoded into when unknown fields are allowed since it is the closest
+	// match, and the error of the first result type otherwise.
+	var firstErr, closestErr error
+	for _, resultType := range resultTypes {
+		var err error
+		if resultType == nil {
+			if !bytes.Equal(bytes.TrimSpace(result), []byte("null")) {
+				err = errors.New("result is not null")
+			}
+		} else {
+			t := reflect.TypeOf(resultType).Elem()
+			err = decodeStrict(result, reflect.New(t).Interface())
+			if err != nil && closestErr == nil &&
+				json.Unmarshal(result, reflect.New(t).Interface()) == nil {
+
+				closestErr = err
+			}
+		}
+		if err == nil {
+			return nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	if closestErr != nil {
+		return closestErr
+	}
+	return firstErr
+}
+
+// serverVersionCache houses the JSON-RPC API version reported by the RPC
+// server the client is currently using so that it is only requested once per
+// server.
+type serverVersionCache struct {
+	mtx     sync.Mutex
+	host    string
+	version *chainjson.VersionResult
+}
+
+// serverAPIVersion returns the JSON-RPC API version of the RPC server the
+// client is currently using, requesting it from the server when it is not
+// already known using the passed context.  Nil is returned when the version
+// could not be determined.
+//
+// This function is safe for concurrent access.
+func (c *Client) serverAPIVersion(ctx context.Context) *chainjson.VersionResult {
+	host := c.host()
+	c.serverVersion.mtx.Lock()
+	if c.serverVersion.version != nil && c.serverVersion.host == host {
+		version := c.serverVersion.version
+		c.serverVersion.mtx.Unlock()
+		return version
+	}
+	c.serverVersion.mtx.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, serverVersionTimeout)
+	defer cancel()
+	versions, err := c.Version(ctx)
+	if err != nil {
+		log.Debugf("Unable to determine the JSON-RPC API version of %s: %v",
+			host, err)
+		return nil
+	}
+	version, ok := versions["dcrdjsonrpcapi"]
+	if !ok {
+		return nil
+	}
+
+	c.serverVersion.mtx.Lock()
+	c.serverVersion.host = host
+	c.serverVersion.version = &version
+	c.serverVersion.mtx.Unlock()
+	return &version
+}
+
+// validateResult returns the response channel to send a request for the
+// provided method with the passed context on given the channel the response is
+// ultimately delivered to.  When the client is configured to strictly validate
+// results, the returned channel is serviced by a goroutine that replaces
+// successful responses with a ResultSchemaError when the result does not match
+// the result types expected for the method.
+func (c *Client) validateResult(ctx context.Context, method string, responseChan chan *response) chan *response {
+	if !c.config.StrictResults {
+		return responseChan
+	}
+	if _, ok := strictResultTypes[method]; !ok {
+		return responseChan
+	}
+
+	validateChan := make(chan *response, 1)
+	go func() {
+		r := <-validateChan
+		if r.err != nil {
+			responseChan <- r
+			return
+		}
+		err := validateResultSchema(method, r.result)
+		if err == nil {
+			responseChan <- r
+			return
+		}
+
+		schemaErr := &ResultSchemaError{
+			Method: method,
+			Result: r.result,
+			Err:    err,
+			ClientVersion: chainjson.VersionResult{
+				VersionString: jsonrpcSemverString,
+				Major:         jsonrpcSemverMajor,
+				Minor:         jsonrpcSemverMinor,
+				Patch:         jsonrpcSemverPatch,
+			},
+		}
+
+		// Determine the version of the server to report any version
+		// skew.  The version is not requested when the result of the
+		// version request itself is invalid.
+		if method != "version" {
+			schemaErr.ServerVersion = c.serverAPIVersion(ctx)
+		}
+		log.Warnf("%v", schemaErr)
+		responseChan <- &response{err: schemaErr}
+	}()
+	return validateChan
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestValidateResultSchema ensures results are strictly validated against the
+// result types expected for their method.
+func TestValidateResultSchema(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		result string
+		valid  bool
+	}{{
+		name:   "valid struct",
+		method: "getbestblock",
+		result: `{"hash":"00","height":5}`,
+		valid:  true,
+	}, {
+		name:   "unknown field",
+		method: "getbestblock",
+		result: `{"hash":"00","height":5,"extra":true}`,
+	}, {
+		name:   "wrong field type",
+		method: "getbestblock",
+		result: `{"hash":"00","height":"5"}`,
+	}, {
+		name:   "integer overflow",
+		method: "getconnectioncount",
+		result: `2147483648`,
+	}, {
+		name:   "float overflow",
+		method: "getdifficulty",
+		result: `1e400`,
+	}, {
+		name:   "trailing data",
+		method: "getblockcount",
+		result: `5 6`,
+	}, {
+		name:   "second result type",
+		method: "getrawmempool",
+		result: `["00","01"]`,
+		valid:  true,
+	}, {
+		name:   "null result type",
+		method: "submitblock",
+		result: `null`,
+		valid:  true,
+	}, {
+		name:   "unknown method",
+		method: "walletpassphrase",
+		result: `{"anything":1}`,
+		valid:  true,
+	}}
+
+	for _, test := range tests {
+		err := validateResultSchema(test.method, []byte(test.result))
+		if test.valid && err != nil {
+			t.Errorf("%q: unexpected error: %v", test.name, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("%q: did not receive expected error", test.name)
+		}
+	}
+}
+
+// TestClientStrictResults ensures clients configured to strictly validate
+// results fail requests with results that do not match the expected result
+// types and report the version of the server.
+func TestClientStrictResults(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		switch req.Method {
+		case "getbestblock":
+			w.Write([]byte(`{"result":{"hash":"00","height":5,` +
+				`"newfield":1},"error":null,"id":1}`))
+		case "getblockcount":
+			w.Write([]byte(`{"result":5,"error":null,"id":1}`))
+		case "version":
+			w.Write([]byte(`{"result":{"dcrdjsonrpcapi":{"versionstring":` +
+				`"7.0.0","major":7,"minor":0,"patch":0}},"error":null,` +
+				`"id":1}`))
+		}
+	}
+
+	// Ensure the result with an unknown field is accepted by default.
+	ctx := context.Background()
+	server, c := newTestHTTPClient(t, handler, nil)
+	defer server.Close()
+	defer c.Shutdown()
+	if _, _, err := c.GetBestBlock(ctx); err != nil {
+		t.Fatalf("unexpected error without strict results: %v", err)
+	}
+
+	// Ensure the result is rejected in strict mode and the version skew is
+	// reported while valid results are still accepted.
+	strictServer, c := newTestHTTPClient(t, handler,
+		&ConnConfig{StrictResults: true})
+	defer strictServer.Close()
+	defer c.Shutdown()
+	if _, err := c.GetBlockCount(ctx); err != nil {
+		t.Fatalf("unexpected error for valid result: %v", err)
+	}
+	_, _, err := c.GetBestBlock(ctx)
+	if !errors.Is(err, ErrResultSchema) {
+		t.Fatalf("unexpected error for invalid result: %v", err)
+	}
+	var schemaErr *ResultSchemaError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("error is not a ResultSchemaError: %v", err)
+	}
+	if schemaErr.Method != "getbestblock" {
+		t.Fatalf("unexpected method: got %q, want %q", schemaErr.Method,
+			"getbestblock")
+	}
+	if !schemaErr.VersionSkew() || schemaErr.ServerVersion.Major != 7 {
+		t.Fatalf("version skew not reported: %v", err)
+	}
+	if !strings.Contains(err.Error(), "server API version 7.0.0") {
+		t.Fatalf("error does not report server version: %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v2"
+)
+
+// defaultTemplateRetryDelay is the default amount of time a template
+// subscriber waits before requesting a template again after a request failed.
+const defaultTemplateRetryDelay = time.Second * 5
+
+// TemplateSubscriber keeps a connection to the server open via long polling
+// getblocktemplate requests and delivers each distinct block template the
+// server generates over a channel.  It is the piece of mining pool software
+// that keeps the work handed out to miners current.
+//
+// Templates are deduplicated by their long poll id, so templates the server
+// returns again once a long poll times out without a new template being
+// generated are not delivered.  Failed requests are retried after a delay
+// until the subscriber is stopped.
+type TemplateSubscriber struct {
+	c          *Client
+	templates  chan *chainjson.GetBlockTemplateResult
+	retryDelay time.Duration
+
+	mtx sync.Mutex
+	err error
+}
+
+// NewTemplateSubscriber returns a TemplateSubscriber which delivers block
+// templates over a channel with the provided buffer size.  It immediately
+// launches a goroutine which requests the current template and then long polls
+// for new templates until the passed context is done or the client is
+// shutdown, at which point the channel is closed.
+//
+// Consumers must keep receiving from the channel since no further templates
+// are requested while it is full.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) NewTemplateSubscriber(ctx context.Context, bufferSize int) *TemplateSubscriber {
+	s := &TemplateSubscriber{
+		c:          c,
+		templates:  make(chan *chainjson.GetBlockTemplateResult, bufferSize),
+		retryDelay: defaultTemplateRetryDelay,
+	}
+
+	c.wg.Add(1)
+	go s.run(ctx)
+	return s
+}
+
+// Templates returns the channel new block templates are delivered to.  The
+// channel is closed once the subscriber stops.
+func (s *TemplateSubscriber) Templates() <-chan *chainjson.GetBlockTemplateResult {
+	return s.templates
+}
+
+// Err returns the reason the subscriber stopped, which is either the error of
+// the context passed when it was created or ErrClientShutdown.  It returns nil
+// while the subscriber is running.
+func (s *TemplateSubscriber) Err() error {
+	s.mtx.Lock()
+	err := s.err
+	s.mtx.Unlock()
+	return err
+}
+
+// stopErr returns the reason the subscriber must stop when the provided context
+// is done or the client is shutdown and nil otherwise.
+func (s *TemplateSubscriber) stopErr(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.c.shutdown:
+		return ErrClientShutdown
+	default:
+	}
+	return nil
+}
+
+// wait waits for the retry delay of the subscriber to elapse.  It returns the
+// reason the subscriber must stop when the provided context is done or the
+// client is shutdown before then.
+func (s *TemplateSubscriber) wait(ctx context.Context) error {
+	t := time.NewTimer(s.retryDelay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.c.shutdown:
+		return ErrClientShutdown
+	}
+}
+
+// run requests templates and delivers the distinct ones until the provided
+// context is done or the client is shutdown.
+//
+// This must be run as a goroutine.
+func (s *TemplateSubscriber) run(ctx context.Context) {
+	defer s.c.wg.Done()
+
+	var err error
+	var lastID string
+	for err == nil {
+		request := &chainjson.TemplateRequest{
+			Capabilities: []string{"longpoll"},
+			LongPollID:   lastID,
+		}
+		template, reqErr := s.c.GetBlockTemplate(ctx, request)
+		if reqErr != nil {
+			if err = s.stopErr(ctx); err != nil {
+				break
+			}
+			log.Warnf("Unable to retrieve block template: %v", reqErr)
+			err = s.wait(ctx)
+			continue
+		}
+
+		// Skip templates that were already delivered, such as those
+		// returned when a long poll times out.
+		if template.LongPollID != "" && template.LongPollID == lastID {
+			continue
+		}
+		lastID = template.LongPollID
+
+		select {
+		case s.templates <- template:
+		case <-ctx.Done():
+			err = ctx.Err()
+		case <-s.c.shutdown:
+			err = ErrClientShutdown
+		}
+
+		// Servers that do not support long polling return templates
+		// without a long poll id, so fall back to periodically polling
+		// them.
+		if err == nil && lastID == "" {
+			err = s.wait(ctx)
+		}
+	}
+
+	s.mtx.Lock()
+	s.err = err
+	s.mtx.Unlock()
+	close(s.templates)
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestTemplateSubscriber ensures the template subscriber long polls with the id
+// of the most recently delivered template, does not deliver the same template
+// more than once, and closes the channel once its context is done.
+func TestTemplateSubscriber(t *testing.T) {
+	// Create a server that returns the same template twice before
+	// returning a new one, which it then returns for all further requests,
+	// and records the long poll ids of the requests.
+	var mtx sync.Mutex
+	var longPollIDs []string
+	server, c := newTestHTTPClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Params []struct {
+				LongPollID string `json:"longpollid"`
+			} `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		mtx.Lock()
+		longPollIDs = append(longPollIDs, req.Params[0].LongPollID)
+		numReqs := len(longPollIDs)
+		mtx.Unlock()
+
+		id := "a"
+		if numReqs > 2 {
+			time.Sleep(time.Millisecond * 10)
+			id = "b"
+		}
+		fmt.Fprintf(w, `{"result":{"header":"%s","longpollid":"%s"},`+
+			`"error":null,"id":1}`, id, id)
+	}, nil)
+	defer server.Close()
+	defer c.Shutdown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sub := c.NewTemplateSubscriber(ctx, 0)
+	for _, want := range []string{"a", "b"} {
+		select {
+		case template := <-sub.Templates():
+			if template.LongPollID != want {
+				t.Fatalf("unexpected template -- got %q, want %q",
+					template.LongPollID, want)
+			}
+		case <-time.After(time.Second * 5):
+			t.Fatalf("timeout waiting for template %q", want)
+		}
+	}
+	if err := sub.Err(); err != nil {
+		t.Fatalf("unexpected error while running: %v", err)
+	}
+
+	// Ensure the channel is closed without delivering the duplicate
+	// templates once the context is canceled.
+	cancel()
+	for template := range sub.Templates() {
+		t.Fatalf("unexpected duplicate template %q", template.LongPollID)
+	}
+	if err := sub.Err(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("unexpected error -- got %v, want %v", err, context.Canceled)
+	}
+
+	mtx.Lock()
+	defer mtx.Unlock()
+	want := []string{"", "a", "a"}
+	if len(longPollIDs) < len(want) {
+		t.Fatalf("unexpected long poll ids -- got %q, want prefix %q",
+			longPollIDs, want)
+	}
+	for i := range want {
+		if longPollIDs[i] != want[i] {
+			t.Fatalf("unexpected long poll ids -- got %q, want prefix %q",
+				longPollIDs, want)
+		}
+	}
+}
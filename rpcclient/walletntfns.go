@@ -0,0 +1,239 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"encoding/json"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil/v3"
+)
+
+// These are the methods of the notifications sent by the websocket JSON-RPC
+// server of dcrwallet.
+const (
+	accountBalanceNtfnMethod    = "accountbalance"
+	dcrdConnectedNtfnMethod     = "dcrdconnected"
+	newTxNtfnMethod             = "newtx"
+	revocationCreatedNtfnMethod = "revocationcreated"
+	ticketPurchasedNtfnMethod   = "ticketpurchased"
+	voteCreatedNtfnMethod       = "votecreated"
+	walletLockStateNtfnMethod   = "walletlockstate"
+)
+
+// WalletTxDetails houses the details of a wallet transaction delivered by a
+// newtx notification.  It mirrors the commonly used fields of the results of
+// the listtransactions RPC of dcrwallet.
+type WalletTxDetails struct {
+	Account       string   `json:"account"`
+	Address       string   `json:"address,omitempty"`
+	Amount        float64  `json:"amount"`
+	BlockHash     string   `json:"blockhash,omitempty"`
+	BlockIndex    *int64   `json:"blockindex,omitempty"`
+	BlockTime     int64    `json:"blocktime,omitempty"`
+	Category      string   `json:"category"`
+	Confirmations int64    `json:"confirmations"`
+	Fee           *float64 `json:"fee,omitempty"`
+	Generated     bool     `json:"generated,omitempty"`
+	Time          int64    `json:"time"`
+	TimeReceived  int64    `json:"timereceived"`
+	TxID          string   `json:"txid"`
+	TxType        *string  `json:"txtype,omitempty"`
+	Vout          uint32   `json:"vout"`
+}
+
+// WalletNotificationHandlers defines callback function pointers to invoke with
+// the notifications sent by the websocket JSON-RPC server of dcrwallet.  Unlike
+// the dcrd notifications, dcrwallet sends these to all websocket clients
+// without any registration.  The chain notifications dcrwallet relays from its
+// backing dcrd server, such as winningtickets and newtickets, are delivered to
+// the corresponding handlers of NotificationHandlers instead.
+//
+// Since all of the functions are nil by default, all notifications are
+// effectively ignored until their handlers are set to a concrete callback.  The
+// same restrictions regarding blocking calls as NotificationHandlers apply.
+type WalletNotificationHandlers struct {
+	// OnAccountBalance is invoked when the confirmed or unconfirmed balance
+	// of an account of the wallet changes.
+	OnAccountBalance func(account string, balance dcrutil.Amount, confirmed bool)
+
+	// OnDcrdConnected is invoked when the connection state of the wallet to
+	// its backing dcrd server changes.
+	OnDcrdConnected func(connected bool)
+
+	// OnNewTx is invoked when a transaction relevant to an account of the
+	// wallet is added to the wallet.
+	OnNewTx func(account string, details *WalletTxDetails)
+
+	// OnTicketPurchased is invoked when the wallet purchases a ticket.
+	OnTicketPurchased func(txHash *chainhash.Hash, amount dcrutil.Amount)
+
+	// OnVoteCreated is invoked when the wallet creates a vote for one of its
+	// tickets.  The sstxIn parameter is the hash of the ticket that votes.
+	OnVoteCreated func(txHash, blockHash *chainhash.Hash, height int32,
+		sstxIn *chainhash.Hash, voteBits uint16)
+
+	// OnRevocationCreated is invoked when the wallet creates a revocation
+	// for one of its tickets.  The sstxIn parameter is the hash of the
+	// ticket that is revoked.
+	OnRevocationCreated func(txHash, sstxIn *chainhash.Hash)
+
+	// OnWalletLockState is invoked when the wallet is locked or unlocked.
+	OnWalletLockState func(locked bool)
+}
+
+// handleWalletNotification examines the passed notification and invokes the
+// corresponding wallet notification handler, if any.  It returns whether or not
+// the notification is a dcrwallet notification.
+func (c *Client) handleWalletNotification(ntfn *rawNotification) bool {
+	handlers := c.ntfnHandlers.Wallet
+	if handlers == nil {
+		return false
+	}
+
+	var err error
+	switch ntfn.Method {
+	case accountBalanceNtfnMethod:
+		if handlers.OnAccountBalance == nil {
+			return true
+		}
+		var account string
+		var balance float64
+		var confirmed bool
+		err = unmarshalNtfnParams(ntfn.Params, &account, &balance, &confirmed)
+		if err != nil {
+			break
+		}
+		var amount dcrutil.Amount
+		amount, err = dcrutil.NewAmount(balance)
+		if err != nil {
+			break
+		}
+		handlers.OnAccountBalance(account, amount, confirmed)
+
+	case dcrdConnectedNtfnMethod:
+		if handlers.OnDcrdConnected == nil {
+			return true
+		}
+		var connected bool
+		err = unmarshalNtfnParams(ntfn.Params, &connected)
+		if err != nil {
+			break
+		}
+		handlers.OnDcrdConnected(connected)
+
+	case newTxNtfnMethod:
+		if handlers.OnNewTx == nil {
+			return true
+		}
+		var account string
+		var details WalletTxDetails
+		err = unmarshalNtfnParams(ntfn.Params, &account, &details)
+		if err != nil {
+			break
+		}
+		handlers.OnNewTx(account, &details)
+
+	case ticketPurchasedNtfnMethod:
+		if handlers.OnTicketPurchased == nil {
+			return true
+		}
+		var txHash string
+		var amount int64
+		err = unmarshalNtfnParams(ntfn.Params, &txHash, &amount)
+		if err != nil {
+			break
+		}
+		var hash *chainhash.Hash
+		hash, err = chainhash.NewHashFromStr(txHash)
+		if err != nil {
+			break
+		}
+		handlers.OnTicketPurchased(hash, dcrutil.Amount(amount))
+
+	case voteCreatedNtfnMethod:
+		if handlers.OnVoteCreated == nil {
+			return true
+		}
+		var txHash, blockHash, sstxIn string
+		var height int32
+		var voteBits uint16
+		err = unmarshalNtfnParams(ntfn.Params, &txHash, &blockHash, &height,
+			&sstxIn, &voteBits)
+		if err != nil {
+			break
+		}
+		var hashes []*chainhash.Hash
+		hashes, err = parseHashStrs(txHash, blockHash, sstxIn)
+		if err != nil {
+			break
+		}
+		handlers.OnVoteCreated(hashes[0], hashes[1], height, hashes[2],
+			voteBits)
+
+	case revocationCreatedNtfnMethod:
+		if handlers.OnRevocationCreated == nil {
+			return true
+		}
+		var txHash, sstxIn string
+		err = unmarshalNtfnParams(ntfn.Params, &txHash, &sstxIn)
+		if err != nil {
+			break
+		}
+		var hashes []*chainhash.Hash
+		hashes, err = parseHashStrs(txHash, sstxIn)
+		if err != nil {
+			break
+		}
+		handlers.OnRevocationCreated(hashes[0], hashes[1])
+
+	case walletLockStateNtfnMethod:
+		if handlers.OnWalletLockState == nil {
+			return true
+		}
+		var locked bool
+		err = unmarshalNtfnParams(ntfn.Params, &locked)
+		if err != nil {
+			break
+		}
+		handlers.OnWalletLockState(locked)
+
+	default:
+		return false
+	}
+
+	if err != nil {
+		log.Warnf("Received invalid %s notification: %v", ntfn.Method, err)
+	}
+	return true
+}
+
+// unmarshalNtfnParams unmarshals the passed notification parameters into the
+// passed targets in order.  An error is returned when the number of parameters
+// does not match the number of targets.
+func unmarshalNtfnParams(params []json.RawMessage, targets ...interface{}) error {
+	if len(params) != len(targets) {
+		return wrongNumParams(len(params))
+	}
+	for i, target := range targets {
+		if err := json.Unmarshal(params[i], target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseHashStrs decodes the passed hex-encoded hashes.
+func parseHashStrs(hashStrs ...string) ([]*chainhash.Hash, error) {
+	hashes := make([]*chainhash.Hash, 0, len(hashStrs))
+	for _, hashStr := range hashStrs {
+		hash, err := chainhash.NewHashFromStr(hashStr)
+		if err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, nil
+}
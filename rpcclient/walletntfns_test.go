@@ -0,0 +1,110 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil/v3"
+)
+
+// TestWalletNotifications ensures the dcrwallet notifications are parsed and
+// delivered to the wallet notification handlers while unknown notifications
+// are still delivered to the unknown notification handler.
+func TestWalletNotifications(t *testing.T) {
+	// makeNtfn returns a raw notification for the provided method with the
+	// provided parameters.
+	makeNtfn := func(method string, params ...interface{}) *rawNotification {
+		ntfn := &rawNotification{Method: method}
+		for _, param := range params {
+			rawParam, err := json.Marshal(param)
+			if err != nil {
+				t.Fatalf("unexpected marshal error: %v", err)
+			}
+			ntfn.Params = append(ntfn.Params, rawParam)
+		}
+		return ntfn
+	}
+	txHashStr := strings.Repeat("01", 32)
+	blockHashStr := strings.Repeat("02", 32)
+	ticketHashStr := strings.Repeat("03", 32)
+
+	var balance dcrutil.Amount
+	var details *WalletTxDetails
+	var ticketHash, voteBlockHash, revokedTicket *chainhash.Hash
+	var locked bool
+	var unknown []string
+	c := &Client{ntfnHandlers: &NotificationHandlers{
+		Wallet: &WalletNotificationHandlers{
+			OnAccountBalance: func(account string, amt dcrutil.Amount, confirmed bool) {
+				if account == "default" && confirmed {
+					balance = amt
+				}
+			},
+			OnNewTx: func(account string, d *WalletTxDetails) {
+				details = d
+			},
+			OnTicketPurchased: func(txHash *chainhash.Hash, amt dcrutil.Amount) {
+				ticketHash = txHash
+			},
+			OnVoteCreated: func(txHash, blockHash *chainhash.Hash, height int32,
+				sstxIn *chainhash.Hash, voteBits uint16) {
+
+				voteBlockHash = blockHash
+			},
+			OnRevocationCreated: func(txHash, sstxIn *chainhash.Hash) {
+				revokedTicket = sstxIn
+			},
+			OnWalletLockState: func(l bool) { locked = l },
+		},
+		OnUnknownNotification: func(method string, params []json.RawMessage) {
+			unknown = append(unknown, method)
+		},
+	}}
+	c.handleNotification(makeNtfn(accountBalanceNtfnMethod, "default", 1.5,
+		true))
+	c.handleNotification(makeNtfn(newTxNtfnMethod, "default",
+		map[string]interface{}{"txid": txHashStr, "amount": 2.5,
+			"category": "receive"}))
+	c.handleNotification(makeNtfn(ticketPurchasedNtfnMethod, txHashStr,
+		int64(1e8)))
+	c.handleNotification(makeNtfn(voteCreatedNtfnMethod, txHashStr,
+		blockHashStr, 432100, ticketHashStr, 1))
+	c.handleNotification(makeNtfn(revocationCreatedNtfnMethod, txHashStr,
+		ticketHashStr))
+	c.handleNotification(makeNtfn(walletLockStateNtfnMethod, true))
+	c.handleNotification(makeNtfn(dcrdConnectedNtfnMethod, true))
+	c.handleNotification(makeNtfn("customntfn"))
+
+	if balance != 150000000 {
+		t.Fatalf("unexpected account balance %v", balance)
+	}
+	if details == nil || details.TxID != txHashStr || details.Amount != 2.5 ||
+		details.Category != "receive" {
+
+		t.Fatalf("unexpected new tx details %+v", details)
+	}
+	if ticketHash == nil || ticketHash.String() != txHashStr {
+		t.Fatalf("unexpected purchased ticket %v", ticketHash)
+	}
+	if voteBlockHash == nil || voteBlockHash.String() != blockHashStr {
+		t.Fatalf("unexpected vote block hash %v", voteBlockHash)
+	}
+	if revokedTicket == nil || revokedTicket.String() != ticketHashStr {
+		t.Fatalf("unexpected revoked ticket %v", revokedTicket)
+	}
+	if !locked {
+		t.Fatal("wallet lock state handler was not invoked")
+	}
+
+	// Ensure only the notification that is neither a dcrd nor a dcrwallet
+	// notification is delivered to the unknown notification handler.
+	if len(unknown) != 1 || unknown[0] != "customntfn" {
+		t.Fatalf("unexpected unknown notifications %q", unknown)
+	}
+}
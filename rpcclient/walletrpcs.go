@@ -0,0 +1,277 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/decred/dcrd/wire"
+)
+
+// ErrWalletMethodsDisabled is an error to describe the condition where one of
+// the dcrwallet passthrough methods of the client is invoked without enabling
+// them via the WalletMethods field of the connection config.
+var ErrWalletMethodsDisabled = errors.New("wallet methods are not enabled")
+
+// WalletAccountBalance houses the balances of a single account of a wallet as
+// returned by the getbalance RPC of dcrwallet.
+type WalletAccountBalance struct {
+	AccountName             string  `json:"accountname"`
+	ImmatureCoinbaseRewards float64 `json:"immaturecoinbaserewards"`
+	ImmatureStakeGeneration float64 `json:"immaturestakegeneration"`
+	LockedByTickets         float64 `json:"lockedbytickets"`
+	Spendable               float64 `json:"spendable"`
+	Total                   float64 `json:"total"`
+	Unconfirmed             float64 `json:"unconfirmed"`
+	VotingAuthority         float64 `json:"votingauthority"`
+}
+
+// WalletBalanceResult models the data returned by the getbalance RPC of
+// dcrwallet.
+type WalletBalanceResult struct {
+	Balances                     []WalletAccountBalance `json:"balances"`
+	BlockHash                    string                 `json:"blockhash"`
+	TotalImmatureCoinbaseRewards float64                `json:"totalimmaturecoinbaserewards,omitempty"`
+	TotalImmatureStakeGeneration float64                `json:"totalimmaturestakegeneration,omitempty"`
+	TotalLockedByTickets         float64                `json:"totallockedbytickets,omitempty"`
+	TotalSpendable               float64                `json:"totalspendable,omitempty"`
+	TotalUnconfirmed             float64                `json:"totalunconfirmed,omitempty"`
+	TotalVotingAuthority         float64                `json:"totalvotingauthority,omitempty"`
+}
+
+// WalletUnspent houses a single unspent output of a wallet as returned by the
+// listunspent RPC of dcrwallet.
+type WalletUnspent struct {
+	TxID          string  `json:"txid"`
+	Vout          uint32  `json:"vout"`
+	Tree          int8    `json:"tree"`
+	TxType        int     `json:"txtype"`
+	Address       string  `json:"address,omitempty"`
+	Account       string  `json:"account"`
+	ScriptPubKey  string  `json:"scriptPubKey"`
+	RedeemScript  string  `json:"redeemScript,omitempty"`
+	Amount        float64 `json:"amount"`
+	Confirmations int64   `json:"confirmations"`
+	Spendable     bool    `json:"spendable"`
+}
+
+// walletRequestAsync issues a request for the provided dcrwallet method with
+// the passed parameters, which are marshalled as JSON, when the wallet methods
+// are enabled.
+func (c *Client) walletRequestAsync(ctx context.Context, method string, params ...interface{}) *cmdRes {
+	if !c.config.WalletMethods {
+		return newFutureError(ctx, ErrWalletMethodsDisabled)
+	}
+
+	rawParams := make([]json.RawMessage, 0, len(params))
+	for _, param := range params {
+		rawParam, err := json.Marshal(param)
+		if err != nil {
+			return newFutureError(ctx, err)
+		}
+		rawParams = append(rawParams, rawParam)
+	}
+	return (*cmdRes)(c.RawRequestAsync(ctx, method, rawParams))
+}
+
+// FutureGetBalanceResult is a future promise to deliver the result of a
+// GetBalanceAsync RPC invocation (or an applicable error).
+type FutureGetBalanceResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// balances of the wallet.
+func (r *FutureGetBalanceResult) Receive() (*WalletBalanceResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getbalance result object.
+	var result WalletBalanceResult
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetBalanceAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See GetBalance for the blocking version and more details.
+func (c *Client) GetBalanceAsync(ctx context.Context, account string, minConf int) *FutureGetBalanceResult {
+	return (*FutureGetBalanceResult)(c.walletRequestAsync(ctx, "getbalance",
+		account, minConf))
+}
+
+// GetBalance returns the balances of the passed account of the wallet, or of
+// all accounts when the account is "*", counting transactions with at least
+// minConf confirmations as confirmed.
+//
+// NOTE: This is a dcrwallet method and requires the WalletMethods field of the
+// connection config to be set.
+func (c *Client) GetBalance(ctx context.Context, account string, minConf int) (*WalletBalanceResult, error) {
+	return c.GetBalanceAsync(ctx, account, minConf).Receive()
+}
+
+// FutureListUnspentResult is a future promise to deliver the result of a
+// ListUnspentAsync RPC invocation (or an applicable error).
+type FutureListUnspentResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// unspent outputs of the wallet.
+func (r *FutureListUnspentResult) Receive() ([]WalletUnspent, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as an array of listunspent result objects.
+	var result []WalletUnspent
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ListUnspentAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See ListUnspent for the blocking version and more details.
+func (c *Client) ListUnspentAsync(ctx context.Context, minConf, maxConf int, addresses []dcrutil.Address) *FutureListUnspentResult {
+	addrs := make([]string, 0, len(addresses))
+	for _, addr := range addresses {
+		addrs = append(addrs, addr.Address())
+	}
+	return (*FutureListUnspentResult)(c.walletRequestAsync(ctx, "listunspent",
+		minConf, maxConf, addrs))
+}
+
+// ListUnspent returns the unspent outputs of the wallet with between minConf
+// and maxConf confirmations, inclusive.  Only outputs that pay to one of the
+// passed addresses are returned unless no addresses are provided.
+//
+// NOTE: This is a dcrwallet method and requires the WalletMethods field of the
+// connection config to be set.
+func (c *Client) ListUnspent(ctx context.Context, minConf, maxConf int, addresses []dcrutil.Address) ([]WalletUnspent, error) {
+	return c.ListUnspentAsync(ctx, minConf, maxConf, addresses).Receive()
+}
+
+// FutureSendToAddressResult is a future promise to deliver the result of a
+// SendToAddressAsync RPC invocation (or an applicable error).
+type FutureSendToAddressResult cmdRes
+
+// Receive waits for the response promised by the future and returns the hash
+// of the transaction that sends the funds.
+func (r *FutureSendToAddressResult) Receive() (*chainhash.Hash, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a string.
+	var txHashStr string
+	err = json.Unmarshal(res, &txHashStr)
+	if err != nil {
+		return nil, err
+	}
+	return chainhash.NewHashFromStr(txHashStr)
+}
+
+// SendToAddressAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See SendToAddress for the blocking version and more details.
+func (c *Client) SendToAddressAsync(ctx context.Context, address dcrutil.Address, amount dcrutil.Amount) *FutureSendToAddressResult {
+	return (*FutureSendToAddressResult)(c.walletRequestAsync(ctx,
+		"sendtoaddress", address.Address(), amount.ToCoin()))
+}
+
+// SendToAddress instructs the wallet to send the passed amount to the passed
+// address and returns the hash of the transaction.  The wallet must be
+// unlocked.
+//
+// NOTE: This is a dcrwallet method and requires the WalletMethods field of the
+// connection config to be set.
+func (c *Client) SendToAddress(ctx context.Context, address dcrutil.Address, amount dcrutil.Amount) (*chainhash.Hash, error) {
+	return c.SendToAddressAsync(ctx, address, amount).Receive()
+}
+
+// FutureSignRawTransactionResult is a future promise to deliver the result of a
+// SignRawTransactionAsync RPC invocation (or an applicable error).
+type FutureSignRawTransactionResult cmdRes
+
+// Receive waits for the response promised by the future and returns the signed
+// transaction along with whether or not all of its inputs are signed.
+func (r *FutureSignRawTransactionResult) Receive() (*wire.MsgTx, bool, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Unmarshal result as a signrawtransaction result object.
+	var result struct {
+		Hex      string `json:"hex"`
+		Complete bool   `json:"complete"`
+	}
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Decode the serialized transaction hex to raw bytes.
+	serializedTx, err := hex.DecodeString(result.Hex)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Deserialize the transaction and return it.
+	var msgTx wire.MsgTx
+	if err := msgTx.Deserialize(bytes.NewReader(serializedTx)); err != nil {
+		return nil, false, err
+	}
+	return &msgTx, result.Complete, nil
+}
+
+// SignRawTransactionAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See SignRawTransaction for the blocking version and more details.
+func (c *Client) SignRawTransactionAsync(ctx context.Context, tx *wire.MsgTx) *FutureSignRawTransactionResult {
+	txHex := ""
+	if tx != nil {
+		// Serialize the transaction and convert to hex string.
+		buf := bytes.NewBuffer(make([]byte, 0, tx.SerializeSize()))
+		if err := tx.Serialize(buf); err != nil {
+			return (*FutureSignRawTransactionResult)(newFutureError(ctx, err))
+		}
+		txHex = hex.EncodeToString(buf.Bytes())
+	}
+
+	return (*FutureSignRawTransactionResult)(c.walletRequestAsync(ctx,
+		"signrawtransaction", txHex))
+}
+
+// SignRawTransaction instructs the wallet to sign the inputs of the passed
+// transaction that spend outputs it has the keys for and returns the signed
+// transaction along with whether or not all of its inputs are signed.  The
+// wallet must be unlocked.
+//
+// NOTE: This is a dcrwallet method and requires the WalletMethods field of the
+// connection config to be set.
+func (c *Client) SignRawTransaction(ctx context.Context, tx *wire.MsgTx) (*wire.MsgTx, bool, error) {
+	return c.SignRawTransactionAsync(ctx, tx).Receive()
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/decred/dcrd/wire"
+)
+
+// testAddress provides a mock address by implementing the dcrutil.Address
+// interface.
+type testAddress string
+
+func (a testAddress) String() string        { return string(a) }
+func (a testAddress) Address() string       { return string(a) }
+func (a testAddress) ScriptAddress() []byte { return nil }
+func (a testAddress) Hash160() *[20]byte    { return nil }
+
+// TestWalletMethods ensures the dcrwallet passthrough methods send the expected
+// requests and parse the results, and that they fail when they are not enabled.
+func TestWalletMethods(t *testing.T) {
+	tx := wire.NewMsgTx()
+	tx.AddTxOut(wire.NewTxOut(5000, []byte{0x51}))
+	txHex, err := tx.Bytes()
+	if err != nil {
+		t.Fatalf("unable to serialize transaction: %v", err)
+	}
+	txHashStr := strings.Repeat("01", 32)
+
+	// Create a server that replies to the wallet methods with canned results
+	// and records the parameters of the requests.
+	var gotParams []json.RawMessage
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		gotParams = req.Params
+
+		var result string
+		switch req.Method {
+		case "getbalance":
+			result = `{"balances":[{"accountname":"default",` +
+				`"spendable":1.5,"total":2}],"blockhash":"00",` +
+				`"totalspendable":1.5}`
+		case "listunspent":
+			result = `[{"txid":"` + txHashStr + `","vout":1,"tree":0,` +
+				`"txtype":0,"account":"default","scriptPubKey":"51",` +
+				`"amount":0.5,"confirmations":3,"spendable":true}]`
+		case "sendtoaddress":
+			result = `"` + txHashStr + `"`
+		case "signrawtransaction":
+			result = fmt.Sprintf(`{"hex":"%x","complete":true}`, txHex)
+		}
+		fmt.Fprintf(w, `{"result":%s,"error":null,"id":1}`, result)
+	}
+	checkParams := func(method string, want string) {
+		t.Helper()
+		got, _ := json.Marshal(gotParams)
+		if string(got) != want {
+			t.Fatalf("%s: unexpected params -- got %s, want %s", method,
+				got, want)
+		}
+	}
+
+	// Ensure the wallet methods fail when they are not enabled.
+	ctx := context.Background()
+	server, c := newTestHTTPClient(t, handler, nil)
+	defer server.Close()
+	defer c.Shutdown()
+	if _, err := c.GetBalance(ctx, "*", 1); !errors.Is(err, ErrWalletMethodsDisabled) {
+		t.Fatalf("unexpected error with wallet methods disabled: %v", err)
+	}
+
+	walletServer, c := newTestHTTPClient(t, handler,
+		&ConnConfig{WalletMethods: true})
+	defer walletServer.Close()
+	defer c.Shutdown()
+	balance, err := c.GetBalance(ctx, "default", 2)
+	if err != nil {
+		t.Fatalf("getbalance: unexpected error: %v", err)
+	}
+	checkParams("getbalance", `["default",2]`)
+	wantBalance := &WalletBalanceResult{
+		Balances: []WalletAccountBalance{{
+			AccountName: "default",
+			Spendable:   1.5,
+			Total:       2,
+		}},
+		BlockHash:      "00",
+		TotalSpendable: 1.5,
+	}
+	if !reflect.DeepEqual(balance, wantBalance) {
+		t.Fatalf("getbalance: unexpected result -- got %+v, want %+v",
+			balance, wantBalance)
+	}
+
+	addr := testAddress("DsExampleAddress")
+	unspent, err := c.ListUnspent(ctx, 1, 100, []dcrutil.Address{addr})
+	if err != nil {
+		t.Fatalf("listunspent: unexpected error: %v", err)
+	}
+	checkParams("listunspent", `[1,100,["DsExampleAddress"]]`)
+	if len(unspent) != 1 || unspent[0].TxID != txHashStr ||
+		unspent[0].Vout != 1 || unspent[0].Amount != 0.5 ||
+		!unspent[0].Spendable {
+
+		t.Fatalf("listunspent: unexpected result: %+v", unspent)
+	}
+
+	txHash, err := c.SendToAddress(ctx, addr, 150000000)
+	if err != nil {
+		t.Fatalf("sendtoaddress: unexpected error: %v", err)
+	}
+	checkParams("sendtoaddress", `["DsExampleAddress",1.5]`)
+	if txHash.String() != txHashStr {
+		t.Fatalf("sendtoaddress: unexpected hash -- got %v, want %v",
+			txHash, txHashStr)
+	}
+
+	signedTx, complete, err := c.SignRawTransaction(ctx, tx)
+	if err != nil {
+		t.Fatalf("signrawtransaction: unexpected error: %v", err)
+	}
+	checkParams("signrawtransaction", fmt.Sprintf(`["%x"]`, txHex))
+	if !complete || signedTx.TxHash() != tx.TxHash() {
+		t.Fatalf("signrawtransaction: unexpected result -- got %v "+
+			"(complete %v), want %v", signedTx.TxHash(), complete,
+			tx.TxHash())
+	}
+}
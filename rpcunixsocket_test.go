@@ -0,0 +1,60 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestListenUnixSocket ensures the RPC unix socket is only accessible by the
+// owner, replaces stale socket files, and does not replace other files.
+func TestListenUnixSocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rpcunixsocket")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Leave a stale socket file behind by listening without removing the
+	// socket on close.
+	path := filepath.Join(dir, "rpc.sock")
+	stale, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	listener, err := listenUnixSocket(path)
+	if err != nil {
+		t.Fatalf("unable to listen on stale socket: %v", err)
+	}
+	defer listener.Close()
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("unable to stat socket: %v", err)
+	}
+	if perm := fi.Mode().Perm(); perm != 0600 {
+		t.Fatalf("unexpected socket permissions -- got %o, want 600", perm)
+	}
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("unable to connect to socket: %v", err)
+	}
+	conn.Close()
+
+	// Ensure files that are not sockets are not replaced.
+	filePath := filepath.Join(dir, "file")
+	if err := ioutil.WriteFile(filePath, nil, 0600); err != nil {
+		t.Fatalf("unable to write file: %v", err)
+	}
+	if _, err := listenUnixSocket(filePath); err == nil {
+		t.Fatal("expected error listening on a path that is not a socket")
+	}
+}
@@ -33,6 +33,13 @@ const fileContents = `[Application Options]
 ; Use simnet.
 ; simnet=1
 
+; Override the number of blocks before newly mined coins can be spent and the
+; number of blocks before newly purchased tickets are eligible to vote.  This
+; allows funds to become spendable more quickly in test environments and is
+; only valid with the regnet and simnet options.
+; coinbasematurity=2
+; ticketmaturity=2
+
 ; Change how long to wait for TCP connection completion.  Valid time units are
 ; {s, m, h}.  Minimum 1 second".
 ; dialtimeout=30s
@@ -67,6 +74,15 @@ const fileContents = `[Application Options]
 ; will have no effect if external IP addresses are specified.
 ; upnp=1
 
+; Discover other nodes running on the same network on the local network via
+; multicast DNS (mDNS/DNS-SD) and automatically connect to them.  This is useful
+; for lab and home cluster setups.  The node is also advertised to other nodes
+; on the local network when it is listening for connections.  This option is
+; ignored when the 'connect' option is specified and it is not allowed on
+; mainnet unless 'allowmdnsmainnet' is also specified.
+; mdns=1
+; allowmdnsmainnet=1
+
 ; Specify the external IP addresses your node is listening on.  One address per
 ; line.  dcrd will not contact 3rd-party sites to obtain external ip addresses.
 ; This means if you are behind NAT, your node will not be able to advertise a
@@ -119,6 +135,19 @@ const fileContents = `[Application Options]
 ; Maximum number of inbound and outbound peers.
 ; maxpeers=8
 
+; Only connect to peers over IPv6.
+; ipv6only=1
+
+; Peers that resolve to both IPv4 and IPv6 addresses are connected to by racing
+; the addresses of both families.  IPv6 is attempted first by default.  Prefer
+; IPv4 instead.
+; preferipv4=1
+
+; Minimum number of automatic outbound peers to maintain over each of IPv4 and
+; IPv6.  The combined targets may not exceed the number of outbound peers.
+; targetoutboundipv4=2
+; targetoutboundipv6=2
+
 ; Disable banning of misbehaving peers.
 ; nobanning=1
 
@@ -137,6 +166,44 @@ const fileContents = `[Application Options]
 ; whitelist=192.168.0.0/24
 ; whitelist=fd00::/16
 
+; Authenticate to peers that also support it with a long-term identity key.  The
+; key is stored in the peeridentity.key file in the data directory and is created
+; if it does not exist.  The public key is logged at startup.
+; peeridentity=1
+
+; Only allow connections with peers that authenticate with one of the specified
+; hex-encoded identity public keys.  This is intended for private networks of
+; known nodes, such as relay backbones, and is typically combined with the
+; connect option.  Note that connections are authenticated, but NOT encrypted.
+; Implies peeridentity=1.
+; allowpeerkey=02...
+; allowpeerkey=03...
+
+; Limit the payload size and optionally the rate of specific types of messages
+; received from peers in the form <command>:<maxbytes>[:<maxrate>[:<burst>]].
+; The rate is the sustained number of messages allowed per second and the burst
+; is the number of messages allowed in quick succession.  A max bytes value of 0
+; only enforces the limit imposed by the protocol.  Peers that exceed any of the
+; limits are disconnected.  The number of messages received and rejected per
+; type is reported by the getpeerinfo RPC.  Only valid with the regnet and
+; simnet options.
+; msglimit=tx:100000:50:100
+; msglimit=getaddr:0:0.01:2
+
+; Maximum rate in KiB/s at which blocks more than 288 blocks deep in the chain
+; are served to each peer that is not whitelisted.  Recently served deep blocks
+; are cached and only a limited number of them are loaded from disk at once, so
+; syncing peers and crawlers can't saturate disk I/O.  Set to 0 to disable the
+; rate limit.
+; ancientblockrate=4096
+
+; Record anonymized peer connection lifecycle, address relay, and inventory
+; announcement events in memory so they can be exported for network health
+; research with the dumppeertelemetry RPC.  Remote addresses are replaced with
+; identifiers derived from a random key that changes each time dcrd starts.
+; The events are written to the peertelemetry directory in the data directory.
+; peertelemetry=1
+
 ; Disable DNS seeding for peers.  By default, when dcrd starts, it will use
 ; DNS to query for available peers to connect with.
 ; nodnsseed=1
@@ -223,6 +290,27 @@ const fileContents = `[Application Options]
 ; Specify the maximum number of concurrent RPC websocket clients.
 ; rpcmaxwebsockets=25
 
+; Append a record of every state-changing RPC, such as sendrawtransaction,
+; setgenerate, and node, to the specified audit log file.  Each record is a
+; JSON object on its own line that includes the time, the RPC username, the
+; address of the client, the parameters, and the result or error.  Requests
+; from limited users for methods they are not allowed to call are also recorded.
+; rpcauditlog=~/.dcrd/rpcaudit.log
+
+; Form a redundancy pair with another dcrd instance that shares a virtual IP
+; address with this one for hot-standby deployments.  The instances agree on a
+; leader by exchanging heartbeats over their RPC link and only the leader serves
+; miners (getwork, getblocktemplate, submitblock, etc.) and websocket clients.
+; The standby rejects them so clients of the virtual IP are always served by the
+; leader, and it takes over once it has not heard from the leader for the
+; duration of the lease.  Both instances must use the same rpcuser and rpcpass
+; and have distinct node identifiers, which default to the hostname.  The
+; certificate of the peer's RPC server defaults to rpccert.
+; coordpeer=10.0.0.2:9109
+; coordnodeid=dcrd-a
+; coordcert=~/.dcrd/peer-rpc.cert
+; coordlease=15s
+
 ; Use the following setting to disable the RPC server even if the rpcuser and
 ; rpcpass are specified above.  This allows one to quickly disable the RPC
 ; server without having to remove credentials from the config file.
@@ -256,6 +344,24 @@ const fileContents = `[Application Options]
 ; Do not accept transactions from remote peers.
 ; blocksonly=1
 
+; Do not advertise the full node network service or serve and announce full
+; blocks to peers that are not whitelisted while still serving block headers and
+; compact filters to SPV clients.  Note that full blocks are still downloaded and
+; the full UTXO set is still maintained in order to validate them, so this only
+; reduces the bandwidth used to serve peers.
+; noserveblocks=1
+
+; Announce transactions to peers that support it via set reconciliation instead
+; of flooding them.  This significantly reduces the bandwidth used for
+; transaction announcements.
+; txrecon=1
+
+; Serve BIP37-style bloom filtered blocks and transactions to legacy SPV clients
+; that do not support version 2 compact filters.  Serving bloom filters is
+; costly since every transaction relayed to a client and every block it requests
+; must be matched against its filter, so it is disabled by default.
+; bloomfilters=1
+
 ; Accept and relay non-standard transactions to the network regardless of the
 ; default network settings.
 ; acceptnonstd=1
@@ -263,6 +369,40 @@ const fileContents = `[Application Options]
 ; Reject non-standard transactions regardless of default network settings.
 ; rejectnonstd=1
 
+; Override the relay policy of specific types of transactions in the form
+; <type>:<policy> regardless of the acceptnonstd and rejectnonstd settings and
+; of any deployments.  The type is one of regular, ticket, vote, or revocation.
+; The policy is std to only accept and relay standard transactions of the type,
+; nonstd to also accept and relay non-standard ones, or reject to neither accept
+; nor relay any of them.  This is useful for testing upgrades before they
+; activate.  Only valid with the regnet and simnet options.
+; txtypepolicy=ticket:reject
+; txtypepolicy=vote:nonstd
+
+; Evict regular transactions and ticket purchases that have been in the mempool
+; longer than the given duration regardless of their expiry.  Valid time units
+; are {s, m, h}.  Minimum 1 minute.  The default of 0 disables time-based
+; eviction.
+; mempoolttl=72h
+
+; Allow regular transactions in the mempool to be replaced by conflicting
+; regular transactions that pay sufficiently higher fees.  Replacements must pay
+; a fee rate that exceeds the fee rate of each transaction they replace by at
+; least the given percentage along with a fee that covers the fees of all of the
+; transactions they evict plus the minimum relay fee.
+; txreplacement=1
+; replacementfeeincrease=10
+
+; Accept transactions relayed by peers from the given IP networks and IPs or
+; submitted via sendrawtransaction by the given RPC users regardless of the
+; minimum relay fee.  This is useful for relaying low-fee transactions, such as
+; consolidations, through trusted nodes.  The transactions must still satisfy
+; all other policy and consensus rules, and other nodes apply their own minimum
+; relay fee.  The RPC user must be the rpcuser or rpclimituser.
+; feeexemptpeer=192.168.0.0/24
+; feeexemptpeer=fd00::/16
+; feeexemptrpcuser=whatever_username_you_want
+
 
 ; ------------------------------------------------------------------------------
 ; Optional Transaction Indexes
@@ -285,6 +425,18 @@ const fileContents = `[Application Options]
 ; addrindex=1
 
 
+; ------------------------------------------------------------------------------
+; Block Validation
+; ------------------------------------------------------------------------------
+
+; Skip script validation for the specified block and its ancestors during the
+; initial chain sync.  All other checks are still enforced.  This greatly
+; reduces the time it takes to sync the chain since script validation is the
+; most expensive part of block validation.  A block built into the software for
+; the active network is used by default.  Set it to 0 to validate all scripts.
+; assumevalid=0
+
+
 ; ------------------------------------------------------------------------------
 ; Signature Verification Cache
 ; ------------------------------------------------------------------------------
@@ -292,6 +444,82 @@ const fileContents = `[Application Options]
 ; Limit the signature cache to a max of 50000 entries.
 ; sigcachemaxsize=50000
 
+; Share the signature cache with other dcrd-derived processes on this host, such
+; as a mining proxy, so signatures verified by one of them are not verified
+; again by the others.  The cache is shared via a memory segment backed by the
+; specified file, which should be in a memory-backed file system.  All of the
+; processes must be configured with the same file and run as the user that owns
+; it since an existing file that is owned by another user or writable by other
+; users is refused.  This is only supported on unix platforms.
+; sharedsigcache=/dev/shm/dcrd-sigcache
+
+
+; ------------------------------------------------------------------------------
+; Disk Space Monitoring
+; ------------------------------------------------------------------------------
+
+; Warn when the free disk space available to the data directory falls below
+; 2048 MiB.  A diskspace notification is also sent to RPC websocket clients that
+; registered for them via notifydiskspace.  Set to 0 to disable.
+; diskspacewarn=2048
+
+; Gracefully shut down when the free disk space available to the data directory
+; falls below 512 MiB so the database is not left mid-write when the disk fills
+; up.  Set to 0 to disable.
+; diskspacestop=512
+
+
+; ------------------------------------------------------------------------------
+; Critical Event Alerts
+; ------------------------------------------------------------------------------
+
+; Record the specified kinds of critical events to a durable outbox named
+; alertoutbox.json in the data directory so the alerts are not lost when no RPC
+; websocket client is attached.  May be specified multiple times.  The kinds
+; are:
+;   reorg            - a chain reorganization disconnected at least
+;                      alertreorgdepth blocks
+;   ticketexhaustion - the live ticket pool fell below half of its target size
+;   corruption       - the stored data for a block was detected to be corrupted
+; alertevent=reorg
+; alertevent=ticketexhaustion
+; alertevent=corruption
+
+; The minimum number of blocks a chain reorganization must disconnect to record
+; a reorg alert.
+; alertreorgdepth=6
+
+; POST the recorded alerts as JSON to the specified HTTP(S) URL.  Deliveries
+; that fail are retried with an exponential backoff until they are accepted,
+; including across restarts.  May be specified multiple times.
+; alertwebhook=https://alerts.example.com/dcrd
+
+
+; ------------------------------------------------------------------------------
+; Webhook Notifications
+; ------------------------------------------------------------------------------
+
+; POST events of the specified category as JSON to the specified HTTP(S) URL in
+; the form category=url.  Deliveries that fail are retried with an exponential
+; backoff up to 8 times.  May be specified multiple times.  The categories are:
+;   block   - a block was connected to the main chain while the chain is current
+;   tx      - a transaction that pays to a webhooktxaddr address was accepted to
+;             the mempool or connected to the main chain in a block
+;   peerban - a peer was banned
+; webhook=block=https://hooks.example.com/dcrd/block
+; webhook=tx=https://hooks.example.com/dcrd/tx
+; webhook=peerban=https://hooks.example.com/dcrd/peerban
+
+; Sign each webhook payload with HMAC-SHA256 keyed by the specified secret.  The
+; hex-encoded signature is sent in the X-Dcrd-Signature header and the event
+; category in the X-Dcrd-Event header.
+; webhooksecret=
+
+; Deliver tx events for transactions that pay to the specified address.  At
+; least one address is required for tx webhooks.  May be specified multiple
+; times.
+; webhooktxaddr=
+
 
 ; ------------------------------------------------------------------------------
 ; Coin Generation (Mining) Settings - The following options control the
@@ -312,6 +540,13 @@ const fileContents = `[Application Options]
 ; miningaddr=youraddress2
 ; miningaddr=youraddress3
 
+; Policy used to choose which of the mining addresses each generated block
+; template pays to.  The addresses and policy may also be changed at runtime via
+; the setminingaddrs RPC.  The policies are:
+;   random     - choose one of the addresses at random for each template
+;   roundrobin - cycle through the addresses in the order they are specified
+; miningaddrpolicy=random
+
 ; Specify the minimum block size in bytes to create.  By default, only
 ; transactions which have enough fees or a high enough priority will be included
 ; in generated block templates.  Specifying a minimum block size will instead
@@ -11,6 +11,7 @@ import (
 	"crypto/rand"
 	"crypto/tls"
 	"encoding/binary"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -35,14 +36,17 @@ import (
 	"github.com/decred/dcrd/chaincfg/v3"
 	"github.com/decred/dcrd/connmgr/v3"
 	"github.com/decred/dcrd/database/v2"
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
 	"github.com/decred/dcrd/dcrutil/v3"
 	"github.com/decred/dcrd/gcs/v2"
 	"github.com/decred/dcrd/gcs/v2/blockcf"
+	"github.com/decred/dcrd/internal/bloom"
 	"github.com/decred/dcrd/internal/fees"
 	"github.com/decred/dcrd/internal/mempool"
 	"github.com/decred/dcrd/internal/mining"
 	"github.com/decred/dcrd/internal/mining/cpuminer"
 	"github.com/decred/dcrd/internal/rpcserver"
+	"github.com/decred/dcrd/internal/txrecon"
 	"github.com/decred/dcrd/internal/version"
 	"github.com/decred/dcrd/lru"
 	"github.com/decred/dcrd/peer/v2"
@@ -73,7 +77,7 @@ const (
 	connectionRetryInterval = time.Second * 5
 
 	// maxProtocolVersion is the max protocol version the server supports.
-	maxProtocolVersion = wire.CFilterV2Version
+	maxProtocolVersion = wire.BloomFilterVersion
 
 	// maxKnownAddrsPerPeer is the maximum number of items to keep in the
 	// per-peer known address cache.
@@ -82,6 +86,15 @@ const (
 	// maxCachedNaSubmissions is the maximum number of network address
 	// submissions cached.
 	maxCachedNaSubmissions = 20
+
+	// txReconInterval is the interval at which transaction inventory
+	// reconciliation rounds are started with outbound peers that support
+	// it.
+	txReconInterval = time.Second * 2
+
+	// peerIdentityKeyFilename is the name of the file in the data directory
+	// that houses the long-term peer identity key.
+	peerIdentityKeyFilename = "peeridentity.key"
 )
 
 var (
@@ -466,20 +479,34 @@ type server struct {
 	db                   database.DB
 	timeSource           blockchain.MedianTimeSource
 	services             wire.ServiceFlag
+	identityKey          *secp256k1.PrivateKey
+	rpcAuditLog          *os.File
+	txReconMetrics       txrecon.Metrics
+	diskSpaceMonitor     *diskSpaceMonitor
+	peerTelemetry        *peerTelemetry
+	alertOutbox          *alertOutbox
+	webhooks             *webhookManager
+	mdns                 *mdnsDiscovery
+	ancientBlocks        *ancientBlockServer
+	coordinator          *coordinator
 
 	// The following fields are used for optional indexes.  They will be nil
 	// if the associated index is not enabled.  These fields are set during
 	// initial creation of the server and never changed afterwards, so they
 	// do not need to be protected for concurrent access.
-	txIndex         *indexers.TxIndex
-	addrIndex       *indexers.AddrIndex
-	existsAddrIndex *indexers.ExistsAddrIndex
-	cfIndex         *indexers.CFIndex
+	txIndex           *indexers.TxIndex
+	addrIndex         *indexers.AddrIndex
+	existsAddrIndex   *indexers.ExistsAddrIndex
+	addrActivityIndex *indexers.AddrActivityIndex
+	cfIndex           *indexers.CFIndex
 }
 
 // serverPeer extends the peer to maintain state shared by the server and
 // the blockmanager.
 type serverPeer struct {
+	// The following variables must only be used atomically.
+	feeFilter int64
+
 	*peer.Peer
 
 	connReq        *connmgr.ConnReq
@@ -489,6 +516,8 @@ type serverPeer struct {
 	relayMtx       sync.Mutex
 	disableRelayTx bool
 	isWhitelisted  bool
+	blocksWithheld bool
+	isFeeExempt    bool
 	knownAddresses lru.Cache
 	banScore       connmgr.DynamicBanScore
 	quit           chan struct{}
@@ -500,12 +529,39 @@ type serverPeer struct {
 	getMiningStateSent bool
 
 	// The following chans are used to sync blockmanager and server.
-	txProcessed    chan struct{}
+	txProcessed    chan txAcceptOutcome
 	blockProcessed chan struct{}
 
+	// txBudget tracks the transaction acceptance budgets of the peer.  It is
+	// only accessed by the peer's input handler.
+	txBudget txAcceptanceBudget
+
+	// ancientThrottle limits the rate at which ancient blocks are served to
+	// the peer.  It is only accessed by the peer's input handler.
+	ancientThrottle ancientBlockThrottle
+
+	// filter is the bloom filter loaded by the peer, if any, which limits
+	// the transactions relayed to it.  bloomBudget tracks the bloom filter
+	// update budget of the peer and is only accessed by the peer's input
+	// handler.
+	filter      *bloom.Filter
+	bloomBudget bloomFilterBudget
+
 	// peerNa is network address of the peer connected to.
 	peerNa    *wire.NetAddress
 	peerNaMtx sync.Mutex
+
+	// txRecon is set when transactions are announced to the peer via set
+	// reconciliation instead of flooding.  It is set during version
+	// negotiation and never changed afterwards, so it does not need to be
+	// protected for concurrent access.
+	txRecon *txrecon.Reconciler
+
+	// tcpInfo houses the transport statistics most recently sampled from
+	// the TCP socket of the connection.  It is nil when sampling is not
+	// supported.
+	tcpInfo    *rpcserver.PeerTCPInfo
+	tcpInfoMtx sync.Mutex
 }
 
 // newServerPeer returns a new serverPeer instance. The peer needs to be set by
@@ -515,8 +571,9 @@ func newServerPeer(s *server, isPersistent bool) *serverPeer {
 		server:         s,
 		persistent:     isPersistent,
 		knownAddresses: lru.NewCache(maxKnownAddrsPerPeer),
+		filter:         bloom.LoadFilter(nil),
 		quit:           make(chan struct{}),
-		txProcessed:    make(chan struct{}, 1),
+		txProcessed:    make(chan txAcceptOutcome, 1),
 		blockProcessed: make(chan struct{}, 1),
 	}
 }
@@ -560,6 +617,21 @@ func (sp *serverPeer) relayTxDisabled() bool {
 	return isDisabled
 }
 
+// setFeeFilter sets the minimum transaction fee rate, in atoms/kB, below which
+// transactions are not announced to the peer.
+// It is safe for concurrent access.
+func (sp *serverPeer) setFeeFilter(feePerKB int64) {
+	atomic.StoreInt64(&sp.feeFilter, feePerKB)
+}
+
+// feeFilterRate returns the minimum transaction fee rate, in atoms/kB, the peer
+// has requested for transaction announcements.  A value of zero indicates the
+// peer has not requested any filtering.
+// It is safe for concurrent access.
+func (sp *serverPeer) feeFilterRate() int64 {
+	return atomic.LoadInt64(&sp.feeFilter)
+}
+
 // pushAddrMsg sends an addr message to the connected peer using the provided
 // addresses.
 func (sp *serverPeer) pushAddrMsg(addresses []*wire.NetAddress) {
@@ -577,6 +649,9 @@ func (sp *serverPeer) pushAddrMsg(addresses []*wire.NetAddress) {
 		return
 	}
 	sp.addKnownAddresses(known)
+	if sp.server.peerTelemetry != nil && len(known) > 0 {
+		sp.server.peerTelemetry.AddrRelayed(sp, telemetryDirSent, known)
+	}
 }
 
 // addBanScore increases the persistent and decaying ban score fields by the
@@ -626,6 +701,29 @@ func hasServices(advertised, desired wire.ServiceFlag) bool {
 	return advertised&desired == desired
 }
 
+// authorizePeer is invoked once a peer has completed authentication during
+// protocol negotiation to determine if it is allowed to connect.  Peers are
+// only restricted when allowed identity keys are configured, in which case
+// they must have authenticated with one of them.
+func (sp *serverPeer) authorizePeer(p *peer.Peer, identityKey *secp256k1.PublicKey) bool {
+	if len(cfg.allowPeerKeys) == 0 {
+		return true
+	}
+	if identityKey == nil {
+		srvrLog.Debugf("Rejecting peer %v that did not authenticate", p)
+		return false
+	}
+
+	var key [secp256k1.PubKeyBytesLenCompressed]byte
+	copy(key[:], identityKey.SerializeCompressed())
+	if _, ok := cfg.allowPeerKeys[key]; !ok {
+		srvrLog.Debugf("Rejecting peer %v with identity key %x that is not "+
+			"allowed", p, key)
+		return false
+	}
+	return true
+}
+
 // OnVersion is invoked when a peer receives a version wire message and is used
 // to negotiate the protocol version details as well as kick start the
 // communications.
@@ -701,6 +799,27 @@ func (sp *serverPeer) OnVersion(p *peer.Peer, msg *wire.MsgVersion) *wire.MsgRej
 	// Choose whether or not to relay transactions.
 	sp.setDisableRelayTx(msg.DisableRelayTx)
 
+	// Announce transactions to the peer via set reconciliation when both
+	// sides support it.  Rounds are always started by the outbound side.
+	if cfg.TxRecon && !cfg.BlocksOnly && !msg.DisableRelayTx &&
+		sp.ProtocolVersion() >= wire.TxReconVersion &&
+		hasServices(msg.Services, wire.SFNodeTxRecon) {
+
+		sp.txRecon = txrecon.NewReconciler(!isInbound,
+			&sp.server.txReconMetrics)
+		if !isInbound {
+			go sp.txReconHandler()
+		}
+	}
+
+	// Advertise the minimum transaction relay fee to peers that support it
+	// so they do not announce transactions that would be rejected anyway.
+	if !cfg.BlocksOnly && cfg.minRelayTxFee > 0 &&
+		sp.ProtocolVersion() >= wire.FeeFilterVersion {
+		feeFilter := wire.NewMsgFeeFilter(int64(cfg.minRelayTxFee))
+		p.QueueMessage(feeFilter, nil)
+	}
+
 	// Add the remote peer time as a sample for creating an offset against
 	// the local clock to keep the network time in sync.
 	sp.server.timeSource.AddTimeSample(p.Addr(), msg.Timestamp)
@@ -708,11 +827,135 @@ func (sp *serverPeer) OnVersion(p *peer.Peer, msg *wire.MsgVersion) *wire.MsgRej
 	// Signal the block manager this peer is a new sync candidate.
 	sp.server.blockManager.NewPeer(sp.Peer)
 
+	// Record the connection for the peer telemetry when enabled.
+	if sp.server.peerTelemetry != nil {
+		sp.server.peerTelemetry.Connected(sp)
+	}
+
 	// Add valid peer to the server.
 	sp.server.AddPeer(sp)
 	return nil
 }
 
+// OnFeeFilter is invoked when a peer receives a feefilter wire message.  It
+// records the minimum fee rate the peer is interested in so that transactions
+// paying less are not announced to it.
+func (sp *serverPeer) OnFeeFilter(p *peer.Peer, msg *wire.MsgFeeFilter) {
+	// Disconnect peers that send a fee rate that is not a valid amount.
+	if msg.MinFee < 0 || msg.MinFee > dcrutil.MaxAmount {
+		peerLog.Debugf("Peer %v sent an invalid feefilter '%v' -- "+
+			"disconnecting", sp, dcrutil.Amount(msg.MinFee))
+		sp.Disconnect()
+		return
+	}
+
+	sp.setFeeFilter(msg.MinFee)
+}
+
+// queueTxInventory queues inventory vectors for the passed transactions to be
+// announced to the peer with the next batch.
+func (sp *serverPeer) queueTxInventory(hashes []chainhash.Hash) {
+	for i := range hashes {
+		sp.QueueInventory(wire.NewInvVect(wire.InvTypeTx, &hashes[i]))
+	}
+}
+
+// txReconHandler periodically starts transaction inventory reconciliation
+// rounds with the peer.  It must be run as a goroutine for outbound peers that
+// announce transactions via set reconciliation.
+func (sp *serverPeer) txReconHandler() {
+	ticker := time.NewTicker(txReconInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			msg, abandoned, err := sp.txRecon.StartRound()
+			if err != nil {
+				peerLog.Errorf("Unable to start transaction "+
+					"reconciliation with %v: %v", sp, err)
+				continue
+			}
+
+			// Announce the transactions of any previous round the
+			// peer did not respond to via flooding.
+			sp.queueTxInventory(abandoned)
+			sp.QueueMessage(msg, nil)
+
+		case <-sp.quit:
+			return
+		}
+	}
+}
+
+// OnReqRecon is invoked when a peer receives a reqrecon wire message.  It
+// responds with a sketch of the transactions pending announcement to the peer.
+func (sp *serverPeer) OnReqRecon(p *peer.Peer, msg *wire.MsgReqRecon) {
+	// Disconnect peers that did not negotiate transaction reconciliation.
+	if sp.txRecon == nil {
+		peerLog.Debugf("Peer %v sent reqrecon without negotiating "+
+			"transaction reconciliation -- disconnecting", sp)
+		sp.Disconnect()
+		return
+	}
+
+	sketch, err := sp.txRecon.HandleReqRecon(msg)
+	if err != nil {
+		peerLog.Debugf("Unable to handle reqrecon from %v: %v", sp, err)
+		sp.addBanScore(0, 20, "unexpected reqrecon")
+		return
+	}
+	p.QueueMessage(sketch, nil)
+}
+
+// OnReconSketch is invoked when a peer receives a reconsketch wire message.  It
+// concludes the reconciliation round by requesting the transactions that are
+// only known to the peer and announcing those that are only known locally.
+func (sp *serverPeer) OnReconSketch(p *peer.Peer, msg *wire.MsgReconSketch) {
+	// Disconnect peers that did not negotiate transaction reconciliation.
+	if sp.txRecon == nil {
+		peerLog.Debugf("Peer %v sent reconsketch without negotiating "+
+			"transaction reconciliation -- disconnecting", sp)
+		sp.Disconnect()
+		return
+	}
+
+	// Note that a sketch for a round that was already abandoned due to
+	// taking too long is not a protocol violation, so it is ignored.
+	diff, announce, err := sp.txRecon.HandleSketch(msg)
+	if err != nil {
+		peerLog.Debugf("Unable to handle reconsketch from %v: %v", sp,
+			err)
+		return
+	}
+	if !diff.Success {
+		peerLog.Tracef("Transaction reconciliation with %v failed -- "+
+			"announcing %d transactions via flooding", sp, len(announce))
+	}
+	p.QueueMessage(diff, nil)
+	sp.queueTxInventory(announce)
+}
+
+// OnReconDiff is invoked when a peer receives a recondiff wire message.  It
+// announces the transactions requested by the peer.
+func (sp *serverPeer) OnReconDiff(p *peer.Peer, msg *wire.MsgReconDiff) {
+	// Disconnect peers that did not negotiate transaction reconciliation.
+	if sp.txRecon == nil {
+		peerLog.Debugf("Peer %v sent recondiff without negotiating "+
+			"transaction reconciliation -- disconnecting", sp)
+		sp.Disconnect()
+		return
+	}
+
+	announce, err := sp.txRecon.HandleReconDiff(msg)
+	if err != nil {
+		peerLog.Debugf("Unable to handle recondiff from %v: %v", sp, err)
+		sp.addBanScore(0, 20, "unexpected recondiff")
+		return
+	}
+	sp.queueTxInventory(announce)
+}
+
 // OnMemPool is invoked when a peer receives a mempool wire message.  It creates
 // and sends an inventory message with the contents of the memory pool up to the
 // maximum inventory allowed per message.
@@ -725,27 +968,45 @@ func (sp *serverPeer) OnMemPool(p *peer.Peer, msg *wire.MsgMemPool) {
 		return
 	}
 
-	// Generate inventory message with the available transactions in the
-	// transaction memory pool.  Limit it to the max allowed inventory
-	// per message.  The NewMsgInvSizeHint function automatically limits
-	// the passed hint to the maximum allowed, so it's safe to pass it
-	// without double checking it here.
-	txMemPool := sp.server.txMemPool
-	txDescs := txMemPool.TxDescs()
+	// Send an inventory message with the available transactions in the
+	// transaction memory pool if there is anything to send.
+	invMsg := sp.mempoolInvMsg(sp.server.txMemPool.TxDescs())
+	if len(invMsg.InvList) > 0 {
+		p.QueueMessage(invMsg, nil)
+	}
+}
+
+// mempoolInvMsg returns an inventory message with the passed memory pool
+// transactions the peer is interested in, limited to the max allowed inventory
+// per message.
+func (sp *serverPeer) mempoolInvMsg(txDescs []*mempool.TxDesc) *wire.MsgInv {
+	// The NewMsgInvSizeHint function automatically limits the passed hint
+	// to the maximum allowed, so it's safe to pass it without double
+	// checking it here.
 	invMsg := wire.NewMsgInvSizeHint(uint(len(txDescs)))
 
-	for i, txDesc := range txDescs {
+	feeFilter := sp.feeFilterRate()
+	for _, txDesc := range txDescs {
+		// Skip transactions that pay less than the fee rate the peer
+		// requested via a feefilter message.
+		txFeeRate := feeFilterTxRate(txDesc)
+		if txFeeRate >= 0 && txFeeRate < feeFilter {
+			continue
+		}
+
+		// Skip transactions that do not match the bloom filter of the
+		// peer when it has one loaded.
+		if !sp.bloomFilterAllowsTx(txDesc.Tx) {
+			continue
+		}
+
 		iv := wire.NewInvVect(wire.InvTypeTx, txDesc.Tx.Hash())
 		invMsg.AddInvVect(iv)
-		if i+1 >= wire.MaxInvPerMsg {
+		if len(invMsg.InvList) >= wire.MaxInvPerMsg {
 			break
 		}
 	}
-
-	// Send the inventory message if there is anything to send.
-	if len(invMsg.InvList) > 0 {
-		p.QueueMessage(invMsg, nil)
-	}
+	return invMsg
 }
 
 // pushMiningStateMsg pushes a mining state message to the queue for a
@@ -885,8 +1146,16 @@ func (sp *serverPeer) OnTx(p *peer.Peer, msg *wire.MsgTx) {
 	// processed and known good or bad.  This helps prevent a malicious peer
 	// from queuing up a bunch of bad transactions before disconnecting (or
 	// being disconnected) and wasting memory.
-	sp.server.blockManager.QueueTx(tx, sp.Peer, sp.txProcessed)
-	<-sp.txProcessed
+	sp.server.blockManager.QueueTx(tx, sp.Peer, sp.isFeeExempt,
+		sp.txProcessed)
+	outcome := <-sp.txProcessed
+
+	// Charge the resources consumed by the transaction to the transaction
+	// acceptance budgets of the peer and penalize it when it is over them.
+	penalty, reason := sp.txBudget.record(&outcome, time.Now())
+	if penalty > 0 {
+		sp.addBanScore(0, penalty, reason)
+	}
 }
 
 // OnBlock is invoked when a peer receives a block wire message.  It blocks
@@ -928,8 +1197,12 @@ func (sp *serverPeer) OnInv(p *peer.Peer, msg *wire.MsgInv) {
 		return
 	}
 
+	if sp.server.peerTelemetry != nil {
+		sp.server.peerTelemetry.InvReceived(sp, msg.InvList)
+	}
+
 	if !cfg.BlocksOnly {
-		sp.server.blockManager.QueueInv(msg, sp.Peer)
+		sp.server.blockManager.QueueInv(msg, sp.Peer, sp.isFeeExempt)
 		return
 	}
 
@@ -948,7 +1221,7 @@ func (sp *serverPeer) OnInv(p *peer.Peer, msg *wire.MsgInv) {
 		}
 	}
 
-	sp.server.blockManager.QueueInv(newInv, sp.Peer)
+	sp.server.blockManager.QueueInv(newInv, sp.Peer, sp.isFeeExempt)
 }
 
 // OnHeaders is invoked when a peer receives a headers wire message.  The
@@ -1017,6 +1290,13 @@ func (sp *serverPeer) OnGetData(p *peer.Peer, msg *wire.MsgGetData) {
 			err = sp.server.pushTxMsg(sp, &iv.Hash, c, waitChan)
 		case wire.InvTypeBlock:
 			err = sp.server.pushBlockMsg(sp, &iv.Hash, c, waitChan)
+		case wire.InvTypeFilteredBlock:
+			if sp.server.services&wire.SFNodeBloom != wire.SFNodeBloom {
+				peerLog.Debugf("%s requested a filtered block with "+
+					"bloom filtering disabled", sp)
+				continue
+			}
+			err = sp.server.pushMerkleBlockMsg(sp, &iv.Hash, c, waitChan)
 		default:
 			peerLog.Warnf("Unknown type '%d' in inventory request from %s",
 				iv.Type, sp)
@@ -1053,6 +1333,12 @@ func (sp *serverPeer) OnGetData(p *peer.Peer, msg *wire.MsgGetData) {
 
 // OnGetBlocks is invoked when a peer receives a getblocks wire message.
 func (sp *serverPeer) OnGetBlocks(p *peer.Peer, msg *wire.MsgGetBlocks) {
+	// Ignore the request when full blocks are withheld from the peer since
+	// it would not be able to fetch the announced blocks anyway.
+	if sp.blocksWithheld {
+		return
+	}
+
 	// Find the most recent known block in the best chain based on the block
 	// locator and fetch all of the block hashes after it until either
 	// wire.MaxBlocksPerMsg have been fetched or the provided stop hash is
@@ -1400,6 +1686,10 @@ func (sp *serverPeer) OnAddr(p *peer.Peer, msg *wire.MsgAddr) {
 		return
 	}
 
+	if sp.server.peerTelemetry != nil {
+		sp.server.peerTelemetry.AddrRelayed(sp, telemetryDirRecv, msg.AddrList)
+	}
+
 	now := time.Now()
 	for _, na := range msg.AddrList {
 		// Don't add more address if we're disconnecting.
@@ -1535,11 +1825,12 @@ func (s *server) PruneRebroadcastInventory() {
 }
 
 // relayTransactions generates and relays inventory vectors for all of the
-// passed transactions to all connected peers.
+// passed transactions to all connected peers.  Votes and revocations are
+// announced immediately while all other transactions are trickled.
 func (s *server) relayTransactions(txns []*dcrutil.Tx) {
 	for _, tx := range txns {
 		iv := wire.NewInvVect(wire.InvTypeTx, tx.Hash())
-		s.RelayInventory(iv, tx, false)
+		s.RelayInventory(iv, tx, isPriorityRelayTx(tx.MsgTx()))
 	}
 }
 
@@ -1555,6 +1846,11 @@ func (s *server) AnnounceNewTransactions(txns []*dcrutil.Tx) {
 	if s.rpcServer != nil {
 		s.rpcServer.NotifyNewTransactions(txns)
 	}
+
+	// Notify webhooks of newly accepted transactions of interest.
+	if s.webhooks != nil {
+		s.webhooks.TxnsAccepted(txns)
+	}
 }
 
 // TransactionConfirmed marks the provided single confirmation transaction as
@@ -1600,7 +1896,7 @@ func (s *server) pushTxMsg(sp *serverPeer, hash *chainhash.Hash, doneChan chan<-
 // pushBlockMsg sends a block message for the provided block hash to the
 // connected peer.  An error is returned if the block hash is not known.
 func (s *server) pushBlockMsg(sp *serverPeer, hash *chainhash.Hash, doneChan chan<- struct{}, waitChan <-chan struct{}) error {
-	block, err := sp.server.chain.BlockByHash(hash)
+	block, err := s.fetchServedBlock(sp, hash)
 	if err != nil {
 		peerLog.Tracef("Unable to fetch requested block hash %v: %v",
 			hash, err)
@@ -1868,12 +2164,92 @@ func (s *server) handleBanPeerMsg(state *peerState, sp *serverPeer) {
 	direction := directionString(sp.Inbound())
 	srvrLog.Infof("Banned peer %s (%s) for %v", host, direction,
 		cfg.BanDuration)
-	state.banned[host] = time.Now().Add(cfg.BanDuration)
+	until := time.Now().Add(cfg.BanDuration)
+	state.banned[host] = until
+	if s.webhooks != nil {
+		s.webhooks.PeerBanned(host, sp.Inbound(), until)
+	}
+}
+
+// txExemptFromFeeFilter returns whether transactions of the provided type are
+// always announced regardless of any fee filter requested by a peer.  Votes and
+// revocations are exempt since they are not required to pay a fee and are
+// critical to the operation of the network.
+func txExemptFromFeeFilter(txType stake.TxType) bool {
+	return txType == stake.TxTypeSSGen || txType == stake.TxTypeSSRtx
+}
+
+// isPriorityRelayTx returns whether the passed transaction is processed and
+// relayed via the priority lane which bypasses the general processing queue and
+// the inventory trickle delay.  Votes and revocations use the priority lane
+// since they are time sensitive and delaying them during mempool congestion
+// results in missed votes.
+func isPriorityRelayTx(tx *wire.MsgTx) bool {
+	return stake.IsSSGen(tx) || stake.IsSSRtx(tx)
+}
+
+// txFeePerKB returns the fee rate in atoms/kB of the passed transaction given
+// the total fee it pays.
+func txFeePerKB(tx *dcrutil.Tx, fee int64) int64 {
+	serializedSize := int64(tx.MsgTx().SerializeSize())
+	if serializedSize == 0 {
+		return 0
+	}
+	return fee * 1000 / serializedSize
+}
+
+// feeFilterTxRate returns the fee rate in atoms/kB of the passed memory pool
+// transaction to compare against the fee filters of peers, or -1 when the
+// transaction is exempt from fee filters.
+func feeFilterTxRate(txDesc *mempool.TxDesc) int64 {
+	if txExemptFromFeeFilter(txDesc.Type) {
+		return -1
+	}
+	return txFeePerKB(txDesc.Tx, txDesc.Fee)
+}
+
+// shouldAnnounceInv returns whether or not the passed inventory should be
+// announced to the peer.  The fee rate is that of the transaction the inventory
+// refers to, or negative when it is unknown or not a transaction, in which case
+// the fee filter of the peer does not apply.  Bloom filters and transaction
+// reconciliation are not considered.
+func (sp *serverPeer) shouldAnnounceInv(iv *wire.InvVect, txFeeRate int64) bool {
+	switch iv.Type {
+	case wire.InvTypeBlock:
+		// Don't announce blocks via inventory to peers that full blocks
+		// are withheld from since they would not be able to fetch them.
+		return !sp.blocksWithheld
+
+	case wire.InvTypeTx:
+		// Don't relay the transaction to the peer when it has
+		// transaction relaying disabled.
+		if sp.relayTxDisabled() {
+			return false
+		}
+
+		// Don't relay the transaction when it pays a lower fee rate
+		// than the peer requested via a feefilter message.
+		if txFeeRate >= 0 && txFeeRate < sp.feeFilterRate() {
+			return false
+		}
+	}
+	return true
 }
 
 // handleRelayInvMsg deals with relaying inventory to peers that are not already
 // known to have it.  It is invoked from the peerHandler goroutine.
 func (s *server) handleRelayInvMsg(state *peerState, msg relayMsg) {
+	// Determine the fee rate of transactions so it can be compared against
+	// the fee filters of the peers.  A negative fee rate indicates it is
+	// unknown, in which case the transaction is announced to all peers.
+	txFeeRate := int64(-1)
+	if msg.invVect.Type == wire.InvTypeTx {
+		txDesc, err := s.txMemPool.FetchTxDesc(&msg.invVect.Hash)
+		if err == nil {
+			txFeeRate = feeFilterTxRate(txDesc)
+		}
+	}
+
 	state.forAllPeers(func(sp *serverPeer) {
 		if !sp.Connected() {
 			return
@@ -1899,10 +2275,32 @@ func (s *server) handleRelayInvMsg(state *peerState, msg relayMsg) {
 			return
 		}
 
+		if !sp.shouldAnnounceInv(msg.invVect, txFeeRate) {
+			return
+		}
+
 		if msg.invVect.Type == wire.InvTypeTx {
-			// Don't relay the transaction to the peer when it has
-			// transaction relaying disabled.
-			if sp.relayTxDisabled() {
+			// Don't relay the transaction when it does not match the
+			// bloom filter of the peer.
+			if sp.filter.IsLoaded() {
+				tx, ok := msg.data.(*dcrutil.Tx)
+				if !ok {
+					peerLog.Warnf("Underlying data for tx inv "+
+						"relay is not a *dcrutil.Tx: %T", msg.data)
+					return
+				}
+				if !sp.filter.MatchTxAndUpdate(tx) {
+					return
+				}
+			}
+
+			// Add the transaction to the set pending reconciliation
+			// when transactions are announced to the peer that way.
+			// It is announced via flooding instead when the set is
+			// full.
+			if sp.txRecon != nil && !msg.immediate &&
+				!sp.IsKnownInventory(msg.invVect) &&
+				sp.txRecon.AddTx(&msg.invVect.Hash) {
 				return
 			}
 		}
@@ -1955,6 +2353,10 @@ type getAddedNodesMsg struct {
 	reply chan []*serverPeer
 }
 
+type getBannedMsg struct {
+	reply chan map[string]time.Time
+}
+
 type disconnectNodeMsg struct {
 	cmp   func(*serverPeer) bool
 	reply chan error
@@ -1999,6 +2401,18 @@ func (s *server) handleQuery(state *peerState, querymsg interface{}) {
 		})
 		msg.reply <- peers
 
+	case getBannedMsg:
+		// Respond with a copy of the hosts that are still banned along
+		// with when their bans expire.
+		now := time.Now()
+		banned := make(map[string]time.Time, len(state.banned))
+		for host, banEnd := range state.banned {
+			if now.Before(banEnd) {
+				banned[host] = banEnd
+			}
+		}
+		msg.reply <- banned
+
 	case connectNodeMsg:
 		// XXX duplicate oneshots?
 		// Limit max number of total peers.
@@ -2017,7 +2431,7 @@ func (s *server) handleQuery(state *peerState, querymsg interface{}) {
 			}
 		}
 
-		netAddr, err := addrStringToNetAddr(msg.addr)
+		netAddrs, err := addrStringToNetAddrs(msg.addr)
 		if err != nil {
 			msg.reply <- err
 			return
@@ -2026,8 +2440,9 @@ func (s *server) handleQuery(state *peerState, querymsg interface{}) {
 		// TODO: if too many, nuke a non-perm peer.
 		go s.connManager.Connect(context.Background(),
 			&connmgr.ConnReq{
-				Addr:      netAddr,
-				Permanent: msg.permanent,
+				Addr:          netAddrs[0],
+				FallbackAddrs: netAddrs[1:],
+				Permanent:     msg.permanent,
 			})
 		msg.reply <- nil
 
@@ -2144,6 +2559,13 @@ func newPeerConfig(sp *serverPeer) *peer.Config {
 		Listeners: peer.MessageListeners{
 			OnVersion:        sp.OnVersion,
 			OnMemPool:        sp.OnMemPool,
+			OnFeeFilter:      sp.OnFeeFilter,
+			OnReqRecon:       sp.OnReqRecon,
+			OnReconSketch:    sp.OnReconSketch,
+			OnReconDiff:      sp.OnReconDiff,
+			OnFilterLoad:     sp.OnFilterLoad,
+			OnFilterAdd:      sp.OnFilterAdd,
+			OnFilterClear:    sp.OnFilterClear,
 			OnGetMiningState: sp.OnGetMiningState,
 			OnMiningState:    sp.OnMiningState,
 			OnTx:             sp.OnTx,
@@ -2174,6 +2596,9 @@ func newPeerConfig(sp *serverPeer) *peer.Config {
 		DisableRelayTx:    cfg.BlocksOnly,
 		ProtocolVersion:   maxProtocolVersion,
 		IdleTimeout:       cfg.PeerIdleTimeout,
+		IdentityKey:       sp.server.identityKey,
+		AuthorizePeer:     sp.authorizePeer,
+		MessageLimits:     cfg.msgLimits,
 	}
 }
 
@@ -2184,9 +2609,12 @@ func newPeerConfig(sp *serverPeer) *peer.Config {
 func (s *server) inboundPeerConnected(conn net.Conn) {
 	sp := newServerPeer(s, false)
 	sp.isWhitelisted = isWhitelisted(conn.RemoteAddr())
+	sp.isFeeExempt = isFeeExempt(conn.RemoteAddr())
+	sp.blocksWithheld = cfg.NoServeBlocks && !sp.isWhitelisted
 	sp.Peer = peer.NewInboundPeer(newPeerConfig(sp))
 	sp.AssociateConnection(conn)
 	go s.peerDoneHandler(sp)
+	go sp.tcpInfoHandler(conn)
 }
 
 // outboundPeerConnected is invoked by the connection manager when a new
@@ -2205,8 +2633,11 @@ func (s *server) outboundPeerConnected(c *connmgr.ConnReq, conn net.Conn) {
 	sp.Peer = p
 	sp.connReq = c
 	sp.isWhitelisted = isWhitelisted(conn.RemoteAddr())
+	sp.isFeeExempt = isFeeExempt(conn.RemoteAddr())
+	sp.blocksWithheld = cfg.NoServeBlocks && !sp.isWhitelisted
 	sp.AssociateConnection(conn)
 	go s.peerDoneHandler(sp)
+	go sp.tcpInfoHandler(conn)
 	s.addrManager.Attempt(sp.NA())
 }
 
@@ -2220,6 +2651,10 @@ func (s *server) peerDoneHandler(sp *serverPeer) {
 	if sp.VersionKnown() {
 		s.blockManager.DonePeer(sp.Peer)
 
+		if s.peerTelemetry != nil {
+			s.peerTelemetry.Disconnected(sp)
+		}
+
 		// Evict any remaining orphans that were sent by the peer.
 		numEvicted := s.txMemPool.RemoveOrphansByTag(mempool.Tag(sp.ID()))
 		if numEvicted > 0 {
@@ -2474,6 +2909,13 @@ func (s *server) AddBytesReceived(bytesReceived uint64) {
 	atomic.AddUint64(&s.bytesReceived, bytesReceived)
 }
 
+// TxReconStats returns statistics about the transaction inventory
+// reconciliation rounds performed with all peers.  The second return value is
+// false when reconciliation is disabled.
+func (s *server) TxReconStats() (txrecon.Stats, bool) {
+	return s.txReconMetrics.Stats(), s.services&wire.SFNodeTxRecon != 0
+}
+
 // NetTotals returns the sum of all bytes received and sent across the network
 // for all peers.  It is safe for concurrent access.
 func (s *server) NetTotals() (uint64, uint64) {
@@ -2670,6 +3112,56 @@ func (s *server) Run(ctx context.Context) {
 		go s.upnpUpdateThread(serverCtx)
 	}
 
+	// Monitor the free disk space available to the data directory when any
+	// of the thresholds are enabled.
+	if cfg.DiskSpaceWarn != 0 || cfg.DiskSpaceStop != 0 {
+		s.wg.Add(1)
+		go func(s *server) {
+			s.diskSpaceMonitor.Run(serverCtx, func(status *rpcserver.DiskSpaceStatus) {
+				if s.rpcServer != nil {
+					s.rpcServer.NotifyDiskSpace(status)
+				}
+			})
+			s.wg.Done()
+		}(s)
+	}
+
+	// Deliver critical event alerts to the configured webhooks.
+	if s.alertOutbox != nil {
+		s.wg.Add(1)
+		go func(s *server) {
+			s.alertOutbox.Run(serverCtx)
+			s.wg.Done()
+		}(s)
+	}
+
+	// Exchange heartbeats with the peer of the redundancy pair.
+	if s.coordinator != nil {
+		s.wg.Add(1)
+		go func(s *server) {
+			s.coordinator.Run(serverCtx)
+			s.wg.Done()
+		}(s)
+	}
+
+	// Deliver events to the configured webhooks.
+	if s.webhooks != nil {
+		s.wg.Add(1)
+		go func(s *server) {
+			s.webhooks.Run(serverCtx)
+			s.wg.Done()
+		}(s)
+	}
+
+	// Discover and connect to other nodes on the local network.
+	if s.mdns != nil {
+		s.wg.Add(1)
+		go func(s *server) {
+			s.mdns.Run(serverCtx)
+			s.wg.Done()
+		}(s)
+	}
+
 	if !cfg.DisableRPC {
 		// Start the rebroadcastHandler, which ensures user tx received by
 		// the RPC server are rebroadcast until being included in a block.
@@ -2679,6 +3171,9 @@ func (s *server) Run(ctx context.Context) {
 		s.wg.Add(1)
 		go func(s *server) {
 			s.rpcServer.Run(serverCtx)
+			if s.rpcAuditLog != nil {
+				s.rpcAuditLog.Close()
+			}
 			s.wg.Done()
 		}(s)
 	}
@@ -2715,6 +3210,11 @@ func (s *server) Run(ctx context.Context) {
 	// down.
 	shutdownServer()
 	s.wg.Wait()
+
+	// Release the shared signature cache segment when it is shared.
+	if err := s.sigCache.Close(); err != nil {
+		srvrLog.Errorf("Unable to close shared signature cache: %v", err)
+	}
 }
 
 // parseListeners determines whether each listen address is IPv4 and IPv6 and
@@ -2840,6 +3340,27 @@ func standardScriptVerifyFlags(chain *blockchain.BlockChain) (txscript.ScriptFla
 	return scriptFlags, nil
 }
 
+// isAgendaActive returns whether or not the agenda with the provided id is
+// active for the block after the current best block.  Agendas that are not
+// defined for the network are treated as inactive.
+func isAgendaActive(chain *blockchain.BlockChain, params *chaincfg.Params, agendaID string) (bool, error) {
+	for version, deployments := range params.Deployments {
+		for i := range deployments {
+			if deployments[i].Vote.Id != agendaID {
+				continue
+			}
+			best := chain.BestSnapshot()
+			state, err := chain.NextThresholdState(&best.Hash, version,
+				agendaID)
+			if err != nil {
+				return false, err
+			}
+			return state.State == blockchain.ThresholdActive, nil
+		}
+	}
+	return false, nil
+}
+
 // genCertPair generates a key/cert pair to the paths provided.
 func genCertPair(certFile, keyFile string, altDNSNames []string, tlsCurve elliptic.Curve) error {
 	rpcsLog.Infof("Generating TLS certificates...")
@@ -2865,6 +3386,39 @@ func genCertPair(certFile, keyFile string, altDNSNames []string, tlsCurve ellipt
 	return nil
 }
 
+// loadPeerIdentityKey loads the hex-encoded long-term peer identity key from
+// the provided path or generates and saves a new one when the file does not
+// exist.
+func loadPeerIdentityKey(keyFile string) (*secp256k1.PrivateKey, error) {
+	if !fileExists(keyFile) {
+		srvrLog.Infof("Generating peer identity key")
+		key, err := secp256k1.GeneratePrivateKey()
+		if err != nil {
+			return nil, err
+		}
+		keyHex := hex.EncodeToString(key.Serialize()) + "\n"
+		if err := ioutil.WriteFile(keyFile, []byte(keyHex), 0600); err != nil {
+			return nil, err
+		}
+		return key, nil
+	}
+
+	keyHex, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	keyBytes, err := hex.DecodeString(strings.TrimSpace(string(keyHex)))
+	if err != nil {
+		return nil, fmt.Errorf("malformed peer identity key file %s: %v",
+			keyFile, err)
+	}
+	if len(keyBytes) != secp256k1.PrivKeyBytesLen {
+		return nil, fmt.Errorf("malformed peer identity key file %s: "+
+			"invalid key length %d", keyFile, len(keyBytes))
+	}
+	return secp256k1.PrivKeyFromBytes(keyBytes), nil
+}
+
 // setupRPCListeners returns a slice of listeners that are configured for use
 // with the RPC server depending on the configuration settings for listen
 // addresses and TLS.
@@ -2923,16 +3477,56 @@ func setupRPCListeners() ([]net.Listener, error) {
 		listeners = append(listeners, listener)
 	}
 
+	// Listen on the unix socket if configured.  It does not use TLS since
+	// the connections never leave the host.
+	if cfg.RPCUnixSocket != "" {
+		listener, err := listenUnixSocket(cfg.RPCUnixSocket)
+		if err != nil {
+			rpcsLog.Warnf("Can't listen on %s: %v", cfg.RPCUnixSocket, err)
+		} else {
+			listeners = append(listeners, listener)
+		}
+	}
+
 	return listeners, nil
 }
 
+// serverServices returns the services the server advertises to peers based on
+// the passed configuration, excluding the peer authentication service which
+// depends on loading the identity key.
+func serverServices(cfg *config) wire.ServiceFlag {
+	services := defaultServices
+	if cfg.NoCFilters {
+		services &^= wire.SFNodeCF
+	}
+	if cfg.NoServeBlocks {
+		services &^= wire.SFNodeNetwork
+	}
+	if cfg.TxRecon && !cfg.BlocksOnly {
+		services |= wire.SFNodeTxRecon
+	}
+	if cfg.BloomFilters {
+		services |= wire.SFNodeBloom
+	}
+	return services
+}
+
 // newServer returns a new dcrd server configured to listen on addr for the
 // decred network type specified by chainParams.  Use start to begin accepting
 // connections from peers.
 func newServer(ctx context.Context, listenAddrs []string, db database.DB, chainParams *chaincfg.Params, dataDir string) (*server, error) {
-	services := defaultServices
-	if cfg.NoCFilters {
-		services &^= wire.SFNodeCF
+	services := serverServices(cfg)
+	var identityKey *secp256k1.PrivateKey
+	if cfg.PeerIdentity {
+		var err error
+		keyFile := path.Join(dataDir, peerIdentityKeyFilename)
+		identityKey, err = loadPeerIdentityKey(keyFile)
+		if err != nil {
+			return nil, err
+		}
+		services |= wire.SFNodePeerAuth
+		srvrLog.Infof("Peer identity key: %x",
+			identityKey.PubKey().SerializeCompressed())
 	}
 
 	amgr := addrmgr.New(cfg.DataDir, dcrdLookup)
@@ -2950,6 +3544,20 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB, chainP
 		}
 	}
 
+	// Share the signature cache with other processes on this host when
+	// requested.
+	sigCache := txscript.NewSigCache(cfg.SigCacheMaxSize)
+	if cfg.SharedSigCache != "" {
+		var err error
+		sigCache, err = txscript.NewSharedSigCache(cfg.SigCacheMaxSize,
+			cfg.SharedSigCache)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open shared signature cache: %v",
+				err)
+		}
+		srvrLog.Infof("Sharing signature cache via %s", cfg.SharedSigCache)
+	}
+
 	s := server{
 		chainParams:          chainParams,
 		addrManager:          amgr,
@@ -2965,7 +3573,8 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB, chainP
 		db:                   db,
 		timeSource:           blockchain.NewMedianTime(),
 		services:             services,
-		sigCache:             txscript.NewSigCache(cfg.SigCacheMaxSize),
+		identityKey:          identityKey,
+		sigCache:             sigCache,
 		subsidyCache:         standalone.NewSubsidyCache(chainParams),
 	}
 
@@ -3000,6 +3609,11 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB, chainP
 		s.existsAddrIndex = indexers.NewExistsAddrIndex(db, chainParams)
 		indexes = append(indexes, s.existsAddrIndex)
 	}
+	if cfg.AddrActivityIndex {
+		indxLog.Info("Address activity index is enabled")
+		s.addrActivityIndex = indexers.NewAddrActivityIndex(db, chainParams)
+		indexes = append(indexes, s.addrActivityIndex)
+	}
 	if !cfg.NoCFilters {
 		indxLog.Info("CF index is enabled")
 		s.cfIndex = indexers.NewCfIndex(db, chainParams)
@@ -3037,6 +3651,10 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB, chainP
 	if !cfg.DisableCheckpoints {
 		checkpoints = s.chainParams.Checkpoints
 	}
+	if cfg.assumeValid != (chainhash.Hash{}) {
+		srvrLog.Infof("Assuming block %v and its ancestors are valid",
+			cfg.assumeValid)
+	}
 
 	// Create a new block chain instance with the appropriate configuration.
 	s.chain, err = blockchain.New(ctx,
@@ -3044,6 +3662,7 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB, chainP
 			DB:          s.db,
 			ChainParams: s.chainParams,
 			Checkpoints: checkpoints,
+			AssumeValid: cfg.assumeValid,
 			TimeSource:  s.timeSource,
 			Notifications: func(notification *blockchain.Notification) {
 				if s.blockManager != nil {
@@ -3063,6 +3682,7 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB, chainP
 			MaxTxVersion:         2,
 			DisableRelayPriority: cfg.NoRelayPriority,
 			AcceptNonStd:         cfg.AcceptNonStd,
+			TxTypePolicies:       cfg.txPolicies,
 			FreeTxRelayLimit:     cfg.FreeTxRelayLimit,
 			MaxOrphanTxs:         cfg.MaxOrphanTxs,
 			MaxOrphanTxSize:      mempool.MaxStandardTxSize,
@@ -3085,6 +3705,12 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB, chainP
 				return standardScriptVerifyFlags(s.chain)
 			},
 			AcceptSequenceLocks: s.chain.IsFixSeqLocksAgendaActive,
+			IsAgendaActive: func(agendaID string) (bool, error) {
+				return isAgendaActive(s.chain, chainParams, agendaID)
+			},
+			MaxTxAge:                      cfg.MempoolTTL,
+			EnableReplacement:             cfg.TxReplacement,
+			MinReplacementFeeRateIncrease: cfg.ReplacementFeeIncrease,
 		},
 		ChainParams: chainParams,
 		NextStakeDifficulty: func() (int64, error) {
@@ -3110,7 +3736,28 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB, chainP
 			}
 		},
 	}
+	if cfg.TxPolicyURL != "" {
+		txPolicy := newExternalTxPolicy(cfg.TxPolicyURL, cfg.TxPolicyTimeout)
+		txC.Policy.ExternalPolicy = txPolicy.Check
+	}
 	s.txMemPool = mempool.New(&txC)
+
+	// Create the outbox for critical event alerts when any kinds of critical
+	// events are enabled.
+	if len(cfg.AlertEvents) > 0 {
+		s.alertOutbox, err = newAlertOutbox(cfg.DataDir, s.chainParams,
+			cfg.AlertEvents, cfg.AlertReorgDepth, cfg.AlertWebhooks)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Create the webhook manager when any webhooks are configured.
+	if len(cfg.webhooks) > 0 {
+		s.webhooks = newWebhookManager(s.chainParams, cfg.webhooks,
+			cfg.WebhookSecret, cfg.webhookAddrs)
+	}
+
 	s.blockManager, err = newBlockManager(&blockManagerConfig{
 		PeerNotifier:       &s,
 		Chain:              s.chain,
@@ -3129,6 +3776,8 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB, chainP
 		RpcServer: func() *rpcserver.Server {
 			return s.rpcServer
 		},
+		AlertOutbox: s.alertOutbox,
+		Webhooks:    s.webhooks,
 	})
 	if err != nil {
 		return nil, err
@@ -3157,7 +3806,7 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB, chainP
 			cfg.MiningTimeOffset)
 
 		s.bg = mining.NewBgBlkTmplGenerator(tg, cfg.miningAddrs,
-			cfg.AllowUnsyncedMining)
+			cfg.miningPolicy, cfg.AllowUnsyncedMining)
 		s.blockManager.cfg.BgBlkTmplGenerator = s.bg
 
 		s.cpuMiner = cpuminer.New(&cpuminer.Config{
@@ -3177,15 +3826,30 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB, chainP
 	// to specified peers and actively avoid advertising and connecting to
 	// discovered peers in order to prevent it from becoming a public test
 	// network.
-	var newAddressFunc func() (net.Addr, error)
+	//
+	// The connection manager requests addresses of a specific family when it
+	// needs more outbound peers of that family to reach the configured
+	// per-family targets.  Only IPv6 addresses are ever returned when running
+	// in IPv6-only mode.
+	var newAddressFunc func(connmgr.AddressFamily) (net.Addr, error)
 	if !cfg.SimNet && !cfg.RegNet && len(cfg.ConnectPeers) == 0 {
-		newAddressFunc = func() (net.Addr, error) {
+		newAddressFunc = func(family connmgr.AddressFamily) (net.Addr, error) {
+			if cfg.IPv6Only {
+				family = connmgr.IPv6Family
+			}
 			for tries := 0; tries < 100; tries++ {
 				addr := s.addrManager.GetAddress()
 				if addr == nil {
 					break
 				}
 
+				// Skip addresses that are not of the requested family.
+				isIPv4 := addr.NetAddress().IP.To4() != nil
+				if (family == connmgr.IPv4Family && !isIPv4) ||
+					(family == connmgr.IPv6Family && isIPv4) {
+					continue
+				}
+
 				// Address will not be invalid, local or unroutable
 				// because addrmanager rejects those on addition.
 				// Just check that we don't already have an address
@@ -3217,41 +3881,84 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB, chainP
 		}
 	}
 
+	// Create the disk space monitor for the data directory with the
+	// thresholds converted from MiB to bytes.
+	s.diskSpaceMonitor = newDiskSpaceMonitor(cfg.DataDir,
+		cfg.DiskSpaceWarn<<20, cfg.DiskSpaceStop<<20)
+
+	// Create the cache and load queue for serving ancient blocks to peers.
+	s.ancientBlocks = newAncientBlockServer(servedBlockCacheSize,
+		maxConcurrentAncientLoads)
+
+	// Create the anonymized peer telemetry recorder when enabled.
+	if cfg.PeerTelemetry {
+		s.peerTelemetry, err = newPeerTelemetry(cfg.DataDir,
+			s.chainParams.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Create a connection manager.
 	targetOutbound := defaultTargetOutbound
 	if cfg.MaxPeers < targetOutbound {
 		targetOutbound = cfg.MaxPeers
 	}
+	preferredFamily := connmgr.IPv6Family
+	if cfg.PreferIPv4 {
+		preferredFamily = connmgr.IPv4Family
+	}
 	cmgr, err := connmgr.New(&connmgr.Config{
-		Listeners:      listeners,
-		OnAccept:       s.inboundPeerConnected,
-		RetryDuration:  connectionRetryInterval,
-		TargetOutbound: uint32(targetOutbound),
-		Dial:           dcrdDial,
-		Timeout:        cfg.DialTimeout,
-		OnConnection:   s.outboundPeerConnected,
-		GetNewAddress:  newAddressFunc,
+		Listeners:           listeners,
+		OnAccept:            s.inboundPeerConnected,
+		RetryDuration:       connectionRetryInterval,
+		TargetOutbound:      uint32(targetOutbound),
+		TargetOutboundIPv4:  cfg.TargetOutboundIPv4,
+		TargetOutboundIPv6:  cfg.TargetOutboundIPv6,
+		IPv6Only:            cfg.IPv6Only,
+		PreferredFamily:     preferredFamily,
+		Dial:                dcrdDial,
+		Timeout:             cfg.DialTimeout,
+		OnConnection:        s.outboundPeerConnected,
+		GetNewAddressFamily: newAddressFunc,
 	})
 	if err != nil {
 		return nil, err
 	}
 	s.connManager = cmgr
 
+	// Create the local network discovery when it is enabled.  The local node
+	// is only advertised when it is listening for connections.
+	if cfg.MDNS {
+		var port uint16
+		if len(listeners) > 0 {
+			if addr, ok := listeners[0].Addr().(*net.TCPAddr); ok {
+				port = uint16(addr.Port)
+			}
+		}
+		s.mdns, err = newMDNSDiscovery(s.chainParams.Name, port,
+			s.connManager.Connect)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Start up persistent peers.
 	permanentPeers := cfg.ConnectPeers
 	if len(permanentPeers) == 0 {
 		permanentPeers = cfg.AddPeers
 	}
 	for _, addr := range permanentPeers {
-		tcpAddr, err := addrStringToNetAddr(addr)
+		netAddrs, err := addrStringToNetAddrs(addr)
 		if err != nil {
 			return nil, err
 		}
 
 		go s.connManager.Connect(context.Background(),
 			&connmgr.ConnReq{
-				Addr:      tcpAddr,
-				Permanent: true,
+				Addr:          netAddrs[0],
+				FallbackAddrs: netAddrs[1:],
+				Permanent:     true,
 			})
 	}
 
@@ -3290,6 +3997,7 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB, chainP
 			RPCPass:              cfg.RPCPass,
 			RPCLimitUser:         cfg.RPCLimitUser,
 			RPCLimitPass:         cfg.RPCLimitPass,
+			FeeExemptRPCUsers:    cfg.FeeExemptRPCUsers,
 			RPCMaxClients:        cfg.RPCMaxClients,
 			RPCMaxConcurrentReqs: cfg.RPCMaxConcurrentReqs,
 			RPCMaxWebsockets:     cfg.RPCMaxWebsockets,
@@ -3300,6 +4008,12 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB, chainP
 			UserAgentVersion:     userAgentVersion,
 			LogManager:           &rpcLogManager{},
 			FiltererV2:           s.chain,
+			DiskSpaceMonitor:     s.diskSpaceMonitor,
+			ProfileDir:           path.Join(dataDir, "profiles"),
+			StandardVerifyFlags: func() (txscript.ScriptFlags, error) {
+				return standardScriptVerifyFlags(s.chain)
+			},
+			EnableExperimentalRPC: cfg.EnableExperimentalRPC,
 		}
 		if s.existsAddrIndex != nil {
 			rpcsConfig.ExistsAddresser = s.existsAddrIndex
@@ -3310,18 +4024,53 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB, chainP
 		if s.txIndex != nil {
 			rpcsConfig.TxIndexer = s.txIndex
 		}
+		if s.peerTelemetry != nil {
+			rpcsConfig.PeerTelemetry = s.peerTelemetry
+		}
 		if s.addrIndex != nil {
 			rpcsConfig.AddrIndexer = s.addrIndex
 		}
 		if s.cfIndex != nil {
 			rpcsConfig.Filterer = s.cfIndex
 		}
+		if s.addrActivityIndex != nil {
+			rpcsConfig.AddrActivityIndexer = s.addrActivityIndex
+		}
+		if cfg.CoordPeer != "" {
+			s.coordinator, err = newCoordinator(cfg.CoordNodeID,
+				cfg.CoordLease, cfg.CoordPeer, cfg.RPCUser, cfg.RPCPass,
+				cfg.CoordCert, cfg.DisableTLS)
+			if err != nil {
+				return nil, fmt.Errorf("unable to set up redundancy "+
+					"pair coordination: %v", err)
+			}
+			rpcsConfig.Coordinator = s.coordinator
+		}
+		if cfg.RPCAuditLog != "" {
+			s.rpcAuditLog, err = os.OpenFile(cfg.RPCAuditLog,
+				os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+			if err != nil {
+				return nil, fmt.Errorf("unable to open RPC audit log: %v",
+					err)
+			}
+			rpcsConfig.AuditLog = s.rpcAuditLog
+		}
 
 		s.rpcServer, err = rpcserver.New(&rpcsConfig)
 		if err != nil {
 			return nil, err
 		}
 
+		// Disconnect the websocket clients when stepping down to the
+		// standby of the redundancy pair so they reconnect to the leader.
+		if s.coordinator != nil {
+			s.coordinator.notify = func(leader bool) {
+				if !leader {
+					s.rpcServer.DisconnectWebsocketClients()
+				}
+			}
+		}
+
 		// Signal process shutdown when the RPC server requests it.
 		go func() {
 			<-s.rpcServer.RequestedProcessShutdown()
@@ -3411,16 +4160,17 @@ func initListeners(ctx context.Context, params *chaincfg.Params, amgr *addrmgr.A
 	return listeners, nat, nil
 }
 
-// addrStringToNetAddr takes an address in the form of 'host:port' and returns
-// a net.Addr which maps to the original address with any host names resolved
-// to IP addresses.
-func addrStringToNetAddr(addr string) (net.Addr, error) {
+// addrStringToNetAddrs takes an address in the form of 'host:port' and returns
+// a net.Addr for every IP address the host resolves to, in the order they were
+// resolved, which maps to a net.TCPAddr.  Multiple addresses allow connections
+// to dual-stack hosts to be raced across the address families.
+func addrStringToNetAddrs(addr string) ([]net.Addr, error) {
 	host, strPort, err := net.SplitHostPort(addr)
 	if err != nil {
 		return nil, err
 	}
 
-	// Attempt to look up an IP address associated with the parsed host.
+	// Attempt to look up the IP addresses associated with the parsed host.
 	// The dcrdLookup function will transparently handle performing the
 	// lookup over Tor if necessary.
 	ips, err := dcrdLookup(host)
@@ -3436,10 +4186,25 @@ func addrStringToNetAddr(addr string) (net.Addr, error) {
 		return nil, err
 	}
 
-	return &net.TCPAddr{
-		IP:   ips[0],
-		Port: port,
-	}, nil
+	netAddrs := make([]net.Addr, 0, len(ips))
+	for _, ip := range ips {
+		netAddrs = append(netAddrs, &net.TCPAddr{
+			IP:   ip,
+			Port: port,
+		})
+	}
+	return netAddrs, nil
+}
+
+// addrStringToNetAddr takes an address in the form of 'host:port' and returns
+// a net.Addr for the first IP address the host resolves to which maps to a
+// net.TCPAddr.
+func addrStringToNetAddr(addr string) (net.Addr, error) {
+	netAddrs, err := addrStringToNetAddrs(addr)
+	if err != nil {
+		return nil, err
+	}
+	return netAddrs[0], nil
 }
 
 // addLocalAddress adds an address that this node is listening on to the
@@ -3491,7 +4256,20 @@ func addLocalAddress(addrMgr *addrmgr.AddrManager, addr string, services wire.Se
 // isWhitelisted returns whether the IP address is included in the whitelisted
 // networks and IPs.
 func isWhitelisted(addr net.Addr) bool {
-	if len(cfg.whitelists) == 0 {
+	return ipNetsContain(cfg.whitelists, addr)
+}
+
+// isFeeExempt returns whether the IP address is included in the networks and
+// IPs of peers whose relayed transactions are exempt from the minimum relay
+// fee.
+func isFeeExempt(addr net.Addr) bool {
+	return ipNetsContain(cfg.feeExempt, addr)
+}
+
+// ipNetsContain returns whether the IP address is included in any of the
+// provided networks.
+func ipNetsContain(ipnets []*net.IPNet, addr net.Addr) bool {
+	if len(ipnets) == 0 {
 		return false
 	}
 
@@ -3506,7 +4284,7 @@ func isWhitelisted(addr net.Addr) bool {
 		return false
 	}
 
-	for _, ipnet := range cfg.whitelists {
+	for _, ipnet := range ipnets {
 		if ipnet.Contains(ip) {
 			return true
 		}
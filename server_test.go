@@ -0,0 +1,198 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/decred/dcrd/blockchain/stake/v3"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/decred/dcrd/internal/bloom"
+	"github.com/decred/dcrd/internal/mempool"
+	"github.com/decred/dcrd/internal/mining"
+	"github.com/decred/dcrd/wire"
+)
+
+// TestServerServices ensures the services advertised by the server reflect the
+// configuration.
+func TestServerServices(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cfg  config
+		want wire.ServiceFlag
+	}{{
+		name: "default",
+		want: wire.SFNodeNetwork | wire.SFNodeCF,
+	}, {
+		name: "no cfilters",
+		cfg:  config{NoCFilters: true},
+		want: wire.SFNodeNetwork,
+	}, {
+		name: "no serve blocks",
+		cfg:  config{NoServeBlocks: true},
+		want: wire.SFNodeCF,
+	}, {
+		name: "txrecon",
+		cfg:  config{TxRecon: true},
+		want: wire.SFNodeNetwork | wire.SFNodeCF | wire.SFNodeTxRecon,
+	}, {
+		name: "txrecon blocks only",
+		cfg:  config{TxRecon: true, BlocksOnly: true},
+		want: wire.SFNodeNetwork | wire.SFNodeCF,
+	}, {
+		name: "bloom filters",
+		cfg:  config{BloomFilters: true},
+		want: wire.SFNodeNetwork | wire.SFNodeCF | wire.SFNodeBloom,
+	}}
+	for _, test := range tests {
+		if got := serverServices(&test.cfg); got != test.want {
+			t.Errorf("%q: unexpected services -- got %v, want %v",
+				test.name, got, test.want)
+		}
+	}
+}
+
+// TestBlocksWithheld ensures full blocks are neither served nor announced to
+// peers they are withheld from while other inventory is unaffected.
+func TestBlocksWithheld(t *testing.T) {
+	t.Parallel()
+
+	blockInv := wire.NewInvVect(wire.InvTypeBlock, &chainhash.Hash{0x01})
+	txInv := wire.NewInvVect(wire.InvTypeTx, &chainhash.Hash{0x02})
+	tests := []struct {
+		name      string
+		withheld  bool
+		iv        *wire.InvVect
+		wantRelay bool
+	}{
+		{"block served", false, blockInv, true},
+		{"block withheld", true, blockInv, false},
+		{"tx blocks served", false, txInv, true},
+		{"tx blocks withheld", true, txInv, true},
+	}
+	for _, test := range tests {
+		sp := &serverPeer{blocksWithheld: test.withheld}
+		if got := sp.shouldAnnounceInv(test.iv, -1); got != test.wantRelay {
+			t.Errorf("%q: unexpected announce result -- got %v, want %v",
+				test.name, got, test.wantRelay)
+		}
+	}
+
+	// Ensure requests for full blocks via getdata and getblocks are not
+	// served to peers they are withheld from.  The server is intentionally
+	// empty to ensure the requests are refused without consulting the
+	// chain.
+	s := &server{}
+	sp := &serverPeer{server: s, blocksWithheld: true}
+	_, err := s.fetchServedBlock(sp, &blockInv.Hash)
+	if !errors.Is(err, errBlocksNotServed) {
+		t.Fatalf("unexpected error -- got %v, want %v", err,
+			errBlocksNotServed)
+	}
+	sp.OnGetBlocks(nil, wire.NewMsgGetBlocks(&chainhash.Hash{}))
+}
+
+// feeFilterTestTxDescs returns memory pool transactions that pay the provided
+// fee rates in atoms/kB along with their types for use in fee filter tests.
+func feeFilterTestTxDescs(feeRates []int64, txTypes []stake.TxType) []*mempool.TxDesc {
+	txDescs := make([]*mempool.TxDesc, 0, len(feeRates))
+	for i, feeRate := range feeRates {
+		msgTx := wire.NewMsgTx()
+		msgTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, 0, nil))
+		msgTx.AddTxOut(wire.NewTxOut(int64(i+1), []byte{0x51}))
+		tx := dcrutil.NewTx(msgTx)
+		txDescs = append(txDescs, &mempool.TxDesc{TxDesc: mining.TxDesc{
+			Tx:   tx,
+			Type: txTypes[i],
+			Fee:  feeRate * int64(msgTx.SerializeSize()) / 1000,
+		}})
+	}
+	return txDescs
+}
+
+// TestMempoolInvFeeFilter ensures the inventory sent in response to mempool
+// requests omits transactions that pay less than the fee filter of the peer
+// except for votes and revocations.
+func TestMempoolInvFeeFilter(t *testing.T) {
+	t.Parallel()
+
+	txDescs := feeFilterTestTxDescs(
+		[]int64{10000, 1000, 0, 0, 5000},
+		[]stake.TxType{stake.TxTypeRegular, stake.TxTypeRegular,
+			stake.TxTypeSSGen, stake.TxTypeSSRtx, stake.TxTypeRegular})
+	tests := []struct {
+		name      string
+		feeFilter int64
+		want      []int // indices of the expected announced txns
+	}{{
+		name:      "no fee filter",
+		feeFilter: 0,
+		want:      []int{0, 1, 2, 3, 4},
+	}, {
+		name:      "fee filter",
+		feeFilter: 5000,
+		want:      []int{0, 2, 3, 4},
+	}, {
+		name:      "fee filter above all fee rates",
+		feeFilter: 20000,
+		want:      []int{2, 3},
+	}}
+	for _, test := range tests {
+		sp := &serverPeer{filter: bloom.LoadFilter(nil)}
+		sp.setFeeFilter(test.feeFilter)
+		invMsg := sp.mempoolInvMsg(txDescs)
+		if len(invMsg.InvList) != len(test.want) {
+			t.Errorf("%q: unexpected number of inventory vectors -- "+
+				"got %d, want %d", test.name, len(invMsg.InvList),
+				len(test.want))
+			continue
+		}
+		for i, iv := range invMsg.InvList {
+			wantHash := txDescs[test.want[i]].Tx.Hash()
+			if iv.Type != wire.InvTypeTx || iv.Hash != *wantHash {
+				t.Errorf("%q: unexpected inventory vector %d -- got %v, "+
+					"want tx %v", test.name, i, iv, wantHash)
+			}
+		}
+	}
+}
+
+// TestRelayInvFeeFilter ensures transaction inventory is only relayed to peers
+// when it pays at least the fee filter of the peer or is exempt from it.
+func TestRelayInvFeeFilter(t *testing.T) {
+	t.Parallel()
+
+	txDescs := feeFilterTestTxDescs(
+		[]int64{10000, 1000, 0},
+		[]stake.TxType{stake.TxTypeRegular, stake.TxTypeRegular,
+			stake.TxTypeSSGen})
+	tests := []struct {
+		name      string
+		txFeeRate int64
+		feeFilter int64
+		want      bool
+	}{
+		{"high fee no filter", feeFilterTxRate(txDescs[0]), 0, true},
+		{"low fee no filter", feeFilterTxRate(txDescs[1]), 0, true},
+		{"high fee with filter", feeFilterTxRate(txDescs[0]), 5000, true},
+		{"low fee with filter", feeFilterTxRate(txDescs[1]), 5000, false},
+		{"fee equal to filter", 5000, 5000, true},
+		{"exempt vote with filter", feeFilterTxRate(txDescs[2]), 5000, true},
+		{"unknown fee with filter", -1, 5000, true},
+	}
+	for _, test := range tests {
+		sp := &serverPeer{}
+		sp.setFeeFilter(test.feeFilter)
+		iv := wire.NewInvVect(wire.InvTypeTx, &chainhash.Hash{0x01})
+		if got := sp.shouldAnnounceInv(iv, test.txFeeRate); got != test.want {
+			t.Errorf("%q: unexpected announce result -- got %v, want %v",
+				test.name, got, test.want)
+		}
+	}
+}
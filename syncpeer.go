@@ -0,0 +1,286 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"time"
+
+	"github.com/decred/dcrd/internal/rpcserver"
+)
+
+const (
+	// blockStallTimeout is the amount of time a peer with blocks in flight
+	// to it has to deliver the next requested block before it is considered
+	// stalled.
+	blockStallTimeout = 30 * time.Second
+
+	// syncCheckInterval is the interval at which peers are checked for
+	// stalled block downloads and the sync peer is checked for sustained
+	// underperformance.
+	syncCheckInterval = 10 * time.Second
+
+	// minSyncBlocksPerCheck is the minimum number of blocks the sync peer
+	// must deliver during each sync check interval while it has blocks in
+	// flight to it and the chain is not current in order to not be
+	// considered underperforming for that interval.
+	minSyncBlocksPerCheck = 5
+
+	// maxSlowSyncChecks is the number of consecutive sync check intervals
+	// the sync peer may underperform before a different sync peer is chosen.
+	maxSlowSyncChecks = 6
+
+	// maxSyncPeerSwitches is the maximum number of sync peer changes that
+	// are retained.
+	maxSyncPeerSwitches = 50
+
+	// These constants define the reasons the sync peer was changed.
+	syncSwitchInitial         = "initial"
+	syncSwitchDisconnected    = "disconnected"
+	syncSwitchStalled         = "stalled"
+	syncSwitchUnderperforming = "underperforming"
+)
+
+// blockDownloadMonitor tracks the deadline by which a peer must deliver the
+// next block requested from it along with the rate it delivers blocks in order
+// to detect stalled and underperforming peers.
+//
+// It is not safe for concurrent access and is only used from the block
+// handler goroutine.
+type blockDownloadMonitor struct {
+	// deadline is the time by which the peer must deliver the next block
+	// requested from it.  It is zero when there are no blocks in flight to
+	// the peer.
+	deadline time.Time
+
+	// numDelivered is the number of blocks the peer delivered since the
+	// most recent rate check.
+	numDelivered int
+
+	// numSlowChecks is the number of consecutive rate checks the peer
+	// delivered fewer than the minimum required number of blocks.
+	numSlowChecks int
+}
+
+// requested updates the download state when blocks are requested from the
+// peer.  A new deadline is only set when there were no blocks in flight to the
+// peer so that repeated requests do not extend the deadline of a stalled peer.
+func (m *blockDownloadMonitor) requested(now time.Time) {
+	if m.deadline.IsZero() {
+		m.deadline = now.Add(blockStallTimeout)
+	}
+}
+
+// settled updates the deadline when a block requested from the peer is either
+// delivered or the peer reports it does not have it.  The number of blocks
+// still in flight to the peer determines whether a new deadline applies.
+func (m *blockDownloadMonitor) settled(now time.Time, numInFlight int) {
+	if numInFlight == 0 {
+		m.deadline = time.Time{}
+		return
+	}
+	m.deadline = now.Add(blockStallTimeout)
+}
+
+// received updates the download state when the peer delivers a requested
+// block.  The number of blocks still in flight to the peer determines whether
+// a new deadline applies.
+func (m *blockDownloadMonitor) received(now time.Time, numInFlight int) {
+	m.numDelivered++
+	m.settled(now, numInFlight)
+}
+
+// stalled returns whether or not the peer missed the deadline to deliver the
+// next block requested from it.
+func (m *blockDownloadMonitor) stalled(now time.Time) bool {
+	return !m.deadline.IsZero() && now.After(m.deadline)
+}
+
+// underperforming records the number of blocks the peer delivered since the
+// previous check and returns whether or not it has delivered fewer than the
+// minimum required number of blocks for the maximum allowed number of
+// consecutive checks.  The busy flag indicates whether the peer has blocks in
+// flight to it so that the time the peer spent idle is not counted against
+// it.
+func (m *blockDownloadMonitor) underperforming(busy bool) bool {
+	numDelivered := m.numDelivered
+	m.numDelivered = 0
+	if !busy || numDelivered >= minSyncBlocksPerCheck {
+		m.numSlowChecks = 0
+		return false
+	}
+	m.numSlowChecks++
+	return m.numSlowChecks >= maxSlowSyncChecks
+}
+
+// syncPeerHistory tracks the changes to the sync peer along with the reasons
+// they were made.
+//
+// It is not safe for concurrent access and is only used from the block
+// handler goroutine.
+type syncPeerHistory struct {
+	// reason is the reason the previous sync peer was removed.  It applies to
+	// the next selected sync peer.
+	reason string
+
+	// prevID and prevAddr identify the most recently selected sync peer.
+	prevID   int32
+	prevAddr string
+
+	// switches are the most recent sync peer changes ordered from oldest to
+	// newest.
+	switches []rpcserver.SyncPeerSwitch
+}
+
+// removed records the reason the current sync peer was removed so it can be
+// associated with the selection of the next sync peer.
+func (h *syncPeerHistory) removed(reason string) {
+	h.reason = reason
+}
+
+// selected records that the peer with the provided id and address was
+// selected as the sync peer while discarding the oldest change when the
+// maximum number of changes is reached.
+func (h *syncPeerHistory) selected(now time.Time, id int32, addr string) {
+	reason := h.reason
+	if h.prevAddr == "" || reason == "" {
+		reason = syncSwitchInitial
+	}
+	if len(h.switches) == maxSyncPeerSwitches {
+		copy(h.switches, h.switches[1:])
+		h.switches = h.switches[:maxSyncPeerSwitches-1]
+	}
+	h.switches = append(h.switches, rpcserver.SyncPeerSwitch{
+		Time:     now,
+		PrevID:   h.prevID,
+		PrevAddr: h.prevAddr,
+		ID:       id,
+		Addr:     addr,
+		Reason:   reason,
+	})
+	h.reason = ""
+	h.prevID = id
+	h.prevAddr = addr
+}
+
+// checkDownloadStalls examines the block downloads from all peers for missed
+// deadlines and the sync peer for sustained underperformance.
+//
+// The blocks requested from a stalled peer that is not the sync peer are made
+// available to be requested from other peers, while a different sync peer is
+// chosen when the sync peer either stalls or underperforms.
+//
+// This function MUST be called from the block handler goroutine.
+func (b *blockManager) checkDownloadStalls(now time.Time) {
+	for peer, state := range b.peerStates {
+		if peer == b.syncPeer || !state.download.stalled(now) {
+			continue
+		}
+
+		bmgrLog.Debugf("Peer %s stalled with %d blocks in flight", peer,
+			len(state.requestedBlocks))
+		for blockHash := range state.requestedBlocks {
+			delete(b.requestedBlocks, blockHash)
+		}
+		state.download.settled(now, 0)
+	}
+
+	if b.syncPeer == nil {
+		return
+	}
+	state, exists := b.peerStates[b.syncPeer]
+	if !exists {
+		return
+	}
+	switch {
+	case state.download.stalled(now):
+		bmgrLog.Infof("Sync peer %s stalled with %d blocks in flight",
+			b.syncPeer, len(state.requestedBlocks))
+		b.switchSyncPeer(now, syncSwitchStalled)
+
+	case !b.current() && state.download.underperforming(
+		len(state.requestedBlocks) > 0):
+
+		bmgrLog.Infof("Sync peer %s delivered fewer than %d blocks every "+
+			"%v for %v", b.syncPeer, minSyncBlocksPerCheck,
+			syncCheckInterval, maxSlowSyncChecks*syncCheckInterval)
+		b.switchSyncPeer(now, syncSwitchUnderperforming)
+	}
+}
+
+// haveOtherSyncCandidate returns whether or not there is a sync candidate
+// other than the current sync peer that is eligible to be chosen as the sync
+// peer.
+//
+// This function MUST be called from the block handler goroutine.
+func (b *blockManager) haveOtherSyncCandidate() bool {
+	best := b.cfg.Chain.BestSnapshot()
+	for peer, state := range b.peerStates {
+		if peer != b.syncPeer && state.syncCandidate &&
+			peer.LastBlock() >= best.Height {
+
+			return true
+		}
+	}
+	return false
+}
+
+// switchSyncPeer chooses a different sync peer for the provided reason.  The
+// blocks in flight to the current sync peer are made available to be
+// requested from the new sync peer.  The current sync peer is kept when there
+// are no other candidates, in which case it is given a new deadline.
+//
+// This function MUST be called from the block handler goroutine.
+func (b *blockManager) switchSyncPeer(now time.Time, reason string) {
+	prevPeer := b.syncPeer
+	state := b.peerStates[prevPeer]
+	if !b.haveOtherSyncCandidate() {
+		bmgrLog.Warnf("Keeping sync peer %s since there are no other sync "+
+			"peer candidates available", prevPeer)
+		state.download.settled(now, len(state.requestedBlocks))
+		return
+	}
+
+	for blockHash := range state.requestedBlocks {
+		delete(b.requestedBlocks, blockHash)
+	}
+	state.download.settled(now, 0)
+
+	// Temporarily remove the current sync peer as a candidate so a
+	// different peer is chosen.  It remains a candidate for future
+	// selections.  Also, reset the headers-first state if in headers-first
+	// mode so the headers are downloaded from the new sync peer.
+	b.syncPeer = nil
+	b.syncHistory.removed(reason)
+	if b.headerSync.Active() {
+		best := b.cfg.Chain.BestSnapshot()
+		b.resetHeaderState(&best.Hash, best.Height)
+	}
+	state.syncCandidate = false
+	b.startSync()
+	state.syncCandidate = b.isSyncCandidate(prevPeer)
+}
+
+// syncPeerInfo returns details about the current sync peer along with the
+// most recent changes to it.
+//
+// This function MUST be called from the block handler goroutine.
+func (b *blockManager) syncPeerInfo() *rpcserver.SyncPeerInfo {
+	info := &rpcserver.SyncPeerInfo{
+		SyncHeight: b.SyncHeight(),
+		Switches: make([]rpcserver.SyncPeerSwitch,
+			len(b.syncHistory.switches)),
+	}
+	copy(info.Switches, b.syncHistory.switches)
+	if b.syncPeer == nil {
+		return info
+	}
+	info.ID = b.syncPeer.ID()
+	info.Addr = b.syncPeer.Addr()
+	if state, exists := b.peerStates[b.syncPeer]; exists {
+		info.BlocksInFlight = len(state.requestedBlocks)
+		info.Deadline = state.download.deadline
+	}
+	return info
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// TestBlockDownloadMonitor ensures the block download deadline of a peer is set,
+// extended, and cleared as blocks are requested and delivered and that stalls
+// are detected once it passes.
+func TestBlockDownloadMonitor(t *testing.T) {
+	t.Parallel()
+
+	var m blockDownloadMonitor
+	now := time.Unix(1592918788, 0)
+	if m.stalled(now.Add(time.Hour)) {
+		t.Fatal("peer without blocks in flight is stalled")
+	}
+
+	// Ensure additional requests do not extend the deadline.
+	m.requested(now)
+	m.requested(now.Add(blockStallTimeout / 2))
+	if want := now.Add(blockStallTimeout); !m.deadline.Equal(want) {
+		t.Fatalf("unexpected deadline -- got %v, want %v", m.deadline, want)
+	}
+	if m.stalled(now.Add(blockStallTimeout)) {
+		t.Fatal("peer is stalled at the deadline")
+	}
+	if !m.stalled(now.Add(blockStallTimeout + time.Second)) {
+		t.Fatal("peer is not stalled after the deadline")
+	}
+
+	// Ensure delivering a block extends the deadline when more blocks are in
+	// flight and clears it otherwise.
+	now = now.Add(blockStallTimeout / 2)
+	m.received(now, 1)
+	if m.stalled(now.Add(blockStallTimeout)) {
+		t.Fatal("peer is stalled before the extended deadline")
+	}
+	m.received(now, 0)
+	if !m.deadline.IsZero() || m.stalled(now.Add(time.Hour)) {
+		t.Fatalf("deadline was not cleared -- got %v", m.deadline)
+	}
+}
+
+// TestBlockDownloadUnderperforming ensures a peer is only considered
+// underperforming once it delivers fewer than the minimum required number of
+// blocks for the maximum allowed number of consecutive checks while it has
+// blocks in flight.
+func TestBlockDownloadUnderperforming(t *testing.T) {
+	t.Parallel()
+
+	var m blockDownloadMonitor
+	now := time.Unix(1592918788, 0)
+	deliver := func(n int) {
+		for i := 0; i < n; i++ {
+			m.received(now, 1)
+		}
+	}
+
+	// Ensure a fast check and an idle check both reset the number of slow
+	// checks.
+	for i := 0; i < maxSlowSyncChecks-1; i++ {
+		if m.underperforming(true) {
+			t.Fatalf("underperforming after %d slow checks", i+1)
+		}
+	}
+	deliver(minSyncBlocksPerCheck)
+	if m.underperforming(true) {
+		t.Fatal("underperforming after a fast check")
+	}
+	for i := 0; i < maxSlowSyncChecks-1; i++ {
+		m.underperforming(true)
+	}
+	if m.underperforming(false) {
+		t.Fatal("underperforming after an idle check")
+	}
+
+	// Ensure the peer is underperforming after the maximum allowed number of
+	// consecutive slow checks.
+	for i := 0; i < maxSlowSyncChecks-1; i++ {
+		deliver(minSyncBlocksPerCheck - 1)
+		if m.underperforming(true) {
+			t.Fatalf("underperforming after %d slow checks", i+1)
+		}
+	}
+	deliver(minSyncBlocksPerCheck - 1)
+	if !m.underperforming(true) {
+		t.Fatalf("not underperforming after %d slow checks",
+			maxSlowSyncChecks)
+	}
+}
+
+// TestSyncPeerHistory ensures the changes to the sync peer are recorded with
+// the expected previous peers and reasons and that the oldest changes are
+// discarded once the maximum number of changes is reached.
+func TestSyncPeerHistory(t *testing.T) {
+	t.Parallel()
+
+	var h syncPeerHistory
+	now := time.Unix(1592918788, 0)
+	h.selected(now, 1, "10.0.0.1:9108")
+	h.removed(syncSwitchStalled)
+	h.selected(now.Add(time.Second), 2, "10.0.0.2:9108")
+	h.removed(syncSwitchDisconnected)
+	h.selected(now.Add(2*time.Second), 3, "10.0.0.3:9108")
+
+	if len(h.switches) != 3 {
+		t.Fatalf("unexpected number of switches -- got %d, want 3",
+			len(h.switches))
+	}
+	wantReasons := []string{syncSwitchInitial, syncSwitchStalled,
+		syncSwitchDisconnected}
+	for i, sw := range h.switches {
+		if sw.Reason != wantReasons[i] {
+			t.Fatalf("unexpected reason for switch %d -- got %q, want %q",
+				i, sw.Reason, wantReasons[i])
+		}
+		if sw.ID != int32(i+1) || sw.PrevID != int32(i) {
+			t.Fatalf("unexpected ids for switch %d -- got %d from %d, "+
+				"want %d from %d", i, sw.ID, sw.PrevID, i+1, i)
+		}
+	}
+	if h.switches[1].PrevAddr != "10.0.0.1:9108" {
+		t.Fatalf("unexpected previous address -- got %q, want %q",
+			h.switches[1].PrevAddr, "10.0.0.1:9108")
+	}
+
+	// Ensure the oldest changes are discarded once the limit is reached.
+	for i := 0; i < maxSyncPeerSwitches; i++ {
+		h.removed(syncSwitchUnderperforming)
+		addr := fmt.Sprintf("10.0.1.%d:9108", i)
+		h.selected(now.Add(time.Minute), int32(i+4), addr)
+	}
+	if len(h.switches) != maxSyncPeerSwitches {
+		t.Fatalf("unexpected number of switches -- got %d, want %d",
+			len(h.switches), maxSyncPeerSwitches)
+	}
+	if first := h.switches[0]; first.ID != 4 || first.PrevID != 3 ||
+		first.Reason != syncSwitchUnderperforming {
+
+		t.Fatalf("unexpected oldest switch -- got %+v", first)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"net"
+	"time"
+)
+
+// tcpInfoSampleInterval is the interval at which the transport statistics of
+// the TCP socket of each peer connection are sampled.
+const tcpInfoSampleInterval = time.Second * 30
+
+// errTCPInfoUnsupported is returned when sampling the transport statistics of a
+// connection is not supported on the current platform or for the type of the
+// connection, such as connections made through a proxy.
+var errTCPInfoUnsupported = errors.New("sampling tcp info is not supported " +
+	"for the connection")
+
+// sampleTCPInfo samples the transport statistics of the provided connection and
+// records them as the most recent statistics of the peer.  It returns false
+// when sampling is not supported for the connection.
+func (sp *serverPeer) sampleTCPInfo(conn net.Conn) bool {
+	info, err := readTCPInfo(conn)
+	if err != nil {
+		if errors.Is(err, errTCPInfoUnsupported) {
+			return false
+		}
+		peerLog.Tracef("Unable to sample tcp info of %v: %v", sp, err)
+		return true
+	}
+	info.SampleTime = time.Now()
+
+	sp.tcpInfoMtx.Lock()
+	sp.tcpInfo = info
+	sp.tcpInfoMtx.Unlock()
+	return true
+}
+
+// tcpInfoHandler periodically samples the transport statistics of the provided
+// connection of the peer so they are available to the getpeerinfo RPC, which
+// helps diagnose slow block propagation.  It stops immediately when sampling is
+// not supported for the connection.
+//
+// This must be run as a goroutine.
+func (sp *serverPeer) tcpInfoHandler(conn net.Conn) {
+	if !sp.sampleTCPInfo(conn) {
+		return
+	}
+
+	ticker := time.NewTicker(tcpInfoSampleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			sp.sampleTCPInfo(conn)
+
+		case <-sp.quit:
+			return
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// +build linux,!386
+
+package main
+
+import (
+	"net"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/decred/dcrd/internal/rpcserver"
+)
+
+// readTCPInfo returns the transport statistics the kernel maintains for the TCP
+// socket of the provided connection.
+func readTCPInfo(conn net.Conn) (*rpcserver.PeerTCPInfo, error) {
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return nil, errTCPInfoUnsupported
+	}
+	rawConn, err := sc.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+
+	var info syscall.TCPInfo
+	var sockErr error
+	err = rawConn.Control(func(fd uintptr) {
+		size := uint32(unsafe.Sizeof(info))
+		_, _, errno := syscall.Syscall6(syscall.SYS_GETSOCKOPT, fd,
+			syscall.IPPROTO_TCP, syscall.TCP_INFO,
+			uintptr(unsafe.Pointer(&info)), uintptr(unsafe.Pointer(&size)), 0)
+		if errno != 0 {
+			sockErr = errno
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if sockErr != nil {
+		return nil, sockErr
+	}
+
+	return &rpcserver.PeerTCPInfo{
+		RTT:          time.Duration(info.Rtt) * time.Microsecond,
+		RTTVar:       time.Duration(info.Rttvar) * time.Microsecond,
+		Retransmits:  uint32(info.Retransmits),
+		TotalRetrans: info.Total_retrans,
+		SndCwnd:      info.Snd_cwnd,
+	}, nil
+}
@@ -0,0 +1,19 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// +build !linux 386
+
+package main
+
+import (
+	"net"
+
+	"github.com/decred/dcrd/internal/rpcserver"
+)
+
+// readTCPInfo returns errTCPInfoUnsupported since sampling the transport
+// statistics of connections is not supported on this platform.
+func readTCPInfo(conn net.Conn) (*rpcserver.PeerTCPInfo, error) {
+	return nil, errTCPInfoUnsupported
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"net"
+	"testing"
+)
+
+// TestReadTCPInfo ensures the transport statistics of TCP connections can be
+// sampled on supported platforms and that connections without an underlying
+// socket are reported as unsupported.
+func TestReadTCPInfo(t *testing.T) {
+	t.Parallel()
+
+	pipeConn, pipeConn2 := net.Pipe()
+	defer pipeConn.Close()
+	defer pipeConn2.Close()
+	if _, err := readTCPInfo(pipeConn); !errors.Is(err, errTCPInfoUnsupported) {
+		t.Fatalf("unexpected error for pipe -- got %v, want %v", err,
+			errTCPInfoUnsupported)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+	defer listener.Close()
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("unable to dial: %v", err)
+	}
+	defer conn.Close()
+
+	info, err := readTCPInfo(conn)
+	if errors.Is(err, errTCPInfoUnsupported) {
+		t.Skip("sampling tcp info is not supported on this platform")
+	}
+	if err != nil {
+		t.Fatalf("unexpected error sampling tcp info: %v", err)
+	}
+	if info.SndCwnd == 0 {
+		t.Fatalf("unexpected empty congestion window in %+v", info)
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/decred/dcrd/blockchain/v3"
+	"github.com/decred/dcrd/internal/mempool"
+)
+
+const (
+	// txBudgetHalfLife is the time it takes the usage of the transaction
+	// acceptance budgets of a peer to decay to half of its value.
+	txBudgetHalfLife = time.Minute
+
+	// txBudgetOrphans is the number of orphan transactions a peer may submit
+	// before exceeding its orphan submission budget.
+	txBudgetOrphans = 50
+
+	// txBudgetInvalid is the number of invalid transactions a peer may submit
+	// before exceeding its invalid transaction budget.
+	txBudgetInvalid = 10
+
+	// txBudgetValidation is the amount of time that may be spent validating
+	// transactions submitted by a peer before it exceeds its validation time
+	// budget.
+	txBudgetValidation = 2 * time.Second
+
+	// txBudgetBasePenalty is the transient ban score a peer incurs the first
+	// time it submits a transaction while over one of its budgets.  The
+	// penalty doubles for every consecutive submission made while over the
+	// same budget up to txBudgetMaxPenalty.
+	txBudgetBasePenalty = 5
+
+	// txBudgetMaxPenalty is the maximum transient ban score a peer incurs
+	// for a single submission made while over one of its budgets.
+	txBudgetMaxPenalty = 160
+)
+
+// txBudgetKind identifies one of the transaction acceptance budgets of a peer.
+type txBudgetKind int
+
+// These constants define the kinds of transaction acceptance budgets.
+const (
+	txBudgetKindOrphans txBudgetKind = iota
+	txBudgetKindInvalid
+	txBudgetKindValidation
+	numTxBudgetKinds
+)
+
+// txBudgetLimits houses the limit of each kind of transaction acceptance
+// budget.  The validation time budget is in seconds.
+var txBudgetLimits = [numTxBudgetKinds]float64{
+	txBudgetKindOrphans:    txBudgetOrphans,
+	txBudgetKindInvalid:    txBudgetInvalid,
+	txBudgetKindValidation: txBudgetValidation.Seconds(),
+}
+
+// txBudgetDescs houses a description of each kind of transaction acceptance
+// budget for use in ban score reasons.
+var txBudgetDescs = [numTxBudgetKinds]string{
+	txBudgetKindOrphans:    "orphan submission",
+	txBudgetKindInvalid:    "invalid transaction",
+	txBudgetKindValidation: "validation time",
+}
+
+// txAcceptOutcome describes the outcome of processing a transaction submitted
+// by a peer in terms of the resources it consumed.
+type txAcceptOutcome struct {
+	// orphan indicates the transaction was an orphan.
+	orphan bool
+
+	// invalid indicates the transaction was rejected for a reason honest
+	// peers would not have relayed it for.
+	invalid bool
+
+	// validationTime is the amount of time spent processing the transaction.
+	validationTime time.Duration
+}
+
+// contextFreeTxErrors houses the blockchain rule error codes a transaction can
+// be rejected with that do not depend on the state of the chain, namely those
+// of the transaction sanity checks and script validation.  Other errors, such
+// as those for spending immature outputs or votes for blocks that are no
+// longer the tip, depend on the current tip and are therefore routinely
+// encountered by honest peers racing new blocks.
+var contextFreeTxErrors = map[blockchain.ErrorCode]struct{}{
+	blockchain.ErrNoTxInputs:            {},
+	blockchain.ErrNoTxOutputs:           {},
+	blockchain.ErrTxTooBig:              {},
+	blockchain.ErrBadTxOutValue:         {},
+	blockchain.ErrBadTxInput:            {},
+	blockchain.ErrDuplicateTxInputs:     {},
+	blockchain.ErrBadCoinbaseFraudProof: {},
+	blockchain.ErrBadCoinbaseOutpoint:   {},
+	blockchain.ErrBadCoinbaseScriptLen:  {},
+	blockchain.ErrBadStakebaseScrVal:    {},
+	blockchain.ErrBadStakebaseScriptLen: {},
+	blockchain.ErrRegTxCreateStakeOut:   {},
+	blockchain.ErrScriptMalformed:       {},
+	blockchain.ErrScriptValidation:      {},
+}
+
+// isInvalidTxAttempt returns whether or not the provided error from processing
+// a transaction indicates the transaction is invalid in a way that honest peers
+// would not relay it, as opposed to being rejected due to races with new
+// blocks or differences in local policy.
+func isInvalidTxAttempt(err error) bool {
+	var rErr mempool.RuleError
+	if !errors.As(err, &rErr) {
+		return false
+	}
+	var cErr blockchain.RuleError
+	if errors.As(rErr.Err, &cErr) {
+		_, ok := contextFreeTxErrors[cErr.ErrorCode]
+		return ok
+	}
+	return mempool.IsErrorCode(err, mempool.ErrInvalid) ||
+		mempool.IsErrorCode(err, mempool.ErrCoinbase)
+}
+
+// txAcceptanceBudget tracks how much of each of its transaction acceptance
+// budgets a peer has used.  The usage decays exponentially over time so that
+// peers are only penalized for sustained abuse as opposed to occasional bursts.
+//
+// Peers that submit transactions while over a budget incur a transient ban
+// score penalty that grows exponentially with every consecutive submission
+// made while over that budget, which quickly leads to the peer being banned
+// when the abuse continues.
+//
+// It is not safe for concurrent access.
+type txAcceptanceBudget struct {
+	lastDecay time.Time
+	usage     [numTxBudgetKinds]float64
+	overruns  [numTxBudgetKinds]uint32
+}
+
+// decay decays the usage of all budgets to the provided time.
+func (b *txAcceptanceBudget) decay(now time.Time) {
+	if b.lastDecay.IsZero() {
+		b.lastDecay = now
+		return
+	}
+	elapsed := now.Sub(b.lastDecay)
+	if elapsed <= 0 {
+		return
+	}
+	factor := math.Exp2(-elapsed.Seconds() / txBudgetHalfLife.Seconds())
+	for i := range b.usage {
+		b.usage[i] *= factor
+	}
+	b.lastDecay = now
+}
+
+// record records the outcome of processing a transaction submitted by the peer
+// at the provided time.  It returns the transient ban score penalty the peer
+// incurs for the submission along with the reason for it.  A penalty of zero
+// is returned when the peer is within all of its budgets.
+func (b *txAcceptanceBudget) record(outcome *txAcceptOutcome, now time.Time) (uint32, string) {
+	b.decay(now)
+	if outcome.orphan {
+		b.usage[txBudgetKindOrphans]++
+	}
+	if outcome.invalid {
+		b.usage[txBudgetKindInvalid]++
+	}
+	b.usage[txBudgetKindValidation] += outcome.validationTime.Seconds()
+
+	var penalty uint32
+	var exceeded []string
+	for kind := range b.usage {
+		if b.usage[kind] <= txBudgetLimits[kind] {
+			b.overruns[kind] = 0
+			continue
+		}
+
+		// Double the penalty for every consecutive submission made while
+		// over the budget.
+		kindPenalty := uint32(txBudgetMaxPenalty)
+		if b.overruns[kind] < 32 {
+			shifted := uint64(txBudgetBasePenalty) << b.overruns[kind]
+			if shifted < txBudgetMaxPenalty {
+				kindPenalty = uint32(shifted)
+			}
+			b.overruns[kind]++
+		}
+		penalty += kindPenalty
+		exceeded = append(exceeded, txBudgetDescs[kind])
+	}
+	if penalty == 0 {
+		return 0, ""
+	}
+
+	budgetStr := pickNoun(uint64(len(exceeded)), "budget", "budgets")
+	reason := fmt.Sprintf("exceeded %s %s", strings.Join(exceeded, " and "),
+		budgetStr)
+	return penalty, reason
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/blockchain/v3"
+	"github.com/decred/dcrd/internal/mempool"
+)
+
+// TestTxAcceptanceBudget ensures peers are only penalized once they exceed
+// their transaction acceptance budgets, that the penalties grow exponentially
+// for consecutive submissions made while over a budget, and that usage decays
+// over time.
+func TestTxAcceptanceBudget(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1592918788, 0)
+	invalid := &txAcceptOutcome{invalid: true}
+
+	// Ensure no penalty is incurred while within the budget.
+	var budget txAcceptanceBudget
+	for i := 0; i < txBudgetInvalid; i++ {
+		penalty, reason := budget.record(invalid, now)
+		if penalty != 0 {
+			t.Fatalf("unexpected penalty %d (%s) for submission %d within "+
+				"budget", penalty, reason, i)
+		}
+	}
+
+	// Ensure the penalty doubles for every consecutive submission made while
+	// over the budget up to the max penalty.
+	wantPenalties := []uint32{5, 10, 20, 40, 80, 160, 160}
+	for i, want := range wantPenalties {
+		penalty, reason := budget.record(invalid, now)
+		if penalty != want {
+			t.Fatalf("unexpected penalty for submission %d over budget -- "+
+				"got %d, want %d", i, penalty, want)
+		}
+		if want := "exceeded invalid transaction budget"; reason != want {
+			t.Fatalf("unexpected reason -- got %q, want %q", reason, want)
+		}
+	}
+
+	// Ensure usage decays to a quarter after two half lives, so the peer is
+	// within the budget again for another 5 submissions (17 / 4 + 5 = 9.25),
+	// and the penalty starts over once the budget is exceeded again.
+	now = now.Add(2 * txBudgetHalfLife)
+	for i := 0; i < 5; i++ {
+		penalty, _ := budget.record(invalid, now)
+		if penalty != 0 {
+			t.Fatalf("unexpected penalty %d for submission %d after decay",
+				penalty, i)
+		}
+	}
+	if penalty, _ := budget.record(invalid, now); penalty != 5 {
+		t.Fatalf("unexpected penalty after decay -- got %d, want 5", penalty)
+	}
+
+	// Ensure submissions that are neither orphans nor invalid are only
+	// charged for the time spent validating them and exceeding multiple
+	// budgets at once combines the penalties.
+	budget = txAcceptanceBudget{}
+	penalty, _ := budget.record(&txAcceptOutcome{
+		validationTime: txBudgetValidation,
+	}, now)
+	if penalty != 0 {
+		t.Fatalf("unexpected penalty %d for validation time within budget",
+			penalty)
+	}
+	for i := 0; i < txBudgetOrphans; i++ {
+		penalty, _ := budget.record(&txAcceptOutcome{orphan: true}, now)
+		if penalty != 0 {
+			t.Fatalf("unexpected penalty %d for orphan %d within budget",
+				penalty, i)
+		}
+	}
+	penalty, reason := budget.record(&txAcceptOutcome{
+		orphan:         true,
+		validationTime: time.Millisecond,
+	}, now)
+	if penalty != 10 {
+		t.Fatalf("unexpected penalty exceeding multiple budgets -- got %d, "+
+			"want 10", penalty)
+	}
+	want := "exceeded orphan submission and validation time budgets"
+	if reason != want {
+		t.Fatalf("unexpected reason -- got %q, want %q", reason, want)
+	}
+}
+
+// TestIsInvalidTxAttempt ensures only transaction rejections that do not depend
+// on the state of the chain or local policy are considered invalid submissions
+// so honest peers racing new blocks are not penalized.
+func TestIsInvalidTxAttempt(t *testing.T) {
+	t.Parallel()
+
+	chainRuleError := func(code blockchain.ErrorCode) error {
+		return mempool.RuleError{
+			Err: blockchain.RuleError{ErrorCode: code, Description: "rejected"},
+		}
+	}
+	txRuleError := func(code mempool.ErrorCode) error {
+		return mempool.RuleError{
+			Err: mempool.TxRuleError{ErrorCode: code, Description: "rejected"},
+		}
+	}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{{
+		name: "no inputs",
+		err:  chainRuleError(blockchain.ErrNoTxInputs),
+		want: true,
+	}, {
+		name: "duplicate inputs",
+		err:  chainRuleError(blockchain.ErrDuplicateTxInputs),
+		want: true,
+	}, {
+		name: "script validation failure",
+		err:  chainRuleError(blockchain.ErrScriptValidation),
+		want: true,
+	}, {
+		name: "immature spend depends on tip",
+		err:  chainRuleError(blockchain.ErrImmatureSpend),
+		want: false,
+	}, {
+		name: "missing output depends on tip",
+		err:  chainRuleError(blockchain.ErrMissingTxOut),
+		want: false,
+	}, {
+		name: "unfinalized depends on tip",
+		err:  chainRuleError(blockchain.ErrUnfinalizedTx),
+		want: false,
+	}, {
+		name: "ticket availability depends on tip",
+		err:  chainRuleError(blockchain.ErrTicketUnavailable),
+		want: false,
+	}, {
+		name: "mempool invalid",
+		err:  txRuleError(mempool.ErrInvalid),
+		want: true,
+	}, {
+		name: "insufficient fee policy",
+		err:  txRuleError(mempool.ErrInsufficientFee),
+		want: false,
+	}, {
+		name: "not a rule error",
+		err:  errors.New("database failure"),
+		want: false,
+	}}
+	for _, test := range tests {
+		if got := isInvalidTxAttempt(test.err); got != test.want {
+			t.Errorf("%q: unexpected result -- got %v, want %v", test.name,
+				got, test.want)
+		}
+	}
+}
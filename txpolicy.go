@@ -0,0 +1,172 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/blockchain/stake/v3"
+	"github.com/decred/dcrd/dcrutil/v3"
+)
+
+// maxTxPolicyResponseSize is the maximum number of bytes of a response from
+// the external transaction policy service that are read.
+const maxTxPolicyResponseSize = 4096
+
+// txPolicyRequest is the request sent to the external transaction policy
+// service for every new transaction that is otherwise acceptable to the
+// mempool.
+type txPolicyRequest struct {
+	TxID string `json:"txid"`
+	Type string `json:"type"`
+	Fee  int64  `json:"fee"`
+	Size int    `json:"size"`
+	Hex  string `json:"hex"`
+}
+
+// txPolicyResponse is the response of the external transaction policy service.
+// The reason is optional and only used when the transaction is not accepted.
+type txPolicyResponse struct {
+	Accept bool   `json:"accept"`
+	Reason string `json:"reason"`
+}
+
+// txPolicyTypeString returns the name of the provided transaction type as it
+// is sent to the external transaction policy service.
+func txPolicyTypeString(txType stake.TxType) string {
+	switch txType {
+	case stake.TxTypeSStx:
+		return "ticket"
+	case stake.TxTypeSSGen:
+		return "vote"
+	case stake.TxTypeSSRtx:
+		return "revocation"
+	}
+	return "regular"
+}
+
+// externalTxPolicy consults an operator-provided HTTP service, such as an
+// anti-spam scoring service, about whether or not the mempool should accept
+// new transactions.
+//
+// It fails open: transactions are accepted whenever the service does not
+// provide a verdict within the configured timeout, responds with a non-2xx
+// status code, or responds with a malformed verdict.
+type externalTxPolicy struct {
+	url     string
+	timeout time.Duration
+
+	// post delivers the provided request to the service and returns its
+	// verdict.  It is a field so it can be mocked by tests.
+	post func(ctx context.Context, req *txPolicyRequest) (*txPolicyResponse, error)
+
+	mtx         sync.Mutex
+	unavailable bool
+}
+
+// newExternalTxPolicy returns an external transaction policy that consults the
+// service at the provided HTTP or HTTPS URL and waits up to the provided
+// timeout for each verdict.
+func newExternalTxPolicy(url string, timeout time.Duration) *externalTxPolicy {
+	p := &externalTxPolicy{url: url, timeout: timeout}
+	p.post = p.postRequest
+	return p
+}
+
+// postRequest delivers the provided request to the service via an HTTP POST
+// request and returns its verdict.
+func (p *externalTxPolicy) postRequest(ctx context.Context, req *txPolicyRequest) (*txPolicyResponse, error) {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url,
+		bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("service responded with status %q",
+			resp.Status)
+	}
+	var verdict txPolicyResponse
+	body := io.LimitReader(resp.Body, maxTxPolicyResponseSize)
+	if err := json.NewDecoder(body).Decode(&verdict); err != nil {
+		return nil, fmt.Errorf("malformed verdict: %v", err)
+	}
+	return &verdict, nil
+}
+
+// setAvailable records whether or not the service provided the most recent
+// verdict and logs transitions between the two states so failures are visible
+// without logging every transaction that is accepted due to them.
+func (p *externalTxPolicy) setAvailable(available bool, err error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	switch {
+	case !available && !p.unavailable:
+		txmpLog.Warnf("External transaction policy is unavailable, "+
+			"accepting transactions without it: %v", err)
+	case available && p.unavailable:
+		txmpLog.Infof("External transaction policy is available again")
+	}
+	p.unavailable = !available
+}
+
+// Check consults the service about whether or not to accept the provided
+// transaction of the provided type that pays the provided fee.  It returns an
+// error with the reason provided by the service when the service rejects the
+// transaction and nil otherwise.
+//
+// This implements the ExternalPolicy function of the mempool policy and is safe
+// for concurrent access.
+func (p *externalTxPolicy) Check(tx *dcrutil.Tx, txType stake.TxType, fee int64) error {
+	txBytes, err := tx.MsgTx().Bytes()
+	if err != nil {
+		return nil
+	}
+	req := &txPolicyRequest{
+		TxID: tx.Hash().String(),
+		Type: txPolicyTypeString(txType),
+		Fee:  fee,
+		Size: len(txBytes),
+		Hex:  hex.EncodeToString(txBytes),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+	verdict, err := p.post(ctx, req)
+	if err != nil {
+		txmpLog.Debugf("Accepting transaction %v without a verdict from the "+
+			"external transaction policy: %v", req.TxID, err)
+		p.setAvailable(false, err)
+		return nil
+	}
+	p.setAvailable(true, nil)
+
+	if !verdict.Accept {
+		reason := verdict.Reason
+		if reason == "" {
+			reason = "no reason provided"
+		}
+		return errors.New(reason)
+	}
+	return nil
+}
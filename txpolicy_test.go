@@ -0,0 +1,91 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/blockchain/stake/v3"
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/decred/dcrd/wire"
+)
+
+// TestExternalTxPolicy ensures transactions are only rejected when the external
+// transaction policy service rejects them and that they are accepted when the
+// service fails to provide a verdict in time.
+func TestExternalTxPolicy(t *testing.T) {
+	t.Parallel()
+
+	tx := dcrutil.NewTx(wire.NewMsgTx())
+	tx.MsgTx().AddTxOut(wire.NewTxOut(1, []byte{0x51}))
+
+	reqs := make(chan txPolicyRequest, 1)
+	handlers := make(chan func(w http.ResponseWriter), 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req txPolicyRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		reqs <- req
+		handler := <-handlers
+		handler(w)
+	}))
+	defer srv.Close()
+	policy := newExternalTxPolicy(srv.URL, time.Millisecond*100)
+
+	tests := []struct {
+		name    string
+		handler func(w http.ResponseWriter)
+		reject  bool
+	}{{
+		name: "accepted",
+		handler: func(w http.ResponseWriter) {
+			w.Write([]byte(`{"accept":true}`))
+		},
+	}, {
+		name: "rejected",
+		handler: func(w http.ResponseWriter) {
+			w.Write([]byte(`{"accept":false,"reason":"spam"}`))
+		},
+		reject: true,
+	}, {
+		name: "error status",
+		handler: func(w http.ResponseWriter) {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"accept":false}`))
+		},
+	}, {
+		name: "malformed verdict",
+		handler: func(w http.ResponseWriter) {
+			w.Write([]byte(`accept`))
+		},
+	}, {
+		name: "timeout",
+		handler: func(w http.ResponseWriter) {
+			time.Sleep(time.Millisecond * 500)
+			w.Write([]byte(`{"accept":false}`))
+		},
+	}}
+
+	for _, test := range tests {
+		handlers <- test.handler
+		err := policy.Check(tx, stake.TxTypeSStx, 2500)
+		if test.reject != (err != nil) {
+			t.Fatalf("%q: unexpected result -- got %v, want reject %v",
+				test.name, err, test.reject)
+		}
+		if test.reject && err.Error() != "spam" {
+			t.Fatalf("%q: unexpected reason %q", test.name, err)
+		}
+		gotReq := <-reqs
+		if gotReq.TxID != tx.Hash().String() || gotReq.Type != "ticket" ||
+			gotReq.Fee != 2500 || gotReq.Size != tx.MsgTx().SerializeSize() {
+
+			t.Fatalf("%q: unexpected request %+v", test.name, gotReq)
+		}
+	}
+}
@@ -2878,7 +2878,21 @@ func opcodeCheckSigAlt(op *opcode, data []byte, vm *Engine) error {
 			vm.dstack.PushBool(false)
 			return nil
 		}
+
+		// Schnorr signatures are only cached when the signature cache
+		// is shared with other processes.
+		var sigHash chainhash.Hash
+		copy(sigHash[:], hash)
+		if vm.sigCache != nil &&
+			vm.sigCache.existsSchnorr(&sigHash, sigBytes, pkBytes) {
+
+			vm.dstack.PushBool(true)
+			return nil
+		}
 		ok := sigSec.Verify(hash, pubKeySec)
+		if ok && vm.sigCache != nil {
+			vm.sigCache.addSchnorr(&sigHash, sigBytes, pkBytes)
+		}
 		vm.dstack.PushBool(ok)
 		return nil
 	}
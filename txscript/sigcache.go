@@ -34,10 +34,14 @@ type sigCacheEntry struct {
 // Secondly, usage of the SigCache introduces a signature verification
 // optimization which speeds up the validation of transactions within a block,
 // if they've already been seen and verified within the mempool.
+//
+// A SigCache created with NewSharedSigCache additionally shares the valid
+// signatures with other processes on the same host via a shared memory segment.
 type SigCache struct {
 	sync.RWMutex
 	validSigs  map[chainhash.Hash]sigCacheEntry
 	maxEntries uint
+	shared     *sharedSigCache
 }
 
 // NewSigCache creates and initializes a new instance of SigCache. Its sole
@@ -52,6 +56,66 @@ func NewSigCache(maxEntries uint) *SigCache {
 	}
 }
 
+// NewSharedSigCache creates and initializes a new instance of SigCache that
+// also shares the valid signatures with other processes on the same host, such
+// as a full node and a mining proxy, which avoids duplicate signature
+// verification work.  The signatures are shared via a memory segment backed by
+// the file at the provided path, which should typically be in a memory-backed
+// file system such as /dev/shm.
+//
+// A new segment with room for 'maxEntries' signatures is created when the file
+// does not exist or is empty.  Otherwise, the existing segment is used so long
+// as its layout version is SharedSigCacheVersion.  The 'maxEntries' parameter
+// also limits the number of entries in the cache local to the process as
+// described by NewSigCache.
+//
+// Note that both ECDSA and Schnorr signatures are shared, while only ECDSA
+// signatures are cached locally.
+//
+// All processes that share the segment are trusted since any of them is able
+// to make invalid signatures appear valid to the others.  For that reason, an
+// existing file is refused unless it is a regular file owned by the current
+// user that is not writable by the group or other users, and the file is never
+// accessed through a symbolic link.  The path should be in a location that
+// other users are not able to replace.
+//
+// Shared memory segments are only supported on unix platforms.
+func NewSharedSigCache(maxEntries uint, path string) (*SigCache, error) {
+	shared, err := openSharedSigCache(path, maxEntries)
+	if err != nil {
+		return nil, err
+	}
+	sigCache := NewSigCache(maxEntries)
+	sigCache.shared = shared
+	return sigCache, nil
+}
+
+// Close releases the shared memory segment of a SigCache created with
+// NewSharedSigCache.  The cache continues to work locally after it is closed.
+// It has no effect on other caches.
+//
+// NOTE: This function is safe for concurrent access.
+func (s *SigCache) Close() error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.shared == nil {
+		return nil
+	}
+	err := s.shared.close()
+	s.shared = nil
+	return err
+}
+
+// ecdsaDigest returns the digest of the provided ECDSA signature, hash, and
+// public key in the shared memory segment.
+//
+// This function MUST be called with the shared memory segment available.
+func (s *SigCache) ecdsaDigest(sigHash *chainhash.Hash, sig *ecdsa.Signature, pubKey *secp256k1.PublicKey) chainhash.Hash {
+	return s.shared.digest(sigTypeECDSA, sigHash, sig.Serialize(),
+		pubKey.SerializeCompressed())
+}
+
 // Exists returns true if an existing entry of 'sig' over 'sigHash' for public
 // key 'pubKey' is found within the SigCache. Otherwise, false is returned.
 //
@@ -59,10 +123,20 @@ func NewSigCache(maxEntries uint) *SigCache {
 // unless there exists a writer, adding an entry to the SigCache.
 func (s *SigCache) Exists(sigHash chainhash.Hash, sig *ecdsa.Signature, pubKey *secp256k1.PublicKey) bool {
 	s.RLock()
+	defer s.RUnlock()
+
 	entry, ok := s.validSigs[sigHash]
-	s.RUnlock()
+	if ok && entry.pubKey.IsEqual(pubKey) && entry.sig.IsEqual(sig) {
+		return true
+	}
 
-	return ok && entry.pubKey.IsEqual(pubKey) && entry.sig.IsEqual(sig)
+	// Fall back to the signatures verified by other processes when the
+	// cache is shared.
+	if s.shared != nil {
+		digest := s.ecdsaDigest(&sigHash, sig, pubKey)
+		return s.shared.exists(&digest)
+	}
+	return false
 }
 
 // Add adds an entry for a signature over 'sigHash' under public key 'pubKey'
@@ -76,6 +150,11 @@ func (s *SigCache) Add(sigHash chainhash.Hash, sig *ecdsa.Signature, pubKey *sec
 	s.Lock()
 	defer s.Unlock()
 
+	if s.shared != nil {
+		digest := s.ecdsaDigest(&sigHash, sig, pubKey)
+		s.shared.add(&digest)
+	}
+
 	if s.maxEntries == 0 {
 		return
 	}
@@ -99,3 +178,36 @@ func (s *SigCache) Add(sigHash chainhash.Hash, sig *ecdsa.Signature, pubKey *sec
 	}
 	s.validSigs[sigHash] = sigCacheEntry{sig, pubKey}
 }
+
+// existsSchnorr returns true if an existing entry of the serialized Schnorr
+// signature 'sig' over 'sigHash' for the serialized public key 'pubKey' is
+// found within the shared memory segment of the SigCache.  Otherwise, false is
+// returned, including when the SigCache is not shared.
+//
+// NOTE: This function is safe for concurrent access.
+func (s *SigCache) existsSchnorr(sigHash *chainhash.Hash, sig, pubKey []byte) bool {
+	s.RLock()
+	defer s.RUnlock()
+
+	if s.shared == nil {
+		return false
+	}
+	digest := s.shared.digest(sigTypeSchnorr, sigHash, sig, pubKey)
+	return s.shared.exists(&digest)
+}
+
+// addSchnorr adds an entry for the serialized Schnorr signature 'sig' over
+// 'sigHash' under the serialized public key 'pubKey' to the shared memory
+// segment of the SigCache.  It has no effect when the SigCache is not shared.
+//
+// NOTE: This function is safe for concurrent access.
+func (s *SigCache) addSchnorr(sigHash *chainhash.Hash, sig, pubKey []byte) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.shared == nil {
+		return
+	}
+	digest := s.shared.digest(sigTypeSchnorr, sigHash, sig, pubKey)
+	s.shared.add(&digest)
+}
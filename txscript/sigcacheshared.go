@@ -0,0 +1,165 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+const (
+	// SharedSigCacheVersion is the version of the layout of the shared
+	// memory segments used by shared signature caches.  Processes may only
+	// share a segment when they use the same layout version.
+	SharedSigCacheVersion = 1
+
+	// sharedSigCacheMagic identifies a shared signature cache segment.
+	sharedSigCacheMagic = "DCRSIGC\x00"
+
+	// sharedSigCacheHeaderSize is the size of the header at the start of a
+	// shared signature cache segment.  The header is laid out as follows:
+	//
+	//   magic (8 bytes) || version (4 bytes) || entry size (4 bytes) ||
+	//   number of slots (8 bytes) || seed (32 bytes) || reserved (8 bytes)
+	//
+	// All integers are little endian.
+	sharedSigCacheHeaderSize = 64
+
+	// sharedSigCacheEntrySize is the size of each slot in a shared signature
+	// cache segment.  Each slot holds the digest of a valid signature.
+	sharedSigCacheEntrySize = chainhash.HashSize
+
+	// sharedSigCacheSeedSize is the size of the random seed that is mixed
+	// into the digests of a shared signature cache segment.
+	sharedSigCacheSeedSize = 32
+)
+
+// These constants identify the kind of signature a digest in a shared
+// signature cache segment commits to so that digests of signatures of
+// different kinds can never collide.
+const (
+	sigTypeECDSA   byte = 0
+	sigTypeSchnorr byte = 1
+)
+
+// sharedSigCache implements the portion of a signature verification cache that
+// is stored in a memory segment shared by multiple processes on the same host.
+//
+// Rather than the signatures themselves, each slot of the segment holds the
+// digest of a valid signature, the hash it signs, and the public key it is
+// valid for, keyed by a random seed that is generated when the segment is
+// created.  The slot is selected by the digest, so the seed prevents remote
+// parties from crafting signatures that evict specific entries.  New entries
+// simply overwrite the entry in their slot.
+//
+// Access from within a process is synchronized by the owning SigCache, however
+// there is no synchronization between processes.  A slot that is read while
+// another process is writing it may contain a mix of two digests, which is
+// harmless since it can only match a digest of a valid signature with
+// negligible probability.
+type sharedSigCache struct {
+	mem      []byte
+	seed     []byte
+	entries  []byte
+	numSlots uint64
+	unmap    func([]byte) error
+}
+
+// initSharedSigCacheSegment initializes the header of the provided memory
+// segment, which must be large enough to hold the provided number of slots,
+// for use as a shared signature cache with the provided seed.
+func initSharedSigCacheSegment(mem []byte, numSlots uint64, seed []byte) {
+	copy(mem[0:8], sharedSigCacheMagic)
+	binary.LittleEndian.PutUint32(mem[8:12], SharedSigCacheVersion)
+	binary.LittleEndian.PutUint32(mem[12:16], sharedSigCacheEntrySize)
+	binary.LittleEndian.PutUint64(mem[16:24], numSlots)
+	copy(mem[24:56], seed)
+}
+
+// sharedSigCacheSegmentSize returns the size of a shared signature cache
+// segment with the provided number of slots.
+func sharedSigCacheSegmentSize(numSlots uint64) uint64 {
+	return sharedSigCacheHeaderSize + numSlots*sharedSigCacheEntrySize
+}
+
+// newSharedSigCache returns a shared signature cache backed by the provided
+// memory segment after ensuring it has been initialized with a compatible
+// layout.
+func newSharedSigCache(mem []byte) (*sharedSigCache, error) {
+	if len(mem) < sharedSigCacheHeaderSize {
+		return nil, fmt.Errorf("shared signature cache segment size of %d "+
+			"is too small", len(mem))
+	}
+	if !bytes.Equal(mem[0:8], []byte(sharedSigCacheMagic)) {
+		return nil, fmt.Errorf("memory segment is not a shared signature " +
+			"cache")
+	}
+	version := binary.LittleEndian.Uint32(mem[8:12])
+	if version != SharedSigCacheVersion {
+		return nil, fmt.Errorf("shared signature cache segment version %d "+
+			"is not the supported version %d", version,
+			SharedSigCacheVersion)
+	}
+	entrySize := binary.LittleEndian.Uint32(mem[12:16])
+	if entrySize != sharedSigCacheEntrySize {
+		return nil, fmt.Errorf("shared signature cache segment entry size "+
+			"%d is not the expected size %d", entrySize,
+			sharedSigCacheEntrySize)
+	}
+	numSlots := binary.LittleEndian.Uint64(mem[16:24])
+	if numSlots == 0 || sharedSigCacheSegmentSize(numSlots) > uint64(len(mem)) {
+		return nil, fmt.Errorf("shared signature cache segment with %d "+
+			"slots does not fit in %d bytes", numSlots, len(mem))
+	}
+
+	return &sharedSigCache{
+		mem:      mem,
+		seed:     mem[24:56],
+		entries:  mem[sharedSigCacheHeaderSize:sharedSigCacheSegmentSize(numSlots)],
+		numSlots: numSlots,
+	}, nil
+}
+
+// digest returns the digest that commits to the provided kind of signature,
+// the signature, the hash it signs, and the public key it is valid for.
+func (c *sharedSigCache) digest(sigType byte, sigHash *chainhash.Hash, sig, pubKey []byte) chainhash.Hash {
+	buf := make([]byte, 0, sharedSigCacheSeedSize+1+chainhash.HashSize+
+		len(sig)+len(pubKey))
+	buf = append(buf, c.seed...)
+	buf = append(buf, sigType)
+	buf = append(buf, sigHash[:]...)
+	buf = append(buf, sig...)
+	buf = append(buf, pubKey...)
+	return chainhash.HashH(buf)
+}
+
+// slot returns the slot of the segment the provided digest is stored in.
+func (c *sharedSigCache) slot(digest *chainhash.Hash) []byte {
+	idx := binary.LittleEndian.Uint64(digest[:8]) % c.numSlots
+	offset := idx * sharedSigCacheEntrySize
+	return c.entries[offset : offset+sharedSigCacheEntrySize]
+}
+
+// exists returns whether or not the provided digest is in the segment.
+func (c *sharedSigCache) exists(digest *chainhash.Hash) bool {
+	return bytes.Equal(c.slot(digest), digest[:])
+}
+
+// add adds the provided digest to the segment.
+func (c *sharedSigCache) add(digest *chainhash.Hash) {
+	copy(c.slot(digest), digest[:])
+}
+
+// close releases the memory segment.  The shared signature cache must not be
+// used after it is closed.
+func (c *sharedSigCache) close() error {
+	if c.unmap == nil {
+		return nil
+	}
+	return c.unmap(c.mem)
+}
@@ -0,0 +1,18 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package txscript
+
+import (
+	"fmt"
+)
+
+// openSharedSigCache returns an error since shared signature caches are not
+// supported on this platform.
+func openSharedSigCache(path string, maxEntries uint) (*sharedSigCache, error) {
+	return nil, fmt.Errorf("shared signature caches are not supported on " +
+		"this platform")
+}
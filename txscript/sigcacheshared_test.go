@@ -0,0 +1,91 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+// TestSharedSigCacheSegment ensures shared signature cache segments are
+// initialized and validated as expected and that digests can be added to and
+// found within them.
+func TestSharedSigCacheSegment(t *testing.T) {
+	const numSlots = 4
+	seed := make([]byte, sharedSigCacheSeedSize)
+	newSegment := func() []byte {
+		mem := make([]byte, sharedSigCacheSegmentSize(numSlots))
+		initSharedSigCacheSegment(mem, numSlots, seed)
+		return mem
+	}
+
+	// Ensure digests that were added are found while others are not and
+	// that the kind of signature is committed to.
+	c, err := newSharedSigCache(newSegment())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sigHash := chainhash.Hash{0x01}
+	sig, pubKey := []byte{0x02}, []byte{0x03}
+	digest := c.digest(sigTypeECDSA, &sigHash, sig, pubKey)
+	if c.exists(&digest) {
+		t.Fatal("digest found before it was added")
+	}
+	c.add(&digest)
+	if !c.exists(&digest) {
+		t.Fatal("digest not found after it was added")
+	}
+	schnorrDigest := c.digest(sigTypeSchnorr, &sigHash, sig, pubKey)
+	if schnorrDigest == digest || c.exists(&schnorrDigest) {
+		t.Fatal("digest does not commit to the signature type")
+	}
+
+	// Ensure segments with incompatible layouts are rejected.
+	tests := []struct {
+		name  string
+		munge func([]byte) []byte
+	}{{
+		name:  "too small for header",
+		munge: func(mem []byte) []byte { return mem[:8] },
+	}, {
+		name: "bad magic",
+		munge: func(mem []byte) []byte {
+			mem[0] ^= 0xff
+			return mem
+		},
+	}, {
+		name: "unsupported version",
+		munge: func(mem []byte) []byte {
+			binary.LittleEndian.PutUint32(mem[8:12],
+				SharedSigCacheVersion+1)
+			return mem
+		},
+	}, {
+		name: "unexpected entry size",
+		munge: func(mem []byte) []byte {
+			binary.LittleEndian.PutUint32(mem[12:16], 64)
+			return mem
+		},
+	}, {
+		name: "no slots",
+		munge: func(mem []byte) []byte {
+			binary.LittleEndian.PutUint64(mem[16:24], 0)
+			return mem
+		},
+	}, {
+		name: "slots exceed size",
+		munge: func(mem []byte) []byte {
+			binary.LittleEndian.PutUint64(mem[16:24], numSlots+1)
+			return mem
+		},
+	}}
+	for _, test := range tests {
+		if _, err := newSharedSigCache(test.munge(newSegment())); err == nil {
+			t.Errorf("%s: did not receive expected error", test.name)
+		}
+	}
+}
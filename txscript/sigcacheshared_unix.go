@@ -0,0 +1,113 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package txscript
+
+import (
+	"crypto/rand"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// openSharedSigCache opens the shared signature cache segment backed by the
+// file at the provided path, such as one in a memory-backed file system, and
+// maps it into memory.  A new segment with room for the provided number of
+// entries is created when the file does not exist or is empty.  Otherwise, the
+// existing segment is used as is.
+//
+// The file is locked while it is initialized so that multiple processes may
+// safely open the same segment concurrently.
+//
+// Since any process that is able to write to the segment is able to make
+// invalid signatures appear valid, an existing file is refused unless it is a
+// regular file owned by the current user that is not writable by other users.
+// New files are created exclusively and symbolic links are not followed.
+func openSharedSigCache(path string, maxEntries uint) (*sharedSigCache, error) {
+	const flags = os.O_RDWR | syscall.O_NOFOLLOW
+	f, err := os.OpenFile(path, flags|os.O_CREATE|os.O_EXCL, 0600)
+	if os.IsExist(err) {
+		f, err = os.OpenFile(path, flags, 0)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The mapping remains valid after the file is closed.
+	defer f.Close()
+
+	fd := int(f.Fd())
+	if err := syscall.Flock(fd, syscall.LOCK_EX); err != nil {
+		return nil, fmt.Errorf("unable to lock shared signature cache: %v",
+			err)
+	}
+	defer syscall.Flock(fd, syscall.LOCK_UN)
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSharedSigCacheFile(fi); err != nil {
+		return nil, fmt.Errorf("refusing to use shared signature cache %s: "+
+			"%v", path, err)
+	}
+	size := fi.Size()
+	create := size == 0
+	if create {
+		if maxEntries == 0 {
+			return nil, fmt.Errorf("shared signature cache must have " +
+				"room for at least one entry")
+		}
+		size = int64(sharedSigCacheSegmentSize(uint64(maxEntries)))
+		if err := f.Truncate(size); err != nil {
+			return nil, err
+		}
+	}
+
+	mem, err := syscall.Mmap(fd, 0, int(size), syscall.PROT_READ|
+		syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, fmt.Errorf("unable to map shared signature cache: %v",
+			err)
+	}
+	if create {
+		seed := make([]byte, sharedSigCacheSeedSize)
+		if _, err := rand.Read(seed); err != nil {
+			syscall.Munmap(mem)
+			return nil, err
+		}
+		initSharedSigCacheSegment(mem, uint64(maxEntries), seed)
+	}
+
+	c, err := newSharedSigCache(mem)
+	if err != nil {
+		syscall.Munmap(mem)
+		return nil, err
+	}
+	c.unmap = syscall.Munmap
+	return c, nil
+}
+
+// checkSharedSigCacheFile returns an error when the file described by the
+// provided file info is not a regular file owned by the current user or is
+// writable by other users.
+func checkSharedSigCacheFile(fi os.FileInfo) error {
+	if !fi.Mode().IsRegular() {
+		return fmt.Errorf("not a regular file")
+	}
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fmt.Errorf("unable to determine file owner")
+	}
+	if int(st.Uid) != os.Getuid() {
+		return fmt.Errorf("file is owned by uid %d instead of the current "+
+			"user", st.Uid)
+	}
+	if fi.Mode().Perm()&0022 != 0 {
+		return fmt.Errorf("file is writable by other users (mode %v)",
+			fi.Mode().Perm())
+	}
+	return nil
+}
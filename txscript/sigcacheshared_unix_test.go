@@ -0,0 +1,134 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package txscript
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
+	"github.com/decred/dcrd/dcrec/secp256k1/v3/ecdsa"
+	"github.com/decred/dcrd/dcrec/secp256k1/v3/schnorr"
+)
+
+// TestSharedSigCache ensures signatures added to a shared signature cache are
+// visible to other shared signature caches that use the same segment.
+func TestSharedSigCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sharedsigcache")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "sigcache")
+
+	// Create two caches that share the same segment to simulate separate
+	// processes.
+	sigCache1, err := NewSharedSigCache(100, path)
+	if err != nil {
+		t.Fatalf("unable to create shared sigcache: %v", err)
+	}
+	defer sigCache1.Close()
+	sigCache2, err := NewSharedSigCache(200, path)
+	if err != nil {
+		t.Fatalf("unable to create shared sigcache: %v", err)
+	}
+	defer sigCache2.Close()
+	if sigCache2.shared.numSlots != 100 {
+		t.Fatalf("existing segment not used -- got %d slots, want 100",
+			sigCache2.shared.numSlots)
+	}
+
+	// Ensure an ECDSA signature added to one cache is found in the other.
+	msg, sig, key, err := genRandomSig()
+	if err != nil {
+		t.Fatalf("unable to generate random signature test data")
+	}
+	sigCache1.Add(*msg, sig, key)
+	sigCopy, _ := ecdsa.ParseDERSignature(sig.Serialize())
+	keyCopy, _ := secp256k1.ParsePubKey(key.SerializeCompressed())
+	if len(sigCache2.validSigs) != 0 {
+		t.Fatal("signature unexpectedly added to local cache")
+	}
+	if !sigCache2.Exists(*msg, sigCopy, keyCopy) {
+		t.Fatal("signature added by other cache not found")
+	}
+
+	// Ensure a different signature for the same hash is not found.
+	_, otherSig, otherKey, err := genRandomSig()
+	if err != nil {
+		t.Fatalf("unable to generate random signature test data")
+	}
+	if sigCache2.Exists(*msg, otherSig, otherKey) {
+		t.Fatal("signature that was not added found")
+	}
+
+	// Ensure a Schnorr signature added to one cache is found in the other.
+	privKey, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		t.Fatalf("unable to generate private key: %v", err)
+	}
+	schnorrSig, err := schnorr.Sign(privKey, msg[:])
+	if err != nil {
+		t.Fatalf("unable to sign: %v", err)
+	}
+	sigBytes := schnorrSig.Serialize()
+	pkBytes := privKey.PubKey().SerializeCompressed()
+	if sigCache2.existsSchnorr(msg, sigBytes, pkBytes) {
+		t.Fatal("schnorr signature found before it was added")
+	}
+	sigCache1.addSchnorr(msg, sigBytes, pkBytes)
+	if !sigCache2.existsSchnorr(msg, sigBytes, pkBytes) {
+		t.Fatal("schnorr signature added by other cache not found")
+	}
+
+	// Ensure the cache only works locally once it is closed.
+	if err := sigCache2.Close(); err != nil {
+		t.Fatalf("unexpected error closing shared sigcache: %v", err)
+	}
+	if sigCache2.Exists(*msg, sigCopy, keyCopy) {
+		t.Fatal("signature found in shared segment after close")
+	}
+	if err := sigCache2.Close(); err != nil {
+		t.Fatalf("unexpected error closing closed shared sigcache: %v", err)
+	}
+
+	// Ensure a file that is not a shared signature cache is rejected.
+	badPath := filepath.Join(dir, "notsigcache")
+	if err := ioutil.WriteFile(badPath, make([]byte, 128), 0600); err != nil {
+		t.Fatalf("unable to write file: %v", err)
+	}
+	if _, err := NewSharedSigCache(100, badPath); err == nil {
+		t.Fatal("did not receive expected error for invalid segment")
+	}
+
+	// Ensure an existing segment that is writable by other users is refused.
+	if err := os.Chmod(path, 0666); err != nil {
+		t.Fatalf("unable to change file mode: %v", err)
+	}
+	if _, err := NewSharedSigCache(100, path); err == nil {
+		t.Fatal("did not receive expected error for writable segment")
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		t.Fatalf("unable to change file mode: %v", err)
+	}
+
+	// Ensure a symbolic link to an existing segment is not followed.
+	linkPath := filepath.Join(dir, "sigcachelink")
+	if err := os.Symlink(path, linkPath); err != nil {
+		t.Fatalf("unable to create symbolic link: %v", err)
+	}
+	if _, err := NewSharedSigCache(100, linkPath); err == nil {
+		t.Fatal("did not receive expected error for symbolic link")
+	}
+
+	// Ensure a new segment requires room for at least one entry.
+	if _, err := NewSharedSigCache(0, filepath.Join(dir, "empty")); err == nil {
+		t.Fatal("did not receive expected error for zero entries")
+	}
+}
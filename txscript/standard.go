@@ -589,20 +589,12 @@ func GetStakeOutSubclass(pkScript []byte) (ScriptClass, error) {
 		return 0, err
 	}
 
-	class := typeOfScript(scriptVersion, pkScript)
-	isStake := class == StakeSubmissionTy ||
-		class == StakeGenTy ||
-		class == StakeRevocationTy ||
-		class == StakeSubChangeTy
-
-	subClass := ScriptClass(0)
-	if isStake {
-		subClass = typeOfScript(scriptVersion, pkScript[1:])
-	} else {
+	analysis := analyzePkScriptV0(pkScript, nil)
+	if analysis.StakeSubClass == NonStandardTy {
 		return 0, fmt.Errorf("not a stake output")
 	}
 
-	return subClass, nil
+	return analysis.StakeSubClass, nil
 }
 
 // ContainsStakeOpCodes returns whether or not a pkScript contains stake tagging
@@ -1136,136 +1128,226 @@ func scriptHashToAddrs(hash []byte, params dcrutil.AddressParams) []dcrutil.Addr
 	return addrs
 }
 
-// ExtractPkScriptAddrs returns the type of script, addresses and required
-// signatures associated with the passed PkScript.  Note that it only works for
-// 'standard' transaction script types.  Any data such as public keys which are
-// invalid are omitted from the results.
+// PkScriptAnalysis houses the results of analyzing a public key script via
+// AnalyzePkScript.
+type PkScriptAnalysis struct {
+	// Version is the script version the script was analyzed under.
+	Version uint16
+
+	// Class is the standard class of the script.  It is NonStandardTy when
+	// the script does not match any of the standard forms.
+	Class ScriptClass
+
+	// StakeSubClass is the class of the script tagged by the stake opcode
+	// when the script is one of the stake classes, which is either
+	// PubKeyHashTy or ScriptHashTy.  It is NonStandardTy for all other
+	// classes.
+	StakeSubClass ScriptClass
+
+	// RequiredSigs is the number of signatures required to redeem the
+	// script.
+	RequiredSigs int
+
+	// Addresses are the addresses the script pays to.  Any data such as
+	// public keys which are invalid are omitted.  It is nil when no address
+	// parameters were provided for the analysis.
+	Addresses []dcrutil.Address
+}
+
+// AnalyzePkScript analyzes the passed public key script under the rules of the
+// provided script version and returns its class, the class of the script
+// tagged by the stake opcode for stake scripts, the number of required
+// signatures, and the addresses it pays to in a single pass.
 //
-// NOTE: This function only attempts to identify version 0 scripts.  The return
-// value will indicate a nonstandard script type for other script versions along
-// with an invalid script version error.
-func ExtractPkScriptAddrs(version uint16, pkScript []byte,
-	chainParams dcrutil.AddressParams) (ScriptClass, []dcrutil.Address, int, error) {
-	if version != 0 {
-		return NonStandardTy, nil, 0, fmt.Errorf("invalid script version")
+// The address parameters may be nil when the addresses are not needed, in
+// which case the work of converting the script data to addresses is skipped.
+//
+// An error with the kind ErrUnsupportedScriptVersion is returned along with a
+// nonstandard analysis for script versions that are not supported.  Only
+// version 0 scripts are currently supported.
+func AnalyzePkScript(version uint16, pkScript []byte, params dcrutil.AddressParams) (PkScriptAnalysis, error) {
+	switch version {
+	case 0:
+		return analyzePkScriptV0(pkScript, params), nil
+	}
+
+	str := fmt.Sprintf("script version %d is not supported", version)
+	analysis := PkScriptAnalysis{
+		Version:       version,
+		Class:         NonStandardTy,
+		StakeSubClass: NonStandardTy,
+	}
+	return analysis, scriptError(ErrUnsupportedScriptVersion, str)
+}
+
+// analyzePkScriptV0 analyzes the passed version 0 public key script.  See
+// AnalyzePkScript for details.
+func analyzePkScriptV0(pkScript []byte, params dcrutil.AddressParams) PkScriptAnalysis {
+	const version = 0
+	analysis := PkScriptAnalysis{
+		Version:       version,
+		Class:         NonStandardTy,
+		StakeSubClass: NonStandardTy,
 	}
+	wantAddrs := params != nil
 
 	// Check for pay-to-pubkey-hash script.
 	if hash := extractPubKeyHash(pkScript); hash != nil {
-		return PubKeyHashTy, pubKeyHashToAddrs(hash, chainParams), 1, nil
+		analysis.Class = PubKeyHashTy
+		analysis.RequiredSigs = 1
+		if wantAddrs {
+			analysis.Addresses = pubKeyHashToAddrs(hash, params)
+		}
+		return analysis
 	}
 
 	// Check for pay-to-script-hash.
 	if hash := ExtractScriptHash(pkScript); hash != nil {
-		return ScriptHashTy, scriptHashToAddrs(hash, chainParams), 1, nil
+		analysis.Class = ScriptHashTy
+		analysis.RequiredSigs = 1
+		if wantAddrs {
+			analysis.Addresses = scriptHashToAddrs(hash, params)
+		}
+		return analysis
 	}
 
 	// Check for pay-to-alt-pubkey-hash script.
 	if data, sigType := extractPubKeyHashAltDetails(pkScript); data != nil {
-		var addrs []dcrutil.Address
-		addr, err := dcrutil.NewAddressPubKeyHash(data, chainParams, sigType)
-		if err == nil {
-			addrs = append(addrs, addr)
+		analysis.Class = PubkeyHashAltTy
+		analysis.RequiredSigs = 1
+		if wantAddrs {
+			addr, err := dcrutil.NewAddressPubKeyHash(data, params, sigType)
+			if err == nil {
+				analysis.Addresses = append(analysis.Addresses, addr)
+			}
 		}
-		return PubkeyHashAltTy, addrs, 1, nil
+		return analysis
 	}
 
 	// Check for pay-to-pubkey script.
 	if data := extractPubKey(pkScript); data != nil {
-		var addrs []dcrutil.Address
-		pk, err := secp256k1.ParsePubKey(data)
-		if err == nil {
-			addr, err := dcrutil.NewAddressSecpPubKeyCompressed(pk, chainParams)
+		analysis.Class = PubKeyTy
+		analysis.RequiredSigs = 1
+		if wantAddrs {
+			pk, err := secp256k1.ParsePubKey(data)
 			if err == nil {
-				addrs = append(addrs, addr)
+				addr, err := dcrutil.NewAddressSecpPubKeyCompressed(pk, params)
+				if err == nil {
+					analysis.Addresses = append(analysis.Addresses, addr)
+				}
 			}
 		}
-		return PubKeyTy, addrs, 1, nil
+		return analysis
 	}
 
 	// Check for pay-to-alt-pubkey script.
 	if pk, sigType := extractPubKeyAltDetails(pkScript); pk != nil {
-		var addrs []dcrutil.Address
-		switch sigType {
-		case dcrec.STEd25519:
-			addr, err := dcrutil.NewAddressEdwardsPubKey(pk, chainParams)
-			if err == nil {
-				addrs = append(addrs, addr)
+		analysis.Class = PubkeyAltTy
+		analysis.RequiredSigs = 1
+		if wantAddrs {
+			var addr dcrutil.Address
+			var err error
+			switch sigType {
+			case dcrec.STEd25519:
+				addr, err = dcrutil.NewAddressEdwardsPubKey(pk, params)
+
+			case dcrec.STSchnorrSecp256k1:
+				addr, err = dcrutil.NewAddressSecSchnorrPubKey(pk, params)
 			}
-
-		case dcrec.STSchnorrSecp256k1:
-			addr, err := dcrutil.NewAddressSecSchnorrPubKey(pk, chainParams)
-			if err == nil {
-				addrs = append(addrs, addr)
+			if addr != nil && err == nil {
+				analysis.Addresses = append(analysis.Addresses, addr)
 			}
 		}
-
-		return PubkeyAltTy, addrs, 1, nil
+		return analysis
 	}
 
 	// Check for multi-signature script.
-	details := extractMultisigScriptDetails(version, pkScript, true)
+	details := extractMultisigScriptDetails(version, pkScript, wantAddrs)
 	if details.valid {
-		// Convert the public keys while skipping any that are invalid.
-		addrs := make([]dcrutil.Address, 0, details.numPubKeys)
-		for i := 0; i < details.numPubKeys; i++ {
-			pubkey, err := secp256k1.ParsePubKey(details.pubKeys[i])
-			if err == nil {
-				addr, err := dcrutil.NewAddressSecpPubKeyCompressed(pubkey,
-					chainParams)
+		analysis.Class = MultiSigTy
+		analysis.RequiredSigs = details.requiredSigs
+		if wantAddrs {
+			// Convert the public keys while skipping any that are invalid.
+			addrs := make([]dcrutil.Address, 0, details.numPubKeys)
+			for i := 0; i < details.numPubKeys; i++ {
+				pubkey, err := secp256k1.ParsePubKey(details.pubKeys[i])
 				if err == nil {
-					addrs = append(addrs, addr)
+					addr, err := dcrutil.NewAddressSecpPubKeyCompressed(pubkey,
+						params)
+					if err == nil {
+						addrs = append(addrs, addr)
+					}
 				}
 			}
+			analysis.Addresses = addrs
+		}
+		return analysis
+	}
+
+	// Check for the stake submission, generation, revocation, and change
+	// scripts.  Only stake-tagged pay-to-pubkey-hash and pay-to-script-hash
+	// are allowed.
+	stakeClasses := [...]struct {
+		opcode byte
+		class  ScriptClass
+	}{
+		{OP_SSTX, StakeSubmissionTy},
+		{OP_SSGEN, StakeGenTy},
+		{OP_SSRTX, StakeRevocationTy},
+		{OP_SSTXCHANGE, StakeSubChangeTy},
+	}
+	for _, stake := range stakeClasses {
+		if hash := extractStakePubKeyHash(pkScript, stake.opcode); hash != nil {
+			analysis.Class = stake.class
+			analysis.StakeSubClass = PubKeyHashTy
+			analysis.RequiredSigs = 1
+			if wantAddrs {
+				analysis.Addresses = pubKeyHashToAddrs(hash, params)
+			}
+			return analysis
+		}
+		if hash := extractStakeScriptHash(pkScript, stake.opcode); hash != nil {
+			analysis.Class = stake.class
+			analysis.StakeSubClass = ScriptHashTy
+			analysis.RequiredSigs = 1
+			if wantAddrs {
+				analysis.Addresses = scriptHashToAddrs(hash, params)
+			}
+			return analysis
 		}
-		return MultiSigTy, addrs, details.requiredSigs, nil
-	}
-
-	// Check for stake submission script.  Only stake-submission-tagged
-	// pay-to-pubkey-hash and pay-to-script-hash are allowed.
-	if hash := extractStakePubKeyHash(pkScript, OP_SSTX); hash != nil {
-		return StakeSubmissionTy, pubKeyHashToAddrs(hash, chainParams), 1, nil
-	}
-	if hash := extractStakeScriptHash(pkScript, OP_SSTX); hash != nil {
-		return StakeSubmissionTy, scriptHashToAddrs(hash, chainParams), 1, nil
-	}
-
-	// Check for stake generation script.  Only stake-generation-tagged
-	// pay-to-pubkey-hash and pay-to-script-hash are allowed.
-	if hash := extractStakePubKeyHash(pkScript, OP_SSGEN); hash != nil {
-		return StakeGenTy, pubKeyHashToAddrs(hash, chainParams), 1, nil
-	}
-	if hash := extractStakeScriptHash(pkScript, OP_SSGEN); hash != nil {
-		return StakeGenTy, scriptHashToAddrs(hash, chainParams), 1, nil
-	}
-
-	// Check for stake revocation script.  Only stake-revocation-tagged
-	// pay-to-pubkey-hash and pay-to-script-hash are allowed.
-	if hash := extractStakePubKeyHash(pkScript, OP_SSRTX); hash != nil {
-		return StakeRevocationTy, pubKeyHashToAddrs(hash, chainParams), 1, nil
-	}
-	if hash := extractStakeScriptHash(pkScript, OP_SSRTX); hash != nil {
-		return StakeRevocationTy, scriptHashToAddrs(hash, chainParams), 1, nil
-	}
-
-	// Check for stake change script.  Only stake-change-tagged
-	// pay-to-pubkey-hash and pay-to-script-hash are allowed.
-	if hash := extractStakePubKeyHash(pkScript, OP_SSTXCHANGE); hash != nil {
-		return StakeSubChangeTy, pubKeyHashToAddrs(hash, chainParams), 1, nil
-	}
-	if hash := extractStakeScriptHash(pkScript, OP_SSTXCHANGE); hash != nil {
-		return StakeSubChangeTy, scriptHashToAddrs(hash, chainParams), 1, nil
 	}
 
-	// Check for null data script.
+	// Check for null data script.  Null data transactions have no addresses
+	// or required signatures.
 	if isNullDataScript(version, pkScript) {
-		// Null data transactions have no addresses or required signatures.
-		return NullDataTy, nil, 0, nil
+		analysis.Class = NullDataTy
+		return analysis
 	}
 
 	// Don't attempt to extract addresses or required signatures for nonstandard
 	// transactions.
-	return NonStandardTy, nil, 0, nil
+	return analysis
+}
+
+// ExtractPkScriptAddrs returns the type of script, addresses and required
+// signatures associated with the passed PkScript.  Note that it only works for
+// 'standard' transaction script types.  Any data such as public keys which are
+// invalid are omitted from the results.
+//
+// This is a convenience wrapper around AnalyzePkScript, which should be
+// preferred by callers that also need the stake subclass of the script.
+//
+// NOTE: This function only attempts to identify version 0 scripts.  The return
+// value will indicate a nonstandard script type for other script versions along
+// with an invalid script version error.
+func ExtractPkScriptAddrs(version uint16, pkScript []byte,
+	chainParams dcrutil.AddressParams) (ScriptClass, []dcrutil.Address, int, error) {
+
+	analysis, err := AnalyzePkScript(version, pkScript, chainParams)
+	if err != nil {
+		return NonStandardTy, nil, 0, err
+	}
+	return analysis.Class, analysis.Addresses, analysis.RequiredSigs, nil
 }
 
 // ExtractPkScriptAltSigType returns the signature scheme to use for an
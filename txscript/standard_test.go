@@ -860,6 +860,53 @@ func TestScriptClass(t *testing.T) {
 	}
 }
 
+// TestAnalyzePkScript ensures analyzing the scripts in scriptClassTests
+// produces the expected class and stake subclass both with and without address
+// parameters and that unsupported script versions are rejected.
+func TestAnalyzePkScript(t *testing.T) {
+	t.Parallel()
+
+	const scriptVersion = 0
+	for _, test := range scriptClassTests {
+		script := mustParseShortForm(test.script)
+		for _, params := range []dcrutil.AddressParams{nil, mainNetParams} {
+			analysis, err := AnalyzePkScript(scriptVersion, script, params)
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", test.name, err)
+				continue
+			}
+			if analysis.Class != test.class {
+				t.Errorf("%s: unexpected class -- got %s, want %s",
+					test.name, analysis.Class, test.class)
+				continue
+			}
+			if analysis.StakeSubClass != test.subClass {
+				t.Errorf("%s: unexpected stake subclass -- got %s, want %s",
+					test.name, analysis.StakeSubClass, test.subClass)
+				continue
+			}
+			if params == nil && analysis.Addresses != nil {
+				t.Errorf("%s: unexpected addresses without params: %v",
+					test.name, analysis.Addresses)
+				continue
+			}
+		}
+	}
+
+	// Ensure unsupported script versions are rejected as nonstandard.
+	script := mustParseShortForm("DUP HASH160 DATA_20 0x433ec2ac1ffa1b7b7d0" +
+		"27f564529c57197f9ae88 EQUALVERIFY CHECKSIG")
+	analysis, err := AnalyzePkScript(1, script, mainNetParams)
+	if !errors.Is(err, ErrUnsupportedScriptVersion) {
+		t.Fatalf("unexpected error -- got %v, want %v", err,
+			ErrUnsupportedScriptVersion)
+	}
+	if analysis.Class != NonStandardTy || analysis.Version != 1 {
+		t.Fatalf("unexpected analysis for unsupported version: %+v",
+			analysis)
+	}
+}
+
 // TestStringifyClass ensures the script class string returns the expected
 // string for each script class.
 func TestStringifyClass(t *testing.T) {
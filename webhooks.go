@@ -0,0 +1,409 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/decred/dcrd/txscript/v3"
+)
+
+const (
+	// These constants define the categories of events that can be delivered
+	// to webhooks.
+	webhookCategoryBlock   = "block"
+	webhookCategoryTx      = "tx"
+	webhookCategoryPeerBan = "peerban"
+
+	// webhookEventHeader is the HTTP header that identifies the category of
+	// the event delivered to a webhook.
+	webhookEventHeader = "X-Dcrd-Event"
+
+	// webhookSignatureHeader is the HTTP header that houses the hex-encoded
+	// HMAC-SHA256 of the payload delivered to a webhook keyed by the webhook
+	// secret.  It is only set when a secret is configured.
+	webhookSignatureHeader = "X-Dcrd-Signature"
+
+	// webhookTimeout is the maximum amount of time to wait for a webhook to
+	// accept a payload before the attempt is considered failed.
+	webhookTimeout = 30 * time.Second
+
+	// maxQueuedWebhookDeliveries is the maximum number of deliveries that may
+	// be waiting for their first attempt.  New deliveries are dropped once it
+	// is reached.
+	maxQueuedWebhookDeliveries = 1000
+
+	// maxWebhookAttempts is the maximum number of times the delivery of an
+	// event to a webhook is attempted before it is dropped.
+	maxWebhookAttempts = 8
+
+	// webhookRetryBaseDelay and webhookRetryMaxDelay are the initial and
+	// maximum amounts of time to wait before retrying the delivery of an
+	// event to a webhook that did not accept it.  The delay doubles after
+	// each failed attempt.
+	webhookRetryBaseDelay = 5 * time.Second
+	webhookRetryMaxDelay  = 10 * time.Minute
+)
+
+// webhookCategories houses the categories of events that can be delivered to
+// webhooks.
+var webhookCategories = map[string]struct{}{
+	webhookCategoryBlock:   {},
+	webhookCategoryTx:      {},
+	webhookCategoryPeerBan: {},
+}
+
+// signWebhookPayload returns the hex-encoded HMAC-SHA256 of the provided
+// payload keyed by the provided secret.
+func signWebhookPayload(secret, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// postWebhook delivers the provided payload for an event of the provided
+// category to the provided webhook via an HTTP POST request.  The payload is
+// signed with the provided secret when it is not empty.  The webhook must
+// respond with a 2xx status code to accept the payload.
+func postWebhook(ctx context.Context, url, category string, payload, secret []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url,
+		bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookEventHeader, category)
+	if len(secret) != 0 {
+		req.Header.Set(webhookSignatureHeader,
+			signWebhookPayload(secret, payload))
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %q", resp.Status)
+	}
+	return nil
+}
+
+// retryDelay returns the amount of time to wait before retrying a delivery
+// after the provided number of failed attempts given the initial and maximum
+// delays.  The delay doubles after each failed attempt.
+func retryDelay(attempts uint32, baseDelay, maxDelay time.Duration) time.Duration {
+	delay := baseDelay
+	for i := uint32(1); i < attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
+
+// webhookEvent is an event as it is delivered to webhooks.
+type webhookEvent struct {
+	ID       uint64      `json:"id"`
+	Time     int64       `json:"time"`
+	Category string      `json:"category"`
+	Network  string      `json:"network"`
+	Data     interface{} `json:"data"`
+}
+
+// webhookBlockData houses the details of a block event.
+type webhookBlockData struct {
+	Hash      string `json:"hash"`
+	Height    int64  `json:"height"`
+	Time      int64  `json:"time"`
+	Size      int    `json:"size"`
+	NumTxns   int    `json:"numtx"`
+	NumSTxns  int    `json:"numstx"`
+	Voters    uint16 `json:"voters"`
+	PrevBlock string `json:"previousblockhash"`
+}
+
+// webhookTxData houses the details of a tx event.  The block hash and height
+// are only set for transactions in a block connected to the main chain.
+type webhookTxData struct {
+	TxID        string   `json:"txid"`
+	Addresses   []string `json:"addresses"`
+	BlockHash   string   `json:"blockhash,omitempty"`
+	BlockHeight int64    `json:"blockheight,omitempty"`
+	Hex         string   `json:"hex"`
+}
+
+// webhookPeerBanData houses the details of a peer ban event.
+type webhookPeerBanData struct {
+	Host    string `json:"host"`
+	Inbound bool   `json:"inbound"`
+	Until   int64  `json:"until"`
+}
+
+// webhookDelivery is the delivery of a serialized event to a webhook.
+type webhookDelivery struct {
+	url      string
+	category string
+	payload  []byte
+	attempts uint32
+	due      time.Time
+}
+
+// webhookManager delivers events such as newly connected blocks, transactions
+// that pay to addresses of interest, and peer bans to operator-registered
+// webhooks with signed payloads and retries so integrations can react to them
+// without maintaining a persistent websocket connection.
+//
+// Unlike the alert outbox, deliveries are not persisted and are dropped after
+// the maximum number of attempts.
+type webhookManager struct {
+	params    *chaincfg.Params
+	endpoints map[string][]string
+	secret    []byte
+	txAddrs   map[string]struct{}
+
+	// now returns the current time and post delivers the provided payload to
+	// the provided webhook.  They are fields so they can be mocked by tests.
+	now  func() time.Time
+	post func(ctx context.Context, url, category string, payload []byte) error
+
+	queue chan *webhookDelivery
+
+	mtx    sync.Mutex
+	nextID uint64
+}
+
+// newWebhookManager returns a webhook manager that delivers events of each
+// category to the provided webhooks for the category.  Payloads are signed
+// with the provided secret when it is not empty.  Tx events are delivered for
+// transactions that pay to any of the provided addresses.
+func newWebhookManager(params *chaincfg.Params, endpoints map[string][]string, secret string, txAddrs []string) *webhookManager {
+	m := &webhookManager{
+		params:    params,
+		endpoints: endpoints,
+		secret:    []byte(secret),
+		txAddrs:   make(map[string]struct{}, len(txAddrs)),
+		now:       time.Now,
+		queue:     make(chan *webhookDelivery, maxQueuedWebhookDeliveries),
+		nextID:    1,
+	}
+	m.post = func(ctx context.Context, url, category string, payload []byte) error {
+		return postWebhook(ctx, url, category, payload, m.secret)
+	}
+	for _, addr := range txAddrs {
+		m.txAddrs[addr] = struct{}{}
+	}
+	return m
+}
+
+// publish queues the delivery of an event of the provided category with the
+// provided data to all webhooks registered for the category.  The delivery is
+// dropped when the queue is full.
+//
+// This function is safe for concurrent access.
+func (m *webhookManager) publish(category string, data interface{}) {
+	urls := m.endpoints[category]
+	if len(urls) == 0 {
+		return
+	}
+
+	m.mtx.Lock()
+	id := m.nextID
+	m.nextID++
+	m.mtx.Unlock()
+	payload, err := json.Marshal(&webhookEvent{
+		ID:       id,
+		Time:     m.now().Unix(),
+		Category: category,
+		Network:  m.params.Name,
+		Data:     data,
+	})
+	if err != nil {
+		srvrLog.Errorf("Unable to serialize %s webhook event: %v", category,
+			err)
+		return
+	}
+
+	for _, url := range urls {
+		select {
+		case m.queue <- &webhookDelivery{
+			url:      url,
+			category: category,
+			payload:  payload,
+		}:
+		default:
+			srvrLog.Warnf("Dropping %s webhook event %d for %s: too many "+
+				"queued deliveries", category, id, url)
+		}
+	}
+}
+
+// matchTx returns the addresses of interest the provided transaction pays to.
+func (m *webhookManager) matchTx(tx *dcrutil.Tx) []string {
+	var matches []string
+	for _, txOut := range tx.MsgTx().TxOut {
+		_, addrs, _, err := txscript.ExtractPkScriptAddrs(txOut.Version,
+			txOut.PkScript, m.params)
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			encoded := addr.Address()
+			if _, ok := m.txAddrs[encoded]; ok {
+				matches = append(matches, encoded)
+			}
+		}
+	}
+	return matches
+}
+
+// publishTxns publishes tx events for the provided transactions that pay to
+// addresses of interest.  The provided block is nil for transactions that are
+// not in a block.
+func (m *webhookManager) publishTxns(txns []*dcrutil.Tx, block *dcrutil.Block) {
+	for _, tx := range txns {
+		addrs := m.matchTx(tx)
+		if len(addrs) == 0 {
+			continue
+		}
+		serializedTx, err := tx.MsgTx().Bytes()
+		if err != nil {
+			srvrLog.Errorf("Unable to serialize tx %v: %v", tx.Hash(), err)
+			continue
+		}
+		data := &webhookTxData{
+			TxID:      tx.Hash().String(),
+			Addresses: addrs,
+			Hex:       hex.EncodeToString(serializedTx),
+		}
+		if block != nil {
+			data.BlockHash = block.Hash().String()
+			data.BlockHeight = block.Height()
+		}
+		m.publish(webhookCategoryTx, data)
+	}
+}
+
+// BlockConnected publishes a block event for the provided block that was
+// connected to the main chain along with tx events for the transactions in it
+// that pay to addresses of interest.
+//
+// This function is safe for concurrent access.
+func (m *webhookManager) BlockConnected(block *dcrutil.Block) {
+	if len(m.endpoints[webhookCategoryBlock]) != 0 {
+		header := &block.MsgBlock().Header
+		m.publish(webhookCategoryBlock, &webhookBlockData{
+			Hash:      block.Hash().String(),
+			Height:    block.Height(),
+			Time:      header.Timestamp.Unix(),
+			Size:      block.MsgBlock().SerializeSize(),
+			NumTxns:   len(block.MsgBlock().Transactions),
+			NumSTxns:  len(block.MsgBlock().STransactions),
+			Voters:    header.Voters,
+			PrevBlock: header.PrevBlock.String(),
+		})
+	}
+	if len(m.endpoints[webhookCategoryTx]) != 0 {
+		m.publishTxns(block.Transactions(), block)
+		m.publishTxns(block.STransactions(), block)
+	}
+}
+
+// TxnsAccepted publishes tx events for the provided transactions that were
+// accepted to the mempool and pay to addresses of interest.
+//
+// This function is safe for concurrent access.
+func (m *webhookManager) TxnsAccepted(txns []*dcrutil.Tx) {
+	if len(m.endpoints[webhookCategoryTx]) != 0 {
+		m.publishTxns(txns, nil)
+	}
+}
+
+// PeerBanned publishes a peer ban event for the provided host which is banned
+// until the provided time.
+//
+// This function is safe for concurrent access.
+func (m *webhookManager) PeerBanned(host string, inbound bool, until time.Time) {
+	m.publish(webhookCategoryPeerBan, &webhookPeerBanData{
+		Host:    host,
+		Inbound: inbound,
+		Until:   until.Unix(),
+	})
+}
+
+// attempt attempts the provided delivery and returns whether it should be
+// retried.
+func (m *webhookManager) attempt(ctx context.Context, d *webhookDelivery) bool {
+	err := m.post(ctx, d.url, d.category, d.payload)
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	d.attempts++
+	if d.attempts >= maxWebhookAttempts {
+		srvrLog.Warnf("Dropping %s webhook delivery to %s after %d failed "+
+			"attempts: %v", d.category, d.url, d.attempts, err)
+		return false
+	}
+	d.due = m.now().Add(retryDelay(d.attempts, webhookRetryBaseDelay,
+		webhookRetryMaxDelay))
+	srvrLog.Debugf("Unable to deliver %s webhook event to %s (attempt %d): "+
+		"%v", d.category, d.url, d.attempts, err)
+	return true
+}
+
+// Run delivers queued events to the webhooks until the provided context is
+// cancelled.  Deliveries that fail are retried with an exponential backoff.
+//
+// This must be run as a goroutine.
+func (m *webhookManager) Run(ctx context.Context) {
+	var retries []*webhookDelivery
+	for {
+		// Wait for the earliest retry to become due or a new delivery.
+		var retry <-chan time.Time
+		if len(retries) != 0 {
+			earliest := retries[0].due
+			for _, d := range retries[1:] {
+				if d.due.Before(earliest) {
+					earliest = d.due
+				}
+			}
+			retry = time.After(earliest.Sub(m.now()))
+		}
+
+		select {
+		case d := <-m.queue:
+			if m.attempt(ctx, d) {
+				retries = append(retries, d)
+			}
+
+		case <-retry:
+			now := m.now()
+			remaining := retries[:0]
+			for _, d := range retries {
+				if d.due.After(now) || m.attempt(ctx, d) {
+					remaining = append(remaining, d)
+				}
+			}
+			retries = remaining
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/decred/dcrd/txscript/v3"
+	"github.com/decred/dcrd/wire"
+)
+
+// TestPostWebhook ensures payloads are delivered to webhooks with the event
+// category and the expected signature and that webhooks which do not respond
+// with a 2xx status code result in an error.
+func TestPostWebhook(t *testing.T) {
+	t.Parallel()
+
+	// The expected signature is the HMAC-SHA256 of the payload keyed by the
+	// secret as specified by RFC 4231 test case 2.
+	const secret = "Jefe"
+	payload := []byte("what do ya want for nothing?")
+	const wantSig = "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b9" +
+		"64ec3843"
+
+	status := http.StatusOK
+	var gotEvent, gotSig string
+	var gotPayload []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotEvent = r.Header.Get(webhookEventHeader)
+		gotSig = r.Header.Get(webhookSignatureHeader)
+		gotPayload, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	err := postWebhook(ctx, srv.URL, webhookCategoryBlock, payload,
+		[]byte(secret))
+	if err != nil {
+		t.Fatalf("unexpected error delivering payload: %v", err)
+	}
+	if gotEvent != webhookCategoryBlock || gotSig != wantSig ||
+		string(gotPayload) != string(payload) {
+
+		t.Fatalf("unexpected delivery -- got event %q, signature %q, "+
+			"payload %q", gotEvent, gotSig, gotPayload)
+	}
+
+	// Ensure the signature is omitted without a secret.
+	err = postWebhook(ctx, srv.URL, webhookCategoryBlock, payload, nil)
+	if err != nil {
+		t.Fatalf("unexpected error delivering payload: %v", err)
+	}
+	if gotSig != "" {
+		t.Fatalf("unexpected signature without secret: %q", gotSig)
+	}
+
+	// Ensure a non-2xx status code is an error.
+	status = http.StatusServiceUnavailable
+	err = postWebhook(ctx, srv.URL, webhookCategoryBlock, payload, nil)
+	if err == nil {
+		t.Fatal("expected error for unavailable webhook")
+	}
+}
+
+// TestWebhookManager ensures events are only published to the webhooks
+// registered for their category, tx events are only published for transactions
+// that pay to addresses of interest, and failed deliveries are retried.
+func TestWebhookManager(t *testing.T) {
+	t.Parallel()
+
+	params := chaincfg.RegNetParams()
+	addr, err := dcrutil.NewAddressScriptHashFromHash(make([]byte, 20), params)
+	if err != nil {
+		t.Fatalf("unexpected error creating address: %v", err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatalf("unexpected error creating script: %v", err)
+	}
+
+	const blockHook, txHook = "http://127.0.0.1/block", "http://127.0.0.1/tx"
+	endpoints := map[string][]string{
+		webhookCategoryBlock: {blockHook},
+		webhookCategoryTx:    {txHook},
+	}
+	m := newWebhookManager(params, endpoints, "secret",
+		[]string{addr.Address()})
+
+	// nextEvent returns the next queued delivery along with its decoded event.
+	nextEvent := func() (*webhookDelivery, map[string]interface{}) {
+		t.Helper()
+		select {
+		case d := <-m.queue:
+			var event map[string]interface{}
+			if err := json.Unmarshal(d.payload, &event); err != nil {
+				t.Fatalf("unexpected error decoding payload: %v", err)
+			}
+			return d, event
+		default:
+			t.Fatal("expected queued delivery")
+		}
+		return nil, nil
+	}
+
+	// Ensure only the transaction that pays to the address of interest is
+	// published when transactions are accepted.
+	matching := wire.NewMsgTx()
+	matching.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, 0, nil))
+	matching.AddTxOut(wire.NewTxOut(1e8, pkScript))
+	other := wire.NewMsgTx()
+	other.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 1}, 0, nil))
+	other.AddTxOut(wire.NewTxOut(1e8, []byte{txscript.OP_TRUE}))
+	m.TxnsAccepted([]*dcrutil.Tx{dcrutil.NewTx(other), dcrutil.NewTx(matching)})
+	d, event := nextEvent()
+	data, _ := event["data"].(map[string]interface{})
+	if d.url != txHook || event["category"] != webhookCategoryTx ||
+		data["txid"] != matching.TxHash().String() {
+
+		t.Fatalf("unexpected tx delivery to %s: %v", d.url, event)
+	}
+	if len(m.queue) != 0 {
+		t.Fatalf("unexpected %d queued deliveries", len(m.queue))
+	}
+
+	// Ensure connected blocks publish a block event along with tx events for
+	// the transactions of interest in them.
+	block := dcrutil.NewBlock(&wire.MsgBlock{
+		Header:       wire.BlockHeader{Height: 100},
+		Transactions: []*wire.MsgTx{other, matching},
+	})
+	m.BlockConnected(block)
+	d, event = nextEvent()
+	data, _ = event["data"].(map[string]interface{})
+	if d.url != blockHook || event["category"] != webhookCategoryBlock ||
+		data["hash"] != block.Hash().String() || data["height"] != 100.0 {
+
+		t.Fatalf("unexpected block delivery to %s: %v", d.url, event)
+	}
+	d, event = nextEvent()
+	data, _ = event["data"].(map[string]interface{})
+	if d.url != txHook || data["blockhash"] != block.Hash().String() {
+		t.Fatalf("unexpected tx delivery to %s: %v", d.url, event)
+	}
+
+	// Ensure events for categories without webhooks are not published.
+	m.PeerBanned("127.0.0.1", true, time.Now())
+	if len(m.queue) != 0 {
+		t.Fatalf("unexpected %d queued deliveries", len(m.queue))
+	}
+
+	// Ensure failed deliveries are retried with backoff until the maximum
+	// number of attempts.
+	now := time.Unix(1592918788, 0)
+	m.now = func() time.Time { return now }
+	m.post = func(context.Context, string, string, []byte) error {
+		return errors.New("unavailable")
+	}
+	ctx := context.Background()
+	for i := uint32(1); i < maxWebhookAttempts; i++ {
+		if !m.attempt(ctx, d) {
+			t.Fatalf("delivery not retried after %d attempts", i)
+		}
+		want := now.Add(retryDelay(i, webhookRetryBaseDelay,
+			webhookRetryMaxDelay))
+		if !d.due.Equal(want) {
+			t.Fatalf("unexpected retry time -- got %v, want %v", d.due, want)
+		}
+	}
+	if m.attempt(ctx, d) {
+		t.Fatal("delivery retried after maximum attempts")
+	}
+}
@@ -121,6 +121,30 @@ const (
 	// ErrMalformedStrictString is returned when a string that has strict
 	// formatting requirements does not conform to the requirements.
 	ErrMalformedStrictString
+
+	// ErrReconSketchTooLarge is returned when a transaction reconciliation
+	// sketch exceeds the maximum allowed size.
+	ErrReconSketchTooLarge
+
+	// ErrTooManyReconShortIDs is returned when the number of transaction
+	// reconciliation short ids exceed the maximum allowed.
+	ErrTooManyReconShortIDs
+
+	// ErrBloomFilterTooLarge is returned when a bloom filter exceeds the
+	// maximum allowed size.
+	ErrBloomFilterTooLarge
+
+	// ErrTooManyBloomHashFuncs is returned when the number of hash functions
+	// of a bloom filter exceed the maximum allowed.
+	ErrTooManyBloomHashFuncs
+
+	// ErrBloomDataTooLarge is returned when the data to add to a bloom
+	// filter exceeds the maximum allowed size.
+	ErrBloomDataTooLarge
+
+	// ErrMerkleFlagsTooLarge is returned when the flags of a partial merkle
+	// tree exceed the maximum allowed size.
+	ErrMerkleFlagsTooLarge
 )
 
 // Map of ErrorCode values back to their constant names for pretty printing.
@@ -153,6 +177,12 @@ var errorCodeStrings = map[ErrorCode]string{
 	ErrUserAgentTooLong:              "ErrUserAgentTooLong",
 	ErrTooManyFilterHeaders:          "ErrTooManyFilterHeaders",
 	ErrMalformedStrictString:         "ErrMalformedStrictString",
+	ErrReconSketchTooLarge:           "ErrReconSketchTooLarge",
+	ErrTooManyReconShortIDs:          "ErrTooManyReconShortIDs",
+	ErrBloomFilterTooLarge:           "ErrBloomFilterTooLarge",
+	ErrTooManyBloomHashFuncs:         "ErrTooManyBloomHashFuncs",
+	ErrBloomDataTooLarge:             "ErrBloomDataTooLarge",
+	ErrMerkleFlagsTooLarge:           "ErrMerkleFlagsTooLarge",
 }
 
 // String returns the ErrorCode as a human-readable name.
@@ -47,6 +47,12 @@ func TestMessageErrorCodeStringer(t *testing.T) {
 		{ErrUserAgentTooLong, "ErrUserAgentTooLong"},
 		{ErrTooManyFilterHeaders, "ErrTooManyFilterHeaders"},
 		{ErrMalformedStrictString, "ErrMalformedStrictString"},
+		{ErrReconSketchTooLarge, "ErrReconSketchTooLarge"},
+		{ErrTooManyReconShortIDs, "ErrTooManyReconShortIDs"},
+		{ErrBloomFilterTooLarge, "ErrBloomFilterTooLarge"},
+		{ErrTooManyBloomHashFuncs, "ErrTooManyBloomHashFuncs"},
+		{ErrBloomDataTooLarge, "ErrBloomDataTooLarge"},
+		{ErrMerkleFlagsTooLarge, "ErrMerkleFlagsTooLarge"},
 		{0xffff, "Unknown ErrorCode (65535)"},
 	}
 
@@ -56,6 +56,14 @@ const (
 	CmdCFTypes        = "cftypes"
 	CmdGetCFilterV2   = "getcfilterv2"
 	CmdCFilterV2      = "cfilterv2"
+	CmdReqRecon       = "reqrecon"
+	CmdReconSketch    = "reconsketch"
+	CmdReconDiff      = "recondiff"
+	CmdPeerAuth       = "peerauth"
+	CmdFilterLoad     = "filterload"
+	CmdFilterAdd      = "filteradd"
+	CmdFilterClear    = "filterclear"
+	CmdMerkleBlock    = "merkleblock"
 )
 
 // Message is an interface that describes a Decred message.  A type that
@@ -160,6 +168,30 @@ func makeEmptyMessage(command string) (Message, error) {
 	case CmdCFilterV2:
 		msg = &MsgCFilterV2{}
 
+	case CmdReqRecon:
+		msg = &MsgReqRecon{}
+
+	case CmdReconSketch:
+		msg = &MsgReconSketch{}
+
+	case CmdReconDiff:
+		msg = &MsgReconDiff{}
+
+	case CmdPeerAuth:
+		msg = &MsgPeerAuth{}
+
+	case CmdFilterLoad:
+		msg = &MsgFilterLoad{}
+
+	case CmdFilterAdd:
+		msg = &MsgFilterAdd{}
+
+	case CmdFilterClear:
+		msg = &MsgFilterClear{}
+
+	case CmdMerkleBlock:
+		msg = &MsgMerkleBlock{}
+
 	default:
 		str := fmt.Sprintf("unhandled command [%s]", command)
 		return nil, messageError(op, ErrUnknownCmd, str)
@@ -303,6 +335,22 @@ func WriteMessage(w io.Writer, msg Message, pver uint32, dcrnet CurrencyNet) err
 // message.  This function is the same as ReadMessage except it also returns the
 // number of bytes read.
 func ReadMessageN(r io.Reader, pver uint32, dcrnet CurrencyNet) (int, Message, []byte, error) {
+	return ReadMessageLimitedN(r, pver, dcrnet, nil)
+}
+
+// PayloadLimitFunc defines a function that returns the maximum payload size, in
+// bytes, that is accepted for messages with the provided command.  A return
+// value of zero indicates there is no limit other than the maximum payload size
+// imposed by the protocol for the message type.
+type PayloadLimitFunc func(command string) uint32
+
+// ReadMessageLimitedN is identical to ReadMessageN except it additionally
+// rejects messages with payloads larger than the limit returned by the provided
+// function for their command before any of the payload is read into memory.
+// This allows callers to impose stricter limits than the protocol for specific
+// message types.  The function may be nil in which case only the limits imposed
+// by the protocol are enforced.
+func ReadMessageLimitedN(r io.Reader, pver uint32, dcrnet CurrencyNet, limit PayloadLimitFunc) (int, Message, []byte, error) {
 	const op = "ReadMessage"
 	totalBytes := 0
 	n, hdr, err := readMessageHeader(r)
@@ -353,6 +401,18 @@ func ReadMessageN(r io.Reader, pver uint32, dcrnet CurrencyNet) (int, Message, [
 		return totalBytes, nil, nil, messageError(op, ErrPayloadTooLarge, msg)
 	}
 
+	// Enforce any additional limit imposed by the caller for the message type.
+	if limit != nil {
+		if maxLen := limit(command); maxLen != 0 && hdr.length > maxLen {
+			discardInput(r, hdr.length)
+			msg := fmt.Sprintf("payload exceeds configured limit - header "+
+				"indicates %v bytes, but the limit for messages of type "+
+				"[%v] is %v.", hdr.length, command, maxLen)
+			return totalBytes, nil, nil, messageError(op, ErrPayloadTooLarge,
+				msg)
+		}
+	}
+
 	// Read payload.
 	payload := make([]byte, hdr.length)
 	n, err = io.ReadFull(r, payload)
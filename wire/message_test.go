@@ -78,6 +78,20 @@ func TestMessage(t *testing.T) {
 	msgCFHeaders := NewMsgCFHeaders()
 	msgCFTypes := NewMsgCFTypes([]FilterType{GCSFilterExtended})
 	msgReject := NewMsgReject("block", RejectDuplicate, "duplicate block")
+	msgReqRecon := NewMsgReqRecon(10, 20)
+	msgReconSketch := NewMsgReconSketch([]byte{0x01, 0x02, 0x03})
+	msgReconDiff := NewMsgReconDiff(true, []uint32{1, 2})
+	msgPeerAuth, _ := testPeerAuthMsg()
+	msgFilterLoad := NewMsgFilterLoad([]byte{0x01}, 10, 0, BloomUpdateNone)
+	msgFilterAdd := NewMsgFilterAdd([]byte{0x01})
+	msgFilterClear := NewMsgFilterClear()
+	msgMerkleBlock := NewMsgMerkleBlock(&testBlock.Header)
+	msgMerkleBlock.Transactions = 1
+	msgMerkleBlock.AddTxHash(&chainhash.Hash{})
+	msgMerkleBlock.STransactions = 1
+	msgMerkleBlock.AddSTxHash(&chainhash.Hash{})
+	msgMerkleBlock.Flags = []byte{0x01}
+	msgMerkleBlock.SFlags = []byte{0x01}
 
 	tests := []struct {
 		in     Message     // Value to encode
@@ -108,6 +122,14 @@ func TestMessage(t *testing.T) {
 		{msgCFilter, msgCFilter, pver, MainNet, 65},           // [24]
 		{msgCFHeaders, msgCFHeaders, pver, MainNet, 58},       // [25]
 		{msgCFTypes, msgCFTypes, pver, MainNet, 26},           // [26]
+		{msgReqRecon, msgReqRecon, pver, MainNet, 36},         // [27]
+		{msgReconSketch, msgReconSketch, pver, MainNet, 28},   // [28]
+		{msgReconDiff, msgReconDiff, pver, MainNet, 34},       // [29]
+		{msgPeerAuth, msgPeerAuth, pver, MainNet, 121},        // [30]
+		{msgFilterLoad, msgFilterLoad, pver, MainNet, 35},     // [31]
+		{msgFilterAdd, msgFilterAdd, pver, MainNet, 26},       // [32]
+		{msgFilterClear, msgFilterClear, pver, MainNet, 24},   // [33]
+		{msgMerkleBlock, msgMerkleBlock, pver, MainNet, 282},  // [34]
 	}
 
 	t.Logf("Running %d tests", len(tests))
@@ -362,6 +384,78 @@ func TestReadMessageWireErrors(t *testing.T) {
 	}
 }
 
+// TestReadMessageLimited ensures reading messages with caller imposed payload
+// limits rejects messages that exceed the limit for their type while accepting
+// those that do not.
+func TestReadMessageLimited(t *testing.T) {
+	pver := ProtocolVersion
+	dcrnet := MainNet
+
+	// Limit pings to less than their 8 byte payload and pongs to exactly
+	// their payload while leaving all other messages unlimited.
+	limit := func(command string) uint32 {
+		switch command {
+		case CmdPing:
+			return 7
+		case CmdPong:
+			return 8
+		}
+		return 0
+	}
+
+	tests := []struct {
+		name    string  // test description
+		msg     Message // message to encode
+		wantErr error   // expected read error
+	}{{
+		name:    "ping exceeds limit",
+		msg:     NewMsgPing(1),
+		wantErr: ErrPayloadTooLarge,
+	}, {
+		name:    "pong at limit",
+		msg:     NewMsgPong(1),
+		wantErr: nil,
+	}, {
+		name:    "unlimited message",
+		msg:     NewMsgVerAck(),
+		wantErr: nil,
+	}}
+
+	for _, test := range tests {
+		var buf bytes.Buffer
+		if err := WriteMessage(&buf, test.msg, pver, dcrnet); err != nil {
+			t.Fatalf("%q: unexpected write error: %v", test.name, err)
+		}
+		wantBytes := buf.Len()
+		if test.wantErr != nil {
+			// Only the header is counted since the payload is discarded.
+			wantBytes = MessageHeaderSize
+		}
+
+		nr, msg, _, err := ReadMessageLimitedN(&buf, pver, dcrnet, limit)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("%q: unexpected error -- got %v, want %v", test.name,
+				err, test.wantErr)
+			continue
+		}
+		if nr != wantBytes {
+			t.Errorf("%q: unexpected num bytes read -- got %d, want %d",
+				test.name, nr, wantBytes)
+			continue
+		}
+		if err == nil && !reflect.DeepEqual(msg, test.msg) {
+			t.Errorf("%q: mismatched message -- got %v, want %v",
+				test.name, spew.Sdump(msg), spew.Sdump(test.msg))
+			continue
+		}
+
+		// Ensure the entire payload of rejected messages is discarded.
+		if buf.Len() != 0 {
+			t.Errorf("%q: %d bytes remain unread", test.name, buf.Len())
+		}
+	}
+}
+
 // TestWriteMessageWireErrors performs negative tests against wire encoding from
 // concrete messages to confirm error paths work correctly.
 func TestWriteMessageWireErrors(t *testing.T) {
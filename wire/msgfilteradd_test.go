@@ -0,0 +1,188 @@
+// Copyright (c) 2014-2016 The btcsuite developers
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// TestFilterAdd tests the MsgFilterAdd API against the latest protocol
+// version.
+func TestFilterAdd(t *testing.T) {
+	pver := ProtocolVersion
+
+	// Ensure the command is expected value.
+	wantCmd := "filteradd"
+	msg := NewMsgFilterAdd([]byte{0x01})
+	if cmd := msg.Command(); cmd != wantCmd {
+		t.Errorf("NewMsgFilterAdd: wrong command - got %v want %v", cmd,
+			wantCmd)
+	}
+
+	// Ensure max payload is expected value for latest protocol version.
+	// Max data element (including varint).
+	wantPayload := uint32(523)
+	maxPayload := msg.MaxPayloadLength(pver)
+	if maxPayload != wantPayload {
+		t.Errorf("MaxPayloadLength: wrong max payload length for protocol "+
+			"version %d - got %v, want %v", pver, maxPayload, wantPayload)
+	}
+
+	// Ensure max payload length is not more than MaxMessagePayload.
+	if maxPayload > MaxMessagePayload {
+		t.Fatalf("MaxPayloadLength: payload length (%v) for protocol version "+
+			"%d exceeds MaxMessagePayload (%v).", maxPayload, pver,
+			MaxMessagePayload)
+	}
+}
+
+// TestFilterAddPreviousProtocol tests the MsgFilterAdd API against the
+// protocol prior to version BloomFilterVersion.
+func TestFilterAddPreviousProtocol(t *testing.T) {
+	// Use the protocol version just prior to BloomFilterVersion changes.
+	pver := BloomFilterVersion - 1
+
+	msg := NewMsgFilterAdd([]byte{0x01})
+
+	// Test encode with old protocol version.
+	var buf bytes.Buffer
+	err := msg.BtcEncode(&buf, pver)
+	if !errors.Is(err, ErrMsgInvalidForPVer) {
+		t.Errorf("unexpected error when encoding for protocol version %d, "+
+			"prior to message introduction - got %v, want %v", pver,
+			err, ErrMsgInvalidForPVer)
+	}
+
+	// Test decode with old protocol version.
+	var readmsg MsgFilterAdd
+	err = readmsg.BtcDecode(&buf, pver)
+	if !errors.Is(err, ErrMsgInvalidForPVer) {
+		t.Errorf("unexpected error when decoding for protocol version %d, "+
+			"prior to message introduction - got %v, want %v", pver,
+			err, ErrMsgInvalidForPVer)
+	}
+}
+
+// TestFilterAddWire tests the MsgFilterAdd wire encode and decode for various
+// protocol versions.
+func TestFilterAddWire(t *testing.T) {
+	msgFilterAdd := NewMsgFilterAdd([]byte{0x01, 0x02, 0x03, 0x04})
+	msgFilterAddEncoded := []byte{
+		0x04,                   // Varint for data size
+		0x01, 0x02, 0x03, 0x04, // Data
+	}
+
+	tests := []struct {
+		in   *MsgFilterAdd // Message to encode
+		out  *MsgFilterAdd // Expected decoded message
+		buf  []byte        // Wire encoding
+		pver uint32        // Protocol version for wire encoding
+	}{{
+		// Latest protocol version.
+		msgFilterAdd,
+		msgFilterAdd,
+		msgFilterAddEncoded,
+		ProtocolVersion,
+	}, {
+		// Protocol version BloomFilterVersion.
+		msgFilterAdd,
+		msgFilterAdd,
+		msgFilterAddEncoded,
+		BloomFilterVersion,
+	}}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		// Encode the message to wire format.
+		var buf bytes.Buffer
+		err := test.in.BtcEncode(&buf, test.pver)
+		if err != nil {
+			t.Errorf("BtcEncode #%d error %v", i, err)
+			continue
+		}
+		if !bytes.Equal(buf.Bytes(), test.buf) {
+			t.Errorf("BtcEncode #%d\n got: %s want: %s", i,
+				spew.Sdump(buf.Bytes()), spew.Sdump(test.buf))
+			continue
+		}
+
+		// Decode the message from wire format.
+		var msg MsgFilterAdd
+		rbuf := bytes.NewReader(test.buf)
+		err = msg.BtcDecode(rbuf, test.pver)
+		if err != nil {
+			t.Errorf("BtcDecode #%d error %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(&msg, test.out) {
+			t.Errorf("BtcDecode #%d\n got: %s want: %s", i, spew.Sdump(&msg),
+				spew.Sdump(test.out))
+			continue
+		}
+	}
+}
+
+// TestFilterAddWireErrors performs negative tests against wire encode and
+// decode of MsgFilterAdd to confirm error paths work correctly.
+func TestFilterAddWireErrors(t *testing.T) {
+	pver := ProtocolVersion
+
+	baseFilterAdd := NewMsgFilterAdd([]byte{0x01, 0x02, 0x03, 0x04})
+	baseFilterAddEncoded := []byte{
+		0x04,                   // Varint for data size
+		0x01, 0x02, 0x03, 0x04, // Data
+	}
+
+	// Message with data that exceeds the max allowed size.
+	maxData := NewMsgFilterAdd(make([]byte, MaxFilterAddDataSize+1))
+	maxDataEncoded := []byte{
+		0xfd, 0x09, 0x02, // Varint for data size
+	}
+
+	tests := []struct {
+		in       *MsgFilterAdd // Value to encode
+		buf      []byte        // Wire encoding
+		pver     uint32        // Protocol version for wire encoding
+		max      int           // Max size of fixed buffer to induce errors
+		writeErr error         // Expected write error
+		readErr  error         // Expected read error
+	}{
+		// Force error in start of data size.
+		{baseFilterAdd, baseFilterAddEncoded, pver, 0, io.ErrShortWrite, io.EOF},
+		// Force error in middle of data.
+		{baseFilterAdd, baseFilterAddEncoded, pver, 3, io.ErrShortWrite, io.ErrUnexpectedEOF},
+		// Force error with data too large.
+		{maxData, maxDataEncoded, pver, len(maxDataEncoded), ErrBloomDataTooLarge, ErrVarBytesTooLong},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		// Encode to wire format.
+		w := newFixedWriter(test.max)
+		err := test.in.BtcEncode(w, test.pver)
+		if !errors.Is(err, test.writeErr) {
+			t.Errorf("BtcEncode #%d wrong error got: %v, want: %v", i, err,
+				test.writeErr)
+			continue
+		}
+
+		// Decode from wire format.
+		var msg MsgFilterAdd
+		r := newFixedReader(test.max, test.buf)
+		err = msg.BtcDecode(r, test.pver)
+		if !errors.Is(err, test.readErr) {
+			t.Errorf("BtcDecode #%d wrong error got: %v, want: %v", i, err,
+				test.readErr)
+			continue
+		}
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright (c) 2014-2016 The btcsuite developers
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// TestFilterLoad tests the MsgFilterLoad API against the latest protocol
+// version.
+func TestFilterLoad(t *testing.T) {
+	pver := ProtocolVersion
+
+	// Ensure the command is expected value.
+	wantCmd := "filterload"
+	msg := NewMsgFilterLoad([]byte{0x01}, 10, 0, BloomUpdateNone)
+	if cmd := msg.Command(); cmd != wantCmd {
+		t.Errorf("NewMsgFilterLoad: wrong command - got %v want %v", cmd,
+			wantCmd)
+	}
+
+	// Ensure max payload is expected value for latest protocol version.
+	// Max filter data (including varint) + hash funcs + tweak + flags.
+	wantPayload := uint32(36012)
+	maxPayload := msg.MaxPayloadLength(pver)
+	if maxPayload != wantPayload {
+		t.Errorf("MaxPayloadLength: wrong max payload length for protocol "+
+			"version %d - got %v, want %v", pver, maxPayload, wantPayload)
+	}
+
+	// Ensure max payload length is not more than MaxMessagePayload.
+	if maxPayload > MaxMessagePayload {
+		t.Fatalf("MaxPayloadLength: payload length (%v) for protocol version "+
+			"%d exceeds MaxMessagePayload (%v).", maxPayload, pver,
+			MaxMessagePayload)
+	}
+}
+
+// TestFilterLoadPreviousProtocol tests the MsgFilterLoad API against the
+// protocol prior to version BloomFilterVersion.
+func TestFilterLoadPreviousProtocol(t *testing.T) {
+	// Use the protocol version just prior to BloomFilterVersion changes.
+	pver := BloomFilterVersion - 1
+
+	msg := NewMsgFilterLoad([]byte{0x01}, 10, 0, BloomUpdateNone)
+
+	// Test encode with old protocol version.
+	var buf bytes.Buffer
+	err := msg.BtcEncode(&buf, pver)
+	if !errors.Is(err, ErrMsgInvalidForPVer) {
+		t.Errorf("unexpected error when encoding for protocol version %d, "+
+			"prior to message introduction - got %v, want %v", pver,
+			err, ErrMsgInvalidForPVer)
+	}
+
+	// Test decode with old protocol version.
+	var readmsg MsgFilterLoad
+	err = readmsg.BtcDecode(&buf, pver)
+	if !errors.Is(err, ErrMsgInvalidForPVer) {
+		t.Errorf("unexpected error when decoding for protocol version %d, "+
+			"prior to message introduction - got %v, want %v", pver,
+			err, ErrMsgInvalidForPVer)
+	}
+}
+
+// TestFilterLoadWire tests the MsgFilterLoad wire encode and decode for
+// various protocol versions.
+func TestFilterLoadWire(t *testing.T) {
+	msgFilterLoad := NewMsgFilterLoad([]byte{0x01, 0x02}, 10, 0x01020304,
+		BloomUpdateAll)
+	msgFilterLoadEncoded := []byte{
+		0x02,       // Varint for filter size
+		0x01, 0x02, // Filter
+		0x0a, 0x00, 0x00, 0x00, // Hash funcs
+		0x04, 0x03, 0x02, 0x01, // Tweak
+		0x01, // Flags
+	}
+
+	tests := []struct {
+		in   *MsgFilterLoad // Message to encode
+		out  *MsgFilterLoad // Expected decoded message
+		buf  []byte         // Wire encoding
+		pver uint32         // Protocol version for wire encoding
+	}{{
+		// Latest protocol version.
+		msgFilterLoad,
+		msgFilterLoad,
+		msgFilterLoadEncoded,
+		ProtocolVersion,
+	}, {
+		// Protocol version BloomFilterVersion.
+		msgFilterLoad,
+		msgFilterLoad,
+		msgFilterLoadEncoded,
+		BloomFilterVersion,
+	}}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		// Encode the message to wire format.
+		var buf bytes.Buffer
+		err := test.in.BtcEncode(&buf, test.pver)
+		if err != nil {
+			t.Errorf("BtcEncode #%d error %v", i, err)
+			continue
+		}
+		if !bytes.Equal(buf.Bytes(), test.buf) {
+			t.Errorf("BtcEncode #%d\n got: %s want: %s", i,
+				spew.Sdump(buf.Bytes()), spew.Sdump(test.buf))
+			continue
+		}
+
+		// Decode the message from wire format.
+		var msg MsgFilterLoad
+		rbuf := bytes.NewReader(test.buf)
+		err = msg.BtcDecode(rbuf, test.pver)
+		if err != nil {
+			t.Errorf("BtcDecode #%d error %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(&msg, test.out) {
+			t.Errorf("BtcDecode #%d\n got: %s want: %s", i, spew.Sdump(&msg),
+				spew.Sdump(test.out))
+			continue
+		}
+	}
+}
+
+// TestFilterLoadWireErrors performs negative tests against wire encode and
+// decode of MsgFilterLoad to confirm error paths work correctly.
+func TestFilterLoadWireErrors(t *testing.T) {
+	pver := ProtocolVersion
+
+	baseFilterLoad := NewMsgFilterLoad([]byte{0x01, 0x02}, 10, 0x01020304,
+		BloomUpdateAll)
+	baseFilterLoadEncoded := []byte{
+		0x02,       // Varint for filter size
+		0x01, 0x02, // Filter
+		0x0a, 0x00, 0x00, 0x00, // Hash funcs
+		0x04, 0x03, 0x02, 0x01, // Tweak
+		0x01, // Flags
+	}
+
+	// Message with a filter that exceeds the max allowed size.
+	maxFilter := NewMsgFilterLoad(make([]byte, MaxFilterLoadFilterSize+1), 10,
+		0, BloomUpdateNone)
+	maxFilterEncoded := []byte{
+		0xfd, 0xa1, 0x8c, // Varint for filter size
+	}
+
+	// Message with more hash functions than allowed.
+	maxHashFuncs := NewMsgFilterLoad(nil, MaxFilterLoadHashFuncs+1, 0,
+		BloomUpdateNone)
+	maxHashFuncsEncoded := []byte{
+		0x00,                   // Varint for filter size
+		0x33, 0x00, 0x00, 0x00, // Hash funcs
+		0x00, 0x00, 0x00, 0x00, // Tweak
+		0x00, // Flags
+	}
+
+	tests := []struct {
+		in       *MsgFilterLoad // Value to encode
+		buf      []byte         // Wire encoding
+		pver     uint32         // Protocol version for wire encoding
+		max      int            // Max size of fixed buffer to induce errors
+		writeErr error          // Expected write error
+		readErr  error          // Expected read error
+	}{
+		// Force error in start of filter size.
+		{baseFilterLoad, baseFilterLoadEncoded, pver, 0, io.ErrShortWrite, io.EOF},
+		// Force error in middle of filter.
+		{baseFilterLoad, baseFilterLoadEncoded, pver, 2, io.ErrShortWrite, io.ErrUnexpectedEOF},
+		// Force error in start of hash funcs.
+		{baseFilterLoad, baseFilterLoadEncoded, pver, 3, io.ErrShortWrite, io.EOF},
+		// Force error in start of tweak.
+		{baseFilterLoad, baseFilterLoadEncoded, pver, 7, io.ErrShortWrite, io.EOF},
+		// Force error in start of flags.
+		{baseFilterLoad, baseFilterLoadEncoded, pver, 11, io.ErrShortWrite, io.EOF},
+		// Force error with filter too large.
+		{maxFilter, maxFilterEncoded, pver, len(maxFilterEncoded), ErrBloomFilterTooLarge, ErrVarBytesTooLong},
+		// Force error with too many hash funcs.
+		{maxHashFuncs, maxHashFuncsEncoded, pver, len(maxHashFuncsEncoded), ErrTooManyBloomHashFuncs, ErrTooManyBloomHashFuncs},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		// Encode to wire format.
+		w := newFixedWriter(test.max)
+		err := test.in.BtcEncode(w, test.pver)
+		if !errors.Is(err, test.writeErr) {
+			t.Errorf("BtcEncode #%d wrong error got: %v, want: %v", i, err,
+				test.writeErr)
+			continue
+		}
+
+		// Decode from wire format.
+		var msg MsgFilterLoad
+		r := newFixedReader(test.max, test.buf)
+		err = msg.BtcDecode(r, test.pver)
+		if !errors.Is(err, test.readErr) {
+			t.Errorf("BtcDecode #%d wrong error got: %v, want: %v", i, err,
+				test.readErr)
+			continue
+		}
+	}
+}
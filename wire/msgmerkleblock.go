@@ -0,0 +1,227 @@
+// Copyright (c) 2014-2016 The btcsuite developers
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+// maxFlagsPerMerkleTree returns the maximum number of flag bytes that could
+// possibly fit into a partial merkle tree for a single transaction tree for the
+// given protocol version.  Each node of the tree that is traversed is assigned
+// a flag bit and a tree has fewer than twice as many nodes as leaves.
+func maxFlagsPerMerkleTree(pver uint32) uint64 {
+	return (MaxTxPerTxTree(pver)*2 + 7) / 8
+}
+
+// MsgMerkleBlock implements the Message interface and represents a decred
+// merkleblock message.  It houses the header of a block along with partial merkle trees for both the
+// regular and stake transaction trees that prove the inclusion of the
+// transactions that match the bloom filter loaded by the requesting peer.  The
+// matching transactions themselves are sent in separate tx messages.
+//
+// This message was not added until protocol versions starting with
+// BloomFilterVersion.
+type MsgMerkleBlock struct {
+	Header        BlockHeader
+	Transactions  uint32
+	Hashes        []*chainhash.Hash
+	STransactions uint32
+	SHashes       []*chainhash.Hash
+	Flags         []byte
+	SFlags        []byte
+}
+
+// AddTxHash adds a new regular transaction tree hash to the message.
+func (msg *MsgMerkleBlock) AddTxHash(hash *chainhash.Hash) error {
+	const op = "MsgMerkleBlock.AddTxHash"
+	if uint64(len(msg.Hashes)+1) > MaxTxPerTxTree(ProtocolVersion) {
+		msg := fmt.Sprintf("too many tx hashes for message [max %v]",
+			MaxTxPerTxTree(ProtocolVersion))
+		return messageError(op, ErrTooManyTxs, msg)
+	}
+
+	msg.Hashes = append(msg.Hashes, hash)
+	return nil
+}
+
+// AddSTxHash adds a new stake transaction tree hash to the message.
+func (msg *MsgMerkleBlock) AddSTxHash(hash *chainhash.Hash) error {
+	const op = "MsgMerkleBlock.AddSTxHash"
+	if uint64(len(msg.SHashes)+1) > MaxTxPerTxTree(ProtocolVersion) {
+		msg := fmt.Sprintf("too many stake tx hashes for message [max %v]",
+			MaxTxPerTxTree(ProtocolVersion))
+		return messageError(op, ErrTooManyTxs, msg)
+	}
+
+	msg.SHashes = append(msg.SHashes, hash)
+	return nil
+}
+
+// readMerkleHashes reads a list of partial merkle tree hashes from r and limits
+// them to the maximum number of transactions per tree.
+func readMerkleHashes(op string, r io.Reader, pver uint32) ([]*chainhash.Hash, error) {
+	count, err := ReadVarInt(r, pver)
+	if err != nil {
+		return nil, err
+	}
+	if count > MaxTxPerTxTree(pver) {
+		msg := fmt.Sprintf("too many transaction hashes for message "+
+			"[count %v, max %v]", count, MaxTxPerTxTree(pver))
+		return nil, messageError(op, ErrTooManyTxs, msg)
+	}
+
+	// Create a contiguous slice of hashes to deserialize into in order to
+	// reduce the number of allocations.
+	hashes := make([]chainhash.Hash, count)
+	hashPtrs := make([]*chainhash.Hash, 0, count)
+	for i := uint64(0); i < count; i++ {
+		hash := &hashes[i]
+		if err := readElement(r, hash); err != nil {
+			return nil, err
+		}
+		hashPtrs = append(hashPtrs, hash)
+	}
+	return hashPtrs, nil
+}
+
+// writeMerkleHashes writes the passed list of partial merkle tree hashes to w
+// after ensuring they do not exceed the maximum number of transactions per
+// tree.
+func writeMerkleHashes(op string, w io.Writer, pver uint32, hashes []*chainhash.Hash) error {
+	count := uint64(len(hashes))
+	if count > MaxTxPerTxTree(pver) {
+		msg := fmt.Sprintf("too many transaction hashes for message "+
+			"[count %v, max %v]", count, MaxTxPerTxTree(pver))
+		return messageError(op, ErrTooManyTxs, msg)
+	}
+
+	if err := WriteVarInt(w, pver, count); err != nil {
+		return err
+	}
+	for _, hash := range hashes {
+		if err := writeElement(w, hash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BtcDecode decodes r using the Decred protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgMerkleBlock) BtcDecode(r io.Reader, pver uint32) error {
+	const op = "MsgMerkleBlock.BtcDecode"
+	if pver < BloomFilterVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	err := readBlockHeader(r, pver, &msg.Header)
+	if err != nil {
+		return err
+	}
+
+	err = readElement(r, &msg.Transactions)
+	if err != nil {
+		return err
+	}
+	msg.Hashes, err = readMerkleHashes(op, r, pver)
+	if err != nil {
+		return err
+	}
+
+	err = readElement(r, &msg.STransactions)
+	if err != nil {
+		return err
+	}
+	msg.SHashes, err = readMerkleHashes(op, r, pver)
+	if err != nil {
+		return err
+	}
+
+	maxFlags := uint32(maxFlagsPerMerkleTree(pver))
+	msg.Flags, err = ReadVarBytes(r, pver, maxFlags, "merkle block flags")
+	if err != nil {
+		return err
+	}
+	msg.SFlags, err = ReadVarBytes(r, pver, maxFlags,
+		"merkle block stake flags")
+	return err
+}
+
+// BtcEncode encodes the receiver to w using the Decred protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgMerkleBlock) BtcEncode(w io.Writer, pver uint32) error {
+	const op = "MsgMerkleBlock.BtcEncode"
+	if pver < BloomFilterVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	maxFlags := maxFlagsPerMerkleTree(pver)
+	if uint64(len(msg.Flags)) > maxFlags ||
+		uint64(len(msg.SFlags)) > maxFlags {
+
+		msg := fmt.Sprintf("too many flag bytes for message [regular "+
+			"%v, stake %v, max %v]", len(msg.Flags), len(msg.SFlags),
+			maxFlags)
+		return messageError(op, ErrMerkleFlagsTooLarge, msg)
+	}
+
+	err := writeBlockHeader(w, pver, &msg.Header)
+	if err != nil {
+		return err
+	}
+
+	err = writeElement(w, msg.Transactions)
+	if err != nil {
+		return err
+	}
+	err = writeMerkleHashes(op, w, pver, msg.Hashes)
+	if err != nil {
+		return err
+	}
+
+	err = writeElement(w, msg.STransactions)
+	if err != nil {
+		return err
+	}
+	err = writeMerkleHashes(op, w, pver, msg.SHashes)
+	if err != nil {
+		return err
+	}
+
+	err = WriteVarBytes(w, pver, msg.Flags)
+	if err != nil {
+		return err
+	}
+	return WriteVarBytes(w, pver, msg.SFlags)
+}
+
+// Command returns the protocol command string for the message.  This is part
+// of the Message interface implementation.
+func (msg *MsgMerkleBlock) Command() string {
+	return CmdMerkleBlock
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgMerkleBlock) MaxPayloadLength(pver uint32) uint32 {
+	return MaxBlockPayload
+}
+
+// NewMsgMerkleBlock returns a new Decred merkleblock message that conforms to
+// the Message interface.  See MsgMerkleBlock for details.
+func NewMsgMerkleBlock(bh *BlockHeader) *MsgMerkleBlock {
+	return &MsgMerkleBlock{
+		Header: *bh,
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright (c) 2014-2016 The btcsuite developers
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+// testMerkleBlock returns a merkle block with a single regular and stake
+// transaction hash along with its wire encoding.
+func testMerkleBlock() (*MsgMerkleBlock, []byte) {
+	txHash, stxHash := chainhash.Hash{0x01}, chainhash.Hash{0x02}
+	msg := NewMsgMerkleBlock(&testBlock.Header)
+	msg.Transactions = 2
+	msg.AddTxHash(&txHash)
+	msg.STransactions = 1
+	msg.AddSTxHash(&stxHash)
+	msg.Flags = []byte{0x05}
+	msg.SFlags = []byte{0x01}
+
+	// The header encoding is the first 180 bytes of the test block.
+	encoded := make([]byte, 0, 258)
+	encoded = append(encoded, testBlockBytes[:180]...)
+	encoded = append(encoded, 0x02, 0x00, 0x00, 0x00) // Num transactions
+	encoded = append(encoded, 0x01)                   // Varint for num hashes
+	encoded = append(encoded, txHash[:]...)
+	encoded = append(encoded, 0x01, 0x00, 0x00, 0x00) // Num stake transactions
+	encoded = append(encoded, 0x01)                   // Varint for num stake hashes
+	encoded = append(encoded, stxHash[:]...)
+	encoded = append(encoded, 0x01, 0x05) // Flags
+	encoded = append(encoded, 0x01, 0x01) // Stake flags
+	return msg, encoded
+}
+
+// TestMerkleBlock tests the MsgMerkleBlock API against the latest protocol
+// version.
+func TestMerkleBlock(t *testing.T) {
+	pver := ProtocolVersion
+
+	// Ensure the command is expected value.
+	wantCmd := "merkleblock"
+	msg := NewMsgMerkleBlock(&testBlock.Header)
+	if cmd := msg.Command(); cmd != wantCmd {
+		t.Errorf("NewMsgMerkleBlock: wrong command - got %v want %v", cmd,
+			wantCmd)
+	}
+
+	// Ensure max payload is expected value for latest protocol version.
+	wantPayload := uint32(MaxBlockPayload)
+	maxPayload := msg.MaxPayloadLength(pver)
+	if maxPayload != wantPayload {
+		t.Errorf("MaxPayloadLength: wrong max payload length for protocol "+
+			"version %d - got %v, want %v", pver, maxPayload, wantPayload)
+	}
+
+	// Ensure max payload length is not more than MaxMessagePayload.
+	if maxPayload > MaxMessagePayload {
+		t.Fatalf("MaxPayloadLength: payload length (%v) for protocol version "+
+			"%d exceeds MaxMessagePayload (%v).", maxPayload, pver,
+			MaxMessagePayload)
+	}
+
+	// Ensure adding more transaction hashes than a tree may hold fails.
+	maxTxPerTree := MaxTxPerTxTree(pver)
+	for i := uint64(0); i < maxTxPerTree; i++ {
+		if err := msg.AddTxHash(&chainhash.Hash{}); err != nil {
+			t.Fatalf("AddTxHash: unexpected error: %v", err)
+		}
+		if err := msg.AddSTxHash(&chainhash.Hash{}); err != nil {
+			t.Fatalf("AddSTxHash: unexpected error: %v", err)
+		}
+	}
+	if err := msg.AddTxHash(&chainhash.Hash{}); !errors.Is(err, ErrTooManyTxs) {
+		t.Fatalf("AddTxHash: wrong error - got %v, want %v", err,
+			ErrTooManyTxs)
+	}
+	if err := msg.AddSTxHash(&chainhash.Hash{}); !errors.Is(err, ErrTooManyTxs) {
+		t.Fatalf("AddSTxHash: wrong error - got %v, want %v", err,
+			ErrTooManyTxs)
+	}
+}
+
+// TestMerkleBlockPreviousProtocol tests the MsgMerkleBlock API against the
+// protocol prior to version BloomFilterVersion.
+func TestMerkleBlockPreviousProtocol(t *testing.T) {
+	// Use the protocol version just prior to BloomFilterVersion changes.
+	pver := BloomFilterVersion - 1
+
+	msg, _ := testMerkleBlock()
+
+	// Test encode with old protocol version.
+	var buf bytes.Buffer
+	err := msg.BtcEncode(&buf, pver)
+	if !errors.Is(err, ErrMsgInvalidForPVer) {
+		t.Errorf("unexpected error when encoding for protocol version %d, "+
+			"prior to message introduction - got %v, want %v", pver,
+			err, ErrMsgInvalidForPVer)
+	}
+
+	// Test decode with old protocol version.
+	var readmsg MsgMerkleBlock
+	err = readmsg.BtcDecode(&buf, pver)
+	if !errors.Is(err, ErrMsgInvalidForPVer) {
+		t.Errorf("unexpected error when decoding for protocol version %d, "+
+			"prior to message introduction - got %v, want %v", pver,
+			err, ErrMsgInvalidForPVer)
+	}
+}
+
+// TestMerkleBlockWire tests the MsgMerkleBlock wire encode and decode for
+// various protocol versions.
+func TestMerkleBlockWire(t *testing.T) {
+	msgMerkleBlock, msgMerkleBlockEncoded := testMerkleBlock()
+
+	tests := []struct {
+		in   *MsgMerkleBlock // Message to encode
+		out  *MsgMerkleBlock // Expected decoded message
+		buf  []byte          // Wire encoding
+		pver uint32          // Protocol version for wire encoding
+	}{{
+		// Latest protocol version.
+		msgMerkleBlock,
+		msgMerkleBlock,
+		msgMerkleBlockEncoded,
+		ProtocolVersion,
+	}, {
+		// Protocol version BloomFilterVersion.
+		msgMerkleBlock,
+		msgMerkleBlock,
+		msgMerkleBlockEncoded,
+		BloomFilterVersion,
+	}}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		// Encode the message to wire format.
+		var buf bytes.Buffer
+		err := test.in.BtcEncode(&buf, test.pver)
+		if err != nil {
+			t.Errorf("BtcEncode #%d error %v", i, err)
+			continue
+		}
+		if !bytes.Equal(buf.Bytes(), test.buf) {
+			t.Errorf("BtcEncode #%d\n got: %s want: %s", i,
+				spew.Sdump(buf.Bytes()), spew.Sdump(test.buf))
+			continue
+		}
+
+		// Decode the message from wire format.
+		var msg MsgMerkleBlock
+		rbuf := bytes.NewReader(test.buf)
+		err = msg.BtcDecode(rbuf, test.pver)
+		if err != nil {
+			t.Errorf("BtcDecode #%d error %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(&msg, test.out) {
+			t.Errorf("BtcDecode #%d\n got: %s want: %s", i, spew.Sdump(&msg),
+				spew.Sdump(test.out))
+			continue
+		}
+	}
+}
+
+// TestMerkleBlockWireErrors performs negative tests against wire encode and
+// decode of MsgMerkleBlock to confirm error paths work correctly.
+func TestMerkleBlockWireErrors(t *testing.T) {
+	pver := ProtocolVersion
+
+	baseMerkleBlock, baseMerkleBlockEncoded := testMerkleBlock()
+
+	// Message with more flag bytes than allowed.
+	maxFlags := NewMsgMerkleBlock(&testBlock.Header)
+	maxFlags.Flags = make([]byte, maxFlagsPerMerkleTree(pver)+1)
+	maxFlagsEncoded := make([]byte, 0, 195)
+	maxFlagsEncoded = append(maxFlagsEncoded, testBlockBytes[:180]...)
+	maxFlagsEncoded = append(maxFlagsEncoded,
+		0x00, 0x00, 0x00, 0x00, // Num transactions
+		0x00,                   // Varint for num hashes
+		0x00, 0x00, 0x00, 0x00, // Num stake transactions
+		0x00,             // Varint for num stake hashes
+		0xfd, 0xac, 0x2a, // Varint for flags size
+		0x00, // Flags
+	)
+
+	// Message with more transaction hashes than allowed.
+	maxHashesEncoded := make([]byte, 0, 187)
+	maxHashesEncoded = append(maxHashesEncoded, testBlockBytes[:180]...)
+	maxHashesEncoded = append(maxHashesEncoded,
+		0x00, 0x00, 0x00, 0x00, // Num transactions
+		0xfd, 0xac, 0xaa, // Varint for num hashes
+	)
+
+	tests := []struct {
+		in       *MsgMerkleBlock // Value to encode
+		buf      []byte          // Wire encoding
+		pver     uint32          // Protocol version for wire encoding
+		max      int             // Max size of fixed buffer to induce errors
+		writeErr error           // Expected write error
+		readErr  error           // Expected read error
+	}{
+		// Force error in header.
+		{baseMerkleBlock, baseMerkleBlockEncoded, pver, 0, io.ErrShortWrite, io.EOF},
+		// Force error in num transactions.
+		{baseMerkleBlock, baseMerkleBlockEncoded, pver, 180, io.ErrShortWrite, io.EOF},
+		// Force error in num hashes.
+		{baseMerkleBlock, baseMerkleBlockEncoded, pver, 184, io.ErrShortWrite, io.EOF},
+		// Force error in hashes.
+		{baseMerkleBlock, baseMerkleBlockEncoded, pver, 185, io.ErrShortWrite, io.EOF},
+		// Force error in num stake transactions.
+		{baseMerkleBlock, baseMerkleBlockEncoded, pver, 217, io.ErrShortWrite, io.EOF},
+		// Force error in num stake hashes.
+		{baseMerkleBlock, baseMerkleBlockEncoded, pver, 221, io.ErrShortWrite, io.EOF},
+		// Force error in stake hashes.
+		{baseMerkleBlock, baseMerkleBlockEncoded, pver, 222, io.ErrShortWrite, io.EOF},
+		// Force error in flags size.
+		{baseMerkleBlock, baseMerkleBlockEncoded, pver, 254, io.ErrShortWrite, io.EOF},
+		// Force error in flags.
+		{baseMerkleBlock, baseMerkleBlockEncoded, pver, 255, io.ErrShortWrite, io.EOF},
+		// Force error in stake flags size.
+		{baseMerkleBlock, baseMerkleBlockEncoded, pver, 256, io.ErrShortWrite, io.EOF},
+		// Force error in stake flags.
+		{baseMerkleBlock, baseMerkleBlockEncoded, pver, 257, io.ErrShortWrite, io.EOF},
+		// Force error with too many flag bytes.
+		{maxFlags, maxFlagsEncoded, pver, len(maxFlagsEncoded), ErrMerkleFlagsTooLarge, ErrVarBytesTooLong},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		// Encode to wire format.
+		w := newFixedWriter(test.max)
+		err := test.in.BtcEncode(w, test.pver)
+		if !errors.Is(err, test.writeErr) {
+			t.Errorf("BtcEncode #%d wrong error got: %v, want: %v", i, err,
+				test.writeErr)
+			continue
+		}
+
+		// Decode from wire format.
+		var msg MsgMerkleBlock
+		r := newFixedReader(test.max, test.buf)
+		err = msg.BtcDecode(r, test.pver)
+		if !errors.Is(err, test.readErr) {
+			t.Errorf("BtcDecode #%d wrong error got: %v, want: %v", i, err,
+				test.readErr)
+			continue
+		}
+	}
+
+	// Ensure decoding more transaction hashes than allowed fails.
+	var msg MsgMerkleBlock
+	r := bytes.NewReader(maxHashesEncoded)
+	err := msg.BtcDecode(r, pver)
+	if !errors.Is(err, ErrTooManyTxs) {
+		t.Errorf("BtcDecode wrong error got: %v, want: %v", err,
+			ErrTooManyTxs)
+	}
+}
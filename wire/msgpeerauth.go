@@ -0,0 +1,97 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+)
+
+const (
+	// PeerAuthPubKeySize is the size of the compressed secp256k1 public key
+	// in a peerauth message.
+	PeerAuthPubKeySize = 33
+
+	// PeerAuthSignatureSize is the size of the schnorr signature in a
+	// peerauth message.
+	PeerAuthSignatureSize = 64
+)
+
+// MsgPeerAuth implements the Message interface and represents a decred
+// peerauth message.  It is used by peers that advertise the SFNodePeerAuth
+// service flag to prove ownership of a long-term identity key immediately
+// after the version handshake.
+//
+// The signature commits to the version nonces exchanged by both sides of the
+// connection so it can't be replayed on other connections.  The details of
+// what is signed are up to the peer implementation.
+//
+// This message was not added until protocol versions starting with
+// PeerAuthVersion.
+type MsgPeerAuth struct {
+	// PubKey is the compressed public key of the identity of the sending
+	// peer.
+	PubKey [PeerAuthPubKeySize]byte
+
+	// Signature is the signature that proves the sending peer has the
+	// private key of the identity.
+	Signature [PeerAuthSignatureSize]byte
+}
+
+// BtcDecode decodes r using the Decred protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgPeerAuth) BtcDecode(r io.Reader, pver uint32) error {
+	const op = "MsgPeerAuth.BtcDecode"
+	if pver < PeerAuthVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	if _, err := io.ReadFull(r, msg.PubKey[:]); err != nil {
+		return err
+	}
+	_, err := io.ReadFull(r, msg.Signature[:])
+	return err
+}
+
+// BtcEncode encodes the receiver to w using the Decred protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgPeerAuth) BtcEncode(w io.Writer, pver uint32) error {
+	const op = "MsgPeerAuth.BtcEncode"
+	if pver < PeerAuthVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	if _, err := w.Write(msg.PubKey[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(msg.Signature[:])
+	return err
+}
+
+// Command returns the protocol command string for the message.  This is part
+// of the Message interface implementation.
+func (msg *MsgPeerAuth) Command() string {
+	return CmdPeerAuth
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgPeerAuth) MaxPayloadLength(pver uint32) uint32 {
+	// Public key + signature.
+	return PeerAuthPubKeySize + PeerAuthSignatureSize
+}
+
+// NewMsgPeerAuth returns a new Decred peerauth message that conforms to the
+// Message interface using the passed parameters.
+func NewMsgPeerAuth(pubKey *[PeerAuthPubKeySize]byte, sig *[PeerAuthSignatureSize]byte) *MsgPeerAuth {
+	return &MsgPeerAuth{
+		PubKey:    *pubKey,
+		Signature: *sig,
+	}
+}
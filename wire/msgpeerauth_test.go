@@ -0,0 +1,197 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// testPeerAuthMsg returns a peerauth message with a distinct value in every
+// byte of its fields along with its wire encoding.
+func testPeerAuthMsg() (*MsgPeerAuth, []byte) {
+	var pubKey [PeerAuthPubKeySize]byte
+	var sig [PeerAuthSignatureSize]byte
+	pubKey[0] = 0x02
+	for i := 1; i < len(pubKey); i++ {
+		pubKey[i] = byte(i)
+	}
+	for i := range sig {
+		sig[i] = byte(0x40 + i)
+	}
+	encoded := []byte{
+		0x02, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07,
+		0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f,
+		0x10, 0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17,
+		0x18, 0x19, 0x1a, 0x1b, 0x1c, 0x1d, 0x1e, 0x1f,
+		0x20, // Public key
+		0x40, 0x41, 0x42, 0x43, 0x44, 0x45, 0x46, 0x47,
+		0x48, 0x49, 0x4a, 0x4b, 0x4c, 0x4d, 0x4e, 0x4f,
+		0x50, 0x51, 0x52, 0x53, 0x54, 0x55, 0x56, 0x57,
+		0x58, 0x59, 0x5a, 0x5b, 0x5c, 0x5d, 0x5e, 0x5f,
+		0x60, 0x61, 0x62, 0x63, 0x64, 0x65, 0x66, 0x67,
+		0x68, 0x69, 0x6a, 0x6b, 0x6c, 0x6d, 0x6e, 0x6f,
+		0x70, 0x71, 0x72, 0x73, 0x74, 0x75, 0x76, 0x77,
+		0x78, 0x79, 0x7a, 0x7b, 0x7c, 0x7d, 0x7e, 0x7f, // Signature
+	}
+	return NewMsgPeerAuth(&pubKey, &sig), encoded
+}
+
+// TestPeerAuth tests the MsgPeerAuth API against the latest protocol version.
+func TestPeerAuth(t *testing.T) {
+	pver := ProtocolVersion
+
+	// Ensure the command is expected value.
+	wantCmd := "peerauth"
+	msg, _ := testPeerAuthMsg()
+	if cmd := msg.Command(); cmd != wantCmd {
+		t.Errorf("NewMsgPeerAuth: wrong command - got %v want %v", cmd,
+			wantCmd)
+	}
+
+	// Ensure max payload is expected value for latest protocol version.
+	// Public key + signature.
+	wantPayload := uint32(97)
+	maxPayload := msg.MaxPayloadLength(pver)
+	if maxPayload != wantPayload {
+		t.Errorf("MaxPayloadLength: wrong max payload length for protocol "+
+			"version %d - got %v, want %v", pver, maxPayload, wantPayload)
+	}
+}
+
+// TestPeerAuthPreviousProtocol tests the MsgPeerAuth API against the protocol
+// prior to version PeerAuthVersion.
+func TestPeerAuthPreviousProtocol(t *testing.T) {
+	// Use the protocol version just prior to PeerAuthVersion changes.
+	pver := PeerAuthVersion - 1
+
+	msg, _ := testPeerAuthMsg()
+
+	// Test encode with old protocol version.
+	var buf bytes.Buffer
+	err := msg.BtcEncode(&buf, pver)
+	if !errors.Is(err, ErrMsgInvalidForPVer) {
+		t.Errorf("unexpected error when encoding for protocol version %d, "+
+			"prior to message introduction - got %v, want %v", pver,
+			err, ErrMsgInvalidForPVer)
+	}
+
+	// Test decode with old protocol version.
+	var readmsg MsgPeerAuth
+	err = readmsg.BtcDecode(&buf, pver)
+	if !errors.Is(err, ErrMsgInvalidForPVer) {
+		t.Errorf("unexpected error when decoding for protocol version %d, "+
+			"prior to message introduction - got %v, want %v", pver,
+			err, ErrMsgInvalidForPVer)
+	}
+}
+
+// TestPeerAuthWire tests the MsgPeerAuth wire encode and decode for various
+// protocol versions.
+func TestPeerAuthWire(t *testing.T) {
+	msgPeerAuth, msgPeerAuthEncoded := testPeerAuthMsg()
+
+	tests := []struct {
+		in   *MsgPeerAuth // Message to encode
+		out  *MsgPeerAuth // Expected decoded message
+		buf  []byte       // Wire encoding
+		pver uint32       // Protocol version for wire encoding
+	}{{
+		// Latest protocol version.
+		msgPeerAuth,
+		msgPeerAuth,
+		msgPeerAuthEncoded,
+		ProtocolVersion,
+	}, {
+		// Protocol version PeerAuthVersion.
+		msgPeerAuth,
+		msgPeerAuth,
+		msgPeerAuthEncoded,
+		PeerAuthVersion,
+	}}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		// Encode the message to wire format.
+		var buf bytes.Buffer
+		err := test.in.BtcEncode(&buf, test.pver)
+		if err != nil {
+			t.Errorf("BtcEncode #%d error %v", i, err)
+			continue
+		}
+		if !bytes.Equal(buf.Bytes(), test.buf) {
+			t.Errorf("BtcEncode #%d\n got: %s want: %s", i,
+				spew.Sdump(buf.Bytes()), spew.Sdump(test.buf))
+			continue
+		}
+
+		// Decode the message from wire format.
+		var msg MsgPeerAuth
+		rbuf := bytes.NewReader(test.buf)
+		err = msg.BtcDecode(rbuf, test.pver)
+		if err != nil {
+			t.Errorf("BtcDecode #%d error %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(&msg, test.out) {
+			t.Errorf("BtcDecode #%d\n got: %s want: %s", i, spew.Sdump(&msg),
+				spew.Sdump(test.out))
+			continue
+		}
+	}
+}
+
+// TestPeerAuthWireErrors performs negative tests against wire encode and
+// decode of MsgPeerAuth to confirm error paths work correctly.
+func TestPeerAuthWireErrors(t *testing.T) {
+	pver := ProtocolVersion
+
+	basePeerAuth, basePeerAuthEncoded := testPeerAuthMsg()
+
+	tests := []struct {
+		in       *MsgPeerAuth // Value to encode
+		buf      []byte       // Wire encoding
+		pver     uint32       // Protocol version for wire encoding
+		max      int          // Max size of fixed buffer to induce errors
+		writeErr error        // Expected write error
+		readErr  error        // Expected read error
+	}{
+		// Force error in start of public key.
+		{basePeerAuth, basePeerAuthEncoded, pver, 0, io.ErrShortWrite, io.EOF},
+		// Force error in middle of public key.
+		{basePeerAuth, basePeerAuthEncoded, pver, 16, io.ErrShortWrite, io.ErrUnexpectedEOF},
+		// Force error in start of signature.
+		{basePeerAuth, basePeerAuthEncoded, pver, 33, io.ErrShortWrite, io.EOF},
+		// Force error in middle of signature.
+		{basePeerAuth, basePeerAuthEncoded, pver, 64, io.ErrShortWrite, io.ErrUnexpectedEOF},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		// Encode to wire format.
+		w := newFixedWriter(test.max)
+		err := test.in.BtcEncode(w, test.pver)
+		if !errors.Is(err, test.writeErr) {
+			t.Errorf("BtcEncode #%d wrong error got: %v, want: %v", i, err,
+				test.writeErr)
+			continue
+		}
+
+		// Decode from wire format.
+		var msg MsgPeerAuth
+		r := newFixedReader(test.max, test.buf)
+		err = msg.BtcDecode(r, test.pver)
+		if !errors.Is(err, test.readErr) {
+			t.Errorf("BtcDecode #%d wrong error got: %v, want: %v", i, err,
+				test.readErr)
+			continue
+		}
+	}
+}
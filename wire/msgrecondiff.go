@@ -0,0 +1,128 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+)
+
+// MaxReconShortIDs is the maximum number of transaction short ids that can be
+// requested in a single recondiff message.
+const MaxReconShortIDs = 20000
+
+// MsgReconDiff implements the Message interface and represents a decred
+// recondiff message.  It concludes a transaction inventory reconciliation
+// round started with a reqrecon message (MsgReqRecon).
+//
+// When Success is true, AskShortIDs contains the short ids from the sketch
+// (MsgReconSketch) the initiator does not know and the receiving peer is
+// expected to announce the associated transactions.  When Success is false,
+// the sketch could not be decoded and the receiving peer is expected to
+// announce all transactions that were part of the sketch.
+//
+// This message was not added until protocol versions starting with
+// TxReconVersion.
+type MsgReconDiff struct {
+	Success     bool
+	AskShortIDs []uint32
+}
+
+// BtcDecode decodes r using the Decred protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgReconDiff) BtcDecode(r io.Reader, pver uint32) error {
+	const op = "MsgReconDiff.BtcDecode"
+	if pver < TxReconVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	err := readElement(r, &msg.Success)
+	if err != nil {
+		return err
+	}
+
+	// Read num short ids and limit to max.
+	count, err := ReadVarInt(r, pver)
+	if err != nil {
+		return err
+	}
+	if count > MaxReconShortIDs {
+		msg := fmt.Sprintf("too many short ids for message "+
+			"[count %v, max %v]", count, MaxReconShortIDs)
+		return messageError(op, ErrTooManyReconShortIDs, msg)
+	}
+
+	msg.AskShortIDs = make([]uint32, count)
+	for i := uint64(0); i < count; i++ {
+		err := readElement(r, &msg.AskShortIDs[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// BtcEncode encodes the receiver to w using the Decred protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgReconDiff) BtcEncode(w io.Writer, pver uint32) error {
+	const op = "MsgReconDiff.BtcEncode"
+	if pver < TxReconVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	count := len(msg.AskShortIDs)
+	if count > MaxReconShortIDs {
+		msg := fmt.Sprintf("too many short ids for message "+
+			"[count %v, max %v]", count, MaxReconShortIDs)
+		return messageError(op, ErrTooManyReconShortIDs, msg)
+	}
+
+	err := writeElement(w, msg.Success)
+	if err != nil {
+		return err
+	}
+
+	err = WriteVarInt(w, pver, uint64(count))
+	if err != nil {
+		return err
+	}
+
+	for _, id := range msg.AskShortIDs {
+		err := writeElement(w, id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Command returns the protocol command string for the message.  This is part
+// of the Message interface implementation.
+func (msg *MsgReconDiff) Command() string {
+	return CmdReconDiff
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgReconDiff) MaxPayloadLength(pver uint32) uint32 {
+	// Success flag + max num short ids (including varint).
+	return 1 + uint32(VarIntSerializeSize(MaxReconShortIDs)) +
+		MaxReconShortIDs*4
+}
+
+// NewMsgReconDiff returns a new Decred recondiff message that conforms to the
+// Message interface using the passed parameters.
+func NewMsgReconDiff(success bool, askShortIDs []uint32) *MsgReconDiff {
+	return &MsgReconDiff{
+		Success:     success,
+		AskShortIDs: askShortIDs,
+	}
+}
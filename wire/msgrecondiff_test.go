@@ -0,0 +1,205 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// TestReconDiff tests the MsgReconDiff API against the latest protocol
+// version.
+func TestReconDiff(t *testing.T) {
+	pver := ProtocolVersion
+
+	// Ensure the command is expected value.
+	wantCmd := "recondiff"
+	msg := NewMsgReconDiff(true, nil)
+	if cmd := msg.Command(); cmd != wantCmd {
+		t.Errorf("NewMsgReconDiff: wrong command - got %v want %v", cmd,
+			wantCmd)
+	}
+
+	// Ensure max payload is expected value for latest protocol version.
+	// Success flag + max num short ids (including varint).
+	wantPayload := uint32(80004)
+	maxPayload := msg.MaxPayloadLength(pver)
+	if maxPayload != wantPayload {
+		t.Errorf("MaxPayloadLength: wrong max payload length for protocol "+
+			"version %d - got %v, want %v", pver, maxPayload, wantPayload)
+	}
+
+	// Ensure max payload length is not more than MaxMessagePayload.
+	if maxPayload > MaxMessagePayload {
+		t.Fatalf("MaxPayloadLength: payload length (%v) for protocol version "+
+			"%d exceeds MaxMessagePayload (%v).", maxPayload, pver,
+			MaxMessagePayload)
+	}
+}
+
+// TestReconDiffPreviousProtocol tests the MsgReconDiff API against the protocol
+// prior to version TxReconVersion.
+func TestReconDiffPreviousProtocol(t *testing.T) {
+	// Use the protocol version just prior to TxReconVersion changes.
+	pver := TxReconVersion - 1
+
+	msg := NewMsgReconDiff(true, []uint32{1})
+
+	// Test encode with old protocol version.
+	var buf bytes.Buffer
+	err := msg.BtcEncode(&buf, pver)
+	if !errors.Is(err, ErrMsgInvalidForPVer) {
+		t.Errorf("unexpected error when encoding for protocol version %d, "+
+			"prior to message introduction - got %v, want %v", pver,
+			err, ErrMsgInvalidForPVer)
+	}
+
+	// Test decode with old protocol version.
+	var readmsg MsgReconDiff
+	err = readmsg.BtcDecode(&buf, pver)
+	if !errors.Is(err, ErrMsgInvalidForPVer) {
+		t.Errorf("unexpected error when decoding for protocol version %d, "+
+			"prior to message introduction - got %v, want %v", pver,
+			err, ErrMsgInvalidForPVer)
+	}
+}
+
+// TestReconDiffWire tests the MsgReconDiff wire encode and decode for various
+// protocol versions.
+func TestReconDiffWire(t *testing.T) {
+	msgReconDiff := NewMsgReconDiff(true, []uint32{0x01020304, 0x05060708})
+	msgReconDiffEncoded := []byte{
+		0x01,                   // Success
+		0x02,                   // Varint for number of short ids
+		0x04, 0x03, 0x02, 0x01, // Short id 1
+		0x08, 0x07, 0x06, 0x05, // Short id 2
+	}
+
+	msgReconDiffFail := NewMsgReconDiff(false, []uint32{})
+	msgReconDiffFailEncoded := []byte{
+		0x00, // Success
+		0x00, // Varint for number of short ids
+	}
+
+	tests := []struct {
+		in   *MsgReconDiff // Message to encode
+		out  *MsgReconDiff // Expected decoded message
+		buf  []byte        // Wire encoding
+		pver uint32        // Protocol version for wire encoding
+	}{{
+		// Latest protocol version.
+		msgReconDiff,
+		msgReconDiff,
+		msgReconDiffEncoded,
+		ProtocolVersion,
+	}, {
+		// Protocol version TxReconVersion.
+		msgReconDiff,
+		msgReconDiff,
+		msgReconDiffEncoded,
+		TxReconVersion,
+	}, {
+		// Failed reconciliation with latest protocol version.
+		msgReconDiffFail,
+		msgReconDiffFail,
+		msgReconDiffFailEncoded,
+		ProtocolVersion,
+	}}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		// Encode the message to wire format.
+		var buf bytes.Buffer
+		err := test.in.BtcEncode(&buf, test.pver)
+		if err != nil {
+			t.Errorf("BtcEncode #%d error %v", i, err)
+			continue
+		}
+		if !bytes.Equal(buf.Bytes(), test.buf) {
+			t.Errorf("BtcEncode #%d\n got: %s want: %s", i,
+				spew.Sdump(buf.Bytes()), spew.Sdump(test.buf))
+			continue
+		}
+
+		// Decode the message from wire format.
+		var msg MsgReconDiff
+		rbuf := bytes.NewReader(test.buf)
+		err = msg.BtcDecode(rbuf, test.pver)
+		if err != nil {
+			t.Errorf("BtcDecode #%d error %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(&msg, test.out) {
+			t.Errorf("BtcDecode #%d\n got: %s want: %s", i, spew.Sdump(&msg),
+				spew.Sdump(test.out))
+			continue
+		}
+	}
+}
+
+// TestReconDiffWireErrors performs negative tests against wire encode and
+// decode of MsgReconDiff to confirm error paths work correctly.
+func TestReconDiffWireErrors(t *testing.T) {
+	pver := ProtocolVersion
+
+	baseReconDiff := NewMsgReconDiff(true, []uint32{0x01020304})
+	baseReconDiffEncoded := []byte{
+		0x01,                   // Success
+		0x01,                   // Varint for number of short ids
+		0x04, 0x03, 0x02, 0x01, // Short id
+	}
+
+	// Message with more short ids than allowed.
+	maxReconDiff := NewMsgReconDiff(true, make([]uint32, MaxReconShortIDs+1))
+	maxReconDiffEncoded := []byte{
+		0x01,             // Success
+		0xfd, 0x21, 0x4e, // Varint for number of short ids (20001)
+	}
+
+	tests := []struct {
+		in       *MsgReconDiff // Value to encode
+		buf      []byte        // Wire encoding
+		pver     uint32        // Protocol version for wire encoding
+		max      int           // Max size of fixed buffer to induce errors
+		writeErr error         // Expected write error
+		readErr  error         // Expected read error
+	}{
+		// Force error in success flag.
+		{baseReconDiff, baseReconDiffEncoded, pver, 0, io.ErrShortWrite, io.EOF},
+		// Force error in number of short ids.
+		{baseReconDiff, baseReconDiffEncoded, pver, 1, io.ErrShortWrite, io.EOF},
+		// Force error in middle of short id.
+		{baseReconDiff, baseReconDiffEncoded, pver, 4, io.ErrShortWrite, io.ErrUnexpectedEOF},
+		// Force error with too many short ids.
+		{maxReconDiff, maxReconDiffEncoded, pver, len(maxReconDiffEncoded), ErrTooManyReconShortIDs, ErrTooManyReconShortIDs},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		// Encode to wire format.
+		w := newFixedWriter(test.max)
+		err := test.in.BtcEncode(w, test.pver)
+		if !errors.Is(err, test.writeErr) {
+			t.Errorf("BtcEncode #%d wrong error got: %v, want: %v", i, err,
+				test.writeErr)
+			continue
+		}
+
+		// Decode from wire format.
+		var msg MsgReconDiff
+		r := newFixedReader(test.max, test.buf)
+		err = msg.BtcDecode(r, test.pver)
+		if !errors.Is(err, test.readErr) {
+			t.Errorf("BtcDecode #%d wrong error got: %v, want: %v", i, err,
+				test.readErr)
+			continue
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+)
+
+// MaxReconSketchSize is the maximum number of bytes a serialized transaction
+// reconciliation sketch can be in a single message.
+const MaxReconSketchSize = 256 * 1024
+
+// MsgReconSketch implements the Message interface and represents a decred
+// reconsketch message.  It is used to deliver a sketch of the short ids of the
+// transactions the sending peer would have otherwise announced to the
+// receiving peer.  The format of the sketch is opaque to the wire protocol.
+//
+// It is delivered in response to a reqrecon message (MsgReqRecon) and is
+// answered with a recondiff message (MsgReconDiff).
+//
+// This message was not added until protocol versions starting with
+// TxReconVersion.
+type MsgReconSketch struct {
+	Sketch []byte
+}
+
+// BtcDecode decodes r using the Decred protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgReconSketch) BtcDecode(r io.Reader, pver uint32) error {
+	const op = "MsgReconSketch.BtcDecode"
+	if pver < TxReconVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	var err error
+	msg.Sketch, err = ReadVarBytes(r, pver, MaxReconSketchSize,
+		"reconsketch sketch")
+	return err
+}
+
+// BtcEncode encodes the receiver to w using the Decred protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgReconSketch) BtcEncode(w io.Writer, pver uint32) error {
+	const op = "MsgReconSketch.BtcEncode"
+	if pver < TxReconVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	size := len(msg.Sketch)
+	if size > MaxReconSketchSize {
+		msg := fmt.Sprintf("sketch size too large for message "+
+			"[size %v, max %v]", size, MaxReconSketchSize)
+		return messageError(op, ErrReconSketchTooLarge, msg)
+	}
+
+	return WriteVarBytes(w, pver, msg.Sketch)
+}
+
+// Command returns the protocol command string for the message.  This is part
+// of the Message interface implementation.
+func (msg *MsgReconSketch) Command() string {
+	return CmdReconSketch
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgReconSketch) MaxPayloadLength(pver uint32) uint32 {
+	// Max sketch data (including varint).
+	return uint32(VarIntSerializeSize(MaxReconSketchSize)) +
+		MaxReconSketchSize
+}
+
+// NewMsgReconSketch returns a new Decred reconsketch message that conforms to
+// the Message interface using the passed parameters.
+func NewMsgReconSketch(sketch []byte) *MsgReconSketch {
+	return &MsgReconSketch{
+		Sketch: sketch,
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// TestReconSketch tests the MsgReconSketch API against the latest protocol
+// version.
+func TestReconSketch(t *testing.T) {
+	pver := ProtocolVersion
+
+	// Ensure the command is expected value.
+	wantCmd := "reconsketch"
+	msg := NewMsgReconSketch([]byte{0x01})
+	if cmd := msg.Command(); cmd != wantCmd {
+		t.Errorf("NewMsgReconSketch: wrong command - got %v want %v", cmd,
+			wantCmd)
+	}
+
+	// Ensure max payload is expected value for latest protocol version.
+	// Max sketch data (including varint).
+	wantPayload := uint32(262149)
+	maxPayload := msg.MaxPayloadLength(pver)
+	if maxPayload != wantPayload {
+		t.Errorf("MaxPayloadLength: wrong max payload length for protocol "+
+			"version %d - got %v, want %v", pver, maxPayload, wantPayload)
+	}
+
+	// Ensure max payload length is not more than MaxMessagePayload.
+	if maxPayload > MaxMessagePayload {
+		t.Fatalf("MaxPayloadLength: payload length (%v) for protocol version "+
+			"%d exceeds MaxMessagePayload (%v).", maxPayload, pver,
+			MaxMessagePayload)
+	}
+}
+
+// TestReconSketchPreviousProtocol tests the MsgReconSketch API against the
+// protocol prior to version TxReconVersion.
+func TestReconSketchPreviousProtocol(t *testing.T) {
+	// Use the protocol version just prior to TxReconVersion changes.
+	pver := TxReconVersion - 1
+
+	msg := NewMsgReconSketch([]byte{0x01})
+
+	// Test encode with old protocol version.
+	var buf bytes.Buffer
+	err := msg.BtcEncode(&buf, pver)
+	if !errors.Is(err, ErrMsgInvalidForPVer) {
+		t.Errorf("unexpected error when encoding for protocol version %d, "+
+			"prior to message introduction - got %v, want %v", pver,
+			err, ErrMsgInvalidForPVer)
+	}
+
+	// Test decode with old protocol version.
+	var readmsg MsgReconSketch
+	err = readmsg.BtcDecode(&buf, pver)
+	if !errors.Is(err, ErrMsgInvalidForPVer) {
+		t.Errorf("unexpected error when decoding for protocol version %d, "+
+			"prior to message introduction - got %v, want %v", pver,
+			err, ErrMsgInvalidForPVer)
+	}
+}
+
+// TestReconSketchWire tests the MsgReconSketch wire encode and decode for
+// various protocol versions.
+func TestReconSketchWire(t *testing.T) {
+	msgReconSketch := NewMsgReconSketch([]byte{0x01, 0x02, 0x03, 0x04})
+	msgReconSketchEncoded := []byte{
+		0x04,                   // Varint for sketch size
+		0x01, 0x02, 0x03, 0x04, // Sketch
+	}
+
+	tests := []struct {
+		in   *MsgReconSketch // Message to encode
+		out  *MsgReconSketch // Expected decoded message
+		buf  []byte          // Wire encoding
+		pver uint32          // Protocol version for wire encoding
+	}{{
+		// Latest protocol version.
+		msgReconSketch,
+		msgReconSketch,
+		msgReconSketchEncoded,
+		ProtocolVersion,
+	}, {
+		// Protocol version TxReconVersion.
+		msgReconSketch,
+		msgReconSketch,
+		msgReconSketchEncoded,
+		TxReconVersion,
+	}}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		// Encode the message to wire format.
+		var buf bytes.Buffer
+		err := test.in.BtcEncode(&buf, test.pver)
+		if err != nil {
+			t.Errorf("BtcEncode #%d error %v", i, err)
+			continue
+		}
+		if !bytes.Equal(buf.Bytes(), test.buf) {
+			t.Errorf("BtcEncode #%d\n got: %s want: %s", i,
+				spew.Sdump(buf.Bytes()), spew.Sdump(test.buf))
+			continue
+		}
+
+		// Decode the message from wire format.
+		var msg MsgReconSketch
+		rbuf := bytes.NewReader(test.buf)
+		err = msg.BtcDecode(rbuf, test.pver)
+		if err != nil {
+			t.Errorf("BtcDecode #%d error %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(&msg, test.out) {
+			t.Errorf("BtcDecode #%d\n got: %s want: %s", i, spew.Sdump(&msg),
+				spew.Sdump(test.out))
+			continue
+		}
+	}
+}
+
+// TestReconSketchWireErrors performs negative tests against wire encode and
+// decode of MsgReconSketch to confirm error paths work correctly.
+func TestReconSketchWireErrors(t *testing.T) {
+	pver := ProtocolVersion
+
+	baseReconSketch := NewMsgReconSketch([]byte{0x01, 0x02, 0x03, 0x04})
+	baseReconSketchEncoded := []byte{
+		0x04,                   // Varint for sketch size
+		0x01, 0x02, 0x03, 0x04, // Sketch
+	}
+
+	// Message with a sketch that exceeds the max allowed size.
+	maxSketch := NewMsgReconSketch(make([]byte, MaxReconSketchSize+1))
+	maxSketchEncoded := []byte{
+		0xfe, 0x01, 0x00, 0x04, 0x00, // Varint for sketch size
+	}
+
+	tests := []struct {
+		in       *MsgReconSketch // Value to encode
+		buf      []byte          // Wire encoding
+		pver     uint32          // Protocol version for wire encoding
+		max      int             // Max size of fixed buffer to induce errors
+		writeErr error           // Expected write error
+		readErr  error           // Expected read error
+	}{
+		// Force error in start of sketch size.
+		{baseReconSketch, baseReconSketchEncoded, pver, 0, io.ErrShortWrite, io.EOF},
+		// Force error in middle of sketch.
+		{baseReconSketch, baseReconSketchEncoded, pver, 3, io.ErrShortWrite, io.ErrUnexpectedEOF},
+		// Force error with sketch too large.
+		{maxSketch, maxSketchEncoded, pver, len(maxSketchEncoded), ErrReconSketchTooLarge, ErrVarBytesTooLong},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		// Encode to wire format.
+		w := newFixedWriter(test.max)
+		err := test.in.BtcEncode(w, test.pver)
+		if !errors.Is(err, test.writeErr) {
+			t.Errorf("BtcEncode #%d wrong error got: %v, want: %v", i, err,
+				test.writeErr)
+			continue
+		}
+
+		// Decode from wire format.
+		var msg MsgReconSketch
+		r := newFixedReader(test.max, test.buf)
+		err = msg.BtcDecode(r, test.pver)
+		if !errors.Is(err, test.readErr) {
+			t.Errorf("BtcDecode #%d wrong error got: %v, want: %v", i, err,
+				test.readErr)
+			continue
+		}
+	}
+}
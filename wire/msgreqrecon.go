@@ -0,0 +1,77 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+)
+
+// MsgReqRecon implements the Message interface and represents a decred
+// reqrecon message.  It is used by the initiator of a transaction inventory
+// reconciliation round to request a sketch of the transactions the receiving
+// peer would have otherwise announced to it.  The sketch is returned via a
+// reconsketch message (MsgReconSketch).
+//
+// This message was not added until protocol versions starting with
+// TxReconVersion.
+type MsgReqRecon struct {
+	// SetSize is the number of transactions the initiator would have
+	// otherwise announced to the receiving peer.  It is used by the
+	// receiving peer to estimate the size of the sketch to send.
+	SetSize uint32
+
+	// Salt is a random value chosen by the initiator for each round that
+	// both peers use to derive the short ids of the transactions.
+	Salt uint64
+}
+
+// BtcDecode decodes r using the Decred protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgReqRecon) BtcDecode(r io.Reader, pver uint32) error {
+	const op = "MsgReqRecon.BtcDecode"
+	if pver < TxReconVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	return readElements(r, &msg.SetSize, &msg.Salt)
+}
+
+// BtcEncode encodes the receiver to w using the Decred protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgReqRecon) BtcEncode(w io.Writer, pver uint32) error {
+	const op = "MsgReqRecon.BtcEncode"
+	if pver < TxReconVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	return writeElements(w, msg.SetSize, msg.Salt)
+}
+
+// Command returns the protocol command string for the message.  This is part
+// of the Message interface implementation.
+func (msg *MsgReqRecon) Command() string {
+	return CmdReqRecon
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgReqRecon) MaxPayloadLength(pver uint32) uint32 {
+	// Set size + salt.
+	return 12
+}
+
+// NewMsgReqRecon returns a new Decred reqrecon message that conforms to the
+// Message interface using the passed parameters.
+func NewMsgReqRecon(setSize uint32, salt uint64) *MsgReqRecon {
+	return &MsgReqRecon{
+		SetSize: setSize,
+		Salt:    salt,
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// TestReqRecon tests the MsgReqRecon API against the latest protocol version.
+func TestReqRecon(t *testing.T) {
+	pver := ProtocolVersion
+
+	// Ensure the command is expected value.
+	wantCmd := "reqrecon"
+	msg := NewMsgReqRecon(100, 200)
+	if cmd := msg.Command(); cmd != wantCmd {
+		t.Errorf("NewMsgReqRecon: wrong command - got %v want %v", cmd,
+			wantCmd)
+	}
+
+	// Ensure max payload is expected value for latest protocol version.
+	// Set size + salt.
+	wantPayload := uint32(12)
+	maxPayload := msg.MaxPayloadLength(pver)
+	if maxPayload != wantPayload {
+		t.Errorf("MaxPayloadLength: wrong max payload length for protocol "+
+			"version %d - got %v, want %v", pver, maxPayload, wantPayload)
+	}
+}
+
+// TestReqReconPreviousProtocol tests the MsgReqRecon API against the protocol
+// prior to version TxReconVersion.
+func TestReqReconPreviousProtocol(t *testing.T) {
+	// Use the protocol version just prior to TxReconVersion changes.
+	pver := TxReconVersion - 1
+
+	msg := NewMsgReqRecon(100, 200)
+
+	// Test encode with old protocol version.
+	var buf bytes.Buffer
+	err := msg.BtcEncode(&buf, pver)
+	if !errors.Is(err, ErrMsgInvalidForPVer) {
+		t.Errorf("unexpected error when encoding for protocol version %d, "+
+			"prior to message introduction - got %v, want %v", pver,
+			err, ErrMsgInvalidForPVer)
+	}
+
+	// Test decode with old protocol version.
+	var readmsg MsgReqRecon
+	err = readmsg.BtcDecode(&buf, pver)
+	if !errors.Is(err, ErrMsgInvalidForPVer) {
+		t.Errorf("unexpected error when decoding for protocol version %d, "+
+			"prior to message introduction - got %v, want %v", pver,
+			err, ErrMsgInvalidForPVer)
+	}
+}
+
+// TestReqReconWire tests the MsgReqRecon wire encode and decode for various
+// protocol versions.
+func TestReqReconWire(t *testing.T) {
+	msgReqRecon := NewMsgReqRecon(0x01020304, 0x0102030405060708)
+	msgReqReconEncoded := []byte{
+		0x04, 0x03, 0x02, 0x01, // Set size
+		0x08, 0x07, 0x06, 0x05, 0x04, 0x03, 0x02, 0x01, // Salt
+	}
+
+	tests := []struct {
+		in   *MsgReqRecon // Message to encode
+		out  *MsgReqRecon // Expected decoded message
+		buf  []byte       // Wire encoding
+		pver uint32       // Protocol version for wire encoding
+	}{{
+		// Latest protocol version.
+		msgReqRecon,
+		msgReqRecon,
+		msgReqReconEncoded,
+		ProtocolVersion,
+	}, {
+		// Protocol version TxReconVersion.
+		msgReqRecon,
+		msgReqRecon,
+		msgReqReconEncoded,
+		TxReconVersion,
+	}}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		// Encode the message to wire format.
+		var buf bytes.Buffer
+		err := test.in.BtcEncode(&buf, test.pver)
+		if err != nil {
+			t.Errorf("BtcEncode #%d error %v", i, err)
+			continue
+		}
+		if !bytes.Equal(buf.Bytes(), test.buf) {
+			t.Errorf("BtcEncode #%d\n got: %s want: %s", i,
+				spew.Sdump(buf.Bytes()), spew.Sdump(test.buf))
+			continue
+		}
+
+		// Decode the message from wire format.
+		var msg MsgReqRecon
+		rbuf := bytes.NewReader(test.buf)
+		err = msg.BtcDecode(rbuf, test.pver)
+		if err != nil {
+			t.Errorf("BtcDecode #%d error %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(&msg, test.out) {
+			t.Errorf("BtcDecode #%d\n got: %s want: %s", i, spew.Sdump(&msg),
+				spew.Sdump(test.out))
+			continue
+		}
+	}
+}
+
+// TestReqReconWireErrors performs negative tests against wire encode and
+// decode of MsgReqRecon to confirm error paths work correctly.
+func TestReqReconWireErrors(t *testing.T) {
+	pver := ProtocolVersion
+
+	baseReqRecon := NewMsgReqRecon(0x01020304, 0x0102030405060708)
+	baseReqReconEncoded := []byte{
+		0x04, 0x03, 0x02, 0x01, // Set size
+		0x08, 0x07, 0x06, 0x05, 0x04, 0x03, 0x02, 0x01, // Salt
+	}
+
+	tests := []struct {
+		in       *MsgReqRecon // Value to encode
+		buf      []byte       // Wire encoding
+		pver     uint32       // Protocol version for wire encoding
+		max      int          // Max size of fixed buffer to induce errors
+		writeErr error        // Expected write error
+		readErr  error        // Expected read error
+	}{
+		// Force error in start of set size.
+		{baseReqRecon, baseReqReconEncoded, pver, 0, io.ErrShortWrite, io.EOF},
+		// Force error in middle of set size.
+		{baseReqRecon, baseReqReconEncoded, pver, 2, io.ErrShortWrite, io.ErrUnexpectedEOF},
+		// Force error in start of salt.
+		{baseReqRecon, baseReqReconEncoded, pver, 4, io.ErrShortWrite, io.EOF},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		// Encode to wire format.
+		w := newFixedWriter(test.max)
+		err := test.in.BtcEncode(w, test.pver)
+		if !errors.Is(err, test.writeErr) {
+			t.Errorf("BtcEncode #%d wrong error got: %v, want: %v", i, err,
+				test.writeErr)
+			continue
+		}
+
+		// Decode from wire format.
+		var msg MsgReqRecon
+		r := newFixedReader(test.max, test.buf)
+		err = msg.BtcDecode(r, test.pver)
+		if !errors.Is(err, test.readErr) {
+			t.Errorf("BtcDecode #%d wrong error got: %v, want: %v", i, err,
+				test.readErr)
+			continue
+		}
+	}
+}
@@ -17,7 +17,7 @@ const (
 	InitialProcotolVersion uint32 = 1
 
 	// ProtocolVersion is the latest protocol version this package supports.
-	ProtocolVersion uint32 = 7
+	ProtocolVersion uint32 = 10
 
 	// NodeBloomVersion is the protocol version which added the SFNodeBloom
 	// service flag (unused).
@@ -43,6 +43,19 @@ const (
 	// CFilterV2Version is the protocol version which adds the getcfilterv2 and
 	// cfiltverv2 messages.
 	CFilterV2Version uint32 = 7
+
+	// TxReconVersion is the protocol version which adds the SFNodeTxRecon
+	// service flag and the reqrecon, reconsketch, and recondiff messages.
+	TxReconVersion uint32 = 8
+
+	// PeerAuthVersion is the protocol version which adds the SFNodePeerAuth
+	// service flag and the peerauth message.
+	PeerAuthVersion uint32 = 9
+
+	// BloomFilterVersion is the protocol version which adds the filterload,
+	// filteradd, filterclear, and merkleblock messages used by peers that
+	// advertise the SFNodeBloom service flag.
+	BloomFilterVersion uint32 = 10
 )
 
 // ServiceFlag identifies services supported by a Decred peer.
@@ -53,19 +66,29 @@ const (
 	SFNodeNetwork ServiceFlag = 1 << iota
 
 	// SFNodeBloom is a flag used to indicate a peer supports bloom
-	// filtering.
+	// filtering via the messages added in BloomFilterVersion.
 	SFNodeBloom
 
 	// SFNodeCF is a flag used to indicate a peer supports v1 gcs filters
 	// (CFs).
 	SFNodeCF
+
+	// SFNodeTxRecon is a flag used to indicate a peer supports announcing
+	// transaction inventory via set reconciliation.
+	SFNodeTxRecon
+
+	// SFNodePeerAuth is a flag used to indicate a peer has a long-term
+	// identity key and supports authenticating it via the peerauth message.
+	SFNodePeerAuth
 )
 
 // Map of service flags back to their constant names for pretty printing.
 var sfStrings = map[ServiceFlag]string{
-	SFNodeNetwork: "SFNodeNetwork",
-	SFNodeBloom:   "SFNodeBloom",
-	SFNodeCF:      "SFNodeCF",
+	SFNodeNetwork:  "SFNodeNetwork",
+	SFNodeBloom:    "SFNodeBloom",
+	SFNodeCF:       "SFNodeCF",
+	SFNodeTxRecon:  "SFNodeTxRecon",
+	SFNodePeerAuth: "SFNodePeerAuth",
 }
 
 // orderedSFStrings is an ordered list of service flags from highest to
@@ -74,6 +97,8 @@ var orderedSFStrings = []ServiceFlag{
 	SFNodeNetwork,
 	SFNodeBloom,
 	SFNodeCF,
+	SFNodeTxRecon,
+	SFNodePeerAuth,
 }
 
 // String returns the ServiceFlag in human-readable form.